@@ -0,0 +1,432 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acme
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// LetsEncryptURL is the directory of the Let's Encrypt certificate
+	// authority
+	LetsEncryptURL = "https://acme-v02.api.letsencrypt.org/directory"
+
+	badNonceError = "urn:ietf:params:acme:error:badNonce"
+
+	statusValid   = "valid"
+	statusInvalid = "invalid"
+	statusPending = "pending"
+)
+
+var (
+	// ErrNotRegistered is returned when a certificate is ordered before the
+	// account is registered
+	ErrNotRegistered = errors.New("acme: account is not registered")
+
+	// ErrNoHTTPChallenge is returned when the certificate authority does not
+	// offer an http-01 challenge for a name
+	ErrNoHTTPChallenge = errors.New("acme: no http-01 challenge was offered")
+
+	// ErrTimeout is returned when the certificate authority does not finish
+	// validating a challenge or issuing a certificate in time
+	ErrTimeout = errors.New("acme: timed out waiting for the certificate authority")
+)
+
+// Error is a problem document returned by the certificate authority
+type Error struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+	Status int    `json:"status"`
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("acme: %d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("acme: %s", e.Detail)
+}
+
+// Directory is the urls of the resources of a certificate authority
+type Directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+}
+
+type identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type order struct {
+	Status         string       `json:"status"`
+	Identifiers    []identifier `json:"identifiers"`
+	Authorizations []string     `json:"authorizations"`
+	Finalize       string       `json:"finalize"`
+	Certificate    string       `json:"certificate,omitempty"`
+	Error          *Error       `json:"error,omitempty"`
+}
+
+type authorization struct {
+	Status     string      `json:"status"`
+	Identifier identifier  `json:"identifier"`
+	Challenges []challenge `json:"challenges"`
+}
+
+type challenge struct {
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Status string `json:"status"`
+	Error  *Error `json:"error,omitempty"`
+}
+
+// Client orders certificates from a certificate authority for an account
+type Client struct {
+	DirectoryURL string
+	HTTPClient   *http.Client
+	PollInterval time.Duration // how often to check an authorization or order
+	PollTimeout  time.Duration // how long to wait for an authorization or order
+
+	key    *ecdsa.PrivateKey
+	mu     sync.Mutex
+	dir    *Directory
+	kid    string
+	nonces []string
+}
+
+// NewClient returns a client of the certificate authority at the directory
+// url for the account with the key
+func NewClient(directoryURL string, key *ecdsa.PrivateKey) *Client {
+	return &Client{
+		DirectoryURL: directoryURL,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		PollInterval: 2 * time.Second,
+		PollTimeout:  2 * time.Minute,
+		key:          key,
+	}
+}
+
+// Register creates the account of the key, or looks it up if the
+// certificate authority already knows the key.  Registering agrees to the
+// terms of service of the certificate authority.
+func (c *Client) Register(email string) error {
+	dir, err := c.directory()
+	if err != nil {
+		return err
+	}
+	req := struct {
+		TermsOfServiceAgreed bool     `json:"termsOfServiceAgreed"`
+		Contact              []string `json:"contact,omitempty"`
+	}{TermsOfServiceAgreed: true}
+	if email != "" {
+		req.Contact = []string{"mailto:" + email}
+	}
+	header, _, err := c.post(dir.NewAccount, req, nil)
+	if err != nil {
+		return err
+	}
+	kid := header.Get("Location")
+	if kid == "" {
+		return errors.New("acme: certificate authority did not return the account url")
+	}
+	c.mu.Lock()
+	c.kid = kid
+	c.mu.Unlock()
+	plog.WithField("account", kid).Debug("Registered ACME account")
+	return nil
+}
+
+// ObtainCertificate orders a certificate for the names, answering the
+// challenges of the certificate authority with the solver.  It returns the
+// PEM encoded certificate chain and the PEM encoded private key of the
+// certificate.
+func (c *Client) ObtainCertificate(names []string, solver Solver) ([]byte, []byte, error) {
+	c.mu.Lock()
+	registered := c.kid != ""
+	c.mu.Unlock()
+	if !registered {
+		return nil, nil, ErrNotRegistered
+	}
+	dir, err := c.directory()
+	if err != nil {
+		return nil, nil, err
+	}
+	logger := plog.WithField("names", names)
+
+	req := struct {
+		Identifiers []identifier `json:"identifiers"`
+	}{}
+	for _, name := range names {
+		req.Identifiers = append(req.Identifiers, identifier{Type: "dns", Value: name})
+	}
+	var o order
+	header, _, err := c.post(dir.NewOrder, req, &o)
+	if err != nil {
+		logger.WithError(err).Debug("Could not create order")
+		return nil, nil, err
+	}
+	orderURL := header.Get("Location")
+
+	for _, authzURL := range o.Authorizations {
+		if err := c.authorize(authzURL, solver); err != nil {
+			logger.WithError(err).WithField("authorization", authzURL).Debug("Could not complete authorization")
+			return nil, nil, err
+		}
+	}
+
+	// sign the request with a new key for the certificate
+	certKey, err := GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, certKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	finalize := struct {
+		CSR string `json:"csr"`
+	}{CSR: b64(csr)}
+	if _, _, err := c.post(o.Finalize, finalize, &o); err != nil {
+		logger.WithError(err).Debug("Could not finalize order")
+		return nil, nil, err
+	}
+	err = c.poll(orderURL, &o, func() (bool, error) {
+		switch o.Status {
+		case statusValid:
+			return true, nil
+		case statusInvalid:
+			if o.Error != nil {
+				return false, o.Error
+			}
+			return false, errors.New("acme: order is invalid")
+		}
+		return false, nil
+	})
+	if err != nil {
+		logger.WithError(err).Debug("Certificate was not issued")
+		return nil, nil, err
+	}
+
+	_, certPEM, err := c.post(o.Certificate, nil, nil)
+	if err != nil {
+		logger.WithError(err).Debug("Could not download certificate")
+		return nil, nil, err
+	}
+	keyPEM, err := EncodeKey(certKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Info("Obtained certificate from ACME certificate authority")
+	return certPEM, keyPEM, nil
+}
+
+// authorize answers the http-01 challenge of an authorization and waits for
+// the certificate authority to validate it
+func (c *Client) authorize(url string, solver Solver) error {
+	var authz authorization
+	if _, _, err := c.post(url, nil, &authz); err != nil {
+		return err
+	} else if authz.Status == statusValid {
+		return nil
+	}
+
+	var chal *challenge
+	for i := range authz.Challenges {
+		if authz.Challenges[i].Type == "http-01" {
+			chal = &authz.Challenges[i]
+			break
+		}
+	}
+	if chal == nil {
+		return ErrNoHTTPChallenge
+	}
+	if err := solver.Present(chal.Token, keyAuthorization(&c.key.PublicKey, chal.Token)); err != nil {
+		return err
+	}
+	defer solver.CleanUp(chal.Token)
+
+	plog.WithFields(logrus.Fields{
+		"name":      authz.Identifier.Value,
+		"challenge": chal.URL,
+	}).Debug("Answering http-01 challenge")
+	if _, _, err := c.post(chal.URL, struct{}{}, nil); err != nil {
+		return err
+	}
+	return c.poll(url, &authz, func() (bool, error) {
+		switch authz.Status {
+		case statusValid:
+			return true, nil
+		case statusPending:
+			return false, nil
+		}
+		for _, ch := range authz.Challenges {
+			if ch.Error != nil {
+				return false, ch.Error
+			}
+		}
+		return false, fmt.Errorf("acme: authorization of %s is %s", authz.Identifier.Value, authz.Status)
+	})
+}
+
+// poll requests the resource at the url until done returns true or an error
+func (c *Client) poll(url string, result interface{}, done func() (bool, error)) error {
+	deadline := time.Now().Add(c.PollTimeout)
+	for {
+		if ok, err := done(); err != nil || ok {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+		time.Sleep(c.PollInterval)
+		if _, _, err := c.post(url, nil, result); err != nil {
+			return err
+		}
+	}
+}
+
+// directory returns the directory of the certificate authority
+func (c *Client) directory() (*Directory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dir != nil {
+		return c.dir, nil
+	}
+	resp, err := c.HTTPClient.Get(c.DirectoryURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &Error{Status: resp.StatusCode}
+	}
+	dir := &Directory{}
+	if err := json.NewDecoder(resp.Body).Decode(dir); err != nil {
+		return nil, err
+	}
+	c.dir = dir
+	return dir, nil
+}
+
+// nonce returns a nonce from a previous response, or a new nonce
+func (c *Client) nonce() (string, error) {
+	c.mu.Lock()
+	if n := len(c.nonces); n > 0 {
+		nonce := c.nonces[n-1]
+		c.nonces = c.nonces[:n-1]
+		c.mu.Unlock()
+		return nonce, nil
+	}
+	c.mu.Unlock()
+
+	dir, err := c.directory()
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.HTTPClient.Head(dir.NewNonce)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return "", errors.New("acme: certificate authority did not return a nonce")
+	}
+	return nonce, nil
+}
+
+func (c *Client) saveNonce(header http.Header) {
+	if nonce := header.Get("Replay-Nonce"); nonce != "" {
+		c.mu.Lock()
+		c.nonces = append(c.nonces, nonce)
+		c.mu.Unlock()
+	}
+}
+
+// post sends a signed request with the payload, and decodes the response
+// into result if it is not nil.  A nil payload is a POST-as-GET request.  It
+// returns the headers and body of the response.
+func (c *Client) post(url string, payload interface{}, result interface{}) (http.Header, []byte, error) {
+	var data []byte
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := c.nonce()
+		if err != nil {
+			return nil, nil, err
+		}
+		header := protectedHeader{Nonce: nonce, URL: url}
+		c.mu.Lock()
+		if c.kid == "" {
+			header.JWK = newJSONWebKey(&c.key.PublicKey)
+		} else {
+			header.KID = c.kid
+		}
+		c.mu.Unlock()
+		body, err := signJWS(c.key, header, data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, err := c.HTTPClient.Post(url, "application/jose+json", bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		c.saveNonce(resp.Header)
+
+		if resp.StatusCode >= 400 {
+			problem := &Error{Status: resp.StatusCode}
+			json.Unmarshal(respBody, problem)
+			// nonces expire, so try once more with the nonce of the error
+			if problem.Type == badNonceError && attempt == 0 {
+				continue
+			}
+			return nil, nil, problem
+		}
+		if result != nil {
+			if err := json.Unmarshal(respBody, result); err != nil {
+				return nil, nil, err
+			}
+		}
+		return resp.Header, respBody, nil
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCA is a certificate authority that validates challenges with the
+// solver of the test
+type testCA struct {
+	t       *testing.T
+	solver  *HTTPSolver
+	server  *httptest.Server
+	mu      sync.Mutex
+	nonce   int
+	account *ecdsa.PublicKey
+	names   []string
+	authz   string
+	status  string
+	cert    []byte
+	badOnce bool
+}
+
+func newTestCA(t *testing.T, solver *HTTPSolver) *testCA {
+	ca := &testCA{t: t, solver: solver, authz: statusPending, status: statusPending, badOnce: true}
+	ca.server = httptest.NewServer(http.HandlerFunc(ca.serveHTTP))
+	return ca
+}
+
+func (ca *testCA) url(path string) string {
+	return ca.server.URL + path
+}
+
+func (ca *testCA) newNonce(w http.ResponseWriter) {
+	ca.nonce++
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", ca.nonce))
+}
+
+func (ca *testCA) problem(w http.ResponseWriter, status int, typ, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Error{Type: typ, Detail: detail, Status: status})
+}
+
+// verify checks the signature of a request and returns its payload
+func (ca *testCA) verify(r *http.Request) ([]byte, error) {
+	var msg jws
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		return nil, err
+	}
+	protected, _ := base64.RawURLEncoding.DecodeString(msg.Protected)
+	var header protectedHeader
+	if err := json.Unmarshal(protected, &header); err != nil {
+		return nil, err
+	}
+	if header.URL != ca.url(r.URL.Path) {
+		return nil, fmt.Errorf("url %s does not match %s", header.URL, r.URL.Path)
+	}
+	key := ca.account
+	if header.JWK != nil {
+		x, _ := base64.RawURLEncoding.DecodeString(header.JWK.X)
+		y, _ := base64.RawURLEncoding.DecodeString(header.JWK.Y)
+		key = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		ca.account = key
+	} else if header.KID != ca.url("/account/1") {
+		return nil, fmt.Errorf("unknown account %s", header.KID)
+	}
+	sig, _ := base64.RawURLEncoding.DecodeString(msg.Signature)
+	digest := sha256.Sum256([]byte(msg.Protected + "." + msg.Payload))
+	r0, s0 := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if key == nil || !ecdsa.Verify(key, digest[:], r0, s0) {
+		return nil, fmt.Errorf("bad signature")
+	}
+	return base64.RawURLEncoding.DecodeString(msg.Payload)
+}
+
+func (ca *testCA) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.newNonce(w)
+
+	if r.URL.Path == "/directory" {
+		json.NewEncoder(w).Encode(Directory{
+			NewNonce:   ca.url("/nonce"),
+			NewAccount: ca.url("/account"),
+			NewOrder:   ca.url("/order"),
+		})
+		return
+	} else if r.URL.Path == "/nonce" {
+		return
+	}
+
+	payload, err := ca.verify(r)
+	if err != nil {
+		ca.problem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:malformed", err.Error())
+		return
+	}
+	order := func() order {
+		o := order{
+			Status:         ca.status,
+			Authorizations: []string{ca.url("/authz/1")},
+			Finalize:       ca.url("/finalize"),
+		}
+		if ca.cert != nil {
+			o.Certificate = ca.url("/cert")
+		}
+		return o
+	}
+
+	switch r.URL.Path {
+	case "/account":
+		w.Header().Set("Location", ca.url("/account/1"))
+		w.WriteHeader(http.StatusCreated)
+	case "/order":
+		if ca.badOnce {
+			ca.badOnce = false
+			ca.problem(w, http.StatusBadRequest, badNonceError, "nonce expired")
+			return
+		}
+		var req struct{ Identifiers []identifier }
+		json.Unmarshal(payload, &req)
+		for _, id := range req.Identifiers {
+			ca.names = append(ca.names, id.Value)
+		}
+		w.Header().Set("Location", ca.url("/order/1"))
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(order())
+	case "/authz/1":
+		json.NewEncoder(w).Encode(authorization{
+			Status:     ca.authz,
+			Identifier: identifier{Type: "dns", Value: ca.names[0]},
+			Challenges: []challenge{
+				{Type: "dns-01", URL: ca.url("/chal/0"), Token: "dnstoken", Status: statusPending},
+				{Type: "http-01", URL: ca.url("/chal/1"), Token: "token1", Status: statusPending},
+			},
+		})
+	case "/chal/1":
+		// validate the challenge
+		rec := httptest.NewRecorder()
+		ca.solver.ServeHTTP(rec, httptest.NewRequest("GET", ChallengePath+"token1", nil))
+		if rec.Body.String() != keyAuthorization(ca.account, "token1") {
+			ca.t.Errorf("wrong key authorization %q", rec.Body.String())
+			ca.authz = statusInvalid
+		} else {
+			ca.authz = statusValid
+			ca.status = "ready"
+		}
+		json.NewEncoder(w).Encode(challenge{Type: "http-01", URL: ca.url("/chal/1"), Token: "token1", Status: statusPending})
+	case "/finalize":
+		var req struct{ CSR string }
+		json.Unmarshal(payload, &req)
+		der, _ := base64.RawURLEncoding.DecodeString(req.CSR)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil {
+			ca.problem(w, http.StatusBadRequest, "urn:ietf:params:acme:error:badCSR", err.Error())
+			return
+		}
+		ca.cert = ca.issue(csr)
+		ca.status = "processing"
+		json.NewEncoder(w).Encode(order())
+	case "/order/1":
+		if ca.status == "processing" {
+			ca.status = statusValid
+		}
+		json.NewEncoder(w).Encode(order())
+	case "/cert":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(ca.cert)
+	default:
+		ca.problem(w, http.StatusNotFound, "urn:ietf:params:acme:error:malformed", "not found")
+	}
+}
+
+// issue signs the certificate request with a new key
+func (ca *testCA) issue(csr *x509.CertificateRequest) []byte {
+	caKey, _ := GenerateKey()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: csr.Subject.CommonName},
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, csr.PublicKey, caKey)
+	if err != nil {
+		ca.t.Fatalf("could not issue certificate: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestClient_ObtainCertificate(t *testing.T) {
+	solver := NewHTTPSolver()
+	ca := newTestCA(t, solver)
+	defer ca.server.Close()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("could not generate key: %s", err)
+	}
+	client := NewClient(ca.url("/directory"), key)
+	client.PollInterval = 10 * time.Millisecond
+
+	if _, _, err := client.ObtainCertificate([]string{"app.example.com"}, solver); err != ErrNotRegistered {
+		t.Fatalf("expected %s, got %v", ErrNotRegistered, err)
+	}
+	if err := client.Register("admin@example.com"); err != nil {
+		t.Fatalf("could not register: %s", err)
+	}
+	certPEM, keyPEM, err := client.ObtainCertificate([]string{"app.example.com"}, solver)
+	if err != nil {
+		t.Fatalf("could not obtain certificate: %s", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("certificate does not match key: %s", err)
+	}
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+	if len(leaf.DNSNames) != 1 || leaf.DNSNames[0] != "app.example.com" {
+		t.Errorf("unexpected names %v", leaf.DNSNames)
+	}
+
+	// the challenge is no longer answered
+	rec := httptest.NewRecorder()
+	solver.ServeHTTP(rec, httptest.NewRequest("GET", ChallengePath+"token1", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected challenge to be cleaned up, got %d", rec.Code)
+	}
+}
+
+func TestClient_Problem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Replay-Nonce", "nonce")
+		if strings.HasSuffix(r.URL.Path, "/directory") {
+			fmt.Fprintf(w, `{"newNonce":"%[1]s/nonce","newAccount":"%[1]s/account","newOrder":"%[1]s/order"}`, "http://"+r.Host)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"type":"urn:ietf:params:acme:error:unauthorized","detail":"account is deactivated","status":403}`)
+	}))
+	defer server.Close()
+
+	key, _ := GenerateKey()
+	err := NewClient(server.URL+"/directory", key).Register("")
+	if problem, ok := err.(*Error); !ok || problem.Status != http.StatusForbidden || problem.Detail != "account is deactivated" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := dir + "/account.pem"
+	key, err := LoadOrCreateKey(filename)
+	if err != nil {
+		t.Fatalf("could not create key: %s", err)
+	}
+	loaded, err := LoadOrCreateKey(filename)
+	if err != nil {
+		t.Fatalf("could not load key: %s", err)
+	} else if thumbprint(&key.PublicKey) != thumbprint(&loaded.PublicKey) {
+		t.Errorf("loaded a different key")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acme
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ChallengePath is the path that the certificate authority requests to
+// validate an http-01 challenge.  It is always served over http on port 80.
+const ChallengePath = "/.well-known/acme-challenge/"
+
+// Solver answers the challenges of the certificate authority
+type Solver interface {
+	// Present starts answering the challenge with the token
+	Present(token, keyAuth string) error
+	// CleanUp stops answering the challenge with the token
+	CleanUp(token string)
+}
+
+// HTTPSolver answers http-01 challenges.  It is the handler of requests to
+// the ChallengePath.
+type HTTPSolver struct {
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+// NewHTTPSolver returns a solver for http-01 challenges
+func NewHTTPSolver() *HTTPSolver {
+	return &HTTPSolver{tokens: make(map[string]string)}
+}
+
+// Present implements Solver
+func (s *HTTPSolver) Present(token, keyAuth string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = keyAuth
+	return nil
+}
+
+// CleanUp implements Solver
+func (s *HTTPSolver) CleanUp(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, token)
+}
+
+// ServeHTTP returns the key authorization of a challenge that is being
+// answered
+func (s *HTTPSolver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, ChallengePath) {
+		http.NotFound(w, r)
+		return
+	}
+	token := strings.TrimPrefix(r.URL.Path, ChallengePath)
+	s.mu.RLock()
+	keyAuth, ok := s.tokens[token]
+	s.mu.RUnlock()
+	if !ok {
+		plog.WithField("token", token).Debug("Received a request for an unknown challenge")
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Write([]byte(keyAuth))
+}
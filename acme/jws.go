@@ -0,0 +1,155 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acme
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+// ErrBadAccountKey is returned when the account key is not an ECDSA P-256 key
+var ErrBadAccountKey = errors.New("acme: account key must be an ECDSA P-256 key")
+
+// jsonWebKey is the public account key.  The fields are in lexical order, as
+// the thumbprint requires (RFC 7638).
+type jsonWebKey struct {
+	Crv string `json:"crv"`
+	Kty string `json:"kty"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// protectedHeader is the header of a signed request.  A request carries the
+// whole key until the account is registered, and the url of the account
+// after.
+type protectedHeader struct {
+	Alg   string      `json:"alg"`
+	JWK   *jsonWebKey `json:"jwk,omitempty"`
+	KID   string      `json:"kid,omitempty"`
+	Nonce string      `json:"nonce"`
+	URL   string      `json:"url"`
+}
+
+// jws is a signed request in the flattened JSON serialization
+type jws struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func newJSONWebKey(pub *ecdsa.PublicKey) *jsonWebKey {
+	return &jsonWebKey{
+		Crv: "P-256",
+		Kty: "EC",
+		X:   b64(pub.X.FillBytes(make([]byte, 32))),
+		Y:   b64(pub.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+// thumbprint returns the thumbprint of the public account key
+func thumbprint(pub *ecdsa.PublicKey) string {
+	data, _ := json.Marshal(newJSONWebKey(pub))
+	sum := sha256.Sum256(data)
+	return b64(sum[:])
+}
+
+// keyAuthorization is the response to a challenge with the token
+func keyAuthorization(pub *ecdsa.PublicKey, token string) string {
+	return token + "." + thumbprint(pub)
+}
+
+// signJWS signs the payload with ES256.  A nil payload is an empty
+// POST-as-GET request.
+func signJWS(key *ecdsa.PrivateKey, header protectedHeader, payload []byte) ([]byte, error) {
+	header.Alg = "ES256"
+	protected, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	msg := jws{Protected: b64(protected), Payload: b64(payload)}
+	digest := sha256.Sum256([]byte(msg.Protected + "." + msg.Payload))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	msg.Signature = b64(sig)
+	return json.Marshal(msg)
+}
+
+// GenerateKey creates a new ECDSA P-256 key for an account or a certificate
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// EncodeKey returns the PEM encoding of a private key
+func EncodeKey(key crypto.Signer) ([]byte, error) {
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrBadAccountKey
+	}
+	der, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// LoadOrCreateKey reads the account key from a file, and creates the file if
+// it does not exist.  The account key identifies the account to the
+// certificate authority, so it is kept across restarts.
+func LoadOrCreateKey(filename string) (*ecdsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		key, err := GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		data, err := EncodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(filename, data, 0600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	} else if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrBadAccountKey
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil || key.Curve != elliptic.P256() {
+		return nil, ErrBadAccountKey
+	}
+	return key, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apierror defines the structured errors of the master api.  An error
+// carries a code that tells the caller whether the operation failed because
+// an entity was not found, conflicts with the state of the cluster, was not
+// valid, or failed temporarily and may be retried.  The code survives the rpc
+// boundary, which only transfers the message of an error, so that callers do
+// not have to match on the error text.
+package apierror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Code is the class of an error
+type Code string
+
+const (
+	// Unknown is the code of an error that has not been classified
+	Unknown Code = "Unknown"
+	// NotFound is the code of an error about an entity that does not exist
+	NotFound Code = "NotFound"
+	// Conflict is the code of an error about an operation that conflicts
+	// with the state of the cluster, such as adding an entity that exists
+	Conflict Code = "Conflict"
+	// Validation is the code of an error about an invalid request
+	Validation Code = "Validation"
+	// Transient is the code of an error that may not happen again if the
+	// operation is retried
+	Transient Code = "Transient"
+	// Unauthorized is the code of an error about a caller that is not
+	// allowed to perform the operation
+	Unauthorized Code = "Unauthorized"
+)
+
+// wirePrefix marks the message of an encoded error
+const wirePrefix = "apierror:"
+
+// Error is an error with a code, a message and optional details about the
+// entities involved.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]string `json:",omitempty"`
+}
+
+// New returns an error with the given code and message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Errorf returns an error with the given code and formatted message
+func Errorf(code Code, format string, args ...interface{}) *Error {
+	return New(code, fmt.Sprintf(format, args...))
+}
+
+// Wrap returns an error with the given code and the message of err
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Message: err.Error()}
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// WithDetail returns a copy of the error with an additional detail
+func (e *Error) WithDetail(name, value string) *Error {
+	result := &Error{Code: e.Code, Message: e.Message, Details: make(map[string]string)}
+	for k, v := range e.Details {
+		result.Details[k] = v
+	}
+	result.Details[name] = value
+	return result
+}
+
+// Coder is implemented by error types that know their code
+type Coder interface {
+	ErrorCode() Code
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[error]Code)
+)
+
+// Register sets the code of sentinel errors, such as those created with
+// errors.New, that cannot implement Coder.
+func Register(code Code, errs ...error) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, err := range errs {
+		registry[err] = code
+	}
+}
+
+// CodeOf returns the code of an error.  Errors that are neither an *Error,
+// a Coder nor registered are Transient if they are connection errors and
+// Unknown otherwise.  The code of a nil error is empty.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	switch e := err.(type) {
+	case *Error:
+		return e.Code
+	case Coder:
+		return e.ErrorCode()
+	}
+
+	if reflect.TypeOf(err).Comparable() {
+		mu.RLock()
+		code, ok := registry[err]
+		mu.RUnlock()
+		if ok {
+			return code
+		}
+	}
+
+	if err == rpc.ErrShutdown || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return Transient
+	}
+	if _, ok := err.(net.Error); ok {
+		return Transient
+	}
+	return Unknown
+}
+
+// IsNotFound returns true if the error is about an entity that does not exist
+func IsNotFound(err error) bool {
+	return CodeOf(err) == NotFound
+}
+
+// IsConflict returns true if the error is about an operation that conflicts
+// with the state of the cluster
+func IsConflict(err error) bool {
+	return CodeOf(err) == Conflict
+}
+
+// IsValidation returns true if the error is about an invalid request
+func IsValidation(err error) bool {
+	return CodeOf(err) == Validation
+}
+
+// IsTransient returns true if the operation may succeed if it is retried
+func IsTransient(err error) bool {
+	return CodeOf(err) == Transient
+}
+
+// From returns the error as an *Error
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Code: CodeOf(err), Message: err.Error()}
+}
+
+// wireError is an error whose message is an encoded *Error
+type wireError string
+
+func (e wireError) Error() string {
+	return string(e)
+}
+
+// Encode returns an error whose message carries the code, message and
+// details of err, so that they can be restored by Decode on the other side of
+// an rpc call.
+func Encode(err error) error {
+	if err == nil {
+		return nil
+	}
+	data, jsonErr := json.Marshal(From(err))
+	if jsonErr != nil {
+		return err
+	}
+	return wireError(wirePrefix + string(data))
+}
+
+// Decode restores an error encoded by Encode.  Other errors, such as those
+// returned by older masters, are returned as is.
+func Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, wirePrefix) {
+		return err
+	}
+	e := &Error{}
+	if jsonErr := json.Unmarshal([]byte(strings.TrimPrefix(msg, wirePrefix)), e); jsonErr != nil {
+		return err
+	}
+	return e
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package apierror
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type coderError struct{}
+
+func (coderError) Error() string   { return "coder" }
+func (coderError) ErrorCode() Code { return Validation }
+
+type sliceError []string
+
+func (sliceError) Error() string { return "slice" }
+
+func TestCodeOf(t *testing.T) {
+	errRegistered := errors.New("registered")
+	Register(Conflict, errRegistered)
+
+	tests := []struct {
+		err  error
+		code Code
+	}{
+		{nil, ""},
+		{New(NotFound, "missing"), NotFound},
+		{coderError{}, Validation},
+		{errRegistered, Conflict},
+		{io.EOF, Transient},
+		{errors.New("other"), Unknown},
+		{sliceError{"a"}, Unknown},
+	}
+	for _, tt := range tests {
+		if code := CodeOf(tt.err); code != tt.code {
+			t.Errorf("CodeOf(%v): expected %q, got %q", tt.err, tt.code, code)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	err := Errorf(NotFound, "pool %s not found", "default").WithDetail("PoolID", "default")
+	wire := Encode(err)
+	if _, ok := wire.(*Error); ok {
+		t.Fatalf("expected an encoded error")
+	}
+
+	// only the message survives the rpc boundary
+	decoded := Decode(errors.New(wire.Error()))
+	e, ok := decoded.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T", decoded)
+	}
+	if e.Code != NotFound || e.Message != "pool default not found" || e.Details["PoolID"] != "default" {
+		t.Errorf("unexpected decoded error: %+v", e)
+	}
+	if !IsNotFound(decoded) {
+		t.Errorf("expected decoded error to be NotFound")
+	}
+
+	// registered and untyped errors keep their code and message
+	e = Decode(Encode(io.EOF)).(*Error)
+	if e.Code != Transient || e.Message != io.EOF.Error() {
+		t.Errorf("unexpected decoded error: %+v", e)
+	}
+
+	// errors that were not encoded are returned as is
+	plain := errors.New("plain")
+	if Decode(plain) != plain {
+		t.Errorf("expected plain error to be returned as is")
+	}
+	if Encode(nil) != nil || Decode(nil) != nil {
+		t.Errorf("expected nil errors to stay nil")
+	}
+}
@@ -49,4 +49,10 @@ const (
 
 	// Deploy is the string value for the deploy action when logging.
 	Deploy = "deploy"
+
+	// Threshold is the string for the threshold reached action when logging.
+	Threshold = "threshold"
+
+	// Profile is the string for the profile collection action when logging.
+	Profile = "profile"
 )
@@ -75,6 +75,11 @@
 		auditLogger = auditLogger.WithField("FieldName", "ItsValue")
 		auditLogger = auditLogger.WithFields(logrus.Fields{"FieldName1": "Value1", "FieldName2":, "Value2"})
 
+	When an entity is updated, the fields that differ before and after the update can be recorded with the "Delta" method.  The names of the
+	changed fields are logged in the "changes" field.
+
+		auditLogger = auditLogger.Delta(current, entity)
+
 	There are a number of ways that can be used to trigger logging which will also signal success or failure.  The signal success, use the "Success"
 	method.
 
@@ -147,6 +152,14 @@
 		}
 
 
+	Querying
+
+	The logger returned by "NewStoreLogger" also saves each entry as an "Entry" in elasticsearch, including the before and after values
+	set by "Delta".  The master uses this logger, and the entries are listed with "serviced audit list --since 24h".  Failing to save an
+	entry does not fail the audited action.
+
+		auditLogger := audit.NewStoreLogger(audit.NewStore())
+
 	Common Patterns
 
 	Here is an example using the "SucceededIf" and "Failed" pattern to add audit logging to a method that adds resource pools.
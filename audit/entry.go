@@ -0,0 +1,66 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// ErrInvalidEntry is returned when an audit entry cannot be stored
+var ErrInvalidEntry = errors.New("audit entry must have an id and a time")
+
+// Entry is an audit log entry that is stored in elasticsearch so that it can
+// be queried.
+type Entry struct {
+	ID       string
+	Time     time.Time
+	User     string
+	Action   string
+	Type     string
+	EntityID string
+	Message  string
+	Success  bool
+	Fields   map[string]string // additional fields of the entry
+	Changes  []Change          // fields of the entity that were modified
+	datastore.VersionedEntity
+}
+
+// Change is the value of a field of an entity before and after it was
+// modified.  The values are JSON encoded.
+type Change struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// GetID implements datastore.Entity
+func (e *Entry) GetID() string {
+	return e.ID
+}
+
+// GetType implements datastore.Entity
+func (e *Entry) GetType() string {
+	return kind
+}
+
+// ValidEntity implements datastore.ValidEntity
+func (e *Entry) ValidEntity() error {
+	if e.ID == "" || e.Time.IsZero() {
+		return ErrInvalidEntry
+	}
+	return nil
+}
@@ -14,11 +14,18 @@
 package audit
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/logri"
 )
 
@@ -49,6 +56,9 @@ type Logger interface {
 	// Add additional fields to the entry.
 	WithFields(fields logrus.Fields) Logger
 
+	// Set the fields of the entity that differ before and after it is modified.
+	Delta(before, after interface{}) Logger
+
 	// Log that the action succeeded.
 	Succeeded()
 
@@ -70,10 +80,20 @@ func NewLogger() Logger {
 	return &logger{loggeri: l}
 }
 
+// NewStoreLogger returns a default implementation of the audit logger that
+// also saves each entry to the store, so that the audit trail can be queried.
+func NewStoreLogger(store Store) Logger {
+	l := logri.GetLogger("audit")
+	return &logger{loggeri: l, store: store}
+}
+
 type logger struct {
 	entry   *logrus.Entry
 	message string
 	loggeri *logri.Logger
+	store   Store
+	ctx     datastore.Context
+	changes []Change
 }
 
 func (l *logger) Action(action string) Logger {
@@ -83,6 +103,7 @@ func (l *logger) Action(action string) Logger {
 func (l *logger) Message(ctx datastore.Context, message string) Logger {
 	result := l.newLoggerWith("user", ctx.User())
 	result.message = message
+	result.ctx = ctx
 	return result
 }
 
@@ -114,6 +135,9 @@ func (l *logger) newLoggerWithFields(fields logrus.Fields) *logger {
 		entry:   l.entry,
 		message: l.message,
 		loggeri: l.loggeri,
+		store:   l.store,
+		ctx:     l.ctx,
+		changes: l.changes,
 	}
 	result.addFields(fields)
 	return result
@@ -127,6 +151,77 @@ func (l *logger) WithFields(fields logrus.Fields) Logger {
 	return l.newLoggerWithFields(fields)
 }
 
+func (l *logger) Delta(before, after interface{}) Logger {
+	changes, err := diffFields(before, after)
+	if err != nil {
+		plog.WithError(err).Debug("Could not compare audited entities")
+		return l
+	}
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	result := l.newLoggerWith("changes", strings.Join(fields, ","))
+	result.changes = changes
+	return result
+}
+
+// ignoredFields are not reported as changes to an entity
+var ignoredFields = map[string]struct{}{
+	"UpdatedAt":       {},
+	"DatabaseVersion": {},
+}
+
+// diffFields returns the top level fields that differ between the JSON
+// encodings of two entities.  Either entity may be nil.
+func diffFields(before, after interface{}) ([]Change, error) {
+	beforeFields, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []Change{}
+	for _, name := range names {
+		if _, ok := ignoredFields[name]; ok {
+			continue
+		}
+		b, a := beforeFields[name], afterFields[name]
+		if !bytes.Equal(b, a) {
+			changes = append(changes, Change{Field: name, Before: string(b), After: string(a)})
+		}
+	}
+	return changes, nil
+}
+
+func jsonFields(entity interface{}) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if entity == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
 func (l *logger) addFields(fields logrus.Fields) Logger {
 	if l.entry != nil {
 		l.entry = l.entry.WithFields(fields)
@@ -166,4 +261,44 @@ func (l *logger) log(success bool) {
 	} else {
 		entry.Warn(l.message)
 	}
+	if l.store != nil && l.ctx != nil {
+		l.record(entry, success)
+	}
+}
+
+// record saves the entry to the store.  The audited action has already
+// happened, so failures are only logged.
+func (l *logger) record(entry *logrus.Entry, success bool) {
+	id, err := utils.NewUUID36()
+	if err != nil {
+		plog.WithError(err).Warn("Could not generate an id for the audit entry")
+		return
+	}
+	e := &Entry{
+		ID:      id,
+		Time:    time.Now().UTC(),
+		Message: l.message,
+		Success: success,
+		Fields:  make(map[string]string),
+		Changes: l.changes,
+	}
+	for name, value := range entry.Data {
+		v := fmt.Sprint(value)
+		switch name {
+		case "user":
+			e.User = v
+		case "action":
+			e.Action = v
+		case "type":
+			e.Type = v
+		case "id":
+			e.EntityID = v
+		case "success", "changes":
+		default:
+			e.Fields[name] = v
+		}
+	}
+	if err := l.store.Put(l.ctx, Key(id), e); err != nil {
+		plog.WithError(err).WithField("message", l.message).Warn("Could not store the audit entry")
+	}
 }
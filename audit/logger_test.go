@@ -0,0 +1,103 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/datastore"
+	datastoremocks "github.com/control-center/serviced/datastore/mocks"
+)
+
+type testEntity struct {
+	ID          string
+	Description string
+	Tags        []string
+	UpdatedAt   string
+}
+
+type testStore struct {
+	Store
+	entries []*Entry
+}
+
+func (s *testStore) Put(ctx datastore.Context, key datastore.Key, entity datastore.ValidEntity) error {
+	if err := entity.ValidEntity(); err != nil {
+		return err
+	}
+	s.entries = append(s.entries, entity.(*Entry))
+	return nil
+}
+
+func TestDiffFields(t *testing.T) {
+	before := &testEntity{ID: "a", Description: "old", UpdatedAt: "yesterday"}
+	after := &testEntity{ID: "a", Description: "new", Tags: []string{"x"}, UpdatedAt: "today"}
+	changes, err := diffFields(before, after)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []Change{
+		{Field: "Description", Before: `"old"`, After: `"new"`},
+		{Field: "Tags", Before: "null", After: `["x"]`},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], changes[i])
+		}
+	}
+
+	// every field of an added entity is a change
+	changes, err = diffFields(nil, after)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if len(changes) != 3 {
+		t.Errorf("Expected 3 changes, got %v", changes)
+	}
+}
+
+func TestStoreLogger(t *testing.T) {
+	ctx := &datastoremocks.Context{}
+	ctx.On("User").Return("admin")
+	store := &testStore{}
+
+	before := &testEntity{ID: "a", Description: "old"}
+	after := &testEntity{ID: "a", Description: "new"}
+	NewStoreLogger(store).Message(ctx, "Updating Entity").Action(Update).
+		Type("entity").ID("a").WithField("pool", "default").Delta(before, after).Succeeded()
+
+	if len(store.entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(store.entries))
+	}
+	entry := store.entries[0]
+	if entry.User != "admin" || entry.Action != Update || entry.Type != "entity" || entry.EntityID != "a" || !entry.Success {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Message != "Updating Entity" || entry.Fields["pool"] != "default" || len(entry.Fields) != 1 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if len(entry.Changes) != 1 || entry.Changes[0].Field != "Description" {
+		t.Errorf("Unexpected changes: %+v", entry.Changes)
+	}
+
+	// entries are not stored without a store
+	NewLogger().Message(ctx, "Updating Entity").Action(Update).Failed()
+	if len(store.entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(store.entries))
+	}
+}
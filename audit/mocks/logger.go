@@ -105,6 +105,18 @@ func (_m *Logger) WithFields(fields logrus.Fields) audit.Logger {
 
 	return r0
 }
+func (_m *Logger) Delta(before interface{}, after interface{}) audit.Logger {
+	ret := _m.Called(before, after)
+
+	var r0 audit.Logger
+	if rf, ok := ret.Get(0).(func() audit.Logger); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(audit.Logger)
+	}
+
+	return r0
+}
 func (_m *Logger) Succeeded() {
 	_m.Called()
 }
@@ -0,0 +1,82 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore returns a new audit entry store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store is the persistent storage of audit entries
+type Store interface {
+	datastore.EntityStore
+
+	// GetEntries returns the entries that were logged at or after the given
+	// time, oldest first
+	GetEntries(ctx datastore.Context, since time.Time) ([]Entry, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetEntries implements Store
+func (s *storeImpl) GetEntries(ctx datastore.Context, since time.Time) ([]Entry, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditStore.GetEntries"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Range(search.Range().Field("Time").From(since.UTC().Format(time.RFC3339Nano))).Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := convert(results)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(ByTime(entries))
+	return entries, nil
+}
+
+// Key creates a Key suitable for getting, putting and deleting audit entries
+func Key(id string) datastore.Key {
+	id = strings.TrimSpace(id)
+	return datastore.NewKey(kind, id)
+}
+
+func convert(results datastore.Results) ([]Entry, error) {
+	entries := make([]Entry, results.Len())
+	for idx := range entries {
+		if err := results.Get(idx, &entries[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// ByTime sorts audit entries by the time they were logged
+type ByTime []Entry
+
+func (e ByTime) Len() int           { return len(e) }
+func (e ByTime) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e ByTime) Less(i, j int) bool { return e[i].Time.Before(e[j].Time) }
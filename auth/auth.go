@@ -90,5 +90,6 @@ type Identity interface {
 	PoolID() string
 	HasAdminAccess() bool
 	HasDFSAccess() bool
+	Role() Role
 	Verifier() (Verifier, error)
 }
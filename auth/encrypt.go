@@ -0,0 +1,114 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrBadCiphertext is thrown when a message encrypted for the master cannot
+// be decrypted
+var ErrBadCiphertext = errors.New("Cannot decrypt message")
+
+// EncryptForMaster encrypts a message that only the master can decrypt.  The
+// message is sealed with a random AES-256-GCM key, which is encrypted with
+// the master's public key.
+func EncryptForMaster(message []byte) ([]byte, error) {
+	key, err := GetMasterPublicKey()
+	if err != nil {
+		return nil, err
+	}
+	public, err := verifyRSAPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, public, dataKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	// length of the wrapped key | wrapped key | nonce | sealed message
+	ciphertext := make([]byte, 2, 2+len(wrappedKey)+len(nonce)+len(message)+gcm.Overhead())
+	binary.BigEndian.PutUint16(ciphertext, uint16(len(wrappedKey)))
+	ciphertext = append(ciphertext, wrappedKey...)
+	ciphertext = append(ciphertext, nonce...)
+	return gcm.Seal(ciphertext, nonce, message, nil), nil
+}
+
+// DecryptAsMaster decrypts a message encrypted with EncryptForMaster.  Will
+// return an error if the delegate running this process is not the master.
+func DecryptAsMaster(ciphertext []byte) ([]byte, error) {
+	key, err := getMasterPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	private, err := verifyRSAPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < 2 {
+		return nil, ErrBadCiphertext
+	}
+	size := int(binary.BigEndian.Uint16(ciphertext))
+	ciphertext = ciphertext[2:]
+	if len(ciphertext) < size {
+		return nil, ErrBadCiphertext
+	}
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, private, ciphertext[:size], nil)
+	if err != nil {
+		return nil, ErrBadCiphertext
+	}
+	ciphertext = ciphertext[size:]
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrBadCiphertext
+	}
+	message, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrBadCiphertext
+	}
+	return message, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
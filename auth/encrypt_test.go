@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"bytes"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAuthSuite) TestEncryptForMaster(c *C) {
+	message := []byte("the database password")
+
+	ciphertext, err := auth.EncryptForMaster(message)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Contains(ciphertext, message), Equals, false)
+
+	decrypted, err := auth.DecryptAsMaster(ciphertext)
+	c.Assert(err, IsNil)
+	c.Assert(decrypted, DeepEquals, message)
+
+	// each encryption uses a new key
+	again, err := auth.EncryptForMaster(message)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Equal(again, ciphertext), Equals, false)
+
+	// tampered messages are rejected
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = auth.DecryptAsMaster(ciphertext)
+	c.Assert(err, Equals, auth.ErrBadCiphertext)
+	_, err = auth.DecryptAsMaster(ciphertext[:10])
+	c.Assert(err, Equals, auth.ErrBadCiphertext)
+}
+
+func (s *TestAuthSuite) TestDecryptAsDelegate(c *C) {
+	// delegates can encrypt with the master's public key, but cannot decrypt
+	auth.ClearKeys()
+	auth.LoadDelegateKeysFromPEM(mPub, dPriv)
+
+	ciphertext, err := auth.EncryptForMaster([]byte("secret"))
+	c.Assert(err, IsNil)
+	_, err = auth.DecryptAsMaster(ciphertext)
+	c.Assert(err, Equals, auth.ErrNoPrivateKey)
+}
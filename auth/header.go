@@ -228,6 +228,12 @@ func NewAuthHeaderWriterTo(token, payload []byte, signer Signer) io.WriterTo {
 // This allows us to support protocols that require the payload for bookkeeping
 // purposes, like RPC.
 func ReadAuthHeader(r io.Reader) (sender Identity, timestamp time.Time, payload []byte, err error) {
+	return readAuthHeader(r, ParseJWTIdentity)
+}
+
+// readAuthHeader reads an authentication header, parsing its token with the
+// given function.
+func readAuthHeader(r io.Reader, parse func(string) (Identity, error)) (sender Identity, timestamp time.Time, payload []byte, err error) {
 
 	// Read and verify the first three bytes are the magic number
 	var m magicNumber
@@ -251,14 +257,14 @@ func ReadAuthHeader(r io.Reader) (sender Identity, timestamp time.Time, payload
 	// version we don't support
 	switch pv {
 	case ProtocolVersion:
-		return readAuthHeaderV1(r)
+		return readAuthHeaderV1(r, parse)
 	}
 	err = ErrUnknownAuthProtocol
 	return
 }
 
 // readAuthHeaderV1 implements version 1 of the authentication header protocol.
-func readAuthHeaderV1(r io.Reader) (sender Identity, tstamp time.Time, payload []byte, err error) {
+func readAuthHeaderV1(r io.Reader, parse func(string) (Identity, error)) (sender Identity, tstamp time.Time, payload []byte, err error) {
 
 	// Read in the length of everything up to the payload length
 	var allLen uint32
@@ -301,7 +307,7 @@ func readAuthHeaderV1(r io.Reader) (sender Identity, tstamp time.Time, payload [
 		err = eatBytesAndGetPayloadError(teed, 0, err)
 		return
 	}
-	sender, err = ParseJWTIdentity(string(token))
+	sender, err = parse(string(token))
 	if err != nil {
 		err = eatBytesAndGetPayloadError(teed, 0, err)
 		return
@@ -50,32 +50,15 @@ type jwtIdentity struct {
 	AdminAccess bool   `json:"adm,omitempty"`
 	DFSAccess   bool   `json:"dfs,omitempty"`
 	PubKey      string `json:"key,omitempty"`
+	Scope       Role   `json:"rol,omitempty"`
 }
 
 // ParseJWTIdentity parses a JSON Web Token string, verifying that it was signed by the master.
 func ParseJWTIdentity(token string) (Identity, error) {
 	claims := &jwtIdentity{}
-	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		// Validate the algorithm matches the key
-		if _, ok := token.Method.(*jwt.SigningMethodRSAPSS); !ok {
-			return nil, ErrInvalidSigningMethod
-		}
-		return GetMasterPublicKey()
-	})
+	parsed, err := jwt.ParseWithClaims(token, claims, masterKeyFunc)
 	if err != nil {
-		if verr, ok := err.(*jwt.ValidationError); ok {
-			if verr.Errors&jwt.ValidationErrorExpired != 0 {
-				return nil, ErrIdentityTokenExpired
-			}
-			if verr.Errors&(jwt.ValidationErrorNotValidYet|jwt.ValidationErrorIssuedAt) != 0 {
-				return nil, ErrIdentityTokenNotValidYet
-			}
-			if verr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0 {
-				return nil, ErrIdentityTokenBadSig
-			}
-			return nil, verr.Inner
-		}
-		return nil, err
+		return nil, jwtError(err)
 	}
 	if claims, ok := parsed.Claims.(*jwtIdentity); ok && parsed.Valid {
 		return claims, nil
@@ -83,17 +66,55 @@ func ParseJWTIdentity(token string) (Identity, error) {
 	return nil, ErrIdentityTokenInvalid
 }
 
+// masterKeyFunc returns the key to verify a token signed by the master
+func masterKeyFunc(token *jwt.Token) (interface{}, error) {
+	// Validate the algorithm matches the key
+	if _, ok := token.Method.(*jwt.SigningMethodRSAPSS); !ok {
+		return nil, ErrInvalidSigningMethod
+	}
+	return GetMasterPublicKey()
+}
+
+// jwtError translates an error parsing a token into an auth error
+func jwtError(err error) error {
+	if verr, ok := err.(*jwt.ValidationError); ok {
+		if verr.Errors&jwt.ValidationErrorExpired != 0 {
+			return ErrIdentityTokenExpired
+		}
+		if verr.Errors&(jwt.ValidationErrorNotValidYet|jwt.ValidationErrorIssuedAt) != 0 {
+			return ErrIdentityTokenNotValidYet
+		}
+		if verr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0 {
+			return ErrIdentityTokenBadSig
+		}
+		return verr.Inner
+	}
+	return err
+}
+
 // CreateJWTIdentity returns a signed string
 func CreateJWTIdentity(hostID, poolID string, admin, dfs bool, pubKeyPEM []byte, expiration time.Duration) (string, int64, error) {
+	var role Role
+	if admin {
+		role = RoleAdmin
+	}
+	return CreateJWTIdentityWithRole(hostID, poolID, role, dfs, pubKeyPEM, expiration)
+}
+
+// CreateJWTIdentityWithRole returns a signed string for an identity that is
+// scoped to the given role.  An identity without a role may only make the
+// calls that delegates need to run services.
+func CreateJWTIdentityWithRole(hostID, poolID string, role Role, dfs bool, pubKeyPEM []byte, expiration time.Duration) (string, int64, error) {
 	now := jwt.TimeFunc().UTC()
 	claims := &jwtIdentity{
 		Host:        hostID,
 		Pool:        poolID,
 		ExpiresAt:   now.Add(expiration).Unix(),
 		IssuedAt:    now.Unix(),
-		AdminAccess: admin,
+		AdminAccess: role == RoleAdmin,
 		DFSAccess:   dfs,
 		PubKey:      string(pubKeyPEM),
+		Scope:       role,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodPS256, claims)
 	masterPrivKey, err := getMasterPrivateKey()
@@ -110,18 +131,29 @@ func (id *jwtIdentity) Valid() error {
 		return ErrIdentityTokenExpired
 	}
 
-	now := jwt.TimeFunc().UTC().Unix()
-	// provide tolerance for clockdrifting slower
-	if now < (id.IssuedAt - int64(ClockDriftDelta.Seconds())) {
+	if id.notValidYet() {
 		return ErrIdentityTokenNotValidYet
 	}
 
 	return nil
 }
 
+// notValidYet returns whether the identity was issued in the future
+func (id *jwtIdentity) notValidYet() bool {
+	now := jwt.TimeFunc().UTC().Unix()
+	// provide tolerance for clockdrifting slower
+	return now < (id.IssuedAt - int64(ClockDriftDelta.Seconds()))
+}
+
 func (id *jwtIdentity) Expired() bool {
+	return id.expiredAfter(0)
+}
+
+// expiredAfter returns whether the identity is expired even with the given
+// grace period
+func (id *jwtIdentity) expiredAfter(grace time.Duration) bool {
 	now := jwt.TimeFunc().UTC().Unix()
-	return now >= (id.ExpiresAt + int64(ClockDriftDelta.Seconds()))
+	return now >= (id.ExpiresAt + int64((ClockDriftDelta + grace).Seconds()))
 }
 
 func (id *jwtIdentity) HostID() string {
@@ -142,6 +174,18 @@ func (id *jwtIdentity) HasDFSAccess() bool {
 	return id.DFSAccess
 }
 
+// Role returns the role of the identity.  Tokens issued before roles were
+// introduced only carry the admin claim.
+func (id *jwtIdentity) Role() Role {
+	if id.Scope != "" {
+		return id.Scope
+	}
+	if id.AdminAccess {
+		return RoleAdmin
+	}
+	return ""
+}
+
 func (id *jwtIdentity) Verifier() (Verifier, error) {
 	return RSAVerifierFromPEM([]byte(id.PubKey))
 }
@@ -35,6 +35,7 @@ func (s *TestAuthSuite) TestIdentityHappyPath(c *C) {
 	c.Assert(identity.Expired(), Equals, false)
 	c.Assert(identity.HasAdminAccess(), Equals, true)
 	c.Assert(identity.HasDFSAccess(), Equals, false)
+	c.Assert(identity.Role(), Equals, auth.RoleAdmin)
 
 	signer, _ := auth.RSASignerFromPEM(s.delegatePrivPEM)
 	message := []byte("this is a message")
@@ -47,6 +48,25 @@ func (s *TestAuthSuite) TestIdentityHappyPath(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *TestAuthSuite) TestIdentityRole(c *C) {
+	token, _, err := auth.CreateJWTIdentityWithRole("host", "pool", auth.RoleReadOnly, false, s.delegatePubPEM, time.Minute)
+	c.Assert(err, IsNil)
+	identity, err := auth.ParseJWTIdentity(token)
+	c.Assert(err, IsNil)
+	c.Assert(identity.Role(), Equals, auth.RoleReadOnly)
+	c.Assert(identity.HasAdminAccess(), Equals, false)
+
+	token, _, err = auth.CreateJWTIdentity("host", "pool", false, false, s.delegatePubPEM, time.Minute)
+	c.Assert(err, IsNil)
+	identity, err = auth.ParseJWTIdentity(token)
+	c.Assert(err, IsNil)
+	c.Assert(identity.Role(), Equals, auth.Role(""))
+
+	c.Assert(auth.RoleOperator.Allows(auth.RoleReadOnly), Equals, true)
+	c.Assert(auth.RoleReadOnly.Allows(auth.RoleOperator), Equals, false)
+	c.Assert(auth.Role("").Allows(auth.RoleReadOnly), Equals, false)
+}
+
 func (s *TestAuthSuite) TestExpiredToken(c *C) {
 	token, _, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
 
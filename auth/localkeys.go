@@ -20,10 +20,13 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/user"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/config"
@@ -39,6 +42,7 @@ const (
 
 var (
 	delegateKeys HostKeys
+	previousKeys HostKeys // delegate keys replaced by the last key rotation
 	masterKeys   MasterKeys
 	mKeyLock     sync.RWMutex
 	dKeyCond     = utils.NewChannelCond()
@@ -106,6 +110,41 @@ func SignAsDelegate(message []byte) ([]byte, error) {
 	return delegateKeys.Sign(message)
 }
 
+// SignAsDelegateFor signs the given message with the delegate private key
+// whose public key is in the identity.  While the delegate keys are rotated,
+// messages are signed with the key of the token that goes with them, until
+// the delegate receives a token for its new key.
+func SignAsDelegateFor(identity Identity, message []byte) ([]byte, error) {
+	dKeyCond.RLock()
+	defer dKeyCond.RUnlock()
+	if delegateKeys.localPrivate == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if previousKeys.localPrivate != nil && !matchesIdentity(identity, delegateKeys.localPrivate) &&
+		matchesIdentity(identity, previousKeys.localPrivate) {
+		return previousKeys.Sign(message)
+	}
+	return delegateKeys.Sign(message)
+}
+
+// matchesIdentity returns true if the private key belongs to the public key
+// of the identity.
+func matchesIdentity(identity Identity, key crypto.PrivateKey) bool {
+	id, ok := identity.(*jwtIdentity)
+	if !ok || id == nil {
+		return false
+	}
+	public, err := RSAPublicKeyFromPEM([]byte(id.PubKey))
+	if err != nil {
+		return false
+	}
+	private, err := verifyRSAPrivateKey(key)
+	if err != nil {
+		return false
+	}
+	return private.PublicKey.N.Cmp(public.N) == 0 && private.PublicKey.E == public.E
+}
+
 // SignAsMaster signs the given message with the master's private key
 // will return an error if the delegate running this process is not the master
 func SignAsMaster(message []byte) ([]byte, error) {
@@ -361,6 +400,96 @@ func WriteKeyToFile(filename string, keydata []byte) error {
 	return ioutil.WriteFile(filename, keydata, 0644)
 }
 
+// KeyFileTemplate describes where and how a delegate key file is installed on
+// a host.  Path is a text/template that may refer to {{.HostID}} and
+// {{.IPAddr}}.  A zero Mode, Owner or Group leaves that attribute unchanged.
+type KeyFileTemplate struct {
+	Path  string
+	Mode  os.FileMode
+	Owner string
+	Group string
+}
+
+// keyFileTemplateData is the data a KeyFileTemplate path is rendered with
+type keyFileTemplateData struct {
+	HostID string
+	IPAddr string
+}
+
+// Render returns the install path of the key file for the given host
+func (t KeyFileTemplate) Render(hostID, hostIPAddr string) (string, error) {
+	tmpl, err := template.New("keyfile").Option("missingkey=error").Parse(t.Path)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, keyFileTemplateData{HostID: hostID, IPAddr: hostIPAddr}); err != nil {
+		return "", err
+	}
+	if buf.Len() == 0 {
+		return "", fmt.Errorf("key file template %q renders an empty path", t.Path)
+	}
+	return buf.String(), nil
+}
+
+// installArgs returns the command that installs the key file from stdin on a
+// remote host.
+func (t KeyFileTemplate) installArgs(filename string) []string {
+	mode := t.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+	args := []string{"install", "-D", "-m", fmt.Sprintf("%04o", mode.Perm())}
+	if t.Owner != "" {
+		args = append(args, "-o", shellQuote(t.Owner))
+	}
+	if t.Group != "" {
+		args = append(args, "-g", shellQuote(t.Group))
+	}
+	return append(args, "/dev/stdin", shellQuote(filename))
+}
+
+// shellQuote quotes a word for the remote shell that runs an ssh command
+func shellQuote(word string) string {
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+}
+
+// InstallKeyFile writes the key data to filename and applies the mode and
+// ownership of the template.
+func InstallKeyFile(filename string, keydata []byte, t KeyFileTemplate) error {
+	if err := WriteKeyToFile(filename, keydata); err != nil {
+		return err
+	}
+	if t.Mode != 0 {
+		if err := os.Chmod(filename, t.Mode.Perm()); err != nil {
+			return err
+		}
+	}
+	if t.Owner == "" && t.Group == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if t.Owner != "" {
+		u, err := user.Lookup(t.Owner)
+		if err != nil {
+			return err
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return err
+		}
+	}
+	if t.Group != "" {
+		g, err := user.LookupGroup(t.Group)
+		if err != nil {
+			return err
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return err
+		}
+	}
+	return os.Chown(filename, uid, gid)
+}
+
 func RegisterLocalHost(keydata []byte) error {
 	keyfile := filepath.Join(config.GetOptions().EtcPath, DelegateKeyFileName)
 	if err := WriteKeyToFile(keyfile, keydata); err != nil {
@@ -371,6 +500,21 @@ func RegisterLocalHost(keydata []byte) error {
 }
 
 func RegisterRemoteHost(hostID string, nat utils.URL, hostIPAddr string, keydata []byte, prompt bool) error {
+	return RegisterRemoteHostWithTemplate(hostID, nat, hostIPAddr, keydata, prompt, nil)
+}
+
+// RegisterRemoteHostWithTemplate installs the delegate keys on a host via
+// ssh.  When tmpl is nil, the keys are registered with "serviced host
+// register", otherwise they are installed as described by the template.
+func RegisterRemoteHostWithTemplate(hostID string, nat utils.URL, hostIPAddr string, keydata []byte, prompt bool, tmpl *KeyFileTemplate) error {
+	var filename string
+	if tmpl != nil {
+		var err error
+		if filename, err = tmpl.Render(hostID, hostIPAddr); err != nil {
+			return err
+		}
+	}
+
 	thisHostID, err := utils.HostID()
 
 	if err != nil {
@@ -379,6 +523,9 @@ func RegisterRemoteHost(hostID string, nat utils.URL, hostIPAddr string, keydata
 
 	if thisHostID == hostID {
 		// Hey, we aren't remote at all
+		if tmpl != nil {
+			return InstallKeyFile(filename, keydata, *tmpl)
+		}
 		return RegisterLocalHost(keydata)
 	}
 
@@ -412,7 +559,12 @@ func RegisterRemoteHost(hostID string, nat utils.URL, hostIPAddr string, keydata
 	}
 
 	// Add the command to run on the remote side, which will read keys from stdin
-	args = append(args, "--", "serviced", "host", "register", "-")
+	args = append(args, "--")
+	if tmpl != nil {
+		args = append(args, tmpl.installArgs(filename)...)
+	} else {
+		args = append(args, "serviced", "host", "register", "-")
+	}
 
 	log.WithField("command", fmt.Sprintf("/usr/bin/ssh %s", strings.Join(args, " "))).Debug("Registering delegate keys via ssh")
 	cmd := exec.Command("/usr/bin/ssh", args...)
@@ -430,7 +582,13 @@ func RegisterRemoteHost(hostID string, nat utils.URL, hostIPAddr string, keydata
 
 func updateDelegateKeys(pub crypto.PublicKey, priv crypto.PrivateKey) {
 	dKeyCond.Lock()
+	if priv == nil {
+		previousKeys = HostKeys{}
+	} else if delegateKeys.localPrivate != nil {
+		previousKeys = delegateKeys
+	}
 	delegateKeys = HostKeys{pub, priv}
 	dKeyCond.Unlock()
+	ClearTokenCache()
 	dKeyCond.Broadcast()
 }
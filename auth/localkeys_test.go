@@ -212,3 +212,26 @@ func (s *TestAuthSuite) TestLoadKeysFromPEM(c *C) {
 	c.Assert(err, IsNil)
 
 }
+
+func (s *TestAuthSuite) TestKeyFileTemplate(c *C) {
+	tmpDir := c.MkDir()
+	tmpl := auth.KeyFileTemplate{
+		Path: tmpDir + "/{{.HostID}}/{{.IPAddr}}.keys",
+		Mode: 0600,
+	}
+	filename, err := tmpl.Render("deadbeef", "10.0.0.2")
+	c.Assert(err, IsNil)
+	c.Assert(filename, Equals, tmpDir+"/deadbeef/10.0.0.2.keys")
+
+	err = auth.InstallKeyFile(filename, []byte("keys"), tmpl)
+	c.Assert(err, IsNil)
+	fi, err := os.Stat(filename)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Mode().Perm(), Equals, os.FileMode(0600))
+
+	// Unknown fields and empty paths are rejected
+	_, err = auth.KeyFileTemplate{Path: "{{.PoolID}}"}.Render("deadbeef", "10.0.0.2")
+	c.Assert(err, NotNil)
+	_, err = auth.KeyFileTemplate{Path: ""}.Render("deadbeef", "10.0.0.2")
+	c.Assert(err, NotNil)
+}
@@ -67,6 +67,18 @@ func (_m *Identity) HasAdminAccess() bool {
 
 	return r0
 }
+func (_m *Identity) Role() auth.Role {
+	ret := _m.Called()
+
+	var r0 auth.Role
+	if rf, ok := ret.Get(0).(func() auth.Role); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(auth.Role)
+	}
+
+	return r0
+}
 func (_m *Identity) HasDFSAccess() bool {
 	ret := _m.Called()
 
@@ -25,12 +25,16 @@ import (
    if the sender is authorized to send data to the receiver or not
 
    ---------------------------------------------------------------------------------------------------------
-   | Auth Token length (4 bytes)  |     Auth Token (N bytes)  | Address (6 bytes) |  Signature (256 bytes) |
+   | Auth Token length (4 bytes)  |     Auth Token (N bytes)  | Address (6 or 18 bytes) |  Signature (256 bytes) |
    ---------------------------------------------------------------------------------------------------------
+
+   The address is the packed port and IP of the receiver, which is 6 bytes for an IPv4 address and
+   18 bytes for an IPv6 address.
 */
 
 const (
-	ADDRESS_BYTES = 6
+	ADDRESS_BYTES      = 6
+	ADDRESS_BYTES_IPV6 = 18
 )
 
 var (
@@ -40,15 +44,26 @@ var (
 )
 
 func AddSignedMuxHeader(w io.Writer, address []byte, token string) error {
-	if len(address) != ADDRESS_BYTES {
+	if !validMuxAddress(address) {
 		return ErrBadMuxAddress
 	}
-	header := NewAuthHeaderWriterTo([]byte(token), address, &delegateKeys)
+	header := NewAuthHeaderWriterTo([]byte(token), address, signerFor(token))
 	_, err := header.WriteTo(w)
 	return err
 }
 
+// ReadMuxHeader reads and verifies the header of a mux connection.  Tokens are
+// verified through the token cache, so that connections are still accepted
+// during a short outage of the master.
 func ReadMuxHeader(r io.Reader) ([]byte, Identity, error) {
-	sender, _, address, err := ReadAuthHeader(r)
+	sender, _, address, err := readAuthHeader(r, ParseCachedJWTIdentity)
+	if err == nil && !validMuxAddress(address) {
+		err = ErrBadMuxAddress
+	}
 	return address, sender, err
 }
+
+// validMuxAddress returns true if the address is a packed IPv4 or IPv6 address
+func validMuxAddress(address []byte) bool {
+	return len(address) == ADDRESS_BYTES || len(address) == ADDRESS_BYTES_IPV6
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/utils"
 	. "gopkg.in/check.v1"
 )
 
@@ -31,6 +32,19 @@ func (s *TestAuthSuite) TestBuildHeaderBadAddr(c *C) {
 	c.Assert(err, Equals, auth.ErrBadMuxAddress)
 }
 
+func (s *TestAuthSuite) TestBuildHeaderIPv6Addr(c *C) {
+	token, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	addr, err := utils.PackTCPAddressString("[2001:db8::10]:22250")
+	c.Assert(err, IsNil)
+	var b bytes.Buffer
+	err = auth.AddSignedMuxHeader(&b, addr, token)
+	c.Assert(err, IsNil)
+
+	extractedAddr, _, err := auth.ReadMuxHeader(&b)
+	c.Assert(err, IsNil)
+	c.Assert(utils.UnpackTCPAddressToString(extractedAddr), Equals, "[2001:db8::10]:22250")
+}
+
 func (s *TestAuthSuite) TestExtractBadHeader(c *C) {
 	mockHeader := []byte{0, 0, 0, 19, 109, 121, 32, 115, 117, 112, 101, 114, 32, 102}
 	b := bytes.NewBuffer(mockHeader)
@@ -58,3 +72,28 @@ func (s *TestAuthSuite) TestBuildAndExtractHeader(c *C) {
 	c.Assert(s.admin, Equals, ident.HasAdminAccess())
 	c.Assert(s.dfs, Equals, ident.HasDFSAccess())
 }
+
+func (s *TestAuthSuite) TestBuildHeaderKeyRotation(c *C) {
+	token, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	addr := "zenoss"
+
+	// rotate the delegate keys
+	newPub, newPriv, err := auth.GenerateRSAKeyPairPEM(nil)
+	c.Assert(err, IsNil)
+	c.Assert(auth.LoadDelegateKeysFromPEM(s.masterPubPEM, newPriv), IsNil)
+
+	// the old token is still signed with its own key
+	var b bytes.Buffer
+	c.Assert(auth.AddSignedMuxHeader(&b, []byte(addr), token), IsNil)
+	_, ident, err := auth.ReadMuxHeader(&b)
+	c.Assert(err, IsNil)
+	c.Assert(ident.HostID(), Equals, s.hostId)
+
+	// the new token is signed with the new key
+	newToken, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, newPub, time.Hour)
+	b.Reset()
+	c.Assert(auth.AddSignedMuxHeader(&b, []byte(addr), newToken), IsNil)
+	_, ident, err = auth.ReadMuxHeader(&b)
+	c.Assert(err, IsNil)
+	c.Assert(ident.HostID(), Equals, s.hostId)
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"io"
+)
+
+/*
+   A stream relayed through the master starts with an auth header whose payload is the
+   request of the relay, such as the instance to attach to, so that the relay can decide
+   whether the sender may relay the stream after the mux has connected it.
+*/
+
+// AddSignedRelayHeader writes the signed header of a relayed stream
+func AddSignedRelayHeader(w io.Writer, request []byte, token string) error {
+	header := NewAuthHeaderWriterTo([]byte(token), request, signerFor(token))
+	_, err := header.WriteTo(w)
+	return err
+}
+
+// ReadRelayHeader reads and verifies the header of a relayed stream, and
+// returns the sender and the request
+func ReadRelayHeader(r io.Reader) (Identity, []byte, error) {
+	sender, _, request, err := ReadAuthHeader(r)
+	return sender, request, err
+}
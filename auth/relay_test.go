@@ -0,0 +1,45 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package auth_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAuthSuite) TestBuildAndExtractRelayHeader(c *C) {
+	token, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	request := []byte(`{"ServiceID":"svc","InstanceID":1}`)
+	var b bytes.Buffer
+	c.Assert(auth.AddSignedRelayHeader(&b, request, token), IsNil)
+
+	ident, extracted, err := auth.ReadRelayHeader(&b)
+	c.Assert(err, IsNil)
+	c.Assert(extracted, DeepEquals, request)
+	c.Assert(ident.HostID(), Equals, s.hostId)
+	c.Assert(ident.PoolID(), Equals, s.poolId)
+	c.Assert(ident.HasAdminAccess(), Equals, s.admin)
+}
+
+func (s *TestAuthSuite) TestExtractBadRelayHeader(c *C) {
+	b := bytes.NewBufferString(`{"ServiceID":"svc","InstanceID":1}`)
+	_, _, err := auth.ReadRelayHeader(b)
+	c.Assert(err, Not(IsNil))
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+// Role is the scope of the master api calls that an identity may make
+type Role string
+
+const (
+	// RoleAdmin may make any call
+	RoleAdmin Role = "admin"
+	// RoleOperator may make read-only calls and operate services, such as
+	// starting, stopping and restarting them, but not change the
+	// configuration of the cluster
+	RoleOperator Role = "operator"
+	// RoleReadOnly may only make calls that do not change the cluster
+	RoleReadOnly Role = "readonly"
+)
+
+// rank orders the roles by the calls that they allow
+func (r Role) rank() int {
+	switch r {
+	case RoleAdmin:
+		return 3
+	case RoleOperator:
+		return 2
+	case RoleReadOnly:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Allows returns true if an identity with this role may make calls that
+// require the given role.
+func (r Role) Allows(required Role) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+// IsValid returns true if the role is one of the known roles
+func (r Role) IsValid() bool {
+	return r.rank() > 0
+}
@@ -98,9 +98,11 @@ func (r *RPCHeaderHandler) WriteHeader(w io.Writer, req []byte, writeAuth bool)
 	if writeAuth {
 		binary.Write(w, byteOrder, uint8(1))
 		// get current host token
-		var signer Signer = &delegateKeys
+		var signer Signer
 		token, err = AuthTokenNonBlocking()
-		if err != nil {
+		if err == nil {
+			signer = signerFor(token)
+		} else {
 			log.WithError(err).Debug("Unable to retrieve delegate token")
 			// We may be an un-added master
 			token, err2 = MasterToken()
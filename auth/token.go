@@ -151,9 +151,10 @@ func HasDFSAccess() bool {
 }
 
 // TokenLoop accepts a function that returns an expiring token. It will then
-// periodically refresh that token, one minute before it is due to expire,
-// setting the result as the current live token, until the done channel is
-// closed.
+// periodically refresh that token before it is due to expire, setting the
+// result as the current live token, until the done channel is closed.  The
+// token is also refreshed when the delegate keys change, so that it matches
+// the new keys.
 func TokenLoop(f TokenFunc, tokenfile string, done <-chan interface{}, forceRefresh <-chan struct{}) {
 	for {
 		expires, err := RefreshToken(f, tokenfile)
@@ -166,14 +167,7 @@ func TokenLoop(f TokenFunc, tokenfile string, done <-chan interface{}, forceRefr
 			}
 			continue
 		}
-		// Reauthenticate 'RefreshAhead' time before the token expires
-		expiration := time.Unix(expires, 0).Sub(now())
-
-		refresh := expiration - time.Duration(RefreshAhead)
-		if expiration < time.Duration(RefreshAhead) {
-			// in case it wraps around
-			refresh = expiration
-		}
+		refresh := refreshDelay(time.Unix(expires, 0).Sub(now()))
 		select {
 		case <-done:
 			return
@@ -184,6 +178,19 @@ func TokenLoop(f TokenFunc, tokenfile string, done <-chan interface{}, forceRefr
 	}
 }
 
+// refreshDelay returns how long to wait before refreshing a token that expires
+// after the given duration.  Tokens are refreshed 'RefreshAhead' time before
+// they expire, or halfway through their lifetime if it is shorter than that.
+func refreshDelay(expiration time.Duration) time.Duration {
+	if expiration <= 0 {
+		return 0
+	}
+	if expiration < 2*RefreshAhead {
+		return expiration / 2
+	}
+	return expiration - RefreshAhead
+}
+
 // Watch a token file for changes. Load the token when those changes occur.
 func WatchTokenFile(tokenfile string, done <-chan interface{}) error {
 	log := log.WithFields(logrus.Fields{
@@ -237,6 +244,32 @@ func ClearToken() {
 	cond.Broadcast()
 }
 
+// tokenSigner signs messages with the delegate key of a token
+type tokenSigner struct {
+	identity Identity
+}
+
+// Sign implements Signer
+func (s *tokenSigner) Sign(message []byte) ([]byte, error) {
+	return SignAsDelegateFor(s.identity, message)
+}
+
+// signerFor returns a signer for messages sent with the token.  It signs with
+// the delegate keys loaded at the time of signing, so that established
+// connections pick up rotated keys and tokens without reconnecting.
+func signerFor(token string) Signer {
+	cond.RLock()
+	identity := currentIdentity
+	if token != currentToken {
+		identity = nil
+	}
+	cond.RUnlock()
+	if identity == nil {
+		identity, _ = ParseJWTIdentity(token)
+	}
+	return &tokenSigner{identity}
+}
+
 func now() time.Time {
 	return time.Now().UTC()
 }
@@ -244,7 +277,7 @@ func now() time.Time {
 // expired returns whether the currently-live token has expired. If the token
 // is empty, is is considered expired for these purposes. If it is the zero
 // instant, it never expires. Otherwise, it is expired if the expiration time
-// minus a margin of error is in the past.
+// minus a margin of error and the grace period is in the past.
 func expired() bool {
 	if currentToken == "" {
 		return true
@@ -252,7 +285,10 @@ func expired() bool {
 	if expiration.IsZero() {
 		return false
 	}
-	return expiration.Add(ClockDriftDelta).Before(now())
+	if !expiration.Add(ClockDriftDelta).Before(now()) {
+		return false
+	}
+	return !useTokenInGracePeriod()
 }
 
 func updateToken(token string, expires time.Time, filename string) {
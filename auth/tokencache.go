@@ -0,0 +1,200 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+/*
+   Delegates keep working through short outages of the master with the tokens
+   they already have:
+
+   |<- token duration ->|<- ClockDriftDelta ->|<- TokenGracePeriod ->|
+   valid ...............| tolerated ..........| grace ................| expired
+
+   While the master cannot be reached, a delegate keeps sending its last token
+   until the grace period is over, and the mux accepts tokens that it has
+   verified before, or that are correctly signed, until the grace period of the
+   token is over.  Once the grace period is over, the token is expired: the
+   delegate waits for a new token and the mux rejects the connection with
+   ErrIdentityTokenExpired.
+*/
+
+// MaxCachedIdentities is the number of verified tokens the mux keeps
+const MaxCachedIdentities = 1024
+
+var (
+	// TokenGracePeriod is how long an expired token stays in use while the
+	// master cannot issue a new one
+	TokenGracePeriod time.Duration
+
+	identityCache = &tokenCache{identities: make(map[string]*jwtIdentity)}
+
+	tokenCacheHits    int64
+	tokenCacheMisses  int64
+	tokenGraceServed  int64
+	tokenGraceExpired int64
+
+	// graceToken is the live token while it is in its grace period
+	graceToken string
+	graceLock  sync.Mutex
+)
+
+// TokenCacheStats are the counters of the authentication token cache
+type TokenCacheStats struct {
+	Hits    int64 // Tokens verified from the cache
+	Misses  int64 // Tokens that had to be verified against the master key
+	Grace   int64 // Tokens used or accepted within their grace period
+	Expired int64 // Tokens rejected or withheld after their grace period
+}
+
+// GetTokenCacheStats returns the counters of the authentication token cache
+func GetTokenCacheStats() TokenCacheStats {
+	return TokenCacheStats{
+		Hits:    atomic.LoadInt64(&tokenCacheHits),
+		Misses:  atomic.LoadInt64(&tokenCacheMisses),
+		Grace:   atomic.LoadInt64(&tokenGraceServed),
+		Expired: atomic.LoadInt64(&tokenGraceExpired),
+	}
+}
+
+// ClearTokenCache forgets all verified tokens, so that they are verified
+// against the master key again
+func ClearTokenCache() {
+	identityCache.clear()
+}
+
+// tokenCache keeps the identities of tokens that have been verified
+type tokenCache struct {
+	sync.Mutex
+	identities map[string]*jwtIdentity
+}
+
+func (c *tokenCache) get(token string) *jwtIdentity {
+	c.Lock()
+	defer c.Unlock()
+	return c.identities[token]
+}
+
+func (c *tokenCache) put(token string, identity *jwtIdentity) {
+	c.Lock()
+	defer c.Unlock()
+	if len(c.identities) >= MaxCachedIdentities {
+		for t, id := range c.identities {
+			if id.expiredAfter(TokenGracePeriod) {
+				delete(c.identities, t)
+			}
+		}
+		if len(c.identities) >= MaxCachedIdentities {
+			c.identities = make(map[string]*jwtIdentity)
+		}
+	}
+	c.identities[token] = identity
+}
+
+func (c *tokenCache) remove(token string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.identities, token)
+}
+
+func (c *tokenCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.identities = make(map[string]*jwtIdentity)
+}
+
+// ParseCachedJWTIdentity is ParseJWTIdentity for connections that should
+// survive an outage of the master.  Tokens that have been verified are not
+// verified again, and tokens are accepted until their grace period is over.
+func ParseCachedJWTIdentity(token string) (Identity, error) {
+	if identity := identityCache.get(token); identity != nil {
+		if err := checkGracePeriod(identity); err != nil {
+			identityCache.remove(token)
+			return nil, err
+		}
+		atomic.AddInt64(&tokenCacheHits, 1)
+		return identity, nil
+	}
+	atomic.AddInt64(&tokenCacheMisses, 1)
+
+	claims := &jwtIdentity{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	parsed, err := parser.ParseWithClaims(token, claims, masterKeyFunc)
+	if err != nil {
+		return nil, jwtError(err)
+	}
+	identity, ok := parsed.Claims.(*jwtIdentity)
+	if !ok || !parsed.Valid {
+		return nil, ErrIdentityTokenInvalid
+	}
+	if identity.notValidYet() {
+		return nil, ErrIdentityTokenNotValidYet
+	}
+	if err := checkGracePeriod(identity); err != nil {
+		return nil, err
+	}
+	identityCache.put(token, identity)
+	return identity, nil
+}
+
+// checkGracePeriod returns ErrIdentityTokenExpired if the grace period of the
+// identity is over, and counts the identities that are accepted within their
+// grace period.
+func checkGracePeriod(identity *jwtIdentity) error {
+	if !identity.Expired() {
+		return nil
+	}
+	if identity.expiredAfter(TokenGracePeriod) {
+		atomic.AddInt64(&tokenGraceExpired, 1)
+		return ErrIdentityTokenExpired
+	}
+	atomic.AddInt64(&tokenGraceServed, 1)
+	log.WithFields(logrus.Fields{
+		"hostid":    identity.HostID(),
+		"expiresat": time.Unix(identity.ExpiresAt, 0).UTC(),
+	}).Debug("Accepted an expired token within its grace period")
+	return nil
+}
+
+// useTokenInGracePeriod returns whether the currently-live token, which is
+// past its expiration, may still be sent.  It logs once per token when the
+// token goes into and out of its grace period.
+func useTokenInGracePeriod() bool {
+	graceLock.Lock()
+	defer graceLock.Unlock()
+	if expiration.Add(ClockDriftDelta + TokenGracePeriod).Before(now()) {
+		if graceToken == currentToken {
+			graceToken = ""
+			atomic.AddInt64(&tokenGraceExpired, 1)
+			log.WithField("expiration", expiration).Error("Authentication token expired at the end of its grace period; waiting for a new token from the master")
+		}
+		return false
+	}
+	atomic.AddInt64(&tokenGraceServed, 1)
+	if graceToken != currentToken {
+		graceToken = currentToken
+		log.WithFields(logrus.Fields{
+			"expiration":  expiration,
+			"graceperiod": TokenGracePeriod,
+		}).Warn("Authentication token expired; using it until the master issues a new one or the grace period is over")
+	}
+	return true
+}
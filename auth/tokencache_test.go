@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package auth_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAuthSuite) TestCachedIdentity(c *C) {
+	token, _, _ := auth.CreateJWTIdentity(s.hostId, s.poolId, s.admin, s.dfs, s.delegatePubPEM, time.Hour)
+	stats := auth.GetTokenCacheStats()
+
+	ident, err := auth.ParseCachedJWTIdentity(token)
+	c.Assert(err, IsNil)
+	c.Assert(ident.HostID(), Equals, s.hostId)
+	ident, err = auth.ParseCachedJWTIdentity(token)
+	c.Assert(err, IsNil)
+	c.Assert(ident.HostID(), Equals, s.hostId)
+
+	after := auth.GetTokenCacheStats()
+	c.Assert(after.Misses-stats.Misses, Equals, int64(1))
+	c.Assert(after.Hits-stats.Hits, Equals, int64(1))
+
+	// rotating the keys forgets the verified tokens
+	c.Assert(auth.LoadDelegateKeysFromPEM(s.masterPubPEM, s.delegatePrivPEM), IsNil)
+	_, err = auth.ParseCachedJWTIdentity(token)
+	c.Assert(err, IsNil)
+	c.Assert(auth.GetTokenCacheStats().Misses-after.Misses, Equals, int64(1))
+}
+
+func (s *TestAuthSuite) TestCachedIdentityBadSignature(c *C) {
+	auth.LoadMasterKeysFromPEM(s.masterPubPEM, s.delegatePrivPEM)
+	token, _, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err := auth.ParseCachedJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenBadSig)
+	}
+}
+
+func (s *TestAuthSuite) TestCachedIdentityGracePeriod(c *C) {
+	defer func(grace time.Duration) { auth.TokenGracePeriod = grace }(auth.TokenGracePeriod)
+	token, _, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
+	stats := auth.GetTokenCacheStats()
+
+	// without a grace period, expired tokens are rejected
+	auth.TokenGracePeriod = 0
+	auth.At(time.Now().UTC().Add(10*time.Minute), func() {
+		_, err := auth.ParseCachedJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	})
+
+	// within the grace period, expired tokens are accepted
+	auth.TokenGracePeriod = 30 * time.Minute
+	auth.At(time.Now().UTC().Add(10*time.Minute), func() {
+		ident, err := auth.ParseCachedJWTIdentity(token)
+		c.Assert(err, IsNil)
+		c.Assert(ident.HostID(), Equals, "host")
+		_, err = auth.ParseCachedJWTIdentity(token)
+		c.Assert(err, IsNil)
+	})
+	c.Assert(auth.GetTokenCacheStats().Grace-stats.Grace, Equals, int64(2))
+
+	// after the grace period, cached tokens are rejected too
+	auth.At(time.Now().UTC().Add(time.Hour), func() {
+		_, err := auth.ParseCachedJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	})
+	c.Assert(auth.GetTokenCacheStats().Expired-stats.Expired, Equals, int64(2))
+
+	// the token still expires as usual outside of the mux
+	auth.At(time.Now().UTC().Add(10*time.Minute), func() {
+		_, err := auth.ParseJWTIdentity(token)
+		c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+	})
+}
+
+func (s *TestAuthSuite) TestAuthTokenGracePeriod(c *C) {
+	defer func(grace time.Duration) { auth.TokenGracePeriod = grace }(auth.TokenGracePeriod)
+	defer auth.ClearToken()
+	token, _, _ := auth.CreateJWTIdentity("host", "pool", true, false, s.delegatePubPEM, time.Minute)
+	expired := func() (string, int64, error) {
+		return token, time.Now().Add(-10 * time.Minute).Unix(), nil
+	}
+	_, err := auth.RefreshToken(expired, "")
+	c.Assert(err, IsNil)
+
+	auth.TokenGracePeriod = 0
+	_, err = auth.AuthTokenNonBlocking()
+	c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+
+	auth.TokenGracePeriod = 30 * time.Minute
+	current, err := auth.AuthTokenNonBlocking()
+	c.Assert(err, IsNil)
+	c.Assert(current, Equals, token)
+
+	auth.TokenGracePeriod = time.Minute
+	_, err = auth.AuthTokenNonBlocking()
+	c.Assert(err, Equals, auth.ErrIdentityTokenExpired)
+}
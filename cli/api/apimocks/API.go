@@ -1,19 +1,33 @@
 package mocks
 
 import api "github.com/control-center/serviced/cli/api"
+import audit "github.com/control-center/serviced/audit"
+import startup "github.com/control-center/serviced/commons/startup"
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import backupschedule "github.com/control-center/serviced/domain/backupschedule"
+import calendar "github.com/control-center/serviced/domain/calendar"
+import delegateprofile "github.com/control-center/serviced/domain/delegateprofile"
+import event "github.com/control-center/serviced/domain/event"
+import feature "github.com/control-center/serviced/domain/feature"
+import pin "github.com/control-center/serviced/domain/pin"
 import dao "github.com/control-center/serviced/dao"
+import dfs "github.com/control-center/serviced/dfs"
 import host "github.com/control-center/serviced/domain/host"
 import io "io"
 import isvcs "github.com/control-center/serviced/isvcs"
+import master "github.com/control-center/serviced/rpc/master"
 import metrics "github.com/control-center/serviced/metrics"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
 import script "github.com/control-center/serviced/script"
+import secret "github.com/control-center/serviced/domain/secret"
 import "github.com/control-center/serviced/utils"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import setting "github.com/control-center/serviced/domain/setting"
+import time "time"
+import validation "github.com/control-center/serviced/validation"
 import volume "github.com/control-center/serviced/volume"
 
 // API is an autogenerated mock type for the API type
@@ -21,6 +35,20 @@ type API struct {
 	mock.Mock
 }
 
+// AddCalendar provides a mock function with given fields: _a0
+func (_m *API) AddCalendar(_a0 calendar.Calendar) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(calendar.Calendar) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: _a0
 func (_m *API) AddHost(_a0 api.HostConfig) (*host.Host, []byte, error) {
 	ret := _m.Called(_a0)
@@ -235,6 +263,29 @@ func (_m *API) AddVirtualIP(_a0 pool.VirtualIP) error {
 	return r0
 }
 
+// AddZKEnsembleMember provides a mock function with given fields: host
+func (_m *API) AddZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	ret := _m.Called(host)
+
+	var r0 *master.ZKEnsembleChange
+	if rf, ok := ret.Get(0).(func(string) *master.ZKEnsembleChange); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*master.ZKEnsembleChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AssignIP provides a mock function with given fields: _a0
 func (_m *API) AssignIP(_a0 api.IPConfig) error {
 	ret := _m.Called(_a0)
@@ -249,26 +300,33 @@ func (_m *API) AssignIP(_a0 api.IPConfig) error {
 	return r0
 }
 
-// RemoveIP provides a mock function with given fields: args
-func (_m *API) RemoveIP(args []string) error {
-	ret := _m.Called(args)
+// DeployServiceCanary provides a mock function with given fields: _a0
+func (_m *API) DeployServiceCanary(_a0 api.CanaryConfig) (string, error) {
+	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]string) error); ok {
-		r0 = rf(args)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(api.CanaryConfig) string); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.CanaryConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// SetIP provides a mock function with given fields: _a0
-func (_m *API) SetIP(_a0 api.IPConfig) error {
+// DiscardBackupOperation provides a mock function with given fields: _a0
+func (_m *API) DiscardBackupOperation(_a0 string) error {
 	ret := _m.Called(_a0)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(api.IPConfig) error); ok {
+	if rf, ok := ret.Get(0).(func(string) error); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Error(0)
@@ -277,62 +335,82 @@ func (_m *API) SetIP(_a0 api.IPConfig) error {
 	return r0
 }
 
-// AttachServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args
-func (_m *API) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
-	ret := _m.Called(serviceID, instanceID, command, args)
+// GetBackupOperations provides a mock function with given fields:
+func (_m *API) GetBackupOperations() ([]dao.BackupOperation, error) {
+	ret := _m.Called()
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
-		r0 = rf(serviceID, instanceID, command, args)
+	var r0 []dao.BackupOperation
+	if rf, ok := ret.Get(0).(func() []dao.BackupOperation); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.BackupOperation)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// AuthenticateHost provides a mock function with given fields: _a0
-func (_m *API) AuthenticateHost(_a0 string) (string, int64, error) {
-	ret := _m.Called(_a0)
+// GetDFSOperations provides a mock function with given fields:
+func (_m *API) GetDFSOperations() ([]dao.DFSOperation, error) {
+	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(_a0)
+	var r0 []dao.DFSOperation
+	if rf, ok := ret.Get(0).(func() []dao.DFSOperation); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.DFSOperation)
+		}
 	}
 
-	var r1 int64
-	if rf, ok := ret.Get(1).(func(string) int64); ok {
-		r1 = rf(_a0)
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
-		r1 = ret.Get(1).(int64)
+		r1 = ret.Error(1)
 	}
 
-	var r2 error
-	if rf, ok := ret.Get(2).(func(string) error); ok {
-		r2 = rf(_a0)
+	return r0, r1
+}
+
+// CancelOperation provides a mock function with given fields: _a0
+func (_m *API) CancelOperation(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
 	} else {
-		r2 = ret.Error(2)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1, r2
+	return r0
 }
 
-// Backup provides a mock function with given fields: _a0, _a1
-func (_m *API) Backup(_a0 string, _a1 []string, _a2 bool) (string, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// GetOperation provides a mock function with given fields: _a0
+func (_m *API) GetOperation(_a0 string) (*dao.Operation, error) {
+	ret := _m.Called(_a0)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string, []string, bool) string); ok {
-		r0 = rf(_a0, _a1, _a2)
+	var r0 *dao.Operation
+	if rf, ok := ret.Get(0).(func(string) *dao.Operation); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Operation)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, []string, bool) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -340,22 +418,22 @@ func (_m *API) Backup(_a0 string, _a1 []string, _a2 bool) (string, error) {
 	return r0, r1
 }
 
-// CloneService provides a mock function with given fields: _a0, _a1
-func (_m *API) CloneService(_a0 string, _a1 string) (*service.ServiceDetails, error) {
-	ret := _m.Called(_a0, _a1)
+// GetOperations provides a mock function with given fields:
+func (_m *API) GetOperations() ([]dao.Operation, error) {
+	ret := _m.Called()
 
-	var r0 *service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(string, string) *service.ServiceDetails); ok {
-		r0 = rf(_a0, _a1)
+	var r0 []dao.Operation
+	if rf, ok := ret.Get(0).(func() []dao.Operation); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.ServiceDetails)
+			r0 = ret.Get(0).([]dao.Operation)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -363,21 +441,21 @@ func (_m *API) CloneService(_a0 string, _a1 string) (*service.ServiceDetails, er
 	return r0, r1
 }
 
-// CompileServiceTemplate provides a mock function with given fields: _a0
-func (_m *API) CompileServiceTemplate(_a0 api.CompileTemplateConfig) (*servicetemplate.ServiceTemplate, error) {
+// GetCalendar provides a mock function with given fields: _a0
+func (_m *API) GetCalendar(_a0 string) (*calendar.Calendar, error) {
 	ret := _m.Called(_a0)
 
-	var r0 *servicetemplate.ServiceTemplate
-	if rf, ok := ret.Get(0).(func(api.CompileTemplateConfig) *servicetemplate.ServiceTemplate); ok {
+	var r0 *calendar.Calendar
+	if rf, ok := ret.Get(0).(func(string) *calendar.Calendar); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*servicetemplate.ServiceTemplate)
+			r0 = ret.Get(0).(*calendar.Calendar)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(api.CompileTemplateConfig) error); ok {
+	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -386,15 +464,17 @@ func (_m *API) CompileServiceTemplate(_a0 api.CompileTemplateConfig) (*servicete
 	return r0, r1
 }
 
-// DebugDisableMetrics provides a mock function with given fields:
-func (_m *API) DebugDisableMetrics() (string, error) {
+// GetCalendars provides a mock function with given fields:
+func (_m *API) GetCalendars() ([]calendar.Calendar, error) {
 	ret := _m.Called()
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
+	var r0 []calendar.Calendar
+	if rf, ok := ret.Get(0).(func() []calendar.Calendar); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]calendar.Calendar)
+		}
 	}
 
 	var r1 error
@@ -407,20 +487,22 @@ func (_m *API) DebugDisableMetrics() (string, error) {
 	return r0, r1
 }
 
-// DebugEnableMetrics provides a mock function with given fields:
-func (_m *API) DebugEnableMetrics() (string, error) {
-	ret := _m.Called()
+// GetAuditEntries provides a mock function with given fields: _a0
+func (_m *API) GetAuditEntries(_a0 time.Time) ([]audit.Entry, error) {
+	ret := _m.Called(_a0)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func() string); ok {
-		r0 = rf()
+	var r0 []audit.Entry
+	if rf, ok := ret.Get(0).(func(time.Time) []audit.Entry); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]audit.Entry)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -428,21 +510,21 @@ func (_m *API) DebugEnableMetrics() (string, error) {
 	return r0, r1
 }
 
-// DeployServiceTemplate provides a mock function with given fields: _a0
-func (_m *API) DeployServiceTemplate(_a0 api.DeployTemplateConfig) ([]service.ServiceDetails, error) {
+// QueryEvents provides a mock function with given fields: _a0
+func (_m *API) QueryEvents(_a0 event.Query) (*event.Page, error) {
 	ret := _m.Called(_a0)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(api.DeployTemplateConfig) []service.ServiceDetails); ok {
+	var r0 *event.Page
+	if rf, ok := ret.Get(0).(func(event.Query) *event.Page); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).(*event.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(api.DeployTemplateConfig) error); ok {
+	if rf, ok := ret.Get(1).(func(event.Query) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -451,55 +533,87 @@ func (_m *API) DeployServiceTemplate(_a0 api.DeployTemplateConfig) ([]service.Se
 	return r0, r1
 }
 
-// DockerOverride provides a mock function with given fields: newImage, oldImage
-func (_m *API) DockerOverride(newImage string, oldImage string) error {
-	ret := _m.Called(newImage, oldImage)
+// GetServiceImagePins provides a mock function with given fields: _a0
+func (_m *API) GetServiceImagePins(_a0 string) ([]service.ImagePin, error) {
+	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(newImage, oldImage)
+	var r0 []service.ImagePin
+	if rf, ok := ret.Get(0).(func(string) []service.ImagePin); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ImagePin)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// EnablePublicEndpointPort provides a mock function with given fields: serviceid, endpointName, portAddr, isEnabled
-func (_m *API) EnablePublicEndpointPort(serviceid string, endpointName string, portAddr string, isEnabled bool) error {
-	ret := _m.Called(serviceid, endpointName, portAddr, isEnabled)
+// PruneBackupLayers provides a mock function with given fields:
+func (_m *API) PruneBackupLayers() (int, int64, error) {
+	ret := _m.Called()
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
-		r0 = rf(serviceid, endpointName, portAddr, isEnabled)
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int)
 	}
 
-	return r0
+	var r1 int64
+	if rf, ok := ret.Get(1).(func() int64); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// EnablePublicEndpointVHost provides a mock function with given fields: serviceid, endpointName, vhost, isEnabled
-func (_m *API) EnablePublicEndpointVHost(serviceid string, endpointName string, vhost string, isEnabled bool) error {
-	ret := _m.Called(serviceid, endpointName, vhost, isEnabled)
+// RefreshServiceImagePins provides a mock function with given fields: _a0
+func (_m *API) RefreshServiceImagePins(_a0 string) ([]service.ImagePin, error) {
+	ret := _m.Called(_a0)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
-		r0 = rf(serviceid, endpointName, vhost, isEnabled)
+	var r0 []service.ImagePin
+	if rf, ok := ret.Get(0).(func(string) []service.ImagePin); ok {
+		r0 = rf(_a0)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ImagePin)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// ExportLogs provides a mock function with given fields: config
-func (_m *API) ExportLogs(config api.ExportLogsConfig) error {
-	ret := _m.Called(config)
+// SetMaintenanceMode provides a mock function with given fields: _a0, _a1
+func (_m *API) SetMaintenanceMode(_a0 string, _a1 *service.MaintenanceMode) error {
+	ret := _m.Called(_a0, _a1)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(api.ExportLogsConfig) error); ok {
-		r0 = rf(config)
+	if rf, ok := ret.Get(0).(func(string, *service.MaintenanceMode) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -507,22 +621,22 @@ func (_m *API) ExportLogs(config api.ExportLogsConfig) error {
 	return r0
 }
 
-// GetAllPublicEndpoints provides a mock function with given fields:
-func (_m *API) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
-	ret := _m.Called()
+// VerifyBackup provides a mock function with given fields: path, testRestore
+func (_m *API) VerifyBackup(path string, testRestore bool) (*dfs.BackupVerification, error) {
+	ret := _m.Called(path, testRestore)
 
-	var r0 []service.PublicEndpoint
-	if rf, ok := ret.Get(0).(func() []service.PublicEndpoint); ok {
-		r0 = rf()
+	var r0 *dfs.BackupVerification
+	if rf, ok := ret.Get(0).(func(string, bool) *dfs.BackupVerification); ok {
+		r0 = rf(path, testRestore)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.PublicEndpoint)
+			r0 = ret.Get(0).(*dfs.BackupVerification)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(path, testRestore)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -530,16 +644,16 @@ func (_m *API) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	return r0, r1
 }
 
-// GetAllServiceDetails provides a mock function with given fields:
-func (_m *API) GetAllServiceDetails() ([]service.ServiceDetails, error) {
+// GetBackupProgress provides a mock function with given fields:
+func (_m *API) GetBackupProgress() (*dao.BackupProgress, error) {
 	ret := _m.Called()
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func() []service.ServiceDetails); ok {
+	var r0 *dao.BackupProgress
+	if rf, ok := ret.Get(0).(func() *dao.BackupProgress); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).(*dao.BackupProgress)
 		}
 	}
 
@@ -553,21 +667,735 @@ func (_m *API) GetAllServiceDetails() ([]service.ServiceDetails, error) {
 	return r0, r1
 }
 
-// GetEndpoints provides a mock function with given fields: serviceID, reportImports, reportExports, validate
-func (_m *API) GetEndpoints(serviceID string, reportImports bool, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error) {
-	ret := _m.Called(serviceID, reportImports, reportExports, validate)
+// GetBackupSchedules provides a mock function with given fields:
+func (_m *API) GetBackupSchedules() ([]backupschedule.BackupSchedule, error) {
+	ret := _m.Called()
 
-	var r0 []applicationendpoint.EndpointReport
-	if rf, ok := ret.Get(0).(func(string, bool, bool, bool) []applicationendpoint.EndpointReport); ok {
-		r0 = rf(serviceID, reportImports, reportExports, validate)
+	var r0 []backupschedule.BackupSchedule
+	if rf, ok := ret.Get(0).(func() []backupschedule.BackupSchedule); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]applicationendpoint.EndpointReport)
+			r0 = ret.Get(0).([]backupschedule.BackupSchedule)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, bool, bool, bool) error); ok {
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddBackupSchedule provides a mock function with given fields: schedule
+func (_m *API) AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error) {
+	ret := _m.Called(schedule)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(backupschedule.BackupSchedule) string); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(backupschedule.BackupSchedule) error); ok {
+		r1 = rf(schedule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveBackupSchedule provides a mock function with given fields: id
+func (_m *API) RemoveBackupSchedule(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveCalendar provides a mock function with given fields: _a0
+func (_m *API) RemoveCalendar(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFeatureFlags provides a mock function with given fields:
+func (_m *API) GetFeatureFlags() ([]feature.Flag, error) {
+	ret := _m.Called()
+
+	var r0 []feature.Flag
+	if rf, ok := ret.Get(0).(func() []feature.Flag); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]feature.Flag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnableFeature provides a mock function with given fields: name, poolID, deploymentID
+func (_m *API) EnableFeature(name string, poolID string, deploymentID string) error {
+	ret := _m.Called(name, poolID, deploymentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, poolID, deploymentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableFeature provides a mock function with given fields: name, poolID, deploymentID
+func (_m *API) DisableFeature(name string, poolID string, deploymentID string) error {
+	ret := _m.Called(name, poolID, deploymentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, poolID, deploymentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSettings provides a mock function with given fields:
+func (_m *API) GetSettings() ([]setting.Setting, error) {
+	ret := _m.Called()
+
+	var r0 []setting.Setting
+	if rf, ok := ret.Get(0).(func() []setting.Setting); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]setting.Setting)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSetting provides a mock function with given fields: name, scope, scopeID, value
+func (_m *API) SetSetting(name string, scope setting.Scope, scopeID string, value string) error {
+	ret := _m.Called(name, scope, scopeID, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, setting.Scope, string, string) error); ok {
+		r0 = rf(name, scope, scopeID, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsetSetting provides a mock function with given fields: name, scope, scopeID
+func (_m *API) UnsetSetting(name string, scope setting.Scope, scopeID string) error {
+	ret := _m.Called(name, scope, scopeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, setting.Scope, string) error); ok {
+		r0 = rf(name, scope, scopeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields:
+func (_m *API) GetSecrets() ([]secret.Secret, error) {
+	ret := _m.Called()
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func() []secret.Secret); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]secret.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSecretValue provides a mock function with given fields: _a0
+func (_m *API) GetSecretValue(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSecret provides a mock function with given fields: name, description, value
+func (_m *API) SetSecret(name string, description string, value string) error {
+	ret := _m.Called(name, description, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, description, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveSecret provides a mock function with given fields: _a0
+func (_m *API) RemoveSecret(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveIP provides a mock function with given fields: args
+func (_m *API) RemoveIP(args []string) error {
+	ret := _m.Called(args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeBackupOperation provides a mock function with given fields: _a0
+func (_m *API) ResumeBackupOperation(_a0 string) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetHostMaintenance provides a mock function with given fields: _a0, _a1
+func (_m *API) SetHostMaintenance(_a0 string, _a1 bool) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetIP provides a mock function with given fields: _a0
+func (_m *API) SetIP(_a0 api.IPConfig) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.IPConfig) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args
+func (_m *API) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
+	ret := _m.Called(serviceID, instanceID, command, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
+		r0 = rf(serviceID, instanceID, command, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuthenticateHost provides a mock function with given fields: _a0
+func (_m *API) AuthenticateHost(_a0 string) (string, int64, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(string) int64); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(_a0)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Backup provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *API) Backup(_a0 string, _a1 []string, _a2 bool, _a3 string) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, []string, bool, string) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, bool, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CloneService provides a mock function with given fields: _a0, _a1
+func (_m *API) CloneService(_a0 string, _a1 string) (*service.ServiceDetails, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string, string) *service.ServiceDetails); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompileServiceTemplate provides a mock function with given fields: _a0
+func (_m *API) CompileServiceTemplate(_a0 api.CompileTemplateConfig) (*servicetemplate.ServiceTemplate, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *servicetemplate.ServiceTemplate
+	if rf, ok := ret.Get(0).(func(api.CompileTemplateConfig) *servicetemplate.ServiceTemplate); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.ServiceTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.CompileTemplateConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DebugDisableMetrics provides a mock function with given fields:
+func (_m *API) DebugDisableMetrics() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DebugEnableMetrics provides a mock function with given fields:
+func (_m *API) DebugEnableMetrics() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CollectProfiles provides a mock function with given fields: hostID, profiles, duration
+func (_m *API) CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	ret := _m.Called(hostID, profiles, duration)
+
+	var r0 map[string][]byte
+	if rf, ok := ret.Get(0).(func(string, []string, time.Duration) map[string][]byte); ok {
+		r0 = rf(hostID, profiles, duration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, time.Duration) error); ok {
+		r1 = rf(hostID, profiles, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeployServiceTemplate provides a mock function with given fields: _a0
+func (_m *API) DeployServiceTemplate(_a0 api.DeployTemplateConfig) ([]service.ServiceDetails, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(api.DeployTemplateConfig) []service.ServiceDetails); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(api.DeployTemplateConfig) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradeServiceTemplate provides a mock function with given fields: _a0, _a1, _a2
+func (_m *API) UpgradeServiceTemplate(_a0 string, _a1 string, _a2 bool) ([]servicetemplate.TemplateChange, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []servicetemplate.TemplateChange
+	if rf, ok := ret.Get(0).(func(string, string, bool) []servicetemplate.TemplateChange); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicetemplate.TemplateChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DockerOverride provides a mock function with given fields: newImage, oldImage
+func (_m *API) DockerOverride(newImage string, oldImage string) error {
+	ret := _m.Called(newImage, oldImage)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(newImage, oldImage)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnablePublicEndpointPort provides a mock function with given fields: serviceid, endpointName, portAddr, isEnabled
+func (_m *API) EnablePublicEndpointPort(serviceid string, endpointName string, portAddr string, isEnabled bool) error {
+	ret := _m.Called(serviceid, endpointName, portAddr, isEnabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr, isEnabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnablePublicEndpointVHost provides a mock function with given fields: serviceid, endpointName, vhost, isEnabled
+func (_m *API) EnablePublicEndpointVHost(serviceid string, endpointName string, vhost string, isEnabled bool) error {
+	ret := _m.Called(serviceid, endpointName, vhost, isEnabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
+		r0 = rf(serviceid, endpointName, vhost, isEnabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostCertificate provides a mock function with given fields: serviceid, vhost, certPEM, keyPEM
+func (_m *API) SetPublicEndpointVHostCertificate(serviceid string, vhost string, certPEM []byte, keyPEM []byte) error {
+	ret := _m.Called(serviceid, vhost, certPEM, keyPEM)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, []byte, []byte) error); ok {
+		r0 = rf(serviceid, vhost, certPEM, keyPEM)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportLogs provides a mock function with given fields: config
+func (_m *API) ExportLogs(config api.ExportLogsConfig) error {
+	ret := _m.Called(config)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.ExportLogsConfig) error); ok {
+		r0 = rf(config)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportState provides a mock function with given fields:
+func (_m *API) ExportState() (*api.ClusterState, error) {
+	ret := _m.Called()
+
+	var r0 *api.ClusterState
+	if rf, ok := ret.Get(0).(func() *api.ClusterState); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.ClusterState)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllPublicEndpoints provides a mock function with given fields:
+func (_m *API) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
+	ret := _m.Called()
+
+	var r0 []service.PublicEndpoint
+	if rf, ok := ret.Get(0).(func() []service.PublicEndpoint); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.PublicEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllServiceDetails provides a mock function with given fields:
+func (_m *API) GetAllServiceDetails() ([]service.ServiceDetails, error) {
+	ret := _m.Called()
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func() []service.ServiceDetails); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointGraph provides a mock function with given fields: serviceID
+func (_m *API) GetEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 *applicationendpoint.EndpointGraph
+	if rf, ok := ret.Get(0).(func(string) *applicationendpoint.EndpointGraph); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*applicationendpoint.EndpointGraph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointMetrics provides a mock function with given fields: serviceID, window
+func (_m *API) GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	ret := _m.Called(serviceID, window)
+
+	var r0 []applicationendpoint.EndpointMetrics
+	if rf, ok := ret.Get(0).(func(string, time.Duration) []applicationendpoint.EndpointMetrics); ok {
+		r0 = rf(serviceID, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointMetrics)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(serviceID, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpoints provides a mock function with given fields: serviceID, reportImports, reportExports, validate
+func (_m *API) GetEndpoints(serviceID string, reportImports bool, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error) {
+	ret := _m.Called(serviceID, reportImports, reportExports, validate)
+
+	var r0 []applicationendpoint.EndpointReport
+	if rf, ok := ret.Get(0).(func(string, bool, bool, bool) []applicationendpoint.EndpointReport); ok {
+		r0 = rf(serviceID, reportImports, reportExports, validate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool, bool, bool) error); ok {
 		r1 = rf(serviceID, reportImports, reportExports, validate)
 	} else {
 		r1 = ret.Error(1)
@@ -737,6 +1565,29 @@ func (_m *API) GetHostsWithAuthInfo() ([]api.AuthHost, error) {
 	return r0, r1
 }
 
+// GetHostsWithAuthInfoInPool provides a mock function with given fields: _a0
+func (_m *API) GetHostsWithAuthInfoInPool(_a0 string) ([]api.AuthHost, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []api.AuthHost
+	if rf, ok := ret.Get(0).(func(string) []api.AuthHost); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.AuthHost)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPoolIPs provides a mock function with given fields: _a0
 func (_m *API) GetPoolIPs(_a0 string) (*pool.PoolIPs, error) {
 	ret := _m.Called(_a0)
@@ -746,13 +1597,87 @@ func (_m *API) GetPoolIPs(_a0 string) (*pool.PoolIPs, error) {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*pool.PoolIPs)
+			r0 = ret.Get(0).(*pool.PoolIPs)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicEndpointsInPool provides a mock function with given fields: _a0
+func (_m *API) GetPublicEndpointsInPool(_a0 string) ([]service.PublicEndpoint, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []service.PublicEndpoint
+	if rf, ok := ret.Get(0).(func(string) []service.PublicEndpoint); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.PublicEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCalendar provides a mock function with given fields: _a0
+func (_m *API) UpdateCalendar(_a0 calendar.Calendar) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(calendar.Calendar) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateHostLabels provides a mock function with given fields: _a0, _a1, _a2
+func (_m *API) UpdateHostLabels(_a0 string, _a1 map[string]string, _a2 []string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string, []string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchTop provides a mock function with given fields: cfg, done
+func (_m *API) WatchTop(cfg api.TopConfig, done <-chan struct{}) (<-chan api.TopView, error) {
+	ret := _m.Called(cfg, done)
+
+	var r0 <-chan api.TopView
+	if rf, ok := ret.Get(0).(func(api.TopConfig, <-chan struct{}) <-chan api.TopView); ok {
+		r0 = rf(cfg, done)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan api.TopView)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(api.TopConfig, <-chan struct{}) error); ok {
+		r1 = rf(cfg, done)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -852,6 +1777,29 @@ func (_m *API) GetServiceDetails(serviceID string) (*service.ServiceDetails, err
 	return r0, r1
 }
 
+// GetServiceDetailsInPool provides a mock function with given fields: _a0
+func (_m *API) GetServiceDetailsInPool(_a0 string) ([]service.ServiceDetails, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceInstances provides a mock function with given fields: serviceID
 func (_m *API) GetServiceInstances(serviceID string) ([]service.Instance, error) {
 	ret := _m.Called(serviceID)
@@ -875,6 +1823,29 @@ func (_m *API) GetServiceInstances(serviceID string) ([]service.Instance, error)
 	return r0, r1
 }
 
+// GetServiceInstanceHistory provides a mock function with given fields: serviceID
+func (_m *API) GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.InstanceHistory
+	if rf, ok := ret.Get(0).(func(string) []service.InstanceHistory); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.InstanceHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceStatus provides a mock function with given fields: _a0
 func (_m *API) GetServiceStatus(_a0 string) (map[string]map[string]interface{}, error) {
 	ret := _m.Called(_a0)
@@ -898,6 +1869,29 @@ func (_m *API) GetServiceStatus(_a0 string) (map[string]map[string]interface{},
 	return r0, r1
 }
 
+// GetServiceStatusInPool provides a mock function with given fields: _a0
+func (_m *API) GetServiceStatusInPool(_a0 string) (map[string]map[string]interface{}, error) {
+	ret := _m.Called(_a0)
+
+	var r0 map[string]map[string]interface{}
+	if rf, ok := ret.Get(0).(func(string) map[string]map[string]interface{}); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[string]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceTemplate provides a mock function with given fields: _a0
 func (_m *API) GetServiceTemplate(_a0 string) (*servicetemplate.ServiceTemplate, error) {
 	ret := _m.Called(_a0)
@@ -921,6 +1915,29 @@ func (_m *API) GetServiceTemplate(_a0 string) (*servicetemplate.ServiceTemplate,
 	return r0, r1
 }
 
+// GetSchema provides a mock function with given fields: _a0
+func (_m *API) GetSchema(_a0 string) (*validation.Schema, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *validation.Schema
+	if rf, ok := ret.Get(0).(func(string) *validation.Schema); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*validation.Schema)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceTemplates provides a mock function with given fields:
 func (_m *API) GetServiceTemplates() ([]servicetemplate.ServiceTemplate, error) {
 	ret := _m.Called()
@@ -1034,6 +2051,112 @@ func (_m *API) GetVolumeStatus() (*volume.Statuses, error) {
 	return r0, r1
 }
 
+// GetZKEnsemble provides a mock function with given fields:
+func (_m *API) GetZKEnsemble() ([]isvcs.ZKMemberStatus, error) {
+	ret := _m.Called()
+
+	var r0 []isvcs.ZKMemberStatus
+	if rf, ok := ret.Get(0).(func() []isvcs.ZKMemberStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]isvcs.ZKMemberStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckDFS provides a mock function with given fields: repair
+func (_m *API) CheckDFS(repair bool) ([]dfs.FsckProblem, error) {
+	ret := _m.Called(repair)
+
+	var r0 []dfs.FsckProblem
+	if rf, ok := ret.Get(0).(func(bool) []dfs.FsckProblem); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dfs.FsckProblem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRollbackPreview provides a mock function with given fields: snapshotID
+func (_m *API) GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error) {
+	ret := _m.Called(snapshotID)
+
+	var r0 *dfs.RollbackPreview
+	if rf, ok := ret.Get(0).(func(string) *dfs.RollbackPreview); ok {
+		r0 = rf(snapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RollbackPreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(snapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResizeVolume provides a mock function with given fields: serviceID, size
+func (_m *API) ResizeVolume(serviceID string, size uint64) (*volume.Quota, error) {
+	ret := _m.Called(serviceID, size)
+
+	var r0 *volume.Quota
+	if rf, ok := ret.Get(0).(func(string, uint64) *volume.Quota); ok {
+		r0 = rf(serviceID, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*volume.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64) error); ok {
+		r1 = rf(serviceID, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LogsForService provides a mock function with given fields: cfg, w
+func (_m *API) LogsForService(cfg api.LogsForServiceConfig, w io.Writer) error {
+	ret := _m.Called(cfg, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.LogsForServiceConfig, io.Writer) error); ok {
+		r0 = rf(cfg, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LogsForServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args
 func (_m *API) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
 	ret := _m.Called(serviceID, instanceID, command, args)
@@ -1230,6 +2353,52 @@ func (_m *API) RemoveSnapshotTag(_a0 string, _a1 string) (string, error) {
 	return r0, r1
 }
 
+// GetCrossPoolFlows provides a mock function with given fields:
+func (_m *API) GetCrossPoolFlows() ([]pool.CrossPoolFlow, error) {
+	ret := _m.Called()
+
+	var r0 []pool.CrossPoolFlow
+	if rf, ok := ret.Get(0).(func() []pool.CrossPoolFlow); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.CrossPoolFlow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualIPEvents provides a mock function with given fields: poolID
+func (_m *API) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []pool.VirtualIPEvent
+	if rf, ok := ret.Get(0).(func(string) []pool.VirtualIPEvent); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.VirtualIPEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveVirtualIP provides a mock function with given fields: _a0
 func (_m *API) RemoveVirtualIP(_a0 pool.VirtualIP) error {
 	ret := _m.Called(_a0)
@@ -1244,6 +2413,29 @@ func (_m *API) RemoveVirtualIP(_a0 pool.VirtualIP) error {
 	return r0
 }
 
+// RemoveZKEnsembleMember provides a mock function with given fields: host
+func (_m *API) RemoveZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	ret := _m.Called(host)
+
+	var r0 *master.ZKEnsembleChange
+	if rf, ok := ret.Get(0).(func(string) *master.ZKEnsembleChange); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*master.ZKEnsembleChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResetHostKey provides a mock function with given fields: _a0
 func (_m *API) ResetHostKey(_a0 string) ([]byte, error) {
 	ret := _m.Called(_a0)
@@ -1360,6 +2552,34 @@ func (_m *API) Restore(_a0 string) error {
 	return r0
 }
 
+// RestoreAs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *API) RestoreAs(_a0 string, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreElastic provides a mock function with given fields: _a0, _a1
+func (_m *API) RestoreElastic(_a0 string, _a1 []string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Rollback provides a mock function with given fields: _a0, _a1
 func (_m *API) Rollback(_a0 string, _a1 bool) error {
 	ret := _m.Called(_a0, _a1)
@@ -1434,7 +2654,53 @@ func (_m *API) SendDockerAction(serviceID string, instanceID int, action string,
 		r0 = ret.Error(0)
 	}
 
-	return r0
+	return r0
+}
+
+// GetConfigDrift provides a mock function with given fields: serviceID
+func (_m *API) GetConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigDrift); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncServiceConfigs provides a mock function with given fields: serviceID, restart
+func (_m *API) SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID, restart)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string, bool) []service.ConfigDrift); ok {
+		r0 = rf(serviceID, restart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(serviceID, restart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ServicedHealthCheck provides a mock function with given fields: IServiceNames
@@ -1460,6 +2726,29 @@ func (_m *API) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHeal
 	return r0, r1
 }
 
+// GetStartupProgress provides a mock function with given fields:
+func (_m *API) GetStartupProgress() ([]startup.Status, error) {
+	ret := _m.Called()
+
+	var r0 []startup.Status
+	if rf, ok := ret.Get(0).(func() []startup.Status); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]startup.Status)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetHostMemory provides a mock function with given fields: _a0
 func (_m *API) SetHostMemory(_a0 api.HostUpdateConfig) error {
 	ret := _m.Called(_a0)
@@ -1474,6 +2763,20 @@ func (_m *API) SetHostMemory(_a0 api.HostUpdateConfig) error {
 	return r0
 }
 
+// SetHostLabels provides a mock function with given fields: _a0, _a1
+func (_m *API) SetHostLabels(_a0 string, _a1 map[string]string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, map[string]string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StartServer provides a mock function with given fields:
 func (_m *API) StartServer() error {
 	ret := _m.Called()
@@ -1579,6 +2882,20 @@ func (_m *API) StopServiceInstance(serviceID string, instanceID int) error {
 	return r0
 }
 
+// SetServiceInstanceEnvironment provides a mock function with given fields: serviceID, instanceID, env
+func (_m *API) SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error {
+	ret := _m.Called(serviceID, instanceID, env)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, []string) error); ok {
+		r0 = rf(serviceID, instanceID, env)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TagSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *API) TagSnapshot(_a0 string, _a1 string) error {
 	ret := _m.Called(_a0, _a1)
@@ -1668,6 +2985,29 @@ func (_m *API) UpgradeRegistry(endpoint string, override bool) error {
 	return r0
 }
 
+// WaitServiceStateEvents provides a mock function with given fields: serviceID, since, timeout
+func (_m *API) WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error) {
+	ret := _m.Called(serviceID, since, timeout)
+
+	var r0 *service.StateEvents
+	if rf, ok := ret.Get(0).(func(string, uint64, time.Duration) *service.StateEvents); ok {
+		r0 = rf(serviceID, since, timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.StateEvents)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64, time.Duration) error); ok {
+		r1 = rf(serviceID, since, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WriteDelegateKey provides a mock function with given fields: _a0, _a1
 func (_m *API) WriteDelegateKey(_a0 string, _a1 []byte) error {
 	ret := _m.Called(_a0, _a1)
@@ -1682,6 +3022,22 @@ func (_m *API) WriteDelegateKey(_a0 string, _a1 []byte) error {
 	return r0
 }
 
+// DistributeDelegateKeys provides a mock function with given fields: _a0, _a1
+func (_m *API) DistributeDelegateKeys(_a0 []api.DelegateKeyTarget, _a1 api.DelegateKeyOptions) []api.DelegateKeyResult {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []api.DelegateKeyResult
+	if rf, ok := ret.Get(0).(func([]api.DelegateKeyTarget, api.DelegateKeyOptions) []api.DelegateKeyResult); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]api.DelegateKeyResult)
+		}
+	}
+
+	return r0
+}
+
 // ClearEmergency provides a mock function with given fields: _a0
 func (_m *API) ClearEmergency(_a0 string) (int, error) {
 	ret := _m.Called(_a0)
@@ -1703,6 +3059,29 @@ func (_m *API) ClearEmergency(_a0 string) (int, error) {
 	return r0, r1
 }
 
+// GetServicePriorities provides a mock function with given fields: serviceID
+func (_m *API) GetServicePriorities(serviceID string) ([]service.ServicePriority, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ServicePriority
+	if rf, ok := ret.Get(0).(func(string) []service.ServicePriority); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServicePriority)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 func (_m *API) GetBackupEstimate(_a0 string, _a1 []string) (*dao.BackupEstimate, error) {
 	ret := _m.Called(_a0, _a1)
 
@@ -1724,3 +3103,257 @@ func (_m *API) GetBackupEstimate(_a0 string, _a1 []string) (*dao.BackupEstimate,
 }
 
 var _ api.API = (*API)(nil)
+
+// GetPins provides a mock function with given fields:
+func (_m *API) GetPins() ([]pin.Pin, error) {
+	ret := _m.Called()
+
+	var r0 []pin.Pin
+	if rf, ok := ret.Get(0).(func() []pin.Pin); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pin.Pin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PinSnapshot provides a mock function with given fields: snapshotID, reason
+func (_m *API) PinSnapshot(snapshotID string, reason string) error {
+	ret := _m.Called(snapshotID, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(snapshotID, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PinImage provides a mock function with given fields: image, reason
+func (_m *API) PinImage(image string, reason string) error {
+	ret := _m.Called(image, reason)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(image, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unpin provides a mock function with given fields: kind, target
+func (_m *API) Unpin(kind pin.Kind, target string) error {
+	ret := _m.Called(kind, target)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(pin.Kind, string) error); ok {
+		r0 = rf(kind, target)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDelegateProfiles provides a mock function with given fields:
+func (_m *API) GetDelegateProfiles() ([]delegateprofile.Profile, error) {
+	ret := _m.Called()
+
+	var r0 []delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func() []delegateprofile.Profile); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.Profile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelegateProfile provides a mock function with given fields: name
+func (_m *API) GetDelegateProfile(name string) (*delegateprofile.Profile, error) {
+	ret := _m.Called(name)
+
+	var r0 *delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(string) *delegateprofile.Profile); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delegateprofile.Profile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddDelegateProfile provides a mock function with given fields: name, description
+func (_m *API) AddDelegateProfile(name string, description string) error {
+	ret := _m.Called(name, description)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, description)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveDelegateProfile provides a mock function with given fields: name
+func (_m *API) RemoveDelegateProfile(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetDelegateProfileOption provides a mock function with given fields: name, option, value
+func (_m *API) SetDelegateProfileOption(name string, option string, value string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, option, value)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, option, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(name, option, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsetDelegateProfileOption provides a mock function with given fields: name, option
+func (_m *API) UnsetDelegateProfileOption(name string, option string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, option)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, option)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, option)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RollbackDelegateProfile provides a mock function with given fields: name, version
+func (_m *API) RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, version)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, int) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(name, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssignDelegateProfile provides a mock function with given fields: poolID, name
+func (_m *API) AssignDelegateProfile(poolID string, name string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(poolID, name)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(poolID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PushDelegateProfile provides a mock function with given fields: poolID
+func (_m *API) PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string) []delegateprofile.HostStatus); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
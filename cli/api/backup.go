@@ -19,13 +19,15 @@ import (
 
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backupschedule"
 	"errors"
 )
 
 // Dump all templates and services to a tgz file.
 // This includes a snapshot of all shared file systems
 // and exports all docker images the services depend on.
-func (a *api) Backup(dirpath string, excludes []string, force bool) (string, error) {
+func (a *api) Backup(dirpath string, excludes []string, force bool, compression string) (string, error) {
 	client, err := a.connectDAO()
 	if err != nil {
 		return "", err
@@ -36,6 +38,7 @@ func (a *api) Backup(dirpath string, excludes []string, force bool) (string, err
 		SnapshotSpacePercent: config.GetOptions().SnapshotSpacePercent,
 		Excludes:             excludes,
 		Force:                force,
+		Compression:          compression,
 	}
 
 	est := dao.BackupEstimate{}
@@ -65,6 +68,43 @@ func (a *api) Restore(path string) error {
 	return client.Restore(dao.RestoreRequest{Filename: filepath.Clean(fp)}, &unusedInt)
 }
 
+// RestoreAs restores the applications of a tgz file under a new deployment
+// id, alongside the applications that were backed up.
+func (a *api) RestoreAs(path, deploymentID, name string) error {
+	client, err := a.connectDAO()
+	if err != nil {
+		return err
+	}
+
+	fp, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
+	}
+
+	req := dao.RestoreRequest{
+		Filename:     filepath.Clean(fp),
+		DeploymentID: deploymentID,
+		Name:         name,
+	}
+	return client.Restore(req, &unusedInt)
+}
+
+// RestoreElastic restores the indices of the given elasticsearch clusters
+// from a tgz file, without restoring its applications.
+func (a *api) RestoreElastic(path string, clusters []string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	fp, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
+	}
+
+	return client.RestoreElastic(filepath.Clean(fp), clusters)
+}
+
 
 func (a *api) GetBackupEstimate(dirpath string, excludes []string) (*dao.BackupEstimate, error) {
 	client, err := a.connectDAO()
@@ -82,4 +122,106 @@ func (a *api) GetBackupEstimate(dirpath string, excludes []string) (*dao.BackupE
 	}
 
 	return &est, nil
-}
\ No newline at end of file
+}
+// GetBackupOperations returns the backups and restores that can be resumed
+func (a *api) GetBackupOperations() ([]dao.BackupOperation, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetBackupOperations()
+}
+
+// GetDFSOperations returns the snapshots, rollbacks, backups, restores and
+// image pushes that are running or queued
+func (a *api) GetDFSOperations() ([]dao.DFSOperation, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDFSOperations()
+}
+
+// ResumeBackupOperation continues a backup or restore from its last
+// checkpoint and returns the path to its backup file
+func (a *api) ResumeBackupOperation(id string) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	return client.ResumeBackupOperation(id)
+}
+
+// DiscardBackupOperation abandons a backup or restore
+func (a *api) DiscardBackupOperation(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.DiscardBackupOperation(id)
+}
+
+// PruneBackupLayers removes the image layers that no backup includes
+func (a *api) PruneBackupLayers() (int, int64, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return 0, 0, err
+	}
+	return client.PruneBackupLayers()
+}
+
+// VerifyBackup checks the integrity of a tgz file.  If testRestore is true,
+// the metadata of its snapshots is read as it would be restored.
+func (a *api) VerifyBackup(path string, testRestore bool) (*dfs.BackupVerification, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	fp, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
+	}
+
+	return client.VerifyBackup(filepath.Clean(fp), testRestore)
+}
+
+// GetBackupProgress returns the progress of the backup or restore that is
+// running, or of the last one
+func (a *api) GetBackupProgress() (*dao.BackupProgress, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetBackupProgress()
+}
+
+// GetBackupSchedules returns all of the scheduled backups
+func (a *api) GetBackupSchedules() ([]backupschedule.BackupSchedule, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetBackupSchedules()
+}
+
+// AddBackupSchedule adds a scheduled backup and returns its id
+func (a *api) AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+	if schedule.Dirpath != "" {
+		schedule.Dirpath = filepath.Clean(schedule.Dirpath)
+	}
+	return client.AddBackupSchedule(schedule)
+}
+
+// RemoveBackupSchedule removes a scheduled backup
+func (a *api) RemoveBackupSchedule(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.RemoveBackupSchedule(id)
+}
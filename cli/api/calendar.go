@@ -0,0 +1,68 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+// Returns a list of all calendars
+func (a *api) GetCalendars() ([]calendar.Calendar, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetCalendars()
+}
+
+// Gets a calendar given its id
+func (a *api) GetCalendar(id string) (*calendar.Calendar, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetCalendar(id)
+}
+
+// Adds a new calendar
+func (a *api) AddCalendar(c calendar.Calendar) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.AddCalendar(c)
+}
+
+// Updates an existing calendar
+func (a *api) UpdateCalendar(c calendar.Calendar) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.UpdateCalendar(c)
+}
+
+// Removes an existing calendar
+func (a *api) RemoveCalendar(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveCalendar(id)
+}
@@ -18,10 +18,14 @@ import (
 	"errors"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/commons"
 	commonsdocker "github.com/control-center/serviced/commons/docker"
+	"github.com/control-center/serviced/commons/startup"
 	"github.com/control-center/serviced/config"
 	coordclient "github.com/control-center/serviced/coordinator/client"
+	coordetcd "github.com/control-center/serviced/coordinator/client/etcd"
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dao"
@@ -30,27 +34,41 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/dfs/nfs"
+	"github.com/control-center/serviced/dfs/rbd"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/properties"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/logship"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/relay"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/scheduler"
+	"github.com/control-center/serviced/scheduler/strategy"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/shell"
 	"github.com/control-center/serviced/stats"
@@ -96,6 +114,9 @@ var (
 
 const (
 	localhost = "127.0.0.1"
+
+	// acmeAccountKeyFileName is the key of the ACME account of the master
+	acmeAccountKeyFileName = ".keys/acme-account.key"
 )
 
 type daemon struct {
@@ -167,7 +188,7 @@ func (d *daemon) getEsClusterName(name string) string {
 	return clusterName
 }
 
-func (d *daemon) startISVCS() {
+func (d *daemon) startISVCS() error {
 	options := config.GetOptions()
 	startZK := options.StartZK
 	bigtable := options.BigTableMetrics
@@ -175,21 +196,18 @@ func (d *daemon) startISVCS() {
 	isvcs.Mgr.SetVolumesDir(options.IsvcsPath)
 	servicedClusterName := d.getEsClusterName("elasticsearch-serviced")
 	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-serviced", "cluster", servicedClusterName); err != nil {
-		log.WithFields(logrus.Fields{
-			"clustername": servicedClusterName,
-		}).WithError(err).Fatal("Could not set Elastic configuration")
+		return fmt.Errorf("could not set Elastic cluster %s: %s", servicedClusterName, err)
 	}
 	logstashClusterName := d.getEsClusterName("elasticsearch-logstash")
 	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-logstash", "cluster", logstashClusterName); err != nil {
-		log.WithFields(logrus.Fields{
-			"clustername": logstashClusterName,
-		}).WithError(err).Fatal("Could not set Elastic configuration")
+		return fmt.Errorf("could not set Elastic cluster %s: %s", logstashClusterName, err)
 	}
 	if err := isvcs.Mgr.Start(); err != nil {
-		log.WithError(err).Fatal("Unable to start internal services")
+		return fmt.Errorf("unable to start internal services: %s", err)
 	}
 	log.Info("Started internal services")
 	go d.startLogstashPurger(10*time.Minute, time.Duration(options.LogstashCycleTime)*time.Hour)
+	return nil
 }
 
 func (d *daemon) startAgentISVCS(serviceNames []string) {
@@ -204,6 +222,10 @@ func (d *daemon) startAgentISVCS(serviceNames []string) {
 }
 
 func (d *daemon) stopISVCS() {
+	if isvcs.Mgr == nil {
+		// a standby master never started its internal services
+		return
+	}
 	log.Debug("Beginning shutdown of internal services")
 	if err := isvcs.Mgr.Stop(); err != nil {
 		log.WithError(err).Error("Error while stopping internal services")
@@ -248,6 +270,7 @@ func (d *daemon) startRPC() {
 		}
 		logger = logger.WithFields(logrus.Fields{
 			"ciphersuite": strings.Join(utils.CipherSuitesByName(tlsConfig), ","),
+			"minversion":  utils.TLSVersionName(tlsConfig.MinVersion),
 		})
 		listener, err = tls.Listen("tcp", options.Listen, tlsConfig)
 	}
@@ -260,13 +283,16 @@ func (d *daemon) startRPC() {
 
 	logger.Info("Listening for incoming RPC requests")
 
+	// serves grpc and jsonrpc clients on the same port
+	grpcServer := rpcutils.NewGRPCServer(d.rpcServer)
+
 	go func() {
 		for {
 			conn, err := listener.Accept()
 			if err != nil {
 				logger.WithError(err).Fatal("Error accepting RPC connection")
 			}
-			go d.rpcServer.ServeCodec(rpcutils.NewDefaultAuthServerCodec(conn))
+			go grpcServer.ServeConn(conn)
 		}
 	}()
 }
@@ -321,14 +347,20 @@ func (d *daemon) run() (err error) {
 	localClient, err := d.initZK(options.Zookeepers)
 	if err != nil {
 		log.WithError(err).WithFields(logrus.Fields{
-			"ensemble": options.Zookeepers,
+			"coordinator": options.CoordinatorDriver,
+			"ensemble":    options.Zookeepers,
+			"etcd":        options.EtcdEndpoints,
 		}).Fatal("Unable to create a local ZooKeeper client")
 	}
 	zzk.InitializeLocalClient(localClient)
 	log.Info("Established ZooKeeper connection")
+	if options.CoordinatorDriver != "etcd" {
+		go d.syncZKEnsemble()
+	}
 
-	if options.Master {
-		d.startISVCS()
+	if options.Master && options.MasterHA {
+		d.startMasterHA()
+	} else if options.Master {
 		if err := d.startMaster(); err != nil {
 			log.WithError(err).Fatal("Unable to start as a serviced master")
 		}
@@ -394,18 +426,30 @@ func (d *daemon) run() (err error) {
 	return nil
 }
 
-func (d *daemon) initContext() datastore.Context {
+func (d *daemon) initContext() (datastore.Context, error) {
 	log.Debug("Acquiring application context from Elastic")
 	datastore.Register(d.dsDriver)
 	ctx := datastore.Get()
 	if ctx == nil {
-		log.Fatal("Unable to acquire application context from Elastic")
+		return nil, errors.New("unable to acquire application context from Elastic")
 	}
-	return ctx
+	return ctx, nil
 }
 
 func (d *daemon) initZK(zks []string) (*coordclient.Client, error) {
 	options := config.GetOptions()
+	if options.CoordinatorDriver == "etcd" {
+		dsn := coordetcd.NewDSN(options.EtcdEndpoints,
+			time.Duration(options.ZKSessionTimeout)*time.Second,
+			coordetcd.DefaultRequestTimeout,
+		).String()
+		log.WithFields(logrus.Fields{
+			"dsn":       dsn,
+			"endpoints": options.EtcdEndpoints,
+		}).Debug("Establishing connection to etcd")
+		return coordclient.New("etcd", dsn, "/", nil)
+	}
+
 	coordzk.RegisterZKLogger()
 	dsn := coordzk.NewDSN(zks,
 		time.Duration(options.ZKSessionTimeout)*time.Second,
@@ -424,12 +468,88 @@ func (d *daemon) initZK(zks []string) (*coordclient.Client, error) {
 
 func (d *daemon) startMaster() (err error) {
 	log.Debug("Starting serviced master")
+	graph, err := startup.NewGraph(
+		startup.Step{
+			Name: "storage",
+			Hint: "check SERVICED_FS_TYPE, SERVICED_VOLUMES_PATH and SERVICED_STORAGE_ARGS",
+			Run:  d.startMasterStorage,
+		},
+		startup.Step{
+			Name: "isvcs",
+			Hint: "check that Docker is running and that SERVICED_ISVCS_PATH is writable",
+			Run:  d.startISVCS,
+		},
+		startup.Step{
+			Name:     "datastore",
+			Requires: []string{"isvcs"},
+			Hint:     "check the logs of the serviced-isvcs_elasticsearch-serviced container",
+			Run:      d.startDatastore,
+		},
+		startup.Step{
+			Name:     "coordinator",
+			Requires: []string{"isvcs"},
+			Hint:     "check SERVICED_ZK and that the coordinator is reachable from this host",
+			Run:      d.checkCoordinator,
+		},
+		startup.Step{
+			Name:     "facade",
+			Requires: []string{"storage", "datastore", "coordinator"},
+			Hint:     "check the serviced log for the failed migration or cache update",
+			Run:      d.startMasterFacade,
+		},
+		startup.Step{
+			Name:     "dfs",
+			Requires: []string{"facade"},
+			Hint:     "check the tenant volumes under SERVICED_VOLUMES_PATH and the NFS server",
+			Run:      d.startMasterDFS,
+		},
+		startup.Step{
+			Name:     "registry",
+			Requires: []string{"facade"},
+			Hint:     "check the logs of the serviced-isvcs_docker-registry container",
+			Run:      d.upgradeRegistry,
+		},
+		startup.Step{
+			Name:     "listeners",
+			Requires: []string{"dfs", "registry"},
+			Hint:     "check that SERVICED_RPC_PORT, SERVICED_UI_PORT and SERVICED_MUX_PORT are free and that SERVICED_SCHEDULER_PLUGINS is valid",
+			Run:      d.startMasterListeners,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Report startup progress before the rest of the master RPC is available
+	server := master.NewStartupServer(graph)
+	rpcutils.RegisterLocal("Startup", server)
+	if err := d.rpcServer.RegisterName("Startup", server); err != nil {
+		return fmt.Errorf("could not register RPC server named Startup: %v", err)
+	}
+
+	if err := graph.Run(); err != nil {
+		if derr, ok := err.(*startup.DependencyError); ok && len(derr.Blocked) > 0 {
+			log.WithFields(logrus.Fields{
+				"step":    derr.Step,
+				"blocked": derr.Blocked,
+			}).Error("Master startup stopped")
+		}
+		return err
+	}
+
+	log.Info("Started serviced master")
+	return nil
+}
+
+// startMasterStorage loads the master keys and initializes the application
+// storage and the DFS network driver.
+func (d *daemon) startMasterStorage() (err error) {
 	options := config.GetOptions()
 	agentIP := options.OutboundIP
 	if agentIP == "" {
 		agentIP, err = utils.GetIPAddress()
 		if err != nil {
-			log.WithError(err).Fatal("Unable to determine outbound IP address")
+			return fmt.Errorf("unable to determine outbound IP address: %s", err)
 		}
 	}
 	log.WithFields(logrus.Fields{
@@ -438,52 +558,83 @@ func (d *daemon) startMaster() (err error) {
 
 	rpcPort := strings.TrimLeft(options.Listen, ":")
 	thisHost, err := host.Build(agentIP, rpcPort, d.masterPoolID, "")
-
 	if err != nil {
-		log.WithFields(logrus.Fields{
-			"address": agentIP,
-			"rpcport": rpcPort,
-		}).WithError(err).Fatal("Unable to register master as host")
+		return fmt.Errorf("unable to register master as host %s:%s: %s", agentIP, rpcPort, err)
 	}
 
 	// Load keys if they exist, else generate them
 	masterKeyFile := filepath.Join(options.IsvcsPath, auth.MasterKeyFileName)
-	keylog := log.WithFields(logrus.Fields{
-		"keyfile": masterKeyFile,
-	})
 	if err = auth.CreateOrLoadMasterKeys(masterKeyFile); err != nil {
-		keylog.WithError(err).Fatal("Unable to load or create master keys")
+		return fmt.Errorf("unable to load or create master keys in %s: %s", masterKeyFile, err)
 	}
-
-	keylog.Info("Loaded master keys from disk")
+	log.WithFields(logrus.Fields{
+		"keyfile": masterKeyFile,
+	}).Info("Loaded master keys from disk")
 
 	// This is storage related
-	storagelogger := log.WithFields(logrus.Fields{
-		"path":   options.VolumesPath,
-		"driver": options.FSType,
-	})
 	if options.FSType == "btrfs" {
 		if !volume.IsBtrfsFilesystem(options.VolumesPath) {
-			storagelogger.Fatal("Volume path does not contain a btrfs filesystem")
+			return fmt.Errorf("volume path %s does not contain a btrfs filesystem", options.VolumesPath)
 		}
 	} else if options.FSType == "devicemapper" {
 		devicemapper.SetStorageStatsUpdateInterval(options.StorageStatsUpdateInterval)
 	}
 	if d.disk, err = volume.GetDriver(options.VolumesPath); err != nil {
-		storagelogger.WithError(err).Fatal("Unable to access application storage")
+		return fmt.Errorf("unable to access application storage at %s: %s", options.VolumesPath, err)
 	}
 
-	if d.net, err = nfs.NewServer(options.VolumesPath, "serviced_volumes_v2", "0.0.0.0/0"); err != nil {
-		storagelogger.WithError(err).Fatal("Unable to initialize NFS server")
+	switch options.DFSNetworkDriver {
+	case network.DriverRBD:
+		rbdServer, err := rbd.NewServer(options.RBDPool, "serviced_volumes_v2", uint64(options.RBDImageSize))
+		if err != nil {
+			return fmt.Errorf("unable to initialize RBD storage: %s", err)
+		}
+		d.net = rbdServer
+	case network.DriverNFS, "":
+		nfsServer, err := nfs.NewServer(options.VolumesPath, "serviced_volumes_v2", "0.0.0.0/0")
+		if err != nil {
+			return fmt.Errorf("unable to initialize NFS server: %s", err)
+		}
+		nfsServer.SetV4Only(options.NFSv4Only)
+		d.net = nfsServer
+	default:
+		return fmt.Errorf("unsupported DFS network driver %q", options.DFSNetworkDriver)
 	}
 
 	if d.storageHandler, err = storage.NewServer(d.net, thisHost, options.VolumesPath); err != nil {
-		log.WithError(err).Fatal("Unable to create internal NFS server manager")
+		return fmt.Errorf("unable to create internal NFS server manager: %s", err)
+	}
+	return nil
+}
+
+// startDatastore connects to the application datastore
+func (d *daemon) startDatastore() (err error) {
+	if d.dsDriver, err = d.initDriver(); err != nil {
+		return err
+	}
+	d.dsContext, err = d.initContext()
+	return err
+}
+
+// checkCoordinator verifies that the coordinator is reachable
+func (d *daemon) checkCoordinator() error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return fmt.Errorf("unable to connect to the coordinator: %s", err)
+	}
+	if _, err := conn.Exists("/"); err != nil {
+		return fmt.Errorf("unable to read from the coordinator: %s", err)
 	}
+	return nil
+}
 
-	d.dsDriver = d.initDriver()
-	d.dsContext = d.initContext()
-	d.facade = d.initFacade()
+// startMasterFacade initializes the facade and brings the stored state up to
+// date
+func (d *daemon) startMasterFacade() (err error) {
+	options := config.GetOptions()
+	if d.facade, err = d.initFacade(); err != nil {
+		return err
+	}
 	d.cpDao = d.initDAO()
 
 	// Initialize service state manager
@@ -494,13 +645,28 @@ func (d *daemon) startMaster() (err error) {
 	d.facade.SyncCurrentStates(d.dsContext)
 
 	if err = d.checkVersion(); err != nil {
-		log.WithError(err).Fatal("Unable to initialize version")
+		return fmt.Errorf("unable to initialize version: %s", err)
+	}
+
+	if options.MasterBootstrap {
+		d.bootstrapMaster()
+	}
+
+	if err := d.facade.SaveServiceTemplateCopies(d.dsContext); err != nil {
+		log.WithError(err).Warn("Unable to save copies of the service templates to the DFS")
 	}
+	return nil
+}
+
+// startMasterDFS creates and exports the tenant volumes and sets up the
+// default pool
+func (d *daemon) startMasterDFS() error {
+	options := config.GetOptions()
 
 	// Create tenant volumes if they do not already exist
 	tenantIDs, err := d.facade.GetTenantIDs(d.dsContext)
 	if err != nil {
-		log.WithError(err).Fatal("Unable to get deployed services")
+		return fmt.Errorf("unable to get deployed services: %s", err)
 	}
 
 	for _, tenantID := range tenantIDs {
@@ -510,16 +676,20 @@ func (d *daemon) startMaster() (err error) {
 		if err == volume.ErrVolumeNotExists {
 			tenantLogger.Warn("Tenant volume not found")
 			if _, err := d.disk.Create(tenantID); err != nil {
-				tenantLogger.WithError(err).Fatal("Could not re-create tenant volume")
+				return fmt.Errorf("could not re-create volume for tenant %s: %s", tenantID, err)
 			}
 			tenantLogger.Warn("Created new tenant volume")
 		} else if err != nil {
-			tenantLogger.WithError(err).Fatal("Could not get volume for tenant")
+			return fmt.Errorf("could not get volume for tenant %s: %s", tenantID, err)
 		}
 	}
 
 	// Set tenant volumes on nfs storagedriver
 	log.Debug("Exporting tenant volumes via NFS")
+	storagelogger := log.WithFields(logrus.Fields{
+		"path":   options.VolumesPath,
+		"driver": options.FSType,
+	})
 	tenantVolumes := make(map[string]struct{})
 	for _, vol := range d.disk.List() {
 		tenantlogger := storagelogger.WithFields(logrus.Fields{"tenant": vol})
@@ -536,15 +706,28 @@ func (d *daemon) startMaster() (err error) {
 	}
 
 	if err = d.facade.CreateDefaultPool(d.dsContext, d.masterPoolID); err != nil {
-		log.WithError(err).Fatal("Unable to create default pool")
+		return fmt.Errorf("unable to create default pool: %s", err)
 	}
 
-	if err = d.facade.UpgradeRegistry(d.dsContext, "", false); err != nil {
-		log.WithError(err).Fatal("Unable to upgrade internal Docker image registry")
+	if err := d.facade.SyncSettings(d.dsContext); err != nil {
+		log.WithError(err).Warn("Unable to publish cluster settings to delegates")
+	}
+	return nil
+}
+
+// upgradeRegistry upgrades the internal Docker image registry
+func (d *daemon) upgradeRegistry() error {
+	if err := d.facade.UpgradeRegistry(d.dsContext, "", false); err != nil {
+		return fmt.Errorf("unable to upgrade internal Docker image registry: %s", err)
 	}
+	return nil
+}
 
-	if err = d.registerMasterRPC(); err != nil {
-		log.WithError(err).Fatal("Unable to register RPC services")
+// startMasterListeners opens the master to RPC, web and pool clients and
+// starts the scheduler
+func (d *daemon) startMasterListeners() error {
+	if err := d.registerMasterRPC(); err != nil {
+		return fmt.Errorf("unable to register RPC services: %s", err)
 	}
 
 	nfsServer, ok := d.net.(*nfs.Server)
@@ -552,13 +735,19 @@ func (d *daemon) startMaster() (err error) {
 		nfsServer.SetClientValidator(facade.NewDfsClientValidator(d.facade, d.dsContext))
 	}
 
+	if options := config.GetOptions(); options.SchedulerPlugins != "" {
+		cfg, err := strategy.LoadScoringConfig(options.SchedulerPlugins)
+		if err != nil {
+			return fmt.Errorf("unable to load the scheduler plugins: %s", err)
+		}
+		strategy.SetScoring(cfg)
+		log.WithField("count", len(cfg.Plugins)).Info("Loaded scheduler plugins")
+	}
+
 	d.initWeb()
 	d.addTemplates()
 	d.startScheduler()
 	d.startPoolListener()
-
-	log.Info("Started serviced master")
-
 	return nil
 }
 
@@ -591,7 +780,7 @@ func (d *daemon) checkVersion() error {
 		}
 
 	} else if err != nil {
-		log.WithError(err).Fatal("Unable to retrieve properties object")
+		return fmt.Errorf("Unable to retrieve properties object: %v", err)
 	} else {
 		// Update the CC Version if not current, could run upgrades here
 		ccVersion, _ := ccProps.CCVersion()
@@ -704,6 +893,7 @@ func createMuxListener() net.Listener {
 		listener, err = tls.Listen("tcp", fmt.Sprintf(":%d", options.MuxPort), tlsConfig)
 		log = log.WithFields(logrus.Fields{
 			"ciphersuite": strings.Join(utils.CipherSuitesByName(tlsConfig), ","),
+			"minversion":  utils.TLSVersionName(tlsConfig.MinVersion),
 		})
 	} else {
 		listener, err = net.Listen("tcp", fmt.Sprintf(":%d", options.MuxPort))
@@ -715,6 +905,101 @@ func createMuxListener() net.Listener {
 	return listener
 }
 
+// startLogShipper starts the log shipper of the delegate with the backends
+// that are configured
+func (d *daemon) startLogShipper(options config.Options) (*logship.Shipper, error) {
+	shipper := logship.NewShipper()
+	if options.LogSyslogAddress != "" {
+		var tlsConfig *tls.Config
+		if options.LogSyslogCAFile != "" {
+			var err error
+			if tlsConfig, err = logship.CATLSConfig(options.LogSyslogCAFile); err != nil {
+				return nil, err
+			}
+		}
+		w, err := logship.NewSyslogWriter(options.LogSyslogAddress, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		shipper.AddWriter(commons.LogShipperSyslog, w)
+	}
+	if options.LogKafkaURL != "" {
+		w, err := logship.NewKafkaWriter(options.LogKafkaURL, options.LogKafkaTopic)
+		if err != nil {
+			shipper.Close()
+			return nil, err
+		}
+		shipper.AddWriter(commons.LogShipperKafka, w)
+	}
+	if err := shipper.Listen(fmt.Sprintf(":%d", options.LogShipperPort)); err != nil {
+		shipper.Close()
+		return nil, err
+	}
+	return shipper, nil
+}
+
+// syncDelegateProfile applies the delegate profile of the pool of this host,
+// which may have changed while the delegate was not running
+func (d *daemon) syncDelegateProfile(hostID string) {
+	logger := log.WithField("hostid", hostID)
+	masterClient, err := master.NewClient(d.servicedEndpoint)
+	if err != nil {
+		logger.WithError(err).Warn("Unable to connect to the master to look up the delegate profile")
+		return
+	}
+	defer masterClient.Close()
+	p, err := masterClient.GetHostDelegateProfile(hostID)
+	if err != nil {
+		logger.WithError(err).Warn("Unable to look up the delegate profile of this host")
+		return
+	}
+	result, err := agent.ApplyDelegateProfile(agent.DelegateProfileFile, agent.DelegateProfileRequest{
+		Profile: p.ID,
+		Version: p.Version,
+		Options: p.Options,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Unable to apply the delegate profile of this host")
+		return
+	}
+	if len(result.Restart) > 0 {
+		logger.WithFields(logrus.Fields{
+			"profile": p.ID,
+			"version": p.Version,
+			"options": result.Restart,
+		}).Warn("Restart serviced to apply the changed options of the delegate profile")
+	}
+}
+
+// relayLocator finds the instances of services for the relay of the master
+type relayLocator struct {
+	f   *facade.Facade
+	ctx datastore.Context
+}
+
+// LocateInstance implements relay.Locator
+func (l *relayLocator) LocateInstance(serviceID string, instanceID int) (*relay.Location, error) {
+	svc, err := l.f.GetService(l.ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := l.f.LocateServiceInstance(l.ctx, serviceID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Location{
+		PoolID:      svc.PoolID,
+		HostID:      location.HostID,
+		HostIP:      location.HostIP,
+		ContainerID: location.ContainerID,
+	}, nil
+}
+
+// CheckInteractiveAccess implements relay.Locator
+func (l *relayLocator) CheckInteractiveAccess(serviceID, poolID string) error {
+	return l.f.CheckInteractiveAccess(l.ctx, serviceID, poolID)
+}
+
 func (d *daemon) startAgent() error {
 	options := config.GetOptions()
 	muxListener := createMuxListener()
@@ -762,6 +1047,7 @@ func (d *daemon) startAgent() error {
 	// Load delegate keys if they exist
 	delegateKeyFile := filepath.Join(options.EtcPath, auth.DelegateKeyFileName)
 	tokenFile := filepath.Join(options.EtcPath, auth.TokenFileName)
+	auth.TokenGracePeriod = time.Duration(options.TokenGracePeriod) * time.Second
 
 	// Start watching for delegate keys to be loaded
 	go auth.WatchDelegateKeyFile(delegateKeyFile, d.shutdown)
@@ -919,6 +1205,8 @@ func (d *daemon) startAgent() error {
 			"poolid": poolID,
 		})
 
+		go d.syncDelegateProfile(myHostID)
+
 		poolPath := zzk.GeneratePoolPath(poolID)
 		poolBasedConn, err := zzk.GetLocalConnection(poolPath)
 		if err != nil {
@@ -977,8 +1265,94 @@ func (d *daemon) startAgent() error {
 			}
 		}
 
+		// roll up the metrics of the instances on this host before shipping
+		// them to the master
+		var aggregatorPort int
+		if options.MetricsRollup > 0 {
+			log := log.WithFields(logrus.Fields{
+				"rollup":    options.MetricsRollup,
+				"port":      options.MetricsAggregatorPort,
+				"spoolpath": options.MetricsSpoolPath,
+			})
+			aggregator, err := stats.NewAggregator(stats.AggregatorOptions{
+				Destination:  fmt.Sprintf("http://%s/api/metrics/store", options.HostStats),
+				Window:       time.Duration(options.MetricsRollup) * time.Second,
+				SpoolPath:    options.MetricsSpoolPath,
+				SpoolMaxSize: int64(options.MetricsSpoolMaxSize) * 1024 * 1024,
+			})
+			if err != nil {
+				log.WithError(err).Error("Unable to start metrics aggregator; instances will post metrics to the master")
+			} else if err := aggregator.Listen(fmt.Sprintf(":%d", options.MetricsAggregatorPort)); err != nil {
+				aggregator.Close()
+				log.WithError(err).Error("Unable to listen for metrics; instances will post metrics to the master")
+			} else {
+				aggregatorPort = options.MetricsAggregatorPort
+				log.Info("Started metrics aggregator")
+				d.waitGroup.Add(1)
+				go func() {
+					defer d.waitGroup.Done()
+					<-d.shutdown
+					aggregator.Close()
+					log.Info("Stopped metrics aggregator")
+				}()
+			}
+		}
+
+		// ship the logs of log configs that do not go to logstash to the
+		// syslog server or kafka topic of the site
+		var logShipperPort int
+		if options.LogShipperPort > 0 && (options.LogSyslogAddress != "" || options.LogKafkaURL != "") {
+			log := log.WithField("port", options.LogShipperPort)
+			if shipper, err := d.startLogShipper(options); err != nil {
+				log.WithError(err).Error("Unable to start log shipper; syslog and kafka logs will not be shipped")
+			} else {
+				logShipperPort = options.LogShipperPort
+				log.Info("Started log shipper")
+				d.waitGroup.Add(1)
+				go func() {
+					defer d.waitGroup.Done()
+					<-d.shutdown
+					shipper.Close()
+					log.Info("Stopped log shipper")
+				}()
+			}
+		}
+
 		muxDisableTLS, _ := strconv.ParseBool(options.MuxDisableTLS)
+
+		// relay attach and logs streams for the clients that cannot reach
+		// the delegate of an instance, and run the relayed streams for the
+		// containers of this host
+		var relayPort int
+		if options.RelayPort > 0 {
+			relayOptions := relay.ServerOptions{
+				Port:    options.RelayPort,
+				MuxPort: options.MuxPort,
+				UseTLS:  !muxDisableTLS,
+				Policy:  options.RelayPolicy,
+			}
+			if options.Master {
+				relayOptions.Locator = &relayLocator{f: d.facade, ctx: d.dsContext}
+			}
+			log := log.WithField("port", options.RelayPort)
+			relayServer := relay.NewServer(relayOptions)
+			if err := relayServer.Listen(fmt.Sprintf("127.0.0.1:%d", options.RelayPort)); err != nil {
+				log.WithError(err).Error("Unable to start relay; attach and logs will not be relayed")
+			} else {
+				relayPort = options.RelayPort
+				log.Info("Started relay")
+				d.waitGroup.Add(1)
+				go func() {
+					defer d.waitGroup.Done()
+					<-d.shutdown
+					relayServer.Close()
+					log.Info("Stopped relay")
+				}()
+			}
+		}
+
 		conntrackFlush, _ := strconv.ParseBool(options.ConntrackFlush)
+		preserveContainers, _ := strconv.ParseBool(options.PreserveContainers)
 
 		agentOptions := node.AgentOptions{
 			IPAddress:             agentIP,
@@ -992,6 +1366,8 @@ func (d *daemon) startAgent() error {
 			Mount:                 options.Mount,
 			FSType:                options.FSType,
 			Zookeepers:            options.Zookeepers,
+			CoordinatorDriver:     options.CoordinatorDriver,
+			EtcdEndpoints:         options.EtcdEndpoints,
 			Mux:                   mux,
 			MuxPort:               fmt.Sprintf("%d", options.MuxPort),
 			UseTLS:                !muxDisableTLS,
@@ -1000,6 +1376,14 @@ func (d *daemon) startAgent() error {
 			VirtualAddressSubnet:  options.VirtualAddressSubnet,
 			ControllerBinary:      options.ControllerBinary,
 			ConntrackFlush:        conntrackFlush,
+			PreserveContainers:    preserveContainers,
+			MaxHealthChecks:       options.MaxHealthChecks,
+			MaxContainerStarts:    options.MaxContainerStarts,
+			ImagePullPolicy:       options.ImagePullPolicy,
+			TracingCollector:      options.TracingCollector,
+			AggregatorPort:        aggregatorPort,
+			LogShipperPort:        logShipperPort,
+			RelayPort:             relayPort,
 			LogstashURL:           options.LogstashURL,
 			DockerLogDriver:       options.DockerLogDriver,
 			DockerLogConfig:       convertStringSliceToMap(options.DockerLogConfigList),
@@ -1036,6 +1420,9 @@ func (d *daemon) startAgent() error {
 		// serviced stats (cpu, ram, etc)
 		if options.ReportStats {
 			statsdest := fmt.Sprintf("http://%s/api/metrics/store", options.HostStats)
+			if aggregatorPort > 0 {
+				statsdest = fmt.Sprintf("http://127.0.0.1:%d/api/metrics/store", aggregatorPort)
+			}
 			statsduration := time.Duration(options.StatsPeriod) * time.Second
 			log := log.WithFields(logrus.Fields{
 				"statsurl": statsdest,
@@ -1191,7 +1578,7 @@ func (d *daemon) registerMasterRPC() error {
 	return nil
 }
 
-func (d *daemon) initDriver() datastore.Driver {
+func (d *daemon) initDriver() (datastore.Driver, error) {
 	log := log.WithFields(logrus.Fields{
 		"address": "localhost:9200",
 		"index":   "controlplane",
@@ -1205,11 +1592,20 @@ func (d *daemon) initDriver() datastore.Driver {
 	eDriver.AddMapping(addressassignment.MAPPING)
 	eDriver.AddMapping(serviceconfigfile.MAPPING)
 	eDriver.AddMapping(user.MAPPING)
-	err := eDriver.Initialize(10 * time.Second)
-	if err != nil {
-		log.WithError(err).Fatal("Unable to establish connection to Elastic database")
-	}
-	return eDriver
+	eDriver.AddMapping(calendar.MAPPING)
+	eDriver.AddMapping(backupschedule.MAPPING)
+	eDriver.AddMapping(feature.MAPPING)
+	eDriver.AddMapping(pin.MAPPING)
+	eDriver.AddMapping(setting.MAPPING)
+	eDriver.AddMapping(delegateprofile.MAPPING)
+	eDriver.AddMapping(secret.MAPPING)
+	eDriver.AddMapping(certificate.MAPPING)
+	eDriver.AddMapping(audit.MAPPING)
+	eDriver.AddMapping(event.MAPPING)
+	if err := eDriver.Initialize(10 * time.Second); err != nil {
+		return nil, fmt.Errorf("unable to establish connection to Elastic database: %s", err)
+	}
+	return eDriver, nil
 }
 
 func initMetricsClient() *metrics.Client {
@@ -1226,9 +1622,36 @@ func initMetricsClient() *metrics.Client {
 	return client
 }
 
-func (d *daemon) initFacade() *facade.Facade {
+// bootstrapMaster rebuilds the database of a new master from the state of
+// the existing cluster in the coordinator and the DFS.
+func (d *daemon) bootstrapMaster() {
+	report, err := d.facade.Bootstrap(d.dsContext)
+	if err == facade.ErrBootstrapNotEmpty {
+		log.Warn("Master already has deployed applications; skipping bootstrap")
+		return
+	} else if err != nil {
+		log.WithError(err).Fatal("Unable to bootstrap master from the existing cluster")
+	}
+	log.WithFields(logrus.Fields{
+		"pools":     len(report.Pools),
+		"hosts":     len(report.Hosts),
+		"templates": len(report.Templates),
+		"images":    report.Images,
+		"tenants":   len(report.Tenants),
+	}).Info("Bootstrapped master from the existing cluster")
+	for _, hostID := range report.Hosts {
+		log.WithField("hostid", hostID).Warn("Adopted host must have its delegate key reset with serviced key reset --register HOSTID")
+	}
+	if len(report.Missing) > 0 {
+		log.WithField("services", report.Missing).Warn("Services that were not in any snapshot could not be adopted")
+	}
+}
+
+func (d *daemon) initFacade() (*facade.Facade, error) {
 	options := config.GetOptions()
 	f := facade.New()
+	f.SetAuditLogger(audit.NewStoreLogger(audit.NewStore()))
+	f.SetEventStore(event.NewStore())
 	index := registry.NewRegistryIndexClient(f)
 	dfs := dfs.NewDistributedFilesystem(d.docker, index, d.reg, d.disk, d.net, time.Duration(options.MaxDFSTimeout)*time.Second)
 	dfs.SetTmp(os.Getenv("TMP"))
@@ -1239,14 +1662,30 @@ func (d *daemon) initFacade() *facade.Facade {
 	f.SetHealthCache(d.hcache)
 	client := initMetricsClient()
 	f.SetMetricsClient(client)
+	f.SetLogsClient(isvcs.NewLogSearchClient(options.LogstashES))
+	f.SetRetentionClient(isvcs.NewRetentionClient(options.LogstashES, "127.0.0.1:4242"))
+	f.SetLogstashReloader(isvcs.NewLogstashReloader())
+	f.SetContainerFilesClient(agent.NewContainerFilesClient())
+	f.SetProfileClient(agent.NewProfileClient())
+	f.SetDelegateProfileClient(agent.NewDelegateProfileClient())
+	f.SetElasticSnapshotClient(facade.ElasticServiced, isvcs.NewServicedSnapshotClient("localhost:9200", options.IsvcsPath))
+	f.SetElasticSnapshotClient(facade.ElasticLogstash, isvcs.NewLogstashSnapshotClient(options.LogstashES, options.IsvcsPath))
 	if err := f.CreateSystemUser(d.dsContext); err != nil {
-		log.WithError(err).Fatal("Unable to create system user")
+		return nil, fmt.Errorf("unable to create system user: %s", err)
 	}
 	if err := f.UpdateServiceCache(d.dsContext); err != nil {
-		log.WithError(err).Fatal("Unable to update the service cache")
+		return nil, fmt.Errorf("unable to update the service cache: %s", err)
 	}
 	f.SetRollingRestartTimeout(time.Duration(options.ServiceRunLevelTimeout) * time.Second)
-	return f
+	if options.AdmissionWebhooks != "" {
+		hooks, err := facade.LoadAdmissionWebhooks(options.AdmissionWebhooks)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the admission webhooks: %s", err)
+		}
+		f.SetAdmissionWebhooks(hooks)
+		log.WithField("count", len(hooks)).Info("Loaded admission webhooks")
+	}
+	return f, nil
 }
 
 // startLogstashPurger purges logstash based on days and size
@@ -1260,6 +1699,12 @@ func (d *daemon) startLogstashPurger(initialStart, cycleTime time.Duration) {
 	}
 	for {
 		isvcs.PurgeLogstashIndices(options.LogstashMaxDays, options.LogstashMaxSize)
+		if err := d.facade.PurgePoolRetention(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to purge logs and metrics of resource pools")
+		}
+		if err := d.facade.PurgeEvents(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to purge events")
+		}
 		select {
 		case <-d.shutdown:
 			return
@@ -1376,6 +1821,9 @@ func (d *daemon) startStorageMonitor() {
 				}
 			}
 		}
+		if err := d.facade.CheckVolumeQuotas(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to check tenant volume quotas")
+		}
 		// Now wait to check again, some duration smaller than that at which
 		// storage metrics are reported, to avoid races
 		select {
@@ -1403,6 +1851,7 @@ func (d *daemon) initDAO() dao.ControlPlane {
 	if err != nil {
 		log.WithError(err).Fatal("Unable to initialize DAO layer")
 	}
+	go cp.RunBackupSchedules(d.shutdown)
 	return cp
 }
 
@@ -1430,6 +1879,17 @@ func (d *daemon) initWeb() {
 		options.SnapshotSpacePercent,
 		d.facade)
 
+	if options.ACMEDirectory != "" {
+		keyFile := filepath.Join(options.EtcPath, acmeAccountKeyFileName)
+		if err := cpserver.EnableACME(options.ACMEDirectory, options.ACMEEmail, options.ACMEDomain, keyFile); err != nil {
+			log.WithError(err).WithField("acmedirectory", options.ACMEDirectory).Error("Unable to manage vhost certificates with ACME")
+		} else {
+			log.WithField("acmedirectory", options.ACMEDirectory).Info("Managing vhost certificates with ACME")
+		}
+	}
+
+	cpserver.SetMaintenancePage(options.MaintenancePage)
+
 	web.SetServiceStatsCacheTimeout(options.SvcStatsCacheTimeout)
 	log.WithFields(logrus.Fields{
 		"cachetimeout": options.SvcStatsCacheTimeout,
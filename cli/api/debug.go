@@ -13,6 +13,10 @@
 
 package api
 
+import (
+	"time"
+)
+
 
 func (a *api) DebugEnableMetrics() (string, error) {
 	client, err := a.connectMaster()
@@ -31,3 +35,14 @@ func (a *api) DebugDisableMetrics() (string, error) {
 
 	return client.DebugDisableMetrics()
 }
+
+// CollectProfiles collects pprof profiles from the master, or from the agent
+// of the delegate if a host id is set
+func (a *api) CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CollectProfiles(hostID, profiles, duration)
+}
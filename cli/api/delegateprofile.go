@@ -0,0 +1,108 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/delegateprofile"
+)
+
+// Returns the delegate profiles
+func (a *api) GetDelegateProfiles() ([]delegateprofile.Profile, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetDelegateProfiles()
+}
+
+// Returns a delegate profile with its previous versions
+func (a *api) GetDelegateProfile(name string) (*delegateprofile.Profile, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetDelegateProfile(name)
+}
+
+// Adds a delegate profile without options
+func (a *api) AddDelegateProfile(name, description string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.AddDelegateProfile(name, description)
+}
+
+// Removes a delegate profile that is not assigned to a pool
+func (a *api) RemoveDelegateProfile(name string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveDelegateProfile(name)
+}
+
+// Sets an option of a delegate profile and pushes the new version
+func (a *api) SetDelegateProfileOption(name, option, value string) ([]delegateprofile.HostStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SetDelegateProfileOption(name, option, value)
+}
+
+// Removes an option from a delegate profile and pushes the new version
+func (a *api) UnsetDelegateProfileOption(name, option string) ([]delegateprofile.HostStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UnsetDelegateProfileOption(name, option)
+}
+
+// Restores a previous version of a delegate profile and pushes it
+func (a *api) RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RollbackDelegateProfile(name, version)
+}
+
+// Assigns a delegate profile to a resource pool and pushes it
+func (a *api) AssignDelegateProfile(poolID, name string) ([]delegateprofile.HostStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AssignDelegateProfile(poolID, name)
+}
+
+// Pushes the delegate profile of a resource pool to its hosts
+func (a *api) PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.PushDelegateProfile(poolID)
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/feature"
+)
+
+// Returns the state of every known feature
+func (a *api) GetFeatureFlags() ([]feature.Flag, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetFeatureFlags()
+}
+
+// Enables a feature in a resource pool, in a deployment, or everywhere
+func (a *api) EnableFeature(name, poolID, deploymentID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.EnableFeature(name, poolID, deploymentID)
+}
+
+// Disables a feature in a resource pool, in a deployment, or everywhere
+func (a *api) DisableFeature(name, poolID, deploymentID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.DisableFeature(name, poolID, deploymentID)
+}
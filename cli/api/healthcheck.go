@@ -14,6 +14,7 @@
 package api
 
 import (
+	"github.com/control-center/serviced/commons/startup"
 	"github.com/control-center/serviced/isvcs"
 )
 
@@ -29,3 +30,12 @@ func (a *api) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealt
 		return results, nil
 	}
 }
+
+// GetStartupProgress returns the status of each step of the master's startup
+func (a *api) GetStartupProgress() ([]startup.Status, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetStartupProgress()
+}
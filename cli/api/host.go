@@ -27,11 +27,12 @@ import (
 
 // HostConfig is the deserialized object from the command-line
 type HostConfig struct {
-	Address *utils.URL
-	Nat     *utils.URL
-	PoolID  string
-	Memory  string
-	IPs     []string
+	Address   *utils.URL
+	Nat       *utils.URL
+	PoolID    string
+	Memory    string
+	IPs       []string
+	Benchmark bool
 }
 
 type HostUpdateConfig struct {
@@ -44,6 +45,34 @@ type AuthHost struct {
 	Authenticated bool
 }
 
+// DelegateKeyTarget is a host whose delegate keys are distributed
+type DelegateKeyTarget struct {
+	HostID string
+	Nat    utils.URL
+}
+
+// DelegateKeyOptions describes how delegate keys are distributed to hosts
+type DelegateKeyOptions struct {
+	Template   *auth.KeyFileTemplate // nil registers the keys with "serviced host register"
+	Retries    int
+	RetryDelay time.Duration
+	Prompt     bool
+}
+
+// DelegateKeyResult is the outcome of distributing the delegate keys to a
+// host.  KeyData holds the reset keys, so that they can be installed by hand
+// if the distribution failed.
+type DelegateKeyResult struct {
+	HostID   string
+	IPAddr   string
+	Attempts int
+	KeyData  []byte
+	Err      error
+}
+
+// registerRemoteHost installs delegate keys on a remote host
+var registerRemoteHost = auth.RegisterRemoteHostWithTemplate
+
 func getAuthInfo(client master.ClientInterface, hosts []host.Host) ([]AuthHost, error) {
 	hostIDs := []string{}
 	for _, h := range hosts {
@@ -89,6 +118,20 @@ func (a *api) GetHostsWithAuthInfo() ([]AuthHost, error) {
 	return getAuthInfo(client, hosts)
 }
 
+// GetHostsWithAuthInfoInPool returns the hosts in a resource pool with their
+// authentication status
+func (a *api) GetHostsWithAuthInfoInPool(poolID string) ([]AuthHost, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := client.FindHostsInPool(poolID)
+	if err != nil {
+		return nil, err
+	}
+	return getAuthInfo(client, hosts)
+}
+
 // Returns a list of all hosts
 func (a *api) GetHosts() ([]host.Host, error) {
 	client, err := a.connectMaster()
@@ -144,10 +187,11 @@ func (a *api) AddHost(config HostConfig) (*host.Host, []byte, error) {
 	}
 
 	req := agent.BuildHostRequest{
-		IP:     config.Address.Host,
-		Port:   config.Address.Port,
-		PoolID: config.PoolID,
-		Memory: config.Memory,
+		IP:        config.Address.Host,
+		Port:      config.Address.Port,
+		PoolID:    config.PoolID,
+		Memory:    config.Memory,
+		Benchmark: config.Benchmark,
 	}
 
 	h, err := agentClient.BuildHost(req)
@@ -193,10 +237,11 @@ func (a *api) AddHostPrivate(config HostConfig) (*host.Host, []byte, error) {
 	}
 
 	req := agent.BuildHostRequest{
-		IP:     config.Address.Host,
-		Port:   config.Address.Port,
-		PoolID: config.PoolID,
-		Memory: config.Memory,
+		IP:        config.Address.Host,
+		Port:      config.Address.Port,
+		PoolID:    config.PoolID,
+		Memory:    config.Memory,
+		Benchmark: config.Benchmark,
 	}
 
 	h, err := agentClient.BuildHost(req)
@@ -229,6 +274,16 @@ func (a *api) RemoveHost(id string) error {
 	return client.RemoveHost(id)
 }
 
+// Enables or disables maintenance mode on an existing host
+func (a *api) SetHostMaintenance(id string, enabled bool) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetHostMaintenance(id, enabled)
+}
+
 // Sets the memory allocation for an existing host
 func (a *api) SetHostMemory(config HostUpdateConfig) error {
 	client, err := a.connectMaster()
@@ -246,6 +301,43 @@ func (a *api) SetHostMemory(config HostUpdateConfig) error {
 	return client.UpdateHost(*h)
 }
 
+// Sets the labels of an existing host, replacing any previous labels
+func (a *api) SetHostLabels(id string, labels map[string]string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	h, err := client.GetHost(id)
+	if err != nil {
+		return err
+	}
+	h.Labels = labels
+	return client.UpdateHost(*h)
+}
+
+// Adds or updates the given labels of an existing host and removes the labels
+// with the given keys
+func (a *api) UpdateHostLabels(id string, labels map[string]string, remove []string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	h, err := client.GetHost(id)
+	if err != nil {
+		return err
+	}
+	if h.Labels == nil {
+		h.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		h.Labels[k] = v
+	}
+	for _, k := range remove {
+		delete(h.Labels, k)
+	}
+	return client.UpdateHost(*h)
+}
+
 func (a *api) AuthenticateHost(hostID string) (string, int64, error) {
 	client, err := a.connectMaster()
 	if err != nil {
@@ -285,3 +377,42 @@ func (a *api) RegisterRemoteHost(h *host.Host, nat utils.URL, keyData []byte, pr
 func (a *api) WriteDelegateKey(filename string, data []byte) error {
 	return auth.WriteKeyToFile(filename, data)
 }
+
+// DistributeDelegateKeys resets the keys of each host and installs them on
+// the host via ssh, retrying failed attempts.
+func (a *api) DistributeDelegateKeys(targets []DelegateKeyTarget, opts DelegateKeyOptions) []DelegateKeyResult {
+	results := make([]DelegateKeyResult, len(targets))
+	for i, target := range targets {
+		results[i].HostID = target.HostID
+	}
+	client, err := a.connectMaster()
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+	for i, target := range targets {
+		h, err := client.GetHost(target.HostID)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].IPAddr = h.IPAddr
+		if results[i].KeyData, err = client.ResetHostKey(h.ID); err != nil {
+			results[i].Err = err
+			continue
+		}
+		for results[i].Attempts <= opts.Retries {
+			if results[i].Attempts > 0 && opts.RetryDelay > 0 {
+				time.Sleep(opts.RetryDelay)
+			}
+			results[i].Attempts++
+			results[i].Err = registerRemoteHost(h.ID, target.Nat, h.IPAddr, results[i].KeyData, opts.Prompt, opts.Template)
+			if results[i].Err == nil {
+				break
+			}
+		}
+	}
+	return results
+}
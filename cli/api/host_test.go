@@ -0,0 +1,66 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package api
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestDistributeDelegateKeys(c *C) {
+	defer func(f func(string, utils.URL, string, []byte, bool, *auth.KeyFileTemplate) error) {
+		registerRemoteHost = f
+	}(registerRemoteHost)
+
+	attempts := map[string]int{}
+	registerRemoteHost = func(hostID string, nat utils.URL, ipAddr string, keydata []byte, prompt bool, tmpl *auth.KeyFileTemplate) error {
+		attempts[hostID]++
+		c.Assert(string(keydata), Equals, "keys-"+hostID)
+		switch hostID {
+		case "retried":
+			if attempts[hostID] < 2 {
+				return auth.ErrSSHFailed
+			}
+		case "unreachable":
+			return auth.ErrSSHFailed
+		}
+		return nil
+	}
+	for _, id := range []string{"ok", "retried", "unreachable"} {
+		s.mockMasterClient.On("GetHost", id).Return(&host.Host{ID: id, IPAddr: "10.0.0.1"}, nil)
+		s.mockMasterClient.On("ResetHostKey", id).Return([]byte("keys-"+id), nil)
+	}
+	s.mockMasterClient.On("GetHost", "missing").Return(nil, errors.New("not found"))
+
+	targets := []DelegateKeyTarget{{HostID: "ok"}, {HostID: "retried"}, {HostID: "unreachable"}, {HostID: "missing"}}
+	results := s.api.DistributeDelegateKeys(targets, DelegateKeyOptions{Retries: 2})
+	c.Assert(results, HasLen, 4)
+
+	c.Assert(results[0].Err, IsNil)
+	c.Assert(results[0].Attempts, Equals, 1)
+	c.Assert(results[1].Err, IsNil)
+	c.Assert(results[1].Attempts, Equals, 2)
+	c.Assert(results[2].Err, Equals, auth.ErrSSHFailed)
+	c.Assert(results[2].Attempts, Equals, 3)
+	c.Assert(string(results[2].KeyData), Equals, "keys-unreachable")
+	c.Assert(results[3].Err, ErrorMatches, "not found")
+	c.Assert(results[3].Attempts, Equals, 0)
+}
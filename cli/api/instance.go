@@ -14,13 +14,21 @@
 package api
 
 import (
+	"fmt"
+	"io"
+	"net"
 	"os"
+	"strconv"
 	"syscall"
+	"time"
 
 	dockerclient "github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/relay"
+	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 // TODO: what to do about logging?
@@ -34,6 +42,16 @@ func (a *api) GetServiceInstances(serviceID string) ([]service.Instance, error)
 	return client.GetServiceInstances(serviceID)
 }
 
+// GetServiceInstanceHistory returns the hosts each instance of a service has
+// run on
+func (a *api) GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceInstanceHistory(serviceID)
+}
+
 // StopServiceInstance stops a running instance of a service.
 func (a *api) StopServiceInstance(serviceID string, instanceID int) error {
 	client, err := a.connectMaster()
@@ -43,6 +61,16 @@ func (a *api) StopServiceInstance(serviceID string, instanceID int) error {
 	return client.StopServiceInstance(serviceID, instanceID)
 }
 
+// SetServiceInstanceEnvironment sets the variables that are added to the
+// environment of an instance of a service when it next starts.
+func (a *api) SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.SetServiceInstanceEnvironment(serviceID, instanceID, env)
+}
+
 // AttachServiceInstance locates and attaches to a running instance of a service
 func (a *api) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
 	var (
@@ -60,6 +88,11 @@ func (a *api) AttachServiceInstance(serviceID string, instanceID int, command st
 		return err
 	}
 
+	// can this host open a shell in the instance?
+	if err := a.checkInteractiveAccess(client, serviceID); err != nil {
+		return err
+	}
+
 	// get the location of the running instance
 	location, err := client.LocateServiceInstance(serviceID, instanceID)
 	if err != nil {
@@ -75,6 +108,15 @@ func (a *api) AttachServiceInstance(serviceID string, instanceID int, command st
 
 	// attach to the container
 	if targetHost != hostID {
+		if a.shouldRelay(location) {
+			return a.relayServiceInstance(relay.Request{
+				ServiceID:  serviceID,
+				InstanceID: instanceID,
+				Kind:       relay.KindAttach,
+				Command:    append([]string{command}, args...),
+				TTY:        terminal.IsTerminal(int(os.Stdin.Fd())),
+			})
+		}
 		cmd, err := a.getSSHCommand(location)
 		if err != nil {
 			return err
@@ -91,6 +133,70 @@ func (a *api) AttachServiceInstance(serviceID string, instanceID int, command st
 	}
 }
 
+const (
+	// logsPageSize is the number of log messages requested from the master
+	// at once
+	logsPageSize = 500
+
+	// sshDialTimeout bounds the check whether ssh on the host of an instance
+	// can be reached before its streams are relayed through the master
+	sshDialTimeout = 3 * time.Second
+
+	// logsPollInterval is how often the master is asked for new log messages
+	// when following the logs of a service
+	logsPollInterval = 2 * time.Second
+)
+
+// LogsForServiceConfig describes which aggregated logs to stream
+type LogsForServiceConfig struct {
+	ServiceID  string
+	InstanceID int       // negative for all instances
+	Since      time.Time // only show messages logged after this time
+	Follow     bool      // keep streaming new messages until Cancel is closed
+	Cancel     <-chan struct{}
+}
+
+// LogsForService writes the aggregated application logs of all instances of
+// a service to the writer, oldest first.
+func (a *api) LogsForService(cfg LogsForServiceConfig, w io.Writer) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	query := service.LogQuery{
+		ServiceID:  cfg.ServiceID,
+		InstanceID: cfg.InstanceID,
+		Since:      cfg.Since,
+		Limit:      logsPageSize,
+	}
+	for {
+		messages, err := client.GetServiceLogs(query)
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			if _, err := fmt.Fprintf(w, "%s [%s] %s: %s\n", msg.Timestamp.Format(time.RFC3339), msg.InstanceID, msg.File, msg.Message); err != nil {
+				return err
+			}
+			query.Since = msg.Timestamp
+		}
+
+		// a full page means there may be more messages waiting
+		if len(messages) >= logsPageSize {
+			continue
+		} else if !cfg.Follow {
+			return nil
+		}
+
+		select {
+		case <-cfg.Cancel:
+			return nil
+		case <-time.After(logsPollInterval):
+		}
+	}
+}
+
 // LogsForServiceInstance returns the logs for the service instance
 func (a *api) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
 	client, err := a.connectMaster()
@@ -113,6 +219,17 @@ func (a *api) LogsForServiceInstance(serviceID string, instanceID int, command s
 	// report container logs
 
 	if location.HostID != hostID {
+		if a.shouldRelay(location) {
+			req := relay.Request{
+				ServiceID:  serviceID,
+				InstanceID: instanceID,
+				Kind:       relay.KindLogs,
+			}
+			if command != "" {
+				req.Command = append([]string{command}, args...)
+			}
+			return a.relayServiceInstance(req)
+		}
 		cmd, err := a.getSSHCommand(location)
 		if err != nil {
 			return err
@@ -140,9 +257,106 @@ func (a *api) SendDockerAction(serviceID string, instanceID int, action string,
 		return err
 	}
 
+	if err := a.checkInteractiveAccess(client, serviceID); err != nil {
+		return err
+	}
+
 	return client.SendDockerAction(serviceID, instanceID, action, args)
 }
 
+// GetConfigDrift compares the config files of the running instances of a
+// service against the files inside their containers
+func (a *api) GetConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetConfigDrift(serviceID)
+}
+
+// SyncServiceConfigs writes the config files of a service over the files
+// that have drifted inside its containers
+func (a *api) SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SyncServiceConfigs(serviceID, restart)
+}
+
+// checkInteractiveAccess returns an error if this host may not open shells
+// in, attach to or run actions in the instances of a service
+func (a *api) checkInteractiveAccess(client master.ClientInterface, serviceID string) error {
+	hostID, err := utils.HostID()
+	if err != nil {
+		return err
+	}
+	return client.CheckInteractiveAccess(serviceID, hostID)
+}
+
+// shouldRelay returns true if the streams of an instance on another host
+// should be relayed through the master instead of over ssh, such as when
+// the host is behind a NAT or a port forward that ssh cannot get through.
+func (a *api) shouldRelay(location *service.LocationInstance) bool {
+	options := config.GetOptions()
+	switch options.AttachRelay {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if options.RelayPort <= 0 {
+		return false
+	}
+	host, err := a.GetHost(location.HostID)
+	if err != nil || (options.GCloud && len(host.NatIP) <= 0) {
+		return false
+	}
+	hostname := location.HostIP
+	if len(host.NatIP) > 0 {
+		hostname = host.NatIP
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(hostname, "22"), sshDialTimeout)
+	if err != nil {
+		log.WithField("host", hostname).WithError(err).Debug("Cannot reach ssh on the host; relaying through the master")
+		return true
+	}
+	conn.Close()
+	return false
+}
+
+// relayServiceInstance attaches the terminal to a stream of an instance that
+// is relayed through the master
+func (a *api) relayServiceInstance(req relay.Request) error {
+	options := config.GetOptions()
+	masterHost, _, err := net.SplitHostPort(options.Endpoint)
+	if err != nil {
+		return err
+	}
+	muxDisableTLS, _ := strconv.ParseBool(options.MuxDisableTLS)
+	conn, err := relay.Dial(net.JoinHostPort(masterHost, strconv.Itoa(options.MuxPort)), !muxDisableTLS, options.RelayPort, req)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if req.Kind == relay.KindAttach {
+		if req.TTY {
+			fd := int(os.Stdin.Fd())
+			state, err := terminal.MakeRaw(fd)
+			if err != nil {
+				return err
+			}
+			defer terminal.Restore(fd, state)
+		}
+		go io.Copy(conn, os.Stdin)
+	}
+	io.Copy(os.Stdout, conn)
+	return nil
+}
+
 func (a *api) getSSHCommand(location *service.LocationInstance) ([]string, error) {
 	host, err := a.GetHost(location.HostID)
 	if err != nil {
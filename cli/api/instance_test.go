@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestLogsForService_pages(c *C) {
+	since := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	last := since.Add(time.Minute)
+
+	page := make([]service.LogMessage, logsPageSize)
+	for i := range page {
+		page[i] = service.LogMessage{Timestamp: since.Add(time.Second), InstanceID: "0", File: "/a.log", Message: "first"}
+	}
+	page[logsPageSize-1].Timestamp = last
+
+	s.mockMasterClient.On("GetServiceLogs", service.LogQuery{ServiceID: "svc", InstanceID: -1, Since: since, Limit: logsPageSize}).Return(page, nil).Once()
+	s.mockMasterClient.On("GetServiceLogs", service.LogQuery{ServiceID: "svc", InstanceID: -1, Since: last, Limit: logsPageSize}).Return([]service.LogMessage{
+		{Timestamp: last.Add(time.Second), InstanceID: "1", File: "/b.log", Message: "second"},
+	}, nil).Once()
+
+	var buf bytes.Buffer
+	err := s.api.LogsForService(LogsForServiceConfig{ServiceID: "svc", InstanceID: -1, Since: since}, &buf)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Count(buf.Bytes(), []byte("\n")), Equals, logsPageSize+1)
+	c.Assert(bytes.HasSuffix(buf.Bytes(), []byte("2017-01-02T03:05:06Z [1] /b.log: second\n")), Equals, true)
+	s.mockMasterClient.AssertExpectations(c)
+}
+
+func (s *TestAPISuite) TestLogsForService_fails(c *C) {
+	errorStub := errors.New("errorStub: GetServiceLogs() failed")
+	s.mockMasterClient.On("GetServiceLogs", mock.AnythingOfType("service.LogQuery")).Return(nil, errorStub)
+
+	var buf bytes.Buffer
+	err := s.api.LogsForService(LogsForServiceConfig{ServiceID: "svc", InstanceID: -1, Follow: true}, &buf)
+	c.Assert(err, Equals, errorStub)
+	c.Assert(buf.Len(), Equals, 0)
+}
@@ -15,18 +15,32 @@ package api
 
 import (
 	"io"
+	"time"
 
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/commons/startup"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/script"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/validation"
 	"github.com/control-center/serviced/volume"
 )
 
@@ -36,6 +50,7 @@ type API interface {
 	// Server
 	StartServer() error
 	ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealthResult, error)
+	GetStartupProgress() ([]startup.Status, error)
 
 	// Hosts
 	GetHosts() ([]host.Host, error)
@@ -44,16 +59,21 @@ type API interface {
 	AddHost(HostConfig) (*host.Host, []byte, error)
 	AddHostPrivate(HostConfig) (*host.Host, []byte, error)
 	RemoveHost(string) error
+	SetHostMaintenance(string, bool) error
 	GetHostMemory(string) (*metrics.MemoryUsageStats, error)
 	SetHostMemory(HostUpdateConfig) error
+	SetHostLabels(string, map[string]string) error
+	UpdateHostLabels(string, map[string]string, []string) error
 	GetHostPublicKey(string) ([]byte, error)
 	RegisterHost([]byte) error
 	RegisterRemoteHost(*host.Host, utils.URL, []byte, bool) error
 	WriteDelegateKey(string, []byte) error
+	DistributeDelegateKeys([]DelegateKeyTarget, DelegateKeyOptions) []DelegateKeyResult
 	AuthenticateHost(string) (string, int64, error)
 	ResetHostKey(string) ([]byte, error)
 	GetHostWithAuthInfo(string) (*AuthHost, error)
 	GetHostsWithAuthInfo() ([]AuthHost, error)
+	GetHostsWithAuthInfoInPool(string) ([]AuthHost, error)
 
 	// Pools
 	GetResourcePools() ([]pool.ResourcePool, error)
@@ -64,11 +84,66 @@ type API interface {
 	GetPoolIPs(string) (*pool.PoolIPs, error)
 	AddVirtualIP(pool.VirtualIP) error
 	RemoveVirtualIP(pool.VirtualIP) error
+	GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error)
+	GetCrossPoolFlows() ([]pool.CrossPoolFlow, error)
+
+	// Audit
+	GetAuditEntries(time.Time) ([]audit.Entry, error)
+
+	// Events
+	QueryEvents(event.Query) (*event.Page, error)
+
+	// Calendars
+	GetCalendars() ([]calendar.Calendar, error)
+	GetCalendar(string) (*calendar.Calendar, error)
+	AddCalendar(calendar.Calendar) error
+	UpdateCalendar(calendar.Calendar) error
+	RemoveCalendar(string) error
+
+	// Feature flags
+	GetFeatureFlags() ([]feature.Flag, error)
+	EnableFeature(name, poolID, deploymentID string) error
+	DisableFeature(name, poolID, deploymentID string) error
+
+	// Pins
+	GetPins() ([]pin.Pin, error)
+	PinSnapshot(snapshotID, reason string) error
+	PinImage(image, reason string) error
+	Unpin(kind pin.Kind, target string) error
+
+	// Settings
+	GetSettings() ([]setting.Setting, error)
+	SetSetting(name string, scope setting.Scope, scopeID, value string) error
+	UnsetSetting(name string, scope setting.Scope, scopeID string) error
+
+	// Delegate profiles
+	GetDelegateProfiles() ([]delegateprofile.Profile, error)
+	GetDelegateProfile(name string) (*delegateprofile.Profile, error)
+	AddDelegateProfile(name, description string) error
+	RemoveDelegateProfile(name string) error
+	SetDelegateProfileOption(name, option, value string) ([]delegateprofile.HostStatus, error)
+	UnsetDelegateProfileOption(name, option string) ([]delegateprofile.HostStatus, error)
+	RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error)
+	AssignDelegateProfile(poolID, name string) ([]delegateprofile.HostStatus, error)
+	PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error)
+
+	// Secrets
+	GetSecrets() ([]secret.Secret, error)
+	GetSecretValue(string) (string, error)
+	SetSecret(name, description, value string) error
+	RemoveSecret(string) error
+
+	// ZooKeeper ensemble
+	GetZKEnsemble() ([]isvcs.ZKMemberStatus, error)
+	AddZKEnsembleMember(host string) (*master.ZKEnsembleChange, error)
+	RemoveZKEnsembleMember(host string) (*master.ZKEnsembleChange, error)
 
 	// Services
 	GetAllServiceDetails() ([]service.ServiceDetails, error)
+	GetServiceDetailsInPool(string) ([]service.ServiceDetails, error)
 	GetServiceDetails(serviceID string) (*service.ServiceDetails, error)
 	GetServiceStatus(string) (map[string]map[string]interface{}, error)
+	GetServiceStatusInPool(string) (map[string]map[string]interface{}, error)
 	GetService(string) (*service.Service, error)
 	AddService(ServiceConfig) (*service.ServiceDetails, error)
 	CloneService(string, string) (*service.ServiceDetails, error)
@@ -82,8 +157,16 @@ type API interface {
 	PauseService(SchedulerConfig) (int, error)
 	AssignIP(IPConfig) error
 	GetEndpoints(serviceID string, reportImports, reportExports, validate bool) ([]applicationendpoint.EndpointReport, error)
+	GetEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error)
+	GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error)
 	ResolveServicePath(path string, noprefix bool) ([]service.ServiceDetails, error)
 	ClearEmergency(serviceID string) (int, error)
+	GetServicePriorities(serviceID string) ([]service.ServicePriority, error)
+	DeployServiceCanary(CanaryConfig) (string, error)
+	GetServiceImagePins(serviceID string) ([]service.ImagePin, error)
+	RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error)
+	SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error
+	WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error)
 	RemoveIP(args []string) error
 	SetIP(IPConfig) error
 
@@ -98,6 +181,7 @@ type API interface {
 	AddSnapshot(SnapshotConfig) (string, error)
 	RemoveSnapshot(string) error
 	Rollback(string, bool) error
+	GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error)
 	TagSnapshot(string, string) error
 	RemoveSnapshotTag(string, string) (string, error)
 
@@ -108,11 +192,30 @@ type API interface {
 	RemoveServiceTemplate(string) error
 	CompileServiceTemplate(CompileTemplateConfig) (*template.ServiceTemplate, error)
 	DeployServiceTemplate(DeployTemplateConfig) ([]service.ServiceDetails, error)
+	UpgradeServiceTemplate(string, string, bool) ([]template.TemplateChange, error)
+	GetSchema(string) (*validation.Schema, error)
 
 	// Backup & Restore
 	GetBackupEstimate(string, []string) (*dao.BackupEstimate, error)
-	Backup(string, []string, bool) (string, error)
+	Backup(string, []string, bool, string) (string, error)
 	Restore(string) error
+	RestoreAs(string, string, string) error
+	RestoreElastic(string, []string) error
+	GetBackupOperations() ([]dao.BackupOperation, error)
+	ResumeBackupOperation(string) (string, error)
+	DiscardBackupOperation(string) error
+	PruneBackupLayers() (int, int64, error)
+	VerifyBackup(string, bool) (*dfs.BackupVerification, error)
+	GetBackupProgress() (*dao.BackupProgress, error)
+	GetBackupSchedules() ([]backupschedule.BackupSchedule, error)
+	AddBackupSchedule(backupschedule.BackupSchedule) (string, error)
+	RemoveBackupSchedule(string) error
+	GetDFSOperations() ([]dao.DFSOperation, error)
+
+	// Operations
+	GetOperations() ([]dao.Operation, error)
+	GetOperation(string) (*dao.Operation, error)
+	CancelOperation(string) error
 
 	// Docker
 	ResetRegistry() error
@@ -132,6 +235,8 @@ type API interface {
 
 	// Volumes
 	GetVolumeStatus() (*volume.Statuses, error)
+	ResizeVolume(serviceID string, size uint64) (*volume.Quota, error)
+	CheckDFS(repair bool) ([]dfs.FsckProblem, error)
 
 	// Public endpoints
 	AddPublicEndpointPort(serviceid, endpointName, portAddr string, usetls bool, protocol string, isEnabled, restart bool) (*servicedefinition.Port, error)
@@ -140,16 +245,30 @@ type API interface {
 	AddPublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled, restart bool) (*servicedefinition.VHost, error)
 	RemovePublicEndpointVHost(serviceid, endpointName, vhost string) error
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
+	SetPublicEndpointVHostCertificate(serviceid, vhost string, certPEM, keyPEM []byte) error
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
+	GetPublicEndpointsInPool(string) ([]service.PublicEndpoint, error)
 
 	// Service Instances
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
+	GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error)
 	StopServiceInstance(serviceID string, instanceID int) error
+	SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error
 	AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error
 	LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error
+	LogsForService(cfg LogsForServiceConfig, w io.Writer) error
 	SendDockerAction(serviceID string, instanceID int, action string, args []string) error
+	GetConfigDrift(serviceID string) ([]service.ConfigDrift, error)
+	SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error)
 
 	// Debug Management
 	DebugEnableMetrics() (string, error)
 	DebugDisableMetrics() (string, error)
+	CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error)
+
+	// Cluster overview
+	WatchTop(cfg TopConfig, done <-chan struct{}) (<-chan TopView, error)
+
+	// Cluster state
+	ExportState() (*ClusterState, error)
 }
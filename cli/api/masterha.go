@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/zzk"
+)
+
+// masterLeaseRetry is how long a master waits to join the election of the
+// master lease again after it could not take over
+var masterLeaseRetry = 10 * time.Second
+
+/*
+   With SERVICED_MASTER_HA, several masters share an external coordinator
+   ensemble and elect one of themselves to hold the master lease.  The master
+   that holds the lease runs SERVICED_MASTER_HA_SCRIPT with acquire, which
+   attaches the DFS and the isvcs data and moves the endpoint address of the
+   master to this host, and then starts the internal services and serves the
+   cluster.  The others wait in the election.
+
+   A master that loses the lease cannot tell whether another master has taken
+   over, so it fences itself: it stops the internal services, runs the script
+   with release and exits, and its process manager restarts it as a standby.
+*/
+
+// startMasterHA starts the master once this host takes the master lease
+func (d *daemon) startMasterHA() {
+	options := config.GetOptions()
+	node := &zzk.MasterLeader{
+		HostID:   d.hostID,
+		Endpoint: options.Endpoint,
+		Started:  time.Now(),
+	}
+	go func() {
+		for {
+			var conn coordclient.Connection
+			select {
+			case conn = <-zzk.Connect("/", zzk.GetLocalConnection):
+				if conn != nil && d.holdMasterLease(conn, node) {
+					return
+				}
+			case <-d.shutdown:
+				return
+			}
+
+			select {
+			case <-time.After(masterLeaseRetry):
+			case <-d.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// holdMasterLease waits for the master lease and then starts the master.  It
+// returns false if this master should join the election again.
+func (d *daemon) holdMasterLease(conn coordclient.Connection, node *zzk.MasterLeader) bool {
+	options := config.GetOptions()
+	logger := log.WithFields(logrus.Fields{
+		"hostid":   node.HostID,
+		"endpoint": node.Endpoint,
+	})
+
+	lease, err := zzk.NewMasterLease(conn)
+	if err != nil {
+		logger.WithError(err).Error("Unable to join the election of the master lease")
+		return false
+	}
+	logger.Info("Waiting for the master lease")
+	done := make(chan struct{})
+	lost, err := lease.TakeLead(node, done)
+	if err != nil {
+		logger.WithError(err).Error("Unable to take the master lease")
+		close(done)
+		return false
+	}
+
+	select {
+	case <-d.shutdown:
+		lease.ReleaseLead()
+		close(done)
+		return true
+	default:
+	}
+
+	logger.Info("Took the master lease")
+	if err := runMasterHAScript(options.MasterHAScript, "acquire"); err != nil {
+		logger.WithError(err).Error("Unable to take over as the master, releasing the master lease")
+		runMasterHAScript(options.MasterHAScript, "release")
+		lease.ReleaseLead()
+		close(done)
+		return false
+	}
+
+	if err := d.startMaster(); err != nil {
+		logger.WithError(err).Fatal("Unable to start as a serviced master")
+	}
+
+	d.waitGroup.Add(1)
+	go func() {
+		defer d.waitGroup.Done()
+		defer close(done)
+		select {
+		case <-lost:
+			logger.Error("Lost the master lease, stopping internal services")
+			d.stopISVCS()
+			if err := runMasterHAScript(options.MasterHAScript, "release"); err != nil {
+				logger.WithError(err).Error("Unable to release the resources of the master")
+			}
+			logger.Fatal("Exiting so that this master restarts as a standby")
+		case <-d.shutdown:
+		}
+	}()
+	return true
+}
+
+// runMasterHAScript runs the master HA script, if one is configured, with the
+// given action
+func runMasterHAScript(script, action string) error {
+	if script == "" {
+		return nil
+	}
+	logger := log.WithFields(logrus.Fields{
+		"script": script,
+		"action": action,
+	})
+	output, err := exec.Command(script, action).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %s: %s", script, action, err, strings.TrimSpace(string(output)))
+	}
+	logger.Info("Ran the master HA script")
+	return nil
+}
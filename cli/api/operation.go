@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/dao"
+)
+
+// GetOperations returns the backups, restores, template deploys, registry
+// syncs and rebalances that are running or recently ended
+func (a *api) GetOperations() ([]dao.Operation, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetOperations()
+}
+
+// GetOperation returns the status, progress and logs of an operation
+func (a *api) GetOperation(id string) (*dao.Operation, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetOperation(id)
+}
+
+// CancelOperation asks a running operation to stop
+func (a *api) CancelOperation(id string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	return client.CancelOperation(id)
+}
@@ -15,15 +15,21 @@ package api
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/logship"
 	"github.com/control-center/serviced/node"
+	"github.com/control-center/serviced/relay"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/validation"
@@ -49,6 +55,10 @@ func ValidateCommonOptions(opts config.Options) error {
 	if err != nil {
 		return fmt.Errorf("error parsing rpc-disable-tls value %v", err)
 	}
+	rpcutils.RPCUseGRPC, err = strconv.ParseBool(opts.RPCUseGRPC)
+	if err != nil {
+		return fmt.Errorf("error parsing rpc-use-grpc value %v", err)
+	}
 
 	if err := validation.ValidUIAddress(opts.UIPort); err != nil {
 		return fmt.Errorf("error validating UI port: %s", err)
@@ -68,6 +78,79 @@ func ValidateServerOptions(options *config.Options) error {
 		return err
 	}
 
+	switch options.CoordinatorDriver {
+	case "zookeeper", "etcd":
+	default:
+		return fmt.Errorf("Invalid coordinator %q; must be zookeeper or etcd", options.CoordinatorDriver)
+	}
+
+	// Connect to the zookeeper ensemble members that the master added since
+	// the configuration was written
+	if options.CoordinatorDriver == "zookeeper" && len(options.Zookeepers) > 0 {
+		published := readZKEnsembleFile(filepath.Join(options.EtcPath, zkEnsembleFileName))
+		options.Zookeepers = mergeZKServers(options.Zookeepers, published)
+	}
+
+	if _, err := docker.ParseMirrors(options.DockerRegistryMirrors); err != nil {
+		return fmt.Errorf("error validating docker-registry-mirrors: %s", err)
+	}
+
+	switch options.MetricsRollup {
+	case 0, 10, 60:
+	default:
+		return fmt.Errorf("error validating metrics-rollup: %d must be 0, 10 or 60 seconds", options.MetricsRollup)
+	}
+
+	if options.EventMaxDays < 0 || options.EventMaxCount < 0 {
+		return fmt.Errorf("error validating event retention: event-max-days and event-max-count cannot be negative")
+	}
+
+	if options.LogSyslogAddress != "" {
+		if _, err := logship.NewSyslogWriter(options.LogSyslogAddress, nil); err != nil {
+			return fmt.Errorf("error validating log-syslog-address: %s", err)
+		}
+	}
+
+	if options.LogKafkaURL != "" {
+		if _, err := logship.NewKafkaWriter(options.LogKafkaURL, options.LogKafkaTopic); err != nil {
+			return fmt.Errorf("error validating log-kafka-url: %s", err)
+		}
+	}
+
+	if err := validation.StringIn(options.RelayPolicy, relay.Policies...); err != nil {
+		return fmt.Errorf("error validating relay-policy: %s", err)
+	}
+
+	if err := validation.StringIn(options.AttachRelay, "auto", "always", "never"); err != nil {
+		return fmt.Errorf("error validating attach-relay: %s", err)
+	}
+
+	if options.ImagePullPolicy != "" {
+		if err := validation.StringIn(options.ImagePullPolicy, commons.PullAlways, commons.PullIfNotPresent, commons.PullNever); err != nil {
+			return fmt.Errorf("error validating image-pull-policy: %s", err)
+		}
+	}
+
+	if options.ACMEDirectory != "" {
+		if u, err := url.Parse(options.ACMEDirectory); err != nil || u.Scheme != "https" {
+			return fmt.Errorf("error validating acme-directory: %s is not an https url", options.ACMEDirectory)
+		}
+	}
+
+	if options.Master && options.MaintenancePage != "" {
+		if _, err := os.Stat(options.MaintenancePage); err != nil {
+			return fmt.Errorf("error validating maintenance-page: %s", err)
+		}
+	}
+
+	if options.MasterHA {
+		if !options.Master {
+			return fmt.Errorf("error validating master-ha: only masters can run in HA")
+		} else if options.CoordinatorDriver == "zookeeper" && options.StartZK {
+			return fmt.Errorf("error validating master-ha: the masters must share an external zookeeper ensemble; set SERVICED_START_ZK=false")
+		}
+	}
+
 	// Make sure we have an endpoint to work with
 	if len(options.Endpoint) == 0 {
 		if options.Master {
@@ -131,6 +214,41 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		StartZK:                    cfg.BoolVal("START_ZK", true),
 		StartAPIKeyProxy:           cfg.BoolVal("START_API_KEY_PROXY", false),
 		BigTableMetrics:            cfg.BoolVal("BIGTABLE_METRICS", false),
+		NFSv4Only:                  cfg.BoolVal("NFS_V4_ONLY", false),
+		DFSNetworkDriver:           cfg.StringVal("DFS_NETWORK_DRIVER", "nfs"),
+		RBDPool:                    cfg.StringVal("RBD_POOL", "rbd"),
+		RBDImageSize:               cfg.IntVal("RBD_IMAGE_SIZE", 102400),
+		AdmissionWebhooks:          cfg.StringVal("ADMISSION_WEBHOOKS", ""),
+		SchedulerPlugins:           cfg.StringVal("SCHEDULER_PLUGINS", ""),
+		DockerRegistryMirrors:      cfg.StringSlice("DOCKER_REGISTRY_MIRRORS", []string{}),
+		DockerPullRetries:          cfg.IntVal("DOCKER_PULL_RETRIES", 3),
+		DockerPullConcurrency:      cfg.IntVal("DOCKER_PULL_CONCURRENCY", 4),
+		BackupLogstashDays:         cfg.IntVal("BACKUP_LOGSTASH_DAYS", 7),
+		BackupLogstashMaxSize:      cfg.IntVal("BACKUP_LOGSTASH_MAX_SIZE", 5),
+		MasterBootstrap:            cfg.BoolVal("MASTER_BOOTSTRAP", false),
+		MasterHA:                   cfg.BoolVal("MASTER_HA", false),
+		MasterHAScript:             cfg.StringVal("MASTER_HA_SCRIPT", ""),
+		MaxHealthChecks:            cfg.IntVal("MAX_HEALTH_CHECKS", 0),
+		MaxContainerStarts:         cfg.IntVal("MAX_CONTAINER_STARTS", 8),
+		ImagePullPolicy:            cfg.StringVal("IMAGE_PULL_POLICY", commons.PullIfNotPresent),
+		ACMEDirectory:              cfg.StringVal("ACME_DIRECTORY", ""),
+		ACMEEmail:                  cfg.StringVal("ACME_EMAIL", ""),
+		ACMEDomain:                 cfg.StringVal("ACME_DOMAIN", ""),
+		TracingCollector:           cfg.StringVal("TRACING_COLLECTOR", ""),
+		MetricsRollup:              cfg.IntVal("METRICS_ROLLUP", 10),
+		MetricsAggregatorPort:      cfg.IntVal("METRICS_AGGREGATOR_PORT", 8445),
+		MetricsSpoolMaxSize:        cfg.IntVal("METRICS_SPOOL_MAX_SIZE", 512),
+		EventMaxDays:               cfg.IntVal("EVENT_MAX_DAYS", 30),
+		EventMaxCount:              cfg.IntVal("EVENT_MAX_COUNT", 100000),
+		LogShipperPort:             cfg.IntVal("LOG_SHIPPER_PORT", 5045),
+		LogSyslogAddress:           cfg.StringVal("LOG_SYSLOG_ADDRESS", ""),
+		LogSyslogCAFile:            cfg.StringVal("LOG_SYSLOG_CA_FILE", ""),
+		LogKafkaURL:                cfg.StringVal("LOG_KAFKA_URL", ""),
+		LogKafkaTopic:              cfg.StringVal("LOG_KAFKA_TOPIC", "serviced-logs"),
+		RelayPort:                  cfg.IntVal("RELAY_PORT", 22251),
+		RelayPolicy:                cfg.StringVal("RELAY_POLICY", relay.PolicyPool),
+		AttachRelay:                cfg.StringVal("ATTACH_RELAY", "auto"),
+		MaintenancePage:            cfg.StringVal("MAINTENANCE_PAGE", ""),
 		DockerDNS:                  cfg.StringSlice("DOCKER_DNS", []string{}),
 		Master:                     cfg.BoolVal("MASTER", false),
 		MuxPort:                    cfg.IntVal("MUX_PORT", 22250),
@@ -138,6 +256,8 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		KeyPEMFile:                 cfg.StringVal("KEY_FILE", ""),
 		CertPEMFile:                cfg.StringVal("CERT_FILE", ""),
 		Zookeepers:                 cfg.StringSlice("ZK", []string{}),
+		CoordinatorDriver:          cfg.StringVal("COORDINATOR", "zookeeper"),
+		EtcdEndpoints:              cfg.StringSlice("ETCD", []string{}),
 		HostStats:                  cfg.StringVal("STATS_PORT", fmt.Sprintf("%s:8443", masterIP)),
 		StatsPeriod:                cfg.IntVal("STATS_PERIOD", 10),
 		SvcStatsCacheTimeout:       cfg.IntVal("SVCSTATS_CACHE_TIMEOUT", 5),
@@ -166,11 +286,13 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		RPCDialTimeout:             cfg.IntVal("RPC_DIAL_TIMEOUT", 30),
 		RPCCertVerify:              strconv.FormatBool(cfg.BoolVal("RPC_CERT_VERIFY", false)),
 		RPCDisableTLS:              strconv.FormatBool(cfg.BoolVal("RPC_DISABLE_TLS", false)),
+		RPCUseGRPC:                 strconv.FormatBool(cfg.BoolVal("RPC_USE_GRPC", false)),
 		RPCTLSCiphers:              cfg.StringSlice("RPC_TLS_CIPHERS", utils.GetDefaultCiphers("rpc")),
 		RPCTLSMinVersion:           cfg.StringVal("RPC_TLS_MIN_VERSION", utils.DefaultTLSMinVersion),
 		SnapshotTTL:                cfg.IntVal("SNAPSHOT_TTL", 12),
 		StartISVCS:                 cfg.StringSlice("ISVCS_START", []string{}),
 		IsvcsENV:                   cfg.StringNumberedList("ISVCS_ENV", []string{}),
+		IsvcsRestartPolicies:       cfg.StringNumberedList("ISVCS_RESTART_POLICY", []string{}),
 		IsvcsZKID:                  cfg.IntVal("ISVCS_ZOOKEEPER_ID", 0),
 		IsvcsZKQuorum:              cfg.StringSlice("ISVCS_ZOOKEEPER_QUORUM", []string{}),
 		IsvcsZKUsername:            cfg.StringVal("ISVCS_ZOOKEEPER_USERNAME", ""),
@@ -182,14 +304,17 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		AllowLoopBack:              strconv.FormatBool(cfg.BoolVal("ALLOW_LOOP_BACK", false)),
 		UIPollFrequency:            cfg.IntVal("UI_POLL_FREQUENCY", 3),
 		ConntrackFlush:             strconv.FormatBool(cfg.BoolVal("CONNTRACK_FLUSH", false)),
+		PreserveContainers:         strconv.FormatBool(cfg.BoolVal("PRESERVE_CONTAINERS", true)),
 		StorageStatsUpdateInterval: cfg.IntVal("STORAGE_STATS_UPDATE_INTERVAL", 300),
 		SnapshotSpacePercent:       cfg.IntVal("SNAPSHOT_USE_PERCENT", 20),
+		BackupCompression:          cfg.StringVal("BACKUP_COMPRESSION", string(volume.DefaultCompression)),
 		ZKSessionTimeout:           cfg.IntVal("ZK_SESSION_TIMEOUT", 15),
 		ZKConnectTimeout:           cfg.IntVal("ZK_CONNECT_TIMEOUT", 1),
 		ZKPerHostConnectDelay:      cfg.IntVal("ZK_PER_HOST_CONNECT_DELAY", 0),
 		ZKReconnectStartDelay:      cfg.IntVal("ZK_RECONNECT_START_DELAY", 1),
 		ZKReconnectMaxDelay:        cfg.IntVal("ZK_RECONNECT_MAX_DELAY", 1),
 		TokenExpiration:            cfg.IntVal("AUTH_TOKEN_EXPIRATION", 60*60),
+		TokenGracePeriod:           cfg.IntVal("AUTH_TOKEN_GRACE_PERIOD", 60*10),
 		ServiceRunLevelTimeout:     cfg.IntVal("RUN_LEVEL_TIMEOUT", 60*10),
 		StorageReportInterval:      cfg.IntVal("STORAGE_REPORT_INTERVAL", 30),
 		StorageMetricMonitorWindow: cfg.IntVal("STORAGE_METRIC_MONITOR_WINDOW", 300),
@@ -226,6 +351,7 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 	options.LogPath = cfg.StringVal("LOG_PATH", "/var/log/serviced")
 	options.VolumesPath = cfg.StringVal("VOLUMES_PATH", filepath.Join(varpath, "volumes"))
 	options.BackupsPath = cfg.StringVal("BACKUPS_PATH", filepath.Join(varpath, "backups"))
+	options.MetricsSpoolPath = cfg.StringVal("METRICS_SPOOL_PATH", filepath.Join(varpath, "metrics"))
 	options.EtcPath = cfg.StringVal("ETC_PATH", filepath.Join(options.HomePath, "etc"))
 	options.StorageArgs = getDefaultStorageOptions(options.FSType, cfg)
 
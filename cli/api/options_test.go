@@ -123,6 +123,39 @@ func (s *TestAPISuite) TestValidateServerOptionsSetsEndpointIfMasterMissingEndpo
 	c.Assert(len(config.GetOptions().Endpoint), Not(Equals), 0)
 }
 
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfRelayPolicyInvalid(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{"RELAY_POLICY": "everyone"})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	config.LoadOptions(testOptions)
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "error validating relay-policy")
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfMasterHAOnAgent(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Agent = true
+	testOptions.MasterHA = true
+	config.LoadOptions(testOptions)
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "only masters can run in HA")
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfMasterHAStartsZK(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.MasterHA = true
+	testOptions.StartZK = true
+	testOptions.CoordinatorDriver = "zookeeper"
+	testOptions.FSType = volume.DriverTypeBtrFS
+	config.LoadOptions(testOptions)
+	err := ValidateServerOptions(&testOptions)
+	s.assertErrorContent(c, err, "set SERVICED_START_ZK=false")
+}
+
 func (s *TestAPISuite) assertErrorContent(c *C, err error, expectedContent string) {
 	c.Assert(err, Not(IsNil))
 	if !strings.Contains(err.Error(), expectedContent) {
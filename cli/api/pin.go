@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/pin"
+)
+
+// Returns the pinned snapshots and image tags
+func (a *api) GetPins() ([]pin.Pin, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPins()
+}
+
+// Pins a snapshot
+func (a *api) PinSnapshot(snapshotID, reason string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.PinSnapshot(snapshotID, reason)
+}
+
+// Pins a tag of an image in the docker registry
+func (a *api) PinImage(image, reason string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.PinImage(image, reason)
+}
+
+// Removes the pin of a snapshot or image tag
+func (a *api) Unpin(kind pin.Kind, target string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.Unpin(kind, target)
+}
@@ -102,6 +102,28 @@ func (a *api) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return client.GetPoolIPs(id)
 }
 
+// GetVirtualIPEvents returns the reachability and failover events of the
+// virtual IPs of a pool
+func (a *api) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetVirtualIPEvents(poolID)
+}
+
+// GetCrossPoolFlows returns the running imports of endpoints that services in
+// other pools export
+func (a *api) GetCrossPoolFlows() ([]pool.CrossPoolFlow, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetCrossPoolFlows()
+}
+
 // Add a VirtualIP to a specific pool
 func (a *api) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	client, err := a.connectMaster()
@@ -76,6 +76,17 @@ func (a *api) EnablePublicEndpointVHost(serviceid, endpointName, vhost string, i
 	return client.EnablePublicEndpointVHost(serviceid, endpointName, vhost, isEnabled)
 }
 
+// SetPublicEndpointVHostCertificate sets the certificate that a vhost
+// presents, or clears it if certPEM is empty
+func (a *api) SetPublicEndpointVHostCertificate(serviceid, vhost string, certPEM, keyPEM []byte) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetPublicEndpointVHostCertificate(serviceid, vhost, certPEM, keyPEM)
+}
+
 func (a *api) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	client, err := a.connectMaster()
 	if err != nil {
@@ -84,3 +95,14 @@ func (a *api) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 
 	return client.GetAllPublicEndpoints()
 }
+
+// GetPublicEndpointsInPool returns the public endpoints of the services in a
+// resource pool
+func (a *api) GetPublicEndpointsInPool(poolID string) ([]service.PublicEndpoint, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetPublicEndpointsInPool(poolID)
+}
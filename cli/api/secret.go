@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// Returns a list of all secrets without their values
+func (a *api) GetSecrets() ([]secret.Secret, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSecrets()
+}
+
+// Gets the decrypted value of a secret
+func (a *api) GetSecretValue(name string) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+
+	return client.GetSecretValue(name)
+}
+
+// Adds or updates a secret
+func (a *api) SetSecret(name, description, value string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetSecret(name, description, value)
+}
+
+// Removes an existing secret
+func (a *api) RemoveSecret(name string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveSecret(name)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/utils"
 
 	"github.com/control-center/serviced/domain/host"
 	"github.com/pivotal-golang/bytefmt"
@@ -53,7 +54,16 @@ type SchedulerConfig struct {
 	Synchronous bool
 }
 
-// IPConfig is the deserialized object from the command-line
+// CanaryConfig is the deserialized object from the command-line
+type CanaryConfig struct {
+	ServiceID string
+	ImageID   string
+	Fraction  float64
+	Timeout   time.Duration
+}
+
+// IPConfig is the deserialized object from the command-line.  IPAddress may
+// be an IPv4 or IPv6 address, and IPv6 addresses may be in brackets.
 type IPConfig struct {
 	ServiceID	string
 	IPAddress	string
@@ -62,6 +72,11 @@ type IPConfig struct {
 	EndpointName	string
 }
 
+// Address returns the canonical form of the ip address of the config
+func (config IPConfig) Address() string {
+	return utils.CanonicalIP(config.IPAddress)
+}
+
 // Type of method that controls the state of a service
 type ServiceStateController func(SchedulerConfig) (int, error)
 
@@ -74,6 +89,16 @@ func (a *api) GetAllServiceDetails() ([]service.ServiceDetails, error) {
 	return client.GetAllServiceDetails(0)
 }
 
+// GetServiceDetailsInPool returns the services in a resource pool
+func (a *api) GetServiceDetailsInPool(poolID string) ([]service.ServiceDetails, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetServiceDetailsInPool(poolID)
+}
+
 func (a *api) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
 	client, err := a.connectMaster()
 	if err != nil {
@@ -115,7 +140,32 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 		}
 	}
 
-	// get status
+	return getServiceStatus(client, svcs)
+}
+
+// GetServiceStatusInPool returns the status of the services in a resource
+// pool
+func (a *api) GetServiceStatusInPool(poolID string) (map[string]map[string]interface{}, error) {
+	client, err := a.connectDAO()
+	if err != nil {
+		return nil, err
+	}
+	masterClient, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	svcs, err := masterClient.GetServiceDetailsInPool(poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getServiceStatus(client, svcs)
+}
+
+// getServiceStatus returns the status rows of the instances of the given
+// services, keyed by service id and instance id
+func getServiceStatus(client dao.ControlPlane, svcs []service.ServiceDetails) (map[string]map[string]interface{}, error) {
 	rowmap := make(map[string]map[string]interface{})
 	for _, svc := range svcs {
 		var status []service.Instance
@@ -167,6 +217,7 @@ func (a *api) GetServiceStatus(serviceID string) (map[string]map[string]interfac
 				row["Hostname"] = stat.HostName
 				row["DockerID"] = fmt.Sprintf("%.12s", stat.ContainerID)
 				row["Uptime"] = uptime.String()
+				row["Restarts"] = stat.Restarts
 
 				if stat.ImageSynced {
 					row["InSync"] = "Y"
@@ -232,6 +283,26 @@ func (a *api) GetEndpoints(serviceID string, reportImports, reportExports, valid
 	}
 }
 
+// GetEndpointGraph returns the dependency graph of the services of the
+// application that the service belongs to
+func (a *api) GetEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServiceEndpointGraph(serviceID)
+}
+
+// GetEndpointMetrics returns the connection statistics of the imports and
+// exports of a service over the window
+func (a *api) GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetEndpointMetrics(serviceID, window)
+}
+
 // Gets the service definition identified by its service ID. This is the full service object
 func (a *api) GetService(id string) (*service.Service, error) {
 	client, err := a.connectDAO()
@@ -408,7 +479,7 @@ func (a *api) AssignIP(config IPConfig) error {
 
 	req := addressassignment.AssignmentRequest{
 		ServiceID:      config.ServiceID,
-		IPAddress:      config.IPAddress,
+		IPAddress:      config.Address(),
 		AutoAssignment: config.IPAddress == "",
 	}
 
@@ -442,7 +513,7 @@ func (a *api) SetIP(config IPConfig) error {
 
 	req := addressassignment.AssignmentRequest{
 		ServiceID:      config.ServiceID,
-		IPAddress:      config.IPAddress,
+		IPAddress:      config.Address(),
 		AutoAssignment: config.IPAddress == "",
 		Port:		config.Port,
 		Proto:		config.Proto,
@@ -484,3 +555,68 @@ func (a *api) ClearEmergency(serviceID string) (int, error) {
 
 	return client.ClearEmergency(serviceID)
 }
+
+// GetServicePriorities returns the effective priority classes of a service
+// and its descendents, or of every deployment if serviceID is empty
+func (a *api) GetServicePriorities(serviceID string) ([]service.ServicePriority, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetServicePriorities(serviceID)
+}
+
+// DeployServiceCanary rolls a new image out to a fraction of the instances of
+// a service and promotes it if they pass their health checks
+func (a *api) DeployServiceCanary(config CanaryConfig) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+
+	return client.DeployServiceCanary(config.ServiceID, config.ImageID, config.Fraction, config.Timeout)
+}
+
+// GetServiceImagePins returns the image digests that a service and its
+// descendants are pinned to
+func (a *api) GetServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetServiceImagePins(serviceID)
+}
+
+// RefreshServiceImagePins pins a service and its descendants to the digests
+// that their images are currently tagged to
+func (a *api) RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RefreshServiceImagePins(serviceID)
+}
+
+// SetMaintenanceMode puts a tenant into maintenance mode, or takes it out of
+// maintenance mode if mode is nil
+func (a *api) SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetMaintenanceMode(tenantID, mode)
+}
+
+// WaitServiceStateEvents returns the state changes of a service after a
+// sequence number, waiting up to the timeout for one to be published
+func (a *api) WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.WaitServiceStateEvents(serviceID, since, timeout)
+}
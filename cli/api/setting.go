@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/setting"
+)
+
+// Returns every known setting with the values it has been set to
+func (a *api) GetSettings() ([]setting.Setting, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSettings()
+}
+
+// Sets the value of a setting in a scope
+func (a *api) SetSetting(name string, scope setting.Scope, scopeID, value string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetSetting(name, scope, scopeID, value)
+}
+
+// Removes the value of a setting in a scope
+func (a *api) UnsetSetting(name string, scope setting.Scope, scopeID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.UnsetSetting(name, scope, scopeID)
+}
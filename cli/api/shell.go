@@ -105,6 +105,14 @@ func buildMounts(lbClientPort string, serviceID string, defaultMounts []string)
 
 // StartShell runs a command for a given service
 func (a *api) StartShell(config ShellConfig) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	if err := a.checkInteractiveAccess(client, config.ServiceID); err != nil {
+		return err
+	}
+
 	mounts, err := buildMounts(config.ServicedEndpoint, config.ServiceID, config.Mounts)
 	if err != nil {
 		return err
@@ -134,6 +142,14 @@ func (a *api) StartShell(config ShellConfig) error {
 
 // RunShell runs a predefined service shell command via the service definition
 func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error) {
+	masterClient, err := a.connectMaster()
+	if err != nil {
+		return 1, err
+	}
+	if err := a.checkInteractiveAccess(masterClient, config.ServiceID); err != nil {
+		return 1, err
+	}
+
 	client, err := a.connectDAO()
 	if err != nil {
 		return 1, err
@@ -18,6 +18,7 @@ import (
 
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 )
 
 type SnapshotConfig struct {
@@ -131,6 +132,15 @@ func (a *api) Rollback(snapshotID string, forceRestart bool) error {
 	return nil
 }
 
+// GetRollbackPreview returns what a rollback to the given snapshot changes
+func (a *api) GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetRollbackPreview(snapshotID)
+}
+
 // TagSnapshot tags an existing snapshot with 1 or more strings
 func (a *api) TagSnapshot(snapshotID string, tagName string) error {
 	client, err := a.connectDAO()
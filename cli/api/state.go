@@ -0,0 +1,294 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// StateVersion is the version of the cluster state format
+const StateVersion = 1
+
+// ClusterState is a normalized dump of the configuration of a cluster.  Each
+// section maps a key that does not depend on generated ids, such as the name
+// path of a service, to the entity with its ids, timestamps and runtime state
+// removed, so that the states of two clusters can be compared.
+type ClusterState struct {
+	Version   int
+	Pools     map[string]interface{}
+	Hosts     map[string]interface{}
+	Templates map[string]interface{}
+	Services  map[string]interface{}
+	Endpoints map[string]interface{}
+}
+
+// StateDifference is a difference between two cluster states.  Field is
+// empty if the whole entity was added or removed; Old is nil if it was added
+// and New is nil if it was removed.
+type StateDifference struct {
+	Section string
+	Key     string
+	Field   string
+	Old     interface{}
+	New     interface{}
+}
+
+// Fields that change without the configuration changing
+var (
+	statePoolFields     = []string{"CoreCapacity", "MemoryCapacity", "MemoryCommitment", "CreatedAt", "UpdatedAt", "DatabaseVersion"}
+	stateHostFields     = []string{"ID", "CreatedAt", "UpdatedAt", "DatabaseVersion", "Benchmark"}
+	stateTemplateFields = []string{"ID", "DatabaseVersion"}
+	stateServiceFields  = []string{"ID", "ParentServiceID", "DeploymentID", "DesiredState", "CurrentState", "EmergencyShutdown", "CreatedAt", "UpdatedAt", "DatabaseVersion"}
+)
+
+// ExportState returns the normalized state of the cluster
+func (a *api) ExportState() (*ClusterState, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ClusterState{
+		Version:   StateVersion,
+		Pools:     make(map[string]interface{}),
+		Hosts:     make(map[string]interface{}),
+		Templates: make(map[string]interface{}),
+		Services:  make(map[string]interface{}),
+		Endpoints: make(map[string]interface{}),
+	}
+
+	pools, err := client.GetResourcePools()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pools {
+		if state.Pools[p.ID], err = normalizeState(p, statePoolFields); err != nil {
+			return nil, err
+		}
+	}
+
+	hosts, err := client.GetHosts()
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hosts {
+		key := h.Name
+		if _, ok := state.Hosts[key]; ok {
+			key = h.Name + "/" + h.ID
+		}
+		if state.Hosts[key], err = normalizeState(h, stateHostFields); err != nil {
+			return nil, err
+		}
+	}
+
+	templates, err := client.GetServiceTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for id, t := range templates {
+		key := t.Name
+		if t.Version != "" {
+			key = t.Name + "/" + t.Version
+		}
+		if _, ok := state.Templates[key]; ok {
+			key = key + "/" + id
+		}
+		if state.Templates[key], err = normalizeState(t, stateTemplateFields); err != nil {
+			return nil, err
+		}
+	}
+
+	details, err := client.GetAllServiceDetails(0)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*service.ServiceDetails)
+	for i := range details {
+		byID[details[i].ID] = &details[i]
+	}
+	paths := serviceStatePaths(byID)
+	for _, d := range details {
+		svc, err := client.GetService(d.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// images are stored in the registry under the tenant id, which is
+		// different on every cluster
+		tenantID := d.ID
+		for p := byID[d.ParentServiceID]; p != nil; p = byID[p.ParentServiceID] {
+			tenantID = p.ID
+		}
+		svc.ImageID = strings.Replace(svc.ImageID, tenantID, paths[tenantID], -1)
+
+		value, err := normalizeState(svc, stateServiceFields)
+		if err != nil {
+			return nil, err
+		}
+		if endpoints, ok := value["Endpoints"].([]interface{}); ok {
+			for _, ep := range endpoints {
+				if m, ok := ep.(map[string]interface{}); ok {
+					delete(m, "AddressAssignment")
+				}
+			}
+		}
+		state.Services[paths[d.ID]] = value
+	}
+
+	endpoints, err := client.GetAllPublicEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	for _, ep := range endpoints {
+		address := ep.VHostName
+		if address == "" {
+			address = ep.PortAddress
+		}
+		key := path.Join(paths[ep.ServiceID], ep.Application, address)
+		state.Endpoints[key] = map[string]interface{}{
+			"Protocol": ep.Protocol,
+			"Enabled":  ep.Enabled,
+		}
+	}
+
+	return state, nil
+}
+
+// serviceStatePaths returns the name path of each service, without the
+// deployment id unless services of different deployments have the same path
+func serviceStatePaths(byID map[string]*service.ServiceDetails) map[string]string {
+	paths := make(map[string]string)
+	count := make(map[string]int)
+	for id, d := range byID {
+		p := d.Name
+		for parent := byID[d.ParentServiceID]; parent != nil; parent = byID[parent.ParentServiceID] {
+			p = parent.Name + "/" + p
+		}
+		paths[id] = p
+		count[p]++
+	}
+	for id, d := range byID {
+		if p := paths[id]; count[p] > 1 {
+			paths[id] = d.DeploymentID + "/" + p
+		}
+	}
+	return paths
+}
+
+// normalizeState returns the json representation of an entity without the
+// given fields
+func normalizeState(entity interface{}, fields []string) (map[string]interface{}, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	value := make(map[string]interface{})
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		delete(value, field)
+	}
+	return value, nil
+}
+
+// DiffState returns the differences between two cluster states, sorted by
+// section, key and field
+func DiffState(a, b *ClusterState) []StateDifference {
+	diffs := []StateDifference{}
+	sections := []struct {
+		name string
+		a, b map[string]interface{}
+	}{
+		{"pools", a.Pools, b.Pools},
+		{"hosts", a.Hosts, b.Hosts},
+		{"templates", a.Templates, b.Templates},
+		{"services", a.Services, b.Services},
+		{"endpoints", a.Endpoints, b.Endpoints},
+	}
+	for _, s := range sections {
+		for _, key := range unionKeys(s.a, s.b) {
+			oldValue, inA := s.a[key]
+			newValue, inB := s.b[key]
+			if !inA {
+				diffs = append(diffs, StateDifference{Section: s.name, Key: key, New: newValue})
+			} else if !inB {
+				diffs = append(diffs, StateDifference{Section: s.name, Key: key, Old: oldValue})
+			} else {
+				diffs = diffStateValue(diffs, s.name, key, "", oldValue, newValue)
+			}
+		}
+	}
+	return diffs
+}
+
+// diffStateValue appends the differences between two values decoded from
+// json
+func diffStateValue(diffs []StateDifference, section, key, field string, a, b interface{}) []StateDifference {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			for _, k := range unionKeys(av, bv) {
+				diffs = diffStateValue(diffs, section, key, joinStateField(field, k), av[k], bv[k])
+			}
+			return diffs
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				var ai, bi interface{}
+				if i < len(av) {
+					ai = av[i]
+				}
+				if i < len(bv) {
+					bi = bv[i]
+				}
+				diffs = diffStateValue(diffs, section, key, fmt.Sprintf("%s[%d]", field, i), ai, bi)
+			}
+			return diffs
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		diffs = append(diffs, StateDifference{Section: section, Key: key, Field: field, Old: a, New: b})
+	}
+	return diffs
+}
+
+func joinStateField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestExportState(c *C) {
+	s.mockMasterClient.On("GetResourcePools").Return([]pool.ResourcePool{
+		{ID: "default", CoreLimit: 4, CoreCapacity: 8},
+	}, nil)
+	s.mockMasterClient.On("GetHosts").Return([]host.Host{
+		{ID: "abc123", Name: "host-1", PoolID: "default", Cores: 8},
+	}, nil)
+	s.mockMasterClient.On("GetServiceTemplates").Return(map[string]servicetemplate.ServiceTemplate{
+		"template-id": {ID: "template-id", Name: "Zenoss.core", Version: "6.0"},
+	}, nil)
+	s.mockMasterClient.On("GetAllServiceDetails", time.Duration(0)).Return([]service.ServiceDetails{
+		{ID: "tenant-id", Name: "Zenoss.core", DeploymentID: "prod"},
+		{ID: "zope-id", Name: "Zope", ParentServiceID: "tenant-id", DeploymentID: "prod"},
+	}, nil)
+	s.mockMasterClient.On("GetService", "tenant-id").Return(&service.Service{
+		ID: "tenant-id", Name: "Zenoss.core", DeploymentID: "prod", DesiredState: int(service.SVCRun),
+	}, nil)
+	s.mockMasterClient.On("GetService", "zope-id").Return(&service.Service{
+		ID: "zope-id", Name: "Zope", ParentServiceID: "tenant-id", Instances: 2,
+		ImageID: "localhost:5000/tenant-id/core:latest",
+		Endpoints: []service.ServiceEndpoint{
+			{Name: "zope", Application: "zope", Purpose: "export"},
+		},
+	}, nil)
+	s.mockMasterClient.On("GetAllPublicEndpoints").Return([]service.PublicEndpoint{
+		{ServiceID: "zope-id", Application: "zope", VHostName: "zenoss5", Protocol: "https", Enabled: true},
+	}, nil)
+
+	state, err := s.api.ExportState()
+	c.Assert(err, IsNil)
+	c.Assert(state.Version, Equals, StateVersion)
+
+	c.Assert(state.Pools, HasLen, 1)
+	p := state.Pools["default"].(map[string]interface{})
+	c.Assert(p["CoreLimit"], Equals, float64(4))
+	_, ok := p["CoreCapacity"]
+	c.Assert(ok, Equals, false)
+
+	c.Assert(state.Hosts, HasLen, 1)
+	h := state.Hosts["host-1"].(map[string]interface{})
+	_, ok = h["ID"]
+	c.Assert(ok, Equals, false)
+
+	_, ok = state.Templates["Zenoss.core/6.0"]
+	c.Assert(ok, Equals, true)
+
+	c.Assert(state.Services, HasLen, 2)
+	tenant := state.Services["Zenoss.core"].(map[string]interface{})
+	_, ok = tenant["DesiredState"]
+	c.Assert(ok, Equals, false)
+	zope := state.Services["Zenoss.core/Zope"].(map[string]interface{})
+	c.Assert(zope["ImageID"], Equals, "localhost:5000/Zenoss.core/core:latest")
+	_, ok = zope["ParentServiceID"]
+	c.Assert(ok, Equals, false)
+	endpoint := zope["Endpoints"].([]interface{})[0].(map[string]interface{})
+	_, ok = endpoint["AddressAssignment"]
+	c.Assert(ok, Equals, false)
+
+	c.Assert(state.Endpoints, DeepEquals, map[string]interface{}{
+		"Zenoss.core/Zope/zope/zenoss5": map[string]interface{}{"Protocol": "https", "Enabled": true},
+	})
+}
+
+func (s *TestAPISuite) TestServiceStatePaths(c *C) {
+	details := []service.ServiceDetails{
+		{ID: "a", Name: "Zenoss.core", DeploymentID: "prod"},
+		{ID: "b", Name: "Zope", ParentServiceID: "a", DeploymentID: "prod"},
+		{ID: "c", Name: "Zenoss.core", DeploymentID: "test"},
+	}
+	byID := make(map[string]*service.ServiceDetails)
+	for i := range details {
+		byID[details[i].ID] = &details[i]
+	}
+	c.Assert(serviceStatePaths(byID), DeepEquals, map[string]string{
+		"a": "prod/Zenoss.core",
+		"b": "Zenoss.core/Zope",
+		"c": "test/Zenoss.core",
+	})
+}
+
+func (s *TestAPISuite) TestDiffState(c *C) {
+	a := &ClusterState{
+		Version: StateVersion,
+		Pools: map[string]interface{}{
+			"default": map[string]interface{}{"CoreLimit": float64(4)},
+			"old":     map[string]interface{}{},
+		},
+		Services: map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{
+				"Instances":   float64(2),
+				"Environment": []interface{}{"A=1"},
+				"Context":     map[string]interface{}{"x": "1"},
+			},
+		},
+	}
+	b := &ClusterState{
+		Version: StateVersion,
+		Pools: map[string]interface{}{
+			"default": map[string]interface{}{"CoreLimit": float64(4)},
+			"new":     map[string]interface{}{},
+		},
+		Services: map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{
+				"Instances":   float64(3),
+				"Environment": []interface{}{"A=1", "B=2"},
+				"Context":     map[string]interface{}{"x": "2"},
+			},
+		},
+	}
+
+	c.Assert(DiffState(a, a), HasLen, 0)
+	c.Assert(DiffState(a, b), DeepEquals, []StateDifference{
+		{Section: "pools", Key: "new", New: map[string]interface{}{}},
+		{Section: "pools", Key: "old", Old: map[string]interface{}{}},
+		{Section: "services", Key: "Zenoss.core/Zope", Field: "Context.x", Old: "1", New: "2"},
+		{Section: "services", Key: "Zenoss.core/Zope", Field: "Environment[1]", New: "B=2"},
+		{Section: "services", Key: "Zenoss.core/Zope", Field: "Instances", Old: float64(2), New: float64(3)},
+	})
+}
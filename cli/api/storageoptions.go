@@ -45,6 +45,9 @@ func getDefaultStorageOptions(driverType volume.DriverType, config utils.ConfigR
 		addStorageOption(config, "DM_LOOPMETADATASIZE", "", func(v string) {
 			options = append(options, fmt.Sprintf("dm.loopmetadatasize=%s", v))
 		})
+		addStorageOption(config, "DM_EXPORTCONCURRENCY", "", func(v string) {
+			options = append(options, fmt.Sprintf("exportconcurrency=%s", v))
+		})
 		addStorageOption(config, "DM_ARGS", "", func(v string) {
 			options = append(options, strings.Split(v, " ")...)
 		})
@@ -107,11 +107,12 @@ func (s *TestAPISuite) TestGetDefaultDevicemapperOptionsForThinpoolDevice(c *C)
 
 func (s *TestAPISuite) TestGetDefaultDevicemapperOptionsForAll(c *C) {
 	configReader := utils.TestConfigReader(map[string]string{
-		"DM_THINPOOLDEV":      "foo",
-		"DM_BASESIZE":         "200G",
-		"DM_LOOPDATASIZE":     "10G",
-		"DM_LOOPMETADATASIZE": "1G",
-		"DM_ARGS":             "arg1=a,arg2=b,arg3=c",
+		"DM_THINPOOLDEV":       "foo",
+		"DM_BASESIZE":          "200G",
+		"DM_LOOPDATASIZE":      "10G",
+		"DM_LOOPMETADATASIZE":  "1G",
+		"DM_EXPORTCONCURRENCY": "8",
+		"DM_ARGS":              "arg1=a,arg2=b,arg3=c",
 	})
 	options := getDefaultStorageOptions(volume.DriverTypeDeviceMapper, configReader)
 	verifyOptions(c, options, []string{
@@ -119,6 +120,7 @@ func (s *TestAPISuite) TestGetDefaultDevicemapperOptionsForAll(c *C) {
 		"dm.basesize=200G",
 		"dm.loopdatasize=10G",
 		"dm.loopmetadatasize=1G",
+		"exportconcurrency=8",
 		"arg1=a,arg2=b,arg3=c",
 	})
 }
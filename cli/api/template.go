@@ -17,10 +17,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/validation"
 )
 
 // DeployTemplateConfig is the configuration object to deploy a template
@@ -29,6 +31,7 @@ type DeployTemplateConfig struct {
 	PoolID          string
 	DeploymentID    string
 	ManualAssignIPs bool
+	Values          template.Values
 }
 
 // CompileTemplateConfig is the configuration object to conpile a template directory
@@ -82,9 +85,23 @@ func (a *api) GetServiceTemplate(id string) (*template.ServiceTemplate, error) {
 
 // Adds a new service template
 func (a *api) AddServiceTemplate(reader io.Reader) (*template.ServiceTemplate, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the JSON against the schema, so type errors are reported with
+	// their path in the template
+	if err := template.ValidateJSON(data); err != nil {
+		if _, ok := err.(*validation.ValidationError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not unmarshal json: %s", err)
+	}
+
 	// Unmarshal JSON from the reader
 	var t template.ServiceTemplate
-	if err := json.NewDecoder(reader).Decode(&t); err != nil {
+	if err := json.Unmarshal(data, &t); err != nil {
 		return nil, fmt.Errorf("could not unmarshal json: %s", err)
 	}
 
@@ -103,6 +120,17 @@ func (a *api) AddServiceTemplate(reader io.Reader) (*template.ServiceTemplate, e
 
 }
 
+// GetSchema returns the JSON Schema of service templates or services that the
+// master validates them against
+func (a *api) GetSchema(name string) (*validation.Schema, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSchema(name)
+}
+
 // RemoveTemplate removes an existing template by its template ID
 func (a *api) RemoveServiceTemplate(id string) error {
 	client, err := a.connectMaster()
@@ -146,6 +174,7 @@ func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.Serv
 		PoolID:       config.PoolID,
 		TemplateID:   config.ID,
 		DeploymentID: config.DeploymentID,
+		Values:       config.Values,
 	}
 
 	ids, err := client.DeployTemplate(req);
@@ -168,3 +197,20 @@ func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.Serv
 
 	return svcs, nil
 }
+
+// UpgradeServiceTemplate upgrades the services of a deployment to a version
+// of a template and returns the changes.  If dryRun is true, the changes are
+// not applied.
+func (a *api) UpgradeServiceTemplate(templateID, deploymentID string, dryRun bool) ([]template.TemplateChange, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	req := template.ServiceTemplateUpgradeRequest{
+		TemplateID:   templateID,
+		DeploymentID: deploymentID,
+		DryRun:       dryRun,
+	}
+	return client.UpgradeTemplate(req)
+}
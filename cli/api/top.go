@@ -0,0 +1,323 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/rpc/master"
+)
+
+// TopConfig is the configuration for watching the cluster overview
+type TopConfig struct {
+	PoolID   string
+	Interval time.Duration
+	Count    int // number of busiest services to report
+}
+
+// TopView is a snapshot of the pools, hosts and busiest services
+type TopView struct {
+	Pools    []TopPool
+	Hosts    []TopHost
+	Services []TopService
+	Updated  time.Time
+	Err      error // error of the last refresh, if the view is stale
+}
+
+// TopPool is the overview of a resource pool
+type TopPool struct {
+	ID               string
+	Hosts            int
+	ActiveHosts      int
+	CoreCapacity     int
+	MemoryCapacity   uint64
+	MemoryCommitment uint64
+	Instances        int
+}
+
+// TopHost is the overview of a host
+type TopHost struct {
+	ID          string
+	Name        string
+	PoolID      string
+	Active      bool
+	Cores       int
+	Memory      uint64
+	Instances   int
+	MemoryUsage int64
+}
+
+// TopService is the overview of a service
+type TopService struct {
+	ID            string
+	Name          string
+	PoolID        string
+	State         string
+	Instances     int
+	Running       int
+	RAMCommitment uint64
+	MemoryUsage   int64
+	Health        string
+}
+
+// topWatcher keeps the hosts and services up to date between refreshes by
+// only requesting the ones that changed since the last change token.
+type topWatcher struct {
+	client       master.ClientInterface
+	cfg          TopConfig
+	hostToken    string
+	serviceToken string
+	hosts        map[string]host.Host
+	services     map[string]service.ServiceDetails
+	last         TopView
+}
+
+// WatchTop sends a new overview of the cluster every interval until done is
+// closed.
+func (a *api) WatchTop(cfg TopConfig, done <-chan struct{}) (<-chan TopView, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 2 * time.Second
+	}
+	w := &topWatcher{
+		client:   client,
+		cfg:      cfg,
+		hosts:    make(map[string]host.Host),
+		services: make(map[string]service.ServiceDetails),
+	}
+
+	views := make(chan TopView)
+	go func() {
+		defer close(views)
+		for {
+			select {
+			case views <- w.refresh():
+			case <-done:
+				return
+			}
+			select {
+			case <-time.After(cfg.Interval):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return views, nil
+}
+
+// refresh returns the current view, or the last view with the error if the
+// master could not be reached.
+func (w *topWatcher) refresh() TopView {
+	view, err := w.view()
+	if err != nil {
+		stale := w.last
+		stale.Err = err
+		return stale
+	}
+	w.last = *view
+	return *view
+}
+
+func (w *topWatcher) view() (*TopView, error) {
+	hostChanges, err := w.client.GetHostsChanges(w.hostToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hostChanges.Hosts {
+		w.hosts[h.ID] = h
+	}
+	hostIDs := topIDSet(hostChanges.HostIDs)
+	for id := range w.hosts {
+		if !hostIDs[id] {
+			delete(w.hosts, id)
+		}
+	}
+	w.hostToken = hostChanges.Token
+
+	svcChanges, err := w.client.GetServiceDetailsChanges(w.serviceToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range svcChanges.Services {
+		w.services[svc.ID] = svc
+	}
+	serviceIDs := topIDSet(svcChanges.ServiceIDs)
+	for id := range w.services {
+		if !serviceIDs[id] {
+			delete(w.services, id)
+		}
+	}
+	w.serviceToken = svcChanges.Token
+
+	pools, err := w.client.GetResourcePools()
+	if err != nil {
+		return nil, err
+	}
+	activeIDs, err := w.client.GetActiveHostIDs()
+	if err != nil {
+		return nil, err
+	}
+	active := topIDSet(activeIDs)
+	healths, err := w.client.GetServicesHealth()
+	if err != nil {
+		return nil, err
+	}
+
+	view := &TopView{Updated: time.Now()}
+
+	// only running services have instances to report
+	hostStats := make(map[string]*TopHost)
+	for _, svc := range w.services {
+		if w.cfg.PoolID != "" && svc.PoolID != w.cfg.PoolID {
+			continue
+		}
+		row := TopService{
+			ID:            svc.ID,
+			Name:          svc.Name,
+			PoolID:        svc.PoolID,
+			State:         service.DesiredState(svc.DesiredState).String(),
+			Instances:     svc.Instances,
+			RAMCommitment: svc.RAMCommitment.Value,
+			Health:        topHealth(healths[svc.ID]),
+		}
+		if svc.DesiredState == int(service.SVCRun) && svc.Instances > 0 {
+			insts, err := w.client.GetServiceInstances(svc.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, inst := range insts {
+				if inst.CurrentState != service.StateRunning {
+					continue
+				}
+				row.Running++
+				row.MemoryUsage += inst.MemoryUsage.Cur
+				stat, ok := hostStats[inst.HostID]
+				if !ok {
+					stat = &TopHost{}
+					hostStats[inst.HostID] = stat
+				}
+				stat.Instances++
+				stat.MemoryUsage += inst.MemoryUsage.Cur
+			}
+		}
+		view.Services = append(view.Services, row)
+	}
+	sort.Sort(topServicesByUsage(view.Services))
+	if w.cfg.Count > 0 && len(view.Services) > w.cfg.Count {
+		view.Services = view.Services[:w.cfg.Count]
+	}
+
+	poolStats := make(map[string]*TopPool)
+	for _, p := range pools {
+		if w.cfg.PoolID != "" && p.ID != w.cfg.PoolID {
+			continue
+		}
+		poolStats[p.ID] = &TopPool{
+			ID:               p.ID,
+			CoreCapacity:     p.CoreCapacity,
+			MemoryCapacity:   p.MemoryCapacity,
+			MemoryCommitment: p.MemoryCommitment,
+		}
+	}
+
+	for _, h := range w.hosts {
+		if w.cfg.PoolID != "" && h.PoolID != w.cfg.PoolID {
+			continue
+		}
+		row := TopHost{
+			ID:     h.ID,
+			Name:   h.Name,
+			PoolID: h.PoolID,
+			Active: active[h.ID],
+			Cores:  h.Cores,
+			Memory: h.Memory,
+		}
+		if stat, ok := hostStats[h.ID]; ok {
+			row.Instances = stat.Instances
+			row.MemoryUsage = stat.MemoryUsage
+		}
+		if p, ok := poolStats[h.PoolID]; ok {
+			p.Hosts++
+			if row.Active {
+				p.ActiveHosts++
+			}
+			p.Instances += row.Instances
+		}
+		view.Hosts = append(view.Hosts, row)
+	}
+	sort.Sort(topHostsByName(view.Hosts))
+
+	for _, p := range poolStats {
+		view.Pools = append(view.Pools, *p)
+	}
+	sort.Sort(topPoolsByID(view.Pools))
+	return view, nil
+}
+
+// topHealth summarizes the health checks of all instances of a service
+func topHealth(instances map[int]map[string]health.HealthStatus) string {
+	if len(instances) == 0 {
+		return ""
+	}
+	for _, checks := range instances {
+		for _, check := range checks {
+			if check.Status != health.OK {
+				return "failed"
+			}
+		}
+	}
+	return "passed"
+}
+
+// topIDSet returns the set of ids that still exist, to detect removals
+func topIDSet(ids []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+type topServicesByUsage []TopService
+
+func (s topServicesByUsage) Len() int      { return len(s) }
+func (s topServicesByUsage) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s topServicesByUsage) Less(i, j int) bool {
+	if s[i].MemoryUsage != s[j].MemoryUsage {
+		return s[i].MemoryUsage > s[j].MemoryUsage
+	}
+	if s[i].Running != s[j].Running {
+		return s[i].Running > s[j].Running
+	}
+	return s[i].Name < s[j].Name
+}
+
+type topHostsByName []TopHost
+
+func (s topHostsByName) Len() int           { return len(s) }
+func (s topHostsByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s topHostsByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+type topPoolsByID []TopPool
+
+func (s topPoolsByID) Len() int           { return len(s) }
+func (s topPoolsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s topPoolsByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
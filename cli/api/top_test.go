@@ -0,0 +1,91 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/utils"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestWatchTop(c *C) {
+	hosts := []host.Host{
+		{ID: "host-1", Name: "host-1", PoolID: "default", Cores: 4, Memory: 1024},
+		{ID: "host-2", Name: "host-2", PoolID: "default", Cores: 2, Memory: 512},
+	}
+	svcs := []service.ServiceDetails{
+		{ID: "svc-1", Name: "svc-1", PoolID: "default", Instances: 1, DesiredState: int(service.SVCRun), RAMCommitment: utils.EngNotation{Value: 256}},
+		{ID: "svc-2", Name: "svc-2", PoolID: "default", Instances: 2, DesiredState: int(service.SVCRun)},
+		{ID: "svc-3", Name: "svc-3", PoolID: "other", Instances: 1, DesiredState: int(service.SVCRun)},
+	}
+	s.mockMasterClient.On("GetHostsChanges", "").Return(&master.HostChanges{
+		Hosts: hosts, HostIDs: []string{"host-1", "host-2"}, Token: "1",
+	}, nil).Once()
+	s.mockMasterClient.On("GetHostsChanges", "1").Return(&master.HostChanges{
+		HostIDs: []string{"host-1"}, Token: "2",
+	}, nil)
+	s.mockMasterClient.On("GetServiceDetailsChanges", "").Return(&master.ServiceDetailsChanges{
+		Services: svcs, ServiceIDs: []string{"svc-1", "svc-2", "svc-3"}, Token: "1",
+	}, nil).Once()
+	s.mockMasterClient.On("GetServiceDetailsChanges", "1").Return(&master.ServiceDetailsChanges{
+		ServiceIDs: []string{"svc-1", "svc-2", "svc-3"}, Token: "2",
+	}, nil)
+	s.mockMasterClient.On("GetResourcePools").Return([]pool.ResourcePool{{ID: "default", CoreCapacity: 6}, {ID: "other"}}, nil)
+	s.mockMasterClient.On("GetActiveHostIDs").Return([]string{"host-1"}, nil)
+	s.mockMasterClient.On("GetServicesHealth").Return(map[string]map[int]map[string]health.HealthStatus{
+		"svc-2": {0: {"ready": {Status: health.OK}}, 1: {"ready": {Status: health.Failed}}},
+	}, nil)
+	s.mockMasterClient.On("GetServiceInstances", "svc-1").Return([]service.Instance{
+		{HostID: "host-1", CurrentState: service.StateRunning, MemoryUsage: service.Usage{Cur: 100}},
+	}, nil)
+	s.mockMasterClient.On("GetServiceInstances", "svc-2").Return([]service.Instance{
+		{HostID: "host-1", CurrentState: service.StateRunning, MemoryUsage: service.Usage{Cur: 200}},
+		{HostID: "host-2", CurrentState: service.StateStopped},
+	}, nil)
+
+	done := make(chan struct{})
+	defer close(done)
+	views, err := s.api.WatchTop(TopConfig{PoolID: "default", Interval: time.Millisecond}, done)
+	c.Assert(err, IsNil)
+
+	view := <-views
+	c.Assert(view.Err, IsNil)
+	c.Assert(view.Pools, DeepEquals, []TopPool{{ID: "default", Hosts: 2, ActiveHosts: 1, CoreCapacity: 6, Instances: 2}})
+	c.Assert(view.Hosts, HasLen, 2)
+	c.Assert(view.Hosts[0].Active, Equals, true)
+	c.Assert(view.Hosts[0].Instances, Equals, 2)
+	c.Assert(view.Hosts[0].MemoryUsage, Equals, int64(300))
+	c.Assert(view.Hosts[1].Active, Equals, false)
+	c.Assert(view.Services, HasLen, 2)
+	c.Assert(view.Services[0].ID, Equals, "svc-2")
+	c.Assert(view.Services[0].Running, Equals, 1)
+	c.Assert(view.Services[0].Health, Equals, "failed")
+	c.Assert(view.Services[1].ID, Equals, "svc-1")
+	c.Assert(view.Services[1].RAMCommitment, Equals, uint64(256))
+	c.Assert(view.Services[1].Health, Equals, "")
+
+	// removed hosts are dropped from the next view
+	view = <-views
+	c.Assert(view.Err, IsNil)
+	c.Assert(view.Hosts, HasLen, 1)
+	c.Assert(view.Hosts[0].ID, Equals, "host-1")
+}
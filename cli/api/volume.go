@@ -13,7 +13,10 @@
 
 package api
 
-import "github.com/control-center/serviced/volume"
+import (
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+)
 
 func (a *api) GetVolumeStatus() (*volume.Statuses, error) {
 	client, err := a.connectMaster()
@@ -27,3 +30,23 @@ func (a *api) GetVolumeStatus() (*volume.Statuses, error) {
 	}
 	return response, nil
 }
+
+// ResizeVolume sets the quota of the volume of the tenant that owns the
+// service
+func (a *api) ResizeVolume(serviceID string, size uint64) (*volume.Quota, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.ResizeVolume(serviceID, size)
+}
+
+// CheckDFS cross-checks the volumes, snapshots, registry and service images
+// of the dfs, repairing the safe problems if repair is true
+func (a *api) CheckDFS(repair bool) ([]dfs.FsckProblem, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.CheckDFS(repair)
+}
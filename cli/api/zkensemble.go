@@ -0,0 +1,103 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/zzk"
+)
+
+// zkEnsembleFileName is the file under the etc path that keeps the servers
+// of the zookeeper ensemble that the master last published
+const zkEnsembleFileName = "zk-ensemble"
+
+// readZKEnsembleFile returns the servers in the zookeeper ensemble file, or
+// nil if it does not exist.
+func readZKEnsembleFile(filename string) []string {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithError(err).WithField("file", filename).Warn("Unable to read the zookeeper ensemble file")
+		}
+		return nil
+	}
+	servers := []string{}
+	for _, server := range strings.Split(string(data), ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// writeZKEnsembleFile saves the servers of the zookeeper ensemble
+func writeZKEnsembleFile(filename string, servers []string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, []byte(strings.Join(servers, ",")+"\n"), 0644)
+}
+
+// mergeZKServers returns the configured zookeeper servers followed by the
+// published servers that are not configured.  Connecting to a server that
+// has left the ensemble fails over to the next one, so the configured
+// servers are kept.
+func mergeZKServers(configured, published []string) []string {
+	servers := append([]string{}, configured...)
+	for _, server := range published {
+		found := false
+		for _, s := range servers {
+			if s == server {
+				found = true
+				break
+			}
+		}
+		if !found {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
+
+// syncZKEnsemble saves the zookeeper ensemble each time the master publishes
+// it, so that serviced connects to the new members when it restarts.
+func (d *daemon) syncZKEnsemble() {
+	options := config.GetOptions()
+	filename := filepath.Join(options.EtcPath, zkEnsembleFileName)
+	logger := log.WithField("file", filename)
+
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		logger.WithError(err).Warn("Unable to watch the zookeeper ensemble")
+		return
+	}
+	err = zzk.WatchEnsemble(d.shutdown, conn, func(ensemble zzk.Ensemble) {
+		if err := writeZKEnsembleFile(filename, ensemble.Servers); err != nil {
+			logger.WithError(err).Warn("Unable to save the zookeeper ensemble")
+			return
+		}
+		logger.WithFields(logrus.Fields{
+			"servers": ensemble.Servers,
+		}).Info("Saved the zookeeper ensemble published by the master")
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Stopped watching the zookeeper ensemble")
+	}
+}
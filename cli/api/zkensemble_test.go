@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestAPISuite) TestMergeZKServers(c *C) {
+	servers := mergeZKServers([]string{"host1:2181", "host2:2181"}, []string{"host2:2181", "host3:2181"})
+	c.Assert(servers, DeepEquals, []string{"host1:2181", "host2:2181", "host3:2181"})
+
+	servers = mergeZKServers([]string{"host1:2181"}, nil)
+	c.Assert(servers, DeepEquals, []string{"host1:2181"})
+}
+
+func (s *TestAPISuite) TestZKEnsembleFile(c *C) {
+	dir, err := ioutil.TempDir("", "serviced-zk-ensemble-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "etc", zkEnsembleFileName)
+
+	c.Assert(readZKEnsembleFile(filename), IsNil)
+	c.Assert(writeZKEnsembleFile(filename, []string{"host1:2181", "host2:2181"}), IsNil)
+	c.Assert(readZKEnsembleFile(filename), DeepEquals, []string{"host1:2181", "host2:2181"})
+}
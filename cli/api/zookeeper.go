@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/rpc/master"
+)
+
+// Returns the state of each member of the zookeeper ensemble
+func (a *api) GetZKEnsemble() ([]isvcs.ZKMemberStatus, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetZKEnsemble()
+}
+
+// Adds a host to the zookeeper ensemble
+func (a *api) AddZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AddZKEnsembleMember(host)
+}
+
+// Removes a host from the zookeeper ensemble
+func (a *api) RemoveZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RemoveZKEnsembleMember(host)
+}
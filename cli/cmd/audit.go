@@ -0,0 +1,120 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced audit subcommands
+func (c *ServicedCli) initAudit() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "audit",
+		Usage:       "Administers the audit log",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the audit log entries of mutating operations",
+				Description:  "serviced audit list [--since DURATION|TIME]",
+				BashComplete: nil,
+				Action:       c.cmdAuditList,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "since",
+						Value: "24h",
+						Usage: "Show entries newer than a duration (e.g. 30m, 24h) or a time (YYYY-MM-DD or RFC3339)",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Time,User,Action,Type,ID,Success,Changes,Message",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			},
+		},
+	})
+}
+
+// parseSince returns the time of a duration before now, or of a date or a
+// RFC3339 time
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", since, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("could not parse %q as a duration or a time", since)
+}
+
+// serviced audit list [--since DURATION|TIME] [--verbose] [--show-fields FIELDS]
+func (c *ServicedCli) cmdAuditList(ctx *cli.Context) {
+	since, err := parseSince(ctx.String("since"), time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	entries, err := c.driver.GetAuditEntries(since)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "no audit entries found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonEntries, err := json.MarshalIndent(entries, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal audit entries: %s", err)
+		} else {
+			fmt.Println(string(jsonEntries))
+		}
+		return
+	}
+
+	t := NewTable(ctx.String("show-fields"))
+	t.Padding = 6
+	for _, entry := range entries {
+		changes := make([]string, len(entry.Changes))
+		for i, change := range entry.Changes {
+			changes[i] = change.Field
+		}
+		t.AddRow(map[string]interface{}{
+			"Time":    entry.Time.Format(time.RFC3339),
+			"User":    entry.User,
+			"Action":  entry.Action,
+			"Type":    entry.Type,
+			"ID":      entry.EntityID,
+			"Success": entry.Success,
+			"Changes": strings.Join(changes, ","),
+			"Message": entry.Message,
+		})
+	}
+	t.Print()
+}
@@ -0,0 +1,122 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+)
+
+type AuditAPITest struct {
+	api.API
+	entries []audit.Entry
+	since   *time.Time
+}
+
+func (t AuditAPITest) GetAuditEntries(since time.Time) ([]audit.Entry, error) {
+	*t.since = since
+	entries := []audit.Entry{}
+	for _, entry := range t.entries {
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func NewAuditAPITest() AuditAPITest {
+	return AuditAPITest{
+		entries: []audit.Entry{
+			{
+				ID:       "1",
+				Time:     time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC),
+				User:     "admin",
+				Action:   audit.Update,
+				Type:     "resourcepool",
+				EntityID: "default",
+				Message:  "Updating Resource Pool",
+				Success:  true,
+				Changes:  []audit.Change{{Field: "Description", Before: `""`, After: `"pool"`}},
+			}, {
+				ID:       "2",
+				Time:     time.Date(2017, time.June, 1, 12, 5, 0, 0, time.UTC),
+				User:     "system",
+				Action:   audit.Remove,
+				Type:     "host",
+				EntityID: "abc123",
+				Message:  "Removing Host",
+				Success:  false,
+			},
+		},
+		since: &time.Time{},
+	}
+}
+
+func runAuditCmd(t AuditAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdAuditList() {
+	runAuditCmd(NewAuditAPITest(), "serviced", "audit", "list", "--since", "2017-06-01T00:00:00Z")
+
+	// Output:
+	// Time                      User        Action      Type              ID           Success      Changes          Message
+	// 2017-06-01T12:00:00Z      admin       update      resourcepool      default      true         Description      Updating Resource Pool
+	// 2017-06-01T12:05:00Z      system      remove      host              abc123       false                         Removing Host
+}
+
+func ExampleServicedCLI_CmdAuditList_none() {
+	pipeStderr(func() {
+		runAuditCmd(NewAuditAPITest(), "serviced", "audit", "list", "--since", "1h")
+	})
+
+	// Output:
+	// no audit entries found
+}
+
+func TestServicedCLI_CmdAuditList_since(t *testing.T) {
+	test := NewAuditAPITest()
+	before := time.Now()
+	pipeStderr(func() { runAuditCmd(test, "serviced", "audit", "list") })
+	after := time.Now()
+	if test.since.Before(before.Add(-24*time.Hour)) || test.since.After(after.Add(-24*time.Hour)) {
+		t.Fatalf("Expected entries of the last day, got since %s", *test.since)
+	}
+
+	pipeStderr(func() { runAuditCmd(test, "serviced", "audit", "list", "--since", "2017-06-01T12:01:00Z") })
+	if expected := time.Date(2017, time.June, 1, 12, 1, 0, 0, time.UTC); !test.since.Equal(expected) {
+		t.Fatalf("Expected since %s, got %s", expected, *test.since)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC)
+	if since, err := parseSince("30m", now); err != nil || !since.Equal(now.Add(-30*time.Minute)) {
+		t.Errorf("Unexpected result for a duration: %s, %v", since, err)
+	}
+	if since, err := parseSince("2017-05-01", now); err != nil || since.Format("2006-01-02 15:04") != "2017-05-01 00:00" {
+		t.Errorf("Unexpected result for a date: %s, %v", since, err)
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Errorf("Expected an error for an invalid time")
+	}
+}
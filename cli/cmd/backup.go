@@ -16,10 +16,24 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/volume"
+	"github.com/dustin/go-humanize"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
+// backupProgressInterval is how often the progress of a backup or restore
+// is polled
+var backupProgressInterval = 2 * time.Second
+
 // Initializer for serviced backup and serviced restore
 func (c *ServicedCli) initBackup() {
 	c.app.Commands = append(
@@ -27,7 +41,7 @@ func (c *ServicedCli) initBackup() {
 		cli.Command{
 			Name:        "backup",
 			Usage:       "Dump all templates and services to a tgz file",
-			Description: "serviced backup DIRPATH",
+			Description: "serviced backup DIRPATH | status | resume [OPERATIONID] | discard OPERATIONID | prune | verify FILEPATH | schedule [add CRON [DIRPATH] | remove SCHEDULEID]",
 			Action:      c.cmdBackup,
 			Flags: []cli.Flag{
 				cli.StringSliceFlag{
@@ -43,6 +57,26 @@ func (c *ServicedCli) initBackup() {
 					Name: "force",
 					Usage: "attempt backup even if space check fails",
 				},
+				cli.BoolFlag{
+					Name:  "test-restore",
+					Usage: "with verify, read the snapshot metadata as it would be restored",
+				},
+				cli.StringFlag{
+					Name:  "compression",
+					Usage: "codec that compresses the backup file (gzip, zstd or none); defaults to SERVICED_BACKUP_COMPRESSION",
+				},
+				cli.IntFlag{
+					Name:  "keep",
+					Usage: "with schedule add, number of scheduled backups to keep; 0 keeps all of them",
+				},
+				cli.StringFlag{
+					Name:  "calendar",
+					Usage: "with schedule add, calendar whose time zone and exclusions apply to the schedule",
+				},
+				cli.StringFlag{
+					Name:  "alert-url",
+					Usage: "with schedule add, url that is posted to when a scheduled backup fails",
+				},
 			},
 		},
 		cli.Command{
@@ -50,6 +84,21 @@ func (c *ServicedCli) initBackup() {
 			Usage:       "Restore templates and services from a tgz file",
 			Description: "serviced restore FILEPATH",
 			Action:      c.cmdRestore,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "deployment-id",
+					Usage: "restore the applications alongside the existing applications under this deployment id",
+				},
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "rename the application restored with --deployment-id",
+				},
+				cli.StringSliceFlag{
+					Name:  "elastic",
+					Value: &cli.StringSlice{},
+					Usage: "only restore the elasticsearch indices of the backup for the cluster (serviced or logstash)",
+				},
+			},
 		},
 	)
 }
@@ -63,6 +112,26 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 		c.exit(1)
 		return
 	}
+	switch args[0] {
+	case "status":
+		c.cmdBackupStatus(ctx)
+		return
+	case "resume":
+		c.cmdBackupResume(ctx, args[1:])
+		return
+	case "discard":
+		c.cmdBackupDiscard(ctx, args[1:])
+		return
+	case "prune":
+		c.cmdBackupPrune(ctx)
+		return
+	case "verify":
+		c.cmdBackupVerify(ctx, args[1:])
+		return
+	case "schedule":
+		c.cmdBackupSchedule(ctx, args[1:])
+		return
+	}
 	if ctx.Bool("check") {
 		fmt.Printf("Checking for space...\n")
 		if backupSpace, err := c.driver.GetBackupEstimate(args[0], ctx.StringSlice("exclude")); err != nil {
@@ -81,7 +150,16 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 		return
 	}
 	// do backup
-	if path, err := c.driver.Backup(args[0], ctx.StringSlice("exclude"), ctx.Bool("force")); err != nil {
+	compression := ctx.String("compression")
+	if _, err := volume.ParseCompression(compression); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to backup: %s: %s\n", err, compression)
+		c.exit(1)
+		return
+	}
+	stop := c.watchBackupProgress()
+	path, err := c.driver.Backup(args[0], ctx.StringSlice("exclude"), ctx.Bool("force"), compression)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stdout, err)
 		c.exit(1)
 		return
@@ -94,6 +172,304 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 	}
 }
 
+// serviced backup status
+func (c *ServicedCli) cmdBackupStatus(ctx *cli.Context) {
+	progress, err := c.driver.GetBackupProgress()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if !progress.Running {
+		if progress.Error != "" {
+			fmt.Fprintf(os.Stderr, "%s of %s failed: %s\n", progress.Operation, progress.Filename, progress.Error)
+		} else {
+			fmt.Fprintln(os.Stderr, "no backup or restore is running")
+		}
+		return
+	}
+	fmt.Println(formatBackupProgress(*progress))
+}
+
+// watchBackupProgress draws the progress of the backup or restore that is
+// running on stderr, if it is a terminal, until the returned function is
+// called.
+func (c *ServicedCli) watchBackupProgress() func() {
+	if !terminal.IsTerminal(syscall.Stderr) {
+		return func() {}
+	}
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(backupProgressInterval)
+		defer ticker.Stop()
+		drawn := false
+		for {
+			select {
+			case <-ticker.C:
+				progress, err := c.driver.GetBackupProgress()
+				if err != nil || !progress.Running {
+					continue
+				}
+				// \x1b[K clears the rest of the line
+				fmt.Fprintf(os.Stderr, "\r%s\x1b[K", formatBackupProgress(*progress))
+				drawn = true
+			case <-done:
+				if drawn {
+					fmt.Fprintln(os.Stderr)
+				}
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// formatBackupProgress returns a line with a progress bar for a backup or
+// restore.  Without an estimated size, only the bytes processed are shown.
+func formatBackupProgress(progress dao.BackupProgress) string {
+	phase := progress.Phase
+	if phase == "" {
+		phase = "starting"
+	}
+	percent := progress.Percent()
+	if percent < 0 {
+		return fmt.Sprintf("%s %s: %s", progress.Operation, phase, humanize.Bytes(uint64(progress.Bytes)))
+	}
+	const width = 30
+	filled := int(percent * width / 100)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+	line := fmt.Sprintf("%s %s: [%s] %3.0f%% %s/%s", progress.Operation, phase, bar, percent,
+		humanize.Bytes(uint64(progress.Bytes)), humanize.Bytes(uint64(progress.TotalBytes)))
+	if progress.ETA > 0 {
+		line += fmt.Sprintf(" ETA %s", progress.ETA.Truncate(time.Second))
+	}
+	return line
+}
+
+// serviced backup resume [OPERATIONID]
+func (c *ServicedCli) cmdBackupResume(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		// list the operations that can be resumed
+		ops, err := c.driver.GetBackupOperations()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		} else if len(ops) == 0 {
+			fmt.Fprintln(os.Stderr, "no backups or restores to resume")
+			return
+		}
+		t := NewTable("ID,Operation,Filename,Completed,Error")
+		t.Padding = 6
+		for _, op := range ops {
+			t.AddRow(map[string]interface{}{
+				"ID":        op.ID,
+				"Operation": op.Operation,
+				"Filename":  op.Filename,
+				"Completed": len(op.Completed),
+				"Error":     op.Error,
+			})
+		}
+		t.Print()
+		return
+	}
+	if path, err := c.driver.ResumeBackupOperation(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else {
+		fmt.Println(path)
+	}
+}
+
+// serviced backup discard OPERATIONID
+func (c *ServicedCli) cmdBackupDiscard(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "backup")
+		c.exit(1)
+		return
+	}
+	if err := c.driver.DiscardBackupOperation(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced backup prune
+func (c *ServicedCli) cmdBackupPrune(ctx *cli.Context) {
+	layers, freed, err := c.driver.PruneBackupLayers()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("Removed %d image layers (%d bytes)\n", layers, freed)
+}
+
+// serviced backup verify FILEPATH [--test-restore]
+func (c *ServicedCli) cmdBackupVerify(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "backup")
+		c.exit(1)
+		return
+	}
+	result, err := c.driver.VerifyBackup(args[0], ctx.Bool("test-restore"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	if len(result.Sections) > 0 {
+		t := NewTable("Section,Entries,Size,Checksum,Verified")
+		t.Padding = 6
+		for _, section := range result.Sections {
+			checksum := section.Checksum
+			if len(checksum) > 12 {
+				checksum = checksum[:12]
+			}
+			t.AddRow(map[string]interface{}{
+				"Section":  section.Name,
+				"Entries":  section.Entries,
+				"Size":     section.Size,
+				"Checksum": checksum,
+				"Verified": section.Verified,
+			})
+		}
+		t.Print()
+	}
+	if result.Compression != "" {
+		fmt.Printf("Compressed with %s\n", result.Compression)
+	}
+	fmt.Printf("Checked %d image layers\n", result.Layers)
+	if result.MetadataRestored {
+		fmt.Println("Restored the snapshot metadata to a temporary directory")
+	}
+	if len(result.Errors) > 0 {
+		for _, msg := range result.Errors {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+		c.exit(1)
+		return
+	}
+	fmt.Printf("%s is intact\n", args[0])
+}
+
+// serviced backup schedule [add CRON [DIRPATH] | remove SCHEDULEID]
+func (c *ServicedCli) cmdBackupSchedule(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		c.cmdBackupScheduleList()
+		return
+	}
+	switch args[0] {
+	case "add":
+		c.cmdBackupScheduleAdd(ctx, args[1:])
+	case "remove", "rm":
+		c.cmdBackupScheduleRemove(ctx, args[1:])
+	default:
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "backup")
+		c.exit(1)
+	}
+}
+
+// serviced backup schedule
+func (c *ServicedCli) cmdBackupScheduleList() {
+	schedules, err := c.driver.GetBackupSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(schedules) == 0 {
+		fmt.Fprintln(os.Stderr, "no backups are scheduled")
+		return
+	}
+	t := NewTable("ID,Schedule,Calendar,Keep,Backups,LastRun,Status")
+	t.Padding = 6
+	for _, s := range schedules {
+		lastRun, status := "never", "ok"
+		if !s.LastRun.IsZero() {
+			lastRun = s.LastRun.Format(time.RFC3339)
+		}
+		if s.LastError != "" {
+			status = s.LastError
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":       s.ID,
+			"Schedule": s.Schedule,
+			"Calendar": s.Calendar,
+			"Keep":     s.Keep,
+			"Backups":  len(s.Backups),
+			"LastRun":  lastRun,
+			"Status":   status,
+		})
+	}
+	t.Print()
+}
+
+// serviced backup schedule add CRON [DIRPATH] [--keep N] [--calendar CALENDARID] [--alert-url URL]
+func (c *ServicedCli) cmdBackupScheduleAdd(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "backup")
+		c.exit(1)
+		return
+	}
+	if _, err := calendar.ParseSchedule(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	compression := ctx.String("compression")
+	if _, err := volume.ParseCompression(compression); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to schedule backup: %s: %s\n", err, compression)
+		c.exit(1)
+		return
+	}
+	schedule := backupschedule.BackupSchedule{
+		Schedule:    args[0],
+		Calendar:    ctx.String("calendar"),
+		Keep:        ctx.Int("keep"),
+		Excludes:    ctx.StringSlice("exclude"),
+		Compression: compression,
+		AlertURL:    ctx.String("alert-url"),
+	}
+	if len(args) > 1 {
+		schedule.Dirpath = args[1]
+	}
+	id, err := c.driver.AddBackupSchedule(schedule)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(id)
+}
+
+// serviced backup schedule remove SCHEDULEID
+func (c *ServicedCli) cmdBackupScheduleRemove(ctx *cli.Context, args []string) {
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "backup")
+		c.exit(1)
+		return
+	}
+	if err := c.driver.RemoveBackupSchedule(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(args[0])
+}
+
 // serviced restore FILEPATH
 func (c *ServicedCli) cmdRestore(ctx *cli.Context) {
 	args := ctx.Args()
@@ -103,7 +479,39 @@ func (c *ServicedCli) cmdRestore(ctx *cli.Context) {
 		return
 	}
 
-	err := c.driver.Restore(args[0])
+	deploymentID, name := ctx.String("deployment-id"), ctx.String("name")
+	if deploymentID == "" && name != "" {
+		fmt.Fprintln(os.Stderr, "--name requires --deployment-id")
+		c.exit(1)
+		return
+	}
+
+	elastic := ctx.StringSlice("elastic")
+	for _, cluster := range elastic {
+		if cluster != "serviced" && cluster != "logstash" {
+			fmt.Fprintf(os.Stderr, "unknown elasticsearch cluster %s, expected serviced or logstash\n", cluster)
+			c.exit(1)
+			return
+		}
+	}
+	if len(elastic) > 0 && deploymentID != "" {
+		fmt.Fprintln(os.Stderr, "--elastic cannot be used with --deployment-id")
+		c.exit(1)
+		return
+	}
+
+	var err error
+	if len(elastic) > 0 {
+		err = c.driver.RestoreElastic(args[0], elastic)
+	} else {
+		stop := c.watchBackupProgress()
+		if deploymentID != "" {
+			err = c.driver.RestoreAs(args[0], deploymentID, name)
+		} else {
+			err = c.driver.Restore(args[0])
+		}
+		stop()
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	}
@@ -20,8 +20,12 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backupschedule"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/utils"
 )
@@ -30,6 +34,7 @@ const (
 	PathNotFound = "PathNotFound"
 	NilPath      = "NilPath"
 	TooSmallPath = "TooSmallPath"
+	NoOperations = "NoOperations"
 )
 
 var DefaultBackupAPITest = BackupAPITest{}
@@ -54,7 +59,7 @@ func InitBackupAPITestNoExit(args ...string) {
 	c.Run(args)
 }
 
-func (t BackupAPITest) Backup(dirpath string, excludes []string, force bool) (string, error) {
+func (t BackupAPITest) Backup(dirpath string, excludes []string, force bool, compression string) (string, error) {
 	switch dirpath {
 	case PathNotFound:
 		return "", ErrBackupFailed
@@ -67,6 +72,9 @@ func (t BackupAPITest) Backup(dirpath string, excludes []string, force bool) (st
 			return "", ErrBackupPathTooSmall
 		}
 	default:
+		if compression == "zstd" {
+			return fmt.Sprintf("%s.tar.zst", path.Base(dirpath)), nil
+		}
 		return fmt.Sprintf("%s.tgz", path.Base(dirpath)), nil
 	}
 }
@@ -80,6 +88,123 @@ func (t BackupAPITest) Restore(path string) error {
 	}
 }
 
+func (t BackupAPITest) RestoreAs(path, deploymentID, name string) error {
+	switch path {
+	case PathNotFound:
+		return ErrRestoreFailed
+	default:
+		fmt.Printf("Restored %s as %s %s\n", path, deploymentID, name)
+		return nil
+	}
+}
+
+func (t BackupAPITest) RestoreElastic(path string, clusters []string) error {
+	switch path {
+	case PathNotFound:
+		return ErrRestoreFailed
+	default:
+		fmt.Printf("Restored %s elasticsearch %s\n", path, strings.Join(clusters, ","))
+		return nil
+	}
+}
+
+func (t BackupAPITest) GetBackupOperations() ([]dao.BackupOperation, error) {
+	return []dao.BackupOperation{
+		{
+			ID:        "backup-2017-01-02-150405",
+			Operation: "backup",
+			Filename:  "/backups/backup-2017-01-02-150405.tgz",
+			Completed: []string{"METADATA.json", "SNAPSHOTS/tenant/label"},
+			Error:     "no space left on device",
+		},
+	}, nil
+}
+
+func (t BackupAPITest) ResumeBackupOperation(id string) (string, error) {
+	if id == PathNotFound {
+		return "", ErrBackupFailed
+	}
+	return fmt.Sprintf("/backups/%s.tgz", id), nil
+}
+
+func (t BackupAPITest) DiscardBackupOperation(id string) error {
+	if id == PathNotFound {
+		return ErrBackupFailed
+	}
+	return nil
+}
+
+func (t BackupAPITest) PruneBackupLayers() (int, int64, error) {
+	return 3, 1048576, nil
+}
+
+func (t BackupAPITest) VerifyBackup(path string, testRestore bool) (*dfs.BackupVerification, error) {
+	if path == PathNotFound {
+		return nil, ErrBackupFailed
+	}
+	result := &dfs.BackupVerification{
+		BackupVersion:    1,
+		Layers:           2,
+		MetadataRestored: testRestore,
+		Sections: []dfs.BackupSection{
+			{Name: "SNAPSHOTS/tenant/label", Entries: 12, Size: 4096, Checksum: "0123456789abcdef", Verified: true},
+			{Name: "IMAGES.dkr", Entries: 5, Size: 1024, Checksum: "fedcba9876543210", Verified: true},
+		},
+	}
+	if path == TooSmallPath {
+		result.Sections[1].Verified = false
+		result.Errors = []string{"checksum of IMAGES.dkr does not match"}
+	}
+	return result, nil
+}
+
+func (t BackupAPITest) GetBackupProgress() (*dao.BackupProgress, error) {
+	return &dao.BackupProgress{
+		ID:         "backup-2017-01-02-150405",
+		Operation:  "backup",
+		Filename:   "/backups/backup-2017-01-02-150405.tgz",
+		Phase:      "volumes",
+		Bytes:      250000000,
+		TotalBytes: 1000000000,
+		ETA:        90 * time.Minute,
+		Running:    true,
+	}, nil
+}
+
+func (t BackupAPITest) GetBackupSchedules() ([]backupschedule.BackupSchedule, error) {
+	return []backupschedule.BackupSchedule{
+		{
+			ID:       "nightly",
+			Schedule: "0 2 * * *",
+			Calendar: "default",
+			Keep:     7,
+			Backups:  []string{"/backups/backup-2017-01-01-020000.tgz", "/backups/backup-2017-01-02-020000.tgz"},
+			LastRun:  time.Date(2017, 1, 2, 2, 0, 0, 0, time.UTC),
+		}, {
+			ID:        "weekly",
+			Schedule:  "0 4 * * 0",
+			LastRun:   time.Date(2017, 1, 1, 4, 0, 0, 0, time.UTC),
+			LastError: "no space left on device",
+		},
+	}, nil
+}
+
+func (t BackupAPITest) AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error) {
+	if schedule.Dirpath == PathNotFound {
+		return "", ErrBackupFailed
+	}
+	fmt.Printf("%s keep=%d calendar=%s dirpath=%s excludes=%s alert=%s\n", schedule.Schedule, schedule.Keep,
+		schedule.Calendar, schedule.Dirpath, strings.Join(schedule.Excludes, ","), schedule.AlertURL)
+	return "nightly", nil
+}
+
+func (t BackupAPITest) RemoveBackupSchedule(id string) error {
+	if id == PathNotFound {
+		return ErrBackupFailed
+	}
+	return nil
+}
+
 func (t BackupAPITest) GetBackupEstimate(path string, _ []string) (*dao.BackupEstimate, error) {
 	switch path{
 	case TooSmallPath:
@@ -129,6 +254,20 @@ func ExampleServicedCli_cmdBackup() {
 	// dir.tgz
 }
 
+func ExampleServicedCli_cmdBackup_compression() {
+	InitBackupAPITest("serviced", "backup", "--compression", "zstd", "path/to/dir")
+
+	// Output:
+	// dir.tar.zst
+}
+
+func ExampleServicedCli_cmdBackup_badCompression() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "backup", "--compression", "bzip2", "path/to/dir") })
+
+	// Output:
+	// Unable to backup: unknown compression codec: bzip2
+}
+
 func ExampleServicedCLI_CmdBackup_usage() {
 	InitBackupAPITestNoExit("serviced", "backup")
 
@@ -142,12 +281,17 @@ func ExampleServicedCLI_CmdBackup_usage() {
 	//    command backup [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced backup DIRPATH
+	//    serviced backup DIRPATH | status | resume [OPERATIONID] | discard OPERATIONID | prune | verify FILEPATH | schedule [add CRON [DIRPATH] | remove SCHEDULEID]
 	//
 	// OPTIONS:
 	//    --exclude '--exclude option --exclude option'	Subdirectory of the tenant volume to exclude from backup
 	//    --check						check space, but do not do backup
 	//    --force						attempt backup even if space check fails
+	//    --test-restore					with verify, read the snapshot metadata as it would be restored
+	//    --compression 					codec that compresses the backup file (gzip, zstd or none); defaults to SERVICED_BACKUP_COMPRESSION
+	//    --keep '0'						with schedule add, number of scheduled backups to keep; 0 keeps all of them
+	//    --calendar 						with schedule add, calendar whose time zone and exclusions apply to the schedule
+	//    --alert-url 						with schedule add, url that is posted to when a scheduled backup fails
 }
 
 func ExampleServicedCLI_CmdBackup_noforce() {
@@ -177,6 +321,113 @@ func ExampleServicedCLI_CmdBackup_check() {
 	// Check only - not taking backup
 }
 
+func ExampleServicedCLI_CmdBackup_resumeList() {
+	InitBackupAPITest("serviced", "backup", "resume")
+
+	// Output:
+	// ID                            Operation      Filename                                   Completed      Error
+	// backup-2017-01-02-150405      backup         /backups/backup-2017-01-02-150405.tgz      2              no space left on device
+}
+
+func ExampleServicedCLI_CmdBackup_resume() {
+	InitBackupAPITest("serviced", "backup", "resume", "backup-2017-01-02-150405")
+
+	// Output:
+	// /backups/backup-2017-01-02-150405.tgz
+}
+
+func ExampleServicedCLI_CmdBackup_resumeFails() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "backup", "resume", PathNotFound) })
+
+	// Output:
+	// backup failed
+}
+
+func ExampleServicedCLI_CmdBackup_discard() {
+	InitBackupAPITest("serviced", "backup", "discard", "backup-2017-01-02-150405")
+
+	// Output:
+	// backup-2017-01-02-150405
+}
+
+func ExampleServicedCLI_CmdBackup_prune() {
+	InitBackupAPITest("serviced", "backup", "prune")
+
+	// Output:
+	// Removed 3 image layers (1048576 bytes)
+}
+
+func ExampleServicedCLI_CmdBackup_status() {
+	InitBackupAPITest("serviced", "backup", "status")
+
+	// Output:
+	// backup volumes: [=======                       ]  25% 250 MB/1.0 GB ETA 1h30m0s
+}
+
+func ExampleServicedCLI_CmdBackup_scheduleList() {
+	InitBackupAPITest("serviced", "backup", "schedule")
+
+	// Output:
+	// ID           Schedule       Calendar      Keep      Backups      LastRun                   Status
+	// nightly      0 2 * * *      default       7         2            2017-01-02T02:00:00Z      ok
+	// weekly       0 4 * * 0                    0         0            2017-01-01T04:00:00Z      no space left on device
+}
+
+func ExampleServicedCLI_CmdBackup_scheduleAdd() {
+	InitBackupAPITest("serviced", "backup", "schedule", "add", "0 2 * * *", "/backups/nightly", "--keep", "7",
+		"--calendar", "default", "--exclude", "tmp", "--alert-url", "http://alerts.example.com/backup")
+
+	// Output:
+	// 0 2 * * * keep=7 calendar=default dirpath=/backups/nightly excludes=tmp alert=http://alerts.example.com/backup
+	// nightly
+}
+
+func ExampleServicedCLI_CmdBackup_scheduleAddInvalid() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "backup", "schedule", "add", "0 25 * * *") })
+
+	// Output:
+	// schedule "0 25 * * *": hour out of range (0-23): 25
+}
+
+func ExampleServicedCLI_CmdBackup_scheduleRemove() {
+	InitBackupAPITest("serviced", "backup", "schedule", "remove", "nightly")
+
+	// Output:
+	// nightly
+}
+
+func Example_formatBackupProgress() {
+	fmt.Println(formatBackupProgress(dao.BackupProgress{Operation: "restore", Bytes: 1500}))
+	fmt.Println(formatBackupProgress(dao.BackupProgress{Operation: "restore", Phase: "images", Bytes: 2000, TotalBytes: 1000}))
+
+	// Output:
+	// restore starting: 1.5 kB
+	// restore images: [==============================] 100% 2.0 kB/1.0 kB
+}
+
+func ExampleServicedCLI_CmdBackup_verify() {
+	InitBackupAPITest("serviced", "backup", "verify", "backup.tgz", "--test-restore")
+
+	// Output:
+	// Section                     Entries      Size      Checksum          Verified
+	// SNAPSHOTS/tenant/label      12           4096      0123456789ab      true
+	// IMAGES.dkr                  5            1024      fedcba987654      true
+	// Checked 2 image layers
+	// Restored the snapshot metadata to a temporary directory
+	// backup.tgz is intact
+}
+
+func ExampleServicedCLI_CmdBackup_verifyCorrupt() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "backup", "verify", TooSmallPath) })
+
+	// Output:
+	// Section                     Entries      Size      Checksum          Verified
+	// SNAPSHOTS/tenant/label      12           4096      0123456789ab      true
+	// IMAGES.dkr                  5            1024      fedcba987654      false
+	// Checked 2 image layers
+	// checksum of IMAGES.dkr does not match
+}
+
 func ExampleServicedCli_cmdRestore() {
 	InitBackupAPITest("serviced", "restore", PathNotFound)
 	InitBackupAPITest("serviced", "restore", "path/to/file")
@@ -200,5 +451,35 @@ func ExampleServicedCLI_CmdRestore_usage() {
 	//    serviced restore FILEPATH
 	//
 	// OPTIONS:
+	//    --deployment-id 					restore the applications alongside the existing applications under this deployment id
+	//    --name 						rename the application restored with --deployment-id
+	//    --elastic '--elastic option --elastic option'	only restore the elasticsearch indices of the backup for the cluster (serviced or logstash)
 }
 
+func ExampleServicedCLI_CmdRestore_as() {
+	InitBackupAPITest("serviced", "restore", "--deployment-id", "copy", "--name", "app-copy", "path/to/file")
+
+	// Output:
+	// Restored path/to/file as copy app-copy
+}
+
+func ExampleServicedCLI_CmdRestore_nameWithoutDeploymentID() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "restore", "--name", "app-copy", "path/to/file") })
+
+	// Output:
+	// --name requires --deployment-id
+}
+
+func ExampleServicedCLI_CmdRestore_elastic() {
+	InitBackupAPITest("serviced", "restore", "--elastic", "serviced", "--elastic", "logstash", "path/to/file")
+
+	// Output:
+	// Restored path/to/file elasticsearch serviced,logstash
+}
+
+func ExampleServicedCLI_CmdRestore_elasticUnknownCluster() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "restore", "--elastic", "opentsdb", "path/to/file") })
+
+	// Output:
+	// unknown elasticsearch cluster opentsdb, expected serviced or logstash
+}
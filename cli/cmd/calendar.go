@@ -0,0 +1,463 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+// Initializer for serviced calendar subcommands
+func (c *ServicedCli) initCalendar() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "calendar",
+		Usage:       "Administers calendars for scheduled operations",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists all calendars",
+				Description:  "serviced calendar list [CALENDARID]",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,TimeZone,Exclusions,Windows,Description",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "add",
+				Usage:        "Adds a new calendar",
+				Description:  "serviced calendar add CALENDARID",
+				BashComplete: nil,
+				Action:       c.cmdCalendarAdd,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "timezone",
+						Value: "UTC",
+						Usage: "IANA time zone of the calendar (e.g. America/Chicago)",
+					},
+					cli.StringFlag{
+						Name:  "description",
+						Value: "",
+						Usage: "Description of the calendar",
+					},
+				},
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes an existing calendar",
+				Description:  "serviced calendar remove CALENDARID ...",
+				BashComplete: c.printCalendarsAll,
+				Action:       c.cmdCalendarRemove,
+			}, {
+				Name:         "set-timezone",
+				Usage:        "Sets the time zone of a calendar",
+				Description:  "serviced calendar set-timezone CALENDARID TIMEZONE",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarSetTimeZone,
+			}, {
+				Name:         "exclude",
+				Usage:        "Excludes dates (YYYY-MM-DD) from scheduled operations",
+				Description:  "serviced calendar exclude CALENDARID DATE ...",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarExclude,
+			}, {
+				Name:         "include",
+				Usage:        "Removes excluded dates (YYYY-MM-DD) from a calendar",
+				Description:  "serviced calendar include CALENDARID DATE ...",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarInclude,
+			}, {
+				Name:         "add-window",
+				Usage:        "Adds a maintenance window or deployment freeze (times are YYYY-MM-DD HH:MM in the calendar's time zone)",
+				Description:  "serviced calendar add-window CALENDARID NAME (maintenance|freeze) START END",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarAddWindow,
+			}, {
+				Name:         "remove-window",
+				Usage:        "Removes a maintenance window or deployment freeze",
+				Description:  "serviced calendar remove-window CALENDARID NAME",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarRemoveWindow,
+			}, {
+				Name:         "next",
+				Usage:        "Prints the next times a cron-style schedule runs on a calendar",
+				Description:  "serviced calendar next CALENDARID SCHEDULE",
+				BashComplete: c.printCalendarsFirst,
+				Action:       c.cmdCalendarNext,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "count, n",
+						Value: 5,
+						Usage: "Number of run times to print",
+					},
+					cli.StringFlag{
+						Name:  "after",
+						Value: "",
+						Usage: "Time (YYYY-MM-DD HH:MM in the calendar's time zone) to start from; defaults to now",
+					},
+				},
+			},
+		},
+	})
+}
+
+// Returns a list of all available calendar IDs
+func (c *ServicedCli) calendars() (data []string) {
+	calendars, err := c.driver.GetCalendars()
+	if err != nil || len(calendars) == 0 {
+		return
+	}
+
+	data = make([]string, len(calendars))
+	for i, cal := range calendars {
+		data[i] = cal.ID
+	}
+
+	return
+}
+
+// Bash-completion command that prints the list of available calendars as the
+// first argument
+func (c *ServicedCli) printCalendarsFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	fmt.Println(strings.Join(c.calendars(), "\n"))
+}
+
+// Bash-completion command that prints the list of available calendars as all
+// arguments
+func (c *ServicedCli) printCalendarsAll(ctx *cli.Context) {
+	args := ctx.Args()
+	for _, id := range c.calendars() {
+		found := false
+		for _, a := range args {
+			if id == a {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fmt.Println(id)
+		}
+	}
+}
+
+// updateCalendar loads a calendar, applies the update and saves it
+func (c *ServicedCli) updateCalendar(id string, update func(*calendar.Calendar) error) error {
+	cal, err := c.driver.GetCalendar(id)
+	if err != nil {
+		return err
+	} else if cal == nil {
+		return fmt.Errorf("calendar not found")
+	}
+	if err := update(cal); err != nil {
+		return err
+	}
+	return c.driver.UpdateCalendar(*cal)
+}
+
+// serviced calendar list [CALENDARID]
+func (c *ServicedCli) cmdCalendarList(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		if cal, err := c.driver.GetCalendar(ctx.Args()[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		} else if cal == nil {
+			fmt.Fprintln(os.Stderr, "calendar not found")
+		} else if jsonCalendar, err := json.MarshalIndent(cal, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal calendar: %s", err)
+		} else {
+			fmt.Println(string(jsonCalendar))
+		}
+		return
+	}
+
+	calendars, err := c.driver.GetCalendars()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(calendars) == 0 {
+		fmt.Fprintln(os.Stderr, "no calendars found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonCalendars, err := json.MarshalIndent(calendars, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal calendar list: %s", err)
+		} else {
+			fmt.Println(string(jsonCalendars))
+		}
+		return
+	}
+
+	t := NewTable(ctx.String("show-fields"))
+	t.Padding = 6
+	for _, cal := range calendars {
+		tz := cal.TimeZone
+		if tz == "" {
+			tz = "UTC"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":          cal.ID,
+			"TimeZone":    tz,
+			"Exclusions":  len(cal.Exclusions),
+			"Windows":     len(cal.Windows),
+			"Description": cal.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced calendar add [--timezone TZ] [--description DESC] CALENDARID
+func (c *ServicedCli) cmdCalendarAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	cal := calendar.New(args[0])
+	cal.TimeZone = ctx.String("timezone")
+	cal.Description = ctx.String("description")
+	if _, err := cal.Location(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid time zone %s: %s\n", cal.TimeZone, err)
+		return
+	}
+	if err := c.driver.AddCalendar(*cal); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(cal.ID)
+}
+
+// serviced calendar remove CALENDARID ...
+func (c *ServicedCli) cmdCalendarRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, id := range args {
+		if err := c.driver.RemoveCalendar(id); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+		} else {
+			fmt.Println(id)
+		}
+	}
+}
+
+// serviced calendar set-timezone CALENDARID TIMEZONE
+func (c *ServicedCli) cmdCalendarSetTimeZone(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-timezone")
+		return
+	}
+
+	if _, err := time.LoadLocation(args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid time zone %s: %s\n", args[1], err)
+		return
+	}
+	if err := c.updateCalendar(args[0], func(cal *calendar.Calendar) error {
+		cal.TimeZone = args[1]
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced calendar exclude CALENDARID DATE ...
+func (c *ServicedCli) cmdCalendarExclude(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "exclude")
+		return
+	}
+
+	for _, date := range args[1:] {
+		if _, err := time.Parse(calendar.DateFormat, date); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid date %s: expected YYYY-MM-DD\n", date)
+			return
+		}
+	}
+	if err := c.updateCalendar(args[0], func(cal *calendar.Calendar) error {
+	dates:
+		for _, date := range args[1:] {
+			for _, exclusion := range cal.Exclusions {
+				if exclusion == date {
+					continue dates
+				}
+			}
+			cal.Exclusions = append(cal.Exclusions, date)
+		}
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced calendar include CALENDARID DATE ...
+func (c *ServicedCli) cmdCalendarInclude(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "include")
+		return
+	}
+
+	if err := c.updateCalendar(args[0], func(cal *calendar.Calendar) error {
+		exclusions := []string{}
+	exclusions:
+		for _, exclusion := range cal.Exclusions {
+			for _, date := range args[1:] {
+				if exclusion == date {
+					continue exclusions
+				}
+			}
+			exclusions = append(exclusions, exclusion)
+		}
+		cal.Exclusions = exclusions
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced calendar add-window CALENDARID NAME KIND START END
+func (c *ServicedCli) cmdCalendarAddWindow(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 5 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add-window")
+		return
+	}
+
+	name, kind := args[1], args[2]
+	if kind != calendar.MaintenanceWindow && kind != calendar.FreezeWindow {
+		fmt.Fprintf(os.Stderr, "invalid window kind %s: expected %s or %s\n", kind, calendar.MaintenanceWindow, calendar.FreezeWindow)
+		return
+	}
+	if err := c.updateCalendar(args[0], func(cal *calendar.Calendar) error {
+		for _, w := range cal.Windows {
+			if w.Name == name {
+				return fmt.Errorf("window %s already exists", name)
+			}
+		}
+		start, err := cal.ParseTime(args[3])
+		if err != nil {
+			return fmt.Errorf("invalid start time %s: expected YYYY-MM-DD HH:MM", args[3])
+		}
+		end, err := cal.ParseTime(args[4])
+		if err != nil {
+			return fmt.Errorf("invalid end time %s: expected YYYY-MM-DD HH:MM", args[4])
+		}
+		cal.Windows = append(cal.Windows, calendar.Window{Name: name, Kind: kind, Start: start, End: end})
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced calendar remove-window CALENDARID NAME
+func (c *ServicedCli) cmdCalendarRemoveWindow(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove-window")
+		return
+	}
+
+	if err := c.updateCalendar(args[0], func(cal *calendar.Calendar) error {
+		for i, w := range cal.Windows {
+			if w.Name == args[1] {
+				cal.Windows = append(cal.Windows[:i], cal.Windows[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("window %s not found", args[1])
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced calendar next [--count N] [--after TIME] CALENDARID SCHEDULE
+func (c *ServicedCli) cmdCalendarNext(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "next")
+		return
+	}
+
+	schedule, err := calendar.ParseSchedule(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	cal, err := c.driver.GetCalendar(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if cal == nil {
+		fmt.Fprintln(os.Stderr, "calendar not found")
+		return
+	}
+
+	t := time.Now()
+	if after := ctx.String("after"); after != "" {
+		if t, err = cal.ParseTime(after); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid time %s: expected YYYY-MM-DD HH:MM\n", after)
+			return
+		}
+	}
+	for i := 0; i < ctx.Int("count"); i++ {
+		if t, err = cal.Next(schedule, t); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		} else if t.IsZero() {
+			break
+		}
+		var notes []string
+		if cal.InMaintenance(t) {
+			notes = append(notes, "maintenance")
+		}
+		if cal.IsFrozen(t) {
+			notes = append(notes, "freeze")
+		}
+		if len(notes) > 0 {
+			fmt.Printf("%s (%s)\n", t.Format(calendar.TimeFormat+" MST"), strings.Join(notes, ", "))
+		} else {
+			fmt.Println(t.Format(calendar.TimeFormat + " MST"))
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+var ErrNoCalendarFound = errors.New("no calendar found")
+
+type CalendarAPITest struct {
+	api.API
+	calendars map[string]*calendar.Calendar
+}
+
+func NewCalendarAPITest() CalendarAPITest {
+	return CalendarAPITest{calendars: map[string]*calendar.Calendar{
+		"default": {ID: "default", Description: "US holidays", TimeZone: "America/New_York", Exclusions: []string{"2017-12-25"}},
+	}}
+}
+
+func (t CalendarAPITest) GetCalendars() ([]calendar.Calendar, error) {
+	cals := []calendar.Calendar{}
+	for _, cal := range t.calendars {
+		cals = append(cals, *cal)
+	}
+	return cals, nil
+}
+
+func (t CalendarAPITest) GetCalendar(id string) (*calendar.Calendar, error) {
+	if cal, ok := t.calendars[id]; ok {
+		c := *cal
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (t CalendarAPITest) AddCalendar(cal calendar.Calendar) error {
+	if _, ok := t.calendars[cal.ID]; ok {
+		return fmt.Errorf("calendar %s exists", cal.ID)
+	}
+	t.calendars[cal.ID] = &cal
+	return nil
+}
+
+func (t CalendarAPITest) UpdateCalendar(cal calendar.Calendar) error {
+	if _, ok := t.calendars[cal.ID]; !ok {
+		return ErrNoCalendarFound
+	}
+	t.calendars[cal.ID] = &cal
+	return nil
+}
+
+func (t CalendarAPITest) RemoveCalendar(id string) error {
+	if _, ok := t.calendars[id]; !ok {
+		return ErrNoCalendarFound
+	}
+	delete(t.calendars, id)
+	return nil
+}
+
+func ExampleServicedCLI_CmdCalendarList() {
+	RunCmd(NewCalendarAPITest(), "serviced", "calendar", "list")
+
+	// Output:
+	// ID           TimeZone              Exclusions      Windows      Description
+	// default      America/New_York      1               0            US holidays
+}
+
+func ExampleServicedCLI_CmdCalendarAdd() {
+	test := NewCalendarAPITest()
+	RunCmd(test, "serviced", "calendar", "add", "--timezone", "Europe/Paris", "emea")
+	pipeStderr(func() { RunCmd(test, "serviced", "calendar", "add", "--timezone", "Mars/Olympus", "mars") })
+	pipeStderr(func() { RunCmd(test, "serviced", "calendar", "add", "default") })
+
+	// Output:
+	// emea
+	// invalid time zone Mars/Olympus: unknown time zone Mars/Olympus
+	// calendar default exists
+}
+
+func ExampleServicedCLI_CmdCalendarRemove() {
+	test := NewCalendarAPITest()
+	RunCmd(test, "serviced", "calendar", "remove", "default")
+	pipeStderr(func() { RunCmd(test, "serviced", "calendar", "remove", "default") })
+
+	// Output:
+	// default
+	// default: no calendar found
+}
+
+func ExampleServicedCLI_CmdCalendarNext() {
+	test := NewCalendarAPITest()
+	RunCmd(test, "serviced", "calendar", "add-window", "default", "patching", "maintenance", "2017-12-27 00:00", "2017-12-27 06:00")
+	RunCmd(test, "serviced", "calendar", "next", "-n", "3", "--after", "2017-12-24 12:00", "default", "0 2 * * *")
+
+	// Output:
+	// 2017-12-26 02:00 EST
+	// 2017-12-27 02:00 EST (maintenance)
+	// 2017-12-28 02:00 EST
+}
+
+func ExampleServicedCLI_CmdCalendarNext_invalid() {
+	pipeStderr(func() { RunCmd(NewCalendarAPITest(), "serviced", "calendar", "next", "default", "0 2 * *") })
+	pipeStderr(func() { RunCmd(NewCalendarAPITest(), "serviced", "calendar", "next", "missing", "0 2 * * *") })
+
+	// Output:
+	// schedule "0 2 * *" must have 5 fields
+	// calendar not found
+}
+
+func TestServicedCLI_CmdCalendarExclude(t *testing.T) {
+	test := NewCalendarAPITest()
+	RunCmd(test, "serviced", "calendar", "exclude", "default", "2017-12-25", "2018-01-01")
+	if excl := test.calendars["default"].Exclusions; len(excl) != 2 || excl[1] != "2018-01-01" {
+		t.Fatalf("Unexpected exclusions: %v", excl)
+	}
+
+	RunCmd(test, "serviced", "calendar", "exclude", "default", "01/02/2018")
+	if excl := test.calendars["default"].Exclusions; len(excl) != 2 {
+		t.Fatalf("Unexpected exclusions: %v", excl)
+	}
+
+	RunCmd(test, "serviced", "calendar", "include", "default", "2017-12-25")
+	if excl := test.calendars["default"].Exclusions; len(excl) != 1 || excl[0] != "2018-01-01" {
+		t.Fatalf("Unexpected exclusions: %v", excl)
+	}
+}
+
+func TestServicedCLI_CmdCalendarWindows(t *testing.T) {
+	test := NewCalendarAPITest()
+	RunCmd(test, "serviced", "calendar", "add-window", "default", "holidays", "freeze", "2017-12-20 00:00", "2018-01-02 00:00")
+	windows := test.calendars["default"].Windows
+	if len(windows) != 1 || windows[0].Kind != calendar.FreezeWindow {
+		t.Fatalf("Unexpected windows: %+v", windows)
+	}
+	if start := windows[0].Start.UTC().Format(calendar.TimeFormat); start != "2017-12-20 05:00" {
+		t.Fatalf("Expected the window to start in the calendar's time zone, got %s", start)
+	}
+
+	RunCmd(test, "serviced", "calendar", "add-window", "default", "outage", "outage", "2017-12-20 00:00", "2018-01-02 00:00")
+	RunCmd(test, "serviced", "calendar", "add-window", "default", "holidays", "freeze", "2018-12-20 00:00", "2019-01-02 00:00")
+	if windows := test.calendars["default"].Windows; len(windows) != 1 {
+		t.Fatalf("Unexpected windows: %+v", windows)
+	}
+
+	RunCmd(test, "serviced", "calendar", "remove-window", "default", "holidays")
+	if windows := test.calendars["default"].Windows; len(windows) != 0 {
+		t.Fatalf("Unexpected windows: %+v", windows)
+	}
+}
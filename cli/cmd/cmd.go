@@ -23,9 +23,11 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/relay"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
@@ -67,7 +69,7 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 	c.app.Flags = []cli.Flag{
 		cli.StringFlag{"docker-registry", defaultOps.DockerRegistry, "local docker registry to use"},
 		cli.StringSliceFlag{"static-ip", convertToStringSlice(defaultOps.StaticIPs), "static ips for this agent to advertise"},
-		cli.StringFlag{"endpoint", defaultOps.Endpoint, fmt.Sprintf("endpoint for remote serviced (example.com:%d)", api.DefaultRPCPort)},
+		cli.StringFlag{"endpoint", defaultOps.Endpoint, fmt.Sprintf("endpoint for remote serviced (example.com:%d); separate multiple masters with commas", api.DefaultRPCPort)},
 		cli.StringFlag{"outbound", defaultOps.OutboundIP, "outbound ip address"},
 		cli.StringFlag{"uiport", defaultOps.UIPort, "port for ui"},
 		cli.StringFlag{"nfs-client", defaultOps.NFSClient, "establish agent as an nfs client sharing data, 0 to disable"},
@@ -87,6 +89,8 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"keyfile", defaultOps.KeyPEMFile, "path to private key file (defaults to compiled in private key)"},
 		cli.StringFlag{"certfile", defaultOps.CertPEMFile, "path to public certificate file (defaults to compiled in public cert)"},
 		cli.StringSliceFlag{"zk", convertToStringSlice(defaultOps.Zookeepers), "Specify a zookeeper instance to connect to (e.g. -zk localhost:2181)"},
+		cli.StringFlag{"coordinator", defaultOps.CoordinatorDriver, "coordinator backend to use (zookeeper or etcd)"},
+		cli.StringSliceFlag{"etcd", convertToStringSlice(defaultOps.EtcdEndpoints), "Specify an etcd endpoint to connect to when -coordinator is etcd (e.g. -etcd localhost:2379)"},
 		cli.StringSliceFlag{"mount", convertToStringSlice(defaultOps.Mount), "bind mount: DOCKER_IMAGE,HOST_PATH[,CONTAINER_PATH]"},
 		cli.StringFlag{"fstype", string(defaultOps.FSType), "driver for underlying file system"},
 		cli.StringSliceFlag{"alias", convertToStringSlice(defaultOps.HostAliases), "list of aliases for this host, e.g., localhost"},
@@ -103,6 +107,7 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"isvcs-zk-username", defaultOps.IsvcsZKUsername, "isvcs zookeeper username"},
 		cli.StringFlag{"isvcs-zk-passwd", defaultOps.IsvcsZKPasswd, "isvcs zookeeper password"},
 		cli.StringSliceFlag{"isvcs-env", convertToStringSlice(defaultOps.IsvcsENV), "internal-service environment variable: ISVC:KEY=VAL"},
+		cli.StringSliceFlag{"isvcs-restart-policy", convertToStringSlice(defaultOps.IsvcsRestartPolicies), "internal-service restart policy: ISVC:max-restarts-per-hour=N,backoff=DURATION,max-backoff=DURATION"},
 		cli.StringSliceFlag{"tls-ciphers", convertToStringSlice(defaultOps.TLSCiphers), "list of supported TLS ciphers for HTTP"},
 		cli.StringFlag{"tls-min-version", string(defaultOps.TLSMinVersion), "mininum TLS version for HTTP"},
 
@@ -117,10 +122,12 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.IntFlag{"rpc-dial-timeout", defaultOps.RPCDialTimeout, "timeout for creating rpc connections"},
 		cli.StringFlag{"rpc-cert-verify", defaultOps.RPCCertVerify, "enable verification of rpc server certificate"},
 		cli.StringFlag{"rpc-disable-tls", defaultOps.RPCDisableTLS, "disable tls for RPC connections"},
+		cli.StringFlag{"rpc-use-grpc", defaultOps.RPCUseGRPC, "use gRPC for RPC connections when the server supports it"},
 		cli.StringSliceFlag{"rpc-tls-ciphers", convertToStringSlice(defaultOps.RPCTLSCiphers), "list of supported TLS ciphers for RPC"},
 		cli.StringFlag{"rpc-tls-min-version", string(defaultOps.RPCTLSMinVersion), "mininum TLS version for RPC"},
 		cli.IntFlag{"snapshot-ttl", defaultOps.SnapshotTTL, "snapshot TTL in hours, 0 to disable"},
 		cli.IntFlag{"snapshot-space-percent", defaultOps.SnapshotSpacePercent, "percent of tenant volume size that is assumed to be needed to create a snapshot"},
+		cli.StringFlag{"backup-compression", defaultOps.BackupCompression, "codec that compresses backup files (gzip, zstd or none)"},
 		cli.StringFlag{"controller-binary", defaultOps.ControllerBinary, "path to the container controller binary"},
 		cli.StringFlag{"log-driver", defaultOps.DockerLogDriver, "log driver for docker containers"},
 		cli.StringSliceFlag{"log-config", convertToStringSlice(defaultOps.DockerLogConfigList), "comma-separated list of key=value settings for docker log driver"},
@@ -133,7 +140,9 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.IntFlag{"zk-reconnect-start-delay", defaultOps.ZKReconnectStartDelay, "zookeeper initial reconnect delay in seconds"},
 		cli.IntFlag{"zk-reconnect-max-delay", defaultOps.ZKReconnectMaxDelay, "zookeeper max recoonect delay in seconds"},
 		cli.IntFlag{"auth-token-expiry", defaultOps.TokenExpiration, "authentication token expiration in seconds"},
+		cli.IntFlag{"auth-token-grace-period", defaultOps.TokenGracePeriod, "time in seconds an expired authentication token stays in use while the master is unreachable"},
 		cli.StringFlag{"conntrack-flush", defaultOps.ConntrackFlush, "whether to flush the conntrack table when a service with an assigned IP is started"},
+		cli.StringFlag{"preserve-containers", defaultOps.PreserveContainers, "whether to leave containers running when the agent stops, so they are re-adopted when it restarts"},
 		cli.IntFlag{"service-run-level-timeout", defaultOps.ServiceRunLevelTimeout, "max time in seconds to wait for services to start/stop before moving on to services at the next run level"},
 
 		cli.BoolTFlag{"logtostderr", "log to standard error instead of files"},
@@ -177,13 +186,27 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 	c.initSnapshot()
 	c.initLog()
 	c.initBackup()
+	c.initOps()
+	c.initOperation()
 	c.initMetric()
 	c.initDocker()
 	c.initScript()
 	c.initServer()
 	c.initVolume()
+	c.initDFS()
 	c.initKey()
 	c.initDebug()
+	c.initTop()
+	c.initCalendar()
+	c.initAudit()
+	c.initEvent()
+	c.initFeature()
+	c.initPin()
+	c.initSetting()
+	c.initDelegateProfile()
+	c.initState()
+	c.initSecret()
+	c.initZK()
 
 	return c
 }
@@ -234,6 +257,41 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		StartZK:                    cfg.BoolVal("START_ZK", true),
 		StartAPIKeyProxy:           cfg.BoolVal("START_API_KEY_PROXY", false),
 		BigTableMetrics:            cfg.BoolVal("BIGTABLE_METRICS", false),
+		NFSv4Only:                  cfg.BoolVal("NFS_V4_ONLY", false),
+		DFSNetworkDriver:           cfg.StringVal("DFS_NETWORK_DRIVER", "nfs"),
+		RBDPool:                    cfg.StringVal("RBD_POOL", "rbd"),
+		RBDImageSize:               cfg.IntVal("RBD_IMAGE_SIZE", 102400),
+		AdmissionWebhooks:          cfg.StringVal("ADMISSION_WEBHOOKS", ""),
+		SchedulerPlugins:           cfg.StringVal("SCHEDULER_PLUGINS", ""),
+		DockerRegistryMirrors:      cfg.StringSlice("DOCKER_REGISTRY_MIRRORS", []string{}),
+		DockerPullRetries:          cfg.IntVal("DOCKER_PULL_RETRIES", 3),
+		DockerPullConcurrency:      cfg.IntVal("DOCKER_PULL_CONCURRENCY", 4),
+		BackupLogstashDays:         cfg.IntVal("BACKUP_LOGSTASH_DAYS", 7),
+		BackupLogstashMaxSize:      cfg.IntVal("BACKUP_LOGSTASH_MAX_SIZE", 5),
+		MasterBootstrap:            cfg.BoolVal("MASTER_BOOTSTRAP", false),
+		MasterHA:                   cfg.BoolVal("MASTER_HA", false),
+		MasterHAScript:             cfg.StringVal("MASTER_HA_SCRIPT", ""),
+		MaxHealthChecks:            cfg.IntVal("MAX_HEALTH_CHECKS", 0),
+		MaxContainerStarts:         cfg.IntVal("MAX_CONTAINER_STARTS", 8),
+		ImagePullPolicy:            cfg.StringVal("IMAGE_PULL_POLICY", commons.PullIfNotPresent),
+		ACMEDirectory:              cfg.StringVal("ACME_DIRECTORY", ""),
+		ACMEEmail:                  cfg.StringVal("ACME_EMAIL", ""),
+		ACMEDomain:                 cfg.StringVal("ACME_DOMAIN", ""),
+		TracingCollector:           cfg.StringVal("TRACING_COLLECTOR", ""),
+		MetricsRollup:              cfg.IntVal("METRICS_ROLLUP", 10),
+		MetricsAggregatorPort:      cfg.IntVal("METRICS_AGGREGATOR_PORT", 8445),
+		MetricsSpoolMaxSize:        cfg.IntVal("METRICS_SPOOL_MAX_SIZE", 512),
+		EventMaxDays:               cfg.IntVal("EVENT_MAX_DAYS", 30),
+		EventMaxCount:              cfg.IntVal("EVENT_MAX_COUNT", 100000),
+		LogShipperPort:             cfg.IntVal("LOG_SHIPPER_PORT", 5045),
+		LogSyslogAddress:           cfg.StringVal("LOG_SYSLOG_ADDRESS", ""),
+		LogSyslogCAFile:            cfg.StringVal("LOG_SYSLOG_CA_FILE", ""),
+		LogKafkaURL:                cfg.StringVal("LOG_KAFKA_URL", ""),
+		LogKafkaTopic:              cfg.StringVal("LOG_KAFKA_TOPIC", "serviced-logs"),
+		RelayPort:                  cfg.IntVal("RELAY_PORT", 22251),
+		RelayPolicy:                cfg.StringVal("RELAY_POLICY", relay.PolicyPool),
+		AttachRelay:                cfg.StringVal("ATTACH_RELAY", "auto"),
+		MaintenancePage:            cfg.StringVal("MAINTENANCE_PAGE", ""),
 		DockerRegistry:             ctx.GlobalString("docker-registry"),
 		NFSClient:                  ctx.GlobalString("nfs-client"),
 		Endpoint:                   ctx.GlobalString("endpoint"),
@@ -248,6 +306,7 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		MUXTLSCiphers:              ctx.GlobalStringSlice("mux-tls-ciphers"),
 		MUXTLSMinVersion:           ctx.GlobalString("mux-tls-min-version"),
 		HomePath:                   api.GetDefaultOptions(cfg).HomePath,
+		MetricsSpoolPath:           api.GetDefaultOptions(cfg).MetricsSpoolPath,
 		VolumesPath:                ctx.GlobalString("volumes-path"),
 		IsvcsPath:                  ctx.GlobalString("isvcs-path"),
 		BackupsPath:                ctx.GlobalString("backups-path"),
@@ -256,6 +315,8 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		KeyPEMFile:                 ctx.GlobalString("keyfile"),
 		CertPEMFile:                ctx.GlobalString("certfile"),
 		Zookeepers:                 ctx.GlobalStringSlice("zk"),
+		CoordinatorDriver:          ctx.GlobalString("coordinator"),
+		EtcdEndpoints:              ctx.GlobalStringSlice("etcd"),
 		Mount:                      ctx.GlobalStringSlice("mount"),
 		HostAliases:                ctx.GlobalStringSlice("alias"),
 		ESStartupTimeout:           ctx.GlobalInt("es-startup-timeout"),
@@ -282,13 +343,16 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		RPCDialTimeout:             ctx.GlobalInt("rpc-dial-timeout"),
 		RPCCertVerify:              ctx.GlobalString("rpc-cert-verify"),
 		RPCDisableTLS:              ctx.GlobalString("rpc-disable-tls"),
+		RPCUseGRPC:                 ctx.GlobalString("rpc-use-grpc"),
 		RPCTLSCiphers:              ctx.GlobalStringSlice("rpc-tls-ciphers"),
 		RPCTLSMinVersion:           ctx.GlobalString("rpc-tls-min-version"),
 		SnapshotTTL:                ctx.GlobalInt("snapshot-ttl"),
 		SnapshotSpacePercent:       ctx.GlobalInt("snapshot-space-percent"),
+		BackupCompression:          ctx.GlobalString("backup-compression"),
 		StorageArgs:                ctx.GlobalStringSlice("storage-opts"),
 		ControllerBinary:           ctx.GlobalString("controller-binary"),
 		IsvcsENV:                   ctx.GlobalStringSlice("isvcs-env"),
+		IsvcsRestartPolicies:       ctx.GlobalStringSlice("isvcs-restart-policy"),
 		StartISVCS:                 ctx.GlobalStringSlice("isvcs-start"),
 		IsvcsZKID:                  ctx.GlobalInt("isvcs-zk-id"),
 		IsvcsZKQuorum:              ctx.GlobalStringSlice("isvcs-zk-quorum"),
@@ -308,7 +372,9 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		ZKReconnectStartDelay:      ctx.GlobalInt("zk-reconnect-start-delay"),
 		ZKReconnectMaxDelay:        ctx.GlobalInt("zk-reconnect-max-delay"),
 		TokenExpiration:            ctx.GlobalInt("auth-token-expiry"),
+		TokenGracePeriod:           ctx.GlobalInt("auth-token-grace-period"),
 		ConntrackFlush:             ctx.GlobalString("conntrack-flush"),
+		PreserveContainers:         ctx.GlobalString("preserve-containers"),
 		ServiceRunLevelTimeout:     ctx.GlobalInt("service-run-level-timeout"),
 		StorageMetricMonitorWindow: ctx.GlobalInt("storage-metric-monitor-window"),
 		StorageLookaheadPeriod:     ctx.GlobalInt("storage-lookahead-period"),
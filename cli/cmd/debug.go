@@ -14,8 +14,15 @@
 package cmd
 
 import (
-	"github.com/codegangsta/cli"
+	"archive/tar"
+	"compress/gzip"
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/utils"
 )
 
 // Initializer for serviced debug
@@ -36,7 +43,36 @@ func (c *ServicedCli) initDebug() {
 				Usage:        "Disable debug metrics",
 				Description:  "serviced debug disable-metrics",
 				Before:       c.cmdDisableDebugMetrics,
-			},		},
+			},
+			{
+				Name:        "profile",
+				Usage:       "Collect pprof profiles from the master or a delegate into a tarball",
+				Description: "serviced debug profile [--host HOST] [--seconds N] [--profiles PROFILE,...] [--out FILE]",
+				Action:      c.cmdDebugProfile,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "host",
+						Value: "",
+						Usage: "ID or name of the delegate to profile; profiles the master if unset",
+					},
+					cli.IntFlag{
+						Name:  "seconds",
+						Value: 30,
+						Usage: "number of seconds to sample the cpu profile",
+					},
+					cli.StringFlag{
+						Name:  "profiles",
+						Value: strings.Join(utils.Profiles, ","),
+						Usage: "comma-separated profiles to collect (cpu, heap, goroutine)",
+					},
+					cli.StringFlag{
+						Name:  "out",
+						Value: "",
+						Usage: "path to output file; defaults to serviced-profile-HOST-TIMESTAMP.tgz",
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -58,3 +94,101 @@ func (c *ServicedCli) cmdDisableDebugMetrics(ctx *cli.Context) error {
 	return fmt.Errorf(message)
 }
 
+
+// serviced debug profile [--host HOST] [--seconds N] [--profiles PROFILE,...] [--out FILE]
+func (c *ServicedCli) cmdDebugProfile(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "profile")
+		c.exit(1)
+		return
+	}
+
+	hostID, name := "", "master"
+	if h := ctx.String("host"); h != "" {
+		hosts, err := c.driver.GetHosts()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		for _, hst := range hosts {
+			if hst.ID == h || hst.Name == h {
+				hostID, name = hst.ID, hst.Name
+				break
+			}
+		}
+		if hostID == "" {
+			fmt.Fprintf(os.Stderr, "host not found: %s\n", h)
+			c.exit(1)
+			return
+		}
+	}
+
+	var names []string
+	for _, p := range strings.Split(ctx.String("profiles"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			names = append(names, p)
+		}
+	}
+	duration := time.Duration(ctx.Int("seconds")) * time.Second
+
+	profiles, err := c.driver.CollectProfiles(hostID, names, duration)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not collect profiles: %s\n", err)
+		c.exit(1)
+		return
+	}
+
+	out := ctx.String("out")
+	if out == "" {
+		out = fmt.Sprintf("serviced-profile-%s-%s.tgz", name, time.Now().UTC().Format("20060102-150405"))
+	}
+	if err := writeProfiles(out, names, profiles); err != nil {
+		fmt.Fprintf(os.Stderr, "could not write profiles: %s\n", err)
+		c.exit(1)
+		return
+	}
+	if ctx.String("out") == "" {
+		fmt.Println(out)
+	}
+}
+
+// writeProfiles writes the profiles into a gzipped tarball with one
+// PROFILE.pprof entry for each, in the order they were requested
+func writeProfiles(filename string, names []string, profiles map[string][]byte) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	wr := tar.NewWriter(gz)
+	now := time.Now()
+	for _, name := range names {
+		data, ok := profiles[name]
+		if !ok {
+			continue
+		}
+		hdr := &tar.Header{
+			Name:    name + ".pprof",
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := wr.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := wr.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := wr.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
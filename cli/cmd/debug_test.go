@@ -0,0 +1,123 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+)
+
+type DebugAPITest struct {
+	api.API
+	fail bool
+}
+
+func (t DebugAPITest) GetHosts() ([]host.Host, error) {
+	return []host.Host{{ID: "deadb10f", Name: "delegate1"}}, nil
+}
+
+func (t DebugAPITest) CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	if t.fail {
+		return nil, errors.New("profile duration must be between 0 and 5m0s")
+	}
+	result := make(map[string][]byte)
+	for _, name := range profiles {
+		result[name] = []byte(fmt.Sprintf("%s %s %s", hostID, name, duration))
+	}
+	return result, nil
+}
+
+func runDebugCmd(t DebugAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+// printProfiles prints the entries of a profile tarball
+func printProfiles(filename string) {
+	file, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		panic(err)
+	}
+	rd := tar.NewReader(gz)
+	for {
+		hdr, err := rd.Next()
+		if err != nil {
+			return
+		}
+		data, _ := ioutil.ReadAll(rd)
+		fmt.Printf("%s: %s\n", hdr.Name, data)
+	}
+}
+
+func ExampleServicedCLI_CmdDebugProfile() {
+	dir, _ := ioutil.TempDir("", "debug-profile")
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "profile.tgz")
+
+	runDebugCmd(DebugAPITest{}, "serviced", "debug", "profile", "--host", "delegate1", "--seconds", "5", "--profiles", "cpu,goroutine", "--out", out)
+	printProfiles(out)
+
+	// Output:
+	// cpu.pprof: deadb10f cpu 5s
+	// goroutine.pprof: deadb10f goroutine 5s
+}
+
+func ExampleServicedCLI_CmdDebugProfile_master() {
+	dir, _ := ioutil.TempDir("", "debug-profile")
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "profile.tgz")
+
+	runDebugCmd(DebugAPITest{}, "serviced", "debug", "profile", "--profiles", "heap", "--out", out)
+	printProfiles(out)
+
+	// Output:
+	// heap.pprof:  heap 30s
+}
+
+func ExampleServicedCLI_CmdDebugProfile_unknownHost() {
+	pipeStderr(func() {
+		runDebugCmd(DebugAPITest{}, "serviced", "debug", "profile", "--host", "nohost")
+	})
+
+	// Output:
+	// host not found: nohost
+}
+
+func ExampleServicedCLI_CmdDebugProfile_fail() {
+	pipeStderr(func() {
+		runDebugCmd(DebugAPITest{fail: true}, "serviced", "debug", "profile", "--seconds", "600")
+	})
+
+	// Output:
+	// could not collect profiles: profile duration must be between 0 and 5m0s
+}
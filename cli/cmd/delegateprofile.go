@@ -0,0 +1,370 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/delegateprofile"
+)
+
+// Initializer for serviced delegate-profile subcommands
+func (c *ServicedCli) initDelegateProfile() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "delegate-profile",
+		Usage:       "Administers the configuration profiles of delegates",
+		Description: "The options of the profile assigned to a resource pool are pushed to the hosts of the pool, which write them to /etc/default/serviced-profile",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the delegate profiles",
+				Description:  "serviced delegate-profile list",
+				BashComplete: nil,
+				Action:       c.cmdDelegateProfileList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "options",
+				Usage:        "Lists the delegate options that profiles may set",
+				Description:  "serviced delegate-profile options",
+				BashComplete: nil,
+				Action:       c.cmdDelegateProfileOptions,
+			}, {
+				Name:         "show",
+				Usage:        "Shows a delegate profile and its previous versions",
+				Description:  "serviced delegate-profile show PROFILE",
+				BashComplete: c.printDelegateProfilesFirst,
+				Action:       c.cmdDelegateProfileShow,
+			}, {
+				Name:         "add",
+				Usage:        "Adds a delegate profile",
+				Description:  "serviced delegate-profile add [--description DESCRIPTION] PROFILE",
+				BashComplete: nil,
+				Action:       c.cmdDelegateProfileAdd,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "description",
+						Value: "",
+						Usage: "Description of the profile",
+					},
+				},
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes a delegate profile that is not assigned to a pool",
+				Description:  "serviced delegate-profile remove PROFILE",
+				BashComplete: c.printDelegateProfilesFirst,
+				Action:       c.cmdDelegateProfileRemove,
+			}, {
+				Name:         "set",
+				Usage:        "Sets an option of a delegate profile and pushes it to the hosts of its pools",
+				Description:  "serviced delegate-profile set PROFILE OPTION VALUE",
+				BashComplete: c.printDelegateProfilesFirst,
+				Action:       c.cmdDelegateProfileSet,
+			}, {
+				Name:         "unset",
+				Usage:        "Removes an option from a delegate profile and pushes it to the hosts of its pools",
+				Description:  "serviced delegate-profile unset PROFILE OPTION",
+				BashComplete: c.printDelegateProfilesFirst,
+				Action:       c.cmdDelegateProfileUnset,
+			}, {
+				Name:         "rollback",
+				Usage:        "Restores a previous version of a delegate profile and pushes it to the hosts of its pools",
+				Description:  "serviced delegate-profile rollback [--version VERSION] PROFILE",
+				BashComplete: c.printDelegateProfilesFirst,
+				Action:       c.cmdDelegateProfileRollback,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "version",
+						Value: 0,
+						Usage: "Version to restore, the version before the current one by default",
+					},
+				},
+			}, {
+				Name:         "assign",
+				Usage:        "Assigns a delegate profile to a resource pool and pushes it to the hosts of the pool",
+				Description:  "serviced delegate-profile assign POOLID PROFILE",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdDelegateProfileAssign,
+			}, {
+				Name:         "unassign",
+				Usage:        "Removes the delegate profile of a resource pool from the hosts of the pool",
+				Description:  "serviced delegate-profile unassign POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdDelegateProfileUnassign,
+			}, {
+				Name:         "push",
+				Usage:        "Pushes the delegate profile of a resource pool to the hosts of the pool",
+				Description:  "serviced delegate-profile push POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdDelegateProfilePush,
+			},
+		},
+	})
+}
+
+// printDelegateProfilesFirst is the completion action for the first argument
+func (c *ServicedCli) printDelegateProfilesFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	profiles, err := c.driver.GetDelegateProfiles()
+	if err != nil {
+		return
+	}
+	for _, p := range profiles {
+		fmt.Println(p.ID)
+	}
+}
+
+// delegateOptions describes the options of a profile
+func delegateOptions(options map[string]string) string {
+	values := []string{}
+	for name, value := range options {
+		values = append(values, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
+// printHostStatuses prints the outcome of pushing a profile to each host
+func printHostStatuses(statuses []delegateprofile.HostStatus) {
+	if len(statuses) == 0 {
+		return
+	}
+	t := NewTable("Host,Pool,Profile,Version,Error,Restart")
+	t.Padding = 6
+	for _, s := range statuses {
+		t.AddRow(map[string]interface{}{
+			"Host":    s.HostID,
+			"Pool":    s.PoolID,
+			"Profile": s.Profile,
+			"Version": s.Version,
+			"Restart": strings.Join(s.Restart, ","),
+			"Error":   s.Error,
+		})
+	}
+	t.Print()
+}
+
+// serviced delegate-profile list [--verbose]
+func (c *ServicedCli) cmdDelegateProfileList(ctx *cli.Context) {
+	profiles, err := c.driver.GetDelegateProfiles()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(profiles) == 0 {
+		fmt.Fprintln(os.Stderr, "no delegate profiles found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonProfiles, err := json.MarshalIndent(profiles, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal delegate profiles: %s", err)
+		} else {
+			fmt.Println(string(jsonProfiles))
+		}
+		return
+	}
+
+	t := NewTable("Name,Version,Options,Description")
+	t.Padding = 6
+	for _, p := range profiles {
+		t.AddRow(map[string]interface{}{
+			"Name":        p.ID,
+			"Version":     p.Version,
+			"Options":     delegateOptions(p.Options),
+			"Description": p.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced delegate-profile options
+func (c *ServicedCli) cmdDelegateProfileOptions(ctx *cli.Context) {
+	t := NewTable("Option,Description")
+	t.Padding = 6
+	for _, name := range delegateprofile.Names() {
+		opt, _ := delegateprofile.Lookup(name)
+		t.AddRow(map[string]interface{}{
+			"Option":      name,
+			"Description": opt.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced delegate-profile show PROFILE
+func (c *ServicedCli) cmdDelegateProfileShow(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "show")
+		return
+	}
+
+	p, err := c.driver.GetDelegateProfile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if jsonProfile, err := json.MarshalIndent(p, " ", "  "); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal delegate profile: %s", err)
+	} else {
+		fmt.Println(string(jsonProfile))
+	}
+}
+
+// serviced delegate-profile add [--description DESCRIPTION] PROFILE
+func (c *ServicedCli) cmdDelegateProfileAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	if err := c.driver.AddDelegateProfile(args[0], ctx.String("description")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced delegate-profile remove PROFILE
+func (c *ServicedCli) cmdDelegateProfileRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	if err := c.driver.RemoveDelegateProfile(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced delegate-profile set PROFILE OPTION VALUE
+func (c *ServicedCli) cmdDelegateProfileSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 3 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	statuses, err := c.driver.SetDelegateProfileOption(args[0], args[1], args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
+
+// serviced delegate-profile unset PROFILE OPTION
+func (c *ServicedCli) cmdDelegateProfileUnset(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "unset")
+		return
+	}
+
+	statuses, err := c.driver.UnsetDelegateProfileOption(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
+
+// serviced delegate-profile rollback [--version VERSION] PROFILE
+func (c *ServicedCli) cmdDelegateProfileRollback(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "rollback")
+		return
+	}
+
+	statuses, err := c.driver.RollbackDelegateProfile(args[0], ctx.Int("version"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
+
+// serviced delegate-profile assign POOLID PROFILE
+func (c *ServicedCli) cmdDelegateProfileAssign(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "assign")
+		return
+	}
+
+	statuses, err := c.driver.AssignDelegateProfile(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
+
+// serviced delegate-profile unassign POOLID
+func (c *ServicedCli) cmdDelegateProfileUnassign(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "unassign")
+		return
+	}
+
+	statuses, err := c.driver.AssignDelegateProfile(args[0], "")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
+
+// serviced delegate-profile push POOLID
+func (c *ServicedCli) cmdDelegateProfilePush(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "push")
+		return
+	}
+
+	statuses, err := c.driver.PushDelegateProfile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	printHostStatuses(statuses)
+}
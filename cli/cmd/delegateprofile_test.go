@@ -0,0 +1,139 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/utils"
+)
+
+type DelegateProfileAPITest struct {
+	api.API
+	profiles map[string]*delegateprofile.Profile
+	pools    map[string]string
+}
+
+func NewDelegateProfileAPITest() DelegateProfileAPITest {
+	debug := delegateprofile.New("debug", "Verbose logging")
+	debug.Options[delegateprofile.LogLevel] = "2"
+	return DelegateProfileAPITest{
+		profiles: map[string]*delegateprofile.Profile{"debug": debug},
+		pools:    map[string]string{"default": ""},
+	}
+}
+
+func (t DelegateProfileAPITest) GetDelegateProfiles() ([]delegateprofile.Profile, error) {
+	profiles := []delegateprofile.Profile{}
+	for _, p := range t.profiles {
+		profiles = append(profiles, *p)
+	}
+	return profiles, nil
+}
+
+func (t DelegateProfileAPITest) AddDelegateProfile(name, description string) error {
+	if _, ok := t.profiles[name]; ok {
+		return errors.New("delegate profile exists")
+	}
+	t.profiles[name] = delegateprofile.New(name, description)
+	return nil
+}
+
+func (t DelegateProfileAPITest) status(poolID string) []delegateprofile.HostStatus {
+	p, ok := t.profiles[t.pools[poolID]]
+	if !ok {
+		return []delegateprofile.HostStatus{{HostID: "host", PoolID: poolID}}
+	}
+	return []delegateprofile.HostStatus{{HostID: "host", PoolID: poolID, Profile: p.ID, Version: p.Version, Restart: []string{"SERVICED_MUX_PORT"}}}
+}
+
+func (t DelegateProfileAPITest) SetDelegateProfileOption(name, option, value string) ([]delegateprofile.HostStatus, error) {
+	p, ok := t.profiles[name]
+	if !ok {
+		return nil, errors.New("delegate profile not found")
+	}
+	p.SetOptions(p.Set(option, value))
+	return []delegateprofile.HostStatus{}, nil
+}
+
+func (t DelegateProfileAPITest) RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error) {
+	p, ok := t.profiles[name]
+	if !ok {
+		return nil, errors.New("delegate profile not found")
+	}
+	return []delegateprofile.HostStatus{}, p.Rollback(version)
+}
+
+func (t DelegateProfileAPITest) AssignDelegateProfile(poolID, name string) ([]delegateprofile.HostStatus, error) {
+	if _, ok := t.pools[poolID]; !ok {
+		return nil, errors.New("pool not found")
+	}
+	t.pools[poolID] = name
+	return t.status(poolID), nil
+}
+
+func runDelegateProfileCmd(t DelegateProfileAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdDelegateProfileList() {
+	runDelegateProfileCmd(NewDelegateProfileAPITest(), "serviced", "delegate-profile", "list")
+
+	// Output:
+	// Name       Version      Options                   Description
+	// debug      1            SERVICED_LOG_LEVEL=2      Verbose logging
+}
+
+func ExampleServicedCLI_CmdDelegateProfileSet() {
+	test := NewDelegateProfileAPITest()
+	runDelegateProfileCmd(test, "serviced", "delegate-profile", "set", "debug", "SERVICED_MUX_PORT", "22251")
+	runDelegateProfileCmd(test, "serviced", "delegate-profile", "rollback", "debug")
+	runDelegateProfileCmd(test, "serviced", "delegate-profile", "list")
+
+	// Output:
+	// Name       Version      Options                   Description
+	// debug      3            SERVICED_LOG_LEVEL=2      Verbose logging
+}
+
+func ExampleServicedCLI_CmdDelegateProfileAssign() {
+	test := NewDelegateProfileAPITest()
+	runDelegateProfileCmd(test, "serviced", "delegate-profile", "assign", "default", "debug")
+	runDelegateProfileCmd(test, "serviced", "delegate-profile", "unassign", "default")
+
+	// Output:
+	// Host      Pool         Profile      Version      Error      Restart
+	// host      default      debug        1                       SERVICED_MUX_PORT
+	// Host      Pool         Profile      Version      Error      Restart
+	// host      default                   0
+}
+
+func ExampleServicedCLI_CmdDelegateProfileAdd_err() {
+	pipeStderr(func() {
+		runDelegateProfileCmd(NewDelegateProfileAPITest(), "serviced", "delegate-profile", "add", "debug")
+	})
+	pipeStderr(func() {
+		runDelegateProfileCmd(NewDelegateProfileAPITest(), "serviced", "delegate-profile", "rollback", "--version", "7", "debug")
+	})
+
+	// Output:
+	// delegate profile exists
+	// profile debug has no previous versions
+}
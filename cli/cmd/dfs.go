@@ -0,0 +1,104 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/dfs"
+)
+
+// Initializer for serviced dfs subcommands
+func (c *ServicedCli) initDFS() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "dfs",
+		Usage:       "Administers the distributed filesystem",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "fsck",
+				Usage:       "Checks the volumes, snapshots, registry and service images for dangling or missing references",
+				Description: "serviced dfs fsck [--repair]",
+				Action:      c.cmdDFSFsck,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "repair",
+						Usage: "Apply the repairs that are safe; the rest are left to be repaired by hand",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced dfs fsck [--repair] [--verbose]
+func (c *ServicedCli) cmdDFSFsck(ctx *cli.Context) {
+	problems, err := c.driver.CheckDFS(ctx.Bool("repair"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonProblems, err := json.MarshalIndent(problems, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal dfs problems: %s\n", err)
+		} else {
+			fmt.Println(string(jsonProblems))
+		}
+	} else if len(problems) == 0 {
+		fmt.Println("No problems found")
+		return
+	} else {
+		t := NewTable("Kind,Tenant,Subject,Detail,Repair")
+		for _, p := range problems {
+			t.AddRow(map[string]interface{}{
+				"Kind":    p.Kind,
+				"Tenant":  p.TenantID,
+				"Subject": p.Subject,
+				"Detail":  p.Detail,
+				"Repair":  fsckRepairStatus(p),
+			})
+		}
+		t.Print()
+	}
+
+	for _, p := range problems {
+		if !p.Repaired {
+			c.exit(1)
+			return
+		}
+	}
+}
+
+// fsckRepairStatus describes the repair of a problem
+func fsckRepairStatus(p dfs.FsckProblem) string {
+	switch {
+	case p.Repair == "":
+		return "repair by hand"
+	case p.Repaired:
+		return "repaired: " + p.Repair
+	case p.Error != "":
+		return fmt.Sprintf("failed to %s: %s", p.Repair, p.Error)
+	default:
+		return "run with --repair to " + p.Repair
+	}
+}
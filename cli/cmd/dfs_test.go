@@ -0,0 +1,72 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"github.com/control-center/serviced/dfs"
+)
+
+func (t ServiceAPITest) CheckDFS(repair bool) ([]dfs.FsckProblem, error) {
+	if t.errs["CheckDFS"] != nil {
+		return nil, t.errs["CheckDFS"]
+	}
+	problems := []dfs.FsckProblem{
+		{
+			Kind:     dfs.FsckStaleSnapshotImage,
+			TenantID: "test-service-1",
+			Subject:  "test-service-1/repo:20170101_000000.000",
+			Detail:   "the snapshot of the image does not exist",
+			Repair:   "remove the image from the registry index",
+		}, {
+			Kind:     dfs.FsckMissingVolume,
+			TenantID: "test-service-2",
+			Subject:  "test-service-2",
+			Detail:   "the application does not have a volume",
+		},
+	}
+	if repair {
+		problems[0].Repaired = true
+	}
+	return problems, nil
+}
+
+func ExampleServicedCLI_CmdDFSFsck() {
+	InitServiceAPITest("serviced", "dfs", "fsck")
+
+	// Output:
+	// Kind                 Tenant         Subject                                 Detail                                   Repair
+	// stale-snapshot-image test-service-1 test-service-1/repo:20170101_000000.000 the snapshot of the image does not exist run with --repair to remove the image from the registry index
+	// missing-volume       test-service-2 test-service-2                          the application does not have a volume   repair by hand
+}
+
+func ExampleServicedCLI_CmdDFSFsck_repair() {
+	InitServiceAPITest("serviced", "dfs", "fsck", "--repair")
+
+	// Output:
+	// Kind                 Tenant         Subject                                 Detail                                   Repair
+	// stale-snapshot-image test-service-1 test-service-1/repo:20170101_000000.000 the snapshot of the image does not exist repaired: remove the image from the registry index
+	// missing-volume       test-service-2 test-service-2                          the application does not have a volume   repair by hand
+}
+
+func ExampleServicedCLI_CmdDFSFsck_err() {
+	DefaultServiceAPITest.errs["CheckDFS"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["CheckDFS"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "dfs", "fsck") })
+
+	// Output:
+	// stub for facade failed
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/event"
+)
+
+// Initializer for serviced event subcommands
+func (c *ServicedCli) initEvent() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "event",
+		Usage:       "Queries the events recorded by the master",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the recorded events, such as changes to the state of services and alerts",
+				Description:  "serviced event list [--since DURATION|TIME] [--until DURATION|TIME] [--type TYPE] [--entity ID]",
+				BashComplete: nil,
+				Action:       c.cmdEventList,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "since",
+						Value: "24h",
+						Usage: "Show events newer than a duration (e.g. 30m, 24h) or a time (YYYY-MM-DD or RFC3339)",
+					},
+					cli.StringFlag{
+						Name:  "until",
+						Value: "",
+						Usage: "Show events older than a duration (e.g. 30m, 24h) or a time (YYYY-MM-DD or RFC3339)",
+					},
+					cli.StringFlag{
+						Name:  "type",
+						Value: "",
+						Usage: fmt.Sprintf("Show only events of a type (%s or %s)", event.TypeServiceState, event.TypeAlert),
+					},
+					cli.StringFlag{
+						Name:  "entity",
+						Value: "",
+						Usage: "Show only events of an entity, or of the services of a tenant",
+					},
+					cli.IntFlag{
+						Name:  "limit",
+						Value: event.DefaultLimit,
+						Usage: "Number of events to show",
+					},
+					cli.IntFlag{
+						Name:  "offset",
+						Value: 0,
+						Usage: "Number of matching events to skip",
+					},
+					cli.BoolFlag{
+						Name:  "all",
+						Usage: "Show every matching event instead of a single page",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Time,Type,EntityType,EntityID,Message",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced event list [--since DURATION|TIME] [--until DURATION|TIME] [--type TYPE] [--entity ID] [--limit N] [--offset N] [--all] [--verbose] [--show-fields FIELDS]
+func (c *ServicedCli) cmdEventList(ctx *cli.Context) {
+	now := time.Now()
+	q := event.Query{
+		Type:     ctx.String("type"),
+		EntityID: ctx.String("entity"),
+		Offset:   ctx.Int("offset"),
+		Limit:    ctx.Int("limit"),
+	}
+	var err error
+	if q.Since, err = parseSince(ctx.String("since"), now); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if until := ctx.String("until"); until != "" {
+		if q.Until, err = parseSince(until, now); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+	}
+
+	events := []event.Event{}
+	next := 0
+	for {
+		page, err := c.driver.QueryEvents(q)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		events = append(events, page.Events...)
+		next = page.Next
+		if next == 0 || !ctx.Bool("all") {
+			break
+		}
+		q.Offset = next
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(os.Stderr, "no events found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonEvents, err := json.MarshalIndent(events, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal events: %s", err)
+		} else {
+			fmt.Println(string(jsonEvents))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		t.Padding = 6
+		for _, e := range events {
+			t.AddRow(map[string]interface{}{
+				"Time":       e.Time.Format(time.RFC3339),
+				"Type":       e.Type,
+				"EntityType": e.EntityType,
+				"EntityID":   e.EntityID,
+				"TenantID":   e.TenantID,
+				"Message":    e.Message,
+			})
+		}
+		t.Print()
+	}
+	if next > 0 {
+		fmt.Fprintf(os.Stderr, "more events: --offset %d\n", next)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/utils"
+)
+
+type EventAPITest struct {
+	api.API
+	events []event.Event
+}
+
+// QueryEvents pages through the events of the test that match the type and
+// entity of the query
+func (t EventAPITest) QueryEvents(q event.Query) (*event.Page, error) {
+	matches := []event.Event{}
+	for _, e := range t.events {
+		if e.Time.Before(q.Since) || (q.Type != "" && e.Type != q.Type) {
+			continue
+		}
+		if q.EntityID != "" && e.EntityID != q.EntityID && e.TenantID != q.EntityID {
+			continue
+		}
+		matches = append(matches, e)
+	}
+	page := &event.Page{Events: []event.Event{}}
+	for i := q.Offset; i < len(matches) && len(page.Events) < q.Limit; i++ {
+		page.Events = append(page.Events, matches[i])
+	}
+	if q.Offset+len(page.Events) < len(matches) {
+		page.Next = q.Offset + len(page.Events)
+	}
+	return page, nil
+}
+
+func NewEventAPITest() EventAPITest {
+	return EventAPITest{
+		events: []event.Event{
+			{
+				ID:         "1",
+				Time:       time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC),
+				Type:       event.TypeServiceState,
+				EntityType: "service",
+				EntityID:   "svc1",
+				TenantID:   "tenant1",
+				Message:    "Service state changed to started",
+			}, {
+				ID:         "2",
+				Time:       time.Date(2017, time.June, 1, 12, 5, 0, 0, time.UTC),
+				Type:       event.TypeAlert,
+				EntityType: "Volume",
+				EntityID:   "tenant1",
+				TenantID:   "tenant1",
+				Message:    "Volume quota threshold reached",
+			}, {
+				ID:         "3",
+				Time:       time.Date(2017, time.June, 1, 12, 10, 0, 0, time.UTC),
+				Type:       event.TypeServiceState,
+				EntityType: "service",
+				EntityID:   "svc2",
+				TenantID:   "tenant2",
+				Message:    "Service state changed to stopped",
+			},
+		},
+	}
+}
+
+func runEventCmd(t EventAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdEventList() {
+	runEventCmd(NewEventAPITest(), "serviced", "event", "list", "--since", "2017-06-01T00:00:00Z", "--entity", "tenant1")
+
+	// Output:
+	// Time                      Type              EntityType      EntityID      Message
+	// 2017-06-01T12:00:00Z      servicestate      service         svc1          Service state changed to started
+	// 2017-06-01T12:05:00Z      alert             Volume          tenant1       Volume quota threshold reached
+}
+
+func ExampleServicedCLI_CmdEventList_page() {
+	pipeStderr(func() {
+		runEventCmd(NewEventAPITest(), "serviced", "event", "list", "--since", "2017-06-01T00:00:00Z", "--limit", "1", "--offset", "1", "--show-fields", "EntityID")
+	})
+
+	// Output:
+	// EntityID
+	// tenant1
+	// more events: --offset 2
+}
+
+func ExampleServicedCLI_CmdEventList_all() {
+	runEventCmd(NewEventAPITest(), "serviced", "event", "list", "--since", "2017-06-01T00:00:00Z", "--limit", "1", "--all", "--show-fields", "EntityID")
+
+	// Output:
+	// EntityID
+	// svc1
+	// tenant1
+	// svc2
+}
+
+func ExampleServicedCLI_CmdEventList_none() {
+	pipeStderr(func() {
+		runEventCmd(NewEventAPITest(), "serviced", "event", "list", "--type", event.TypeAlert, "--since", "1h")
+	})
+
+	// Output:
+	// no events found
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/feature"
+)
+
+// Initializer for serviced feature subcommands
+func (c *ServicedCli) initFeature() {
+	scopeFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "pool",
+			Value: "",
+			Usage: "Only toggle the feature in this resource pool",
+		},
+		cli.StringFlag{
+			Name:  "deployment",
+			Value: "",
+			Usage: "Only toggle the feature in this deployment",
+		},
+	}
+
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "feature",
+		Usage:       "Administers feature flags",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the features and where they are enabled",
+				Description:  "serviced feature list",
+				BashComplete: nil,
+				Action:       c.cmdFeatureList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "enable",
+				Usage:        "Enables a feature everywhere or in a pool or deployment",
+				Description:  "serviced feature enable [--pool POOLID] [--deployment DEPLOYMENTID] FEATURE",
+				BashComplete: c.printFeaturesFirst,
+				Action:       c.cmdFeatureEnable,
+				Flags:        scopeFlags,
+			}, {
+				Name:         "disable",
+				Usage:        "Disables a feature everywhere or in a pool or deployment",
+				Description:  "serviced feature disable [--pool POOLID] [--deployment DEPLOYMENTID] FEATURE",
+				BashComplete: c.printFeaturesFirst,
+				Action:       c.cmdFeatureDisable,
+				Flags:        scopeFlags,
+			},
+		},
+	})
+}
+
+// printFeaturesFirst is the generic completion action for the first argument
+func (c *ServicedCli) printFeaturesFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	for _, name := range feature.Names() {
+		fmt.Println(name)
+	}
+}
+
+// serviced feature list [--verbose]
+func (c *ServicedCli) cmdFeatureList(ctx *cli.Context) {
+	flags, err := c.driver.GetFeatureFlags()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(flags) == 0 {
+		fmt.Fprintln(os.Stderr, "no features found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonFlags, err := json.MarshalIndent(flags, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal feature flags: %s", err)
+		} else {
+			fmt.Println(string(jsonFlags))
+		}
+		return
+	}
+
+	t := NewTable("Name,Enabled,Pools,Deployments,Description")
+	t.Padding = 6
+	for _, flag := range flags {
+		def, _ := feature.Lookup(flag.ID)
+		t.AddRow(map[string]interface{}{
+			"Name":        flag.ID,
+			"Enabled":     flag.Global,
+			"Pools":       strings.Join(flag.Pools, ","),
+			"Deployments": strings.Join(flag.Deployments, ","),
+			"Description": def.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced feature enable [--pool POOLID] [--deployment DEPLOYMENTID] FEATURE
+func (c *ServicedCli) cmdFeatureEnable(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "enable")
+		return
+	}
+
+	if err := c.driver.EnableFeature(args[0], ctx.String("pool"), ctx.String("deployment")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced feature disable [--pool POOLID] [--deployment DEPLOYMENTID] FEATURE
+func (c *ServicedCli) cmdFeatureDisable(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "disable")
+		return
+	}
+
+	if err := c.driver.DisableFeature(args[0], ctx.String("pool"), ctx.String("deployment")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/feature"
+	"github.com/control-center/serviced/utils"
+)
+
+type FeatureAPITest struct {
+	api.API
+	flags map[string]*feature.Flag
+}
+
+func NewFeatureAPITest() FeatureAPITest {
+	return FeatureAPITest{
+		flags: map[string]*feature.Flag{
+			feature.StickyRescheduling: {ID: feature.StickyRescheduling, Global: true},
+		},
+	}
+}
+
+func (t FeatureAPITest) GetFeatureFlags() ([]feature.Flag, error) {
+	flags := []feature.Flag{}
+	for _, name := range feature.Names() {
+		flags = append(flags, *t.flags[name])
+	}
+	return flags, nil
+}
+
+func (t FeatureAPITest) EnableFeature(name, poolID, deploymentID string) error {
+	flag, ok := t.flags[name]
+	if !ok {
+		return errors.New("feature not found")
+	}
+	flag.Enable(poolID, deploymentID)
+	return nil
+}
+
+func (t FeatureAPITest) DisableFeature(name, poolID, deploymentID string) error {
+	flag, ok := t.flags[name]
+	if !ok {
+		return errors.New("feature not found")
+	}
+	flag.Disable(poolID, deploymentID)
+	return nil
+}
+
+func runFeatureCmd(t FeatureAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdFeatureList() {
+	runFeatureCmd(NewFeatureAPITest(), "serviced", "feature", "list")
+
+	// Output:
+	// Name                     Enabled      Pools      Deployments      Description
+	// sticky-rescheduling      true                                     Reschedule service instances on the host where they last ran
+}
+
+func ExampleServicedCLI_CmdFeatureDisable() {
+	test := NewFeatureAPITest()
+	runFeatureCmd(test, "serviced", "feature", "disable", feature.StickyRescheduling)
+	runFeatureCmd(test, "serviced", "feature", "enable", "--pool", "default", feature.StickyRescheduling)
+	runFeatureCmd(test, "serviced", "feature", "list")
+
+	// Output:
+	// sticky-rescheduling
+	// sticky-rescheduling
+	// Name                     Enabled      Pools        Deployments      Description
+	// sticky-rescheduling      false        default                       Reschedule service instances on the host where they last ran
+}
+
+func ExampleServicedCLI_CmdFeatureEnable_err() {
+	pipeStderr(func() { runFeatureCmd(NewFeatureAPITest(), "serviced", "feature", "enable", "no-such-feature") })
+
+	// Output:
+	// feature not found
+}
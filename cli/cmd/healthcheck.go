@@ -16,8 +16,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/commons/startup"
 )
 
 // Initializer for serviced healthcheck subcommands
@@ -25,8 +28,18 @@ func (c *ServicedCli) initHealthCheck() {
 	c.app.Commands = append(c.app.Commands, cli.Command{
 		Name:        "healthcheck",
 		Usage:       "Reports on health of serviced",
-		Description: "serviced healthcheck [ISERVICENAME-1 [ISERVICENAME-2 ... [ISERVICENAME-N]]]",
+		Description: "serviced healthcheck [--startup|--history] [ISERVICENAME-1 [ISERVICENAME-2 ... [ISERVICENAME-N]]]",
 		Before:      c.cmdHealthCheck,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "startup",
+				Usage: "Show the progress of each step of the master's startup",
+			},
+			cli.BoolFlag{
+				Name:  "history",
+				Usage: "Show recent health transitions and restarts of internal services",
+			},
+		},
 	})
 }
 
@@ -36,6 +49,14 @@ func (c *ServicedCli) cmdHealthCheck(ctx *cli.Context) error {
 		return nil
 	}
 
+	if ctx.Bool("startup") {
+		return c.cmdHealthCheckStartup(ctx)
+	}
+
+	if ctx.Bool("history") {
+		return c.cmdHealthCheckHistory(ctx)
+	}
+
 	if results, err := c.driver.ServicedHealthCheck(ctx.Args()); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return c.exit(2)
@@ -67,6 +88,86 @@ func (c *ServicedCli) cmdHealthCheck(ctx *cli.Context) error {
 	}
 }
 
+// serviced healthcheck --startup
+func (c *ServicedCli) cmdHealthCheckStartup(ctx *cli.Context) error {
+	progress, err := c.driver.GetStartupProgress()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return c.exit(2)
+	}
+
+	exitStatus := 0
+	var failures []startup.Status
+	t := NewTable("Step,Requires,Duration,State")
+	t.Padding = 2
+	for _, step := range progress {
+		if step.State != startup.StateDone {
+			exitStatus = 1
+		}
+		if step.State == startup.StateFailed {
+			failures = append(failures, step)
+		}
+		duration := ""
+		if step.State != startup.StatePending && step.State != startup.StateSkipped {
+			duration = step.Duration.Round(time.Millisecond).String()
+		}
+		t.AddRow(map[string]interface{}{
+			"Step":     step.Name,
+			"Requires": strings.Join(step.Requires, ","),
+			"Duration": duration,
+			"State":    step.State,
+		})
+	}
+	t.Print()
+	for _, step := range failures {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", step.Name, getCombinedStatus(step.Error, step.Hint))
+	}
+	return c.exit(exitStatus)
+}
+
+// serviced healthcheck --history [ISERVICENAME]
+func (c *ServicedCli) cmdHealthCheckHistory(ctx *cli.Context) error {
+	results, err := c.driver.ServicedHealthCheck(ctx.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return c.exit(2)
+	}
+
+	t := NewTable("Service Name,Time,Health Check,Instance,Transition")
+	t.Padding = 2
+	for _, serviceHealth := range results {
+		for _, transition := range serviceHealth.History {
+			t.AddRow(map[string]interface{}{
+				"Service Name": serviceHealth.ServiceName,
+				"Time":         time.Unix(transition.Timestamp, 0).UTC().Format(time.RFC3339),
+				"Health Check": transition.HealthCheck,
+				"Instance":     transition.Instance,
+				"Transition":   getCombinedStatus(fmt.Sprintf("%s -> %s", transition.From, transition.To), transition.Failure),
+			})
+		}
+	}
+	t.Print()
+	fmt.Println()
+
+	t = NewTable("Service Name,Restarts (1h),Max Restarts,Backoff,Max Backoff")
+	t.Padding = 2
+	for _, serviceHealth := range results {
+		maxRestarts := "unlimited"
+		if serviceHealth.RestartPolicy.MaxRestartsPerHour > 0 {
+			maxRestarts = fmt.Sprintf("%d", serviceHealth.RestartPolicy.MaxRestartsPerHour)
+		}
+		t.AddRow(map[string]interface{}{
+			"Service Name":  serviceHealth.ServiceName,
+			"Restarts (1h)": serviceHealth.Restarts,
+			"Max Restarts":  maxRestarts,
+			"Backoff":       serviceHealth.RestartPolicy.Backoff,
+			"Max Backoff":   serviceHealth.RestartPolicy.MaxBackoff,
+		})
+	}
+	t.Print()
+	return c.exit(0)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -16,9 +16,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/commons/startup"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/utils"
@@ -93,11 +96,38 @@ var DefaultTestHealthCheckResults = []isvcs.IServiceHealthResult{
 		ContainerID:    "id-unknown",
 		HealthStatuses: []domain.HealthCheckStatus{UnknownHealthStatus},
 	},
+	isvcs.IServiceHealthResult{
+		ServiceName:    "test-iservice-history",
+		ContainerName:  "container-history",
+		ContainerID:    "id-history",
+		HealthStatuses: []domain.HealthCheckStatus{DefaultHealthStatus},
+		History: []isvcs.HealthTransition{
+			{Timestamp: 1483326245, HealthCheck: "running", From: "unknown", To: "passed"},
+			{Timestamp: 1483326305, HealthCheck: "running", From: "passed", To: "stopped", Failure: "isvc: service received exit code 1"},
+			{Timestamp: 1483326315, HealthCheck: "running", Instance: 1, From: "stopped", To: "passed"},
+		},
+		Restarts:      1,
+		RestartPolicy: isvcs.RestartPolicy{MaxRestartsPerHour: 10, Backoff: 5 * time.Second, MaxBackoff: 5 * time.Minute},
+	},
+}
+
+var DefaultTestStartupProgress = []startup.Status{
+	{Name: "storage", State: startup.StateDone, Duration: 1200 * time.Millisecond},
+	{Name: "isvcs", State: startup.StateDone, Duration: 45 * time.Second},
+	{Name: "datastore", Requires: []string{"isvcs"}, State: startup.StateRunning, Duration: 3 * time.Second},
+	{Name: "facade", Requires: []string{"storage", "datastore"}, State: startup.StatePending},
+}
+
+var FailedTestStartupProgress = []startup.Status{
+	{Name: "isvcs", State: startup.StateDone, Duration: 45 * time.Second},
+	{Name: "datastore", Requires: []string{"isvcs"}, State: startup.StateFailed, Duration: 10 * time.Second, Error: "connection refused", Hint: "check elastic"},
+	{Name: "facade", Requires: []string{"datastore"}, State: startup.StateSkipped},
 }
 
 type HealthCheckAPITest struct {
 	api.API
 	apiResults []isvcs.IServiceHealthResult
+	startup    []startup.Status
 }
 
 func InitHealthCheckAPITest(args ...string) {
@@ -106,6 +136,19 @@ func InitHealthCheckAPITest(args ...string) {
 	c.Run(args)
 }
 
+func InitHealthCheckStartupAPITest(progress []startup.Status, args ...string) {
+	c := New(HealthCheckAPITest{startup: progress}, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func (t HealthCheckAPITest) GetStartupProgress() ([]startup.Status, error) {
+	if t.startup == nil {
+		return nil, errors.New("could not find service Startup")
+	}
+	return t.startup, nil
+}
+
 func (t HealthCheckAPITest) ServicedHealthCheck(IServiceNames []string) ([]isvcs.IServiceHealthResult, error) {
 	mockResults := make([]isvcs.IServiceHealthResult, 0)
 	for _, serviceName := range IServiceNames {
@@ -176,3 +219,60 @@ func ExampleServicedCLI_CmdHealthCheck_unknownStatus() {
 	// test-iservice-unknown  container-unknown  id-unknown    running       unknown
 	// exit code 1
 }
+
+func ExampleServicedCLI_CmdHealthCheck_history() {
+	pipeStderr(func() {
+		InitHealthCheckAPITest("serviced", "healthcheck", "--history", "test-iservice-history", "test-iservice-1")
+	})
+
+	// Output:
+	// Service Name           Time                  Health Check  Instance  Transition
+	// test-iservice-history  2017-01-02T03:04:05Z  running       0         unknown -> passed
+	// test-iservice-history  2017-01-02T03:05:05Z  running       0         passed -> stopped - isvc: service received exit code 1
+	// test-iservice-history  2017-01-02T03:05:15Z  running       1         stopped -> passed
+	//
+	// Service Name           Restarts (1h)  Max Restarts  Backoff  Max Backoff
+	// test-iservice-history  1              10            5s       5m0s
+	// test-iservice-1        0              unlimited     0s       0s
+	// exit code 0
+}
+
+func ExampleServicedCLI_CmdHealthCheck_historyUndefinedService() {
+	pipeStderr(func() { InitHealthCheckAPITest("serviced", "healthcheck", "--history", "undefined-iservice") })
+
+	// Output:
+	// could not find isvc "undefined-iservice"
+	// exit code 2
+}
+
+func ExampleServicedCLI_CmdHealthCheck_startup() {
+	pipeStderr(func() { InitHealthCheckStartupAPITest(DefaultTestStartupProgress, "serviced", "healthcheck", "--startup") })
+
+	// Output:
+	// Step       Requires           Duration  State
+	// storage                       1.2s      done
+	// isvcs                         45s       done
+	// datastore  isvcs              3s        running
+	// facade     storage,datastore            pending
+	// exit code 1
+}
+
+func ExampleServicedCLI_CmdHealthCheck_startupFailed() {
+	pipeStderr(func() { InitHealthCheckStartupAPITest(FailedTestStartupProgress, "serviced", "healthcheck", "--startup") })
+
+	// Output:
+	// Step       Requires   Duration  State
+	// isvcs                 45s       done
+	// datastore  isvcs      10s       failed
+	// facade     datastore            skipped
+	// datastore: connection refused - check elastic
+	// exit code 1
+}
+
+func ExampleServicedCLI_CmdHealthCheck_startupUnavailable() {
+	pipeStderr(func() { InitHealthCheckStartupAPITest(nil, "serviced", "healthcheck", "--startup") })
+
+	// Output:
+	// could not find service Startup
+	// exit code 2
+}
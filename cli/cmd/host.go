@@ -20,9 +20,11 @@ import (
 	"net"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
 	"github.com/pivotal-golang/bytefmt"
 )
@@ -50,6 +52,14 @@ func (c *ServicedCli) initHost() {
 						Value: "ID,Auth,Pool,Name,Addr,RPCPort,Cores,RAM,Cur/Max/Avg,Network,Release",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
+					cli.StringFlag{
+						Name:  "pool",
+						Usage: "Only list the hosts in this resource pool",
+					},
+					cli.BoolFlag{
+						Name:  "benchmarks",
+						Usage: "Show the benchmark results of the hosts",
+					},
 				},
 			}, {
 				Name:         "add",
@@ -77,6 +87,10 @@ func (c *ServicedCli) initHost() {
 						Name:  "register, r",
 						Usage: "Register delegate keys on the host via ssh",
 					},
+					cli.BoolFlag{
+						Name:  "benchmark",
+						Usage: "Benchmark the disk, network, and cpu of the host",
+					},
 				},
 			}, {
 				Name:         "add-private",
@@ -96,6 +110,10 @@ func (c *ServicedCli) initHost() {
 						Value: "",
 						Usage: "The HOST:PORT of the NAT for this delegate",
 					},
+					cli.BoolFlag{
+						Name:  "benchmark",
+						Usage: "Benchmark the disk, network, and cpu of the host",
+					},
 				},
 			}, {
 				Name:         "remove",
@@ -104,6 +122,25 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host remove HOSTID ...",
 				BashComplete: c.printHostsAll,
 				Action:       c.cmdHostRemove,
+			}, {
+				Name:        "maintenance",
+				Usage:       "Administers host maintenance mode",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:         "enable",
+						Usage:        "Stops scheduling to a host and moves its running instances to other hosts",
+						Description:  "serviced host maintenance enable HOSTID ...",
+						BashComplete: c.printHostsAll,
+						Action:       c.cmdHostMaintenanceEnable,
+					}, {
+						Name:         "disable",
+						Usage:        "Allows instances to be scheduled to a host again",
+						Description:  "serviced host maintenance disable HOSTID ...",
+						BashComplete: c.printHostsAll,
+						Action:       c.cmdHostMaintenanceDisable,
+					},
+				},
 			}, {
 				Name:        "register",
 				Usage:       "Set the authentication keys to use for this host. When KEYSFILE is -, read from stdin.",
@@ -115,6 +152,18 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host set-memory HOSTID ALLOCATION",
 				BashComplete: c.printHostsAll,
 				Action:       c.cmdHostSetMemory,
+			}, {
+				Name:         "set-labels",
+				Usage:        "Set the scheduling labels of a specific host",
+				Description:  "serviced host set-labels HOSTID [KEY=VALUE ...]",
+				BashComplete: c.printHostsAll,
+				Action:       c.cmdHostSetLabels,
+			}, {
+				Name:         "set-label",
+				Usage:        "Add, update or remove (KEY-) scheduling labels of a specific host",
+				Description:  "serviced host set-label HOSTID KEY=VALUE|KEY- ...",
+				BashComplete: c.printHostsAll,
+				Action:       c.cmdHostSetLabel,
 			},
 		},
 	})
@@ -202,7 +251,15 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 		return
 	}
 
-	hosts, err := c.driver.GetHostsWithAuthInfo()
+	var (
+		hosts []api.AuthHost
+		err   error
+	)
+	if poolID := ctx.String("pool"); poolID != "" {
+		hosts, err = c.driver.GetHostsWithAuthInfoInPool(poolID)
+	} else {
+		hosts, err = c.driver.GetHostsWithAuthInfo()
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
@@ -218,7 +275,11 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 			fmt.Println(string(jsonHost))
 		}
 	} else {
-		t := NewTable(ctx.String("show-fields"))
+		fields := ctx.String("show-fields")
+		if ctx.Bool("benchmarks") && !ctx.IsSet("show-fields") {
+			fields = hostBenchmarkFields
+		}
+		t := NewTable(fields)
 		for _, h := range hosts {
 			var usage string
 			if stats, err := c.driver.GetHostMemory(h.ID); err != nil {
@@ -226,7 +287,7 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 			} else {
 				usage = fmt.Sprintf("%s / %s / %s", bytefmt.ByteSize(uint64(stats.Last)), bytefmt.ByteSize(uint64(stats.Max)), bytefmt.ByteSize(uint64(stats.Average)))
 			}
-			t.AddRow(map[string]interface{}{
+			row := map[string]interface{}{
 				"ID":          h.ID,
 				"Auth":        h.Authenticated,
 				"Pool":        h.PoolID,
@@ -238,13 +299,42 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 				"Cur/Max/Avg": usage,
 				"Network":     h.PrivateNetwork,
 				"Release":     h.ServiceD.Release,
-			})
+			}
+			for k, v := range hostBenchmarkColumns(h.Benchmark) {
+				row[k] = v
+			}
+			t.AddRow(row)
 		}
 		t.Padding = 6
 		t.Print()
 	}
 }
 
+// hostBenchmarkFields are the fields shown by serviced host list --benchmarks
+const hostBenchmarkFields = "ID,Pool,Name,Addr,Cores,DiskRate,DiskLatency,NetRate,CPUScore,Benchmarked"
+
+// hostBenchmarkColumns returns the columns of the benchmark results of a host
+func hostBenchmarkColumns(b *host.Benchmark) map[string]interface{} {
+	columns := map[string]interface{}{
+		"DiskRate":    "--",
+		"DiskLatency": "--",
+		"NetRate":     "--",
+		"CPUScore":    "--",
+		"Benchmarked": "--",
+	}
+	if b == nil {
+		return columns
+	}
+	columns["DiskRate"] = bytefmt.ByteSize(b.DiskThroughput) + "/s"
+	columns["DiskLatency"] = b.DiskLatency.String()
+	if b.NetworkBandwidth > 0 {
+		columns["NetRate"] = bytefmt.ByteSize(b.NetworkBandwidth) + "/s"
+	}
+	columns["CPUScore"] = b.CPUScore
+	columns["Benchmarked"] = b.RanAt.Format(time.RFC3339)
+	return columns
+}
+
 // serviced host add HOST:PORT POOLID [--memory SIZE|%] [--nat-address HOST:PORT]
 func (c *ServicedCli) cmdHostAdd(ctx *cli.Context) {
 	args := ctx.Args()
@@ -297,10 +387,11 @@ func (c *ServicedCli) cmdHostAdd(ctx *cli.Context) {
 	}
 
 	cfg := api.HostConfig{
-		Address: &address,
-		Nat:     &nat,
-		PoolID:  args[1],
-		Memory:  ctx.String("memory"),
+		Address:   &address,
+		Nat:       &nat,
+		PoolID:    args[1],
+		Memory:    ctx.String("memory"),
+		Benchmark: ctx.Bool("benchmark"),
 	}
 
 	host, privateKey, err := c.driver.AddHost(cfg)
@@ -369,10 +460,11 @@ func (c *ServicedCli) cmdHostAddPrivate(ctx *cli.Context) {
 	}
 
 	cfg := api.HostConfig{
-		Address: &address,
-		Nat:     &nat,
-		PoolID:  args[1],
-		Memory:  ctx.String("memory"),
+		Address:   &address,
+		Nat:       &nat,
+		PoolID:    args[1],
+		Memory:    ctx.String("memory"),
+		Benchmark: ctx.Bool("benchmark"),
 	}
 
 	host, keyblock, err := c.driver.AddHostPrivate(cfg)
@@ -405,6 +497,33 @@ func (c *ServicedCli) cmdHostRemove(ctx *cli.Context) {
 	}
 }
 
+// serviced host maintenance enable HOSTID ...
+func (c *ServicedCli) cmdHostMaintenanceEnable(ctx *cli.Context) {
+	c.setHostMaintenance(ctx, "enable", true)
+}
+
+// serviced host maintenance disable HOSTID ...
+func (c *ServicedCli) cmdHostMaintenanceDisable(ctx *cli.Context) {
+	c.setHostMaintenance(ctx, "disable", false)
+}
+
+func (c *ServicedCli) setHostMaintenance(ctx *cli.Context, command string, enabled bool) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, command)
+		return
+	}
+
+	for _, id := range args {
+		if err := c.driver.SetHostMaintenance(id, enabled); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", id, err)
+		} else {
+			fmt.Println(id)
+		}
+	}
+}
+
 // serviced host set-memory HOSTID MEMALLOC
 func (c *ServicedCli) cmdHostSetMemory(ctx *cli.Context) {
 	args := ctx.Args()
@@ -419,6 +538,59 @@ func (c *ServicedCli) cmdHostSetMemory(ctx *cli.Context) {
 	}
 }
 
+// serviced host set-labels HOSTID [KEY=VALUE ...]
+func (c *ServicedCli) cmdHostSetLabels(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-labels")
+		return
+	}
+
+	labels := make(map[string]string)
+	for _, arg := range args[1:] {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			fmt.Fprintf(os.Stderr, "invalid label %s: expected KEY=VALUE\n", arg)
+			return
+		}
+		labels[parts[0]] = parts[1]
+	}
+
+	if err := c.driver.SetHostLabels(args[0], labels); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// serviced host set-label HOSTID KEY=VALUE|KEY- ...
+func (c *ServicedCli) cmdHostSetLabel(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-label")
+		return
+	}
+
+	labels := make(map[string]string)
+	remove := []string{}
+	for _, arg := range args[1:] {
+		if key := strings.TrimSuffix(arg, "-"); key != arg && key != "" && !strings.Contains(key, "=") {
+			remove = append(remove, key)
+			continue
+		}
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			fmt.Fprintf(os.Stderr, "invalid label %s: expected KEY=VALUE or KEY-\n", arg)
+			return
+		}
+		labels[parts[0]] = parts[1]
+	}
+
+	if err := c.driver.UpdateHostLabels(args[0], labels, remove); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
 // serviced host register (KEYSFILE | -)
 func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 	args := ctx.Args()
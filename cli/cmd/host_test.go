@@ -19,11 +19,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -41,6 +45,13 @@ var DefaultTestHosts = []host.Host{
 		Cores:          4,
 		Memory:         4 * 1024 * 1024 * 1024,
 		PrivateNetwork: "172.16.42.0/24",
+		Benchmark: &host.Benchmark{
+			DiskThroughput:   100 * 1024 * 1024,
+			DiskLatency:      2 * time.Millisecond,
+			NetworkBandwidth: 50 * 1024 * 1024,
+			CPUScore:         800,
+			RanAt:            time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
 	}, {
 		ID:             "test-host-id-2",
 		PoolID:         "default",
@@ -106,6 +117,10 @@ func (t HostAPITest) GetHost(id string) (*host.Host, error) {
 	return nil, nil
 }
 
+func (t HostAPITest) GetHostMemory(id string) (*metrics.MemoryUsageStats, error) {
+	return nil, ErrNoHostFound
+}
+
 func (t HostAPITest) AddHost(config api.HostConfig) (*host.Host, []byte, error) {
 	if t.fail {
 		return nil, nil, ErrInvalidHost
@@ -129,6 +144,40 @@ func (t HostAPITest) RemoveHost(id string) error {
 	return nil
 }
 
+func (t HostAPITest) SetHostMaintenance(id string, enabled bool) error {
+	if h, err := t.GetHost(id); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	return nil
+}
+
+func (t HostAPITest) SetHostLabels(id string, labels map[string]string) error {
+	if h, err := t.GetHost(id); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	fmt.Printf("%s: %d labels\n", id, len(labels))
+	return nil
+}
+
+func (t HostAPITest) UpdateHostLabels(id string, labels map[string]string, remove []string) error {
+	if h, err := t.GetHost(id); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	keys := []string{}
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("%s: set %v, remove %v\n", id, keys, remove)
+	return nil
+}
+
 func (t HostAPITest) RegisterRemoteHost(h *host.Host, nat utils.URL, data []byte, prompt bool) error {
 	if t.registerFail {
 		return errors.New("Forcing RemoteRegisterHost to fail for testing")
@@ -168,6 +217,19 @@ func (t HostAPITest) GetHostsWithAuthInfo() ([]api.AuthHost, error) {
 	return authHosts, nil
 }
 
+func (t HostAPITest) GetHostsWithAuthInfoInPool(poolID string) ([]api.AuthHost, error) {
+	if t.fail {
+		return nil, ErrInvalidHost
+	}
+	authHosts := []api.AuthHost{}
+	for _, h := range t.hosts {
+		if h.PoolID == poolID {
+			authHosts = append(authHosts, api.AuthHost{h, true})
+		}
+	}
+	return authHosts, nil
+}
+
 func TestServicedCLI_CmdHostList_one(t *testing.T) {
 	hostID := "test-host-id-1"
 
@@ -211,6 +273,35 @@ func TestServicedCLI_CmdHostList_all(t *testing.T) {
 	}
 }
 
+func TestServicedCLI_CmdHostList_benchmarks(t *testing.T) {
+	output := string(captureStdout(func() { InitHostAPITest("serviced", "host", "list", "--benchmarks") }))
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 hosts, got:\n%s", output)
+	}
+	for _, field := range strings.Split(hostBenchmarkFields, ",") {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("expected field %s in header %q", field, lines[0])
+		}
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "test-host-id-1":
+			for _, value := range []string{"100M/s", "2ms", "50M/s", "800", "2017-01-02T15:04:05Z"} {
+				if !strings.Contains(line, value) {
+					t.Errorf("expected %s in %q", value, line)
+				}
+			}
+		default:
+			if !strings.Contains(line, "--") {
+				t.Errorf("expected no benchmark in %q", line)
+			}
+		}
+	}
+}
+
 func ExampleServicedCLI_CmdHostList() {
 	// The result displays spaces at the end of each row, which gofmt cleans up
 	InitHostAPITest("serviced", "host", "list")
@@ -242,6 +333,20 @@ func ExampleServicedCLI_CmdHostList_err() {
 	// no hosts found
 }
 
+func ExampleServicedCLI_CmdHostList_pool() {
+	// No hosts in the pool
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "list", "--pool", "emptypool") })
+
+	DefaultHostAPITest.fail = true
+	defer func() { DefaultHostAPITest.fail = false }()
+	// Error retrieving the hosts in the pool
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "list", "--pool", "testpool") })
+
+	// Output:
+	// no hosts found
+	// invalid host
+}
+
 func ExampleServicedCLI_CmdHostList_complete() {
 	InitHostAPITest("serviced", "host", "list", "--generate-bash-completion")
 
@@ -400,6 +505,64 @@ func ExampleServicedCLI_CmdHostRemove_err() {
 	// test-host-id-0: no host found
 }
 
+func ExampleServicedCLI_CmdHostMaintenanceEnable() {
+	InitHostAPITest("serviced", "host", "maintenance", "enable", "test-host-id-1", "test-host-id-2")
+
+	// Output:
+	// test-host-id-1
+	// test-host-id-2
+}
+
+func ExampleServicedCLI_CmdHostMaintenanceEnable_err() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "maintenance", "enable", "test-host-id-0") })
+
+	// Output:
+	// test-host-id-0: no host found
+}
+
+func ExampleServicedCLI_CmdHostMaintenanceDisable() {
+	InitHostAPITest("serviced", "host", "maintenance", "disable", "test-host-id-3")
+
+	// Output:
+	// test-host-id-3
+}
+
+func ExampleServicedCLI_CmdHostSetLabels() {
+	InitHostAPITest("serviced", "host", "set-labels", "test-host-id-1", "zone=east", "disk=ssd")
+	InitHostAPITest("serviced", "host", "set-labels", "test-host-id-2")
+
+	// Output:
+	// test-host-id-1: 2 labels
+	// test-host-id-2: 0 labels
+}
+
+func ExampleServicedCLI_CmdHostSetLabels_err() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "set-labels", "test-host-id-1", "zone") })
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "set-labels", "test-host-id-0", "zone=east") })
+
+	// Output:
+	// invalid label zone: expected KEY=VALUE
+	// no host found
+}
+
+func ExampleServicedCLI_CmdHostSetLabel() {
+	InitHostAPITest("serviced", "host", "set-label", "test-host-id-1", "gpu=nvidia", "disk=ssd")
+	InitHostAPITest("serviced", "host", "set-label", "test-host-id-2", "disk-", "zone=east")
+
+	// Output:
+	// test-host-id-1: set [disk gpu], remove []
+	// test-host-id-2: set [zone], remove [disk]
+}
+
+func ExampleServicedCLI_CmdHostSetLabel_err() {
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "set-label", "test-host-id-1", "gpu") })
+	pipeStderr(func() { InitHostAPITest("serviced", "host", "set-label", "test-host-id-0", "gpu=nvidia") })
+
+	// Output:
+	// invalid label gpu: expected KEY=VALUE or KEY-
+	// no host found
+}
+
 func ExampleServicedCLI_CmdHostRemove_complete() {
 	InitHostAPITest("serviced", "host", "rm", "--generate-bash-completion")
 	fmt.Println("")
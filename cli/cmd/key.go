@@ -14,13 +14,18 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
@@ -61,6 +66,48 @@ func (c *ServicedCli) initKey() {
 						Usage: "Register delegate keys on the host via ssh",
 					},
 				},
+			}, {
+				Name:        "distribute",
+				Usage:       "Regenerate and install the keys of the hosts listed in an inventory file via ssh",
+				Description: "serviced key distribute INVENTORY",
+				Action:      c.cmdKeyDistribute,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "retries",
+						Value: 2,
+						Usage: "Number of times to retry a failed ssh connection to a host",
+					},
+					cli.IntFlag{
+						Name:  "retry-delay",
+						Value: 5,
+						Usage: "Seconds to wait between attempts",
+					},
+					cli.StringFlag{
+						Name:  "install-path",
+						Value: "",
+						Usage: "Install the keys at this path instead of registering them with serviced; may refer to {{.HostID}} and {{.IPAddr}}",
+					},
+					cli.StringFlag{
+						Name:  "mode",
+						Value: "",
+						Usage: "Octal permissions of the installed key file",
+					},
+					cli.StringFlag{
+						Name:  "owner",
+						Value: "",
+						Usage: "Owner of the installed key file",
+					},
+					cli.StringFlag{
+						Name:  "group",
+						Value: "",
+						Usage: "Group of the installed key file",
+					},
+					cli.StringFlag{
+						Name:  "key-dir",
+						Value: ".",
+						Usage: "Directory for the key files of hosts that could not be reached",
+					},
+				},
 			},
 		},
 	}
@@ -92,31 +139,10 @@ func (c *ServicedCli) cmdKeyReset(ctx *cli.Context) {
 	}
 
 	// Parse/resolve the NAT address, if provided.
-	var nat utils.URL
-	natString := ctx.String("nat-address")
-	if len(natString) > 0 {
-		// Both host or host:port are accepted since the host portion is the only thing used
-		// for key reset.  If they don't provide the port, append ":0" so the host is parsed properly.
-		if !strings.Contains(natString, ":") {
-			natString += ":0"
-		}
-		if err := nat.Set(natString); err != nil {
-			fmt.Println(err)
-			return
-		}
-		if natip := net.ParseIP(nat.Host); natip == nil {
-			// NAT did not parse, try resolving
-			addr, err := net.ResolveIPAddr("ip", nat.Host) // unknown network tcp
-			if err != nil {
-				fmt.Printf("Could not resolve nat address (%s): %s\n", nat.Host, err)
-				return
-			}
-			nat.Host = addr.IP.String()
-		}
-		if strings.HasPrefix(nat.Host, "127.") {
-			fmt.Printf("The nat address %s must not resolve to a loopback address\n", natString)
-			return
-		}
+	nat, err := parseKeyNatAddress(ctx.String("nat-address"))
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
 
 	hostID := args[0]
@@ -172,6 +198,157 @@ func (c *ServicedCli) outputDelegateKey(host *host.Host, nat utils.URL, keyData
 	fmt.Println(host.ID)
 }
 
+// parseKeyNatAddress parses and resolves the NAT address of a delegate.  Both
+// host or host:port are accepted since the host portion is the only thing used
+// for registering keys.
+func parseKeyNatAddress(natString string) (utils.URL, error) {
+	var nat utils.URL
+	if len(natString) == 0 {
+		return nat, nil
+	}
+	// If they don't provide the port, append ":0" so the host is parsed properly.
+	if !strings.Contains(natString, ":") {
+		natString += ":0"
+	}
+	if err := nat.Set(natString); err != nil {
+		return nat, err
+	}
+	if natip := net.ParseIP(nat.Host); natip == nil {
+		// NAT did not parse, try resolving
+		addr, err := net.ResolveIPAddr("ip", nat.Host) // unknown network tcp
+		if err != nil {
+			return nat, fmt.Errorf("Could not resolve nat address (%s): %s", nat.Host, err)
+		}
+		nat.Host = addr.IP.String()
+	}
+	if strings.HasPrefix(nat.Host, "127.") {
+		return nat, fmt.Errorf("The nat address %s must not resolve to a loopback address", natString)
+	}
+	return nat, nil
+}
+
+// readKeyInventory reads the hosts of a key inventory file.  Each line holds
+// a host ID, optionally followed by the NAT address of the host.  Blank lines
+// and lines starting with # are ignored.
+func readKeyInventory(r io.Reader) ([]api.DelegateKeyTarget, error) {
+	var targets []api.DelegateKeyTarget
+	scanner := bufio.NewScanner(r)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected HOSTID [NAT-ADDRESS]", lineno)
+		}
+		target := api.DelegateKeyTarget{HostID: fields[0]}
+		if len(fields) == 2 {
+			nat, err := parseKeyNatAddress(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", lineno, err)
+			}
+			target.Nat = nat
+		}
+		targets = append(targets, target)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// serviced key distribute INVENTORY
+func (c *ServicedCli) cmdKeyDistribute(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "distribute")
+		return
+	}
+
+	var (
+		targets []api.DelegateKeyTarget
+		err     error
+	)
+	switch args[0] {
+	case "-":
+		targets, err = readKeyInventory(os.Stdin)
+	default:
+		var f *os.File
+		if f, err = os.Open(args[0]); err == nil {
+			targets, err = readKeyInventory(f)
+			f.Close()
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "no hosts found in", args[0])
+		c.exit(1)
+		return
+	}
+
+	opts := api.DelegateKeyOptions{
+		Retries:    ctx.Int("retries"),
+		RetryDelay: time.Duration(ctx.Int("retry-delay")) * time.Second,
+		Prompt:     len(targets) == 1 && utils.Isatty(os.Stdin) && utils.Isatty(os.Stdout),
+	}
+	if path := ctx.String("install-path"); path != "" {
+		opts.Template = &auth.KeyFileTemplate{
+			Path:  path,
+			Owner: ctx.String("owner"),
+			Group: ctx.String("group"),
+		}
+		if mode := ctx.String("mode"); mode != "" {
+			m, err := strconv.ParseUint(mode, 8, 32)
+			if err != nil || m > 0777 {
+				fmt.Fprintf(os.Stderr, "Invalid file mode %q\n", mode)
+				c.exit(1)
+				return
+			}
+			opts.Template.Mode = os.FileMode(m)
+		}
+	} else if ctx.String("mode") != "" || ctx.String("owner") != "" || ctx.String("group") != "" {
+		fmt.Fprintln(os.Stderr, "--mode, --owner and --group require --install-path")
+		c.exit(1)
+		return
+	}
+
+	results := c.driver.DistributeDelegateKeys(targets, opts)
+	failed := 0
+	t := NewTable("Host,Address,Attempts,Result")
+	for _, result := range results {
+		status := "registered"
+		if result.Err != nil {
+			failed++
+			status = result.Err.Error()
+			// The keys were reset, so keep them around to install by hand
+			if len(result.KeyData) > 0 {
+				keyfileName := filepath.Join(ctx.String("key-dir"), fmt.Sprintf("IP-%s.delegate.key", strings.Replace(result.IPAddr, ".", "-", -1)))
+				if err := c.driver.WriteDelegateKey(keyfileName, result.KeyData); err != nil {
+					fmt.Fprintf(os.Stderr, "Error writing delegate key file \"%s\": %s\n", keyfileName, err)
+				} else {
+					status += "; wrote " + keyfileName
+				}
+			}
+		}
+		t.AddRow(map[string]interface{}{
+			"Host":     result.HostID,
+			"Address":  result.IPAddr,
+			"Attempts": result.Attempts,
+			"Result":   status,
+		})
+	}
+	t.Print()
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Could not register %d of %d hosts\n", failed, len(results))
+		c.exit(1)
+	}
+}
+
 // Registers a host with the given keydata, and stores the key at the location designated by auth.DelegateKeyFileName
 func (c *ServicedCli) outputCommonKey(host *host.Host, nat utils.URL, keyData []byte) {
 	keyfileName := filepath.Join(config.GetOptions().EtcPath, auth.DelegateKeyFileName)
@@ -17,9 +17,13 @@ package cmd
 
 import (
 	"errors"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/cli/api/apimocks"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
@@ -120,3 +124,39 @@ func (s *mySuite) Test_outputDelegateKey_registerfail_keyfile(c *C) {
 	s.cli.outputDelegateKey(&testHost, nat, testKeyData, keyfileName, true)
 	s.api.AssertExpectations(c)
 }
+
+func (s *mySuite) Test_readKeyInventory(c *C) {
+	inventory := "# hosts\n\nhost-a\nhost-b 10.1.2.3\n"
+	targets, err := readKeyInventory(strings.NewReader(inventory))
+	c.Assert(err, IsNil)
+	c.Assert(targets, HasLen, 2)
+	c.Assert(targets[0].HostID, Equals, "host-a")
+	c.Assert(targets[0].Nat.Host, Equals, "")
+	c.Assert(targets[1].HostID, Equals, "host-b")
+	c.Assert(targets[1].Nat.Host, Equals, "10.1.2.3")
+
+	_, err = readKeyInventory(strings.NewReader("host-a 10.1.2.3 extra\n"))
+	c.Assert(err, ErrorMatches, "line 1: .*")
+}
+
+func (s *mySuite) Test_cmdKeyDistribute(c *C) {
+	inventory := filepath.Join(c.MkDir(), "inventory")
+	err := ioutil.WriteFile(inventory, []byte(testHost.ID+"\nhost-b\n"), 0644)
+	c.Assert(err, IsNil)
+
+	targets := []api.DelegateKeyTarget{{HostID: testHost.ID}, {HostID: "host-b"}}
+	opts := api.DelegateKeyOptions{
+		Template:   &auth.KeyFileTemplate{Path: "/etc/serviced/{{.HostID}}.keys", Mode: 0600, Owner: "root"},
+		Retries:    1,
+		RetryDelay: 0,
+	}
+	s.api.On("DistributeDelegateKeys", targets, opts).Return([]api.DelegateKeyResult{
+		{HostID: testHost.ID, IPAddr: testHost.IPAddr, Attempts: 1, KeyData: testKeyData},
+		{HostID: "host-b", IPAddr: "192.168.0.2", Attempts: 2, KeyData: testKeyData, Err: errors.New("woot")},
+	})
+	s.api.On("WriteDelegateKey", "IP-192-168-0-2.delegate.key", testKeyData).Return(nil)
+	s.cli.exitDisabled = true
+	s.cli.Run([]string{"serviced", "key", "distribute", "--retries", "1", "--retry-delay", "0",
+		"--install-path", "/etc/serviced/{{.HostID}}.keys", "--mode", "600", "--owner", "root", inventory})
+	s.api.AssertExpectations(c)
+}
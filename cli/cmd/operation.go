@@ -0,0 +1,181 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/dao"
+)
+
+// Initializer for serviced op subcommands
+func (c *ServicedCli) initOperation() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "op",
+		Usage:       "Follows and cancels the backups, restores, template deploys, registry syncs and rebalances of the master",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "list",
+				Usage:       "Lists the operations that are running or recently ended",
+				Description: "serviced op list",
+				Action:      c.cmdOperationList,
+			}, {
+				Name:         "status",
+				Usage:        "Shows the status, progress and logs of an operation",
+				Description:  "serviced op status OPERATIONID",
+				BashComplete: c.printRunningOperationsFirst,
+				Action:       c.cmdOperationStatus,
+			}, {
+				Name:         "cancel",
+				Usage:        "Asks a running operation to stop at its next safe point",
+				Description:  "serviced op cancel OPERATIONID",
+				BashComplete: c.printRunningOperationsFirst,
+				Action:       c.cmdOperationCancel,
+			},
+		},
+	})
+}
+
+// Bash-completion command that prints the running operations as the first
+// argument
+func (c *ServicedCli) printRunningOperationsFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	ops, err := c.driver.GetOperations()
+	if err != nil {
+		return
+	}
+	for _, op := range ops {
+		if op.State == "running" {
+			fmt.Println(op.ID)
+		}
+	}
+}
+
+// formatOperationProgress returns the progress of an operation, or an empty
+// string if it is unknown
+func formatOperationProgress(op dao.Operation) string {
+	if op.Progress < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", op.Progress)
+}
+
+// formatOperationTime returns a time in RFC3339, or an empty string if it is
+// not set
+func formatOperationTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// serviced op list
+func (c *ServicedCli) cmdOperationList(ctx *cli.Context) {
+	ops, err := c.driver.GetOperations()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(ops) == 0 {
+		fmt.Fprintln(os.Stderr, "no operations found")
+		return
+	}
+
+	t := NewTable("ID,Type,Target,User,State,Progress,Started,Ended")
+	for _, op := range ops {
+		state := op.State
+		if op.CancelRequested && op.State == "running" {
+			state = "canceling"
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":       op.ID,
+			"Type":     op.Type,
+			"Target":   op.Target,
+			"User":     op.User,
+			"State":    state,
+			"Progress": formatOperationProgress(op),
+			"Started":  formatOperationTime(op.StartedAt),
+			"Ended":    formatOperationTime(op.EndedAt),
+		})
+	}
+	t.Print()
+}
+
+// serviced op status OPERATIONID
+func (c *ServicedCli) cmdOperationStatus(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "status")
+		c.exit(1)
+		return
+	}
+	op, err := c.driver.GetOperation(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	state := op.State
+	if op.CancelRequested && op.State == "running" {
+		state += " (cancel requested)"
+	}
+	progress := strings.TrimSpace(formatOperationProgress(*op) + " " + op.Message)
+	fmt.Printf("ID:       %s\n", op.ID)
+	fmt.Printf("Type:     %s\n", op.Type)
+	fmt.Printf("Target:   %s\n", op.Target)
+	fmt.Printf("User:     %s\n", op.User)
+	fmt.Printf("State:    %s\n", state)
+	if progress != "" {
+		fmt.Printf("Progress: %s\n", progress)
+	}
+	fmt.Printf("Started:  %s\n", formatOperationTime(op.StartedAt))
+	if !op.EndedAt.IsZero() {
+		fmt.Printf("Ended:    %s\n", formatOperationTime(op.EndedAt))
+	}
+	if op.Error != "" {
+		fmt.Printf("Error:    %s\n", op.Error)
+	}
+	if len(op.Logs) > 0 {
+		fmt.Println("Logs:")
+		for _, line := range op.Logs {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+}
+
+// serviced op cancel OPERATIONID
+func (c *ServicedCli) cmdOperationCancel(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "cancel")
+		c.exit(1)
+		return
+	}
+	if err := c.driver.CancelOperation(args[0]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(args[0])
+}
@@ -0,0 +1,140 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/utils"
+)
+
+var errOperationNotFound = errors.New("operation not found")
+
+type OperationAPITest struct {
+	api.API
+	ops []dao.Operation
+}
+
+func (t OperationAPITest) GetOperations() ([]dao.Operation, error) {
+	return t.ops, nil
+}
+
+func (t OperationAPITest) GetOperation(id string) (*dao.Operation, error) {
+	for _, op := range t.ops {
+		if op.ID == id {
+			return &op, nil
+		}
+	}
+	return nil, errOperationNotFound
+}
+
+func (t OperationAPITest) CancelOperation(id string) error {
+	_, err := t.GetOperation(id)
+	return err
+}
+
+func runOperationCmd(t OperationAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+var testOperations = []dao.Operation{
+	{
+		ID:        "1",
+		Type:      "rebalance",
+		Target:    "svc1",
+		User:      "bob",
+		State:     "failed",
+		Progress:  -1,
+		Error:     "service not found",
+		StartedAt: time.Date(2017, 6, 1, 12, 1, 0, 0, time.UTC),
+		EndedAt:   time.Date(2017, 6, 1, 12, 2, 0, 0, time.UTC),
+	}, {
+		ID:        "2",
+		Type:      "backup",
+		Target:    "/backups",
+		User:      "alice",
+		State:     "running",
+		Progress:  25,
+		Message:   "exporting volumes",
+		Logs:      []string{"2017-06-01T12:03:00Z Started backup"},
+		StartedAt: time.Date(2017, 6, 1, 12, 3, 0, 0, time.UTC),
+	},
+}
+
+func ExampleServicedCLI_CmdOperationList() {
+	runOperationCmd(OperationAPITest{ops: testOperations}, "serviced", "op", "list")
+
+	// Output:
+	// ID Type      Target   User  State   Progress Started              Ended
+	// 1  rebalance svc1     bob   failed           2017-06-01T12:01:00Z 2017-06-01T12:02:00Z
+	// 2  backup    /backups alice running 25%      2017-06-01T12:03:00Z
+}
+
+func ExampleServicedCLI_CmdOperationList_none() {
+	pipeStderr(func() { runOperationCmd(OperationAPITest{}, "serviced", "op", "list") })
+
+	// Output:
+	// no operations found
+}
+
+func ExampleServicedCLI_CmdOperationStatus() {
+	runOperationCmd(OperationAPITest{ops: testOperations}, "serviced", "op", "status", "2")
+
+	// Output:
+	// ID:       2
+	// Type:     backup
+	// Target:   /backups
+	// User:     alice
+	// State:    running
+	// Progress: 25% exporting volumes
+	// Started:  2017-06-01T12:03:00Z
+	// Logs:
+	//   2017-06-01T12:03:00Z Started backup
+}
+
+func ExampleServicedCLI_CmdOperationStatus_failed() {
+	runOperationCmd(OperationAPITest{ops: testOperations}, "serviced", "op", "status", "1")
+
+	// Output:
+	// ID:       1
+	// Type:     rebalance
+	// Target:   svc1
+	// User:     bob
+	// State:    failed
+	// Started:  2017-06-01T12:01:00Z
+	// Ended:    2017-06-01T12:02:00Z
+	// Error:    service not found
+}
+
+func ExampleServicedCLI_CmdOperationCancel() {
+	runOperationCmd(OperationAPITest{ops: testOperations}, "serviced", "op", "cancel", "2")
+
+	// Output:
+	// 2
+}
+
+func ExampleServicedCLI_CmdOperationCancel_notFound() {
+	pipeStderr(func() { runOperationCmd(OperationAPITest{ops: testOperations}, "serviced", "op", "cancel", "3") })
+
+	// Output:
+	// operation not found
+}
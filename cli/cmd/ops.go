@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced ops subcommands
+func (c *ServicedCli) initOps() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "ops",
+		Usage:       "Shows the snapshots, rollbacks, backups, restores and image pushes that are running or queued",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "list",
+				Usage:       "Lists the running and queued operations, in the order that they were submitted",
+				Description: "serviced ops list",
+				Action:      c.cmdOpsList,
+			},
+		},
+	})
+}
+
+// serviced ops list
+func (c *ServicedCli) cmdOpsList(ctx *cli.Context) {
+	ops, err := c.driver.GetDFSOperations()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(ops) == 0 {
+		fmt.Fprintln(os.Stderr, "no operations are running or queued")
+		return
+	}
+
+	t := NewTable("ID,Type,Target,Pools,User,State,Position,Submitted,Started")
+	for _, op := range ops {
+		position, started := "", ""
+		if op.Position > 0 {
+			position = strconv.Itoa(op.Position)
+		}
+		if !op.StartedAt.IsZero() {
+			started = op.StartedAt.Format(time.RFC3339)
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":        op.ID,
+			"Type":      op.Type,
+			"Target":    op.Target,
+			"Pools":     strings.Join(op.PoolIDs, ","),
+			"User":      op.User,
+			"State":     op.State,
+			"Position":  position,
+			"Submitted": op.SubmittedAt.Format(time.RFC3339),
+			"Started":   started,
+		})
+	}
+	t.Print()
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/utils"
+)
+
+type OpsAPITest struct {
+	api.API
+	ops []dao.DFSOperation
+}
+
+func (t OpsAPITest) GetDFSOperations() ([]dao.DFSOperation, error) {
+	return t.ops, nil
+}
+
+func runOpsCmd(t OpsAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdOpsList() {
+	submitted := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	runOpsCmd(OpsAPITest{ops: []dao.DFSOperation{
+		{
+			ID:          "1",
+			Type:        "snapshot",
+			Target:      "tenant1",
+			PoolIDs:     []string{"default"},
+			User:        "alice",
+			State:       "running",
+			SubmittedAt: submitted,
+			StartedAt:   submitted,
+		}, {
+			ID:          "2",
+			Type:        "backup",
+			Target:      "/backups",
+			PoolIDs:     []string{"default", "remote"},
+			User:        "bob",
+			State:       "queued",
+			Position:    1,
+			SubmittedAt: submitted.Add(time.Minute),
+		},
+	}}, "serviced", "ops", "list")
+
+	// Output:
+	// ID Type     Target   Pools          User  State   Position Submitted            Started
+	// 1  snapshot tenant1  default        alice running          2017-06-01T12:00:00Z 2017-06-01T12:00:00Z
+	// 2  backup   /backups default,remote bob   queued  1        2017-06-01T12:01:00Z
+}
+
+func ExampleServicedCLI_CmdOpsList_none() {
+	pipeStderr(func() { runOpsCmd(OpsAPITest{}, "serviced", "ops", "list") })
+
+	// Output:
+	// no operations are running or queued
+}
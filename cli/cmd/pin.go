@@ -0,0 +1,169 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/pin"
+)
+
+// Initializer for serviced pin subcommands
+func (c *ServicedCli) initPin() {
+	reasonFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "reason",
+			Value: "",
+			Usage: "Why the object is pinned",
+		},
+	}
+
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "pin",
+		Usage:       "Protects snapshots and images from cleanup",
+		Description: "Pinned snapshots and image tags are kept by the snapshot TTL and registry cleanup, and cannot be deleted until they are unpinned",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the pinned snapshots and images",
+				Description:  "serviced pin list",
+				BashComplete: nil,
+				Action:       c.cmdPinList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "snapshot",
+				Usage:        "Pins a snapshot",
+				Description:  "serviced pin snapshot [--reason REASON] SNAPSHOTID",
+				BashComplete: c.printSnapshotsFirst,
+				Action:       c.cmdPinSnapshot,
+				Flags:        reasonFlags,
+			}, {
+				Name:         "image",
+				Usage:        "Pins a tag of an image in the docker registry",
+				Description:  "serviced pin image [--reason REASON] TENANTID/REPO:TAG",
+				BashComplete: nil,
+				Action:       c.cmdPinImage,
+				Flags:        reasonFlags,
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes the pin of a snapshot or image",
+				Description:  "serviced pin remove snapshot|image TARGET",
+				BashComplete: c.printPinKindsFirst,
+				Action:       c.cmdPinRemove,
+			},
+		},
+	})
+}
+
+// printPinKindsFirst is the completion action for the first argument
+func (c *ServicedCli) printPinKindsFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	for _, kind := range pin.Kinds {
+		fmt.Println(kind)
+	}
+}
+
+// serviced pin list [--verbose]
+func (c *ServicedCli) cmdPinList(ctx *cli.Context) {
+	pins, err := c.driver.GetPins()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(pins) == 0 {
+		fmt.Fprintln(os.Stderr, "no pins found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonPins, err := json.MarshalIndent(pins, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal pins: %s", err)
+		} else {
+			fmt.Println(string(jsonPins))
+		}
+		return
+	}
+
+	t := NewTable("Kind,Target,TenantID,Created,Reason")
+	t.Padding = 6
+	for _, p := range pins {
+		t.AddRow(map[string]interface{}{
+			"Kind":     p.Kind,
+			"Target":   p.Target,
+			"TenantID": p.TenantID,
+			"Created":  p.CreatedAt.Format(time.RFC3339),
+			"Reason":   p.Reason,
+		})
+	}
+	t.Print()
+}
+
+// serviced pin snapshot [--reason REASON] SNAPSHOTID
+func (c *ServicedCli) cmdPinSnapshot(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "snapshot")
+		return
+	}
+
+	if err := c.driver.PinSnapshot(args[0], ctx.String("reason")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced pin image [--reason REASON] TENANTID/REPO:TAG
+func (c *ServicedCli) cmdPinImage(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "image")
+		return
+	}
+
+	if err := c.driver.PinImage(args[0], ctx.String("reason")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced pin remove snapshot|image TARGET
+func (c *ServicedCli) cmdPinRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	if err := c.driver.Unpin(pin.Kind(args[0]), args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[1])
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/pin"
+	"github.com/control-center/serviced/utils"
+)
+
+type PinAPITest struct {
+	api.API
+	pins *[]pin.Pin
+}
+
+func NewPinAPITest() PinAPITest {
+	return PinAPITest{pins: &[]pin.Pin{}}
+}
+
+func (t PinAPITest) GetPins() ([]pin.Pin, error) {
+	return *t.pins, nil
+}
+
+func (t PinAPITest) add(kind pin.Kind, target, reason string) {
+	p := pin.New(kind, target, "tenant", reason)
+	p.CreatedAt = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	*t.pins = append(*t.pins, *p)
+}
+
+func (t PinAPITest) PinSnapshot(snapshotID, reason string) error {
+	t.add(pin.KindSnapshot, snapshotID, reason)
+	return nil
+}
+
+func (t PinAPITest) PinImage(image, reason string) error {
+	t.add(pin.KindImage, image, reason)
+	return nil
+}
+
+func (t PinAPITest) Unpin(kind pin.Kind, target string) error {
+	for i, p := range *t.pins {
+		if p.Kind == kind && p.Target == target {
+			*t.pins = append((*t.pins)[:i], (*t.pins)[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("pin not found")
+}
+
+func runPinCmd(t PinAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdPinList() {
+	test := NewPinAPITest()
+	runPinCmd(test, "serviced", "pin", "snapshot", "--reason", "audit", "tenant_20170101-000000")
+	runPinCmd(test, "serviced", "pin", "image", "tenant/repo:latest")
+	runPinCmd(test, "serviced", "pin", "list")
+
+	// Output:
+	// tenant_20170101-000000
+	// tenant/repo:latest
+	// Kind          Target                      TenantID      Created                   Reason
+	// snapshot      tenant_20170101-000000      tenant        2017-01-01T00:00:00Z      audit
+	// image         tenant/repo:latest          tenant        2017-01-01T00:00:00Z
+}
+
+func ExampleServicedCLI_CmdPinRemove() {
+	test := NewPinAPITest()
+	runPinCmd(test, "serviced", "pin", "image", "tenant/repo:latest")
+	runPinCmd(test, "serviced", "pin", "remove", "image", "tenant/repo:latest")
+	pipeStderr(func() { runPinCmd(test, "serviced", "pin", "list") })
+
+	// Output:
+	// tenant/repo:latest
+	// tenant/repo:latest
+	// no pins found
+}
+
+func ExampleServicedCLI_CmdPinRemove_err() {
+	pipeStderr(func() { runPinCmd(NewPinAPITest(), "serviced", "pin", "remove", "snapshot", "nosnapshot") })
+
+	// Output:
+	// pin not found
+}
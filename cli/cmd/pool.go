@@ -17,6 +17,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -65,6 +66,18 @@ func (c *ServicedCli) initPool() {
 						Name:  "admin",
 						Usage: "Allow pool to use administrative functions",
 					},
+					cli.BoolFlag{
+						Name:  "operator",
+						Usage: "Allow pool to start, stop and restart services",
+					},
+					cli.BoolFlag{
+						Name:  "read-only",
+						Usage: "Allow pool to view the cluster",
+					},
+					cli.BoolFlag{
+						Name:  "interactive",
+						Usage: "Allow pool to open shells in, attach to and run actions in services",
+					},
 				},
 			}, {
 				Name:         "remove",
@@ -102,12 +115,70 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool remove-virtual-ip POOLID IPADDRESS",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdRemoveVirtualIP,
+			}, {
+				Name:         "set-vip-check",
+				Usage:        "Set the interval between reachability checks of a resource pool's virtual IPs (e.g. 10s, 1m; 0 = disabled)",
+				Description:  "serviced pool set-vip-check POOLID INTERVAL",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetVirtualIPCheck,
+			}, {
+				Name:         "vip-events",
+				Usage:        "Show the reachability and failover events of a resource pool's virtual IPs",
+				Description:  "serviced pool vip-events POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdVirtualIPEvents,
 			}, {
 				Name:         "set-conn-timeout",
 				Usage:        "Set a connection timeout for a high latency resource pool (e.g. 5m, 2h, 6.6s)",
 				Description:  "serviced pool set-conn-timeout POOLID TIMEOUT",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdSetConnTimeout,
+			}, {
+				Name:         "set-strategy",
+				Usage:        "Set the scheduling strategy of a resource pool (spread, binpack, label-affinity)",
+				Description:  "serviced pool set-strategy POOLID STRATEGY",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetStrategy,
+			}, {
+				Name:         "set-retention",
+				Usage:        "Set the days to keep the logs and metrics of a resource pool's services (0 = cluster default)",
+				Description:  "serviced pool set-retention [FLAGS] POOLID",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetRetention,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "logs",
+						Usage: "Days to keep application logs",
+					},
+					cli.IntFlag{
+						Name:  "metrics",
+						Usage: "Days to keep metrics",
+					},
+				},
+			}, {
+				Name:         "set-dfs-ops",
+				Usage:        "Set how many snapshots, rollbacks, backups and image pushes of a resource pool's applications run at once (0 = unlimited)",
+				Description:  "serviced pool set-dfs-ops POOLID LIMIT",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetDFSOperations,
+			}, {
+				Name:         "set-cross-pool",
+				Usage:        "Set whether services in other pools may import a resource pool's endpoints (allow, restrict)",
+				Description:  "serviced pool set-cross-pool [--import APPLICATION[=POOLID,...]] POOLID POLICY",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetCrossPool,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "import",
+						Value: &cli.StringSlice{},
+						Usage: "Application pattern that services in other pools, or only in the listed pools, may import under the restrict policy; replaces the current rules",
+					},
+				},
+			}, {
+				Name:        "cross-pool-flows",
+				Usage:       "Show the running imports of endpoints that services in other pools export",
+				Description: "serviced pool cross-pool-flows",
+				Action:      c.cmdCrossPoolFlows,
 			}, {
 				Name:         "set-permission",
 				Usage:        "Set permission flags for hosts in a pool",
@@ -123,6 +194,18 @@ func (c *ServicedCli) initPool() {
 						Name:  "admin",
 						Usage: "Control permission to use administrative functions",
 					},
+					cli.BoolFlag{
+						Name:  "operator",
+						Usage: "Control permission to start, stop and restart services",
+					},
+					cli.BoolFlag{
+						Name:  "read-only",
+						Usage: "Control permission to view the cluster",
+					},
+					cli.BoolFlag{
+						Name:  "interactive",
+						Usage: "Control permission to open shells in, attach to and run actions in services",
+					},
 				},
 			},
 		},
@@ -212,6 +295,15 @@ func (c *ServicedCli) cmdPoolList(ctx *cli.Context) {
 			if p.HasAdminAccess() {
 				perms = append(perms, "Admin")
 			}
+			if p.HasOperatorAccess() {
+				perms = append(perms, "Operator")
+			}
+			if p.HasReadOnlyAccess() {
+				perms = append(perms, "ReadOnly")
+			}
+			if p.Permissions&pool.InteractiveAccess != 0 {
+				perms = append(perms, "Interactive")
+			}
 			t.AddRow(map[string]interface{}{
 				"ID":          p.ID,
 				"Permissions": perms,
@@ -260,6 +352,9 @@ func (c *ServicedCli) cmdPoolAdd(ctx *cli.Context) {
 	}
 	updatePerms("dfs", pool.DFSAccess)
 	updatePerms("admin", pool.AdminAccess)
+	updatePerms("operator", pool.OperatorAccess)
+	updatePerms("read-only", pool.ReadOnlyAccess)
+	updatePerms("interactive", pool.InteractiveAccess)
 
 	if pool, err := c.driver.AddResourcePool(cfg); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -369,6 +464,71 @@ func (c *ServicedCli) cmdRemoveVirtualIP(ctx *cli.Context) {
 	}
 }
 
+// serviced pool set-vip-check POOLID INTERVAL
+func (c *ServicedCli) cmdSetVirtualIPCheck(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-vip-check")
+		return
+	}
+
+	interval, err := time.ParseDuration(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse duration: %s\n", err)
+		return
+	} else if interval < 0 {
+		fmt.Fprintln(os.Stderr, "duration cannot be negative")
+		return
+	}
+
+	pool, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if pool == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	pool.VirtualIPCheckInterval = int(interval.Seconds() * 1000)
+	if err := c.driver.UpdateResourcePool(*pool); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool vip-events POOLID
+func (c *ServicedCli) cmdVirtualIPEvents(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "vip-events")
+		return
+	}
+
+	events, err := c.driver.GetVirtualIPEvents(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(events) == 0 {
+		fmt.Fprintln(os.Stderr, "no virtual ip events found")
+		return
+	}
+
+	t := NewTable("Time,Type,IPAddress,HostID,Message")
+	for _, evt := range events {
+		t.AddRow(map[string]interface{}{
+			"Time":      evt.Timestamp.Format(time.RFC3339),
+			"Type":      evt.Type,
+			"IPAddress": evt.IPAddress,
+			"HostID":    evt.HostID,
+			"Message":   evt.Message,
+		})
+	}
+	t.Print()
+}
+
 // serviced pool set-conn-timeout POOLID TIMEOUT
 func (c *ServicedCli) cmdSetConnTimeout(ctx *cli.Context) {
 	args := ctx.Args()
@@ -403,6 +563,175 @@ func (c *ServicedCli) cmdSetConnTimeout(ctx *cli.Context) {
 	}
 }
 
+// serviced pool set-strategy POOLID STRATEGY
+func (c *ServicedCli) cmdSetStrategy(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-strategy")
+		return
+	}
+
+	switch args[1] {
+	case pool.StrategySpread, pool.StrategyBinpack, pool.StrategyLabelAffinity:
+	default:
+		fmt.Fprintf(os.Stderr, "invalid scheduling strategy: %s\n", args[1])
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.SchedulingStrategy = args[1]
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-retention [--logs DAYS] [--metrics DAYS] POOLID
+func (c *ServicedCli) cmdSetRetention(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-retention")
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	if ctx.IsSet("logs") {
+		p.LogRetentionDays = ctx.Int("logs")
+	}
+	if ctx.IsSet("metrics") {
+		p.MetricRetentionDays = ctx.Int("metrics")
+	}
+	if p.LogRetentionDays < 0 || p.MetricRetentionDays < 0 {
+		fmt.Fprintln(os.Stderr, "retention cannot be less than 0 days")
+		return
+	}
+
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-dfs-ops POOLID LIMIT
+func (c *ServicedCli) cmdSetDFSOperations(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-dfs-ops")
+		return
+	}
+
+	limit, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse limit: %s\n", err)
+		return
+	} else if limit < 0 {
+		fmt.Fprintln(os.Stderr, "limit cannot be negative")
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.MaxDFSOperations = limit
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool set-cross-pool [--import APPLICATION[=POOLID,...]] POOLID POLICY
+func (c *ServicedCli) cmdSetCrossPool(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-cross-pool")
+		return
+	}
+
+	switch args[1] {
+	case pool.CrossPoolAllow, pool.CrossPoolRestrict:
+	default:
+		fmt.Fprintf(os.Stderr, "invalid cross-pool policy: %s\n", args[1])
+		return
+	}
+
+	p, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if p == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	p.CrossPoolPolicy = args[1]
+	if ctx.IsSet("import") {
+		p.CrossPoolImports = []pool.CrossPoolImport{}
+		for _, value := range ctx.StringSlice("import") {
+			parts := strings.SplitN(value, "=", 2)
+			rule := pool.CrossPoolImport{Application: parts[0]}
+			if len(parts) == 2 {
+				rule.Pools = strings.Split(parts[1], ",")
+			}
+			p.CrossPoolImports = append(p.CrossPoolImports, rule)
+		}
+	}
+	if err := c.driver.UpdateResourcePool(*p); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
+
+// serviced pool cross-pool-flows
+func (c *ServicedCli) cmdCrossPoolFlows(ctx *cli.Context) {
+	flows, err := c.driver.GetCrossPoolFlows()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(flows) == 0 {
+		fmt.Fprintln(os.Stderr, "no cross-pool flows found")
+		return
+	}
+
+	t := NewTable("Service,Pool,Application,ExportService,ExportPool,Allowed")
+	for _, flow := range flows {
+		t.AddRow(map[string]interface{}{
+			"Service":       flow.ServiceName,
+			"Pool":          flow.PoolID,
+			"Application":   flow.Application,
+			"ExportService": flow.ExportServiceName,
+			"ExportPool":    flow.ExportPoolID,
+			"Allowed":       flow.Allowed,
+		})
+	}
+	t.Print()
+}
+
 func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) != 1 {
@@ -433,6 +762,9 @@ func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 	}
 	updatePerms("dfs", pool.DFSAccess)
 	updatePerms("admin", pool.AdminAccess)
+	updatePerms("operator", pool.OperatorAccess)
+	updatePerms("read-only", pool.ReadOnlyAccess)
+	updatePerms("interactive", pool.InteractiveAccess)
 
 	// Fold the accumulated permissions into the current permissions
 	p.Permissions &^= perm_mask
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
@@ -161,6 +162,55 @@ func (t PoolAPITest) GetPoolIPs(id string) (*pool.PoolIPs, error) {
 	return &pool.PoolIPs{PoolID: p.ID, HostIPs: t.hostIPs}, nil
 }
 
+func (t PoolAPITest) GetVirtualIPEvents(id string) ([]pool.VirtualIPEvent, error) {
+	if p, err := t.GetResourcePool(id); err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	}
+	if id != "test-pool-id-1" {
+		return []pool.VirtualIPEvent{}, nil
+	}
+	return []pool.VirtualIPEvent{
+		{
+			Timestamp: time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC),
+			Type:      pool.EventVirtualIPUnreachable,
+			IPAddress: "10.0.0.10",
+			HostID:    "host1",
+			Message:   "virtual ip did not answer its reachability check",
+		}, {
+			Timestamp: time.Date(2017, 6, 1, 12, 0, 30, 0, time.UTC),
+			Type:      pool.EventVirtualIPFailover,
+			IPAddress: "10.0.0.10",
+			HostID:    "host1",
+			Message:   "virtual ip failed 3 reachability checks and was moved off of the host",
+		},
+	}, nil
+}
+
+func (t PoolAPITest) GetCrossPoolFlows() ([]pool.CrossPoolFlow, error) {
+	if t.fail {
+		return nil, ErrInvalidPool
+	}
+	return []pool.CrossPoolFlow{
+		{
+			ServiceName:       "Zope",
+			PoolID:            "test-pool-id-1",
+			ExportServiceName: "RabbitMQ",
+			ExportPoolID:      "test-pool-id-2",
+			Application:       "rabbitmq",
+			Allowed:           true,
+		}, {
+			ServiceName:       "Zope",
+			PoolID:            "test-pool-id-1",
+			ExportServiceName: "MariaDB",
+			ExportPoolID:      "test-pool-id-2",
+			Application:       "zodb_mariadb",
+			Allowed:           false,
+		},
+	}, nil
+}
+
 func (t PoolAPITest) UpdateResourcePool(pool pool.ResourcePool) error {
 	for i, p := range *t.pools {
 		if p.ID == pool.ID {
@@ -446,4 +496,150 @@ func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	assertPerm(poolID, pool.DFSAccess)
 	RunCmd(test, "serviced", "pool", "set-permission", "--admin", "--dfs=false", poolID)
 	assertPerm(poolID, pool.AdminAccess)
+
+	poolID = "poolID_roles"
+	RunCmd(test, "serviced", "pool", "add", "--read-only", poolID)
+	assertPerm(poolID, pool.ReadOnlyAccess)
+	RunCmd(test, "serviced", "pool", "set-permission", "--operator", "--read-only=false", poolID)
+	assertPerm(poolID, pool.OperatorAccess)
+	RunCmd(test, "serviced", "pool", "set-permission", "--interactive", poolID)
+	assertPerm(poolID, pool.OperatorAccess|pool.InteractiveAccess)
+}
+
+func TestServicedCLI_CmdPoolSetStrategy(t *testing.T) {
+	test := EmptyPoolAPI()
+	assertStrategy := func(poolID string, expected string) {
+		if p, err := test.GetResourcePool(poolID); err != nil {
+			t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err.Error())
+		} else if p.GetSchedulingStrategy() != expected {
+			t.Fatalf("Unexpected strategy for %s: %s != %s", poolID, p.GetSchedulingStrategy(), expected)
+		}
+	}
+
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	assertStrategy(poolID, pool.StrategySpread)
+	RunCmd(test, "serviced", "pool", "set-strategy", poolID, "binpack")
+	assertStrategy(poolID, pool.StrategyBinpack)
+	RunCmd(test, "serviced", "pool", "set-strategy", poolID, "label-affinity")
+	assertStrategy(poolID, pool.StrategyLabelAffinity)
+	RunCmd(test, "serviced", "pool", "set-strategy", poolID, "bogus")
+	assertStrategy(poolID, pool.StrategyLabelAffinity)
+}
+
+func TestServicedCLI_CmdPoolSetRetention(t *testing.T) {
+	test := EmptyPoolAPI()
+	assertRetention := func(poolID string, logs, metrics int) {
+		if p, err := test.GetResourcePool(poolID); err != nil {
+			t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err.Error())
+		} else if p.LogRetentionDays != logs || p.MetricRetentionDays != metrics {
+			t.Fatalf("Unexpected retention for %s: %d/%d != %d/%d", poolID, p.LogRetentionDays, p.MetricRetentionDays, logs, metrics)
+		}
+	}
+
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	assertRetention(poolID, 0, 0)
+	RunCmd(test, "serviced", "pool", "set-retention", "--logs", "3", poolID)
+	assertRetention(poolID, 3, 0)
+	RunCmd(test, "serviced", "pool", "set-retention", "--metrics", "7", poolID)
+	assertRetention(poolID, 3, 7)
+	RunCmd(test, "serviced", "pool", "set-retention", "--logs", "-1", poolID)
+	assertRetention(poolID, 3, 7)
+	RunCmd(test, "serviced", "pool", "set-retention", "--logs", "0", "--metrics", "0", poolID)
+	assertRetention(poolID, 0, 0)
+}
+
+func TestServicedCLI_CmdPoolSetVirtualIPCheck(t *testing.T) {
+	test := EmptyPoolAPI()
+	assertInterval := func(poolID string, interval int) {
+		if p, err := test.GetResourcePool(poolID); err != nil {
+			t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err.Error())
+		} else if p.VirtualIPCheckInterval != interval {
+			t.Fatalf("Unexpected virtual ip check interval for %s: %d != %d", poolID, p.VirtualIPCheckInterval, interval)
+		}
+	}
+
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	assertInterval(poolID, 0)
+	RunCmd(test, "serviced", "pool", "set-vip-check", poolID, "10s")
+	assertInterval(poolID, 10000)
+	RunCmd(test, "serviced", "pool", "set-vip-check", poolID, "-1s")
+	assertInterval(poolID, 10000)
+	RunCmd(test, "serviced", "pool", "set-vip-check", poolID, "0")
+	assertInterval(poolID, 0)
+}
+
+func TestServicedCLI_CmdPoolSetDFSOperations(t *testing.T) {
+	test := EmptyPoolAPI()
+	assertLimit := func(poolID string, limit int) {
+		if p, err := test.GetResourcePool(poolID); err != nil {
+			t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err.Error())
+		} else if p.MaxDFSOperations != limit {
+			t.Fatalf("Unexpected max dfs operations for %s: %d != %d", poolID, p.MaxDFSOperations, limit)
+		}
+	}
+
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	assertLimit(poolID, 0)
+	RunCmd(test, "serviced", "pool", "set-dfs-ops", poolID, "2")
+	assertLimit(poolID, 2)
+	captureStderr(func() { RunCmd(test, "serviced", "pool", "set-dfs-ops", poolID, "-1") })
+	assertLimit(poolID, 2)
+	captureStderr(func() { RunCmd(test, "serviced", "pool", "set-dfs-ops", poolID, "abc") })
+	assertLimit(poolID, 2)
+	RunCmd(test, "serviced", "pool", "set-dfs-ops", poolID, "0")
+	assertLimit(poolID, 0)
+}
+
+func ExampleServicedCLI_CmdPoolVirtualIPEvents() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "vip-events", "test-pool-id-1")
+
+	// Output:
+	// Time                 Type            IPAddress HostID Message
+	// 2017-06-01T12:00:00Z vip_unreachable 10.0.0.10 host1  virtual ip did not answer its reachability check
+	// 2017-06-01T12:00:30Z vip_failover    10.0.0.10 host1  virtual ip failed 3 reachability checks and was moved off of the host
+}
+
+func ExampleServicedCLI_CmdPoolVirtualIPEvents_none() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "vip-events", "test-pool-id-2") })
+
+	// Output:
+	// no virtual ip events found
+}
+
+func TestServicedCLI_CmdPoolSetCrossPool(t *testing.T) {
+	test := EmptyPoolAPI()
+	assertCrossPool := func(poolID, policy string, rules []pool.CrossPoolImport) {
+		if p, err := test.GetResourcePool(poolID); err != nil {
+			t.Fatalf("GetResourcePool(\"%s\"): %s", poolID, err.Error())
+		} else if p.GetCrossPoolPolicy() != policy || !reflect.DeepEqual(p.CrossPoolImports, rules) {
+			t.Fatalf("Unexpected cross-pool policy for %s: %s %v != %s %v", poolID, p.GetCrossPoolPolicy(), p.CrossPoolImports, policy, rules)
+		}
+	}
+
+	poolID := "poolID"
+	RunCmd(test, "serviced", "pool", "add", poolID)
+	assertCrossPool(poolID, pool.CrossPoolAllow, nil)
+	RunCmd(test, "serviced", "pool", "set-cross-pool", "--import", "rabbitmq*", "--import", "mariadb=reporting,backup", poolID, "restrict")
+	rules := []pool.CrossPoolImport{
+		{Application: "rabbitmq*"},
+		{Application: "mariadb", Pools: []string{"reporting", "backup"}},
+	}
+	assertCrossPool(poolID, pool.CrossPoolRestrict, rules)
+	captureStderr(func() { RunCmd(test, "serviced", "pool", "set-cross-pool", poolID, "deny") })
+	assertCrossPool(poolID, pool.CrossPoolRestrict, rules)
+	RunCmd(test, "serviced", "pool", "set-cross-pool", poolID, "allow")
+	assertCrossPool(poolID, pool.CrossPoolAllow, rules)
+}
+
+func ExampleServicedCLI_CmdPoolCrossPoolFlows() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "cross-pool-flows")
+
+	// Output:
+	// Service Pool           Application  ExportService ExportPool     Allowed
+	// Zope    test-pool-id-1 rabbitmq     RabbitMQ      test-pool-id-2 true
+	// Zope    test-pool-id-1 zodb_mariadb MariaDB       test-pool-id-2 false
 }
@@ -16,6 +16,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
 
@@ -89,7 +90,15 @@ func cmdPublicEndpointsList(c *ServicedCli, ctx *cli.Context, showVHosts bool, s
 		}
 	} else {
 		// Showing all service ports/vhosts.
-		peps, err := c.driver.GetAllPublicEndpoints()
+		var (
+			peps []service.PublicEndpoint
+			err  error
+		)
+		if poolID := ctx.String("pool"); poolID != "" {
+			peps, err = c.driver.GetPublicEndpointsInPool(poolID)
+		} else {
+			peps, err = c.driver.GetAllPublicEndpoints()
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Unable to get public endpoints: %s\n", err)
 			return
@@ -467,3 +476,50 @@ func (c *ServicedCli) cmdPublicEndpointsVHostEnable(ctx *cli.Context) {
 	}
 	return
 }
+
+// serviced service public-endpoints set-cert <SERVICEID> <VHOST> --cert CERTFILE --key KEYFILE
+func (c *ServicedCli) cmdPublicEndpointsSetCert(ctx *cli.Context) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 2 {
+		cli.ShowCommandHelp(ctx, "set-cert")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	vhostName := ctx.Args()[1]
+
+	var certPEM, keyPEM []byte
+	if ctx.Bool("clear") {
+		if ctx.String("cert") != "" || ctx.String("key") != "" {
+			fmt.Fprintln(os.Stderr, "--clear cannot be set with --cert or --key")
+			return
+		}
+	} else {
+		if ctx.String("cert") == "" || ctx.String("key") == "" {
+			fmt.Fprintln(os.Stderr, "--cert and --key are required")
+			return
+		}
+		var err error
+		if certPEM, err = ioutil.ReadFile(ctx.String("cert")); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		if keyPEM, err = ioutil.ReadFile(ctx.String("key")); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+	}
+
+	// We need the serviceid, but they may have provided the service id or name.
+	svc, _, err := c.searchForService(serviceid, ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if err := c.driver.SetPublicEndpointVHostCertificate(svc.ID, vhostName, certPEM, keyPEM); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Println(vhostName)
+	}
+}
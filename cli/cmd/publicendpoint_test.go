@@ -96,6 +96,13 @@ func (t ServiceAPITest) EnablePublicEndpointVHost(serviceID, endpointName, vhost
 	return nil
 }
 
+func (t ServiceAPITest) SetPublicEndpointVHostCertificate(serviceID, vhost string, certPEM, keyPEM []byte) error {
+	if t.errs["SetPublicEndpointVHostCertificate"] != nil {
+		return t.errs["SetPublicEndpointVHostCertificate"]
+	}
+	return nil
+}
+
 func InitPublicEndpointPortTest(args ...string) {
 	c := New(DefaultServiceAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
 	c.exitDisabled = true
@@ -460,3 +467,23 @@ func ExampleServicedCLI_CmdPublicEndpointsVHostEnable_ValidFlags() {
 	// zproxy
 	// zproxy
 }
+
+func ExampleServicedCLI_CmdPublicEndpointsSetCert_MissingFlags() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "set-cert", "Zenoss", "zproxy", "--cert", "cert.pem")
+	})
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "set-cert", "Zenoss", "zproxy", "--clear", "--key", "key.pem")
+	})
+
+	// Output:
+	// --cert and --key are required
+	// --clear cannot be set with --cert or --key
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsSetCert_Clear() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "set-cert", "Zenoss", "zproxy", "--clear")
+
+	// Output:
+	// zproxy
+}
@@ -0,0 +1,220 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// Initializer for serviced secret subcommands
+func (c *ServicedCli) initSecret() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "secret",
+		Usage:       "Administers secrets referenced by service config files",
+		Description: "Config files reference a secret with {{secret \"NAME\"}}; the value is only written inside the container. Templates and services reference registry credentials, stored as USER:PASSWORD or a JSON auth object, with RegistryCredential",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists all secrets",
+				Description:  "serviced secret list",
+				BashComplete: nil,
+				Action:       c.cmdSecretList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Name,UpdatedAt,Description",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "get",
+				Usage:        "Prints the value of a secret",
+				Description:  "serviced secret get NAME",
+				BashComplete: c.printSecretsFirst,
+				Action:       c.cmdSecretGet,
+			}, {
+				Name:         "set",
+				Usage:        "Adds or updates a secret; the value is read from stdin if it is not given",
+				Description:  "serviced secret set NAME [VALUE]",
+				BashComplete: c.printSecretsFirst,
+				Action:       c.cmdSecretSet,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "file",
+						Value: "",
+						Usage: "Read the value from a file",
+					},
+					cli.StringFlag{
+						Name:  "description",
+						Value: "",
+						Usage: "Description of the secret",
+					},
+				},
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes an existing secret",
+				Description:  "serviced secret remove NAME ...",
+				BashComplete: c.printSecretsFirst,
+				Action:       c.cmdSecretRemove,
+			},
+		},
+	})
+}
+
+// Bash-completion command that prints the list of available secrets as the
+// first argument
+func (c *ServicedCli) printSecretsFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	secrets, err := c.driver.GetSecrets()
+	if err != nil {
+		return
+	}
+	for _, s := range secrets {
+		fmt.Println(s.Name)
+	}
+}
+
+// serviced secret list
+func (c *ServicedCli) cmdSecretList(ctx *cli.Context) {
+	secrets, err := c.driver.GetSecrets()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(secrets) == 0 {
+		fmt.Fprintln(os.Stderr, "no secrets found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSecrets, err := json.MarshalIndent(secrets, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal secret list: %s", err)
+		} else {
+			fmt.Println(string(jsonSecrets))
+		}
+		return
+	}
+
+	t := NewTable(ctx.String("show-fields"))
+	t.Padding = 6
+	for _, s := range secrets {
+		t.AddRow(map[string]interface{}{
+			"Name":        s.Name,
+			"UpdatedAt":   s.UpdatedAt.UTC().Format(time.RFC3339),
+			"Description": s.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced secret get NAME
+func (c *ServicedCli) cmdSecretGet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "get")
+		return
+	}
+
+	value, err := c.driver.GetSecretValue(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(value)
+}
+
+// serviced secret set [--file FILE] [--description DESC] NAME [VALUE]
+func (c *ServicedCli) cmdSecretSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	name := args[0]
+	if !secret.ValidName(name) {
+		fmt.Fprintf(os.Stderr, "invalid secret name %q: use letters, digits, '.', '_' and '-'\n", name)
+		c.exit(1)
+		return
+	}
+
+	var value string
+	if len(args) == 2 {
+		value = args[1]
+	} else if filename := ctx.String("file"); filename != "" {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		value = string(data)
+	} else {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		// drop the newline added by echo or typing the value
+		value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	}
+	if value == "" {
+		fmt.Fprintln(os.Stderr, "secret value is empty")
+		c.exit(1)
+		return
+	}
+
+	if err := c.driver.SetSecret(name, ctx.String("description"), value); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Println(name)
+}
+
+// serviced secret remove NAME ...
+func (c *ServicedCli) cmdSecretRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, name := range args {
+		if err := c.driver.RemoveSecret(name); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		} else {
+			fmt.Println(name)
+		}
+	}
+}
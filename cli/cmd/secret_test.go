@@ -0,0 +1,138 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/utils"
+)
+
+var ErrNoSecretFound = errors.New("secret not found")
+
+type SecretAPITest struct {
+	api.API
+	secrets map[string]*secret.Secret
+}
+
+func NewSecretAPITest() SecretAPITest {
+	return SecretAPITest{secrets: map[string]*secret.Secret{
+		"db.password": {
+			Name:        "db.password",
+			Description: "database password",
+			Value:       []byte("hunter2"),
+			UpdatedAt:   time.Date(2017, time.June, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}}
+}
+
+func (t SecretAPITest) GetSecrets() ([]secret.Secret, error) {
+	secrets := []secret.Secret{}
+	for _, s := range t.secrets {
+		s := *s
+		s.Value = nil
+		secrets = append(secrets, s)
+	}
+	return secrets, nil
+}
+
+func (t SecretAPITest) GetSecretValue(name string) (string, error) {
+	if s, ok := t.secrets[name]; ok {
+		return string(s.Value), nil
+	}
+	return "", ErrNoSecretFound
+}
+
+func (t SecretAPITest) SetSecret(name, description, value string) error {
+	t.secrets[name] = &secret.Secret{Name: name, Description: description, Value: []byte(value)}
+	return nil
+}
+
+func (t SecretAPITest) RemoveSecret(name string) error {
+	if _, ok := t.secrets[name]; !ok {
+		return ErrNoSecretFound
+	}
+	delete(t.secrets, name)
+	return nil
+}
+
+func runSecretCmd(t SecretAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdSecretList() {
+	runSecretCmd(NewSecretAPITest(), "serviced", "secret", "list")
+
+	// Output:
+	// Name             UpdatedAt                 Description
+	// db.password      2017-06-01T12:00:00Z      database password
+}
+
+func ExampleServicedCLI_CmdSecretGet() {
+	test := NewSecretAPITest()
+	runSecretCmd(test, "serviced", "secret", "get", "db.password")
+	pipeStderr(func() { runSecretCmd(test, "serviced", "secret", "get", "missing") })
+
+	// Output:
+	// hunter2
+	// secret not found
+}
+
+func ExampleServicedCLI_CmdSecretRemove() {
+	test := NewSecretAPITest()
+	runSecretCmd(test, "serviced", "secret", "remove", "db.password")
+	pipeStderr(func() { runSecretCmd(test, "serviced", "secret", "remove", "db.password") })
+
+	// Output:
+	// db.password
+	// db.password: secret not found
+}
+
+func TestServicedCLI_CmdSecretSet(t *testing.T) {
+	test := NewSecretAPITest()
+	runSecretCmd(test, "serviced", "secret", "set", "--description", "api token", "api-token", "abc123")
+	if s := test.secrets["api-token"]; s == nil || string(s.Value) != "abc123" || s.Description != "api token" {
+		t.Fatalf("Unexpected secret: %+v", s)
+	}
+
+	dir, err := ioutil.TempDir("", "serviced-secret-")
+	if err != nil {
+		t.Fatalf("Could not create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(filename, []byte("correct horse\n"), 0600); err != nil {
+		t.Fatalf("Could not write value file: %s", err)
+	}
+	runSecretCmd(test, "serviced", "secret", "set", "--file", filename, "db.password")
+	if s := test.secrets["db.password"]; string(s.Value) != "correct horse\n" {
+		t.Fatalf("Unexpected value: %q", s.Value)
+	}
+
+	pipeStderr(func() { runSecretCmd(test, "serviced", "secret", "set", "db password", "value") })
+	if _, ok := test.secrets["db password"]; ok {
+		t.Fatalf("Expected an invalid name to be rejected")
+	}
+}
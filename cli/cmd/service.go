@@ -32,9 +32,11 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
+	"github.com/docker/go-units"
 )
 
 var unstartedTime = time.Date(1999, 12, 31, 23, 59, 0, 0, time.UTC)
@@ -72,6 +74,10 @@ func (c *ServicedCli) initService() {
 						Value: "Name,ServiceID,Inst,ImageID,Pool,DState,Launch,DepID",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
+					cli.StringFlag{
+						Name:  "pool",
+						Usage: "Only list the services in this resource pool",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -89,9 +95,13 @@ func (c *ServicedCli) initService() {
 					},
 					cli.StringFlag{
 						Name:  "show-fields",
-						Value: "Name,ServiceID,Status,HC Fail,Healthcheck,Healthcheck Status,Uptime,RAM,Cur/Max/Avg,Hostname,InSync,DockerID",
+						Value: "Name,ServiceID,Status,HC Fail,Healthcheck,Healthcheck Status,Uptime,Restarts,RAM,Cur/Max/Avg,Hostname,InSync,DockerID",
 						Usage: "Comma-delimited list describing which fields to display",
 					},
+					cli.StringFlag{
+						Name:  "pool",
+						Usage: "Only show the services in this resource pool",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -221,6 +231,83 @@ func (c *ServicedCli) initService() {
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
 					},
 				},
+			}, {
+				Name:         "instance-env",
+				Usage:        "Override the environment of a single service instance the next time it starts",
+				Description:  "serviced service instance-env { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }/INSTANCE [KEY=VALUE ...]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceInstanceEnv,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Remove the environment override of the instance",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			}, {
+				Name:         "edit-env",
+				Usage:        "Override environment variables of a service without editing its definition; without variables, lists the overrides",
+				Description:  "serviced service edit-env [--unset KEY ...] [--clear] { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } [KEY=VALUE ...]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceEditEnv,
+				Flags: []cli.Flag{
+					cli.StringSliceFlag{
+						Name:  "unset",
+						Value: &cli.StringSlice{},
+						Usage: "Remove the override of a variable",
+					},
+					cli.BoolFlag{
+						Name:  "clear",
+						Usage: "Remove all environment overrides of the service",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			}, {
+				Name:         "config-drift",
+				Usage:        "Compares the config files of the running instances of a service against the files inside their containers",
+				Description:  "serviced service config-drift { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceConfigDrift,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			}, {
+				Name:         "sync-config",
+				Usage:        "Writes the config files of a service over the files that have drifted inside its containers",
+				Description:  "serviced service sync-config [--restart] { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceSyncConfig,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "restart",
+						Usage: "Restart the service if any file was written",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			}, {
+				Name:         "priorities",
+				Usage:        "Shows the effective priority classes of the services of a deployment and the order they start and emergency stop in",
+				Description:  "serviced service priorities [SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServicePriorities,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
 			}, {
 				Name:         "stop",
 				Usage:        "Stops one or more services",
@@ -352,11 +439,25 @@ func (c *ServicedCli) initService() {
 				},
 			}, {
 				Name:         "logs",
-				Usage:        "Output the logs of a running service container - calls docker logs",
+				Usage:        "Output the logs of a running service container - calls docker logs; with --follow, --since or --instance, outputs the aggregated logs of all instances from logstash",
 				Description:  "serviced service logs { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME/INSTANCE }",
 				BashComplete: c.printServicesFirst,
 				Before:       c.cmdServiceLogs,
 				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "follow, f",
+						Usage: "Keep streaming new aggregated log messages",
+					},
+					cli.StringFlag{
+						Name:  "since",
+						Value: "",
+						Usage: "Show aggregated log messages since a timestamp (RFC3339) or a relative time (e.g. 30m); defaults to 10m",
+					},
+					cli.IntFlag{
+						Name:  "instance",
+						Value: -1,
+						Usage: "Only show aggregated log messages from one instance",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -419,6 +520,32 @@ func (c *ServicedCli) initService() {
 						Name:  "verify, v",
 						Usage: "verify endpoints",
 					},
+					cli.BoolFlag{
+						Name:  "stats, s",
+						Usage: "show the connection statistics of the endpoints",
+					},
+					cli.StringFlag{
+						Name:  "window",
+						Value: "1h",
+						Usage: "window of the connection statistics (e.g. 15m, 24h)",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			}, {
+				Name:         "graph",
+				Usage:        "Shows how the services of an application depend on each other through their endpoints",
+				Description:  "serviced service graph [--format dot|json] SERVICEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceGraph,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "format",
+						Value: "dot",
+						Usage: "Output format: dot or json",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -435,6 +562,10 @@ func (c *ServicedCli) initService() {
 						Description: "serviced service public-endpoints list [SERVICEID] [ENDPOINTNAME]",
 						Action:      c.cmdPublicEndpointsListAll,
 						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "pool",
+								Usage: "Only list the public endpoints of services in this resource pool",
+							},
 							cli.BoolFlag{
 								Name:  "ascii, a",
 								Usage: "use ascii characters for service tree (env SERVICED_TREE_ASCII=1 will default to ascii)",
@@ -604,6 +735,30 @@ func (c *ServicedCli) initService() {
 							},
 						},
 					},
+					{
+						Name:        "set-cert",
+						Usage:       "Sets the certificate that a vhost public endpoint presents",
+						Description: "serviced service public-endpoints set-cert <SERVICEID> <VHOST> --cert CERTFILE --key KEYFILE",
+						Action:      c.cmdPublicEndpointsSetCert,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "cert",
+								Usage: "PEM file with the certificate chain of the vhost, leaf first",
+							},
+							cli.StringFlag{
+								Name:  "key",
+								Usage: "PEM file with the private key of the certificate",
+							},
+							cli.BoolFlag{
+								Name:  "clear",
+								Usage: "Present the default certificate instead",
+							},
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+							},
+						},
+					},
 				},
 			},
 			{
@@ -619,6 +774,124 @@ func (c *ServicedCli) initService() {
 					},
 				},
 			},
+			{
+				Name:         "deploy-image",
+				Usage:        "Roll a new image out to a service, starting with a fraction of its instances",
+				Description:  "serviced service deploy-image { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } IMAGEID",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceDeployImage,
+				Flags: []cli.Flag{
+					cli.Float64Flag{
+						Name:  "canary",
+						Value: 1,
+						Usage: "Fraction of instances that must pass their health checks on the new image before it is promoted",
+					},
+					cli.StringFlag{
+						Name:  "timeout",
+						Value: "10m",
+						Usage: "Time to wait for the canary instances to pass their health checks (e.g. 5m, 1h)",
+					},
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			},
+			{
+				Name:        "image-pins",
+				Usage:       "View and refresh the image digests that services are pinned to",
+				Description: "serviced service image-pins",
+				Subcommands: []cli.Command{
+					{
+						Name:         "list",
+						Usage:        "List the image digests that a service and its descendants are pinned to",
+						Description:  "serviced service image-pins list { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceImagePinsList,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+							},
+						},
+					},
+					{
+						Name:         "refresh",
+						Usage:        "Pin a service and its descendants to the digests that their images are currently tagged to",
+						Description:  "serviced service image-pins refresh { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceImagePinsRefresh,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:        "maintenance",
+				Usage:       "Put an application into or out of maintenance mode",
+				Description: "serviced service maintenance",
+				Subcommands: []cli.Command{
+					{
+						Name:         "on",
+						Usage:        "Serve a maintenance page from the http public endpoints of an application",
+						Description:  "serviced service maintenance on { TENANTID | TENANTNAME }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceMaintenanceOn,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "message, m",
+								Value: "",
+								Usage: "Message to show on the maintenance page",
+							},
+							cli.IntFlag{
+								Name:  "retry-after",
+								Value: service.DefaultMaintenanceRetryAfter,
+								Usage: "Seconds that clients are asked to wait before retrying",
+							},
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make TENANTID matches on name strict 'ends with' matches",
+							},
+						},
+					},
+					{
+						Name:         "off",
+						Usage:        "Serve the application from its http public endpoints again",
+						Description:  "serviced service maintenance off { TENANTID | TENANTNAME }",
+						BashComplete: c.printServicesFirst,
+						Action:       c.cmdServiceMaintenanceOff,
+						Flags: []cli.Flag{
+							cli.BoolFlag{
+								Name:  "no-prefix-match, np",
+								Usage: "Make TENANTID matches on name strict 'ends with' matches",
+							},
+						},
+					},
+					{
+						Name:        "status",
+						Usage:       "List the applications that are in maintenance mode",
+						Description: "serviced service maintenance status",
+						Action:      c.cmdServiceMaintenanceStatus,
+					},
+				},
+			},
+			{
+				Name:         "watch",
+				Usage:        "Print the state changes of a service as they happen",
+				Description:  "serviced service watch { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdServiceWatch,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+					},
+				},
+			},
 			{
 				Name:         "remove-ip",
 				Usage:        "Remove the IP assignment of a service's endpoints",
@@ -1051,6 +1324,12 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 			c.exit(1)
 			return
 		}
+	} else if poolID := ctx.String("pool"); poolID != "" {
+		if states, err = c.driver.GetServiceStatusInPool(poolID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
 	} else {
 		if states, err = c.driver.GetServiceStatus(""); err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -1065,6 +1344,10 @@ func (c *ServicedCli) cmdServiceStatus(ctx *cli.Context) {
 	childmap := make(map[string][]string)
 	for id, state := range states {
 		parent := fmt.Sprintf("%v", state["ParentID"])
+		if _, ok := states[parent]; !ok {
+			// the parent is in another pool
+			parent = ""
+		}
 		childmap[parent] = append(childmap[parent], id)
 	}
 
@@ -1134,7 +1417,15 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		return
 	}
 
-	services, err := c.driver.GetAllServiceDetails()
+	var (
+		services []service.ServiceDetails
+		err      error
+	)
+	if poolID := ctx.String("pool"); poolID != "" {
+		services, err = c.driver.GetServiceDetailsInPool(poolID)
+	} else {
+		services, err = c.driver.GetAllServiceDetails()
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
@@ -1157,11 +1448,18 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 		cmdSetTreeCharset(ctx, c.config)
 
 		servicemap := api.NewServiceMap(services)
+		tree := servicemap.Tree()
+		for parentID, children := range tree {
+			if _, ok := servicemap[parentID]; parentID != "" && !ok {
+				// the parent is in another pool
+				tree[""] = append(tree[""], children...)
+			}
+		}
 		t := NewTable(ctx.String("show-fields"))
 
 		var addRows func(string)
 		addRows = func(root string) {
-			rowids := servicemap.Tree()[root]
+			rowids := tree[root]
 			if len(rowids) > 0 {
 				sort.Strings(rowids)
 				t.IndentRow()
@@ -1176,8 +1474,12 @@ func (c *ServicedCli) cmdServiceList(ctx *cli.Context) {
 						id[1] = id[1][:7] + "..."
 						imageID = strings.Join(id, "/")
 					}
+					name := row.Name
+					if row.Maintenance != nil {
+						name += " (maintenance)"
+					}
 					t.AddRow(map[string]interface{}{
-						"Name":      row.Name,
+						"Name":      name,
 						"ServiceID": row.ID,
 						"Inst":      row.Instances,
 						"ImageID":   imageID,
@@ -1876,91 +2178,336 @@ func (c *ServicedCli) cmdServiceRestart(ctx *cli.Context) {
 	return
 }
 
-// serviced service stop SERVICEID
-func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
+// serviced service instance-env { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }/INSTANCE [KEY=VALUE ...]
+func (c *ServicedCli) cmdServiceInstanceEnv(ctx *cli.Context) {
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) < 1 || (len(args) < 2 && !ctx.Bool("clear")) || (len(args) > 1 && ctx.Bool("clear")) {
 		fmt.Printf("Incorrect Usage.\n\n")
-		cli.ShowCommandHelp(ctx, "stop")
+		cli.ShowCommandHelp(ctx, "instance-env")
 		c.exit(1)
 		return
 	}
 
-	serviceIDs := make([]string, len(args))
-	for i, svcID := range args {
-		svc, _, err := c.searchForService(svcID, ctx.Bool("no-prefix-match"))
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			c.exit(1)
-			return
-		}
-
-		serviceIDs[i] = svc.ID
+	svc, instanceID, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if instanceID < 0 {
+		fmt.Fprintln(os.Stderr, "an instance of the service is required")
+		c.exit(1)
+		return
 	}
 
-	if affected, err := c.driver.StopService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+	env := []string(args[1:])
+	if err := c.driver.SetServiceInstanceEnvironment(svc.ID, instanceID, env); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
-	} else if affected == 0 {
-		fmt.Println("Service(s) already stopped")
+		return
+	}
+	if len(env) == 0 {
+		fmt.Printf("Cleared the environment override of instance %s/%d\n", svc.ID, instanceID)
 	} else {
-		fmt.Printf("Scheduled %d service(s) to stop\n", affected)
+		fmt.Printf("Set %d variable(s) on instance %s/%d; they take effect when the instance restarts\n", len(env), svc.ID, instanceID)
 	}
-	return
 }
 
-// serviced service pause SERVICEID
-func (c *ServicedCli) cmdServicePause(ctx *cli.Context) {
+// serviced service edit-env [--unset KEY ...] [--clear] SERVICEID [KEY=VALUE ...]
+func (c *ServicedCli) cmdServiceEditEnv(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 1 {
 		fmt.Printf("Incorrect Usage.\n\n")
-		cli.ShowCommandHelp(ctx, "pause")
+		cli.ShowCommandHelp(ctx, "edit-env")
 		c.exit(1)
 		return
 	}
 
-	serviceIDs := make([]string, len(args))
-	for i, svcID := range args {
-		svc, _, err := c.searchForService(svcID, ctx.Bool("no-prefix-match"))
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
+	svcDetails, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	svc, err := c.driver.GetService(svcDetails.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if svc == nil {
+		fmt.Fprintln(os.Stderr, "service not found")
+		c.exit(1)
+		return
+	}
+
+	// list the overrides
+	unset := ctx.StringSlice("unset")
+	if len(args) == 1 && len(unset) == 0 && !ctx.Bool("clear") {
+		keys := make([]string, 0, len(svc.EnvironmentOverrides))
+		for key := range svc.EnvironmentOverrides {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s=%s\n", key, svc.EnvironmentOverrides[key])
+		}
+		return
+	}
+
+	env := make(map[string]string)
+	if !ctx.Bool("clear") {
+		for key, value := range svc.EnvironmentOverrides {
+			env[key] = value
+		}
+	}
+	for _, key := range unset {
+		delete(env, key)
+	}
+	for _, v := range args[1:] {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			fmt.Fprintf(os.Stderr, "invalid variable %q; variables must be of the form KEY=VALUE\n", v)
 			c.exit(1)
 			return
 		}
-
-		serviceIDs[i] = svc.ID
+		env[parts[0]] = parts[1]
 	}
 
-	if affected, err := c.driver.PauseService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+	updated := *svc
+	updated.EnvironmentOverrides = env
+	if len(env) == 0 {
+		updated.EnvironmentOverrides = nil
+	}
+	if _, err := c.driver.UpdateServiceObj(updated); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
-	} else if affected == 0 {
-		fmt.Println("Service(s) already paused")
+		return
+	}
+	if len(env) == 0 {
+		fmt.Printf("Cleared the environment overrides of service %s\n", svc.ID)
 	} else {
-		fmt.Printf("Scheduled %d service(s) to pause\n", affected)
+		fmt.Printf("Service %s overrides %d variable(s); they take effect when its instances restart\n", svc.ID, len(env))
 	}
-	return
 }
 
-// serviced service shell [--saveas SAVEAS]  [--interactive, -i] SERVICEID [COMMAND]
-func (c *ServicedCli) cmdServiceShell(ctx *cli.Context) error {
+// serviced service config-drift { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceConfigDrift(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 1 {
-		if !ctx.Bool("help") {
-			fmt.Fprintf(os.Stderr, "Incorrect Usage.\n\n")
-		}
-		cli.ShowSubcommandHelp(ctx)
-		return c.exit(1)
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "config-drift")
+		c.exit(1)
+		return
 	}
 
-	// Bash completion
-	if args[len(args)-1] == "--generate-bash-completion" {
-		// CC-892: Disable bash completion after SERVICE_ID because possible matches
-		// are unavailable outside the container.
-		return c.exit(1)
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
 	}
 
-	var (
+	drift, err := c.driver.GetConfigDrift(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(drift) == 0 {
+		fmt.Fprintln(os.Stderr, "no config files found on running instances")
+		return
+	}
+
+	t := NewTable("Instance,Host,Filename,Status")
+	for _, d := range drift {
+		t.AddRow(map[string]interface{}{
+			"Instance": d.InstanceID,
+			"Host":     d.HostID,
+			"Filename": d.Filename,
+			"Status":   d.Status,
+		})
+	}
+	t.Print()
+	printConfigDriftErrors(svc.ID, drift)
+}
+
+// serviced service sync-config [--restart] { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceSyncConfig(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "sync-config")
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	drift, err := c.driver.SyncServiceConfigs(svc.ID, ctx.Bool("restart"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	synced := 0
+	for _, d := range drift {
+		if d.Status == service.ConfigModified || d.Status == service.ConfigMissing {
+			fmt.Printf("Wrote %s on instance %s/%d (%s)\n", d.Filename, svc.ID, d.InstanceID, d.Status)
+			synced++
+		}
+	}
+	if synced == 0 {
+		fmt.Printf("No config files of service %s have drifted\n", svc.ID)
+	} else if ctx.Bool("restart") {
+		fmt.Printf("Restarting service %s\n", svc.ID)
+	}
+	if printConfigDriftErrors(svc.ID, drift) {
+		c.exit(1)
+	}
+}
+
+// printConfigDriftErrors reports the instances whose config files could not
+// be checked, once per instance, and returns true if there were any.
+func printConfigDriftErrors(serviceID string, drift []service.ConfigDrift) bool {
+	reported := make(map[int]bool)
+	for _, d := range drift {
+		if d.Status == service.ConfigUnknown && !reported[d.InstanceID] {
+			fmt.Fprintf(os.Stderr, "could not check the config files of instance %s/%d: %s\n", serviceID, d.InstanceID, d.Error)
+			reported[d.InstanceID] = true
+		}
+	}
+	return len(reported) > 0
+}
+
+// serviced service priorities [SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME]
+func (c *ServicedCli) cmdServicePriorities(ctx *cli.Context) {
+	serviceID := ""
+	if args := ctx.Args(); len(args) > 0 {
+		svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		serviceID = svc.ID
+	}
+
+	priorities, err := c.driver.GetServicePriorities(serviceID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(priorities) == 0 {
+		fmt.Fprintln(os.Stderr, "no services found")
+		return
+	}
+
+	t := NewTable("Name,ServiceID,DepID,Priority,StartLevel,EmergencyLevel,StartBatch,ShutdownBatch")
+	t.Padding = 4
+	for _, p := range priorities {
+		t.AddRow(map[string]interface{}{
+			"Name":           p.Name,
+			"ServiceID":      p.ServiceID,
+			"DepID":          p.DeploymentID,
+			"Priority":       p.PriorityClass,
+			"StartLevel":     p.StartLevel,
+			"EmergencyLevel": p.EmergencyShutdownLevel,
+			"StartBatch":     p.StartBatch,
+			"ShutdownBatch":  p.ShutdownBatch,
+		})
+	}
+	t.Print()
+}
+
+// serviced service stop SERVICEID
+func (c *ServicedCli) cmdServiceStop(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "stop")
+		c.exit(1)
+		return
+	}
+
+	serviceIDs := make([]string, len(args))
+	for i, svcID := range args {
+		svc, _, err := c.searchForService(svcID, ctx.Bool("no-prefix-match"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+
+		serviceIDs[i] = svc.ID
+	}
+
+	if affected, err := c.driver.StopService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if affected == 0 {
+		fmt.Println("Service(s) already stopped")
+	} else {
+		fmt.Printf("Scheduled %d service(s) to stop\n", affected)
+	}
+	return
+}
+
+// serviced service pause SERVICEID
+func (c *ServicedCli) cmdServicePause(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "pause")
+		c.exit(1)
+		return
+	}
+
+	serviceIDs := make([]string, len(args))
+	for i, svcID := range args {
+		svc, _, err := c.searchForService(svcID, ctx.Bool("no-prefix-match"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+
+		serviceIDs[i] = svc.ID
+	}
+
+	if affected, err := c.driver.PauseService(api.SchedulerConfig{serviceIDs, ctx.Bool("auto-launch"), ctx.Bool("sync")}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else if affected == 0 {
+		fmt.Println("Service(s) already paused")
+	} else {
+		fmt.Printf("Scheduled %d service(s) to pause\n", affected)
+	}
+	return
+}
+
+// serviced service shell [--saveas SAVEAS]  [--interactive, -i] SERVICEID [COMMAND]
+func (c *ServicedCli) cmdServiceShell(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 1 {
+		if !ctx.Bool("help") {
+			fmt.Fprintf(os.Stderr, "Incorrect Usage.\n\n")
+		}
+		cli.ShowSubcommandHelp(ctx)
+		return c.exit(1)
+	}
+
+	// Bash completion
+	if args[len(args)-1] == "--generate-bash-completion" {
+		// CC-892: Disable bash completion after SERVICE_ID because possible matches
+		// are unavailable outside the container.
+		return c.exit(1)
+	}
+
+	var (
 		command string
 		argv    []string
 		isTTY   bool
@@ -2244,6 +2791,14 @@ func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 		return err
 	}
 
+	if ctx.Bool("follow") || ctx.IsSet("since") || ctx.IsSet("instance") {
+		if err := c.serviceAggregatedLogs(ctx, svc.ID, instanceID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+		}
+		return fmt.Errorf("serviced service logs")
+	}
+
 	if instanceID < 0 {
 		instanceID = 0
 	}
@@ -2262,6 +2817,57 @@ func (c *ServicedCli) cmdServiceLogs(ctx *cli.Context) error {
 	return fmt.Errorf("serviced service logs")
 }
 
+// defaultLogsSince is how far back aggregated logs are shown when --since
+// is not set
+const defaultLogsSince = 10 * time.Minute
+
+// serviceAggregatedLogs streams the logstash logs of all instances of a
+// service until interrupted
+func (c *ServicedCli) serviceAggregatedLogs(ctx *cli.Context, serviceID string, instanceID int) error {
+	if ctx.IsSet("instance") {
+		instanceID = ctx.Int("instance")
+	}
+
+	since, err := parseLogsSince(ctx.String("since"), time.Now())
+	if err != nil {
+		return err
+	}
+
+	cancel := make(chan struct{})
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	go func() {
+		<-sigc
+		close(cancel)
+	}()
+
+	cfg := api.LogsForServiceConfig{
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+		Since:      since,
+		Follow:     ctx.Bool("follow"),
+		Cancel:     cancel,
+	}
+	return c.driver.LogsForService(cfg, os.Stdout)
+}
+
+// parseLogsSince parses a --since value, which is either an RFC3339
+// timestamp or a duration before now.
+func parseLogsSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now.Add(-defaultLogsSince), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since value %q: expected an RFC3339 timestamp or a duration like 30m", value)
+	}
+	return now.Add(-d), nil
+}
+
 // serviced service list-snapshot SERVICEID [--show-tags]
 func (c *ServicedCli) cmdServiceListSnapshots(ctx *cli.Context) {
 	showTags := ctx.Bool("show-tags")
@@ -2373,6 +2979,17 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 		return
 	}
 
+	if ctx.Bool("stats") {
+		window, err := time.ParseDuration(ctx.String("window"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not parse duration: %s\n", err)
+			c.exit(1)
+			return
+		}
+		c.printEndpointMetrics(svc.ID, svc.Name, window)
+		return
+	}
+
 	var reportExports, reportImports bool
 	if ctx.Bool("all") {
 		reportImports = true
@@ -2436,6 +3053,332 @@ func (c *ServicedCli) cmdServiceEndpoints(ctx *cli.Context) {
 	return
 }
 
+// printEndpointMetrics prints the connection statistics of the endpoints of
+// a service
+func (c *ServicedCli) printEndpointMetrics(serviceID, name string, window time.Duration) {
+	stats, err := c.driver.GetEndpointMetrics(serviceID, window)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(stats) == 0 {
+		fmt.Fprintf(os.Stderr, "%s - no endpoints defined\n", name)
+		return
+	}
+
+	t := NewTable("Endpoint,Purpose,Connections,Active,ConnectErrors,ErrorRate,Sent,Received")
+	t.Padding = 4
+	for _, m := range stats {
+		t.AddRow(map[string]interface{}{
+			"Endpoint":      m.Application,
+			"Purpose":       m.Purpose,
+			"Connections":   m.Connections,
+			"Active":        m.Active,
+			"ConnectErrors": m.ConnectErrors,
+			"ErrorRate":     fmt.Sprintf("%.1f%%", 100*m.ConnectErrorRate()),
+			"Sent":          units.BytesSize(float64(m.BytesSent)),
+			"Received":      units.BytesSize(float64(m.BytesReceived)),
+		})
+	}
+	t.Print()
+}
+
+// serviced service graph [--format dot|json] SERVICEID
+func (c *ServicedCli) cmdServiceGraph(ctx *cli.Context) {
+	if len(ctx.Args()) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "graph")
+		c.exit(1)
+		return
+	}
+	format := ctx.String("format")
+	if format != "dot" && format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %s; use dot or json\n", format)
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(ctx.Args().First(), ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	graph, err := c.driver.GetEndpointGraph(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if format == "json" {
+		if jsonGraph, err := json.MarshalIndent(graph, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal endpoint graph: %s\n", err)
+			c.exit(1)
+		} else {
+			fmt.Println(string(jsonGraph))
+		}
+		return
+	}
+	printEndpointGraphDot(graph)
+}
+
+// printEndpointGraphDot prints an endpoint graph in the graphviz dot language.
+// Unresolved imports point to a dashed node of the application pattern.
+func printEndpointGraphDot(graph *applicationendpoint.EndpointGraph) {
+	fmt.Printf("digraph %q {\n", graph.TenantID)
+	for _, node := range graph.Services {
+		fmt.Printf("  %q [label=%q];\n", node.ServiceID, node.Name)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Unresolved() {
+			missing := "unresolved:" + edge.Application
+			fmt.Printf("  %q [label=%q, shape=box, style=dashed, color=red];\n", missing, edge.Application)
+			fmt.Printf("  %q -> %q [label=%q, style=dashed, color=red];\n", edge.ServiceID, missing, edge.Purpose)
+			continue
+		}
+		fmt.Printf("  %q -> %q [label=%q];\n", edge.ServiceID, edge.ExportServiceID, edge.ExportApplication)
+	}
+	fmt.Println("}")
+}
+
+// serviced service deploy-image { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME } IMAGEID
+func (c *ServicedCli) cmdServiceDeployImage(ctx *cli.Context) {
+	// verify args
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "deploy-image")
+		c.exit(1)
+		return
+	}
+
+	timeout, err := time.ParseDuration(ctx.String("timeout"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse duration: %s\n", err)
+		c.exit(1)
+		return
+	} else if timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "timeout must be positive")
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	cfg := api.CanaryConfig{
+		ServiceID: svc.ID,
+		ImageID:   args[1],
+		Fraction:  ctx.Float64("canary"),
+		Timeout:   timeout,
+	}
+	snapshotID, err := c.driver.DeployServiceCanary(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	fmt.Printf("Deployed image %s to service %s (snapshot %s)\n", args[1], svc.Name, snapshotID)
+}
+
+// serviced service image-pins list { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceImagePinsList(ctx *cli.Context) {
+	c.serviceImagePins(ctx, "list", c.driver.GetServiceImagePins)
+}
+
+// serviced service image-pins refresh { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceImagePinsRefresh(ctx *cli.Context) {
+	c.serviceImagePins(ctx, "refresh", c.driver.RefreshServiceImagePins)
+}
+
+// serviceImagePins prints the image pins that the command returns for the
+// service
+func (c *ServicedCli) serviceImagePins(ctx *cli.Context, command string, getPins func(string) ([]service.ImagePin, error)) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, command)
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	pins, err := getPins(svc.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if len(pins) == 0 {
+		fmt.Fprintln(os.Stderr, "no images found")
+		return
+	}
+
+	t := NewTable("Name,ServiceID,Image,Pinned,Current")
+	t.Padding = 4
+	for _, pin := range pins {
+		pinned, current := shortDigest(pin.Digest), shortDigest(pin.Current)
+		if pin.Digest == "" {
+			pinned = "unpinned"
+		}
+		if pin.Stale() {
+			current += " (changed)"
+		}
+		t.AddRow(map[string]interface{}{
+			"Name":      pin.Name,
+			"ServiceID": pin.ServiceID,
+			"Image":     pin.ImageID,
+			"Pinned":    pinned,
+			"Current":   current,
+		})
+	}
+	t.Print()
+}
+
+// serviced service maintenance on { TENANTID | TENANTNAME } [--message MESSAGE] [--retry-after SECONDS]
+func (c *ServicedCli) cmdServiceMaintenanceOn(ctx *cli.Context) {
+	if ctx.Int("retry-after") < 0 {
+		fmt.Fprintln(os.Stderr, "retry-after must not be negative")
+		c.exit(1)
+		return
+	}
+	mode := &service.MaintenanceMode{
+		Message:    ctx.String("message"),
+		RetryAfter: ctx.Int("retry-after"),
+	}
+	if svc := c.setServiceMaintenance(ctx, "on", mode); svc != nil {
+		fmt.Printf("Application %s is in maintenance mode\n", svc.Name)
+	}
+}
+
+// serviced service maintenance off { TENANTID | TENANTNAME }
+func (c *ServicedCli) cmdServiceMaintenanceOff(ctx *cli.Context) {
+	if svc := c.setServiceMaintenance(ctx, "off", nil); svc != nil {
+		fmt.Printf("Application %s is out of maintenance mode\n", svc.Name)
+	}
+}
+
+// setServiceMaintenance sets the maintenance mode of the tenant that the
+// command names, and returns the tenant if it succeeded
+func (c *ServicedCli) setServiceMaintenance(ctx *cli.Context, command string, mode *service.MaintenanceMode) *service.ServiceDetails {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, command)
+		c.exit(1)
+		return nil
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return nil
+	} else if svc.ParentServiceID != "" {
+		fmt.Fprintf(os.Stderr, "service %s is not an application\n", svc.Name)
+		c.exit(1)
+		return nil
+	}
+
+	if err := c.driver.SetMaintenanceMode(svc.ID, mode); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return nil
+	}
+	return svc
+}
+
+// serviced service maintenance status
+func (c *ServicedCli) cmdServiceMaintenanceStatus(ctx *cli.Context) {
+	services, err := c.driver.GetAllServiceDetails()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	t := NewTable("Name,ServiceID,Since,RetryAfter,Message")
+	t.Padding = 4
+	count := 0
+	for _, svc := range services {
+		if svc.ParentServiceID != "" || svc.Maintenance == nil {
+			continue
+		}
+		count++
+		t.AddRow(map[string]interface{}{
+			"Name":       svc.Name,
+			"ServiceID":  svc.ID,
+			"Since":      svc.Maintenance.Since.Format(time.RFC3339),
+			"RetryAfter": fmt.Sprintf("%ds", svc.Maintenance.RetryAfter),
+			"Message":    svc.Maintenance.Message,
+		})
+	}
+	if count == 0 {
+		fmt.Fprintln(os.Stderr, "no applications are in maintenance mode")
+		return
+	}
+	t.Print()
+}
+
+// shortDigest abbreviates an image digest to its first 12 hex digits, as
+// docker does
+func shortDigest(digest string) string {
+	if i := strings.Index(digest, ":"); i >= 0 && len(digest) > i+13 {
+		return digest[:i+13]
+	}
+	return digest
+}
+
+// serviceWatchTimeout is how long each request for state changes waits on
+// the master
+const serviceWatchTimeout = 30 * time.Second
+
+// serviced service watch { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+func (c *ServicedCli) cmdServiceWatch(ctx *cli.Context) {
+	// verify args
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "watch")
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	// long poll the master for state changes until interrupted
+	var since uint64
+	for {
+		events, err := c.driver.WaitServiceStateEvents(svc.ID, since, serviceWatchTimeout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		for _, ev := range events.Events {
+			fmt.Printf("%s %s %s\n", ev.Timestamp.Format(time.RFC3339), ev.ServiceID, ev.State)
+		}
+		since = events.Seq
+	}
+}
+
 // serviced service clear-emergency { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
 func (c *ServicedCli) cmdServiceClearEmergency(ctx *cli.Context) {
 	// verify args
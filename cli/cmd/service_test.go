@@ -23,6 +23,7 @@ import (
 	//	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
@@ -194,6 +195,19 @@ func (t ServiceAPITest) GetAllServiceDetails() ([]service.ServiceDetails, error)
 	return servicesToServiceDetails(t.services), nil
 }
 
+func (t ServiceAPITest) GetServiceDetailsInPool(poolID string) ([]service.ServiceDetails, error) {
+	if t.errs["GetServiceDetailsInPool"] != nil {
+		return nil, t.errs["GetServiceDetailsInPool"]
+	}
+	var svcs []service.Service
+	for _, svc := range t.services {
+		if svc.PoolID == poolID {
+			svcs = append(svcs, svc)
+		}
+	}
+	return servicesToServiceDetails(svcs), nil
+}
+
 func (t ServiceAPITest) ResolveServicePath(name string, noprefix bool) ([]service.ServiceDetails, error) {
 	if t.errs["ResolveServicePath"] != nil {
 		return nil, t.errs["ResolveServicePath"]
@@ -231,6 +245,35 @@ func (t ServiceAPITest) GetEndpoints(serviceID string, reportImports, reportExpo
 	return []applicationendpoint.EndpointReport{}, nil
 }
 
+func (t ServiceAPITest) GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	if t.errs["GetEndpointMetrics"] != nil {
+		return nil, t.errs["GetEndpointMetrics"]
+	} else if serviceID != "test-service-2" {
+		return []applicationendpoint.EndpointMetrics{}, nil
+	}
+	return []applicationendpoint.EndpointMetrics{
+		{ServiceID: serviceID, Application: "zope", Purpose: "export", Connections: 30, Active: 2, BytesSent: 1 << 20, BytesReceived: 2048},
+		{ServiceID: serviceID, Application: "zodb_.*", Purpose: "import_all", Connections: 9, ConnectErrors: 1, BytesSent: 512, BytesReceived: 1 << 30},
+	}, nil
+}
+
+func (t ServiceAPITest) GetEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	if t.errs["GetEndpointGraph"] != nil {
+		return nil, t.errs["GetEndpointGraph"]
+	}
+	return &applicationendpoint.EndpointGraph{
+		TenantID: "test-service-1",
+		Services: []applicationendpoint.EndpointGraphService{
+			{ServiceID: "test-service-2", Name: "Zope", Exports: []string{"zope"}},
+			{ServiceID: "test-service-3", Name: "zencommand"},
+		},
+		Edges: []applicationendpoint.EndpointGraphEdge{
+			{ServiceID: "test-service-3", Application: "zope", Purpose: "import", ExportServiceID: "test-service-2", ExportApplication: "zope"},
+			{ServiceID: "test-service-3", Application: "redis", Purpose: "import", Error: "no service exports a matching application"},
+		},
+	}, nil
+}
+
 func (t ServiceAPITest) GetService(id string) (*service.Service, error) {
 	if t.errs["GetService"] != nil {
 		return nil, t.errs["GetService"]
@@ -356,6 +399,7 @@ func serviceToServiceDetails(svc service.Service) service.ServiceDetails {
 		DeploymentID:    svc.DeploymentID,
 		DesiredState:    svc.DesiredState,
 		Launch:          svc.Launch,
+		Maintenance:     svc.Maintenance,
 	}
 	return details
 }
@@ -475,6 +519,112 @@ func (t ServiceAPITest) ClearEmergency(serviceID string) (int, error) {
 	return 1, nil
 }
 
+func (t ServiceAPITest) SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error {
+	if t.errs["SetServiceInstanceEnvironment"] != nil {
+		return t.errs["SetServiceInstanceEnvironment"]
+	}
+	return nil
+}
+
+var testConfigDrift = []service.ConfigDrift{
+	{InstanceID: 0, HostID: "test-host-1", Filename: "/etc/app.conf", Status: service.ConfigInSync},
+	{InstanceID: 0, HostID: "test-host-1", Filename: "/etc/log.conf", Status: service.ConfigModified},
+	{InstanceID: 1, HostID: "test-host-2", Filename: "/etc/app.conf", Status: service.ConfigUnknown, Error: "connection refused"},
+	{InstanceID: 1, HostID: "test-host-2", Filename: "/etc/log.conf", Status: service.ConfigUnknown, Error: "connection refused"},
+}
+
+func (t ServiceAPITest) GetConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	if t.errs["GetConfigDrift"] != nil {
+		return nil, t.errs["GetConfigDrift"]
+	}
+	return testConfigDrift, nil
+}
+
+func (t ServiceAPITest) SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	if t.errs["SyncServiceConfigs"] != nil {
+		return nil, t.errs["SyncServiceConfigs"]
+	}
+	return testConfigDrift[:2], nil
+}
+
+func (t ServiceAPITest) GetServicePriorities(serviceID string) ([]service.ServicePriority, error) {
+	if t.errs["GetServicePriorities"] != nil {
+		return nil, t.errs["GetServicePriorities"]
+	}
+	priorities := []service.ServicePriority{
+		{ServiceID: "test-service-2", Name: "Zenoss", DeploymentID: "test", PriorityClass: "low", StartLevel: 2, StartBatch: 3, ShutdownBatch: 1},
+		{ServiceID: "test-service-1", Name: "Zope", DeploymentID: "test", PriorityClass: "normal", StartLevel: 1, StartBatch: 2, ShutdownBatch: 2},
+		{ServiceID: "test-service-3", Name: "zencommand", DeploymentID: "test", PriorityClass: "critical", StartLevel: 1, EmergencyShutdownLevel: 1, StartBatch: 1, ShutdownBatch: 3},
+	}
+	if serviceID != "" {
+		return priorities[2:], nil
+	}
+	return priorities, nil
+}
+
+func (t ServiceAPITest) DeployServiceCanary(cfg api.CanaryConfig) (string, error) {
+	if t.errs["DeployServiceCanary"] != nil {
+		return "", t.errs["DeployServiceCanary"]
+	}
+	return "test-tenant_20170101_000000.000", nil
+}
+
+func (t ServiceAPITest) GetServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	if t.errs["GetServiceImagePins"] != nil {
+		return nil, t.errs["GetServiceImagePins"]
+	}
+	return []service.ImagePin{
+		{
+			ServiceID: serviceID,
+			Name:      "Zenoss",
+			ImageID:   "test-tenant/repo:latest",
+			Digest:    "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+			Current:   "sha256:9a0bca8d42d0c7b1f2a1d79dd5c9a19a1a13ebb38cb6a0c1e0d2ef5bb29c1d07",
+		},
+	}, nil
+}
+
+func (t ServiceAPITest) SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error {
+	if t.errs["SetMaintenanceMode"] != nil {
+		return t.errs["SetMaintenanceMode"]
+	}
+	if mode != nil {
+		fmt.Printf("%s: %q retry after %ds\n", tenantID, mode.Message, mode.RetryAfter)
+	}
+	return nil
+}
+
+func (t ServiceAPITest) RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	if t.errs["RefreshServiceImagePins"] != nil {
+		return nil, t.errs["RefreshServiceImagePins"]
+	}
+	return []service.ImagePin{
+		{
+			ServiceID: serviceID,
+			Name:      "Zenoss",
+			ImageID:   "test-tenant/repo:latest",
+			Digest:    "sha256:9a0bca8d42d0c7b1f2a1d79dd5c9a19a1a13ebb38cb6a0c1e0d2ef5bb29c1d07",
+			Current:   "sha256:9a0bca8d42d0c7b1f2a1d79dd5c9a19a1a13ebb38cb6a0c1e0d2ef5bb29c1d07",
+		},
+	}, nil
+}
+
+func (t ServiceAPITest) WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error) {
+	if t.errs["WaitServiceStateEvents"] != nil {
+		return nil, t.errs["WaitServiceStateEvents"]
+	} else if since > 0 {
+		return nil, ErrStub
+	}
+	ts := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &service.StateEvents{
+		Events: []service.StateEvent{
+			{Seq: 1, ServiceID: serviceID, State: service.SVCCSStarting, Timestamp: ts},
+			{Seq: 2, ServiceID: serviceID, State: service.SVCCSRunning, Timestamp: ts.Add(time.Second)},
+		},
+		Seq: 2,
+	}, nil
+}
+
 func TestServicedCLI_CmdServiceList_one(t *testing.T) {
 	serviceID := "test-service-1"
 
@@ -551,6 +701,16 @@ func ExampleServicedCLI_CmdServiceList_err() {
 	// no services found
 }
 
+func ExampleServicedCLI_CmdServiceList_pool() {
+	InitServiceAPITest("serviced", "service", "list", "--pool", "remote", "--format", "{{.ID}}\n")
+	// No services in the pool
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "list", "--pool", "empty") })
+
+	// Output:
+	// test-service-3
+	// no services found
+}
+
 func ExampleServicedCLI_CmdServiceList_complete() {
 	InitServiceAPITest("serviced", "service", "list", "--generate-bash-completion")
 
@@ -1088,6 +1248,121 @@ func ExampleServicedCLI_CmdServiceRestart() {
 
 }
 
+func ExampleServicedCLI_CmdServiceInstanceEnv() {
+	InitServiceAPITest("serviced", "service", "instance-env", "test-service-3/1", "LOG_LEVEL=debug", "TRACE=1")
+	InitServiceAPITest("serviced", "service", "instance-env", "--clear", "test-service-3/1")
+
+	// Output:
+	// Set 2 variable(s) on instance test-service-3/1; they take effect when the instance restarts
+	// Cleared the environment override of instance test-service-3/1
+}
+
+func ExampleServicedCLI_CmdServiceInstanceEnv_noInstance() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "instance-env", "test-service-3", "LOG_LEVEL=debug") })
+
+	// Output:
+	// an instance of the service is required
+}
+
+func ExampleServicedCLI_CmdServiceInstanceEnv_err() {
+	DefaultServiceAPITest.errs["SetServiceInstanceEnvironment"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["SetServiceInstanceEnvironment"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "instance-env", "test-service-3/1", "LOG_LEVEL=debug") })
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceEditEnv() {
+	InitServiceAPITest("serviced", "service", "edit-env", "test-service-3", "LOG_LEVEL=debug", "TRACE=1")
+	InitServiceAPITest("serviced", "service", "edit-env", "--clear", "test-service-3")
+
+	// Output:
+	// Service test-service-3 overrides 2 variable(s); they take effect when its instances restart
+	// Cleared the environment overrides of service test-service-3
+}
+
+func ExampleServicedCLI_CmdServiceEditEnv_list() {
+	svcs := append([]service.Service{}, DefaultTestServices...)
+	svcs[2].EnvironmentOverrides = map[string]string{"TRACE": "1", "LOG_LEVEL": "debug"}
+	t := DefaultServiceAPITest
+	t.services = svcs
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run([]string{"serviced", "service", "edit-env", "test-service-3"})
+	c.Run([]string{"serviced", "service", "edit-env", "--unset", "TRACE", "test-service-3", "LOG_LEVEL=info"})
+
+	// Output:
+	// LOG_LEVEL=debug
+	// TRACE=1
+	// Service test-service-3 overrides 1 variable(s); they take effect when its instances restart
+}
+
+func ExampleServicedCLI_CmdServiceEditEnv_invalid() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "edit-env", "test-service-3", "LOG_LEVEL") })
+
+	// Output:
+	// invalid variable "LOG_LEVEL"; variables must be of the form KEY=VALUE
+}
+
+func ExampleServicedCLI_CmdServiceConfigDrift() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "config-drift", "test-service-3") })
+
+	// Output:
+	// Instance Host        Filename      Status
+	// 0        test-host-1 /etc/app.conf in sync
+	// 0        test-host-1 /etc/log.conf modified
+	// 1        test-host-2 /etc/app.conf unknown
+	// 1        test-host-2 /etc/log.conf unknown
+	// could not check the config files of instance test-service-3/1: connection refused
+}
+
+func ExampleServicedCLI_CmdServicePriorities() {
+	InitServiceAPITest("serviced", "service", "priorities")
+
+	// Output:
+	// Name          ServiceID         DepID    Priority    StartLevel    EmergencyLevel    StartBatch    ShutdownBatch
+	// Zenoss        test-service-2    test     low         2             0                 3             1
+	// Zope          test-service-1    test     normal      1             0                 2             2
+	// zencommand    test-service-3    test     critical    1             1                 1             3
+}
+
+func ExampleServicedCLI_CmdServicePriorities_service() {
+	InitServiceAPITest("serviced", "service", "priorities", "test-service-3")
+
+	// Output:
+	// Name          ServiceID         DepID    Priority    StartLevel    EmergencyLevel    StartBatch    ShutdownBatch
+	// zencommand    test-service-3    test     critical    1             1                 1             3
+}
+
+func ExampleServicedCLI_CmdServiceSyncConfig() {
+	InitServiceAPITest("serviced", "service", "sync-config", "--restart", "test-service-3")
+
+	// Output:
+	// Wrote /etc/log.conf on instance test-service-3/0 (modified)
+	// Restarting service test-service-3
+}
+
+func ExampleServicedCLI_CmdServiceSyncConfig_usage() {
+	InitServiceAPITest("serviced", "service", "sync-config")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    sync-config - Writes the config files of a service over the files that have drifted inside its containers
+	//
+	// USAGE:
+	//    command sync-config [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced service sync-config [--restart] { SERVICEID | SERVICENAME | DEPLOYMENTID/...PARENTNAME.../SERVICENAME }
+	//
+	// OPTIONS:
+	//    --restart			Restart the service if any file was written
+	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
+}
+
 func ExampleServicedCLI_CmdServiceStop_usage() {
 	InitServiceAPITest("serviced", "service", "stop")
 
@@ -1441,6 +1716,8 @@ func ExampleServicedCLI_CmdServiceEndpoints_usage() {
 	//    --imports, -i		include only imported endpoints
 	//    --all, -a			include all endpoints (imports and exports)
 	//    --verify, -v			verify endpoints
+	//    --stats, -s			show the connection statistics of the endpoints
+	//    --window '1h'		window of the connection statistics (e.g. 15m, 24h)
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 
 }
@@ -1468,6 +1745,60 @@ func ExampleServicedCLI_CmdServiceEndpoints_works() {
 	// Zope    test-service-2    endpointName2    import     hostID2    hostIP2    20          containerID2    containerIP2    200
 }
 
+func ExampleServicedCLI_CmdServiceEndpoints_stats() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "endpoints", "--stats", "test-service-2") })
+
+	// Output:
+	// Endpoint    Purpose       Connections    Active    ConnectErrors    ErrorRate    Sent     Received
+	// zope        export        30             2         0                0.0%         1 MiB    2 KiB
+	// zodb_.*     import_all    9              0         1                10.0%        512 B    1 GiB
+}
+
+func ExampleServicedCLI_CmdServiceEndpoints_statsNoEndpoints() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "endpoints", "--stats", "test-service-1") })
+
+	// Output:
+	// Zenoss - no endpoints defined
+}
+
+func ExampleServicedCLI_CmdServiceEndpoints_statsBadWindow() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "endpoints", "--stats", "--window", "soon", "test-service-2")
+	})
+
+	// Output:
+	// could not parse duration: time: invalid duration "soon"
+}
+
+func ExampleServicedCLI_CmdServiceGraph() {
+	InitServiceAPITest("serviced", "service", "graph", "test-service-3")
+
+	// Output:
+	// digraph "test-service-1" {
+	//   "test-service-2" [label="Zope"];
+	//   "test-service-3" [label="zencommand"];
+	//   "test-service-3" -> "test-service-2" [label="zope"];
+	//   "unresolved:redis" [label="redis", shape=box, style=dashed, color=red];
+	//   "test-service-3" -> "unresolved:redis" [label="import", style=dashed, color=red];
+	// }
+}
+
+func ExampleServicedCLI_CmdServiceGraph_badFormat() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "graph", "--format", "svg", "test-service-3") })
+
+	// Output:
+	// unknown format svg; use dot or json
+}
+
+func ExampleServicedCLI_CmdServiceGraph_err() {
+	DefaultServiceAPITest.errs["GetEndpointGraph"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["GetEndpointGraph"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "graph", "test-service-3") })
+
+	// Output:
+	// stub for facade failed
+}
+
 func ExampleServicedCLI_CmdServiceClearEmergency_works() {
 	pipeStderr(func() { InitServiceAPITest("serviced", "service", "clear-emergency", "test-service-1") })
 
@@ -1510,6 +1841,128 @@ func ExampleServicedCLI_CmdServiceClearEmergency_usage() {
 	//    --no-prefix-match, --np	Make SERVICEID matches on name strict 'ends with' matches
 }
 
+func ExampleServicedCLI_CmdServiceDeployImage_works() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "deploy-image", "--canary", "0.5", "test-service-1", "repo/image:2.0")
+	})
+
+	// Output:
+	// Deployed image repo/image:2.0 to service Zenoss (snapshot test-tenant_20170101_000000.000)
+}
+
+func ExampleServicedCLI_CmdServiceDeployImage_err() {
+	DefaultServiceAPITest.errs["DeployServiceCanary"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["DeployServiceCanary"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "deploy-image", "test-service-1", "repo/image:2.0") })
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceImagePinsList() {
+	InitServiceAPITest("serviced", "service", "image-pins", "list", "test-service-1")
+
+	// Output:
+	// Name      ServiceID         Image                      Pinned                 Current
+	// Zenoss    test-service-1    test-tenant/repo:latest    sha256:4f53cda18c2b    sha256:9a0bca8d42d0 (changed)
+}
+
+func ExampleServicedCLI_CmdServiceImagePinsRefresh() {
+	InitServiceAPITest("serviced", "service", "image-pins", "refresh", "test-service-1")
+
+	// Output:
+	// Name      ServiceID         Image                      Pinned                 Current
+	// Zenoss    test-service-1    test-tenant/repo:latest    sha256:9a0bca8d42d0    sha256:9a0bca8d42d0
+}
+
+func ExampleServicedCLI_CmdServiceImagePinsRefresh_err() {
+	DefaultServiceAPITest.errs["RefreshServiceImagePins"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["RefreshServiceImagePins"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "image-pins", "refresh", "test-service-1") })
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceOn() {
+	InitServiceAPITest("serviced", "service", "maintenance", "on", "--message", "Upgrading", "--retry-after", "600", "test-service-1")
+
+	// Output:
+	// test-service-1: "Upgrading" retry after 600s
+	// Application Zenoss is in maintenance mode
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceOn_notFound() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "maintenance", "on", "test-service-0") })
+
+	// Output:
+	// service not found
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceOff() {
+	InitServiceAPITest("serviced", "service", "maintenance", "off", "test-service-1")
+
+	// Output:
+	// Application Zenoss is out of maintenance mode
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceOff_err() {
+	DefaultServiceAPITest.errs["SetMaintenanceMode"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["SetMaintenanceMode"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "maintenance", "off", "test-service-1") })
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceStatus() {
+	DefaultTestServices[1].Maintenance = &service.MaintenanceMode{
+		Message:    "Upgrading to 6.1",
+		RetryAfter: 300,
+		Since:      time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC),
+	}
+	defer func() { DefaultTestServices[1].Maintenance = nil }()
+	InitServiceAPITest("serviced", "service", "maintenance", "status")
+
+	// Output:
+	// Name    ServiceID         Since                   RetryAfter    Message
+	// Zope    test-service-2    2026-10-15T09:30:00Z    300s          Upgrading to 6.1
+}
+
+func ExampleServicedCLI_CmdServiceMaintenanceStatus_none() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "maintenance", "status") })
+
+	// Output:
+	// no applications are in maintenance mode
+}
+
+func ExampleServicedCLI_CmdServiceDeployImage_badTimeout() {
+	pipeStderr(func() {
+		InitServiceAPITest("serviced", "service", "deploy-image", "--timeout", "soon", "test-service-1", "repo/image:2.0")
+	})
+
+	// Output:
+	// could not parse duration: time: invalid duration "soon"
+}
+
+func ExampleServicedCLI_CmdServiceWatch() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "watch", "test-service-1") })
+
+	// Output:
+	// 2017-01-01T00:00:00Z test-service-1 starting
+	// 2017-01-01T00:00:01Z test-service-1 started
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdServiceWatch_err() {
+	DefaultServiceAPITest.errs["WaitServiceStateEvents"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["WaitServiceStateEvents"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "service", "watch", "test-service-1") })
+
+	// Output:
+	// stub for facade failed
+}
+
 func ExampleServiceCLI_CmdServiceTune_usage() {
 	pipeStderr(func() { InitServiceAPITest("serviced", "service", "tune") })
 	// Output:
@@ -1768,3 +2221,20 @@ func ExampleServiceCLI_CmdServiceVariableUnset() {
 	// Output:
 	// test-service-2
 }
+
+func TestParseLogsSince(t *testing.T) {
+	now := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if since, err := parseLogsSince("", now); err != nil || !since.Equal(now.Add(-defaultLogsSince)) {
+		t.Errorf("Expected default since %s, got %s (%v)", now.Add(-defaultLogsSince), since, err)
+	}
+	if since, err := parseLogsSince("30m", now); err != nil || !since.Equal(now.Add(-30*time.Minute)) {
+		t.Errorf("Expected since %s, got %s (%v)", now.Add(-30*time.Minute), since, err)
+	}
+	if since, err := parseLogsSince("2016-12-31T23:00:00Z", now); err != nil || !since.Equal(time.Date(2016, 12, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected timestamp since, got %s (%v)", since, err)
+	}
+	if _, err := parseLogsSince("yesterday", now); err == nil {
+		t.Errorf("Expected error for invalid since value")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/setting"
+)
+
+// Initializer for serviced setting subcommands
+func (c *ServicedCli) initSetting() {
+	scopeFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "pool",
+			Value: "",
+			Usage: "Only change the setting in this resource pool",
+		},
+		cli.StringFlag{
+			Name:  "deployment",
+			Value: "",
+			Usage: "Only change the setting in this deployment",
+		},
+	}
+
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "setting",
+		Usage:       "Administers cluster settings",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the settings and the values they are set to",
+				Description:  "serviced setting list",
+				BashComplete: nil,
+				Action:       c.cmdSettingList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "set",
+				Usage:        "Sets a setting everywhere or in a pool or deployment",
+				Description:  "serviced setting set [--pool POOLID] [--deployment DEPLOYMENTID] SETTING VALUE",
+				BashComplete: c.printSettingsFirst,
+				Action:       c.cmdSettingSet,
+				Flags:        scopeFlags,
+			}, {
+				Name:         "unset",
+				Usage:        "Unsets a setting everywhere or in a pool or deployment",
+				Description:  "serviced setting unset [--pool POOLID] [--deployment DEPLOYMENTID] SETTING",
+				BashComplete: c.printSettingsFirst,
+				Action:       c.cmdSettingUnset,
+				Flags:        scopeFlags,
+			},
+		},
+	})
+}
+
+// printSettingsFirst is the generic completion action for the first argument
+func (c *ServicedCli) printSettingsFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	for _, name := range setting.Names() {
+		fmt.Println(name)
+	}
+}
+
+// settingScope returns the scope selected by the --pool and --deployment
+// flags
+func settingScope(ctx *cli.Context) (setting.Scope, string, error) {
+	poolID, deploymentID := ctx.String("pool"), ctx.String("deployment")
+	switch {
+	case poolID != "" && deploymentID != "":
+		return "", "", fmt.Errorf("only one of --pool and --deployment can be set")
+	case poolID != "":
+		return setting.ScopePool, poolID, nil
+	case deploymentID != "":
+		return setting.ScopeDeployment, deploymentID, nil
+	}
+	return setting.ScopeGlobal, "", nil
+}
+
+// settingValues describes the values of a setting in each scope
+func settingValues(s setting.Setting) string {
+	values := []string{}
+	for _, v := range s.Values {
+		if v.Scope == setting.ScopeGlobal {
+			values = append(values, v.Value)
+		} else {
+			values = append(values, fmt.Sprintf("%s/%s=%s", v.Scope, v.ScopeID, v.Value))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// serviced setting list [--verbose]
+func (c *ServicedCli) cmdSettingList(ctx *cli.Context) {
+	settings, err := c.driver.GetSettings()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(settings) == 0 {
+		fmt.Fprintln(os.Stderr, "no settings found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSettings, err := json.MarshalIndent(settings, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal settings: %s", err)
+		} else {
+			fmt.Println(string(jsonSettings))
+		}
+		return
+	}
+
+	t := NewTable("Name,Type,Default,Values,Description")
+	t.Padding = 6
+	for _, s := range settings {
+		def, _ := setting.Lookup(s.ID)
+		t.AddRow(map[string]interface{}{
+			"Name":        s.ID,
+			"Type":        def.Type,
+			"Default":     def.Default,
+			"Values":      settingValues(s),
+			"Description": def.Description,
+		})
+	}
+	t.Print()
+}
+
+// serviced setting set [--pool POOLID] [--deployment DEPLOYMENTID] SETTING VALUE
+func (c *ServicedCli) cmdSettingSet(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set")
+		return
+	}
+
+	scope, scopeID, err := settingScope(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if err := c.driver.SetSetting(args[0], scope, scopeID, args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
+
+// serviced setting unset [--pool POOLID] [--deployment DEPLOYMENTID] SETTING
+func (c *ServicedCli) cmdSettingUnset(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "unset")
+		return
+	}
+
+	scope, scopeID, err := settingScope(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if err := c.driver.UnsetSetting(args[0], scope, scopeID); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Println(args[0])
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/control-center/serviced/utils"
+)
+
+type SettingAPITest struct {
+	api.API
+	settings map[string]*setting.Setting
+}
+
+func NewSettingAPITest() SettingAPITest {
+	return SettingAPITest{
+		settings: map[string]*setting.Setting{
+			setting.ImagePullPolicy:  setting.New(setting.ImagePullPolicy),
+			setting.TracingCollector: setting.New(setting.TracingCollector),
+		},
+	}
+}
+
+func (t SettingAPITest) GetSettings() ([]setting.Setting, error) {
+	settings := []setting.Setting{}
+	for _, name := range setting.Names() {
+		settings = append(settings, *t.settings[name])
+	}
+	return settings, nil
+}
+
+func (t SettingAPITest) SetSetting(name string, scope setting.Scope, scopeID, value string) error {
+	s, ok := t.settings[name]
+	if !ok {
+		return errors.New("setting not found")
+	}
+	s.Set(scope, scopeID, value)
+	return nil
+}
+
+func (t SettingAPITest) UnsetSetting(name string, scope setting.Scope, scopeID string) error {
+	s, ok := t.settings[name]
+	if !ok {
+		return errors.New("setting not found")
+	}
+	s.Unset(scope, scopeID)
+	return nil
+}
+
+func runSettingCmd(t SettingAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdSettingList() {
+	runSettingCmd(NewSettingAPITest(), "serviced", "setting", "list")
+
+	// Output:
+	// Name                   Type        Default           Values      Description
+	// image-pull-policy      string      IfNotPresent                  When delegates pull the images of services that do not select a pull policy
+	// tracing-collector      string                                    Address of the OTLP/HTTP collector that the spans of services with tracing are forwarded to
+}
+
+func ExampleServicedCLI_CmdSettingSet() {
+	test := NewSettingAPITest()
+	runSettingCmd(test, "serviced", "setting", "set", setting.ImagePullPolicy, "Never")
+	runSettingCmd(test, "serviced", "setting", "set", "--pool", "default", setting.ImagePullPolicy, "Always")
+	runSettingCmd(test, "serviced", "setting", "set", "--deployment", "prod", setting.ImagePullPolicy, "Always")
+	runSettingCmd(test, "serviced", "setting", "unset", "--deployment", "prod", setting.ImagePullPolicy)
+	runSettingCmd(test, "serviced", "setting", "list")
+
+	// Output:
+	// image-pull-policy
+	// image-pull-policy
+	// image-pull-policy
+	// image-pull-policy
+	// Name                   Type        Default           Values                         Description
+	// image-pull-policy      string      IfNotPresent      Never,pool/default=Always      When delegates pull the images of services that do not select a pull policy
+	// tracing-collector      string                                                       Address of the OTLP/HTTP collector that the spans of services with tracing are forwarded to
+}
+
+func ExampleServicedCLI_CmdSettingSet_err() {
+	pipeStderr(func() { runSettingCmd(NewSettingAPITest(), "serviced", "setting", "set", "no-such-setting", "value") })
+	pipeStderr(func() {
+		runSettingCmd(NewSettingAPITest(), "serviced", "setting", "set", "--pool", "default", "--deployment", "prod", setting.ImagePullPolicy, "Never")
+	})
+
+	// Output:
+	// setting not found
+	// only one of --pool and --deployment can be set
+}
@@ -14,6 +14,7 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"strings"
@@ -21,6 +22,7 @@ import (
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 )
 
 // initSnapshot is the initializer for serviced snapshot
@@ -41,6 +43,10 @@ func (c *ServicedCli) initSnapshot() {
 						Name:  "show-tags, t",
 						Usage: "shows tags associated with each snapshot",
 					},
+					cli.StringFlag{
+						Name:  "pool",
+						Usage: "Only list the snapshots of applications in this resource pool",
+					},
 				},
 			}, {
 				Name:         "add",
@@ -87,6 +93,14 @@ func (c *ServicedCli) initSnapshot() {
 						Name:  "force-restart",
 						Usage: "restarts running services during rollback",
 					},
+					cli.BoolFlag{
+						Name:  "force, f",
+						Usage: "rolls back without previewing the changes and asking for confirmation",
+					},
+					cli.BoolFlag{
+						Name:  "preview",
+						Usage: "shows the changes of the rollback without rolling back",
+					},
 				},
 				BashComplete: c.printSnapshotsFirst,
 				Action:       c.cmdSnapshotRollback,
@@ -189,6 +203,11 @@ func (c *ServicedCli) cmdSnapshotList(ctx *cli.Context) {
 			fmt.Fprintln(os.Stderr, err)
 			return
 		}
+	} else if poolID := ctx.String("pool"); poolID != "" {
+		if snapshots, err = c.getSnapshotsInPool(poolID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
 	} else {
 		if snapshots, err = c.driver.GetSnapshots(); err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -228,6 +247,27 @@ func (c *ServicedCli) cmdSnapshotList(ctx *cli.Context) {
 	return
 }
 
+// getSnapshotsInPool returns the snapshots of the applications whose tenant
+// service is in the resource pool
+func (c *ServicedCli) getSnapshotsInPool(poolID string) ([]dao.SnapshotInfo, error) {
+	services, err := c.driver.GetServiceDetailsInPool(poolID)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []dao.SnapshotInfo
+	for _, svc := range services {
+		if svc.ParentServiceID != "" {
+			continue
+		}
+		tenantSnapshots, err := c.driver.GetSnapshotsByServiceID(svc.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, tenantSnapshots...)
+	}
+	return snapshots, nil
+}
+
 // serviced snapshot add SERVICEID [--tags=<tag1>,<tag2>...]
 func (c *ServicedCli) cmdSnapshotAdd(ctx *cli.Context) {
 	nArgs := len(ctx.Args())
@@ -332,7 +372,7 @@ func (c *ServicedCli) cmdSnapshotCommit(ctx *cli.Context) {
 	}
 }
 
-// serviced snapshot rollback SNAPSHOTID [--force-restart]
+// serviced snapshot rollback SNAPSHOTID [--force-restart] [--force] [--preview]
 func (c *ServicedCli) cmdSnapshotRollback(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 1 {
@@ -341,6 +381,27 @@ func (c *ServicedCli) cmdSnapshotRollback(ctx *cli.Context) {
 		return
 	}
 
+	if !ctx.Bool("force") {
+		preview, err := c.driver.GetRollbackPreview(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+			return
+		}
+		printRollbackPreview(preview, ctx.Bool("force-restart"))
+		if ctx.Bool("preview") {
+			return
+		}
+		fmt.Printf("Roll back to snapshot %s? [y/N]: ", args[0])
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		fmt.Println()
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(os.Stderr, "Rollback cancelled; run with --force to roll back without confirmation")
+			c.exit(1)
+			return
+		}
+	}
+
 	if err := c.driver.Rollback(args[0], ctx.Bool("force-restart")); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
@@ -349,6 +410,65 @@ func (c *ServicedCli) cmdSnapshotRollback(ctx *cli.Context) {
 	}
 }
 
+// printRollbackPreview prints the changes of a rollback
+func printRollbackPreview(preview *dfs.RollbackPreview, forceRestart bool) {
+	fmt.Printf("Rollback to snapshot %s of tenant %s\n", preview.SnapshotID, preview.TenantID)
+
+	if len(preview.Stopped) > 0 {
+		if forceRestart {
+			fmt.Printf("\nServices that are stopped and restarted (%d):\n", len(preview.Stopped))
+		} else {
+			fmt.Printf("\nServices that are running and must be stopped, or run with --force-restart (%d):\n", len(preview.Stopped))
+		}
+		t := NewTable("Name,ServiceID")
+		for _, svc := range preview.Stopped {
+			t.AddRow(map[string]interface{}{
+				"Name":      svc.Name,
+				"ServiceID": svc.ServiceID,
+			})
+		}
+		t.Print()
+	} else {
+		fmt.Println("\nNo services are running")
+	}
+
+	if len(preview.Images) > 0 {
+		fmt.Printf("\nImages that change (%d):\n", len(preview.Images))
+		t := NewTable("Image,Current,Snapshot")
+		for _, image := range preview.Images {
+			current := image.Current
+			if current == "" {
+				current = "<none>"
+			}
+			t.AddRow(map[string]interface{}{
+				"Image":    image.Image,
+				"Current":  current,
+				"Snapshot": image.Snapshot,
+			})
+		}
+		t.Print()
+	} else {
+		fmt.Println("\nNo images change")
+	}
+
+	if len(preview.ConfigFiles) > 0 {
+		fmt.Printf("\nConfig files that change (%d):\n", len(preview.ConfigFiles))
+		t := NewTable("Service,ServiceID,Filename,Change")
+		for _, conf := range preview.ConfigFiles {
+			t.AddRow(map[string]interface{}{
+				"Service":   conf.Name,
+				"ServiceID": conf.ServiceID,
+				"Filename":  conf.Filename,
+				"Change":    conf.Change,
+			})
+		}
+		t.Print()
+	} else {
+		fmt.Println("\nNo config files change")
+	}
+	fmt.Println()
+}
+
 // serviced snapshot tag SNAPSHOTID TAG-NAME
 func (c *ServicedCli) cmdSnapshotTag(ctx *cli.Context) {
 	args := ctx.Args()
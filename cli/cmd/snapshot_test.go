@@ -23,6 +23,7 @@ import (
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume/btrfs"
 )
@@ -145,6 +146,25 @@ func (t SnapshotAPITest) Rollback(id string, f bool) error {
 	return t.RemoveSnapshot(id)
 }
 
+func (t SnapshotAPITest) GetRollbackPreview(id string) (*dfs.RollbackPreview, error) {
+	if ok, err := t.hasSnapshot(id); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrNoSnapshotFound
+	}
+	return &dfs.RollbackPreview{
+		SnapshotID: id,
+		TenantID:   "test-service-1",
+		Stopped: []dfs.RollbackService{
+			{ServiceID: "test-service-2", Name: "Zope"},
+		},
+		Images: []dfs.ImageChange{
+			{Image: "test-service-1/zope:latest", Current: "sha256:new", Snapshot: "sha256:old"},
+		},
+		ConfigFiles: []dfs.ConfigFileChange{},
+	}, nil
+}
+
 func (t SnapshotAPITest) TagSnapshot(snapshotID string, tagName string) error {
 	if t.fail {
 		return ErrInvalidSnapshot
@@ -502,12 +522,29 @@ func ExampleServicedCLI_CmdSnapshotCommit_err() {
 }
 
 func ExampleServicedCLI_CmdSnapshotRollback() {
-	InitSnapshotAPITest("serviced", "snapshot", "rollback", "test-service-1-snapshot-1")
+	InitSnapshotAPITest("serviced", "snapshot", "rollback", "--force", "test-service-1-snapshot-1")
 
 	// Output:
 	// test-service-1-snapshot-1
 }
 
+func ExampleServicedCLI_CmdSnapshotRollback_preview() {
+	InitSnapshotAPITest("serviced", "snapshot", "rollback", "--preview", "test-service-1-snapshot-1")
+
+	// Output:
+	// Rollback to snapshot test-service-1-snapshot-1 of tenant test-service-1
+	//
+	// Services that are running and must be stopped, or run with --force-restart (1):
+	// Name ServiceID
+	// Zope test-service-2
+	//
+	// Images that change (1):
+	// Image                      Current    Snapshot
+	// test-service-1/zope:latest sha256:new sha256:old
+	//
+	// No config files change
+}
+
 func ExampleServicedCLI_CmdSnapshotRollback_usage() {
 	InitSnapshotAPITest("serviced", "snapshot", "rollback")
 
@@ -525,6 +562,8 @@ func ExampleServicedCLI_CmdSnapshotRollback_usage() {
 	//
 	// OPTIONS:
 	//    --force-restart	restarts running services during rollback
+	//    --force, -f		rolls back without previewing the changes and asking for confirmation
+	//    --preview		shows the changes of the rollback without rolling back
 }
 
 /*
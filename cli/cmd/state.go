@@ -0,0 +1,151 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/cli/api"
+)
+
+// Initializer for serviced state subcommands
+func (c *ServicedCli) initState() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "state",
+		Usage:       "Exports and compares the configuration of clusters",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "export",
+				Usage:       "Dumps the pools, hosts, templates, services and public endpoints of the cluster",
+				Description: "serviced state export",
+				Action:      c.cmdStateExport,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "out",
+						Value: "",
+						Usage: "path to output file",
+					},
+				},
+			}, {
+				Name:        "diff",
+				Usage:       "Compares two exported states, or an exported state with the cluster; exits with status 1 if they differ",
+				Description: "serviced state diff FILEA [FILEB]",
+				Action:      c.cmdStateDiff,
+			},
+		},
+	})
+}
+
+// serviced state export [--out FILE]
+func (c *ServicedCli) cmdStateExport(ctx *cli.Context) {
+	state, err := c.driver.ExportState()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	if out := ctx.String("out"); out != "" {
+		if err := ioutil.WriteFile(out, append(data, '\n'), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			c.exit(1)
+		}
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// serviced state diff FILEA [FILEB]
+func (c *ServicedCli) cmdStateDiff(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "diff")
+		c.exit(1)
+		return
+	}
+
+	a, err := readState(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	var b *api.ClusterState
+	if len(args) > 1 {
+		b, err = readState(args[1])
+	} else {
+		b, err = c.driver.ExportState()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	diffs := api.DiffState(a, b)
+	for _, d := range diffs {
+		switch {
+		case d.Old == nil && d.Field == "":
+			fmt.Printf("+ %s/%s\n", d.Section, d.Key)
+		case d.New == nil && d.Field == "":
+			fmt.Printf("- %s/%s\n", d.Section, d.Key)
+		default:
+			fmt.Printf("~ %s/%s %s: %s -> %s\n", d.Section, d.Key, d.Field, stateValue(d.Old), stateValue(d.New))
+		}
+	}
+	if len(diffs) > 0 {
+		c.exit(1)
+	}
+}
+
+// readState reads a cluster state exported to a file
+func readState(filename string) (*api.ClusterState, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	state := &api.ClusterState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("could not read state from %s: %s", filename, err)
+	} else if state.Version != api.StateVersion {
+		return nil, fmt.Errorf("could not read state from %s: unsupported version %d", filename, state.Version)
+	}
+	return state, nil
+}
+
+// stateValue returns a value of a cluster state as compact json
+func stateValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
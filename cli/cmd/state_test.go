@@ -0,0 +1,140 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/utils"
+)
+
+type StateAPITest struct {
+	api.API
+	fail bool
+}
+
+func (t StateAPITest) ExportState() (*api.ClusterState, error) {
+	if t.fail {
+		return nil, errors.New("could not connect to master")
+	}
+	return &api.ClusterState{
+		Version: api.StateVersion,
+		Pools: map[string]interface{}{
+			"default": map[string]interface{}{"CoreLimit": float64(4)},
+		},
+		Services: map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{"Instances": float64(3)},
+		},
+	}, nil
+}
+
+func runStateCmd(t StateAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+// writeStateFile writes a cluster state to a file in a temporary directory
+func writeStateFile(dir, name, data string) string {
+	filename := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		panic(err)
+	}
+	return filename
+}
+
+func ExampleServicedCLI_CmdStateExport() {
+	runStateCmd(StateAPITest{}, "serviced", "state", "export")
+
+	// Output:
+	// {
+	//   "Version": 1,
+	//   "Pools": {
+	//     "default": {
+	//       "CoreLimit": 4
+	//     }
+	//   },
+	//   "Hosts": null,
+	//   "Templates": null,
+	//   "Services": {
+	//     "Zenoss.core/Zope": {
+	//       "Instances": 3
+	//     }
+	//   },
+	//   "Endpoints": null
+	// }
+}
+
+func ExampleServicedCLI_CmdStateExport_fail() {
+	pipeStderr(func() { runStateCmd(StateAPITest{fail: true}, "serviced", "state", "export") })
+
+	// Output:
+	// could not connect to master
+}
+
+func ExampleServicedCLI_CmdStateDiff() {
+	dir, err := ioutil.TempDir("", "state-diff-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	a := writeStateFile(dir, "a.json", `{"Version": 1, "Pools": {"default": {"CoreLimit": 4}, "test": {}}, "Services": {"Zenoss.core/Zope": {"Instances": 2}}}`)
+	b := writeStateFile(dir, "b.json", `{"Version": 1, "Pools": {"default": {"CoreLimit": 4}}, "Services": {"Zenoss.core/Zope": {"Instances": 3}, "Zenoss.core/Redis": {}}}`)
+
+	runStateCmd(StateAPITest{}, "serviced", "state", "diff", a, b)
+
+	// Output:
+	// - pools/test
+	// + services/Zenoss.core/Redis
+	// ~ services/Zenoss.core/Zope Instances: 2 -> 3
+}
+
+func ExampleServicedCLI_CmdStateDiff_cluster() {
+	dir, err := ioutil.TempDir("", "state-diff-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	a := writeStateFile(dir, "golden.json", `{"Version": 1, "Pools": {"default": {"CoreLimit": 4}}, "Services": {"Zenoss.core/Zope": {"Instances": 3}}}`)
+
+	runStateCmd(StateAPITest{}, "serviced", "state", "diff", a)
+	fmt.Println("no differences")
+
+	// Output:
+	// no differences
+}
+
+func ExampleServicedCLI_CmdStateDiff_badVersion() {
+	dir, err := ioutil.TempDir("", "state-diff-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	writeStateFile(dir, "a.json", `{"Version": 2}`)
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(wd)
+
+	pipeStderr(func() { runStateCmd(StateAPITest{}, "serviced", "state", "diff", "a.json", "a.json") })
+
+	// Output:
+	// could not read state from a.json: unsupported version 2
+}
@@ -16,6 +16,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/control-center/serviced/cli/api"
 	template "github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/servicedversion"
+	"github.com/control-center/serviced/validation"
 )
 
 // initTemplate is the initializer for serviced template
@@ -73,6 +75,28 @@ func (c *ServicedCli) initTemplate() {
 						Name:  "manual-assign-ips",
 						Usage: "Manually assign IP addresses",
 					},
+					cli.StringSliceFlag{
+						Name:  "values, f",
+						Value: &cli.StringSlice{},
+						Usage: "Yaml or json file of values that parameterize the template",
+					},
+					cli.StringSliceFlag{
+						Name:  "set",
+						Value: &cli.StringSlice{},
+						Usage: "Set a value of the template, as KEY.PATH=VALUE",
+					},
+				},
+			}, {
+				Name:         "upgrade",
+				Usage:        "Upgrades the services of a deployment to a template",
+				Description:  "serviced template upgrade TEMPLATEID DEPLOYMENTID",
+				BashComplete: c.printTemplatesFirst,
+				Action:       c.cmdTemplateUpgrade,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show the changes without applying them",
+					},
 				},
 			}, {
 				Name:        "compile",
@@ -86,6 +110,16 @@ func (c *ServicedCli) initTemplate() {
 						Usage: "Map a given image name to another (e.g. -map zenoss/zenoss5x:latest,quay.io/zenoss-core:alpha2)",
 					},
 				},
+			}, {
+				Name:        "schema",
+				Usage:       "Prints the JSON Schema of templates or services",
+				Description: "serviced template schema [template|service]",
+				Action:      c.cmdTemplateSchema,
+			}, {
+				Name:        "validate",
+				Usage:       "Checks a template against the JSON Schema of templates",
+				Description: "serviced template validate [FILE]",
+				Action:      c.cmdTemplateValidate,
 			},
 		},
 	})
@@ -219,6 +253,60 @@ func (c *ServicedCli) cmdTemplateAdd(ctx *cli.Context) {
 	}
 }
 
+// serviced template schema [template|service]
+func (c *ServicedCli) cmdTemplateSchema(ctx *cli.Context) {
+	name := "template"
+	if len(ctx.Args()) > 0 {
+		name = ctx.Args().First()
+	}
+
+	schema, err := c.driver.GetSchema(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	if jsonSchema, err := json.MarshalIndent(schema, "", "  "); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal schema: %s\n", err)
+		c.exit(1)
+	} else {
+		fmt.Println(string(jsonSchema))
+	}
+}
+
+// serviced template validate [FILE]
+func (c *ServicedCli) cmdTemplateValidate(ctx *cli.Context) {
+	var data []byte
+	var err error
+	if filepath := ctx.Args().First(); filepath != "" {
+		data, err = ioutil.ReadFile(filepath)
+	} else {
+		data, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	schema, err := c.driver.GetSchema("template")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	if err := schema.Validate(data); err != nil {
+		if verr, ok := err.(*validation.ValidationError); ok {
+			for _, violation := range verr.Errors {
+				fmt.Fprintln(os.Stderr, violation)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "could not unmarshal json: %s\n", err)
+		}
+		c.exit(1)
+	}
+}
+
 // serviced template remove TEMPLATEID ...
 func (c *ServicedCli) cmdTemplateRemove(ctx *cli.Context) {
 	args := ctx.Args()
@@ -237,7 +325,7 @@ func (c *ServicedCli) cmdTemplateRemove(ctx *cli.Context) {
 	}
 }
 
-// serviced template deploy TEMPLATEID POOLID DEPLOYMENTID [--manual-assign-ips]
+// serviced template deploy TEMPLATEID POOLID DEPLOYMENTID [--manual-assign-ips] [--values FILE ...] [--set KEY=VALUE ...]
 func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 	args := ctx.Args()
 	if len(args) < 3 {
@@ -246,11 +334,18 @@ func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 		return
 	}
 
+	values, err := readTemplateValues(ctx.StringSlice("values"), ctx.StringSlice("set"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
 	cfg := api.DeployTemplateConfig{
 		ID:              args[0],
 		PoolID:          args[1],
 		DeploymentID:    args[2],
 		ManualAssignIPs: ctx.Bool("manual-assign-ips"),
+		Values:          values,
 	}
 
 	fmt.Fprintln(os.Stderr, "Deploying template - please wait...")
@@ -265,6 +360,75 @@ func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 	}
 }
 
+// serviced template upgrade TEMPLATEID DEPLOYMENTID [--dry-run]
+func (c *ServicedCli) cmdTemplateUpgrade(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "upgrade")
+		return
+	}
+
+	dryRun := ctx.Bool("dry-run")
+	if !dryRun {
+		fmt.Fprintln(os.Stderr, "Upgrading deployment - please wait...")
+	}
+	changes, err := c.driver.UpgradeServiceTemplate(args[0], args[1], dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(changes) == 0 {
+		fmt.Fprintln(os.Stderr, "deployment is up to date")
+		return
+	}
+	for _, change := range changes {
+		fmt.Println(formatTemplateChange(change))
+	}
+}
+
+// formatTemplateChange returns a line of the diff between a template and a
+// deployment
+func formatTemplateChange(change template.TemplateChange) string {
+	switch change.Action {
+	case template.TemplateChangeKeep:
+		return fmt.Sprintf("  %s (not in template)", change.ServicePath)
+	case template.TemplateChangeAdd:
+		if change.Field == "" {
+			return fmt.Sprintf("+ %s", change.ServicePath)
+		}
+		return fmt.Sprintf("+ %s %s: %s", change.ServicePath, change.Field, change.New)
+	case template.TemplateChangePull:
+		return fmt.Sprintf("~ %s %s: pull %s", change.ServicePath, change.Field, change.New)
+	}
+	if change.Old == "" {
+		return fmt.Sprintf("~ %s %s: %s", change.ServicePath, change.Field, change.New)
+	}
+	return fmt.Sprintf("~ %s %s: %s -> %s", change.ServicePath, change.Field, change.Old, change.New)
+}
+
+// readTemplateValues merges the values of the files in order, and then sets
+// the values given on the command line
+func readTemplateValues(filenames, sets []string) (template.Values, error) {
+	values := template.Values{}
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		fileValues, err := template.ParseValues(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse values file %s: %s", filename, err)
+		}
+		values = values.Merge(fileValues)
+	}
+	for _, set := range sets {
+		if err := values.Set(set); err != nil {
+			return nil, fmt.Errorf("%s: %s", set, err)
+		}
+	}
+	return values, nil
+}
+
 type metaTemplate struct {
 	template.ServiceTemplate
 	ServicedVersion servicedversion.ServicedVersion
@@ -20,11 +20,15 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	template "github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/validation"
 
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
 	"testing"
 )
 
@@ -96,6 +100,15 @@ func (t TemplateAPITest) AddServiceTemplate(r io.Reader) (*template.ServiceTempl
 	return &template, nil
 }
 
+func (t TemplateAPITest) GetSchema(name string) (*validation.Schema, error) {
+	if t.fail {
+		return nil, ErrInvalidTemplate
+	} else if name != "template" {
+		return nil, ErrNoTemplateFound
+	}
+	return template.Schema(), nil
+}
+
 func (t TemplateAPITest) RemoveServiceTemplate(id string) error {
 	if t, err := t.GetServiceTemplate(id); err != nil {
 		return err
@@ -132,6 +145,21 @@ func (t TemplateAPITest) DeployServiceTemplate(cfg api.DeployTemplateConfig) ([]
 	return []service.ServiceDetails{s}, nil
 }
 
+func (t TemplateAPITest) UpgradeServiceTemplate(templateID, deploymentID string, dryRun bool) ([]template.TemplateChange, error) {
+	tpl, err := t.GetServiceTemplate(templateID)
+	if err != nil {
+		return nil, err
+	} else if tpl == nil || !dryRun {
+		return nil, nil
+	}
+	return []template.TemplateChange{
+		{ServicePath: tpl.Name, ServiceID: "tenant", Action: template.TemplateChangeUpdate, Field: "Version", Old: "1.0", New: "1.1"},
+		{ServicePath: tpl.Name, ServiceID: "tenant", Action: template.TemplateChangeAdd, Field: "Environment", New: "FOO=bar"},
+		{ServicePath: tpl.Name + "/new", Action: template.TemplateChangeAdd},
+		{ServicePath: tpl.Name + "/old", ServiceID: "old", Action: template.TemplateChangeKeep},
+	}, nil
+}
+
 func TestServicedCLI_CmdTemplateList_one(t *testing.T) {
 	templateID := "test-template-1"
 
@@ -274,7 +302,9 @@ func ExampleServicedCLI_CmdTemplateDeploy_usage() {
 	//    serviced template deploy TEMPLATEID POOLID DEPLOYMENTID
 	//
 	// OPTIONS:
-	//    --manual-assign-ips	Manually assign IP addresses
+	//    --manual-assign-ips					Manually assign IP addresses
+	//    --values, -f '--values option --values option'	Yaml or json file of values that parameterize the template
+	//    --set '--set option --set option'			Set a value of the template, as KEY.PATH=VALUE
 }
 
 func ExampleServicedCLI_CmdTemplateDeploy_fail() {
@@ -299,6 +329,91 @@ func ExampleServicedCLI_CmdTemplateDeploy_err() {
 	// received nil service definition
 }
 
+func ExampleServicedCLI_CmdTemplateDeploy_badSet() {
+	pipeStderr(func() {
+		InitTemplateAPITest("serviced", "template", "deploy", "--set", "instances", "test-template-1", "test-pool", "deployment-id")
+	})
+
+	// Output:
+	// instances: values must be set as KEY=VALUE
+}
+
+func ExampleServicedCLI_CmdTemplateUpgrade() {
+	pipeStderr(func() {
+		InitTemplateAPITest("serviced", "template", "upgrade", "test-template-1", "deployment-id")
+	})
+
+	// Output:
+	// Upgrading deployment - please wait...
+	// deployment is up to date
+}
+
+func ExampleServicedCLI_CmdTemplateUpgrade_dryRun() {
+	InitTemplateAPITest("serviced", "template", "upgrade", "--dry-run", "test-template-1", "deployment-id")
+
+	// Output:
+	// ~ Alpha Version: 1.0 -> 1.1
+	// + Alpha Environment: FOO=bar
+	// + Alpha/new
+	//   Alpha/old (not in template)
+}
+
+func ExampleServicedCLI_CmdTemplateUpgrade_usage() {
+	InitTemplateAPITest("serviced", "template", "upgrade", "test-template-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    upgrade - Upgrades the services of a deployment to a template
+	//
+	// USAGE:
+	//    command upgrade [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced template upgrade TEMPLATEID DEPLOYMENTID
+	//
+	// OPTIONS:
+	//    --dry-run	Show the changes without applying them
+}
+
+func ExampleServicedCLI_CmdTemplateUpgrade_fail() {
+	DefaultTemplateAPITest.fail = true
+	defer func() { DefaultTemplateAPITest.fail = false }()
+	pipeStderr(func() {
+		InitTemplateAPITest("serviced", "template", "upgrade", "--dry-run", "test-template-1", "deployment-id")
+	})
+
+	// Output:
+	// invalid template
+}
+
+func TestServicedCLI_ReadTemplateValues(t *testing.T) {
+	f, err := ioutil.TempFile("", "values")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("services:\n  app/web:\n    instances: 2\n    ramCommitment: 1G\n")
+	f.Close()
+
+	values, err := readTemplateValues([]string{f.Name()}, []string{"services.app/web.instances=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := template.Values{
+		"services": map[string]interface{}{
+			"app/web": map[string]interface{}{
+				"instances":     "3",
+				"ramCommitment": "1G",
+			},
+		},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %+v, got %+v", expected, values)
+	}
+}
+
 func TestServicedCLI_CmdTemplateCompile(t *testing.T) {
 	dir := "/path/to/template"
 
@@ -353,3 +468,31 @@ func ExampleServicedCLI_CmdTemplateCompile_err() {
 	// Output:
 	// received nil template
 }
+
+func TestServicedCLI_CmdTemplateSchema(t *testing.T) {
+	var actual validation.Schema
+	output := captureStdout(func() { InitTemplateAPITest("serviced", "template", "schema") })
+	if err := json.Unmarshal(output, &actual); err != nil {
+		t.Fatalf("error unmarshaling schema: %s", err)
+	}
+
+	expected := template.Schema()
+	if !reflect.DeepEqual(&actual, expected) {
+		t.Fatalf("got:\n%+v\nwant:\n%+v", actual, expected)
+	}
+}
+
+func ExampleServicedCLI_CmdTemplateValidate() {
+	f, err := ioutil.TempFile("", "template")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`{"Name": "Alpha", "Services": [{"Name": "web", "Instances": {"Min": 1}}]}`)
+	f.Close()
+
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "validate", f.Name()) })
+
+	// Output:
+}
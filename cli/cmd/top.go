@@ -0,0 +1,182 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/cli/api"
+	"github.com/pivotal-golang/bytefmt"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	topClearScreen = "\033[H\033[2J"
+	topHideCursor  = "\033[?25l"
+	topShowCursor  = "\033[?25h"
+	topKeyCtrlC    = 3
+)
+
+// Initializer for serviced top
+func (c *ServicedCli) initTop() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "top",
+		Usage:       "Shows a live overview of pools, hosts and the busiest services",
+		Description: "serviced top",
+		Action:      c.cmdTop,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "pool",
+				Value: "",
+				Usage: "Show only the hosts and services in a resource pool",
+			},
+			cli.IntFlag{
+				Name:  "interval, d",
+				Value: 2,
+				Usage: "Seconds between refreshes",
+			},
+			cli.IntFlag{
+				Name:  "count, n",
+				Value: 10,
+				Usage: "Number of services to show",
+			},
+			cli.BoolFlag{
+				Name:  "once",
+				Usage: "Print the overview once and exit",
+			},
+		},
+	})
+}
+
+// serviced top [--pool POOLID] [--interval SECONDS] [--count N] [--once]
+func (c *ServicedCli) cmdTop(ctx *cli.Context) {
+	cfg := api.TopConfig{
+		PoolID:   ctx.String("pool"),
+		Interval: time.Duration(ctx.Int("interval")) * time.Second,
+		Count:    ctx.Int("count"),
+	}
+	done := make(chan struct{})
+	defer close(done)
+	views, err := c.driver.WatchTop(cfg, done)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	// print each view in turn if the output is not a terminal
+	if ctx.Bool("once") || !terminal.IsTerminal(syscall.Stdout) {
+		for view := range views {
+			fmt.Print(renderTop(view, ""))
+			if ctx.Bool("once") {
+				return
+			}
+			fmt.Println()
+		}
+		return
+	}
+
+	state, err := terminal.MakeRaw(syscall.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not set up terminal: %s\n", err)
+		return
+	}
+	defer terminal.Restore(syscall.Stdin, state)
+	fmt.Print(topHideCursor)
+	defer fmt.Print(topClearScreen + topShowCursor)
+
+	keys := make(chan byte)
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if n, err := os.Stdin.Read(buf); err != nil {
+				return
+			} else if n > 0 {
+				select {
+				case keys <- buf[0]:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		select {
+		case view, ok := <-views:
+			if !ok {
+				return
+			}
+			// the terminal does not translate newlines in raw mode
+			out := renderTop(view, "press q to quit")
+			fmt.Print(topClearScreen + strings.Replace(out, "\n", "\r\n", -1))
+		case key := <-keys:
+			if key == 'q' || key == 'Q' || key == topKeyCtrlC {
+				return
+			}
+		}
+	}
+}
+
+// renderTop formats the pools, hosts and services of a view as tables
+func renderTop(view api.TopView, hint string) string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "serviced top - %s", view.Updated.Format("15:04:05"))
+	if hint != "" {
+		fmt.Fprintf(buf, " (%s)", hint)
+	}
+	fmt.Fprintln(buf)
+	if view.Err != nil {
+		fmt.Fprintf(buf, "error: %s\n", view.Err)
+	}
+
+	w := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nPOOL\tHOSTS\tCORES\tMEMORY\tCOMMITTED\tINSTANCES")
+	for _, p := range view.Pools {
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%s\t%s\t%d\n", p.ID, p.ActiveHosts, p.Hosts, p.CoreCapacity,
+			bytefmt.ByteSize(p.MemoryCapacity), bytefmt.ByteSize(p.MemoryCommitment), p.Instances)
+	}
+	w.Flush()
+
+	w = tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nHOST\tPOOL\tSTATE\tCORES\tMEMORY\tMEM USED\tINSTANCES")
+	for _, h := range view.Hosts {
+		state := "down"
+		if h.Active {
+			state = "up"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%d\n", h.Name, h.PoolID, state, h.Cores,
+			bytefmt.ByteSize(h.Memory), bytefmt.ByteSize(uint64(h.MemoryUsage)), h.Instances)
+	}
+	w.Flush()
+
+	w = tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "\nSERVICE\tPOOL\tSTATE\tINSTANCES\tRAM\tMEM USED\tHEALTH")
+	for _, s := range view.Services {
+		health := s.Health
+		if health == "" {
+			health = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\t%s\t%s\n", s.Name, s.PoolID, s.State, s.Running, s.Instances,
+			bytefmt.ByteSize(s.RAMCommitment), bytefmt.ByteSize(uint64(s.MemoryUsage)), health)
+	}
+	w.Flush()
+	return buf.String()
+}
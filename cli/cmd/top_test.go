@@ -0,0 +1,76 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+)
+
+type TopAPITest struct {
+	api.API
+	fail bool
+}
+
+func (t TopAPITest) WatchTop(cfg api.TopConfig, done <-chan struct{}) (<-chan api.TopView, error) {
+	if t.fail {
+		return nil, errors.New("could not connect to master")
+	}
+	views := make(chan api.TopView, 1)
+	views <- api.TopView{
+		Pools: []api.TopPool{
+			{ID: "default", Hosts: 2, ActiveHosts: 1, CoreCapacity: 8, MemoryCapacity: 2 * 1024 * 1024 * 1024, MemoryCommitment: 1024 * 1024 * 1024, Instances: 3},
+		},
+		Hosts: []api.TopHost{
+			{ID: "host-1", Name: "host-1", PoolID: "default", Active: true, Cores: 4, Memory: 1024 * 1024 * 1024, Instances: 3, MemoryUsage: 512 * 1024 * 1024},
+			{ID: "host-2", Name: "host-2", PoolID: "default", Cores: 4, Memory: 1024 * 1024 * 1024},
+		},
+		Services: []api.TopService{
+			{ID: "svc-1", Name: "zope", PoolID: "default", State: "run", Instances: 2, Running: 2, RAMCommitment: 256 * 1024 * 1024, MemoryUsage: 384 * 1024 * 1024, Health: "passed"},
+			{ID: "svc-2", Name: "redis", PoolID: "default", State: "run", Instances: 1, Running: 1, MemoryUsage: 128 * 1024 * 1024},
+		},
+		Updated: time.Date(2017, 1, 1, 12, 30, 0, 0, time.UTC),
+	}
+	close(views)
+	return views, nil
+}
+
+func ExampleServicedCLI_CmdTop() {
+	RunCmd(TopAPITest{}, "serviced", "top", "--once")
+
+	// Output:
+	// serviced top - 12:30:00
+	//
+	// POOL     HOSTS  CORES  MEMORY  COMMITTED  INSTANCES
+	// default  1/2    8      2G      1G         3
+	//
+	// HOST    POOL     STATE  CORES  MEMORY  MEM USED  INSTANCES
+	// host-1  default  up     4      1G      512M      3
+	// host-2  default  down   4      1G      0         0
+	//
+	// SERVICE  POOL     STATE  INSTANCES  RAM   MEM USED  HEALTH
+	// zope     default  run    2/2        256M  384M      passed
+	// redis    default  run    1/1        0     128M      -
+}
+
+func ExampleServicedCLI_CmdTop_fail() {
+	pipeStderr(func() { RunCmd(TopAPITest{fail: true}, "serviced", "top", "--once") })
+
+	// Output:
+	// could not connect to master
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/volume"
+	"github.com/docker/go-units"
 )
 
 // Initializer for serviced pool subcommands
@@ -41,6 +42,19 @@ func (c *ServicedCli) initVolume() {
 					},
 				},
 			},
+			{
+				Name:         "resize",
+				Usage:        "Grows the volume of a tenant application and sets its quota",
+				Description:  "serviced volume resize { TENANTID | TENANTNAME } SIZE",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdVolumeResize,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "no-prefix-match, np",
+						Usage: "Make TENANTID matches on name strict 'ends with' matches",
+					},
+				},
+			},
 		},
 	})
 }
@@ -60,6 +74,47 @@ func (c *ServicedCli) cmdVolumeStatus(ctx *cli.Context) {
 	return
 }
 
+// serviced volume resize { TENANTID | TENANTNAME } SIZE
+func (c *ServicedCli) cmdVolumeResize(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "resize")
+		c.exit(1)
+		return
+	}
+
+	size, err := units.RAMInBytes(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not parse size: %s\n", err)
+		c.exit(1)
+		return
+	} else if size <= 0 {
+		fmt.Fprintln(os.Stderr, "size must be positive")
+		c.exit(1)
+		return
+	}
+
+	svc, _, err := c.searchForService(args[0], ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	} else if svc.ParentServiceID != "" {
+		fmt.Fprintf(os.Stderr, "service %s is not a tenant application\n", svc.Name)
+		c.exit(1)
+		return
+	}
+
+	quota, err := c.driver.ResizeVolume(svc.ID, uint64(size))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("Resized volume of %s to %s (%s used)\n", svc.Name, units.BytesSize(float64(quota.Limit)), units.BytesSize(float64(quota.Used)))
+}
+
 func printStatuses(statuses *volume.Statuses) {
 	for path, status := range statuses.GetAllStatuses() {
 		fmt.Printf("Status for volume %s:\n", path)
@@ -0,0 +1,50 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"github.com/control-center/serviced/volume"
+)
+
+func (t ServiceAPITest) ResizeVolume(serviceID string, size uint64) (*volume.Quota, error) {
+	if t.errs["ResizeVolume"] != nil {
+		return nil, t.errs["ResizeVolume"]
+	}
+	return &volume.Quota{Limit: size, Used: size / 4}, nil
+}
+
+func ExampleServicedCLI_CmdVolumeResize_works() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "volume", "resize", "test-service-1", "200G") })
+
+	// Output:
+	// Resized volume of Zenoss to 200 GiB (50 GiB used)
+}
+
+func ExampleServicedCLI_CmdVolumeResize_err() {
+	DefaultServiceAPITest.errs["ResizeVolume"] = ErrStub
+	defer func() { DefaultServiceAPITest.errs["ResizeVolume"] = nil }()
+	pipeStderr(func() { InitServiceAPITest("serviced", "volume", "resize", "test-service-1", "200G") })
+
+	// Output:
+	// stub for facade failed
+}
+
+func ExampleServicedCLI_CmdVolumeResize_badSize() {
+	pipeStderr(func() { InitServiceAPITest("serviced", "volume", "resize", "test-service-1", "lots") })
+
+	// Output:
+	// could not parse size: invalid size: 'lots'
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/rpc/master"
+)
+
+// Initializer for serviced zk subcommands
+func (c *ServicedCli) initZK() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "zk",
+		Usage:       "Administers the zookeeper internal service",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "ensemble",
+				Usage:       "Administers the members of the zookeeper ensemble",
+				Description: "",
+				Subcommands: []cli.Command{
+					{
+						Name:        "status",
+						Usage:       "Shows the state of each member of the zookeeper ensemble",
+						Description: "serviced zk ensemble status",
+						Action:      c.cmdZKEnsembleStatus,
+						Flags: []cli.Flag{
+							cli.StringFlag{
+								Name:  "show-fields",
+								Value: "ID,Host,Status",
+								Usage: "Comma-delimited list describing which fields to display",
+							},
+						},
+					}, {
+						Name:        "add",
+						Usage:       "Adds a host to the zookeeper ensemble; zookeeper must already be running on the host",
+						Description: "serviced zk ensemble add HOST",
+						Action:      c.cmdZKEnsembleAdd,
+					}, {
+						Name:        "remove",
+						ShortName:   "rm",
+						Usage:       "Removes a host from the zookeeper ensemble",
+						Description: "serviced zk ensemble remove HOST",
+						Action:      c.cmdZKEnsembleRemove,
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced zk ensemble status
+func (c *ServicedCli) cmdZKEnsembleStatus(ctx *cli.Context) {
+	statuses, err := c.driver.GetZKEnsemble()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	printZKEnsemble(ctx.String("show-fields"), statuses)
+}
+
+// serviced zk ensemble add HOST
+func (c *ServicedCli) cmdZKEnsembleAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	change, err := c.driver.AddZKEnsembleMember(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	printZKEnsembleChange(change, true)
+}
+
+// serviced zk ensemble remove HOST
+func (c *ServicedCli) cmdZKEnsembleRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	change, err := c.driver.RemoveZKEnsembleMember(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	printZKEnsembleChange(change, false)
+}
+
+func printZKEnsemble(fields string, statuses []isvcs.ZKMemberStatus) {
+	t := NewTable(fields)
+	t.Padding = 6
+	for _, s := range statuses {
+		status := s.Mode
+		if !s.Serving() {
+			status = s.Error
+		}
+		t.AddRow(map[string]interface{}{
+			"ID":     s.Member.ID,
+			"Host":   s.Member.Host,
+			"Status": status,
+		})
+	}
+	t.Print()
+}
+
+// printZKEnsembleChange prints the new ensemble and the settings that each
+// host needs so that the change survives a restart.
+func printZKEnsembleChange(change *master.ZKEnsembleChange, add bool) {
+	action := fmt.Sprintf("remove %s from", change.Member)
+	if add {
+		action = fmt.Sprintf("add %s to", change.Member)
+	}
+	quorum := "SERVICED_ISVCS_ZOOKEEPER_QUORUM=" + strings.Join(change.Quorum, ",")
+	servers := "SERVICED_ZK=" + strings.Join(change.Servers, ",")
+	if change.Dynamic {
+		fmt.Printf("Zookeeper ensemble reconfigured to %s it\n\n", action)
+		printZKEnsemble("ID,Host,Status", change.Statuses)
+		fmt.Printf("\nDelegates will use the new ensemble when they next connect. Update the\nconfiguration of each host before it restarts:\n\n  %s\n  %s\n", servers, quorum)
+		return
+	}
+	fmt.Printf("Zookeeper cannot be reconfigured while it is running. To %s the\nensemble, restart one ensemble member at a time with the new configuration,\nwaiting for `serviced zk ensemble status` to report a leader between restarts:\n\n  %s\n  %s\n",
+		action, servers, quorum)
+}
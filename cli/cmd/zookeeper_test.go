@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/utils"
+)
+
+type ZKAPITest struct {
+	api.API
+	members []isvcs.ZKMember
+	dynamic bool
+}
+
+func NewZKAPITest(dynamic bool) ZKAPITest {
+	members, _ := isvcs.ParseZKQuorum([]string{"1@host1:2888:3888", "2@host2:2888:3888", "3@host3:2888:3888"})
+	return ZKAPITest{members: members, dynamic: dynamic}
+}
+
+func (t ZKAPITest) statuses(members []isvcs.ZKMember) []isvcs.ZKMemberStatus {
+	statuses := make([]isvcs.ZKMemberStatus, len(members))
+	for i, m := range members {
+		statuses[i] = isvcs.ZKMemberStatus{Member: m, Mode: "follower"}
+	}
+	statuses[0].Mode = "leader"
+	return statuses
+}
+
+func (t ZKAPITest) change(members []isvcs.ZKMember, member isvcs.ZKMember) *master.ZKEnsembleChange {
+	change := &master.ZKEnsembleChange{
+		Member:  member,
+		Dynamic: t.dynamic,
+		Quorum:  isvcs.FormatZKQuorum(members),
+		Servers: isvcs.ZKClientAddresses(members),
+	}
+	if t.dynamic {
+		change.Statuses = t.statuses(members)
+	}
+	return change
+}
+
+func (t ZKAPITest) GetZKEnsemble() ([]isvcs.ZKMemberStatus, error) {
+	return t.statuses(t.members), nil
+}
+
+func (t ZKAPITest) AddZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	members, member, err := isvcs.AddZKMember(t.members, host)
+	if err != nil {
+		return nil, err
+	}
+	return t.change(members, member), nil
+}
+
+func (t ZKAPITest) RemoveZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	members, member, err := isvcs.RemoveZKMember(t.members, host)
+	if err != nil {
+		return nil, err
+	}
+	return t.change(members, member), nil
+}
+
+func runZKCmd(t ZKAPITest, args ...string) {
+	c := New(t, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdZKEnsembleStatus() {
+	runZKCmd(NewZKAPITest(true), "serviced", "zk", "ensemble", "status")
+
+	// Output:
+	// ID      Host       Status
+	// 1       host1      leader
+	// 2       host2      follower
+	// 3       host3      follower
+}
+
+func ExampleServicedCLI_CmdZKEnsembleAdd() {
+	runZKCmd(NewZKAPITest(true), "serviced", "zk", "ensemble", "add", "host4")
+	pipeStderr(func() { runZKCmd(NewZKAPITest(true), "serviced", "zk", "ensemble", "add", "host1") })
+
+	// Output:
+	// Zookeeper ensemble reconfigured to add 4@host4:2888:3888 to it
+	//
+	// ID      Host       Status
+	// 1       host1      leader
+	// 2       host2      follower
+	// 3       host3      follower
+	// 4       host4      follower
+	//
+	// Delegates will use the new ensemble when they next connect. Update the
+	// configuration of each host before it restarts:
+	//
+	//   SERVICED_ZK=host1:2181,host2:2181,host3:2181,host4:2181
+	//   SERVICED_ISVCS_ZOOKEEPER_QUORUM=1@host1:2888:3888,2@host2:2888:3888,3@host3:2888:3888,4@host4:2888:3888
+	// host is already a member of the zookeeper ensemble
+}
+
+func ExampleServicedCLI_CmdZKEnsembleRemove() {
+	runZKCmd(NewZKAPITest(false), "serviced", "zk", "ensemble", "remove", "host3")
+
+	// Output:
+	// Zookeeper cannot be reconfigured while it is running. To remove 3@host3:2888:3888 from the
+	// ensemble, restart one ensemble member at a time with the new configuration,
+	// waiting for `serviced zk ensemble status` to report a leader between restarts:
+	//
+	//   SERVICED_ZK=host1:2181,host2:2181
+	//   SERVICED_ISVCS_ZOOKEEPER_QUORUM=1@host1:2888:3888,2@host2:2888:3888
+}
@@ -21,3 +21,26 @@ const (
 	STATIC  string = "static"
 	VIRTUAL string = "virtual"
 )
+
+// Docker log drivers that may be selected for service containers
+const (
+	LogDriverJSONFile string = "json-file"
+	LogDriverJournald string = "journald"
+	LogDriverFluentd  string = "fluentd"
+)
+
+// Backends that the application logs of a LogConfig may be shipped to.  An
+// empty shipper ships them to logstash.
+const (
+	LogShipperLogstash string = "logstash"
+	LogShipperSyslog   string = "syslog"
+	LogShipperKafka    string = "kafka"
+)
+
+// Image pull policies that select when a delegate pulls the image of an
+// instance from the docker registry
+const (
+	PullAlways       string = "Always"       // Pull on every start and verify against the registry index
+	PullIfNotPresent string = "IfNotPresent" // Pull only if the indexed image is not on the host
+	PullNever        string = "Never"        // Use the image on the host; never pull
+)
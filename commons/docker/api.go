@@ -16,6 +16,7 @@ package docker
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"sync"
@@ -310,6 +311,33 @@ func (c *Container) Export(outfile *os.File) error {
 	return dc.ExportContainer(dockerclient.ExportContainerOptions{c.ID, outfile})
 }
 
+// Exec runs a command in the container with its streams attached, in a tty
+// of the container if tty is true.  It blocks until the command exits.
+func (c *Container) Exec(cmd []string, tty bool, stdin io.Reader, stdout, stderr io.Writer) error {
+	dc, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	exec, err := dc.CreateExec(dockerclient.CreateExecOptions{
+		Container:    c.ID,
+		Cmd:          cmd,
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          tty,
+	})
+	if err != nil {
+		return err
+	}
+	return dc.StartExec(exec.ID, dockerclient.StartExecOptions{
+		InputStream:  stdin,
+		OutputStream: stdout,
+		ErrorStream:  stderr,
+		Tty:          tty,
+		RawTerminal:  tty,
+	})
+}
+
 // Kill sends a SIGKILL signal to the container. If the container is not started
 // no action is taken.
 func (c *Container) Kill() error {
@@ -320,6 +348,15 @@ func (c *Container) Kill() error {
 	return dc.KillContainer(dockerclient.KillContainerOptions{ID: c.ID, Signal: dockerclient.SIGKILL})
 }
 
+// Signal sends the given signal to the container's main process.
+func (c *Container) Signal(sig dockerclient.Signal) error {
+	dc, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+	return dc.KillContainer(dockerclient.KillContainerOptions{ID: c.ID, Signal: sig})
+}
+
 // Inspect returns information about the container specified by id.
 func (c *Container) Inspect() (*dockerclient.Container, error) {
 	dc, err := getDockerClient()
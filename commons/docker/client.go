@@ -43,6 +43,8 @@ type ClientInterface interface {
 
 	CreateContainer(opts dockerclient.CreateContainerOptions) (*dockerclient.Container, error)
 
+	CreateExec(opts dockerclient.CreateExecOptions) (*dockerclient.Exec, error)
+
 	ExportContainer(opts dockerclient.ExportContainerOptions) error
 
 	ImportImage(opts dockerclient.ImportImageOptions) error
@@ -75,6 +77,8 @@ type ClientInterface interface {
 
 	StartContainer(id string, hostConfig *dockerclient.HostConfig) error
 
+	StartExec(id string, opts dockerclient.StartExecOptions) error
+
 	StopContainer(id string, timeout uint) error
 
 	TagImage(name string, opts dockerclient.TagImageOptions) error
@@ -103,6 +107,14 @@ func (c *Client) CreateContainer(opts dockerclient.CreateContainerOptions) (*doc
 	return c.dc.CreateContainer(opts)
 }
 
+func (c *Client) CreateExec(opts dockerclient.CreateExecOptions) (*dockerclient.Exec, error) {
+	return c.dc.CreateExec(opts)
+}
+
+func (c *Client) StartExec(id string, opts dockerclient.StartExecOptions) error {
+	return c.dc.StartExec(id, opts)
+}
+
 func (c *Client) ExportContainer(opts dockerclient.ExportContainerOptions) error {
 	return c.dc.ExportContainer(opts)
 }
@@ -37,6 +37,15 @@ func (mdc *MockDockerClient) CreateContainer(opts dockerclient.CreateContainerOp
 	return args.Get(0).(*dockerclient.Container), args.Error(1)
 }
 
+func (mdc *MockDockerClient) CreateExec(opts dockerclient.CreateExecOptions) (*dockerclient.Exec, error) {
+	args := mdc.Mock.Called(opts)
+	return args.Get(0).(*dockerclient.Exec), args.Error(1)
+}
+
+func (mdc *MockDockerClient) StartExec(id string, opts dockerclient.StartExecOptions) error {
+	return mdc.Mock.Called(id, opts).Error(0)
+}
+
 func (mdc *MockDockerClient) ExportContainer(opts dockerclient.ExportContainerOptions) error {
 	return mdc.Mock.Called(opts).Error(0)
 }
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -45,6 +46,9 @@ var (
 	underscore rune
 )
 
+// digestPattern matches the content digest of an image
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
 // ImageID represents a Docker Image identifier.
 type ImageID struct {
 	Host string
@@ -52,6 +56,8 @@ type ImageID struct {
 	User string
 	Repo string
 	Tag  string
+	// Digest is the content digest that the image is referenced by, if any
+	Digest string
 }
 
 func init() {
@@ -96,18 +102,27 @@ func RenameImageID(dockerRegistry, tenantId string, imgID string, tag string) (*
 
 // ParseImageID parses the string representation of a Docker image ID into an ImageID structure.
 // The grammar used by the parser is:
-// image id = [host(':'port|'/')]reponame[':'tag]
+// image id = [host(':'port|'/')]reponame[':'tag]['@'digest]
 // host     = {alpha|digit|'.'|'-'}+
 // port     = {digit}+
 // reponame = [user'/']repo
 // user     = {alpha|digit|'-'|'_'}+
 // repo     = {alpha|digit|'-'|'_'|'.'}+
 // tag      = {alpha|digit|'-'|'_'|'.'}+
+// digest   = 'sha256:'{hexdigit}64
 // The grammar is ambiguous so the parser is a little messy in places.
 func ParseImageID(iid string) (*ImageID, error) {
-	scanner := bufio.NewScanner(strings.NewReader(iid))
-	scanner.Split(bufio.ScanRunes)
 	result := &ImageID{}
+	name := iid
+	if at := strings.LastIndex(iid, "@"); at >= 0 {
+		if !ValidImageDigest(iid[at+1:]) {
+			return nil, fmt.Errorf("invalid ImageID %s: bad digest", iid)
+		}
+		result.Digest = iid[at+1:]
+		name = iid[:at]
+	}
+	scanner := bufio.NewScanner(strings.NewReader(name))
+	scanner.Split(bufio.ScanRunes)
 
 	scanned := []string{}
 	tokbuf := []byte{}
@@ -274,6 +289,18 @@ func ParseImageID(iid string) (*ImageID, error) {
 	return result, nil
 }
 
+// ValidImageDigest returns whether the string is the content digest of an
+// image, e.g. sha256:<64 hex digits>.
+func ValidImageDigest(digest string) bool {
+	return digestPattern.MatchString(digest)
+}
+
+// DigestTag returns the tag that pins a repo to the image with the digest.
+// Docker tags cannot contain colons, so sha256:abc... is tagged sha256-abc...
+func DigestTag(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
 // JoinRepoTag joins an image repo with the tag
 func JoinRepoTag(repo, tag string) string {
 	return fmt.Sprintf("%s:%s", repo, tag)
@@ -285,6 +312,10 @@ func (iid ImageID) Equals(iid2 ImageID) bool {
 		return false
 	}
 
+	if iid.Digest != iid2.Digest {
+		return false
+	}
+
 	return iid.Tag == iid2.Tag || (iid.IsLatest() && iid2.IsLatest())
 }
 
@@ -296,10 +327,15 @@ func (iid ImageID) String() string {
 		name = name + ":" + iid.Tag
 	}
 
+	if iid.Digest != "" {
+		name = name + "@" + iid.Digest
+	}
+
 	return name
 }
 
 // BaseName returns a string representation of the ImageID structure sans tag
+// and digest
 func (iid ImageID) BaseName() string {
 	s := []string{}
 
@@ -363,6 +399,7 @@ func (iid *ImageID) Copy() *ImageID {
 	newImage.User = iid.User
 	newImage.Repo = iid.Repo
 	newImage.Tag = iid.Tag
+	newImage.Digest = iid.Digest
 	return newImage
 }
 
@@ -384,5 +421,8 @@ func (iid *ImageID) Merge(new *ImageID) error {
 	if new.Tag != "" {
 		iid.Tag = new.Tag
 	}
+	if new.Digest != "" {
+		iid.Digest = new.Digest
+	}
 	return nil
 }
@@ -290,6 +290,29 @@ var imgidtests = []ImageIDTest{
 		},
 		"",
 	},
+	// user, repo, digest
+	{
+		"zenoss/resmgr@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+		&ImageID{
+			User:   "zenoss",
+			Repo:   "resmgr",
+			Digest: "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+		},
+		"",
+	},
+	// host:port, user, repo, tag, digest
+	{
+		"quay.io:443/zenossinc/resmgr:5.0.0@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+		&ImageID{
+			Host:   "quay.io",
+			Port:   443,
+			User:   "zenossinc",
+			Repo:   "resmgr",
+			Tag:    "5.0.0",
+			Digest: "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+		},
+		"",
+	},
 }
 
 func doTest(c *C, parse func(string) (*ImageID, error), name string, tests []ImageIDTest) {
@@ -317,6 +340,20 @@ func (s *TestCommonsSuite) TestBogusTag(c *C) {
 	c.Assert(err, Not(IsNil))
 }
 
+func (s *TestCommonsSuite) TestBogusDigest(c *C) {
+	_, err := ParseImageID("sierramadre@sha256:1925")
+	c.Assert(err, Not(IsNil))
+	_, err = ParseImageID("sierramadre@")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *TestCommonsSuite) TestDigestTag(c *C) {
+	digest := "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	c.Assert(DigestTag(digest), Equals, "sha256-4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945")
+	iid := &ImageID{Repo: "sierramadre", Tag: DigestTag(digest)}
+	c.Assert(iid.Validate(), Equals, true)
+}
+
 func (s *TestCommonsSuite) TestValidateInvalid(c *C) {
 	iid := &ImageID{
 		Host: "warner.bros",
@@ -367,6 +404,8 @@ func (s *TestCommonsSuite) TestEquals(c *C) {
 		{"warner.bros:1948/dobbs/sierramadre", "warner.bros:1948/dobbs/sierramadre", true},
 		{"warner.bros:1948/dobbs/sierramadre", "niblet3:5000/devimg", false},
 		{"warner.bros:1948/dobbs/sierramadre", "niblet3:5000/devimg:latest", false},
+		{"dobbs/sierramadre@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945", "dobbs/sierramadre@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945", true},
+		{"dobbs/sierramadre@sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945", "dobbs/sierramadre", false},
 	}
 	doImageEqualsTest(c, tests)
 }
@@ -439,16 +478,17 @@ func (s *TestCommonsSuite) TestMerge(c *C) {
 		"user",
 		"repo",
 		"tag",
+		"",
 	}
 
 	img1 := img1_orig.Copy()
-	img2 := &ImageID{"host2", 2, "user2", "repo2", "tag2"}
+	img2 := &ImageID{"host2", 2, "user2", "repo2", "tag2", "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"}
 	img1.Merge(img2)
 	c.Assert(img2, DeepEquals, img1)
 
 	img1 = img1_orig.Copy()
 	img1.Merge(&ImageID{Repo: "apples"})
-	c.Assert(img1, DeepEquals, &ImageID{"host", 1, "user", "apples", "tag"})
+	c.Assert(img1, DeepEquals, &ImageID{"host", 1, "user", "apples", "tag", ""})
 
 	img1 = img1_orig.Copy()
 	img1.Merge(&ImageID{})
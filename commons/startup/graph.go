@@ -0,0 +1,233 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package startup runs the steps of a process's startup as a dependency graph,
+// starting each step as soon as the steps it requires have completed.
+package startup
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State is the progress of a startup step
+type State string
+
+const (
+	// StatePending means that the step is waiting on its dependencies
+	StatePending State = "pending"
+
+	// StateRunning means that the step has started
+	StateRunning State = "running"
+
+	// StateDone means that the step completed successfully
+	StateDone State = "done"
+
+	// StateFailed means that the step returned an error
+	StateFailed State = "failed"
+
+	// StateSkipped means that the step never ran because startup failed
+	StateSkipped State = "skipped"
+)
+
+// Step is a unit of startup work
+type Step struct {
+	Name     string
+	Requires []string
+	Hint     string // what to check if the step fails
+	Run      func() error
+}
+
+// Status is the progress of a startup step
+type Status struct {
+	Name     string
+	Requires []string
+	State    State
+	Started  time.Time
+	Duration time.Duration
+	Error    string
+	Hint     string
+}
+
+// DependencyError reports the startup step that failed and the steps that
+// could not run because of it.
+type DependencyError struct {
+	Step    string
+	Hint    string
+	Err     error
+	Blocked []string
+}
+
+func (err *DependencyError) Error() string {
+	msg := fmt.Sprintf("startup step %q failed: %s", err.Step, err.Err)
+	if err.Hint != "" {
+		msg += "; " + err.Hint
+	}
+	return msg
+}
+
+// Graph runs a set of startup steps in dependency order
+type Graph struct {
+	mu     sync.Mutex
+	steps  []Step
+	status []Status
+}
+
+// NewGraph returns a graph of the given steps.  A step may only require steps
+// that precede it, which keeps the graph free of cycles.
+func NewGraph(steps ...Step) (*Graph, error) {
+	g := &Graph{
+		steps:  steps,
+		status: make([]Status, len(steps)),
+	}
+	index := make(map[string]int)
+	for i, step := range steps {
+		if _, ok := index[step.Name]; ok {
+			return nil, fmt.Errorf("duplicate startup step %q", step.Name)
+		}
+		for _, name := range step.Requires {
+			if _, ok := index[name]; !ok {
+				return nil, fmt.Errorf("startup step %q requires unknown or later step %q", step.Name, name)
+			}
+		}
+		index[step.Name] = i
+		g.status[i] = Status{
+			Name:     step.Name,
+			Requires: step.Requires,
+			State:    StatePending,
+			Hint:     step.Hint,
+		}
+	}
+	return g, nil
+}
+
+// Run starts each step once all of the steps it requires are done, running
+// independent steps in parallel.  After the first failure no new steps are
+// started; Run waits for the steps already running and returns a
+// *DependencyError for the failed step.
+func (g *Graph) Run() error {
+	type result struct {
+		i   int
+		err error
+	}
+	results := make(chan result)
+	running := 0
+	var failed *DependencyError
+
+	for {
+		if failed == nil {
+			for _, i := range g.ready() {
+				g.setRunning(i)
+				running++
+				go func(i int) {
+					results <- result{i, g.steps[i].Run()}
+				}(i)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		r := <-results
+		running--
+		g.setDone(r.i, r.err)
+		if r.err != nil && failed == nil {
+			failed = &DependencyError{
+				Step: g.steps[r.i].Name,
+				Hint: g.steps[r.i].Hint,
+				Err:  r.err,
+			}
+		}
+	}
+
+	if failed != nil {
+		failed.Blocked = g.skipPending()
+		return failed
+	}
+	return nil
+}
+
+// Progress returns the status of every step
+func (g *Graph) Progress() []Status {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	status := make([]Status, len(g.status))
+	copy(status, g.status)
+	for i := range status {
+		if status[i].State == StateRunning {
+			status[i].Duration = time.Since(status[i].Started)
+		}
+	}
+	return status
+}
+
+// ready returns the pending steps whose dependencies are done
+func (g *Graph) ready() []int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	state := make(map[string]State)
+	for _, s := range g.status {
+		state[s.Name] = s.State
+	}
+	var ready []int
+	for i, s := range g.status {
+		if s.State != StatePending {
+			continue
+		}
+		ok := true
+		for _, name := range s.Requires {
+			if state[name] != StateDone {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ready = append(ready, i)
+		}
+	}
+	return ready
+}
+
+func (g *Graph) setRunning(i int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.status[i].State = StateRunning
+	g.status[i].Started = time.Now()
+}
+
+func (g *Graph) setDone(i int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.status[i].Duration = time.Since(g.status[i].Started)
+	if err != nil {
+		g.status[i].State = StateFailed
+		g.status[i].Error = err.Error()
+	} else {
+		g.status[i].State = StateDone
+	}
+}
+
+// skipPending marks the steps that never started as skipped and returns
+// their names
+func (g *Graph) skipPending() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var skipped []string
+	for i := range g.status {
+		if g.status[i].State == StatePending {
+			g.status[i].State = StateSkipped
+			skipped = append(skipped, g.status[i].Name)
+		}
+	}
+	return skipped
+}
@@ -0,0 +1,106 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package startup
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+type StartupSuite struct{}
+
+var _ = Suite(&StartupSuite{})
+
+func TestStartup(t *testing.T) { TestingT(t) }
+
+func (s *StartupSuite) TestNewGraph_Invalid(c *C) {
+	noop := func() error { return nil }
+
+	_, err := NewGraph(Step{Name: "a", Run: noop}, Step{Name: "a", Run: noop})
+	c.Assert(err, NotNil)
+
+	_, err = NewGraph(Step{Name: "a", Requires: []string{"b"}, Run: noop}, Step{Name: "b", Run: noop})
+	c.Assert(err, NotNil)
+}
+
+func (s *StartupSuite) TestRun_Order(c *C) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() error {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	// b and c both wait on each other through the channel, so they can only
+	// finish if they run in parallel
+	bc := make(chan struct{})
+	g, err := NewGraph(
+		Step{Name: "a", Run: record("a")},
+		Step{Name: "b", Requires: []string{"a"}, Run: func() error {
+			bc <- struct{}{}
+			return record("b")()
+		}},
+		Step{Name: "c", Requires: []string{"a"}, Run: func() error {
+			<-bc
+			return record("c")()
+		}},
+		Step{Name: "d", Requires: []string{"b", "c"}, Run: record("d")},
+	)
+	c.Assert(err, IsNil)
+	c.Assert(g.Run(), IsNil)
+
+	c.Assert(order, HasLen, 4)
+	c.Check(order[0], Equals, "a")
+	c.Check(order[3], Equals, "d")
+	for _, status := range g.Progress() {
+		c.Check(status.State, Equals, StateDone)
+	}
+}
+
+func (s *StartupSuite) TestRun_Failure(c *C) {
+	ran := false
+	g, err := NewGraph(
+		Step{Name: "a", Run: func() error { return nil }},
+		Step{Name: "b", Requires: []string{"a"}, Hint: "check b", Run: func() error { return errors.New("b broke") }},
+		Step{Name: "c", Requires: []string{"b"}, Run: func() error {
+			ran = true
+			return nil
+		}},
+	)
+	c.Assert(err, IsNil)
+
+	err = g.Run()
+	derr, ok := err.(*DependencyError)
+	c.Assert(ok, Equals, true)
+	c.Check(derr.Step, Equals, "b")
+	c.Check(derr.Blocked, DeepEquals, []string{"c"})
+	c.Check(derr.Error(), Equals, `startup step "b" failed: b broke; check b`)
+	c.Check(ran, Equals, false)
+
+	progress := g.Progress()
+	c.Check(progress[0].State, Equals, StateDone)
+	c.Check(progress[1].State, Equals, StateFailed)
+	c.Check(progress[1].Error, Equals, "b broke")
+	c.Check(progress[2].State, Equals, StateSkipped)
+}
@@ -62,6 +62,8 @@ type Options struct {
 	ResourcePath               string
 	LogPath                    string // Serviced logs directory
 	Zookeepers                 []string
+	CoordinatorDriver          string   // The coordinator backend to use (zookeeper or etcd)
+	EtcdEndpoints              []string // The etcd endpoints to connect to when the coordinator driver is etcd
 	ReportStats                bool
 	HostStats                  string
 	StatsPeriod                int
@@ -94,6 +96,7 @@ type Options struct {
 	RPCDialTimeout             int
 	RPCCertVerify              string            //  server certificate verify for rpc connections, string val of bool
 	RPCDisableTLS              string            //  Disable TLS for RPC connections, string val of bool
+	RPCUseGRPC                 string            //  Use gRPC for RPC connections when the server supports it, string val of bool
 	RPCTLSCiphers              []string          // List of tls ciphers supported for rpc
 	RPCTLSMinVersion           string            // Minimum TLS version supported for rpc
 	SnapshotTTL                int               // hours to keep snapshots around, zero for infinity
@@ -102,6 +105,7 @@ type Options struct {
 	ControllerBinary           string            // Path to the container controller binary
 	StartISVCS                 []string          // ISVCS to start when running as an agent
 	IsvcsENV                   []string          // Isvcs env variables
+	IsvcsRestartPolicies       []string          // Isvcs restart policies
 	IsvcsZKID                  int               // Zookeeper server id when running as a quorum
 	IsvcsZKQuorum              []string          // Members of the zookeeper quorum
 	IsvcsZKUsername            string            // Zookeeper username required for quorum authentication
@@ -114,13 +118,16 @@ type Options struct {
 	UIPollFrequency            int               // frequency in seconds that UI should poll for service changes
 	StorageStatsUpdateInterval int               // frequency in seconds that low-level devicemapper storage stats should be refreshed
 	SnapshotSpacePercent       int               // Percent of tenant volume size that is assumed to be needed to create a snapshot
+	BackupCompression          string            // The codec that compresses backup files (gzip, zstd or none)
 	ZKSessionTimeout           int               // The session timeout of a zookeeper client connection.
 	ZKConnectTimeout           int               // The network connect timeout, in seconds, for a zookeeper client connection.
 	ZKPerHostConnectDelay      int               // The delay, in seconds, between connection attempts to other zookeeper servers.
 	ZKReconnectStartDelay      int               // The initial delay, in seconds, before attempting to reconnect after none of the zookeepers are reachable
 	ZKReconnectMaxDelay        int               // The maximum delay, in seconds, before attempting to reconnect after none of the zookeepers are reachable
 	TokenExpiration            int               // The time in seconds before an authentication token expires
+	TokenGracePeriod           int               // The time in seconds an expired authentication token stays in use while the master is unreachable
 	ConntrackFlush             string            // Whether to flush the conntrack table when a service with an assigned IP is started
+	PreserveContainers         string            // Whether a delegate leaves its containers running when it stops, so they are re-adopted when it restarts
 	LogConfigFilename          string            // Path to the logri configuration
 	StorageReportInterval      int               // frequency in seconds to report storage stats to opentsdb
 	ServiceRunLevelTimeout     int               // The time in seconds serviced will wait for a batch of services to stop/start before moving to services with the next run level
@@ -132,6 +139,10 @@ type Options struct {
 	StartZK                    bool              // Should ZooKeeper ISVC be started
 	StartAPIKeyProxy           bool              // Should API Key Proxy ISVC be started
 	BigTableMetrics            bool              // Should serviced metrics be stored in gcp bigtable
+	NFSv4Only                  bool              // Should the DFS be exported over NFSv4 only
+	DFSNetworkDriver           string            // The driver that shares the DFS with remote hosts (nfs or rbd)
+	RBDPool                    string            // The ceph pool of the images that back the DFS when using the rbd driver
+	RBDImageSize               int               // The size in megabytes of new tenant images when using the rbd driver
 	Auth0Domain                string            // Domain configured for tenant in Auth0. Ref: https://auth0.com/docs/getting-started/the-basics#domain
 	Auth0Audience              string            // Audience configured for application (?) in Auth0
 	Auth0Group                 []string          // Group membership(s) required in Auth0 token for login, comma separated list
@@ -139,6 +150,38 @@ type Options struct {
 	Auth0Scope                 string            // Auth0 Scope for request.
 	KeyProxyJsonServer         string            // Address of api-key-server endpoint for getting CC Access tokens
 	KeyProxyListenPort         string            // Port where api-key-proxy will listen
+	AdmissionWebhooks          string            // Path to a json file of the webhooks that review control plane changes
+	SchedulerPlugins           string            // Path to a json file of the score plugins and weights of the scheduler
+	DockerRegistryMirrors      []string          // Registries that are pulled through a mirror, as UPSTREAM=MIRROR[/PREFIX]
+	DockerPullRetries          int               // Number of times an image push or pull is attempted before giving up
+	DockerPullConcurrency      int               // Number of images that are pulled at the same time during a registry upgrade
+	BackupLogstashDays         int               // Days of logstash indices to include in backups, 0 to leave them out
+	BackupLogstashMaxSize      int               // Max size in gigabytes of the logstash indices included in backups
+	MasterBootstrap            bool              // Rebuild the master database from the state of the existing cluster on startup
+	MasterHA                   bool              // Run as one of several masters that serve only while holding the master lease
+	MasterHAScript             string            // Script run with acquire before taking over as the master and with release after losing the lease
+	MaxHealthChecks            int               // Number of health checks that may run at the same time on a host, 0 for one per cpu
+	MaxContainerStarts         int               // Number of containers that a host starts at the same time, 0 for no limit
+	ImagePullPolicy            string            // When delegates pull the images of services that do not select a policy (Always, IfNotPresent or Never)
+	ACMEDirectory              string            // Directory url of the ACME certificate authority of the vhost certificates, empty to manage them manually
+	ACMEEmail                  string            // Contact email of the ACME account
+	ACMEDomain                 string            // Domain of the host names of the vhosts whose names have no dot
+	TracingCollector           string            // Address of the OTLP/HTTP collector that the spans of services with tracing are forwarded to
+	MetricsRollup              int               // Seconds of the summaries that delegates roll metrics up into (10 or 60), 0 to post every sample to the master
+	MetricsAggregatorPort      int               // Local port of the delegate metrics aggregator that the instances post to
+	MetricsSpoolPath           string            // Directory of the metrics that delegates could not ship to the master
+	MetricsSpoolMaxSize        int               // Max size in megabytes of the metrics spool of a delegate, 0 for no limit
+	EventMaxDays               int               // Days to keep the events of the cluster, 0 to keep them forever
+	EventMaxCount              int               // Max number of events of the cluster to keep, 0 for no limit
+	LogShipperPort             int               // Local port of the delegate log shipper that the instances post syslog and kafka logs to, 0 to disable it
+	LogSyslogAddress           string            // Address of the syslog server of log configs with the syslog shipper (udp://, tcp:// or tls://host:port)
+	LogSyslogCAFile            string            // CA certificate file that verifies a tls syslog server, empty for the roots of the host
+	LogKafkaURL                string            // Url of the Kafka REST proxy of log configs with the kafka shipper
+	LogKafkaTopic              string            // Kafka topic that the logs are produced to
+	RelayPort                  int               // Local port of the relay of attach and logs streams on every host, 0 to disable it
+	RelayPolicy                string            // Which hosts the master relays attach and logs streams for (disabled, admin, pool or all)
+	AttachRelay                string            // When the CLI relays attach and logs through the master (auto, always or never)
+	MaintenancePage            string            // Path to the html page that the public endpoints of tenants in maintenance mode serve, empty for the built-in page
 
 }
 
@@ -21,6 +21,7 @@ import (
 	coordclient "github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/health"
@@ -107,6 +108,7 @@ type ControllerOptions struct {
 	MetricForwarding     bool   // Whether or not the Controller should forward metrics
 	HostIPs              string // The ip addresses of the host
 	ServiceNamePath      string // Path of the service
+	TracingCollector     string // The collector that the spans of the service are forwarded to
 }
 
 // Controller is a object to manage the operations withing a container. For example,
@@ -119,6 +121,7 @@ type Controller struct {
 	metricForwarder    *MetricForwarder
 	logforwarder       *subprocess.Instance
 	logforwarderExited chan error
+	logTailer          *logTailer
 	closing            chan chan error
 	prereqs            []domain.Prereq
 	zkInfo             node.ZkInfo
@@ -127,6 +130,7 @@ type Controller struct {
 	exitStatus         int
 	endpoints          *ContainerEndpoints
 	healthChecks       map[string]health.HealthCheck
+	readiness          *readinessGates
 	ccApiProxy         *servicedApiProxy
 }
 
@@ -158,6 +162,24 @@ func getService(lbClientPort string, serviceID string, instanceID int) (*service
 	return &evaluatedServiceResponse.Service, evaluatedServiceResponse.TenantID, evaluatedServiceResponse.ServiceNamePath, nil
 }
 
+// getServiceSecrets retrieves the values of the secrets that the config files
+// of a service instance reference
+func getServiceSecrets(lbClientPort string, serviceID string, instanceID int) (map[string]string, error) {
+	client, err := node.NewLBClient(lbClientPort)
+	if err != nil {
+		glog.Errorf("Could not create a client to endpoint: %s, %s", lbClientPort, err)
+		return nil, err
+	}
+	defer client.Close()
+
+	var values map[string]string
+	if err := client.GetServiceSecrets(node.EvaluateServiceRequest{ServiceID: serviceID, InstanceID: instanceID}, &values); err != nil {
+		glog.Errorf("Error getting secrets for service %s error: %s", serviceID, err)
+		return nil, err
+	}
+	return values, nil
+}
+
 // getAgentHostID retrieves the agent's host id
 func getAgentHostID(lbClientPort string) (string, error) {
 	client, err := node.NewLBClient(lbClientPort)
@@ -259,6 +281,30 @@ func setupConfigFiles(svc *service.Service) error {
 	return nil
 }
 
+// setupSecrets replaces the secret references in the config files with their
+// values.  The values are only written to the config files in the container.
+func setupSecrets(lbClientPort string, svc *service.Service, instanceID int) error {
+	referenced := false
+	for _, config := range svc.ConfigFiles {
+		referenced = referenced || len(secret.References(config.Content)) > 0
+	}
+	if !referenced {
+		return nil
+	}
+
+	values, err := getServiceSecrets(lbClientPort, svc.ID, instanceID)
+	if err != nil {
+		return err
+	}
+	for key, config := range svc.ConfigFiles {
+		if config.Content, err = secret.Render(config.Content, values); err != nil {
+			return fmt.Errorf("%s: %s", config.Filename, err)
+		}
+		svc.ConfigFiles[key] = config
+	}
+	return nil
+}
+
 // setupLogstashFiles sets up logstash files
 func setupLogstashFiles(hostID string, hostIPs string, svcPath string, service *service.Service, instanceID string, logforwarderOptions LogforwarderOptions) error {
 	// write out logstash files
@@ -331,6 +377,12 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		}
 	}
 
+	// render secrets into the config files
+	if err := setupSecrets(options.ServicedEndpoint, service, instanceID); err != nil {
+		glog.Errorf("Could not render secrets in config files error:%s", err)
+		return c, fmt.Errorf("container: invalid ConfigFiles secrets error:%s", err)
+	}
+
 	// create config files
 	if err := setupConfigFiles(service); err != nil {
 		glog.Errorf("Could not setup config files error:%s", err)
@@ -345,6 +397,10 @@ func NewController(options ControllerOptions) (*Controller, error) {
 	}
 
 	if options.Logforwarder.Enabled && len(service.LogConfigs) > 0 {
+		c.logTailer = newLogTailer(c.hostID, options.HostIPs, options.ServiceNamePath, service, options.Service.InstanceID)
+	}
+
+	if options.Logforwarder.Enabled && hasLogstashConfigs(service) {
 		if err := setupLogstashFiles(c.hostID, options.HostIPs, options.ServiceNamePath, service,
 			options.Service.InstanceID, options.Logforwarder); err != nil {
 			glog.Errorf("Could not setup logstash files error:%s", err)
@@ -411,7 +467,11 @@ func NewController(options ControllerOptions) (*Controller, error) {
 
 	// endpoints are created at the root level (not pool aware)
 	rootBasePath := ""
-	zClient, err := coordclient.New("zookeeper", c.zkInfo.ZkDSN, rootBasePath, nil)
+	driver := c.zkInfo.Driver
+	if driver == "" {
+		driver = "zookeeper"
+	}
+	zClient, err := coordclient.New(driver, c.zkInfo.ZkDSN, rootBasePath, nil)
 	if err != nil {
 		glog.Errorf("failed create a new coordclient: %v", err)
 		return c, err
@@ -434,6 +494,14 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		return c, err
 	}
 
+	// keep the readiness gates that must pass before the exports are
+	// registered
+	c.readiness = &readinessGates{
+		gates:            service.ReadinessGates,
+		request:          c.endpoints.ReadinessRequest(service.Name),
+		getServiceHealth: c.getServiceHealth,
+	}
+
 	// CC Rest API proxy
 	c.ccApiProxy = newServicedApiProxy()
 
@@ -648,11 +716,34 @@ func (c *Controller) Run() (err error) {
 	var service *subprocess.Instance = nil
 	serviceExited := make(chan error, 1)
 	endpointExit := make(chan struct{})
-	c.endpoints.Run(endpointExit)
+	c.endpoints.RunImports(endpointExit)
+
+	// the instance receives traffic once its exports are registered, which
+	// waits for its readiness gates to pass
+	go func() {
+		if c.readiness.wait(endpointExit) {
+			c.endpoints.RunExports(endpointExit)
+		}
+	}()
 
 	// Start CC Rest API Proxy
 	go c.ccApiProxy.run()
 
+	// Forward the spans of the service to the tracing collector
+	if c.options.TracingCollector != "" {
+		forwarder, err := newTracingForwarder(node.TracingForwarderAddress, c.options.TracingCollector)
+		if err != nil {
+			glog.Errorf("Could not start the tracing forwarder: %s", err)
+		} else {
+			go forwarder.run(endpointExit)
+		}
+	}
+
+	// Ship the logs that do not go to logstash to the delegate log shipper
+	if c.logTailer != nil {
+		go c.logTailer.run(endpointExit)
+	}
+
 	// HACK: I guess this is how it used to work?  This code is horrible.
 	go func() {
 		errc := <-c.closing
@@ -799,6 +890,20 @@ func (c *Controller) checkPrereqs(prereqsPassed chan bool, rpcDead chan struct{}
 	}
 }
 
+// getServiceHealth returns the health checks of the instances of a service
+func (c *Controller) getServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	client, err := node.NewLBClient(c.options.ServicedEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	var statuses map[int]map[string]health.HealthStatus
+	if err := client.GetServiceHealth(serviceID, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
 func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	client, err := node.NewLBClient(c.options.ServicedEndpoint)
 	if err != nil {
@@ -812,31 +917,39 @@ func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 		glog.Errorf("Invalid instance from instanceID:%s", c.options.Service.InstanceID)
 		return
 	}
-	for name, hc := range c.healthChecks {
-		glog.Infof("Kicking off health check %s.", name)
-		glog.Infof("Setting up health check: %s", hc.Script)
-		key := health.HealthStatusKey{
-			ServiceID:       c.options.Service.ID,
-			InstanceID:      instanceID,
-			HealthCheckName: name,
+	key := health.HealthStatusKey{
+		ServiceID:  c.options.Service.ID,
+		InstanceID: instanceID,
+	}
+	for _, batch := range health.NewBatches(c.healthChecks) {
+		for _, name := range batch.Names() {
+			glog.Infof("Kicking off health check %s.", name)
+			glog.Infof("Setting up health check: %s", batch.Checks[name].Script)
 		}
-		go c.doHealthCheck(healthExit, key, hc)
+		go c.doHealthChecks(healthExit, key, batch)
 	}
 	return
 }
 
-func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStatusKey, hc health.HealthCheck) {
-	logger := plog.WithFields(log.Fields{
-		"service":     key.ServiceID,
-		"instance":    key.InstanceID,
-		"healthcheck": key.HealthCheckName,
-	})
-	hc.Ping(cancel, key, func(stat health.HealthStatus) {
+// doHealthChecks runs a batch of health checks that share an interval and
+// reports their results.
+func (c *Controller) doHealthChecks(cancel <-chan struct{}, key health.HealthStatusKey, batch *health.Batch) {
+	batch.Ping(cancel, key, c.acquireHealthCheckSlots(batch.Interval), func(name string, hc *health.HealthCheck, stat health.HealthStatus) {
+		key := key
+		key.HealthCheckName = name
+		logger := plog.WithFields(log.Fields{
+			"service":     key.ServiceID,
+			"instance":    key.InstanceID,
+			"healthcheck": key.HealthCheckName,
+		})
 		req := master.HealthStatusRequest{
 			Key:     key,
 			Value:   stat,
 			Expires: hc.Expires(),
 		}
+		if stat.Status == health.Failed {
+			req.Remediation = hc.GetRemediation()
+		}
 		client, err := node.NewLBClient(c.options.ServicedEndpoint)
 		if err != nil {
 			glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
@@ -851,6 +964,35 @@ func (c *Controller) doHealthCheck(cancel <-chan struct{}, key health.HealthStat
 	})
 }
 
+// acquireHealthCheckSlots returns a function that waits up to one interval
+// for the host agent to admit a batch of health checks.  If the agent is
+// unreachable or the wait runs out, the batch runs anyway rather than
+// report stale results.
+func (c *Controller) acquireHealthCheckSlots(interval time.Duration) health.Acquirer {
+	return func(slots int, budget time.Duration) func() {
+		client, err := node.NewLBClient(c.options.ServicedEndpoint)
+		if err != nil {
+			glog.Errorf("Could not create a client to endpoint: %s, %s", c.options.ServicedEndpoint, err)
+			return func() {}
+		}
+		req := node.HealthCheckSlotRequest{
+			Slots:  slots,
+			Budget: budget,
+			Wait:   interval,
+		}
+		var leaseID string
+		if err := client.AcquireHealthCheckSlots(req, &leaseID); err != nil {
+			plog.WithError(err).Debug("Running health checks without a slot")
+			client.Close()
+			return func() {}
+		}
+		return func() {
+			client.ReleaseHealthCheckSlots(leaseID, nil)
+			client.Close()
+		}
+	}
+}
+
 func (c *Controller) handleControlCenterImports(rpcdead chan struct{}) error {
 	// this function is currently needed to handle special control center imports
 	// from GetISvcEndpoints() that do not exist in endpoints from getServiceState
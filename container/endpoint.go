@@ -19,11 +19,13 @@ import (
 	"net"
 	"os"
 	"regexp"
+	"strconv"
 	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
@@ -185,18 +187,51 @@ func (ce *ContainerEndpoints) loadState(svc *service.Service) (bool, error) {
 
 // Run manages the container endpoints
 func (ce *ContainerEndpoints) Run(cancel <-chan struct{}) {
+	ce.RunImports(cancel)
+	ce.RunExports(cancel)
+}
 
-	// register all of the exports
+// RunExports registers the exports of the instance, so that it receives
+// traffic
+func (ce *ContainerEndpoints) RunExports(cancel <-chan struct{}) {
 	for _, bind := range ce.state.Exports {
-		ce.ports[bind.PortNumber] = struct{}{}
 		go ce.AddExport(cancel, bind)
 	}
+}
+
+// RunImports tracks the imports of the instance
+func (ce *ContainerEndpoints) RunImports(cancel <-chan struct{}) {
+	// reserve the ports of the exports, which may be registered later
+	for _, bind := range ce.state.Exports {
+		ce.ports[bind.PortNumber] = struct{}{}
+	}
 
-	// track all of the imports
 	// TODO: set up another tracker for cc exports
 	go ce.RunImportListener(cancel, ce.opts.TenantID, ce.state.Imports...)
 }
 
+// ReadinessRequest describes the instance to the webhooks of its readiness
+// gates
+func (ce *ContainerEndpoints) ReadinessRequest(serviceName string) servicedefinition.ReadinessRequest {
+	req := servicedefinition.ReadinessRequest{
+		ServiceID:   ce.state.ServiceID,
+		ServiceName: serviceName,
+		InstanceID:  ce.state.InstanceID,
+		HostID:      ce.state.HostID,
+		HostIP:      ce.state.HostIP,
+		PrivateIP:   ce.state.PrivateIP,
+		Exports:     make([]servicedefinition.ReadinessExport, len(ce.state.Exports)),
+	}
+	for i, bind := range ce.state.Exports {
+		req.Exports[i] = servicedefinition.ReadinessExport{
+			Application: bind.Application,
+			Protocol:    bind.Protocol,
+			PortNumber:  bind.PortNumber,
+		}
+	}
+	return req
+}
+
 // AddExport ensures that an export is registered for other services to bind
 func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.ExportBinding) {
 	logger := plog.WithFields(log.Fields{
@@ -527,6 +562,7 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 			c.useTLS,
 			listener,
 			c.allowDirect,
+			importStats.get(application),
 		)
 		if err != nil {
 			logger.WithError(err).Debug("Could not start proxy")
@@ -543,7 +579,7 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 	for i, export := range exports {
 		addresses[i] = addressTuple{
 			host:          export.HostIP,
-			containerAddr: fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber),
+			containerAddr: net.JoinHostPort(export.PrivateIP, strconv.Itoa(int(export.PortNumber))),
 		}
 	}
 	prxy.SetNewAddresses(addresses)
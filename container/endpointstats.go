@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/control-center/serviced/stats"
+)
+
+// importStats counts the connections of the import proxies of the container
+var importStats = newEndpointStats()
+
+// endpointCounters are the connection counts of the proxies of an imported
+// application.  All but active are cumulative.
+type endpointCounters struct {
+	connections   int64
+	active        int64
+	connectErrors int64
+	bytesSent     int64
+	bytesReceived int64
+}
+
+// opened counts a connection that was proxied
+func (c *endpointCounters) opened() {
+	atomic.AddInt64(&c.connections, 1)
+	atomic.AddInt64(&c.active, 1)
+}
+
+// closed counts the end of a proxied connection and the bytes sent to and
+// received from the remote endpoint
+func (c *endpointCounters) closed(sent, received int64) {
+	atomic.AddInt64(&c.active, -1)
+	atomic.AddInt64(&c.bytesSent, sent)
+	atomic.AddInt64(&c.bytesReceived, received)
+}
+
+// failed counts a connection that could not reach the remote endpoint
+func (c *endpointCounters) failed() {
+	atomic.AddInt64(&c.connectErrors, 1)
+}
+
+// endpointStats are the connection counts of each imported application
+type endpointStats struct {
+	mu   sync.Mutex
+	apps map[string]*endpointCounters
+}
+
+func newEndpointStats() *endpointStats {
+	return &endpointStats{apps: make(map[string]*endpointCounters)}
+}
+
+// get returns the counters of the application
+func (s *endpointStats) get(application string) *endpointCounters {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.apps[application]
+	if !ok {
+		c = &endpointCounters{}
+		s.apps[application] = c
+	}
+	return c
+}
+
+// samples returns the counters as metric samples, tagged by application
+func (s *endpointStats) samples(now int64) []stats.Sample {
+	s.mu.Lock()
+	apps := make([]string, 0, len(s.apps))
+	for app := range s.apps {
+		apps = append(apps, app)
+	}
+	s.mu.Unlock()
+	sort.Strings(apps)
+
+	samples := []stats.Sample{}
+	for _, app := range apps {
+		c := s.get(app)
+		tags := map[string]string{"application": app, "purpose": "import"}
+		for _, v := range []struct {
+			metric string
+			value  *int64
+		}{
+			{"endpoint.connections", &c.connections},
+			{"endpoint.connections.active", &c.active},
+			{"endpoint.connect.errors", &c.connectErrors},
+			{"endpoint.bytes.sent", &c.bytesSent},
+			{"endpoint.bytes.received", &c.bytesReceived},
+		} {
+			samples = append(samples, stats.Sample{
+				Metric:    v.metric,
+				Value:     strconv.FormatInt(atomic.LoadInt64(v.value), 10),
+				Timestamp: now,
+				Tags:      tags,
+			})
+		}
+	}
+	return samples
+}
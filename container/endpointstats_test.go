@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package container
+
+import (
+	"testing"
+)
+
+func TestEndpointStats(t *testing.T) {
+	s := newEndpointStats()
+	c := s.get("zodb")
+	c.opened()
+	c.opened()
+	c.closed(10, 20)
+	c.failed()
+	if s.get("zodb") != c {
+		t.Fatalf("expected the same counters for the application")
+	}
+
+	samples := s.samples(100)
+	if len(samples) != 5 {
+		t.Fatalf("expected 5 samples, got %d", len(samples))
+	}
+	expected := map[string]string{
+		"endpoint.connections":        "2",
+		"endpoint.connections.active": "1",
+		"endpoint.connect.errors":     "1",
+		"endpoint.bytes.sent":         "10",
+		"endpoint.bytes.received":     "20",
+	}
+	for _, sample := range samples {
+		if sample.Value != expected[sample.Metric] {
+			t.Errorf("expected %s to be %s, got %s", sample.Metric, expected[sample.Metric], sample.Value)
+		}
+		if sample.Tags["application"] != "zodb" || sample.Timestamp != 100 {
+			t.Errorf("unexpected sample %+v", sample)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/logship"
+)
+
+const (
+	// logShipperURL is where the delegate log shipper is mapped in the
+	// container
+	logShipperURL = "http://127.0.0.1:5045/api/logs"
+
+	// logTailInterval is how often the log files are checked for new lines
+	logTailInterval = time.Second
+
+	// logTailMaxRead bounds what is read from a file and shipped at once
+	logTailMaxRead = 1024 * 1024
+
+	// logTailRetry is how long the tailer waits after the shipper failed
+	logTailRetry = 10 * time.Second
+)
+
+// isLogstashConfig returns true if the log is shipped to logstash by filebeat
+func isLogstashConfig(logConfig servicedefinition.LogConfig) bool {
+	return logConfig.Shipper == "" || logConfig.Shipper == commons.LogShipperLogstash
+}
+
+// hasLogstashConfigs returns true if filebeat ships any logs of the service
+func hasLogstashConfigs(svc *service.Service) bool {
+	for _, logConfig := range svc.LogConfigs {
+		if isLogstashConfig(logConfig) {
+			return true
+		}
+	}
+	return false
+}
+
+// tailedLog is a log config whose files are shipped by the log tailer
+type tailedLog struct {
+	shipper string
+	pattern string
+	fields  map[string]string
+}
+
+// logTailer ships the new lines of the files of log configs that go to
+// syslog or kafka to the log shipper of the delegate, since filebeat only
+// ships to logstash.  Lines are shipped once they are complete; a file that
+// shrinks is assumed to be truncated and is read again from the start.
+type logTailer struct {
+	logs    []tailedLog
+	offsets map[string]int64 // path -> offset shipped
+	url     string
+	post    func(url string, batch logship.Batch) error
+}
+
+// newLogTailer returns a tailer of the log configs of the service that are
+// not shipped to logstash, or nil if there are none.
+func newLogTailer(hostID, hostIPs, svcPath string, svc *service.Service, instanceID string) *logTailer {
+	var logs []tailedLog
+	for i := range svc.LogConfigs {
+		logConfig := svc.LogConfigs[i]
+		if isLogstashConfig(logConfig) {
+			continue
+		}
+		logs = append(logs, tailedLog{
+			shipper: logConfig.Shipper,
+			pattern: logConfig.Path,
+			fields:  createFields(hostID, hostIPs, svcPath, svc, instanceID, &logConfig),
+		})
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	return &logTailer{
+		logs:    logs,
+		offsets: make(map[string]int64),
+		url:     logShipperURL,
+		post:    logship.Post,
+	}
+}
+
+// run ships the logs until exit is closed
+func (t *logTailer) run(exit <-chan struct{}) {
+	ticker := time.NewTicker(logTailInterval)
+	defer ticker.Stop()
+	var retryAt time.Time
+	for {
+		select {
+		case now := <-ticker.C:
+			if now.Before(retryAt) {
+				continue
+			}
+			if err := t.poll(now); err != nil {
+				plog.WithError(err).Warn("Could not ship logs to the log shipper of the delegate")
+				retryAt = now.Add(logTailRetry)
+			}
+		case <-exit:
+			t.poll(time.Now())
+			return
+		}
+	}
+}
+
+// poll ships the new lines of every file of the logs, and stops at the first
+// batch that could not be shipped so that it is retried
+func (t *logTailer) poll(now time.Time) error {
+	for _, l := range t.logs {
+		paths, err := filepath.Glob(l.pattern)
+		if err != nil {
+			plog.WithField("path", l.pattern).WithError(err).Debug("Could not match log files")
+			continue
+		}
+		for _, path := range paths {
+			if err := t.ship(now, l, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ship posts the complete lines of the file after its offset
+func (t *logTailer) ship(now time.Time, l tailedLog, path string) error {
+	logger := plog.WithFields(log.Fields{
+		"path":    path,
+		"shipper": l.shipper,
+	})
+	f, err := os.Open(path)
+	if err != nil {
+		logger.WithError(err).Debug("Could not open log file")
+		return nil
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		return nil
+	}
+	offset := t.offsets[path]
+	if fi.Size() < offset {
+		logger.Debug("Log file was truncated; shipping it from the start")
+		offset = 0
+		t.offsets[path] = 0
+	}
+	for offset < fi.Size() {
+		buf := make([]byte, logTailMaxRead)
+		n, err := f.ReadAt(buf, offset)
+		if err != nil && err != io.EOF {
+			logger.WithError(err).Debug("Could not read log file")
+			return nil
+		}
+		end := bytes.LastIndexByte(buf[:n], '\n')
+		if end < 0 {
+			if n < logTailMaxRead {
+				// wait for the rest of the line
+				return nil
+			}
+			// ship a line that does not fit in a read as it is
+			end = n - 1
+		}
+		lines := bytes.Split(buf[:end], []byte{'\n'})
+		batch := logship.Batch{Shipper: l.shipper, Entries: make([]logship.Entry, 0, len(lines))}
+		for _, line := range lines {
+			line = bytes.TrimRight(line, "\r")
+			if len(line) == 0 {
+				continue
+			}
+			batch.Entries = append(batch.Entries, logship.Entry{
+				Time:    now,
+				Fields:  l.fields,
+				Message: string(line),
+			})
+		}
+		if len(batch.Entries) > 0 {
+			if err := t.post(t.url, batch); err != nil {
+				return err
+			}
+		}
+		offset += int64(end + 1)
+		t.offsets[path] = offset
+	}
+	return nil
+}
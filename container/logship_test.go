@@ -0,0 +1,180 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package container
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/logship"
+)
+
+func TestNewLogTailer(t *testing.T) {
+	svc := getTestService()
+	if newLogTailer("host1", "192.168.1.1", "/svc", &svc, "0") != nil {
+		t.Errorf("expected no tailer for logstash logs")
+	}
+	if !hasLogstashConfigs(&svc) {
+		t.Errorf("expected logstash logs")
+	}
+
+	svc.LogConfigs[1].Shipper = "kafka"
+	tailer := newLogTailer("host1", "192.168.1.1", "/svc", &svc, "0")
+	if tailer == nil || len(tailer.logs) != 1 {
+		t.Fatalf("expected a tailer of 1 log, got %+v", tailer)
+	}
+	l := tailer.logs[0]
+	if l.shipper != "kafka" || l.pattern != "/path/to/second/log/file" {
+		t.Errorf("unexpected log %+v", l)
+	}
+	if l.fields["type"] != "test2" || l.fields["pepe"] != "foobar" || l.fields["instance"] != "0" {
+		t.Errorf("unexpected fields %+v", l.fields)
+	}
+
+	svc.LogConfigs[0].Shipper = "syslog"
+	if hasLogstashConfigs(&svc) {
+		t.Errorf("expected no logstash logs")
+	}
+}
+
+func TestWriteLogstashAgentConfigSkipsShippedLogs(t *testing.T) {
+	svc := getTestService()
+	svc.LogConfigs[1].Shipper = "syslog"
+
+	tmp, err := ioutil.TempFile("/tmp", "test-logstash-")
+	if err != nil {
+		t.Fatalf("Error creating temporary file error: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	resources, err := ioutil.TempDir("", "test-logstash-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(resources)
+	logforwarderOptions := LogforwarderOptions{Enabled: true, Path: filepath.Join(resources, "filebeat"), ConfigFile: tmp.Name()}
+	if err := ioutil.WriteFile(filepath.Join(resources, "filebeat.conf.in"), []byte("${PROSPECTORS_SECTION}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeLogstashAgentConfig("host1", "192.168.1.1", "/svc", &svc, "0", logforwarderOptions); err != nil {
+		t.Fatalf("Error writing config file %s", err)
+	}
+	contents, err := ioutil.ReadFile(tmp.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(contents), "/path/to/log/file") || strings.Contains(string(contents), "/path/to/second/log/file") {
+		t.Errorf("expected only the logstash log in the config file %s", string(contents))
+	}
+}
+
+func TestLogTailerShip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-logship-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	var batches []logship.Batch
+	var postErr error
+	tailer := &logTailer{
+		logs: []tailedLog{{
+			shipper: "syslog",
+			pattern: filepath.Join(dir, "*.log"),
+			fields:  map[string]string{"type": "app"},
+		}},
+		offsets: make(map[string]int64),
+		url:     "http://shipper/api/logs",
+		post: func(url string, batch logship.Batch) error {
+			if postErr != nil {
+				return postErr
+			}
+			batches = append(batches, batch)
+			return nil
+		},
+	}
+	messages := func() []string {
+		var msgs []string
+		for _, b := range batches {
+			if b.Shipper != "syslog" {
+				t.Errorf("expected shipper syslog, got %s", b.Shipper)
+			}
+			for _, e := range b.Entries {
+				if e.Fields["type"] != "app" {
+					t.Errorf("unexpected fields %+v", e.Fields)
+				}
+				msgs = append(msgs, e.Message)
+			}
+		}
+		batches = nil
+		return msgs
+	}
+	now := time.Now()
+
+	// only complete lines are shipped
+	if err := ioutil.WriteFile(path, []byte("one\r\ntwo\n\nthr"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tailer.poll(now); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := messages(); len(msgs) != 2 || msgs[0] != "one" || msgs[1] != "two" {
+		t.Errorf("unexpected messages %q", msgs)
+	}
+
+	// the rest of the line is shipped once it is complete, but not while the
+	// shipper fails
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("ee\nfour\n")
+	f.Close()
+	postErr = errors.New("shipper unavailable")
+	if err := tailer.poll(now); err == nil {
+		t.Errorf("expected an error")
+	}
+	postErr = nil
+	if err := tailer.poll(now); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := messages(); len(msgs) != 2 || msgs[0] != "three" || msgs[1] != "four" {
+		t.Errorf("unexpected messages %q", msgs)
+	}
+	if err := tailer.poll(now); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := messages(); len(msgs) != 0 {
+		t.Errorf("expected no messages, got %q", msgs)
+	}
+
+	// a truncated file is shipped from the start
+	if err := ioutil.WriteFile(path, []byte("five\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tailer.poll(now); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := messages(); len(msgs) != 1 || msgs[0] != "five" {
+		t.Errorf("unexpected messages %q", msgs)
+	}
+}
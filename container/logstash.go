@@ -67,6 +67,10 @@ func writeLogstashAgentConfig(hostID string, hostIPs string, svcPath string, ser
 	// generate a prospector configuration for each service log file
 	prospectorsConf := ``
 	for _, logConfig := range service.LogConfigs {
+		// the logs that go to syslog or kafka are shipped by the log tailer
+		if !isLogstashConfig(logConfig) {
+			continue
+		}
 		prospectorsConf = prospectorsConf + `
     - ignore_older: 10m
       close_inactive: 5m
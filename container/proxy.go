@@ -21,13 +21,22 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	muxproxy "github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
 )
 
+// The proxies of a container share a multiplexed session to the mux of each
+// remote host.
+var (
+	muxDialer    = muxproxy.NewMuxDialer(nil)
+	tlsMuxDialer = muxproxy.NewMuxDialer(&tls.Config{InsecureSkipVerify: true})
+)
+
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
@@ -86,10 +95,11 @@ type proxy struct {
 	newAddresses     chan []addressTuple // a stream of updates to the addresses
 	listener         net.Listener        // handle on the listening socket
 	allowDirectConn  bool                // allow container to container connections
+	stats            *endpointCounters   // connection counts of the remote service
 }
 
 // Newproxy create a new proxy object. It starts listening on the prxy port asynchronously.
-func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool) (p *proxy, err error) {
+func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, listener net.Listener, allowDirectConn bool, stats *endpointCounters) (p *proxy, err error) {
 	if len(name) == 0 {
 		return nil, fmt.Errorf("prxy: name can not be empty")
 	}
@@ -101,6 +111,7 @@ func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, lis
 		useTLS:           useTLS,
 		listener:         listener,
 		allowDirectConn:  allowDirectConn,
+		stats:            stats,
 	}
 	p.newAddresses = make(chan []addressTuple, 2)
 	go p.listenAndproxy()
@@ -167,6 +178,7 @@ func (p *proxy) listenAndproxy() {
 		case conn := <-connections:
 			if len(p.addresses) == 0 {
 				glog.Warningf("No remote services available for prxying %v", p)
+				p.stats.failed()
 				conn.Close()
 				continue
 			}
@@ -211,18 +223,16 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		// don't proxy localhost addresses, we'll end up in a loop
 		if isLocalContainer {
 			switch {
-			case strings.HasPrefix(address.host, "127"):
-				isLocalContainer = false
-			case address.host == "localhost":
+			case isLoopbackHost(address.host):
 				isLocalContainer = false
-			case strings.HasPrefix(address.containerAddr, "127") || strings.HasPrefix(address.containerAddr, "localhost:"):
+			case isLoopbackAddr(address.containerAddr):
 				//if the host is local and the container has a local style addr
 				//then container is exposing port directly on host; go to host and use container port
 				if containerPort, err := getPort(address.containerAddr); err != nil {
 					glog.Warningf("could not get port %v", err)
 					isLocalContainer = false
 				} else {
-					localAddr = fmt.Sprintf("%s:%d", address.host, containerPort)
+					localAddr = net.JoinHostPort(address.host, strconv.Itoa(containerPort))
 				}
 			}
 		}
@@ -233,7 +243,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		p.tcpMuxPort = 22250
 	}
 
-	muxAddr := fmt.Sprintf("%s:%d", address.host, p.tcpMuxPort)
+	muxAddr := net.JoinHostPort(address.host, strconv.Itoa(int(p.tcpMuxPort)))
 
 	// Build the authentication header before dialing the connection, so the
 	// connection isn't sitting open waiting for an authentication token to be
@@ -248,12 +258,14 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		muxAddrPacked, err = utils.PackTCPAddressString(address.containerAddr)
 		if err != nil {
 			glog.Errorf("Container address is invalid. Can't create proxy: %s", address.containerAddr)
+			p.stats.failed()
 			return
 		}
 		select {
 		case token = <-auth.AuthToken(nil):
 		case <-time.After(tokenTimeout):
 			glog.Error("Unable to retrieve authentication token with 30 seconds")
+			p.stats.failed()
 			return
 		}
 	}
@@ -263,27 +275,26 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	switch {
 	case isLocalContainer:
 		glog.V(2).Infof("dialing local addr=> %s", localAddr)
-		remote, err = net.Dial("tcp4", localAddr)
+		remote, err = net.Dial("tcp", localAddr)
 		if err != nil {
 			glog.Errorf("Error Local (net.Dial): %s", err)
+			p.stats.failed()
 			return
 		}
 	case p.useTLS:
 		glog.V(2).Infof("dialing remote tls => %s", muxAddr)
-		config := tls.Config{InsecureSkipVerify: true}
-		tlsConn, err := tls.Dial("tcp4", muxAddr, &config)
+		remote, err = tlsMuxDialer.Dial("tcp", muxAddr)
 		if err != nil {
 			glog.Errorf("Error TLS (net.Dial): %s", err)
+			p.stats.failed()
 			return
 		}
-		remote = tlsConn // cast it to the net.Conn interface
-		cipher := tlsConn.ConnectionState().CipherSuite
-		glog.V(2).Infof("Proxy connected to mux with TLS cipher=%s (%d)", utils.GetCipherName(cipher), cipher)
 	default:
 		glog.V(2).Infof("dialing remote => %s", muxAddr)
-		remote, err = net.Dial("tcp4", muxAddr)
+		remote, err = muxDialer.Dial("tcp", muxAddr)
 		if err != nil {
 			glog.Errorf("Error Remote (net.Dial): %s", err)
+			p.stats.failed()
 			return
 		}
 	}
@@ -295,17 +306,27 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 
 	glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
 		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+	p.stats.opened()
+	var sent, received int64
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		wg.Wait()
+		p.stats.closed(sent, received)
+	}()
 	go func(address string) {
+		defer wg.Done()
 		defer local.Close()
 		defer remote.Close()
-		io.Copy(local, remote)
+		received, _ = io.Copy(local, remote)
 		glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
 			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
 	}(address.containerAddr)
 	go func(address string) {
+		defer wg.Done()
 		defer local.Close()
 		defer remote.Close()
-		io.Copy(remote, local)
+		sent, _ = io.Copy(remote, local)
 		glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
 			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
 	}(address.containerAddr)
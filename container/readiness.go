@@ -0,0 +1,157 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
+)
+
+// ErrServiceNotHealthy is returned by a service readiness gate while no
+// instance of the service passes all of its health checks
+var ErrServiceNotHealthy = errors.New("no instance of the service is healthy")
+
+// readinessGates checks the readiness gates of an instance
+type readinessGates struct {
+	gates   []servicedefinition.ReadinessGate
+	request servicedefinition.ReadinessRequest
+
+	// getServiceHealth returns the health checks of the instances of a
+	// service
+	getServiceHealth func(serviceID string) (map[int]map[string]health.HealthStatus, error)
+}
+
+// wait checks each gate at its interval until it passes, and returns true
+// once all of the gates have passed, or false if it is canceled first.
+func (r *readinessGates) wait(cancel <-chan struct{}) bool {
+	if len(r.gates) == 0 {
+		return true
+	}
+
+	var wg sync.WaitGroup
+	for _, gate := range r.gates {
+		wg.Add(1)
+		go func(gate servicedefinition.ReadinessGate) {
+			defer wg.Done()
+			r.waitGate(cancel, gate)
+		}(gate)
+	}
+	wg.Wait()
+
+	select {
+	case <-cancel:
+		return false
+	default:
+		plog.WithFields(log.Fields{
+			"serviceid":  r.request.ServiceID,
+			"instanceid": r.request.InstanceID,
+		}).Info("Passed all readiness gates")
+		return true
+	}
+}
+
+// waitGate checks a gate until it passes or is canceled
+func (r *readinessGates) waitGate(cancel <-chan struct{}, gate servicedefinition.ReadinessGate) {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  r.request.ServiceID,
+		"instanceid": r.request.InstanceID,
+		"gate":       gate.Name,
+		"type":       gate.Type,
+	})
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-cancel:
+			return
+		}
+		err := r.check(gate)
+		if err == nil {
+			logger.Info("Passed readiness gate")
+			return
+		}
+		logger.WithError(err).Debug("Readiness gate is not satisfied")
+		timer.Reset(gate.GetInterval())
+	}
+}
+
+// check returns nil if the gate is satisfied
+func (r *readinessGates) check(gate servicedefinition.ReadinessGate) error {
+	switch gate.Type {
+	case servicedefinition.ReadinessService:
+		statuses, err := r.getServiceHealth(gate.ServiceID)
+		if err != nil {
+			return err
+		}
+		if !anyInstanceHealthy(statuses) {
+			return ErrServiceNotHealthy
+		}
+		return nil
+	case servicedefinition.ReadinessWebhook:
+		return postReadinessWebhook(gate.URL, gate.GetTimeout(), r.request)
+	default:
+		return fmt.Errorf("unknown readiness gate type %q", gate.Type)
+	}
+}
+
+// anyInstanceHealthy returns true if an instance has health checks and all
+// of them are passing
+func anyInstanceHealthy(statuses map[int]map[string]health.HealthStatus) bool {
+	for _, checks := range statuses {
+		if len(checks) == 0 {
+			continue
+		}
+		healthy := true
+		for _, status := range checks {
+			if status.Status != health.OK {
+				healthy = false
+				break
+			}
+		}
+		if healthy {
+			return true
+		}
+	}
+	return false
+}
+
+// postReadinessWebhook posts the instance to the url of a webhook gate,
+// which passes if the webhook responds with a 2xx code
+func postReadinessWebhook(url string, timeout time.Duration, request servicedefinition.ReadinessRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
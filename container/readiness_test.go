@@ -0,0 +1,148 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/health"
+)
+
+func TestAnyInstanceHealthy(t *testing.T) {
+	passed := health.HealthStatus{Status: health.OK}
+	failed := health.HealthStatus{Status: health.Failed}
+
+	if anyInstanceHealthy(nil) {
+		t.Errorf("expected no healthy instances without instances")
+	}
+	if anyInstanceHealthy(map[int]map[string]health.HealthStatus{0: {}}) {
+		t.Errorf("expected no healthy instances without health checks")
+	}
+	if anyInstanceHealthy(map[int]map[string]health.HealthStatus{0: {"ready": passed, "alive": failed}}) {
+		t.Errorf("expected no healthy instances with a failing check")
+	}
+	if !anyInstanceHealthy(map[int]map[string]health.HealthStatus{
+		0: {"ready": passed, "alive": failed},
+		1: {"ready": passed, "alive": passed},
+	}) {
+		t.Errorf("expected a healthy instance")
+	}
+}
+
+func TestReadinessGates_Webhook(t *testing.T) {
+	var received servicedefinition.ReadinessRequest
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// the load balancer registration completes on the second call
+		if calls < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := &readinessGates{
+		gates: []servicedefinition.ReadinessGate{
+			{Name: "lb", Type: servicedefinition.ReadinessWebhook, URL: server.URL, Interval: 1},
+		},
+		request: servicedefinition.ReadinessRequest{ServiceID: "svc", InstanceID: 2, PrivateIP: "172.17.0.2"},
+	}
+
+	gate := r.gates[0]
+	if err := r.check(gate); err == nil {
+		t.Fatalf("expected the first check to fail")
+	}
+	if err := r.check(gate); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if received.ServiceID != "svc" || received.InstanceID != 2 || received.PrivateIP != "172.17.0.2" {
+		t.Errorf("unexpected request: %+v", received)
+	}
+}
+
+func TestReadinessGates_Wait(t *testing.T) {
+	healthy := make(chan struct{})
+	r := &readinessGates{
+		gates: []servicedefinition.ReadinessGate{
+			{Name: "db", Type: servicedefinition.ReadinessService, ServiceID: "db", Interval: 1},
+		},
+		getServiceHealth: func(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+			if serviceID != "db" {
+				return nil, errors.New("unexpected service")
+			}
+			select {
+			case <-healthy:
+				return map[int]map[string]health.HealthStatus{0: {"ready": {Status: health.OK}}}, nil
+			default:
+				return map[int]map[string]health.HealthStatus{0: {"ready": {Status: health.Failed}}}, nil
+			}
+		},
+	}
+
+	done := make(chan bool)
+	go func() { done <- r.wait(make(chan struct{})) }()
+
+	select {
+	case <-done:
+		t.Fatalf("expected wait to block while the service is unhealthy")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(healthy)
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Errorf("expected the gates to pass")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the gates to pass")
+	}
+}
+
+func TestReadinessGates_WaitCanceled(t *testing.T) {
+	r := &readinessGates{
+		gates: []servicedefinition.ReadinessGate{
+			{Name: "db", Type: servicedefinition.ReadinessService, ServiceID: "db"},
+		},
+		getServiceHealth: func(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+			return nil, errors.New("master is unavailable")
+		},
+	}
+
+	cancel := make(chan struct{})
+	close(cancel)
+	if r.wait(cancel) {
+		t.Errorf("expected wait to return false when it is canceled")
+	}
+
+	r.gates = nil
+	if !r.wait(cancel) {
+		t.Errorf("expected wait to return true without gates")
+	}
+}
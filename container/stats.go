@@ -90,6 +90,9 @@ func collect(ts time.Time, statsUrl string) {
 	}
 
 
+	// collect the connection counts of the imported endpoints
+	samples = append(samples, importStats.samples(now)...)
+
 	glog.V(4).Infof("posting samples: %+v", samples)
 	if err := stats.Post(statsUrl, samples); err != nil {
 		glog.Errorf("could not post stats: %s", err)
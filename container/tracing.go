@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"net"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	muxproxy "github.com/control-center/serviced/proxy"
+)
+
+// tracingDialTimeout bounds the connection to the tracing collector
+const tracingDialTimeout = 10 * time.Second
+
+// tracingForwarder receives the spans of the service on the address of the
+// container and forwards each connection to the tracing collector, so the
+// service does not need to know where the collector is.
+type tracingForwarder struct {
+	listener  net.Listener
+	collector string
+}
+
+// newTracingForwarder listens for spans on the address
+func newTracingForwarder(address, collector string) (*tracingForwarder, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingForwarder{listener: listener, collector: collector}, nil
+}
+
+// Addr returns the address that the forwarder receives spans on
+func (f *tracingForwarder) Addr() net.Addr {
+	return f.listener.Addr()
+}
+
+// run forwards connections until exit is closed
+func (f *tracingForwarder) run(exit <-chan struct{}) {
+	logger := plog.WithFields(log.Fields{
+		"address":   f.listener.Addr(),
+		"collector": f.collector,
+	})
+	quit := make(chan bool)
+	go func() {
+		<-exit
+		f.listener.Close()
+		close(quit)
+	}()
+
+	logger.Debug("Forwarding spans to the tracing collector")
+	for {
+		local, err := f.listener.Accept()
+		if err != nil {
+			logger.WithError(err).Debug("Stopped forwarding spans")
+			return
+		}
+		go func() {
+			remote, err := net.DialTimeout("tcp", f.collector, tracingDialTimeout)
+			if err != nil {
+				logger.WithError(err).Warn("Could not connect to the tracing collector")
+				local.Close()
+				return
+			}
+			muxproxy.ProxyLoop(local, remote, quit)
+		}()
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package container
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTracingForwarder(t *testing.T) {
+	// a collector that echoes the spans
+	collector, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not start collector: %s", err)
+	}
+	defer collector.Close()
+	go func() {
+		for {
+			conn, err := collector.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	forwarder, err := newTracingForwarder("127.0.0.1:0", collector.Addr().String())
+	if err != nil {
+		t.Fatalf("could not start forwarder: %s", err)
+	}
+	exit := make(chan struct{})
+	go forwarder.run(exit)
+
+	conn, err := net.Dial("tcp", forwarder.Addr().String())
+	if err != nil {
+		t.Fatalf("could not connect to forwarder: %s", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("span")); err != nil {
+		t.Fatalf("could not send span: %s", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "span" {
+		t.Fatalf("span was not forwarded: %q %v", buf, err)
+	}
+
+	// the forwarder stops listening on exit
+	close(exit)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c, err := net.Dial("tcp", forwarder.Addr().String())
+		if err != nil {
+			break
+		}
+		c.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("forwarder did not stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,10 +1,12 @@
 package container
 
 import (
+	"net"
 	"os"
 	"strings"
 
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/utils"
 )
 
 var plog = logging.PackageLogger()
@@ -19,13 +21,29 @@ func init() {
 	trimmed := strings.Trim(rawval, `'"`)
 	// Fill the set
 	for _, ip := range strings.Fields(trimmed) {
-		hostIPs[ip] = struct{}{}
+		hostIPs[utils.CanonicalIP(ip)] = struct{}{}
 	}
 }
 
 // isLocalAddress() simply checks the given IP against those passed in as the
 // IPs of the host on which this container is running
 func isLocalAddress(ip string) bool {
-	_, ok := hostIPs[ip]
+	_, ok := hostIPs[utils.CanonicalIP(ip)]
 	return ok
 }
+
+// isLoopbackHost returns true if the host is localhost or a loopback IP
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// isLoopbackAddr returns true if the host of the host:port address is
+// localhost or a loopback IP
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && isLoopbackHost(host)
+}
@@ -0,0 +1,277 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// etcd v2 error codes
+const (
+	errKeyNotFound  = 100
+	errTestFailed   = 101
+	errNotFile      = 102
+	errNotDir       = 104
+	errNodeExist    = 105
+	errDirNotEmpty  = 108
+	errRaftInternal = 300
+	errLeaderElect  = 301
+)
+
+// etcd v2 actions
+const (
+	actionCreate           = "create"
+	actionSet              = "set"
+	actionDelete           = "delete"
+	actionCompareAndDelete = "compareAndDelete"
+	actionExpire           = "expire"
+)
+
+// apiError is an error returned by the etcd keys api
+type apiError struct {
+	Code    int    `json:"errorCode"`
+	Message string `json:"message"`
+	Cause   string `json:"cause"`
+	Index   uint64 `json:"index"`
+}
+
+func (err *apiError) Error() string {
+	return fmt.Sprintf("etcd: %s (%s) [%d]", err.Message, err.Cause, err.Code)
+}
+
+// node is a key or directory returned by the etcd keys api
+type node struct {
+	Key           string  `json:"key"`
+	Dir           bool    `json:"dir,omitempty"`
+	Value         string  `json:"value,omitempty"`
+	Nodes         []*node `json:"nodes,omitempty"`
+	CreatedIndex  uint64  `json:"createdIndex,omitempty"`
+	ModifiedIndex uint64  `json:"modifiedIndex,omitempty"`
+	TTL           int64   `json:"ttl,omitempty"`
+}
+
+// response is a successful response from the etcd keys api
+type response struct {
+	Action    string `json:"action"`
+	Node      *node  `json:"node"`
+	PrevNode  *node  `json:"prevNode,omitempty"`
+	EtcdIndex uint64 `json:"-"`
+}
+
+// api is a minimal client for the etcd v2 keys api that fails over between
+// the configured endpoints.
+type api struct {
+	mu        sync.Mutex
+	endpoints []string
+	current   int
+	client    *http.Client
+	timeout   time.Duration
+}
+
+func newAPI(endpoints []string, timeout time.Duration) *api {
+	eps := make([]string, len(endpoints))
+	for i, ep := range endpoints {
+		if !strings.Contains(ep, "://") {
+			ep = "http://" + ep
+		}
+		eps[i] = strings.TrimRight(ep, "/")
+	}
+	return &api{
+		endpoints: eps,
+		client:    &http.Client{},
+		timeout:   timeout,
+	}
+}
+
+// keyURL returns the escaped url path of a key
+func keyURL(key string) string {
+	return (&url.URL{Path: "/v2/keys" + key}).EscapedPath()
+}
+
+// do sends a request to the keys api.  If wait is true, the request is a
+// long poll that only returns when the context is cancelled or an event
+// occurs.
+func (a *api) do(ctx context.Context, method, key string, params url.Values, wait bool) (*response, error) {
+	a.mu.Lock()
+	start := a.current
+	count := len(a.endpoints)
+	a.mu.Unlock()
+
+	var lastErr error
+	for i := 0; i < count; i++ {
+		idx := (start + i) % count
+		resp, err := a.doOne(ctx, a.endpoints[idx], method, key, params, wait)
+		if err == nil {
+			a.mu.Lock()
+			a.current = idx
+			a.mu.Unlock()
+			return resp, nil
+		}
+		if _, ok := err.(*apiError); ok {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		plog.WithField("endpoint", a.endpoints[idx]).WithError(err).Debug("Could not reach etcd endpoint")
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (a *api) doOne(ctx context.Context, endpoint, method, key string, params url.Values, wait bool) (*response, error) {
+	u := endpoint + keyURL(key)
+	var body string
+	if method == "GET" || method == "DELETE" {
+		if len(params) > 0 {
+			u += "?" + params.Encode()
+		}
+	} else {
+		body = params.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if !wait && a.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeout)
+		defer cancel()
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &apiError{}
+		if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Code == 0 {
+			return nil, fmt.Errorf("etcd: unexpected response %s", resp.Status)
+		}
+		return nil, apiErr
+	}
+
+	r := &response{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, err
+	}
+	if idx := resp.Header.Get("X-Etcd-Index"); idx != "" {
+		r.EtcdIndex, _ = strconv.ParseUint(idx, 10, 64)
+	}
+	return r, nil
+}
+
+// get returns the key or directory at the given path
+func (a *api) get(key string, recursive bool) (*response, error) {
+	params := url.Values{}
+	if recursive {
+		params.Set("recursive", "true")
+	}
+	return a.do(context.Background(), "GET", key, params, false)
+}
+
+// set assigns a value to a key.  If prevExist is false, the key must not
+// already exist.  If prevIndex is greater than 0, the key's modified index
+// must match.
+func (a *api) set(key, value string, prevExist *bool, prevIndex uint64, ttl time.Duration) (*response, error) {
+	params := url.Values{}
+	params.Set("value", value)
+	if prevExist != nil {
+		params.Set("prevExist", strconv.FormatBool(*prevExist))
+	}
+	if prevIndex > 0 {
+		params.Set("prevIndex", strconv.FormatUint(prevIndex, 10))
+	}
+	if ttl > 0 {
+		params.Set("ttl", strconv.FormatInt(int64(ttl.Seconds()), 10))
+	}
+	return a.do(context.Background(), "PUT", key, params, false)
+}
+
+// mkdir creates a directory.  If ttl is greater than 0, the directory expires
+// unless it is refreshed.
+func (a *api) mkdir(key string, ttl time.Duration) (*response, error) {
+	params := url.Values{}
+	params.Set("dir", "true")
+	params.Set("prevExist", "false")
+	if ttl > 0 {
+		params.Set("ttl", strconv.FormatInt(int64(ttl.Seconds()), 10))
+	}
+	return a.do(context.Background(), "PUT", key, params, false)
+}
+
+// refresh resets the ttl of a directory without notifying watchers
+func (a *api) refresh(key string, ttl time.Duration) (*response, error) {
+	params := url.Values{}
+	params.Set("dir", "true")
+	params.Set("prevExist", "true")
+	params.Set("refresh", "true")
+	params.Set("ttl", strconv.FormatInt(int64(ttl.Seconds()), 10))
+	return a.do(context.Background(), "PUT", key, params, false)
+}
+
+// post creates an in-order key in the given directory
+func (a *api) post(dir, value string, ttl time.Duration) (*response, error) {
+	params := url.Values{}
+	params.Set("value", value)
+	if ttl > 0 {
+		params.Set("ttl", strconv.FormatInt(int64(ttl.Seconds()), 10))
+	}
+	return a.do(context.Background(), "POST", dir, params, false)
+}
+
+// delete removes a key or a directory and all of its contents
+func (a *api) delete(key string, dir bool) (*response, error) {
+	params := url.Values{}
+	if dir {
+		params.Set("dir", "true")
+		params.Set("recursive", "true")
+	}
+	return a.do(context.Background(), "DELETE", key, params, false)
+}
+
+// watch waits for the next change at or after the given index
+func (a *api) watch(ctx context.Context, key string, index uint64, recursive bool) (*response, error) {
+	params := url.Values{}
+	params.Set("wait", "true")
+	if index > 0 {
+		params.Set("waitIndex", strconv.FormatUint(index, 10))
+	}
+	if recursive {
+		params.Set("recursive", "true")
+	}
+	return a.do(ctx, "GET", key, params, true)
+}
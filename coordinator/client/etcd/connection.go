@@ -0,0 +1,678 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Every coordinator node is stored as an etcd directory so that it may have
+// children.  The serialized data of the node is kept in a hidden key inside
+// of that directory, which etcd leaves out of directory listings and
+// recursive watches.
+const (
+	dataKey = "_data"
+	seqKey  = "_seq"
+)
+
+// Stat is the version information of a node stored in etcd
+type Stat struct {
+	ModifiedIndex uint64
+}
+
+// Connection is an etcd based implementation of client.Connection.
+type Connection struct {
+	sync.RWMutex
+	api        *api
+	basePath   string
+	ttl        time.Duration
+	onClose    func(int)
+	id         int
+	emu        sync.Mutex
+	ephemerals map[string]struct{}
+	done       chan struct{}
+}
+
+// Assert that Connection implements client.Connection.
+var _ client.Connection = &Connection{}
+
+// IsClosed returns connection closed error if true, otherwise returns nil.
+func (c *Connection) isClosed() error {
+	if c.api == nil {
+		return client.ErrConnectionClosed
+	}
+	return nil
+}
+
+// Close closes the client connection to etcd and removes all of the
+// ephemeral nodes owned by the connection.  Calling close twice will result
+// in a no-op.
+func (c *Connection) Close() {
+	c.Lock()
+	defer c.Unlock()
+	if c.api != nil {
+		close(c.done)
+		c.emu.Lock()
+		for key := range c.ephemerals {
+			if _, err := c.api.delete(key, true); err != nil {
+				plog.WithField("key", key).WithError(err).Debug("Could not delete ephemeral node")
+			}
+		}
+		c.ephemerals = nil
+		c.emu.Unlock()
+		c.api = nil
+		if c.onClose != nil {
+			c.onClose(c.id)
+			c.onClose = nil
+		}
+	}
+}
+
+// SetID sets the connection ID
+func (c *Connection) SetID(i int) {
+	c.Lock()
+	defer c.Unlock()
+	c.id = i
+}
+
+// ID gets the connection ID
+func (c *Connection) ID() int {
+	c.RLock()
+	defer c.RUnlock()
+	return c.id
+}
+
+// SetOnClose performs cleanup when a connection is closed
+func (c *Connection) SetOnClose(onClose func(int)) {
+	c.Lock()
+	defer c.Unlock()
+	if err := c.isClosed(); err == nil {
+		c.onClose = onClose
+	}
+}
+
+// keepalive refreshes the ttl of the ephemeral nodes owned by the connection
+// until the connection is closed.
+func (c *Connection) keepalive() {
+	ticker := time.NewTicker(c.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.RLock()
+			if c.api != nil {
+				c.emu.Lock()
+				for key := range c.ephemerals {
+					if _, err := c.api.refresh(key, c.ttl); err != nil {
+						plog.WithField("key", key).WithError(err).Debug("Could not refresh ephemeral node")
+						if xlateError(err) == client.ErrNoNode {
+							delete(c.ephemerals, key)
+						}
+					}
+				}
+				c.emu.Unlock()
+			}
+			c.RUnlock()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// NewTransaction creates a new transaction object
+func (c *Connection) NewTransaction() client.Transaction {
+	return &Transaction{
+		conn: c,
+		ops:  []multiReq{},
+	}
+}
+
+// NewLock creates a new lock object
+func (c *Connection) NewLock(p string) (client.Lock, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return &Lock{conn: c, path: p}, nil
+}
+
+// NewLeader returns a managed leader object at the given path bound to the
+// current connection.
+func (c *Connection) NewLeader(p string) (client.Leader, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return &Leader{conn: c, path: p}, nil
+}
+
+// key returns the etcd key of the node at the given path
+func (c *Connection) key(p string) string {
+	return path.Join("/", c.basePath, p)
+}
+
+// Create adds a node at the specified path
+func (c *Connection) Create(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return err
+	}
+	return c.create(path, node)
+}
+
+// CreateIfExists adds a node at the specified path if the dirpath already
+// exists.
+func (c *Connection) CreateIfExists(p string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	if ok, err := c.exists(path.Dir(p)); err != nil {
+		return err
+	} else if !ok {
+		return client.ErrNoNode
+	}
+	return c.create(p, node)
+}
+
+func (c *Connection) create(p string, node client.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	key := c.key(p)
+	if _, err := c.api.mkdir(key, 0); err != nil {
+		return xlateError(err)
+	}
+	resp, err := c.setData(key, bytes, 0)
+	if err != nil {
+		c.api.delete(key, true)
+		return err
+	}
+	node.SetVersion(&Stat{ModifiedIndex: resp.Node.ModifiedIndex})
+	return nil
+}
+
+// setData writes the serialized node data into the directory of the node.
+func (c *Connection) setData(key string, data []byte, prevIndex uint64) (*response, error) {
+	resp, err := c.api.set(path.Join(key, dataKey), string(data), nil, prevIndex, 0)
+	if err != nil {
+		return nil, xlateError(err)
+	}
+	return resp, nil
+}
+
+// CreateDir adds a dir at the specified path
+func (c *Connection) CreateDir(path string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return err
+	}
+	return c.createDir(path)
+}
+
+func (c *Connection) createDir(p string) error {
+	_, err := c.api.mkdir(c.key(p), 0)
+	return xlateError(err)
+}
+
+func (c *Connection) ensurePath(p string) error {
+	dp := path.Dir(p)
+	if p == "/" || p == "" {
+		return nil
+	}
+	if exists, err := c.exists(dp); err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+	if err := c.ensurePath(dp); err != nil {
+		return err
+	} else if err := c.createDir(dp); err != nil && err != client.ErrNodeExists {
+		return err
+	}
+	return nil
+}
+
+// CreateEphemeral creates a node whose existance depends on the persistence of
+// the connection.
+func (c *Connection) CreateEphemeral(path string, node client.Node) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return "", err
+	}
+	if err := c.ensurePath(path); err != nil {
+		return "", err
+	}
+	return c.createEphemeral(path, node)
+}
+
+// CreateEphemeralIfExists creates an ephemeral node at the given path if it
+// exists.
+func (c *Connection) CreateEphemeralIfExists(p string, node client.Node) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return "", err
+	}
+	if ok, err := c.exists(path.Dir(p)); err != nil {
+		return "", err
+	} else if !ok {
+		return "", client.ErrNoNode
+	}
+	return c.createEphemeral(p, node)
+}
+
+// createEphemeral creates a sequential node that expires unless the
+// connection keeps refreshing it.  Like zookeeper, the sequence number is
+// appended to the name of the node and the returned path includes the base
+// path of the connection.
+func (c *Connection) createEphemeral(p string, node client.Node) (string, error) {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return "", client.ErrSerialization
+	}
+	key := c.key(p)
+	seq, err := c.nextSequence(path.Dir(key))
+	if err != nil {
+		return "", err
+	}
+	ekey := fmt.Sprintf("%s%010d", key, seq)
+	if _, err := c.api.mkdir(ekey, c.ttl); err != nil {
+		return "", xlateError(err)
+	}
+	resp, err := c.setData(ekey, bytes, 0)
+	if err != nil {
+		c.api.delete(ekey, true)
+		return "", err
+	}
+	c.addEphemeral(ekey)
+	node.SetVersion(&Stat{ModifiedIndex: resp.Node.ModifiedIndex})
+	return ekey, nil
+}
+
+// nextSequence returns a cluster-wide increasing number by creating an
+// in-order key in the hidden sequence directory of the given parent.
+func (c *Connection) nextSequence(parent string) (uint64, error) {
+	resp, err := c.api.post(path.Join(parent, seqKey), "", time.Minute)
+	if err != nil {
+		return 0, xlateError(err)
+	}
+	c.api.delete(resp.Node.Key, false)
+	return resp.Node.CreatedIndex, nil
+}
+
+// addEphemeral registers a node to be refreshed by the keepalive
+func (c *Connection) addEphemeral(key string) {
+	c.emu.Lock()
+	defer c.emu.Unlock()
+	if c.ephemerals != nil {
+		c.ephemerals[key] = struct{}{}
+	}
+}
+
+// removeEphemeral stops refreshing a node
+func (c *Connection) removeEphemeral(key string) {
+	c.emu.Lock()
+	defer c.emu.Unlock()
+	delete(c.ephemerals, key)
+}
+
+// Set assigns a value to an existing node at a given path
+func (c *Connection) Set(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	return c.set(path, node)
+}
+
+func (c *Connection) set(p string, node client.Node) error {
+	bytes, err := json.Marshal(node)
+	if err != nil {
+		return client.ErrSerialization
+	}
+	stat := &Stat{}
+	if version := node.Version(); version != nil {
+		var ok bool
+		if stat, ok = version.(*Stat); !ok {
+			return client.ErrInvalidVersionObj
+		}
+	}
+	if ok, err := c.exists(p); err != nil {
+		return err
+	} else if !ok {
+		return client.ErrNoNode
+	}
+	resp, err := c.setData(c.key(p), bytes, stat.ModifiedIndex)
+	if err != nil {
+		return err
+	}
+	node.SetVersion(&Stat{ModifiedIndex: resp.Node.ModifiedIndex})
+	return nil
+}
+
+// Delete recursively removes a path and its children
+func (c *Connection) Delete(path string) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	return c.delete(path)
+}
+
+func (c *Connection) delete(p string) error {
+	_, err := c.api.delete(c.key(p), true)
+	return xlateError(err)
+}
+
+// Exists returns true if the path exists
+func (c *Connection) Exists(path string) (bool, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, err
+	}
+	return c.exists(path)
+}
+
+func (c *Connection) exists(p string) (bool, error) {
+	if _, err := c.api.get(c.key(p), false); err != nil {
+		if err = xlateError(err); err == client.ErrNoNode {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ExistsW sets a watch on a node and alerts whenever it is added or removed.
+func (c *Connection) ExistsW(path string, cancel <-chan struct{}) (bool, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return false, nil, err
+	}
+	return c.existsW(path, cancel)
+}
+
+func (c *Connection) existsW(p string, cancel <-chan struct{}) (bool, <-chan client.Event, error) {
+	key := c.key(p)
+	ok := true
+	resp, err := c.api.get(key, false)
+	if err != nil {
+		apiErr, isAPIErr := err.(*apiError)
+		if !isAPIErr || apiErr.Code != errKeyNotFound {
+			return false, nil, xlateError(err)
+		}
+		ok = false
+		resp = &response{EtcdIndex: apiErr.Index}
+	}
+	return ok, c.watch(key, resp.EtcdIndex+1, false, acceptAll, cancel), nil
+}
+
+// Get returns the node at the given path.
+func (c *Connection) Get(path string, node client.Node) error {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return err
+	}
+	_, err := c.get(path, node)
+	return err
+}
+
+func (c *Connection) get(p string, node client.Node) (uint64, error) {
+	key := c.key(p)
+	resp, err := c.api.get(path.Join(key, dataKey), false)
+	if err != nil {
+		if err = xlateError(err); err != client.ErrNoNode {
+			return 0, err
+		}
+		// the node may exist without any data
+		dresp, err := c.api.get(key, false)
+		if err != nil {
+			return 0, xlateError(err)
+		}
+		node.SetVersion(&Stat{ModifiedIndex: dresp.Node.ModifiedIndex})
+		return dresp.EtcdIndex, client.ErrEmptyNode
+	}
+	if len(resp.Node.Value) > 0 {
+		if err := json.Unmarshal([]byte(resp.Node.Value), node); err != nil {
+			return 0, client.ErrSerialization
+		}
+	} else {
+		err = client.ErrEmptyNode
+	}
+	node.SetVersion(&Stat{ModifiedIndex: resp.Node.ModifiedIndex})
+	return resp.EtcdIndex, err
+}
+
+// GetW returns the node at the given path as well as a channel to watch for
+// events on that node.
+func (c *Connection) GetW(path string, node client.Node, cancel <-chan struct{}) (<-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return nil, err
+	}
+	return c.getW(path, node, cancel)
+}
+
+func (c *Connection) getW(p string, node client.Node, cancel <-chan struct{}) (<-chan client.Event, error) {
+	index, err := c.get(p, node)
+	if err != nil {
+		return nil, err
+	}
+	key := path.Join(c.key(p), dataKey)
+	return c.watch(key, index+1, false, acceptAll, cancel), nil
+}
+
+// Children returns the children of the node at the given path.
+func (c *Connection) Children(path string) ([]string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, err
+	}
+	children, _, err := c.children(path)
+	return children, err
+}
+
+func (c *Connection) children(p string) ([]string, uint64, error) {
+	resp, err := c.api.get(c.key(p), false)
+	if err != nil {
+		return []string{}, 0, xlateError(err)
+	}
+	children := make([]string, 0, len(resp.Node.Nodes))
+	for _, n := range resp.Node.Nodes {
+		children = append(children, path.Base(n.Key))
+	}
+	return children, resp.EtcdIndex, nil
+}
+
+// ChildrenW returns the children of the node at the given path as well as a
+// channel to watch for events on that node.
+func (c *Connection) ChildrenW(path string, cancel <-chan struct{}) ([]string, <-chan client.Event, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return []string{}, nil, err
+	}
+	return c.childrenW(path, cancel)
+}
+
+func (c *Connection) childrenW(p string, cancel <-chan struct{}) ([]string, <-chan client.Event, error) {
+	children, index, err := c.children(p)
+	if err != nil {
+		return []string{}, nil, err
+	}
+	key := c.key(p)
+
+	// only changes to the node itself or its immediate children trigger an
+	// event
+	filter := func(resp *response) bool {
+		if resp.Node.Key == key {
+			return true
+		}
+		if path.Dir(resp.Node.Key) != key {
+			return false
+		}
+		switch resp.Action {
+		case actionCreate, actionDelete, actionExpire, actionCompareAndDelete:
+			return true
+		case actionSet:
+			return resp.PrevNode == nil
+		}
+		return false
+	}
+	return children, c.watch(key, index+1, true, filter, cancel), nil
+}
+
+// acceptAll is a watch filter that accepts every event
+func acceptAll(*response) bool { return true }
+
+// watch waits for the first event on the key accepted by the filter and sends
+// it on the returned channel.
+func (c *Connection) watch(key string, index uint64, recursive bool, filter func(*response) bool, cancel <-chan struct{}) <-chan client.Event {
+	evCh := make(chan client.Event, 1)
+	ctx, stop := context.WithCancel(context.Background())
+	a := c.api
+	go func() {
+		select {
+		case <-cancel:
+		case <-c.done:
+		}
+		stop()
+	}()
+	go func() {
+		defer stop()
+		for {
+			resp, err := a.watch(ctx, key, index, recursive)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				ev := client.Event{Type: client.EventNotWatching, Path: key, Err: xlateError(err)}
+				if _, ok := err.(*apiError); !ok {
+					ev.Type = client.EventSession
+				}
+				select {
+				case evCh <- ev:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if !filter(resp) {
+				index = resp.Node.ModifiedIndex + 1
+				continue
+			}
+			ev := client.Event{Type: toEventType(resp, key), Path: key}
+			select {
+			case evCh <- ev:
+			case <-ctx.Done():
+			}
+			return
+		}
+	}()
+	return evCh
+}
+
+// toEventType converts the action of a watch response on the given key into
+// a client event type.
+func toEventType(resp *response, key string) client.EventType {
+	if resp.Node.Key != key {
+		if strings.HasPrefix(key, resp.Node.Key+"/") {
+			// an ancestor of the key was removed
+			return client.EventNodeDeleted
+		}
+		return client.EventNodeChildrenChanged
+	}
+	switch resp.Action {
+	case actionCreate:
+		return client.EventNodeCreated
+	case actionSet:
+		if resp.PrevNode == nil {
+			return client.EventNodeCreated
+		}
+		return client.EventNodeDataChanged
+	case actionDelete, actionExpire, actionCompareAndDelete:
+		return client.EventNodeDeleted
+	}
+	return client.EventNodeDataChanged
+}
+
+// sequenced returns the children of a directory ordered by the sequence
+// number appended to their names.
+func (c *Connection) sequenced(key string) ([]string, error) {
+	resp, err := c.api.get(key, false)
+	if err != nil {
+		return nil, xlateError(err)
+	}
+	type entry struct {
+		key string
+		seq uint64
+	}
+	entries := []entry{}
+	for _, n := range resp.Node.Nodes {
+		seq, err := parseSeq(n.Key)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{n.Key, seq})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.key
+	}
+	return keys, nil
+}
+
+// parseSeq returns the sequence number appended to the name of a node
+func parseSeq(key string) (uint64, error) {
+	name := path.Base(key)
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	if i == len(name) {
+		return 0, fmt.Errorf("no sequence in %s", name)
+	}
+	return strconv.ParseUint(name[i:], 10, 64)
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	plog = logging.PackageLogger() // the standard package logger
+)
+
+// DefaultSessionTTL is the default time to live of ephemeral nodes when the
+// connection that owns them stops refreshing them.
+const DefaultSessionTTL = 15 * time.Second
+
+// DefaultRequestTimeout is the default timeout of a single request to etcd
+const DefaultRequestTimeout = 10 * time.Second
+
+// Driver implements an etcd based client.Driver interface
+type Driver struct{}
+
+// Assert that the etcd driver meets the Driver interface
+var _ client.Driver = &Driver{}
+
+func init() {
+	client.RegisterDriver("etcd", &Driver{})
+}
+
+// DSN is an etcd specific struct used for connections. It can be
+// serialized.
+type DSN struct {
+	Endpoints      []string
+	SessionTTL     time.Duration
+	RequestTimeout time.Duration
+}
+
+// NewDSN returns a new DSN object from endpoints and timeouts.
+func NewDSN(endpoints []string, sessionTTL, requestTimeout time.Duration) DSN {
+	dsn := DSN{
+		Endpoints:      endpoints,
+		SessionTTL:     sessionTTL,
+		RequestTimeout: requestTimeout,
+	}
+	if len(dsn.Endpoints) == 0 {
+		dsn.Endpoints = []string{"127.0.0.1:2379"}
+	}
+	if dsn.SessionTTL < time.Second {
+		dsn.SessionTTL = DefaultSessionTTL
+	}
+	if dsn.RequestTimeout <= 0 {
+		dsn.RequestTimeout = DefaultRequestTimeout
+	}
+	return dsn
+}
+
+// String creates a parsable (JSON) string represenation of this DSN.
+func (dsn DSN) String() string {
+	bytes, err := json.Marshal(dsn)
+	if err != nil {
+		panic(err)
+	}
+	return string(bytes)
+}
+
+// ParseDSN decodes a string (JSON) represnation of a DSN object.
+func ParseDSN(dsn string) (val DSN, err error) {
+	err = json.Unmarshal([]byte(dsn), &val)
+	return val, err
+}
+
+// GetConnection returns an etcd connection given the dsn. The caller is
+// responsible for closing the returned connection.
+func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, error) {
+	dsnVal, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	dsnVal = NewDSN(dsnVal.Endpoints, dsnVal.SessionTTL, dsnVal.RequestTimeout)
+
+	a := newAPI(dsnVal.Endpoints, dsnVal.RequestTimeout)
+
+	// verify that the cluster is reachable
+	if _, err := a.get("/", false); err != nil {
+		plog.WithField("endpoints", dsnVal.Endpoints).WithError(err).Debug("Could not reach etcd")
+		return nil, client.ErrNoServer
+	}
+
+	conn := &Connection{
+		api:        a,
+		basePath:   basePath,
+		ttl:        dsnVal.SessionTTL,
+		ephemerals: make(map[string]struct{}),
+		done:       make(chan struct{}),
+	}
+	go conn.keepalive()
+	return conn, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+func xlateError(err error) error {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		return err
+	}
+
+	switch apiErr.Code {
+	case errKeyNotFound:
+		return client.ErrNoNode
+	case errTestFailed:
+		return client.ErrBadVersion
+	case errNodeExist:
+		return client.ErrNodeExists
+	case errDirNotEmpty:
+		return client.ErrNotEmpty
+	case errNotFile, errNotDir:
+		return client.ErrInvalidPath
+	case errRaftInternal, errLeaderElect:
+		return client.ErrNoServer
+	}
+	return client.ErrAPIError
+}
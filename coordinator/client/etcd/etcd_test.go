@@ -0,0 +1,136 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package etcd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+func TestNewDSN_Defaults(t *testing.T) {
+	dsn := NewDSN(nil, 0, 0)
+	if len(dsn.Endpoints) != 1 || dsn.Endpoints[0] != "127.0.0.1:2379" {
+		t.Errorf("Unexpected default endpoints: %v", dsn.Endpoints)
+	}
+	if dsn.SessionTTL != DefaultSessionTTL {
+		t.Errorf("Expected session ttl %s, got %s", DefaultSessionTTL, dsn.SessionTTL)
+	}
+	if dsn.RequestTimeout != DefaultRequestTimeout {
+		t.Errorf("Expected request timeout %s, got %s", DefaultRequestTimeout, dsn.RequestTimeout)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	expected := NewDSN([]string{"host1:2379", "host2:2379"}, 30*time.Second, 5*time.Second)
+	actual, err := ParseDSN(expected.String())
+	if err != nil {
+		t.Fatalf("Could not parse dsn: %s", err)
+	}
+	if actual.String() != expected.String() {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
+
+func TestXlateError(t *testing.T) {
+	other := errors.New("other")
+	for _, tc := range []struct {
+		in  error
+		out error
+	}{
+		{&apiError{Code: errKeyNotFound}, client.ErrNoNode},
+		{&apiError{Code: errTestFailed}, client.ErrBadVersion},
+		{&apiError{Code: errNodeExist}, client.ErrNodeExists},
+		{&apiError{Code: errDirNotEmpty}, client.ErrNotEmpty},
+		{&apiError{Code: errNotDir}, client.ErrInvalidPath},
+		{&apiError{Code: errLeaderElect}, client.ErrNoServer},
+		{&apiError{Code: 999}, client.ErrAPIError},
+		{other, other},
+	} {
+		if err := xlateError(tc.in); err != tc.out {
+			t.Errorf("Expected %v for %v, got %v", tc.out, tc.in, err)
+		}
+	}
+}
+
+func TestParseSeq(t *testing.T) {
+	if seq, err := parseSeq("/a/b/lock-0000000042"); err != nil || seq != 42 {
+		t.Errorf("Expected 42, got %d (%v)", seq, err)
+	}
+	if _, err := parseSeq("/a/b/lock-"); err == nil {
+		t.Errorf("Expected an error for a key without a sequence")
+	}
+}
+
+func TestToEventType(t *testing.T) {
+	key := "/a/b"
+	for _, tc := range []struct {
+		resp *response
+		out  client.EventType
+	}{
+		{&response{Action: actionCreate, Node: &node{Key: key}}, client.EventNodeCreated},
+		{&response{Action: actionSet, Node: &node{Key: key}}, client.EventNodeCreated},
+		{&response{Action: actionSet, Node: &node{Key: key}, PrevNode: &node{Key: key}}, client.EventNodeDataChanged},
+		{&response{Action: actionExpire, Node: &node{Key: key}}, client.EventNodeDeleted},
+		{&response{Action: actionDelete, Node: &node{Key: "/a"}}, client.EventNodeDeleted},
+		{&response{Action: actionCreate, Node: &node{Key: "/a/b/c"}}, client.EventNodeChildrenChanged},
+	} {
+		if actual := toEventType(tc.resp, key); actual != tc.out {
+			t.Errorf("Expected %v for %s %s, got %v", tc.out, tc.resp.Action, tc.resp.Node.Key, actual)
+		}
+	}
+}
+
+func TestAPI_Failover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Etcd-Index", "7")
+		fmt.Fprintf(w, `{"action":"get","node":{"key":"%s","value":"v","modifiedIndex":5}}`, r.URL.Path[len("/v2/keys"):])
+	}))
+	defer up.Close()
+
+	a := newAPI([]string{down.URL, up.URL}, time.Second)
+	resp, err := a.get("/foo", false)
+	if err != nil {
+		t.Fatalf("Could not get key: %s", err)
+	}
+	if resp.Node.Key != "/foo" || resp.Node.Value != "v" || resp.Node.ModifiedIndex != 5 {
+		t.Errorf("Unexpected node: %+v", resp.Node)
+	}
+	if resp.EtcdIndex != 7 {
+		t.Errorf("Expected etcd index 7, got %d", resp.EtcdIndex)
+	}
+}
+
+func TestAPI_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errorCode":100,"message":"Key not found","cause":"/foo","index":3}`)
+	}))
+	defer srv.Close()
+
+	a := newAPI([]string{srv.URL}, time.Second)
+	if _, err := a.get("/foo", false); xlateError(err) != client.ErrNoNode {
+		t.Errorf("Expected %s, got %v", client.ErrNoNode, err)
+	}
+}
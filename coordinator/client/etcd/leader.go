@@ -0,0 +1,144 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"encoding/json"
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Leader is an object to facilitate creating an election in etcd.
+type Leader struct {
+	conn     *Connection
+	path     string
+	lockPath string
+}
+
+// Current returns the currect elected leader and deserializes it in to node.
+// It will return ErrNoLeaderFound if no leader has been elected.
+func (l *Leader) Current(node client.Node) error {
+	l.conn.RLock()
+	defer l.conn.RUnlock()
+	if err := l.conn.isClosed(); err != nil {
+		return err
+	}
+
+	leader, err := l.getLowestSequence()
+	if err != nil {
+		return err
+	}
+	resp, err := l.conn.api.get(path.Join(leader, dataKey), false)
+	if err != nil {
+		return xlateError(err)
+	}
+	if len(resp.Node.Value) == 0 {
+		return client.ErrEmptyNode
+	}
+	if err := json.Unmarshal([]byte(resp.Node.Value), node); err != nil {
+		return client.ErrSerialization
+	}
+	node.SetVersion(&Stat{ModifiedIndex: resp.Node.ModifiedIndex})
+	return nil
+}
+
+// TakeLead attempts to aquire the leader role. When aquired it returns a
+// channel on the leader node so the caller can react to changes in etcd
+func (l *Leader) TakeLead(node client.Node, cancel <-chan struct{}) (<-chan client.Event, error) {
+	if l.lockPath != "" {
+		return nil, ErrDeadlock
+	}
+
+	l.conn.RLock()
+	defer l.conn.RUnlock()
+	if err := l.conn.isClosed(); err != nil {
+		return nil, err
+	}
+
+	prefix := path.Join(l.path, "leader-")
+	if err := l.conn.ensurePath(prefix); err != nil {
+		return nil, err
+	}
+	lockPath, err := l.conn.createEphemeral(prefix, node)
+	if err != nil {
+		return nil, err
+	}
+	l.lockPath = lockPath
+	lockSeq, err := parseSeq(l.lockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// This follows the same recipe as the zookeeper driver; wait until the
+	// lowest sequenced node is this one.
+	for {
+		leader, err := l.getLowestSequence()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := l.conn.api.get(leader, false)
+		if err != nil {
+			if xlateError(err) == client.ErrNoNode {
+				continue
+			}
+			return nil, xlateError(err)
+		}
+		if leader == l.lockPath {
+			return l.conn.watch(leader, resp.EtcdIndex+1, false, acceptAll, cancel), nil
+		} else if seq, _ := parseSeq(leader); seq > lockSeq {
+			return nil, client.ErrNoNode
+		}
+
+		done := make(chan struct{})
+		ev := <-l.conn.watch(leader, resp.EtcdIndex+1, false, acceptAll, done)
+		close(done)
+		if ev.Type == client.EventSession {
+			return nil, ev.Err
+		}
+	}
+}
+
+// ReleaseLead release the current leader role. It will return ErrNotLocked if
+// the current object is not locked.
+func (l *Leader) ReleaseLead() error {
+	if l.lockPath == "" {
+		return ErrNotLocked
+	}
+
+	l.conn.RLock()
+	defer l.conn.RUnlock()
+	if err := l.conn.isClosed(); err != nil {
+		return err
+	}
+
+	l.conn.removeEphemeral(l.lockPath)
+	if _, err := l.conn.api.delete(l.lockPath, true); err != nil {
+		return xlateError(err)
+	}
+	l.lockPath = ""
+	return nil
+}
+
+// getLowestSequence returns the key of the node with the lowest sequence
+func (l *Leader) getLowestSequence() (string, error) {
+	keys, err := l.conn.sequenced(l.conn.key(l.path))
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 0 {
+		return "", ErrNoLeaderFound
+	}
+	return keys[0], nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"errors"
+	"path"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+var (
+	// ErrDeadlock is returned when a lock is aquired twice on the same object.
+	ErrDeadlock = errors.New("etcd: trying to acquire a lock twice")
+
+	// ErrNotLocked is returned when a caller attempts to release a lock that
+	// has not been aquired
+	ErrNotLocked = errors.New("etcd: not locked")
+
+	// ErrNoLeaderFound is returned when a leader has not been elected
+	ErrNoLeaderFound = errors.New("etcd: no leader found")
+)
+
+// Lock creates a object to facilitate create a locking pattern in etcd.
+type Lock struct {
+	conn    *Connection
+	path    string
+	lockKey string
+}
+
+// Lock attempts to acquire the lock.
+func (l *Lock) Lock() error {
+	if l.lockKey != "" {
+		return ErrDeadlock
+	}
+
+	l.conn.RLock()
+	defer l.conn.RUnlock()
+	if err := l.conn.isClosed(); err != nil {
+		return err
+	}
+
+	pth := path.Join(l.path, "lock-")
+	if err := l.conn.ensurePath(pth); err != nil {
+		return err
+	}
+	lockKey, err := l.conn.createEphemeral(pth, &client.Dir{})
+	if err != nil {
+		return err
+	}
+
+	// wait for every node ahead of this one to be released
+	for {
+		keys, err := l.conn.sequenced(path.Dir(lockKey))
+		if err != nil {
+			l.conn.api.delete(lockKey, true)
+			l.conn.removeEphemeral(lockKey)
+			return err
+		}
+
+		prev := ""
+		for _, key := range keys {
+			if key == lockKey {
+				break
+			}
+			prev = key
+		}
+		if prev == "" {
+			l.lockKey = lockKey
+			return nil
+		}
+
+		done := make(chan struct{})
+		resp, err := l.conn.api.get(prev, false)
+		if err != nil {
+			close(done)
+			if xlateError(err) == client.ErrNoNode {
+				continue
+			}
+			l.conn.api.delete(lockKey, true)
+			l.conn.removeEphemeral(lockKey)
+			return xlateError(err)
+		}
+		ev := <-l.conn.watch(prev, resp.EtcdIndex+1, false, acceptAll, done)
+		close(done)
+		if ev.Type == client.EventSession {
+			l.conn.api.delete(lockKey, true)
+			l.conn.removeEphemeral(lockKey)
+			return ev.Err
+		}
+	}
+}
+
+// Unlock attempts to release the lock.
+func (l *Lock) Unlock() error {
+	if l.lockKey == "" {
+		return ErrNotLocked
+	}
+
+	l.conn.RLock()
+	defer l.conn.RUnlock()
+	if err := l.conn.isClosed(); err != nil {
+		return err
+	}
+
+	l.conn.removeEphemeral(l.lockKey)
+	if _, err := l.conn.api.delete(l.lockKey, true); err != nil {
+		if err = xlateError(err); err != client.ErrNoNode {
+			return err
+		}
+	}
+	l.lockKey = ""
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+const (
+	multiCreate int = iota
+	multiSet
+	multiDelete
+)
+
+type multiReq struct {
+	Type int
+	Path string
+	Node client.Node
+}
+
+// Transaction applies a batch of operations.  The etcd v2 api does not
+// support multi-key transactions, so the operations are validated up front
+// and then applied in order; a failure part way through stops the commit but
+// does not roll back the operations that were already applied.
+type Transaction struct {
+	conn *Connection
+	ops  []multiReq
+}
+
+func (t *Transaction) Create(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiCreate, path, node})
+	return t
+}
+
+func (t *Transaction) Set(path string, node client.Node) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiSet, path, node})
+	return t
+}
+
+func (t *Transaction) Delete(path string) client.Transaction {
+	t.ops = append(t.ops, multiReq{multiDelete, path, nil})
+	return t
+}
+
+func (t *Transaction) Commit() error {
+	t.conn.RLock()
+	defer t.conn.RUnlock()
+	if err := t.conn.isClosed(); err != nil {
+		return err
+	}
+
+	// validate the operations before applying any of them
+	for _, op := range t.ops {
+		exists, err := t.conn.exists(op.Path)
+		if err != nil {
+			return err
+		}
+		switch op.Type {
+		case multiCreate:
+			if exists {
+				return client.ErrNodeExists
+			}
+		case multiSet:
+			if !exists {
+				return client.ErrNoNode
+			}
+			if vers := op.Node.Version(); vers != nil {
+				if _, ok := vers.(*Stat); !ok {
+					plog.WithField("path", op.Path).WithField("node", op.Node).Error("Could not parse version of node at path")
+					return client.ErrInvalidVersionObj
+				}
+			}
+		case multiDelete:
+			if !exists {
+				return client.ErrNoNode
+			}
+		}
+	}
+
+	for _, op := range t.ops {
+		logger := plog.WithField("path", op.Path)
+		var err error
+		switch op.Type {
+		case multiCreate:
+			err = t.conn.create(op.Path, op.Node)
+		case multiSet:
+			err = t.conn.set(op.Path, op.Node)
+		case multiDelete:
+			err = t.conn.delete(op.Path)
+		}
+		if err != nil {
+			logger.WithError(err).Error("Could not apply transaction operation")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "sync/atomic"
+
+// SessionStats counts the session and watch activity of the coordinator
+// connections in this process.
+type SessionStats struct {
+	Sessions              int64 // sessions established, including reconnects
+	Disconnects           int64 // connections lost to the server
+	Expirations           int64 // sessions expired by the server
+	WatchesOutstanding    int64 // watches waiting on an event
+	WatchesLost           int64 // watches ended by a lost session
+	EphemeralReplays      int64 // ephemeral nodes re-created after a lost session
+	EphemeralReplayErrors int64 // ephemeral nodes that could not be re-created
+}
+
+var sessionStats SessionStats
+
+// GetSessionStats returns a snapshot of the session statistics
+func GetSessionStats() SessionStats {
+	return SessionStats{
+		Sessions:              atomic.LoadInt64(&sessionStats.Sessions),
+		Disconnects:           atomic.LoadInt64(&sessionStats.Disconnects),
+		Expirations:           atomic.LoadInt64(&sessionStats.Expirations),
+		WatchesOutstanding:    atomic.LoadInt64(&sessionStats.WatchesOutstanding),
+		WatchesLost:           atomic.LoadInt64(&sessionStats.WatchesLost),
+		EphemeralReplays:      atomic.LoadInt64(&sessionStats.EphemeralReplays),
+		EphemeralReplayErrors: atomic.LoadInt64(&sessionStats.EphemeralReplayErrors),
+	}
+}
+
+// RecordSession is called by drivers when a session is established
+func RecordSession() {
+	atomic.AddInt64(&sessionStats.Sessions, 1)
+}
+
+// RecordDisconnect is called by drivers when the connection to the server
+// is lost
+func RecordDisconnect() {
+	atomic.AddInt64(&sessionStats.Disconnects, 1)
+}
+
+// RecordExpiration is called by drivers when the server expires a session
+func RecordExpiration() {
+	atomic.AddInt64(&sessionStats.Expirations, 1)
+}
+
+// RecordWatch is called by drivers when a watch is set
+func RecordWatch() {
+	atomic.AddInt64(&sessionStats.WatchesOutstanding, 1)
+}
+
+// RecordWatchDone is called by drivers when a watch fires or is canceled.
+// Lost is true if the watch ended because the session was lost.
+func RecordWatchDone(lost bool) {
+	atomic.AddInt64(&sessionStats.WatchesOutstanding, -1)
+	if lost {
+		atomic.AddInt64(&sessionStats.WatchesLost, 1)
+	}
+}
+
+// RecordEphemeralReplay is called by drivers after re-creating an ephemeral
+// node that was lost with its session.
+func RecordEphemeralReplay(err error) {
+	if err != nil {
+		atomic.AddInt64(&sessionStats.EphemeralReplayErrors, 1)
+	} else {
+		atomic.AddInt64(&sessionStats.EphemeralReplays, 1)
+	}
+}
@@ -16,6 +16,7 @@ package zookeeper
 import (
 	"encoding/json"
 	"path"
+	"strings"
 	"sync"
 
 	zklib "github.com/control-center/go-zookeeper/zk"
@@ -29,6 +30,17 @@ type Connection struct {
 	basePath string
 	onClose  func(int)
 	id       int
+
+	// ephemeral nodes to replay if the session expires, by created path
+	ephemeralsLock sync.Mutex
+	ephemerals     map[string]ephemeral
+	expired        bool
+}
+
+// ephemeral is an ephemeral node created by the connection
+type ephemeral struct {
+	path string
+	data []byte
 }
 
 // Assert that Connection implements client.Connection.
@@ -221,9 +233,102 @@ func (c *Connection) createEphemeral(p string, node client.Node) (string, error)
 	}
 	pth := path.Join(c.basePath, p)
 	epth, err := c.conn.CreateProtectedEphemeralSequential(pth, bytes, zklib.WorldACL(zklib.PermAll))
+	if err == nil {
+		c.ephemeralsLock.Lock()
+		if c.ephemerals == nil {
+			c.ephemerals = make(map[string]ephemeral)
+		}
+		c.ephemerals[epth] = ephemeral{path: pth, data: bytes}
+		c.ephemeralsLock.Unlock()
+	}
 	return epth, xlateError(err)
 }
 
+// sessionEvent records a change in the state of the session and replays the
+// ephemeral nodes of the connection once a session replaces one that expired.
+func (c *Connection) sessionEvent(e zklib.Event) {
+	logger := plog.WithField("server", e.Server)
+	switch e.State {
+	case zklib.StateDisconnected:
+		client.RecordDisconnect()
+		logger.Debug("Disconnected from the coordinator")
+	case zklib.StateExpired:
+		client.RecordExpiration()
+		logger.Warn("Coordinator session expired")
+		c.ephemeralsLock.Lock()
+		c.expired = true
+		c.ephemeralsLock.Unlock()
+	case zklib.StateHasSession:
+		client.RecordSession()
+		c.ephemeralsLock.Lock()
+		expired := c.expired
+		c.expired = false
+		c.ephemeralsLock.Unlock()
+		if expired {
+			logger.Info("Established a new coordinator session")
+			go c.replayEphemerals()
+		}
+	}
+}
+
+// replayEphemerals re-creates the ephemeral nodes of the connection that were
+// deleted with its expired session, unless their owners registered them
+// again or their parent nodes are gone.
+func (c *Connection) replayEphemerals() {
+	c.RLock()
+	defer c.RUnlock()
+	if err := c.isClosed(); err != nil {
+		return
+	}
+	c.ephemeralsLock.Lock()
+	defer c.ephemeralsLock.Unlock()
+	for epth, e := range c.ephemerals {
+		logger := plog.WithField("path", e.path)
+		if ok, _, err := c.conn.Exists(epth); err == nil && ok {
+			continue
+		}
+		children, _, err := c.conn.Children(path.Dir(e.path))
+		if err == zklib.ErrNoNode {
+			logger.Debug("Parent of ephemeral node was removed, not replaying")
+			delete(c.ephemerals, epth)
+			continue
+		} else if err != nil {
+			client.RecordEphemeralReplay(err)
+			logger.WithError(err).Warn("Could not replay ephemeral node")
+			continue
+		}
+		if hasEphemeral(children, path.Base(e.path)) {
+			logger.Debug("Ephemeral node was registered again by its owner")
+			delete(c.ephemerals, epth)
+			continue
+		}
+		npth, err := c.conn.CreateProtectedEphemeralSequential(e.path, e.data, zklib.WorldACL(zklib.PermAll))
+		client.RecordEphemeralReplay(err)
+		if err != nil {
+			logger.WithError(err).Warn("Could not replay ephemeral node")
+			continue
+		}
+		delete(c.ephemerals, epth)
+		c.ephemerals[npth] = e
+		logger.WithField("node", npth).Info("Replayed ephemeral node after the session expired")
+	}
+}
+
+// hasEphemeral returns true if one of the children is a sequential node with
+// the given name
+func hasEphemeral(children []string, name string) bool {
+	for _, child := range children {
+		// strip the prefix of a protected node, _c_<32 character guid>-
+		if strings.HasPrefix(child, "_c_") && len(child) > 36 {
+			child = child[36:]
+		}
+		if strings.HasPrefix(child, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // Set assigns a value to an existing node at a given path
 func (c *Connection) Set(path string, node client.Node) error {
 	c.RLock()
@@ -254,13 +359,26 @@ func (c *Connection) set(p string, node client.Node) error {
 }
 
 // Delete recursively removes a path and its children
-func (c *Connection) Delete(path string) error {
+func (c *Connection) Delete(p string) error {
 	c.RLock()
 	defer c.RUnlock()
 	if err := c.isClosed(); err != nil {
 		return err
 	}
-	return c.delete(path)
+	if err := c.delete(p); err != nil {
+		return err
+	}
+
+	// stop replaying the ephemeral nodes that were deleted
+	pth := path.Join(c.basePath, p)
+	c.ephemeralsLock.Lock()
+	defer c.ephemeralsLock.Unlock()
+	for epth := range c.ephemerals {
+		if epth == pth || strings.HasPrefix(epth, pth+"/") {
+			delete(c.ephemerals, epth)
+		}
+	}
+	return nil
 }
 
 func (c *Connection) delete(p string) error {
@@ -373,15 +491,18 @@ func (c *Connection) getW(p string, node client.Node, cancel <-chan struct{}) (<
 
 func (c *Connection) toClientEvent(ch <-chan zklib.Event, cancel <-chan struct{}) <-chan client.Event {
 	evCh := make(chan client.Event, 1)
+	client.RecordWatch()
 	go func() {
 		select {
 		case zkev := <-ch:
+			client.RecordWatchDone(zkev.Type == zklib.EventNotWatching)
 			ev := client.Event{Type: client.EventType(zkev.Type)}
 			select {
 			case evCh <- ev:
 			case <-cancel:
 			}
 		case <-cancel:
+			client.RecordWatchDone(false)
 			c.cancelEvent(ch)
 		}
 	}()
@@ -112,12 +112,17 @@ func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, er
 		case e := <-event:
 			if e.State == zklib.StateHasSession {
 				connected = true
+				client.RecordSession()
 				plog.WithField("event", e).Debug("zk connection has session")
 			} else {
 				plog.WithField("event", e).Debug("waiting for zk connection to have session")
 			}
 		}
 	}
+	c := &Connection{
+		basePath: basePath,
+		conn:     conn,
+	}
 	go func() {
 		for {
 			select {
@@ -127,12 +132,10 @@ func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, er
 					return
 				} else {
 					plog.WithField("event", e).Debug("zk state change event received")
+					c.sessionEvent(e)
 				}
 			}
 		}
 	}()
-	return &Connection{
-		basePath: basePath,
-		conn:     conn,
-	}, nil
+	return c, nil
 }
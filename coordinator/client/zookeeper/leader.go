@@ -94,6 +94,7 @@ func (l *Leader) TakeLead(node client.Node, cancel <-chan struct{}) (<-chan clie
 	}
 	lockSeq, err := parseSeq(l.lockPath)
 	if err != nil {
+		l.abandon()
 		return nil, err
 	}
 	// This implements the leader election recipe recommeded by ZooKeeper
@@ -101,10 +102,12 @@ func (l *Leader) TakeLead(node client.Node, cancel <-chan struct{}) (<-chan clie
 	for {
 		leader, seq, err := l.getLowestSequence()
 		if err != nil {
+			l.abandon()
 			return nil, err
 		}
 		exists, _, ch, err := l.c.ExistsW(leader)
 		if err != nil && err != zklib.ErrNoNode {
+			l.abandon()
 			return nil, xlateError(err)
 		} else if !exists {
 			l.c.CancelEvent(ch)
@@ -113,26 +116,41 @@ func (l *Leader) TakeLead(node client.Node, cancel <-chan struct{}) (<-chan clie
 		if leader == l.lockPath {
 			return l.toClientEvent(ch, cancel), nil
 		} else if seq > lockSeq {
+			// the candidate node was lost with its session
 			l.c.CancelEvent(ch)
+			l.abandon()
 			return nil, client.ErrNoNode
 		}
 		if ev := <-ch; ev.Err != nil {
+			l.abandon()
 			return nil, xlateError(ev.Err)
 		}
 	}
 }
 
+// abandon gives up the candidacy of a failed election, so that TakeLead can
+// be called again once the session is restored.
+func (l *Leader) abandon() {
+	if err := l.c.Delete(l.lockPath, -1); err != nil && err != zklib.ErrNoNode {
+		plog.WithError(err).WithField("path", l.lockPath).Debug("Could not delete leader candidate node")
+	}
+	l.lockPath = ""
+}
+
 func (l *Leader) toClientEvent(ch <-chan zklib.Event, cancel <-chan struct{}) <-chan client.Event {
 	evCh := make(chan client.Event, 1)
+	client.RecordWatch()
 	go func() {
 		select {
 		case zkEv := <-ch:
+			client.RecordWatchDone(zkEv.Type == zklib.EventNotWatching)
 			ev := client.Event{Type: client.EventType(zkEv.Type)}
 			select {
 			case evCh <- ev:
 			case <-cancel:
 			}
 		case <-cancel:
+			client.RecordWatchDone(false)
 			l.c.CancelEvent(ch)
 		}
 	}()
@@ -145,7 +163,8 @@ func (l *Leader) ReleaseLead() error {
 	if l.lockPath == "" {
 		return ErrNotLocked
 	}
-	if err := l.c.Delete(l.lockPath, -1); err != nil {
+	// the node is already gone if the session expired
+	if err := l.c.Delete(l.lockPath, -1); err != nil && err != zklib.ErrNoNode {
 		return xlateError(err)
 	}
 	l.lockPath = ""
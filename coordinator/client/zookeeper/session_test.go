@@ -0,0 +1,153 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package zookeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	zklib "github.com/control-center/go-zookeeper/zk"
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	zzktest "github.com/control-center/serviced/zzk/test"
+)
+
+func TestZkDriver_ReplayEphemerals(t *testing.T) {
+	zzkServer := &zzktest.ZZKServer{}
+	if err := zzkServer.Start(); err != nil {
+		t.Fatalf("Could not start zookeeper: %s", err)
+	}
+	defer zzkServer.Stop()
+	time.Sleep(time.Second)
+
+	servers := []string{fmt.Sprintf("127.0.0.1:%d", zzkServer.Port)}
+
+	drv := Driver{}
+	dsnBytes, err := json.Marshal(DSN{Servers: servers, SessionTimeout: time.Second * 15})
+	if err != nil {
+		t.Fatalf("unexpected error creating zk DSN: %s", err)
+	}
+	c, err := drv.GetConnection(string(dsnBytes), "/replay")
+	if err != nil {
+		t.Fatal("unexpected error getting connection")
+	}
+	defer c.Close()
+	conn := c.(*Connection)
+
+	if err := conn.CreateDir("/hosts/host1/online"); err != nil {
+		t.Fatalf("could not create online node: %s", err)
+	}
+	if err := conn.CreateDir("/hosts/host2/online"); err != nil {
+		t.Fatalf("could not create online node: %s", err)
+	}
+	epth, err := conn.CreateEphemeral("/hosts/host1/online/host1", &testNodeT{Name: "host1"})
+	if err != nil {
+		t.Fatalf("could not create ephemeral node: %s", err)
+	}
+	if _, err := conn.CreateEphemeral("/hosts/host2/online/host2", &testNodeT{Name: "host2"}); err != nil {
+		t.Fatalf("could not create ephemeral node: %s", err)
+	}
+
+	// simulate losing the node of host1 with an expired session, while host2
+	// has already been unregistered by its owner
+	if err := conn.conn.Delete(epth, -1); err != nil {
+		t.Fatalf("could not delete ephemeral node: %s", err)
+	}
+	if err := conn.Delete("/hosts/host2/online"); err != nil {
+		t.Fatalf("could not delete online node: %s", err)
+	}
+
+	before := coordclient.GetSessionStats()
+	conn.sessionEvent(zklib.Event{Type: zklib.EventSession, State: zklib.StateExpired})
+	conn.sessionEvent(zklib.Event{Type: zklib.EventSession, State: zklib.StateHasSession})
+
+	timeout := time.After(5 * time.Second)
+	for {
+		children, err := conn.Children("/hosts/host1/online")
+		if err != nil {
+			t.Fatalf("could not get children: %s", err)
+		}
+		if len(children) == 1 {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("ephemeral node was not replayed")
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	after := coordclient.GetSessionStats()
+	if after.Expirations != before.Expirations+1 {
+		t.Errorf("expected 1 expiration, got %d", after.Expirations-before.Expirations)
+	}
+	if after.EphemeralReplays != before.EphemeralReplays+1 {
+		t.Errorf("expected 1 replay, got %d", after.EphemeralReplays-before.EphemeralReplays)
+	}
+	if ok, _ := conn.Exists("/hosts/host2/online"); ok {
+		t.Errorf("deleted node was replayed")
+	}
+
+	// a second replay does not duplicate the node
+	conn.replayEphemerals()
+	if children, _ := conn.Children("/hosts/host1/online"); len(children) != 1 {
+		t.Errorf("expected 1 child, got %v", children)
+	}
+}
+
+func TestLeader_ReleaseLostLead(t *testing.T) {
+	zzkServer := &zzktest.ZZKServer{}
+	if err := zzkServer.Start(); err != nil {
+		t.Fatalf("Could not start zookeeper: %s", err)
+	}
+	defer zzkServer.Stop()
+	time.Sleep(time.Second)
+
+	servers := []string{fmt.Sprintf("127.0.0.1:%d", zzkServer.Port)}
+
+	drv := Driver{}
+	dsnBytes, err := json.Marshal(DSN{Servers: servers, SessionTimeout: time.Second * 15})
+	if err != nil {
+		t.Fatalf("unexpected error creating zk DSN: %s", err)
+	}
+	c, err := drv.GetConnection(string(dsnBytes), "/lost")
+	if err != nil {
+		t.Fatal("unexpected error getting connection")
+	}
+	defer c.Close()
+
+	leader, err := c.NewLeader("/leader")
+	if err != nil {
+		t.Fatalf("could not create leader: %s", err)
+	}
+	if _, err := leader.TakeLead(&testNodeT{Name: "leader"}, nil); err != nil {
+		t.Fatalf("could not take lead: %s", err)
+	}
+
+	// simulate losing the leader node with an expired session
+	l := leader.(*Leader)
+	if err := l.c.Delete(l.lockPath, -1); err != nil {
+		t.Fatalf("could not delete leader node: %s", err)
+	}
+	if err := leader.ReleaseLead(); err != nil {
+		t.Fatalf("could not release lost lead: %s", err)
+	}
+	if _, err := leader.TakeLead(&testNodeT{Name: "leader"}, nil); err != nil {
+		t.Fatalf("could not take lead again: %s", err)
+	}
+}
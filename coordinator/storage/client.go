@@ -23,7 +23,9 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/dfs/nfs"
+	"github.com/control-center/serviced/dfs/rbd"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk"
@@ -34,6 +36,9 @@ type nfsMountT func(string, string) error
 
 var nfsMount = nfs.Mount
 var nfsUnmount = nfs.Unmount
+var rbdMount = rbd.Mount
+var rbdUnmount = rbd.Unmount
+var rbdInstalled = rbd.Installed
 var mkdirAll = os.MkdirAll
 var storageClient *Client
 var mp = utils.GetDefaultMountProc()
@@ -44,6 +49,7 @@ var ErrClientNotInitialized = errors.New("storage client not initialized")
 type Client struct {
 	host         *host.Host
 	exportedPath string
+	network      string
 	localPath    string
 	closing      chan struct{}
 	mounted      chan chan<- string
@@ -119,13 +125,31 @@ func (c *Client) Close() {
 
 // Mount source  to local destination path. The source is relative to the exported path
 func (c *Client) Mount(source, destination string) error {
+	if c.network == network.DriverRBD {
+		spec, err := rbd.ImageSpec(exportedPathName(c.exportedPath), source)
+		if err != nil {
+			return err
+		}
+		return rbdMount(spec, destination)
+	}
 	return nfsMount(&nfs.NFSDriver{}, path.Join(c.exportedPath, source), destination)
 }
 
 func (c *Client) Unmount(destination string) error {
+	if c.network == network.DriverRBD {
+		return rbdUnmount(destination)
+	}
 	return nfsUnmount(&nfs.NFSDriver{}, destination)
 }
 
+// exportedPathName strips the leader address from an exported path
+func exportedPathName(exportedPath string) string {
+	if idx := strings.Index(exportedPath, ":"); idx >= 0 {
+		return exportedPath[idx+1:]
+	}
+	return exportedPath
+}
+
 func (c *Client) loop() {
 	var err error
 	var e <-chan client.Event
@@ -207,7 +231,13 @@ func (c *Client) loop() {
 			continue
 		}
 
-		if leaderNode.IPAddr != c.host.IPAddr {
+		if leaderNode.Network == network.DriverRBD {
+			logger.Info("Checking if RBD is supported")
+			if err = rbdInstalled(); err != nil {
+				logger.WithError(err).Error("Install the ceph-common package")
+				continue
+			}
+		} else if leaderNode.IPAddr != c.host.IPAddr {
 			logger.Info("Checking if NFS is supported")
 			nfsd := &nfs.NFSDriver{}
 			err = nfsd.Installed()
@@ -225,6 +255,7 @@ func (c *Client) loop() {
 		select {
 		case doneC <- leaderNode.ExportPath:
 			c.exportedPath = leaderNode.ExportPath
+			c.network = leaderNode.Network
 			storageClient = c
 			// notifying someone who cares
 			doneC = nil
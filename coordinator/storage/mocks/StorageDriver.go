@@ -6,6 +6,19 @@ type StorageDriver struct {
 	mock.Mock
 }
 
+func (_m *StorageDriver) Driver() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 func (_m *StorageDriver) ExportPath() string {
 	ret := _m.Called()
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/coordinator/client/zookeeper"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/logging"
 )
@@ -38,21 +39,7 @@ type Server struct {
 // StorageDriver is an interface that storage subsystem must implement to be used
 // by this packages Server implementation.
 type StorageDriver interface {
-	// ExportPath will be something like "serviced_volumes_v2"
-	ExportPath() string
-	// ExportNamePath() will be something like "/exports/serviced_volumes_v2"
-	ExportNamePath() string
-	SetClients(clients ...string)
-	Sync() error
-	//TODO: remove Restart and Stop
-	Restart() error
-	Stop() error
-	// AddVolume notify storage driver that volume at path is available for sharing
-	AddVolume(path string) error
-	// RemoveVolume notify storage driver that volume at path is should not be shared
-	RemoveVolume(path string) error
-	// Get the backing device for a path
-	GetDevice(path string) (uint64, error)
+	network.Storage
 }
 
 // NewServer returns a Server object to manage the exported file system
@@ -72,6 +59,7 @@ func NewServer(driver StorageDriver, host *host.Host, volumesPath string) (*Serv
 func (s *Server) Run(shutdown <-chan interface{}, conn client.Connection) error {
 	node := &Node{
 		Host:       *s.host,
+		Network:    s.driver.Driver(),
 		ExportPath: fmt.Sprintf("%s:%s", s.host.IPAddr, s.driver.ExportPath()),
 		ExportTime: strconv.FormatInt(time.Now().UnixNano(), 16),
 	}
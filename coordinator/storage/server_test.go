@@ -18,6 +18,7 @@ package storage
 import (
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/coordinator/client/zookeeper"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
@@ -40,6 +41,10 @@ type mockNfsDriverT struct {
 	exportPath string
 }
 
+func (m *mockNfsDriverT) Driver() string {
+	return network.DriverNFS
+}
+
 func (m *mockNfsDriverT) ExportPath() string {
 	return path.Join(m.exportPath, m.exportName)
 }
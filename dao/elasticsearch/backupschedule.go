@@ -0,0 +1,95 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	model "github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/facade"
+)
+
+// backupScheduleInterval is how often the master looks for scheduled backups
+// that are due
+var backupScheduleInterval = time.Minute
+
+// RunBackupSchedules takes the backups of the backup schedules as they fall
+// due, until shutdown.
+func (dao *ControlPlaneDao) RunBackupSchedules(shutdown <-chan interface{}) {
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-time.After(backupScheduleInterval):
+		}
+		dao.runDueBackups(time.Now())
+	}
+}
+
+// runDueBackups takes a backup for each schedule that is due, one at a time,
+// and deletes the backups that the schedules no longer keep.
+func (dao *ControlPlaneDao) runDueBackups(now time.Time) {
+	ctx := datastore.Get()
+	schedules, err := dao.facade.GetDueBackupSchedules(ctx, now)
+	if err != nil {
+		log.WithError(err).Warn("Could not look up due backup schedules")
+		return
+	}
+
+	for _, s := range schedules {
+		logger := log.WithFields(logrus.Fields{
+			"scheduleid": s.ID,
+			"schedule":   s.Schedule,
+		})
+
+		dirpath := s.Dirpath
+		if dirpath == "" {
+			dirpath = dao.backupsPath
+		}
+		req := model.BackupRequest{
+			Dirpath:              dirpath,
+			SnapshotSpacePercent: config.GetOptions().SnapshotSpacePercent,
+			Excludes:             s.Excludes,
+			Compression:          s.Compression,
+		}
+
+		logger.Info("Taking scheduled backup")
+		var filename string
+		backupErr := dao.facade.RunOperation(ctx, facade.BackupOperation, dirpath, func(ctx datastore.Context) error {
+			return dao.backup(ctx, req, &filename)
+		})
+		if backupErr == nil {
+			logger.WithField("filename", filename).Info("Took scheduled backup")
+		}
+
+		pruned, err := dao.facade.RecordBackupScheduleRun(ctx, s.ID, now, filename, backupErr)
+		if err != nil {
+			logger.WithError(err).Warn("Could not record scheduled backup")
+			continue
+		}
+		for _, name := range pruned {
+			path := filepath.Join(dirpath, name)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logger.WithField("filename", path).WithError(err).Warn("Could not delete backup beyond the retention of the schedule")
+			} else {
+				logger.WithField("filename", path).Info("Deleted backup beyond the retention of the schedule")
+			}
+		}
+	}
+}
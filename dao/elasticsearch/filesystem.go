@@ -18,17 +18,18 @@ import (
 	"os"
 
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"errors"
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
 	model "github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
-	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/volume"
-	gzip "github.com/klauspost/pgzip"
 )
 
 var (
@@ -96,13 +97,34 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	if len(backupRequest.Username) > 0 {
 		ctx.SetUser(backupRequest.Username)
 	}
+	return dao.backup(ctx, backupRequest, filename)
+}
+
+func (dao *ControlPlaneDao) backup(ctx datastore.Context, backupRequest model.BackupRequest, filename *string) (err error) {
+	if backupRequest.Dirpath == "" {
+		backupRequest.Dirpath = dao.backupsPath
+	}
+
+	// wait for the limits of the pools
+	endOperation, err := dao.facade.BeginDFSOperation(ctx, facade.BackupOperation, backupRequest.Dirpath)
+	if err != nil {
+		log.WithError(err).Error("Could not queue backup")
+		return err
+	}
+	defer endOperation()
+
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("backup")
 	defer dfslocker.Unlock()
 
-	if backupRequest.Dirpath == "" {
-		backupRequest.Dirpath = dao.backupsPath
+	if backupRequest.Compression == "" {
+		backupRequest.Compression = config.GetOptions().BackupCompression
+	}
+	compression, err := volume.ParseCompression(backupRequest.Compression)
+	if err != nil {
+		log.WithField("compression", backupRequest.Compression).WithError(err).Error("Could not take backup")
+		return err
 	}
 	// CC-2421: Check for space before doing backup
 	est := model.BackupEstimate{}
@@ -127,32 +149,19 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	}
 
 	// set the progress of the backup file
-	*filename = time.Now().UTC().Format("backup-2006-01-02-150405.tgz")
+	*filename = time.Now().UTC().Format("backup-2006-01-02-150405") + compression.Extension()
 	backupfilename := filepath.Join(backupRequest.Dirpath, *filename)
 
 	inprogress.SetProgress(backupfilename, "backup")
 	defer func() {
 		if err != nil {
 			log.WithError(err).Error("Backup failed with error")
-			os.Remove(backupfilename)
 		}
 		inprogress.SetError(err)
 	}()
-	// create the file and write
-	fh, err := os.Create(backupfilename)
-	if err != nil {
-		log.WithError(err).WithField("backupfilename", backupfilename).Error("Could not create backup file")
-		return
-	}
-	defer fh.Close()
-	w := gzip.NewWriter(fh)
-	// CC-2292: Limit concurrency of backup gzipping
-	// This setting will cause the writer to process up to 2 100KB blocks
-	// at a time before the writer blocks. The default was 16 250KB blocks.
-	// Smaller blocks will allow other goroutines to get time more frequently.
-	w.SetConcurrency(100000, 2)
-	defer w.Close()
-	err = dao.facade.Backup(ctx, w, backupRequest.Excludes, backupRequest.SnapshotSpacePercent, backupfilename)
+	// the facade keeps a partial backup file, so that a failed backup can
+	// be resumed
+	err = dao.facade.BackupToFile(ctx, backupRequest.Excludes, backupRequest.SnapshotSpacePercent, backupfilename, est.EstimatedBytes, compression)
 	return
 }
 
@@ -175,7 +184,8 @@ func (dao *ControlPlaneDao) GetBackupEstimate(backupRequest model.BackupRequest,
 	return nil
 }
 
-// AsyncBackup is the same as backup, but asynchronous
+// AsyncBackup is the same as backup, but asynchronous.  The backup runs as
+// an operation of the master that can be followed and canceled.
 func (dao *ControlPlaneDao) AsyncBackup(backupRequest model.BackupRequest, filename *string) (err error) {
 	ctx := datastore.Get()
 	if len(backupRequest.Username) > 0 {
@@ -185,7 +195,15 @@ func (dao *ControlPlaneDao) AsyncBackup(backupRequest model.BackupRequest, filen
 	dfslocker.Lock("backup")
 	inprogress.Reset()
 	dfslocker.Unlock()
-	go dao.Backup(backupRequest, filename)
+	target := backupRequest.Dirpath
+	if target == "" {
+		target = dao.backupsPath
+	}
+	id := dao.facade.StartOperation(ctx, facade.BackupOperation, target, func(ctx datastore.Context) error {
+		var backupFilename string
+		return dao.backup(ctx, backupRequest, &backupFilename)
+	})
+	log.WithField("operationid", id).Info("Started backup")
 	return
 }
 
@@ -195,6 +213,17 @@ func (dao *ControlPlaneDao) Restore(restoreRequest model.RestoreRequest, _ *int)
 	if len(restoreRequest.Username) > 0 {
 		ctx.SetUser(restoreRequest.Username)
 	}
+	return dao.restore(ctx, restoreRequest)
+}
+
+func (dao *ControlPlaneDao) restore(ctx datastore.Context, restoreRequest model.RestoreRequest) (err error) {
+	endOperation, err := dao.facade.BeginDFSOperation(ctx, facade.RestoreOperation, restoreRequest.Filename)
+	if err != nil {
+		log.WithError(err).Error("Could not queue restore")
+		return err
+	}
+	defer endOperation()
+
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("restore")
 	defer dfslocker.Unlock()
@@ -205,25 +234,16 @@ func (dao *ControlPlaneDao) Restore(restoreRequest model.RestoreRequest, _ *int)
 		}
 		inprogress.SetError(err)
 	}()
-	info, err := dfs.ExtractBackupInfo(restoreRequest.Filename)
-	if err != nil {
-		return err
-	}
-	fh, err := os.Open(restoreRequest.Filename)
-	if err != nil {
-		return err
-	}
-	defer fh.Close()
-	gz, err := gzip.NewReader(fh)
-	if err != nil {
-		return err
+	if restoreRequest.DeploymentID != "" {
+		err = dao.facade.RestoreFromFileAs(ctx, restoreRequest.Filename, restoreRequest.DeploymentID, restoreRequest.Name)
+	} else {
+		err = dao.facade.RestoreFromFile(ctx, restoreRequest.Filename)
 	}
-	defer gz.Close()
-	err = dao.facade.Restore(ctx, gz, info, restoreRequest.Filename)
 	return err
 }
 
-// AsyncRestore is the same as restore, but asynchronous.
+// AsyncRestore is the same as restore, but asynchronous.  The restore runs
+// as an operation of the master that can be followed and canceled.
 func (dao *ControlPlaneDao) AsyncRestore(restoreRequest model.RestoreRequest, unused *int) (err error) {
 	ctx := datastore.Get()
 	if len(restoreRequest.Username) > 0 {
@@ -233,7 +253,10 @@ func (dao *ControlPlaneDao) AsyncRestore(restoreRequest model.RestoreRequest, un
 	dfslocker.Lock("restore")
 	inprogress.Reset()
 	dfslocker.Unlock()
-	go dao.Restore(restoreRequest, unused)
+	id := dao.facade.StartOperation(ctx, facade.RestoreOperation, restoreRequest.Filename, func(ctx datastore.Context) error {
+		return dao.restore(ctx, restoreRequest)
+	})
+	log.WithField("operationid", id).Info("Started restore")
 	return
 }
 
@@ -263,13 +286,17 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 			// Set up the backup file
 			fullpath := filepath.Join(dirpath, fi.Name())
 			bf := model.BackupFile{
-				InProgress: running && fullpath == fp,
+				InProgress: running && (fullpath == fp || fullpath == fp+facade.PartialBackupSuffix),
 				FullPath:   fullpath,
 				Name:       fi.Name(),
 				Size:       fi.Size(),
 				Mode:       fi.Mode(),
 				ModTime:    fi.ModTime(),
 			}
+			// Partial backups are only listed while they are being written
+			if strings.HasSuffix(fullpath, facade.PartialBackupSuffix) && !bf.InProgress {
+				continue
+			}
 			// If it is not running, make sure the backup is legit
 			if !bf.InProgress {
 				isbackup := func(filename string) bool {
@@ -278,12 +305,12 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 						return false
 					}
 					defer fh.Close()
-					gz, err := gzip.NewReader(fh)
+					zr, _, err := volume.NewDecompressor(fh)
 					if err != nil {
 						return false
 					}
-					defer gz.Close()
-					_, err = dao.facade.BackupInfo(datastore.Get(), gz)
+					defer zr.Close()
+					_, err = dao.facade.BackupInfo(datastore.Get(), zr)
 					if err != nil {
 						return false
 					}
@@ -306,6 +333,13 @@ func (dao *ControlPlaneDao) BackupStatus(_ model.EntityRequest, status *string)
 	running, filename, op, err := inprogress.GetProgress()
 	if running {
 		*status = fmt.Sprintf("Performing a %s on %s", op, filename)
+		if progress, err := dao.facade.GetBackupProgress(datastore.Get()); err == nil && progress.Running && progress.Phase != "" {
+			if percent := progress.Percent(); percent >= 0 {
+				*status += fmt.Sprintf(" (%s, %.0f%%)", progress.Phase, percent)
+			} else {
+				*status += fmt.Sprintf(" (%s)", progress.Phase)
+			}
+		}
 	} else {
 		if err != nil {
 			*status = fmt.Sprintf("Completed a %s on %s with error: %s", op, filename, err)
@@ -321,6 +355,23 @@ func (dao *ControlPlaneDao) BackupStatus(_ model.EntityRequest, status *string)
 func (dao *ControlPlaneDao) Snapshot(req model.SnapshotRequest, snapshotID *string) (err error) {
 	ctx := datastore.Get()
 
+	// wait for the limits of the pool; the tenant of a container is not
+	// known until it is committed, so commits count against every pool
+	var endOperation func()
+	if req.ContainerID != "" {
+		endOperation, err = dao.facade.BeginDFSOperation(ctx, facade.DFSOperationSnapshot, req.ContainerID)
+	} else {
+		var tenantID string
+		if tenantID, err = dao.facade.GetTenantID(ctx, req.ServiceID); err != nil {
+			return
+		}
+		endOperation, err = dao.facade.BeginDFSOperation(ctx, facade.DFSOperationSnapshot, req.ServiceID, tenantID)
+	}
+	if err != nil {
+		return
+	}
+	defer endOperation()
+
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("snapshot")
@@ -343,6 +394,17 @@ func (dao *ControlPlaneDao) Snapshot(req model.SnapshotRequest, snapshotID *stri
 func (dao *ControlPlaneDao) Rollback(req model.RollbackRequest, _ *int) (err error) {
 	ctx := datastore.Get()
 
+	// wait for the limits of the pool
+	info, err := dao.facade.GetSnapshotInfo(ctx, req.SnapshotID)
+	if err != nil {
+		return
+	}
+	endOperation, err := dao.facade.BeginDFSOperation(ctx, facade.DFSOperationRollback, req.SnapshotID, info.TenantID)
+	if err != nil {
+		return
+	}
+	defer endOperation()
+
 	// synchronize the dfs
 	dfslocker := dao.facade.DFSLock(ctx)
 	dfslocker.Lock("rollback")
@@ -411,6 +473,9 @@ func (dao *ControlPlaneDao) ListSnapshots(serviceID string, snapshots *[]model.S
 				Invalid:     false,
 			}
 		}
+		if newInfo.Pinned, err = dao.facade.SnapshotPinned(ctx, snapshotID); err != nil {
+			return err
+		}
 		*snapshots = append(*snapshots, newInfo)
 	}
 	return
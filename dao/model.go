@@ -81,6 +81,7 @@ type SnapshotInfo struct {
 	Tags        []string
 	Created     time.Time
 	Invalid     bool
+	Pinned      bool // The snapshot or its image tag is pinned
 }
 
 func (s SnapshotInfo) String() string {
@@ -111,7 +112,8 @@ func (s *SnapshotInfo) Equals(s2 *SnapshotInfo) bool {
 		s.TenantID == s2.TenantID &&
 		s.Description == s2.Description &&
 		s.Created == s2.Created &&
-		s.Invalid == s2.Invalid
+		s.Invalid == s2.Invalid &&
+		s.Pinned == s2.Pinned
 }
 
 // ServiceInstanceRequest requests information about a service instance given
@@ -127,11 +129,14 @@ type BackupRequest struct {
 	Excludes             []string
 	Force                bool
 	Username             string
+	Compression          string // codec of the backup file; gzip if empty
 }
 
 type RestoreRequest struct {
-	Filename string
-	Username string
+	Filename     string
+	Username     string
+	DeploymentID string // restores alongside the existing applications if set
+	Name         string // renames the restored application
 }
 
 type BackupEstimate struct {
@@ -142,3 +147,78 @@ type BackupEstimate struct {
 	BackupPath      string
 	AllowBackup     bool
 }
+
+// BackupOperation is the state of a backup or restore that can be resumed
+// from its last checkpoint if it is interrupted
+type BackupOperation struct {
+	ID        string
+	Operation string
+	Filename  string
+	Completed []string
+	Offset    int64
+	StartedAt time.Time
+	UpdatedAt time.Time
+	Error     string
+}
+
+// DFSOperation is a snapshot, rollback, backup, restore or image push that
+// is running or is queued behind the concurrent operation limits of its pools.
+// Position is the place of a queued operation in the queue, starting at 1.
+type DFSOperation struct {
+	ID          string
+	Type        string
+	Target      string
+	PoolIDs     []string
+	User        string
+	State       string
+	Position    int
+	SubmittedAt time.Time
+	StartedAt   time.Time
+}
+
+// Operation is a backup, restore, template deploy, registry sync or rebalance
+// that the master runs or ran.  Progress is the percentage of the operation
+// that is done, or -1 if it is unknown.
+type Operation struct {
+	ID              string
+	Type            string
+	Target          string
+	User            string
+	State           string
+	Progress        float64
+	Message         string
+	Logs            []string
+	Error           string
+	CancelRequested bool
+	StartedAt       time.Time
+	EndedAt         time.Time
+}
+
+// BackupProgress is the progress of the last backup or restore.  Bytes is
+// the number of bytes of the backup file that were written or read, and
+// TotalBytes is the estimated size of a backup or the size of the file that
+// is restored.  ETA is zero until it can be estimated.
+type BackupProgress struct {
+	ID         string
+	Operation  string
+	Filename   string
+	Phase      string
+	Bytes      int64
+	TotalBytes int64
+	StartedAt  time.Time
+	ETA        time.Duration
+	Running    bool
+	Error      string
+}
+
+// Percent returns the percentage of the backup file that was written or
+// read, or -1 if its size is unknown.
+func (p BackupProgress) Percent() float64 {
+	if p.TotalBytes <= 0 {
+		return -1
+	}
+	if p.Bytes >= p.TotalBytes {
+		return 100
+	}
+	return float64(p.Bytes) * 100 / float64(p.TotalBytes)
+}
@@ -15,6 +15,8 @@ package datastore
 
 import (
 	"fmt"
+
+	"github.com/control-center/serviced/apierror"
 )
 
 // ErrNoSuchEntity is returned when no entity was found for a given key.
@@ -26,6 +28,11 @@ func (e ErrNoSuchEntity) Error() string {
 	return fmt.Sprintf("No such entity {kind:%s, id:%s}", e.Key.Kind(), e.Key.ID())
 }
 
+// ErrorCode implements apierror.Coder
+func (e ErrNoSuchEntity) ErrorCode() apierror.Code {
+	return apierror.NotFound
+}
+
 //IsErrNoSuchEntity check see if error param is of type ErrNoSuchEntity
 func IsErrNoSuchEntity(err error) bool {
 	switch err.(type) {
@@ -17,7 +17,6 @@ import (
 	"archive/tar"
 	"encoding/json"
 	"io"
-	"path"
 	"path/filepath"
 	"time"
 
@@ -31,10 +30,14 @@ import (
 const (
 	BackupMetadataFile   = ".BACKUPINFO"
 	SnapshotsMetadataDir = "SNAPSHOTS/"
+	ElasticMetadataDir   = "ELASTIC/"
 	DockerImagesFile     = "IMAGES.dkr"
 )
 
-// Backup writes all application data into an export stream
+// Backup writes all application data into an export stream.  If the writer
+// implements Checkpoint, sections that were completed by a previous attempt
+// are skipped and each finished section is checkpointed.  If the writer
+// implements LayerStore, the docker image layers are written to the store.
 func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 
 	backupLogger := plog.WithFields(log.Fields{
@@ -45,16 +48,26 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	progress := NewProgressCounter(300)
 	progress.Log = func() { plog.Infof("Written %v bytes to archive for backup", progress.Total) }
 
+	cp := getCheckpoint(w)
+	pr := getPhaseReporter(w)
 	tarOut := tar.NewWriter(io.MultiWriter(w, progress))
 
 	// write the backup metadata
-	if err := dfs.writeBackupMetadata(data, tarOut); err != nil {
-		plog.WithError(err).Error("Unable to write metadata for backup")
-		return err
+	pr.Phase(PhaseMetadata)
+	if !cp.Completed(BackupMetadataFile) {
+		if err := dfs.writeBackupMetadata(data, tarOut); err != nil {
+			plog.WithError(err).Error("Unable to write metadata for backup")
+			return err
+		}
+		if err := checkpointTar(cp, BackupMetadataFile, tarOut); err != nil {
+			plog.WithError(err).Error("Unable to checkpoint metadata for backup")
+			return err
+		}
 	}
 
 	var images []string
 
+	pr.Phase(PhaseImages)
 	baseImageLogger := backupLogger.WithField("total", len(data.BaseImages))
 	baseImageLogger.Info("Preparing docker images for backup")
 
@@ -86,6 +99,7 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	numberOfSnapshots := len(data.Snapshots)
 
 	backupLogger.WithField("total", numberOfSnapshots).Info("Preparing snapshots for backup")
+	pr.Phase(PhaseVolumes)
 
 	// export the snapshots
 	for i, snapshot := range data.Snapshots {
@@ -98,15 +112,9 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		tenantLogger := backupLogger.WithField("tenant", info.TenantID)
 		tenantLogger.Info("Preparing images for tenant")
 
-		r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
-		if err != nil {
-			tenantLogger.WithError(err).Error("Could not receive images metadata for tenant")
-			return err
-		}
-
 		var imgs []string
-		if err := importJSON(r, &imgs); err != nil {
-			tenantLogger.WithError(err).Error("Could not interpret images metadata for tenant")
+		if err := volume.ReadSnapshotMetadata(vol, info.Label, ImagesMetadata, &imgs); err != nil {
+			tenantLogger.WithError(err).Error("Could not read images metadata for tenant")
 			return err
 		}
 
@@ -135,10 +143,16 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 
 		snapshotLogger := backupLogger.WithField("snapshot", snapshot)
 
+		section := SnapshotSection(info.TenantID, info.Label)
+		if cp.Completed(section) {
+			snapshotLogger.Info("Snapshot was exported by a previous attempt, skipping")
+			continue
+		}
+
 		// dump the snapshot into the backup
-		prefix := path.Join(SnapshotsMetadataDir, info.TenantID, info.Label)
 		snapReader, errchan := dfs.snapshotSavePipe(vol, info.Label, data.SnapshotExcludes[snapshot])
-		if err := rewriteTar(prefix, tarOut, snapReader); err != nil {
+		sum := newSectionChecksum()
+		if err := rewriteTar(section, tarOut, snapReader, sum); err != nil {
 			// be a good citizen and clean up any running threads
 			<-errchan
 			snapshotLogger.WithError(err).Error("Could not write snapshot to backup")
@@ -146,6 +160,12 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		} else if err := <-errchan; err != nil {
 			snapshotLogger.WithError(err).Error("Could not export snapshot for backup")
 			return err
+		} else if err := writeChecksum(tarOut, section, sum); err != nil {
+			snapshotLogger.WithError(err).Error("Could not write checksum of snapshot to backup")
+			return err
+		} else if err := checkpointTar(cp, section, tarOut); err != nil {
+			snapshotLogger.WithError(err).Error("Could not checkpoint snapshot for backup")
+			return err
 		}
 
 		snapshotLogger.WithFields(log.Fields{
@@ -154,11 +174,49 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		}).Info("Exported snapshot to backup")
 	}
 
+	// export the elasticsearch snapshots
+	if len(data.ElasticSnapshots) > 0 {
+		pr.Phase(PhaseElastic)
+	}
+	for _, snapshot := range data.ElasticSnapshots {
+		elasticLogger := backupLogger.WithFields(log.Fields{
+			"cluster":  snapshot.Cluster,
+			"snapshot": snapshot.Name,
+		})
+
+		section := ElasticSection(snapshot.Cluster)
+		if cp.Completed(section) {
+			elasticLogger.Info("Elasticsearch snapshot was exported by a previous attempt, skipping")
+			continue
+		}
+
+		elasticReader, errchan := elasticSavePipe(snapshot.Path)
+		sum := newSectionChecksum()
+		if err := rewriteTar(section, tarOut, elasticReader, sum); err != nil {
+			<-errchan
+			elasticLogger.WithError(err).Error("Could not write elasticsearch snapshot to backup")
+			return err
+		} else if err := <-errchan; err != nil {
+			elasticLogger.WithError(err).Error("Could not export elasticsearch snapshot for backup")
+			return err
+		} else if err := writeChecksum(tarOut, section, sum); err != nil {
+			elasticLogger.WithError(err).Error("Could not write checksum of elasticsearch snapshot to backup")
+			return err
+		} else if err := checkpointTar(cp, section, tarOut); err != nil {
+			elasticLogger.WithError(err).Error("Could not checkpoint elasticsearch snapshot for backup")
+			return err
+		}
+
+		elasticLogger.Info("Exported elasticsearch snapshot to backup")
+	}
+
 	// dump the images from all the snapshots into the backup
+	pr.Phase(PhaseImages)
 	imageReader, errchan := dfs.dockerSavePipe(images...)
 	imageLogger := backupLogger.WithField("images", images)
 	imageLogger.Info("Starting export of images to backup")
-	if err := rewriteTar(DockerImagesFile, tarOut, imageReader); err != nil {
+	sum := newSectionChecksum()
+	if err := rewriteImageTar(tarOut, imageReader, getLayerStore(w), sum); err != nil {
 		// be a good citizen and clean up any running threads
 		<-errchan
 		imageLogger.WithError(err).Error("Could not write images to backup")
@@ -166,6 +224,9 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	} else if err := <-errchan; err != nil {
 		imageLogger.WithError(err).Error("Could not export images for backup")
 		return err
+	} else if err := writeChecksum(tarOut, DockerImagesFile, sum); err != nil {
+		imageLogger.WithError(err).Error("Could not write checksum of images to backup")
+		return err
 	}
 	tarOut.Close()
 
@@ -174,6 +235,15 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	return nil
 }
 
+// checkpointTar pads the last entry of the section and checkpoints it, so
+// that a resumed backup can append the next section to the archive.
+func checkpointTar(cp Checkpoint, section string, tarWriter *tar.Writer) error {
+	if err := tarWriter.Flush(); err != nil {
+		return err
+	}
+	return cp.Checkpoint(section)
+}
+
 // savePipe is a generic io pipe that returns the reader
 func savePipe(do func(w io.Writer) error) (*io.PipeReader, <-chan error) {
 	r, w := io.Pipe()
@@ -201,8 +271,9 @@ func (dfs *DistributedFilesystem) snapshotSavePipe(vol volume.Volume, label stri
 }
 
 // rewriteTar interprets an pipe reader as a tar reader and rewrites the
-// headers so they can get written to the outfile.
-func rewriteTar(prefix string, tarWriter *tar.Writer, r *io.PipeReader) error {
+// headers so they can get written to the outfile.  The entries are added to
+// the checksum of the section.
+func rewriteTar(prefix string, tarWriter *tar.Writer, r *io.PipeReader, sum *sectionChecksum) error {
 	defer r.Close()
 	tarReader := tar.NewReader(r)
 
@@ -219,9 +290,60 @@ func rewriteTar(prefix string, tarWriter *tar.Writer, r *io.PipeReader) error {
 		if err := tarWriter.WriteHeader(header); err != nil {
 			return err
 		}
-		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+		sum.Header(header)
+		if _, err := io.Copy(io.MultiWriter(tarWriter, sum), tarReader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rewriteImageTar writes the images section of a backup.  If a layer store
+// is set, the image layers are written to the store, and the archive only
+// holds their digests.
+func rewriteImageTar(tarWriter *tar.Writer, r *io.PipeReader, layers LayerStore, sum *sectionChecksum) error {
+	if layers == nil {
+		return rewriteTar(DockerImagesFile, tarWriter, r, sum)
+	}
+	defer r.Close()
+	tarReader := tar.NewReader(r)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		header.Name = filepath.Join(DockerImagesFile, header.Name)
+		if !isImageLayer(header) {
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			sum.Header(header)
+			if _, err := io.Copy(io.MultiWriter(tarWriter, sum), tarReader); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Replace the layer data with a reference to the store
+		digest, err := layers.PutLayer(tarReader)
+		if err != nil {
+			return err
+		}
+		header.Size = 0
+		if header.PAXRecords == nil {
+			header.PAXRecords = make(map[string]string)
+		}
+		header.PAXRecords[LayerDigestRecord] = digest
+		header.Format = tar.FormatPAX
+		if err := tarWriter.WriteHeader(header); err != nil {
 			return err
 		}
+		sum.Header(header)
 	}
 
 	return nil
@@ -17,13 +17,19 @@ import (
 	"archive/tar"
 	"encoding/json"
 	"io"
-	"os/exec"
+	"os"
 
+	"github.com/control-center/serviced/volume"
 	"github.com/zenoss/glog"
 )
 
 // BackupInfo provides metadata info about the contents of a backup
 func (dfs *DistributedFilesystem) BackupInfo(r io.Reader) (*BackupInfo, error) {
+	return readBackupInfo(r)
+}
+
+// readBackupInfo reads the backup metadata from an uncompressed backup
+func readBackupInfo(r io.Reader) (*BackupInfo, error) {
 	tarfile := tar.NewReader(r)
 	for {
 		header, err := tarfile.Next()
@@ -46,15 +52,26 @@ func (dfs *DistributedFilesystem) BackupInfo(r io.Reader) (*BackupInfo, error) {
 
 // ExtractBackupInfo extracts the backup metadata from a tarball on disk in as
 // cheaply a manner as possible. The serialized BackupInfo is stored at the
-// front of the tarball to facilitate this.
+// front of the tarball to facilitate this, so only the start of the file is
+// decompressed, with the codec that the file was compressed with.
 func ExtractBackupInfo(filename string) (*BackupInfo, error) {
-	var info BackupInfo
-	data, err := exec.Command("tar", "-O", "--occurrence", "-xzf", filename, BackupMetadataFile).CombinedOutput()
+	fh, err := os.Open(filename)
 	if err != nil {
 		return nil, ErrRestoreNoInfo
 	}
-	if err := json.Unmarshal(data, &info); err != nil {
+	defer fh.Close()
+	r, codec, err := volume.NewDecompressor(fh)
+	if err != nil {
 		return nil, ErrRestoreNoInfo
 	}
-	return &info, nil
+	defer r.Close()
+	info, err := readBackupInfo(r)
+	if err != nil {
+		return nil, ErrRestoreNoInfo
+	}
+	if info.Compression != "" && info.Compression != codec {
+		glog.Errorf("Backup %s is compressed with %s, but its metadata says %s", filename, codec, info.Compression)
+		return nil, ErrBackupCompressionMismatch
+	}
+	return info, nil
 }
@@ -19,11 +19,16 @@ import (
 	"archive/tar"
 	"bytes"
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"time"
 
 	. "github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/volume"
 	. "gopkg.in/check.v1"
 )
 
@@ -101,3 +106,47 @@ func (s *DFSTestSuite) TestBackupInfo_Success(c *C) {
 	c.Assert(actual, DeepEquals, &expected)
 	c.Assert(err, IsNil)
 }
+
+func (s *DFSTestSuite) TestExtractBackupInfo_Compression(c *C) {
+	codecs := []volume.Compression{volume.CompressionGzip, volume.CompressionNone}
+	if _, err := exec.LookPath(volume.ZstdCommand); err == nil {
+		codecs = append(codecs, volume.CompressionZstd)
+	}
+	tmpdir := c.MkDir()
+	for _, codec := range codecs {
+		expected := BackupInfo{BackupVersion: 1, Compression: codec}
+		marshal, err := json.Marshal(expected)
+		c.Assert(err, IsNil)
+		filename := filepath.Join(tmpdir, "backup"+codec.Extension())
+		fh, err := os.Create(filename)
+		c.Assert(err, IsNil)
+		w, err := volume.NewCompressor(fh, codec)
+		c.Assert(err, IsNil)
+		tarfile := tar.NewWriter(w)
+		err = tarfile.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(marshal))})
+		c.Assert(err, IsNil)
+		_, err = tarfile.Write(marshal)
+		c.Assert(err, IsNil)
+		c.Assert(tarfile.Close(), IsNil)
+		c.Assert(w.Close(), IsNil)
+		c.Assert(fh.Close(), IsNil)
+
+		actual, err := ExtractBackupInfo(filename)
+		c.Assert(err, IsNil)
+		c.Assert(actual, DeepEquals, &expected)
+	}
+
+	// the file must be compressed with the codec of its metadata
+	marshal, err := json.Marshal(BackupInfo{BackupVersion: 1, Compression: volume.CompressionZstd})
+	c.Assert(err, IsNil)
+	var buf bytes.Buffer
+	tarfile := tar.NewWriter(&buf)
+	c.Assert(tarfile.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(marshal))}), IsNil)
+	_, err = tarfile.Write(marshal)
+	c.Assert(err, IsNil)
+	c.Assert(tarfile.Close(), IsNil)
+	filename := filepath.Join(tmpdir, "mismatch.tar")
+	c.Assert(ioutil.WriteFile(filename, buf.Bytes(), 0644), IsNil)
+	_, err = ExtractBackupInfo(filename)
+	c.Assert(err, Equals, ErrBackupCompressionMismatch)
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import "path"
+
+// Checkpoint persists the progress of a backup or restore, so that an
+// interrupted operation can continue from where it left off instead of
+// starting over.  Backup checks whether the writer it is given implements
+// Checkpoint, and Restore checks the reader.
+type Checkpoint interface {
+	// Completed returns true if a previous attempt finished the section
+	Completed(section string) bool
+	// Checkpoint records that the section is finished.  During a backup, all
+	// of the data of the section has been written to the archive.
+	Checkpoint(section string) error
+}
+
+// SnapshotSection returns the name of the section of a backup that holds
+// the volume data of a tenant's snapshot.
+func SnapshotSection(tenantID, label string) string {
+	return path.Join(SnapshotsMetadataDir, tenantID, label)
+}
+
+// noCheckpoint is used by operations that cannot be resumed
+type noCheckpoint struct{}
+
+func (noCheckpoint) Completed(section string) bool   { return false }
+func (noCheckpoint) Checkpoint(section string) error { return nil }
+
+// getCheckpoint returns the checkpoint of the writer or reader of an
+// operation
+func getCheckpoint(v interface{}) Checkpoint {
+	if cp, ok := v.(Checkpoint); ok {
+		return cp
+	}
+	return noCheckpoint{}
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// checkpointBuffer is a backup writer that records its checkpoints
+type checkpointBuffer struct {
+	bytes.Buffer
+	completed map[string]bool
+	sections  []string
+	offsets   []int
+}
+
+func (b *checkpointBuffer) Completed(section string) bool {
+	return b.completed[section]
+}
+
+func (b *checkpointBuffer) Checkpoint(section string) error {
+	b.sections = append(b.sections, section)
+	b.offsets = append(b.offsets, b.Len())
+	return nil
+}
+
+// checkpointReader is a restore reader that records its checkpoints
+type checkpointReader struct {
+	io.Reader
+	completed map[string]bool
+	sections  []string
+}
+
+func (r *checkpointReader) Completed(section string) bool {
+	return r.completed[section]
+}
+
+func (r *checkpointReader) Checkpoint(section string) error {
+	r.sections = append(r.sections, section)
+	return nil
+}
+
+func (s *DFSTestSuite) setupCheckpointBackup(c *C, export bool) BackupInfo {
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	info := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(info, nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString("[]")}, nil)
+	writeFile := func(w io.Writer) {
+		tarwriter := tar.NewWriter(w)
+		data := []byte("here is some data")
+		tarwriter.WriteHeader(&tar.Header{Name: "afile", Size: int64(len(data))})
+		tarwriter.Write(data)
+		tarwriter.Close()
+	}
+	if export {
+		vol.On("Export", "LABEL", "", mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
+			writeFile(a.Get(2).(io.Writer))
+		})
+	}
+	s.docker.On("SaveImages", mock.Anything, mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
+		writeFile(a.Get(1).(io.Writer))
+	})
+	return BackupInfo{
+		Snapshots: []string{"BASE_LABEL"},
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (s *DFSTestSuite) readBackupNames(c *C, r io.Reader) []string {
+	names := []string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		c.Assert(err, IsNil)
+		names = append(names, hdr.Name)
+	}
+}
+
+func (s *DFSTestSuite) TestBackup_Checkpoint(c *C) {
+	backupInfo := s.setupCheckpointBackup(c, true)
+	buf := &checkpointBuffer{completed: map[string]bool{}}
+	err := s.dfs.Backup(backupInfo, buf)
+	c.Assert(err, IsNil)
+
+	// each section ends on a tar block boundary
+	c.Assert(buf.sections, DeepEquals, []string{BackupMetadataFile, SnapshotSection("BASE", "LABEL")})
+	for _, offset := range buf.offsets {
+		c.Assert(offset%512, Equals, 0)
+	}
+	c.Assert(s.readBackupNames(c, buf), DeepEquals, []string{
+		BackupMetadataFile,
+		path.Join(SnapshotSection("BASE", "LABEL"), "afile"),
+		ChecksumSection(SnapshotSection("BASE", "LABEL")),
+		path.Join(DockerImagesFile, "afile"),
+		ChecksumSection(DockerImagesFile),
+	})
+}
+
+func (s *DFSTestSuite) TestBackup_ResumeCheckpoint(c *C) {
+	backupInfo := s.setupCheckpointBackup(c, false)
+	buf := &checkpointBuffer{completed: map[string]bool{
+		BackupMetadataFile:               true,
+		SnapshotSection("BASE", "LABEL"): true,
+	}}
+	err := s.dfs.Backup(backupInfo, buf)
+	c.Assert(err, IsNil)
+
+	// only the images are appended
+	c.Assert(buf.sections, HasLen, 0)
+	c.Assert(s.readBackupNames(c, buf), DeepEquals, []string{
+		path.Join(DockerImagesFile, "afile"),
+		ChecksumSection(DockerImagesFile),
+	})
+}
+
+func (s *DFSTestSuite) TestRestore_ResumeCheckpoint(c *C) {
+	buf := bytes.NewBufferString("")
+	tw := tar.NewWriter(buf)
+	bytedata, err := json.Marshal(BackupInfo{BackupVersion: 1})
+	c.Assert(err, IsNil)
+	tw.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(bytedata))})
+	tw.Write(bytedata)
+	tw.WriteHeader(&tar.Header{Name: path.Join(SnapshotSection("BASE", "LABEL"), "dummy")})
+	tw.WriteHeader(&tar.Header{Name: path.Join(SnapshotSection("BASE", "LABEL2"), "dummy")})
+	tw.Close()
+
+	// LABEL was loaded by a previous attempt, so only LABEL2 is imported
+	vol := &volumemocks.Volume{}
+	s.disk.On("Create", "BASE").Return(vol, nil)
+	s.disk.On("Get", "BASE").Return(vol, nil)
+	vol.On("Import", "LABEL2", mock.Anything).Return(nil).Once()
+	vol.On("ReadMetadata", "LABEL2", ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString("[]")}, nil).Once()
+
+	r := &checkpointReader{
+		Reader:    buf,
+		completed: map[string]bool{SnapshotSection("BASE", "LABEL"): true},
+	}
+	err = s.dfs.Restore(r, 1)
+	c.Assert(err, IsNil)
+	c.Assert(r.sections, DeepEquals, []string{SnapshotSection("BASE", "LABEL2")})
+	vol.AssertExpectations(c)
+}
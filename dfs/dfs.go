@@ -18,14 +18,15 @@ import (
 	"time"
 
 	csync "github.com/control-center/serviced/commons/sync"
-	"github.com/control-center/serviced/coordinator/storage"
 	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicetemplate"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/volume"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
 var (
@@ -43,18 +44,30 @@ type DFS interface {
 	Destroy(tenantID string) error
 	// Download adds an image for an application into the registry
 	Download(image, tenantID string, upgrade bool) (registry string, err error)
+	// DownloadWithCredentials adds an image for an application into the
+	// registry, pulling it from its upstream registry with the credentials
+	DownloadWithCredentials(image, tenantID string, upgrade bool, creds *dockerclient.AuthConfiguration) (registry string, err error)
 	// Commit uploads a new image into the registry
 	Commit(ctrID string) (tenantID string, err error)
 	// Snapshot captures application data at a specific point in time
 	Snapshot(info SnapshotInfo, SnapshotSpacePercent int) (string, error)
 	// Rollback reverts application to a specific snapshot
 	Rollback(snapshotID string) error
+	// RollbackImage reverts an image in the registry to a specific snapshot
+	RollbackImage(snapshotID, image string) error
+	// RollbackImages returns the images of a snapshot whose latest tag is
+	// changed by a rollback to the snapshot
+	RollbackImages(snapshotID string) ([]ImageChange, error)
 	// Delete deletes an application's snapshot
 	Delete(snapshotID string) error
 	// List lists snapshots for a particular application
 	List(tenantID string) (snapshots []string, err error)
+	// Volumes lists the applications that have a volume
+	Volumes() []string
 	// Info provides detailed info for a particular snapshot
 	Info(snapshotID string) (*SnapshotInfo, error)
+	// Latest provides detailed info for the most recent snapshot of an application
+	Latest(tenantID string) (*SnapshotInfo, error)
 	// Backup saves and exports the current state of the system
 	Backup(info BackupInfo, w io.Writer) error
 	// Restore restores the system to the state of the backup
@@ -80,6 +93,16 @@ type DFS interface {
 	DfPath(path string, excludes []string) (uint64, error)
 	// Verifies that the mount points are correct. Returns nil if there are no problems.
 	VerifyTenantMounts(tenantID string) (err error)
+	// Resize sets the quota of an application's volume
+	Resize(tenantID string, size uint64) error
+	// Quota returns the size limit and usage of an application's volume
+	Quota(tenantID string) (*volume.Quota, error)
+	// SaveTemplate writes a copy of a service template to the dfs
+	SaveTemplate(template servicetemplate.ServiceTemplate) error
+	// DeleteTemplate removes the copy of a service template from the dfs
+	DeleteTemplate(templateID string) error
+	// Templates returns the copies of the service templates on the dfs
+	Templates() ([]servicetemplate.ServiceTemplate, error)
 }
 
 var _ = DFS(&DistributedFilesystem{})
@@ -93,6 +116,18 @@ type BackupInfo struct {
 	SnapshotExcludes map[string][]string
 	Timestamp        time.Time
 	BackupVersion    int
+	ElasticSnapshots []ElasticSnapshot
+	Compression      volume.Compression // codec of the backup file; gzip if empty
+}
+
+// ElasticSnapshot describes the snapshot of the indices of an elasticsearch
+// cluster that is included in a backup.  Path is the directory of the
+// snapshot repository on the host that took the backup.
+type ElasticSnapshot struct {
+	Cluster string
+	Name    string
+	Indices []string
+	Path    string
 }
 
 // SnapshotInfo provides meta info about a snapshot
@@ -109,9 +144,8 @@ type DistributedFilesystem struct {
 	index  registry.RegistryIndex
 	reg    registry.Registry
 	disk   volume.Driver
-	// FIXME: replace this with a NFS server, instead of restarting the
-	// daemon
-	net     storage.StorageDriver
+	// shares the tenant volumes with the delegates
+	net     network.Storage
 	timeout time.Duration
 	locker  *csync.TimedMutex
 	tmp     string // tmp directory where backups are temporarily spooled
@@ -124,7 +158,7 @@ type ImageInfo struct {
 }
 
 // NewDistributedFilesystem instantiates a new DistributedFilsystem object
-func NewDistributedFilesystem(docker docker.Docker, index registry.RegistryIndex, reg registry.Registry, disk volume.Driver, net storage.StorageDriver, timeout time.Duration) *DistributedFilesystem {
+func NewDistributedFilesystem(docker docker.Docker, index registry.RegistryIndex, reg registry.Registry, disk volume.Driver, net network.Storage, timeout time.Duration) *DistributedFilesystem {
 	return &DistributedFilesystem{
 		docker:  docker,
 		index:   index,
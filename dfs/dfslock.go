@@ -16,6 +16,8 @@ package dfs
 import (
 	"fmt"
 	"time"
+
+	"github.com/control-center/serviced/apierror"
 )
 
 type DFSLocker interface {
@@ -38,6 +40,11 @@ func (e ErrDfsBusy) Error() string {
 	return fmt.Sprintf("DFS is locked for %s, try again later.", e.blocker)
 }
 
+// ErrorCode implements apierror.Coder
+func (e ErrDfsBusy) ErrorCode() apierror.Code {
+	return apierror.Transient
+}
+
 func (dfs *DistributedFilesystem) Lock(opName string) {
 	dfs.locker.Lock(opName)
 }
@@ -25,6 +25,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/logging"
 	dockerclient "github.com/fsouza/go-dockerclient"
 )
@@ -69,6 +70,7 @@ type Docker interface {
 	LoadImage(reader io.Reader) error
 	PushImage(image string) error
 	PullImage(image string) error
+	PullImageWithCredentials(image string, creds dockerclient.AuthConfiguration) error
 	TagImage(oldImage, newImage string) error
 	RemoveImage(image string) error
 	FindContainer(ctr string) (*dockerclient.Container, error)
@@ -80,15 +82,30 @@ type Docker interface {
 }
 
 type DockerClient struct {
-	dc *dockerclient.Client
+	dc       *dockerclient.Client
+	mirrors  []Mirror
+	retries  int
+	interval time.Duration
 }
 
+// NewDockerClient returns a client to the local docker daemon that pulls
+// images through the configured registry mirrors.
 func NewDockerClient() (*DockerClient, error) {
 	dc, err := dockerclient.NewClient(DefaultSocket)
 	if err != nil {
 		return nil, err
 	}
-	return &DockerClient{dc}, nil
+	options := config.GetOptions()
+	mirrors, err := ParseMirrors(options.DockerRegistryMirrors)
+	if err != nil {
+		return nil, err
+	}
+	return &DockerClient{
+		dc:       dc,
+		mirrors:  mirrors,
+		retries:  options.DockerPullRetries,
+		interval: time.Second,
+	}, nil
 }
 
 func (d *DockerClient) FindImage(image string) (*dockerclient.Image, error) {
@@ -181,10 +198,39 @@ func (d *DockerClient) PushImage(image string) error {
 		Registry: imageID.Registry(),
 	}
 	creds := d.fetchCreds(imageID.Registry())
-	return d.dc.PushImage(opts, creds)
+	return retry(d.retries, d.interval, func() error {
+		return d.dc.PushImage(opts, creds)
+	})
 }
 
+// PullImage pulls an image through the mirror of its registry, if there is
+// one, and tags it with its original name.  The image is pulled from its
+// own registry if the mirror cannot provide it.
 func (d *DockerClient) PullImage(image string) error {
+	if mirrorImage, ok := MirrorImage(d.mirrors, image); ok {
+		err := d.pullImage(mirrorImage, nil)
+		if err == nil {
+			if err = d.TagImage(mirrorImage, image); err == nil {
+				return nil
+			}
+		}
+		plog.WithError(err).WithFields(log.Fields{
+			"image":  image,
+			"mirror": mirrorImage,
+		}).Warn("Could not pull image from registry mirror, pulling from upstream registry")
+	}
+	return d.pullImage(image, nil)
+}
+
+// PullImageWithCredentials pulls an image from its registry with the given
+// credentials instead of the credentials of the docker config file.  Registry
+// mirrors are skipped, since the credentials are only meant for the image's
+// own registry.
+func (d *DockerClient) PullImageWithCredentials(image string, creds dockerclient.AuthConfiguration) error {
+	return d.pullImage(image, &creds)
+}
+
+func (d *DockerClient) pullImage(image string, auth *dockerclient.AuthConfiguration) error {
 	imageID, err := commons.ParseImageID(image)
 	if err != nil {
 		return err
@@ -195,7 +241,12 @@ func (d *DockerClient) PullImage(image string) error {
 		Tag:        imageID.Tag,
 	}
 	creds := d.fetchCreds(imageID.Registry())
-	return d.dc.PullImage(opts, creds)
+	if auth != nil {
+		creds = *auth
+	}
+	return retry(d.retries, d.interval, func() error {
+		return d.dc.PullImage(opts, creds)
+	})
 }
 
 func (d *DockerClient) TagImage(oldImage, newImage string) error {
@@ -0,0 +1,143 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/commons"
+)
+
+// ErrBadMirror is returned when a registry mirror is not of the form
+// UPSTREAM=MIRROR[/PREFIX]
+var ErrBadMirror = errors.New("registry mirror must be of the form UPSTREAM=MIRROR[/PREFIX]")
+
+const (
+	dockerHub        = "docker.io"
+	maxRetryInterval = 30 * time.Second
+)
+
+// Mirror is a registry that serves the images of an upstream registry, such
+// as a pull through cache or an authenticated proxy.
+type Mirror struct {
+	Upstream string // host[:port] of the upstream registry
+	Host     string // host[:port] of the mirror
+	Prefix   string // path under which the mirror serves the upstream images
+}
+
+// ParseMirrors parses a list of UPSTREAM=MIRROR[/PREFIX] registry mirrors.
+func ParseMirrors(specs []string) ([]Mirror, error) {
+	mirrors := []Mirror{}
+	for _, spec := range specs {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			return nil, ErrBadMirror
+		}
+		mirror := strings.TrimSuffix(strings.TrimSpace(parts[1]), "/")
+		if i := strings.Index(mirror, "://"); i >= 0 {
+			mirror = mirror[i+3:]
+		}
+		host, prefix := mirror, ""
+		if i := strings.Index(mirror, "/"); i >= 0 {
+			host, prefix = mirror[:i], mirror[i+1:]
+		}
+		if host == "" {
+			return nil, ErrBadMirror
+		}
+		mirrors = append(mirrors, Mirror{
+			Upstream: normalizeRegistry(parts[0]),
+			Host:     host,
+			Prefix:   prefix,
+		})
+	}
+	return mirrors, nil
+}
+
+// normalizeRegistry returns the host[:port] of a registry, using docker.io
+// for all of the names of the docker hub.
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimSpace(registry)
+	if i := strings.Index(registry, "://"); i >= 0 {
+		registry = registry[i+3:]
+	}
+	registry = strings.TrimSuffix(registry, "/")
+	if i := strings.Index(registry, "/"); i >= 0 {
+		registry = registry[:i]
+	}
+	switch registry {
+	case "", "docker.io", "index.docker.io", "registry-1.docker.io":
+		return dockerHub
+	}
+	return registry
+}
+
+// MirrorImage returns the name of the image on the first mirror of its
+// registry, or false if its registry is not mirrored.
+func MirrorImage(mirrors []Mirror, image string) (string, bool) {
+	imageID, err := commons.ParseImageID(image)
+	if err != nil {
+		return "", false
+	}
+	upstream := normalizeRegistry(imageID.Registry())
+	for _, m := range mirrors {
+		if m.Upstream != upstream {
+			continue
+		}
+		path := []string{m.Host}
+		if m.Prefix != "" {
+			path = append(path, m.Prefix)
+		}
+		if imageID.User != "" {
+			path = append(path, imageID.User)
+		} else if upstream == dockerHub {
+			// official images live under library on the docker hub
+			path = append(path, "library")
+		}
+		path = append(path, imageID.Repo)
+		name := strings.Join(path, "/")
+		if imageID.Tag != "" {
+			name = fmt.Sprintf("%s:%s", name, imageID.Tag)
+		}
+		return name, true
+	}
+	return "", false
+}
+
+// retry calls f until it succeeds, it has been called attempts times, or it
+// returns an image not found error.  The wait between attempts doubles from
+// interval up to maxRetryInterval.
+func retry(attempts int, interval time.Duration, f func() error) (err error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(interval)
+			if interval *= 2; interval > maxRetryInterval {
+				interval = maxRetryInterval
+			}
+		}
+		if err = f(); err == nil || IsImageNotFound(err) {
+			return
+		}
+		plog.WithError(err).WithField("attempt", i+1).Debug("Docker registry request failed")
+	}
+	return
+}
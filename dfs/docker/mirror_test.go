@@ -0,0 +1,93 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package docker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMirrors(t *testing.T) {
+	mirrors, err := ParseMirrors([]string{
+		"docker.io=mirror.example.com:5000",
+		" https://index.docker.io/v1/ = https://mirror.example.com/hub/ ",
+		"quay.io=mirror.example.com:5000/quay",
+		"",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Mirror{
+		{Upstream: "docker.io", Host: "mirror.example.com:5000"},
+		{Upstream: "docker.io", Host: "mirror.example.com", Prefix: "hub"},
+		{Upstream: "quay.io", Host: "mirror.example.com:5000", Prefix: "quay"},
+	}, mirrors)
+
+	_, err = ParseMirrors([]string{"mirror.example.com"})
+	assert.Equal(t, ErrBadMirror, err)
+	_, err = ParseMirrors([]string{"docker.io="})
+	assert.Equal(t, ErrBadMirror, err)
+}
+
+func TestMirrorImage(t *testing.T) {
+	mirrors := []Mirror{
+		{Upstream: "docker.io", Host: "mirror.example.com:5000"},
+		{Upstream: "quay.io", Host: "mirror.example.com:5000", Prefix: "quay"},
+	}
+	for image, expected := range map[string]string{
+		"ubuntu:16.04":                      "mirror.example.com:5000/library/ubuntu:16.04",
+		"zenoss/core:5.3":                   "mirror.example.com:5000/zenoss/core:5.3",
+		"docker.io/zenoss/core":             "mirror.example.com:5000/zenoss/core",
+		"quay.io/coreos/etcd:v3.1":          "mirror.example.com:5000/quay/coreos/etcd:v3.1",
+		"localhost:5000/tenant/core:latest": "",
+	} {
+		actual, ok := MirrorImage(mirrors, image)
+		assert.Equal(t, expected != "", ok, image)
+		assert.Equal(t, expected, actual, image)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	calls := 0
+	err := retry(3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = retry(3, time.Millisecond, func() error {
+		calls++
+		return errors.New("connection reset")
+	})
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 3, calls)
+
+	// missing images are not retried
+	calls = 0
+	err = retry(3, time.Millisecond, func() error {
+		calls++
+		return dockerclient.ErrNoSuchImage
+	})
+	assert.Equal(t, dockerclient.ErrNoSuchImage, err)
+	assert.Equal(t, 1, calls)
+}
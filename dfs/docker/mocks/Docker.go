@@ -94,6 +94,18 @@ func (_m *Docker) PullImage(image string) error {
 
 	return r0
 }
+func (_m *Docker) PullImageWithCredentials(image string, creds dockerclient.AuthConfiguration) error {
+	ret := _m.Called(image, creds)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, dockerclient.AuthConfiguration) error); ok {
+		r0 = rf(image, creds)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *Docker) TagImage(oldImage string, newImage string) error {
 	ret := _m.Called(oldImage, newImage)
 
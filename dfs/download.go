@@ -31,6 +31,12 @@ var (
 // Download will download the image from upstream and save the image to the
 // registry.
 func (dfs *DistributedFilesystem) Download(image, tenantID string, upgrade bool) (string, error) {
+	return dfs.DownloadWithCredentials(image, tenantID, upgrade, nil)
+}
+
+// DownloadWithCredentials is Download for images in a private registry.  If
+// creds is nil, the credentials of the docker config file are used.
+func (dfs *DistributedFilesystem) DownloadWithCredentials(image, tenantID string, upgrade bool, creds *dockerclient.AuthConfiguration) (string, error) {
 	if !upgrade {
 		// Is this an image that has already been deployed?
 		if rImage, err := dfs.findImage(image, tenantID); err != nil {
@@ -45,7 +51,7 @@ func (dfs *DistributedFilesystem) Download(image, tenantID string, upgrade bool)
 		return "", err
 	}
 	// Pull the image (if it doesn't exist locally)
-	img, err := dfs.pullImage(image)
+	img, err := dfs.pullImage(image, creds)
 	if err != nil {
 		return "", err
 	}
@@ -123,12 +129,16 @@ func (dfs *DistributedFilesystem) parseRegistryImage(image, tenantID string) (st
 
 // pullImage pulls the image from the upstream if it doesn't already exist
 // locally
-func (dfs *DistributedFilesystem) pullImage(image string) (*dockerclient.Image, error) {
+func (dfs *DistributedFilesystem) pullImage(image string, creds *dockerclient.AuthConfiguration) (*dockerclient.Image, error) {
 	// Find (or download) the image
 	img, err := dfs.docker.FindImage(image)
 	if docker.IsImageNotFound(err) {
 		glog.Infof("Image %s not found locally, pulling", image)
-		if err := dfs.docker.PullImage(image); err != nil {
+		pull := dfs.docker.PullImage
+		if creds != nil {
+			pull = func(image string) error { return dfs.docker.PullImageWithCredentials(image, *creds) }
+		}
+		if err := pull(image); err != nil {
 			glog.Errorf("Could not pull image %s: %s", image, err)
 			return nil, err
 		} else if img, err = dfs.docker.FindImage(image); err != nil {
@@ -124,6 +124,18 @@ func (s *DFSTestSuite) TestDownload_NoImage(c *C) {
 	s.docker.AssertExpectations(c)
 }
 
+func (s *DFSTestSuite) TestDownloadWithCredentials(c *C) {
+	creds := &dockerclient.AuthConfiguration{Username: "user", Password: "pass"}
+	s.index.On("FindImage", "private.io/repo:tag").Return(nil, index.ErrImageNotFound)
+	s.docker.On("FindImage", "private.io/repo:tag").Return(nil, dockerclient.ErrNoSuchImage)
+	s.docker.On("PullImageWithCredentials", "private.io/repo:tag", *creds).Return(ErrTestNoPull)
+	img, err := s.dfs.DownloadWithCredentials("private.io/repo:tag", "tenant", false, creds)
+	c.Assert(img, Equals, "")
+	c.Assert(err, Equals, ErrTestNoPull)
+	s.docker.AssertNotCalled(c, "PullImage", "private.io/repo:tag")
+	s.docker.AssertExpectations(c)
+}
+
 func (s *DFSTestSuite) TestDownload_Upgrade(c *C) {
 	image := &dockerclient.Image{ID: "testimage1"}
 	s.docker.On("FindImage", "library/repo:tag").Return(image, nil)
@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/control-center/serviced/volume"
+)
+
+// ErrElasticSnapshotNotFound is returned when a backup does not include the
+// snapshot of an elasticsearch cluster.
+var ErrElasticSnapshotNotFound = errors.New("backup does not include the elasticsearch snapshot")
+
+// ElasticSection returns the name of the section of a backup that holds the
+// snapshot repository of an elasticsearch cluster.
+func ElasticSection(cluster string) string {
+	return path.Join(ElasticMetadataDir, cluster)
+}
+
+// elasticSavePipe returns a pipe that exports the directory of a snapshot
+// repository
+func elasticSavePipe(dirpath string) (*io.PipeReader, <-chan error) {
+	return savePipe(func(w io.Writer) error {
+		tarfile := tar.NewWriter(w)
+		if err := volume.ExportDirectory(tarfile, dirpath, "."); err != nil {
+			return err
+		}
+		return tarfile.Close()
+	})
+}
+
+// ExtractElasticSnapshots reads the uncompressed stream of a backup and
+// writes the snapshot repository of each cluster in dirpaths to its
+// directory.  The snapshots are not part of a restore, so that the
+// elasticsearch indices can be restored separately from the applications.
+func ExtractElasticSnapshots(r io.Reader, dirpaths map[string]string) error {
+	backuptar := tar.NewReader(r)
+	found := make(map[string]bool)
+	for {
+		hdr, err := backuptar.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			plog.WithError(err).Error("Could not read backup file")
+			return err
+		}
+
+		if strings.HasPrefix(hdr.Name, DockerImagesFile) {
+			// the images are always the last section of a backup
+			break
+		} else if !strings.HasPrefix(hdr.Name, ElasticMetadataDir) {
+			continue
+		}
+
+		parts := strings.SplitN(hdr.Name, "/", 3)
+		dirpath, ok := dirpaths[parts[1]]
+		if !ok {
+			continue
+		}
+		if !found[parts[1]] {
+			if err := os.MkdirAll(dirpath, 0777); err != nil {
+				return err
+			}
+			found[parts[1]] = true
+		}
+		if len(parts) < 3 || parts[2] == "" {
+			// this is the root of the repository
+			continue
+		}
+		hdr.Name = parts[2]
+		if err := volume.ImportArchiveHeader(hdr, backuptar, dirpath); err != nil {
+			plog.WithError(err).WithField("cluster", parts[1]).Error("Could not extract elasticsearch snapshot")
+			return err
+		}
+	}
+
+	for cluster := range dirpaths {
+		if !found[cluster] {
+			plog.WithField("cluster", cluster).Debug("Backup is missing the elasticsearch snapshot")
+			return ErrElasticSnapshotNotFound
+		}
+	}
+	return nil
+}
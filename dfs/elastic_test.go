@@ -0,0 +1,64 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package dfs_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/control-center/serviced/dfs"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestBackup_ElasticSnapshot(c *C) {
+	repo, err := ioutil.TempDir("", "dfs-elastic-repo")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(repo)
+	c.Assert(os.MkdirAll(filepath.Join(repo, "indices", "controlplane"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(repo, "index"), []byte("snapshots"), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(repo, "indices", "controlplane", "snapshot-backup"), []byte("shard data"), 0644), IsNil)
+
+	backupInfo := s.setupCheckpointBackup(c, true)
+	backupInfo.ElasticSnapshots = []ElasticSnapshot{
+		{Cluster: "serviced", Name: "backup", Indices: []string{"controlplane"}, Path: repo},
+	}
+	buf := &checkpointBuffer{completed: map[string]bool{}}
+	err = s.dfs.Backup(backupInfo, buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf.sections, DeepEquals, []string{BackupMetadataFile, SnapshotSection("BASE", "LABEL"), ElasticSection("serviced")})
+	data := buf.Bytes()
+
+	// the repository is extracted into a new directory
+	restored, err := ioutil.TempDir("", "dfs-elastic-restored")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(restored)
+	dirpath := filepath.Join(restored, "backup")
+	err = ExtractElasticSnapshots(bytes.NewReader(data), map[string]string{"serviced": dirpath})
+	c.Assert(err, IsNil)
+	content, err := ioutil.ReadFile(filepath.Join(dirpath, "index"))
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, "snapshots")
+	content, err = ioutil.ReadFile(filepath.Join(dirpath, "indices", "controlplane", "snapshot-backup"))
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, "shard data")
+
+	// the backup does not include the logstash indices
+	err = ExtractElasticSnapshots(bytes.NewReader(data), map[string]string{"logstash": filepath.Join(restored, "logs")})
+	c.Assert(err, Equals, ErrElasticSnapshotNotFound)
+}
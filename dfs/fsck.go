@@ -0,0 +1,50 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+// The kinds of problems that a consistency check of the dfs finds
+const (
+	// FsckOrphanedVolume is a volume whose application does not exist
+	FsckOrphanedVolume = "orphaned-volume"
+	// FsckMissingVolume is an application that does not have a volume
+	FsckMissingVolume = "missing-volume"
+	// FsckBadSnapshot is a snapshot whose metadata cannot be read
+	FsckBadSnapshot = "bad-snapshot"
+	// FsckMissingSnapshotImage is an image of a snapshot that is not in the
+	// registry, so rolling back to the snapshot fails
+	FsckMissingSnapshotImage = "missing-snapshot-image"
+	// FsckOrphanedImage is a registry image of an application that does not
+	// exist
+	FsckOrphanedImage = "orphaned-image"
+	// FsckStaleSnapshotImage is a registry image that is tagged for a
+	// snapshot that does not exist
+	FsckStaleSnapshotImage = "stale-snapshot-image"
+	// FsckMissingServiceImage is a service whose image is not in the registry
+	FsckMissingServiceImage = "missing-service-image"
+	// FsckStaleImagePin is a service that is pinned to a digest that is not
+	// tagged in the registry
+	FsckStaleImagePin = "stale-image-pin"
+)
+
+// FsckProblem is a dangling or missing reference between the volumes, the
+// snapshots, the registry and the images of the services of the dfs.
+type FsckProblem struct {
+	Kind     string
+	TenantID string
+	Subject  string // the volume, snapshot, image or service with the problem
+	Detail   string
+	Repair   string // the safe repair; empty if it must be repaired by hand
+	Repaired bool
+	Error    string `json:",omitempty"` // why the repair failed
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/volume"
+	"github.com/zenoss/glog"
+)
+
+// ErrNoSnapshots is returned when an application does not have any
+// snapshots.
+var ErrNoSnapshots = errors.New("application does not have any snapshots")
+
+// Latest returns detailed info for the most recent snapshot of an
+// application.
+func (dfs *DistributedFilesystem) Latest(tenantID string) (*SnapshotInfo, error) {
+	if !dfs.disk.Exists(tenantID) {
+		return nil, volume.ErrVolumeNotExists
+	}
+	vol, err := dfs.disk.Get(tenantID)
+	if err != nil {
+		glog.Errorf("Could not get volume for tenant %s: %s", tenantID, err)
+		return nil, err
+	}
+	snapshots, err := vol.Snapshots()
+	if err != nil {
+		glog.Errorf("Could not get snapshots for tenant %s: %s", tenantID, err)
+		return nil, err
+	}
+	var latest *volume.SnapshotInfo
+	for _, snapshotID := range snapshots {
+		info, err := vol.SnapshotInfo(snapshotID)
+		if err != nil {
+			glog.Errorf("Could not get info for snapshot %s: %s", snapshotID, err)
+			return nil, err
+		}
+		if latest == nil || info.Created.After(latest.Created) {
+			latest = info
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoSnapshots
+	}
+	return readSnapshotInfo(vol, latest)
+}
@@ -0,0 +1,109 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestLatest_NoVolume(c *C) {
+	s.disk.On("Exists", "Base").Return(false)
+	info, err := s.dfs.Latest("Base")
+	c.Assert(info, IsNil)
+	c.Assert(err, Equals, volume.ErrVolumeNotExists)
+}
+
+func (s *DFSTestSuite) TestLatest_NoSnapshots(c *C) {
+	vol := &volumemocks.Volume{}
+	s.disk.On("Exists", "Base").Return(true)
+	s.disk.On("Get", "Base").Return(vol, nil)
+	vol.On("Snapshots").Return([]string{}, nil)
+	info, err := s.dfs.Latest("Base")
+	c.Assert(info, IsNil)
+	c.Assert(err, Equals, ErrNoSnapshots)
+}
+
+func (s *DFSTestSuite) TestLatest_Success(c *C) {
+	vol := &volumemocks.Volume{}
+	s.disk.On("Exists", "Base").Return(true)
+	s.disk.On("Get", "Base").Return(vol, nil)
+	vol.On("Snapshots").Return([]string{"Base_OLD", "Base_NEW", "Base_MID"}, nil)
+	now := time.Now().UTC()
+	for i, label := range []string{"OLD", "MID", "NEW"} {
+		vol.On("SnapshotInfo", "Base_"+label).Return(&volume.SnapshotInfo{
+			Name:     "Base_" + label,
+			TenantID: "Base",
+			Label:    label,
+			Created:  now.Add(time.Duration(i) * time.Hour),
+		}, nil)
+	}
+	svcs := []service.Service{{ID: "Base", Name: "base"}}
+	svcsbuffer := bytes.NewBufferString("")
+	err := json.NewEncoder(svcsbuffer).Encode(svcs)
+	c.Assert(err, IsNil)
+	vol.On("ReadMetadata", "NEW", ServicesMetadataFile).Return(&NopCloser{svcsbuffer}, nil)
+	imgsbuffer := bytes.NewBufferString("")
+	err = json.NewEncoder(imgsbuffer).Encode([]string{"Base/repo:NEW"})
+	c.Assert(err, IsNil)
+	vol.On("ReadMetadata", "NEW", ImagesMetadataFile).Return(&NopCloser{imgsbuffer}, nil)
+	info, err := s.dfs.Latest("Base")
+	c.Assert(err, IsNil)
+	c.Assert(info.Name, Equals, "Base_NEW")
+	c.Assert(info.Images, DeepEquals, []string{"Base/repo:NEW"})
+	c.Assert(info.Services, HasLen, 1)
+	c.Assert(info.Services[0].ID, Equals, "Base")
+}
+
+func (s *DFSTestSuite) TestTemplates(c *C) {
+	root, err := ioutil.TempDir("", "dfs-templates-")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(root)
+	s.disk.On("Root").Return(root)
+
+	templates, err := s.dfs.Templates()
+	c.Assert(err, IsNil)
+	c.Assert(templates, HasLen, 0)
+
+	t1 := servicetemplate.ServiceTemplate{ID: "t1", Name: "first"}
+	t1.DatabaseVersion = 3
+	t2 := servicetemplate.ServiceTemplate{ID: "t2", Name: "second"}
+	c.Assert(s.dfs.SaveTemplate(t1), IsNil)
+	c.Assert(s.dfs.SaveTemplate(t2), IsNil)
+	templates, err = s.dfs.Templates()
+	c.Assert(err, IsNil)
+	c.Assert(templates, HasLen, 2)
+	c.Assert(templates[0].ID, Equals, "t1")
+	c.Assert(templates[0].DatabaseVersion, Equals, 0)
+	c.Assert(templates[1].Name, Equals, "second")
+
+	c.Assert(s.dfs.DeleteTemplate("t1"), IsNil)
+	c.Assert(s.dfs.DeleteTemplate("t1"), IsNil)
+	templates, err = s.dfs.Templates()
+	c.Assert(err, IsNil)
+	c.Assert(templates, HasLen, 1)
+	c.Assert(templates[0].ID, Equals, "t2")
+}
@@ -0,0 +1,272 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LayerDigestRecord is the PAX record of an entry in the images section of a
+// backup that refers to a layer in a LayerStore instead of holding its data.
+const LayerDigestRecord = "SERVICED.layerdigest"
+
+var (
+	// ErrInvalidLayerDigest is returned when a layer digest is malformed
+	ErrInvalidLayerDigest = errors.New("invalid layer digest")
+
+	// ErrLayerNotFound is returned when a layer is not in the store
+	ErrLayerNotFound = errors.New("layer not found in the image store")
+
+	// ErrNoLayerStore is returned when a backup refers to layers in a store,
+	// but the restore has no store to read them from
+	ErrNoLayerStore = errors.New("backup refers to image layers, but no image store is available")
+
+	layerDigestRegexp = regexp.MustCompile("^sha256:[a-f0-9]{64}$")
+)
+
+// LayerStore keeps the docker image layers of backups outside of their
+// archives, so that a layer that is included in many backups is only stored
+// once.  Backup checks whether the writer it is given implements LayerStore,
+// and Restore checks the reader.
+type LayerStore interface {
+	// PutLayer stores a layer and returns its digest
+	PutLayer(r io.Reader) (digest string, err error)
+	// GetLayer returns the data and the size of a layer
+	GetLayer(digest string) (io.ReadCloser, int64, error)
+}
+
+// getLayerStore returns the layer store of the writer or reader of an
+// operation, or nil if the layers are stored in the archive
+func getLayerStore(v interface{}) LayerStore {
+	if store, ok := v.(LayerStore); ok {
+		return store
+	}
+	return nil
+}
+
+// isImageLayer returns true if the entry of a docker save archive holds the
+// data of an image layer
+func isImageLayer(hdr *tar.Header) bool {
+	if hdr.Typeflag != tar.TypeReg {
+		return false
+	}
+	return path.Base(hdr.Name) == "layer.tar" || strings.HasPrefix(hdr.Name, "blobs/")
+}
+
+// layerRefs is the list of layers that a backup file references
+type layerRefs struct {
+	Backup string
+	Layers []string
+}
+
+// FileLayerStore is a content addressed LayerStore on the local filesystem.
+// Each backup file references the layers that it includes, and a layer is
+// removed by Prune when no backup file references it anymore.
+type FileLayerStore struct {
+	root string
+	mu   sync.Mutex
+}
+
+// NewFileLayerStore returns a layer store at the given path
+func NewFileLayerStore(root string) *FileLayerStore {
+	return &FileLayerStore{root: root}
+}
+
+func (s *FileLayerStore) layerPath(digest string) string {
+	return filepath.Join(s.root, "layers", strings.Replace(digest, ":", "/", 1))
+}
+
+func (s *FileLayerStore) refsPath(backup string) string {
+	return filepath.Join(s.root, "refs", filepath.Base(backup)+".json")
+}
+
+// PutLayer implements LayerStore
+func (s *FileLayerStore) PutLayer(r io.Reader) (string, error) {
+	dir := filepath.Join(s.root, "layers", "sha256")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	fh, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(fh.Name())
+	defer fh.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(fh, hash), r); err != nil {
+		return "", err
+	}
+	if err := fh.Sync(); err != nil {
+		return "", err
+	}
+	digest := "sha256:" + hex.EncodeToString(hash.Sum(nil))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(s.layerPath(digest)); err == nil {
+		// the layer is already in the store
+		return digest, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.Rename(fh.Name(), s.layerPath(digest)); err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// GetLayer implements LayerStore
+func (s *FileLayerStore) GetLayer(digest string) (io.ReadCloser, int64, error) {
+	if !layerDigestRegexp.MatchString(digest) {
+		return nil, 0, ErrInvalidLayerDigest
+	}
+	fh, err := os.Open(s.layerPath(digest))
+	if os.IsNotExist(err) {
+		return nil, 0, ErrLayerNotFound
+	} else if err != nil {
+		return nil, 0, err
+	}
+	fi, err := fh.Stat()
+	if err != nil {
+		fh.Close()
+		return nil, 0, err
+	}
+	return fh, fi.Size(), nil
+}
+
+// Reference records the layers that a backup file includes
+func (s *FileLayerStore) Reference(backup string, digests []string) error {
+	data, err := json.Marshal(layerRefs{Backup: backup, Layers: digests})
+	if err != nil {
+		return err
+	}
+	filename := s.refsPath(backup)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// References returns the number of backup files that reference each layer
+func (s *FileLayerStore) References() (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs, err := s.readRefs()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, r := range refs {
+		for _, digest := range r.Layers {
+			counts[digest]++
+		}
+	}
+	return counts, nil
+}
+
+// readRefs returns the layer references of all backup files
+func (s *FileLayerStore) readRefs() (map[string]layerRefs, error) {
+	refs := make(map[string]layerRefs)
+	fis, err := ioutil.ReadDir(filepath.Join(s.root, "refs"))
+	if os.IsNotExist(err) {
+		return refs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".json" {
+			continue
+		}
+		filename := filepath.Join(s.root, "refs", fi.Name())
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		var r layerRefs
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("could not read layer references %s: %s", filename, err)
+		}
+		refs[filename] = r
+	}
+	return refs, nil
+}
+
+// Prune drops the references of the backup files that no longer exist and
+// removes the layers that are not referenced by any backup file.  It returns
+// the number of layers removed and the number of bytes freed.
+func (s *FileLayerStore) Prune(exists func(backup string) bool) (int, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	refs, err := s.readRefs()
+	if err != nil {
+		return 0, 0, err
+	}
+	referenced := make(map[string]struct{})
+	for filename, r := range refs {
+		if !exists(r.Backup) {
+			plog.WithField("backup", r.Backup).Info("Dropping image layer references of deleted backup")
+			if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return 0, 0, err
+			}
+			continue
+		}
+		for _, digest := range r.Layers {
+			referenced[digest] = struct{}{}
+		}
+	}
+
+	dir := filepath.Join(s.root, "layers", "sha256")
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	}
+	count, freed := 0, int64(0)
+	for _, fi := range fis {
+		digest := "sha256:" + fi.Name()
+		if fi.IsDir() || !layerDigestRegexp.MatchString(digest) {
+			continue
+		}
+		if _, ok := referenced[digest]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, fi.Name())); err != nil && !os.IsNotExist(err) {
+			return count, freed, err
+		}
+		count++
+		freed += fi.Size()
+	}
+	return count, freed, nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// layerBuffer is a backup writer that stores image layers
+type layerBuffer struct {
+	bytes.Buffer
+	*FileLayerStore
+}
+
+// layerReader is a restore reader that reads image layers from a store
+type layerReader struct {
+	io.Reader
+	*FileLayerStore
+}
+
+func (s *DFSTestSuite) TestFileLayerStore_PutGetPrune(c *C) {
+	tmpdir := c.MkDir()
+	store := NewFileLayerStore(tmpdir)
+
+	digest1, err := store.PutLayer(strings.NewReader("layer one"))
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(digest1, "sha256:"), Equals, true)
+	again, err := store.PutLayer(strings.NewReader("layer one"))
+	c.Assert(err, IsNil)
+	c.Assert(again, Equals, digest1)
+	digest2, err := store.PutLayer(strings.NewReader("layer two"))
+	c.Assert(err, IsNil)
+	c.Assert(digest2, Not(Equals), digest1)
+
+	// each layer is only stored once
+	fis, err := ioutil.ReadDir(filepath.Join(tmpdir, "layers", "sha256"))
+	c.Assert(err, IsNil)
+	c.Assert(fis, HasLen, 2)
+
+	rc, size, err := store.GetLayer(digest1)
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "layer one")
+	c.Assert(size, Equals, int64(len("layer one")))
+
+	_, _, err = store.GetLayer("sha256:../../etc/passwd")
+	c.Assert(err, Equals, ErrInvalidLayerDigest)
+
+	c.Assert(store.Reference("/backups/backup-1.tgz", []string{digest1, digest2}), IsNil)
+	c.Assert(store.Reference("/backups/backup-2.tgz", []string{digest1}), IsNil)
+	refs, err := store.References()
+	c.Assert(err, IsNil)
+	c.Assert(refs, DeepEquals, map[string]int{digest1: 2, digest2: 1})
+
+	// backup-1 was deleted, so only the layer that backup-2 shares is kept
+	count, freed, err := store.Prune(func(backup string) bool {
+		return backup == "/backups/backup-2.tgz"
+	})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 1)
+	c.Assert(freed, Equals, int64(len("layer two")))
+	_, _, err = store.GetLayer(digest2)
+	c.Assert(err, Equals, ErrLayerNotFound)
+	_, _, err = store.GetLayer(digest1)
+	c.Assert(err, IsNil)
+	refs, err = store.References()
+	c.Assert(err, IsNil)
+	c.Assert(refs, DeepEquals, map[string]int{digest1: 1})
+}
+
+func (s *DFSTestSuite) TestBackup_LayerStore(c *C) {
+	store := NewFileLayerStore(c.MkDir())
+	s.docker.On("SaveImages", mock.Anything, mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
+		tw := tar.NewWriter(a.Get(1).(io.Writer))
+		for _, f := range []struct{ name, data string }{
+			{"abc/json", "{}"},
+			{"abc/layer.tar", "some layer data"},
+		} {
+			tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Size: int64(len(f.data))})
+			tw.Write([]byte(f.data))
+		}
+		tw.Close()
+	})
+	buf := &layerBuffer{FileLayerStore: store}
+	err := s.dfs.Backup(BackupInfo{Timestamp: time.Now().UTC()}, buf)
+	c.Assert(err, IsNil)
+
+	refs := map[string]string{}
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		if digest, ok := hdr.PAXRecords[LayerDigestRecord]; ok {
+			c.Assert(hdr.Size, Equals, int64(0))
+			refs[hdr.Name] = digest
+		}
+	}
+	c.Assert(refs, HasLen, 1)
+	digest, ok := refs[filepath.Join(DockerImagesFile, "abc/layer.tar")]
+	c.Assert(ok, Equals, true)
+	rc, _, err := store.GetLayer(digest)
+	c.Assert(err, IsNil)
+	data, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	c.Assert(string(data), Equals, "some layer data")
+}
+
+// writeLayerBackup returns a backup that refers to an image layer by digest
+func writeLayerBackup(c *C, digest string) *bytes.Buffer {
+	buf := bytes.NewBufferString("")
+	tw := tar.NewWriter(buf)
+	bytedata, err := json.Marshal(BackupInfo{BackupVersion: 1})
+	c.Assert(err, IsNil)
+	tw.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(bytedata))})
+	tw.Write(bytedata)
+	tw.WriteHeader(&tar.Header{
+		Name:       filepath.Join(DockerImagesFile, "abc/layer.tar"),
+		Typeflag:   tar.TypeReg,
+		PAXRecords: map[string]string{LayerDigestRecord: digest},
+		Format:     tar.FormatPAX,
+	})
+	tw.Close()
+	return buf
+}
+
+func (s *DFSTestSuite) TestRestore_LayerStore(c *C) {
+	store := NewFileLayerStore(c.MkDir())
+	digest, err := store.PutLayer(strings.NewReader("some layer data"))
+	c.Assert(err, IsNil)
+	buf := writeLayerBackup(c, digest)
+
+	loaded := map[string]string{}
+	s.docker.On("LoadImage", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		tr := tar.NewReader(a.Get(0).(io.Reader))
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				return
+			}
+			data, _ := ioutil.ReadAll(tr)
+			loaded[hdr.Name] = string(data)
+		}
+	})
+	err = s.dfs.Restore(&layerReader{Reader: buf, FileLayerStore: store}, 1)
+	c.Assert(err, IsNil)
+	c.Assert(loaded, DeepEquals, map[string]string{"abc/layer.tar": "some layer data"})
+}
+
+func (s *DFSTestSuite) TestRestore_NoLayerStore(c *C) {
+	buf := writeLayerBackup(c, "sha256:"+strings.Repeat("0", 64))
+	s.docker.On("LoadImage", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		ioutil.ReadAll(a.Get(0).(io.Reader))
+	})
+	err := s.dfs.Restore(buf, 1)
+	c.Assert(err, Equals, ErrNoLayerStore)
+}
@@ -13,7 +13,11 @@
 
 package dfs
 
-import "github.com/zenoss/glog"
+import (
+	"strings"
+
+	"github.com/zenoss/glog"
+)
 
 // List returns the list of snapshots for a given tenant.
 func (dfs *DistributedFilesystem) List(tenantID string) ([]string, error) {
@@ -29,3 +33,24 @@ func (dfs *DistributedFilesystem) List(tenantID string) ([]string, error) {
 	}
 	return snapshots, nil
 }
+
+// Volumes returns the names of the applications that have a volume.  The
+// volumes of snapshots are not included.
+func (dfs *DistributedFilesystem) Volumes() []string {
+	names := dfs.disk.List()
+	volumes := make(map[string]bool)
+	for _, name := range names {
+		volumes[name] = true
+	}
+	result := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if i := strings.Index(name, "_"); i > 0 && volumes[name[:i]] {
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
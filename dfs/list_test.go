@@ -45,3 +45,8 @@ func (s *DFSTestSuite) TestList_Success(c *C) {
 	c.Assert(snapshots, DeepEquals, snaps)
 	c.Assert(err, IsNil)
 }
+
+func (s *DFSTestSuite) TestVolumes(c *C) {
+	s.disk.On("List").Return([]string{"tenant", "tenant_label1", ".rsync", "other_tenant"})
+	c.Assert(s.dfs.Volumes(), DeepEquals, []string{"tenant", "other_tenant"})
+}
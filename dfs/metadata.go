@@ -0,0 +1,89 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
+)
+
+// Kinds of metadata that are stored with the snapshot of an application
+const (
+	ImagesMetadata   = "images"
+	ServicesMetadata = "services"
+)
+
+func init() {
+	if err := volume.RegisterMetadata(ImagesMetadata, volume.MetadataSchema{
+		Name:       ImagesMetadataFile,
+		Version:    1,
+		Migrations: []func(json.RawMessage) (json.RawMessage, error){unversionedMetadata},
+		Validate:   validateImagesMetadata,
+	}); err != nil {
+		panic(err)
+	}
+	if err := volume.RegisterMetadata(ServicesMetadata, volume.MetadataSchema{
+		Name:       ServicesMetadataFile,
+		Version:    1,
+		Migrations: []func(json.RawMessage) (json.RawMessage, error){unversionedMetadata},
+		Validate:   validateServicesMetadata,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+// unversionedMetadata migrates the metadata of snapshots that were taken
+// before metadata was versioned.  Its data did not change.
+func unversionedMetadata(data json.RawMessage) (json.RawMessage, error) {
+	return data, nil
+}
+
+// validateImagesMetadata checks the list of image paths of a snapshot
+func validateImagesMetadata(v interface{}) error {
+	images, ok := v.(*[]string)
+	if !ok {
+		if list, isList := v.([]string); isList {
+			images = &list
+		} else {
+			return errors.New("images metadata must be a list of image paths")
+		}
+	}
+	for _, image := range *images {
+		if image == "" {
+			return errors.New("images metadata has an empty image path")
+		}
+	}
+	return nil
+}
+
+// validateServicesMetadata checks the list of services of a snapshot
+func validateServicesMetadata(v interface{}) error {
+	svcs, ok := v.(*[]service.Service)
+	if !ok {
+		if list, isList := v.([]service.Service); isList {
+			svcs = &list
+		} else {
+			return errors.New("services metadata must be a list of services")
+		}
+	}
+	for _, svc := range *svcs {
+		if svc.ID == "" {
+			return errors.New("services metadata has a service without an id")
+		}
+	}
+	return nil
+}
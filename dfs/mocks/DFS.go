@@ -8,6 +8,9 @@ import "time"
 
 import (
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/volume"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
 type DFS struct {
@@ -91,6 +94,25 @@ func (_m *DFS) Download(image string, tenantID string, upgrade bool) (string, er
 
 	return r0, r1
 }
+func (_m *DFS) DownloadWithCredentials(image string, tenantID string, upgrade bool, creds *dockerclient.AuthConfiguration) (string, error) {
+	ret := _m.Called(image, tenantID, upgrade, creds)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, bool, *dockerclient.AuthConfiguration) string); ok {
+		r0 = rf(image, tenantID, upgrade, creds)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, bool, *dockerclient.AuthConfiguration) error); ok {
+		r1 = rf(image, tenantID, upgrade, creds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 
 // Commit provides a mock function with given fields: ctrID
 func (_m *DFS) Commit(ctrID string) (string, error) {
@@ -148,6 +170,43 @@ func (_m *DFS) Rollback(snapshotID string) error {
 	return r0
 }
 
+// RollbackImage provides a mock function with given fields: snapshotID, image
+func (_m *DFS) RollbackImage(snapshotID string, image string) error {
+	ret := _m.Called(snapshotID, image)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(snapshotID, image)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RollbackImages provides a mock function with given fields: snapshotID
+func (_m *DFS) RollbackImages(snapshotID string) ([]dfs.ImageChange, error) {
+	ret := _m.Called(snapshotID)
+
+	var r0 []dfs.ImageChange
+	if rf, ok := ret.Get(0).(func(string) []dfs.ImageChange); ok {
+		r0 = rf(snapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dfs.ImageChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(snapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: snapshotID
 func (_m *DFS) Delete(snapshotID string) error {
 	ret := _m.Called(snapshotID)
@@ -185,6 +244,22 @@ func (_m *DFS) List(tenantID string) ([]string, error) {
 	return r0, r1
 }
 
+// Volumes provides a mock function with given fields:
+func (_m *DFS) Volumes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // Info provides a mock function with given fields: snapshotID
 func (_m *DFS) Info(snapshotID string) (*dfs.SnapshotInfo, error) {
 	ret := _m.Called(snapshotID)
@@ -208,6 +283,29 @@ func (_m *DFS) Info(snapshotID string) (*dfs.SnapshotInfo, error) {
 	return r0, r1
 }
 
+// Latest provides a mock function with given fields: tenantID
+func (_m *DFS) Latest(tenantID string) (*dfs.SnapshotInfo, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 *dfs.SnapshotInfo
+	if rf, ok := ret.Get(0).(func(string) *dfs.SnapshotInfo); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.SnapshotInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Backup provides a mock function with given fields: info, w
 func (_m *DFS) Backup(info dfs.BackupInfo, w io.Writer) error {
 	ret := _m.Called(info, w)
@@ -424,3 +522,91 @@ func (_m *DFS) VerifyTenantMounts(tenantid string) error {
 
 	return r0
 }
+
+// Resize provides a mock function with given fields: tenantID, size
+func (_m *DFS) Resize(tenantID string, size uint64) error {
+	ret := _m.Called(tenantID, size)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, uint64) error); ok {
+		r0 = rf(tenantID, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Quota provides a mock function with given fields: tenantID
+func (_m *DFS) Quota(tenantID string) (*volume.Quota, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 *volume.Quota
+	if rf, ok := ret.Get(0).(func(string) *volume.Quota); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*volume.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTemplate provides a mock function with given fields: template
+func (_m *DFS) SaveTemplate(template servicetemplate.ServiceTemplate) error {
+	ret := _m.Called(template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplate) error); ok {
+		r0 = rf(template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTemplate provides a mock function with given fields: templateID
+func (_m *DFS) DeleteTemplate(templateID string) error {
+	ret := _m.Called(templateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Templates provides a mock function with given fields:
+func (_m *DFS) Templates() ([]servicetemplate.ServiceTemplate, error) {
+	ret := _m.Called()
+
+	var r0 []servicetemplate.ServiceTemplate
+	if rf, ok := ret.Get(0).(func() []servicetemplate.ServiceTemplate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicetemplate.ServiceTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package network defines the interface between the distributed filesystem
+// and the subsystem that shares tenant volumes with remote hosts.
+package network
+
+// Names of the supported network storage drivers
+const (
+	DriverNFS = "nfs"
+	DriverRBD = "rbd"
+)
+
+// Storage shares tenant volumes on the master with the delegates in the
+// cluster.
+type Storage interface {
+	// Driver returns the name of the network storage driver
+	Driver() string
+	// ExportPath will be something like "serviced_volumes_v2"
+	ExportPath() string
+	// ExportNamePath() will be something like "/exports/serviced_volumes_v2"
+	ExportNamePath() string
+	SetClients(clients ...string)
+	Sync() error
+	//TODO: remove Restart and Stop
+	Restart() error
+	Stop() error
+	// AddVolume notify storage driver that volume at path is available for sharing
+	AddVolume(path string) error
+	// RemoveVolume notify storage driver that volume at path is should not be shared
+	RemoveVolume(path string) error
+	// Get the backing device for a path
+	GetDevice(path string) (uint64, error)
+}
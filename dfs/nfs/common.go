@@ -33,6 +33,8 @@ var etcHostsAllow = "/etc/hosts.allow"
 var etcHostsDeny = "/etc/hosts.deny"
 var etcFstab = "/etc/fstab"
 var etcExports = "/etc/exports"
+var etcNFSConf = "/etc/nfs.conf"
+var etcIdmapdConf = "/etc/idmapd.conf"
 var exportsDir = "/exports"
 var lookPath = exec.LookPath
 var staleNFSCheck = utils.IsNFSMountStale
@@ -15,6 +15,7 @@ package nfs
 
 import (
 	"bufio"
+	"crypto/md5"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -29,6 +30,7 @@ import (
 	"syscall"
 
 	"github.com/control-center/serviced/commons/atomicfile"
+	"github.com/control-center/serviced/dfs/network"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
@@ -51,6 +53,7 @@ type Server struct {
 	volumes          map[string]int32
 	exported         map[string]struct{}
 	clientValidator  NfsClientValidator
+	v4Only           bool
 }
 
 var (
@@ -88,6 +91,17 @@ const etcExportsStartMarker = "\n# --- SERVICED EXPORTS BEGIN ---\n# --- Do not
 const etcExportsEndMarker = "\n# --- SERVICED EXPORTS END ---\n"
 const etcExportsRemoveComment = "# serviced removed: "
 
+const etcConfStartMarker = "\n# --- SERVICED NFS CONFIG BEGIN ---\n# --- Do not edit this section\n"
+const etcConfEndMarker = "\n# --- SERVICED NFS CONFIG END ---\n"
+
+// nfsConfV4Only disables the NFS versions that depend on rpcbind, statd and
+// lockd.
+const nfsConfV4Only = "[nfsd]\nudp=n\nvers2=n\nvers3=n\nvers4=y\n"
+
+// idmapdConfV4Only sets the idmapd domain so that NFSv4 owners are mapped
+// consistently when numeric ids are not in use.
+const idmapdConfV4Only = "[General]\nDomain = localdomain\n"
+
 func verifyExportsDir(path string) error {
 	stat, err := os.Stat(path)
 	if err != nil {
@@ -146,6 +160,11 @@ func NewServer(basePath, exportedName, network string) (*Server, error) {
 	}, nil
 }
 
+// Driver returns the name of the network storage driver
+func (c *Server) Driver() string {
+	return network.DriverNFS
+}
+
 // ExportPath returns the external export name; foo for nfs export /exports/foo
 func (c *Server) ExportPath() string {
 	return filepath.Join("/", c.exportedName)
@@ -202,6 +221,15 @@ func (c *Server) SetClientValidator(validator NfsClientValidator) {
 	c.clientValidator = validator
 }
 
+// SetV4Only configures the server to export only over NFSv4, so that it does
+// not depend on the rpcbind and statd daemons.  Volumes are exported with
+// stable fsids and the change takes effect on the next Sync or Restart.
+func (c *Server) SetV4Only(v4Only bool) {
+	c.Lock()
+	defer c.Unlock()
+	c.v4Only = v4Only
+}
+
 // SetClients replaces the existing clients with the new clients
 func (c *Server) SetClients(clients ...string) {
 	c.Lock()
@@ -247,7 +275,18 @@ func (c *Server) Sync() error {
 		glog.Errorf("error writing exports %v", err)
 		return err
 	}
-	if err := start(); err != nil {
+	changed, err := c.writeNFSConfig()
+	if err != nil {
+		glog.Errorf("error writing nfs config %v", err)
+		return err
+	}
+	if changed {
+		// the nfs versions are only read when the server starts
+		if err := restart(); err != nil {
+			glog.Errorf("error running restart %v", err)
+			return err
+		}
+	} else if err := start(); err != nil {
 		glog.Errorf("error running start %v", err)
 		return err
 	}
@@ -272,6 +311,9 @@ func (c *Server) Restart() error {
 	if err := c.writeExports(); err != nil {
 		return err
 	}
+	if _, err := c.writeNFSConfig(); err != nil {
+		return err
+	}
 	if err := restart(); err != nil {
 		return err
 	}
@@ -370,8 +412,8 @@ func (c *Server) writeExports() error {
 		if err := bindMount(volume, exported); err != nil {
 			return err
 		}
-		serviced_exports += fmt.Sprintf("%s\t%s(rw,fsid=%d,no_root_squash,insecure,no_subtree_check,async)\n",
-			exported, network, fsid)
+		serviced_exports += fmt.Sprintf("%s\t%s(rw,fsid=%s,no_root_squash,insecure,no_subtree_check,async)\n",
+			exported, network, c.fsid(volume, fsid))
 	}
 	c.exported = exports
 
@@ -415,6 +457,67 @@ func (c *Server) writeExports() error {
 	return atomicfile.WriteFile(etcExports, []byte(fileContents), 0664)
 }
 
+// fsid returns the fsid export option of a volume.  The fsid counter is
+// reassigned every time serviced starts, which is harmless for NFSv3 but
+// leaves NFSv4 clients with stale handles, so v4-only exports use a uuid
+// derived from the volume path.
+func (c *Server) fsid(volume string, idx int32) string {
+	if !c.v4Only {
+		return fmt.Sprintf("%d", idx)
+	}
+	sum := md5.Sum([]byte(volume))
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// writeNFSConfig adds or removes the serviced sections of the nfs and idmapd
+// configuration files, and reports whether either file changed.
+func (c *Server) writeNFSConfig() (bool, error) {
+	nfsConf, idmapdConf := "", ""
+	if c.v4Only {
+		nfsConf, idmapdConf = nfsConfV4Only, idmapdConfV4Only
+	}
+	changed, err := writeConfigSection(etcNFSConf, nfsConf)
+	if err != nil {
+		return false, err
+	}
+	idmapdChanged, err := writeConfigSection(etcIdmapdConf, idmapdConf)
+	if err != nil {
+		return false, err
+	}
+	return changed || idmapdChanged, nil
+}
+
+// writeConfigSection replaces the serviced section of a configuration file
+// with the given contents, removing the section if the contents are empty.
+// The file is only written if it changes.
+func writeConfigSection(path, section string) (bool, error) {
+	original, err := readFileIfExists(path)
+	if err != nil {
+		return false, err
+	}
+
+	preamble, postamble := original, ""
+	if index := strings.Index(original, etcConfStartMarker); index >= 0 {
+		preamble = original[:index]
+		remainder := original[index:]
+		if index := strings.Index(remainder, etcConfEndMarker); index >= 0 {
+			postamble = remainder[index+len(etcConfEndMarker):]
+		}
+	}
+	contents := preamble + postamble
+	if section != "" {
+		contents = preamble + etcConfStartMarker + section + etcConfEndMarker + postamble
+	}
+	if contents == original {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	glog.Infof("updating serviced section of %s", path)
+	return true, atomicfile.WriteFile(path, []byte(contents), 0644)
+}
+
 // umnount any bind mounts in exported directory if not exported
 func (c *Server) cleanupBindMounts() {
 	edir := filepath.Join(exportsDir, c.exportedName)
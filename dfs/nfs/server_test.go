@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
 )
@@ -290,3 +291,73 @@ func TestRemoveDeprecated(t *testing.T) {
 	assertPathExists(t, nonemptyTempDir)
 
 }
+
+func TestWriteNFSConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "nfs_unit_tests_")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	defer func(nfsConf, idmapdConf string) {
+		etcNFSConf = nfsConf
+		etcIdmapdConf = idmapdConf
+	}(etcNFSConf, etcIdmapdConf)
+	etcNFSConf = path.Join(tempDir, "etc/nfs.conf")
+	etcIdmapdConf = path.Join(tempDir, "etc/idmapd.conf")
+
+	s := Server{}
+
+	// nothing is written unless v4-only is enabled
+	if changed, err := s.writeNFSConfig(); err != nil || changed {
+		t.Fatalf("expected no change, got %v (%v)", changed, err)
+	}
+	assertPathDoesNotExist(t, etcNFSConf)
+	assertPathDoesNotExist(t, etcIdmapdConf)
+
+	preamble := "[general]\npipefs-directory=/run/rpc_pipefs\n"
+	if err := os.MkdirAll(path.Dir(etcNFSConf), 0755); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := ioutil.WriteFile(etcNFSConf, []byte(preamble), 0644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s.SetV4Only(true)
+	if changed, err := s.writeNFSConfig(); err != nil || !changed {
+		t.Fatalf("expected change, got %v (%v)", changed, err)
+	}
+	assertFileContents(t, etcNFSConf, []byte(preamble+etcConfStartMarker+nfsConfV4Only+etcConfEndMarker))
+	assertFileContents(t, etcIdmapdConf, []byte(etcConfStartMarker+idmapdConfV4Only+etcConfEndMarker))
+
+	// rewriting the same config is not a change
+	if changed, err := s.writeNFSConfig(); err != nil || changed {
+		t.Fatalf("expected no change, got %v (%v)", changed, err)
+	}
+
+	s.SetV4Only(false)
+	if changed, err := s.writeNFSConfig(); err != nil || !changed {
+		t.Fatalf("expected change, got %v (%v)", changed, err)
+	}
+	assertFileContents(t, etcNFSConf, []byte(preamble))
+	assertFileContents(t, etcIdmapdConf, []byte{})
+}
+
+func TestFsid(t *testing.T) {
+	s := Server{}
+	if fsid := s.fsid("/opt/serviced/var/volumes/abc", 3); fsid != "3" {
+		t.Errorf("expected fsid 3, got %s", fsid)
+	}
+
+	s.SetV4Only(true)
+	fsid := s.fsid("/opt/serviced/var/volumes/abc", 3)
+	if len(fsid) != 36 || strings.Count(fsid, "-") != 4 {
+		t.Errorf("expected a uuid fsid, got %s", fsid)
+	}
+	if other := s.fsid("/opt/serviced/var/volumes/abc", 4); other != fsid {
+		t.Errorf("expected a stable fsid, got %s and %s", fsid, other)
+	}
+	if other := s.fsid("/opt/serviced/var/volumes/def", 3); other == fsid {
+		t.Errorf("expected distinct fsids, got %s for both volumes", fsid)
+	}
+}
@@ -61,3 +61,32 @@ func NewProgressCounterWithClock(interval int, clock Clock) *ProgressCounter {
 		updateIntervalSeconds: interval,
 	}
 }
+
+// Phases of a backup or restore
+const (
+	PhaseMetadata = "metadata"
+	PhaseVolumes  = "volumes"
+	PhaseElastic  = "elastic"
+	PhaseImages   = "images"
+)
+
+// PhaseReporter is notified when a backup or restore moves on to its next
+// phase.  Backup checks whether the writer it is given implements
+// PhaseReporter, and Restore checks the reader.
+type PhaseReporter interface {
+	Phase(phase string)
+}
+
+// noPhaseReporter is used by operations that do not report their progress
+type noPhaseReporter struct{}
+
+func (noPhaseReporter) Phase(phase string) {}
+
+// getPhaseReporter returns the phase reporter of the writer or reader of an
+// operation
+func getPhaseReporter(v interface{}) PhaseReporter {
+	if pr, ok := v.(PhaseReporter); ok {
+		return pr
+	}
+	return noPhaseReporter{}
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"github.com/control-center/serviced/volume"
+	"github.com/zenoss/glog"
+)
+
+// Resize sets the quota of an application's volume.  Devicemapper volumes
+// are grown online; btrfs volumes are limited with a qgroup.
+func (dfs *DistributedFilesystem) Resize(tenantID string, size uint64) error {
+	if _, ok := dfs.disk.(volume.QuotaDriver); !ok {
+		return volume.ErrQuotaNotSupported
+	}
+	if !dfs.disk.Exists(tenantID) {
+		glog.Errorf("Could not find volume for tenant %s", tenantID)
+		return volume.ErrVolumeNotExists
+	}
+	if err := dfs.disk.Resize(tenantID, size); err != nil {
+		glog.Errorf("Could not resize volume for tenant %s: %s", tenantID, err)
+		return err
+	}
+	return nil
+}
+
+// Quota returns the size limit and usage of an application's volume
+func (dfs *DistributedFilesystem) Quota(tenantID string) (*volume.Quota, error) {
+	quota, err := volume.GetQuota(dfs.disk, tenantID)
+	if err != nil {
+		glog.Errorf("Could not get the quota of the volume for tenant %s: %s", tenantID, err)
+		return nil, err
+	}
+	return quota, nil
+}
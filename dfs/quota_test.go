@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/volume"
+	. "gopkg.in/check.v1"
+)
+
+func (s *DFSTestSuite) TestResize_NoVolume(c *C) {
+	s.disk.On("Exists", "Base").Return(false)
+	err := s.dfs.Resize("Base", 1024)
+	c.Assert(err, Equals, volume.ErrVolumeNotExists)
+	s.disk.AssertNotCalled(c, "Resize", "Base", uint64(1024))
+}
+
+func (s *DFSTestSuite) TestResize_Fail(c *C) {
+	errResize := errors.New("no shrinkage")
+	s.disk.On("Exists", "Base").Return(true)
+	s.disk.On("Resize", "Base", uint64(1024)).Return(errResize)
+	err := s.dfs.Resize("Base", 1024)
+	c.Assert(err, Equals, errResize)
+}
+
+func (s *DFSTestSuite) TestResize_Success(c *C) {
+	s.disk.On("Exists", "Base").Return(true)
+	s.disk.On("Resize", "Base", uint64(1024)).Return(nil)
+	err := s.dfs.Resize("Base", 1024)
+	c.Assert(err, IsNil)
+}
+
+func (s *DFSTestSuite) TestQuota(c *C) {
+	s.disk.On("Quota", "Base").Return(&volume.Quota{Limit: 1024, Used: 512}, nil)
+	quota, err := s.dfs.Quota("Base")
+	c.Assert(err, IsNil)
+	c.Assert(quota.Limit, Equals, uint64(1024))
+	c.Assert(quota.Used, Equals, uint64(512))
+}
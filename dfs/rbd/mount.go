@@ -0,0 +1,72 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Mount maps the image on this host and mounts it at the local path.  It is
+// a no-op if the path already has a mounted device.
+func Mount(spec, localPath string) error {
+	logger := plog.WithFields(logrus.Fields{
+		"image":     spec,
+		"localpath": localPath,
+	})
+
+	if err := Installed(); err != nil {
+		return err
+	}
+	if device, err := mountedDevice(localPath); err != nil {
+		logger.WithError(err).Debug("Could not look up mount point")
+		return err
+	} else if device != "" {
+		logger.WithField("device", device).Debug("Image is already mounted")
+		return nil
+	}
+
+	if err := os.MkdirAll(localPath, 0775); err != nil {
+		return err
+	}
+	device, err := mapImage(spec)
+	if err != nil {
+		logger.WithError(err).Debug("Could not map image")
+		return err
+	}
+	if err := mountDevice(device, localPath); err != nil {
+		logger.WithError(err).WithField("device", device).Debug("Could not mount image")
+		unmapImage(device)
+		return err
+	}
+	logger.WithField("device", device).Info("Mounted image")
+	return nil
+}
+
+// Unmount unmounts the image at the local path and unmaps its device
+func Unmount(localPath string) error {
+	device, err := mountedDevice(localPath)
+	if err != nil {
+		return err
+	}
+	if err := mp.Unmount(localPath); err != nil {
+		return err
+	}
+	if strings.HasPrefix(device, "/dev/rbd") {
+		return unmapImage(device)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rbd shares tenant volumes as Ceph RBD images that are mapped
+// directly on the delegates, instead of being exported over NFS.
+package rbd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	// ErrInvalidPool is returned when the ceph pool name is not valid
+	ErrInvalidPool = errors.New("rbd: invalid pool")
+	// ErrInvalidExportPath is returned when an export path does not name a
+	// pool and an image prefix
+	ErrInvalidExportPath = errors.New("rbd: invalid export path")
+	// ErrRBDUnsupported is returned when the rbd binary is not found
+	ErrRBDUnsupported = errors.New("rbd mapping not supported; install ceph-common")
+
+	plog = logging.PackageLogger()
+)
+
+const (
+	rbdBin      = "rbd"
+	mkfsBin     = "mkfs.xfs"
+	mountBin    = "mount"
+	imageFSType = "xfs"
+)
+
+var (
+	lookPath = exec.LookPath
+	mp       = utils.GetDefaultMountProc()
+)
+
+// exec.Cmd interface subset we need
+type command interface {
+	CombinedOutput() ([]byte, error)
+}
+
+// locally plugable command interface
+var commandFactory = func(name string, args ...string) command {
+	return exec.Command(name, args...)
+}
+
+func run(name string, args ...string) (string, error) {
+	output, err := commandFactory(name, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %s (%s)", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Installed returns nil if the rbd binary is available on this host
+func Installed() error {
+	if _, err := lookPath(rbdBin); err != nil {
+		return ErrRBDUnsupported
+	}
+	return nil
+}
+
+// ImageSpec returns the pool/image name of the image that backs a tenant
+// volume, given the export path published by the storage leader.
+func ImageSpec(exportPath, tenantID string) (string, error) {
+	parts := strings.Split(strings.Trim(exportPath, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || tenantID == "" {
+		return "", ErrInvalidExportPath
+	}
+	return path.Join(parts[0], parts[1]+"_"+tenantID), nil
+}
+
+// imageExists returns true if the image is in the ceph cluster
+func imageExists(spec string) (bool, error) {
+	output, err := commandFactory(rbdBin, "info", spec).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "No such file or directory") {
+			return false, nil
+		}
+		return false, fmt.Errorf("rbd info %s: %s (%s)", spec, err, strings.TrimSpace(string(output)))
+	}
+	return true, nil
+}
+
+// createImage creates an image of the given size in megabytes
+func createImage(spec string, sizeMB uint64) error {
+	_, err := run(rbdBin, "create", "--size", fmt.Sprintf("%d", sizeMB), "--image-feature", "layering", spec)
+	return err
+}
+
+// mapImage maps an image to a local block device and returns the device
+func mapImage(spec string) (string, error) {
+	device, err := run(rbdBin, "map", spec)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(device, "/dev/") {
+		return "", fmt.Errorf("rbd map %s: unexpected device %q", spec, device)
+	}
+	return device, nil
+}
+
+// unmapImage releases the local block device of a mapped image
+func unmapImage(device string) error {
+	_, err := run(rbdBin, "unmap", device)
+	return err
+}
+
+// formatDevice creates the filesystem of a new image
+func formatDevice(device string) error {
+	_, err := run(mkfsBin, "-q", device)
+	return err
+}
+
+// mountDevice mounts a mapped image at the local path
+func mountDevice(device, localPath string) error {
+	_, err := run(mountBin, "-t", imageFSType, device, localPath)
+	return err
+}
+
+// mountedDevice returns the device that is mounted at the local path
+func mountedDevice(localPath string) (string, error) {
+	mounts, err := mp.ListAll()
+	if err != nil {
+		return "", err
+	}
+	for _, mount := range mounts {
+		if mount.MountPoint == localPath {
+			return mount.Device, nil
+		}
+	}
+	return "", nil
+}
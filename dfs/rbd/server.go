@@ -0,0 +1,269 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dfs/network"
+)
+
+// DefaultImageSize is the size in megabytes of new tenant images
+const DefaultImageSize = 100 * 1024
+
+var exportsDir = "/exports"
+
+// Server manages the ceph rbd images that back the tenant volumes.  Each
+// volume is backed by an image named after its export path and tenant, which
+// the master mounts under the export path and the delegates map and mount
+// locally.
+type Server struct {
+	sync.Mutex
+	pool             string
+	exportedName     string
+	exportedNamePath string
+	imageSize        uint64
+	clients          map[string]struct{}
+	volumes          map[string]struct{}
+	mapped           map[string]string // image spec -> local device
+}
+
+var _ network.Storage = &Server{}
+
+// NewServer returns a rbd.Server that creates images of imageSize megabytes
+// in the given ceph pool.
+func NewServer(pool, exportedName string, imageSize uint64) (*Server, error) {
+	if pool == "" || strings.Contains(pool, "/") {
+		return nil, ErrInvalidPool
+	}
+	if len(exportedName) < 2 || strings.Contains(exportedName, "/") {
+		return nil, ErrInvalidExportPath
+	}
+	if imageSize == 0 {
+		imageSize = DefaultImageSize
+	}
+	exportedNamePath := filepath.Join(exportsDir, exportedName)
+	if err := os.MkdirAll(exportedNamePath, 0755); err != nil {
+		return nil, err
+	}
+	return &Server{
+		pool:             pool,
+		exportedName:     exportedName,
+		exportedNamePath: exportedNamePath,
+		imageSize:        imageSize,
+		clients:          make(map[string]struct{}),
+		volumes:          make(map[string]struct{}),
+		mapped:           make(map[string]string),
+	}, nil
+}
+
+// Driver returns the name of the network storage driver
+func (c *Server) Driver() string {
+	return network.DriverRBD
+}
+
+// ExportPath returns the pool and the image prefix; /rbd/foo for images
+// named foo_<tenant> in the rbd pool
+func (c *Server) ExportPath() string {
+	return filepath.Join("/", c.pool, c.exportedName)
+}
+
+// ExportNamePath returns the local directory where the images are mounted
+func (c *Server) ExportNamePath() string {
+	return c.exportedNamePath
+}
+
+// GetDevice returns the backing device for a given path.  Volume paths are
+// served from their image, so their device is the device of the image mount.
+func (c *Server) GetDevice(path string) (uint64, error) {
+	c.Lock()
+	if _, ok := c.volumes[path]; ok {
+		path = filepath.Join(c.exportedNamePath, filepath.Base(path))
+	}
+	c.Unlock()
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to get volume stats for %s: %s", path, err)
+	}
+	sysStat, ok := stat.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("Unable to convert volume stats to Stat_t for %s", path)
+	}
+	return sysStat.Dev, nil
+}
+
+// SetClients replaces the existing clients with the new clients.  Images are
+// mapped by the clients themselves, so they are only tracked for logging.
+func (c *Server) SetClients(clients ...string) {
+	c.Lock()
+	defer c.Unlock()
+	c.clients = make(map[string]struct{})
+	for _, client := range clients {
+		c.clients[client] = struct{}{}
+	}
+}
+
+// AddVolume sets the path of a volume that should be backed by an image
+func (c *Server) AddVolume(volumePath string) error {
+	c.Lock()
+	defer c.Unlock()
+	c.volumes[volumePath] = struct{}{}
+	return nil
+}
+
+// RemoveVolume sets the path of a volume that should no longer be shared
+func (c *Server) RemoveVolume(volumePath string) error {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.volumes, volumePath)
+	return nil
+}
+
+// Sync ensures that every volume has an image that is mounted under the
+// export path, and releases the images of removed volumes.
+func (c *Server) Sync() error {
+	c.Lock()
+	defer c.Unlock()
+
+	wanted := make(map[string]string)
+	for volumePath := range c.volumes {
+		tenantID := filepath.Base(volumePath)
+		spec, err := ImageSpec(c.ExportPath(), tenantID)
+		if err != nil {
+			return err
+		}
+		wanted[spec] = tenantID
+	}
+
+	for spec, device := range c.mapped {
+		if _, ok := wanted[spec]; !ok {
+			if err := c.release(spec, device); err != nil {
+				return err
+			}
+		}
+	}
+
+	for spec, tenantID := range wanted {
+		if err := c.attach(spec, tenantID); err != nil {
+			return err
+		}
+	}
+	plog.WithField("clients", len(c.clients)).Debug("Synced rbd images")
+	return nil
+}
+
+// attach creates, maps and mounts the image of a tenant on the master
+func (c *Server) attach(spec, tenantID string) error {
+	logger := plog.WithFields(logrus.Fields{
+		"image":    spec,
+		"tenantid": tenantID,
+	})
+
+	exists, err := imageExists(spec)
+	if err != nil {
+		logger.WithError(err).Error("Could not look up image")
+		return err
+	}
+	if !exists {
+		if err := createImage(spec, c.imageSize); err != nil {
+			logger.WithError(err).Error("Could not create image")
+			return err
+		}
+		logger.Info("Created image")
+	}
+
+	device, ok := c.mapped[spec]
+	if !ok {
+		if device, err = mapImage(spec); err != nil {
+			logger.WithError(err).Error("Could not map image")
+			return err
+		}
+		c.mapped[spec] = device
+	}
+
+	if !exists {
+		if err := formatDevice(device); err != nil {
+			logger.WithError(err).Error("Could not format image")
+			return err
+		}
+	}
+
+	mountPoint := filepath.Join(c.exportedNamePath, tenantID)
+	if current, err := mountedDevice(mountPoint); err != nil {
+		return err
+	} else if current == device {
+		return nil
+	}
+	if err := os.MkdirAll(mountPoint, 0755); err != nil {
+		return err
+	}
+	if err := mountDevice(device, mountPoint); err != nil {
+		logger.WithError(err).Error("Could not mount image")
+		return err
+	}
+	logger.WithField("device", device).Info("Mounted image")
+	return nil
+}
+
+// release unmounts and unmaps the image of a removed volume.  The image
+// itself is kept in the pool.
+func (c *Server) release(spec, device string) error {
+	logger := plog.WithFields(logrus.Fields{
+		"image":  spec,
+		"device": device,
+	})
+	if mounts, err := mp.ListAll(); err != nil {
+		return err
+	} else {
+		for _, mount := range mounts {
+			if mount.Device == device {
+				if err := mp.Unmount(mount.MountPoint); err != nil {
+					logger.WithError(err).Error("Could not unmount image")
+					return err
+				}
+			}
+		}
+	}
+	if err := unmapImage(device); err != nil {
+		logger.WithError(err).Error("Could not unmap image")
+		return err
+	}
+	delete(c.mapped, spec)
+	logger.Info("Released image")
+	return nil
+}
+
+// Restart remaps the images of all volumes
+func (c *Server) Restart() error {
+	return c.Sync()
+}
+
+// Stop releases all of the mapped images
+func (c *Server) Stop() error {
+	c.Lock()
+	defer c.Unlock()
+	for spec, device := range c.mapped {
+		if err := c.release(spec, device); err != nil {
+			return err
+		}
+	}
+	return nil
+}
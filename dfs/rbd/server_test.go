@@ -0,0 +1,197 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package rbd
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/control-center/serviced/utils"
+)
+
+type mockCommand struct {
+	output []byte
+	err    error
+}
+
+func (c *mockCommand) CombinedOutput() ([]byte, error) {
+	return c.output, c.err
+}
+
+// mockCeph records the commands that are run against a fake ceph cluster
+type mockCeph struct {
+	images   map[string]bool
+	mounts   []utils.MountInfo
+	commands []string
+}
+
+func (m *mockCeph) command(name string, args ...string) command {
+	m.commands = append(m.commands, name+" "+strings.Join(args, " "))
+	switch {
+	case name == rbdBin && args[0] == "info":
+		if !m.images[args[1]] {
+			return &mockCommand{[]byte("rbd: error opening image: (2) No such file or directory"), errors.New("exit status 2")}
+		}
+	case name == rbdBin && args[0] == "create":
+		m.images[args[len(args)-1]] = true
+	case name == rbdBin && args[0] == "map":
+		return &mockCommand{[]byte("/dev/rbd0\n"), nil}
+	case name == mountBin:
+		m.mounts = append(m.mounts, utils.MountInfo{Device: args[2], MountPoint: args[3]})
+	}
+	return &mockCommand{}
+}
+
+func (m *mockCeph) ListAll() ([]utils.MountInfo, error) {
+	return m.mounts, nil
+}
+
+func (m *mockCeph) IsMounted(path string) (bool, error) {
+	for _, mount := range m.mounts {
+		if mount.Device == path || mount.MountPoint == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockCeph) Unmount(path string) error {
+	for i, mount := range m.mounts {
+		if mount.MountPoint == path {
+			m.mounts = append(m.mounts[:i], m.mounts[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func setUpMockCeph(t *testing.T) (*mockCeph, func()) {
+	tmpDir, err := ioutil.TempDir("", "rbd")
+	if err != nil {
+		t.Fatalf("Could not create temp dir: %s", err)
+	}
+	ceph := &mockCeph{images: make(map[string]bool)}
+	oldExportsDir, oldCommandFactory, oldMp := exportsDir, commandFactory, mp
+	exportsDir, commandFactory, mp = tmpDir, ceph.command, ceph
+	return ceph, func() {
+		exportsDir, commandFactory, mp = oldExportsDir, oldCommandFactory, oldMp
+		os.RemoveAll(tmpDir)
+	}
+}
+
+func TestImageSpec(t *testing.T) {
+	if spec, err := ImageSpec("/rbd/serviced_volumes_v2", "tenant"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	} else if spec != "rbd/serviced_volumes_v2_tenant" {
+		t.Errorf("Unexpected image spec %s", spec)
+	}
+	for _, exportPath := range []string{"", "/rbd", "/rbd/a/b", "//foo"} {
+		if _, err := ImageSpec(exportPath, "tenant"); err != ErrInvalidExportPath {
+			t.Errorf("Expected ErrInvalidExportPath for %q, got %v", exportPath, err)
+		}
+	}
+}
+
+func TestNewServer_Invalid(t *testing.T) {
+	if _, err := NewServer("", "serviced_volumes_v2", 0); err != ErrInvalidPool {
+		t.Errorf("Expected ErrInvalidPool, got %v", err)
+	}
+	if _, err := NewServer("rbd", "a/b", 0); err != ErrInvalidExportPath {
+		t.Errorf("Expected ErrInvalidExportPath, got %v", err)
+	}
+}
+
+func TestServer_Sync(t *testing.T) {
+	ceph, tearDown := setUpMockCeph(t)
+	defer tearDown()
+
+	s, err := NewServer("rbd", "serviced_volumes_v2", 1024)
+	if err != nil {
+		t.Fatalf("Could not create server: %s", err)
+	}
+	if s.ExportPath() != "/rbd/serviced_volumes_v2" {
+		t.Errorf("Unexpected export path %s", s.ExportPath())
+	}
+
+	s.AddVolume("/opt/serviced/var/volumes/tenant")
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Could not sync: %s", err)
+	}
+	expected := []string{
+		"rbd info rbd/serviced_volumes_v2_tenant",
+		"rbd create --size 1024 --image-feature layering rbd/serviced_volumes_v2_tenant",
+		"rbd map rbd/serviced_volumes_v2_tenant",
+		"mkfs.xfs -q /dev/rbd0",
+		"mount -t xfs /dev/rbd0 " + filepath.Join(exportsDir, "serviced_volumes_v2", "tenant"),
+	}
+	if strings.Join(ceph.commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected commands:\n%s", strings.Join(ceph.commands, "\n"))
+	}
+
+	// syncing again does not remap or reformat the image
+	ceph.commands = nil
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Could not sync: %s", err)
+	}
+	if strings.Join(ceph.commands, "\n") != "rbd info rbd/serviced_volumes_v2_tenant" {
+		t.Errorf("Unexpected commands:\n%s", strings.Join(ceph.commands, "\n"))
+	}
+
+	// removed volumes are unmounted and unmapped, but the image is kept
+	ceph.commands = nil
+	s.RemoveVolume("/opt/serviced/var/volumes/tenant")
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Could not sync: %s", err)
+	}
+	if strings.Join(ceph.commands, "\n") != "rbd unmap /dev/rbd0" {
+		t.Errorf("Unexpected commands:\n%s", strings.Join(ceph.commands, "\n"))
+	}
+	if len(ceph.mounts) != 0 {
+		t.Errorf("Expected no mounts, got %v", ceph.mounts)
+	}
+	if !ceph.images["rbd/serviced_volumes_v2_tenant"] {
+		t.Errorf("Expected image to be kept")
+	}
+}
+
+func TestMount(t *testing.T) {
+	ceph, tearDown := setUpMockCeph(t)
+	defer tearDown()
+	oldLookPath := lookPath
+	lookPath = func(string) (string, error) { return "/usr/bin/rbd", nil }
+	defer func() { lookPath = oldLookPath }()
+
+	localPath := filepath.Join(exportsDir, "tenant")
+	if err := Mount("rbd/serviced_volumes_v2_tenant", localPath); err != nil {
+		t.Fatalf("Could not mount: %s", err)
+	}
+	if err := Mount("rbd/serviced_volumes_v2_tenant", localPath); err != nil {
+		t.Fatalf("Could not mount: %s", err)
+	}
+	if len(ceph.mounts) != 1 || ceph.mounts[0].Device != "/dev/rbd0" {
+		t.Errorf("Unexpected mounts %v", ceph.mounts)
+	}
+	if err := Unmount(localPath); err != nil {
+		t.Fatalf("Could not unmount: %s", err)
+	}
+	if last := ceph.commands[len(ceph.commands)-1]; last != "rbd unmap /dev/rbd0" {
+		t.Errorf("Expected image to be unmapped, got %s", last)
+	}
+}
@@ -50,6 +50,18 @@ func (_m *Registry) PullImage(cancel <-chan time.Time, image string) error {
 
 	return r0
 }
+func (_m *Registry) PullImageWithPolicy(cancel <-chan time.Time, image string, policy string) error {
+	ret := _m.Called(cancel, image, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(<-chan time.Time, string, string) error); ok {
+		r0 = rf(cancel, image, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *Registry) ImagePath(image string) (string, error) {
 	ret := _m.Called(image)
 
@@ -28,12 +28,21 @@ import (
 
 var (
 	ErrOpTimeout = errors.New("operation timed out")
+
+	// ErrUnknownPullPolicy is returned when an image pull policy is not
+	// supported
+	ErrUnknownPullPolicy = errors.New("unknown image pull policy")
+
+	// ErrImageNotPresent is returned when an image is not on the host and
+	// the pull policy does not allow it to be pulled
+	ErrImageNotPresent = errors.New("image is not present on the host")
 )
 
 // Registry performs specific docker actions based on the registry index
 type Registry interface {
 	SetConnection(conn client.Connection)
 	PullImage(cancel <-chan time.Time, image string) error
+	PullImageWithPolicy(cancel <-chan time.Time, image, policy string) error
 	ImagePath(image string) (string, error)
 	FindImage(rImg *registry.Image) (*dockerclient.Image, error)
 	GetAddress() (addr string)
@@ -61,6 +70,53 @@ func (l *RegistryListener) ImagePath(image string) (string, error) {
 	return path.Join(l.address, rImage.String()), nil
 }
 
+// PullImageWithPolicy makes an image available on the host according to the
+// pull policy.  IfNotPresent (or an empty policy) is PullImage.  Always pulls
+// the image from the docker registry before it is checked against the
+// registry index like PullImage.  Never does not pull; the image that is
+// tagged on the host is used, or else the indexed image if it is on the host.
+func (l *RegistryListener) PullImageWithPolicy(cancel <-chan time.Time, image, policy string) error {
+	switch policy {
+	case "", commons.PullIfNotPresent:
+		return l.PullImage(cancel, image)
+	case commons.PullAlways:
+		regaddr, err := l.ImagePath(image)
+		if err != nil {
+			return err
+		}
+		glog.Infof("Pulling image %s from the docker registry (policy %s)", regaddr, policy)
+		if err := l.docker.PullImage(regaddr); err != nil && !docker.IsImageNotFound(err) {
+			glog.Errorf("Could not pull %s: %s", regaddr, err)
+			return err
+		}
+		return l.PullImage(cancel, image)
+	case commons.PullNever:
+		regaddr, err := l.ImagePath(image)
+		if err != nil {
+			return err
+		}
+		if _, err := l.docker.FindImage(regaddr); err == nil {
+			glog.Infof("Using image %s on the host (policy %s)", regaddr, policy)
+			return nil
+		} else if !docker.IsImageNotFound(err) {
+			return err
+		}
+		uuid, err := GetImageUUID(l.conn, image)
+		if err != nil {
+			return err
+		}
+		if err := l.docker.TagImage(uuid, regaddr); docker.IsImageNotFound(err) {
+			glog.Errorf("Image %s is not on the host and the pull policy is %s", regaddr, policy)
+			return ErrImageNotPresent
+		} else if err != nil {
+			glog.Errorf("Could not update tag %s for image %s: %s", regaddr, uuid, err)
+			return err
+		}
+		return nil
+	}
+	return ErrUnknownPullPolicy
+}
+
 // PullImage waits for an image to be available on the docker registry so it
 // can be pulled (if it does not exist locally).
 func (l *RegistryListener) PullImage(cancel <-chan time.Time, image string) error {
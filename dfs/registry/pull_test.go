@@ -19,6 +19,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/domain/registry"
 	dockerclient "github.com/fsouza/go-dockerclient"
 	"github.com/stretchr/testify/mock"
@@ -228,3 +229,63 @@ func (s *RegistryListenerSuite) TestPull_ImageNotPushingTimeout(c *C) {
 	}
 	s.docker.AssertExpectations(c)
 }
+
+func (s *RegistryListenerSuite) TestPullWithPolicy_Always(c *C) {
+	rImage := &testImage{
+		Image: &registry.Image{
+			Library: "libraryname",
+			Repo:    "reponame",
+			Tag:     "tagname",
+			UUID:    "uuidvalue",
+		},
+	}
+	_ = rImage.Create(c, s.conn)
+	rAddress := rImage.Address(s.listener.address)
+	s.docker.On("PullImage", rAddress).Return(nil).Once()
+	s.docker.On("TagImage", rImage.Image.UUID, rAddress).Return(nil).Once()
+	err := s.listener.PullImageWithPolicy(time.After(15*time.Second), rAddress, commons.PullAlways)
+	c.Assert(err, IsNil)
+	s.docker.AssertExpectations(c)
+}
+
+func (s *RegistryListenerSuite) TestPullWithPolicy_NeverTaggedOnHost(c *C) {
+	rImage := &testImage{
+		Image: &registry.Image{
+			Library: "libraryname",
+			Repo:    "reponame",
+			Tag:     "tagname",
+			UUID:    "uuidvalue",
+		},
+	}
+	_ = rImage.Create(c, s.conn)
+	rAddress := rImage.Address(s.listener.address)
+	s.docker.On("FindImage", rAddress).Return(&dockerclient.Image{ID: "localvalue"}, nil).Once()
+	err := s.listener.PullImageWithPolicy(time.After(15*time.Second), rAddress, commons.PullNever)
+	c.Assert(err, IsNil)
+	s.docker.AssertExpectations(c)
+	s.docker.AssertNotCalled(c, "PullImage", rAddress)
+}
+
+func (s *RegistryListenerSuite) TestPullWithPolicy_NeverNotPresent(c *C) {
+	rImage := &testImage{
+		Image: &registry.Image{
+			Library: "libraryname",
+			Repo:    "reponame",
+			Tag:     "tagname",
+			UUID:    "uuidvalue",
+		},
+	}
+	_ = rImage.Create(c, s.conn)
+	rAddress := rImage.Address(s.listener.address)
+	s.docker.On("FindImage", rAddress).Return(nil, dockerclient.ErrNoSuchImage).Once()
+	s.docker.On("TagImage", rImage.Image.UUID, rAddress).Return(dockerclient.ErrNoSuchImage).Once()
+	err := s.listener.PullImageWithPolicy(time.After(15*time.Second), rAddress, commons.PullNever)
+	c.Assert(err, Equals, ErrImageNotPresent)
+	s.docker.AssertExpectations(c)
+	s.docker.AssertNotCalled(c, "PullImage", rAddress)
+}
+
+func (s *RegistryListenerSuite) TestPullWithPolicy_Unknown(c *C) {
+	err := s.listener.PullImageWithPolicy(time.After(15*time.Second), "someimage", "Sometimes")
+	c.Assert(err, Equals, ErrUnknownPullPolicy)
+}
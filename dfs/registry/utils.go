@@ -34,6 +34,27 @@ func GetRegistryImage(conn client.Connection, id string) (*registry.Image, error
 	return &node.Image, nil
 }
 
+// GetRegistryImages returns all of the registry images in the coordinator
+// index.
+func GetRegistryImages(conn client.Connection) ([]registry.Image, error) {
+	ids, err := conn.Children(zkregistrytags)
+	if err == client.ErrNoNode {
+		return []registry.Image{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	rImages := []registry.Image{}
+	for _, id := range ids {
+		var node RegistryImageNode
+		if err := conn.Get(path.Join(zkregistrytags, id), &node); err != nil {
+			glog.Errorf("Could not look up registry image %s: %s", id, err)
+			return nil, err
+		}
+		rImages = append(rImages, node.Image)
+	}
+	return rImages, nil
+}
+
 // SetRegistryImage inserts a registry image into the coordinator index.
 func SetRegistryImage(conn client.Connection, rImage registry.Image) error {
 	leaderpath := path.Join(zkregistryrepos, rImage.Library, rImage.Repo)
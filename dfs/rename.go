@@ -0,0 +1,51 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import "github.com/control-center/serviced/commons"
+
+// TenantRenamer restores the applications of a backup under new tenant ids,
+// so that they can be verified alongside the applications that were backed
+// up instead of replacing them.  Restore checks whether the reader it is
+// given implements TenantRenamer.
+type TenantRenamer interface {
+	// RenameTenant returns the id of the tenant that the snapshots and
+	// images of a tenant in the backup are restored to
+	RenameTenant(tenantID string) string
+}
+
+// noRename is used by restores that replace the applications
+type noRename struct{}
+
+func (noRename) RenameTenant(tenantID string) string { return tenantID }
+
+// getTenantRenamer returns the tenant renamer of the reader of a restore
+func getTenantRenamer(v interface{}) TenantRenamer {
+	if rn, ok := v.(TenantRenamer); ok {
+		return rn
+	}
+	return noRename{}
+}
+
+// tenantImage returns the name of an image in the library of a tenant.  The
+// images of a snapshot are always in the library of its tenant, so this maps
+// the images of a restored snapshot to the tenant it was restored to.
+func tenantImage(image, tenantID string) string {
+	imageID, err := commons.ParseImageID(image)
+	if err != nil || imageID.User == "" {
+		return image
+	}
+	imageID.User = tenantID
+	return imageID.String()
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// renamedReader is a restore reader that renames the tenants of the backup
+type renamedReader struct {
+	io.Reader
+	tenants map[string]string
+}
+
+func (r *renamedReader) RenameTenant(tenantID string) string {
+	if newTenantID, ok := r.tenants[tenantID]; ok {
+		return newTenantID
+	}
+	return tenantID
+}
+
+func (s *DFSTestSuite) TestRestore_RenameTenant(c *C) {
+	buf := bytes.NewBufferString("")
+	tarfile := tar.NewWriter(buf)
+	backupInfo := BackupInfo{
+		Snapshots:     []string{"BASE_LABEL"},
+		Timestamp:     time.Now().UTC(),
+		BackupVersion: 1,
+	}
+	s.writeBackupInfo(c, tarfile, backupInfo)
+	err := tarfile.WriteHeader(&tar.Header{Name: path.Join(SnapshotsMetadataDir, "BASE", "LABEL", "dummy"), Size: 0})
+	c.Assert(err, IsNil)
+	tarfile.Close()
+
+	// the snapshot is imported into the volume of the renamed tenant
+	vol := &volumemocks.Volume{}
+	s.disk.On("Create", "CLONE").Return(&volumemocks.Volume{}, volume.ErrVolumeExists)
+	s.disk.On("Get", "CLONE").Return(vol, nil)
+	vol.On("Import", "LABEL", mock.Anything).Return(nil)
+	imgbuffer := bytes.NewBufferString("")
+	err = json.NewEncoder(imgbuffer).Encode([]string{"test:5000/BASE/repo:LABEL"})
+	c.Assert(err, IsNil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{imgbuffer}, nil)
+
+	// the images are added to the library of the renamed tenant
+	s.docker.On("FindImage", "test:5000/BASE/repo:LABEL").Return(&dockerclient.Image{ID: "someimageid"}, nil)
+	s.docker.On("GetImageHash", "someimageid").Return("hashvalue", nil)
+	s.index.On("PushImage", "test:5000/CLONE/repo:LABEL", "someimageid", "hashvalue").Return(nil)
+
+	r := &renamedReader{Reader: buf, tenants: map[string]string{"BASE": "CLONE"}}
+	err = s.dfs.Restore(r, backupInfo.BackupVersion)
+	c.Assert(err, IsNil)
+	s.disk.AssertNotCalled(c, "Create", "BASE")
+	vol.AssertExpectations(c)
+	s.index.AssertExpectations(c)
+}
@@ -17,7 +17,6 @@ import (
 	"archive/tar"
 	"errors"
 	"io"
-	"path"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
@@ -27,9 +26,14 @@ import (
 var (
 	ErrRestoreNoInfo        = errors.New("backup is missing metadata")
 	ErrInvalidBackupVersion = errors.New("backup has an invalid version")
+	// ErrBackupCompressionMismatch is returned when a backup file is not
+	// compressed with the codec that its metadata records
+	ErrBackupCompressionMismatch = errors.New("backup is not compressed with the codec of its metadata")
 )
 
-// Restore restores application data from a backup.
+// Restore restores application data from a backup.  If the reader
+// implements TenantRenamer, the snapshots and images of each tenant are
+// restored to the tenant it is renamed to.
 func (dfs *DistributedFilesystem) Restore(r io.Reader, version int) error {
 	plog.WithField("version", version).Info("Detected backup version")
 	switch version {
@@ -44,6 +48,7 @@ func (dfs *DistributedFilesystem) Restore(r io.Reader, version int) error {
 
 // restoreV0 restores a pre-1.1.3 backup
 func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
+	rn := getTenantRenamer(r)
 	backuptar := tar.NewReader(r)
 
 	// keep track of the snapshots that have been imported
@@ -71,7 +76,7 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 			}
 
 			// restore the snapshot
-			tenant, label := parts[1], parts[2]
+			tenant, label := rn.RenameTenant(parts[1]), parts[2]
 			if err := dfs.restoreSnapshot(tenant, label, backuptar); err != nil {
 				plog.WithError(err).WithFields(log.Fields{
 					"label":    label,
@@ -110,8 +115,15 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 // data is contained in a single tar.  It does this by partitioning the tar
 // stream into multiple other streams: One for Docker images, which used to be
 // and independent tar file within the tar stream (but is now included inline),
-// and one for each DFS snapshot being restored.
+// and one for each DFS snapshot being restored.  If the reader implements
+// Checkpoint, the images and snapshots that were loaded by a previous attempt
+// are skipped.  Image layers that the backup refers to by digest are read
+// from the reader's LayerStore.
 func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
+	cp := getCheckpoint(r)
+	layers := getLayerStore(r)
+	rn := getTenantRenamer(r)
+	pr := getPhaseReporter(r)
 	backuptar := tar.NewReader(r)
 
 	// Keep track of all the data pipes
@@ -161,14 +173,19 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 				// to the snapshot stream.
 				continue
 			}
-			tenant, label := parts[1], parts[2]
+			tenant, label := rn.RenameTenant(parts[1]), parts[2]
+			pr.Phase(PhaseVolumes)
 
 			tenantLogger := plog.WithFields(log.Fields{
 				"label":  label,
 				"tenant": tenant,
 			})
 
-			id := path.Join(SnapshotsMetadataDir, tenant, label)
+			id := SnapshotSection(tenant, label)
+			if cp.Completed(id) {
+				// loaded by a previous attempt
+				continue
+			}
 			// Find or create the pipe that's got a restoreSnapshot for this
 			// volume reading from the other end
 			s, ok := streamMap[id]
@@ -198,6 +215,11 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 				dataError = err
 				return err
 			}
+		case strings.HasPrefix(hdr.Name, ChecksumsDir):
+			// Checksums are only read by VerifyBackup
+		case strings.HasPrefix(hdr.Name, ElasticMetadataDir):
+			// Elasticsearch snapshots are restored separately by
+			// ExtractElasticSnapshots
 		case strings.HasPrefix(hdr.Name, DockerImagesFile):
 			// Find or create the pipe that's got a LoadImages for this path
 			// reading from the other end.
@@ -207,6 +229,11 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			}
 
 			id := parts[0]
+			if cp.Completed(DockerImagesFile) {
+				// loaded by a previous attempt
+				continue
+			}
+			pr.Phase(PhaseImages)
 			s, ok := streamMap[id]
 			if !ok {
 				plog.Info("Loading docker images from backup")
@@ -216,7 +243,15 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 				streamMap[DockerImagesFile] = s
 			}
 			hdr.Name = parts[1]
-			if err := s.tarwriter.WriteHeader(hdr); err != nil {
+			if digest, ok := hdr.PAXRecords[LayerDigestRecord]; ok {
+				// reassemble the layer from the store
+				if err := copyLayer(s.tarwriter, hdr, digest, layers); err != nil {
+					plog.WithError(err).WithField("header", hdr.Name).WithField("digest", digest).
+						Error("Could not write image layer from store")
+					dataError = err
+					return err
+				}
+			} else if err := s.tarwriter.WriteHeader(hdr); err != nil {
 				plog.WithError(err).WithField("header", hdr.Name).
 					Error("Could not write image header")
 				dataError = err
@@ -235,6 +270,7 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 	// make sure the image load finishes first
 	s, ok := streamMap[DockerImagesFile]
 	if ok {
+		pr.Phase(PhaseImages)
 		delete(streamMap, DockerImagesFile)
 		s.tarwriter.Close()
 		s.writer.Close()
@@ -242,12 +278,21 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			plog.WithError(err).Error("Could not load docker images from backup")
 			dataError = err
 			return err
+		} else if err := cp.Checkpoint(DockerImagesFile); err != nil {
+			plog.WithError(err).Error("Could not checkpoint docker images from backup")
+			dataError = err
+			return err
 		}
+	} else if cp.Completed(DockerImagesFile) {
+		plog.Info("Docker images were loaded by a previous attempt")
 	} else {
 		plog.Warn("Backup missing docker image data")
 	}
 
 	// load the snapshots and update the images in the registry
+	if len(streamMap) > 0 {
+		pr.Phase(PhaseVolumes)
+	}
 	for id, s := range streamMap {
 		delete(streamMap, id)
 		s.tarwriter.Close()
@@ -272,11 +317,36 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 			dataError = err
 			continue
 		}
+
+		if err := cp.Checkpoint(id); err != nil {
+			plog.WithError(err).WithField("id", id).Error("Could not checkpoint snapshot")
+			dataError = err
+		}
 	}
 
 	return dataError
 }
 
+// copyLayer writes an image layer that a backup refers to by digest from the
+// layer store
+func copyLayer(tarWriter *tar.Writer, hdr *tar.Header, digest string, layers LayerStore) error {
+	if layers == nil {
+		return ErrNoLayerStore
+	}
+	rc, size, err := layers.GetLayer(digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	delete(hdr.PAXRecords, LayerDigestRecord)
+	hdr.Size = size
+	if err := tarWriter.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tarWriter, rc)
+	return err
+}
+
 // imageLoadPipe returns a pipe writer and error channel for restoring docker
 // images.
 func (dfs *DistributedFilesystem) imageLoadPipe() (*io.PipeWriter, <-chan error) {
@@ -341,16 +411,9 @@ func (dfs *DistributedFilesystem) loadSnapshotImages(tenant, label string) error
 
 	// get the list of images for this snapshot
 	images, err := func() ([]string, error) {
-		r, err := vol.ReadMetadata(label, ImagesMetadataFile)
-		defer r.Close()
-		if err != nil {
-			tenantLogger.WithError(err).Error("Could not read images metadata from snapshot for tenant")
-			return nil, err
-		}
-
 		images := []string{}
-		if err := importJSON(r, &images); err != nil {
-			tenantLogger.WithError(err).Error("Could not interpret images metadata from snapshot for tenant")
+		if err := volume.ReadSnapshotMetadata(vol, label, ImagesMetadata, &images); err != nil {
+			tenantLogger.WithError(err).Error("Could not read images metadata from snapshot for tenant")
 			return nil, err
 		}
 		return images, nil
@@ -379,7 +442,7 @@ func (dfs *DistributedFilesystem) loadSnapshotImages(tenant, label string) error
 			return err
 		}
 
-		if err := dfs.index.PushImage(image, img.ID, hash); err != nil {
+		if err := dfs.index.PushImage(tenantImage(image, tenant), img.ID, hash); err != nil {
 			imageLogger.WithError(err).Error("Could not push image into the registry")
 			return err
 		}
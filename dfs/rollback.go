@@ -15,9 +15,49 @@ package dfs
 
 import (
 	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/dfs/registry"
+	"github.com/control-center/serviced/volume"
 	"github.com/zenoss/glog"
 )
 
+// RollbackPreview describes what a rollback to a snapshot changes
+type RollbackPreview struct {
+	SnapshotID  string
+	TenantID    string
+	Stopped     []RollbackService  // running services that the rollback stops
+	Images      []ImageChange      // images whose latest tag is changed
+	ConfigFiles []ConfigFileChange // config files that differ from the snapshot
+}
+
+// RollbackService is a service that is stopped by a rollback
+type RollbackService struct {
+	ServiceID string
+	Name      string
+}
+
+// ImageChange is an image whose latest tag is changed by a rollback
+type ImageChange struct {
+	Image    string
+	Current  string // the image id that is tagged latest; empty if not tagged
+	Snapshot string // the image id of the snapshot
+}
+
+// Config file changes that are made by a rollback
+const (
+	ConfigFileAdded    = "added"
+	ConfigFileRemoved  = "removed"
+	ConfigFileModified = "modified"
+)
+
+// ConfigFileChange is a config file of a service that is changed by a
+// rollback
+type ConfigFileChange struct {
+	ServiceID string
+	Name      string
+	Filename  string
+	Change    string
+}
+
 // Rollback reverts an application to a previous snapshot.
 func (dfs *DistributedFilesystem) Rollback(snapshotID string) error {
 	vol, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
@@ -25,18 +65,13 @@ func (dfs *DistributedFilesystem) Rollback(snapshotID string) error {
 		return err
 	}
 	// do all the images exist in the registry?
-	r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
-	if err != nil {
-		glog.Errorf("Could not receive images metadata from snapshot %s: %s", snapshotID, err)
-		return err
-	}
 	var images []string
-	if err := importJSON(r, &images); err != nil {
-		glog.Errorf("Could not interpret images metadata file from snapshot %s: %s", snapshotID, err)
+	if err := volume.ReadSnapshotMetadata(vol, info.Label, ImagesMetadata, &images); err != nil {
+		glog.Errorf("Could not read images metadata from snapshot %s: %s", snapshotID, err)
 		return err
 	}
 	for _, image := range images {
-		rImage, err := dfs.index.FindImage(image)
+		rImage, err := dfs.index.FindImage(tenantImage(image, info.TenantID))
 		if err != nil {
 			glog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
 			return err
@@ -58,3 +93,64 @@ func (dfs *DistributedFilesystem) Rollback(snapshotID string) error {
 	}
 	return nil
 }
+
+// RollbackImages returns the images of a snapshot whose latest tag is changed
+// by a rollback to the snapshot.
+func (dfs *DistributedFilesystem) RollbackImages(snapshotID string) ([]ImageChange, error) {
+	vol, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	if err := volume.ReadSnapshotMetadata(vol, info.Label, ImagesMetadata, &images); err != nil {
+		glog.Errorf("Could not read images metadata from snapshot %s: %s", snapshotID, err)
+		return nil, err
+	}
+	changes := []ImageChange{}
+	for _, image := range images {
+		sImage, err := dfs.index.FindImage(tenantImage(image, info.TenantID))
+		if err != nil {
+			glog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
+			return nil, err
+		}
+		change := ImageChange{Snapshot: sImage.UUID}
+		sImage.Tag = docker.Latest
+		change.Image = sImage.String()
+		if rImage, err := dfs.index.FindImage(sImage.String()); err == nil {
+			change.Current = rImage.UUID
+		} else if err != registry.ErrImageNotFound {
+			glog.Errorf("Could not find image %s in the registry: %s", sImage, err)
+			return nil, err
+		}
+		if change.Current != change.Snapshot {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// RollbackImage reverts the latest tag of an image in the registry to the
+// image that was tagged when the snapshot was taken.
+func (dfs *DistributedFilesystem) RollbackImage(snapshotID, image string) error {
+	_, info, err := dfs.getSnapshotVolumeAndInfo(snapshotID)
+	if err != nil {
+		return err
+	}
+	rImage, err := dfs.index.FindImage(image)
+	if err != nil {
+		glog.Errorf("Could not find image %s in the registry: %s", image, err)
+		return err
+	}
+	rImage.Tag = info.Label
+	sImage, err := dfs.index.FindImage(rImage.String())
+	if err != nil {
+		glog.Errorf("Could not find image %s from snapshot %s: %s", image, snapshotID, err)
+		return err
+	}
+	sImage.Tag = docker.Latest
+	if err := dfs.index.PushImage(sImage.String(), sImage.UUID, sImage.Hash); err != nil {
+		glog.Errorf("Could not update image %s from snapshot %s in the registry: %s", image, snapshotID, err)
+		return err
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	. "github.com/control-center/serviced/dfs"
+	index "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/volume"
 	volumemock "github.com/control-center/serviced/volume/mocks"
@@ -116,3 +117,84 @@ func (s *DFSTestSuite) TestRollback_Success(c *C) {
 	err = s.dfs.Rollback("BASE_LABEL")
 	c.Assert(err, IsNil)
 }
+
+func (s *DFSTestSuite) TestRollbackImage_Success(c *C) {
+	vinfo := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(vinfo, nil)
+	s.index.On("FindImage", "BASE/repo").Return(&registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "latest",
+		UUID:    "newuuid",
+		Hash:    "newhash",
+	}, nil)
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(&registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "LABEL",
+		UUID:    "testuuid",
+		Hash:    "hashvalue",
+	}, nil)
+	s.index.On("PushImage", "BASE/repo:latest", "testuuid", "hashvalue").Return(nil)
+	err := s.dfs.RollbackImage("BASE_LABEL", "BASE/repo")
+	c.Assert(err, IsNil)
+	s.index.AssertExpectations(c)
+}
+
+func (s *DFSTestSuite) TestRollbackImage_NotInSnapshot(c *C) {
+	vinfo := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(vinfo, nil)
+	s.index.On("FindImage", "BASE/repo").Return(&registry.Image{
+		Library: "BASE",
+		Repo:    "repo",
+		Tag:     "latest",
+	}, nil)
+	s.index.On("FindImage", "BASE/repo:LABEL").Return(&registry.Image{}, ErrTestImageNotInRegistry)
+	err := s.dfs.RollbackImage("BASE_LABEL", "BASE/repo")
+	c.Assert(err, Equals, ErrTestImageNotInRegistry)
+}
+
+func (s *DFSTestSuite) TestRollbackImages(c *C) {
+	vinfo := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vimages := []string{"BASE/repo:LABEL", "BASE/same:LABEL", "BASE/gone:LABEL"}
+	vimagesbuf := bytes.NewBufferString("")
+	err := json.NewEncoder(vimagesbuf).Encode(vimages)
+	c.Assert(err, IsNil)
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(vinfo, nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{vimagesbuf}, nil)
+	for _, repo := range []string{"repo", "same", "gone"} {
+		s.index.On("FindImage", "BASE/"+repo+":LABEL").Return(&registry.Image{
+			Library: "BASE",
+			Repo:    repo,
+			Tag:     "LABEL",
+			UUID:    "snapshotuuid",
+		}, nil)
+	}
+	s.index.On("FindImage", "BASE/repo:latest").Return(&registry.Image{UUID: "newuuid"}, nil)
+	s.index.On("FindImage", "BASE/same:latest").Return(&registry.Image{UUID: "snapshotuuid"}, nil)
+	s.index.On("FindImage", "BASE/gone:latest").Return(nil, index.ErrImageNotFound)
+	changes, err := s.dfs.RollbackImages("BASE_LABEL")
+	c.Assert(err, IsNil)
+	c.Assert(changes, DeepEquals, []ImageChange{
+		{Image: "BASE/repo:latest", Current: "newuuid", Snapshot: "snapshotuuid"},
+		{Image: "BASE/gone:latest", Current: "", Snapshot: "snapshotuuid"},
+	})
+}
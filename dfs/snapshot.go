@@ -76,21 +76,11 @@ func (dfs *DistributedFilesystem) Snapshot(data SnapshotInfo, spaceFactor int) (
 		images[i] = fullImagePath
 	}
 	// write snapshot metadata
-	w, err := vol.WriteMetadata(label, ImagesMetadataFile)
-	if err != nil {
-		glog.Errorf("Could not create image metadata file for tenant %s: %s", data.TenantID, err)
-		return "", err
-	}
-	if err := exportJSON(w, images); err != nil {
-		glog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
-		return "", err
-	}
-	w, err = vol.WriteMetadata(label, ServicesMetadataFile)
-	if err != nil {
-		glog.Errorf("Could not create service metadata file for tenant %s: %s", data.TenantID, err)
+	if err := volume.WriteSnapshotMetadata(vol, label, ImagesMetadata, images); err != nil {
+		glog.Errorf("Could not write image metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
-	if err := exportJSON(w, data.Services); err != nil {
+	if err := volume.WriteSnapshotMetadata(vol, label, ServicesMetadata, data.Services); err != nil {
 		glog.Errorf("Could not write service metadata file for tenant %s: %s", data.TenantID, err)
 		return "", err
 	}
@@ -215,12 +215,25 @@ func (s *DFSTestSuite) TestSnapshot_Success(c *C) {
 	vol.On("Snapshot", mock.AnythingOfType("string"), data.Message, data.Tags).Return(nil).Run(func(a mock.Arguments) {
 		label := a.Get(0).(string)
 		name = "BASE_" + label
+		// metadata is written as versioned documents
+		var imagesDoc, servicesDoc struct {
+			Kind    string
+			Version int
+			Data    json.RawMessage
+		}
+		err := json.NewDecoder(imagesBuffer).Decode(&imagesDoc)
+		c.Assert(err, IsNil)
+		c.Assert(imagesDoc.Kind, Equals, ImagesMetadata)
+		c.Assert(imagesDoc.Version, Equals, 1)
 		var actualImages []string
-		err := json.NewDecoder(imagesBuffer).Decode(&actualImages)
+		err = json.Unmarshal(imagesDoc.Data, &actualImages)
 		c.Assert(err, IsNil)
 		c.Assert(actualImages, DeepEquals, []string{"test:5000/BASE/repo:" + label})
+		err = json.NewDecoder(servicesBuffer).Decode(&servicesDoc)
+		c.Assert(err, IsNil)
+		c.Assert(servicesDoc.Kind, Equals, ServicesMetadata)
 		var actualServices []service.Service
-		err = json.NewDecoder(servicesBuffer).Decode(&actualServices)
+		err = json.Unmarshal(servicesDoc.Data, &actualServices)
 		c.Assert(err, IsNil)
 		c.Assert(actualServices, DeepEquals, data.Services)
 
@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/zenoss/glog"
+)
+
+// TemplatesDir is the directory under the root of the volumes that holds a
+// copy of each service template, so that a new master can recover the
+// templates from the dfs without restoring a backup.
+const TemplatesDir = ".templates"
+
+// templatePath returns the path to the copy of a service template
+func (dfs *DistributedFilesystem) templatePath(templateID string) string {
+	return filepath.Join(dfs.disk.Root(), TemplatesDir, templateID+".json")
+}
+
+// SaveTemplate writes a copy of a service template to the dfs.
+func (dfs *DistributedFilesystem) SaveTemplate(template servicetemplate.ServiceTemplate) error {
+	filename := dfs.templatePath(template.ID)
+	if err := os.MkdirAll(filepath.Dir(filename), 0750); err != nil {
+		glog.Errorf("Could not create directory for service template copies: %s", err)
+		return err
+	}
+	template.DatabaseVersion = 0
+	data, err := json.Marshal(template)
+	if err != nil {
+		glog.Errorf("Could not marshal service template %s: %s", template.ID, err)
+		return err
+	}
+	// write to a temporary file first so that a copy is never left
+	// half-written
+	tmpname := filename + ".tmp"
+	if err := ioutil.WriteFile(tmpname, data, 0640); err != nil {
+		glog.Errorf("Could not write copy of service template %s: %s", template.ID, err)
+		return err
+	}
+	if err := os.Rename(tmpname, filename); err != nil {
+		glog.Errorf("Could not save copy of service template %s: %s", template.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTemplate removes the copy of a service template from the dfs.
+func (dfs *DistributedFilesystem) DeleteTemplate(templateID string) error {
+	if err := os.Remove(dfs.templatePath(templateID)); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("Could not delete copy of service template %s: %s", templateID, err)
+		return err
+	}
+	return nil
+}
+
+// Templates returns the copies of the service templates on the dfs.
+func (dfs *DistributedFilesystem) Templates() ([]servicetemplate.ServiceTemplate, error) {
+	dirpath := filepath.Join(dfs.disk.Root(), TemplatesDir)
+	files, err := ioutil.ReadDir(dirpath)
+	if os.IsNotExist(err) {
+		return []servicetemplate.ServiceTemplate{}, nil
+	} else if err != nil {
+		glog.Errorf("Could not read service template copies at %s: %s", dirpath, err)
+		return nil, err
+	}
+	templates := []servicetemplate.ServiceTemplate{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dirpath, file.Name()))
+		if err != nil {
+			glog.Errorf("Could not read service template copy %s: %s", file.Name(), err)
+			return nil, err
+		}
+		var template servicetemplate.ServiceTemplate
+		if err := json.Unmarshal(data, &template); err != nil {
+			glog.Errorf("Could not interpret service template copy %s: %s", file.Name(), err)
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
@@ -78,8 +78,8 @@ func (ttl *SnapshotTTL) Purge(age time.Duration) (time.Duration, error) {
 			return 0, err
 		}
 		for _, s := range snapshots {
-			//ignore snapshots that have any tag
-			if len(s.Tags) == 0 {
+			//ignore snapshots that have any tag or are pinned
+			if len(s.Tags) == 0 && !s.Pinned {
 				// check the age of the snapshot
 				if timeToLive := s.Created.Sub(expire); timeToLive <= 0 {
 					snapshotLogger := logger.WithFields(log.Fields{
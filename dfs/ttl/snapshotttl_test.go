@@ -185,3 +185,26 @@ func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_DontDeleteTaggedSnap(c *C)
 		c.Errorf("Tags missing from remaning snapshot")
 	}
 }
+
+func (s *SnapshotTTLTestSuite) TestSnapshotTTL_Purge_DontDeletePinnedSnap(c *C) {
+	timeCreated := time.Now().UTC().Add(-5 * time.Minute)
+
+	snapToSave := dao.SnapshotInfo{
+		SnapshotID: "snapshotpin_" + timeCreated.Format(timeFormat),
+		Created:    timeCreated,
+		Pinned:     true,
+	}
+
+	iface := &TestSnapshotTTLInterface{
+		tenantIDs: []string{"test service id"},
+		snaps:     []dao.SnapshotInfo{snapToSave},
+	}
+	ttl := &SnapshotTTL{iface}
+	if _, err := ttl.Purge(time.Minute); err != nil {
+		c.Errorf("Unexpected error: %s", err)
+	}
+
+	if len(iface.snaps) != 1 || iface.snaps[0].SnapshotID != snapToSave.SnapshotID {
+		c.Errorf("Pinned snapshot should not have been deleted")
+	}
+}
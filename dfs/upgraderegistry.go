@@ -15,7 +15,9 @@ package dfs
 
 import (
 	"fmt"
+	"sync"
 
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/zenoss/glog"
@@ -25,6 +27,7 @@ import (
 // index.  Also migrates images from a previous (or V1) registry at
 // registryHost (host:port).
 func (dfs *DistributedFilesystem) UpgradeRegistry(svcs []service.ServiceDetails, tenantID, registryHost string, override bool) error {
+	var upgrades []registryUpgrade
 	imageIDs := make(map[string]struct{})
 	for _, svc := range svcs {
 		if svc.ImageID == "" {
@@ -54,18 +57,19 @@ func (dfs *DistributedFilesystem) UpgradeRegistry(svcs []service.ServiceDetails,
 			glog.Warningf("Cannot parse image name %s under service %s (%s)", image, svc.Name, svc.ID)
 			continue
 		}
-		// download image from old registry at registryHost defined at HOST:PORT
-		// and retag it at the original registry path as defined by the service.
-		if registryHost != "" {
-			glog.Infof("Downloading image %s from %s registry", image, registryHost)
-			oldImage := fmt.Sprintf("%s/%s", registryHost, rImage)
-			if err := dfs.docker.PullImage(oldImage); err != nil {
-				glog.Warningf("Could not pull image %s from registry %s, falling back to local library: %s", image, registryHost, err)
-			} else if err := dfs.docker.TagImage(oldImage, image); err != nil {
-				glog.Errorf("Could not retag image %s as %s: %s", oldImage, image, err)
-				return err
-			}
+		upgrades = append(upgrades, registryUpgrade{svc: svc, image: image, rImage: rImage})
+	}
+
+	// download images from old registry at registryHost defined at HOST:PORT
+	// and retag them at the original registry path as defined by the service.
+	if registryHost != "" {
+		if err := dfs.pullRegistryImages(upgrades, registryHost); err != nil {
+			return err
 		}
+	}
+
+	for _, u := range upgrades {
+		svc, image, rImage := u.svc, u.image, u.rImage
 		// find image in docker library
 		img, err := dfs.docker.FindImage(image)
 		if docker.IsImageNotFound(err) {
@@ -91,3 +95,53 @@ func (dfs *DistributedFilesystem) UpgradeRegistry(svcs []service.ServiceDetails,
 	}
 	return nil
 }
+
+// registryUpgrade is a service image that is missing from the registry index
+type registryUpgrade struct {
+	svc    service.ServiceDetails
+	image  string
+	rImage string
+}
+
+// pullRegistryImages pulls the images from the registry at registryHost,
+// DockerPullConcurrency images at a time.  Images that cannot be pulled are
+// looked up in the local library instead.
+func (dfs *DistributedFilesystem) pullRegistryImages(upgrades []registryUpgrade, registryHost string) error {
+	workers := config.GetOptions().DockerPullConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		tagErr error
+	)
+	queue := make(chan registryUpgrade)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				glog.Infof("Downloading image %s from %s registry", u.image, registryHost)
+				oldImage := fmt.Sprintf("%s/%s", registryHost, u.rImage)
+				if err := dfs.docker.PullImage(oldImage); err != nil {
+					glog.Warningf("Could not pull image %s from registry %s, falling back to local library: %s", u.image, registryHost, err)
+				} else if err := dfs.docker.TagImage(oldImage, u.image); err != nil {
+					glog.Errorf("Could not retag image %s as %s: %s", oldImage, u.image, err)
+					mu.Lock()
+					if tagErr == nil {
+						tagErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, u := range upgrades {
+		queue <- u
+	}
+	close(queue)
+	wg.Wait()
+	return tagErr
+}
@@ -271,3 +271,48 @@ func (s *DFSTestSuite) TestUpgradeRegistry_MigrateSuccess(c *C) {
 	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "old-server:5001", false)
 	c.Assert(err, IsNil)
 }
+
+// images pulled from an old registry, falling back to the local library
+func (s *DFSTestSuite) TestUpgradeRegistry_PullFromRegistryHost(c *C) {
+	svcs := []service.ServiceDetails{
+		{
+			Name:    "service1",
+			ID:      "service_id1",
+			ImageID: "localhost:5000/tenantid/repo1",
+		}, {
+			Name:    "service2",
+			ID:      "service_id2",
+			ImageID: "localhost:5000/tenantid/repo2",
+		},
+	}
+	s.docker.On("PullImage", "oldhost:5000/tenantid/repo1:latest").Return(nil)
+	s.docker.On("TagImage", "oldhost:5000/tenantid/repo1:latest", "localhost:5000/tenantid/repo1").Return(nil)
+	s.docker.On("PullImage", "oldhost:5000/tenantid/repo2:latest").Return(ErrTestGeneric)
+	image1 := &dockerclient.Image{ID: "image1"}
+	image2 := &dockerclient.Image{ID: "image2"}
+	s.docker.On("FindImage", "localhost:5000/tenantid/repo1").Return(image1, nil)
+	s.docker.On("FindImage", "localhost:5000/tenantid/repo2").Return(image2, nil)
+	s.docker.On("GetImageHash", "image1").Return("hash1", nil)
+	s.docker.On("GetImageHash", "image2").Return("hash2", nil)
+	s.index.On("PushImage", "tenantid/repo1:latest", "image1", "hash1").Return(nil)
+	s.index.On("PushImage", "tenantid/repo2:latest", "image2", "hash2").Return(nil)
+	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "oldhost:5000", true)
+	c.Assert(err, IsNil)
+	s.docker.AssertExpectations(c)
+	s.index.AssertExpectations(c)
+}
+
+// error when retagging an image pulled from an old registry
+func (s *DFSTestSuite) TestUpgradeRegistry_TagImageFail(c *C) {
+	svcs := []service.ServiceDetails{
+		{
+			Name:    "service",
+			ID:      "service_id",
+			ImageID: "localhost:5000/tenantid/repo",
+		},
+	}
+	s.docker.On("PullImage", "oldhost:5000/tenantid/repo:latest").Return(nil)
+	s.docker.On("TagImage", "oldhost:5000/tenantid/repo:latest", "localhost:5000/tenantid/repo").Return(ErrTestGeneric)
+	err := s.dfs.UpgradeRegistry(svcs, "tenantid", "oldhost:5000", true)
+	c.Assert(err, Equals, ErrTestGeneric)
+}
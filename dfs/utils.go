@@ -14,45 +14,22 @@
 package dfs
 
 import (
-	"encoding/json"
-	"io"
-
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/volume"
 	"github.com/zenoss/glog"
 )
 
-func importJSON(r io.ReadCloser, v interface{}) error {
-	defer r.Close()
-	return json.NewDecoder(r).Decode(v)
-}
-
-func exportJSON(w io.WriteCloser, v interface{}) error {
-	defer w.Close()
-	return json.NewEncoder(w).Encode(v)
-}
-
 func readSnapshotInfo(vol volume.Volume, info *volume.SnapshotInfo) (*SnapshotInfo, error) {
 	// Retrieve the images metadata
-	r, err := vol.ReadMetadata(info.Label, ImagesMetadataFile)
-	if err != nil {
-		glog.Errorf("Could not read images metadata from snapshot %s: %s", info.Label, err)
-		return nil, err
-	}
 	var images []string
-	if err := importJSON(r, &images); err != nil {
-		glog.Errorf("Could not interpret images metadata from snapshot %s: %s", info.Label, err)
+	if err := volume.ReadSnapshotMetadata(vol, info.Label, ImagesMetadata, &images); err != nil {
+		glog.Errorf("Could not read images metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
 	// Retrieve services metadata
-	r, err = vol.ReadMetadata(info.Label, ServicesMetadataFile)
-	if err != nil {
-		glog.Errorf("Could not read services metadata from snapshot %s: %s", info.Label, err)
-		return nil, err
-	}
 	var svcs []service.Service
-	if err := importJSON(r, &svcs); err != nil {
-		glog.Errorf("Could not interpret services metadata from snapshot %s: %s", info.Label, err)
+	if err := volume.ReadSnapshotMetadata(vol, info.Label, ServicesMetadata, &svcs); err != nil {
+		glog.Errorf("Could not read services metadata from snapshot %s: %s", info.Label, err)
 		return nil, err
 	}
 	return &SnapshotInfo{info, images, svcs}, nil
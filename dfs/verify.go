@@ -0,0 +1,374 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
+)
+
+// ChecksumsDir holds an entry for each section of a backup with the sha256
+// checksum of the entries of the section.  Backups that were taken before
+// checksums were recorded do not have it.
+const ChecksumsDir = "CHECKSUMS/"
+
+// ChecksumSection returns the name of the entry that holds the checksum of a
+// section of a backup.
+func ChecksumSection(section string) string {
+	return path.Join(ChecksumsDir, section)
+}
+
+// BackupVerification is the result of checking the integrity of a backup
+// file.  The backup is intact if there are no errors.
+type BackupVerification struct {
+	BackupVersion    int
+	Timestamp        time.Time
+	Sections         []BackupSection
+	Layers           int
+	MetadataRestored bool
+	Compression      volume.Compression // codec recorded in the manifest
+	Errors           []string
+}
+
+// BackupSection describes a snapshot, elasticsearch snapshot or the images
+// of a backup.  Verified is true if its checksum matches the checksum that
+// was recorded when the backup was taken.
+type BackupSection struct {
+	Name     string
+	Entries  int
+	Size     int64
+	Checksum string
+	Verified bool
+}
+
+func (v *BackupVerification) fail(format string, args ...interface{}) {
+	v.Errors = append(v.Errors, fmt.Sprintf(format, args...))
+}
+
+// sectionChecksum hashes the names and the data of the entries of a section
+type sectionChecksum struct {
+	hash hash.Hash
+}
+
+func newSectionChecksum() *sectionChecksum {
+	return &sectionChecksum{hash: sha256.New()}
+}
+
+// Header adds an entry to the checksum.  The data of the entry is written
+// after it.
+func (c *sectionChecksum) Header(hdr *tar.Header) {
+	io.WriteString(c.hash, hdr.Name+"\x00")
+	if digest, ok := hdr.PAXRecords[LayerDigestRecord]; ok {
+		io.WriteString(c.hash, digest+"\x00")
+	}
+}
+
+func (c *sectionChecksum) Write(p []byte) (int, error) {
+	return c.hash.Write(p)
+}
+
+// Sum returns the hex encoded checksum of the section
+func (c *sectionChecksum) Sum() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// writeChecksum writes the checksum entry of a section
+func writeChecksum(tarWriter *tar.Writer, section string, sum *sectionChecksum) error {
+	data := []byte(sum.Sum())
+	header := &tar.Header{Name: ChecksumSection(section), Size: int64(len(data)), Mode: 0644}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tarWriter.Write(data)
+	return err
+}
+
+// backupSection returns the section that an entry of a backup belongs to, or
+// an empty string if the entry is not part of a section
+func backupSection(name string) string {
+	parts := strings.Split(name, "/")
+	switch {
+	case strings.HasPrefix(name, SnapshotsMetadataDir) && len(parts) >= 3:
+		return SnapshotSection(parts[1], parts[2])
+	case strings.HasPrefix(name, ElasticMetadataDir) && len(parts) >= 2:
+		return ElasticSection(parts[1])
+	case strings.HasPrefix(name, DockerImagesFile):
+		return DockerImagesFile
+	}
+	return ""
+}
+
+// snapshotMetadataKind returns the kind of snapshot metadata that an entry
+// of a snapshot section holds, or an empty string if it is volume data.
+// Drivers export the metadata of a snapshot into a directory that is
+// suffixed with -metadata.
+func snapshotMetadataKind(name string) string {
+	parts := strings.SplitN(name, "/", 5)
+	if len(parts) < 5 || !strings.HasSuffix(parts[3], "-metadata") {
+		return ""
+	}
+	switch parts[4] {
+	case path.Clean(ImagesMetadataFile):
+		return ImagesMetadata
+	case path.Clean(ServicesMetadataFile):
+		return ServicesMetadata
+	}
+	return ""
+}
+
+// VerifyBackup reads a backup to the end and checks that its manifest is
+// complete and that the checksum of each of its sections matches the one
+// that was recorded.  If the reader implements LayerStore, the image layers
+// that the backup refers to are checksummed as well.  If testRestore is
+// true, the metadata of each snapshot is extracted into a temporary
+// directory and read as it would be restored.  Problems with the backup are
+// reported in the result; an error is only returned if the verification
+// could not run.
+func VerifyBackup(r io.Reader, testRestore bool) (*BackupVerification, error) {
+	layers := getLayerStore(r)
+	v := &BackupVerification{}
+
+	var tmpdir string
+	if testRestore {
+		var err error
+		if tmpdir, err = ioutil.TempDir("", "serviced-verify-"); err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmpdir)
+	}
+
+	var info *BackupInfo
+	sums := make(map[string]*sectionChecksum)
+	sections := make(map[string]*BackupSection)
+	var order []string
+	verifiedLayers := make(map[string]bool)
+
+	backuptar := tar.NewReader(r)
+	for first := true; ; first = false {
+		hdr, err := backuptar.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			v.fail("could not read backup: %s", err)
+			break
+		}
+
+		if hdr.Name == BackupMetadataFile {
+			if !first {
+				v.fail("backup manifest is not the first entry")
+			}
+			info = &BackupInfo{}
+			if err := json.NewDecoder(backuptar).Decode(info); err != nil {
+				v.fail("could not load backup manifest: %s", err)
+				info = nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(hdr.Name, ChecksumsDir) {
+			name := strings.TrimPrefix(hdr.Name, ChecksumsDir)
+			expected, err := ioutil.ReadAll(backuptar)
+			if err != nil {
+				v.fail("could not read checksum of %s: %s", name, err)
+				break
+			}
+			sec, ok := sections[name]
+			if !ok {
+				v.fail("backup has a checksum for %s, but not its data", name)
+				continue
+			}
+			sec.Checksum = sums[name].Sum()
+			if sec.Checksum != strings.TrimSpace(string(expected)) {
+				v.fail("checksum of %s does not match", name)
+				continue
+			}
+			sec.Verified = true
+			continue
+		}
+
+		name := backupSection(hdr.Name)
+		if name == "" {
+			continue
+		}
+		sec, ok := sections[name]
+		if !ok {
+			sec = &BackupSection{Name: name}
+			sections[name] = sec
+			sums[name] = newSectionChecksum()
+			order = append(order, name)
+		}
+		sum := sums[name]
+		sum.Header(hdr)
+		sec.Entries++
+
+		if digest, ok := hdr.PAXRecords[LayerDigestRecord]; ok {
+			if !verifiedLayers[digest] {
+				verifiedLayers[digest] = true
+				if err := verifyLayer(layers, digest); err != nil {
+					v.fail("image layer %s: %s", digest, err)
+				}
+			}
+			continue
+		}
+
+		w := io.Writer(sum)
+		var fh *os.File
+		if kind := snapshotMetadataKind(hdr.Name); kind != "" && tmpdir != "" {
+			if fh, err = createMetadataFile(tmpdir, name, kind); err != nil {
+				return nil, err
+			}
+			w = io.MultiWriter(sum, fh)
+		}
+		n, err := io.Copy(w, backuptar)
+		if fh != nil {
+			fh.Close()
+		}
+		sec.Size += n
+		if err != nil {
+			v.fail("could not read %s: %s", hdr.Name, err)
+			break
+		}
+	}
+	v.Layers = len(verifiedLayers)
+
+	for _, name := range order {
+		sec := sections[name]
+		if sec.Checksum == "" {
+			sec.Checksum = sums[name].Sum()
+		}
+		v.Sections = append(v.Sections, *sec)
+	}
+
+	if info == nil {
+		v.fail("backup has no manifest")
+		return v, nil
+	}
+	v.BackupVersion, v.Timestamp, v.Compression = info.BackupVersion, info.Timestamp, info.Compression
+	if info.BackupVersion != 1 {
+		// pre-1.1.3 backups do not have sections
+		if info.BackupVersion != 0 {
+			v.fail("backup version %d is not supported", info.BackupVersion)
+		}
+		return v, nil
+	}
+
+	// every section in the manifest must be in the archive
+	expected := map[string]bool{DockerImagesFile: true}
+	for _, snapshot := range info.Snapshots {
+		tenantID, label := snapshotTenantLabel(snapshot)
+		expected[SnapshotSection(tenantID, label)] = true
+	}
+	for _, snapshot := range info.ElasticSnapshots {
+		expected[ElasticSection(snapshot.Cluster)] = true
+	}
+	for name := range expected {
+		if _, ok := sections[name]; !ok {
+			v.fail("backup is missing %s", name)
+		}
+	}
+	for _, name := range order {
+		if !expected[name] {
+			v.fail("backup has %s, which is not in its manifest", name)
+		}
+	}
+
+	if testRestore {
+		for _, snapshot := range info.Snapshots {
+			tenantID, label := snapshotTenantLabel(snapshot)
+			restoreMetadata(v, tmpdir, SnapshotSection(tenantID, label))
+		}
+		v.MetadataRestored = true
+	}
+	return v, nil
+}
+
+// snapshotTenantLabel returns the tenant and the label of a snapshot id
+func snapshotTenantLabel(snapshotID string) (string, string) {
+	parts := strings.SplitN(snapshotID, "_", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// verifyLayer checks that an image layer is in the store and that its data
+// matches its digest
+func verifyLayer(layers LayerStore, digest string) error {
+	if layers == nil {
+		return ErrNoLayerStore
+	}
+	rc, _, err := layers.GetLayer(digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return err
+	}
+	if "sha256:"+hex.EncodeToString(hash.Sum(nil)) != digest {
+		return fmt.Errorf("checksum does not match")
+	}
+	return nil
+}
+
+// createMetadataFile creates the file in the temporary directory of a
+// verification that a snapshot metadata document is extracted to
+func createMetadataFile(tmpdir, section, kind string) (*os.File, error) {
+	dir := filepath.Join(tmpdir, filepath.FromSlash(section))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return os.Create(filepath.Join(dir, kind))
+}
+
+// restoreMetadata reads the metadata documents of a snapshot that were
+// extracted to the temporary directory of a verification
+func restoreMetadata(v *BackupVerification, tmpdir, section string) {
+	var images []string
+	var svcs []service.Service
+	values := map[string]interface{}{
+		ImagesMetadata:   &images,
+		ServicesMetadata: &svcs,
+	}
+	for _, kind := range []string{ImagesMetadata, ServicesMetadata} {
+		value := values[kind]
+		fh, err := os.Open(filepath.Join(tmpdir, filepath.FromSlash(section), kind))
+		if os.IsNotExist(err) {
+			v.fail("%s has no %s metadata", section, kind)
+			continue
+		} else if err != nil {
+			v.fail("could not open %s metadata of %s: %s", kind, section, err)
+			continue
+		}
+		if err := volume.DecodeSnapshotMetadata(fh, kind, value); err != nil {
+			v.fail("could not restore %s metadata of %s: %s", kind, section, err)
+		}
+		fh.Close()
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	. "gopkg.in/check.v1"
+)
+
+// writeVerifyBackup writes a backup with the given manifest and entries,
+// without checksums
+func writeVerifyBackup(c *C, info BackupInfo, entries [][2]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	data, err := json.Marshal(info)
+	c.Assert(err, IsNil)
+	c.Assert(tw.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(data))}), IsNil)
+	_, err = tw.Write(data)
+	c.Assert(err, IsNil)
+	for _, entry := range entries {
+		c.Assert(tw.WriteHeader(&tar.Header{Name: entry[0], Size: int64(len(entry[1]))}), IsNil)
+		_, err = tw.Write([]byte(entry[1]))
+		c.Assert(err, IsNil)
+	}
+	c.Assert(tw.Close(), IsNil)
+	return buf
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Success(c *C) {
+	backupInfo := s.setupCheckpointBackup(c, true)
+	backupInfo.BackupVersion = 1
+	buf := &bytes.Buffer{}
+	c.Assert(s.dfs.Backup(backupInfo, buf), IsNil)
+
+	v, err := VerifyBackup(buf, false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Errors, HasLen, 0)
+	c.Assert(v.BackupVersion, Equals, 1)
+	c.Assert(v.Sections, HasLen, 2)
+	c.Assert(v.Sections[0].Name, Equals, SnapshotSection("BASE", "LABEL"))
+	c.Assert(v.Sections[0].Entries, Equals, 1)
+	c.Assert(v.Sections[0].Size, Equals, int64(len("here is some data")))
+	c.Assert(v.Sections[0].Verified, Equals, true)
+	c.Assert(v.Sections[1].Name, Equals, DockerImagesFile)
+	c.Assert(v.Sections[1].Verified, Equals, true)
+	c.Assert(v.MetadataRestored, Equals, false)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Corrupt(c *C) {
+	backupInfo := s.setupCheckpointBackup(c, true)
+	backupInfo.BackupVersion = 1
+	buf := &bytes.Buffer{}
+	c.Assert(s.dfs.Backup(backupInfo, buf), IsNil)
+
+	// change the data of the snapshot
+	data := bytes.Replace(buf.Bytes(), []byte("here is some data"), []byte("here is some dada"), 1)
+	v, err := VerifyBackup(bytes.NewReader(data), false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Errors, DeepEquals, []string{"checksum of " + SnapshotSection("BASE", "LABEL") + " does not match"})
+	c.Assert(v.Sections[0].Verified, Equals, false)
+	c.Assert(v.Sections[1].Verified, Equals, true)
+
+	// truncate the archive
+	v, err = VerifyBackup(bytes.NewReader(buf.Bytes()[:1200]), false)
+	c.Assert(err, IsNil)
+	c.Assert(len(v.Errors) > 0, Equals, true)
+	c.Assert(strings.HasPrefix(v.Errors[0], "could not read"), Equals, true)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Manifest(c *C) {
+	// no manifest
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	c.Assert(tw.Close(), IsNil)
+	v, err := VerifyBackup(buf, false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Errors, DeepEquals, []string{"backup has no manifest"})
+
+	// sections are missing from and not in the manifest
+	info := BackupInfo{
+		BackupVersion:    1,
+		Snapshots:        []string{"BASE_LABEL"},
+		ElasticSnapshots: []ElasticSnapshot{{Cluster: "serviced"}},
+	}
+	buf = writeVerifyBackup(c, info, [][2]string{
+		{SnapshotSection("BASE", "OTHER") + "/afile", "data"},
+		{ElasticSection("serviced") + "/index", "data"},
+		{DockerImagesFile + "/repositories", "{}"},
+	})
+	v, err = VerifyBackup(buf, false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Errors, DeepEquals, []string{
+		"backup is missing " + SnapshotSection("BASE", "LABEL"),
+		"backup has " + SnapshotSection("BASE", "OTHER") + ", which is not in its manifest",
+	})
+	c.Assert(v.Sections, HasLen, 3)
+	for _, sec := range v.Sections {
+		// the backup has no checksums
+		c.Assert(sec.Verified, Equals, false)
+		c.Assert(sec.Checksum, Not(Equals), "")
+	}
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Layers(c *C) {
+	store := NewFileLayerStore(c.MkDir())
+	digest, err := store.PutLayer(strings.NewReader("some layer data"))
+	c.Assert(err, IsNil)
+	missing := "sha256:" + strings.Repeat("0", 64)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	data, err := json.Marshal(BackupInfo{BackupVersion: 1})
+	c.Assert(err, IsNil)
+	c.Assert(tw.WriteHeader(&tar.Header{Name: BackupMetadataFile, Size: int64(len(data))}), IsNil)
+	_, err = tw.Write(data)
+	c.Assert(err, IsNil)
+	for _, d := range []string{digest, digest, missing} {
+		c.Assert(tw.WriteHeader(&tar.Header{
+			Name:       DockerImagesFile + "/abc/layer.tar",
+			Typeflag:   tar.TypeReg,
+			PAXRecords: map[string]string{LayerDigestRecord: d},
+			Format:     tar.FormatPAX,
+		}), IsNil)
+	}
+	c.Assert(tw.Close(), IsNil)
+
+	v, err := VerifyBackup(&layerReader{Reader: bytes.NewReader(buf.Bytes()), FileLayerStore: store}, false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Layers, Equals, 2)
+	c.Assert(v.Errors, DeepEquals, []string{"image layer " + missing + ": " + ErrLayerNotFound.Error()})
+
+	// without a store, the layers cannot be checked
+	v, err = VerifyBackup(bytes.NewReader(buf.Bytes()), false)
+	c.Assert(err, IsNil)
+	c.Assert(v.Errors, HasLen, 2)
+	c.Assert(v.Errors[0], Equals, "image layer "+digest+": "+ErrNoLayerStore.Error())
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_TestRestore(c *C) {
+	info := BackupInfo{
+		BackupVersion: 1,
+		Snapshots:     []string{"BASE_LABEL", "BASE_OTHER"},
+		Timestamp:     time.Now().UTC(),
+	}
+	buf := writeVerifyBackup(c, info, [][2]string{
+		{SnapshotSection("BASE", "LABEL") + "/BASE_LABEL-metadata/.snapshot/images.json", `{"kind":"images","version":1,"data":["BASE/repo:latest"]}`},
+		{SnapshotSection("BASE", "LABEL") + "/BASE_LABEL-metadata/.snapshot/services.json", `[]`},
+		{SnapshotSection("BASE", "LABEL") + "/BASE_LABEL-volume/afile", "data"},
+		{SnapshotSection("BASE", "OTHER") + "/BASE_OTHER-metadata/.snapshot/images.json", `[""]`},
+		{DockerImagesFile + "/repositories", "{}"},
+	})
+
+	v, err := VerifyBackup(buf, true)
+	c.Assert(err, IsNil)
+	c.Assert(v.MetadataRestored, Equals, true)
+	c.Assert(v.Errors, DeepEquals, []string{
+		"could not restore images metadata of " + SnapshotSection("BASE", "OTHER") + ": images metadata has an empty image path",
+		SnapshotSection("BASE", "OTHER") + " has no services metadata",
+	})
+}
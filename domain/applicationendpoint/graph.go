@@ -0,0 +1,56 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationendpoint
+
+// EndpointGraph is the dependency graph of the services of an application
+// through the endpoints that they import and export
+type EndpointGraph struct {
+	TenantID string
+	Services []EndpointGraphService
+	Edges    []EndpointGraphEdge
+}
+
+// EndpointGraphService is a service that imports or exports an endpoint
+type EndpointGraphService struct {
+	ServiceID string
+	Name      string
+	Exports   []string // the applications that the service exports
+}
+
+// EndpointGraphEdge is an import of a service and an export that it resolves
+// to.  An import that no service exports is unresolved, and has no export.
+type EndpointGraphEdge struct {
+	ServiceID         string // the service that imports
+	Application       string // the application pattern of the import
+	Purpose           string // import or import_all
+	ExportServiceID   string `json:",omitempty"`
+	ExportApplication string `json:",omitempty"`
+	Error             string `json:",omitempty"` // why the import cannot resolve
+}
+
+// Unresolved returns true if no service exports the import
+func (e EndpointGraphEdge) Unresolved() bool {
+	return e.ExportServiceID == ""
+}
+
+// Unresolved returns the imports of the graph that no service exports
+func (g *EndpointGraph) Unresolved() []EndpointGraphEdge {
+	edges := []EndpointGraphEdge{}
+	for _, e := range g.Edges {
+		if e.Unresolved() {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationendpoint
+
+// EndpointMetrics are the connection statistics of an endpoint of a service
+// over a window.  The statistics of an import are of the connections that the
+// service made to the application, and those of an export are of the
+// connections that every importer of the application made to it.
+type EndpointMetrics struct {
+	ServiceID     string
+	Application   string
+	Purpose       string
+	Connections   int64 // connections made in the window
+	Active        int64 // open connections at the end of the window
+	ConnectErrors int64 // connections that could not reach the endpoint
+	BytesSent     int64 // bytes sent by the service
+	BytesReceived int64 // bytes received by the service
+}
+
+// ConnectErrorRate returns the fraction of the connection attempts that
+// failed
+func (m EndpointMetrics) ConnectErrorRate() float64 {
+	if attempts := m.Connections + m.ConnectErrors; attempts > 0 {
+		return float64(m.ConnectErrors) / float64(attempts)
+	}
+	return 0
+}
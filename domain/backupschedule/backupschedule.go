@@ -0,0 +1,93 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+// BackupSchedule is a cron-style schedule on which the master takes backups
+type BackupSchedule struct {
+	ID          string    // Unique identifier of the schedule, assigned when it is added
+	Schedule    string    // Cron-style expression of when backups are taken, eg "0 2 * * *"
+	Calendar    string    // Calendar whose time zone and exclusions apply; UTC if empty
+	Keep        int       // Number of backups of the schedule to keep; all of them if 0
+	Dirpath     string    // Directory the backups are written to; the backups path if empty
+	Excludes    []string  // Subdirectories of the tenant volumes to exclude from backups
+	Compression string    // Codec that compresses the backups; the default codec if empty
+	AlertURL    string    // URL that failed backups are posted to
+	LastRun     time.Time // When the schedule last took a backup
+	LastError   string    // Why the last backup failed; empty if it succeeded
+	Backups     []string  // Files written by the schedule, oldest first
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	datastore.VersionedEntity
+}
+
+// Next returns the first time after the given time that the schedule takes a
+// backup, skipping the exclusions of the calendar.  cal may be nil.
+func (s *BackupSchedule) Next(cal *calendar.Calendar, after time.Time) (time.Time, error) {
+	schedule, err := calendar.ParseSchedule(s.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if cal == nil {
+		cal = &calendar.Calendar{}
+	}
+	return cal.Next(schedule, after)
+}
+
+// Due returns true if a backup fell due since the schedule last ran, or since
+// it was created if it never ran.  Backups missed while no master was running
+// are only taken once.
+func (s *BackupSchedule) Due(cal *calendar.Calendar, now time.Time) (bool, error) {
+	since := s.LastRun
+	if since.IsZero() {
+		since = s.CreatedAt
+	}
+	next, err := s.Next(cal, since)
+	if err != nil {
+		return false, err
+	}
+	return !next.IsZero() && !next.After(now), nil
+}
+
+// Prune drops the oldest backups beyond the number to keep and returns them
+func (s *BackupSchedule) Prune() []string {
+	if s.Keep <= 0 || len(s.Backups) <= s.Keep {
+		return nil
+	}
+	n := len(s.Backups) - s.Keep
+	pruned := append([]string{}, s.Backups[:n]...)
+	s.Backups = append([]string{}, s.Backups[n:]...)
+	return pruned
+}
+
+// GetType returns the datastore type of backup schedules
+func GetType() string {
+	return kind
+}
+
+// GetID is an implementation of audit.Entity
+func (s *BackupSchedule) GetID() string {
+	return s.ID
+}
+
+// GetType is an implementation of audit.Entity
+func (s *BackupSchedule) GetType() string {
+	return GetType()
+}
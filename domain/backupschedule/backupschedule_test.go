@@ -0,0 +1,100 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package backupschedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+func TestBackupSchedule_Due(t *testing.T) {
+	created := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := &BackupSchedule{ID: "nightly", Schedule: "0 2 * * *", CreatedAt: created}
+
+	for _, tc := range []struct {
+		now time.Time
+		due bool
+	}{
+		{time.Date(2017, 3, 2, 1, 59, 0, 0, time.UTC), false},
+		{time.Date(2017, 3, 2, 2, 0, 0, 0, time.UTC), true},
+		// a backup missed while no master was running is taken once
+		{time.Date(2017, 3, 5, 9, 0, 0, 0, time.UTC), true},
+	} {
+		if due, err := s.Due(nil, tc.now); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		} else if due != tc.due {
+			t.Errorf("Expected due=%v at %s, got %v", tc.due, tc.now, due)
+		}
+	}
+
+	s.LastRun = time.Date(2017, 3, 5, 9, 0, 0, 0, time.UTC)
+	if due, _ := s.Due(nil, time.Date(2017, 3, 6, 1, 0, 0, 0, time.UTC)); due {
+		t.Errorf("Expected no backup due before the next run")
+	}
+	if due, _ := s.Due(nil, time.Date(2017, 3, 6, 2, 0, 0, 0, time.UTC)); !due {
+		t.Errorf("Expected a backup due at the next run")
+	}
+}
+
+func TestBackupSchedule_DueCalendar(t *testing.T) {
+	cal := &calendar.Calendar{ID: "default", TimeZone: "America/Chicago", Exclusions: []string{"2017-03-02"}}
+	s := &BackupSchedule{ID: "nightly", Schedule: "0 2 * * *", Calendar: cal.ID, CreatedAt: time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)}
+
+	next, err := s.Next(cal, s.CreatedAt)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// 2am in Chicago on the day after the excluded date
+	if expected := time.Date(2017, 3, 3, 8, 0, 0, 0, time.UTC); !next.Equal(expected) {
+		t.Errorf("Expected next run at %s, got %s", expected, next)
+	}
+}
+
+func TestBackupSchedule_Prune(t *testing.T) {
+	s := &BackupSchedule{Keep: 2, Backups: []string{"a.tgz", "b.tgz", "c.tgz"}}
+	if pruned := s.Prune(); !reflect.DeepEqual(pruned, []string{"a.tgz"}) {
+		t.Errorf("Expected a.tgz to be pruned, got %v", pruned)
+	}
+	if !reflect.DeepEqual(s.Backups, []string{"b.tgz", "c.tgz"}) {
+		t.Errorf("Expected the newest backups to be kept, got %v", s.Backups)
+	}
+
+	s = &BackupSchedule{Backups: []string{"a.tgz", "b.tgz", "c.tgz"}}
+	if pruned := s.Prune(); len(pruned) > 0 {
+		t.Errorf("Expected all backups to be kept, got %v pruned", pruned)
+	}
+}
+
+func TestBackupSchedule_ValidEntity(t *testing.T) {
+	s := &BackupSchedule{ID: "nightly", Schedule: "@daily", Keep: 7, AlertURL: "https://alerts.example.com/backup"}
+	if err := s.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	for _, invalid := range []BackupSchedule{
+		{Schedule: "@daily"},
+		{ID: "nightly", Schedule: "* * *"},
+		{ID: "nightly", Schedule: "@daily", Keep: -1},
+		{ID: "nightly", Schedule: "@daily", AlertURL: "alerts.example.com"},
+	} {
+		if err := invalid.ValidEntity(); err == nil {
+			t.Errorf("Expected %+v to be invalid", invalid)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	kind          = "backupschedule"
+	plog          = logging.PackageLogger()
+	mappingString = fmt.Sprintf(`
+{
+     "%s": {
+      "properties":{
+        "ID":             {"type": "string", "index":"not_analyzed"},
+        "Schedule":       {"type": "string", "index":"not_analyzed"},
+        "Calendar":       {"type": "string", "index":"not_analyzed"},
+        "Keep":           {"type": "long", "index":"not_analyzed"},
+        "Dirpath":        {"type": "string", "index":"not_analyzed"},
+        "Excludes":       {"type": "string", "index":"not_analyzed"},
+        "Compression":    {"type": "string", "index":"not_analyzed"},
+        "AlertURL":       {"type": "string", "index":"not_analyzed"},
+        "LastRun":        {"type": "date", "format" : "dateOptionalTime"},
+        "LastError":      {"type": "string", "index":"not_analyzed"},
+        "Backups":        {"type": "string", "index":"not_analyzed"},
+        "CreatedAt":      {"type": "date", "format" : "dateOptionalTime"},
+        "UpdatedAt":      {"type": "date", "format" : "dateOptionalTime"}
+      }
+    }
+}
+`, kind)
+	// MAPPING is the elastic mapping for a backup schedule
+	MAPPING, mappingError = elastic.NewMapping(mappingString)
+)
+
+func init() {
+	if mappingError != nil {
+		plog.WithError(mappingError).Fatal("error creating mapping for the backup schedule object")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a backup schedule store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with backup schedule persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetBackupSchedules returns all backup schedules
+	GetBackupSchedules(ctx datastore.Context) ([]BackupSchedule, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetBackupSchedules returns all backup schedules
+func (s *storeImpl) GetBackupSchedules(ctx datastore.Context) ([]BackupSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("BackupScheduleStore.GetBackupSchedules"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting backup
+// schedules
+func Key(id string) datastore.Key {
+	id = strings.TrimSpace(id)
+	return datastore.NewKey(kind, id)
+}
+
+func convert(results datastore.Results) ([]BackupSchedule, error) {
+	schedules := make([]BackupSchedule, results.Len())
+	for idx := range schedules {
+		if err := results.Get(idx, &schedules[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
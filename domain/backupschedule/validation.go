@@ -0,0 +1,45 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backupschedule
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the backup schedule fields
+func (s *BackupSchedule) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("BackupSchedule.ID", s.ID))
+
+	if _, err := calendar.ParseSchedule(s.Schedule); err != nil {
+		violations.Add(err)
+	}
+	if s.Keep < 0 {
+		violations.Add(fmt.Errorf("number of backups to keep must not be negative: %d", s.Keep))
+	}
+	if s.AlertURL != "" {
+		if u, err := url.Parse(s.AlertURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			violations.Add(fmt.Errorf("invalid alert url %s: expected an http or https url", s.AlertURL))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
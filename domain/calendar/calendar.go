@@ -0,0 +1,187 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calendar
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// DateFormat is the layout of exclusion dates
+const DateFormat = "2006-01-02"
+
+// TimeFormat is the layout of window start and end times in the time zone of
+// the calendar
+const TimeFormat = "2006-01-02 15:04"
+
+// Window kinds
+const (
+	// MaintenanceWindow is a period during which maintenance may be performed
+	MaintenanceWindow = "maintenance"
+	// FreezeWindow is a period during which deployments are not allowed
+	FreezeWindow = "freeze"
+)
+
+// Window is a period of time on a calendar
+type Window struct {
+	Name  string
+	Kind  string // maintenance or freeze
+	Start time.Time
+	End   time.Time
+}
+
+// Contains returns true if t is within the window
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Calendar is a time zone with the dates and periods that scheduled operations
+// (backups, service tasks, deployments) must observe.
+type Calendar struct {
+	ID          string   // Unique identifier for the calendar, eg "default"
+	Description string   // Description of the calendar
+	TimeZone    string   // IANA time zone name, eg "America/Chicago"; UTC if empty
+	Exclusions  []string // Dates (YYYY-MM-DD) in the time zone when scheduled operations do not run
+	Windows     []Window // Maintenance windows and deployment freezes
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a new calendar
+func New(id string) *Calendar {
+	return &Calendar{ID: id}
+}
+
+// Location returns the time zone of the calendar
+func (c *Calendar) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.TimeZone)
+}
+
+// ParseTime parses a time (YYYY-MM-DD HH:MM) in the time zone of the calendar
+func (c *Calendar) ParseTime(value string) (time.Time, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(TimeFormat, value, loc)
+}
+
+// IsExcluded returns true if t falls on an exclusion date of the calendar
+func (c *Calendar) IsExcluded(t time.Time) bool {
+	loc, err := c.Location()
+	if err != nil {
+		return false
+	}
+	date := t.In(loc).Format(DateFormat)
+	for _, exclusion := range c.Exclusions {
+		if exclusion == date {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveWindow returns the first window of the given kind that contains t, or
+// nil if there is none.
+func (c *Calendar) ActiveWindow(kind string, t time.Time) *Window {
+	for i, w := range c.Windows {
+		if w.Kind == kind && w.Contains(t) {
+			return &c.Windows[i]
+		}
+	}
+	return nil
+}
+
+// InMaintenance returns true if t is within a maintenance window
+func (c *Calendar) InMaintenance(t time.Time) bool {
+	return c.ActiveWindow(MaintenanceWindow, t) != nil
+}
+
+// IsFrozen returns true if t is within a deployment freeze
+func (c *Calendar) IsFrozen(t time.Time) bool {
+	return c.ActiveWindow(FreezeWindow, t) != nil
+}
+
+// Next returns the next time after the given time that the schedule runs in
+// the time zone of the calendar, skipping exclusion dates.  Returns the zero
+// time if the schedule does not run again.
+func (c *Calendar) Next(s *Schedule, after time.Time) (time.Time, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := after.In(loc)
+	for {
+		if t = s.Next(t); t.IsZero() || !c.IsExcluded(t) {
+			return t, nil
+		}
+	}
+}
+
+// Equals returns true if two calendars are equal
+func (c *Calendar) Equals(b *Calendar) bool {
+	if c.ID != b.ID {
+		return false
+	}
+	if c.Description != b.Description {
+		return false
+	}
+	if c.TimeZone != b.TimeZone {
+		return false
+	}
+	if len(c.Exclusions) != len(b.Exclusions) {
+		return false
+	}
+	for i := range c.Exclusions {
+		if c.Exclusions[i] != b.Exclusions[i] {
+			return false
+		}
+	}
+	if len(c.Windows) != len(b.Windows) {
+		return false
+	}
+	for i := range c.Windows {
+		w, bw := c.Windows[i], b.Windows[i]
+		if w.Name != bw.Name || w.Kind != bw.Kind || !w.Start.Equal(bw.Start) || !w.End.Equal(bw.End) {
+			return false
+		}
+	}
+	if c.CreatedAt.Unix() != b.CreatedAt.Unix() {
+		return false
+	}
+	if c.UpdatedAt.Unix() != b.UpdatedAt.Unix() {
+		return false
+	}
+	return true
+}
+
+// GetType returns the kind of the calendar entity
+func GetType() string {
+	return kind
+}
+
+// GetID returns the ID of the calendar
+func (c *Calendar) GetID() string {
+	return c.ID
+}
+
+// GetType returns the kind of the calendar entity
+func (c *Calendar) GetType() string {
+	return GetType()
+}
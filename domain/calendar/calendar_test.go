@@ -0,0 +1,105 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_Next(t *testing.T) {
+	c := &Calendar{
+		ID:         "default",
+		TimeZone:   "America/New_York",
+		Exclusions: []string{"2017-12-25"},
+	}
+	loc, err := c.Location()
+	if err != nil {
+		t.Skipf("Time zone data not available: %s", err)
+	}
+	s, err := ParseSchedule("0 1 * * *")
+	if err != nil {
+		t.Fatalf("Could not parse schedule: %s", err)
+	}
+
+	// 07:00 UTC is 02:00 in New York, so the next run is on the 25th, which
+	// is excluded
+	actual, err := c.Next(s, time.Date(2017, time.December, 24, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := time.Date(2017, time.December, 26, 1, 0, 0, 0, loc)
+	if !actual.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
+
+func TestCalendar_IsExcluded(t *testing.T) {
+	c := &Calendar{ID: "default", TimeZone: "Asia/Tokyo", Exclusions: []string{"2017-01-01"}}
+	if _, err := c.Location(); err != nil {
+		t.Skipf("Time zone data not available: %s", err)
+	}
+	if !c.IsExcluded(time.Date(2016, time.December, 31, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected new year's day in Tokyo to be excluded")
+	}
+	if c.IsExcluded(time.Date(2016, time.December, 31, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected new year's eve in Tokyo not to be excluded")
+	}
+}
+
+func TestCalendar_Windows(t *testing.T) {
+	c := &Calendar{ID: "default"}
+	start, _ := c.ParseTime("2017-06-01 00:00")
+	end, _ := c.ParseTime("2017-06-08 00:00")
+	c.Windows = []Window{
+		{Name: "release", Kind: FreezeWindow, Start: start, End: end},
+	}
+
+	if !c.IsFrozen(start) {
+		t.Errorf("Expected the start of the window to be frozen")
+	}
+	if c.IsFrozen(end) {
+		t.Errorf("Expected the end of the window not to be frozen")
+	}
+	if c.InMaintenance(start) {
+		t.Errorf("Expected no maintenance window")
+	}
+	if w := c.ActiveWindow(FreezeWindow, start.Add(time.Hour)); w == nil || w.Name != "release" {
+		t.Errorf("Expected the release window, got %+v", w)
+	}
+}
+
+func TestCalendar_ValidEntity(t *testing.T) {
+	c := &Calendar{ID: "default", TimeZone: "UTC", Exclusions: []string{"2017-01-01"}}
+	if err := c.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	invalid := []*Calendar{
+		{ID: ""},
+		{ID: " default"},
+		{ID: "default", TimeZone: "Not/AZone"},
+		{ID: "default", Exclusions: []string{"01/01/2017"}},
+		{ID: "default", Windows: []Window{{Kind: "outage", Start: now, End: now.Add(time.Hour)}}},
+		{ID: "default", Windows: []Window{{Kind: FreezeWindow, Start: now, End: now}}},
+	}
+	for i, c := range invalid {
+		if err := c.ValidEntity(); err == nil {
+			t.Errorf("Test %d: expected a validation error", i)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) *Schedule {
+	s, err := ParseSchedule(expr)
+	if err != nil {
+		t.Fatalf("Could not parse schedule %q: %s", expr, err)
+	}
+	return s
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("Expected an error parsing %q", expr)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	start := time.Date(2017, time.March, 10, 10, 30, 0, 0, time.UTC) // a friday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2017, time.March, 10, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2017, time.March, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2017, time.March, 11, 2, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2017, time.March, 11, 10, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2017, time.March, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2017, time.March, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2017, time.March, 13, 9, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 1", time.Date(2017, time.March, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		if actual := mustParse(t, test.expr).Next(start); !actual.Equal(test.expected) {
+			t.Errorf("Schedule %q: expected %s, got %s", test.expr, test.expected, actual)
+		}
+	}
+}
+
+func TestSchedule_NextTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("Time zone data not available: %s", err)
+	}
+	s := mustParse(t, "30 2 * * *")
+
+	// 2:30 does not exist when daylight saving time starts
+	actual := s.Next(time.Date(2017, time.March, 11, 12, 0, 0, 0, loc))
+	expected := time.Date(2017, time.March, 13, 2, 30, 0, 0, loc)
+	if !actual.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+
+	actual = s.Next(time.Date(2017, time.June, 1, 12, 0, 0, 0, loc))
+	expected = time.Date(2017, time.June, 2, 7, 30, 0, 0, time.UTC)
+	if !actual.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
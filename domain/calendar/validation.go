@@ -0,0 +1,52 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the calendar fields
+func (c *Calendar) ValidEntity() error {
+	trimmedID := strings.TrimSpace(c.ID)
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Calendar.ID", c.ID))
+	violations.Add(validation.StringsEqual(c.ID, trimmedID, "leading and trailing spaces not allowed for calendar id"))
+
+	if _, err := c.Location(); err != nil {
+		violations.Add(fmt.Errorf("invalid time zone %s: %s", c.TimeZone, err))
+	}
+
+	for _, date := range c.Exclusions {
+		if _, err := time.Parse(DateFormat, date); err != nil {
+			violations.Add(fmt.Errorf("invalid exclusion date %s: expected YYYY-MM-DD", date))
+		}
+	}
+
+	for _, w := range c.Windows {
+		violations.Add(validation.StringIn(w.Kind, MaintenanceWindow, FreezeWindow))
+		if !w.End.After(w.Start) {
+			violations.Add(fmt.Errorf("window %s must end after it starts", w.Name))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// ErrNoCertificate is returned when a PEM chain has no certificate
+var ErrNoCertificate = errors.New("no certificate in PEM data")
+
+// IssuerCustom is the issuer of the certificates that were uploaded for a
+// virtual host rather than obtained from a certificate authority
+const IssuerCustom = "custom"
+
+// Certificate is the tls certificate of a virtual host public endpoint that
+// was obtained from an ACME certificate authority, or uploaded for the
+// virtual host.  The private key is encrypted with the master's public key.
+type Certificate struct {
+	Name      string    // Host name of the certificate, or name of the uploaded vhost
+	Issuer    string    // Directory url of the certificate authority, or IssuerCustom
+	CertPEM   []byte    // PEM encoded certificate chain, leaf first
+	KeyPEM    []byte    // Encrypted PEM encoded private key
+	NotAfter  time.Time // When the certificate expires
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a certificate for a host name from its PEM encoded chain and
+// encrypted private key
+func New(name, issuer string, certPEM, keyPEM []byte) (*Certificate, error) {
+	notAfter, err := ExpiresAt(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{
+		Name:     name,
+		Issuer:   issuer,
+		CertPEM:  certPEM,
+		KeyPEM:   keyPEM,
+		NotAfter: notAfter,
+	}, nil
+}
+
+// ExpiresAt returns when the leaf of a PEM encoded certificate chain expires
+func ExpiresAt(certPEM []byte) (time.Time, error) {
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return cert.NotAfter, nil
+	}
+	return time.Time{}, ErrNoCertificate
+}
+
+// NeedsRenewal returns true if the certificate expires within the window
+func (c *Certificate) NeedsRenewal(now time.Time, window time.Duration) bool {
+	return !now.Add(window).Before(c.NotAfter)
+}
+
+// GetType returns the kind of the certificate entity
+func GetType() string {
+	return kind
+}
+
+// GetID returns the host name of the certificate
+func (c *Certificate) GetID() string {
+	return c.Name
+}
+
+// GetType returns the kind of the certificate entity
+func (c *Certificate) GetType() string {
+	return GetType()
+}
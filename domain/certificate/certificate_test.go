@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func testCertPEM(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Could not generate key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"app.example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Could not create certificate: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestNew(t *testing.T) {
+	notAfter := time.Now().Add(60 * 24 * time.Hour).Truncate(time.Second).UTC()
+	cert, err := New("app.example.com", "https://ca/directory", testCertPEM(t, notAfter), []byte("key"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !cert.NotAfter.Equal(notAfter) {
+		t.Errorf("Expected expiration %s, got %s", notAfter, cert.NotAfter)
+	}
+	if err := cert.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if _, err := New("app.example.com", "", []byte("not a certificate"), []byte("key")); err != ErrNoCertificate {
+		t.Errorf("Expected %s, got %v", ErrNoCertificate, err)
+	}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	now := time.Now()
+	cert := &Certificate{NotAfter: now.Add(40 * 24 * time.Hour)}
+	if cert.NeedsRenewal(now, 30*24*time.Hour) {
+		t.Errorf("Certificate should not need renewal")
+	}
+	if !cert.NeedsRenewal(now.Add(15*24*time.Hour), 30*24*time.Hour) {
+		t.Errorf("Certificate should need renewal")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a certificate store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with certificate persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetCertificates returns all certificates
+	GetCertificates(ctx datastore.Context) ([]Certificate, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetCertificates returns all certificates
+func (s *storeImpl) GetCertificates(ctx datastore.Context) ([]Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.GetCertificates"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:Name")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting certificates
+func Key(name string) datastore.Key {
+	name = strings.TrimSpace(name)
+	return datastore.NewKey(kind, name)
+}
+
+func convert(results datastore.Results) ([]Certificate, error) {
+	certs := make([]Certificate, results.Len())
+	for idx := range certs {
+		if err := results.Get(idx, &certs[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return certs, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the certificate fields
+func (c *Certificate) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Certificate.Name", c.Name))
+	if len(c.CertPEM) == 0 {
+		violations.Add(fmt.Errorf("certificate %s has no certificate chain", c.Name))
+	}
+	if len(c.KeyPEM) == 0 {
+		violations.Add(fmt.Errorf("certificate %s has no private key", c.Name))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
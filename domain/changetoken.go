@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ChangeTokenOverlap is subtracted from the time a change token is issued, so
+// that entities written while a listing was in progress are returned again by
+// the next incremental listing.
+const ChangeTokenOverlap = 2 * time.Second
+
+// ErrInvalidChangeToken is returned when a change token cannot be parsed
+var ErrInvalidChangeToken = errors.New("invalid change token")
+
+// NewChangeToken returns an opaque token for a listing that started at t.
+// Passing the token to the next listing returns only the entities that were
+// modified since.
+func NewChangeToken(t time.Time) string {
+	return strconv.FormatInt(t.Add(-ChangeTokenOverlap).UnixNano(), 10)
+}
+
+// ParseChangeToken returns the time encoded in a change token.  An empty
+// token returns the zero time, which matches every entity.
+func ParseChangeToken(token string) (time.Time, error) {
+	if token == "" {
+		return time.Time{}, nil
+	}
+	nsec, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || nsec < 0 {
+		return time.Time{}, ErrInvalidChangeToken
+	}
+	return time.Unix(0, nsec), nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangeToken(t *testing.T) {
+	now := time.Now()
+	since, err := ParseChangeToken(NewChangeToken(now))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing change token: %s", err)
+	}
+	if !since.Equal(now.Add(-ChangeTokenOverlap)) {
+		t.Errorf("Expected %s, got %s", now.Add(-ChangeTokenOverlap), since)
+	}
+
+	if since, err = ParseChangeToken(""); err != nil || !since.IsZero() {
+		t.Errorf("Expected zero time for an empty token, got %s (%v)", since, err)
+	}
+
+	for _, token := range []string{"abc", "-5"} {
+		if _, err := ParseChangeToken(token); err != ErrInvalidChangeToken {
+			t.Errorf("Expected ErrInvalidChangeToken for %q, got %v", token, err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegateprofile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// MaxHistory is the number of previous versions of a profile that are kept
+// for rollback
+const MaxHistory = 10
+
+// LogLevel is the option that delegates apply without restarting
+const LogLevel = "SERVICED_LOG_LEVEL"
+
+// Option describes a delegate option that a profile may set
+type Option struct {
+	Name        string
+	Description string
+	Integer     bool // the value must be an integer
+}
+
+// Options are the options of /etc/default/serviced that profiles may set
+var Options = map[string]Option{
+	LogLevel: {
+		Name:        LogLevel,
+		Description: "Log level of the delegate",
+		Integer:     true,
+	},
+	"SERVICED_MUX_PORT": {
+		Name:        "SERVICED_MUX_PORT",
+		Description: "Port of the TCP multiplexer",
+		Integer:     true,
+	},
+	"SERVICED_MUX_DISABLE_TLS": {
+		Name:        "SERVICED_MUX_DISABLE_TLS",
+		Description: "Disables TLS on the connections of the TCP multiplexer (0 or 1)",
+		Integer:     true,
+	},
+	"SERVICED_MUX_TLS_MIN_VERSION": {
+		Name:        "SERVICED_MUX_TLS_MIN_VERSION",
+		Description: "Minimum TLS version of the TCP multiplexer",
+	},
+	"SERVICED_MUX_TLS_CIPHERS": {
+		Name:        "SERVICED_MUX_TLS_CIPHERS",
+		Description: "TLS 1.2 ciphers of the TCP multiplexer",
+	},
+	"SERVICED_MAX_HEALTH_CHECKS": {
+		Name:        "SERVICED_MAX_HEALTH_CHECKS",
+		Description: "Health checks that may run at the same time, 0 for one per cpu",
+		Integer:     true,
+	},
+	"SERVICED_DOCKER_PULL_CONCURRENCY": {
+		Name:        "SERVICED_DOCKER_PULL_CONCURRENCY",
+		Description: "Images that the delegate pulls at the same time",
+		Integer:     true,
+	},
+	"SERVICED_VOLUMES_PATH": {
+		Name:        "SERVICED_VOLUMES_PATH",
+		Description: "Path of the application volumes",
+	},
+	"SERVICED_LOG_PATH": {
+		Name:        "SERVICED_LOG_PATH",
+		Description: "Path of the access and audit logs",
+	},
+	"SERVICED_METRICS_SPOOL_PATH": {
+		Name:        "SERVICED_METRICS_SPOOL_PATH",
+		Description: "Path where metrics are spooled while the master is unreachable",
+	},
+}
+
+// Lookup returns the description of an option
+func Lookup(name string) (Option, bool) {
+	opt, ok := Options[name]
+	return opt, ok
+}
+
+// Names returns the sorted names of the options that profiles may set
+func Names() []string {
+	names := make([]string, 0, len(Options))
+	for name := range Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if value is not a valid value of the option
+func (o Option) Validate(value string) error {
+	if o.Integer {
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("invalid integer value %q for option %s", value, o.Name)
+		}
+	}
+	return nil
+}
+
+// Revision is a previous version of the options of a profile
+type Revision struct {
+	Version   int
+	Options   map[string]string
+	UpdatedAt time.Time
+}
+
+// Profile is the delegate options that are applied to the hosts of the
+// resource pools that it is assigned to.  Each change to the options is a
+// new version, and the previous versions are kept so that a change can be
+// rolled back.
+type Profile struct {
+	ID          string // Name of the profile
+	Description string
+	Options     map[string]string // Values of the delegate options by name
+	Version     int
+	History     []Revision // Previous versions of the options, oldest first
+	UpdatedAt   time.Time
+	datastore.VersionedEntity
+}
+
+// New creates the first version of a profile without options
+func New(name, description string) *Profile {
+	return &Profile{
+		ID:          name,
+		Description: description,
+		Options:     make(map[string]string),
+		Version:     1,
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// SetOptions replaces the options of the profile with a new version
+func (p *Profile) SetOptions(options map[string]string) {
+	p.History = append(p.History, Revision{
+		Version:   p.Version,
+		Options:   p.Options,
+		UpdatedAt: p.UpdatedAt,
+	})
+	if len(p.History) > MaxHistory {
+		p.History = p.History[len(p.History)-MaxHistory:]
+	}
+	p.Options = make(map[string]string)
+	for name, value := range options {
+		p.Options[name] = value
+	}
+	p.Version++
+	p.UpdatedAt = time.Now()
+}
+
+// Set returns a copy of the options of the profile with an option set
+func (p *Profile) Set(name, value string) map[string]string {
+	options := p.copyOptions()
+	options[name] = value
+	return options
+}
+
+// Unset returns a copy of the options of the profile without an option
+func (p *Profile) Unset(name string) map[string]string {
+	options := p.copyOptions()
+	delete(options, name)
+	return options
+}
+
+// Rollback restores the options of a previous version as a new version, so
+// that delegates see the change.  A version of 0 restores the version before
+// the current one.
+func (p *Profile) Rollback(version int) error {
+	if len(p.History) == 0 {
+		return fmt.Errorf("profile %s has no previous versions", p.ID)
+	}
+	if version == 0 {
+		version = p.History[len(p.History)-1].Version
+	}
+	for _, rev := range p.History {
+		if rev.Version == version {
+			p.SetOptions(rev.Options)
+			return nil
+		}
+	}
+	return fmt.Errorf("profile %s has no version %d", p.ID, version)
+}
+
+// HostStatus is the outcome of pushing a version of a profile to a host
+type HostStatus struct {
+	HostID  string
+	PoolID  string
+	Profile string
+	Version int
+	Restart []string // Options that take effect when the delegate restarts
+	Error   string   // Why the host did not apply the profile
+}
+
+func (p *Profile) copyOptions() map[string]string {
+	options := make(map[string]string)
+	for name, value := range p.Options {
+		options[name] = value
+	}
+	return options
+}
+
+// GetType returns the kind of a delegate profile
+func GetType() string {
+	return kind
+}
+
+// GetID returns the name of the profile
+func (p *Profile) GetID() string {
+	return p.ID
+}
+
+// GetType returns the kind of the profile entity
+func (p *Profile) GetType() string {
+	return GetType()
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package delegateprofile
+
+import (
+	"testing"
+)
+
+func TestProfile_SetOptions(t *testing.T) {
+	p := New("debug", "")
+	p.SetOptions(p.Set(LogLevel, "2"))
+	p.SetOptions(p.Set("SERVICED_MAX_HEALTH_CHECKS", "4"))
+	if p.Version != 3 || len(p.History) != 2 {
+		t.Fatalf("expected version 3 with 2 previous versions, got %d with %v", p.Version, p.History)
+	}
+	if p.Options[LogLevel] != "2" || p.Options["SERVICED_MAX_HEALTH_CHECKS"] != "4" {
+		t.Errorf("unexpected options %v", p.Options)
+	}
+	if len(p.History[1].Options) != 1 {
+		t.Errorf("expected the previous version to keep its options, got %v", p.History[1].Options)
+	}
+
+	p.SetOptions(p.Unset(LogLevel))
+	if _, ok := p.Options[LogLevel]; ok || p.Version != 4 {
+		t.Errorf("expected version 4 without the log level, got %d with %v", p.Version, p.Options)
+	}
+
+	for i := 0; i < MaxHistory+5; i++ {
+		p.SetOptions(p.Options)
+	}
+	if len(p.History) != MaxHistory {
+		t.Errorf("expected %d previous versions, got %d", MaxHistory, len(p.History))
+	}
+}
+
+func TestProfile_Rollback(t *testing.T) {
+	p := New("debug", "")
+	if err := p.Rollback(0); err == nil {
+		t.Errorf("expected an error rolling back a profile without history")
+	}
+	p.SetOptions(p.Set(LogLevel, "2"))
+	p.SetOptions(p.Set(LogLevel, "0"))
+
+	if err := p.Rollback(0); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if p.Version != 4 || p.Options[LogLevel] != "2" {
+		t.Errorf("expected version 4 with log level 2, got %d with %v", p.Version, p.Options)
+	}
+
+	if err := p.Rollback(1); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if p.Version != 5 || len(p.Options) != 0 {
+		t.Errorf("expected version 5 without options, got %d with %v", p.Version, p.Options)
+	}
+
+	if err := p.Rollback(42); err == nil {
+		t.Errorf("expected an error rolling back to a missing version")
+	}
+}
+
+func TestProfile_ValidEntity(t *testing.T) {
+	p := New("debug", "")
+	p.Options[LogLevel] = "2"
+	if err := p.ValidEntity(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	p.Options[LogLevel] = "debug"
+	if err := p.ValidEntity(); err == nil {
+		t.Errorf("expected an error for a log level that is not an integer")
+	}
+	delete(p.Options, LogLevel)
+	p.Options["SERVICED_MASTER"] = "1"
+	if err := p.ValidEntity(); err == nil {
+		t.Errorf("expected an error for an option that profiles may not set")
+	}
+}
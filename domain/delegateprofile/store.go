@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegateprofile
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a delegate profiles store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with delegate profile persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetProfiles returns all delegate profiles
+	GetProfiles(ctx datastore.Context) ([]Profile, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetProfiles returns all delegate profiles
+func (s *storeImpl) GetProfiles(ctx datastore.Context) ([]Profile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("DelegateProfileStore.GetProfiles"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting delegate profiles
+func Key(name string) datastore.Key {
+	name = strings.TrimSpace(name)
+	return datastore.NewKey(kind, name)
+}
+
+func convert(results datastore.Results) ([]Profile, error) {
+	profiles := make([]Profile, results.Len())
+	for idx := range profiles {
+		if err := results.Get(idx, &profiles[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delegateprofile
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the delegate profile fields
+func (p *Profile) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Profile.ID", p.ID))
+	if p.Version < 1 {
+		violations.Add(fmt.Errorf("invalid version %d of profile %s", p.Version, p.ID))
+	}
+	for name, value := range p.Options {
+		opt, ok := Lookup(name)
+		if !ok {
+			violations.Add(fmt.Errorf("unknown delegate option %s", name))
+			continue
+		}
+		violations.Add(opt.Validate(value))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
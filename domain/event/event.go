@@ -0,0 +1,76 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+const (
+	// TypeServiceState is recorded when the current state of a service
+	// changes
+	TypeServiceState = "servicestate"
+
+	// TypeAlert is recorded when a condition needs the attention of an
+	// operator, such as a failed backup or a volume quota threshold
+	TypeAlert = "alert"
+)
+
+// Event is a persisted record of something that happened in the cluster
+type Event struct {
+	ID         string
+	Time       time.Time
+	Type       string
+	EntityType string
+	EntityID   string
+	TenantID   string
+	Message    string
+	Fields     map[string]string // additional details of the event
+	datastore.VersionedEntity
+}
+
+// Query selects the events recorded within a time range, oldest first.
+// Zero values match every event.
+type Query struct {
+	Since    time.Time // inclusive
+	Until    time.Time // inclusive
+	Type     string
+	EntityID string // matches the entity or the tenant of the event
+	Offset   int
+	Limit    int
+}
+
+// Page is a page of the events that match a query.  Next is the offset of
+// the following page, or 0 if this is the last page.
+type Page struct {
+	Events []Event
+	Next   int
+}
+
+// GetType returns the kind of an event
+func GetType() string {
+	return kind
+}
+
+// GetID returns the id of the event
+func (e *Event) GetID() string {
+	return e.ID
+}
+
+// GetType returns the kind of the event entity
+func (e *Event) GetType() string {
+	return GetType()
+}
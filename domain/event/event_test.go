@@ -0,0 +1,39 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package event
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvent_ValidEntity(t *testing.T) {
+	e := &Event{ID: "id", Time: time.Now(), Type: TypeAlert}
+	if err := e.ValidEntity(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	for _, e := range []*Event{
+		{Time: time.Now(), Type: TypeAlert},
+		{ID: "id", Type: TypeAlert},
+		{ID: "id", Time: time.Now()},
+	} {
+		if err := e.ValidEntity(); err == nil {
+			t.Errorf("expected an error for %+v", e)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+const (
+	// DefaultLimit is the size of a page of events when the query does not
+	// set a limit
+	DefaultLimit = 100
+
+	// MaxLimit is the largest page of events that can be queried
+	MaxLimit = 10000
+
+	// pruneBatch is the most events deleted for each retention policy in a
+	// single prune
+	pruneBatch = 10000
+)
+
+// NewStore creates an event store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with event persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// Query returns a page of the events that match the query, oldest first
+	Query(ctx datastore.Context, q Query) (*Page, error)
+
+	// Prune deletes the events recorded at or before the given time and the
+	// oldest events beyond the most recent max, and returns the number of
+	// events deleted.  Zero values disable each policy.
+	Prune(ctx datastore.Context, before time.Time, max int) (int, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// Query returns a page of the events that match the query, oldest first
+func (s *storeImpl) Query(ctx datastore.Context, q Query) (*Page, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("EventStore.Query"))
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	filters := []interface{}{"and", search.Filter().Exists("ID")}
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		r := search.Range().Field("Time")
+		if !q.Since.IsZero() {
+			r = r.From(formatTime(q.Since))
+		}
+		if !q.Until.IsZero() {
+			r = r.To(formatTime(q.Until))
+		}
+		filters = append(filters, r)
+	}
+	if q.Type != "" {
+		filters = append(filters, search.Filter().Terms("Type", q.Type))
+	}
+	if q.EntityID != "" {
+		filters = append(filters, map[string]interface{}{
+			"or": []interface{}{
+				search.Filter().Terms("EntityID", q.EntityID),
+				search.Filter().Terms("TenantID", q.EntityID),
+			},
+		})
+	}
+
+	srch := search.Search("controlplane").Type(kind).Filter(filters...).
+		Sort(search.Sort("Time").Asc()).
+		From(strconv.Itoa(q.Offset)).Size(strconv.Itoa(limit))
+	results, err := datastore.NewQuery(ctx).Execute(srch)
+	if err != nil {
+		return nil, err
+	}
+	events, err := convert(results)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Events: events}
+	if len(events) == limit {
+		page.Next = q.Offset + limit
+	}
+	return page, nil
+}
+
+// Prune deletes the events recorded at or before the given time and the
+// oldest events beyond the most recent max
+func (s *storeImpl) Prune(ctx datastore.Context, before time.Time, max int) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("EventStore.Prune"))
+
+	var searches []*search.SearchDsl
+	if !before.IsZero() {
+		searches = append(searches, search.Search("controlplane").Type(kind).
+			Filter(search.Range().Field("Time").To(formatTime(before))).
+			Size(strconv.Itoa(pruneBatch)))
+	}
+	if max > 0 {
+		searches = append(searches, search.Search("controlplane").Type(kind).
+			Filter(search.Filter().Exists("ID")).
+			Sort(search.Sort("Time").Desc()).
+			From(strconv.Itoa(max)).Size(strconv.Itoa(pruneBatch)))
+	}
+
+	deleted := make(map[string]struct{})
+	for _, srch := range searches {
+		results, err := datastore.NewQuery(ctx).Execute(srch)
+		if err != nil {
+			return len(deleted), err
+		}
+		events, err := convert(results)
+		if err != nil {
+			return len(deleted), err
+		}
+		for _, e := range events {
+			if _, ok := deleted[e.ID]; ok {
+				continue
+			}
+			if err := s.Delete(ctx, Key(e.ID)); err != nil && !datastore.IsErrNoSuchEntity(err) {
+				return len(deleted), err
+			}
+			deleted[e.ID] = struct{}{}
+		}
+	}
+	return len(deleted), nil
+}
+
+// Key creates a Key suitable for getting, putting and deleting events
+func Key(id string) datastore.Key {
+	id = strings.TrimSpace(id)
+	return datastore.NewKey(kind, id)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func convert(results datastore.Results) ([]Event, error) {
+	events := make([]Event, results.Len())
+	for idx := range events {
+		if err := results.Get(idx, &events[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package event
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&S{
+	ElasticTest: elastic.ElasticTest{
+		Index:    "controlplane",
+		Mappings: []elastic.Mapping{MAPPING},
+	}})
+
+type S struct {
+	elastic.ElasticTest
+	ctx   datastore.Context
+	store Store
+}
+
+func (s *S) SetUpTest(c *C) {
+	s.ElasticTest.SetUpTest(c)
+	datastore.Register(s.Driver())
+	s.ctx = datastore.Get()
+	s.store = NewStore()
+}
+
+// putEvents records an event of each type for each entity, a minute apart
+func (s *S) putEvents(c *C, start time.Time, entities ...string) {
+	i := 0
+	for _, entityID := range entities {
+		for _, typ := range []string{TypeServiceState, TypeAlert} {
+			e := &Event{
+				ID:       fmt.Sprintf("event%d", i),
+				Time:     start.Add(time.Duration(i) * time.Minute),
+				Type:     typ,
+				EntityID: entityID,
+				TenantID: "tenant",
+			}
+			c.Assert(s.store.Put(s.ctx, Key(e.ID), e), IsNil)
+			i++
+		}
+	}
+}
+
+func (s *S) Test_Query(c *C) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.putEvents(c, start, "svc1", "svc2")
+
+	page, err := s.store.Query(s.ctx, Query{})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 4)
+	c.Assert(page.Next, Equals, 0)
+	c.Assert(page.Events[0].ID, Equals, "event0")
+
+	page, err = s.store.Query(s.ctx, Query{Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute)})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 2)
+	c.Assert(page.Events[0].ID, Equals, "event1")
+
+	page, err = s.store.Query(s.ctx, Query{Type: TypeAlert, EntityID: "svc2"})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 1)
+	c.Assert(page.Events[0].ID, Equals, "event3")
+
+	page, err = s.store.Query(s.ctx, Query{EntityID: "tenant", Limit: 3})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 3)
+	c.Assert(page.Next, Equals, 3)
+
+	page, err = s.store.Query(s.ctx, Query{EntityID: "tenant", Offset: page.Next, Limit: 3})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 1)
+	c.Assert(page.Next, Equals, 0)
+}
+
+func (s *S) Test_Prune(c *C) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.putEvents(c, start, "svc1", "svc2", "svc3")
+
+	// drop the first event by age and the next two beyond the three most
+	// recent
+	deleted, err := s.store.Prune(s.ctx, start, 3)
+	c.Assert(err, IsNil)
+	c.Assert(deleted, Equals, 3)
+
+	page, err := s.store.Query(s.ctx, Query{})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 3)
+	c.Assert(page.Events[0].ID, Equals, "event3")
+
+	deleted, err = s.store.Prune(s.ctx, time.Time{}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(deleted, Equals, 0)
+}
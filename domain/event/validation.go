@@ -0,0 +1,38 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ErrMissingTime is returned when an event does not have a time
+var ErrMissingTime = errors.New("event must have a time")
+
+// ValidEntity validates the event fields
+func (e *Event) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Event.ID", e.ID))
+	violations.Add(validation.NotEmpty("Event.Type", e.Type))
+	if e.Time.IsZero() {
+		violations.Add(ErrMissingTime)
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// StickyRescheduling keeps a rescheduled service instance on the host where
+// it last ran when the host can still run it.
+const StickyRescheduling = "sticky-rescheduling"
+
+// Definition describes a feature that can be toggled
+type Definition struct {
+	Name        string
+	Description string
+	Default     bool // Whether the feature is enabled when it has not been toggled
+}
+
+// Definitions are the features known to this version of serviced
+var Definitions = map[string]Definition{
+	StickyRescheduling: {
+		Name:        StickyRescheduling,
+		Description: "Reschedule service instances on the host where they last ran",
+		Default:     true,
+	},
+}
+
+// Lookup returns the definition of a feature
+func Lookup(name string) (Definition, bool) {
+	def, ok := Definitions[name]
+	return def, ok
+}
+
+// Names returns the sorted names of the known features
+func Names() []string {
+	names := make([]string, 0, len(Definitions))
+	for name := range Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Flag is the state of a feature that has been toggled.  A feature can be
+// enabled for the whole cluster or only for some resource pools and
+// deployments, so that it can be rolled out gradually.
+type Flag struct {
+	ID          string   // Name of the feature
+	Global      bool     // Whether the feature is enabled everywhere
+	Pools       []string // Resource pools in which the feature is enabled
+	Deployments []string // Deployments in which the feature is enabled
+	UpdatedAt   time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a flag that disables a feature everywhere
+func New(name string) *Flag {
+	return &Flag{ID: name}
+}
+
+// EnabledFor returns true if the feature is enabled in the given pool or
+// deployment.
+func (f *Flag) EnabledFor(poolID, deploymentID string) bool {
+	if f.Global {
+		return true
+	}
+	if poolID != "" && contains(f.Pools, poolID) {
+		return true
+	}
+	if deploymentID != "" && contains(f.Deployments, deploymentID) {
+		return true
+	}
+	return false
+}
+
+// Enable enables the feature in a pool, in a deployment, or everywhere if
+// neither is set.
+func (f *Flag) Enable(poolID, deploymentID string) {
+	if poolID == "" && deploymentID == "" {
+		f.Global = true
+		return
+	}
+	if poolID != "" && !contains(f.Pools, poolID) {
+		f.Pools = append(f.Pools, poolID)
+	}
+	if deploymentID != "" && !contains(f.Deployments, deploymentID) {
+		f.Deployments = append(f.Deployments, deploymentID)
+	}
+}
+
+// Disable disables the feature in a pool, in a deployment, or everywhere if
+// neither is set.
+func (f *Flag) Disable(poolID, deploymentID string) {
+	if poolID == "" && deploymentID == "" {
+		f.Global = false
+		f.Pools = nil
+		f.Deployments = nil
+		return
+	}
+	if poolID != "" {
+		f.Pools = remove(f.Pools, poolID)
+	}
+	if deploymentID != "" {
+		f.Deployments = remove(f.Deployments, deploymentID)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(values []string, value string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// GetType returns the kind of a feature flag
+func GetType() string {
+	return kind
+}
+
+// GetID returns the name of the feature
+func (f *Flag) GetID() string {
+	return f.ID
+}
+
+// GetType returns the kind of the feature flag entity
+func (f *Flag) GetType() string {
+	return GetType()
+}
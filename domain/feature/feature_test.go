@@ -0,0 +1,65 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package feature
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlag_EnableDisable(t *testing.T) {
+	f := New(StickyRescheduling)
+	if f.EnabledFor("pool", "deployment") {
+		t.Fatalf("expected a new flag to be disabled")
+	}
+
+	f.Enable("pool", "")
+	f.Enable("", "deployment")
+	f.Enable("pool", "")
+	if !reflect.DeepEqual(f.Pools, []string{"pool"}) || !reflect.DeepEqual(f.Deployments, []string{"deployment"}) {
+		t.Fatalf("unexpected scopes: %v %v", f.Pools, f.Deployments)
+	}
+	if !f.EnabledFor("pool", "") || !f.EnabledFor("other", "deployment") {
+		t.Errorf("expected the flag to be enabled in its pool and deployment")
+	}
+	if f.EnabledFor("other", "other") || f.EnabledFor("", "") {
+		t.Errorf("expected the flag to be disabled elsewhere")
+	}
+
+	f.Disable("pool", "")
+	if f.EnabledFor("pool", "") {
+		t.Errorf("expected the flag to be disabled in the pool")
+	}
+
+	f.Enable("", "")
+	if !f.EnabledFor("other", "") {
+		t.Errorf("expected the flag to be enabled everywhere")
+	}
+
+	f.Disable("", "")
+	if f.EnabledFor("", "deployment") || f.Global || len(f.Deployments) > 0 {
+		t.Errorf("expected the flag to be disabled everywhere")
+	}
+}
+
+func TestFlag_ValidEntity(t *testing.T) {
+	if err := New(StickyRescheduling).ValidEntity(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := New("no-such-feature").ValidEntity(); err == nil {
+		t.Errorf("expected an error for an unknown feature")
+	}
+}
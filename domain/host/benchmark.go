@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package host
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	// BenchmarkDiskSize is the number of bytes written to measure the disk
+	// throughput of a host.
+	BenchmarkDiskSize = 64 * 1024 * 1024
+
+	// BenchmarkNetworkSize is the number of bytes sent to the master to
+	// measure the network bandwidth of a host.
+	BenchmarkNetworkSize = 16 * 1024 * 1024
+
+	benchmarkBlockSize   = 1024 * 1024
+	benchmarkSyncSize    = 4096
+	benchmarkSyncCount   = 16
+	benchmarkCPUDuration = 2 * time.Second
+)
+
+// Benchmark is the result of the benchmark that runs on a host when it is
+// added.  Hosts that score better are preferred by the scheduler.
+type Benchmark struct {
+	DiskThroughput   uint64        // Bytes per second written to the DFS mount path
+	DiskLatency      time.Duration // Mean time to write and sync a block to the DFS mount path
+	NetworkBandwidth uint64        // Bytes per second sent to the master
+	CPUScore         int           // Megabytes per second hashed on all cores
+	RanAt            time.Time
+}
+
+// Equals verifies whether two benchmarks are equal
+func (a *Benchmark) Equals(b *Benchmark) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.DiskThroughput == b.DiskThroughput &&
+		a.DiskLatency == b.DiskLatency &&
+		a.NetworkBandwidth == b.NetworkBandwidth &&
+		a.CPUScore == b.CPUScore &&
+		a.RanAt.Unix() == b.RanAt.Unix()
+}
+
+// BenchmarkDisk measures the throughput of writing size bytes to a file in
+// the given directory and the latency of synced block writes.
+func BenchmarkDisk(dirpath string, size int64) (uint64, time.Duration, error) {
+	fh, err := ioutil.TempFile(dirpath, ".benchmark")
+	if err != nil {
+		return 0, 0, err
+	}
+	defer os.Remove(fh.Name())
+	defer fh.Close()
+
+	// throughput
+	block := make([]byte, benchmarkBlockSize)
+	start := time.Now()
+	for written := int64(0); written < size; written += int64(len(block)) {
+		if _, err := fh.Write(block); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := fh.Sync(); err != nil {
+		return 0, 0, err
+	}
+	throughput := bytesPerSecond(uint64(size), time.Since(start))
+
+	// latency
+	if _, err := fh.Seek(0, os.SEEK_SET); err != nil {
+		return 0, 0, err
+	}
+	block = block[:benchmarkSyncSize]
+	start = time.Now()
+	for i := 0; i < benchmarkSyncCount; i++ {
+		if _, err := fh.Write(block); err != nil {
+			return 0, 0, err
+		}
+		if err := fh.Sync(); err != nil {
+			return 0, 0, err
+		}
+	}
+	latency := time.Since(start) / benchmarkSyncCount
+
+	return throughput, latency, nil
+}
+
+// BenchmarkCPU returns the number of megabytes hashed per second on all
+// cores of the host.
+func BenchmarkCPU() int {
+	return benchmarkCPU(runtime.NumCPU(), benchmarkCPUDuration)
+}
+
+func benchmarkCPU(cores int, d time.Duration) int {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		hashed uint64
+	)
+	block := make([]byte, benchmarkBlockSize)
+	start := time.Now()
+	for i := 0; i < cores; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n uint64
+			for time.Since(start) < d {
+				sha256.Sum256(block)
+				n += uint64(len(block))
+			}
+			mu.Lock()
+			hashed += n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return int(bytesPerSecond(hashed, time.Since(start)) / (1024 * 1024))
+}
+
+// BenchmarkNetwork measures the bandwidth of sending size bytes with the
+// given function.
+func BenchmarkNetwork(size int, send func([]byte) error) (uint64, error) {
+	data := make([]byte, size)
+	start := time.Now()
+	if err := send(data); err != nil {
+		return 0, err
+	}
+	return bytesPerSecond(uint64(size), time.Since(start)), nil
+}
+
+func bytesPerSecond(n uint64, d time.Duration) uint64 {
+	if d <= 0 {
+		d = time.Nanosecond
+	}
+	return uint64(float64(n) / d.Seconds())
+}
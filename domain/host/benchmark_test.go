@@ -0,0 +1,96 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package host
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_BenchmarkDisk(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "benchmark")
+	if err != nil {
+		t.Fatalf("Could not create temp dir: %s", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	throughput, latency, err := BenchmarkDisk(tmpdir, 4*1024*1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if throughput == 0 {
+		t.Errorf("Expected a disk throughput")
+	}
+	if latency <= 0 {
+		t.Errorf("Expected a disk latency")
+	}
+	if fis, _ := ioutil.ReadDir(tmpdir); len(fis) > 0 {
+		t.Errorf("Benchmark file was not removed")
+	}
+
+	if _, _, err := BenchmarkDisk(tmpdir+"/missing", 1024); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
+
+func Test_BenchmarkCPU(t *testing.T) {
+	if score := benchmarkCPU(1, 100*time.Millisecond); score <= 0 {
+		t.Errorf("Expected a positive cpu score, got %d", score)
+	}
+}
+
+func Test_BenchmarkNetwork(t *testing.T) {
+	sent := 0
+	bandwidth, err := BenchmarkNetwork(1024, func(data []byte) error {
+		sent = len(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if sent != 1024 {
+		t.Errorf("Expected 1024 bytes sent, got %d", sent)
+	}
+	if bandwidth == 0 {
+		t.Errorf("Expected a network bandwidth")
+	}
+
+	expected := errors.New("send failed")
+	if _, err := BenchmarkNetwork(1024, func([]byte) error { return expected }); err != expected {
+		t.Errorf("Expected %s, got %v", expected, err)
+	}
+}
+
+func Test_BenchmarkEquals(t *testing.T) {
+	now := time.Now()
+	a := &Benchmark{DiskThroughput: 1, DiskLatency: time.Millisecond, NetworkBandwidth: 2, CPUScore: 3, RanAt: now}
+	b := *a
+	b.RanAt = now.UTC()
+	if !a.Equals(&b) {
+		t.Errorf("Expected benchmarks to be equal")
+	}
+	b.CPUScore = 4
+	if a.Equals(&b) {
+		t.Errorf("Expected benchmarks to differ")
+	}
+	var c *Benchmark
+	if a.Equals(c) || !c.Equals(nil) {
+		t.Errorf("Unexpected comparison with a nil benchmark")
+	}
+}
@@ -49,17 +49,18 @@ func GetRAMLimit(value string, base uint64) (mem uint64, err error) {
 
 //Host that runs the control center agent.
 type Host struct {
-	ID              string // Unique identifier, default to hostid
-	Name            string // A label for the host, eg hostname, role
-	PoolID          string // Pool that the Host belongs to
-	IPAddr          string // The IP address the host can be reached at from a serviced master
-	RPCPort         int    // The RPC port of the host
-	Cores           int    // Number of cores available to serviced
-	Memory          uint64 // Amount of RAM (bytes) available to serviced
-	CoresCommitment int    // Number of CPU shares (cores) allocated by the user
-	RAMCommitment   uint64 // DEPRECATED: Amount of RAM (bytes) allocated by the user
-	RAMLimit        string // Amount of RAM (size, %) allocated by the user
-	PrivateNetwork  string // The private network where containers run, eg 172.16.42.0/24
+	ID              string            // Unique identifier, default to hostid
+	Name            string            // A label for the host, eg hostname, role
+	PoolID          string            // Pool that the Host belongs to
+	IPAddr          string            // The IP address the host can be reached at from a serviced master
+	RPCPort         int               // The RPC port of the host
+	Cores           int               // Number of cores available to serviced
+	Memory          uint64            // Amount of RAM (bytes) available to serviced
+	CoresCommitment int               // Number of CPU shares (cores) allocated by the user
+	RAMCommitment   uint64            // DEPRECATED: Amount of RAM (bytes) allocated by the user
+	RAMLimit        string            // Amount of RAM (size, %) allocated by the user
+	PrivateNetwork  string            // The private network where containers run, eg 172.16.42.0/24
+	Labels          map[string]string // User assigned labels, used for label-affinity scheduling
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 	IPs             []HostIPResource // The static IP resources available on the host
@@ -75,7 +76,8 @@ type Host struct {
 	}
 	MonitoringProfile domain.MonitorProfile
 	datastore.VersionedEntity
-	NatIP string
+	NatIP     string
+	Benchmark *Benchmark // The benchmark run when the host was added, if any
 }
 
 //ReadHost is a minimal representation of hosts.
@@ -172,10 +174,32 @@ func (a *Host) Equals(b *Host) bool {
 	if a.NatIP != b.NatIP {
 		return false
 	}
+	if !reflect.DeepEqual(a.Labels, b.Labels) {
+		return false
+	}
+	if !a.Benchmark.Equals(b.Benchmark) {
+		return false
+	}
 
 	return true
 }
 
+// MatchesLabels returns true if the host has every label in affinity and
+// none of the labels in antiAffinity.
+func (a *Host) MatchesLabels(affinity, antiAffinity map[string]string) bool {
+	for key, value := range affinity {
+		if v, ok := a.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	for key, value := range antiAffinity {
+		if v, ok := a.Labels[key]; ok && v == value {
+			return false
+		}
+	}
+	return true
+}
+
 //HostIPResource contains information about a specific IP available as a resource
 type HostIPResource struct {
 	HostID        string
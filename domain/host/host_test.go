@@ -199,6 +199,21 @@ func Test_getIPResources(t *testing.T) {
 	}
 }
 
+func Test_isLoopBack(t *testing.T) {
+	for ip, expected := range map[string]bool{
+		"127.0.0.1":       true,
+		"::1":             true,
+		"[::1]":           true,
+		"0:0:0:0:0:0:0:1": true,
+		"10.0.0.1":        false,
+		"2001:db8::1":     false,
+	} {
+		if actual := isLoopBack(ip); actual != expected {
+			t.Errorf("Expected isLoopBack(%s) to be %v", ip, expected)
+		}
+	}
+}
+
 func Test_getOSKernelData(t *testing.T) {
 	kernelVersion, kernelRelease, err := getOSKernelData()
 
@@ -212,3 +227,27 @@ func Test_getOSKernelData(t *testing.T) {
 
 	t.Logf("Kernel Version:  %v Kernel Release: %v", kernelVersion, kernelRelease)
 }
+
+func Test_MatchesLabels(t *testing.T) {
+	h := Host{Labels: map[string]string{"zone": "east", "disk": "ssd"}}
+
+	tests := []struct {
+		affinity     map[string]string
+		antiAffinity map[string]string
+		expected     bool
+	}{
+		{nil, nil, true},
+		{map[string]string{"zone": "east"}, nil, true},
+		{map[string]string{"zone": "east", "disk": "ssd"}, nil, true},
+		{map[string]string{"zone": "west"}, nil, false},
+		{map[string]string{"gpu": "true"}, nil, false},
+		{nil, map[string]string{"disk": "hdd"}, true},
+		{nil, map[string]string{"disk": "ssd"}, false},
+		{map[string]string{"zone": "east"}, map[string]string{"disk": "ssd"}, false},
+	}
+	for i, test := range tests {
+		if actual := h.MatchesLabels(test.affinity, test.antiAffinity); actual != test.expected {
+			t.Errorf("Test %d: expected %t, got %t", i, test.expected, actual)
+		}
+	}
+}
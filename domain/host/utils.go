@@ -69,7 +69,7 @@ func currentHost(ip string, rpcPort int, poolID string) (host *Host, err error)
 			return nil, IsLoopbackError(ip)
 		}
 
-		host.IPAddr = ip
+		host.IPAddr = utils.CanonicalIP(ip)
 	} else {
 		host.IPAddr, err = utils.GetIPAddress()
 		if err != nil {
@@ -145,14 +145,20 @@ func getIPResources(hostID string, hostIP string, staticIPs ...string) ([]HostIP
 		"interfaces": ifacemap,
 	}).Debug("Interfaces on this host")
 
-	// Get a unique list of ips from staticIPs and hostIP.
+	// Get a unique list of ips from staticIPs and hostIP.  IPv6 addresses
+	// are compared in their canonical form.
 	ips := func() []string {
-		for _, ip := range staticIPs {
+		result := make([]string, len(staticIPs))
+		for i, ip := range staticIPs {
+			result[i] = utils.CanonicalIP(ip)
+		}
+		hostIP := utils.CanonicalIP(hostIP)
+		for _, ip := range result {
 			if hostIP == ip {
-				return staticIPs
+				return result
 			}
 		}
-		return append(staticIPs, hostIP)
+		return append(result, hostIP)
 	}()
 
 	hostIPResources := make([]HostIPResource, len(ips))
@@ -196,7 +202,7 @@ func getInterfaceMap() (map[string]net.Interface, error) {
 		}
 		for _, ip := range addrs {
 			normalIP := strings.SplitN(ip.String(), "/", 2)[0]
-			normalIP = strings.Trim(strings.ToLower(normalIP), " ")
+			normalIP = utils.CanonicalIP(strings.Trim(strings.ToLower(normalIP), " "))
 
 			ips[normalIP] = iface
 		}
@@ -208,5 +214,8 @@ func isLoopBack(ip string) bool {
 	if strings.HasPrefix(ip, "127") {
 		return true
 	}
+	if ipaddr := net.ParseIP(strings.Trim(ip, "[]")); ipaddr != nil {
+		return ipaddr.IsLoopback()
+	}
 	return false
 }
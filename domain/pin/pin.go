@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pin
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Kind is the kind of object that a pin protects
+type Kind string
+
+const (
+	// KindSnapshot pins a snapshot of an application by its ID
+	KindSnapshot Kind = "snapshot"
+	// KindImage pins a tag of an image in the docker registry, such as
+	// tenantid/repo:tag
+	KindImage Kind = "image"
+)
+
+// Kinds are the kinds of objects that can be pinned
+var Kinds = []Kind{KindSnapshot, KindImage}
+
+// Pin protects a snapshot or an image tag from the snapshot TTL, from
+// registry cleanup and from being deleted until the pin is removed, so that
+// forensic or compliance artifacts can be kept indefinitely.
+type Pin struct {
+	ID        string // Encoded kind and target, see Key
+	Kind      Kind
+	Target    string // ID of the snapshot or tag of the image
+	TenantID  string // Application that owns the snapshot or image
+	Reason    string
+	CreatedAt time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a pin of a snapshot or image tag
+func New(kind Kind, target, tenantID, reason string) *Pin {
+	return &Pin{
+		ID:        encode(kind, target),
+		Kind:      kind,
+		Target:    target,
+		TenantID:  tenantID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
+
+// GetType returns the kind of a pin
+func GetType() string {
+	return kind
+}
+
+// GetID returns the encoded kind and target of the pin
+func (p *Pin) GetID() string {
+	return p.ID
+}
+
+// GetType returns the kind of the pin entity
+func (p *Pin) GetType() string {
+	return GetType()
+}
+
+// encode returns an ID for the pin that is safe to use in a document ID,
+// since image tags contain slashes and colons.
+func encode(kind Kind, target string) string {
+	return base64.URLEncoding.EncodeToString([]byte(string(kind) + ":" + target))
+}
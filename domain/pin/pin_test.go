@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package pin
+
+import (
+	"testing"
+)
+
+func TestPin_ValidEntity(t *testing.T) {
+	p := New(KindImage, "tenant/repo:tag", "tenant", "audit")
+	if err := p.ValidEntity(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if p.ID == New(KindSnapshot, "tenant/repo:tag", "tenant", "").ID {
+		t.Errorf("expected pins of different kinds to have different IDs")
+	}
+	if Key(KindImage, "tenant/repo:tag").ID() != p.ID {
+		t.Errorf("expected the key to match the ID of the pin")
+	}
+
+	p.Kind = "volume"
+	if err := p.ValidEntity(); err == nil {
+		t.Errorf("expected an error for an unknown kind")
+	}
+	p.Kind = KindSnapshot
+	if err := p.ValidEntity(); err == nil {
+		t.Errorf("expected an error for an ID that does not match")
+	}
+	if err := (&Pin{Kind: KindSnapshot}).ValidEntity(); err == nil {
+		t.Errorf("expected an error for an empty target")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pin
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a pin store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with pin persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetPins returns all pins
+	GetPins(ctx datastore.Context) ([]Pin, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetPins returns all pins
+func (s *storeImpl) GetPins(ctx datastore.Context) ([]Pin, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("PinStore.GetPins"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting the pin of a
+// snapshot or image tag
+func Key(k Kind, target string) datastore.Key {
+	return datastore.NewKey(kind, encode(k, target))
+}
+
+func convert(results datastore.Results) ([]Pin, error) {
+	pins := make([]Pin, results.Len())
+	for idx := range pins {
+		if err := results.Get(idx, &pins[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return pins, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pin
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the pin fields
+func (p *Pin) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Pin.ID", p.ID))
+	violations.Add(validation.NotEmpty("Pin.Target", p.Target))
+	if !ValidKind(p.Kind) {
+		violations.Add(fmt.Errorf("unknown pin kind %q", p.Kind))
+	} else if p.ID != encode(p.Kind, p.Target) {
+		violations.Add(fmt.Errorf("pin ID does not match its kind and target"))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
+
+// ValidKind returns true if objects of the kind can be pinned
+func ValidKind(k Kind) bool {
+	for _, kind := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
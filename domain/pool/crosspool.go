@@ -0,0 +1,110 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"fmt"
+	"path"
+)
+
+// Cross-pool policies of the endpoints that the services of a pool export
+const (
+	// CrossPoolAllow lets services in any pool import the endpoints of the
+	// pool (default)
+	CrossPoolAllow = "allow"
+	// CrossPoolRestrict only lets services in other pools import the
+	// endpoints that a cross-pool import rule of the pool allows
+	CrossPoolRestrict = "restrict"
+)
+
+// CrossPoolImport is a rule that allows services in other pools to import the
+// endpoints of the pool
+type CrossPoolImport struct {
+	Application string   // Glob pattern of the applications that may be imported, eg "rabbitmq*"
+	Pools       []string // Pools whose services may import the applications, empty = every pool
+}
+
+// Matches returns true if the rule allows services in the pool to import the
+// application.
+func (r CrossPoolImport) Matches(poolID, application string) bool {
+	if ok, err := path.Match(r.Application, application); err != nil || !ok {
+		return false
+	}
+	if len(r.Pools) == 0 {
+		return true
+	}
+	for _, id := range r.Pools {
+		if id == poolID {
+			return true
+		}
+	}
+	return false
+}
+
+// CrossPoolFlow is a running import of an endpoint that a service in another
+// pool exports.
+type CrossPoolFlow struct {
+	TenantID          string
+	ServiceID         string // Service that imports the endpoint
+	ServiceName       string
+	PoolID            string
+	ExportServiceID   string // Service that exports the endpoint
+	ExportServiceName string
+	ExportPoolID      string
+	Application       string
+	Allowed           bool // Whether the policy of the export pool allows the flow
+}
+
+// GetCrossPoolPolicy returns the cross-pool policy of the pool, which
+// defaults to allow.
+func (p ResourcePool) GetCrossPoolPolicy() string {
+	if p.CrossPoolPolicy == "" {
+		return CrossPoolAllow
+	}
+	return p.CrossPoolPolicy
+}
+
+// AllowsImport returns true if services in the pool poolID may import the
+// application that a service of this pool exports.  Services may always
+// import the endpoints of their own pool.
+func (p ResourcePool) AllowsImport(poolID, application string) bool {
+	if poolID == p.ID || p.GetCrossPoolPolicy() != CrossPoolRestrict {
+		return true
+	}
+	for _, r := range p.CrossPoolImports {
+		if r.Matches(poolID, application) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCrossPoolImports returns an error if a rule has no application
+// pattern or a malformed one.
+func validateCrossPoolImports(rules []CrossPoolImport) error {
+	for _, r := range rules {
+		if r.Application == "" {
+			return fmt.Errorf("cross-pool import rule has no application")
+		}
+		if _, err := path.Match(r.Application, ""); err != nil {
+			return fmt.Errorf("cross-pool import rule has a malformed application pattern %q", r.Application)
+		}
+		for _, id := range r.Pools {
+			if id == "" {
+				return fmt.Errorf("cross-pool import rule for %q has an empty pool", r.Application)
+			}
+		}
+	}
+	return nil
+}
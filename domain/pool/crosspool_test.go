@@ -0,0 +1,64 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package pool
+
+import "testing"
+
+func TestResourcePool_AllowsImport(t *testing.T) {
+	p := ResourcePool{ID: "backend", Realm: "default"}
+	if !p.AllowsImport("frontend", "mariadb") {
+		t.Errorf("expected the allow policy to allow every import")
+	}
+
+	p.CrossPoolPolicy = CrossPoolRestrict
+	p.CrossPoolImports = []CrossPoolImport{
+		{Application: "rabbitmq*"},
+		{Application: "mariadb", Pools: []string{"reporting"}},
+	}
+	if err := p.ValidEntity(); err != nil {
+		t.Fatalf("expected a valid pool, got %s", err)
+	}
+
+	for _, tc := range []struct {
+		pool, application string
+		expected          bool
+	}{
+		{"backend", "mariadb", true},
+		{"frontend", "rabbitmq", true},
+		{"frontend", "rabbitmq_events", true},
+		{"frontend", "mariadb", false},
+		{"reporting", "mariadb", true},
+		{"frontend", "zookeeper", false},
+	} {
+		if actual := p.AllowsImport(tc.pool, tc.application); actual != tc.expected {
+			t.Errorf("pool %q application %q: expected %t, got %t", tc.pool, tc.application, tc.expected, actual)
+		}
+	}
+}
+
+func TestResourcePool_ValidateCrossPool(t *testing.T) {
+	for _, p := range []ResourcePool{
+		{ID: "backend", Realm: "default", CrossPoolPolicy: "deny"},
+		{ID: "backend", Realm: "default", CrossPoolImports: []CrossPoolImport{{}}},
+		{ID: "backend", Realm: "default", CrossPoolImports: []CrossPoolImport{{Application: "["}}},
+		{ID: "backend", Realm: "default", CrossPoolImports: []CrossPoolImport{{Application: "mariadb", Pools: []string{""}}}},
+	} {
+		if err := p.ValidEntity(); err == nil {
+			t.Errorf("expected an error for policy %q with rules %v", p.CrossPoolPolicy, p.CrossPoolImports)
+		}
+	}
+}
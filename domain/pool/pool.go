@@ -34,36 +34,109 @@ type VirtualIP struct {
 	BindInterface string
 }
 
+// Event types recorded on the virtual IP timeline of a pool
+const (
+	// EventVirtualIPUnreachable is recorded when a virtual IP first fails
+	// its reachability check.
+	EventVirtualIPUnreachable = "vip_unreachable"
+
+	// EventVirtualIPRecovered is recorded when an unreachable virtual IP
+	// answers again before it fails over.
+	EventVirtualIPRecovered = "vip_recovered"
+
+	// EventVirtualIPFailover is recorded when a virtual IP is moved off of
+	// the host that it is unreachable on.
+	EventVirtualIPFailover = "vip_failover"
+
+	// EventVirtualIPFailoverSkipped is recorded when an unreachable virtual
+	// IP is not moved because no other host can take it.
+	EventVirtualIPFailoverSkipped = "vip_failover_skipped"
+)
+
+// VirtualIPEvent is an entry on the virtual IP timeline of a pool
+type VirtualIPEvent struct {
+	Timestamp time.Time
+	Type      string
+	IPAddress string
+	HostID    string
+	Message   string
+}
+
+// Scheduling strategies for placing service instances on the hosts of a pool
+const (
+	// StrategySpread places instances on the hosts with the most free
+	// resources (default)
+	StrategySpread = "spread"
+	// StrategyBinpack fills hosts before placing instances on the next one
+	StrategyBinpack = "binpack"
+	// StrategyLabelAffinity only places instances on hosts whose labels
+	// satisfy the host affinity and anti-affinity of the service, and spreads
+	// them across the remaining hosts
+	StrategyLabelAffinity = "label-affinity"
+)
+
 type Permission uint
 
 const (
 	AdminAccess Permission = 1 << iota
 	DFSAccess
+	// OperatorAccess lets the hosts of the pool operate services without
+	// changing the configuration of the cluster
+	OperatorAccess
+	// ReadOnlyAccess lets the hosts of the pool make read-only master calls
+	ReadOnlyAccess
+	// InteractiveAccess lets the hosts of the pool open shells in, attach to
+	// and run actions in the containers of services.  Admin pools have it
+	// without the flag.
+	InteractiveAccess
 )
 
 // ResourcePool A collection of computing resources with optional quotas.
 type ResourcePool struct {
-	ID                string      // Unique identifier for resource pool, eg "default"
-	Realm             string      // The name of the realm where this pool resides
-	Description       string      // Description of the resource pool
-	VirtualIPs        []VirtualIP // All virtual IPs associated with a pool
-	CoreLimit         int         // Number of cores on the host available to serviced
-	MemoryLimit       uint64      // A quota on the amount (bytes) of RAM in the pool, 0 = unlimited
-	CoreCapacity      int         // Number of cores available as a sum of all cores on all hosts in the pool
-	MemoryCapacity    uint64      // Amount (bytes) of RAM available as a sum of all memory on all hosts in the pool
-	MemoryCommitment  uint64      // Amount (bytes) of RAM committed to services
-	ConnectionTimeout int         // Wait delay on service rescheduling when an outage is reported (milliseconds)
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
-	MonitoringProfile domain.MonitorProfile
-	Permissions       Permission
+	ID                     string            // Unique identifier for resource pool, eg "default"
+	Realm                  string            // The name of the realm where this pool resides
+	Description            string            // Description of the resource pool
+	VirtualIPs             []VirtualIP       // All virtual IPs associated with a pool
+	CoreLimit              int               // Number of cores on the host available to serviced
+	MemoryLimit            uint64            // A quota on the amount (bytes) of RAM in the pool, 0 = unlimited
+	CoreCapacity           int               // Number of cores available as a sum of all cores on all hosts in the pool
+	MemoryCapacity         uint64            // Amount (bytes) of RAM available as a sum of all memory on all hosts in the pool
+	MemoryCommitment       uint64            // Amount (bytes) of RAM committed to services
+	ConnectionTimeout      int               // Wait delay on service rescheduling when an outage is reported (milliseconds)
+	SchedulingStrategy     string            // Placement strategy of service instances on hosts (spread, binpack, label-affinity)
+	LogRetentionDays       int               // Days to keep the application logs of the pool's services, 0 = cluster default
+	MetricRetentionDays    int               // Days to keep the metrics of the pool's services, 0 = cluster default
+	VirtualIPCheckInterval int               // Interval between reachability checks of the virtual IPs (milliseconds), 0 = disabled
+	MaxDFSOperations       int               // Snapshots, rollbacks, backups and image pushes of the pool's applications that run at once, 0 = unlimited
+	CrossPoolPolicy        string            // Whether services in other pools may import the pool's endpoints (allow, restrict)
+	CrossPoolImports       []CrossPoolImport // Endpoints that services in other pools may import under the restrict policy
+	DelegateProfile        string            // Delegate configuration profile that is applied to the hosts of the pool, none if empty
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	MonitoringProfile      domain.MonitorProfile
+	Permissions            Permission
 	datastore.VersionedEntity
 }
 
+// GetSchedulingStrategy returns the scheduling strategy of the pool, which
+// defaults to spread.
+func (p ResourcePool) GetSchedulingStrategy() string {
+	if p.SchedulingStrategy == "" {
+		return StrategySpread
+	}
+	return p.SchedulingStrategy
+}
+
 func (p ResourcePool) GetConnectionTimeout() time.Duration {
 	return time.Duration(p.ConnectionTimeout) * time.Millisecond
 }
 
+// GetVirtualIPCheckInterval returns the interval between reachability checks
+// of the virtual IPs of the pool, or 0 if they are not checked.
+func (p ResourcePool) GetVirtualIPCheckInterval() time.Duration {
+	return time.Duration(p.VirtualIPCheckInterval) * time.Millisecond
+}
+
 // PoolIPs type for IP resources available in a ResourcePool
 type PoolIPs struct {
 	PoolID     string
@@ -125,6 +198,18 @@ func (a *ResourcePool) Equals(b *ResourcePool) bool {
 	if a.MemoryCommitment != b.MemoryCommitment {
 		return false
 	}
+	if a.SchedulingStrategy != b.SchedulingStrategy {
+		return false
+	}
+	if a.LogRetentionDays != b.LogRetentionDays {
+		return false
+	}
+	if a.MetricRetentionDays != b.MetricRetentionDays {
+		return false
+	}
+	if a.VirtualIPCheckInterval != b.VirtualIPCheckInterval {
+		return false
+	}
 	if a.CreatedAt.Unix() != b.CreatedAt.Unix() {
 		return false
 	}
@@ -154,6 +239,18 @@ func (a *ResourcePool) HasAdminAccess() bool {
 	return a.Permissions&AdminAccess != 0
 }
 
+func (a *ResourcePool) HasOperatorAccess() bool {
+	return a.Permissions&OperatorAccess != 0
+}
+
+func (a *ResourcePool) HasReadOnlyAccess() bool {
+	return a.Permissions&ReadOnlyAccess != 0
+}
+
+func (a *ResourcePool) HasInteractiveAccess() bool {
+	return a.Permissions&(AdminAccess|InteractiveAccess) != 0
+}
+
 // GetType returns a ResourcePool's type or kind, can be used to get
 // the string value of ResourcePool's type without a ResourcePool instance.
 // It returns the kind as a string.
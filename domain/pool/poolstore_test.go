@@ -107,6 +107,37 @@ func (s *S) Test_ValidateConnectionTimeout(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *S) Test_ValidateRetention(c *C) {
+	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
+	pool := New("Test_GetPools1")
+	pool.Realm = "test_realm1"
+	pool.LogRetentionDays = -1
+	err := s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(strings.Contains(err.Error(), "log retention cannot be less than 0 days"), Equals, true)
+
+	pool.LogRetentionDays = 3
+	pool.MetricRetentionDays = -1
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(strings.Contains(err.Error(), "metric retention cannot be less than 0 days"), Equals, true)
+
+	pool.MetricRetentionDays = 7
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, IsNil)
+}
+
+func (s *S) Test_ValidateVirtualIPCheckInterval(c *C) {
+	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
+	pool := New("Test_GetPools1")
+	pool.Realm = "test_realm1"
+	pool.VirtualIPCheckInterval = -1
+	err := s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(strings.Contains(err.Error(), "virtual ip check interval cannot be less than 0"), Equals, true)
+
+	pool.VirtualIPCheckInterval = 10000
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, IsNil)
+}
+
 func (s *S) Test_GetPools(t *C) {
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools2"))
@@ -211,3 +242,16 @@ func (s *S) Test_HasVirtualIP(t *C) {
 		t.Errorf("Did not find pool!")
 	}
 }
+
+func (s *S) Test_ValidateMaxDFSOperations(c *C) {
+	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
+	pool := New("Test_GetPools1")
+	pool.Realm = "test_realm1"
+	pool.MaxDFSOperations = -1
+	err := s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(strings.Contains(err.Error(), "max dfs operations cannot be less than 0"), Equals, true)
+
+	pool.MaxDFSOperations = 2
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, IsNil)
+}
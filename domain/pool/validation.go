@@ -37,6 +37,34 @@ func (p *ResourcePool) ValidEntity() error {
 		violations.Add(validation.NewViolation(fmt.Sprintf("connection timeout cannot be less than 0")))
 	}
 
+	if p.SchedulingStrategy != "" {
+		violations.Add(validation.StringIn(p.SchedulingStrategy, StrategySpread, StrategyBinpack, StrategyLabelAffinity))
+	}
+
+	if p.LogRetentionDays < 0 {
+		violations.Add(validation.NewViolation("log retention cannot be less than 0 days"))
+	}
+
+	if p.MetricRetentionDays < 0 {
+		violations.Add(validation.NewViolation("metric retention cannot be less than 0 days"))
+	}
+
+	if p.VirtualIPCheckInterval < 0 {
+		violations.Add(validation.NewViolation("virtual ip check interval cannot be less than 0"))
+	}
+
+	if p.MaxDFSOperations < 0 {
+		violations.Add(validation.NewViolation("max dfs operations cannot be less than 0"))
+	}
+
+	if p.CrossPoolPolicy != "" {
+		violations.Add(validation.StringIn(p.CrossPoolPolicy, CrossPoolAllow, CrossPoolRestrict))
+	}
+
+	if err := validateCrossPoolImports(p.CrossPoolImports); err != nil {
+		violations.Add(err)
+	}
+
 	if len(violations.Errors) > 0 {
 		return violations
 	}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidRegistryCredential is returned when the value of a secret is not
+// a registry credential
+var ErrInvalidRegistryCredential = errors.New("secret is not a registry credential: expected USERNAME:PASSWORD or a JSON object with username and password")
+
+// RegistryCredential is the value of a secret that a service or template
+// references to pull images from a private docker registry.  The value is
+// either USERNAME:PASSWORD or a JSON object in the format of a docker auth
+// configuration.
+type RegistryCredential struct {
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	Email         string `json:"email,omitempty"`
+	ServerAddress string `json:"serveraddress,omitempty"`
+}
+
+// ParseRegistryCredential parses the decrypted value of a registry credential
+// secret
+func ParseRegistryCredential(value string) (*RegistryCredential, error) {
+	value = strings.TrimSpace(value)
+	cred := &RegistryCredential{}
+	if strings.HasPrefix(value, "{") {
+		if err := json.Unmarshal([]byte(value), cred); err != nil {
+			return nil, ErrInvalidRegistryCredential
+		}
+	} else if parts := strings.SplitN(value, ":", 2); len(parts) == 2 {
+		cred.Username, cred.Password = parts[0], parts[1]
+	}
+	if cred.Username == "" || cred.Password == "" {
+		return nil, ErrInvalidRegistryCredential
+	}
+	return cred, nil
+}
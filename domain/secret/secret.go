@@ -0,0 +1,52 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Secret is a named value that config files reference with {{secret "NAME"}}.
+// The value is encrypted with the master's public key and is only decrypted
+// when a container that references the secret starts.
+type Secret struct {
+	Name        string // Unique name of the secret
+	Description string // Description of the secret
+	Value       []byte // Encrypted value of the secret
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a new secret with an encrypted value
+func New(name string, value []byte) *Secret {
+	return &Secret{Name: name, Value: value}
+}
+
+// GetType returns the kind of the secret entity
+func GetType() string {
+	return kind
+}
+
+// GetID returns the name of the secret
+func (s *Secret) GetID() string {
+	return s.Name
+}
+
+// GetType returns the kind of the secret entity
+func (s *Secret) GetType() string {
+	return GetType()
+}
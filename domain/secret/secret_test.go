@@ -0,0 +1,95 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package secret
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	content := `user={{.Name}}
+password={{secret "db.password"}}
+token={{ secret "api-token" }}
+again={{secret "db.password"}}`
+	expected := []string{"db.password", "api-token"}
+	if actual := References(content); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+	if actual := References("no secrets here"); len(actual) != 0 {
+		t.Errorf("Expected no references, got %v", actual)
+	}
+	if actual := References(Reference("api-token")); !reflect.DeepEqual(actual, []string{"api-token"}) {
+		t.Errorf("Expected the reference to be found, got %v", actual)
+	}
+}
+
+func TestRender(t *testing.T) {
+	content := `password={{secret "db.password"}} token={{ secret "api-token" }}`
+	values := map[string]string{"db.password": "hunter2", "api-token": "abc{{123}}"}
+
+	actual, err := Render(content, values)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := "password=hunter2 token=abc{{123}}"; actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+
+	if _, err := Render(`{{secret "missing"}}`, values); err == nil {
+		t.Errorf("Expected an error rendering a missing secret")
+	}
+}
+
+func TestSecret_ValidEntity(t *testing.T) {
+	if err := New("db.password", []byte{1}).ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	invalid := []*Secret{
+		New("", []byte{1}),
+		New("db password", []byte{1}),
+		New(`db"password`, []byte{1}),
+		New("db.password", nil),
+	}
+	for i, s := range invalid {
+		if err := s.ValidEntity(); err == nil {
+			t.Errorf("Test %d: expected a validation error", i)
+		}
+	}
+}
+
+func TestParseRegistryCredential(t *testing.T) {
+	expected := &RegistryCredential{Username: "deploy", Password: "pa:ss"}
+	if actual, err := ParseRegistryCredential("deploy:pa:ss\n"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	} else if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, actual)
+	}
+
+	expected = &RegistryCredential{Username: "deploy", Password: "secret", ServerAddress: "registry.example.com"}
+	if actual, err := ParseRegistryCredential(`{"username": "deploy", "password": "secret", "serveraddress": "registry.example.com"}`); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	} else if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, actual)
+	}
+
+	for _, value := range []string{"", "deploy", ":secret", `{"username": "deploy"}`, `{"username"`} {
+		if _, err := ParseRegistryCredential(value); err != ErrInvalidRegistryCredential {
+			t.Errorf("Expected %q to be invalid, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a secret store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with secret persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetSecrets returns all secrets
+	GetSecrets(ctx datastore.Context) ([]Secret, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetSecrets returns all secrets
+func (s *storeImpl) GetSecrets(ctx datastore.Context) ([]Secret, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SecretStore.GetSecrets"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:Name")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting secrets
+func Key(name string) datastore.Key {
+	name = strings.TrimSpace(name)
+	return datastore.NewKey(kind, name)
+}
+
+func convert(results datastore.Results) ([]Secret, error) {
+	secrets := make([]Secret, results.Len())
+	for idx := range secrets {
+		if err := results.Get(idx, &secrets[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	namePattern      = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	referencePattern = regexp.MustCompile(`{{-?\s*secret\s+"([A-Za-z0-9._-]+)"\s*-?}}`)
+)
+
+// ValidName returns true if name may be used as the name of a secret
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Reference returns the template action that refers to a secret.  Service
+// templates evaluate {{secret "NAME"}} to itself so that the value is only
+// rendered inside the container.
+func Reference(name string) string {
+	return fmt.Sprintf("{{secret %q}}", name)
+}
+
+// References returns the distinct names of the secrets referenced in a
+// template, in the order they first appear.
+func References(content string) []string {
+	seen := make(map[string]struct{})
+	names := []string{}
+	for _, match := range referencePattern.FindAllStringSubmatch(content, -1) {
+		if _, ok := seen[match[1]]; !ok {
+			seen[match[1]] = struct{}{}
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Render replaces the secret references in content with their values.
+// Returns an error if a referenced secret has no value.
+func Render(content string, values map[string]string) (string, error) {
+	var err error
+	result := referencePattern.ReplaceAllStringFunc(content, func(ref string) string {
+		name := referencePattern.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("secret %s not found", name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
@@ -36,6 +36,8 @@ type PublicEndpoint struct {
 	VHostName   string `json:",omitempty"`
 	PortAddress string `json:",omitempty"`
 	Enabled     bool
+	Certificate string `json:",omitempty"` // name of the uploaded certificate of a vhost
+	ServerName  string `json:",omitempty"` // TLS server name that routes connections to a shared port
 }
 
 // BaseIPAssignment is a minimal service object that describes a service endpoint
@@ -75,3 +77,35 @@ type Config struct {
 	ID       string
 	Filename string
 }
+
+// Config file drift states
+const (
+	ConfigInSync   = "in sync"
+	ConfigModified = "modified"
+	ConfigMissing  = "missing"
+	ConfigUnknown  = "unknown"
+)
+
+// ConfigDrift describes whether a config file inside the container of a
+// running service instance still matches the file rendered for it
+type ConfigDrift struct {
+	InstanceID int
+	HostID     string
+	Filename   string
+	Status     string
+	Error      string `json:",omitempty"` // why the file could not be checked
+}
+
+// ServicePriority is the effective priority class of a service and the
+// batches in which it is started and stopped in an emergency shutdown of its
+// deployment.  Batches are numbered from 1 and run in order.
+type ServicePriority struct {
+	ServiceID              string
+	Name                   string
+	DeploymentID           string
+	PriorityClass          string
+	StartLevel             uint
+	EmergencyShutdownLevel uint
+	StartBatch             int
+	ShutdownBatch          int
+}
@@ -22,6 +22,8 @@ import (
 	"text/template"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 func parent(gs GetService) func(s *runtimeContext) (*runtimeContext, error) {
@@ -210,6 +212,7 @@ func (service *Service) evaluateTemplate(gs GetService, fc FindChildService, ins
 		"plus":          plus,
 		"uintToInt":     uintToInt,
 		"each":          each,
+		"secret":        secret.Reference,
 	}
 
 	// parse the template
@@ -324,7 +327,8 @@ func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildServi
 	return
 }
 
-// EvaluateHealthCheckTemplate parses and evals the Script field for each HealthCheck.
+// EvaluateHealthCheckTemplate parses and evals the Script, Address and Command
+// fields for each HealthCheck.
 func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
 	log.WithFields(log.Fields{
 		"servicename": service.Name,
@@ -339,9 +343,54 @@ func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildS
 		}
 		if result != "" {
 			healthcheck.Script = result
-			service.HealthChecks[key] = healthcheck
 		}
+
+		// probes that do not use a shell may template their address and
+		// command
+		err, result = service.evaluateTemplate(gs, fc, instanceID, healthcheck.Address)
+		if err != nil {
+			return err
+		}
+		if result != "" {
+			healthcheck.Address = result
+		}
+		if len(healthcheck.Command) > 0 {
+			command := make([]string, len(healthcheck.Command))
+			for i, arg := range healthcheck.Command {
+				err, result = service.evaluateTemplate(gs, fc, instanceID, arg)
+				if err != nil {
+					return err
+				}
+				command[i] = result
+			}
+			healthcheck.Command = command
+		}
+		service.HealthChecks[key] = healthcheck
+	}
+	return
+}
+
+// EvaluateReadinessGatesTemplate parses and evals the ServiceID and URL
+// fields for each ReadinessGate.
+func (service *Service) EvaluateReadinessGatesTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
+	if len(service.ReadinessGates) == 0 {
+		return nil
+	}
+	gates := make([]servicedefinition.ReadinessGate, len(service.ReadinessGates))
+	for i, gate := range service.ReadinessGates {
+		err, result := service.evaluateTemplate(gs, fc, instanceID, gate.ServiceID)
+		if err != nil {
+			return err
+		}
+		gate.ServiceID = result
+		err, result = service.evaluateTemplate(gs, fc, instanceID, gate.URL)
+		if err != nil {
+			return err
+		}
+		gate.URL = result
+		gates[i] = gate
 	}
+	service.ReadinessGates = gates
 	return
 }
 
@@ -499,6 +548,10 @@ func (service *Service) Evaluate(getSvc GetService, findChild FindChildService,
 		plog.WithError(err).Error()
 		return err
 	}
+	if err = service.EvaluateReadinessGatesTemplate(getSvc, findChild, instanceID); err != nil {
+		plog.WithError(err).Error()
+		return err
+	}
 	if err = service.EvaluateEnvironmentTemplate(getSvc, findChild, instanceID); err != nil {
 		plog.WithError(err).Error()
 		return err
@@ -17,6 +17,7 @@ package service_test
 
 import (
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	. "gopkg.in/check.v1"
 )
 
@@ -40,3 +41,20 @@ func (s *ServiceDomainUnitTestSuite) TestRound(c *C) {
 		c.Assert(service.Round(test.value), Equals, test.expected)
 	}
 }
+
+func (s *ServiceDomainUnitTestSuite) TestEvaluateConfigFilesTemplate_Secret(c *C) {
+	svc := &service.Service{
+		Name: "db",
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/db.conf": {
+				Filename: "/etc/db.conf",
+				Content:  `name={{.Name}} password={{secret "db.password"}}`,
+			},
+		},
+	}
+
+	// secrets are left for the container to render
+	err := svc.EvaluateConfigFilesTemplate(nil, nil, 0)
+	c.Assert(err, IsNil)
+	c.Assert(svc.ConfigFiles["/etc/db.conf"].Content, Equals, `name=db password={{secret "db.password"}}`)
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+// ImagePin is the image digest that the instances of a service run
+type ImagePin struct {
+	ServiceID string
+	Name      string
+	ImageID   string // the image of the service as it is tagged in the registry
+	Digest    string // the digest the instances run; empty if not pinned
+	Current   string // the digest that ImageID is currently tagged to
+}
+
+// Stale returns whether the image of the service has been tagged to a
+// different digest since the service was pinned.
+func (pin ImagePin) Stale() bool {
+	return pin.Digest != "" && pin.Digest != pin.Current
+}
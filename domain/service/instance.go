@@ -35,6 +35,7 @@ const (
 	StatePendingRestart    InstanceCurrentState = "pending_restart"
 	StateEmergencyStopping InstanceCurrentState = "emergency_stopping"
 	StateEmergencyStopped  InstanceCurrentState = "emergency_stopped"
+	StateStartFailed       InstanceCurrentState = "start_failed"
 )
 
 // Usage describes the current, max, and avg values of an instance
@@ -56,6 +57,9 @@ type Instance struct {
 	DesiredState  DesiredState
 	CurrentState  InstanceCurrentState
 	HealthStatus  map[string]health.Status
+	HealthProbes  map[string]health.HealthStatus `json:",omitempty"` // Last result of each health check
+	Restarts      int                            // Restarts triggered by failing health checks
+	Environment   []string                       `json:",omitempty"` // Added to the environment of the instance when it starts
 	RAMCommitment int64
 	RAMThreshold  uint
 	MemoryUsage   Usage
@@ -64,12 +68,22 @@ type Instance struct {
 	Terminated    time.Time
 }
 
+// InstanceHistory describes a single run of a service instance on a host.
+// Stopped is zero while the instance is still scheduled.
+type InstanceHistory struct {
+	InstanceID int
+	HostID     string
+	Started    time.Time
+	Stopped    time.Time
+}
+
 // StrategyInstance collects service strategy information about a service
 // instance.
 type StrategyInstance struct {
 	HostID        string
 	ServiceID     string
 	CPUCommitment int
+	CPURequest    float64
 	RAMCommitment uint64
 	RAMThreshold  uint
 	HostPolicy    servicedefinition.HostPolicy
@@ -0,0 +1,38 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+)
+
+// LogQuery selects the aggregated application log messages of a service
+type LogQuery struct {
+	ServiceID  string
+	InstanceID int       // negative for all instances
+	Since      time.Time // only messages logged after this time
+	Limit      int       // maximum number of messages to return
+}
+
+// LogMessage is a single line of an application log forwarded to logstash
+type LogMessage struct {
+	Timestamp   time.Time
+	ServiceID   string
+	InstanceID  string
+	HostID      string
+	ContainerID string
+	File        string
+	Offset      uint64
+	Message     string
+}
@@ -18,8 +18,10 @@ import (
 )
 
 type Query struct {
-	Name    string
-	Since   time.Duration
-	Tags    []string
-	Tenants bool
+	Name         string
+	Since        time.Duration
+	ChangedSince time.Time // only services updated at or after this time
+	Tags         []string
+	Tenants      bool
+	PoolID       string // only services in this resource pool
 }
@@ -0,0 +1,45 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/validation"
+)
+
+// Schema returns the JSON Schema of services
+func Schema() *validation.Schema {
+	s := validation.NewSchema("Control Center Service", Service{})
+	def := s.Definition(Service{})
+	def.Property("DesiredState").SetEnum(int(SVCRun), int(SVCStop), int(SVCPause))
+	def.Property("Instances").SetMinimum(0)
+	def.Property("StartTimeout").SetMinimum(0)
+	def.Property("CPURequest").SetMinimum(0)
+	def.Property("CPULimit").SetMinimum(0)
+	def.Property("ImagePullPolicy").SetEnum("", commons.PullAlways, commons.PullIfNotPresent, commons.PullNever)
+	def.Property("DockerLogDriver").SetEnum("", commons.LogDriverJSONFile, commons.LogDriverJournald, commons.LogDriverFluentd)
+	def.Property("PriorityClass").SetEnum("", servicedefinition.PriorityCritical, servicedefinition.PriorityHigh,
+		servicedefinition.PriorityNormal, servicedefinition.PriorityLow)
+	if ep := s.Definition(ServiceEndpoint{}); ep != nil {
+		ep.Property("Name").SetMinLength(1)
+		ep.Property("Purpose").SetEnum("export", "import", "import_all")
+		ep.Property("Protocol").SetEnum("", "tcp", "udp")
+	}
+	if mm := s.Definition(MaintenanceMode{}); mm != nil {
+		mm.Property("RetryAfter").SetMinimum(0)
+	}
+	servicedefinition.ConstrainSchema(s)
+	return s
+}
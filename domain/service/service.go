@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -240,18 +241,38 @@ type Service struct {
 	LogConfigs        []servicedefinition.LogConfig
 	Snapshot          servicedefinition.SnapshotCommands
 	DisableShell      bool
+	InteractivePools  []string // Pools whose hosts may open shells in and attach to the service and its children; the parent's pools apply if empty
 	Runs              map[string]string // FIXME: This field is deprecated. Remove when possible.
 	Commands          map[string]domain.Command
 	RAMCommitment     utils.EngNotation
 	RAMThreshold      uint
 	CPUCommitment     uint64
+	CPURequest        float64           // CPU cores reserved per instance at scheduling time
+	CPULimit          float64           // Maximum CPU cores an instance may use
+	DockerLogDriver   string            // Docker log driver of the instances; the delegate's driver is used if empty
+	DockerLogConfig   map[string]string // Docker log options for DockerLogDriver
+	HostAffinity      map[string]string // Host labels required to run instances in label-affinity pools
+	HostAntiAffinity  map[string]string // Host labels that exclude a host from running instances in label-affinity pools
+	NodeSelector      map[string]string // Host labels that every host running instances must have, in any pool
 	Actions           map[string]string
-	HealthChecks      map[string]health.HealthCheck // A health check for the service.
-	Prereqs           []domain.Prereq               // Optional list of scripts that must be successfully run before kicking off the service command.
+	HealthChecks      map[string]health.HealthCheck     // A health check for the service.
+	Prereqs           []domain.Prereq                   // Optional list of scripts that must be successfully run before kicking off the service command.
+	ReadinessGates    []servicedefinition.ReadinessGate // Optional external conditions that must pass before instances receive traffic
 	MonitoringProfile domain.MonitorProfile
 	MemoryLimit       float64
 	CPUShares         int64
 	PIDFile           string
+	// StartTimeout is the number of seconds an instance may take to pull its
+	// image and start its container before the delegate marks the start as
+	// failed.  A value of 0 waits indefinitely.
+	StartTimeout int
+	// ImagePullPolicy is when the delegate pulls the image of an instance
+	// before starting it: Always, IfNotPresent or Never.  The delegate's
+	// policy is used if empty.
+	ImagePullPolicy string
+	// Tracing injects the OpenTelemetry environment into the instances and
+	// forwards their spans to the tracing collector of the cluster.
+	Tracing bool
 	// StartLevel represents the order in which services are started and stopped
 	// in normal operations.  All services of a given level start before any services
 	// at higher levels.  Stopping services occurs in the reverse order.  Services
@@ -265,12 +286,46 @@ type Service struct {
 	// are stopped after services with a defined EmergencyShutdownLevel, in the normal order
 	// dictated by their StartLevel.
 	EmergencyShutdownLevel uint
+	// PriorityClass orders the service against the services of other
+	// priority classes: emergency shutdowns and host drains stop the lowest
+	// class first, ahead of the levels above, and delegates start the
+	// highest class first.  The service is in the normal class if empty.
+	PriorityClass string
 	// EmergencyShutdown is a flag that indicates whether this service has been shutdown due
 	// to an emergency (low-storage) situation.  Services with this flag set can not be started
 	EmergencyShutdown bool
+	// RegistryCredential is the name of the secret that holds the credentials
+	// of the registry that ImageID is pulled from when it is deployed.
+	RegistryCredential string
+	// ImageDigest pins the instances to the image with this digest, which is
+	// resolved from ImageID when the service is deployed.  Instances run
+	// ImageID as it is currently tagged if empty.
+	ImageDigest string
+	// EnvironmentOverrides sets variables in the environment of the
+	// instances, replacing the variables of the same name in Environment,
+	// so that a variable can be changed without editing the service
+	// definition.
+	EnvironmentOverrides map[string]string
+	// Maintenance is set on a tenant that is in maintenance mode.  The http
+	// public endpoints of the tenant serve a maintenance page instead of the
+	// application until it is cleared.
+	Maintenance *MaintenanceMode `json:",omitempty"`
 	datastore.VersionedEntity
 }
 
+// DefaultMaintenanceRetryAfter is the number of seconds that clients of a
+// tenant in maintenance mode are asked to wait before retrying, if the mode
+// does not say otherwise.
+const DefaultMaintenanceRetryAfter = 300
+
+// MaintenanceMode describes why and since when a tenant is in maintenance
+// mode
+type MaintenanceMode struct {
+	Message    string    // Shown on the maintenance page
+	RetryAfter int       // Seconds that clients are asked to wait before retrying
+	Since      time.Time // When the tenant entered maintenance mode
+}
+
 //ServiceEndpoint endpoint exported or imported by a service
 type ServiceEndpoint struct {
 	Name                string // Human readable name of the endpoint. Unique per service definition
@@ -366,6 +421,7 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.InstanceLimits = sd.Instances
 	svc.ChangeOptions = sd.ChangeOptions
 	svc.ImageID = sd.ImageID
+	svc.RegistryCredential = sd.RegistryCredential
 	svc.PoolID = poolID
 	svc.DesiredState = desiredState
 	svc.Launch = sd.Launch
@@ -384,15 +440,27 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.RAMCommitment = sd.RAMCommitment
 	svc.RAMThreshold = sd.RAMThreshold
 	svc.CPUCommitment = sd.CPUCommitment
+	svc.CPURequest = sd.CPURequest
+	svc.CPULimit = sd.CPULimit
+	svc.DockerLogDriver = sd.DockerLogDriver
+	svc.DockerLogConfig = sd.DockerLogConfig
+	svc.HostAffinity = sd.HostAffinity
+	svc.HostAntiAffinity = sd.HostAntiAffinity
+	svc.NodeSelector = sd.NodeSelector
 	svc.DisableShell = sd.DisableShell
 	svc.Runs = sd.Runs
 	svc.Commands = sd.Commands
 	svc.Actions = sd.Actions
 	svc.HealthChecks = sd.HealthChecks
 	svc.Prereqs = sd.Prereqs
+	svc.ReadinessGates = sd.ReadinessGates
 	svc.PIDFile = sd.PIDFile
+	svc.StartTimeout = sd.StartTimeout
+	svc.ImagePullPolicy = sd.ImagePullPolicy
+	svc.Tracing = sd.Tracing
 	svc.StartLevel = sd.StartLevel
 	svc.EmergencyShutdownLevel = sd.EmergencyShutdownLevel
+	svc.PriorityClass = sd.PriorityClass
 
 	svc.Endpoints = make([]ServiceEndpoint, 0)
 	for _, ep := range sd.Endpoints {
@@ -545,12 +613,15 @@ func (s *Service) AddVirtualHost(application, vhostName string, isEnabled bool)
 			if ep.Application == application && ep.Purpose == "export" {
 				_vhostName := strings.ToLower(vhostName)
 				vhosts := make([]servicedefinition.VHost, 0)
+				appProtocol, certificate := "", ""
 				for _, vhost := range ep.VHostList {
 					if strings.ToLower(vhost.Name) != _vhostName {
 						vhosts = append(vhosts, vhost)
+					} else {
+						appProtocol, certificate = vhost.AppProtocol, vhost.Certificate
 					}
 				}
-				vhost := &servicedefinition.VHost{Name: _vhostName, Enabled: isEnabled}
+				vhost := &servicedefinition.VHost{Name: _vhostName, Enabled: isEnabled, AppProtocol: appProtocol, Certificate: certificate}
 				ep.VHostList = append(vhosts, *vhost)
 				return vhost, nil
 			}
@@ -592,12 +663,15 @@ func (s *Service) AddPort(application string, portAddr string, usetls bool, prot
 			if ep.Application == application && ep.Purpose == "export" {
 				var ports = make([]servicedefinition.Port, 0)
 				portAddrLower := strings.ToLower(portAddr)
+				appProtocol, serverName := "", ""
 				for _, port := range ep.PortList {
 					if strings.ToLower(port.PortAddr) != portAddrLower {
 						ports = append(ports, port)
+					} else {
+						appProtocol, serverName = port.AppProtocol, port.ServerName
 					}
 				}
-				port := &servicedefinition.Port{PortAddr: portAddr, Enabled: isEnabled, UseTLS: usetls, Protocol: protocol}
+				port := &servicedefinition.Port{PortAddr: portAddr, Enabled: isEnabled, UseTLS: usetls, Protocol: protocol, AppProtocol: appProtocol, ServerName: serverName}
 				ep.PortList = append(ports, *port)
 				return port, nil
 			}
@@ -741,6 +815,22 @@ func (s *Service) EnableVirtualHost(application, vhostName string, enable bool)
 	return nil
 }
 
+// SetVirtualHostCertificate sets the name of the uploaded certificate that a
+// virtual host of the service presents.  An empty name presents the default
+// certificate.
+func (s *Service) SetVirtualHostCertificate(vhostName, certificate string) error {
+	_vhostName := strings.ToLower(vhostName)
+	for _, ep := range s.GetServiceVHosts() {
+		for i, vhost := range ep.VHostList {
+			if strings.ToLower(vhost.Name) == _vhostName {
+				ep.VHostList[i].Certificate = certificate
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("vhost %s not found in service %s:%s", vhostName, s.ID, s.Name)
+}
+
 // RemoveVirtualHost Remove a virtual host for given service
 func (s *Service) RemoveVirtualHost(application, vhostName string) error {
 	if s.Endpoints != nil {
@@ -855,6 +945,36 @@ func (a *Service) GetType() string {
 	return GetType()
 }
 
+// ContainerEnvironment returns the environment of the instances: the
+// variables of Environment, with the variables of EnvironmentOverrides
+// replacing those of the same name and the others added in order of name.
+func (s *Service) ContainerEnvironment() []string {
+	if len(s.EnvironmentOverrides) == 0 {
+		return s.Environment
+	}
+	env := make([]string, 0, len(s.Environment)+len(s.EnvironmentOverrides))
+	replaced := make(map[string]bool)
+	for _, v := range s.Environment {
+		key := strings.SplitN(v, "=", 2)[0]
+		if value, ok := s.EnvironmentOverrides[key]; ok {
+			v = key + "=" + value
+			replaced[key] = true
+		}
+		env = append(env, v)
+	}
+	keys := make([]string, 0, len(s.EnvironmentOverrides))
+	for key := range s.EnvironmentOverrides {
+		if !replaced[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+s.EnvironmentOverrides[key])
+	}
+	return env
+}
+
 //Equals are they the same
 func (s *Service) Equals(b *Service) bool {
 	if s.ID != b.ID {
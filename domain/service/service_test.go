@@ -61,6 +61,28 @@ func (s *S) TestAddVirtualHost(t *C) {
 	t.Assert(svc.Endpoints[0].VHostList[1].Enabled, Equals, false)
 }
 
+func (s *S) TestSetVirtualHostCertificate(t *C) {
+	svc := Service{
+		Endpoints: []ServiceEndpoint{
+			BuildServiceEndpoint(
+				servicedefinition.EndpointDefinition{
+					Purpose:     "export",
+					Application: "server",
+					VHostList:   []servicedefinition.VHost{{Name: "name0", Enabled: true}},
+				}),
+		},
+	}
+
+	t.Assert(svc.SetVirtualHostCertificate("NAME0", "name0"), IsNil)
+	t.Assert(svc.Endpoints[0].VHostList[0].Certificate, Equals, "name0")
+	t.Assert(svc.SetVirtualHostCertificate("name1", "name1"), NotNil)
+
+	// the certificate is kept when the vhost is added again
+	_, err := svc.AddVirtualHost("server", "name0", false)
+	t.Assert(err, IsNil)
+	t.Assert(svc.Endpoints[0].VHostList[0].Certificate, Equals, "name0")
+}
+
 func (s *S) TestRemoveVirtualHost(t *C) {
 	svc := Service{
 		Endpoints: []ServiceEndpoint{
@@ -47,6 +47,9 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 	pidFile := "pidfile"
 	startLevel := uint(1234)
 	shutdownLevel := uint(4567)
+	logDriver := "journald"
+	logConfig := map[string]string{"tag": "svc"}
+	pullPolicy := "Never"
 
 	sd := servicedefinition.ServiceDefinition{
 		Name:        name,
@@ -83,6 +86,10 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 		PIDFile:       pidFile,
 		StartLevel:    startLevel,
 		EmergencyShutdownLevel: shutdownLevel,
+		DockerLogDriver:        logDriver,
+		DockerLogConfig:        logConfig,
+		ImagePullPolicy:        pullPolicy,
+		PriorityClass:          servicedefinition.PriorityCritical,
 	}
 	actual, err := service.BuildService(sd, "", "", 0, "")
 
@@ -109,4 +116,30 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 	t.Check(actual.PIDFile, Equals, pidFile)
 	t.Check(actual.StartLevel, Equals, startLevel)
 	t.Check(actual.EmergencyShutdownLevel, Equals, shutdownLevel)
+	t.Check(actual.DockerLogDriver, Equals, logDriver)
+	t.Check(actual.DockerLogConfig, DeepEquals, logConfig)
+	t.Check(actual.ImagePullPolicy, Equals, pullPolicy)
+	t.Check(actual.PriorityClass, Equals, servicedefinition.PriorityCritical)
+}
+
+// Test that the environment overrides replace or add variables
+func (s *ServiceDomainUnitTestSuite) TestContainerEnvironment(t *C) {
+	svc := service.Service{Environment: []string{"LOG_LEVEL=info", "HOME=/root", "EMPTY"}}
+	t.Check(svc.ContainerEnvironment(), DeepEquals, svc.Environment)
+
+	svc.EnvironmentOverrides = map[string]string{"TRACE": "1", "LOG_LEVEL": "debug", "EMPTY": "", "A": "b=c"}
+	t.Check(svc.ContainerEnvironment(), DeepEquals, []string{"LOG_LEVEL=debug", "HOME=/root", "EMPTY=", "A=b=c", "TRACE=1"})
+	t.Check(svc.Environment, DeepEquals, []string{"LOG_LEVEL=info", "HOME=/root", "EMPTY"})
+}
+
+// Test that environment overrides must have valid names
+func (s *ServiceDomainUnitTestSuite) TestInvalidEnvironmentOverrides(t *C) {
+	svc := service.Service{ID: "id", Name: "name", PoolID: "pool", Launch: "auto"}
+	svc.EnvironmentOverrides = map[string]string{"LOG_LEVEL": "debug"}
+	t.Check(svc.ValidEntity(), IsNil)
+
+	for _, key := range []string{"", "A=B", "A B"} {
+		svc.EnvironmentOverrides = map[string]string{key: "value"}
+		t.Check(svc.ValidEntity(), NotNil, Commentf("key %q", key))
+	}
 }
@@ -44,6 +44,7 @@ type ServiceDetails struct {
 	Launch            string
 	Tags              []string
 	EmergencyShutdown bool
+	Maintenance       *MaintenanceMode `json:",omitempty"`
 	UpdatedAt         time.Time
 	CreatedAt         time.Time
 	Version           string
@@ -109,5 +110,8 @@ func (s *ServiceDetails) Equals(b *ServiceDetails) bool {
 	if s.EmergencyShutdown != b.EmergencyShutdown {
 		return false
 	}
+	if (s.Maintenance == nil) != (b.Maintenance == nil) {
+		return false
+	}
 	return true
 }
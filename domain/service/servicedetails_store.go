@@ -71,10 +71,18 @@ func (s *storeImpl) Query(ctx datastore.Context, query Query) ([]ServiceDetails,
 			continue
 		}
 
+		if d.UpdatedAt.Before(query.ChangedSince) {
+			continue
+		}
+
 		if query.Tenants && d.ParentServiceID != "" {
 			continue
 		}
 
+		if query.PoolID != "" && d.PoolID != query.PoolID {
+			continue
+		}
+
 		if len(query.Tags) > 0 {
 			tagsMatch := true
 			for _, t := range query.Tags {
@@ -458,6 +466,7 @@ func createPublicEndpoints(result EndpointQueryResult) []PublicEndpoint {
 				Protocol:    "https",
 				VHostName:   vhost.Name,
 				Enabled:     vhost.Enabled,
+				Certificate: vhost.Certificate,
 			})
 		}
 
@@ -563,6 +572,7 @@ var serviceDetailsFields = []string{
 	"Launch",
 	"Tags",
 	"EmergencyShutdown",
+	"Maintenance",
 	"UpdatedAt",
 	"CreatedAt",
 	"Version",
@@ -0,0 +1,32 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "time"
+
+// StateEvent is published when the current state of a service changes
+type StateEvent struct {
+	Seq       uint64 // Increases with each event published by the master
+	ServiceID string
+	TenantID  string
+	State     ServiceCurrentState
+	Timestamp time.Time
+}
+
+// StateEvents are the state changes that were published after a sequence
+// number.  Seq is the sequence number to wait after for the next changes.
+type StateEvents struct {
+	Events []StateEvent
+	Seq    uint64
+}
@@ -15,8 +15,11 @@ package service
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -43,6 +46,49 @@ func (s *Service) ValidEntity() error {
 		}
 	}
 
+	if s.StartTimeout < 0 {
+		vErr.Add(fmt.Errorf("Start timeout (%d) cannot be negative", s.StartTimeout))
+	}
+
+	vErr.Add(servicedefinition.ValidateReadinessGates(s.ReadinessGates))
+
+	if s.ImagePullPolicy != "" {
+		vErr.Add(validation.StringIn(s.ImagePullPolicy, commons.PullAlways, commons.PullIfNotPresent, commons.PullNever))
+	}
+
+	if s.PriorityClass != "" {
+		vErr.Add(validation.StringIn(s.PriorityClass, servicedefinition.PriorityClasses...))
+	}
+
+	if s.CPURequest < 0 {
+		vErr.Add(fmt.Errorf("CPU request (%g) cannot be negative", s.CPURequest))
+	}
+	if s.CPULimit < 0 {
+		vErr.Add(fmt.Errorf("CPU limit (%g) cannot be negative", s.CPULimit))
+	} else if s.CPULimit > 0 && s.CPULimit < s.CPURequest {
+		vErr.Add(fmt.Errorf("CPU limit (%g) cannot be less than the CPU request (%g)", s.CPULimit, s.CPURequest))
+	}
+
+	if s.RegistryCredential != "" && !secret.ValidName(s.RegistryCredential) {
+		vErr.Add(fmt.Errorf("Invalid registry credential secret name %q", s.RegistryCredential))
+	}
+
+	if s.ImageDigest != "" && !commons.ValidImageDigest(s.ImageDigest) {
+		vErr.Add(fmt.Errorf("Invalid image digest %q", s.ImageDigest))
+	}
+
+	for key := range s.EnvironmentOverrides {
+		if key == "" || strings.ContainsAny(key, "= ") {
+			vErr.Add(fmt.Errorf("Invalid environment variable name %q", key))
+		}
+	}
+
+	if s.DockerLogDriver != "" {
+		vErr.Add(validation.StringIn(s.DockerLogDriver, commons.LogDriverJSONFile, commons.LogDriverJournald, commons.LogDriverFluentd))
+	} else if len(s.DockerLogConfig) > 0 {
+		vErr.Add(fmt.Errorf("Docker log options require a docker log driver"))
+	}
+
 	// validate the monitoring profile
 	vErr.Add(s.MonitoringProfile.ValidEntity())
 
@@ -61,6 +107,7 @@ func (s *Service) ValidEntity() error {
 }
 
 // ValidEntity ensures the enpoint has valid values, does not check vhosts, public ports and assignments
+// other than the protocols of their backends
 func (endpoint ServiceEndpoint) ValidEntity() error {
 	violations := validation.NewValidationError()
 	violations.Add(validation.NotEmpty("endpoint.Name", endpoint.Name))
@@ -71,6 +118,14 @@ func (endpoint ServiceEndpoint) ValidEntity() error {
 		violations.Add(validation.ValidPort(int(endpoint.PortNumber)))
 	}
 
+	for _, vhost := range endpoint.VHostList {
+		violations.Add(vhost.ValidAppProtocol())
+	}
+	for _, port := range endpoint.PortList {
+		violations.Add(port.ValidAppProtocol())
+		violations.Add(port.ValidServerName())
+	}
+
 	violations.Add(validation.NotEmpty("endpoint.Application", endpoint.Application))
 
 	if violations.HasError() {
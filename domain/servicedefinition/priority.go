@@ -0,0 +1,54 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+// Priority classes of services.  The class of a service orders it against
+// the services of other classes when the cluster is under pressure:
+// emergency shutdowns and host drains stop the lowest class first, and
+// delegates start the highest class first when more instances are waiting
+// to start than they start at a time, such as while recovering from an
+// outage.  Services without a class are in PriorityNormal.
+const (
+	PriorityCritical = "critical"
+	PriorityHigh     = "high"
+	PriorityNormal   = "normal"
+	PriorityLow      = "low"
+)
+
+// PriorityClasses are the priority classes from the highest to the lowest
+var PriorityClasses = []string{PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow}
+
+// EffectivePriorityClass returns the class of a service, which is
+// PriorityNormal if none is set
+func EffectivePriorityClass(class string) string {
+	if class == "" {
+		return PriorityNormal
+	}
+	return class
+}
+
+// PriorityRank returns the rank of a priority class.  Higher classes have
+// higher ranks, and PriorityNormal ranks 0.
+func PriorityRank(class string) int {
+	switch class {
+	case PriorityCritical:
+		return 2
+	case PriorityHigh:
+		return 1
+	case PriorityLow:
+		return -1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// Types of readiness gates
+const (
+	// ReadinessService passes when an instance of another service passes all
+	// of its health checks
+	ReadinessService = "service"
+	// ReadinessWebhook passes when a POST of the instance to a url responds
+	// with a 2xx code, such as when a load balancer registration completes
+	ReadinessWebhook = "webhook"
+)
+
+// Defaults of readiness gates
+const (
+	DefaultReadinessInterval = 5 * time.Second
+	DefaultReadinessTimeout  = 10 * time.Second
+)
+
+// ReadinessGate is a condition external to an instance that must be
+// satisfied before the instance is marked ready and its exports are
+// registered to receive traffic.  The instance starts while its gates are
+// pending, and each gate is checked every Interval seconds until it passes.
+type ReadinessGate struct {
+	Name      string // Name of the gate. Unique per service definition
+	Type      string // service or webhook
+	ServiceID string // Service that must be healthy for a service gate; may be a template, e.g. {{(child (parent .) "db").ID}}
+	URL       string // Url that a webhook gate posts a ReadinessRequest to; may be a template
+	Interval  int    // Seconds between checks of the gate; 5 if 0
+	Timeout   int    // Seconds a webhook may take to respond; 10 if 0
+}
+
+// ReadinessRequest is the body that a webhook readiness gate posts
+type ReadinessRequest struct {
+	ServiceID   string
+	ServiceName string
+	InstanceID  int
+	HostID      string
+	HostIP      string
+	PrivateIP   string
+	Exports     []ReadinessExport
+}
+
+// ReadinessExport is an endpoint that an instance exports once it is ready
+type ReadinessExport struct {
+	Application string
+	Protocol    string
+	PortNumber  uint16
+}
+
+// GetInterval returns the time between checks of the gate
+func (g ReadinessGate) GetInterval() time.Duration {
+	if g.Interval <= 0 {
+		return DefaultReadinessInterval
+	}
+	return time.Duration(g.Interval) * time.Second
+}
+
+// GetTimeout returns the time that a webhook may take to respond
+func (g ReadinessGate) GetTimeout() time.Duration {
+	if g.Timeout <= 0 {
+		return DefaultReadinessTimeout
+	}
+	return time.Duration(g.Timeout) * time.Second
+}
+
+// Validate checks the type and the target of the gate
+func (g ReadinessGate) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("readiness gate name cannot be empty")
+	}
+	if err := validation.StringIn(g.Type, ReadinessService, ReadinessWebhook); err != nil {
+		return fmt.Errorf("readiness gate %s: invalid type %v", g.Name, err)
+	}
+	if g.Type == ReadinessService && g.ServiceID == "" {
+		return fmt.Errorf("readiness gate %s: service gates require a service id", g.Name)
+	}
+	if g.Type == ReadinessWebhook && g.URL == "" {
+		return fmt.Errorf("readiness gate %s: webhook gates require a url", g.Name)
+	}
+	if g.Interval < 0 || g.Timeout < 0 {
+		return fmt.Errorf("readiness gate %s: interval and timeout cannot be negative", g.Name)
+	}
+	return nil
+}
+
+// ValidateReadinessGates checks the gates of a service and that their names
+// are unique
+func ValidateReadinessGates(gates []ReadinessGate) error {
+	names := make(map[string]struct{})
+	for _, g := range gates {
+		if err := g.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[g.Name]; ok {
+			return fmt.Errorf("readiness gate %s is defined more than once", g.Name)
+		}
+		names[g.Name] = struct{}{}
+	}
+	return nil
+}
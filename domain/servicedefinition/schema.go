@@ -0,0 +1,56 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/validation"
+)
+
+// ConstrainSchema adds the constraints of service definitions to the
+// definitions of a JSON Schema that describes them
+func ConstrainSchema(s *validation.Schema) {
+	if def := s.Definition(ServiceDefinition{}); def != nil {
+		def.Require("Name")
+		def.Property("Name").SetMinLength(1)
+		def.Property("StartTimeout").SetMinimum(0)
+		def.Property("CPURequest").SetMinimum(0)
+		def.Property("CPULimit").SetMinimum(0)
+		def.Property("ImagePullPolicy").SetEnum("", commons.PullAlways, commons.PullIfNotPresent, commons.PullNever)
+		def.Property("DockerLogDriver").SetEnum("", commons.LogDriverJSONFile, commons.LogDriverJournald, commons.LogDriverFluentd)
+		def.Property("PriorityClass").SetEnum("", PriorityCritical, PriorityHigh, PriorityNormal, PriorityLow)
+	}
+	if def := s.Definition(ReadinessGate{}); def != nil {
+		def.Require("Name", "Type")
+		def.Property("Name").SetMinLength(1)
+		def.Property("Type").SetEnum(ReadinessService, ReadinessWebhook)
+		def.Property("Interval").SetMinimum(0)
+		def.Property("Timeout").SetMinimum(0)
+	}
+	if def := s.Definition(EndpointDefinition{}); def != nil {
+		def.Require("Name")
+		def.Property("Name").SetMinLength(1)
+	}
+	for _, v := range []interface{}{VHost{}, Port{}} {
+		if def := s.Definition(v); def != nil {
+			def.Property("AppProtocol").SetEnum(AppProtocolHTTP1, AppProtocolHTTP2, AppProtocolGRPC)
+		}
+	}
+	if def := s.Definition(domain.MinMax{}); def != nil {
+		def.Property("Min").SetMinimum(0)
+		def.Property("Max").SetMinimum(0)
+		def.Property("Default").SetMinimum(0)
+	}
+}
@@ -38,6 +38,7 @@ type ServiceDefinition struct {
 	Environment            []string               // Environment variables to be injected, of the form NAME="value"
 	Tags                   []string               // Searchable service tags
 	ImageID                string                 // Docker image hosting the service
+	RegistryCredential     string                 // Name of the secret holding the credentials of the image's registry
 	Instances              domain.MinMax          // Constraints on the number of instances
 	ChangeOptions          []ChangeOption         // Control options for what happens when a running service is changed
 	Launch                 string                 // Must be "AUTO", the default, or "MANUAL"
@@ -55,18 +56,30 @@ type ServiceDefinition struct {
 	RAMCommitment          utils.EngNotation             // expected RAM commitment to use for scheduling
 	RAMThreshold           uint                          // RAM Threshold
 	CPUCommitment          uint64                        // expected CPU commitment (#cores) to use for scheduling
+	CPURequest             float64                       // CPU cores reserved per instance; hosts without enough free cores are not scheduled
+	CPULimit               float64                       // maximum CPU cores an instance may use, enforced by docker
+	DockerLogDriver        string                        // docker log driver of the instances; the delegate's driver is used if empty
+	DockerLogConfig        map[string]string             // docker log options for DockerLogDriver
+	HostAffinity           map[string]string             // host labels required to run instances in label-affinity pools
+	HostAntiAffinity       map[string]string             // host labels that exclude a host from running instances in label-affinity pools
+	NodeSelector           map[string]string             // host labels that every host running instances must have, in any pool
 	DisableShell           bool                          // disables shell commands on the service
 	Runs                   map[string]string             // FIXME: This field is deprecated. Remove when possible.
 	Commands               map[string]domain.Command     // Map of commands that can be executed with 'serviced run ...'
 	Actions                map[string]string             // Map of commands that can be executed with 'serviced action ...'
 	HealthChecks           map[string]health.HealthCheck // HealthChecks for a service.
 	Prereqs                []domain.Prereq               // Optional list of scripts that must be successfully run before kicking off the service command.
+	ReadinessGates         []ReadinessGate               // Optional external conditions that must pass before instances receive traffic
 	MonitoringProfile      domain.MonitorProfile         // An optional list of queryable metrics, graphs, and thresholds
 	MemoryLimit            float64
 	CPUShares              int64
 	PIDFile                string // An optional path or command to generate a path for a PID file to which signals are relayed.
+	StartTimeout           int    // Seconds an instance may take to start before the start is marked failed; 0 waits indefinitely
+	ImagePullPolicy        string // When delegates pull the image (Always, IfNotPresent or Never); the delegate's policy is used if empty
+	Tracing                bool   // Inject the OpenTelemetry environment and forward spans to the tracing collector of the cluster
 	StartLevel             uint   // Services start in the order implied by this field (low to high) and stopped in reverse order
 	EmergencyShutdownLevel uint   // In case of low storage, Services stopped in the order implied by this field (low to high)
+	PriorityClass          string // critical, high, normal or low; orders the service against other classes under pressure
 }
 
 // SnapshotCommands commands to be called during and after a snapshot
@@ -92,18 +105,36 @@ type EndpointDefinition struct {
 	PortList  []Port
 }
 
+// The protocols that the backend of an http public endpoint can speak.  The
+// public endpoint proxy forwards requests to backends that speak HTTP/2 with
+// HTTP/2 over cleartext (h2c) instead of downgrading them to HTTP/1.1.
+const (
+	AppProtocolHTTP1 = ""      // HTTP/1.1, the default
+	AppProtocolHTTP2 = "http2" // HTTP/2 over cleartext
+	AppProtocolGRPC  = "grpc"  // gRPC, which is HTTP/2 with streaming bodies and trailers
+)
+
+// IsHTTP2 returns true if the backend of a public endpoint speaks HTTP/2
+func IsHTTP2(appProtocol string) bool {
+	return appProtocol == AppProtocolHTTP2 || appProtocol == AppProtocolGRPC
+}
+
 // VHost is the configuration for an application endpoint that wants an http VHost endpoint provided by Control Center
 type VHost struct {
-	Name    string // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
-	Enabled bool   // whether the vhost should be enabled or disabled.
+	Name        string // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
+	Enabled     bool   // whether the vhost should be enabled or disabled.
+	AppProtocol string // protocol spoken by the backend: "" for HTTP/1.1, "http2" or "grpc"
+	Certificate string `json:",omitempty"` // name of the uploaded certificate that the vhost presents, empty for the default certificate
 }
 
 // Port is the configuration for an application endpoint port.
 type Port struct {
-	PortAddr string // which port number to use for this endpoint
-	Enabled  bool   // whether the port should be enabled or disabled.
-	UseTLS   bool   // Does this port endpoint use tls.
-	Protocol string // What protocol (if any) does the endpoind use.
+	PortAddr    string // which port number to use for this endpoint
+	Enabled     bool   // whether the port should be enabled or disabled.
+	UseTLS      bool   // Does this port endpoint use tls.
+	Protocol    string // What protocol (if any) does the endpoind use.
+	AppProtocol string // protocol spoken by the backend of an http(s) port: "" for HTTP/1.1, "http2" or "grpc"
+	ServerName  string `json:",omitempty"` // TLS server name that routes connections to a tcp port whose address is shared with other ports
 }
 
 // Volume import defines a file system directory underneath an export directory
@@ -143,6 +174,7 @@ type LogConfig struct {
 	Filters []string // A list of filters that must be contained in either the LogFilters or a parent's LogFilter,
 	LogTags []LogTag // Key value pair of tags that are sent to logstash for all entries coming out of this logfile
 	IsAudit bool     // Whether to send log entries to /var/log/serviced/application-audit.log or not for each LogConfig Type
+	Shipper string   // Where the delegate ships the log: logstash (the default), syslog or kafka
 }
 
 // LogTag  no clue what this is. Maybe someone actually reads this
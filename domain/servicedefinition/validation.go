@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -43,6 +44,44 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 		return fmt.Errorf("service definition %v: invalid launch setting %v", sd.Name, err)
 	}
 
+	if sd.StartTimeout < 0 {
+		return fmt.Errorf("service definition %v: start timeout cannot be negative", sd.Name)
+	}
+
+	if err := ValidateReadinessGates(sd.ReadinessGates); err != nil {
+		return fmt.Errorf("service definition %v: %v", sd.Name, err)
+	}
+
+	if sd.ImagePullPolicy != "" {
+		if err := validation.StringIn(sd.ImagePullPolicy, commons.PullAlways, commons.PullIfNotPresent, commons.PullNever); err != nil {
+			return fmt.Errorf("service definition %v: invalid image pull policy %v", sd.Name, err)
+		}
+	}
+
+	if sd.PriorityClass != "" {
+		if err := validation.StringIn(sd.PriorityClass, PriorityClasses...); err != nil {
+			return fmt.Errorf("service definition %v: invalid priority class %v", sd.Name, err)
+		}
+	}
+
+	if sd.CPURequest < 0 || sd.CPULimit < 0 {
+		return fmt.Errorf("service definition %v: cpu request and limit cannot be negative", sd.Name)
+	} else if sd.CPULimit > 0 && sd.CPULimit < sd.CPURequest {
+		return fmt.Errorf("service definition %v: cpu limit cannot be less than the cpu request", sd.Name)
+	}
+
+	if sd.RegistryCredential != "" && !secret.ValidName(sd.RegistryCredential) {
+		return fmt.Errorf("service definition %v: invalid registry credential secret name %q", sd.Name, sd.RegistryCredential)
+	}
+
+	if sd.DockerLogDriver != "" {
+		if err := validation.StringIn(sd.DockerLogDriver, commons.LogDriverJSONFile, commons.LogDriverJournald, commons.LogDriverFluentd); err != nil {
+			return fmt.Errorf("service definition %v: invalid docker log driver %v", sd.Name, err)
+		}
+	} else if len(sd.DockerLogConfig) > 0 {
+		return fmt.Errorf("service definition %v: docker log options require a docker log driver", sd.Name)
+	}
+
 	//validate endpoint config
 	names := make(map[string]struct{})
 	for _, se := range sd.Endpoints {
@@ -59,7 +98,13 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 		}
 		names[trimName] = struct{}{}
 	}
-	//TODO: validate LogConfigs
+	for _, lc := range sd.LogConfigs {
+		if lc.Shipper != "" {
+			if err := validation.StringIn(lc.Shipper, commons.LogShipperLogstash, commons.LogShipperSyslog, commons.LogShipperKafka); err != nil {
+				return fmt.Errorf("service definition %v: invalid log shipper for %s: %v", sd.Name, lc.Path, err)
+			}
+		}
+	}
 
 	// validate Monitoring Profile
 	if err := sd.MonitoringProfile.ValidEntity(); err != nil {
@@ -121,9 +166,64 @@ func (se EndpointDefinition) ValidEntity() error {
 			return fmt.Errorf("endpoint '%s': %s", se.Name, err)
 		}
 	}
+	for _, vhost := range se.VHostList {
+		if err := vhost.ValidAppProtocol(); err != nil {
+			return fmt.Errorf("endpoint '%s' vhost %s: %s", se.Name, vhost.Name, err)
+		}
+	}
+	for _, port := range se.PortList {
+		if err := port.ValidAppProtocol(); err != nil {
+			return fmt.Errorf("endpoint '%s' port %s: %s", se.Name, port.PortAddr, err)
+		}
+		if err := port.ValidServerName(); err != nil {
+			return fmt.Errorf("endpoint '%s' port %s: %s", se.Name, port.PortAddr, err)
+		}
+	}
 	return se.AddressConfig.ValidEntity()
 }
 
+func validAppProtocol(appProtocol string) error {
+	return validation.StringIn(appProtocol, AppProtocolHTTP1, AppProtocolHTTP2, AppProtocolGRPC)
+}
+
+// ValidAppProtocol returns an error if the protocol of the backend of the
+// vhost is not known
+func (v VHost) ValidAppProtocol() error {
+	return validAppProtocol(v.AppProtocol)
+}
+
+// ValidAppProtocol returns an error if the protocol of the backend of the
+// port is not known.  Only http ports are proxied, so only they can select a
+// protocol.
+func (p Port) ValidAppProtocol() error {
+	if err := validAppProtocol(p.AppProtocol); err != nil {
+		return err
+	}
+	if p.AppProtocol != AppProtocolHTTP1 && p.Protocol != "http" && p.Protocol != "https" {
+		return fmt.Errorf("app protocol %s requires an http or https port", p.AppProtocol)
+	}
+	return nil
+}
+
+var serverNameRegex = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ValidServerName returns an error if the port routes by a server name that
+// is not a lowercase host name, optionally a wildcard.  Only tcp ports are
+// routed by the TLS server name of their connections; http ports are served
+// by an http server of their own.
+func (p Port) ValidServerName() error {
+	if p.ServerName == "" {
+		return nil
+	}
+	if !serverNameRegex.MatchString(p.ServerName) {
+		return fmt.Errorf("invalid server name %s", p.ServerName)
+	}
+	if p.Protocol == "http" || p.Protocol == "https" {
+		return fmt.Errorf("server name %s requires a tcp port", p.ServerName)
+	}
+	return nil
+}
+
 func applicationValidation(application string) error {
 	_, err := regexp.Compile(application)
 	if err != nil {
@@ -137,3 +137,92 @@ func TestServiceDefinitionEmptyEndpointName(t *testing.T) {
 		t.Errorf("Unexpected Error %v", err)
 	}
 }
+
+func TestPortValidAppProtocol(t *testing.T) {
+	port := Port{PortAddr: ":50051", Protocol: "https", AppProtocol: AppProtocolGRPC}
+	if err := port.ValidAppProtocol(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	port.Protocol = ""
+	if err := port.ValidAppProtocol(); err == nil {
+		t.Error("Expected error for grpc on a tcp port")
+	}
+
+	port.Protocol = "http"
+	port.AppProtocol = "spdy"
+	if err := port.ValidAppProtocol(); err == nil || !strings.Contains(err.Error(), "string spdy not in") {
+		t.Errorf("Expected error for invalid app protocol %v", err)
+	}
+
+	vhost := VHost{Name: "grpc", AppProtocol: AppProtocolHTTP2}
+	if err := vhost.ValidAppProtocol(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestPortValidServerName(t *testing.T) {
+	port := Port{PortAddr: ":5432", Protocol: "", ServerName: "db.example.com"}
+	if err := port.ValidServerName(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	port.ServerName = "*.example.com"
+	if err := port.ValidServerName(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	port.ServerName = "DB.example.com"
+	if err := port.ValidServerName(); err == nil {
+		t.Error("Expected error for an uppercase server name")
+	}
+
+	port.ServerName = "db.example.com"
+	port.Protocol = "https"
+	if err := port.ValidServerName(); err == nil {
+		t.Error("Expected error for a server name on an https port")
+	}
+}
+
+func TestServiceDefinitionReadinessGates(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].ReadinessGates = []ReadinessGate{
+		{Name: "db", Type: ReadinessService, ServiceID: `{{(child (parent .) "db").ID}}`},
+		{Name: "lb", Type: ReadinessWebhook, URL: "http://lb.example.com/register", Timeout: 5},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].ReadinessGates[1].Name = "db"
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "defined more than once") {
+		t.Errorf("Expected error for a duplicate gate name, got %v", err)
+	}
+
+	sd.Services[0].ReadinessGates[1] = ReadinessGate{Name: "lb", Type: ReadinessWebhook}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "require a url") {
+		t.Errorf("Expected error for a webhook gate without a url, got %v", err)
+	}
+
+	sd.Services[0].ReadinessGates[1] = ReadinessGate{Name: "lb", Type: "dns"}
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "invalid type") {
+		t.Errorf("Expected error for an invalid gate type, got %v", err)
+	}
+}
+
+func TestServiceDefinitionLogShipper(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].LogConfigs = []LogConfig{
+		{Path: "/var/log/app.log", Type: "app"},
+		{Path: "/var/log/audit.log", Type: "audit", Shipper: commons.LogShipperSyslog},
+		{Path: "/var/log/access.log", Type: "access", Shipper: commons.LogShipperKafka},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].LogConfigs[2].Shipper = "fluentd"
+	if err := sd.ValidEntity(); err == nil || !strings.Contains(err.Error(), "invalid log shipper") {
+		t.Errorf("Expected error for an invalid log shipper, got %v", err)
+	}
+}
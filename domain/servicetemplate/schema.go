@@ -0,0 +1,35 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/validation"
+)
+
+// Schema returns the JSON Schema of service templates
+func Schema() *validation.Schema {
+	s := validation.NewSchema("Control Center Service Template", ServiceTemplate{})
+	def := s.Definition(ServiceTemplate{})
+	def.Require("Name")
+	def.Property("Name").SetMinLength(1)
+	servicedefinition.ConstrainSchema(s)
+	return s
+}
+
+// ValidateJSON checks a JSON encoded service template against the schema of
+// service templates
+func ValidateJSON(data []byte) error {
+	return Schema().Validate(data)
+}
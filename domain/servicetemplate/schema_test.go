@@ -0,0 +1,73 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package servicetemplate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "github.com/control-center/serviced/domain/servicedefinition/testutils"
+	"github.com/control-center/serviced/validation"
+)
+
+func TestServiceTemplateSchemaValid(t *testing.T) {
+	template := ServiceTemplate{
+		Name:     "template",
+		Services: []servicedefinition.ServiceDefinition{*ValidSvcDef},
+	}
+	data, err := json.Marshal(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateJSON(data); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestServiceTemplateSchemaViolations(t *testing.T) {
+	data := []byte(`{
+		"Services": [{
+			"Name": "parent",
+			"Services": [{
+				"Name": "",
+				"Instances": {"Min": "1"},
+				"Endpoints": [{"Name": "web", "PortNumber": 70000, "VHostList": [{"Name": "web", "AppProtocol": "spdy"}]}]
+			}]
+		}]
+	}`)
+	err := ValidateJSON(data)
+	verr, ok := err.(*validation.ValidationError)
+	if !ok {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	expected := []string{
+		"Name: is required",
+		"Services[0].Services[0].Endpoints[0].PortNumber: must be at most 65535",
+		`Services[0].Services[0].Endpoints[0].VHostList[0].AppProtocol: must be one of "", "http2", "grpc"`,
+		"Services[0].Services[0].Instances.Min: expected integer, got string",
+		"Services[0].Services[0].Name: must be at least 1 characters",
+	}
+	if len(verr.Errors) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), verr.Errors)
+	}
+	for i, e := range verr.Errors {
+		if e.Error() != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], e.Error())
+		}
+	}
+}
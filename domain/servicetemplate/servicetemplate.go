@@ -30,9 +30,11 @@ var plog = logging.PackageLogger()
 
 // A request to deploy a service template
 type ServiceTemplateDeploymentRequest struct {
-	PoolID       string // Pool Id to deploy service into
-	TemplateID   string // Id of template to be deployed
-	DeploymentID string // Unique id of the instance of this template
+	PoolID         string // Pool Id to deploy service into
+	TemplateID     string // Id of template to be deployed
+	DeploymentID   string // Unique id of the instance of this template
+	Values         Values `json:",omitempty"` // Values that parameterize the deployment
+	IdempotencyKey string `json:",omitempty"` // Identifies the request across retries
 }
 
 // ServiceTemplate type to hold service definitions
@@ -43,6 +45,10 @@ type ServiceTemplate struct {
 	Description string                                  // Meaningful description of service
 	Services    []servicedefinition.ServiceDefinition   // Child services
 	ConfigFiles map[string]servicedefinition.ConfigFile // Config file templates
+	Values      Values                                  `json:",omitempty"` // Default values of a deployment
+	// RegistryCredential is the name of the secret holding the registry
+	// credentials of the services that do not name their own.
+	RegistryCredential string `json:",omitempty"`
 	datastore.VersionedEntity
 }
 
@@ -66,9 +72,33 @@ func (a *ServiceTemplate) Equals(b *ServiceTemplate) bool {
 	if !reflect.DeepEqual(a.ConfigFiles, b.ConfigFiles) {
 		return false
 	}
+	if !reflect.DeepEqual(a.Values, b.Values) {
+		return false
+	}
+	if a.RegistryCredential != b.RegistryCredential {
+		return false
+	}
 	return true
 }
 
+// ApplyRegistryCredential sets the registry credential of the template on
+// the services that do not reference a registry credential of their own.
+func (a *ServiceTemplate) ApplyRegistryCredential() {
+	if a.RegistryCredential == "" {
+		return
+	}
+	var apply func(sds []servicedefinition.ServiceDefinition)
+	apply = func(sds []servicedefinition.ServiceDefinition) {
+		for i := range sds {
+			if sds[i].RegistryCredential == "" {
+				sds[i].RegistryCredential = a.RegistryCredential
+			}
+			apply(sds[i].Services)
+		}
+	}
+	apply(a.Services)
+}
+
 func (a *ServiceTemplate) Hash() (string, error) {
 	tpl := *a
 	tpl.ID = ""
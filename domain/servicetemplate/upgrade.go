@@ -0,0 +1,45 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+// Actions of a template change
+const (
+	// TemplateChangeAdd adds a service, or a value to a field of a service
+	TemplateChangeAdd = "add"
+	// TemplateChangeUpdate replaces the value of a field of a service
+	TemplateChangeUpdate = "update"
+	// TemplateChangePull pulls the image of a service from the template
+	TemplateChangePull = "pull"
+	// TemplateChangeKeep leaves a service that is not in the template in place
+	TemplateChangeKeep = "keep"
+)
+
+// ServiceTemplateUpgradeRequest is the request to upgrade the services of a
+// deployment to a version of a template.
+type ServiceTemplateUpgradeRequest struct {
+	TemplateID   string
+	DeploymentID string
+	DryRun       bool // report the changes without applying them
+}
+
+// TemplateChange is a difference between a template and the services of a
+// deployment that is applied by a template upgrade.
+type TemplateChange struct {
+	ServicePath string // service names from the tenant, separated by '/'
+	ServiceID   string // empty if the service is added
+	Action      string
+	Field       string // empty if the change is to the whole service
+	Old         string
+	New         string
+}
@@ -16,6 +16,7 @@ package servicetemplate
 import (
 	"fmt"
 
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
@@ -25,6 +26,9 @@ func (st *ServiceTemplate) ValidEntity() error {
 	//	trimmedID := strings.TrimSpace(st.ID)
 	violations := validation.NewValidationError()
 	violations.Add(validation.NotEmpty("ServiceTemplate.ID", st.ID))
+	if st.RegistryCredential != "" && !secret.ValidName(st.RegistryCredential) {
+		violations.Add(fmt.Errorf("invalid registry credential secret name %q", st.RegistryCredential))
+	}
 	//	violations.Add(validation.StringsEqual(st.ID, trimmedID, "leading and trailing spaces not allowed for service template id"))
 
 	//TODO: check name, description, config files.
@@ -0,0 +1,382 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// ErrValuesNotMap is returned when a values document is not a map
+	ErrValuesNotMap = errors.New("values must be a map")
+
+	// ErrValuesBadSet is returned when a value is not set as KEY=VALUE
+	ErrValuesBadSet = errors.New("values must be set as KEY=VALUE")
+)
+
+// ValuesServicesKey is the key of the values that configure the services of
+// a template
+const ValuesServicesKey = "services"
+
+// Values parameterize the deployment of a template, like the values of a
+// helm chart.  The services key maps the path of a service in the template
+// (the names of the service and its parents, joined by "/") to its settings:
+//
+//	services:
+//	  Zenoss.core/Zope:
+//	    instances: 3
+//	    ramCommitment: 4G
+//	    environment:
+//	      JVM_ARGS: -Xmx2g
+//	    endpoints:
+//	      zope:
+//	        port: 9080
+//	        vhosts: [zope]
+//	        ports: [":9443"]
+//
+// The values of a template are its defaults, and the values given at deploy
+// time override them.
+type Values map[string]interface{}
+
+// ParseValues reads values from a yaml or json document
+func ParseValues(data []byte) (Values, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return Values{}, nil
+	}
+	m, ok := normalizeValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, ErrValuesNotMap
+	}
+	return Values(m), nil
+}
+
+// normalizeValue converts the maps decoded from yaml to maps with string
+// keys, so that values encode to json
+func normalizeValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalizeValue(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = normalizeValue(value)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, value := range v {
+			l[i] = normalizeValue(value)
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// Set assigns a value at a key path of the form a.b.c=VALUE.  A dot that is
+// part of a key, as in the name of a service, is escaped with a backslash.
+func (v Values) Set(expr string) error {
+	idx := strings.Index(expr, "=")
+	if idx <= 0 {
+		return ErrValuesBadSet
+	}
+	keys := splitKeyPath(expr[:idx])
+	m := map[string]interface{}(v)
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			m[key] = child
+		}
+		m = child
+	}
+	m[keys[len(keys)-1]] = expr[idx+1:]
+	return nil
+}
+
+// splitKeyPath splits a key path on the dots that are not escaped
+func splitKeyPath(path string) []string {
+	keys := []string{}
+	var key []rune
+	escaped := false
+	for _, r := range path {
+		switch {
+		case escaped:
+			key = append(key, r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			keys = append(keys, string(key))
+			key = nil
+		default:
+			key = append(key, r)
+		}
+	}
+	return append(keys, string(key))
+}
+
+// Merge returns a copy of the values where the maps of other are merged
+// into the maps of v, and the other settings of other replace those of v.
+func (v Values) Merge(other Values) Values {
+	merged := mergeMaps(normalizeValue(map[string]interface{}(v)).(map[string]interface{}), other)
+	return Values(merged)
+}
+
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	for key, value := range src {
+		srcMap, srcOK := value.(map[string]interface{})
+		dstMap, dstOK := dst[key].(map[string]interface{})
+		if srcOK && dstOK {
+			dst[key] = mergeMaps(dstMap, srcMap)
+		} else {
+			dst[key] = normalizeValue(value)
+		}
+	}
+	return dst
+}
+
+// ApplyValues sets the values given at deploy time, merged over the values
+// of the template, on the services of the template.
+func (st *ServiceTemplate) ApplyValues(values Values) error {
+	merged := st.Values.Merge(values)
+	raw, ok := merged[ValuesServicesKey]
+	if !ok {
+		return nil
+	}
+	services, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("values: %s must be a map of service paths", ValuesServicesKey)
+	}
+
+	// apply the values in a stable order so that errors are reproducible
+	paths := make([]string, 0, len(services))
+	for path := range services {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		sd := findServiceDefinition(st.Services, path)
+		if sd == nil {
+			return fmt.Errorf("values: service %s is not in the template", path)
+		}
+		if err := applyServiceValues(sd, services[path]); err != nil {
+			return fmt.Errorf("values: service %s: %s", path, err)
+		}
+	}
+	return nil
+}
+
+// findServiceDefinition returns the service definition at the path of
+// service names joined by "/"
+func findServiceDefinition(sds []servicedefinition.ServiceDefinition, path string) *servicedefinition.ServiceDefinition {
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	var sd *servicedefinition.ServiceDefinition
+	for _, name := range names {
+		sd = nil
+		for i := range sds {
+			if sds[i].Name == name {
+				sd = &sds[i]
+				break
+			}
+		}
+		if sd == nil {
+			return nil
+		}
+		sds = sd.Services
+	}
+	return sd
+}
+
+// applyServiceValues sets the settings of a service definition
+func applyServiceValues(sd *servicedefinition.ServiceDefinition, raw interface{}) error {
+	settings, ok := raw.(map[string]interface{})
+	if !ok {
+		return ErrValuesNotMap
+	}
+	for key, value := range settings {
+		switch key {
+		case "instances":
+			count, err := valueToInt(value)
+			if err != nil {
+				return fmt.Errorf("instances: %s", err)
+			}
+			sd.Instances.Default = count
+			if sd.Instances.Min > count {
+				sd.Instances.Min = count
+			}
+			if sd.Instances.Max > 0 && sd.Instances.Max < count {
+				sd.Instances.Max = count
+			}
+		case "ramCommitment":
+			ram, err := utils.NewEngNotationFromString(fmt.Sprint(value))
+			if err != nil {
+				return fmt.Errorf("ramCommitment: %s", err)
+			}
+			sd.RAMCommitment = ram
+		case "environment":
+			env, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("environment: %s", ErrValuesNotMap)
+			}
+			setEnvironment(sd, env)
+		case "endpoints":
+			endpoints, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("endpoints: %s", ErrValuesNotMap)
+			}
+			for application, epValue := range endpoints {
+				if err := applyEndpointValues(sd, application, epValue); err != nil {
+					return fmt.Errorf("endpoint %s: %s", application, err)
+				}
+			}
+		default:
+			return fmt.Errorf("unknown setting %s", key)
+		}
+	}
+	return nil
+}
+
+// setEnvironment adds or replaces the environment variables of a service
+// definition
+func setEnvironment(sd *servicedefinition.ServiceDefinition, env map[string]interface{}) {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		variable := fmt.Sprintf("%s=%v", name, env[name])
+		replaced := false
+		for i, e := range sd.Environment {
+			if strings.SplitN(e, "=", 2)[0] == name {
+				sd.Environment[i] = variable
+				replaced = true
+			}
+		}
+		if !replaced {
+			sd.Environment = append(sd.Environment, variable)
+		}
+	}
+}
+
+// applyEndpointValues sets the port, vhosts and public ports of the endpoints
+// that a service definition exports for an application
+func applyEndpointValues(sd *servicedefinition.ServiceDefinition, application string, raw interface{}) error {
+	settings, ok := raw.(map[string]interface{})
+	if !ok {
+		return ErrValuesNotMap
+	}
+	found := false
+	for i := range sd.Endpoints {
+		ep := &sd.Endpoints[i]
+		if ep.Purpose != "export" || ep.Application != application {
+			continue
+		}
+		found = true
+		for key, value := range settings {
+			switch key {
+			case "port":
+				port, err := valueToInt(value)
+				if err != nil || port <= 0 || port > 65535 {
+					return fmt.Errorf("invalid port %v", value)
+				}
+				ep.PortNumber = uint16(port)
+			case "vhosts":
+				// keep the protocol of the vhosts that the template defines
+				vhost := servicedefinition.VHost{}
+				if len(ep.VHostList) > 0 {
+					vhost = ep.VHostList[0]
+				}
+				ep.VHostList = []servicedefinition.VHost{}
+				for _, name := range valueToStrings(value) {
+					vhost.Name, vhost.Enabled = name, true
+					ep.VHostList = append(ep.VHostList, vhost)
+				}
+			case "ports":
+				// keep the protocol of the ports that the template defines
+				port := servicedefinition.Port{}
+				if len(ep.PortList) > 0 {
+					port = ep.PortList[0]
+				}
+				ep.PortList = []servicedefinition.Port{}
+				for _, addr := range valueToStrings(value) {
+					port.PortAddr, port.Enabled = addr, true
+					ep.PortList = append(ep.PortList, port)
+				}
+			default:
+				return fmt.Errorf("unknown setting %s", key)
+			}
+		}
+	}
+	if !found {
+		return errors.New("no exported endpoint")
+	}
+	return nil
+}
+
+// valueToInt converts a number from yaml, json or --set to an int
+func valueToInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int(v), nil
+	case string:
+		return strconv.Atoi(v)
+	default:
+		return 0, fmt.Errorf("%v is not an integer", v)
+	}
+}
+
+// valueToStrings converts a list from yaml or json, or a comma separated
+// list from --set, to a list of strings
+func valueToStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		l := make([]string, len(v))
+		for i, item := range v {
+			l[i] = fmt.Sprint(item)
+		}
+		return l
+	case string:
+		if v == "" {
+			return []string{}
+		}
+		return strings.Split(v, ",")
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicetemplate
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+)
+
+func valuesTestTemplate() *ServiceTemplate {
+	return &ServiceTemplate{
+		ID: "template",
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name: "Zenoss.core",
+				Services: []servicedefinition.ServiceDefinition{
+					{
+						Name:        "Zope",
+						Instances:   domain.MinMax{Min: 1, Max: 2, Default: 1},
+						Environment: []string{"JVM_ARGS=-Xmx1g", "DEBUG=0"},
+						Endpoints: []servicedefinition.EndpointDefinition{
+							{
+								Name:        "zope",
+								Application: "zope",
+								Purpose:     "export",
+								PortNumber:  9080,
+								PortList:    []servicedefinition.Port{{PortAddr: ":8080", Enabled: true, UseTLS: true, Protocol: "https"}},
+							},
+							{
+								Name:        "mysql",
+								Application: "zodb",
+								Purpose:     "import",
+								PortNumber:  3306,
+							},
+						},
+					},
+				},
+			},
+		},
+		Values: Values{
+			"services": map[string]interface{}{
+				"Zenoss.core/Zope": map[string]interface{}{
+					"ramCommitment": "1G",
+				},
+			},
+		},
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues([]byte("services:\n  Zenoss.core/Zope:\n    instances: 3\n    environment:\n      DEBUG: 1\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Values{
+		"services": map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{
+				"instances":   3,
+				"environment": map[string]interface{}{"DEBUG": 1},
+			},
+		},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, values)
+	}
+
+	if _, err := ParseValues([]byte("- a\n- b\n")); err != ErrValuesNotMap {
+		t.Errorf("Expected %v, got %v", ErrValuesNotMap, err)
+	}
+}
+
+func TestValuesSet(t *testing.T) {
+	values := Values{}
+	if err := values.Set(`services.Zenoss\.core/Zope.instances=3`); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := values.Set(`services.Zenoss\.core/Zope.environment.JVM_ARGS=-Xmx2g -Dx=y`); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Values{
+		"services": map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{
+				"instances":   "3",
+				"environment": map[string]interface{}{"JVM_ARGS": "-Xmx2g -Dx=y"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, values)
+	}
+
+	if err := values.Set("instances"); err != ErrValuesBadSet {
+		t.Errorf("Expected %v, got %v", ErrValuesBadSet, err)
+	}
+}
+
+func TestValuesMerge(t *testing.T) {
+	defaults := Values{"services": map[string]interface{}{"a": map[string]interface{}{"instances": 1, "ramCommitment": "1G"}}}
+	values := Values{"services": map[string]interface{}{"a": map[string]interface{}{"instances": 2}}}
+	merged := defaults.Merge(values)
+	expected := Values{"services": map[string]interface{}{"a": map[string]interface{}{"instances": 2, "ramCommitment": "1G"}}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, merged)
+	}
+	// the defaults are not changed
+	if defaults["services"].(map[string]interface{})["a"].(map[string]interface{})["instances"] != 1 {
+		t.Errorf("Merge changed the original values")
+	}
+}
+
+func TestApplyValues(t *testing.T) {
+	st := valuesTestTemplate()
+	values := Values{
+		"services": map[string]interface{}{
+			"Zenoss.core/Zope": map[string]interface{}{
+				"instances":   "3",
+				"environment": map[string]interface{}{"DEBUG": 1, "ZOPE_THREADS": 4},
+				"endpoints": map[string]interface{}{
+					"zope": map[string]interface{}{
+						"port":   9090,
+						"vhosts": []interface{}{"zope"},
+						"ports":  ":8443,:9443",
+					},
+				},
+			},
+		},
+	}
+	if err := st.ApplyValues(values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	zope := st.Services[0].Services[0]
+	if zope.Instances != (domain.MinMax{Min: 1, Max: 3, Default: 3}) {
+		t.Errorf("Unexpected instances %+v", zope.Instances)
+	}
+	if zope.RAMCommitment.Value != utils.NewEngNotation(1<<30).Value {
+		t.Errorf("Expected the ram commitment of the template values, got %v", zope.RAMCommitment.Value)
+	}
+	if !reflect.DeepEqual(zope.Environment, []string{"JVM_ARGS=-Xmx1g", "DEBUG=1", "ZOPE_THREADS=4"}) {
+		t.Errorf("Unexpected environment %v", zope.Environment)
+	}
+	ep := zope.Endpoints[0]
+	if ep.PortNumber != 9090 {
+		t.Errorf("Expected port 9090, got %d", ep.PortNumber)
+	}
+	if !reflect.DeepEqual(ep.VHostList, []servicedefinition.VHost{{Name: "zope", Enabled: true}}) {
+		t.Errorf("Unexpected vhosts %+v", ep.VHostList)
+	}
+	expectedPorts := []servicedefinition.Port{
+		{PortAddr: ":8443", Enabled: true, UseTLS: true, Protocol: "https"},
+		{PortAddr: ":9443", Enabled: true, UseTLS: true, Protocol: "https"},
+	}
+	if !reflect.DeepEqual(ep.PortList, expectedPorts) {
+		t.Errorf("Unexpected ports %+v", ep.PortList)
+	}
+	if zope.Endpoints[1].PortNumber != 3306 {
+		t.Errorf("Imported endpoint should not change")
+	}
+}
+
+func TestApplyValues_Errors(t *testing.T) {
+	for _, values := range []Values{
+		{"services": map[string]interface{}{"Zenoss.core/Missing": map[string]interface{}{"instances": 1}}},
+		{"services": map[string]interface{}{"Zenoss.core/Zope": map[string]interface{}{"replicas": 1}}},
+		{"services": map[string]interface{}{"Zenoss.core/Zope": map[string]interface{}{"instances": "many"}}},
+		{"services": map[string]interface{}{"Zenoss.core/Zope": map[string]interface{}{"endpoints": map[string]interface{}{"zodb": map[string]interface{}{"port": 1}}}}},
+		{"services": "Zenoss.core/Zope"},
+	} {
+		if err := valuesTestTemplate().ApplyValues(values); err == nil {
+			t.Errorf("Expected an error applying %+v", values)
+		}
+	}
+}
+
+func TestApplyRegistryCredential(t *testing.T) {
+	st := valuesTestTemplate()
+	st.RegistryCredential = "registry.creds"
+	st.Services[0].Services[0].RegistryCredential = "zope.creds"
+	st.ApplyRegistryCredential()
+	if cred := st.Services[0].RegistryCredential; cred != "registry.creds" {
+		t.Errorf("Expected the credential of the template, got %q", cred)
+	}
+	if cred := st.Services[0].Services[0].RegistryCredential; cred != "zope.creds" {
+		t.Errorf("Expected the credential of the service, got %q", cred)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setting
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+)
+
+const (
+	// ImagePullPolicy is when delegates pull the images of services that do
+	// not select a policy.  It overrides the IMAGE_PULL_POLICY of the
+	// delegates.
+	ImagePullPolicy = "image-pull-policy"
+
+	// TracingCollector is the host:port of the OTLP/HTTP collector that the
+	// spans of services with tracing are forwarded to.  It overrides the
+	// TRACING_COLLECTOR of the delegates.
+	TracingCollector = "tracing-collector"
+)
+
+// Type is the type of the value of a setting
+type Type string
+
+const (
+	// TypeString is a setting with a string value
+	TypeString Type = "string"
+	// TypeInt is a setting with an integer value
+	TypeInt Type = "int"
+	// TypeBool is a setting with a boolean value
+	TypeBool Type = "bool"
+	// TypeDuration is a setting with a duration value, such as 90s
+	TypeDuration Type = "duration"
+)
+
+// Scope is where the value of a setting applies.  A value set for a
+// deployment overrides a value set for a resource pool, which overrides a
+// value set for the whole cluster.
+type Scope string
+
+const (
+	// ScopeGlobal applies to the whole cluster
+	ScopeGlobal Scope = "global"
+	// ScopePool applies to the hosts and services of a resource pool
+	ScopePool Scope = "pool"
+	// ScopeDeployment applies to the services of a deployment
+	ScopeDeployment Scope = "deployment"
+)
+
+// Definition describes a setting that can be changed at runtime
+type Definition struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     string   // Value used when the setting has not been set and Env is not configured
+	Allowed     []string // Values the setting may have; any value of the type if empty
+	Env         string   // Option of the daemon that is used when the setting has not been set
+}
+
+// Validate returns an error if value is not a valid value of the setting
+func (d Definition) Validate(value string) error {
+	var err error
+	switch d.Type {
+	case TypeInt:
+		_, err = strconv.Atoi(value)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	case TypeDuration:
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q for setting %s", d.Type, value, d.Name)
+	}
+	if len(d.Allowed) > 0 && !contains(d.Allowed, value) {
+		return fmt.Errorf("invalid value %q for setting %s; allowed values are %v", value, d.Name, d.Allowed)
+	}
+	return nil
+}
+
+// Definitions are the settings known to this version of serviced
+var Definitions = map[string]Definition{
+	ImagePullPolicy: {
+		Name:        ImagePullPolicy,
+		Description: "When delegates pull the images of services that do not select a pull policy",
+		Type:        TypeString,
+		Default:     commons.PullIfNotPresent,
+		Allowed:     []string{commons.PullAlways, commons.PullIfNotPresent, commons.PullNever},
+		Env:         "SERVICED_IMAGE_PULL_POLICY",
+	},
+	TracingCollector: {
+		Name:        TracingCollector,
+		Description: "Address of the OTLP/HTTP collector that the spans of services with tracing are forwarded to",
+		Type:        TypeString,
+		Env:         "SERVICED_TRACING_COLLECTOR",
+	},
+}
+
+// Lookup returns the definition of a setting
+func Lookup(name string) (Definition, bool) {
+	def, ok := Definitions[name]
+	return def, ok
+}
+
+// Names returns the sorted names of the known settings
+func Names() []string {
+	names := make([]string, 0, len(Definitions))
+	for name := range Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Value is the value of a setting in a scope
+type Value struct {
+	Scope   Scope
+	ScopeID string // ID of the resource pool or deployment; empty for the global scope
+	Value   string
+}
+
+// Setting is the values that a setting has been set to
+type Setting struct {
+	ID        string // Name of the setting
+	Values    []Value
+	UpdatedAt time.Time
+	datastore.VersionedEntity
+}
+
+// New creates a setting that has not been set in any scope
+func New(name string) *Setting {
+	return &Setting{ID: name}
+}
+
+// Set sets the value of the setting in a scope
+func (s *Setting) Set(scope Scope, scopeID, value string) {
+	for i, v := range s.Values {
+		if v.Scope == scope && v.ScopeID == scopeID {
+			s.Values[i].Value = value
+			return
+		}
+	}
+	s.Values = append(s.Values, Value{Scope: scope, ScopeID: scopeID, Value: value})
+}
+
+// Unset removes the value of the setting in a scope.  It returns false if the
+// setting was not set in the scope.
+func (s *Setting) Unset(scope Scope, scopeID string) bool {
+	for i, v := range s.Values {
+		if v.Scope == scope && v.ScopeID == scopeID {
+			s.Values = append(s.Values[:i], s.Values[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ValueFor returns the value of the setting in the given resource pool and
+// deployment, either of which may be empty.  It returns false if the setting
+// is not set in any scope that applies.
+func (s *Setting) ValueFor(poolID, deploymentID string) (string, bool) {
+	var global, pool *Value
+	for i, v := range s.Values {
+		switch v.Scope {
+		case ScopeDeployment:
+			if deploymentID != "" && v.ScopeID == deploymentID {
+				return v.Value, true
+			}
+		case ScopePool:
+			if poolID != "" && v.ScopeID == poolID {
+				pool = &s.Values[i]
+			}
+		case ScopeGlobal:
+			global = &s.Values[i]
+		}
+	}
+	if pool != nil {
+		return pool.Value, true
+	} else if global != nil {
+		return global.Value, true
+	}
+	return "", false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetType returns the kind of a setting
+func GetType() string {
+	return kind
+}
+
+// GetID returns the name of the setting
+func (s *Setting) GetID() string {
+	return s.ID
+}
+
+// GetType returns the kind of the setting entity
+func (s *Setting) GetType() string {
+	return GetType()
+}
+
+// Cache holds the settings of the cluster as they are watched by a delegate,
+// so that they can be read without a round trip to the master.
+type Cache struct {
+	mu       sync.RWMutex
+	settings map[string]Setting
+}
+
+// NewCache creates an empty settings cache
+func NewCache() *Cache {
+	return &Cache{settings: make(map[string]Setting)}
+}
+
+// Update replaces the settings in the cache
+func (c *Cache) Update(settings []Setting) {
+	settingMap := make(map[string]Setting)
+	for _, s := range settings {
+		settingMap[s.ID] = s
+	}
+	c.mu.Lock()
+	c.settings = settingMap
+	c.mu.Unlock()
+}
+
+// Value returns the value of a setting in the given resource pool and
+// deployment.  It returns false if the setting is not set in any scope that
+// applies, in which case the caller uses its own option.
+func (c *Cache) Value(name, poolID, deploymentID string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.RLock()
+	s, ok := c.settings[name]
+	c.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return s.ValueFor(poolID, deploymentID)
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package setting
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/commons"
+)
+
+func TestSetting_ValueFor(t *testing.T) {
+	s := New(ImagePullPolicy)
+	if _, ok := s.ValueFor("pool", "deployment"); ok {
+		t.Fatalf("expected a new setting to be unset")
+	}
+
+	s.Set(ScopeGlobal, "", commons.PullNever)
+	s.Set(ScopePool, "pool", commons.PullIfNotPresent)
+	s.Set(ScopeDeployment, "deployment", commons.PullAlways)
+	s.Set(ScopePool, "pool", commons.PullAlways)
+	if len(s.Values) != 3 {
+		t.Fatalf("expected 3 values, got %v", s.Values)
+	}
+
+	for _, tc := range []struct {
+		pool, deployment, expected string
+	}{
+		{"", "", commons.PullNever},
+		{"other", "other", commons.PullNever},
+		{"pool", "", commons.PullAlways},
+		{"other", "deployment", commons.PullAlways},
+	} {
+		if value, ok := s.ValueFor(tc.pool, tc.deployment); !ok || value != tc.expected {
+			t.Errorf("pool %q deployment %q: expected %s, got %q", tc.pool, tc.deployment, tc.expected, value)
+		}
+	}
+
+	if !s.Unset(ScopeGlobal, "") || s.Unset(ScopeGlobal, "") {
+		t.Errorf("expected the global value to be unset once")
+	}
+	if _, ok := s.ValueFor("other", ""); ok {
+		t.Errorf("expected no value outside of the pool and deployment")
+	}
+}
+
+func TestSetting_ValidEntity(t *testing.T) {
+	s := New(ImagePullPolicy)
+	s.Set(ScopePool, "pool", commons.PullNever)
+	if err := s.ValidEntity(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	s.Set(ScopeGlobal, "", "Sometimes")
+	if err := s.ValidEntity(); err == nil {
+		t.Errorf("expected an error for a value that is not allowed")
+	}
+	s = New(ImagePullPolicy)
+	s.Set(ScopePool, "", commons.PullNever)
+	if err := s.ValidEntity(); err == nil {
+		t.Errorf("expected an error for a pool value without a pool")
+	}
+	if err := New("no-such-setting").ValidEntity(); err == nil {
+		t.Errorf("expected an error for an unknown setting")
+	}
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		typ   Type
+		value string
+		valid bool
+	}{
+		{TypeString, "anything", true},
+		{TypeInt, "10", true},
+		{TypeInt, "ten", false},
+		{TypeBool, "true", true},
+		{TypeBool, "yes", false},
+		{TypeDuration, "90s", true},
+		{TypeDuration, "90", false},
+	} {
+		err := Definition{Name: "test", Type: tc.typ}.Validate(tc.value)
+		if tc.valid && err != nil {
+			t.Errorf("%s %q: unexpected error: %s", tc.typ, tc.value, err)
+		} else if !tc.valid && err == nil {
+			t.Errorf("%s %q: expected an error", tc.typ, tc.value)
+		}
+	}
+}
+
+func TestCache_Value(t *testing.T) {
+	var nilCache *Cache
+	if _, ok := nilCache.Value(ImagePullPolicy, "pool", ""); ok {
+		t.Errorf("expected no value from a nil cache")
+	}
+
+	c := NewCache()
+	if _, ok := c.Value(ImagePullPolicy, "pool", ""); ok {
+		t.Errorf("expected no value from an empty cache")
+	}
+	s := New(ImagePullPolicy)
+	s.Set(ScopePool, "pool", commons.PullAlways)
+	c.Update([]Setting{*s})
+	if value, ok := c.Value(ImagePullPolicy, "pool", ""); !ok || value != commons.PullAlways {
+		t.Errorf("expected %s, got %q", commons.PullAlways, value)
+	}
+	c.Update(nil)
+	if _, ok := c.Value(ImagePullPolicy, "pool", ""); ok {
+		t.Errorf("expected the value to be removed")
+	}
+}
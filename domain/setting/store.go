@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setting
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// NewStore creates a settings store
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Store type for interacting with setting persistent storage
+type Store interface {
+	datastore.EntityStore
+
+	// GetSettings returns all settings that have been set
+	GetSettings(ctx datastore.Context) ([]Setting, error)
+}
+
+type storeImpl struct {
+	datastore.DataStore
+}
+
+// GetSettings returns all settings that have been set
+func (s *storeImpl) GetSettings(ctx datastore.Context) ([]Setting, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SettingStore.GetSettings"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:ID")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting settings
+func Key(name string) datastore.Key {
+	name = strings.TrimSpace(name)
+	return datastore.NewKey(kind, name)
+}
+
+func convert(results datastore.Results) ([]Setting, error) {
+	settings := make([]Setting, results.Len())
+	for idx := range settings {
+		if err := results.Get(idx, &settings[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return settings, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setting
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates the setting fields
+func (s *Setting) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Setting.ID", s.ID))
+	def, ok := Lookup(s.ID)
+	if !ok {
+		violations.Add(fmt.Errorf("unknown setting %s", s.ID))
+	}
+	for _, v := range s.Values {
+		switch v.Scope {
+		case ScopeGlobal:
+			if v.ScopeID != "" {
+				violations.Add(fmt.Errorf("global value of setting %s cannot have a scope id", s.ID))
+			}
+		case ScopePool, ScopeDeployment:
+			violations.Add(validation.NotEmpty("Value.ScopeID", v.ScopeID))
+		default:
+			violations.Add(fmt.Errorf("unknown scope %q", v.Scope))
+		}
+		if ok {
+			violations.Add(def.Validate(v.Value))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+)
+
+// Operations reviewed by admission webhooks
+const (
+	AdmissionServiceAdd     = "service.add"
+	AdmissionServiceUpdate  = "service.update"
+	AdmissionEndpointUpdate = "endpoint.update"
+	AdmissionTemplateDeploy = "template.deploy"
+)
+
+// defaultAdmissionTimeout is how long to wait for a webhook that does not set
+// a timeout
+const defaultAdmissionTimeout = 10 * time.Second
+
+// AdmissionWebhook is an external endpoint that validates or mutates changes
+// to the control plane before they are applied.  Webhooks are called in the
+// order they are listed, and each one reviews the object as mutated by the
+// previous ones.
+type AdmissionWebhook struct {
+	Name           string
+	URL            string
+	Operations     []string // operations to review; all of them if empty
+	TimeoutSeconds int
+	FailOpen       bool // allow the change if the webhook cannot be reached
+}
+
+// reviews returns true if the webhook reviews the operation
+func (hook AdmissionWebhook) reviews(operation string) bool {
+	if len(hook.Operations) == 0 {
+		return true
+	}
+	for _, op := range hook.Operations {
+		if op == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (hook AdmissionWebhook) timeout() time.Duration {
+	if hook.TimeoutSeconds > 0 {
+		return time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	return defaultAdmissionTimeout
+}
+
+// AdmissionReview is the request posted to an admission webhook
+type AdmissionReview struct {
+	Operation string
+	User      string
+	Object    interface{}
+	OldObject interface{} `json:",omitempty"`
+}
+
+// AdmissionResponse is the reply of an admission webhook.  If Object is set,
+// it replaces the object under review.
+type AdmissionResponse struct {
+	Allowed bool
+	Message string
+	Object  json.RawMessage `json:",omitempty"`
+}
+
+// PublicEndpointChange is a change to a public endpoint under admission
+// review.  Action is add, remove or enable.
+type PublicEndpointChange struct {
+	ServiceID    string
+	EndpointName string
+	Action       string
+	PortAddress  string `json:",omitempty"`
+	VHostName    string `json:",omitempty"`
+	UseTLS       bool
+	Protocol     string
+	Enabled      bool
+}
+
+// TemplateDeployment is a template deploy under admission review.  Webhooks
+// may only mutate the template.
+type TemplateDeployment struct {
+	TemplateID   string
+	PoolID       string
+	DeploymentID string
+	Template     servicetemplate.ServiceTemplate
+}
+
+// AdmissionError is returned when an admission webhook denies a change, or
+// cannot be reached and does not fail open.
+type AdmissionError struct {
+	Webhook string
+	Message string
+}
+
+func (err AdmissionError) Error() string {
+	return fmt.Sprintf("denied by admission webhook %s: %s", err.Webhook, err.Message)
+}
+
+// LoadAdmissionWebhooks reads the list of admission webhooks from a json file
+func LoadAdmissionWebhooks(filename string) ([]AdmissionWebhook, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []AdmissionWebhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("could not parse admission webhooks in %s: %s", filename, err)
+	}
+	for i, hook := range hooks {
+		if hook.URL == "" {
+			return nil, fmt.Errorf("admission webhook %d in %s has no url", i, filename)
+		}
+		if hook.Name == "" {
+			hooks[i].Name = hook.URL
+		}
+		for _, op := range hook.Operations {
+			switch op {
+			case AdmissionServiceAdd, AdmissionServiceUpdate, AdmissionEndpointUpdate, AdmissionTemplateDeploy:
+			default:
+				return nil, fmt.Errorf("admission webhook %s in %s has unknown operation %q", hooks[i].Name, filename, op)
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// SetAdmissionWebhooks sets the webhooks that review control plane changes
+func (f *Facade) SetAdmissionWebhooks(hooks []AdmissionWebhook) { f.admissionWebhooks = hooks }
+
+// admit sends a change to the admission webhooks that review the operation.
+// obj must be a pointer; webhooks that mutate the change overwrite it.
+func (f *Facade) admit(ctx datastore.Context, operation string, obj, old interface{}) error {
+	for _, hook := range f.admissionWebhooks {
+		if !hook.reviews(operation) {
+			continue
+		}
+		logger := plog.WithFields(logrus.Fields{
+			"webhook":   hook.Name,
+			"operation": operation,
+		})
+
+		review := AdmissionReview{
+			Operation: operation,
+			User:      ctx.User(),
+			Object:    obj,
+			OldObject: old,
+		}
+		resp, err := postAdmissionReview(hook, review)
+		if err != nil {
+			if hook.FailOpen {
+				logger.WithError(err).Warn("Could not reach admission webhook, allowing change")
+				continue
+			}
+			logger.WithError(err).Warn("Could not reach admission webhook, denying change")
+			return AdmissionError{Webhook: hook.Name, Message: err.Error()}
+		}
+
+		if !resp.Allowed {
+			logger.WithField("message", resp.Message).Info("Admission webhook denied change")
+			return AdmissionError{Webhook: hook.Name, Message: resp.Message}
+		}
+		if len(resp.Object) > 0 {
+			if err := json.Unmarshal(resp.Object, obj); err != nil {
+				logger.WithError(err).Warn("Admission webhook returned an invalid object")
+				return AdmissionError{Webhook: hook.Name, Message: fmt.Sprintf("invalid object: %s", err)}
+			}
+			logger.Debug("Admission webhook mutated change")
+		}
+	}
+	return nil
+}
+
+// admitServiceUpdate sends a service update, along with the service as it is
+// stored, to the admission webhooks
+func (f *Facade) admitServiceUpdate(ctx datastore.Context, svc *service.Service) error {
+	reviewed := false
+	for _, hook := range f.admissionWebhooks {
+		reviewed = reviewed || hook.reviews(AdmissionServiceUpdate)
+	}
+	if !reviewed {
+		return nil
+	}
+	cursvc, err := f.serviceStore.Get(ctx, svc.ID)
+	if err != nil {
+		return err
+	}
+	return f.admit(ctx, AdmissionServiceUpdate, svc, cursvc)
+}
+
+// postAdmissionReview posts a review to a webhook and returns its response
+func postAdmissionReview(hook AdmissionWebhook, review AdmissionReview) (*AdmissionResponse, error) {
+	body, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: hook.timeout()}
+	r, err := client.Post(hook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	} else if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received %d status code", r.StatusCode)
+	}
+
+	resp := &AdmissionResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
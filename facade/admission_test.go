@@ -0,0 +1,173 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	datastoremocks "github.com/control-center/serviced/datastore/mocks"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&AdmissionTest{})
+
+type AdmissionTest struct {
+	ctx *datastoremocks.Context
+}
+
+func (t *AdmissionTest) SetUpTest(c *C) {
+	t.ctx = &datastoremocks.Context{}
+	t.ctx.On("User").Return("admin")
+}
+
+// admissionServer returns a webhook server that records the reviews it
+// receives and replies with the given response
+func admissionServer(resp AdmissionResponse, reviews *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&review)
+		*reviews = append(*reviews, review)
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func (t *AdmissionTest) Test_LoadAdmissionWebhooks(c *C) {
+	dir := c.MkDir()
+	write := func(data string) string {
+		filename := filepath.Join(dir, "webhooks.json")
+		c.Assert(ioutil.WriteFile(filename, []byte(data), 0644), IsNil)
+		return filename
+	}
+
+	hooks, err := LoadAdmissionWebhooks(write(`[{"URL": "http://policy:8080/admit", "Operations": ["service.add"]}]`))
+	c.Assert(err, IsNil)
+	c.Assert(hooks, HasLen, 1)
+	c.Assert(hooks[0].Name, Equals, "http://policy:8080/admit")
+	c.Assert(hooks[0].timeout(), Equals, defaultAdmissionTimeout)
+
+	_, err = LoadAdmissionWebhooks(write(`[{"Name": "policy"}]`))
+	c.Assert(err, NotNil)
+	_, err = LoadAdmissionWebhooks(write(`[{"URL": "http://policy", "Operations": ["pool.add"]}]`))
+	c.Assert(err, NotNil)
+	_, err = LoadAdmissionWebhooks(write(`{`))
+	c.Assert(err, NotNil)
+	_, err = LoadAdmissionWebhooks(filepath.Join(dir, "missing.json"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (t *AdmissionTest) Test_AdmitAllowed(c *C) {
+	var reviews []map[string]interface{}
+	server := admissionServer(AdmissionResponse{Allowed: true}, &reviews)
+	defer server.Close()
+
+	f := &Facade{admissionWebhooks: []AdmissionWebhook{{Name: "policy", URL: server.URL}}}
+	svc := &service.Service{ID: "svc1", Name: "svc"}
+	err := f.admit(t.ctx, AdmissionServiceAdd, svc, nil)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Name, Equals, "svc")
+	c.Assert(reviews, HasLen, 1)
+	c.Assert(reviews[0]["Operation"], Equals, AdmissionServiceAdd)
+	c.Assert(reviews[0]["User"], Equals, "admin")
+	_, ok := reviews[0]["OldObject"]
+	c.Assert(ok, Equals, false)
+}
+
+func (t *AdmissionTest) Test_AdmitDenied(c *C) {
+	var reviews, skipped []map[string]interface{}
+	deny := admissionServer(AdmissionResponse{Allowed: false, Message: "no root images"}, &reviews)
+	defer deny.Close()
+	after := admissionServer(AdmissionResponse{Allowed: true}, &skipped)
+	defer after.Close()
+
+	f := &Facade{admissionWebhooks: []AdmissionWebhook{
+		{Name: "policy", URL: deny.URL},
+		{Name: "after", URL: after.URL},
+	}}
+	err := f.admit(t.ctx, AdmissionServiceAdd, &service.Service{ID: "svc1"}, nil)
+	c.Assert(err, DeepEquals, AdmissionError{Webhook: "policy", Message: "no root images"})
+	c.Assert(reviews, HasLen, 1)
+	c.Assert(skipped, HasLen, 0)
+}
+
+func (t *AdmissionTest) Test_AdmitMutated(c *C) {
+	var first, second []map[string]interface{}
+	mutate := admissionServer(AdmissionResponse{
+		Allowed: true,
+		Object:  json.RawMessage(`{"ID": "svc1", "Name": "svc", "Instances": 3}`),
+	}, &first)
+	defer mutate.Close()
+	check := admissionServer(AdmissionResponse{Allowed: true}, &second)
+	defer check.Close()
+
+	f := &Facade{admissionWebhooks: []AdmissionWebhook{
+		{Name: "mutate", URL: mutate.URL},
+		{Name: "check", URL: check.URL},
+	}}
+	svc := &service.Service{ID: "svc1", Name: "svc", Instances: 1}
+	err := f.admit(t.ctx, AdmissionServiceUpdate, svc, &service.Service{ID: "svc1"})
+	c.Assert(err, IsNil)
+	c.Assert(svc.Instances, Equals, 3)
+
+	// later webhooks review the mutated object
+	c.Assert(second, HasLen, 1)
+	obj := second[0]["Object"].(map[string]interface{})
+	c.Assert(obj["Instances"], Equals, float64(3))
+	_, ok := second[0]["OldObject"]
+	c.Assert(ok, Equals, true)
+}
+
+func (t *AdmissionTest) Test_AdmitOperations(c *C) {
+	var reviews []map[string]interface{}
+	server := admissionServer(AdmissionResponse{Allowed: false, Message: "denied"}, &reviews)
+	defer server.Close()
+
+	f := &Facade{admissionWebhooks: []AdmissionWebhook{
+		{Name: "endpoints", URL: server.URL, Operations: []string{AdmissionEndpointUpdate}},
+	}}
+	err := f.admit(t.ctx, AdmissionServiceAdd, &service.Service{ID: "svc1"}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(reviews, HasLen, 0)
+
+	change := &PublicEndpointChange{ServiceID: "svc1", EndpointName: "www", Action: "add", VHostName: "www"}
+	err = f.admit(t.ctx, AdmissionEndpointUpdate, change, nil)
+	c.Assert(err, NotNil)
+	c.Assert(reviews, HasLen, 1)
+
+	// service updates are not looked up unless a webhook reviews them
+	err = f.admitServiceUpdate(t.ctx, &service.Service{ID: "svc1"})
+	c.Assert(err, IsNil)
+}
+
+func (t *AdmissionTest) Test_AdmitUnreachable(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	f := &Facade{admissionWebhooks: []AdmissionWebhook{{Name: "policy", URL: server.URL}}}
+	err := f.admit(t.ctx, AdmissionServiceAdd, &service.Service{ID: "svc1"}, nil)
+	c.Assert(err, FitsTypeOf, AdmissionError{})
+
+	f.admissionWebhooks[0].FailOpen = true
+	err = f.admit(t.ctx, AdmissionServiceAdd, &service.Service{ID: "svc1"}, nil)
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,33 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+)
+
+// GetAuditEntries returns the audit log entries of the mutating operations
+// that were performed at or after the given time, oldest first.
+func (f *Facade) GetAuditEntries(ctx datastore.Context, since time.Time) ([]audit.Entry, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAuditEntries"))
+	entries, err := f.auditStore.GetEntries(ctx, since)
+	if err != nil {
+		plog.WithError(err).WithField("since", since).Debug("Could not look up audit entries")
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,610 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+)
+
+const (
+	// BackupOperation is the operation of a backup in progress
+	BackupOperation = "backup"
+
+	// RestoreOperation is the operation of a restore in progress
+	RestoreOperation = "restore"
+
+	// PartialBackupSuffix is appended to the name of a backup file until the
+	// backup is complete
+	PartialBackupSuffix = ".partial"
+
+	// backupOperationsDir is the directory in the backups path that holds
+	// the state of the backups and restores that can be resumed
+	backupOperationsDir = ".operations"
+
+	// rollbackSection is the checkpoint section of the snapshots that were
+	// rolled back by a restore
+	rollbackSection = "ROLLBACK"
+
+	// backupLayersDir is the directory in the backups path that holds the
+	// docker image layers that backups share
+	backupLayersDir = ".layers"
+)
+
+var (
+	// ErrBackupOperationNotFound is returned when the state of a backup or
+	// restore does not exist
+	ErrBackupOperationNotFound = errors.New("facade: backup operation not found")
+)
+
+// backupOperation is the persisted state of a backup or restore
+type backupOperation struct {
+	dao.BackupOperation
+	Info   *dfs.BackupInfo `json:",omitempty"`
+	Layers []string        `json:",omitempty"`
+	Rename *restoreRename  `json:",omitempty"`
+	// EstimatedBytes is the estimated size of the backup file
+	EstimatedBytes int64 `json:",omitempty"`
+}
+
+// Completed implements dfs.Checkpoint
+func (op *backupOperation) Completed(section string) bool {
+	for _, s := range op.BackupOperation.Completed {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// checkpoint records a completed section and the offset of the backup file
+// where the next section starts
+func (op *backupOperation) checkpoint(section string, offset int64) error {
+	op.BackupOperation.Completed = append(op.BackupOperation.Completed, section)
+	op.Offset = offset
+	return saveBackupOperation(op)
+}
+
+// partialFilename returns the name of the backup file while it is written
+func (op *backupOperation) partialFilename() string {
+	return op.Filename + PartialBackupSuffix
+}
+
+// backupLayerStore returns the store of the docker image layers of backups
+func backupLayerStore() *dfs.FileLayerStore {
+	return dfs.NewFileLayerStore(filepath.Join(config.GetOptions().BackupsPath, backupLayersDir))
+}
+
+func backupOperationPath(id string) string {
+	return filepath.Join(config.GetOptions().BackupsPath, backupOperationsDir, id+".json")
+}
+
+// saveBackupOperation atomically writes the state of an operation
+func saveBackupOperation(op *backupOperation) error {
+	op.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	filename := backupOperationPath(op.ID)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+// loadBackupOperation reads the state of an operation
+func loadBackupOperation(id string) (*backupOperation, error) {
+	data, err := ioutil.ReadFile(backupOperationPath(id))
+	if os.IsNotExist(err) {
+		return nil, ErrBackupOperationNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	op := &backupOperation{}
+	if err := json.Unmarshal(data, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// removeBackupOperation deletes the state of an operation
+func removeBackupOperation(id string) error {
+	if err := os.Remove(backupOperationPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// compression returns the codec of the backup file of an operation
+func (op *backupOperation) compression() volume.Compression {
+	if op.Info == nil {
+		return volume.DefaultCompression
+	}
+	return op.Info.Compression
+}
+
+// newBackupDecompressor returns the reader that decompresses a backup file
+// with the codec that it was compressed with, and the codec.  If the metadata
+// of the backup is known, the codec must match the codec that it records.
+func newBackupDecompressor(r io.Reader, info *dfs.BackupInfo) (io.ReadCloser, volume.Compression, error) {
+	zr, codec, err := volume.NewDecompressor(r)
+	if err != nil {
+		return nil, "", err
+	}
+	if info != nil && info.Compression != "" && info.Compression != codec {
+		zr.Close()
+		return nil, "", dfs.ErrBackupCompressionMismatch
+	}
+	return zr, codec, nil
+}
+
+// backupFileWriter compresses a backup into a file.  Each checkpoint ends a
+// compressed stream of the file, so that a resumed backup can truncate the
+// file at the last checkpoint and append new streams to it.
+type backupFileWriter struct {
+	*dfs.FileLayerStore
+	fh       *os.File
+	out      io.Writer
+	zw       io.WriteCloser
+	op       *backupOperation
+	progress *backupProgress
+}
+
+func newBackupFileWriter(op *backupOperation, progress *backupProgress) (*backupFileWriter, error) {
+	fh, err := os.OpenFile(op.partialFilename(), os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := fh.Truncate(op.Offset); err != nil {
+		fh.Close()
+		return nil, err
+	}
+	if _, err := fh.Seek(op.Offset, io.SeekStart); err != nil {
+		fh.Close()
+		return nil, err
+	}
+	out := &progressWriter{w: fh, progress: progress}
+	zw, err := volume.NewCompressor(out, op.compression())
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return &backupFileWriter{
+		FileLayerStore: backupLayerStore(),
+		fh:             fh,
+		out:            out,
+		zw:             zw,
+		op:             op,
+		progress:       progress,
+	}, nil
+}
+
+func (w *backupFileWriter) Write(p []byte) (int, error) {
+	return w.zw.Write(p)
+}
+
+// PutLayer implements dfs.LayerStore
+func (w *backupFileWriter) PutLayer(r io.Reader) (string, error) {
+	digest, err := w.FileLayerStore.PutLayer(r)
+	if err != nil {
+		return "", err
+	}
+	for _, d := range w.op.Layers {
+		if d == digest {
+			return digest, nil
+		}
+	}
+	w.op.Layers = append(w.op.Layers, digest)
+	return digest, nil
+}
+
+// Completed implements dfs.Checkpoint
+func (w *backupFileWriter) Completed(section string) bool {
+	return w.op.Completed(section)
+}
+
+// Checkpoint implements dfs.Checkpoint
+func (w *backupFileWriter) Checkpoint(section string) error {
+	if err := w.zw.Close(); err != nil {
+		return err
+	}
+	if err := w.fh.Sync(); err != nil {
+		return err
+	}
+	offset, err := w.fh.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if err := w.op.checkpoint(section, offset); err != nil {
+		return err
+	}
+	zw, err := volume.NewCompressor(w.out, w.op.compression())
+	if err != nil {
+		return err
+	}
+	w.zw = zw
+	return nil
+}
+
+// Phase implements dfs.PhaseReporter
+func (w *backupFileWriter) Phase(phase string) {
+	w.progress.Phase(phase)
+}
+
+// Close ends the last compressed stream and closes the file
+func (w *backupFileWriter) Close() error {
+	if err := w.zw.Close(); err != nil {
+		w.fh.Close()
+		return err
+	}
+	return w.fh.Close()
+}
+
+// backupFileReader decompresses a backup file for a restore
+type backupFileReader struct {
+	*dfs.FileLayerStore
+	zr       io.Reader
+	op       *backupOperation
+	progress *backupProgress
+}
+
+func (r *backupFileReader) Read(p []byte) (int, error) {
+	return r.zr.Read(p)
+}
+
+// Completed implements dfs.Checkpoint
+func (r *backupFileReader) Completed(section string) bool {
+	return r.op.Completed(section)
+}
+
+// Checkpoint implements dfs.Checkpoint
+func (r *backupFileReader) Checkpoint(section string) error {
+	return r.op.checkpoint(section, 0)
+}
+
+// RenameTenant implements dfs.TenantRenamer
+func (r *backupFileReader) RenameTenant(tenantID string) string {
+	if r.op.Rename == nil {
+		return tenantID
+	}
+	return r.op.Rename.RenameTenant(tenantID)
+}
+
+func (r *backupFileReader) restoreRename() *restoreRename {
+	return r.op.Rename
+}
+
+// Phase implements dfs.PhaseReporter
+func (r *backupFileReader) Phase(phase string) {
+	r.progress.Phase(phase)
+}
+
+// BackupToFile takes a backup of all installed applications and compresses
+// it into a file.  If the backup fails after its snapshots were taken, it can
+// be continued with ResumeBackupOperation.  The estimated size of the file
+// is used to report the progress of the backup.
+func (f *Facade) BackupToFile(ctx datastore.Context, excludes []string, snapshotSpacePercent int, filename string, estimatedBytes uint64, compression volume.Compression) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.BackupToFile"))
+	// Do not DFSLock here, ControlPlaneDao does that
+	data, err := f.prepareBackup(ctx, excludes, snapshotSpacePercent, filename)
+	if err != nil {
+		return err
+	}
+	data.Compression = compression
+	now := time.Now().UTC()
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        volume.TrimArchiveExtension(filepath.Base(filename)),
+			Operation: BackupOperation,
+			Filename:  filename,
+			StartedAt: now,
+		},
+		Info:           data,
+		EstimatedBytes: int64(estimatedBytes),
+	}
+	if err := saveBackupOperation(op); err != nil {
+		plog.WithError(err).Debug("Could not save the state of the backup")
+		for _, snapshot := range data.Snapshots {
+			f.deleteBackupSnapshot(ctx, snapshot)
+		}
+		f.deleteElasticSnapshots(data.ElasticSnapshots)
+		return err
+	}
+	return f.runBackupOperation(ctx, op)
+}
+
+// runBackupOperation writes the backup file of an operation from its last
+// checkpoint
+func (f *Facade) runBackupOperation(ctx datastore.Context, op *backupOperation) (err error) {
+	logger := plog.WithField("operation", op.ID)
+	defer func() { f.endBackupOperation(op, err) }()
+	f.backupProgress.start(op, op.Offset, op.EstimatedBytes, operationFromContext(ctx))
+	w, err := newBackupFileWriter(op, f.backupProgress)
+	if err != nil {
+		logger.WithError(err).Debug("Could not open backup file")
+		return err
+	}
+	if err = f.writeBackup(ctx, w, op.Info, op.Filename); err != nil {
+		w.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		logger.WithError(err).Debug("Could not close backup file")
+		return err
+	}
+	if err = w.Reference(op.Filename, op.Layers); err != nil {
+		logger.WithError(err).Debug("Could not reference the image layers of the backup")
+		return err
+	}
+	if err = os.Rename(op.partialFilename(), op.Filename); err != nil {
+		logger.WithError(err).Debug("Could not rename backup file")
+		return err
+	}
+	for _, snapshot := range op.Info.Snapshots {
+		f.deleteBackupSnapshot(ctx, snapshot)
+	}
+	f.deleteElasticSnapshots(op.Info.ElasticSnapshots)
+	if _, _, err := f.pruneBackupLayers(); err != nil {
+		logger.WithError(err).Warn("Could not prune the image layers of deleted backups")
+	}
+	return nil
+}
+
+// PruneBackupLayers removes the docker image layers that are no longer
+// included in any backup file, and returns the number of layers removed and
+// the number of bytes freed.
+func (f *Facade) PruneBackupLayers(ctx datastore.Context) (int, int64, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PruneBackupLayers"))
+	// backups add layers before they reference them
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("prune backup layers")
+	defer dfslocker.Unlock()
+	return f.pruneBackupLayers()
+}
+
+func (f *Facade) pruneBackupLayers() (int, int64, error) {
+	count, freed, err := backupLayerStore().Prune(func(backup string) bool {
+		_, err := os.Stat(backup)
+		return !os.IsNotExist(err)
+	})
+	if err != nil {
+		return count, freed, err
+	}
+	if count > 0 {
+		plog.WithFields(logrus.Fields{
+			"layers": count,
+			"freed":  freed,
+		}).Info("Pruned the image layers of deleted backups")
+	}
+	return count, freed, nil
+}
+
+// VerifyBackupFile checks the integrity of a compressed backup file without
+// restoring it.  If testRestore is true, the metadata of its snapshots is
+// read into a temporary directory as it would be restored.
+func (f *Facade) VerifyBackupFile(ctx datastore.Context, filename string, testRestore bool) (*dfs.BackupVerification, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.VerifyBackupFile"))
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	result := &dfs.BackupVerification{}
+	zr, codec, err := newBackupDecompressor(fh, nil)
+	if err != nil {
+		result.Errors = []string{fmt.Sprintf("could not read backup: %s", err)}
+		return result, nil
+	}
+	defer zr.Close()
+	r := &backupVerifyReader{Reader: zr, FileLayerStore: backupLayerStore()}
+	result, err = dfs.VerifyBackup(r, testRestore)
+	if err != nil {
+		return nil, err
+	}
+	if result.Compression != "" && result.Compression != codec {
+		result.Errors = append(result.Errors, fmt.Sprintf("backup is compressed with %s, but its manifest says %s", codec, result.Compression))
+	}
+	result.Compression = codec
+	return result, nil
+}
+
+// backupVerifyReader decompresses a backup file for a verification
+type backupVerifyReader struct {
+	io.Reader
+	*dfs.FileLayerStore
+}
+
+// RestoreFromFile restores application data from a compressed backup file.
+// If the restore fails, it can be continued with ResumeBackupOperation.
+func (f *Facade) RestoreFromFile(ctx datastore.Context, filename string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestoreFromFile"))
+	// Do not DFSLock here, ControlPlaneDao does that
+	now := time.Now().UTC()
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        now.Format("restore-2006-01-02-150405"),
+			Operation: RestoreOperation,
+			Filename:  filename,
+			StartedAt: now,
+		},
+	}
+	if err := saveBackupOperation(op); err != nil {
+		plog.WithError(err).Debug("Could not save the state of the restore")
+		return err
+	}
+	return f.runRestoreOperation(ctx, op)
+}
+
+// runRestoreOperation restores the backup file of an operation, skipping
+// the sections that were checkpointed
+func (f *Facade) runRestoreOperation(ctx datastore.Context, op *backupOperation) (err error) {
+	defer func() { f.endBackupOperation(op, err) }()
+	var size int64
+	if fi, err := os.Stat(op.Filename); err == nil {
+		size = fi.Size()
+	}
+	f.backupProgress.start(op, 0, size, operationFromContext(ctx))
+	info, err := dfs.ExtractBackupInfo(op.Filename)
+	if err != nil {
+		return err
+	}
+	if op.Rename != nil && op.Rename.Tenants == nil {
+		// the new tenant ids must not change if the restore is resumed
+		if err := op.Rename.mapTenants(info.Snapshots); err != nil {
+			return err
+		}
+		if err := saveBackupOperation(op); err != nil {
+			return err
+		}
+	}
+	fh, err := os.Open(op.Filename)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	zr, _, err := newBackupDecompressor(&progressReader{r: fh, progress: f.backupProgress}, info)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	r := &backupFileReader{FileLayerStore: backupLayerStore(), zr: zr, op: op, progress: f.backupProgress}
+	return f.Restore(ctx, r, info, op.Filename)
+}
+
+// endBackupOperation removes the state of an operation that succeeded, or
+// records the error of an operation that failed.
+func (f *Facade) endBackupOperation(op *backupOperation, err error) {
+	f.backupProgress.end(err)
+	logger := plog.WithField("operation", op.ID)
+	if err == nil {
+		if err := removeBackupOperation(op.ID); err != nil {
+			logger.WithError(err).Warn("Could not remove the state of a completed operation")
+		}
+		return
+	}
+	op.Error = err.Error()
+	if err := saveBackupOperation(op); err != nil {
+		logger.WithError(err).Warn("Could not save the state of a failed operation")
+		return
+	}
+	logger.WithError(err).Info("Operation can be resumed")
+}
+
+// GetBackupOperations returns the backups and restores that did not complete
+func (f *Facade) GetBackupOperations(ctx datastore.Context) ([]dao.BackupOperation, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetBackupOperations"))
+	ops := []dao.BackupOperation{}
+	dirname := filepath.Join(config.GetOptions().BackupsPath, backupOperationsDir)
+	fis, err := ioutil.ReadDir(dirname)
+	if os.IsNotExist(err) {
+		return ops, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".json" {
+			continue
+		}
+		op, err := loadBackupOperation(strings.TrimSuffix(fi.Name(), ".json"))
+		if err != nil {
+			plog.WithError(err).WithField("file", fi.Name()).Warn("Could not load the state of an operation")
+			continue
+		}
+		ops = append(ops, op.BackupOperation)
+	}
+	return ops, nil
+}
+
+// ResumeBackupOperation continues a backup or restore from its last
+// checkpoint and returns the name of its backup file.
+func (f *Facade) ResumeBackupOperation(ctx datastore.Context, id string) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ResumeBackupOperation"))
+	op, err := loadBackupOperation(id)
+	if err != nil {
+		return "", err
+	}
+	logger := plog.WithFields(logrus.Fields{
+		"operation": op.ID,
+		"completed": op.BackupOperation.Completed,
+	})
+
+	endOperation, err := f.BeginDFSOperation(ctx, op.Operation, op.Filename)
+	if err != nil {
+		return "", err
+	}
+	defer endOperation()
+
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("resume " + op.Operation)
+	defer dfslocker.Unlock()
+
+	op.Error = ""
+	switch op.Operation {
+	case BackupOperation:
+		logger.Info("Resuming backup")
+		err = f.runBackupOperation(ctx, op)
+	case RestoreOperation:
+		logger.Info("Resuming restore")
+		err = f.runRestoreOperation(ctx, op)
+	default:
+		err = fmt.Errorf("unknown operation %s", op.Operation)
+	}
+	return op.Filename, err
+}
+
+// DiscardBackupOperation abandons a backup or restore that did not
+// complete.  The snapshots and the partial file of a backup are deleted.
+func (f *Facade) DiscardBackupOperation(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DiscardBackupOperation"))
+	op, err := loadBackupOperation(id)
+	if err != nil {
+		return err
+	}
+
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("discard " + op.Operation)
+	defer dfslocker.Unlock()
+
+	if op.Operation == BackupOperation {
+		if op.Info != nil {
+			for _, snapshot := range op.Info.Snapshots {
+				f.deleteBackupSnapshot(ctx, snapshot)
+			}
+			f.deleteElasticSnapshots(op.Info.ElasticSnapshots)
+		}
+		if err := os.Remove(op.partialFilename()); err != nil && !os.IsNotExist(err) {
+			plog.WithError(err).WithField("file", op.partialFilename()).Debug("Could not remove partial backup file")
+			return err
+		}
+	}
+	return removeBackupOperation(op.ID)
+}
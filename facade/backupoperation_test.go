@@ -0,0 +1,186 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+	gzip "github.com/klauspost/pgzip"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&BackupOperationTest{})
+
+type BackupOperationTest struct {
+	tmpdir  string
+	options config.Options
+}
+
+func (t *BackupOperationTest) SetUpTest(c *C) {
+	var err error
+	t.tmpdir, err = ioutil.TempDir("", "backupoperation")
+	c.Assert(err, IsNil)
+	t.options = config.GetOptions()
+	options := t.options
+	options.BackupsPath = t.tmpdir
+	config.LoadOptions(options)
+}
+
+func (t *BackupOperationTest) TearDownTest(c *C) {
+	config.LoadOptions(t.options)
+	os.RemoveAll(t.tmpdir)
+}
+
+func (t *BackupOperationTest) Test_BackupFileWriter_Resume(c *C) {
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        "backup-test",
+			Operation: BackupOperation,
+			Filename:  filepath.Join(t.tmpdir, "backup-test.tgz"),
+		},
+	}
+	w, err := newBackupFileWriter(op, nil)
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte("first section;"))
+	c.Assert(err, IsNil)
+	c.Assert(w.Checkpoint("first"), IsNil)
+
+	// interrupt the backup while it writes the second section
+	_, err = w.Write([]byte("lost section;"))
+	c.Assert(err, IsNil)
+	w.zw.(*gzip.Writer).Flush()
+	w.fh.Close()
+
+	op, err = loadBackupOperation("backup-test")
+	c.Assert(err, IsNil)
+	c.Assert(op.Completed("first"), Equals, true)
+	c.Assert(op.Completed("lost"), Equals, false)
+	c.Assert(op.Offset > 0, Equals, true)
+
+	// the resumed backup overwrites everything after the checkpoint
+	w, err = newBackupFileWriter(op, nil)
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte("second section;"))
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+
+	fh, err := os.Open(op.partialFilename())
+	c.Assert(err, IsNil)
+	defer fh.Close()
+	gz, err := gzip.NewReader(fh)
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(gz)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "first section;second section;")
+}
+
+func (t *BackupOperationTest) Test_BackupFileWriter_Compression(c *C) {
+	if _, err := exec.LookPath(volume.ZstdCommand); err != nil {
+		c.Skip("zstd is not installed")
+	}
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        "backup-zstd",
+			Operation: BackupOperation,
+			Filename:  filepath.Join(t.tmpdir, "backup-zstd.tar.zst"),
+		},
+		Info: &dfs.BackupInfo{Compression: volume.CompressionZstd},
+	}
+	w, err := newBackupFileWriter(op, nil)
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte("first section;"))
+	c.Assert(err, IsNil)
+	c.Assert(w.Checkpoint("first"), IsNil)
+	_, err = w.Write([]byte("second section;"))
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+
+	fh, err := os.Open(op.partialFilename())
+	c.Assert(err, IsNil)
+	defer fh.Close()
+	zr, codec, err := newBackupDecompressor(fh, op.Info)
+	c.Assert(err, IsNil)
+	defer zr.Close()
+	c.Assert(codec, Equals, volume.CompressionZstd)
+	data, err := ioutil.ReadAll(zr)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "first section;second section;")
+
+	// the codec must match the metadata of the backup
+	_, err = fh.Seek(0, io.SeekStart)
+	c.Assert(err, IsNil)
+	_, _, err = newBackupDecompressor(fh, &dfs.BackupInfo{Compression: volume.CompressionGzip})
+	c.Assert(err, Equals, dfs.ErrBackupCompressionMismatch)
+}
+
+func (t *BackupOperationTest) Test_BackupOperations_SaveLoadRemove(c *C) {
+	_, err := loadBackupOperation("restore-test")
+	c.Assert(err, Equals, ErrBackupOperationNotFound)
+
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        "restore-test",
+			Operation: RestoreOperation,
+			Filename:  "/backups/backup.tgz",
+		},
+	}
+	r := &backupFileReader{op: op}
+	c.Assert(r.Checkpoint("SNAPSHOTS/tenant/label"), IsNil)
+
+	actual, err := loadBackupOperation("restore-test")
+	c.Assert(err, IsNil)
+	c.Assert(actual.Completed("SNAPSHOTS/tenant/label"), Equals, true)
+	c.Assert(actual.Filename, Equals, "/backups/backup.tgz")
+
+	c.Assert(removeBackupOperation("restore-test"), IsNil)
+	_, err = loadBackupOperation("restore-test")
+	c.Assert(err, Equals, ErrBackupOperationNotFound)
+}
+
+func (t *BackupOperationTest) Test_BackupFileWriter_PutLayer(c *C) {
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        "backup-layers",
+			Operation: BackupOperation,
+			Filename:  filepath.Join(t.tmpdir, "backup-layers.tgz"),
+		},
+	}
+	w, err := newBackupFileWriter(op, nil)
+	c.Assert(err, IsNil)
+	defer w.Close()
+
+	// the backup references each layer once
+	digest, err := w.PutLayer(strings.NewReader("layer"))
+	c.Assert(err, IsNil)
+	again, err := w.PutLayer(strings.NewReader("layer"))
+	c.Assert(err, IsNil)
+	c.Assert(again, Equals, digest)
+	c.Assert(op.Layers, DeepEquals, []string{digest})
+
+	rc, _, err := backupLayerStore().GetLayer(digest)
+	c.Assert(err, IsNil)
+	rc.Close()
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+)
+
+// backupProgress tracks the last backup or restore, so that clients can
+// poll its progress.  Its methods do nothing on a nil tracker.
+type backupProgress struct {
+	mu         sync.Mutex
+	current    dao.BackupProgress
+	startBytes int64
+	running    *Operation // reports the progress to the operation, if any
+	now        func() time.Time
+}
+
+func newBackupProgress() *backupProgress {
+	return &backupProgress{now: time.Now}
+}
+
+// start begins tracking an operation.  bytes is the number of bytes of the
+// backup file that were processed by a previous attempt.  The progress is
+// also reported to the running operation, which may be nil.
+func (p *backupProgress) start(op *backupOperation, bytes, total int64, running *Operation) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = dao.BackupProgress{
+		ID:         op.ID,
+		Operation:  op.Operation,
+		Filename:   op.Filename,
+		Bytes:      bytes,
+		TotalBytes: total,
+		StartedAt:  p.now().UTC(),
+		Running:    true,
+	}
+	p.startBytes = bytes
+	p.running = running
+}
+
+// Phase implements dfs.PhaseReporter
+func (p *backupProgress) Phase(phase string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current.Phase = phase
+	p.running.SetProgress(p.current.Percent(), phase)
+	p.running.Logf("%s", phase)
+}
+
+// add counts bytes of the backup file that were written or read
+func (p *backupProgress) add(n int) {
+	if p == nil || n <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current.Bytes += int64(n)
+	p.running.SetProgress(p.current.Percent(), p.current.Phase)
+}
+
+// canceled returns true if the running operation was asked to be canceled
+func (p *backupProgress) canceled() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running.IsCanceled()
+}
+
+// end stops tracking the operation
+func (p *backupProgress) end(err error) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current.Running = false
+	p.running = nil
+	if err != nil {
+		p.current.Error = err.Error()
+	}
+}
+
+// get returns the progress of the operation.  The ETA assumes that the rest
+// of the file is processed at the rate of this attempt so far.
+func (p *backupProgress) get() dao.BackupProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	progress := p.current
+	done := progress.Bytes - p.startBytes
+	remaining := progress.TotalBytes - progress.Bytes
+	if progress.Running && done > 0 && remaining > 0 {
+		elapsed := p.now().Sub(progress.StartedAt)
+		progress.ETA = time.Duration(float64(elapsed) * float64(remaining) / float64(done))
+	}
+	return progress
+}
+
+// progressWriter counts the bytes written to a backup file
+type progressWriter struct {
+	w        io.Writer
+	progress *backupProgress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	if w.progress.canceled() {
+		return 0, ErrOperationCanceled
+	}
+	n, err := w.w.Write(p)
+	w.progress.add(n)
+	return n, err
+}
+
+// progressReader counts the bytes read from a backup file
+type progressReader struct {
+	r        io.Reader
+	progress *backupProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if r.progress.canceled() {
+		return 0, ErrOperationCanceled
+	}
+	n, err := r.r.Read(p)
+	r.progress.add(n)
+	return n, err
+}
+
+// GetBackupProgress returns the progress of the backup or restore that is
+// running, or of the last one if none is running.
+func (f *Facade) GetBackupProgress(ctx datastore.Context) (*dao.BackupProgress, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetBackupProgress"))
+	if f.backupProgress == nil {
+		return &dao.BackupProgress{}, nil
+	}
+	progress := f.backupProgress.get()
+	return &progress, nil
+}
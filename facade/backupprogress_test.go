@@ -0,0 +1,103 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&BackupProgressTest{})
+
+type BackupProgressTest struct{}
+
+func (t *BackupProgressTest) TestBackupProgress(c *C) {
+	now := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+	p := newBackupProgress()
+	p.now = func() time.Time { return now }
+
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        "backup-test",
+			Operation: BackupOperation,
+			Filename:  "/backups/backup-test.tgz",
+		},
+	}
+	// a resumed backup already wrote 100 bytes
+	p.start(op, 100, 1100, nil)
+	p.Phase(dfs.PhaseVolumes)
+	progress := p.get()
+	c.Assert(progress.Running, Equals, true)
+	c.Assert(progress.Phase, Equals, dfs.PhaseVolumes)
+	c.Assert(progress.Bytes, Equals, int64(100))
+	c.Assert(progress.ETA, Equals, time.Duration(0))
+
+	// the rest of the file is estimated at the rate of this attempt
+	w := &progressWriter{w: &bytes.Buffer{}, progress: p}
+	_, err := w.Write(make([]byte, 250))
+	c.Assert(err, IsNil)
+	now = now.Add(time.Minute)
+	progress = p.get()
+	c.Assert(progress.Bytes, Equals, int64(350))
+	c.Assert(progress.Percent(), Equals, float64(350)*100/1100)
+	c.Assert(progress.ETA, Equals, 3*time.Minute)
+
+	p.end(errors.New("no space left on device"))
+	progress = p.get()
+	c.Assert(progress.Running, Equals, false)
+	c.Assert(progress.Error, Equals, "no space left on device")
+	c.Assert(progress.ETA, Equals, time.Duration(0))
+
+	// a nil tracker does nothing
+	var none *backupProgress
+	none.start(op, 0, 0, nil)
+	none.Phase(dfs.PhaseImages)
+	none.add(10)
+	none.end(nil)
+	r := &progressReader{r: bytes.NewBufferString("data"), progress: none}
+	n, err := r.Read(make([]byte, 10))
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 4)
+}
+
+func (t *BackupProgressTest) TestBackupProgressOperation(c *C) {
+	r := newOperationRegistry()
+	running := r.start(BackupOperation, "/backups", "admin")
+	p := newBackupProgress()
+	p.start(&backupOperation{BackupOperation: dao.BackupOperation{ID: "backup-test", Operation: BackupOperation}}, 0, 1000, running)
+
+	// the progress is reported to the operation
+	p.Phase(dfs.PhaseVolumes)
+	w := &progressWriter{w: &bytes.Buffer{}, progress: p}
+	_, err := w.Write(make([]byte, 250))
+	c.Assert(err, IsNil)
+	state := running.get()
+	c.Assert(state.Progress, Equals, float64(25))
+	c.Assert(state.Message, Equals, dfs.PhaseVolumes)
+	c.Assert(state.Logs, HasLen, 1)
+
+	// the backup file stops at the next write once the operation is canceled
+	c.Assert(r.cancel(state.ID), IsNil)
+	_, err = w.Write(make([]byte, 250))
+	c.Assert(err, Equals, ErrOperationCanceled)
+	c.Assert(p.get().Bytes, Equals, int64(250))
+}
@@ -0,0 +1,234 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	// ErrBackupScheduleExists is returned when adding a backup schedule that
+	// already exists
+	ErrBackupScheduleExists = errors.New("facade: backup schedule exists")
+	// ErrBackupScheduleNotFound is returned when a backup schedule does not
+	// exist
+	ErrBackupScheduleNotFound = errors.New("facade: backup schedule not found")
+)
+
+// backupAlertTimeout is how long to wait for the alert url of a backup
+// schedule
+const backupAlertTimeout = 10 * time.Second
+
+// BackupAlert is posted to the alert url of a backup schedule when one of its
+// backups fails
+type BackupAlert struct {
+	ScheduleID string
+	Schedule   string
+	Time       time.Time
+	Error      string
+}
+
+// AddBackupSchedule adds a new backup schedule, assigning its id if it has
+// none
+func (f *Facade) AddBackupSchedule(ctx datastore.Context, entity *backupschedule.BackupSchedule) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddBackupSchedule"))
+
+	if entity.ID == "" {
+		id, err := utils.NewUUID62()
+		if err != nil {
+			return err
+		}
+		entity.ID = id
+	}
+	alog := f.auditLogger.Message(ctx, "Adding Backup Schedule").Action(audit.Add).Entity(entity)
+
+	if s, err := f.GetBackupSchedule(ctx, entity.ID); err != nil {
+		return alog.Error(err)
+	} else if s != nil {
+		return alog.Error(ErrBackupScheduleExists)
+	}
+	if entity.Calendar != "" {
+		if c, err := f.GetCalendar(ctx, entity.Calendar); err != nil {
+			return alog.Error(err)
+		} else if c == nil {
+			return alog.Error(ErrCalendarNotFound)
+		}
+	}
+
+	now := time.Now()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	entity.LastRun = time.Time{}
+	entity.LastError = ""
+	entity.Backups = nil
+	return alog.Error(f.scheduleStore.Put(ctx, backupschedule.Key(entity.ID), entity))
+}
+
+// RemoveBackupSchedule removes a backup schedule.  The backups it took are
+// kept.
+func (f *Facade) RemoveBackupSchedule(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveBackupSchedule"))
+	alog := f.auditLogger.Message(ctx, "Removing Backup Schedule").Action(audit.Remove).ID(id).Type(backupschedule.GetType())
+
+	if s, err := f.GetBackupSchedule(ctx, id); err != nil {
+		return alog.Error(err)
+	} else if s == nil {
+		return alog.Error(ErrBackupScheduleNotFound)
+	}
+	return alog.Error(f.scheduleStore.Delete(ctx, backupschedule.Key(id)))
+}
+
+// GetBackupSchedule returns the backup schedule with the given id or nil if
+// it does not exist
+func (f *Facade) GetBackupSchedule(ctx datastore.Context, id string) (*backupschedule.BackupSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetBackupSchedule"))
+	var entity backupschedule.BackupSchedule
+	if err := f.scheduleStore.Get(ctx, backupschedule.Key(id), &entity); datastore.IsErrNoSuchEntity(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// GetBackupSchedules returns all backup schedules
+func (f *Facade) GetBackupSchedules(ctx datastore.Context) ([]backupschedule.BackupSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetBackupSchedules"))
+	return f.scheduleStore.GetBackupSchedules(ctx)
+}
+
+// GetDueBackupSchedules returns the backup schedules that have a backup due
+// at the given time.  The state of each schedule is kept in the database, so
+// a master that takes over runs the backups that fell due while no master
+// was running.
+func (f *Facade) GetDueBackupSchedules(ctx datastore.Context, now time.Time) ([]backupschedule.BackupSchedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetDueBackupSchedules"))
+
+	schedules, err := f.GetBackupSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	due := []backupschedule.BackupSchedule{}
+	for _, s := range schedules {
+		logger := plog.WithField("scheduleid", s.ID)
+
+		var cal *calendar.Calendar
+		if s.Calendar != "" {
+			if cal, err = f.GetCalendar(ctx, s.Calendar); err != nil {
+				return nil, err
+			} else if cal == nil {
+				logger.WithField("calendar", s.Calendar).Warn("Calendar of backup schedule does not exist, using UTC")
+			}
+		}
+
+		if ok, err := s.Due(cal, now); err != nil {
+			logger.WithError(err).Warn("Could not evaluate backup schedule")
+		} else if ok {
+			due = append(due, s)
+		}
+	}
+	return due, nil
+}
+
+// RecordBackupScheduleRun records a backup taken by a schedule and returns
+// the files of the schedule beyond the number it keeps, which the caller
+// deletes.  If the backup failed, it is recorded as an alert event and
+// posted to the alert url of the schedule.
+func (f *Facade) RecordBackupScheduleRun(ctx datastore.Context, id string, at time.Time, filename string, runErr error) ([]string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RecordBackupScheduleRun"))
+
+	s, err := f.GetBackupSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if s == nil {
+		return nil, ErrBackupScheduleNotFound
+	}
+	logger := plog.WithFields(logrus.Fields{
+		"scheduleid": s.ID,
+		"schedule":   s.Schedule,
+	})
+
+	s.LastRun = at
+	s.LastError = ""
+	if runErr != nil {
+		s.LastError = runErr.Error()
+	} else {
+		s.Backups = append(s.Backups, filename)
+	}
+	pruned := s.Prune()
+	s.UpdatedAt = time.Now()
+	if err := f.scheduleStore.Put(ctx, backupschedule.Key(s.ID), s); err != nil {
+		logger.WithError(err).Error("Could not record run of backup schedule")
+		return nil, err
+	}
+
+	f.auditLogger.Message(ctx, "Scheduled Backup").Action(audit.Backup).Entity(s).
+		WithField("filename", filename).Error(runErr)
+	if runErr != nil {
+		logger.WithError(runErr).Error("Scheduled backup failed")
+		f.recordEvent(ctx, event.Event{
+			Time:       at,
+			Type:       event.TypeAlert,
+			EntityType: backupschedule.GetType(),
+			EntityID:   s.ID,
+			Message:    "Scheduled backup failed",
+			Fields:     map[string]string{"error": runErr.Error()},
+		})
+		if s.AlertURL != "" {
+			alert := BackupAlert{
+				ScheduleID: s.ID,
+				Schedule:   s.Schedule,
+				Time:       at,
+				Error:      runErr.Error(),
+			}
+			if err := postBackupAlert(s.AlertURL, alert); err != nil {
+				logger.WithError(err).WithField("alerturl", s.AlertURL).Warn("Could not post alert of failed backup")
+			}
+		}
+	}
+	return pruned, nil
+}
+
+// postBackupAlert posts the alert of a failed backup as json
+func postBackupAlert(url string, alert BackupAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: backupAlertTimeout}
+	r, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode < 200 || r.StatusCode >= 300 {
+		return fmt.Errorf("received %d status code", r.StatusCode)
+	}
+	return nil
+}
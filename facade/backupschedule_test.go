@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package facade
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/control-center/serviced/domain/backupschedule"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) TestBackupSchedule_CRUD(c *C) {
+	s := &backupschedule.BackupSchedule{ID: "nightly", Schedule: "0 2 * * *", Keep: 2}
+	err := ft.Facade.AddBackupSchedule(ft.CTX, s)
+	c.Assert(err, IsNil)
+	err = ft.Facade.AddBackupSchedule(ft.CTX, s)
+	c.Assert(err, Equals, ErrBackupScheduleExists)
+
+	s2 := &backupschedule.BackupSchedule{Schedule: "0 4 * * 0", Calendar: "missing"}
+	err = ft.Facade.AddBackupSchedule(ft.CTX, s2)
+	c.Assert(err, Equals, ErrCalendarNotFound)
+
+	schedules, err := ft.Facade.GetBackupSchedules(ft.CTX)
+	c.Assert(err, IsNil)
+	c.Assert(schedules, HasLen, 1)
+	c.Assert(schedules[0].Schedule, Equals, "0 2 * * *")
+
+	err = ft.Facade.RemoveBackupSchedule(ft.CTX, "nightly")
+	c.Assert(err, IsNil)
+	actual, err := ft.Facade.GetBackupSchedule(ft.CTX, "nightly")
+	c.Assert(err, IsNil)
+	c.Assert(actual, IsNil)
+	err = ft.Facade.RemoveBackupSchedule(ft.CTX, "nightly")
+	c.Assert(err, Equals, ErrBackupScheduleNotFound)
+}
+
+func (ft *FacadeIntegrationTest) TestBackupSchedule_RecordRun(c *C) {
+	alerts := make(chan BackupAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert BackupAlert
+		json.NewDecoder(r.Body).Decode(&alert)
+		alerts <- alert
+	}))
+	defer server.Close()
+
+	s := &backupschedule.BackupSchedule{ID: "nightly", Schedule: "0 2 * * *", Keep: 2, AlertURL: server.URL}
+	err := ft.Facade.AddBackupSchedule(ft.CTX, s)
+	c.Assert(err, IsNil)
+
+	// nothing is due before the first time after the schedule was created
+	due, err := ft.Facade.GetDueBackupSchedules(ft.CTX, s.CreatedAt)
+	c.Assert(err, IsNil)
+	c.Assert(due, HasLen, 0)
+	due, err = ft.Facade.GetDueBackupSchedules(ft.CTX, s.CreatedAt.Add(24*time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(due, HasLen, 1)
+
+	at := time.Now()
+	for _, filename := range []string{"a.tgz", "b.tgz"} {
+		pruned, err := ft.Facade.RecordBackupScheduleRun(ft.CTX, "nightly", at, filename, nil)
+		c.Assert(err, IsNil)
+		c.Assert(pruned, HasLen, 0)
+	}
+	pruned, err := ft.Facade.RecordBackupScheduleRun(ft.CTX, "nightly", at, "c.tgz", nil)
+	c.Assert(err, IsNil)
+	c.Assert(pruned, DeepEquals, []string{"a.tgz"})
+
+	pruned, err = ft.Facade.RecordBackupScheduleRun(ft.CTX, "nightly", at, "", errors.New("no space left on device"))
+	c.Assert(err, IsNil)
+	c.Assert(pruned, HasLen, 0)
+	select {
+	case alert := <-alerts:
+		c.Assert(alert.ScheduleID, Equals, "nightly")
+		c.Assert(alert.Error, Equals, "no space left on device")
+	case <-time.After(5 * time.Second):
+		c.Fatalf("alert was not posted")
+	}
+
+	actual, err := ft.Facade.GetBackupSchedule(ft.CTX, "nightly")
+	c.Assert(err, IsNil)
+	c.Assert(actual.Backups, DeepEquals, []string{"b.tgz", "c.tgz"})
+	c.Assert(actual.LastError, Equals, "no space left on device")
+}
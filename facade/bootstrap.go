@@ -0,0 +1,280 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/volume"
+	zks "github.com/control-center/serviced/zzk/service"
+)
+
+var (
+	// ErrBootstrapNotEmpty is returned when the master is bootstrapped after
+	// applications were deployed to it.
+	ErrBootstrapNotEmpty = errors.New("master already has deployed applications")
+	// ErrBootstrapNoSnapshot is returned when an application on the dfs
+	// does not have a snapshot to read its services from.
+	ErrBootstrapNoSnapshot = errors.New("application does not have a snapshot to bootstrap from")
+)
+
+// BootstrapTenant describes an application that was adopted by a bootstrap.
+type BootstrapTenant struct {
+	TenantID string
+	Snapshot string
+	Services int
+}
+
+// BootstrapReport describes the cluster state that was adopted by a
+// bootstrap.
+type BootstrapReport struct {
+	Pools     []string
+	Hosts     []string
+	Templates []string
+	Images    int
+	Tenants   []BootstrapTenant
+	Missing   []string // services in the coordinator that no snapshot has
+}
+
+// bootstrapState is the cluster state that is read before anything is
+// written to the database.
+type bootstrapState struct {
+	pools     []pool.ResourcePool
+	hosts     []host.Host
+	templates []servicetemplate.ServiceTemplate
+	images    []registry.Image
+	snapshots []dfs.SnapshotInfo
+	nodes     map[string]zks.ServiceNode
+}
+
+// Bootstrap rebuilds the database of a freshly installed master from the
+// state that the existing cluster keeps outside of it.  Resource pools,
+// hosts and the registry index are read from the coordinator, service
+// templates from the copies on the dfs, and services from the latest
+// snapshot of each application, updated with the scheduling state in the
+// coordinator.  This must run before the master synchronizes the
+// coordinator with the database.  Delegates are adopted without their keys,
+// which must be reset before they can authenticate with the new master.
+func (f *Facade) Bootstrap(ctx datastore.Context) (*BootstrapReport, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Bootstrap"))
+	f.DFSLock(ctx).Lock("bootstrap master")
+	defer f.DFSLock(ctx).Unlock()
+
+	if tenantIDs, err := f.GetTenantIDs(ctx); err != nil {
+		plog.WithError(err).Debug("Could not look up deployed applications")
+		return nil, err
+	} else if len(tenantIDs) > 0 {
+		return nil, ErrBootstrapNotEmpty
+	}
+
+	// read everything first, so that the database is left untouched if any
+	// of the state cannot be recovered
+	state, err := f.readBootstrapState()
+	if err != nil {
+		return nil, err
+	}
+	report := &BootstrapReport{Images: len(state.images)}
+
+	if err := f.RestoreResourcePools(ctx, state.pools); err != nil {
+		plog.WithError(err).Debug("Could not adopt resource pools")
+		return nil, err
+	}
+	for _, p := range state.pools {
+		report.Pools = append(report.Pools, p.ID)
+	}
+	plog.WithField("pools", len(state.pools)).Info("Adopted resource pools")
+
+	for i := range state.hosts {
+		h := &state.hosts[i]
+		logger := plog.WithFields(logrus.Fields{
+			"hostid": h.ID,
+			"poolid": h.PoolID,
+		})
+		if exists, err := f.GetHost(ctx, h.ID); err != nil {
+			logger.WithError(err).Debug("Could not look up host")
+			return nil, err
+		} else if exists != nil {
+			continue
+		}
+		h.DatabaseVersion = 0
+		h.UpdatedAt = time.Now()
+		if err := f.hostStore.Put(ctx, host.HostKey(h.ID), h); err != nil {
+			logger.WithError(err).Debug("Could not adopt host")
+			return nil, err
+		}
+		report.Hosts = append(report.Hosts, h.ID)
+		logger.Info("Adopted host; its delegate key must be reset")
+	}
+	f.poolCache.SetDirty()
+
+	if len(state.templates) > 0 {
+		if err := f.RestoreServiceTemplates(ctx, state.templates); err != nil {
+			plog.WithError(err).Debug("Could not adopt service templates")
+			return nil, err
+		}
+		for _, t := range state.templates {
+			report.Templates = append(report.Templates, t.Name)
+		}
+		plog.WithField("templates", len(state.templates)).Info("Adopted service templates")
+	}
+
+	for i := range state.images {
+		if err := f.registryStore.Put(ctx, &state.images[i]); err != nil {
+			plog.WithField("image", state.images[i].String()).WithError(err).Debug("Could not adopt registry image")
+			return nil, err
+		}
+	}
+	plog.WithField("images", len(state.images)).Info("Adopted registry index")
+
+	found := make(map[string]struct{})
+	for _, info := range state.snapshots {
+		logger := plog.WithFields(logrus.Fields{
+			"tenantid": info.TenantID,
+			"snapshot": info.Name,
+		})
+		if err := f.bootstrapServices(ctx, info.TenantID, info.Services, state.nodes); err != nil {
+			logger.WithError(err).Debug("Could not adopt services")
+			return nil, err
+		}
+		for _, svc := range info.Services {
+			found[svc.ID] = struct{}{}
+		}
+		report.Tenants = append(report.Tenants, BootstrapTenant{
+			TenantID: info.TenantID,
+			Snapshot: info.Name,
+			Services: len(info.Services),
+		})
+		logger.WithField("services", len(info.Services)).Info("Adopted services from snapshot")
+	}
+	for serviceID := range state.nodes {
+		if _, ok := found[serviceID]; !ok {
+			report.Missing = append(report.Missing, serviceID)
+			plog.WithField("serviceid", serviceID).Warn("Service was not in the latest snapshot of any application and will be removed from the coordinator")
+		}
+	}
+	return report, nil
+}
+
+// readBootstrapState reads the state of the cluster from the coordinator
+// and the dfs.
+func (f *Facade) readBootstrapState() (*bootstrapState, error) {
+	state := &bootstrapState{nodes: make(map[string]zks.ServiceNode)}
+
+	var err error
+	if state.pools, err = f.zzk.GetResourcePools(); err != nil {
+		plog.WithError(err).Debug("Could not read resource pools from the coordinator")
+		return nil, err
+	}
+	for _, p := range state.pools {
+		hosts, err := f.zzk.GetHosts(p.ID)
+		if err != nil {
+			plog.WithField("poolid", p.ID).WithError(err).Debug("Could not read hosts from the coordinator")
+			return nil, err
+		}
+		state.hosts = append(state.hosts, hosts...)
+	}
+	if state.templates, err = f.dfs.Templates(); err != nil {
+		plog.WithError(err).Debug("Could not read service templates from the dfs")
+		return nil, err
+	}
+	if state.images, err = f.zzk.GetRegistryImages(); err != nil {
+		plog.WithError(err).Debug("Could not read the registry index from the coordinator")
+		return nil, err
+	}
+
+	nodes, err := f.zzk.GetServiceNodes()
+	if err != nil {
+		plog.WithError(err).Debug("Could not read services from the coordinator")
+		return nil, err
+	}
+	for _, node := range nodes {
+		state.nodes[node.ID] = node
+	}
+
+	// only tenant services have a volume on the dfs
+	for _, node := range nodes {
+		logger := plog.WithField("serviceid", node.ID)
+		info, err := f.dfs.Latest(node.ID)
+		if err == volume.ErrVolumeNotExists {
+			continue
+		} else if err == dfs.ErrNoSnapshots {
+			logger.Error("Application does not have a snapshot to bootstrap its services from")
+			return nil, ErrBootstrapNoSnapshot
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not read the latest snapshot of the application")
+			return nil, err
+		}
+		state.snapshots = append(state.snapshots, *info)
+	}
+	return state, nil
+}
+
+// bootstrapServices adds the services of an application from a snapshot,
+// keeping the scheduling state that the coordinator has for each service so
+// that running services are not stopped.
+func (f *Facade) bootstrapServices(ctx datastore.Context, tenantID string, svcs []service.Service, nodes map[string]zks.ServiceNode) error {
+	svcsmap := make(map[string][]service.Service)
+	for _, svc := range svcs {
+		svcsmap[svc.ParentServiceID] = append(svcsmap[svc.ParentServiceID], svc)
+	}
+	var traverse func(parentID string) error
+	traverse = func(parentID string) error {
+		for _, svc := range svcsmap[parentID] {
+			svc.DatabaseVersion = 0
+			if node, ok := nodes[svc.ID]; ok {
+				applyServiceNode(&svc, node)
+			} else {
+				svc.DesiredState = int(service.SVCStop)
+			}
+			if err := f.addService(ctx, tenantID, svc, false); err != nil {
+				return err
+			}
+			if err := f.restoreIPs(ctx, &svc); err != nil {
+				plog.WithFields(logrus.Fields{
+					"servicename": svc.Name,
+					"serviceid":   svc.ID,
+				}).WithError(err).Warn("Could not restore address assignments for service")
+			}
+			if err := traverse(svc.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return traverse("")
+}
+
+// applyServiceNode updates a service with the scheduling state in its
+// coordinator node, which is more recent than the snapshot.
+func applyServiceNode(svc *service.Service, node zks.ServiceNode) {
+	svc.DesiredState = node.DesiredState
+	svc.Instances = node.Instances
+	svc.RAMCommitment = node.RAMCommitment
+	svc.CPUCommitment = uint64(node.CPUCommitment)
+	svc.CPURequest = node.CPURequest
+	svc.ChangeOptions = node.ChangeOptions
+	svc.HostPolicy = node.HostPolicy
+	svc.HostAffinity = node.HostAffinity
+	svc.HostAntiAffinity = node.HostAntiAffinity
+	svc.NodeSelector = node.NodeSelector
+}
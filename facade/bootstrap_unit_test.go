@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/volume"
+	zks "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_BootstrapNotEmpty(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{{ID: "tenant"}}, nil)
+
+	report, err := ft.Facade.Bootstrap(ft.ctx)
+	c.Assert(report, IsNil)
+	c.Assert(err, Equals, facade.ErrBootstrapNotEmpty)
+	ft.zzk.AssertNotCalled(c, "GetResourcePools")
+}
+
+func (ft *FacadeUnitTest) Test_BootstrapNoSnapshot(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{}, nil)
+	ft.zzk.On("GetResourcePools").Return([]pool.ResourcePool{{ID: "default"}}, nil)
+	ft.zzk.On("GetHosts", "default").Return(nil, nil)
+	ft.dfs.On("Templates").Return([]servicetemplate.ServiceTemplate{}, nil)
+	ft.zzk.On("GetRegistryImages").Return([]registry.Image{}, nil)
+	ft.zzk.On("GetServiceNodes").Return([]zks.ServiceNode{{ID: "tenant"}, {ID: "child"}}, nil)
+	ft.dfs.On("Latest", "tenant").Return(nil, dfs.ErrNoSnapshots)
+	ft.dfs.On("Latest", "child").Return(nil, volume.ErrVolumeNotExists)
+
+	report, err := ft.Facade.Bootstrap(ft.ctx)
+	c.Assert(report, IsNil)
+	c.Assert(err, Equals, facade.ErrBootstrapNoSnapshot)
+
+	// nothing is written unless all of the state could be read
+	ft.poolStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+	ft.registryStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_BootstrapRegistryIndex(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{}, nil)
+	ft.zzk.On("GetResourcePools").Return([]pool.ResourcePool{}, nil)
+	ft.dfs.On("Templates").Return([]servicetemplate.ServiceTemplate{}, nil)
+	image := registry.Image{Library: "tenant", Repo: "repo", Tag: "latest", UUID: "uuid", Hash: "hash"}
+	ft.zzk.On("GetRegistryImages").Return([]registry.Image{image}, nil)
+	ft.registryStore.On("Put", ft.ctx, &image).Return(nil)
+	ft.zzk.On("GetServiceNodes").Return([]zks.ServiceNode{{ID: "orphan"}}, nil)
+	ft.dfs.On("Latest", "orphan").Return(nil, volume.ErrVolumeNotExists)
+
+	report, err := ft.Facade.Bootstrap(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(report.Images, Equals, 1)
+	c.Assert(report.Tenants, HasLen, 0)
+	c.Assert(report.Missing, DeepEquals, []string{"orphan"})
+	ft.registryStore.AssertCalled(c, "Put", ft.ctx, &image)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+var (
+	// ErrCalendarExists is returned when adding a calendar that already exists
+	ErrCalendarExists = errors.New("facade: calendar exists")
+	// ErrCalendarNotFound is returned when a calendar does not exist
+	ErrCalendarNotFound = errors.New("facade: calendar not found")
+)
+
+// AddCalendar adds a new calendar
+func (f *Facade) AddCalendar(ctx datastore.Context, entity *calendar.Calendar) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddCalendar"))
+	alog := f.auditLogger.Message(ctx, "Adding Calendar").Action(audit.Add).Entity(entity)
+
+	if c, err := f.GetCalendar(ctx, entity.ID); err != nil {
+		return alog.Error(err)
+	} else if c != nil {
+		return alog.Error(ErrCalendarExists)
+	}
+
+	now := time.Now()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	return alog.Error(f.calendarStore.Put(ctx, calendar.Key(entity.ID), entity))
+}
+
+// UpdateCalendar updates an existing calendar
+func (f *Facade) UpdateCalendar(ctx datastore.Context, entity *calendar.Calendar) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpdateCalendar"))
+	alog := f.auditLogger.Message(ctx, "Updating Calendar").Action(audit.Update).Entity(entity)
+
+	c, err := f.GetCalendar(ctx, entity.ID)
+	if err != nil {
+		return alog.Error(err)
+	} else if c == nil {
+		return alog.Error(ErrCalendarNotFound)
+	}
+
+	entity.CreatedAt = c.CreatedAt
+	entity.UpdatedAt = time.Now()
+	alog = alog.Delta(c, entity)
+	return alog.Error(f.calendarStore.Put(ctx, calendar.Key(entity.ID), entity))
+}
+
+// RemoveCalendar removes a calendar
+func (f *Facade) RemoveCalendar(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveCalendar"))
+	alog := f.auditLogger.Message(ctx, "Removing Calendar").Action(audit.Remove).ID(id).Type(calendar.GetType())
+
+	if c, err := f.GetCalendar(ctx, id); err != nil {
+		return alog.Error(err)
+	} else if c == nil {
+		return alog.Error(ErrCalendarNotFound)
+	}
+	return alog.Error(f.calendarStore.Delete(ctx, calendar.Key(id)))
+}
+
+// GetCalendar returns the calendar with the given id or nil if it does not
+// exist
+func (f *Facade) GetCalendar(ctx datastore.Context, id string) (*calendar.Calendar, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCalendar"))
+	var entity calendar.Calendar
+	if err := f.calendarStore.Get(ctx, calendar.Key(id), &entity); datastore.IsErrNoSuchEntity(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// GetCalendars returns all calendars
+func (f *Facade) GetCalendars(ctx datastore.Context) ([]calendar.Calendar, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCalendars"))
+	return f.calendarStore.GetCalendars(ctx)
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/calendar"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) TestCalendar_CRUD(c *C) {
+	cal := calendar.New("default")
+	cal.TimeZone = "America/Chicago"
+	cal.Exclusions = []string{"2017-12-25"}
+
+	err := ft.Facade.AddCalendar(ft.CTX, cal)
+	c.Assert(err, IsNil)
+	err = ft.Facade.AddCalendar(ft.CTX, cal)
+	c.Assert(err, Equals, ErrCalendarExists)
+
+	actual, err := ft.Facade.GetCalendar(ft.CTX, "default")
+	c.Assert(err, IsNil)
+	c.Assert(actual.Equals(cal), Equals, true)
+
+	start, err := actual.ParseTime("2017-12-20 00:00")
+	c.Assert(err, IsNil)
+	actual.Windows = []calendar.Window{
+		{Name: "holidays", Kind: calendar.FreezeWindow, Start: start, End: start.Add(14 * 24 * time.Hour)},
+	}
+	err = ft.Facade.UpdateCalendar(ft.CTX, actual)
+	c.Assert(err, IsNil)
+
+	cals, err := ft.Facade.GetCalendars(ft.CTX)
+	c.Assert(err, IsNil)
+	c.Assert(cals, HasLen, 1)
+	c.Assert(cals[0].IsFrozen(start), Equals, true)
+
+	err = ft.Facade.RemoveCalendar(ft.CTX, "default")
+	c.Assert(err, IsNil)
+	actual, err = ft.Facade.GetCalendar(ft.CTX, "default")
+	c.Assert(err, IsNil)
+	c.Assert(actual, IsNil)
+
+	err = ft.Facade.UpdateCalendar(ft.CTX, cal)
+	c.Assert(err, Equals, ErrCalendarNotFound)
+}
@@ -0,0 +1,277 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+)
+
+var (
+	// ErrInvalidCanaryFraction is returned when the fraction of canary
+	// instances is not greater than 0 and at most 1.
+	ErrInvalidCanaryFraction = errors.New("canary fraction must be greater than 0 and at most 1")
+	// ErrCanaryNoImage is returned when the service does not run an image
+	ErrCanaryNoImage = errors.New("service does not have an image")
+	// ErrCanaryNotRunning is returned when the service is not running
+	ErrCanaryNotRunning = errors.New("service is not running")
+	// ErrCanaryFailed is returned when the canary instances did not pass
+	// their health checks and the deploy was rolled back.
+	ErrCanaryFailed = errors.New("canary instances did not pass their health checks")
+)
+
+// canaryPollInterval is how often the health of the canary instances is
+// checked.
+var canaryPollInterval = 5 * time.Second
+
+// DeployServiceCanary rolls a new image out to a running service.  The tenant
+// is snapshotted first, so the instances that are not canaries can be pinned
+// to the image captured by the snapshot while the new image is pulled into
+// the registry.  The given fraction of instances (at least one) is then
+// restarted on the new image.  If all of the canaries pass their health
+// checks within the timeout, the image is promoted to the service and the
+// rest of the instances are restarted onto it; otherwise the image and the
+// canaries are rolled back and ErrCanaryFailed is returned.  It returns the
+// id of the snapshot.
+func (f *Facade) DeployServiceCanary(ctx datastore.Context, serviceID, imageID string, fraction float64, timeout time.Duration) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeployServiceCanary"))
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid": serviceID,
+		"imageid":   imageID,
+	})
+
+	if fraction <= 0 || fraction > 1 {
+		return "", ErrInvalidCanaryFraction
+	}
+
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return "", err
+	}
+	if svc.ImageID == "" {
+		return "", ErrCanaryNoImage
+	}
+	if svc.DesiredState != int(service.SVCRun) || svc.Instances < 1 {
+		return "", ErrCanaryNotRunning
+	}
+	canaries := canaryCount(svc.Instances, fraction)
+	logger = logger.WithField("canaries", canaries)
+
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up tenant of service")
+		return "", err
+	}
+
+	snapshotID, oldImage, newImage, err := f.stageCanaryImage(ctx, tenantID, svc, imageID)
+	if err != nil {
+		return snapshotID, err
+	}
+	logger = logger.WithField("snapshotid", snapshotID)
+
+	since := time.Now()
+	if err := f.pinCanary(ctx, svc, canaries, oldImage, newImage); err != nil {
+		logger.WithError(err).Warn("Could not pin the images of the service instances, rolling back")
+		f.rollbackCanary(ctx, svc, canaries, snapshotID)
+		return snapshotID, err
+	}
+	logger.Info("Started canary instances")
+
+	if !f.waitCanaryHealth(ctx, svc, canaries, since, timeout) {
+		logger.Warn("Canary instances did not pass their health checks, rolling back")
+		f.rollbackCanary(ctx, svc, canaries, snapshotID)
+		return snapshotID, ErrCanaryFailed
+	}
+
+	if err := f.promoteCanary(ctx, svc, canaries, newImage); err != nil {
+		logger.WithError(err).Debug("Could not promote image")
+		return snapshotID, err
+	}
+	logger.Info("Promoted canary image")
+	return snapshotID, nil
+}
+
+// canaryCount returns the number of instances of a service that run on the
+// new image.
+func canaryCount(instances int, fraction float64) int {
+	count := int(math.Ceil(float64(instances) * fraction))
+	if count < 1 {
+		count = 1
+	} else if count > instances {
+		count = instances
+	}
+	return count
+}
+
+// stageCanaryImage snapshots the tenant and pulls the new image into the
+// registry.  It returns the id of the snapshot, the image of the service as
+// tagged by the snapshot and the registry image of the new image.
+func (f *Facade) stageCanaryImage(ctx datastore.Context, tenantID string, svc *service.Service, imageID string) (string, string, string, error) {
+	logger := plog.WithFields(logrus.Fields{
+		"tenantid":  tenantID,
+		"serviceid": svc.ID,
+		"imageid":   imageID,
+	})
+
+	endOperation, err := f.BeginDFSOperation(ctx, DFSOperationSnapshot, svc.ID, tenantID)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer endOperation()
+
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("canary deploy")
+	defer dfslocker.Unlock()
+
+	message := fmt.Sprintf("canary deploy of %s to service %s", imageID, svc.Name)
+	snapshotID, err := f.Snapshot(ctx, tenantID, message, []string{}, config.GetOptions().SnapshotSpacePercent)
+	if err != nil {
+		logger.WithError(err).Debug("Could not snapshot tenant")
+		return "", "", "", err
+	}
+	info, err := f.dfs.Info(snapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get info for snapshot")
+		return snapshotID, "", "", err
+	}
+	oldImage, err := commons.ParseImageID(svc.ImageID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not parse the image of the service")
+		return snapshotID, "", "", err
+	}
+	oldImage.Tag = info.Label
+
+	newImage, err := f.downloadImage(ctx, imageID, tenantID, true, svc.RegistryCredential)
+	if err != nil {
+		logger.WithError(err).Debug("Could not download image")
+		return snapshotID, "", "", err
+	}
+	return snapshotID, oldImage.String(), newImage, nil
+}
+
+// pinCanary restarts the canary instances on the new image and pins the rest
+// of the instances to the old image.
+func (f *Facade) pinCanary(ctx datastore.Context, svc *service.Service, canaries int, oldImage, newImage string) error {
+	for i := 0; i < svc.Instances; i++ {
+		imageID, restart := oldImage, false
+		if i < canaries {
+			imageID, restart = newImage, true
+		}
+		if err := f.zzk.PinInstanceImage(ctx, svc.PoolID, svc.ID, i, imageID, restart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackCanary reverts the image in the registry to the snapshot and
+// restarts the canary instances onto it.
+func (f *Facade) rollbackCanary(ctx datastore.Context, svc *service.Service, canaries int, snapshotID string) {
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid":  svc.ID,
+		"snapshotid": snapshotID,
+	})
+
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("canary rollback")
+	err := f.dfs.RollbackImage(snapshotID, svc.ImageID)
+	dfslocker.Unlock()
+	if err != nil {
+		logger.WithError(err).Error("Could not roll back the image of the service")
+	}
+
+	for i := 0; i < svc.Instances; i++ {
+		if err := f.zzk.PinInstanceImage(ctx, svc.PoolID, svc.ID, i, "", i < canaries); err != nil {
+			logger.WithField("instanceid", i).WithError(err).Warn("Could not unpin the image of the instance")
+		}
+	}
+}
+
+// promoteCanary sets the new image on the service and restarts the instances
+// that are not canaries onto it.
+func (f *Facade) promoteCanary(ctx datastore.Context, svc *service.Service, canaries int, newImage string) error {
+	current, err := f.GetService(ctx, svc.ID)
+	if err != nil {
+		return err
+	}
+	current.ImageID = newImage
+	if err := f.pinServiceImage(ctx, current); err != nil {
+		return err
+	}
+	if err := f.UpdateService(ctx, *current); err != nil {
+		return err
+	}
+	for i := 0; i < svc.Instances; i++ {
+		if err := f.zzk.PinInstanceImage(ctx, svc.PoolID, svc.ID, i, "", i >= canaries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitCanaryHealth waits for the canary instances to pass their health
+// checks.  It returns false if they do not pass before the timeout.
+func (f *Facade) waitCanaryHealth(ctx datastore.Context, svc *service.Service, canaries int, since time.Time, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(canaryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if ok, err := f.canariesHealthy(ctx, svc, canaries, since); err != nil {
+			plog.WithField("serviceid", svc.ID).WithError(err).Debug("Could not check the health of the canary instances")
+		} else if ok {
+			return true
+		}
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// canariesHealthy returns true if every canary instance has started since the
+// deploy began and all of its health checks pass.
+func (f *Facade) canariesHealthy(ctx datastore.Context, svc *service.Service, canaries int, since time.Time) (bool, error) {
+	svch, err := f.serviceStore.GetServiceHealth(ctx, svc.ID)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < canaries; i++ {
+		state, err := f.zzk.GetServiceState(ctx, svc.PoolID, svc.ID, i)
+		if err != nil {
+			return false, err
+		}
+		if state.Started.Before(since) || state.Terminated.After(state.Started) {
+			return false, nil
+		}
+		for _, status := range f.getInstanceHealth(svch, i) {
+			if status != health.OK {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"time"
+
+	datastoremocks "github.com/control-center/serviced/datastore/mocks"
+	dfsmocks "github.com/control-center/serviced/dfs/mocks"
+	"github.com/control-center/serviced/domain/service"
+	servicemocks "github.com/control-center/serviced/domain/service/mocks"
+	zzkmocks "github.com/control-center/serviced/facade/mocks"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/metrics"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&CanaryTest{})
+
+type CanaryTest struct {
+	f            *Facade
+	ctx          *datastoremocks.Context
+	dfs          *dfsmocks.DFS
+	zzk          *zzkmocks.ZZK
+	serviceStore *servicemocks.Store
+	svc          *service.Service
+}
+
+func (t *CanaryTest) SetUpTest(c *C) {
+	t.f = New()
+	t.ctx = &datastoremocks.Context{}
+	t.ctx.On("Metrics").Return(metrics.NewMetrics())
+	t.dfs = &dfsmocks.DFS{}
+	t.f.SetDFS(t.dfs)
+	t.zzk = &zzkmocks.ZZK{}
+	t.f.SetZZK(t.zzk)
+	t.serviceStore = &servicemocks.Store{}
+	t.f.SetServiceStore(t.serviceStore)
+	t.f.SetHealthCache(health.New())
+	t.svc = &service.Service{
+		ID:        "serviceid",
+		PoolID:    "default",
+		ImageID:   "tenantid/repo:latest",
+		Instances: 3,
+	}
+}
+
+func (t *CanaryTest) Test_CanaryCount(c *C) {
+	c.Assert(canaryCount(10, 0.1), Equals, 1)
+	c.Assert(canaryCount(10, 0.25), Equals, 3)
+	c.Assert(canaryCount(3, 0.01), Equals, 1)
+	c.Assert(canaryCount(4, 1), Equals, 4)
+}
+
+func (t *CanaryTest) Test_PinCanary(c *C) {
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 0, "tenantid/newrepo:latest", true).Return(nil)
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 1, "tenantid/repo:LABEL", false).Return(nil)
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 2, "tenantid/repo:LABEL", false).Return(nil)
+	err := t.f.pinCanary(t.ctx, t.svc, 1, "tenantid/repo:LABEL", "tenantid/newrepo:latest")
+	c.Assert(err, IsNil)
+	t.zzk.AssertExpectations(c)
+}
+
+func (t *CanaryTest) Test_PinCanary_Error(c *C) {
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 0, "tenantid/newrepo:latest", true).Return(ErrHostOffline)
+	err := t.f.pinCanary(t.ctx, t.svc, 1, "tenantid/repo:LABEL", "tenantid/newrepo:latest")
+	c.Assert(err, Equals, ErrHostOffline)
+}
+
+func (t *CanaryTest) Test_RollbackCanary(c *C) {
+	t.dfs.On("RollbackImage", "tenantid_LABEL", "tenantid/repo:latest").Return(nil)
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 0, "", true).Return(nil)
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 1, "", true).Return(nil)
+	t.zzk.On("PinInstanceImage", t.ctx, "default", "serviceid", 2, "", false).Return(nil)
+	t.f.rollbackCanary(t.ctx, t.svc, 2, "tenantid_LABEL")
+	t.dfs.AssertExpectations(c)
+	t.zzk.AssertExpectations(c)
+}
+
+func (t *CanaryTest) Test_CanariesHealthy(c *C) {
+	since := time.Now()
+	t.serviceStore.On("GetServiceHealth", t.ctx, "serviceid").Return(&service.ServiceHealth{
+		ID:           "serviceid",
+		PoolID:       "default",
+		Instances:    3,
+		HealthChecks: map[string]health.HealthCheck{"ready": {}},
+	}, nil)
+	state := &zkservice.State{ServiceID: "serviceid"}
+	t.zzk.On("GetServiceState", t.ctx, "default", "serviceid", 0).Return(state, nil)
+
+	// the canary has not restarted yet
+	state.Started = since.Add(-time.Minute)
+	ok, err := t.f.canariesHealthy(t.ctx, t.svc, 1, since)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	// the canary has not reported its health
+	state.Started = since.Add(time.Second)
+	ok, err = t.f.canariesHealthy(t.ctx, t.svc, 1, since)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	key := health.HealthStatusKey{ServiceID: "serviceid", InstanceID: 0, HealthCheckName: "ready"}
+	t.f.ReportHealthStatus(key, health.HealthStatus{Status: health.Failed}, time.Minute)
+	ok, err = t.f.canariesHealthy(t.ctx, t.svc, 1, since)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	t.f.ReportHealthStatus(key, health.HealthStatus{Status: health.OK}, time.Minute)
+	ok, err = t.f.canariesHealthy(t.ctx, t.svc, 1, since)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+}
+
+func (t *CanaryTest) Test_DeployServiceCanary_InvalidFraction(c *C) {
+	_, err := t.f.DeployServiceCanary(t.ctx, "serviceid", "repo", 0, time.Minute)
+	c.Assert(err, Equals, ErrInvalidCanaryFraction)
+	_, err = t.f.DeployServiceCanary(t.ctx, "serviceid", "repo", 1.5, time.Minute)
+	c.Assert(err, Equals, ErrInvalidCanaryFraction)
+}
@@ -0,0 +1,111 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// ErrCertificateNotFound is returned when a host name has no certificate
+var ErrCertificateNotFound = errors.New("facade: certificate not found")
+
+// SetCertificate stores the certificate chain and private key of a virtual
+// host, replacing its certificate if it has one.  The private key is
+// encrypted for the master.
+func (f *Facade) SetCertificate(ctx datastore.Context, name, issuer string, certPEM, keyPEM []byte) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetCertificate"))
+	alog := f.auditLogger.Message(ctx, "Setting Certificate").Action(audit.Update).ID(name).Type(certificate.GetType())
+
+	ciphertext, err := auth.EncryptForMaster(keyPEM)
+	if err != nil {
+		return alog.Error(err)
+	}
+	entity, err := certificate.New(name, issuer, certPEM, ciphertext)
+	if err != nil {
+		return alog.Error(err)
+	}
+
+	now := time.Now()
+	if c, err := f.getCertificate(ctx, name); err != nil {
+		return alog.Error(err)
+	} else if c != nil {
+		entity.CreatedAt = c.CreatedAt
+		entity.DatabaseVersion = c.DatabaseVersion
+	} else {
+		entity.CreatedAt = now
+	}
+	entity.UpdatedAt = now
+	return alog.Error(f.certStore.Put(ctx, certificate.Key(name), entity))
+}
+
+// RemoveCertificate removes the certificate of a virtual host
+func (f *Facade) RemoveCertificate(ctx datastore.Context, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveCertificate"))
+	alog := f.auditLogger.Message(ctx, "Removing Certificate").Action(audit.Remove).ID(name).Type(certificate.GetType())
+
+	if c, err := f.getCertificate(ctx, name); err != nil {
+		return alog.Error(err)
+	} else if c == nil {
+		return alog.Error(ErrCertificateNotFound)
+	}
+	return alog.Error(f.certStore.Delete(ctx, certificate.Key(name)))
+}
+
+// GetCertificates returns all certificates without their private keys
+func (f *Facade) GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCertificates"))
+	certs, err := f.certStore.GetCertificates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range certs {
+		certs[i].KeyPEM = nil
+	}
+	return certs, nil
+}
+
+// GetCertificateKeyPair returns the certificate chain and the decrypted
+// private key of a virtual host
+func (f *Facade) GetCertificateKeyPair(ctx datastore.Context, name string) ([]byte, []byte, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCertificateKeyPair"))
+	c, err := f.getCertificate(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	} else if c == nil {
+		return nil, nil, ErrCertificateNotFound
+	}
+	keyPEM, err := auth.DecryptAsMaster(c.KeyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.CertPEM, keyPEM, nil
+}
+
+// getCertificate returns the certificate of a host name or nil if it does
+// not exist
+func (f *Facade) getCertificate(ctx datastore.Context, name string) (*certificate.Certificate, error) {
+	var entity certificate.Certificate
+	if err := f.certStore.Get(ctx, certificate.Key(name), &entity); datastore.IsErrNoSuchEntity(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/secret"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// ErrContainerFilesUnavailable is returned when the facade cannot reach the
+// files inside the containers of service instances
+var ErrContainerFilesUnavailable = errors.New("facade: container files are not available")
+
+// configInstance is a running service instance and the config files rendered
+// for it, keyed by filename
+type configInstance struct {
+	instanceID  int
+	hostID      string
+	address     string
+	containerID string
+	files       map[string]servicedefinition.ConfigFile
+}
+
+// GetConfigDrift compares the config files rendered for each running instance
+// of a service against the files inside its container.  Files that could not
+// be checked are reported as unknown.
+func (f *Facade) GetConfigDrift(ctx datastore.Context, serviceID string) ([]service.ConfigDrift, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetConfigDrift"))
+
+	instances, err := f.getConfigInstances(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := []service.ConfigDrift{}
+	for _, inst := range instances {
+		drift = append(drift, f.checkConfigFiles(inst)...)
+	}
+	return drift, nil
+}
+
+// SyncServiceConfigs writes the rendered config files over the files that
+// have drifted inside the containers of a service.  If restart is set and
+// any file was written, the service is restarted so that its applications
+// reload them.  It returns the drift found before the files were written.
+func (f *Facade) SyncServiceConfigs(ctx datastore.Context, serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SyncServiceConfigs"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	alog := f.auditLogger.Message(ctx, "Syncing Service Configuration").
+		Action(audit.Update).ID(serviceID).Type(service.GetType()).
+		WithFields(log.Fields{"restart": restart})
+
+	instances, err := f.getConfigInstances(ctx, serviceID)
+	if err != nil {
+		return nil, alog.Error(err)
+	}
+
+	drift := []service.ConfigDrift{}
+	written := 0
+	for _, inst := range instances {
+		var files []servicedefinition.ConfigFile
+		for _, d := range f.checkConfigFiles(inst) {
+			if d.Status == service.ConfigModified || d.Status == service.ConfigMissing {
+				files = append(files, inst.files[d.Filename])
+			}
+			drift = append(drift, d)
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		logger := logger.WithFields(log.Fields{
+			"instanceid": inst.instanceID,
+			"hostid":     inst.hostID,
+			"files":      len(files),
+		})
+		if err := f.filesClient.WriteContainerFiles(inst.address, inst.containerID, files); err != nil {
+			logger.WithError(err).Debug("Could not write config files into the container")
+			return nil, alog.Error(err)
+		}
+		written += len(files)
+		logger.Info("Synced drifted config files into the container")
+	}
+
+	if restart && written > 0 {
+		if _, err := f.RestartServices(ctx, []string{serviceID}, false); err != nil {
+			logger.WithError(err).Debug("Could not restart service")
+			return nil, alog.Error(err)
+		}
+	}
+
+	alog.WithFields(log.Fields{"files": written}).Succeeded()
+	return drift, nil
+}
+
+// getConfigInstances returns the running instances of a service with the
+// config files rendered for each of them, ordered by instance id.
+func (f *Facade) getConfigInstances(ctx datastore.Context, serviceID string) ([]configInstance, error) {
+	logger := plog.WithField("serviceid", serviceID)
+
+	if f.filesClient == nil {
+		return nil, ErrContainerFilesUnavailable
+	}
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+
+	states, err := f.zzk.GetServiceStates(ctx, svc.PoolID, svc.ID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up running instances")
+		return nil, err
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].InstanceID < states[j].InstanceID })
+
+	hostMap := make(map[string]host.Host)
+	instances := []configInstance{}
+	for _, state := range states {
+		if state.ContainerID == "" || state.Status != service.StateRunning {
+			continue
+		}
+
+		hst, ok := hostMap[state.HostID]
+		if !ok {
+			if err := f.hostStore.Get(ctx, host.HostKey(state.HostID), &hst); err != nil {
+				logger.WithField("hostid", state.HostID).WithError(err).Debug("Could not look up host for instance")
+				return nil, err
+			}
+			hostMap[state.HostID] = hst
+		}
+
+		files, err := f.renderConfigFiles(ctx, serviceID, state.InstanceID)
+		if err != nil {
+			logger.WithField("instanceid", state.InstanceID).WithError(err).Debug("Could not render config files for instance")
+			return nil, err
+		}
+
+		instances = append(instances, configInstance{
+			instanceID:  state.InstanceID,
+			hostID:      state.HostID,
+			address:     fmt.Sprintf("%s:%d", hst.IPAddr, hst.RPCPort),
+			containerID: state.ContainerID,
+			files:       files,
+		})
+	}
+	return instances, nil
+}
+
+// renderConfigFiles returns the config files of a service instance as the
+// container writes them when it starts, with their secrets filled in.
+func (f *Facade) renderConfigFiles(ctx datastore.Context, serviceID string, instanceID int) (map[string]servicedefinition.ConfigFile, error) {
+	svc, err := f.GetEvaluatedService(ctx, serviceID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := false
+	for _, configFile := range svc.ConfigFiles {
+		referenced = referenced || len(secret.References(configFile.Content)) > 0
+	}
+	values := map[string]string{}
+	if referenced {
+		if values, err = f.GetServiceSecrets(ctx, serviceID, instanceID); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string]servicedefinition.ConfigFile)
+	for _, configFile := range svc.ConfigFiles {
+		if configFile.Content, err = secret.Render(configFile.Content, values); err != nil {
+			return nil, fmt.Errorf("%s: %s", configFile.Filename, err)
+		}
+		files[configFile.Filename] = configFile
+	}
+	return files, nil
+}
+
+// checkConfigFiles compares the config files of an instance against the
+// files inside its container, ordered by filename.
+func (f *Facade) checkConfigFiles(inst configInstance) []service.ConfigDrift {
+	filenames := make([]string, 0, len(inst.files))
+	for filename := range inst.files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	drift := make([]service.ConfigDrift, len(filenames))
+	for i, filename := range filenames {
+		drift[i] = service.ConfigDrift{
+			InstanceID: inst.instanceID,
+			HostID:     inst.hostID,
+			Filename:   filename,
+		}
+	}
+	if len(filenames) == 0 {
+		return drift
+	}
+
+	hashes, err := f.filesClient.HashContainerFiles(inst.address, inst.containerID, filenames)
+	if err != nil {
+		plog.WithFields(log.Fields{
+			"instanceid": inst.instanceID,
+			"hostid":     inst.hostID,
+		}).WithError(err).Debug("Could not read config files from the container")
+		for i := range drift {
+			drift[i].Status = service.ConfigUnknown
+			drift[i].Error = err.Error()
+		}
+		return drift
+	}
+
+	for i, filename := range filenames {
+		actual, ok := hashes[filename]
+		if !ok {
+			drift[i].Status = service.ConfigMissing
+		} else if actual != hashConfigContent(inst.files[filename].Content) {
+			drift[i].Status = service.ConfigModified
+		} else {
+			drift[i].Status = service.ConfigInSync
+		}
+	}
+	return drift
+}
+
+// hashConfigContent returns the sha256 sum of the content of a config file,
+// as the agent computes it for the file inside a container
+func hashConfigContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/facade"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// testFilesClient serves the hashes of the files inside containers and
+// records the files written into them
+type testFilesClient struct {
+	files   map[string]map[string]string // contents by container and filename
+	errs    map[string]error             // errors by container
+	written map[string][]servicedefinition.ConfigFile
+}
+
+func (t *testFilesClient) HashContainerFiles(address, containerID string, filenames []string) (map[string]string, error) {
+	if err := t.errs[containerID]; err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string)
+	for _, filename := range filenames {
+		if content, ok := t.files[containerID][filename]; ok {
+			sum := sha256.Sum256([]byte(content))
+			hashes[filename] = hex.EncodeToString(sum[:])
+		}
+	}
+	return hashes, nil
+}
+
+func (t *testFilesClient) WriteContainerFiles(address, containerID string, files []servicedefinition.ConfigFile) error {
+	t.written[containerID] = append(t.written[containerID], files...)
+	return nil
+}
+
+func (ft *FacadeUnitTest) setupConfigDrift(c *C) *testFilesClient {
+	serviceID := "0"
+	svc := service.Service{
+		ID:     serviceID,
+		Name:   "service0",
+		PoolID: "default",
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(func(datastore.Context, string) *service.Service {
+		copy := svc
+		return &copy
+	}, nil)
+
+	files := []*serviceconfigfile.SvcConfigFile{}
+	for _, conf := range []servicedefinition.ConfigFile{
+		{Filename: "/etc/app.conf", Owner: "app:app", Permissions: "0640", Content: "port=8080\n"},
+		{Filename: "/etc/log.conf", Content: "level=info\n"},
+	} {
+		file, err := serviceconfigfile.New(serviceID, "/"+serviceID, conf)
+		c.Assert(err, IsNil)
+		files = append(files, file)
+	}
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return(files, nil)
+
+	states := []zkservice.State{}
+	for i, ctr := range []string{"ctr0", "ctr1", ""} {
+		state := zkservice.State{HostID: "host0", ServiceID: serviceID, InstanceID: i}
+		state.ContainerID = ctr
+		if ctr != "" {
+			state.Status = service.StateRunning
+		}
+		states = append(states, state)
+		ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, i).Return(&state, nil)
+	}
+	ft.zzk.On("GetServiceStates", ft.ctx, "default", serviceID).Return(states, nil)
+
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host0"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host0", IPAddr: "10.0.0.1", RPCPort: 4979}
+		})
+
+	client := &testFilesClient{
+		files: map[string]map[string]string{
+			"ctr0": {"/etc/app.conf": "port=8080\n", "/etc/log.conf": "level=debug\n"},
+			"ctr1": {"/etc/app.conf": "port=8080\n"},
+		},
+		errs:    map[string]error{},
+		written: map[string][]servicedefinition.ConfigFile{},
+	}
+	ft.Facade.SetContainerFilesClient(client)
+	return client
+}
+
+func (ft *FacadeUnitTest) Test_GetConfigDrift(c *C) {
+	client := ft.setupConfigDrift(c)
+	defer ft.Facade.SetContainerFilesClient(nil)
+
+	drift, err := ft.Facade.GetConfigDrift(ft.ctx, "0")
+	c.Assert(err, IsNil)
+	c.Assert(drift, DeepEquals, []service.ConfigDrift{
+		{InstanceID: 0, HostID: "host0", Filename: "/etc/app.conf", Status: service.ConfigInSync},
+		{InstanceID: 0, HostID: "host0", Filename: "/etc/log.conf", Status: service.ConfigModified},
+		{InstanceID: 1, HostID: "host0", Filename: "/etc/app.conf", Status: service.ConfigInSync},
+		{InstanceID: 1, HostID: "host0", Filename: "/etc/log.conf", Status: service.ConfigMissing},
+	})
+
+	// files of an unreachable container are unknown
+	client.errs["ctr1"] = errors.New("agent unreachable")
+	drift, err = ft.Facade.GetConfigDrift(ft.ctx, "0")
+	c.Assert(err, IsNil)
+	c.Assert(drift[2].Status, Equals, service.ConfigUnknown)
+	c.Assert(drift[2].Error, Equals, "agent unreachable")
+	c.Assert(drift[3].Status, Equals, service.ConfigUnknown)
+}
+
+func (ft *FacadeUnitTest) Test_SyncServiceConfigs(c *C) {
+	client := ft.setupConfigDrift(c)
+	defer ft.Facade.SetContainerFilesClient(nil)
+
+	drift, err := ft.Facade.SyncServiceConfigs(ft.ctx, "0", false)
+	c.Assert(err, IsNil)
+	c.Assert(drift, HasLen, 4)
+	c.Assert(client.written, DeepEquals, map[string][]servicedefinition.ConfigFile{
+		"ctr0": {{Filename: "/etc/log.conf", Content: "level=info\n"}},
+		"ctr1": {{Filename: "/etc/log.conf", Content: "level=info\n"}},
+	})
+}
+
+func (ft *FacadeUnitTest) Test_GetConfigDriftUnavailable(c *C) {
+	_, err := ft.Facade.GetConfigDrift(ft.ctx, "0")
+	c.Assert(err, Equals, facade.ErrContainerFilesUnavailable)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// GetCrossPoolFlows returns the imports of the running services that resolve
+// to endpoints that running services in other pools export, and whether the
+// cross-pool policy of the export pool allows them.
+func (f *Facade) GetCrossPoolFlows(ctx datastore.Context) ([]pool.CrossPoolFlow, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCrossPoolFlows"))
+
+	pools, err := f.GetResourcePools(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get resource pools")
+		return nil, err
+	}
+	poolsByID := make(map[string]pool.ResourcePool)
+	for _, p := range pools {
+		poolsByID[p.ID] = p
+	}
+
+	tenantIDs, err := f.GetTenantIDs(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get tenant ids")
+		return nil, err
+	}
+	flows := []pool.CrossPoolFlow{}
+	for _, tenantID := range tenantIDs {
+		svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
+		if err != nil {
+			plog.WithField("tenantid", tenantID).WithError(err).Debug("Could not get services of tenant")
+			return nil, err
+		}
+		flows = append(flows, buildCrossPoolFlows(tenantID, svcs, poolsByID)...)
+	}
+	return flows, nil
+}
+
+// buildCrossPoolFlows returns the resolved imports of the endpoint graph of
+// the tenant whose importing and exporting services are running in different
+// pools.
+func buildCrossPoolFlows(tenantID string, svcs []service.Service, pools map[string]pool.ResourcePool) []pool.CrossPoolFlow {
+	graph := buildEndpointGraph(tenantID, svcs)
+	svcsByID := make(map[string]*service.Service)
+	for i := range svcs {
+		svcsByID[svcs[i].ID] = &svcs[i]
+	}
+
+	flows := []pool.CrossPoolFlow{}
+	for _, edge := range graph.Edges {
+		if edge.Error != "" {
+			continue
+		}
+		importer, exporter := svcsByID[edge.ServiceID], svcsByID[edge.ExportServiceID]
+		if importer.PoolID == exporter.PoolID {
+			continue
+		}
+		if importer.DesiredState != int(service.SVCRun) || exporter.DesiredState != int(service.SVCRun) {
+			continue
+		}
+
+		// pools that no longer exist have the default policy
+		exportPool, ok := pools[exporter.PoolID]
+		if !ok {
+			exportPool = pool.ResourcePool{ID: exporter.PoolID}
+		}
+		flows = append(flows, pool.CrossPoolFlow{
+			TenantID:          tenantID,
+			ServiceID:         importer.ID,
+			ServiceName:       importer.Name,
+			PoolID:            importer.PoolID,
+			ExportServiceID:   exporter.ID,
+			ExportServiceName: exporter.Name,
+			ExportPoolID:      exporter.PoolID,
+			Application:       edge.ExportApplication,
+			Allowed:           exportPool.AllowsImport(importer.PoolID, edge.ExportApplication),
+		})
+	}
+	return flows
+}
@@ -0,0 +1,108 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&CrossPoolFlowTest{})
+
+type CrossPoolFlowTest struct{}
+
+func (t *CrossPoolFlowTest) Test_BuildCrossPoolFlows(c *C) {
+	running := int(service.SVCRun)
+	svcs := []service.Service{
+		{
+			ID:           "zope",
+			Name:         "Zope",
+			PoolID:       "frontend",
+			DesiredState: running,
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "zodb_.*", Purpose: "import_all"},
+				{Application: "rabbitmq", Purpose: "import"},
+				{Application: "redis", Purpose: "import"},
+			},
+		}, {
+			ID:           "mariadb",
+			Name:         "MariaDB",
+			PoolID:       "backend",
+			DesiredState: running,
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "zodb_mariadb", Purpose: "export"},
+			},
+		}, {
+			ID:           "rabbitmq",
+			Name:         "RabbitMQ",
+			PoolID:       "backend",
+			DesiredState: running,
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "rabbitmq", Purpose: "export"},
+			},
+		}, {
+			ID:           "redis",
+			Name:         "Redis",
+			PoolID:       "frontend",
+			DesiredState: running,
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "redis", Purpose: "export"},
+			},
+		}, {
+			ID:           "reports",
+			Name:         "Reports",
+			PoolID:       "reporting",
+			DesiredState: int(service.SVCStop),
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "rabbitmq", Purpose: "import"},
+			},
+		},
+	}
+	pools := map[string]pool.ResourcePool{
+		"backend": {
+			ID:               "backend",
+			CrossPoolPolicy:  pool.CrossPoolRestrict,
+			CrossPoolImports: []pool.CrossPoolImport{{Application: "rabbitmq"}},
+		},
+	}
+
+	flows := buildCrossPoolFlows("tenant", svcs, pools)
+	c.Assert(flows, DeepEquals, []pool.CrossPoolFlow{
+		{
+			TenantID:          "tenant",
+			ServiceID:         "zope",
+			ServiceName:       "Zope",
+			PoolID:            "frontend",
+			ExportServiceID:   "mariadb",
+			ExportServiceName: "MariaDB",
+			ExportPoolID:      "backend",
+			Application:       "zodb_mariadb",
+			Allowed:           false,
+		}, {
+			TenantID:          "tenant",
+			ServiceID:         "zope",
+			ServiceName:       "Zope",
+			PoolID:            "frontend",
+			ExportServiceID:   "rabbitmq",
+			ExportServiceName: "RabbitMQ",
+			ExportPoolID:      "backend",
+			Application:       "rabbitmq",
+			Allowed:           true,
+		},
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/pool"
+)
+
+var (
+	// ErrDelegateProfileNotFound is returned when a delegate profile does
+	// not exist
+	ErrDelegateProfileNotFound = errors.New("facade: delegate profile not found")
+
+	// ErrDelegateProfileExists is returned when adding a delegate profile
+	// whose name is taken
+	ErrDelegateProfileExists = errors.New("facade: delegate profile exists")
+
+	// ErrDelegateProfileInUse is returned when removing a delegate profile
+	// that is assigned to a resource pool
+	ErrDelegateProfileInUse = errors.New("facade: delegate profile is assigned to a resource pool")
+
+	// ErrDelegateOptionNotFound is returned when setting a delegate option
+	// that profiles may not set
+	ErrDelegateOptionNotFound = errors.New("facade: delegate option not found")
+
+	// ErrDelegateProfilesUnavailable is returned when the facade cannot reach
+	// the agents of the delegates to apply their profiles
+	ErrDelegateProfilesUnavailable = errors.New("facade: delegate profiles cannot be pushed")
+)
+
+// GetDelegateProfiles returns the delegate profiles
+func (f *Facade) GetDelegateProfiles(ctx datastore.Context) ([]delegateprofile.Profile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetDelegateProfiles"))
+	return f.profileStore.GetProfiles(ctx)
+}
+
+// GetDelegateProfile returns a delegate profile with its previous versions
+func (f *Facade) GetDelegateProfile(ctx datastore.Context, name string) (*delegateprofile.Profile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetDelegateProfile"))
+	return f.getDelegateProfile(ctx, name)
+}
+
+// AddDelegateProfile adds a delegate profile without options
+func (f *Facade) AddDelegateProfile(ctx datastore.Context, name, description string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddDelegateProfile"))
+	alog := f.auditLogger.Message(ctx, "Adding Delegate Profile").
+		Action(audit.Add).ID(name).Type(delegateprofile.GetType())
+
+	if _, err := f.getDelegateProfile(ctx, name); err == nil {
+		return alog.Error(ErrDelegateProfileExists)
+	} else if err != ErrDelegateProfileNotFound {
+		return alog.Error(err)
+	}
+	p := delegateprofile.New(name, description)
+	if err := f.profileStore.Put(ctx, delegateprofile.Key(name), p); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// RemoveDelegateProfile removes a delegate profile that is not assigned to
+// any resource pool
+func (f *Facade) RemoveDelegateProfile(ctx datastore.Context, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveDelegateProfile"))
+	alog := f.auditLogger.Message(ctx, "Removing Delegate Profile").
+		Action(audit.Remove).ID(name).Type(delegateprofile.GetType())
+
+	if _, err := f.getDelegateProfile(ctx, name); err != nil {
+		return alog.Error(err)
+	}
+	pools, err := f.poolStore.GetResourcePools(ctx)
+	if err != nil {
+		return alog.Error(err)
+	}
+	for _, p := range pools {
+		if p.DelegateProfile == name {
+			return alog.Error(ErrDelegateProfileInUse)
+		}
+	}
+	if err := f.profileStore.Delete(ctx, delegateprofile.Key(name)); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// SetDelegateProfileOption sets an option of a delegate profile as a new
+// version, and pushes it to the hosts of the pools that the profile is
+// assigned to.
+func (f *Facade) SetDelegateProfileOption(ctx datastore.Context, name, option, value string) ([]delegateprofile.HostStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetDelegateProfileOption"))
+	opt, ok := delegateprofile.Lookup(option)
+	if !ok {
+		return nil, ErrDelegateOptionNotFound
+	}
+	if err := opt.Validate(value); err != nil {
+		return nil, err
+	}
+	return f.changeDelegateProfile(ctx, "Setting Delegate Profile Option", name, func(p *delegateprofile.Profile) error {
+		p.SetOptions(p.Set(option, value))
+		return nil
+	})
+}
+
+// UnsetDelegateProfileOption removes an option from a delegate profile as a
+// new version, and pushes it to the hosts of the pools that the profile is
+// assigned to.  The hosts use their own value of the option.
+func (f *Facade) UnsetDelegateProfileOption(ctx datastore.Context, name, option string) ([]delegateprofile.HostStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UnsetDelegateProfileOption"))
+	if _, ok := delegateprofile.Lookup(option); !ok {
+		return nil, ErrDelegateOptionNotFound
+	}
+	return f.changeDelegateProfile(ctx, "Unsetting Delegate Profile Option", name, func(p *delegateprofile.Profile) error {
+		p.SetOptions(p.Unset(option))
+		return nil
+	})
+}
+
+// RollbackDelegateProfile restores the options of a previous version of a
+// delegate profile as a new version, and pushes it to the hosts of the pools
+// that the profile is assigned to.  A version of 0 restores the version
+// before the current one.
+func (f *Facade) RollbackDelegateProfile(ctx datastore.Context, name string, version int) ([]delegateprofile.HostStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RollbackDelegateProfile"))
+	return f.changeDelegateProfile(ctx, "Rolling Back Delegate Profile", name, func(p *delegateprofile.Profile) error {
+		return p.Rollback(version)
+	})
+}
+
+// changeDelegateProfile applies a change to the options of a delegate profile
+// and pushes the new version.
+func (f *Facade) changeDelegateProfile(ctx datastore.Context, message, name string, change func(*delegateprofile.Profile) error) ([]delegateprofile.HostStatus, error) {
+	alog := f.auditLogger.Message(ctx, message).Action(audit.Update).ID(name).Type(delegateprofile.GetType())
+
+	p, err := f.getDelegateProfile(ctx, name)
+	if err != nil {
+		return nil, alog.Error(err)
+	}
+	before := *p
+	if err := change(p); err != nil {
+		return nil, alog.Error(err)
+	}
+	alog = alog.Delta(&before, p).WithFields(log.Fields{"version": p.Version})
+	if err := f.profileStore.Put(ctx, delegateprofile.Key(name), p); err != nil {
+		return nil, alog.Error(err)
+	}
+	alog.Succeeded()
+
+	pools, err := f.poolStore.GetResourcePools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []delegateprofile.HostStatus{}
+	for _, rp := range pools {
+		if rp.DelegateProfile == name {
+			statuses = append(statuses, f.pushDelegateProfile(ctx, rp.ID, p)...)
+		}
+	}
+	return statuses, nil
+}
+
+// AssignDelegateProfile assigns a delegate profile to a resource pool and
+// pushes it to the hosts of the pool.  An empty name removes the profile of
+// the pool, and the hosts use their own options.
+func (f *Facade) AssignDelegateProfile(ctx datastore.Context, poolID, name string) ([]delegateprofile.HostStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AssignDelegateProfile"))
+	alog := f.auditLogger.Message(ctx, "Assigning Delegate Profile").
+		Action(audit.Update).ID(poolID).Type(pool.GetType()).
+		WithField("profile", name)
+
+	var p *delegateprofile.Profile
+	if name != "" {
+		var err error
+		if p, err = f.getDelegateProfile(ctx, name); err != nil {
+			return nil, alog.Error(err)
+		}
+	}
+	rp, err := f.GetResourcePool(ctx, poolID)
+	if err != nil {
+		return nil, alog.Error(err)
+	} else if rp == nil {
+		return nil, alog.Error(ErrPoolNotExists)
+	}
+	if rp.DelegateProfile != name {
+		rp.DelegateProfile = name
+		if err := f.UpdateResourcePool(ctx, rp); err != nil {
+			return nil, alog.Error(err)
+		}
+	}
+	alog.Succeeded()
+	return f.pushDelegateProfile(ctx, poolID, p), nil
+}
+
+// PushDelegateProfile pushes the current version of the delegate profile of
+// a resource pool to the hosts of the pool, such as hosts that could not be
+// reached when the profile changed.
+func (f *Facade) PushDelegateProfile(ctx datastore.Context, poolID string) ([]delegateprofile.HostStatus, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PushDelegateProfile"))
+	rp := &pool.ResourcePool{}
+	if err := f.poolStore.Get(ctx, pool.Key(poolID), rp); datastore.IsErrNoSuchEntity(err) {
+		return nil, ErrPoolNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	var p *delegateprofile.Profile
+	if rp.DelegateProfile != "" {
+		var err error
+		if p, err = f.getDelegateProfile(ctx, rp.DelegateProfile); err != nil {
+			return nil, err
+		}
+	}
+	return f.pushDelegateProfile(ctx, poolID, p), nil
+}
+
+// GetHostDelegateProfile returns the delegate profile of the pool of a host,
+// which the delegate applies when it starts.  It returns an empty profile if
+// the pool has none.
+func (f *Facade) GetHostDelegateProfile(ctx datastore.Context, hostID string) (*delegateprofile.Profile, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetHostDelegateProfile"))
+	hst, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		return nil, err
+	} else if hst == nil {
+		return nil, ErrHostDoesNotExist
+	}
+	rp := &pool.ResourcePool{}
+	if err := f.poolStore.Get(ctx, pool.Key(hst.PoolID), rp); datastore.IsErrNoSuchEntity(err) {
+		return nil, ErrPoolNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	if rp.DelegateProfile == "" {
+		return &delegateprofile.Profile{Options: make(map[string]string)}, nil
+	}
+	return f.getDelegateProfile(ctx, rp.DelegateProfile)
+}
+
+// pushDelegateProfile applies a version of a profile on the agent of each
+// host in a pool.  A nil profile removes the options of the previous profile.
+// Hosts that cannot be reached apply the profile when they start.
+func (f *Facade) pushDelegateProfile(ctx datastore.Context, poolID string, p *delegateprofile.Profile) []delegateprofile.HostStatus {
+	if p == nil {
+		p = &delegateprofile.Profile{}
+	}
+	logger := plog.WithFields(log.Fields{
+		"poolid":  poolID,
+		"profile": p.ID,
+		"version": p.Version,
+	})
+
+	hosts, err := f.FindHostsInPool(ctx, poolID)
+	if err != nil {
+		logger.WithError(err).Warn("Could not look up the hosts of the pool to push the delegate profile")
+		return []delegateprofile.HostStatus{{PoolID: poolID, Profile: p.ID, Version: p.Version, Error: err.Error()}}
+	}
+
+	statuses := []delegateprofile.HostStatus{}
+	for _, hst := range hosts {
+		status := delegateprofile.HostStatus{
+			HostID:  hst.ID,
+			PoolID:  poolID,
+			Profile: p.ID,
+			Version: p.Version,
+		}
+		if f.delegateClient == nil {
+			status.Error = ErrDelegateProfilesUnavailable.Error()
+		} else {
+			address := fmt.Sprintf("%s:%d", hst.IPAddr, hst.RPCPort)
+			restart, err := f.delegateClient.ApplyDelegateProfile(address, p.ID, p.Version, p.Options)
+			if err != nil {
+				logger.WithField("hostid", hst.ID).WithError(err).Warn("Could not push the delegate profile to the host")
+				status.Error = err.Error()
+			}
+			status.Restart = restart
+		}
+		statuses = append(statuses, status)
+	}
+	logger.WithField("hosts", len(hosts)).Info("Pushed delegate profile")
+	return statuses
+}
+
+// getDelegateProfile returns the stored delegate profile
+func (f *Facade) getDelegateProfile(ctx datastore.Context, name string) (*delegateprofile.Profile, error) {
+	p := &delegateprofile.Profile{}
+	if err := f.profileStore.Get(ctx, delegateprofile.Key(name), p); datastore.IsErrNoSuchEntity(err) {
+		return nil, ErrDelegateProfileNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// testDelegateProfileClient records the profiles applied on each agent
+type testDelegateProfileClient struct {
+	applied map[string]int
+	err     error
+}
+
+func (t *testDelegateProfileClient) ApplyDelegateProfile(address, profile string, version int, options map[string]string) ([]string, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	t.applied[address] = version
+	return []string{"SERVICED_MUX_PORT"}, nil
+}
+
+func (ft *FacadeUnitTest) setupDelegateProfile(p *delegateprofile.Profile) *testDelegateProfileClient {
+	ft.profileStore.On("Get", ft.ctx, delegateprofile.Key(p.ID), mock.AnythingOfType("*delegateprofile.Profile")).Return(nil).Run(
+		func(args mock.Arguments) {
+			*args.Get(2).(*delegateprofile.Profile) = *p
+		})
+	ft.profileStore.On("Put", ft.ctx, delegateprofile.Key(p.ID), mock.AnythingOfType("*delegateprofile.Profile")).Return(nil)
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return([]pool.ResourcePool{
+		{ID: "backend", DelegateProfile: p.ID},
+		{ID: "frontend"},
+	}, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "backend").Return([]host.Host{
+		{ID: "host1", PoolID: "backend", IPAddr: "10.0.0.1", RPCPort: 4979},
+		{ID: "host2", PoolID: "backend", IPAddr: "10.0.0.2", RPCPort: 4979},
+	}, nil)
+
+	client := &testDelegateProfileClient{applied: make(map[string]int)}
+	ft.Facade.SetDelegateProfileClient(client)
+	return client
+}
+
+func (ft *FacadeUnitTest) Test_SetDelegateProfileOption_Pushes(c *C) {
+	client := ft.setupDelegateProfile(delegateprofile.New("debug", ""))
+	defer ft.Facade.SetDelegateProfileClient(nil)
+
+	statuses, err := ft.Facade.SetDelegateProfileOption(ft.ctx, "debug", "SERVICED_MUX_PORT", "22251")
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 2)
+	c.Assert(statuses[0].Version, Equals, 2)
+	c.Assert(statuses[0].Restart, DeepEquals, []string{"SERVICED_MUX_PORT"})
+	c.Assert(client.applied, DeepEquals, map[string]int{"10.0.0.1:4979": 2, "10.0.0.2:4979": 2})
+
+	ft.profileStore.AssertCalled(c, "Put", ft.ctx, delegateprofile.Key("debug"), mock.MatchedBy(func(p *delegateprofile.Profile) bool {
+		return p.Version == 2 && p.Options["SERVICED_MUX_PORT"] == "22251" && len(p.History) == 1
+	}))
+	ft.hostStore.AssertNotCalled(c, "FindHostsWithPoolID", ft.ctx, "frontend")
+}
+
+func (ft *FacadeUnitTest) Test_SetDelegateProfileOption_Invalid(c *C) {
+	_, err := ft.Facade.SetDelegateProfileOption(ft.ctx, "debug", "SERVICED_MASTER", "1")
+	c.Assert(err, Equals, facade.ErrDelegateOptionNotFound)
+
+	_, err = ft.Facade.SetDelegateProfileOption(ft.ctx, "debug", delegateprofile.LogLevel, "debug")
+	c.Assert(err, NotNil)
+	ft.profileStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RollbackDelegateProfile_Unreachable(c *C) {
+	p := delegateprofile.New("debug", "")
+	p.SetOptions(p.Set(delegateprofile.LogLevel, "2"))
+	client := ft.setupDelegateProfile(p)
+	client.err = errors.New("agent unreachable")
+	defer ft.Facade.SetDelegateProfileClient(nil)
+
+	statuses, err := ft.Facade.RollbackDelegateProfile(ft.ctx, "debug", 0)
+	c.Assert(err, IsNil)
+	c.Assert(statuses, HasLen, 2)
+	c.Assert(statuses[1].Version, Equals, 3)
+	c.Assert(statuses[1].Error, Equals, "agent unreachable")
+	ft.profileStore.AssertCalled(c, "Put", ft.ctx, delegateprofile.Key("debug"), mock.MatchedBy(func(p *delegateprofile.Profile) bool {
+		return p.Version == 3 && len(p.Options) == 0
+	}))
+}
+
+func (ft *FacadeUnitTest) Test_RemoveDelegateProfile_InUse(c *C) {
+	ft.setupDelegateProfile(delegateprofile.New("debug", ""))
+	defer ft.Facade.SetDelegateProfileClient(nil)
+
+	err := ft.Facade.RemoveDelegateProfile(ft.ctx, "debug")
+	c.Assert(err, Equals, facade.ErrDelegateProfileInUse)
+	ft.profileStore.AssertNotCalled(c, "Delete", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_GetHostDelegateProfile(c *C) {
+	ft.setupDelegateProfile(delegateprofile.New("debug", ""))
+	defer ft.Facade.SetDelegateProfileClient(nil)
+	for _, h := range []host.Host{{ID: "host1", PoolID: "backend"}, {ID: "host3", PoolID: "frontend"}} {
+		h := h
+		ft.hostStore.On("Get", ft.ctx, host.HostKey(h.ID), mock.AnythingOfType("*host.Host")).Return(nil).Run(
+			func(args mock.Arguments) {
+				*args.Get(2).(*host.Host) = h
+			})
+	}
+	for _, p := range []pool.ResourcePool{{ID: "backend", DelegateProfile: "debug"}, {ID: "frontend"}} {
+		p := p
+		ft.poolStore.On("Get", ft.ctx, pool.Key(p.ID), mock.AnythingOfType("*pool.ResourcePool")).Return(nil).Run(
+			func(args mock.Arguments) {
+				*args.Get(2).(*pool.ResourcePool) = p
+			})
+	}
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host4"), mock.AnythingOfType("*host.Host")).Return(datastore.ErrNoSuchEntity{})
+
+	p, err := ft.Facade.GetHostDelegateProfile(ft.ctx, "host1")
+	c.Assert(err, IsNil)
+	c.Assert(p.ID, Equals, "debug")
+
+	p, err = ft.Facade.GetHostDelegateProfile(ft.ctx, "host3")
+	c.Assert(err, IsNil)
+	c.Assert(p.ID, Equals, "")
+	c.Assert(p.Version, Equals, 0)
+
+	_, err = ft.Facade.GetHostDelegateProfile(ft.ctx, "host4")
+	c.Assert(err, Equals, facade.ErrHostDoesNotExist)
+}
@@ -20,7 +20,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -33,6 +35,7 @@ import (
 	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/volume"
 	"github.com/dustin/go-humanize"
@@ -70,6 +73,16 @@ var registryVersionInfos = map[int]registryVersionInfo{
 func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, snapshotSpacePercent int, backupFilename string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Backup"))
 	// Do not DFSLock here, ControlPlaneDao does that
+	data, err := f.prepareBackup(ctx, excludes, snapshotSpacePercent, backupFilename)
+	if err != nil {
+		return err
+	}
+	return f.writeBackup(ctx, w, data, backupFilename)
+}
+
+// prepareBackup snapshots all of the tenants and collects the metadata of a
+// backup.  The snapshots are deleted if the backup cannot be prepared.
+func (f *Facade) prepareBackup(ctx datastore.Context, excludes []string, snapshotSpacePercent int, backupFilename string) (data *dfs.BackupInfo, err error) {
 	stime := time.Now()
 	message := fmt.Sprintf("started backup at %s", stime.UTC())
 	plog.WithField("excludes", excludes).Info("Started backup")
@@ -84,46 +97,51 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 	templates, images, err := f.GetServiceTemplatesAndImages(ctx)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get service templates and images")
-		return alog.Error(err)
+		return nil, alog.Error(err)
 	}
 	plog.WithField("elapsed", time.Since(stime)).Info("Loaded templates and their images")
 	pools, err := f.GetResourcePools(ctx)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get resource pools")
-		return alog.Error(err)
+		return nil, alog.Error(err)
 	}
 	plog.WithField("elapsed", time.Since(stime)).Info("Loaded resource pools")
 	tenants, err := f.GetTenantIDs(ctx)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get tenants")
-		return alog.Error(err)
+		return nil, alog.Error(err)
 	}
-	snapshots := make([]string, len(tenants))
+	snapshots := []string{}
+	elasticSnapshots := []dfs.ElasticSnapshot{}
+	defer func() {
+		if err != nil {
+			for _, snapshot := range snapshots {
+				f.deleteBackupSnapshot(ctx, snapshot)
+			}
+			f.deleteElasticSnapshots(elasticSnapshots)
+		}
+	}()
 	snapshotExcludes := map[string][]string{}
-	for i, tenant := range tenants {
+	for _, tenant := range tenants {
 		tenantLogger := plog.WithField("tenant", tenant)
 		tag := fmt.Sprintf("backup-%s-%s", tenant, stime)
 		snapshot, err := f.Snapshot(ctx, tenant, message, []string{tag}, snapshotSpacePercent)
 		if err != nil {
 			tenantLogger.WithError(err).Debug("Could not snapshot tenant")
-			return alog.Error(err)
+			return nil, alog.Error(err)
 		}
-
-		defer func(tenant, snapshot, tag string) {
-			if err := f.DeleteSnapshot(ctx, snapshot); err != nil {
-				tenantLogger.WithError(err).Warn("Could not delete snapshot; untagging for consumption by TTL")
-				if _, err := f.RemoveSnapshotTag(ctx, tenant, tag); err != nil {
-					tenantLogger.WithError(err).Error("Could not untag snapshot.  Snapshot must be deleted manually!")
-				}
-			}
-		}(tenant, snapshot, tag)
-
-		snapshots[i] = snapshot
+		snapshots = append(snapshots, snapshot)
 		snapshotExcludes[snapshot] = append(excludes, f.getExcludedVolumes(ctx, tenant)...)
 		tenantLogger.WithField("snapshot", snapshot).Info("Created a snapshot for tenant")
 	}
 	plog.WithField("elapsed", time.Since(stime)).Info("Loaded tenants")
-	data := dfs.BackupInfo{
+	elasticSnapshots, err = f.prepareElasticBackup(stime.UTC().Format("backup-2006-01-02-150405"))
+	if err != nil {
+		plog.WithError(err).Debug("Could not snapshot elasticsearch")
+		return nil, alog.Error(err)
+	}
+	plog.WithField("elapsed", time.Since(stime)).Info("Loaded elasticsearch snapshots")
+	return &dfs.BackupInfo{
 		Templates:        templates,
 		BaseImages:       images,
 		Pools:            pools,
@@ -131,13 +149,29 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 		SnapshotExcludes: snapshotExcludes,
 		Timestamp:        stime,
 		BackupVersion:    1,
-	}
+		ElasticSnapshots: elasticSnapshots,
+	}, nil
+}
+
+// writeBackup writes a prepared backup and deletes its snapshots.  If the
+// writer implements dfs.Checkpoint, the snapshots are left for the caller to
+// delete, so that a failed backup can be resumed.
+func (f *Facade) writeBackup(ctx datastore.Context, w io.Writer, data *dfs.BackupInfo, backupFilename string) error {
+	alog := f.auditLogger.Message(ctx, "Completed Backup").
+		Action(audit.Backup)
 	plog.WithField("data", data).Info("Calling dfs.Backup")
-	if err := f.dfs.Backup(data, w); err != nil {
+	err := f.dfs.Backup(*data, w)
+	if _, ok := w.(dfs.Checkpoint); !ok {
+		for _, snapshot := range data.Snapshots {
+			f.deleteBackupSnapshot(ctx, snapshot)
+		}
+		f.deleteElasticSnapshots(data.ElasticSnapshots)
+	}
+	if err != nil {
 		plog.WithError(err).Debug("Could not backup")
 		return alog.Error(err)
 	}
-	duration := time.Since(stime)
+	duration := time.Since(data.Timestamp)
 	plog.WithField("duration", duration).Info("Completed backup")
 	alog.WithFields(logrus.Fields{
 				"backupfile": backupFilename,
@@ -146,6 +180,27 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 	return nil
 }
 
+// deleteBackupSnapshot deletes a snapshot that was taken for or restored from
+// a backup.  If the snapshot cannot be deleted, it is untagged so that the TTL
+// reaper removes it eventually.
+func (f *Facade) deleteBackupSnapshot(ctx datastore.Context, snapshot string) {
+	logger := plog.WithField("snapshot", snapshot)
+	if err := f.DeleteSnapshot(ctx, snapshot); err != nil {
+		info, err := f.dfs.Info(snapshot)
+		if err != nil {
+			logger.WithError(err).Warning("Could not get info for snapshot.")
+		} else if len(info.Tags) > 0 {
+			if _, err := f.dfs.Untag(info.TenantID, info.Tags[0]); err != nil {
+				logger.WithError(err).Warning("Could not untag snapshot.  Snapshot must be deleted manually!")
+			} else {
+				logger.Info("Snapshot from backup untagged.")
+			}
+		}
+	} else {
+		logger.Info("Removed snapshot from backup")
+	}
+}
+
 // EstimateBackup estimates storage requirements to take a backup of all installed applications
 func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest, estimate *dao.BackupEstimate) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EstimateBackup"))
@@ -209,13 +264,16 @@ func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest
 	plog.WithField("elapsed", time.Since(stime)).Debugf("Estimated Docker pull size at %d", size)
 	DockerBytesRequired = size
 
+	ElasticBytesRequired := f.estimateElasticBackup()
+	plog.WithField("elapsed", time.Since(stime)).Debugf("Estimated elasticsearch snapshot size at %d", ElasticBytesRequired)
+
 	MinOverheadBytes, err := humanize.ParseBytes(options.BackupMinOverhead)
 	if err != nil {
 		plog.WithError(err).Info("Unable to get MinOverheadBytes")
 		MinOverheadBytes = 1 * 1000 * 1000 * 1000 // default to 1G
 	}
 	CompressionEst := options.BackupEstimatedCompression
-	TotalBytesRequired := FilesystemBytesRequired + DockerBytesRequired
+	TotalBytesRequired := FilesystemBytesRequired + DockerBytesRequired + ElasticBytesRequired
 	AdjustedBytesRequired := uint64(float64(TotalBytesRequired)/CompressionEst+0.5) + MinOverheadBytes
 	estimate.EstimatedBytes = AdjustedBytesRequired
 	estimate.EstimatedString = humanize.Bytes(AdjustedBytesRequired)
@@ -225,6 +283,7 @@ func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest
 		"duration":                   time.Since(stime),
 		"filesystembytes":            FilesystemBytesRequired,
 		"dockerbytes":                DockerBytesRequired,
+		"elasticbytes":               ElasticBytesRequired,
 		"BackupEstimatedCompression": CompressionEst,
 		"BackupMinOverhead":          options.BackupMinOverhead,
 		"estimate":                   estimate,
@@ -253,6 +312,12 @@ func (f *Facade) Commit(ctx datastore.Context, ctrID, message string, tags []str
 		return "", err
 	}
 	logger = logger.WithField("tenantid", tenantID)
+	// the services of the tenant run the committed image once they are
+	// pinned to it
+	if _, err := f.RefreshServiceImagePins(ctx, tenantID); err != nil {
+		logger.WithError(err).Debug("Could not pin the services of the tenant to the committed image")
+		return "", err
+	}
 	snapshotID, err := f.Snapshot(ctx, tenantID, message, tags, snapshotSpacePercent)
 	if err != nil {
 		logger.WithError(err).Debug("Could not snapshot tenant")
@@ -261,10 +326,15 @@ func (f *Facade) Commit(ctx datastore.Context, ctrID, message string, tags []str
 	return snapshotID, nil
 }
 
-// DeleteSnapshot removes a snapshot from an application.
+// DeleteSnapshot removes a snapshot from an application.  Snapshots that are
+// pinned, or whose image tag is pinned, cannot be removed.
 func (f *Facade) DeleteSnapshot(ctx datastore.Context, snapshotID string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeleteSnapshot"))
 	// Do not DFSLock here, ControlPlaneDao does that
+	if err := f.checkSnapshotPins(ctx, snapshotID); err != nil {
+		plog.WithField("snapshotid", snapshotID).WithError(err).Debug("Could not delete snapshot")
+		return err
+	}
 	if err := f.dfs.Delete(snapshotID); err != nil {
 		plog.WithField("snapshotid", snapshotID).WithError(err).Debug("Could not delete snapshot")
 		return err
@@ -574,7 +644,11 @@ func (f *Facade) markLocalDockerRegistryUpgraded(version int) error {
 	return nil
 }
 
-// Restore restores application data from a backup.
+// Restore restores application data from a backup.  If the reader implements
+// dfs.Checkpoint, the snapshots that were rolled back by a previous attempt
+// are skipped.  If the reader renames the tenants of the backup, the
+// applications are restored alongside the existing applications instead of
+// replacing them.
 func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.BackupInfo, backupFilename string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Restore"))
 	// Do not DFSLock here, ControlPlaneDao does that
@@ -590,6 +664,9 @@ func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.Bac
 		plog.WithError(err).Debug("Could not restore from backup")
 		return alog.Error(err)
 	}
+	if pr, ok := r.(dfs.PhaseReporter); ok {
+		pr.Phase(dfs.PhaseMetadata)
+	}
 	if err := f.RestoreServiceTemplates(ctx, backupInfo.Templates); err != nil {
 		plog.WithError(err).Debug("Could not restore service templates from backup")
 		return alog.Error(err)
@@ -600,30 +677,34 @@ func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.Bac
 		return alog.Error(err)
 	}
 	plog.Info("Restored resource pools")
+	cp, _ := r.(dfs.Checkpoint)
+	rename := getRestoreRename(r)
 	for _, snapshot := range backupInfo.Snapshots {
 		logger := plog.WithField("snapshot", snapshot)
-		if err := f.Rollback(ctx, snapshot, false); err != nil {
+		section := path.Join(rollbackSection, snapshot)
+		if cp != nil && cp.Completed(section) {
+			logger.Info("Snapshot was rolled back by a previous attempt")
+			continue
+		}
+		restored := snapshot
+		if rename != nil {
+			var err error
+			if restored, err = f.restoreSnapshotAs(ctx, snapshot, rename); err != nil {
+				logger.WithError(err).Debug("Could not restore snapshot alongside the existing applications")
+				return alog.Error(err)
+			}
+		} else if err := f.Rollback(ctx, snapshot, false); err != nil {
 			logger.WithError(err).Debug("Could not rollback snapshot")
 			return alog.Error(err)
-		} else {
-			logger.Info("Rolled back snapshot")
-			if err := f.dfs.Delete(snapshot); err != nil {
-				// if we couldn't delete, untag it so the TTL reaper will get it eventually
-				info, err := f.dfs.Info(snapshot)
-				if err != nil {
-					logger.WithError(err).Warning("Could not get info for snapshot.")
-				} else if len(info.Tags) > 0 {
-					if _, err := f.dfs.Untag(info.TenantID, info.Tags[0]); err != nil {
-						logger.WithError(err).Warning("Could not untag snapshot.  Snapshot must be deleted manually!")
-					} else {
-						logger.Info("Snapshot from backup untagged.")
-					}
-				}
-			} else {
-				logger.Info("Removed snapshot after rollback")
+		}
+		logger.Info("Rolled back snapshot")
+		if cp != nil {
+			if err := cp.Checkpoint(section); err != nil {
+				logger.WithError(err).Debug("Could not checkpoint rollback of snapshot")
+				return alog.Error(err)
 			}
 		}
-
+		f.deleteBackupSnapshot(ctx, restored)
 	}
 	restoreDuration := time.Since(stime)
 	plog.Info("Completed restore from backup")
@@ -709,6 +790,105 @@ func (f *Facade) Rollback(ctx datastore.Context, snapshotID string, force bool)
 	return nil
 }
 
+// RollbackPreview returns the services that are stopped, the images that
+// are retagged and the config files that are changed by a rollback to the
+// snapshot, without rolling back.
+func (f *Facade) RollbackPreview(ctx datastore.Context, snapshotID string) (*dfs.RollbackPreview, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RollbackPreview"))
+	logger := plog.WithField("snapshotid", snapshotID)
+	info, err := f.dfs.Info(snapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get info for snapshot")
+		return nil, err
+	}
+	logger = logger.WithField("tenantid", info.TenantID)
+	svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: info.TenantID})
+	if err != nil {
+		logger.WithError(err).Debug("Could not get services under tenant")
+		return nil, err
+	}
+	images, err := f.dfs.RollbackImages(snapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not compare the images of the snapshot")
+		return nil, err
+	}
+	preview := &dfs.RollbackPreview{
+		SnapshotID:  snapshotID,
+		TenantID:    info.TenantID,
+		Stopped:     []dfs.RollbackService{},
+		Images:      images,
+		ConfigFiles: diffConfigFiles(svcs, info.Services),
+	}
+	for _, svc := range svcs {
+		if svc.DesiredState == int(service.SVCRun) {
+			preview.Stopped = append(preview.Stopped, dfs.RollbackService{ServiceID: svc.ID, Name: svc.Name})
+		}
+	}
+	sort.Slice(preview.Stopped, func(i, j int) bool {
+		return preview.Stopped[i].Name < preview.Stopped[j].Name
+	})
+	return preview, nil
+}
+
+// diffConfigFiles returns the config files of the current services that are
+// changed by restoring the services of a snapshot.
+func diffConfigFiles(current, snapshot []service.Service) []dfs.ConfigFileChange {
+	type serviceConfigs struct {
+		name    string
+		current map[string]servicedefinition.ConfigFile
+		restore map[string]servicedefinition.ConfigFile
+	}
+	configs := make(map[string]*serviceConfigs)
+	get := func(svc service.Service) *serviceConfigs {
+		c, ok := configs[svc.ID]
+		if !ok {
+			c = &serviceConfigs{name: svc.Name}
+			configs[svc.ID] = c
+		}
+		return c
+	}
+	for _, svc := range current {
+		get(svc).current = svc.ConfigFiles
+	}
+	for _, svc := range snapshot {
+		get(svc).restore = svc.ConfigFiles
+	}
+
+	changes := []dfs.ConfigFileChange{}
+	for serviceID, c := range configs {
+		for filename, conf := range c.current {
+			change := dfs.ConfigFileChange{ServiceID: serviceID, Name: c.name, Filename: filename}
+			if restored, ok := c.restore[filename]; !ok {
+				change.Change = dfs.ConfigFileRemoved
+			} else if restored != conf {
+				change.Change = dfs.ConfigFileModified
+			} else {
+				continue
+			}
+			changes = append(changes, change)
+		}
+		for filename := range c.restore {
+			if _, ok := c.current[filename]; !ok {
+				changes = append(changes, dfs.ConfigFileChange{
+					ServiceID: serviceID,
+					Name:      c.name,
+					Filename:  filename,
+					Change:    dfs.ConfigFileAdded,
+				})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		} else if changes[i].ServiceID != changes[j].ServiceID {
+			return changes[i].ServiceID < changes[j].ServiceID
+		}
+		return changes[i].Filename < changes[j].Filename
+	})
+	return changes
+}
+
 // Snapshot takes a snapshot for a particular application.
 func (f *Facade) Snapshot(ctx datastore.Context, serviceID, message string, tags []string, snapshotSpacePercent int) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Snapshot"))
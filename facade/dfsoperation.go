@@ -0,0 +1,267 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+)
+
+// Types of DFS operations, besides backups and restores
+const (
+	DFSOperationSnapshot  = "snapshot"
+	DFSOperationRollback  = "rollback"
+	DFSOperationImagePush = "image push"
+)
+
+// States of a DFS operation
+const (
+	DFSOperationQueued  = "queued"
+	DFSOperationRunning = "running"
+)
+
+/*
+   Snapshots, rollbacks, backups, restores and image pushes are heavy on the
+   storage backend.  A pool can limit how many of these operations run at once
+   on its applications; the operations past the limit wait in a queue, in the
+   order they were submitted, so that operators can see what is ahead of them.
+   Backups and restores cover every application, so they count against the
+   limit of every pool that has an application.  Operations are admitted
+   before they wait for the dfs lock.
+*/
+
+// dfsOperation is an operation in the queue, with the limits of its pools
+// at the time that it was submitted.
+type dfsOperation struct {
+	dao.DFSOperation
+	limits map[string]int
+}
+
+// dfsOperationQueue admits DFS operations in the order that they are
+// submitted, within the limits of their pools.  Its methods do nothing on a
+// nil queue.
+type dfsOperationQueue struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	ops  []*dfsOperation
+	seq  int
+	now  func() time.Time
+}
+
+func newDFSOperationQueue() *dfsOperationQueue {
+	q := &dfsOperationQueue{now: time.Now}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// begin blocks until the operation can run within the limits of its pools,
+// and returns a function that ends the operation.  If cancel is closed while
+// the operation is queued, it leaves the queue and ErrOperationCanceled is
+// returned.
+func (q *dfsOperationQueue) begin(op dao.DFSOperation, limits map[string]int, cancel <-chan struct{}) (func(), error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	op.ID = strconv.Itoa(q.seq)
+	op.State = DFSOperationQueued
+	op.SubmittedAt = q.now().UTC()
+	entry := &dfsOperation{DFSOperation: op, limits: limits}
+	q.ops = append(q.ops, entry)
+
+	logger := plog.WithFields(logrus.Fields{
+		"operation": op.Type,
+		"target":    op.Target,
+		"poolids":   op.PoolIDs,
+	})
+	if !q.admissible(entry) {
+		logger.Info("Queued DFS operation behind the concurrent operation limits of its pools")
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-cancel:
+				q.mu.Lock()
+				q.cond.Broadcast()
+				q.mu.Unlock()
+			case <-done:
+			}
+		}()
+		for !q.admissible(entry) {
+			select {
+			case <-cancel:
+				q.remove(entry)
+				logger.Info("Canceled queued DFS operation")
+				return nil, ErrOperationCanceled
+			default:
+			}
+			q.cond.Wait()
+		}
+	}
+	entry.State = DFSOperationRunning
+	entry.StartedAt = q.now().UTC()
+	logger.Debug("Started DFS operation")
+
+	// operations that were queued behind this one may be able to run
+	q.cond.Broadcast()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			q.remove(entry)
+			logger.Debug("Ended DFS operation")
+		})
+	}, nil
+}
+
+// remove takes an operation out of the queue and wakes the operations that
+// may be able to run without it.  The caller holds the lock.
+func (q *dfsOperationQueue) remove(entry *dfsOperation) {
+	for i, o := range q.ops {
+		if o == entry {
+			q.ops = append(q.ops[:i], q.ops[i+1:]...)
+			break
+		}
+	}
+	q.cond.Broadcast()
+}
+
+// admissible returns true if the operation can run; none of its pools may
+// be at their limit or have an operation that was queued before it.
+func (q *dfsOperationQueue) admissible(op *dfsOperation) bool {
+	for _, poolID := range op.PoolIDs {
+		limit := op.limits[poolID]
+		if limit <= 0 {
+			continue
+		}
+		running, ahead := 0, true
+		for _, other := range q.ops {
+			if other == op {
+				ahead = false
+			} else if !containsString(other.PoolIDs, poolID) {
+				continue
+			} else if other.State == DFSOperationRunning {
+				running++
+			} else if ahead {
+				return false
+			}
+		}
+		if running >= limit {
+			return false
+		}
+	}
+	return true
+}
+
+// list returns the running and queued operations, in the order that they
+// were submitted.
+func (q *dfsOperationQueue) list() []dao.DFSOperation {
+	ops := []dao.DFSOperation{}
+	if q == nil {
+		return ops
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	position := 0
+	for _, o := range q.ops {
+		op := o.DFSOperation
+		op.PoolIDs = append([]string{}, o.PoolIDs...)
+		if op.State == DFSOperationQueued {
+			position++
+			op.Position = position
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// BeginDFSOperation waits until the pools of the tenants can run another DFS
+// operation, and returns a function that ends the operation.  If no tenants
+// are given, the operation counts against every pool that has a tenant.  If
+// the operation of the context is canceled while it waits,
+// ErrOperationCanceled is returned.
+func (f *Facade) BeginDFSOperation(ctx datastore.Context, opType, target string, tenantIDs ...string) (func(), error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.BeginDFSOperation"))
+	logger := plog.WithFields(logrus.Fields{
+		"operation": opType,
+		"target":    target,
+	})
+
+	if len(tenantIDs) == 0 {
+		var err error
+		if tenantIDs, err = f.GetTenantIDs(ctx); err != nil {
+			logger.WithError(err).Debug("Could not look up the tenants of the operation")
+			return nil, err
+		}
+	}
+
+	limits := make(map[string]int)
+	for _, tenantID := range tenantIDs {
+		svc, err := f.GetService(ctx, tenantID)
+		if err != nil {
+			logger.WithField("tenantid", tenantID).WithError(err).Debug("Could not look up the tenant of the operation")
+			return nil, err
+		}
+		if _, ok := limits[svc.PoolID]; ok {
+			continue
+		}
+		p, err := f.GetResourcePool(ctx, svc.PoolID)
+		if err != nil {
+			logger.WithField("poolid", svc.PoolID).WithError(err).Debug("Could not look up the pool of the operation")
+			return nil, err
+		}
+		limits[svc.PoolID] = 0
+		if p != nil {
+			limits[svc.PoolID] = p.MaxDFSOperations
+		}
+	}
+	poolIDs := make([]string, 0, len(limits))
+	for poolID := range limits {
+		poolIDs = append(poolIDs, poolID)
+	}
+	sort.Strings(poolIDs)
+
+	return f.dfsOperations.begin(dao.DFSOperation{
+		Type:    opType,
+		Target:  target,
+		PoolIDs: poolIDs,
+		User:    ctx.User(),
+	}, limits, operationFromContext(ctx).Canceled())
+}
+
+// GetDFSOperations returns the DFS operations that are running or queued
+func (f *Facade) GetDFSOperations(ctx datastore.Context) ([]dao.DFSOperation, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetDFSOperations"))
+	return f.dfsOperations.list(), nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/dao"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&DFSOperationQueueTest{})
+
+type DFSOperationQueueTest struct{}
+
+// waitForState waits until the operations in the queue are in the states
+func waitForState(c *C, q *dfsOperationQueue, states ...string) []dao.DFSOperation {
+	timeout := time.After(5 * time.Second)
+	for {
+		ops := q.list()
+		actual := make([]string, len(ops))
+		for i, op := range ops {
+			actual[i] = op.State
+		}
+		if len(actual) == len(states) {
+			match := true
+			for i := range states {
+				match = match && actual[i] == states[i]
+			}
+			if match {
+				return ops
+			}
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("operations are %v, expected %v", actual, states)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (t *DFSOperationQueueTest) TestDFSOperationQueue(c *C) {
+	q := newDFSOperationQueue()
+	limits := map[string]int{"default": 1, "other": 0}
+
+	endA, err := q.begin(dao.DFSOperation{Type: DFSOperationSnapshot, Target: "a", PoolIDs: []string{"default"}}, limits, nil)
+	c.Assert(err, IsNil)
+	waitForState(c, q, DFSOperationRunning)
+
+	// the second operation on the pool is queued
+	ended := make(chan func(), 2)
+	go func() {
+		end, _ := q.begin(dao.DFSOperation{Type: DFSOperationRollback, Target: "b", PoolIDs: []string{"default"}}, limits, nil)
+		ended <- end
+	}()
+	ops := waitForState(c, q, DFSOperationRunning, DFSOperationQueued)
+	c.Assert(ops[1].Target, Equals, "b")
+	c.Assert(ops[1].Position, Equals, 1)
+
+	// operations on pools without a limit are not queued
+	endC, err := q.begin(dao.DFSOperation{Type: DFSOperationImagePush, Target: "c", PoolIDs: []string{"other"}}, limits, nil)
+	c.Assert(err, IsNil)
+	waitForState(c, q, DFSOperationRunning, DFSOperationQueued, DFSOperationRunning)
+	endC()
+
+	// backups wait behind the operations that were queued before them
+	go func() {
+		end, _ := q.begin(dao.DFSOperation{Type: BackupOperation, PoolIDs: []string{"default", "other"}}, limits, nil)
+		ended <- end
+	}()
+	ops = waitForState(c, q, DFSOperationRunning, DFSOperationQueued, DFSOperationQueued)
+	c.Assert(ops[2].Type, Equals, BackupOperation)
+	c.Assert(ops[2].Position, Equals, 2)
+
+	endA()
+	ops = waitForState(c, q, DFSOperationRunning, DFSOperationQueued)
+	c.Assert(ops[0].Target, Equals, "b")
+	endB := <-ended
+	endB()
+	endB() // ending an operation twice does nothing
+	waitForState(c, q, DFSOperationRunning)
+	(<-ended)()
+	waitForState(c, q)
+}
+
+func (t *DFSOperationQueueTest) TestDFSOperationQueueNil(c *C) {
+	var q *dfsOperationQueue
+	end, err := q.begin(dao.DFSOperation{Type: DFSOperationSnapshot}, nil, nil)
+	c.Assert(err, IsNil)
+	end()
+	c.Assert(q.list(), HasLen, 0)
+}
+
+func (t *DFSOperationQueueTest) TestDFSOperationQueueCancel(c *C) {
+	q := newDFSOperationQueue()
+	limits := map[string]int{"default": 1}
+
+	endA, err := q.begin(dao.DFSOperation{Type: DFSOperationSnapshot, Target: "a", PoolIDs: []string{"default"}}, limits, nil)
+	c.Assert(err, IsNil)
+	defer endA()
+
+	// a queued operation leaves the queue when it is canceled
+	cancel := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		_, err := q.begin(dao.DFSOperation{Type: BackupOperation, PoolIDs: []string{"default"}}, limits, cancel)
+		errs <- err
+	}()
+	waitForState(c, q, DFSOperationRunning, DFSOperationQueued)
+	close(cancel)
+	select {
+	case err := <-errs:
+		c.Assert(err, Equals, ErrOperationCanceled)
+	case <-time.After(5 * time.Second):
+		c.Fatalf("canceled operation did not leave the queue")
+	}
+	waitForState(c, q, DFSOperationRunning)
+}
@@ -0,0 +1,276 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+)
+
+const (
+	// ElasticServiced is the elasticsearch cluster of the control plane
+	ElasticServiced = "serviced"
+
+	// ElasticLogstash is the elasticsearch cluster of the application logs
+	ElasticLogstash = "logstash"
+
+	// logstashIndexPrefix is the prefix of the daily logstash indices
+	logstashIndexPrefix = "logstash-"
+
+	// logstashIndexDate is the date format of the daily logstash indices
+	logstashIndexDate = "2006.01.02"
+)
+
+var (
+	// ErrElasticNotConfigured is returned when the snapshots of an
+	// elasticsearch cluster cannot be taken or restored
+	ErrElasticNotConfigured = errors.New("facade: elasticsearch snapshots are not configured for the cluster")
+)
+
+// servicedIndices are the control plane indices that backups include
+var servicedIndices = []string{"controlplane"}
+
+// prepareElasticBackup snapshots the control plane indices and the most
+// recent logstash indices for a backup.  The backup fails if the control
+// plane cannot be snapshotted, but it leaves out the application logs if
+// they cannot be snapshotted.
+func (f *Facade) prepareElasticBackup(name string) ([]dfs.ElasticSnapshot, error) {
+	snapshots := []dfs.ElasticSnapshot{}
+	if client, ok := f.elasticClients[ElasticServiced]; ok {
+		logger := plog.WithFields(logrus.Fields{
+			"cluster":  ElasticServiced,
+			"snapshot": name,
+		})
+		if err := client.CreateSnapshot(name, servicedIndices); err != nil {
+			logger.WithError(err).Debug("Could not snapshot the control plane indices")
+			if err := client.DeleteSnapshot(name); err != nil {
+				logger.WithError(err).Warn("Could not delete elasticsearch snapshot")
+			}
+			return nil, err
+		}
+		snapshots = append(snapshots, dfs.ElasticSnapshot{
+			Cluster: ElasticServiced,
+			Name:    name,
+			Indices: servicedIndices,
+			Path:    client.SnapshotPath(name),
+		})
+		logger.Info("Created a snapshot of the control plane indices")
+	}
+
+	options := config.GetOptions()
+	if client, ok := f.elasticClients[ElasticLogstash]; ok && options.BackupLogstashDays > 0 {
+		logger := plog.WithFields(logrus.Fields{
+			"cluster":  ElasticLogstash,
+			"snapshot": name,
+		})
+		sizes, err := client.IndexSizes(logstashIndexPrefix + "*")
+		if err != nil {
+			logger.WithError(err).Warn("Could not get the sizes of the logstash indices, leaving application logs out of the backup")
+			return snapshots, nil
+		}
+		maxBytes := int64(options.BackupLogstashMaxSize) << 30
+		indices := selectLogstashIndices(sizes, options.BackupLogstashDays, maxBytes, time.Now())
+		if len(indices) == 0 {
+			logger.Info("No logstash indices to include in the backup")
+			return snapshots, nil
+		}
+		if err := client.CreateSnapshot(name, indices); err != nil {
+			logger.WithError(err).Warn("Could not snapshot the logstash indices, leaving application logs out of the backup")
+			if err := client.DeleteSnapshot(name); err != nil {
+				logger.WithError(err).Warn("Could not delete elasticsearch snapshot")
+			}
+			return snapshots, nil
+		}
+		snapshots = append(snapshots, dfs.ElasticSnapshot{
+			Cluster: ElasticLogstash,
+			Name:    name,
+			Indices: indices,
+			Path:    client.SnapshotPath(name),
+		})
+		logger.WithField("indices", indices).Info("Created a snapshot of the logstash indices")
+	}
+	return snapshots, nil
+}
+
+// selectLogstashIndices returns the daily logstash indices of the last days,
+// newest first, up to a total size of maxBytes.  If maxBytes is 0, the size
+// is not limited.
+func selectLogstashIndices(sizes map[string]int64, days int, maxBytes int64, now time.Time) []string {
+	if days <= 0 {
+		return nil
+	}
+	y, m, d := now.UTC().Date()
+	cutoff := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1-days)
+
+	candidates := []string{}
+	for index := range sizes {
+		date, err := time.Parse(logstashIndexDate, strings.TrimPrefix(index, logstashIndexPrefix))
+		if err != nil || !strings.HasPrefix(index, logstashIndexPrefix) {
+			continue
+		}
+		if !date.Before(cutoff) {
+			candidates = append(candidates, index)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(candidates)))
+
+	indices := []string{}
+	var total int64
+	for _, index := range candidates {
+		if maxBytes > 0 && total+sizes[index] > maxBytes {
+			break
+		}
+		total += sizes[index]
+		indices = append(indices, index)
+	}
+	return indices
+}
+
+// estimateElasticBackup returns the size of the elasticsearch indices that
+// a backup would include.
+func (f *Facade) estimateElasticBackup() uint64 {
+	var total int64
+	if client, ok := f.elasticClients[ElasticServiced]; ok {
+		sizes, err := client.IndexSizes(strings.Join(servicedIndices, ","))
+		if err != nil {
+			plog.WithError(err).Info("Could not get the sizes of the control plane indices")
+		}
+		for _, size := range sizes {
+			total += size
+		}
+	}
+	options := config.GetOptions()
+	if client, ok := f.elasticClients[ElasticLogstash]; ok && options.BackupLogstashDays > 0 {
+		sizes, err := client.IndexSizes(logstashIndexPrefix + "*")
+		if err != nil {
+			plog.WithError(err).Info("Could not get the sizes of the logstash indices")
+		}
+		maxBytes := int64(options.BackupLogstashMaxSize) << 30
+		for _, index := range selectLogstashIndices(sizes, options.BackupLogstashDays, maxBytes, time.Now()) {
+			total += sizes[index]
+		}
+	}
+	return uint64(total)
+}
+
+// deleteElasticSnapshots deletes the elasticsearch snapshots that were taken
+// for a backup.
+func (f *Facade) deleteElasticSnapshots(snapshots []dfs.ElasticSnapshot) {
+	for _, snapshot := range snapshots {
+		logger := plog.WithFields(logrus.Fields{
+			"cluster":  snapshot.Cluster,
+			"snapshot": snapshot.Name,
+		})
+		var err error
+		if client, ok := f.elasticClients[snapshot.Cluster]; ok {
+			err = client.DeleteSnapshot(snapshot.Name)
+		} else {
+			err = os.RemoveAll(snapshot.Path)
+		}
+		if err != nil {
+			logger.WithError(err).Warn("Could not delete elasticsearch snapshot from backup")
+		} else {
+			logger.Info("Removed elasticsearch snapshot from backup")
+		}
+	}
+}
+
+// RestoreElasticFromFile restores the indices of the given elasticsearch
+// clusters from a compressed backup file, replacing the indices that exist.
+// The applications of the backup are not restored.
+func (f *Facade) RestoreElasticFromFile(ctx datastore.Context, filename string, clusters []string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestoreElasticFromFile"))
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("restore elasticsearch")
+	defer dfslocker.Unlock()
+
+	info, err := dfs.ExtractBackupInfo(filename)
+	if err != nil {
+		return err
+	}
+
+	snapshots := []dfs.ElasticSnapshot{}
+	dirpaths := make(map[string]string)
+	for _, cluster := range clusters {
+		client, ok := f.elasticClients[cluster]
+		if !ok {
+			plog.WithField("cluster", cluster).Debug("No snapshot client for elasticsearch cluster")
+			return ErrElasticNotConfigured
+		}
+		found := false
+		for _, snapshot := range info.ElasticSnapshots {
+			if snapshot.Cluster == cluster {
+				dirpath := client.SnapshotPath(snapshot.Name)
+				if err := os.RemoveAll(dirpath); err != nil {
+					return err
+				}
+				snapshots = append(snapshots, snapshot)
+				dirpaths[cluster] = dirpath
+				found = true
+				break
+			}
+		}
+		if !found {
+			plog.WithField("cluster", cluster).Debug("Backup does not include elasticsearch snapshot")
+			return dfs.ErrElasticSnapshotNotFound
+		}
+	}
+
+	fh, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	zr, _, err := newBackupDecompressor(fh, info)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	if err := dfs.ExtractElasticSnapshots(zr, dirpaths); err != nil {
+		return err
+	}
+
+	for _, snapshot := range snapshots {
+		logger := plog.WithFields(logrus.Fields{
+			"cluster":  snapshot.Cluster,
+			"snapshot": snapshot.Name,
+			"indices":  snapshot.Indices,
+		})
+		client := f.elasticClients[snapshot.Cluster]
+		if err := client.RestoreSnapshot(snapshot.Name, snapshot.Indices); err != nil {
+			logger.WithError(err).Debug("Could not restore elasticsearch snapshot")
+			return err
+		}
+		logger.Info("Restored elasticsearch snapshot")
+		if err := client.DeleteSnapshot(snapshot.Name); err != nil {
+			logger.WithError(err).Warn("Could not delete restored elasticsearch snapshot")
+		}
+		if snapshot.Cluster == ElasticServiced {
+			// the caches were loaded from the indices that were replaced
+			if err := f.UpdateServiceCache(ctx); err != nil {
+				logger.WithError(err).Warn("Could not reload the service cache")
+			}
+			f.poolCache.SetDirty()
+		}
+	}
+	return nil
+}
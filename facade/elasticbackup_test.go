@@ -0,0 +1,50 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&ElasticBackupTest{})
+
+type ElasticBackupTest struct{}
+
+func (t *ElasticBackupTest) TestSelectLogstashIndices(c *C) {
+	now := time.Date(2017, 6, 10, 12, 0, 0, 0, time.UTC)
+	sizes := map[string]int64{
+		"logstash-2017.06.10": 100,
+		"logstash-2017.06.09": 200,
+		"logstash-2017.06.08": 300,
+		"logstash-2017.06.01": 50,
+		"logstash-current":    10,
+		"kibana-2017.06.10":   10,
+	}
+
+	// only the indices of the last days
+	indices := selectLogstashIndices(sizes, 3, 0, now)
+	c.Assert(indices, DeepEquals, []string{"logstash-2017.06.10", "logstash-2017.06.09", "logstash-2017.06.08"})
+
+	// the newest indices that fit in the size limit
+	indices = selectLogstashIndices(sizes, 30, 350, now)
+	c.Assert(indices, DeepEquals, []string{"logstash-2017.06.10", "logstash-2017.06.09"})
+
+	// application logs are left out of backups
+	indices = selectLogstashIndices(sizes, 0, 0, now)
+	c.Assert(indices, HasLen, 0)
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// GetServiceEndpointGraph returns the dependency graph of the services of the
+// application that the service belongs to, through the endpoints that they
+// import and export.  Imports that no service exports are included as
+// unresolved.
+func (f *Facade) GetServiceEndpointGraph(ctx datastore.Context, serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceEndpointGraph"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get tenant id of service")
+		return nil, err
+	}
+	svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
+	if err != nil {
+		logger.WithError(err).Debug("Could not get services of tenant")
+		return nil, err
+	}
+	return buildEndpointGraph(tenantID, svcs), nil
+}
+
+// buildEndpointGraph resolves the imports of the services against their
+// exports.  Imports match exports as they do in the containers, by the
+// application pattern of the import.
+func buildEndpointGraph(tenantID string, svcs []service.Service) *applicationendpoint.EndpointGraph {
+	sort.Slice(svcs, func(i, j int) bool {
+		if svcs[i].Name != svcs[j].Name {
+			return svcs[i].Name < svcs[j].Name
+		}
+		return svcs[i].ID < svcs[j].ID
+	})
+
+	graph := &applicationendpoint.EndpointGraph{
+		TenantID: tenantID,
+		Services: []applicationendpoint.EndpointGraphService{},
+		Edges:    []applicationendpoint.EndpointGraphEdge{},
+	}
+	exporters := make(map[string][]string) // application -> service ids
+	applications := []string{}
+	for _, svc := range svcs {
+		node := applicationendpoint.EndpointGraphService{ServiceID: svc.ID, Name: svc.Name}
+		hasEndpoints := false
+		for _, ep := range svc.Endpoints {
+			if ep.Purpose == "export" {
+				node.Exports = append(node.Exports, ep.Application)
+				if _, ok := exporters[ep.Application]; !ok {
+					applications = append(applications, ep.Application)
+				}
+				exporters[ep.Application] = append(exporters[ep.Application], svc.ID)
+			}
+			hasEndpoints = true
+		}
+		if hasEndpoints {
+			graph.Services = append(graph.Services, node)
+		}
+	}
+	sort.Strings(applications)
+
+	for _, svc := range svcs {
+		for _, ep := range svc.Endpoints {
+			if !strings.HasPrefix(ep.Purpose, "import") {
+				continue
+			}
+			edge := applicationendpoint.EndpointGraphEdge{
+				ServiceID:   svc.ID,
+				Application: ep.Application,
+				Purpose:     ep.Purpose,
+			}
+			rgx, err := regexp.Compile(fmt.Sprintf("^%s$", ep.Application))
+			if err != nil {
+				edge.Error = fmt.Sprintf("could not compile the application pattern: %s", err)
+				graph.Edges = append(graph.Edges, edge)
+				continue
+			}
+			resolved := false
+			for _, app := range applications {
+				if !rgx.MatchString(app) {
+					continue
+				}
+				for _, exportServiceID := range exporters[app] {
+					edge.ExportServiceID, edge.ExportApplication = exportServiceID, app
+					graph.Edges = append(graph.Edges, edge)
+					resolved = true
+				}
+			}
+			if !resolved {
+				edge.Error = "no service exports a matching application"
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+	return graph
+}
@@ -0,0 +1,66 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&EndpointGraphTest{})
+
+type EndpointGraphTest struct{}
+
+func (t *EndpointGraphTest) Test_BuildEndpointGraph(c *C) {
+	svcs := []service.Service{
+		{
+			ID:   "zope",
+			Name: "Zope",
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "zope", Purpose: "export"},
+				{Application: "zodb_.*", Purpose: "import_all"},
+				{Application: "redis", Purpose: "import"},
+				{Application: "bad[", Purpose: "import"},
+			},
+		}, {
+			ID:   "mariadb",
+			Name: "MariaDB",
+			Endpoints: []service.ServiceEndpoint{
+				{Application: "zodb_mariadb", Purpose: "export"},
+			},
+		}, {
+			ID:   "tenant",
+			Name: "Zenoss",
+		},
+	}
+
+	graph := buildEndpointGraph("tenant", svcs)
+	c.Assert(graph.TenantID, Equals, "tenant")
+	c.Assert(graph.Services, DeepEquals, []applicationendpoint.EndpointGraphService{
+		{ServiceID: "mariadb", Name: "MariaDB", Exports: []string{"zodb_mariadb"}},
+		{ServiceID: "zope", Name: "Zope", Exports: []string{"zope"}},
+	})
+	c.Assert(graph.Edges, HasLen, 3)
+	c.Check(graph.Edges[:2], DeepEquals, []applicationendpoint.EndpointGraphEdge{
+		{ServiceID: "zope", Application: "zodb_.*", Purpose: "import_all", ExportServiceID: "mariadb", ExportApplication: "zodb_mariadb"},
+		{ServiceID: "zope", Application: "redis", Purpose: "import", Error: "no service exports a matching application"},
+	})
+	c.Check(graph.Edges[2].Application, Equals, "bad[")
+	c.Check(graph.Edges[2].Error, Matches, "could not compile the application pattern: .*")
+	c.Assert(graph.Unresolved(), HasLen, 2)
+}
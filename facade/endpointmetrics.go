@@ -0,0 +1,102 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/metrics"
+)
+
+// GetEndpointMetrics returns the connection statistics of the imports and
+// exports of a service over the window.  The import proxies of the instances
+// report the connections that they make, so the statistics of an export are
+// those of the imports of its application.
+func (f *Facade) GetEndpointMetrics(ctx datastore.Context, serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetEndpointMetrics"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	svc, err := f.GetService(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get service")
+		return nil, err
+	}
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get tenant id of service")
+		return nil, err
+	}
+	stats, err := f.metricsClient.GetEndpointMetrics(time.Now().Add(-window), tenantID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get endpoint metrics")
+		return nil, err
+	}
+
+	result := []applicationendpoint.EndpointMetrics{}
+	seen := make(map[string]bool)
+	for _, ep := range svc.Endpoints {
+		key := ep.Purpose + " " + ep.Application
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		m := applicationendpoint.EndpointMetrics{
+			ServiceID:   svc.ID,
+			Application: ep.Application,
+			Purpose:     ep.Purpose,
+		}
+		if strings.HasPrefix(ep.Purpose, "import") {
+			rgx, err := regexp.Compile(fmt.Sprintf("^%s$", ep.Application))
+			if err != nil {
+				logger.WithField("application", ep.Application).WithError(err).Debug("Could not compile the application pattern of the import")
+				continue
+			}
+			for _, s := range stats {
+				if s.ServiceID == svc.ID && rgx.MatchString(s.Application) {
+					addEndpointMetrics(&m, s, false)
+				}
+			}
+		} else {
+			for _, s := range stats {
+				if s.Application == ep.Application {
+					addEndpointMetrics(&m, s, true)
+				}
+			}
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// addEndpointMetrics adds the statistics of an import proxy to the metrics
+// of an endpoint.  The bytes are swapped for an export, which receives what
+// the importer sends.
+func addEndpointMetrics(m *applicationendpoint.EndpointMetrics, s metrics.EndpointMetrics, export bool) {
+	m.Connections += s.Connections
+	m.Active += s.Active
+	m.ConnectErrors += s.ConnectErrors
+	if export {
+		m.BytesSent += s.BytesReceived
+		m.BytesReceived += s.BytesSent
+	} else {
+		m.BytesSent += s.BytesSent
+		m.BytesReceived += s.BytesReceived
+	}
+}
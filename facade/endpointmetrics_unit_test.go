@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/metrics"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_GetEndpointMetrics(c *C) {
+	svc := service.Service{
+		ID:   "zope",
+		Name: "Zope",
+		Endpoints: []service.ServiceEndpoint{
+			{Application: "zope", Purpose: "export"},
+			{Application: "zodb_.*", Purpose: "import_all"},
+		},
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "zope").
+		Return(&service.ServiceDetails{ID: "zope", ParentServiceID: "tenant"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "tenant").
+		Return(&service.ServiceDetails{ID: "tenant"}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "zope").Return(&svc, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, "tenant", "/tenant/zope").Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.metricsClient.On("GetEndpointMetrics", mock.AnythingOfType("time.Time"), "tenant").Return([]metrics.EndpointMetrics{
+		{ServiceID: "zope", Application: "zodb_mariadb", Connections: 3, ConnectErrors: 1, BytesSent: 10, BytesReceived: 100},
+		{ServiceID: "zope", Application: "zodb_session", Connections: 2, Active: 1, BytesSent: 5, BytesReceived: 50},
+		{ServiceID: "zenhub", Application: "zope", Connections: 4, Active: 2, BytesSent: 40, BytesReceived: 400},
+		{ServiceID: "zenhub", Application: "zodb_mariadb", Connections: 7},
+	}, nil)
+
+	stats, err := ft.Facade.GetEndpointMetrics(ft.ctx, "zope", time.Hour)
+	c.Assert(err, IsNil)
+	c.Assert(stats, DeepEquals, []applicationendpoint.EndpointMetrics{
+		{
+			ServiceID:     "zope",
+			Application:   "zope",
+			Purpose:       "export",
+			Connections:   4,
+			Active:        2,
+			BytesSent:     400,
+			BytesReceived: 40,
+		}, {
+			ServiceID:     "zope",
+			Application:   "zodb_.*",
+			Purpose:       "import_all",
+			Connections:   5,
+			Active:        1,
+			ConnectErrors: 1,
+			BytesSent:     15,
+			BytesReceived: 150,
+		},
+	})
+	c.Assert(stats[1].ConnectErrorRate(), Equals, 1.0/6)
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"github.com/control-center/serviced/apierror"
+)
+
+// The codes of the facade errors, which tell the clients of the master api
+// whether to retry an operation.
+func init() {
+	apierror.Register(apierror.NotFound,
+		ErrBackupOperationNotFound,
+		ErrCalendarNotFound,
+		ErrHostDoesNotExist,
+		ErrPoolNotExists,
+		ErrIPNotExists,
+		ErrSecretNotFound,
+		ErrServiceDoesNotExist,
+		ErrNoDeployment,
+		ErrBootstrapNoSnapshot,
+		ErrFeatureNotFound,
+		ErrSettingNotFound,
+		ErrDelegateProfileNotFound,
+		ErrDelegateOptionNotFound,
+		ErrCertificateNotFound,
+		ErrSchemaNotFound,
+		ErrOperationNotFound,
+		ErrBackupScheduleNotFound,
+	)
+	apierror.Register(apierror.Conflict,
+		ErrBootstrapNotEmpty,
+		ErrCalendarExists,
+		ErrPendingDeploymentConflict,
+		ErrPoolExists,
+		ErrDelegateProfileExists,
+		ErrDelegateProfileInUse,
+		ErrIPExists,
+		ErrDefaultPool,
+		ErrServiceExists,
+		ErrServiceCollision,
+		ErrEmergencyShutdownNoOp,
+		ErrCanaryNotRunning,
+		ErrOperationEnded,
+		ErrBackupScheduleExists,
+	)
+	apierror.Register(apierror.Validation,
+		ErrInvalidCanaryFraction,
+		ErrCanaryNoImage,
+		ErrRestoreNoDeploymentID,
+		ErrRestoreNameTenants,
+		ErrTenantDoesNotMatch,
+		ErrServiceMissingAssignment,
+		ErrServiceDuplicateEndpoint,
+		ErrInvalidSettingScope,
+		ErrMaintenanceNotTenant,
+	)
+	apierror.Register(apierror.Transient,
+		ErrHostOffline,
+		ErrLogsUnavailable,
+		ErrContainerFilesUnavailable,
+	)
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/utils"
+)
+
+// ErrEventsUnavailable is returned when the master does not record events
+var ErrEventsUnavailable = errors.New("facade: events are not available")
+
+// recordEvent saves an event to the event store.  The event has already
+// happened, so failures are only logged.
+func (f *Facade) recordEvent(ctx datastore.Context, e event.Event) {
+	if f.eventStore == nil {
+		return
+	}
+	logger := plog.WithField("type", e.Type).WithField("entityid", e.EntityID)
+
+	id, err := utils.NewUUID36()
+	if err != nil {
+		logger.WithError(err).Warn("Could not generate an id for the event")
+		return
+	}
+	e.ID = id
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if err := f.eventStore.Put(ctx, event.Key(e.ID), &e); err != nil {
+		logger.WithError(err).Warn("Could not store the event")
+	}
+}
+
+// QueryEvents returns a page of the recorded events that match the query,
+// oldest first.
+func (f *Facade) QueryEvents(ctx datastore.Context, q event.Query) (*event.Page, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.QueryEvents"))
+	if f.eventStore == nil {
+		return nil, ErrEventsUnavailable
+	}
+	page, err := f.eventStore.Query(ctx, q)
+	if err != nil {
+		plog.WithError(err).WithField("query", q).Debug("Could not look up events")
+		return nil, err
+	}
+	return page, nil
+}
+
+// PurgeEvents deletes the events that are older than EventMaxDays, and the
+// oldest events beyond the most recent EventMaxCount.
+func (f *Facade) PurgeEvents(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PurgeEvents"))
+	if f.eventStore == nil {
+		return nil
+	}
+
+	options := config.GetOptions()
+	var before time.Time
+	if options.EventMaxDays > 0 {
+		before = time.Now().AddDate(0, 0, -options.EventMaxDays)
+	}
+	logger := plog.WithField("eventmaxdays", options.EventMaxDays).WithField("eventmaxcount", options.EventMaxCount)
+
+	deleted, err := f.eventStore.Prune(ctx, before, options.EventMaxCount)
+	if err != nil {
+		logger.WithError(err).Debug("Could not purge events")
+		return err
+	}
+	if deleted > 0 {
+		logger.WithField("deleted", deleted).Info("Purged events beyond their retention")
+	}
+	return nil
+}
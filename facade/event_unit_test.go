@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	. "gopkg.in/check.v1"
+)
+
+// testEventStore keeps the events that are put in memory and records the
+// retention of the last prune
+type testEventStore struct {
+	events []event.Event
+	before time.Time
+	max    int
+}
+
+func (t *testEventStore) Put(ctx datastore.Context, key datastore.Key, entity datastore.ValidEntity) error {
+	t.events = append(t.events, *entity.(*event.Event))
+	return nil
+}
+
+func (t *testEventStore) Get(ctx datastore.Context, key datastore.Key, entity datastore.ValidEntity) error {
+	return datastore.ErrNoSuchEntity{Key: key}
+}
+
+func (t *testEventStore) Delete(ctx datastore.Context, key datastore.Key) error {
+	return nil
+}
+
+func (t *testEventStore) Query(ctx datastore.Context, q event.Query) (*event.Page, error) {
+	return &event.Page{Events: t.events}, nil
+}
+
+func (t *testEventStore) Prune(ctx datastore.Context, before time.Time, max int) (int, error) {
+	t.before, t.max = before, max
+	return 0, nil
+}
+
+func (ft *FacadeUnitTest) Test_RecordServiceStateEvents(c *C) {
+	store := &testEventStore{}
+	ft.Facade.SetEventStore(store)
+	defer ft.Facade.SetEventStore(nil)
+
+	serviceID := "svc-events"
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("UpdateCurrentState", ft.ctx, serviceID, string(service.SVCCSRunning)).Return(nil)
+	ft.serviceStore.On("UpdateCurrentState", ft.ctx, serviceID, string(service.SVCCSStopped)).Return(nil)
+
+	ft.Facade.SetServicesCurrentState(ft.ctx, service.SVCCSRunning, serviceID)
+	ft.Facade.SetServicesCurrentState(ft.ctx, service.SVCCSRunning, serviceID)
+	ft.Facade.SetServicesCurrentState(ft.ctx, service.SVCCSStopped, serviceID)
+
+	page, err := ft.Facade.QueryEvents(ft.ctx, event.Query{})
+	c.Assert(err, IsNil)
+	c.Assert(page.Events, HasLen, 2)
+	for i, state := range []service.ServiceCurrentState{service.SVCCSRunning, service.SVCCSStopped} {
+		e := page.Events[i]
+		c.Assert(e.ID, Not(Equals), "")
+		c.Assert(e.Type, Equals, event.TypeServiceState)
+		c.Assert(e.EntityID, Equals, serviceID)
+		c.Assert(e.TenantID, Equals, serviceID)
+		c.Assert(e.Fields["state"], Equals, string(state))
+		c.Assert(e.ValidEntity(), IsNil)
+	}
+}
+
+func (ft *FacadeUnitTest) Test_QueryEvents_Unavailable(c *C) {
+	_, err := ft.Facade.QueryEvents(ft.ctx, event.Query{})
+	c.Assert(err, Equals, facade.ErrEventsUnavailable)
+	c.Assert(ft.Facade.PurgeEvents(ft.ctx), IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_PurgeEvents(c *C) {
+	store := &testEventStore{}
+	ft.Facade.SetEventStore(store)
+	defer ft.Facade.SetEventStore(nil)
+
+	options := config.GetOptions()
+	defer config.LoadOptions(options)
+	testOptions := options
+	testOptions.EventMaxDays = 7
+	testOptions.EventMaxCount = 1000
+	config.LoadOptions(testOptions)
+
+	before := time.Now()
+	c.Assert(ft.Facade.PurgeEvents(ft.ctx), IsNil)
+	after := time.Now()
+	c.Assert(store.before.Before(before.AddDate(0, 0, -7)), Equals, false)
+	c.Assert(store.before.After(after.AddDate(0, 0, -7)), Equals, false)
+	c.Assert(store.max, Equals, 1000)
+
+	testOptions.EventMaxDays = 0
+	config.LoadOptions(testOptions)
+	c.Assert(ft.Facade.PurgeEvents(ft.ctx), IsNil)
+	c.Assert(store.before.IsZero(), Equals, true)
+}
@@ -19,24 +19,78 @@ import (
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
+	"github.com/control-center/serviced/domain/logfilter"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
-	"github.com/control-center/serviced/domain/logfilter"
 )
 
 type MetricsClient interface {
 	GetInstanceMemoryStats(time.Time, ...metrics.ServiceInstance) ([]metrics.MemoryUsageStats, error)
 	GetAvailableStorage(time.Duration, string, ...string) (*metrics.StorageMetrics, error)
+	GetEndpointMetrics(time.Time, string) ([]metrics.EndpointMetrics, error)
+}
+
+type LogsClient interface {
+	SearchServiceLogs(service.LogQuery) ([]service.LogMessage, error)
+}
+
+type RetentionClient interface {
+	PurgePoolLogs(poolID string, before time.Time) error
+	PurgeServiceMetrics(serviceIDs []string, before time.Time) error
+}
+
+type LogstashReloader interface {
+	ReloadLogstash() error
+	RestartLogstash() error
+}
+
+type ElasticSnapshotClient interface {
+	SnapshotPath(name string) string
+	IndexSizes(pattern string) (map[string]int64, error)
+	CreateSnapshot(name string, indices []string) error
+	RestoreSnapshot(name string, indices []string) error
+	DeleteSnapshot(name string) error
+}
+
+// ContainerFilesClient reads and writes the files inside the container of a
+// running service instance through the agent at the given address.
+type ContainerFilesClient interface {
+	// HashContainerFiles returns the sha256 sum of each file that exists in
+	// the container, keyed by filename.
+	HashContainerFiles(address, containerID string, filenames []string) (map[string]string, error)
+	WriteContainerFiles(address, containerID string, files []servicedefinition.ConfigFile) error
+}
+
+// ProfileClient collects pprof profiles from the agent at the given address
+type ProfileClient interface {
+	CollectProfiles(address string, profiles []string, duration time.Duration) (map[string][]byte, error)
+}
+
+// DelegateProfileClient applies a version of a delegate profile on the agent
+// at the given address, and returns the options that take effect when the
+// delegate restarts.
+type DelegateProfileClient interface {
+	ApplyDelegateProfile(address, profile string, version int, options map[string]string) ([]string, error)
 }
 
 // instantiate the package logger
@@ -58,10 +112,24 @@ func New() *Facade {
 		templateStore:  servicetemplate.NewStore(),
 		logFilterStore: logfilter.NewStore(),
 		userStore:      user.NewStore(),
+		calendarStore:  calendar.NewStore(),
+		scheduleStore:  backupschedule.NewStore(),
+		featureStore:   feature.NewStore(),
+		pinStore:       pin.NewStore(),
+		secretStore:    secret.NewStore(),
+		certStore:      certificate.NewStore(),
+		settingStore:   setting.NewStore(),
+		profileStore:   delegateprofile.NewStore(),
+		auditStore:     audit.NewStore(),
 		serviceCache:   NewServiceCache(),
 		poolCache:      NewPoolCache(),
 		hostRegistry:   auth.NewHostExpirationRegistry(),
 		deployments:    NewPendingDeploymentMgr(),
+		serviceEvents:  newServiceEventBus(),
+		idempotency:    newIdempotencyCache(),
+		backupProgress: newBackupProgress(),
+		dfsOperations:  newDFSOperationQueue(),
+		operations:     newOperationRegistry(),
 		zzk:            getZZK(),
 	}
 }
@@ -77,18 +145,43 @@ type Facade struct {
 	serviceStore   service.Store
 	configStore    serviceconfigfile.Store
 	userStore      user.Store
+	calendarStore  calendar.Store
+	scheduleStore  backupschedule.Store
+	featureStore   feature.Store
+	pinStore       pin.Store
+	secretStore    secret.Store
+	certStore      certificate.Store
+	settingStore   setting.Store
+	profileStore   delegateprofile.Store
+	auditStore     audit.Store
+	eventStore     event.Store
 
-	auditLogger   audit.Logger
-	zzk           ZZK
-	dfs           dfs.DFS
-	hcache        *health.HealthStatusCache
-	metricsClient MetricsClient
-	serviceCache  *serviceCache
-	poolCache     *poolCache
-	hostRegistry  auth.HostExpirationRegistryInterface
-	deployments   *PendingDeploymentMgr
-	ssm           servicestatemanager.ServiceStateManager
-	isvcsPath     string
+	auditLogger     audit.Logger
+	zzk             ZZK
+	dfs             dfs.DFS
+	hcache          *health.HealthStatusCache
+	metricsClient   MetricsClient
+	logsClient      LogsClient
+	retentionClient RetentionClient
+	logstash        LogstashReloader
+	elasticClients  map[string]ElasticSnapshotClient
+	filesClient     ContainerFilesClient
+	profileClient   ProfileClient
+	delegateClient  DelegateProfileClient
+	serviceCache    *serviceCache
+	poolCache       *poolCache
+	hostRegistry    auth.HostExpirationRegistryInterface
+	deployments     *PendingDeploymentMgr
+	ssm             servicestatemanager.ServiceStateManager
+	isvcsPath       string
+	quotaLevels     quotaLevels
+	serviceEvents   *serviceEventBus
+	idempotency     *idempotencyCache
+	backupProgress  *backupProgress
+	dfsOperations   *dfsOperationQueue
+	operations      *operationRegistry
+
+	admissionWebhooks []AdmissionWebhook
 
 	rollingRestartTimeout time.Duration
 }
@@ -124,6 +217,26 @@ func (f *Facade) SetConfigStore(store serviceconfigfile.Store) { f.configStore =
 
 func (f *Facade) SetUserStore(store user.Store) { f.userStore = store }
 
+func (f *Facade) SetCalendarStore(store calendar.Store) { f.calendarStore = store }
+
+func (f *Facade) SetBackupScheduleStore(store backupschedule.Store) { f.scheduleStore = store }
+
+func (f *Facade) SetFeatureStore(store feature.Store) { f.featureStore = store }
+
+func (f *Facade) SetPinStore(store pin.Store) { f.pinStore = store }
+
+func (f *Facade) SetSecretStore(store secret.Store) { f.secretStore = store }
+
+func (f *Facade) SetCertificateStore(store certificate.Store) { f.certStore = store }
+
+func (f *Facade) SetSettingStore(store setting.Store) { f.settingStore = store }
+
+func (f *Facade) SetDelegateProfileStore(store delegateprofile.Store) { f.profileStore = store }
+
+func (f *Facade) SetAuditStore(store audit.Store) { f.auditStore = store }
+
+func (f *Facade) SetEventStore(store event.Store) { f.eventStore = store }
+
 func (f *Facade) SetTemplateStore(store servicetemplate.Store) { f.templateStore = store }
 
 func (f *Facade) SetLogFilterStore(store logfilter.Store) { f.logFilterStore = store }
@@ -132,6 +245,25 @@ func (f *Facade) SetHealthCache(hcache *health.HealthStatusCache) { f.hcache = h
 
 func (f *Facade) SetMetricsClient(client MetricsClient) { f.metricsClient = client }
 
+func (f *Facade) SetLogsClient(client LogsClient) { f.logsClient = client }
+
+func (f *Facade) SetRetentionClient(client RetentionClient) { f.retentionClient = client }
+
+func (f *Facade) SetLogstashReloader(reloader LogstashReloader) { f.logstash = reloader }
+
+func (f *Facade) SetContainerFilesClient(client ContainerFilesClient) { f.filesClient = client }
+
+func (f *Facade) SetProfileClient(client ProfileClient) { f.profileClient = client }
+
+func (f *Facade) SetDelegateProfileClient(client DelegateProfileClient) { f.delegateClient = client }
+
+func (f *Facade) SetElasticSnapshotClient(cluster string, client ElasticSnapshotClient) {
+	if f.elasticClients == nil {
+		f.elasticClients = make(map[string]ElasticSnapshotClient)
+	}
+	f.elasticClients[cluster] = client
+}
+
 func (f *Facade) SetIsvcsPath(path string) { f.isvcsPath = path }
 
 func (f *Facade) SetHostExpirationRegistry(hostRegistry auth.HostExpirationRegistryInterface) {
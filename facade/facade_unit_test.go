@@ -23,8 +23,10 @@ import (
 	authmocks "github.com/control-center/serviced/auth/mocks"
 	datastoremocks "github.com/control-center/serviced/datastore/mocks"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
+	profilemocks "github.com/control-center/serviced/domain/delegateprofile/mocks"
 	hostmocks "github.com/control-center/serviced/domain/host/mocks"
 	keymocks "github.com/control-center/serviced/domain/hostkey/mocks"
+	pinmocks "github.com/control-center/serviced/domain/pin/mocks"
 	poolmocks "github.com/control-center/serviced/domain/pool/mocks"
 	registrymocks "github.com/control-center/serviced/domain/registry/mocks"
 	servicemocks "github.com/control-center/serviced/domain/service/mocks"
@@ -53,6 +55,8 @@ type FacadeUnitTest struct {
 	configStore      *configmocks.Store
 	templateStore    *templatemocks.Store
 	logFilterStore   *logfiltermocks.Store
+	pinStore         *pinmocks.Store
+	profileStore     *profilemocks.Store
 	metricsClient    *zzkmocks.MetricsClient
 	hostauthregistry *authmocks.HostExpirationRegistryInterface
 }
@@ -79,6 +83,7 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	mockLogger.On("Entity", mock.AnythingOfType("*host.Host")).Return(mockLogger)
 	mockLogger.On("WithField", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(mockLogger)
 	mockLogger.On("WithFields", mock.AnythingOfType("logrus.Fields")).Return(mockLogger)
+	mockLogger.On("Delta", mock.Anything, mock.Anything).Return(mockLogger)
 	mockLogger.On("Error", mock.Anything)
 	mockLogger.On("Succeeded", mock.Anything)
 	mockLogger.On("SucceededIf", mock.AnythingOfType("bool"))
@@ -112,6 +117,12 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.logFilterStore = &logfiltermocks.Store{}
 	ft.Facade.SetLogFilterStore(ft.logFilterStore)
 
+	ft.pinStore = &pinmocks.Store{}
+	ft.Facade.SetPinStore(ft.pinStore)
+
+	ft.profileStore = &profilemocks.Store{}
+	ft.Facade.SetDelegateProfileStore(ft.profileStore)
+
 	ft.zzk = &zzkmocks.ZZK{}
 	ft.Facade.SetZZK(ft.zzk)
 
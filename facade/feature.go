@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/feature"
+)
+
+// ErrFeatureNotFound is returned when a feature is not known
+var ErrFeatureNotFound = errors.New("facade: feature not found")
+
+// GetFeatureFlags returns the state of every known feature.  Features that
+// have not been toggled have their default state.
+func (f *Facade) GetFeatureFlags(ctx datastore.Context) ([]feature.Flag, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetFeatureFlags"))
+	stored, err := f.featureStore.GetFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flagMap := make(map[string]feature.Flag)
+	for _, flag := range stored {
+		flagMap[flag.ID] = flag
+	}
+
+	flags := []feature.Flag{}
+	for _, name := range feature.Names() {
+		flag, ok := flagMap[name]
+		if !ok {
+			def, _ := feature.Lookup(name)
+			flag = feature.Flag{ID: name, Global: def.Default}
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// EnableFeature enables a feature in a resource pool, in a deployment, or
+// everywhere if neither is set.
+func (f *Facade) EnableFeature(ctx datastore.Context, name, poolID, deploymentID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EnableFeature"))
+	return f.toggleFeature(ctx, "Enabling Feature", name, func(flag *feature.Flag) {
+		flag.Enable(poolID, deploymentID)
+	})
+}
+
+// DisableFeature disables a feature in a resource pool, in a deployment, or
+// everywhere if neither is set.
+func (f *Facade) DisableFeature(ctx datastore.Context, name, poolID, deploymentID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DisableFeature"))
+	return f.toggleFeature(ctx, "Disabling Feature", name, func(flag *feature.Flag) {
+		flag.Disable(poolID, deploymentID)
+	})
+}
+
+// toggleFeature applies a change to the flag of a feature.  A feature that
+// has not been toggled starts from its default state.
+func (f *Facade) toggleFeature(ctx datastore.Context, message, name string, toggle func(*feature.Flag)) error {
+	alog := f.auditLogger.Message(ctx, message).Action(audit.Update).ID(name).Type(feature.GetType())
+
+	flag, err := f.getFeatureFlag(ctx, name)
+	if err != nil {
+		return alog.Error(err)
+	}
+	before := *flag
+	toggle(flag)
+	flag.UpdatedAt = time.Now()
+	alog = alog.Delta(&before, flag)
+	return alog.Error(f.featureStore.Put(ctx, feature.Key(name), flag))
+}
+
+// getFeatureFlag returns the stored flag of a feature, or a flag with the
+// default state of the feature if it has not been toggled.
+func (f *Facade) getFeatureFlag(ctx datastore.Context, name string) (*feature.Flag, error) {
+	def, ok := feature.Lookup(name)
+	if !ok {
+		return nil, ErrFeatureNotFound
+	}
+	var flag feature.Flag
+	if err := f.featureStore.Get(ctx, feature.Key(name), &flag); datastore.IsErrNoSuchEntity(err) {
+		return &feature.Flag{ID: name, Global: def.Default}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// FeatureEnabled returns true if a feature is enabled in the given resource
+// pool or deployment.  Either may be empty.
+func (f *Facade) FeatureEnabled(ctx datastore.Context, name, poolID, deploymentID string) (bool, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.FeatureEnabled"))
+	flag, err := f.getFeatureFlag(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return flag.EnabledFor(poolID, deploymentID), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/feature"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) TestFeature_Toggle(c *C) {
+	// sticky rescheduling is enabled until it is toggled
+	enabled, err := ft.Facade.FeatureEnabled(ft.CTX, feature.StickyRescheduling, "pool", "")
+	c.Assert(err, IsNil)
+	c.Assert(enabled, Equals, true)
+
+	err = ft.Facade.DisableFeature(ft.CTX, feature.StickyRescheduling, "", "")
+	c.Assert(err, IsNil)
+	err = ft.Facade.EnableFeature(ft.CTX, feature.StickyRescheduling, "pool", "")
+	c.Assert(err, IsNil)
+
+	enabled, err = ft.Facade.FeatureEnabled(ft.CTX, feature.StickyRescheduling, "pool", "")
+	c.Assert(err, IsNil)
+	c.Assert(enabled, Equals, true)
+	enabled, err = ft.Facade.FeatureEnabled(ft.CTX, feature.StickyRescheduling, "other", "")
+	c.Assert(err, IsNil)
+	c.Assert(enabled, Equals, false)
+
+	flags, err := ft.Facade.GetFeatureFlags(ft.CTX)
+	c.Assert(err, IsNil)
+	c.Assert(flags, HasLen, len(feature.Definitions))
+	c.Assert(flags[0].Pools, DeepEquals, []string{"pool"})
+
+	_, err = ft.Facade.FeatureEnabled(ft.CTX, "no-such-feature", "", "")
+	c.Assert(err, Equals, ErrFeatureNotFound)
+	err = ft.Facade.EnableFeature(ft.CTX, "no-such-feature", "", "")
+	c.Assert(err, Equals, ErrFeatureNotFound)
+}
@@ -0,0 +1,215 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/pin"
+	"github.com/control-center/serviced/domain/registry"
+)
+
+// snapshotLabelRegex matches the registry tags of the images of snapshots
+var snapshotLabelRegex = regexp.MustCompile(`^\d{8}_\d{6}\.\d{3}$`)
+
+// fsckCheck is a problem found by CheckDFS and the function that repairs it,
+// if it can be repaired safely
+type fsckCheck struct {
+	problem dfs.FsckProblem
+	repair  func() error
+}
+
+// CheckDFS cross-checks the volumes of the applications, the metadata of
+// their snapshots, the registry index and the images of the services, and
+// returns the dangling or missing references that it finds along with how
+// to repair them.  If repair is true, the problems that can be repaired
+// safely are repaired; the rest are left to be repaired by hand.
+func (f *Facade) CheckDFS(ctx datastore.Context, repair bool) ([]dfs.FsckProblem, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CheckDFS"))
+	checks, err := f.checkDFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]dfs.FsckProblem, len(checks))
+	for i, check := range checks {
+		if repair && check.repair != nil {
+			logger := plog.WithFields(logrus.Fields{
+				"kind":     check.problem.Kind,
+				"tenantid": check.problem.TenantID,
+				"subject":  check.problem.Subject,
+			})
+			if err := check.repair(); err != nil {
+				logger.WithError(err).Warn("Could not repair dfs problem")
+				check.problem.Error = err.Error()
+			} else {
+				logger.Info("Repaired dfs problem")
+				check.problem.Repaired = true
+			}
+		}
+		problems[i] = check.problem
+	}
+	return problems, nil
+}
+
+// checkDFS finds the problems of the dfs while holding the dfs lock
+func (f *Facade) checkDFS(ctx datastore.Context) ([]fsckCheck, error) {
+	if err := f.DFSLock(ctx).LockWithTimeout("check dfs", userLockTimeout); err != nil {
+		plog.WithError(err).Debug("Cannot check dfs")
+		return nil, err
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	checks := []fsckCheck{}
+	add := func(kind, tenantID, subject, detail, repair string, fix func() error) {
+		checks = append(checks, fsckCheck{
+			problem: dfs.FsckProblem{
+				Kind:     kind,
+				TenantID: tenantID,
+				Subject:  subject,
+				Detail:   detail,
+				Repair:   repair,
+			},
+			repair: fix,
+		})
+	}
+
+	tenantIDs, err := f.GetTenantIDs(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get tenants")
+		return nil, err
+	}
+	tenants := make(map[string]bool)
+	for _, tenantID := range tenantIDs {
+		tenants[tenantID] = true
+	}
+	volumes := make(map[string]bool)
+	for _, name := range f.dfs.Volumes() {
+		volumes[name] = true
+		if !tenants[name] {
+			add(dfs.FsckOrphanedVolume, name, name, "the application of the volume does not exist", "", nil)
+		}
+	}
+
+	rImages, err := f.registryStore.GetImages(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get images from the registry index")
+		return nil, err
+	}
+	indexed := make(map[string]*registry.Image)
+	for i := range rImages {
+		indexed[rImages[i].String()] = &rImages[i]
+	}
+	pins, err := f.pinStore.GetPins(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not get pins")
+		return nil, err
+	}
+	pinned := make(map[string]bool)
+	for _, p := range pins {
+		if p.Kind == pin.KindImage {
+			pinned[p.Target] = true
+		}
+	}
+
+	// the snapshot labels of each tenant whose snapshots could be listed
+	labels := make(map[string]map[string]bool)
+	for _, tenantID := range tenantIDs {
+		if !volumes[tenantID] {
+			add(dfs.FsckMissingVolume, tenantID, tenantID, "the application does not have a volume", "", nil)
+			continue
+		}
+		snapshotIDs, err := f.dfs.List(tenantID)
+		if err != nil {
+			plog.WithField("tenantid", tenantID).WithError(err).Warn("Could not list snapshots; skipping the snapshot checks of the tenant")
+			continue
+		}
+		labels[tenantID] = make(map[string]bool)
+		for _, snapshotID := range snapshotIDs {
+			info, err := f.dfs.Info(snapshotID)
+			if err != nil {
+				add(dfs.FsckBadSnapshot, tenantID, snapshotID, fmt.Sprintf("could not read the snapshot: %s", err), "", nil)
+				continue
+			}
+			labels[tenantID][info.Label] = true
+			for _, image := range info.Images {
+				imageID, err := commons.ParseImageID(image)
+				if err != nil {
+					add(dfs.FsckMissingSnapshotImage, tenantID, snapshotID, fmt.Sprintf("could not parse image %s: %s", image, err), "", nil)
+					continue
+				}
+				key := (&registry.Image{Library: imageID.User, Repo: imageID.Repo, Tag: imageID.Tag}).String()
+				if _, ok := indexed[key]; !ok {
+					add(dfs.FsckMissingSnapshotImage, tenantID, snapshotID, fmt.Sprintf("image %s is not in the registry; the snapshot cannot be rolled back", key), "", nil)
+				}
+			}
+		}
+	}
+
+	for _, rImage := range rImages {
+		key := rImage.String()
+		if pinned[key] {
+			// pinned images are kept even when nothing refers to them
+			continue
+		}
+		remove := func() error { return f.DeleteRegistryImage(ctx, key) }
+		if !tenants[rImage.Library] {
+			add(dfs.FsckOrphanedImage, rImage.Library, key, "the application of the image does not exist", "remove the image from the registry index", remove)
+		} else if tenantLabels, ok := labels[rImage.Library]; ok && snapshotLabelRegex.MatchString(rImage.Tag) && !tenantLabels[rImage.Tag] {
+			add(dfs.FsckStaleSnapshotImage, rImage.Library, key, "the snapshot of the image does not exist", "remove the image from the registry index", remove)
+		}
+	}
+
+	for _, tenantID := range tenantIDs {
+		svcs, err := f.GetServices(ctx, dao.ServiceRequest{TenantID: tenantID})
+		if err != nil {
+			plog.WithField("tenantid", tenantID).WithError(err).Debug("Could not get services of tenant")
+			return nil, err
+		}
+		for _, svc := range svcs {
+			if svc.ImageID == "" {
+				continue
+			}
+			subject := fmt.Sprintf("%s (%s)", svc.Name, svc.ID)
+			if rImage, err := f.getServiceRegistryImage(ctx, svc.ImageID, ""); err != nil || rImage == nil {
+				add(dfs.FsckMissingServiceImage, tenantID, subject, fmt.Sprintf("image %s is not in the registry; the service cannot start", svc.ImageID), "", nil)
+				continue
+			}
+			if svc.ImageDigest == "" {
+				continue
+			}
+			if pin, err := f.getServiceRegistryImage(ctx, svc.ImageID, svc.ImageDigest); err == nil && pin != nil && pin.UUID == svc.ImageDigest {
+				continue
+			}
+			serviceID := svc.ID
+			add(dfs.FsckStaleImagePin, tenantID, subject, fmt.Sprintf("digest %s is not tagged in the registry", svc.ImageDigest), "pin the service to the current digest of its image", func() error {
+				svc, err := f.GetService(ctx, serviceID)
+				if err != nil {
+					return err
+				}
+				if err := f.pinServiceImage(ctx, svc); err != nil {
+					return err
+				}
+				return f.UpdateService(ctx, *svc)
+			})
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/pin"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupCheckDFS() {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{{ID: "fsck-tenant"}}, nil)
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{}, nil)
+	ft.dfs.On("Volumes").Return([]string{"fsck-tenant", "fsck-gone"})
+	ft.dfs.On("List", "fsck-tenant").Return([]string{"fsck-tenant_20170101_000000.000"}, nil)
+	ft.dfs.On("Info", "fsck-tenant_20170101_000000.000").Return(&dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{
+			Name:     "fsck-tenant_20170101_000000.000",
+			TenantID: "fsck-tenant",
+			Label:    "20170101_000000.000",
+		},
+		Images: []string{"localhost:5000/fsck-tenant/repo:20170101_000000.000"},
+	}, nil)
+	ft.registryStore.On("GetImages", ft.ctx).Return([]registry.Image{
+		{Library: "fsck-tenant", Repo: "repo", Tag: "latest"},
+		{Library: "fsck-tenant", Repo: "repo", Tag: "20170102_000000.000"},
+		{Library: "fsck-gone", Repo: "repo", Tag: "latest"},
+	}, nil)
+	ft.pinStore.On("GetPins", ft.ctx).Return([]pin.Pin{}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_CheckDFS(c *C) {
+	ft.setupCheckDFS()
+
+	problems, err := ft.Facade.CheckDFS(ft.ctx, false)
+	c.Assert(err, IsNil)
+	c.Assert(problems, DeepEquals, []dfs.FsckProblem{
+		{
+			Kind:     dfs.FsckOrphanedVolume,
+			TenantID: "fsck-gone",
+			Subject:  "fsck-gone",
+			Detail:   "the application of the volume does not exist",
+		}, {
+			Kind:     dfs.FsckMissingSnapshotImage,
+			TenantID: "fsck-tenant",
+			Subject:  "fsck-tenant_20170101_000000.000",
+			Detail:   "image fsck-tenant/repo:20170101_000000.000 is not in the registry; the snapshot cannot be rolled back",
+		}, {
+			Kind:     dfs.FsckStaleSnapshotImage,
+			TenantID: "fsck-tenant",
+			Subject:  "fsck-tenant/repo:20170102_000000.000",
+			Detail:   "the snapshot of the image does not exist",
+			Repair:   "remove the image from the registry index",
+		}, {
+			Kind:     dfs.FsckOrphanedImage,
+			TenantID: "fsck-gone",
+			Subject:  "fsck-gone/repo:latest",
+			Detail:   "the application of the image does not exist",
+			Repair:   "remove the image from the registry index",
+		},
+	})
+	ft.registryStore.AssertNotCalled(c, "Delete", ft.ctx, mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeUnitTest) Test_CheckDFSRepair(c *C) {
+	ft.setupCheckDFS()
+	ft.registryStore.On("Delete", ft.ctx, mock.AnythingOfType("string")).Return(nil)
+	ft.zzk.On("DeleteRegistryImage", mock.AnythingOfType("string")).Return(nil)
+	ft.pinStore.On("Get", ft.ctx, mock.Anything, mock.Anything).Return(datastore.ErrNoSuchEntity{Key: pin.Key(pin.KindImage, "")})
+
+	problems, err := ft.Facade.CheckDFS(ft.ctx, true)
+	c.Assert(err, IsNil)
+	c.Assert(problems, HasLen, 4)
+	for _, p := range problems {
+		c.Check(p.Repaired, Equals, p.Repair != "")
+	}
+	ft.registryStore.AssertCalled(c, "Delete", ft.ctx, "fsck-tenant/repo:20170102_000000.000")
+	ft.registryStore.AssertCalled(c, "Delete", ft.ctx, "fsck-gone/repo:latest")
+	ft.registryStore.AssertNumberOfCalls(c, "Delete", 2)
+}
+
+func (ft *FacadeUnitTest) Test_CheckDFSSkipsPinnedImages(c *C) {
+	ft.pinStore.On("GetPins", ft.ctx).Return([]pin.Pin{
+		*pin.New(pin.KindImage, "fsck-gone/repo:latest", "fsck-gone", "evidence"),
+	}, nil)
+	ft.setupCheckDFS()
+
+	problems, err := ft.Facade.CheckDFS(ft.ctx, false)
+	c.Assert(err, IsNil)
+	c.Assert(problems, HasLen, 3)
+	for _, p := range problems {
+		c.Check(p.Subject, Not(Equals), "fsck-gone/repo:latest")
+	}
+}
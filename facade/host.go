@@ -16,8 +16,11 @@ package facade
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/datastore"
@@ -25,7 +28,9 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
 	"github.com/zenoss/glog"
 )
 
@@ -268,6 +273,7 @@ func (f *Facade) UpdateHost(ctx datastore.Context, entity *host.Host) error {
 	} else if foundhost == nil {
 		return alog.Error(fmt.Errorf("host does not exist: %s", entity.ID))
 	}
+	alog = alog.Delta(foundhost, entity)
 
 	// validate the pool exists
 	if pool, err := f.GetResourcePool(ctx, entity.PoolID); err != nil {
@@ -452,6 +458,110 @@ func (f *Facade) HostIsAuthenticated(ctx datastore.Context, hostid string) (bool
 	return !isExpired, nil
 }
 
+// SetHostMaintenance enables or disables maintenance mode on a host.  While
+// in maintenance mode, no new instances are scheduled to the host.  Enabling
+// maintenance mode also moves the instances running on the host to other
+// hosts in the pool, one at a time, in the background.
+func (f *Facade) SetHostMaintenance(ctx datastore.Context, hostID string, enabled bool) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetHostMaintenance"))
+	alog := f.auditLogger.Message(ctx, "Setting Host Maintenance").Action(audit.Update).
+		ID(hostID).Type(host.GetType()).WithField("enabled", strconv.FormatBool(enabled))
+
+	h, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		return alog.Error(err)
+	} else if h == nil {
+		return alog.Error(ErrHostDoesNotExist)
+	}
+
+	if err := f.zzk.SetHostMaintenance(h.PoolID, h.ID, enabled); err != nil {
+		return alog.Error(err)
+	}
+
+	if enabled {
+		go f.drainHost(datastore.Get(), h.PoolID, h.ID)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// drainHost stops the instances running on a host one at a time, waiting for
+// each to start on another host before moving on to the next.  Instances of
+// the lowest priority class are moved first, so the most important services
+// keep running undisturbed for longest.
+func (f *Facade) drainHost(ctx datastore.Context, poolID, hostID string) {
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolID,
+		"hostid": hostID,
+	})
+
+	states, err := f.zzk.GetHostStates(ctx, poolID, hostID)
+	if err != nil {
+		logger.WithError(err).Warn("Could not look up instances to move off of host")
+		return
+	}
+
+	svcs := make(map[string]*service.Service)
+	for _, state := range states {
+		if _, ok := svcs[state.ServiceID]; ok {
+			continue
+		}
+		svc, err := f.serviceStore.Get(ctx, state.ServiceID)
+		if err != nil {
+			logger.WithError(err).WithField("serviceid", state.ServiceID).Warn("Could not look up service of instance on host")
+		}
+		svcs[state.ServiceID] = svc
+	}
+	sort.SliceStable(states, func(i, j int) bool {
+		return evictionRank(svcs[states[i].ServiceID]) < evictionRank(svcs[states[j].ServiceID])
+	})
+
+	for _, state := range states {
+		ilogger := logger.WithFields(log.Fields{
+			"serviceid":  state.ServiceID,
+			"instanceid": state.InstanceID,
+		})
+
+		svc := svcs[state.ServiceID]
+		if svc == nil {
+			continue
+		}
+
+		if err := f.zzk.StopServiceInstance(poolID, state.ServiceID, state.InstanceID); err != nil {
+			ilogger.WithError(err).Warn("Could not stop instance on host")
+			continue
+		}
+
+		// only running services are rescheduled
+		if service.DesiredState(svc.DesiredState) != service.SVCRun {
+			continue
+		}
+
+		cancel := make(chan struct{})
+		timer := time.AfterFunc(f.rollingRestartTimeout, func() { close(cancel) })
+		err = f.zzk.WaitInstance(ctx, svc, state.InstanceID, func(s *zkservice.State, exists bool) bool {
+			return exists && s.HostID != hostID && service.InstanceCurrentState(s.Status) == service.StateRunning
+		}, cancel)
+		if !timer.Stop() {
+			ilogger.Warn("Timed out waiting for instance to start on another host")
+		} else if err != nil {
+			ilogger.WithError(err).Warn("Could not wait for instance to start on another host")
+		} else {
+			ilogger.Info("Moved instance off of host")
+		}
+	}
+	logger.WithField("instances", len(states)).Info("Finished moving instances off of host")
+}
+
+// evictionRank returns the rank of the priority class of a service whose
+// instances are moved off of a host
+func evictionRank(svc *service.Service) int {
+	if svc == nil {
+		return 0
+	}
+	return servicedefinition.PriorityRank(svc.PriorityClass)
+}
+
 // GetHosts returns a list of all registered hosts
 func (f *Facade) GetHosts(ctx datastore.Context) ([]host.Host, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetHosts"))
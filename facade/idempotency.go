@@ -0,0 +1,93 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/apierror"
+	"github.com/control-center/serviced/datastore"
+)
+
+// IdempotencyRetention is how long the result of a call with an idempotency
+// key is kept, and so how long a client may retry the call.
+var IdempotencyRetention = 10 * time.Minute
+
+// idempotentCall is a call made with an idempotency key.  done is closed
+// once the call has returned.
+type idempotentCall struct {
+	done    chan struct{}
+	result  interface{}
+	err     error
+	expires time.Time
+}
+
+// idempotencyCache maps idempotency keys to the calls made with them
+type idempotencyCache struct {
+	calls map[string]*idempotentCall
+	mutex sync.Mutex
+}
+
+// newIdempotencyCache returns a new idempotencyCache
+func newIdempotencyCache() *idempotencyCache {
+	return &idempotencyCache{
+		calls: make(map[string]*idempotentCall),
+	}
+}
+
+// do runs fn once per key within the retention window and returns its
+// result to every caller with that key.  A call that fails with a transient
+// error is forgotten so that it runs again when it is retried.
+func (c *idempotencyCache) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	now := time.Now()
+	for k, call := range c.calls {
+		if !call.expires.IsZero() && now.After(call.expires) {
+			delete(c.calls, k)
+		}
+	}
+	if call, ok := c.calls[key]; ok {
+		c.mutex.Unlock()
+		<-call.done
+		return call.result, call.err
+	}
+	call := &idempotentCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mutex.Unlock()
+
+	call.result, call.err = fn()
+
+	c.mutex.Lock()
+	if apierror.IsTransient(call.err) {
+		delete(c.calls, key)
+	} else {
+		call.expires = time.Now().Add(IdempotencyRetention)
+	}
+	c.mutex.Unlock()
+	close(call.done)
+	return call.result, call.err
+}
+
+// Idempotent runs fn unless a call with the same idempotency key has already
+// run within the retention window, in which case it returns the result of
+// that call.  Clients send the same key when they retry a mutating call
+// that timed out, so that it does not take effect twice.  Calls without a
+// key always run.
+func (f *Facade) Idempotent(ctx datastore.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	if key == "" || f.idempotency == nil {
+		return fn()
+	}
+	return f.idempotency.do(key, fn)
+}
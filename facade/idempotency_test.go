@@ -0,0 +1,134 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/apierror"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&IdempotencyTest{})
+
+type IdempotencyTest struct{}
+
+func (t *IdempotencyTest) TestIdempotent_SameKey(c *C) {
+	f := &Facade{idempotency: newIdempotencyCache()}
+	calls := 0
+	fn := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	result, err := f.Idempotent(nil, "key", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 1)
+
+	// a retry returns the result of the first call
+	result, err = f.Idempotent(nil, "key", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 1)
+
+	// other keys and calls without a key run
+	result, err = f.Idempotent(nil, "other", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 2)
+	result, err = f.Idempotent(nil, "", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 3)
+	result, err = f.Idempotent(nil, "", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 4)
+}
+
+func (t *IdempotencyTest) TestIdempotent_Errors(c *C) {
+	f := &Facade{idempotency: newIdempotencyCache()}
+	errConflict := errors.New("conflict")
+	calls := 0
+
+	// errors that are not transient are returned to retries
+	fn := func() (interface{}, error) {
+		calls++
+		return nil, errConflict
+	}
+	_, err := f.Idempotent(nil, "conflict", fn)
+	c.Assert(err, Equals, errConflict)
+	_, err = f.Idempotent(nil, "conflict", fn)
+	c.Assert(err, Equals, errConflict)
+	c.Assert(calls, Equals, 1)
+
+	// transient errors let the call run again
+	calls = 0
+	fn = func() (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, apierror.New(apierror.Transient, "try again")
+		}
+		return "ok", nil
+	}
+	_, err = f.Idempotent(nil, "transient", fn)
+	c.Assert(apierror.IsTransient(err), Equals, true)
+	result, err := f.Idempotent(nil, "transient", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, "ok")
+	c.Assert(calls, Equals, 2)
+}
+
+func (t *IdempotencyTest) TestIdempotent_Expired(c *C) {
+	defer func(retention time.Duration) { IdempotencyRetention = retention }(IdempotencyRetention)
+	IdempotencyRetention = -time.Second
+
+	f := &Facade{idempotency: newIdempotencyCache()}
+	calls := 0
+	fn := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	f.Idempotent(nil, "key", fn)
+	result, err := f.Idempotent(nil, "key", fn)
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, 2)
+}
+
+func (t *IdempotencyTest) TestIdempotent_Concurrent(c *C) {
+	f := &Facade{idempotency: newIdempotencyCache()}
+	release := make(chan struct{})
+	calls := 0
+	fn := func() (interface{}, error) {
+		calls++
+		<-release
+		return "done", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = f.Idempotent(nil, "key", fn)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+	c.Assert(calls, Equals, 1)
+	for _, result := range results {
+		c.Assert(result, Equals, "done")
+	}
+}
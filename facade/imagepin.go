@@ -0,0 +1,200 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/service"
+)
+
+/*
+   A service is pinned to the digest of its image when it is deployed.  The
+   digest is tagged in the registry as tenant/repo:sha256-<hex>, and the
+   instances of the service run that tag instead of the image of the service,
+   so restarts and reschedules run the same image even if the image of the
+   service is tagged to different bytes.  Deploys, upgrades, canary promotions
+   and commits pin the service again; otherwise the pin only changes when it is
+   refreshed.
+*/
+
+// GetServiceImagePins returns the image pins of a service and its
+// descendants that have an image.
+func (f *Facade) GetServiceImagePins(ctx datastore.Context, serviceID string) ([]service.ImagePin, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceImagePins"))
+	pins := []service.ImagePin{}
+	err := f.walkServices(ctx, serviceID, true, func(svc *service.Service) error {
+		if svc.ImageID == "" {
+			return nil
+		}
+		pin := service.ImagePin{
+			ServiceID: svc.ID,
+			Name:      svc.Name,
+			ImageID:   svc.ImageID,
+			Digest:    svc.ImageDigest,
+		}
+		rImage, err := f.getServiceRegistryImage(ctx, svc.ImageID, "")
+		if err != nil {
+			return err
+		} else if rImage != nil {
+			pin.Current = rImage.UUID
+		}
+		pins = append(pins, pin)
+		return nil
+	}, "GetServiceImagePins")
+	if err != nil {
+		plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not get image pins")
+		return nil, err
+	}
+	return pins, nil
+}
+
+// RefreshServiceImagePins pins a service and its descendants to the digests
+// that their images are currently tagged to, and returns the new pins.
+// Running instances keep their image until they are restarted.
+func (f *Facade) RefreshServiceImagePins(ctx datastore.Context, serviceID string) ([]service.ImagePin, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RefreshServiceImagePins"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	serviceIDs := []string{}
+	err := f.walkServices(ctx, serviceID, true, func(svc *service.Service) error {
+		if svc.ImageID != "" {
+			serviceIDs = append(serviceIDs, svc.ID)
+		}
+		return nil
+	}, "RefreshServiceImagePins")
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up services to pin")
+		return nil, err
+	}
+
+	for _, id := range serviceIDs {
+		svc, err := f.GetService(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		digest := svc.ImageDigest
+		if err := f.pinServiceImage(ctx, svc); err != nil {
+			return nil, err
+		}
+		if svc.ImageDigest == digest {
+			continue
+		}
+		if err := f.UpdateService(ctx, *svc); err != nil {
+			logger.WithField("pinnedserviceid", id).WithError(err).Debug("Could not update the image pin of service")
+			return nil, err
+		}
+		logger.WithFields(logrus.Fields{
+			"pinnedserviceid": id,
+			"old":             digest,
+			"new":             svc.ImageDigest,
+		}).Info("Refreshed the image pin of service")
+	}
+	return f.GetServiceImagePins(ctx, serviceID)
+}
+
+// pinServiceImage pins the service to the digest that its image is tagged
+// to in the registry, and tags the digest in the registry so the instances
+// can pull it on any host.  Services whose image is not in the registry, or
+// is not content-addressed, are left unpinned.
+func (f *Facade) pinServiceImage(ctx datastore.Context, svc *service.Service) error {
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid": svc.ID,
+		"imageid":   svc.ImageID,
+	})
+	if svc.ImageID == "" {
+		svc.ImageDigest = ""
+		return nil
+	}
+
+	rImage, err := f.getServiceRegistryImage(ctx, svc.ImageID, "")
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the image of the service in the registry")
+		return err
+	} else if rImage == nil || !commons.ValidImageDigest(rImage.UUID) {
+		logger.Warn("Image is not in the registry index by digest; service will not be pinned")
+		svc.ImageDigest = ""
+		return nil
+	}
+
+	pin := &registry.Image{
+		Library: rImage.Library,
+		Repo:    rImage.Repo,
+		Tag:     commons.DigestTag(rImage.UUID),
+		UUID:    rImage.UUID,
+		Hash:    rImage.Hash,
+	}
+	if current, err := f.getServiceRegistryImage(ctx, svc.ImageID, rImage.UUID); err != nil {
+		return err
+	} else if current == nil || current.UUID != pin.UUID {
+		if err := f.SetRegistryImage(ctx, pin); err != nil {
+			logger.WithError(err).Debug("Could not tag the digest of the image in the registry")
+			return err
+		}
+	}
+	svc.ImageDigest = rImage.UUID
+	logger.WithField("digest", svc.ImageDigest).Debug("Pinned service to the digest of its image")
+	return nil
+}
+
+// pinnedImageID returns the image that the instances of a pinned service run.
+// If the digest is no longer tagged in the registry, as when the service was
+// restored from a backup, the image of the service is run until the pin is
+// refreshed.
+func (f *Facade) pinnedImageID(ctx datastore.Context, svc *service.Service) string {
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid": svc.ID,
+		"imageid":   svc.ImageID,
+		"digest":    svc.ImageDigest,
+	})
+	imageID, err := commons.ParseImageID(svc.ImageID)
+	if err != nil {
+		logger.WithError(err).Warn("Could not parse the image of the pinned service")
+		return svc.ImageID
+	}
+	if rImage, err := f.getServiceRegistryImage(ctx, svc.ImageID, svc.ImageDigest); err != nil || rImage == nil || rImage.UUID != svc.ImageDigest {
+		logger.Warn("Pinned digest is not tagged in the registry; running the image of the service until the pin is refreshed")
+		return svc.ImageID
+	}
+	imageID.Tag = commons.DigestTag(svc.ImageDigest)
+	imageID.Digest = ""
+	return imageID.String()
+}
+
+// getServiceRegistryImage returns the registry index entry of the image of a
+// service, or of the tag of the digest if digest is set.  It returns nil if
+// the image is not in the registry index.
+func (f *Facade) getServiceRegistryImage(ctx datastore.Context, image, digest string) (*registry.Image, error) {
+	imageID, err := commons.ParseImageID(image)
+	if err != nil {
+		return nil, err
+	}
+	tag := imageID.Tag
+	if digest != "" {
+		tag = commons.DigestTag(digest)
+	} else if imageID.IsLatest() {
+		tag = docker.Latest
+	}
+	key := (&registry.Image{Library: imageID.User, Repo: imageID.Repo, Tag: tag}).String()
+	rImage, err := f.registryStore.Get(ctx, key)
+	if datastore.IsErrNoSuchEntity(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return rImage, nil
+}
@@ -30,6 +30,10 @@ import (
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
+// ErrInvalidInstanceEnvironment is returned when an environment override of
+// a service instance is not of the form KEY=VALUE
+var ErrInvalidInstanceEnvironment = errors.New("environment variables must be of the form KEY=VALUE")
+
 // GetServiceInstances returns the state of all instances for a particular
 // service.
 func (f *Facade) GetServiceInstances(ctx datastore.Context, since time.Time, serviceID string) ([]service.Instance, error) {
@@ -120,6 +124,26 @@ func (f *Facade) GetServiceInstances(ctx datastore.Context, since time.Time, ser
 	return insts, nil
 }
 
+// GetServiceInstanceHistory returns the hosts that each instance of a service
+// has run on, with their start and stop times, ordered from oldest to newest.
+func (f *Facade) GetServiceInstanceHistory(ctx datastore.Context, serviceID string) ([]service.InstanceHistory, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceInstanceHistory"))
+	logger := plog.WithField("serviceid", serviceID)
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+
+	history, err := f.zzk.GetServiceInstanceHistory(ctx, svc.PoolID, svc.ID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up instance history")
+		return nil, err
+	}
+	return history, nil
+}
+
 // GetHostInstances returns the state of all instances for a particular host.
 func (f *Facade) GetHostInstances(ctx datastore.Context, since time.Time, hostID string) ([]service.Instance, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetHostInstances"))
@@ -250,6 +274,9 @@ func (f *Facade) getInstance(ctx datastore.Context, hst host.Host, svc service.S
 		DesiredState:  state.DesiredState,
 		CurrentState:  curState,
 		HealthStatus:  f.getInstanceHealth(svch, state.InstanceID),
+		HealthProbes:  f.getInstanceProbes(svch, state.InstanceID),
+		Restarts:      state.Restarts,
+		Environment:   state.Environment,
 		RAMCommitment: int64(svc.RAMCommitment.Value),
 		Scheduled:     state.Scheduled,
 		Started:       state.Started,
@@ -400,6 +427,26 @@ func (f *Facade) getInstanceHealth(svch *service.ServiceHealth, instanceID int)
 	return hstats
 }
 
+// getInstanceProbes returns the last result of each health check of an
+// instance, including why a failing check failed
+func (f *Facade) getInstanceProbes(svch *service.ServiceHealth, instanceID int) map[string]health.HealthStatus {
+	var probes map[string]health.HealthStatus
+	for name := range svch.HealthChecks {
+		key := health.HealthStatusKey{
+			ServiceID:       svch.ID,
+			InstanceID:      instanceID,
+			HealthCheckName: name,
+		}
+		if result, ok := f.hcache.Get(key); ok {
+			if probes == nil {
+				probes = make(map[string]health.HealthStatus)
+			}
+			probes[name] = result
+		}
+	}
+	return probes
+}
+
 // GetHostStrategyInstances returns the strategy objects of all the instances
 // running on a host.
 func (f *Facade) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Host) ([]*service.StrategyInstance, error) {
@@ -440,6 +487,7 @@ func (f *Facade) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Ho
 				inst = service.StrategyInstance{
 					ServiceID:     s.ID,
 					CPUCommitment: int(s.CPUCommitment),
+					CPURequest:    s.CPURequest,
 					RAMCommitment: s.RAMCommitment.Value,
 					HostPolicy:    s.HostPolicy,
 				}
@@ -479,6 +527,40 @@ func (f *Facade) StopServiceInstance(ctx datastore.Context, serviceID string, in
 	return nil
 }
 
+// SetServiceInstanceEnvironment sets variables that are added to the
+// environment of a single instance of a service, overriding the variables of
+// the service with the same name.  The override takes effect the next time
+// the instance starts; neither the instance nor the other instances of the
+// service are restarted.  It lasts until it is cleared with an empty
+// environment or the instance is rescheduled.
+func (f *Facade) SetServiceInstanceEnvironment(ctx datastore.Context, serviceID string, instanceID int, env []string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetServiceInstanceEnvironment"))
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	for _, v := range env {
+		if strings.Index(v, "=") < 1 {
+			return ErrInvalidInstanceEnvironment
+		}
+	}
+
+	svc, err := f.serviceStore.Get(ctx, serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return err
+	}
+
+	if err := f.zzk.SetInstanceEnvironment(ctx, svc.PoolID, svc.ID, instanceID, env); err != nil {
+		logger.WithError(err).Debug("Could not set service instance environment")
+		return err
+	}
+
+	logger.Info("Set the environment override of service instance; it takes effect when the instance restarts")
+	return nil
+}
+
 // LocateServiceInstance returns host and container information about a service
 // instance
 func (f *Facade) LocateServiceInstance(ctx datastore.Context, serviceID string, instanceID int) (*service.LocationInstance, error) {
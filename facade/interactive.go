@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/utils"
+)
+
+// ErrInteractiveAccessDenied is returned when the hosts of a pool may not
+// open shells in, attach to or run actions in the instances of a service
+var ErrInteractiveAccessDenied = errors.New("facade: pool does not have interactive access to the service")
+
+// CheckInteractiveAccess returns an error if the hosts of a pool may not open
+// shells in, attach to or run actions in the instances of a service.  The
+// pool must have interactive or admin access, and must be one of the
+// interactive pools of the nearest service up the tree that restricts them.
+// Viewing the status and logs of the service is not checked.
+func (f *Facade) CheckInteractiveAccess(ctx datastore.Context, serviceID, poolID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CheckInteractiveAccess"))
+	p := &pool.ResourcePool{}
+	if err := f.poolStore.Get(ctx, pool.Key(poolID), p); err != nil {
+		if datastore.IsErrNoSuchEntity(err) {
+			return ErrInteractiveAccessDenied
+		}
+		return err
+	}
+	if !p.HasInteractiveAccess() {
+		return ErrInteractiveAccessDenied
+	}
+	for serviceID != "" {
+		svc, err := f.serviceStore.Get(ctx, serviceID)
+		if err != nil {
+			return err
+		}
+		if len(svc.InteractivePools) > 0 {
+			if utils.StringInSlice(poolID, svc.InteractivePools) {
+				return nil
+			}
+			return ErrInteractiveAccessDenied
+		}
+		serviceID = svc.ParentServiceID
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupInteractivePool(poolID string, perms pool.Permission) {
+	ft.poolStore.On("Get", ft.ctx, pool.Key(poolID), mock.AnythingOfType("*pool.ResourcePool")).Return(nil).Run(
+		func(args mock.Arguments) {
+			p := args.Get(2).(*pool.ResourcePool)
+			p.ID = poolID
+			p.Permissions = perms
+		})
+}
+
+func (ft *FacadeUnitTest) setupInteractiveTree() {
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant"}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "prod").Return(&service.Service{ID: "prod", ParentServiceID: "tenant", InteractivePools: []string{"admins"}}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "db").Return(&service.Service{ID: "db", ParentServiceID: "prod"}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "cache").Return(&service.Service{ID: "cache", ParentServiceID: "prod", InteractivePools: []string{"admins", "support"}}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_CheckInteractiveAccess_Permissions(c *C) {
+	ft.setupInteractiveTree()
+	ft.setupInteractivePool("admins", pool.AdminAccess)
+	ft.setupInteractivePool("support", pool.ReadOnlyAccess|pool.OperatorAccess)
+	ft.setupInteractivePool("ops", pool.OperatorAccess|pool.InteractiveAccess)
+	ft.poolStore.On("Get", ft.ctx, pool.Key("missing"), mock.AnythingOfType("*pool.ResourcePool")).Return(datastore.ErrNoSuchEntity{})
+
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "tenant", "admins"), IsNil)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "tenant", "ops"), IsNil)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "tenant", "support"), Equals, facade.ErrInteractiveAccessDenied)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "tenant", "missing"), Equals, facade.ErrInteractiveAccessDenied)
+}
+
+func (ft *FacadeUnitTest) Test_CheckInteractiveAccess_Subtree(c *C) {
+	ft.setupInteractiveTree()
+	ft.setupInteractivePool("admins", pool.AdminAccess)
+	ft.setupInteractivePool("ops", pool.InteractiveAccess)
+	ft.setupInteractivePool("support", pool.InteractiveAccess)
+
+	// the nearest service up the tree with interactive pools decides
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "db", "admins"), IsNil)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "db", "ops"), Equals, facade.ErrInteractiveAccessDenied)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "cache", "support"), IsNil)
+	c.Assert(ft.Facade.CheckInteractiveAccess(ft.ctx, "cache", "ops"), Equals, facade.ErrInteractiveAccessDenied)
+}
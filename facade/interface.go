@@ -16,19 +16,28 @@ package facade
 import (
 	"time"
 
+	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/health"
 
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/validation"
 )
 
 // The FacadeInterface is the API for a Facade
@@ -58,6 +67,8 @@ type FacadeInterface interface {
 
 	WaitService(ctx datastore.Context, dstate service.DesiredState, timeout time.Duration, recursive bool, serviceIDs ...string) error
 
+	WaitServiceStateEvents(ctx datastore.Context, serviceID string, since uint64, timeout time.Duration, cancel <-chan struct{}) (*service.StateEvents, error)
+
 	AssignIPs(ctx datastore.Context, assignmentRequest addressassignment.AssignmentRequest) (err error)
 
 	RemoveIPs(ctx datastore.Context, args []string) error
@@ -68,11 +79,15 @@ type FacadeInterface interface {
 
 	GetServiceTemplates(ctx datastore.Context) (map[string]servicetemplate.ServiceTemplate, error)
 
+	GetSchema(ctx datastore.Context, name string) (*validation.Schema, error)
+
 	RemoveServiceTemplate(ctx datastore.Context, templateID string) error
 
 	UpdateServiceTemplate(ctx datastore.Context, template servicetemplate.ServiceTemplate, reloadLogstashConfig bool) error
 
-	DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error)
+	DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, values servicetemplate.Values) ([]string, error)
+
+	UpgradeTemplate(ctx datastore.Context, templateID, deploymentID string, dryRun bool) ([]servicetemplate.TemplateChange, error)
 
 	DeployTemplateActive() (active []map[string]string, err error)
 
@@ -86,6 +101,8 @@ type FacadeInterface interface {
 
 	GetHosts(ctx datastore.Context) ([]host.Host, error)
 
+	SetHostMaintenance(ctx datastore.Context, hostID string, enabled bool) error
+
 	GetHostKey(ctx datastore.Context, hostID string) ([]byte, error)
 
 	ResetHostKey(ctx datastore.Context, hostID string) ([]byte, error)
@@ -134,12 +151,18 @@ type FacadeInterface interface {
 
 	EnablePublicEndpointVHost(ctx datastore.Context, serviceid, endpointName, vhost string, isEnabled bool) error
 
+	SetPublicEndpointVHostCertificate(ctx datastore.Context, serviceid, vhost string, certPEM, keyPEM []byte) error
+
 	GetHostInstances(ctx datastore.Context, since time.Time, hostid string) ([]service.Instance, error)
 
 	ListTenants(datastore.Context) ([]string, error)
 
 	GetServiceInstances(ctx datastore.Context, since time.Time, serviceid string) ([]service.Instance, error)
 
+	GetServiceInstanceHistory(ctx datastore.Context, serviceID string) ([]service.InstanceHistory, error)
+
+	GetServiceLogs(ctx datastore.Context, query service.LogQuery) ([]service.LogMessage, error)
+
 	GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error)
 
 	GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error)
@@ -178,8 +201,94 @@ type FacadeInterface interface {
 
 	ValidateCredentials(ctx datastore.Context, u user.User) (bool, error)
 
+	AddCalendar(ctx datastore.Context, entity *calendar.Calendar) error
+
+	UpdateCalendar(ctx datastore.Context, entity *calendar.Calendar) error
+
+	RemoveCalendar(ctx datastore.Context, id string) error
+
+	GetCalendar(ctx datastore.Context, id string) (*calendar.Calendar, error)
+
+	GetCalendars(ctx datastore.Context) ([]calendar.Calendar, error)
+
+	GetAuditEntries(ctx datastore.Context, since time.Time) ([]audit.Entry, error)
+
+	Idempotent(ctx datastore.Context, key string, fn func() (interface{}, error)) (interface{}, error)
+
+	GetFeatureFlags(ctx datastore.Context) ([]feature.Flag, error)
+
+	EnableFeature(ctx datastore.Context, name, poolID, deploymentID string) error
+
+	DisableFeature(ctx datastore.Context, name, poolID, deploymentID string) error
+
+	FeatureEnabled(ctx datastore.Context, name, poolID, deploymentID string) (bool, error)
+
+	GetPins(ctx datastore.Context) ([]pin.Pin, error)
+
+	PinSnapshot(ctx datastore.Context, snapshotID, reason string) error
+
+	PinImage(ctx datastore.Context, image, reason string) error
+
+	Unpin(ctx datastore.Context, kind pin.Kind, target string) error
+
+	SnapshotPinned(ctx datastore.Context, snapshotID string) (bool, error)
+
+	CheckInteractiveAccess(ctx datastore.Context, serviceID, poolID string) error
+
+	GetSettings(ctx datastore.Context) ([]setting.Setting, error)
+
+	SetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID, value string) error
+
+	UnsetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID string) error
+
+	GetDelegateProfiles(ctx datastore.Context) ([]delegateprofile.Profile, error)
+
+	GetDelegateProfile(ctx datastore.Context, name string) (*delegateprofile.Profile, error)
+
+	AddDelegateProfile(ctx datastore.Context, name, description string) error
+
+	RemoveDelegateProfile(ctx datastore.Context, name string) error
+
+	SetDelegateProfileOption(ctx datastore.Context, name, option, value string) ([]delegateprofile.HostStatus, error)
+
+	UnsetDelegateProfileOption(ctx datastore.Context, name, option string) ([]delegateprofile.HostStatus, error)
+
+	RollbackDelegateProfile(ctx datastore.Context, name string, version int) ([]delegateprofile.HostStatus, error)
+
+	AssignDelegateProfile(ctx datastore.Context, poolID, name string) ([]delegateprofile.HostStatus, error)
+
+	PushDelegateProfile(ctx datastore.Context, poolID string) ([]delegateprofile.HostStatus, error)
+
+	GetHostDelegateProfile(ctx datastore.Context, hostID string) (*delegateprofile.Profile, error)
+
+	SetMaintenanceMode(ctx datastore.Context, tenantID string, mode *service.MaintenanceMode) error
+
+	GetMaintenanceMode(ctx datastore.Context, tenantID string) (*service.MaintenanceMode, error)
+
+	SyncSettings(ctx datastore.Context) error
+
+	SetSecret(ctx datastore.Context, name, description, value string) error
+
+	RemoveSecret(ctx datastore.Context, name string) error
+
+	GetSecrets(ctx datastore.Context) ([]secret.Secret, error)
+
+	GetSecretValue(ctx datastore.Context, name string) (string, error)
+
+	GetServiceSecrets(ctx datastore.Context, serviceID string, instanceID int) (map[string]string, error)
+
+	SetCertificate(ctx datastore.Context, name, issuer string, certPEM, keyPEM []byte) error
+
+	RemoveCertificate(ctx datastore.Context, name string) error
+
+	GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error)
+
+	GetCertificateKeyPair(ctx datastore.Context, name string) ([]byte, []byte, error)
+
 	GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error)
 
+	GetServiceHealth(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthStatus, error)
+
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration)
 
 	ReportInstanceDead(serviceID string, instanceID int)
@@ -219,4 +328,10 @@ type FacadeInterface interface {
 	StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
 
 	PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error)
+
+	StartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error)
+
+	RestartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error)
+
+	StopServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error)
 }
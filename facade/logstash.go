@@ -52,8 +52,9 @@ type serviceLogInfo struct {
 // can expand the scope of auditable logs or change log filters without touching the currently loaded templates.
 //
 // If the new configuration is different from the one currently used by logstash,
-// then it will rewrite the logstash.conf file, trusting that logstash will recognize the file change
-// and reload the new filter set.
+// then it will rewrite the logstash.conf file and signal logstash to reload the new filter set in place.
+// The logstash container is only restarted if the reload signal cannot be delivered, because a restart
+// drops log ingestion for as long as logstash takes to start.
 //
 // This method should be called anytime the available service templates are modified or deployed services are upgraded.
 //
@@ -125,6 +126,25 @@ func (f *Facade) ReloadLogstashConfig(ctx datastore.Context) error {
 		plog.WithError(err).Error("Could not write logstash configuration: %s", err)
 		return err
 	}
+	return f.applyLogstashConfig()
+}
+
+// applyLogstashConfig asks logstash to reload the rewritten configuration,
+// falling back to a restart of the logstash container.
+func (f *Facade) applyLogstashConfig() error {
+	if f.logstash == nil {
+		return nil
+	}
+	err := f.logstash.ReloadLogstash()
+	if err == nil {
+		plog.Info("Reloaded logstash configuration")
+		return nil
+	}
+	plog.WithError(err).Warn("Could not reload logstash configuration, restarting logstash")
+	if err := f.logstash.RestartLogstash(); err != nil {
+		plog.WithError(err).Error("Could not restart logstash")
+		return err
+	}
 	return nil
 }
 
@@ -21,6 +21,7 @@
 package facade
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -657,6 +658,55 @@ func  (t *LogStashTest) TestWritingConfigFile(c *C) {
 	}
 }
 
+type testLogstashReloader struct {
+	reloadErr  error
+	restartErr error
+	reloads    int
+	restarts   int
+}
+
+func (r *testLogstashReloader) ReloadLogstash() error {
+	r.reloads++
+	return r.reloadErr
+}
+
+func (r *testLogstashReloader) RestartLogstash() error {
+	r.restarts++
+	return r.restartErr
+}
+
+func (t *LogStashTest) Test_applyLogstashConfig_NoReloader(c *C) {
+	f := &Facade{}
+	c.Assert(f.applyLogstashConfig(), IsNil)
+}
+
+func (t *LogStashTest) Test_applyLogstashConfig_Reloads(c *C) {
+	reloader := &testLogstashReloader{}
+	f := &Facade{logstash: reloader}
+
+	c.Assert(f.applyLogstashConfig(), IsNil)
+	c.Assert(reloader.reloads, Equals, 1)
+	c.Assert(reloader.restarts, Equals, 0)
+}
+
+func (t *LogStashTest) Test_applyLogstashConfig_RestartsWhenReloadFails(c *C) {
+	reloader := &testLogstashReloader{reloadErr: errors.New("no such container")}
+	f := &Facade{logstash: reloader}
+
+	c.Assert(f.applyLogstashConfig(), IsNil)
+	c.Assert(reloader.reloads, Equals, 1)
+	c.Assert(reloader.restarts, Equals, 1)
+}
+
+func (t *LogStashTest) Test_applyLogstashConfig_RestartFails(c *C) {
+	restartErr := errors.New("restart failed")
+	reloader := &testLogstashReloader{reloadErr: errors.New("no such container"), restartErr: restartErr}
+	f := &Facade{logstash: reloader}
+
+	c.Assert(f.applyLogstashConfig(), Equals, restartErr)
+	c.Assert(reloader.restarts, Equals, 1)
+}
+
 func getTestServices(version string) []service.Service {
 	return []service.Service{
 		service.Service{
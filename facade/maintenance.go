@@ -0,0 +1,93 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+var (
+	// ErrMaintenanceNotTenant is returned when setting the maintenance mode
+	// of a service that is not a tenant
+	ErrMaintenanceNotTenant = errors.New("facade: maintenance mode can only be set on a tenant")
+)
+
+// SetMaintenanceMode puts a tenant into maintenance mode, so that its http
+// public endpoints serve a maintenance page with a 503 status while work on
+// the tenant proceeds, or takes it out of maintenance mode if mode is nil.
+func (f *Facade) SetMaintenanceMode(ctx datastore.Context, tenantID string, mode *service.MaintenanceMode) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetMaintenanceMode"))
+	logger := plog.WithField("tenantid", tenantID)
+	alog := f.auditLogger.Message(ctx, "Setting Maintenance Mode").Action(audit.Update).ID(tenantID).Type(service.GetType()).WithField("enabled", strconv.FormatBool(mode != nil))
+
+	svc, err := f.GetService(ctx, tenantID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up tenant")
+		return alog.Error(err)
+	} else if svc.ParentServiceID != "" {
+		return alog.Error(ErrMaintenanceNotTenant)
+	}
+
+	if mode != nil {
+		m := *mode
+		if m.RetryAfter <= 0 {
+			m.RetryAfter = service.DefaultMaintenanceRetryAfter
+		}
+		if svc.Maintenance != nil {
+			// keep the time that the tenant entered maintenance mode
+			m.Since = svc.Maintenance.Since
+		} else if m.Since.IsZero() {
+			m.Since = time.Now().UTC()
+		}
+		mode = &m
+	} else if svc.Maintenance == nil {
+		alog.Succeeded()
+		return nil
+	}
+
+	svc.Maintenance = mode
+	if err := f.UpdateService(ctx, *svc); err != nil {
+		logger.WithError(err).Debug("Could not update the maintenance mode of tenant")
+		return alog.Error(err)
+	}
+
+	if mode != nil {
+		logger.WithFields(logrus.Fields{
+			"message":    mode.Message,
+			"retryafter": mode.RetryAfter,
+		}).Info("Tenant entered maintenance mode")
+	} else {
+		logger.Info("Tenant left maintenance mode")
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// GetMaintenanceMode returns the maintenance mode of a tenant, or nil if the
+// tenant is not in maintenance mode
+func (f *Facade) GetMaintenanceMode(ctx datastore.Context, tenantID string) (*service.MaintenanceMode, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetMaintenanceMode"))
+	details, err := f.serviceStore.GetServiceDetails(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return details.Maintenance, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *FacadeIntegrationTest) TestSetMaintenanceMode(c *C) {
+	t.AddServices(c)
+
+	// only tenants have a maintenance mode
+	err := t.Facade.SetMaintenanceMode(t.CTX, "2222", &service.MaintenanceMode{})
+	c.Assert(err, Equals, ErrMaintenanceNotTenant)
+
+	err = t.Facade.SetMaintenanceMode(t.CTX, "1111", &service.MaintenanceMode{Message: "Upgrading"})
+	c.Assert(err, IsNil)
+	mode, err := t.Facade.GetMaintenanceMode(t.CTX, "1111")
+	c.Assert(err, IsNil)
+	c.Assert(mode, NotNil)
+	c.Assert(mode.Message, Equals, "Upgrading")
+	c.Assert(mode.RetryAfter, Equals, service.DefaultMaintenanceRetryAfter)
+	c.Assert(mode.Since.IsZero(), Equals, false)
+	since := mode.Since
+
+	// changing the message keeps the time that the tenant entered
+	// maintenance mode
+	err = t.Facade.SetMaintenanceMode(t.CTX, "1111", &service.MaintenanceMode{Message: "Migrating", RetryAfter: 60})
+	c.Assert(err, IsNil)
+	details, err := t.Facade.GetServiceDetails(t.CTX, "1111")
+	c.Assert(err, IsNil)
+	c.Assert(details.Maintenance, NotNil)
+	c.Assert(details.Maintenance.Message, Equals, "Migrating")
+	c.Assert(details.Maintenance.RetryAfter, Equals, 60)
+	c.Assert(details.Maintenance.Since.Equal(since), Equals, true)
+
+	mode, err = t.Facade.GetMaintenanceMode(t.CTX, "3333")
+	c.Assert(err, IsNil)
+	c.Assert(mode, IsNil)
+
+	err = t.Facade.SetMaintenanceMode(t.CTX, "1111", nil)
+	c.Assert(err, IsNil)
+	mode, err = t.Facade.GetMaintenanceMode(t.CTX, "1111")
+	c.Assert(err, IsNil)
+	c.Assert(mode, IsNil)
+}
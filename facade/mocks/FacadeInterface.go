@@ -1,6 +1,10 @@
 package mocks
 
 import addressassignment "github.com/control-center/serviced/domain/addressassignment"
+import calendar "github.com/control-center/serviced/domain/calendar"
+import certificate "github.com/control-center/serviced/domain/certificate"
+import delegateprofile "github.com/control-center/serviced/domain/delegateprofile"
+import feature "github.com/control-center/serviced/domain/feature"
 import dao "github.com/control-center/serviced/dao"
 import datastore "github.com/control-center/serviced/datastore"
 import domain "github.com/control-center/serviced/domain"
@@ -8,19 +12,38 @@ import domain "github.com/control-center/serviced/domain"
 import health "github.com/control-center/serviced/health"
 import host "github.com/control-center/serviced/domain/host"
 import mock "github.com/stretchr/testify/mock"
+import pin "github.com/control-center/serviced/domain/pin"
 import pool "github.com/control-center/serviced/domain/pool"
+import secret "github.com/control-center/serviced/domain/secret"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import setting "github.com/control-center/serviced/domain/setting"
+import audit "github.com/control-center/serviced/audit"
 import time "time"
 import user "github.com/control-center/serviced/domain/user"
 import "github.com/control-center/serviced/utils"
+import validation "github.com/control-center/serviced/validation"
 
 // FacadeInterface is an autogenerated mock type for the FacadeInterface type
 type FacadeInterface struct {
 	mock.Mock
 }
 
+// AddCalendar provides a mock function with given fields: ctx, entity
+func (_m *FacadeInterface) AddCalendar(ctx datastore.Context, entity *calendar.Calendar) error {
+	ret := _m.Called(ctx, entity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *calendar.Calendar) error); ok {
+		r0 = rf(ctx, entity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: ctx, entity
 func (_m *FacadeInterface) AddHost(ctx datastore.Context, entity *host.Host) ([]byte, error) {
 	ret := _m.Called(ctx, entity)
@@ -204,48 +227,45 @@ func (_m *FacadeInterface) AssignIPs(ctx datastore.Context, assignmentRequest ad
 	return r0
 }
 
-// RemoveIPs provides a mock function with given fields: ctx, []string
-func (_m *FacadeInterface) RemoveIPs(ctx datastore.Context, args []string) error {
-	ret := _m.Called(ctx, args)
+// GetCalendar provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) GetCalendar(ctx datastore.Context, id string) (*calendar.Calendar, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, []string) error); ok {
-		r0 = rf(ctx, args)
+	var r0 *calendar.Calendar
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *calendar.Calendar); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*calendar.Calendar)
+		}
 	}
 
-	return r0
-}
-
-// SetIPs  provides a mock function with given fields: ctx, assignmentRequest
-func (_m *FacadeInterface) SetIPs(ctx datastore.Context, assignmentRequest addressassignment.AssignmentRequest) error {
-	ret := _m.Called(ctx, assignmentRequest)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, addressassignment.AssignmentRequest) error); ok {
-		r0 = rf(ctx, assignmentRequest)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// ClearEmergencyStopFlag provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) ClearEmergencyStopFlag(ctx datastore.Context, serviceID string) (int, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetCalendars provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetCalendars(ctx datastore.Context) ([]calendar.Calendar, error) {
+	ret := _m.Called(ctx)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) int); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 []calendar.Calendar
+	if rf, ok := ret.Get(0).(func(datastore.Context) []calendar.Calendar); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]calendar.Calendar)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -253,22 +273,22 @@ func (_m *FacadeInterface) ClearEmergencyStopFlag(ctx datastore.Context, service
 	return r0, r1
 }
 
-// CountDescendantStates provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) CountDescendantStates(ctx datastore.Context, serviceID string) (map[string]map[string]int, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetAuditEntries provides a mock function with given fields: ctx, since
+func (_m *FacadeInterface) GetAuditEntries(ctx datastore.Context, since time.Time) ([]audit.Entry, error) {
+	ret := _m.Called(ctx, since)
 
-	var r0 map[string]map[string]int
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[string]map[string]int); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 []audit.Entry
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time) []audit.Entry); ok {
+		r0 = rf(ctx, since)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]map[string]int)
+			r0 = ret.Get(0).([]audit.Entry)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time) error); ok {
+		r1 = rf(ctx, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -276,36 +296,22 @@ func (_m *FacadeInterface) CountDescendantStates(ctx datastore.Context, serviceI
 	return r0, r1
 }
 
-// DeleteServiceConfig provides a mock function with given fields: ctx, fileID
-func (_m *FacadeInterface) DeleteServiceConfig(ctx datastore.Context, fileID string) error {
-	ret := _m.Called(ctx, fileID)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, fileID)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// DeployTemplate provides a mock function with given fields: ctx, poolID, templateID, deploymentID
-func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error) {
-	ret := _m.Called(ctx, poolID, templateID, deploymentID)
+// Idempotent provides a mock function with given fields: ctx, key, fn
+func (_m *FacadeInterface) Idempotent(ctx datastore.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	ret := _m.Called(ctx, key, fn)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) []string); ok {
-		r0 = rf(ctx, poolID, templateID, deploymentID)
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, func() (interface{}, error)) interface{}); ok {
+		r0 = rf(ctx, key, fn)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(interface{})
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string) error); ok {
-		r1 = rf(ctx, poolID, templateID, deploymentID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, func() (interface{}, error)) error); ok {
+		r1 = rf(ctx, key, fn)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -313,22 +319,22 @@ func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string,
 	return r0, r1
 }
 
-// DeployTemplateActive provides a mock function with given fields:
-func (_m *FacadeInterface) DeployTemplateActive() ([]map[string]string, error) {
-	ret := _m.Called()
+// GetFeatureFlags provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetFeatureFlags(ctx datastore.Context) ([]feature.Flag, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []map[string]string
-	if rf, ok := ret.Get(0).(func() []map[string]string); ok {
-		r0 = rf()
+	var r0 []feature.Flag
+	if rf, ok := ret.Get(0).(func(datastore.Context) []feature.Flag); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]map[string]string)
+			r0 = ret.Get(0).([]feature.Flag)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -336,41 +342,48 @@ func (_m *FacadeInterface) DeployTemplateActive() ([]map[string]string, error) {
 	return r0, r1
 }
 
-// DeployTemplateStatus provides a mock function with given fields: deploymentID, lastStatus, timeout
-func (_m *FacadeInterface) DeployTemplateStatus(deploymentID string, lastStatus string, timeout time.Duration) (string, error) {
-	ret := _m.Called(deploymentID, lastStatus, timeout)
+// EnableFeature provides a mock function with given fields: ctx, name, poolID, deploymentID
+func (_m *FacadeInterface) EnableFeature(ctx datastore.Context, name string, poolID string, deploymentID string) error {
+	ret := _m.Called(ctx, name, poolID, deploymentID)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string, string, time.Duration) string); ok {
-		r0 = rf(deploymentID, lastStatus, timeout)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, name, poolID, deploymentID)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, time.Duration) error); ok {
-		r1 = rf(deploymentID, lastStatus, timeout)
+	return r0
+}
+
+// DisableFeature provides a mock function with given fields: ctx, name, poolID, deploymentID
+func (_m *FacadeInterface) DisableFeature(ctx datastore.Context, name string, poolID string, deploymentID string) error {
+	ret := _m.Called(ctx, name, poolID, deploymentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, name, poolID, deploymentID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// EmergencyStopService provides a mock function with given fields: ctx, request
-func (_m *FacadeInterface) EmergencyStopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
-	ret := _m.Called(ctx, request)
+// FeatureEnabled provides a mock function with given fields: ctx, name, poolID, deploymentID
+func (_m *FacadeInterface) FeatureEnabled(ctx datastore.Context, name string, poolID string, deploymentID string) (bool, error) {
+	ret := _m.Called(ctx, name, poolID, deploymentID)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
-		r0 = rf(ctx, request)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, name, poolID, deploymentID)
 	} else {
-		r0 = ret.Get(0).(int)
+		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, poolID, deploymentID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -378,13 +391,13 @@ func (_m *FacadeInterface) EmergencyStopService(ctx datastore.Context, request d
 	return r0, r1
 }
 
-// EnablePublicEndpointPort provides a mock function with given fields: ctx, serviceid, endpointName, portAddr, isEnabled
-func (_m *FacadeInterface) EnablePublicEndpointPort(ctx datastore.Context, serviceid string, endpointName string, portAddr string, isEnabled bool) error {
-	ret := _m.Called(ctx, serviceid, endpointName, portAddr, isEnabled)
+// RemoveCalendar provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) RemoveCalendar(ctx datastore.Context, id string) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, bool) error); ok {
-		r0 = rf(ctx, serviceid, endpointName, portAddr, isEnabled)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -392,13 +405,13 @@ func (_m *FacadeInterface) EnablePublicEndpointPort(ctx datastore.Context, servi
 	return r0
 }
 
-// EnablePublicEndpointVHost provides a mock function with given fields: ctx, serviceid, endpointName, vhost, isEnabled
-func (_m *FacadeInterface) EnablePublicEndpointVHost(ctx datastore.Context, serviceid string, endpointName string, vhost string, isEnabled bool) error {
-	ret := _m.Called(ctx, serviceid, endpointName, vhost, isEnabled)
+// SetSecret provides a mock function with given fields: ctx, name, description, value
+func (_m *FacadeInterface) SetSecret(ctx datastore.Context, name string, description string, value string) error {
+	ret := _m.Called(ctx, name, description, value)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, bool) error); ok {
-		r0 = rf(ctx, serviceid, endpointName, vhost, isEnabled)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, name, description, value)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -406,22 +419,36 @@ func (_m *FacadeInterface) EnablePublicEndpointVHost(ctx datastore.Context, serv
 	return r0
 }
 
-// FindHostsInPool provides a mock function with given fields: ctx, poolID
-func (_m *FacadeInterface) FindHostsInPool(ctx datastore.Context, poolID string) ([]host.Host, error) {
-	ret := _m.Called(ctx, poolID)
+// RemoveSecret provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) RemoveSecret(ctx datastore.Context, name string) error {
+	ret := _m.Called(ctx, name)
 
-	var r0 []host.Host
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []host.Host); ok {
-		r0 = rf(ctx, poolID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetSecrets(ctx datastore.Context) ([]secret.Secret, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func(datastore.Context) []secret.Secret); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.Host)
+			r0 = ret.Get(0).([]secret.Secret)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, poolID)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -429,22 +456,20 @@ func (_m *FacadeInterface) FindHostsInPool(ctx datastore.Context, poolID string)
 	return r0, r1
 }
 
-// FindReadHostsInPool provides a mock function with given fields: ctx, poolID
-func (_m *FacadeInterface) FindReadHostsInPool(ctx datastore.Context, poolID string) ([]host.ReadHost, error) {
-	ret := _m.Called(ctx, poolID)
+// GetSecretValue provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) GetSecretValue(ctx datastore.Context, name string) (string, error) {
+	ret := _m.Called(ctx, name)
 
-	var r0 []host.ReadHost
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []host.ReadHost); ok {
-		r0 = rf(ctx, poolID)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+		r0 = rf(ctx, name)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.ReadHost)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, poolID)
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -452,22 +477,22 @@ func (_m *FacadeInterface) FindReadHostsInPool(ctx datastore.Context, poolID str
 	return r0, r1
 }
 
-// GetActiveHostIDs provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetActiveHostIDs(ctx datastore.Context) ([]string, error) {
-	ret := _m.Called(ctx)
+// GetServiceSecrets provides a mock function with given fields: ctx, serviceID, instanceID
+func (_m *FacadeInterface) GetServiceSecrets(ctx datastore.Context, serviceID string, instanceID int) (map[string]string, error) {
+	ret := _m.Called(ctx, serviceID, instanceID)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(datastore.Context) []string); ok {
-		r0 = rf(ctx)
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) map[string]string); ok {
+		r0 = rf(ctx, serviceID, instanceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]string)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, int) error); ok {
+		r1 = rf(ctx, serviceID, instanceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -475,39 +500,44 @@ func (_m *FacadeInterface) GetActiveHostIDs(ctx datastore.Context) ([]string, er
 	return r0, r1
 }
 
-// GetAggregateServices provides a mock function with given fields: ctx, since, serviceids
-func (_m *FacadeInterface) GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error) {
-	ret := _m.Called(ctx, since, serviceids)
+// SetCertificate provides a mock function with given fields: ctx, name, issuer, certPEM, keyPEM
+func (_m *FacadeInterface) SetCertificate(ctx datastore.Context, name string, issuer string, certPEM []byte, keyPEM []byte) error {
+	ret := _m.Called(ctx, name, issuer, certPEM, keyPEM)
 
-	var r0 []service.AggregateService
-	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, []string) []service.AggregateService); ok {
-		r0 = rf(ctx, since, serviceids)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, []byte, []byte) error); ok {
+		r0 = rf(ctx, name, issuer, certPEM, keyPEM)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.AggregateService)
-		}
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, []string) error); ok {
-		r1 = rf(ctx, since, serviceids)
+	return r0
+}
+
+// RemoveCertificate provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) RemoveCertificate(ctx datastore.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, name)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// GetAllPublicEndpoints provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetAllPublicEndpoints(ctx datastore.Context) ([]service.PublicEndpoint, error) {
+// GetCertificates provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
 	ret := _m.Called(ctx)
 
-	var r0 []service.PublicEndpoint
-	if rf, ok := ret.Get(0).(func(datastore.Context) []service.PublicEndpoint); ok {
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context) []certificate.Certificate); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.PublicEndpoint)
+			r0 = ret.Get(0).([]certificate.Certificate)
 		}
 	}
 
@@ -521,68 +551,80 @@ func (_m *FacadeInterface) GetAllPublicEndpoints(ctx datastore.Context) ([]servi
 	return r0, r1
 }
 
-// GetEvaluatedService provides a mock function with given fields: ctx, servicedID, instanceID
-func (_m *FacadeInterface) GetEvaluatedService(ctx datastore.Context, servicedID string, instanceID int) (*service.Service, error) {
-	ret := _m.Called(ctx, servicedID, instanceID)
+// GetCertificateKeyPair provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) GetCertificateKeyPair(ctx datastore.Context, name string) ([]byte, []byte, error) {
+	ret := _m.Called(ctx, name)
 
-	var r0 *service.Service
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) *service.Service); ok {
-		r0 = rf(ctx, servicedID, instanceID)
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.Service)
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, int) error); ok {
-		r1 = rf(ctx, servicedID, instanceID)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetHealthChecksForService provides a mock function with given fields: ctx, id
-func (_m *FacadeInterface) GetHealthChecksForService(ctx datastore.Context, id string) (map[string]health.HealthCheck, error) {
-	ret := _m.Called(ctx, id)
-
-	var r0 map[string]health.HealthCheck
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[string]health.HealthCheck); ok {
-		r0 = rf(ctx, id)
+	var r1 []byte
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) []byte); ok {
+		r1 = rf(ctx, name)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]health.HealthCheck)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	var r2 error
+	if rf, ok := ret.Get(2).(func(datastore.Context, string) error); ok {
+		r2 = rf(ctx, name)
 	} else {
-		r1 = ret.Error(1)
+		r2 = ret.Error(2)
 	}
 
-	return r0, r1
+	return r0, r1, r2
 }
 
-// GetHost provides a mock function with given fields: ctx, hostID
-func (_m *FacadeInterface) GetHost(ctx datastore.Context, hostID string) (*host.Host, error) {
-	ret := _m.Called(ctx, hostID)
+// RemoveIPs provides a mock function with given fields: ctx, []string
+func (_m *FacadeInterface) RemoveIPs(ctx datastore.Context, args []string) error {
+	ret := _m.Called(ctx, args)
 
-	var r0 *host.Host
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *host.Host); ok {
-		r0 = rf(ctx, hostID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string) error); ok {
+		r0 = rf(ctx, args)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*host.Host)
-		}
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetIPs  provides a mock function with given fields: ctx, assignmentRequest
+func (_m *FacadeInterface) SetIPs(ctx datastore.Context, assignmentRequest addressassignment.AssignmentRequest) error {
+	ret := _m.Called(ctx, assignmentRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, addressassignment.AssignmentRequest) error); ok {
+		r0 = rf(ctx, assignmentRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearEmergencyStopFlag provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) ClearEmergencyStopFlag(ctx datastore.Context, serviceID string) (int, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) int); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, hostID)
+		r1 = rf(ctx, serviceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -590,22 +632,22 @@ func (_m *FacadeInterface) GetHost(ctx datastore.Context, hostID string) (*host.
 	return r0, r1
 }
 
-// GetHostInstances provides a mock function with given fields: ctx, since, hostid
-func (_m *FacadeInterface) GetHostInstances(ctx datastore.Context, since time.Time, hostid string) ([]service.Instance, error) {
-	ret := _m.Called(ctx, since, hostid)
+// CountDescendantStates provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) CountDescendantStates(ctx datastore.Context, serviceID string) (map[string]map[string]int, error) {
+	ret := _m.Called(ctx, serviceID)
 
-	var r0 []service.Instance
-	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, string) []service.Instance); ok {
-		r0 = rf(ctx, since, hostid)
+	var r0 map[string]map[string]int
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[string]map[string]int); ok {
+		r0 = rf(ctx, serviceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Instance)
+			r0 = ret.Get(0).(map[string]map[string]int)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, string) error); ok {
-		r1 = rf(ctx, since, hostid)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -613,22 +655,36 @@ func (_m *FacadeInterface) GetHostInstances(ctx datastore.Context, since time.Ti
 	return r0, r1
 }
 
-// GetHostKey provides a mock function with given fields: ctx, hostID
-func (_m *FacadeInterface) GetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
-	ret := _m.Called(ctx, hostID)
+// DeleteServiceConfig provides a mock function with given fields: ctx, fileID
+func (_m *FacadeInterface) DeleteServiceConfig(ctx datastore.Context, fileID string) error {
+	ret := _m.Called(ctx, fileID)
 
-	var r0 []byte
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
-		r0 = rf(ctx, hostID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeployTemplate provides a mock function with given fields: ctx, poolID, templateID, deploymentID, values
+func (_m *FacadeInterface) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, values servicetemplate.Values) ([]string, error) {
+	ret := _m.Called(ctx, poolID, templateID, deploymentID, values)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, servicetemplate.Values) []string); ok {
+		r0 = rf(ctx, poolID, templateID, deploymentID, values)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, hostID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string, servicetemplate.Values) error); ok {
+		r1 = rf(ctx, poolID, templateID, deploymentID, values)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -636,22 +692,22 @@ func (_m *FacadeInterface) GetHostKey(ctx datastore.Context, hostID string) ([]b
 	return r0, r1
 }
 
-// GetHostStatuses provides a mock function with given fields: ctx, hostIDs, since
-func (_m *FacadeInterface) GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error) {
-	ret := _m.Called(ctx, hostIDs, since)
+// UpgradeTemplate provides a mock function with given fields: ctx, templateID, deploymentID, dryRun
+func (_m *FacadeInterface) UpgradeTemplate(ctx datastore.Context, templateID string, deploymentID string, dryRun bool) ([]servicetemplate.TemplateChange, error) {
+	ret := _m.Called(ctx, templateID, deploymentID, dryRun)
 
-	var r0 []host.HostStatus
-	if rf, ok := ret.Get(0).(func(datastore.Context, []string, time.Time) []host.HostStatus); ok {
-		r0 = rf(ctx, hostIDs, since)
+	var r0 []servicetemplate.TemplateChange
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, bool) []servicetemplate.TemplateChange); ok {
+		r0 = rf(ctx, templateID, deploymentID, dryRun)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.HostStatus)
+			r0 = ret.Get(0).([]servicetemplate.TemplateChange)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, []string, time.Time) error); ok {
-		r1 = rf(ctx, hostIDs, since)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, bool) error); ok {
+		r1 = rf(ctx, templateID, deploymentID, dryRun)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -659,22 +715,22 @@ func (_m *FacadeInterface) GetHostStatuses(ctx datastore.Context, hostIDs []stri
 	return r0, r1
 }
 
-// GetHosts provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetHosts(ctx datastore.Context) ([]host.Host, error) {
-	ret := _m.Called(ctx)
+// DeployTemplateActive provides a mock function with given fields:
+func (_m *FacadeInterface) DeployTemplateActive() ([]map[string]string, error) {
+	ret := _m.Called()
 
-	var r0 []host.Host
-	if rf, ok := ret.Get(0).(func(datastore.Context) []host.Host); ok {
-		r0 = rf(ctx)
+	var r0 []map[string]string
+	if rf, ok := ret.Get(0).(func() []map[string]string); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.Host)
+			r0 = ret.Get(0).([]map[string]string)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -682,22 +738,20 @@ func (_m *FacadeInterface) GetHosts(ctx datastore.Context) ([]host.Host, error)
 	return r0, r1
 }
 
-// GetPoolIPs provides a mock function with given fields: ctx, poolID
-func (_m *FacadeInterface) GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs, error) {
-	ret := _m.Called(ctx, poolID)
+// DeployTemplateStatus provides a mock function with given fields: deploymentID, lastStatus, timeout
+func (_m *FacadeInterface) DeployTemplateStatus(deploymentID string, lastStatus string, timeout time.Duration) (string, error) {
+	ret := _m.Called(deploymentID, lastStatus, timeout)
 
-	var r0 *pool.PoolIPs
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *pool.PoolIPs); ok {
-		r0 = rf(ctx, poolID)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) string); ok {
+		r0 = rf(deploymentID, lastStatus, timeout)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*pool.PoolIPs)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, poolID)
+	if rf, ok := ret.Get(1).(func(string, string, time.Duration) error); ok {
+		r1 = rf(deploymentID, lastStatus, timeout)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -705,22 +759,20 @@ func (_m *FacadeInterface) GetPoolIPs(ctx datastore.Context, poolID string) (*po
 	return r0, r1
 }
 
-// GetReadHosts provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetReadHosts(ctx datastore.Context) ([]host.ReadHost, error) {
-	ret := _m.Called(ctx)
+// EmergencyStopService provides a mock function with given fields: ctx, request
+func (_m *FacadeInterface) EmergencyStopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+	ret := _m.Called(ctx, request)
 
-	var r0 []host.ReadHost
-	if rf, ok := ret.Get(0).(func(datastore.Context) []host.ReadHost); ok {
-		r0 = rf(ctx)
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
+		r0 = rf(ctx, request)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.ReadHost)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
+		r1 = rf(ctx, request)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -728,22 +780,64 @@ func (_m *FacadeInterface) GetReadHosts(ctx datastore.Context) ([]host.ReadHost,
 	return r0, r1
 }
 
-// GetReadPools provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error) {
-	ret := _m.Called(ctx)
+// EnablePublicEndpointPort provides a mock function with given fields: ctx, serviceid, endpointName, portAddr, isEnabled
+func (_m *FacadeInterface) EnablePublicEndpointPort(ctx datastore.Context, serviceid string, endpointName string, portAddr string, isEnabled bool) error {
+	ret := _m.Called(ctx, serviceid, endpointName, portAddr, isEnabled)
 
-	var r0 []pool.ReadPool
-	if rf, ok := ret.Get(0).(func(datastore.Context) []pool.ReadPool); ok {
-		r0 = rf(ctx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, bool) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, portAddr, isEnabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnablePublicEndpointVHost provides a mock function with given fields: ctx, serviceid, endpointName, vhost, isEnabled
+func (_m *FacadeInterface) EnablePublicEndpointVHost(ctx datastore.Context, serviceid string, endpointName string, vhost string, isEnabled bool) error {
+	ret := _m.Called(ctx, serviceid, endpointName, vhost, isEnabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, bool) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, vhost, isEnabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostCertificate provides a mock function with given fields: ctx, serviceid, vhost, certPEM, keyPEM
+func (_m *FacadeInterface) SetPublicEndpointVHostCertificate(ctx datastore.Context, serviceid string, vhost string, certPEM []byte, keyPEM []byte) error {
+	ret := _m.Called(ctx, serviceid, vhost, certPEM, keyPEM)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, []byte, []byte) error); ok {
+		r0 = rf(ctx, serviceid, vhost, certPEM, keyPEM)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindHostsInPool provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) FindHostsInPool(ctx datastore.Context, poolID string) ([]host.Host, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 []host.Host
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []host.Host); ok {
+		r0 = rf(ctx, poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]pool.ReadPool)
+			r0 = ret.Get(0).([]host.Host)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -751,16 +845,16 @@ func (_m *FacadeInterface) GetReadPools(ctx datastore.Context) ([]pool.ReadPool,
 	return r0, r1
 }
 
-// GetResourcePool provides a mock function with given fields: ctx, poolID
-func (_m *FacadeInterface) GetResourcePool(ctx datastore.Context, poolID string) (*pool.ResourcePool, error) {
+// FindReadHostsInPool provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) FindReadHostsInPool(ctx datastore.Context, poolID string) ([]host.ReadHost, error) {
 	ret := _m.Called(ctx, poolID)
 
-	var r0 *pool.ResourcePool
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *pool.ResourcePool); ok {
+	var r0 []host.ReadHost
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []host.ReadHost); ok {
 		r0 = rf(ctx, poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*pool.ResourcePool)
+			r0 = ret.Get(0).([]host.ReadHost)
 		}
 	}
 
@@ -774,16 +868,16 @@ func (_m *FacadeInterface) GetResourcePool(ctx datastore.Context, poolID string)
 	return r0, r1
 }
 
-// GetResourcePools provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetResourcePools(ctx datastore.Context) ([]pool.ResourcePool, error) {
+// GetActiveHostIDs provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetActiveHostIDs(ctx datastore.Context) ([]string, error) {
 	ret := _m.Called(ctx)
 
-	var r0 []pool.ResourcePool
-	if rf, ok := ret.Get(0).(func(datastore.Context) []pool.ResourcePool); ok {
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(datastore.Context) []string); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]pool.ResourcePool)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
@@ -797,22 +891,22 @@ func (_m *FacadeInterface) GetResourcePools(ctx datastore.Context) ([]pool.Resou
 	return r0, r1
 }
 
-// GetService provides a mock function with given fields: ctx, id
-func (_m *FacadeInterface) GetService(ctx datastore.Context, id string) (*service.Service, error) {
-	ret := _m.Called(ctx, id)
+// GetAggregateServices provides a mock function with given fields: ctx, since, serviceids
+func (_m *FacadeInterface) GetAggregateServices(ctx datastore.Context, since time.Time, serviceids []string) ([]service.AggregateService, error) {
+	ret := _m.Called(ctx, since, serviceids)
 
-	var r0 *service.Service
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.Service); ok {
-		r0 = rf(ctx, id)
+	var r0 []service.AggregateService
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, []string) []service.AggregateService); ok {
+		r0 = rf(ctx, since, serviceids)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.Service)
+			r0 = ret.Get(0).([]service.AggregateService)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, []string) error); ok {
+		r1 = rf(ctx, since, serviceids)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -820,45 +914,22 @@ func (_m *FacadeInterface) GetService(ctx datastore.Context, id string) (*servic
 	return r0, r1
 }
 
-// GetServiceAddressAssignmentDetails provides a mock function with given fields: ctx, serviceID, children
-func (_m *FacadeInterface) GetServiceAddressAssignmentDetails(ctx datastore.Context, serviceID string, children bool) ([]service.IPAssignment, error) {
-	ret := _m.Called(ctx, serviceID, children)
+// GetAllPublicEndpoints provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetAllPublicEndpoints(ctx datastore.Context) ([]service.PublicEndpoint, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []service.IPAssignment
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.IPAssignment); ok {
-		r0 = rf(ctx, serviceID, children)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.IPAssignment)
-		}
-	}
-
-	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
-		r1 = rf(ctx, serviceID, children)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// GetServiceConfig provides a mock function with given fields: ctx, fileID
-func (_m *FacadeInterface) GetServiceConfig(ctx datastore.Context, fileID string) (*servicedefinition.ConfigFile, error) {
-	ret := _m.Called(ctx, fileID)
-
-	var r0 *servicedefinition.ConfigFile
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *servicedefinition.ConfigFile); ok {
-		r0 = rf(ctx, fileID)
+	var r0 []service.PublicEndpoint
+	if rf, ok := ret.Get(0).(func(datastore.Context) []service.PublicEndpoint); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*servicedefinition.ConfigFile)
+			r0 = ret.Get(0).([]service.PublicEndpoint)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, fileID)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -866,22 +937,22 @@ func (_m *FacadeInterface) GetServiceConfig(ctx datastore.Context, fileID string
 	return r0, r1
 }
 
-// GetServiceConfigs provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) GetServiceConfigs(ctx datastore.Context, serviceID string) ([]service.Config, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetEvaluatedService provides a mock function with given fields: ctx, servicedID, instanceID
+func (_m *FacadeInterface) GetEvaluatedService(ctx datastore.Context, servicedID string, instanceID int) (*service.Service, error) {
+	ret := _m.Called(ctx, servicedID, instanceID)
 
-	var r0 []service.Config
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.Config); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) *service.Service); ok {
+		r0 = rf(ctx, servicedID, instanceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Config)
+			r0 = ret.Get(0).(*service.Service)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, int) error); ok {
+		r1 = rf(ctx, servicedID, instanceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -889,22 +960,22 @@ func (_m *FacadeInterface) GetServiceConfigs(ctx datastore.Context, serviceID st
 	return r0, r1
 }
 
-// GetServiceDetails provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) GetServiceDetails(ctx datastore.Context, serviceID string) (*service.ServiceDetails, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetHealthChecksForService provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) GetHealthChecksForService(ctx datastore.Context, id string) (map[string]health.HealthCheck, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 *service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.ServiceDetails); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 map[string]health.HealthCheck
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[string]health.HealthCheck); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.ServiceDetails)
+			r0 = ret.Get(0).(map[string]health.HealthCheck)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -912,22 +983,22 @@ func (_m *FacadeInterface) GetServiceDetails(ctx datastore.Context, serviceID st
 	return r0, r1
 }
 
-// GetServiceDetailsAncestry provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) GetServiceDetailsAncestry(ctx datastore.Context, serviceID string) (*service.ServiceDetails, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetHost provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) GetHost(ctx datastore.Context, hostID string) (*host.Host, error) {
+	ret := _m.Called(ctx, hostID)
 
-	var r0 *service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.ServiceDetails); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 *host.Host
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *host.Host); ok {
+		r0 = rf(ctx, hostID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.ServiceDetails)
+			r0 = ret.Get(0).(*host.Host)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+		r1 = rf(ctx, hostID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -935,22 +1006,22 @@ func (_m *FacadeInterface) GetServiceDetailsAncestry(ctx datastore.Context, serv
 	return r0, r1
 }
 
-// GetServiceDetailsByParentID provides a mock function with given fields: ctx, serviceID, since
-func (_m *FacadeInterface) GetServiceDetailsByParentID(ctx datastore.Context, serviceID string, since time.Duration) ([]service.ServiceDetails, error) {
-	ret := _m.Called(ctx, serviceID, since)
+// GetHostInstances provides a mock function with given fields: ctx, since, hostid
+func (_m *FacadeInterface) GetHostInstances(ctx datastore.Context, since time.Time, hostid string) ([]service.Instance, error) {
+	ret := _m.Called(ctx, since, hostid)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, time.Duration) []service.ServiceDetails); ok {
-		r0 = rf(ctx, serviceID, since)
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, string) []service.Instance); ok {
+		r0 = rf(ctx, since, hostid)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).([]service.Instance)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, time.Duration) error); ok {
-		r1 = rf(ctx, serviceID, since)
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, since, hostid)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -958,22 +1029,22 @@ func (_m *FacadeInterface) GetServiceDetailsByParentID(ctx datastore.Context, se
 	return r0, r1
 }
 
-// GetServiceDetailsByTenantID provides a mock function with given fields: ctx, tenantID
-func (_m *FacadeInterface) GetServiceDetailsByTenantID(ctx datastore.Context, tenantID string) ([]service.ServiceDetails, error) {
-	ret := _m.Called(ctx, tenantID)
+// GetHostKey provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) GetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
+	ret := _m.Called(ctx, hostID)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.ServiceDetails); ok {
-		r0 = rf(ctx, tenantID)
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
+		r0 = rf(ctx, hostID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, tenantID)
+		r1 = rf(ctx, hostID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -981,22 +1052,22 @@ func (_m *FacadeInterface) GetServiceDetailsByTenantID(ctx datastore.Context, te
 	return r0, r1
 }
 
-// GetServiceExportedEndpoints provides a mock function with given fields: ctx, serviceID, children
-func (_m *FacadeInterface) GetServiceExportedEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.ExportedEndpoint, error) {
-	ret := _m.Called(ctx, serviceID, children)
+// GetHostStatuses provides a mock function with given fields: ctx, hostIDs, since
+func (_m *FacadeInterface) GetHostStatuses(ctx datastore.Context, hostIDs []string, since time.Time) ([]host.HostStatus, error) {
+	ret := _m.Called(ctx, hostIDs, since)
 
-	var r0 []service.ExportedEndpoint
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.ExportedEndpoint); ok {
-		r0 = rf(ctx, serviceID, children)
+	var r0 []host.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, time.Time) []host.HostStatus); ok {
+		r0 = rf(ctx, hostIDs, since)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ExportedEndpoint)
+			r0 = ret.Get(0).([]host.HostStatus)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
-		r1 = rf(ctx, serviceID, children)
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, time.Time) error); ok {
+		r1 = rf(ctx, hostIDs, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1004,22 +1075,22 @@ func (_m *FacadeInterface) GetServiceExportedEndpoints(ctx datastore.Context, se
 	return r0, r1
 }
 
-// GetServiceInstances provides a mock function with given fields: ctx, since, serviceid
-func (_m *FacadeInterface) GetServiceInstances(ctx datastore.Context, since time.Time, serviceid string) ([]service.Instance, error) {
-	ret := _m.Called(ctx, since, serviceid)
+// GetHosts provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetHosts(ctx datastore.Context) ([]host.Host, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []service.Instance
-	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, string) []service.Instance); ok {
-		r0 = rf(ctx, since, serviceid)
+	var r0 []host.Host
+	if rf, ok := ret.Get(0).(func(datastore.Context) []host.Host); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Instance)
+			r0 = ret.Get(0).([]host.Host)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, string) error); ok {
-		r1 = rf(ctx, since, serviceid)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1027,22 +1098,22 @@ func (_m *FacadeInterface) GetServiceInstances(ctx datastore.Context, since time
 	return r0, r1
 }
 
-// GetServiceMonitoringProfile provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) GetServiceMonitoringProfile(ctx datastore.Context, serviceID string) (*domain.MonitorProfile, error) {
-	ret := _m.Called(ctx, serviceID)
+// GetPoolIPs provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) GetPoolIPs(ctx datastore.Context, poolID string) (*pool.PoolIPs, error) {
+	ret := _m.Called(ctx, poolID)
 
-	var r0 *domain.MonitorProfile
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) *domain.MonitorProfile); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 *pool.PoolIPs
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *pool.PoolIPs); ok {
+		r0 = rf(ctx, poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.MonitorProfile)
+			r0 = ret.Get(0).(*pool.PoolIPs)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, serviceID)
+		r1 = rf(ctx, poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1050,22 +1121,22 @@ func (_m *FacadeInterface) GetServiceMonitoringProfile(ctx datastore.Context, se
 	return r0, r1
 }
 
-// GetServicePublicEndpoints provides a mock function with given fields: ctx, serviceID, children
-func (_m *FacadeInterface) GetServicePublicEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.PublicEndpoint, error) {
-	ret := _m.Called(ctx, serviceID, children)
+// GetReadHosts provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetReadHosts(ctx datastore.Context) ([]host.ReadHost, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []service.PublicEndpoint
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.PublicEndpoint); ok {
-		r0 = rf(ctx, serviceID, children)
+	var r0 []host.ReadHost
+	if rf, ok := ret.Get(0).(func(datastore.Context) []host.ReadHost); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.PublicEndpoint)
+			r0 = ret.Get(0).([]host.ReadHost)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
-		r1 = rf(ctx, serviceID, children)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1073,16 +1144,16 @@ func (_m *FacadeInterface) GetServicePublicEndpoints(ctx datastore.Context, serv
 	return r0, r1
 }
 
-// GetServiceTemplates provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetServiceTemplates(ctx datastore.Context) (map[string]servicetemplate.ServiceTemplate, error) {
+// GetReadPools provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error) {
 	ret := _m.Called(ctx)
 
-	var r0 map[string]servicetemplate.ServiceTemplate
-	if rf, ok := ret.Get(0).(func(datastore.Context) map[string]servicetemplate.ServiceTemplate); ok {
+	var r0 []pool.ReadPool
+	if rf, ok := ret.Get(0).(func(datastore.Context) []pool.ReadPool); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
+			r0 = ret.Get(0).([]pool.ReadPool)
 		}
 	}
 
@@ -1096,22 +1167,22 @@ func (_m *FacadeInterface) GetServiceTemplates(ctx datastore.Context) (map[strin
 	return r0, r1
 }
 
-// GetServices provides a mock function with given fields: ctx, request
-func (_m *FacadeInterface) GetServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
-	ret := _m.Called(ctx, request)
+// GetResourcePool provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) GetResourcePool(ctx datastore.Context, poolID string) (*pool.ResourcePool, error) {
+	ret := _m.Called(ctx, poolID)
 
-	var r0 []service.Service
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.EntityRequest) []service.Service); ok {
-		r0 = rf(ctx, request)
+	var r0 *pool.ResourcePool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *pool.ResourcePool); ok {
+		r0 = rf(ctx, poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Service)
+			r0 = ret.Get(0).(*pool.ResourcePool)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, dao.EntityRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1119,16 +1190,16 @@ func (_m *FacadeInterface) GetServices(ctx datastore.Context, request dao.Entity
 	return r0, r1
 }
 
-// GetServicesHealth provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error) {
+// GetResourcePools provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetResourcePools(ctx datastore.Context) ([]pool.ResourcePool, error) {
 	ret := _m.Called(ctx)
 
-	var r0 map[string]map[int]map[string]health.HealthStatus
-	if rf, ok := ret.Get(0).(func(datastore.Context) map[string]map[int]map[string]health.HealthStatus); ok {
+	var r0 []pool.ResourcePool
+	if rf, ok := ret.Get(0).(func(datastore.Context) []pool.ResourcePool); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]map[int]map[string]health.HealthStatus)
+			r0 = ret.Get(0).([]pool.ResourcePool)
 		}
 	}
 
@@ -1142,20 +1213,22 @@ func (_m *FacadeInterface) GetServicesHealth(ctx datastore.Context) (map[string]
 	return r0, r1
 }
 
-// GetSystemUser provides a mock function with given fields: ctx
-func (_m *FacadeInterface) GetSystemUser(ctx datastore.Context) (user.User, error) {
-	ret := _m.Called(ctx)
+// GetService provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) GetService(ctx datastore.Context, id string) (*service.Service, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 user.User
-	if rf, ok := ret.Get(0).(func(datastore.Context) user.User); ok {
-		r0 = rf(ctx)
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.Service); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(user.User)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Service)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1163,22 +1236,22 @@ func (_m *FacadeInterface) GetSystemUser(ctx datastore.Context) (user.User, erro
 	return r0, r1
 }
 
-// GetTaggedServices provides a mock function with given fields: ctx, request
-func (_m *FacadeInterface) GetTaggedServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
-	ret := _m.Called(ctx, request)
+// GetServiceAddressAssignmentDetails provides a mock function with given fields: ctx, serviceID, children
+func (_m *FacadeInterface) GetServiceAddressAssignmentDetails(ctx datastore.Context, serviceID string, children bool) ([]service.IPAssignment, error) {
+	ret := _m.Called(ctx, serviceID, children)
 
-	var r0 []service.Service
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.EntityRequest) []service.Service); ok {
-		r0 = rf(ctx, request)
+	var r0 []service.IPAssignment
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.IPAssignment); ok {
+		r0 = rf(ctx, serviceID, children)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Service)
+			r0 = ret.Get(0).([]service.IPAssignment)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, dao.EntityRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
+		r1 = rf(ctx, serviceID, children)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1186,15 +1259,40 @@ func (_m *FacadeInterface) GetTaggedServices(ctx datastore.Context, request dao.
 	return r0, r1
 }
 
-// GetTenantID provides a mock function with given fields: ctx, serviceID
-func (_m *FacadeInterface) GetTenantID(ctx datastore.Context, serviceID string) (string, error) {
+// GetServiceConfig provides a mock function with given fields: ctx, fileID
+func (_m *FacadeInterface) GetServiceConfig(ctx datastore.Context, fileID string) (*servicedefinition.ConfigFile, error) {
+	ret := _m.Called(ctx, fileID)
+
+	var r0 *servicedefinition.ConfigFile
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *servicedefinition.ConfigFile); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicedefinition.ConfigFile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceConfigs provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceConfigs(ctx datastore.Context, serviceID string) ([]service.Config, error) {
 	ret := _m.Called(ctx, serviceID)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+	var r0 []service.Config
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.Config); ok {
 		r0 = rf(ctx, serviceID)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Config)
+		}
 	}
 
 	var r1 error
@@ -1207,20 +1305,22 @@ func (_m *FacadeInterface) GetTenantID(ctx datastore.Context, serviceID string)
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: ctx, userName
-func (_m *FacadeInterface) GetUser(ctx datastore.Context, userName string) (user.User, error) {
-	ret := _m.Called(ctx, userName)
+// GetServiceDetails provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceDetails(ctx datastore.Context, serviceID string) (*service.ServiceDetails, error) {
+	ret := _m.Called(ctx, serviceID)
 
-	var r0 user.User
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) user.User); ok {
-		r0 = rf(ctx, userName)
+	var r0 *service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.ServiceDetails); ok {
+		r0 = rf(ctx, serviceID)
 	} else {
-		r0 = ret.Get(0).(user.User)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceDetails)
+		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, userName)
+		r1 = rf(ctx, serviceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1228,20 +1328,808 @@ func (_m *FacadeInterface) GetUser(ctx datastore.Context, userName string) (user
 	return r0, r1
 }
 
-// HasIP provides a mock function with given fields: ctx, poolID, ipAddr
-func (_m *FacadeInterface) HasIP(ctx datastore.Context, poolID string, ipAddr string) (bool, error) {
-	ret := _m.Called(ctx, poolID, ipAddr)
+// GetServiceDetailsAncestry provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceDetailsAncestry(ctx datastore.Context, serviceID string) (*service.ServiceDetails, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 *service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.ServiceDetails); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDetailsByParentID provides a mock function with given fields: ctx, serviceID, since
+func (_m *FacadeInterface) GetServiceDetailsByParentID(ctx datastore.Context, serviceID string, since time.Duration) ([]service.ServiceDetails, error) {
+	ret := _m.Called(ctx, serviceID, since)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, time.Duration) []service.ServiceDetails); ok {
+		r0 = rf(ctx, serviceID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, serviceID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDetailsByTenantID provides a mock function with given fields: ctx, tenantID
+func (_m *FacadeInterface) GetServiceDetailsByTenantID(ctx datastore.Context, tenantID string) ([]service.ServiceDetails, error) {
+	ret := _m.Called(ctx, tenantID)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.ServiceDetails); ok {
+		r0 = rf(ctx, tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceExportedEndpoints provides a mock function with given fields: ctx, serviceID, children
+func (_m *FacadeInterface) GetServiceExportedEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.ExportedEndpoint, error) {
+	ret := _m.Called(ctx, serviceID, children)
+
+	var r0 []service.ExportedEndpoint
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.ExportedEndpoint); ok {
+		r0 = rf(ctx, serviceID, children)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ExportedEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
+		r1 = rf(ctx, serviceID, children)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceInstances provides a mock function with given fields: ctx, since, serviceid
+func (_m *FacadeInterface) GetServiceInstances(ctx datastore.Context, since time.Time, serviceid string) ([]service.Instance, error) {
+	ret := _m.Called(ctx, since, serviceid)
+
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Time, string) []service.Instance); ok {
+		r0 = rf(ctx, since, serviceid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, since, serviceid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceInstanceHistory provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceInstanceHistory(ctx datastore.Context, serviceID string) ([]service.InstanceHistory, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 []service.InstanceHistory
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []service.InstanceHistory); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.InstanceHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceLogs provides a mock function with given fields: ctx, query
+func (_m *FacadeInterface) GetServiceLogs(ctx datastore.Context, query service.LogQuery) ([]service.LogMessage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []service.LogMessage
+	if rf, ok := ret.Get(0).(func(datastore.Context, service.LogQuery) []service.LogMessage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.LogMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, service.LogQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceMonitoringProfile provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceMonitoringProfile(ctx datastore.Context, serviceID string) (*domain.MonitorProfile, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 *domain.MonitorProfile
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *domain.MonitorProfile); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MonitorProfile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServicePublicEndpoints provides a mock function with given fields: ctx, serviceID, children
+func (_m *FacadeInterface) GetServicePublicEndpoints(ctx datastore.Context, serviceID string, children bool) ([]service.PublicEndpoint, error) {
+	ret := _m.Called(ctx, serviceID, children)
+
+	var r0 []service.PublicEndpoint
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) []service.PublicEndpoint); ok {
+		r0 = rf(ctx, serviceID, children)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.PublicEndpoint)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
+		r1 = rf(ctx, serviceID, children)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceTemplates provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetServiceTemplates(ctx datastore.Context) (map[string]servicetemplate.ServiceTemplate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]servicetemplate.ServiceTemplate
+	if rf, ok := ret.Get(0).(func(datastore.Context) map[string]servicetemplate.ServiceTemplate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchema provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) GetSchema(ctx datastore.Context, name string) (*validation.Schema, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *validation.Schema
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *validation.Schema); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*validation.Schema)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServices provides a mock function with given fields: ctx, request
+func (_m *FacadeInterface) GetServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.EntityRequest) []service.Service); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, dao.EntityRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServicesHealth provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context) map[string]map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealth provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetServiceHealth(ctx datastore.Context, serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSystemUser provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetSystemUser(ctx datastore.Context) (user.User, error) {
+	ret := _m.Called(ctx)
+
+	var r0 user.User
+	if rf, ok := ret.Get(0).(func(datastore.Context) user.User); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(user.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTaggedServices provides a mock function with given fields: ctx, request
+func (_m *FacadeInterface) GetTaggedServices(ctx datastore.Context, request dao.EntityRequest) ([]service.Service, error) {
+	ret := _m.Called(ctx, request)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.EntityRequest) []service.Service); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, dao.EntityRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTenantID provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) GetTenantID(ctx datastore.Context, serviceID string) (string, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, userName
+func (_m *FacadeInterface) GetUser(ctx datastore.Context, userName string) (user.User, error) {
+	ret := _m.Called(ctx, userName)
+
+	var r0 user.User
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) user.User); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		r0 = ret.Get(0).(user.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasIP provides a mock function with given fields: ctx, poolID, ipAddr
+func (_m *FacadeInterface) HasIP(ctx datastore.Context, poolID string, ipAddr string) (bool, error) {
+	ret := _m.Called(ctx, poolID, ipAddr)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) bool); ok {
+		r0 = rf(ctx, poolID, ipAddr)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, poolID, ipAddr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HostIsAuthenticated provides a mock function with given fields: ctx, hostid
+func (_m *FacadeInterface) HostIsAuthenticated(ctx datastore.Context, hostid string) (bool, error) {
+	ret := _m.Called(ctx, hostid)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) bool); ok {
+		r0 = rf(ctx, hostid)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTenants provides a mock function with given fields: _a0
+func (_m *FacadeInterface) ListTenants(_a0 datastore.Context) ([]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(datastore.Context) []string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrateServices provides a mock function with given fields: ctx, request
+func (_m *FacadeInterface) MigrateServices(ctx datastore.Context, request dao.ServiceMigrationRequest) error {
+	ret := _m.Called(ctx, request)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ServiceMigrationRequest) error); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PredictStorageAvailability provides a mock function with given fields: ctx, lookahead
+func (_m *FacadeInterface) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]float64, error) {
+	ret := _m.Called(ctx, lookahead)
+
+	var r0 map[string]float64
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Duration) map[string]float64); ok {
+		r0 = rf(ctx, lookahead)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]float64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Duration) error); ok {
+		r1 = rf(ctx, lookahead)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegisterHostKeys provides a mock function with given fields: ctx, entity, keys, prompt
+func (_m *FacadeInterface) RegisterHostKeys(ctx datastore.Context, entity *host.Host, nat utils.URL, keys []byte, prompt bool) error {
+	ret := _m.Called(ctx, entity, nat, keys, prompt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *host.Host, utils.URL, []byte, bool) error); ok {
+		r0 = rf(ctx, entity, nat, keys, prompt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReloadLogstashConfig provides a mock function with given fields: ctx
+func (_m *FacadeInterface) ReloadLogstashConfig(ctx datastore.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveHost provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) RemoveHost(ctx datastore.Context, hostID string) error {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveHostExpiration provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) RemoveHostExpiration(ctx datastore.Context, hostID string) {
+	_m.Called(ctx, hostID)
+}
+
+// RemovePublicEndpointPort provides a mock function with given fields: ctx, serviceid, endpointName, portAddr
+func (_m *FacadeInterface) RemovePublicEndpointPort(ctx datastore.Context, serviceid string, endpointName string, portAddr string) error {
+	ret := _m.Called(ctx, serviceid, endpointName, portAddr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, portAddr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePublicEndpointVHost provides a mock function with given fields: ctx, serviceid, endpointName, vhost
+func (_m *FacadeInterface) RemovePublicEndpointVHost(ctx datastore.Context, serviceid string, endpointName string, vhost string) error {
+	ret := _m.Called(ctx, serviceid, endpointName, vhost)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, vhost)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveResourcePool provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) RemoveResourcePool(ctx datastore.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveService provides a mock function with given fields: ctx, id
+func (_m *FacadeInterface) RemoveService(ctx datastore.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveServiceTemplate provides a mock function with given fields: ctx, templateID
+func (_m *FacadeInterface) RemoveServiceTemplate(ctx datastore.Context, templateID string) error {
+	ret := _m.Called(ctx, templateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, templateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveUser provides a mock function with given fields: ctx, userName
+func (_m *FacadeInterface) RemoveUser(ctx datastore.Context, userName string) error {
+	ret := _m.Called(ctx, userName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportHealthStatus provides a mock function with given fields: key, value, expires
+func (_m *FacadeInterface) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) {
+	_m.Called(key, value, expires)
+}
+
+// ReportInstanceDead provides a mock function with given fields: serviceID, instanceID
+func (_m *FacadeInterface) ReportInstanceDead(serviceID string, instanceID int) {
+	_m.Called(serviceID, instanceID)
+}
+
+// ResetHostKey provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) ResetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleService provides a mock function with given fields: ctx, serviceID, autoLaunch, synchronous, desiredState
+func (_m *FacadeInterface) ScheduleServices(ctx datastore.Context, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, emergency bool) (int, error) {
+	ret := _m.Called(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, bool, bool, service.DesiredState, bool) int); ok {
+		r0 = rf(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, bool, bool, service.DesiredState, bool) error); ok {
+		r1 = rf(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetHostExpiration provides a mock function with given fields: ctx, hostID, expiration
+func (_m *FacadeInterface) SetHostExpiration(ctx datastore.Context, hostID string, expiration int64) {
+	_m.Called(ctx, hostID, expiration)
+}
+
+//StartService provides a mock function with given fields: ctx, ScheduleServiceRequest
+func (_m *FacadeInterface) StartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(0)
+	}
+
+	return r0, r1
+}
+
+//StopService provides a mock function with given fields: ctx, ScheduleServiceRequest
+func (_m *FacadeInterface) StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(0)
+	}
+
+	return r0, r1
+}
+
+//PauseService provides a mock function with given fields: ctx, ScheduleServiceRequest
+func (_m *FacadeInterface) PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(0)
+	}
+
+	return r0, r1
+}
+
+//RestartService provides a mock function with given fields: ctx, ScheduleServiceRequest
+func (_m *FacadeInterface) RestartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+
+	ret := _m.Called(ctx, request)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
+		r0 = rf(ctx, request)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(0)
+	}
+
+	return r0, r1
+}
+
+// StartServices provides a mock function with given fields: ctx, serviceIDs, synchronous
+func (_m *FacadeInterface) StartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(ctx, serviceIDs, synchronous)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) bool); ok {
-		r0 = rf(ctx, poolID, ipAddr)
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, bool) int); ok {
+		r0 = rf(ctx, serviceIDs, synchronous)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
-		r1 = rf(ctx, poolID, ipAddr)
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, bool) error); ok {
+		r1 = rf(ctx, serviceIDs, synchronous)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1249,20 +2137,20 @@ func (_m *FacadeInterface) HasIP(ctx datastore.Context, poolID string, ipAddr st
 	return r0, r1
 }
 
-// HostIsAuthenticated provides a mock function with given fields: ctx, hostid
-func (_m *FacadeInterface) HostIsAuthenticated(ctx datastore.Context, hostid string) (bool, error) {
-	ret := _m.Called(ctx, hostid)
+// RestartServices provides a mock function with given fields: ctx, serviceIDs, synchronous
+func (_m *FacadeInterface) RestartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(ctx, serviceIDs, synchronous)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) bool); ok {
-		r0 = rf(ctx, hostid)
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, bool) int); ok {
+		r0 = rf(ctx, serviceIDs, synchronous)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, hostid)
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, bool) error); ok {
+		r1 = rf(ctx, serviceIDs, synchronous)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1270,22 +2158,20 @@ func (_m *FacadeInterface) HostIsAuthenticated(ctx datastore.Context, hostid str
 	return r0, r1
 }
 
-// ListTenants provides a mock function with given fields: _a0
-func (_m *FacadeInterface) ListTenants(_a0 datastore.Context) ([]string, error) {
-	ret := _m.Called(_a0)
+// StopServices provides a mock function with given fields: ctx, serviceIDs, synchronous
+func (_m *FacadeInterface) StopServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(ctx, serviceIDs, synchronous)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(datastore.Context) []string); ok {
-		r0 = rf(_a0)
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, []string, bool) int); ok {
+		r0 = rf(ctx, serviceIDs, synchronous)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(datastore.Context, []string, bool) error); ok {
+		r1 = rf(ctx, serviceIDs, synchronous)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1293,13 +2179,13 @@ func (_m *FacadeInterface) ListTenants(_a0 datastore.Context) ([]string, error)
 	return r0, r1
 }
 
-// MigrateServices provides a mock function with given fields: ctx, request
-func (_m *FacadeInterface) MigrateServices(ctx datastore.Context, request dao.ServiceMigrationRequest) error {
-	ret := _m.Called(ctx, request)
+// SetHostMaintenance provides a mock function with given fields: ctx, hostID, enabled
+func (_m *FacadeInterface) SetHostMaintenance(ctx datastore.Context, hostID string, enabled bool) error {
+	ret := _m.Called(ctx, hostID, enabled)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ServiceMigrationRequest) error); ok {
-		r0 = rf(ctx, request)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) error); ok {
+		r0 = rf(ctx, hostID, enabled)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1307,36 +2193,27 @@ func (_m *FacadeInterface) MigrateServices(ctx datastore.Context, request dao.Se
 	return r0
 }
 
-// PredictStorageAvailability provides a mock function with given fields: ctx, lookahead
-func (_m *FacadeInterface) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]float64, error) {
-	ret := _m.Called(ctx, lookahead)
-
-	var r0 map[string]float64
-	if rf, ok := ret.Get(0).(func(datastore.Context, time.Duration) map[string]float64); ok {
-		r0 = rf(ctx, lookahead)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]float64)
-		}
-	}
+// SyncServiceRegistry provides a mock function with given fields: ctx, svc
+func (_m *FacadeInterface) SyncServiceRegistry(ctx datastore.Context, svc *service.Service) error {
+	ret := _m.Called(ctx, svc)
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, time.Duration) error); ok {
-		r1 = rf(ctx, lookahead)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *service.Service) error); ok {
+		r0 = rf(ctx, svc)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// RegisterHostKeys provides a mock function with given fields: ctx, entity, keys, prompt
-func (_m *FacadeInterface) RegisterHostKeys(ctx datastore.Context, entity *host.Host, nat utils.URL, keys []byte, prompt bool) error {
-	ret := _m.Called(ctx, entity, nat, keys, prompt)
+// UpdateCalendar provides a mock function with given fields: ctx, entity
+func (_m *FacadeInterface) UpdateCalendar(ctx datastore.Context, entity *calendar.Calendar) error {
+	ret := _m.Called(ctx, entity)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, *host.Host, utils.URL, []byte, bool) error); ok {
-		r0 = rf(ctx, entity, nat, keys, prompt)
+	if rf, ok := ret.Get(0).(func(datastore.Context, *calendar.Calendar) error); ok {
+		r0 = rf(ctx, entity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1344,13 +2221,13 @@ func (_m *FacadeInterface) RegisterHostKeys(ctx datastore.Context, entity *host.
 	return r0
 }
 
-// ReloadLogstashConfig provides a mock function with given fields: ctx
-func (_m *FacadeInterface) ReloadLogstashConfig(ctx datastore.Context) error {
-	ret := _m.Called(ctx)
+// UpdateHost provides a mock function with given fields: ctx, entity
+func (_m *FacadeInterface) UpdateHost(ctx datastore.Context, entity *host.Host) error {
+	ret := _m.Called(ctx, entity)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(datastore.Context, *host.Host) error); ok {
+		r0 = rf(ctx, entity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1358,13 +2235,13 @@ func (_m *FacadeInterface) ReloadLogstashConfig(ctx datastore.Context) error {
 	return r0
 }
 
-// RemoveHost provides a mock function with given fields: ctx, hostID
-func (_m *FacadeInterface) RemoveHost(ctx datastore.Context, hostID string) error {
-	ret := _m.Called(ctx, hostID)
+// UpdateResourcePool provides a mock function with given fields: ctx, entity
+func (_m *FacadeInterface) UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error {
+	ret := _m.Called(ctx, entity)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, hostID)
+	if rf, ok := ret.Get(0).(func(datastore.Context, *pool.ResourcePool) error); ok {
+		r0 = rf(ctx, entity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1372,18 +2249,13 @@ func (_m *FacadeInterface) RemoveHost(ctx datastore.Context, hostID string) erro
 	return r0
 }
 
-// RemoveHostExpiration provides a mock function with given fields: ctx, hostID
-func (_m *FacadeInterface) RemoveHostExpiration(ctx datastore.Context, hostID string) {
-	_m.Called(ctx, hostID)
-}
-
-// RemovePublicEndpointPort provides a mock function with given fields: ctx, serviceid, endpointName, portAddr
-func (_m *FacadeInterface) RemovePublicEndpointPort(ctx datastore.Context, serviceid string, endpointName string, portAddr string) error {
-	ret := _m.Called(ctx, serviceid, endpointName, portAddr)
+// UpdateService provides a mock function with given fields: ctx, svc
+func (_m *FacadeInterface) UpdateService(ctx datastore.Context, svc service.Service) error {
+	ret := _m.Called(ctx, svc)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
-		r0 = rf(ctx, serviceid, endpointName, portAddr)
+	if rf, ok := ret.Get(0).(func(datastore.Context, service.Service) error); ok {
+		r0 = rf(ctx, svc)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1391,13 +2263,13 @@ func (_m *FacadeInterface) RemovePublicEndpointPort(ctx datastore.Context, servi
 	return r0
 }
 
-// RemovePublicEndpointVHost provides a mock function with given fields: ctx, serviceid, endpointName, vhost
-func (_m *FacadeInterface) RemovePublicEndpointVHost(ctx datastore.Context, serviceid string, endpointName string, vhost string) error {
-	ret := _m.Called(ctx, serviceid, endpointName, vhost)
+// UpdateServiceCache provides a mock function with given fields: ctx
+func (_m *FacadeInterface) UpdateServiceCache(ctx datastore.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) error); ok {
-		r0 = rf(ctx, serviceid, endpointName, vhost)
+	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1405,13 +2277,13 @@ func (_m *FacadeInterface) RemovePublicEndpointVHost(ctx datastore.Context, serv
 	return r0
 }
 
-// RemoveResourcePool provides a mock function with given fields: ctx, id
-func (_m *FacadeInterface) RemoveResourcePool(ctx datastore.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// UpdateServiceConfig provides a mock function with given fields: ctx, fileID, conf
+func (_m *FacadeInterface) UpdateServiceConfig(ctx datastore.Context, fileID string, conf servicedefinition.ConfigFile) error {
+	ret := _m.Called(ctx, fileID, conf)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, servicedefinition.ConfigFile) error); ok {
+		r0 = rf(ctx, fileID, conf)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1419,13 +2291,13 @@ func (_m *FacadeInterface) RemoveResourcePool(ctx datastore.Context, id string)
 	return r0
 }
 
-// RemoveService provides a mock function with given fields: ctx, id
-func (_m *FacadeInterface) RemoveService(ctx datastore.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// UpdateServiceTemplate provides a mock function with given fields: ctx, template, reloadLogstashConfig
+func (_m *FacadeInterface) UpdateServiceTemplate(ctx datastore.Context, template servicetemplate.ServiceTemplate, reloadLogstashConfig bool) error {
+	ret := _m.Called(ctx, template, reloadLogstashConfig)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(datastore.Context, servicetemplate.ServiceTemplate, bool) error); ok {
+		r0 = rf(ctx, template, reloadLogstashConfig)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1433,13 +2305,13 @@ func (_m *FacadeInterface) RemoveService(ctx datastore.Context, id string) error
 	return r0
 }
 
-// RemoveServiceTemplate provides a mock function with given fields: ctx, templateID
-func (_m *FacadeInterface) RemoveServiceTemplate(ctx datastore.Context, templateID string) error {
-	ret := _m.Called(ctx, templateID)
+// UpdateUser provides a mock function with given fields: ctx, u
+func (_m *FacadeInterface) UpdateUser(ctx datastore.Context, u user.User) error {
+	ret := _m.Called(ctx, u)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, templateID)
+	if rf, ok := ret.Get(0).(func(datastore.Context, user.User) error); ok {
+		r0 = rf(ctx, u)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1447,46 +2319,57 @@ func (_m *FacadeInterface) RemoveServiceTemplate(ctx datastore.Context, template
 	return r0
 }
 
-// RemoveUser provides a mock function with given fields: ctx, userName
-func (_m *FacadeInterface) RemoveUser(ctx datastore.Context, userName string) error {
-	ret := _m.Called(ctx, userName)
+// ValidateCredentials provides a mock function with given fields: ctx, u
+func (_m *FacadeInterface) ValidateCredentials(ctx datastore.Context, u user.User) (bool, error) {
+	ret := _m.Called(ctx, u)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
-		r0 = rf(ctx, userName)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, user.User) bool); ok {
+		r0 = rf(ctx, u)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(bool)
 	}
 
-	return r0
-}
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, user.User) error); ok {
+		r1 = rf(ctx, u)
+	} else {
+		r1 = ret.Error(1)
+	}
 
-// ReportHealthStatus provides a mock function with given fields: key, value, expires
-func (_m *FacadeInterface) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) {
-	_m.Called(key, value, expires)
+	return r0, r1
 }
 
-// ReportInstanceDead provides a mock function with given fields: serviceID, instanceID
-func (_m *FacadeInterface) ReportInstanceDead(serviceID string, instanceID int) {
-	_m.Called(serviceID, instanceID)
+// WaitService provides a mock function with given fields: ctx, dstate, timeout, recursive, serviceIDs
+func (_m *FacadeInterface) WaitService(ctx datastore.Context, dstate service.DesiredState, timeout time.Duration, recursive bool, serviceIDs ...string) error {
+	ret := _m.Called(ctx, dstate, timeout, recursive, serviceIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, service.DesiredState, time.Duration, bool, ...string) error); ok {
+		r0 = rf(ctx, dstate, timeout, recursive, serviceIDs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
-// ResetHostKey provides a mock function with given fields: ctx, hostID
-func (_m *FacadeInterface) ResetHostKey(ctx datastore.Context, hostID string) ([]byte, error) {
-	ret := _m.Called(ctx, hostID)
+// WaitServiceStateEvents provides a mock function with given fields: ctx, serviceID, since, timeout, cancel
+func (_m *FacadeInterface) WaitServiceStateEvents(ctx datastore.Context, serviceID string, since uint64, timeout time.Duration, cancel <-chan struct{}) (*service.StateEvents, error) {
+	ret := _m.Called(ctx, serviceID, since, timeout, cancel)
 
-	var r0 []byte
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
-		r0 = rf(ctx, hostID)
+	var r0 *service.StateEvents
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, uint64, time.Duration, <-chan struct{}) *service.StateEvents); ok {
+		r0 = rf(ctx, serviceID, since, timeout, cancel)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).(*service.StateEvents)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r1 = rf(ctx, hostID)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, uint64, time.Duration, <-chan struct{}) error); ok {
+		r1 = rf(ctx, serviceID, since, timeout, cancel)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1494,20 +2377,21 @@ func (_m *FacadeInterface) ResetHostKey(ctx datastore.Context, hostID string) ([
 	return r0, r1
 }
 
-// ScheduleService provides a mock function with given fields: ctx, serviceID, autoLaunch, synchronous, desiredState
-func (_m *FacadeInterface) ScheduleServices(ctx datastore.Context, serviceIDs []string, autoLaunch bool, synchronous bool, desiredState service.DesiredState, emergency bool) (int, error) {
-	ret := _m.Called(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+func (_m *FacadeInterface) QueryServiceDetails(ctx datastore.Context, query service.Query) ([]service.ServiceDetails, error) {
+	ret := _m.Called(ctx, query)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, []string, bool, bool, service.DesiredState, bool) int); ok {
-		r0 = rf(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(datastore.Context, service.Query) []service.ServiceDetails); ok {
+		r0 = rf(ctx, query)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, []string, bool, bool, service.DesiredState, bool) error); ok {
-		r1 = rf(ctx, serviceIDs, autoLaunch, synchronous, desiredState, emergency)
+	if rf, ok := ret.Get(1).(func(datastore.Context, service.Query) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1515,106 +2399,132 @@ func (_m *FacadeInterface) ScheduleServices(ctx datastore.Context, serviceIDs []
 	return r0, r1
 }
 
-// SetHostExpiration provides a mock function with given fields: ctx, hostID, expiration
-func (_m *FacadeInterface) SetHostExpiration(ctx datastore.Context, hostID string, expiration int64) {
-	_m.Called(ctx, hostID, expiration)
-}
-
-//StartService provides a mock function with given fields: ctx, ScheduleServiceRequest
-func (_m *FacadeInterface) StartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+func (_m *FacadeInterface) GetServiceNamePath(ctx datastore.Context, serviceID string) (string, string, error) {
+	ret := _m.Called(ctx, serviceID)
 
-	ret := _m.Called(ctx, request)
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(string)
+		}
+	}
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
-		r0 = rf(ctx, request)
+	var r1 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+		r1 = rf(ctx, serviceID)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r1 = ret.Get(0).(string)
+		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
-		r1 = rf(ctx, request)
+	var r2 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r2 = rf(ctx, serviceID)
 	} else {
-		r1 = ret.Error(0)
+		r2 = ret.Error(1)
 	}
 
-	return r0, r1
+	return r0, r1, r2
 }
 
-//StopService provides a mock function with given fields: ctx, ScheduleServiceRequest
-func (_m *FacadeInterface) StopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
-
-	ret := _m.Called(ctx, request)
+// GetSettings provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetSettings(ctx datastore.Context) ([]setting.Setting, error) {
+	ret := _m.Called(ctx)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
-		r0 = rf(ctx, request)
+	var r0 []setting.Setting
+	if rf, ok := ret.Get(0).(func(datastore.Context) []setting.Setting); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]setting.Setting)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
-		r1 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
 
-//PauseService provides a mock function with given fields: ctx, ScheduleServiceRequest
-func (_m *FacadeInterface) PauseService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
-
-	ret := _m.Called(ctx, request)
+// SetSetting provides a mock function with given fields: ctx, name, scope, scopeID, value
+func (_m *FacadeInterface) SetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID string, value string) error {
+	ret := _m.Called(ctx, name, scope, scopeID, value)
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
-		r0 = rf(ctx, request)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, setting.Scope, string, string) error); ok {
+		r0 = rf(ctx, name, scope, scopeID, value)
 	} else {
-		r0 = ret.Get(0).(int)
+		r0 = ret.Error(0)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
-		r1 = rf(ctx, request)
+	return r0
+}
+
+// UnsetSetting provides a mock function with given fields: ctx, name, scope, scopeID
+func (_m *FacadeInterface) UnsetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID string) error {
+	ret := _m.Called(ctx, name, scope, scopeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, setting.Scope, string) error); ok {
+		r0 = rf(ctx, name, scope, scopeID)
 	} else {
-		r1 = ret.Error(0)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-//RestartService provides a mock function with given fields: ctx, ScheduleServiceRequest
-func (_m *FacadeInterface) RestartService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
+// SyncSettings provides a mock function with given fields: ctx
+func (_m *FacadeInterface) SyncSettings(ctx datastore.Context) error {
+	ret := _m.Called(ctx)
 
-	ret := _m.Called(ctx, request)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
 
-	var r0 int
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) int); ok {
-		r0 = rf(ctx, request)
+	return r0
+}
+
+// GetPins provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetPins(ctx datastore.Context) ([]pin.Pin, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []pin.Pin
+	if rf, ok := ret.Get(0).(func(datastore.Context) []pin.Pin); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pin.Pin)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, dao.ScheduleServiceRequest) error); ok {
-		r1 = rf(ctx, request)
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
-		r1 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
 
-// SyncServiceRegistry provides a mock function with given fields: ctx, svc
-func (_m *FacadeInterface) SyncServiceRegistry(ctx datastore.Context, svc *service.Service) error {
-	ret := _m.Called(ctx, svc)
+// PinSnapshot provides a mock function with given fields: ctx, snapshotID, reason
+func (_m *FacadeInterface) PinSnapshot(ctx datastore.Context, snapshotID string, reason string) error {
+	ret := _m.Called(ctx, snapshotID, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, *service.Service) error); ok {
-		r0 = rf(ctx, svc)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, snapshotID, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1622,13 +2532,13 @@ func (_m *FacadeInterface) SyncServiceRegistry(ctx datastore.Context, svc *servi
 	return r0
 }
 
-// UpdateHost provides a mock function with given fields: ctx, entity
-func (_m *FacadeInterface) UpdateHost(ctx datastore.Context, entity *host.Host) error {
-	ret := _m.Called(ctx, entity)
+// PinImage provides a mock function with given fields: ctx, image, reason
+func (_m *FacadeInterface) PinImage(ctx datastore.Context, image string, reason string) error {
+	ret := _m.Called(ctx, image, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, *host.Host) error); ok {
-		r0 = rf(ctx, entity)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, image, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1636,13 +2546,13 @@ func (_m *FacadeInterface) UpdateHost(ctx datastore.Context, entity *host.Host)
 	return r0
 }
 
-// UpdateResourcePool provides a mock function with given fields: ctx, entity
-func (_m *FacadeInterface) UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error {
-	ret := _m.Called(ctx, entity)
+// Unpin provides a mock function with given fields: ctx, kind, target
+func (_m *FacadeInterface) Unpin(ctx datastore.Context, kind pin.Kind, target string) error {
+	ret := _m.Called(ctx, kind, target)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, *pool.ResourcePool) error); ok {
-		r0 = rf(ctx, entity)
+	if rf, ok := ret.Get(0).(func(datastore.Context, pin.Kind, string) error); ok {
+		r0 = rf(ctx, kind, target)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1650,13 +2560,34 @@ func (_m *FacadeInterface) UpdateResourcePool(ctx datastore.Context, entity *poo
 	return r0
 }
 
-// UpdateService provides a mock function with given fields: ctx, svc
-func (_m *FacadeInterface) UpdateService(ctx datastore.Context, svc service.Service) error {
-	ret := _m.Called(ctx, svc)
+// SnapshotPinned provides a mock function with given fields: ctx, snapshotID
+func (_m *FacadeInterface) SnapshotPinned(ctx datastore.Context, snapshotID string) (bool, error) {
+	ret := _m.Called(ctx, snapshotID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) bool); ok {
+		r0 = rf(ctx, snapshotID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, snapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckInteractiveAccess provides a mock function with given fields: ctx, serviceID, poolID
+func (_m *FacadeInterface) CheckInteractiveAccess(ctx datastore.Context, serviceID string, poolID string) error {
+	ret := _m.Called(ctx, serviceID, poolID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, service.Service) error); ok {
-		r0 = rf(ctx, svc)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, serviceID, poolID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1664,41 +2595,59 @@ func (_m *FacadeInterface) UpdateService(ctx datastore.Context, svc service.Serv
 	return r0
 }
 
-// UpdateServiceCache provides a mock function with given fields: ctx
-func (_m *FacadeInterface) UpdateServiceCache(ctx datastore.Context) error {
+// GetDelegateProfiles provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetDelegateProfiles(ctx datastore.Context) ([]delegateprofile.Profile, error) {
 	ret := _m.Called(ctx)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
+	var r0 []delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(datastore.Context) []delegateprofile.Profile); ok {
 		r0 = rf(ctx)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.Profile)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpdateServiceConfig provides a mock function with given fields: ctx, fileID, conf
-func (_m *FacadeInterface) UpdateServiceConfig(ctx datastore.Context, fileID string, conf servicedefinition.ConfigFile) error {
-	ret := _m.Called(ctx, fileID, conf)
+// GetDelegateProfile provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) GetDelegateProfile(ctx datastore.Context, name string) (*delegateprofile.Profile, error) {
+	ret := _m.Called(ctx, name)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, string, servicedefinition.ConfigFile) error); ok {
-		r0 = rf(ctx, fileID, conf)
+	var r0 *delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *delegateprofile.Profile); ok {
+		r0 = rf(ctx, name)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delegateprofile.Profile)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UpdateServiceTemplate provides a mock function with given fields: ctx, template, reloadLogstashConfig
-func (_m *FacadeInterface) UpdateServiceTemplate(ctx datastore.Context, template servicetemplate.ServiceTemplate, reloadLogstashConfig bool) error {
-	ret := _m.Called(ctx, template, reloadLogstashConfig)
+// AddDelegateProfile provides a mock function with given fields: ctx, name, description
+func (_m *FacadeInterface) AddDelegateProfile(ctx datastore.Context, name string, description string) error {
+	ret := _m.Called(ctx, name, description)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, servicetemplate.ServiceTemplate, bool) error); ok {
-		r0 = rf(ctx, template, reloadLogstashConfig)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, name, description)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1706,13 +2655,13 @@ func (_m *FacadeInterface) UpdateServiceTemplate(ctx datastore.Context, template
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: ctx, u
-func (_m *FacadeInterface) UpdateUser(ctx datastore.Context, u user.User) error {
-	ret := _m.Called(ctx, u)
+// RemoveDelegateProfile provides a mock function with given fields: ctx, name
+func (_m *FacadeInterface) RemoveDelegateProfile(ctx datastore.Context, name string) error {
+	ret := _m.Called(ctx, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, user.User) error); ok {
-		r0 = rf(ctx, u)
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1720,20 +2669,22 @@ func (_m *FacadeInterface) UpdateUser(ctx datastore.Context, u user.User) error
 	return r0
 }
 
-// ValidateCredentials provides a mock function with given fields: ctx, u
-func (_m *FacadeInterface) ValidateCredentials(ctx datastore.Context, u user.User) (bool, error) {
-	ret := _m.Called(ctx, u)
+// SetDelegateProfileOption provides a mock function with given fields: ctx, name, option, value
+func (_m *FacadeInterface) SetDelegateProfileOption(ctx datastore.Context, name string, option string, value string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(ctx, name, option, value)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(datastore.Context, user.User) bool); ok {
-		r0 = rf(ctx, u)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(ctx, name, option, value)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, user.User) error); ok {
-		r1 = rf(ctx, u)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, option, value)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1741,35 +2692,45 @@ func (_m *FacadeInterface) ValidateCredentials(ctx datastore.Context, u user.Use
 	return r0, r1
 }
 
-// WaitService provides a mock function with given fields: ctx, dstate, timeout, recursive, serviceIDs
-func (_m *FacadeInterface) WaitService(ctx datastore.Context, dstate service.DesiredState, timeout time.Duration, recursive bool, serviceIDs ...string) error {
-	ret := _m.Called(ctx, dstate, timeout, recursive, serviceIDs)
+// UnsetDelegateProfileOption provides a mock function with given fields: ctx, name, option
+func (_m *FacadeInterface) UnsetDelegateProfileOption(ctx datastore.Context, name string, option string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(ctx, name, option)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(datastore.Context, service.DesiredState, time.Duration, bool, ...string) error); ok {
-		r0 = rf(ctx, dstate, timeout, recursive, serviceIDs...)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(ctx, name, option)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, name, option)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-func (_m *FacadeInterface) QueryServiceDetails(ctx datastore.Context, query service.Query) ([]service.ServiceDetails, error) {
-	ret := _m.Called(ctx, query)
+// RollbackDelegateProfile provides a mock function with given fields: ctx, name, version
+func (_m *FacadeInterface) RollbackDelegateProfile(ctx datastore.Context, name string, version int) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(ctx, name, version)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(datastore.Context, service.Query) []service.ServiceDetails); ok {
-		r0 = rf(ctx, query)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, int) []delegateprofile.HostStatus); ok {
+		r0 = rf(ctx, name, version)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(datastore.Context, service.Query) error); ok {
-		r1 = rf(ctx, query)
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, int) error); ok {
+		r1 = rf(ctx, name, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1777,33 +2738,108 @@ func (_m *FacadeInterface) QueryServiceDetails(ctx datastore.Context, query serv
 	return r0, r1
 }
 
-func (_m *FacadeInterface) GetServiceNamePath(ctx datastore.Context, serviceID string) (string, string, error) {
-	ret := _m.Called(ctx, serviceID)
+// AssignDelegateProfile provides a mock function with given fields: ctx, poolID, name
+func (_m *FacadeInterface) AssignDelegateProfile(ctx datastore.Context, poolID string, name string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(ctx, poolID, name)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
-		r0 = rf(ctx, serviceID)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(ctx, poolID, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(string)
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
-		r1 = rf(ctx, serviceID)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, poolID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PushDelegateProfile provides a mock function with given fields: ctx, poolID
+func (_m *FacadeInterface) PushDelegateProfile(ctx datastore.Context, poolID string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(ctx, poolID)
+
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(ctx, poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r1 = ret.Get(0).(string)
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
 		}
 	}
 
-	var r2 error
+	var r1 error
 	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
-		r2 = rf(ctx, serviceID)
+		r1 = rf(ctx, poolID)
 	} else {
-		r2 = ret.Error(1)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
+}
+
+// GetHostDelegateProfile provides a mock function with given fields: ctx, hostID
+func (_m *FacadeInterface) GetHostDelegateProfile(ctx datastore.Context, hostID string) (*delegateprofile.Profile, error) {
+	ret := _m.Called(ctx, hostID)
+
+	var r0 *delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *delegateprofile.Profile); ok {
+		r0 = rf(ctx, hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delegateprofile.Profile)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetMaintenanceMode provides a mock function with given fields: ctx, tenantID, mode
+func (_m *FacadeInterface) SetMaintenanceMode(ctx datastore.Context, tenantID string, mode *service.MaintenanceMode) error {
+	ret := _m.Called(ctx, tenantID, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, *service.MaintenanceMode) error); ok {
+		r0 = rf(ctx, tenantID, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMaintenanceMode provides a mock function with given fields: ctx, tenantID
+func (_m *FacadeInterface) GetMaintenanceMode(ctx datastore.Context, tenantID string) (*service.MaintenanceMode, error) {
+	ret := _m.Called(ctx, tenantID)
+
+	var r0 *service.MaintenanceMode
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *service.MaintenanceMode); ok {
+		r0 = rf(ctx, tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.MaintenanceMode)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
@@ -32,6 +32,29 @@ func (_m *MetricsClient) GetAvailableStorage(_a0 time.Duration, _a1 string, _a2
 	return r0, r1
 }
 
+// GetEndpointMetrics provides a mock function with given fields: _a0, _a1
+func (_m *MetricsClient) GetEndpointMetrics(_a0 time.Time, _a1 string) ([]metrics.EndpointMetrics, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []metrics.EndpointMetrics
+	if rf, ok := ret.Get(0).(func(time.Time, string) []metrics.EndpointMetrics); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]metrics.EndpointMetrics)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInstanceMemoryStats provides a mock function with given fields: _a0, _a1
 func (_m *MetricsClient) GetInstanceMemoryStats(_a0 time.Time, _a1 ...metrics.ServiceInstance) ([]metrics.MemoryUsageStats, error) {
 	ret := _m.Called(_a0, _a1)
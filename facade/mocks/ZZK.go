@@ -7,6 +7,7 @@ import "github.com/control-center/serviced/domain/host"
 import "github.com/control-center/serviced/domain/pool"
 import "github.com/control-center/serviced/domain/registry"
 import "github.com/control-center/serviced/domain/service"
+import "github.com/control-center/serviced/domain/setting"
 import zkservice "github.com/control-center/serviced/zzk/service"
 
 type ZZK struct {
@@ -228,6 +229,30 @@ func (_m *ZZK) IsHostActive(poolID string, hostId string) (bool, error) {
 
 	return r0, r1
 }
+func (_m *ZZK) SetHostMaintenance(poolID string, hostID string, enabled bool) error {
+	ret := _m.Called(poolID, hostID, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) error); ok {
+		r0 = rf(poolID, hostID, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) SetSettings(settings []setting.Setting) error {
+	ret := _m.Called(settings)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]setting.Setting) error); ok {
+		r0 = rf(settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ZZK) UpdateResourcePool(_pool *pool.ResourcePool) error {
 	ret := _m.Called(_pool)
 
@@ -252,6 +277,52 @@ func (_m *ZZK) RemoveResourcePool(poolID string) error {
 
 	return r0
 }
+
+// GetResourcePools provides a mock function with given fields:
+func (_m *ZZK) GetResourcePools() ([]pool.ResourcePool, error) {
+	ret := _m.Called()
+
+	var r0 []pool.ResourcePool
+	if rf, ok := ret.Get(0).(func() []pool.ResourcePool); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.ResourcePool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHosts provides a mock function with given fields: poolID
+func (_m *ZZK) GetHosts(poolID string) ([]host.Host, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []host.Host
+	if rf, ok := ret.Get(0).(func(string) []host.Host); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]host.Host)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) AddVirtualIP(vip *pool.VirtualIP) error {
 	ret := _m.Called(vip)
 
@@ -295,6 +366,27 @@ func (_m *ZZK) GetVirtualIPHostID(poolID string, ip string) (string, error) {
 
 	return r0, r1
 }
+func (_m *ZZK) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []pool.VirtualIPEvent
+	if rf, ok := ret.Get(0).(func(string) []pool.VirtualIPEvent); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.VirtualIPEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) GetRegistryImage(id string) (*registry.Image, error) {
 	ret := _m.Called(id)
 
@@ -316,6 +408,29 @@ func (_m *ZZK) GetRegistryImage(id string) (*registry.Image, error) {
 
 	return r0, r1
 }
+
+// GetRegistryImages provides a mock function with given fields:
+func (_m *ZZK) GetRegistryImages() ([]registry.Image, error) {
+	ret := _m.Called()
+
+	var r0 []registry.Image
+	if rf, ok := ret.Get(0).(func() []registry.Image); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]registry.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) SetRegistryImage(rImage *registry.Image) error {
 	ret := _m.Called(rImage)
 
@@ -397,6 +512,27 @@ func (_m *ZZK) GetServiceStates(ctx datastore.Context, poolID string, serviceID
 
 	return r0, r1
 }
+func (_m *ZZK) GetServiceInstanceHistory(ctx datastore.Context, poolID string, serviceID string) ([]service.InstanceHistory, error) {
+	ret := _m.Called(ctx, poolID, serviceID)
+
+	var r0 []service.InstanceHistory
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) []service.InstanceHistory); ok {
+		r0 = rf(ctx, poolID, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.InstanceHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, poolID, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
 func (_m *ZZK) GetHostStates(ctx datastore.Context, poolID string, hostID string) ([]zkservice.State, error) {
 	ret := _m.Called(ctx, poolID, hostID)
 
@@ -565,3 +701,27 @@ func (_m *ZZK) UpdateInstanceCurrentState(ctx datastore.Context, poolID, service
 
 	return r0
 }
+func (_m *ZZK) PinInstanceImage(ctx datastore.Context, poolID string, serviceID string, instanceID int, imageID string, restart bool) error {
+	ret := _m.Called(ctx, poolID, serviceID, instanceID, imageID, restart)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, int, string, bool) error); ok {
+		r0 = rf(ctx, poolID, serviceID, instanceID, imageID, restart)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+func (_m *ZZK) SetInstanceEnvironment(ctx datastore.Context, poolID string, serviceID string, instanceID int, env []string) error {
+	ret := _m.Called(ctx, poolID, serviceID, instanceID, env)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, int, []string) error); ok {
+		r0 = rf(ctx, poolID, serviceID, instanceID, env)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,354 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+)
+
+// Types of operations, besides backups and restores
+const (
+	OperationTemplateDeploy = "template deploy"
+	OperationRegistrySync   = "registry sync"
+	OperationRebalance      = "rebalance"
+)
+
+// States of an operation
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCanceled  = "canceled"
+)
+
+const (
+	// maxFinishedOperations is the number of operations that are kept after
+	// they end, so that clients can look up how they ended
+	maxFinishedOperations = 100
+
+	// maxOperationLogs is the number of log lines that are kept per operation
+	maxOperationLogs = 200
+)
+
+var (
+	// ErrOperationNotFound is returned when an operation does not exist
+	ErrOperationNotFound = errors.New("facade: operation not found")
+
+	// ErrOperationEnded is returned when an operation that ended is canceled
+	ErrOperationEnded = errors.New("facade: operation has already ended")
+
+	// ErrOperationCanceled is returned by an operation that stopped because
+	// it was canceled
+	ErrOperationCanceled = errors.New("facade: operation was canceled")
+)
+
+/*
+   Backups, restores, template deploys, registry syncs and rebalances can run
+   for a long time.  The master tracks each of them as an operation with an
+   id, so that clients can poll its progress and logs after the request that
+   started it returns, and ask for it to be canceled.  Canceling is
+   cooperative: the operation stops at its next safe point and ends as
+   canceled, or runs to the end if it has no more safe points.  Operations
+   are kept in memory and do not survive a restart of the master.
+*/
+
+// Operation is the handle that a running operation reports its progress and
+// logs to.  Its methods do nothing on a nil operation, so code that runs
+// both inside and outside of an operation does not need to check.
+type Operation struct {
+	mu     sync.Mutex
+	op     dao.Operation
+	cancel chan struct{}
+	now    func() time.Time
+}
+
+// Logf appends a line to the logs of the operation
+func (op *Operation) Logf(format string, args ...interface{}) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	line := fmt.Sprintf("%s %s", op.now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	op.op.Logs = append(op.op.Logs, line)
+	if len(op.op.Logs) > maxOperationLogs {
+		op.op.Logs = op.op.Logs[len(op.op.Logs)-maxOperationLogs:]
+	}
+}
+
+// SetProgress sets the percentage of the operation that is done, or -1 if it
+// is unknown, and what the operation is doing.
+func (op *Operation) SetProgress(percent float64, message string) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.op.Progress = percent
+	op.op.Message = message
+}
+
+// Canceled returns a channel that is closed when the operation is asked to
+// be canceled.  It is never closed on a nil operation.
+func (op *Operation) Canceled() <-chan struct{} {
+	if op == nil {
+		return nil
+	}
+	return op.cancel
+}
+
+// IsCanceled returns true if the operation was asked to be canceled
+func (op *Operation) IsCanceled() bool {
+	select {
+	case <-op.Canceled():
+		return true
+	default:
+		return false
+	}
+}
+
+// get returns a copy of the state of the operation
+func (op *Operation) get() dao.Operation {
+	if op == nil {
+		return dao.Operation{}
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	state := op.op
+	state.Logs = append([]string{}, op.op.Logs...)
+	return state
+}
+
+// operationContext is the datastore context of an operation, so that the
+// facade calls that the operation makes can report to it.
+type operationContext struct {
+	datastore.Context
+	op *Operation
+}
+
+// operationFromContext returns the operation that a context belongs to, or
+// nil if it does not belong to an operation.
+func operationFromContext(ctx datastore.Context) *Operation {
+	if octx, ok := ctx.(*operationContext); ok {
+		return octx.op
+	}
+	return nil
+}
+
+// operationRegistry tracks the operations that are running and the last
+// operations that ended.  Its methods do nothing on a nil registry.
+type operationRegistry struct {
+	mu  sync.Mutex
+	ops []*Operation
+	seq int
+	now func() time.Time
+}
+
+func newOperationRegistry() *operationRegistry {
+	return &operationRegistry{now: time.Now}
+}
+
+// start registers a new running operation
+func (r *operationRegistry) start(opType, target, user string) *Operation {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	op := &Operation{
+		op: dao.Operation{
+			ID:        strconv.Itoa(r.seq),
+			Type:      opType,
+			Target:    target,
+			User:      user,
+			State:     OperationRunning,
+			Progress:  -1,
+			StartedAt: r.now().UTC(),
+		},
+		cancel: make(chan struct{}),
+		now:    r.now,
+	}
+	r.ops = append(r.ops, op)
+	return op
+}
+
+// end records how an operation ended, and forgets the oldest operations
+// that ended past the number that are kept.  An operation that fails after
+// it was asked to be canceled ends as canceled.
+func (r *operationRegistry) end(op *Operation, err error) {
+	if r == nil || op == nil {
+		return
+	}
+	op.mu.Lock()
+	op.op.EndedAt = r.now().UTC()
+	switch {
+	case err == nil:
+		op.op.State = OperationSucceeded
+		op.op.Progress = 100
+	case err == ErrOperationCanceled || op.op.CancelRequested:
+		op.op.State = OperationCanceled
+	default:
+		op.op.State = OperationFailed
+	}
+	if err != nil && err != ErrOperationCanceled {
+		op.op.Error = err.Error()
+	}
+	op.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	finished := 0
+	for i := len(r.ops) - 1; i >= 0; i-- {
+		if r.ops[i].get().State == OperationRunning {
+			continue
+		}
+		if finished++; finished > maxFinishedOperations {
+			r.ops = append(r.ops[:i], r.ops[i+1:]...)
+		}
+	}
+}
+
+// get returns the operation with the id
+func (r *operationRegistry) get(id string) (*Operation, error) {
+	if r == nil {
+		return nil, ErrOperationNotFound
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, op := range r.ops {
+		if op.op.ID == id {
+			return op, nil
+		}
+	}
+	return nil, ErrOperationNotFound
+}
+
+// list returns the operations, in the order that they were started
+func (r *operationRegistry) list() []dao.Operation {
+	ops := []dao.Operation{}
+	if r == nil {
+		return ops
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, op := range r.ops {
+		ops = append(ops, op.get())
+	}
+	return ops
+}
+
+// cancel asks a running operation to stop
+func (r *operationRegistry) cancel(id string) error {
+	op, err := r.get(id)
+	if err != nil {
+		return err
+	}
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if op.op.State != OperationRunning {
+		return ErrOperationEnded
+	}
+	if !op.op.CancelRequested {
+		op.op.CancelRequested = true
+		close(op.cancel)
+	}
+	return nil
+}
+
+// StartOperation runs a function as an operation in the background and
+// returns the id of the operation.  The function is passed a context that
+// reports the progress of the facade calls it makes to the operation.
+func (f *Facade) StartOperation(ctx datastore.Context, opType, target string, run func(datastore.Context) error) string {
+	op := f.operations.start(opType, target, ctx.User())
+	go f.runOperation(ctx, op, run)
+	return op.get().ID
+}
+
+// RunOperation runs a function as an operation and waits for it to end, so
+// that clients can follow and cancel it while the caller waits.
+func (f *Facade) RunOperation(ctx datastore.Context, opType, target string, run func(datastore.Context) error) error {
+	op := f.operations.start(opType, target, ctx.User())
+	return f.runOperation(ctx, op, run)
+}
+
+func (f *Facade) runOperation(ctx datastore.Context, op *Operation, run func(datastore.Context) error) error {
+	if op == nil {
+		return run(ctx)
+	}
+	state := op.get()
+	logger := plog.WithFields(logrus.Fields{
+		"operationid": state.ID,
+		"operation":   state.Type,
+		"target":      state.Target,
+	})
+	logger.Info("Started operation")
+	op.Logf("Started %s", state.Type)
+	err := run(&operationContext{Context: ctx, op: op})
+	f.operations.end(op, err)
+	switch op.get().State {
+	case OperationSucceeded:
+		op.Logf("Completed %s", state.Type)
+		logger.Info("Completed operation")
+	case OperationCanceled:
+		op.Logf("Canceled %s", state.Type)
+		logger.Info("Canceled operation")
+	default:
+		op.Logf("Failed %s: %s", state.Type, err)
+		logger.WithError(err).Warn("Operation failed")
+	}
+	return err
+}
+
+// GetOperations returns the operations that are running and the last
+// operations that ended, in the order that they were started.
+func (f *Facade) GetOperations(ctx datastore.Context) ([]dao.Operation, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetOperations"))
+	return f.operations.list(), nil
+}
+
+// GetOperation returns the status, progress and logs of an operation
+func (f *Facade) GetOperation(ctx datastore.Context, id string) (*dao.Operation, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetOperation"))
+	op, err := f.operations.get(id)
+	if err != nil {
+		return nil, err
+	}
+	state := op.get()
+	return &state, nil
+}
+
+// CancelOperation asks a running operation to stop at its next safe point
+func (f *Facade) CancelOperation(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CancelOperation"))
+	if err := f.operations.cancel(id); err != nil {
+		plog.WithField("operationid", id).WithError(err).Debug("Could not cancel operation")
+		return err
+	}
+	op, _ := f.operations.get(id)
+	op.Logf("Cancel requested by %s", ctx.User())
+	plog.WithFields(logrus.Fields{
+		"operationid": id,
+		"user":        ctx.User(),
+	}).Info("Requested cancel of operation")
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&OperationRegistryTest{})
+
+type OperationRegistryTest struct{}
+
+func (t *OperationRegistryTest) TestOperationRegistry(c *C) {
+	now := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+	r := newOperationRegistry()
+	r.now = func() time.Time { return now }
+
+	op := r.start(OperationRebalance, "svc1", "admin")
+	op.SetProgress(50, "stopping services")
+	op.Logf("Stopped %d services", 3)
+	state := op.get()
+	c.Assert(state.ID, Equals, "1")
+	c.Assert(state.State, Equals, OperationRunning)
+	c.Assert(state.User, Equals, "admin")
+	c.Assert(state.Progress, Equals, float64(50))
+	c.Assert(state.Message, Equals, "stopping services")
+	c.Assert(state.Logs, DeepEquals, []string{"2017-01-02T15:04:05Z Stopped 3 services"})
+
+	// operations end as succeeded, failed or canceled
+	r.end(op, nil)
+	failed := r.start(OperationRegistrySync, "", "admin")
+	r.end(failed, errors.New("registry is down"))
+	canceled := r.start(BackupOperation, "/backups", "admin")
+	c.Assert(canceled.IsCanceled(), Equals, false)
+	c.Assert(r.cancel(canceled.get().ID), IsNil)
+	c.Assert(r.cancel(canceled.get().ID), IsNil)
+	c.Assert(canceled.IsCanceled(), Equals, true)
+	r.end(canceled, errors.New("write failed"))
+
+	ops := r.list()
+	c.Assert(ops, HasLen, 3)
+	c.Assert(ops[0].State, Equals, OperationSucceeded)
+	c.Assert(ops[0].Progress, Equals, float64(100))
+	c.Assert(ops[1].State, Equals, OperationFailed)
+	c.Assert(ops[1].Error, Equals, "registry is down")
+	c.Assert(ops[2].State, Equals, OperationCanceled)
+	c.Assert(ops[2].CancelRequested, Equals, true)
+
+	// operations that ended cannot be canceled
+	c.Assert(r.cancel("1"), Equals, ErrOperationEnded)
+	c.Assert(r.cancel("missing"), Equals, ErrOperationNotFound)
+	_, err := r.get("missing")
+	c.Assert(err, Equals, ErrOperationNotFound)
+}
+
+func (t *OperationRegistryTest) TestOperationRegistryPrune(c *C) {
+	r := newOperationRegistry()
+	running := r.start(OperationRebalance, "svc1", "admin")
+	for i := 0; i < maxFinishedOperations+5; i++ {
+		r.end(r.start(OperationRegistrySync, fmt.Sprintf("%d", i), "admin"), nil)
+	}
+
+	// the oldest operations that ended are forgotten, but not running ones
+	ops := r.list()
+	c.Assert(ops, HasLen, maxFinishedOperations+1)
+	c.Assert(ops[0].ID, Equals, running.get().ID)
+	c.Assert(ops[1].Target, Equals, "5")
+}
+
+func (t *OperationRegistryTest) TestOperationNil(c *C) {
+	var op *Operation
+	op.Logf("nothing")
+	op.SetProgress(10, "nothing")
+	c.Assert(op.IsCanceled(), Equals, false)
+
+	var r *operationRegistry
+	c.Assert(r.start(OperationRebalance, "", ""), IsNil)
+	c.Assert(r.list(), HasLen, 0)
+	c.Assert(operationFromContext(datastore.Get()), IsNil)
+}
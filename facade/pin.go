@@ -0,0 +1,148 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/pin"
+)
+
+var (
+	// ErrSnapshotPinned is returned when deleting a pinned snapshot
+	ErrSnapshotPinned = errors.New("facade: snapshot is pinned")
+	// ErrImagePinned is returned when deleting a pinned image tag, or a
+	// snapshot whose image tag is pinned
+	ErrImagePinned = errors.New("facade: image is pinned")
+	// ErrPinNotFound is returned when removing a pin that does not exist
+	ErrPinNotFound = errors.New("facade: pin not found")
+	// ErrInvalidPinKind is returned when pinning an object of an unknown kind
+	ErrInvalidPinKind = errors.New("facade: invalid pin kind")
+)
+
+// GetPins returns the pinned snapshots and image tags
+func (f *Facade) GetPins(ctx datastore.Context) ([]pin.Pin, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetPins"))
+	return f.pinStore.GetPins(ctx)
+}
+
+// PinSnapshot protects a snapshot from the snapshot TTL and from being
+// deleted until it is unpinned.
+func (f *Facade) PinSnapshot(ctx datastore.Context, snapshotID, reason string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PinSnapshot"))
+	alog := f.auditLogger.Message(ctx, "Pinning Snapshot").Action(audit.Add).ID(snapshotID).Type(pin.GetType()).WithField("reason", reason)
+	info, err := f.dfs.Info(snapshotID)
+	if err != nil {
+		plog.WithField("snapshotid", snapshotID).WithError(err).Debug("Could not get info for snapshot")
+		return alog.Error(err)
+	}
+	p := pin.New(pin.KindSnapshot, snapshotID, info.TenantID, reason)
+	return alog.Error(f.pinStore.Put(ctx, pin.Key(p.Kind, p.Target), p))
+}
+
+// PinImage protects a tag of an image in the docker registry, such as
+// tenantid/repo:tag, from registry cleanup and from being deleted until it is
+// unpinned.
+func (f *Facade) PinImage(ctx datastore.Context, image, reason string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PinImage"))
+	alog := f.auditLogger.Message(ctx, "Pinning Image").Action(audit.Add).ID(image).Type(pin.GetType()).WithField("reason", reason)
+	rImage, err := f.registryStore.Get(ctx, image)
+	if err != nil {
+		plog.WithField("image", image).WithError(err).Debug("Could not find image in the registry")
+		return alog.Error(err)
+	}
+	p := pin.New(pin.KindImage, rImage.String(), rImage.Library, reason)
+	return alog.Error(f.pinStore.Put(ctx, pin.Key(p.Kind, p.Target), p))
+}
+
+// Unpin removes the pin of a snapshot or image tag, so that it is cleaned up
+// and can be deleted again.
+func (f *Facade) Unpin(ctx datastore.Context, kind pin.Kind, target string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Unpin"))
+	alog := f.auditLogger.Message(ctx, "Unpinning").Action(audit.Remove).ID(target).Type(pin.GetType())
+	if !pin.ValidKind(kind) {
+		return alog.Error(ErrInvalidPinKind)
+	}
+	key := pin.Key(kind, target)
+	var p pin.Pin
+	if err := f.pinStore.Get(ctx, key, &p); datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(ErrPinNotFound)
+	} else if err != nil {
+		return alog.Error(err)
+	}
+	alog = alog.WithField("reason", p.Reason)
+	return alog.Error(f.pinStore.Delete(ctx, key))
+}
+
+// SnapshotPinned returns true if a snapshot, or the image tag of a snapshot,
+// is pinned.
+func (f *Facade) SnapshotPinned(ctx datastore.Context, snapshotID string) (bool, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SnapshotPinned"))
+	switch err := f.checkSnapshotPins(ctx, snapshotID); err {
+	case nil:
+		return false, nil
+	case ErrSnapshotPinned, ErrImagePinned:
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// checkSnapshotPins returns ErrSnapshotPinned or ErrImagePinned if the
+// snapshot cannot be deleted because it or one of its image tags is pinned.
+func (f *Facade) checkSnapshotPins(ctx datastore.Context, snapshotID string) error {
+	pins, err := f.pinStore.GetPins(ctx)
+	if err != nil {
+		return err
+	} else if len(pins) == 0 {
+		return nil
+	}
+
+	// snapshots that cannot be read still honor their own pins
+	tenantID, label := "", ""
+	if info, err := f.dfs.Info(snapshotID); err == nil {
+		tenantID, label = info.TenantID, info.Label
+	}
+	for _, p := range pins {
+		switch p.Kind {
+		case pin.KindSnapshot:
+			if p.Target == snapshotID {
+				return ErrSnapshotPinned
+			}
+		case pin.KindImage:
+			if label == "" || p.TenantID != tenantID {
+				continue
+			}
+			if imageID, err := commons.ParseImageID(p.Target); err == nil && imageID.Tag == label {
+				return ErrImagePinned
+			}
+		}
+	}
+	return nil
+}
+
+// imagePinned returns true if a tag of an image in the docker registry is
+// pinned.
+func (f *Facade) imagePinned(ctx datastore.Context, image string) (bool, error) {
+	var p pin.Pin
+	if err := f.pinStore.Get(ctx, pin.Key(pin.KindImage, image), &p); datastore.IsErrNoSuchEntity(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/pin"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupPinnedSnapshot(pins ...pin.Pin) {
+	ft.pinStore.On("GetPins", ft.ctx).Return(pins, nil)
+	ft.dfs.On("Info", "tenant_20170101_000000.000").Return(&dfs.SnapshotInfo{
+		SnapshotInfo: &volume.SnapshotInfo{
+			Name:     "tenant_20170101_000000.000",
+			TenantID: "tenant",
+			Label:    "20170101_000000.000",
+		},
+	}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_DeleteSnapshot_Pinned(c *C) {
+	ft.setupPinnedSnapshot(*pin.New(pin.KindSnapshot, "tenant_20170101_000000.000", "tenant", "audit"))
+
+	err := ft.Facade.DeleteSnapshot(ft.ctx, "tenant_20170101_000000.000")
+	c.Assert(err, Equals, facade.ErrSnapshotPinned)
+	ft.dfs.AssertNotCalled(c, "Delete", mock.AnythingOfType("string"))
+
+	pinned, err := ft.Facade.SnapshotPinned(ft.ctx, "tenant_20170101_000000.000")
+	c.Assert(err, IsNil)
+	c.Assert(pinned, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_DeleteSnapshot_ImagePinned(c *C) {
+	ft.setupPinnedSnapshot(
+		*pin.New(pin.KindImage, "other/repo:20170101_000000.000", "other", ""),
+		*pin.New(pin.KindImage, "tenant/repo:20170101_000000.000", "tenant", ""),
+	)
+
+	err := ft.Facade.DeleteSnapshot(ft.ctx, "tenant_20170101_000000.000")
+	c.Assert(err, Equals, facade.ErrImagePinned)
+	ft.dfs.AssertNotCalled(c, "Delete", mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeUnitTest) Test_DeleteSnapshot_NotPinned(c *C) {
+	ft.setupPinnedSnapshot(
+		*pin.New(pin.KindSnapshot, "tenant_20170102_000000.000", "tenant", ""),
+		*pin.New(pin.KindImage, "tenant/repo:latest", "tenant", ""),
+	)
+	ft.dfs.On("Delete", "tenant_20170101_000000.000").Return(nil)
+
+	err := ft.Facade.DeleteSnapshot(ft.ctx, "tenant_20170101_000000.000")
+	c.Assert(err, IsNil)
+	ft.dfs.AssertCalled(c, "Delete", "tenant_20170101_000000.000")
+
+	pinned, err := ft.Facade.SnapshotPinned(ft.ctx, "tenant_20170101_000000.000")
+	c.Assert(err, IsNil)
+	c.Assert(pinned, Equals, false)
+}
+
+func (ft *FacadeUnitTest) Test_DeleteRegistryImage_Pinned(c *C) {
+	ft.pinStore.On("Get", ft.ctx, pin.Key(pin.KindImage, "tenant/repo:latest"), mock.Anything).Return(nil)
+
+	err := ft.Facade.DeleteRegistryImage(ft.ctx, "tenant/repo:latest")
+	c.Assert(err, Equals, facade.ErrImagePinned)
+	ft.registryStore.AssertNotCalled(c, "Delete", ft.ctx, mock.AnythingOfType("string"))
+}
+
+func (ft *FacadeUnitTest) Test_Unpin_NotFound(c *C) {
+	key := pin.Key(pin.KindSnapshot, "tenant_20170101_000000.000")
+	ft.pinStore.On("Get", ft.ctx, key, mock.Anything).Return(datastore.ErrNoSuchEntity{Key: key})
+
+	err := ft.Facade.Unpin(ft.ctx, pin.KindSnapshot, "tenant_20170101_000000.000")
+	c.Assert(err, Equals, facade.ErrPinNotFound)
+	ft.pinStore.AssertNotCalled(c, "Delete", ft.ctx, key)
+
+	err = ft.Facade.Unpin(ft.ctx, "volume", "tenant")
+	c.Assert(err, Equals, facade.ErrInvalidPinKind)
+}
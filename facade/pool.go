@@ -19,6 +19,7 @@ import (
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/validation"
 	"github.com/zenoss/glog"
 
@@ -112,6 +113,9 @@ func (f *Facade) UpdateResourcePool(ctx datastore.Context, entity *pool.Resource
 	}
 	defer f.DFSLock(ctx).Unlock()
 
+	if current, err := f.GetResourcePool(ctx, entity.ID); err == nil && current != nil {
+		alog = alog.Delta(current, entity)
+	}
 	return alog.Error(f.updateResourcePool(ctx, entity))
 }
 
@@ -132,6 +136,7 @@ func (f *Facade) updateResourcePool(ctx datastore.Context, entity *pool.Resource
 
 	// Add the virtual ips that do not already exist
 	for _, vip := range entity.VirtualIPs {
+		vip.IP = utils.CanonicalIP(vip.IP)
 		if _, ok := currentVIPs[vip.IP]; !ok {
 			if err := f.addVirtualIP(ctx, &vip); err != nil {
 				glog.Warningf("Could not add virtual ip %s: %s", vip.IP, err)
@@ -278,9 +283,10 @@ func (f *Facade) addVirtualIP(ctx datastore.Context, vip *pool.VirtualIP) error
 		return ErrPoolNotExists
 	}
 
+	vip.IP = utils.CanonicalIP(vip.IP)
 	if err := validation.IsIP(vip.IP); err != nil {
 		return err
-	} else if err := validation.IsIP(vip.Netmask); err != nil {
+	} else if err := validation.IsNetmask(vip.IP, vip.Netmask); err != nil {
 		return err
 	} else if err := validation.NotEmpty("Bind Interface", vip.BindInterface); err != nil {
 		return err
@@ -297,6 +303,23 @@ func (f *Facade) addVirtualIP(ctx datastore.Context, vip *pool.VirtualIP) error
 	return nil
 }
 
+// GetVirtualIPEvents returns the reachability and failover events of the
+// virtual IPs of a pool, from oldest to newest.
+func (f *Facade) GetVirtualIPEvents(ctx datastore.Context, poolID string) ([]pool.VirtualIPEvent, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetVirtualIPEvents"))
+	if entity, err := f.GetResourcePool(ctx, poolID); err != nil {
+		return nil, err
+	} else if entity == nil {
+		return nil, ErrPoolNotExists
+	}
+	events, err := f.zzk.GetVirtualIPEvents(poolID)
+	if err != nil {
+		plog.WithField("poolid", poolID).WithError(err).Debug("Could not look up virtual ip events")
+		return nil, err
+	}
+	return events, nil
+}
+
 // RemoveVirtualIP removes a virtual IP from a pool
 func (f *Facade) RemoveVirtualIP(ctx datastore.Context, vip pool.VirtualIP) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveVirtualIP"))
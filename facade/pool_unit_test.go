@@ -22,6 +22,7 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/utils"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
@@ -102,6 +103,36 @@ func (ft *FacadeUnitTest) Test_GetResourcePoolFailsForOtherDBError(c *C) {
 	c.Assert(err, Equals, expectedError)
 }
 
+func (ft *FacadeUnitTest) Test_GetVirtualIPEvents(c *C) {
+	ft.setupMockDFSLocking()
+	poolID := "somePoolID"
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, poolID).Return([]host.Host{}, nil)
+	ft.poolStore.On("Get", ft.ctx, pool.Key(poolID), mock.AnythingOfType("*pool.ResourcePool")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*pool.ResourcePool) = pool.ResourcePool{ID: poolID}
+		})
+	expected := []pool.VirtualIPEvent{
+		{Type: pool.EventVirtualIPFailover, IPAddress: "10.0.0.10", HostID: "host1"},
+	}
+	ft.zzk.On("GetVirtualIPEvents", poolID).Return(expected, nil)
+
+	events, err := ft.Facade.GetVirtualIPEvents(ft.ctx, poolID)
+	c.Assert(err, IsNil)
+	c.Assert(events, DeepEquals, expected)
+}
+
+func (ft *FacadeUnitTest) Test_GetVirtualIPEventsNoPool(c *C) {
+	ft.setupMockDFSLocking()
+	poolID := "somePoolID"
+	ft.poolStore.On("Get", ft.ctx, pool.Key(poolID), mock.AnythingOfType("*pool.ResourcePool")).Return(datastore.ErrNoSuchEntity{})
+
+	events, err := ft.Facade.GetVirtualIPEvents(ft.ctx, poolID)
+	c.Assert(err, Equals, facade.ErrPoolNotExists)
+	c.Assert(events, IsNil)
+	ft.zzk.AssertNotCalled(c, "GetVirtualIPEvents", poolID)
+}
+
 func (ft *FacadeUnitTest) Test_GetReadPoolsShouldReturnCorrectValues(c *C) {
 	ft.setupMockDFSLocking()
 
@@ -0,0 +1,103 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"sort"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/scheduler/servicestatemanager"
+)
+
+// GetServicePriorities returns the effective priority classes of a service
+// and its descendents, or of every deployment if serviceID is empty, with
+// the batches that the service state manager starts them and stops them in
+// during an emergency shutdown.  The batches of a subtree are numbered as if
+// only the subtree were scheduled.
+func (f *Facade) GetServicePriorities(ctx datastore.Context, serviceID string) ([]service.ServicePriority, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServicePriorities"))
+
+	rootIDs := []string{serviceID}
+	if serviceID == "" {
+		var err error
+		if rootIDs, err = f.GetTenantIDs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := []service.ServicePriority{}
+	for _, rootID := range rootIDs {
+		svcs := make(map[string]*servicestatemanager.CancellableService)
+		visitor := func(svc *service.Service) error {
+			svcs[svc.ID] = servicestatemanager.NewCancellableService(svc)
+			return nil
+		}
+		if err := f.walkServices(ctx, rootID, true, visitor, "GetServicePriorities"); err != nil {
+			return nil, err
+		} else if len(svcs) == 0 {
+			continue
+		}
+
+		startBatches, err := priorityBatches(svcs, service.SVCRun, false)
+		if err != nil {
+			return nil, err
+		}
+		shutdownBatches, err := priorityBatches(svcs, service.SVCStop, true)
+		if err != nil {
+			return nil, err
+		}
+
+		priorities := []service.ServicePriority{}
+		for id, svc := range svcs {
+			priorities = append(priorities, service.ServicePriority{
+				ServiceID:              id,
+				Name:                   svc.Name,
+				DeploymentID:           svc.DeploymentID,
+				PriorityClass:          servicedefinition.EffectivePriorityClass(svc.PriorityClass),
+				StartLevel:             svc.StartLevel,
+				EmergencyShutdownLevel: svc.EmergencyShutdownLevel,
+				StartBatch:             startBatches[id],
+				ShutdownBatch:          shutdownBatches[id],
+			})
+		}
+		sort.Slice(priorities, func(i, j int) bool {
+			if priorities[i].ShutdownBatch != priorities[j].ShutdownBatch {
+				return priorities[i].ShutdownBatch < priorities[j].ShutdownBatch
+			}
+			return priorities[i].Name < priorities[j].Name
+		})
+		result = append(result, priorities...)
+	}
+	return result, nil
+}
+
+// priorityBatches returns the number of the batch of each service when the
+// services are scheduled to the desired state
+func priorityBatches(svcs map[string]*servicestatemanager.CancellableService, desiredState service.DesiredState, emergency bool) (map[string]int, error) {
+	batches, err := servicestatemanager.MergeBatches([]servicestatemanager.ServiceStateChangeBatch{
+		{Services: svcs, DesiredState: desiredState, Emergency: emergency},
+	})
+	if err != nil {
+		return nil, err
+	}
+	numbers := make(map[string]int)
+	for i, batch := range batches {
+		for id := range batch.Services {
+			numbers[id] = i + 1
+		}
+	}
+	return numbers, nil
+}
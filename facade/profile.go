@@ -0,0 +1,90 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
+)
+
+// MaxProfileDuration is the longest a cpu profile may be sampled
+const MaxProfileDuration = 5 * time.Minute
+
+var (
+	// ErrProfilesUnavailable is returned when the facade cannot reach the
+	// agents of the delegates to profile them
+	ErrProfilesUnavailable = errors.New("facade: delegate profiles are not available")
+
+	// ErrInvalidProfileDuration is returned when the profile duration is
+	// negative or longer than MaxProfileDuration
+	ErrInvalidProfileDuration = fmt.Errorf("facade: profile duration must be between 0 and %s", MaxProfileDuration)
+)
+
+// CollectProfiles collects pprof profiles from the master, or from the agent
+// of the delegate if a host id is set, and returns each one keyed by its
+// name.  The cpu profile is sampled for the duration.
+func (f *Facade) CollectProfiles(ctx datastore.Context, hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CollectProfiles"))
+	logger := plog.WithFields(log.Fields{
+		"hostid":   hostID,
+		"profiles": profiles,
+		"duration": duration,
+	})
+
+	alog := f.auditLogger.Message(ctx, "Collecting Profiles").
+		Action(audit.Profile).ID(hostID).Type(host.GetType()).
+		WithFields(log.Fields{"profiles": profiles, "duration": duration})
+
+	if duration < 0 || duration > MaxProfileDuration {
+		return nil, alog.Error(ErrInvalidProfileDuration)
+	}
+
+	if hostID == "" {
+		result, err := utils.CollectProfiles(profiles, duration)
+		if err != nil {
+			logger.WithError(err).Debug("Could not collect profiles from the master")
+			return nil, alog.Error(err)
+		}
+		alog.Succeeded()
+		return result, nil
+	}
+
+	if f.profileClient == nil {
+		return nil, alog.Error(ErrProfilesUnavailable)
+	}
+
+	hst, err := f.GetHost(ctx, hostID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up host")
+		return nil, alog.Error(err)
+	} else if hst == nil {
+		return nil, alog.Error(ErrHostDoesNotExist)
+	}
+
+	address := fmt.Sprintf("%s:%d", hst.IPAddr, hst.RPCPort)
+	result, err := f.profileClient.CollectProfiles(address, profiles, duration)
+	if err != nil {
+		logger.WithError(err).Debug("Could not collect profiles from the delegate")
+		return nil, alog.Error(err)
+	}
+	alog.Succeeded()
+	return result, nil
+}
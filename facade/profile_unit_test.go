@@ -0,0 +1,87 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/utils"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+// testProfileClient returns the address and duration as the profiles of
+// the agent
+type testProfileClient struct {
+	err error
+}
+
+func (t *testProfileClient) CollectProfiles(address string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	result := make(map[string][]byte)
+	for _, name := range profiles {
+		result[name] = []byte(address + " " + duration.String())
+	}
+	return result, nil
+}
+
+func (ft *FacadeUnitTest) Test_CollectProfiles_Master(c *C) {
+	result, err := ft.Facade.CollectProfiles(ft.ctx, "", []string{utils.ProfileGoroutine}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 1)
+	c.Assert(len(result[utils.ProfileGoroutine]) > 0, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_CollectProfiles_Delegate(c *C) {
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host0"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(2).(*host.Host) = host.Host{ID: "host0", IPAddr: "10.0.0.1", RPCPort: 4979}
+		})
+
+	_, err := ft.Facade.CollectProfiles(ft.ctx, "host0", []string{utils.ProfileCPU}, time.Second)
+	c.Assert(err, Equals, facade.ErrProfilesUnavailable)
+
+	ft.Facade.SetProfileClient(&testProfileClient{})
+	defer ft.Facade.SetProfileClient(nil)
+
+	result, err := ft.Facade.CollectProfiles(ft.ctx, "host0", []string{utils.ProfileCPU, utils.ProfileHeap}, time.Second)
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, map[string][]byte{
+		utils.ProfileCPU:  []byte("10.0.0.1:4979 1s"),
+		utils.ProfileHeap: []byte("10.0.0.1:4979 1s"),
+	})
+}
+
+func (ft *FacadeUnitTest) Test_CollectProfiles_AgentError(c *C) {
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("host0"), mock.AnythingOfType("*host.Host")).Return(nil)
+	ft.Facade.SetProfileClient(&testProfileClient{err: errors.New("agent unreachable")})
+	defer ft.Facade.SetProfileClient(nil)
+
+	_, err := ft.Facade.CollectProfiles(ft.ctx, "host0", []string{utils.ProfileCPU}, time.Second)
+	c.Assert(err, ErrorMatches, "agent unreachable")
+}
+
+func (ft *FacadeUnitTest) Test_CollectProfiles_InvalidDuration(c *C) {
+	_, err := ft.Facade.CollectProfiles(ft.ctx, "", []string{utils.ProfileCPU}, 10*time.Minute)
+	c.Assert(err, Equals, facade.ErrInvalidProfileDuration)
+}
@@ -14,6 +14,7 @@
 package facade
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"regexp"
@@ -22,6 +23,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/zenoss/glog"
@@ -41,6 +43,13 @@ func (f *Facade) AddPublicEndpointPort(ctx datastore.Context, serviceID, endpoin
 			"protocol":     protocol,
 			"isenabled":    isEnabled,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceID, EndpointName: endpointName, Action: "add",
+		PortAddress: portAddr, UseTLS: usetls, Protocol: protocol, Enabled: isEnabled}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return nil, alog.Error(err)
+	}
+	portAddr, usetls, protocol, isEnabled = change.PortAddress, change.UseTLS, change.Protocol, change.Enabled
+
 	// Scrub the port for all checks, as this is what gets stored against the service.
 	portAddr = service.ScrubPortString(portAddr)
 
@@ -152,6 +161,11 @@ func (f *Facade) RemovePublicEndpointPort(ctx datastore.Context, serviceid, endp
 			"endpointname": endpointName,
 			"portaddr":     portAddr,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceid, EndpointName: endpointName, Action: "remove", PortAddress: portAddr}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return alog.Error(err)
+	}
+
 	// Scrub the port for all checks, as this is what gets stored against the service.
 	portAddr = service.ScrubPortString(portAddr)
 
@@ -225,6 +239,13 @@ func (f *Facade) EnablePublicEndpointPort(ctx datastore.Context, serviceid, endp
 			"endpointname": endpointName,
 			"portaddr":     portAddr,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceid, EndpointName: endpointName, Action: "enable",
+		PortAddress: portAddr, Enabled: isEnabled}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return alog.Error(err)
+	}
+	isEnabled = change.Enabled
+
 	// Scrub the port for all checks, as this is what gets stored against the service.
 	portAddr = service.ScrubPortString(portAddr)
 
@@ -303,6 +324,13 @@ func (f *Facade) AddPublicEndpointVHost(ctx datastore.Context, serviceid, endpoi
 			"vhostname":    vhostName,
 			"isenabled":    isEnabled,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceid, EndpointName: endpointName, Action: "add",
+		VHostName: vhostName, Enabled: isEnabled}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return nil, alog.Error(err)
+	}
+	vhostName, isEnabled = change.VHostName, change.Enabled
+
 	// Get the service for this service id.
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
@@ -381,6 +409,11 @@ func (f *Facade) RemovePublicEndpointVHost(ctx datastore.Context, serviceid, end
 			"endpointname": endpointName,
 			"vhost":        vhost,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceid, EndpointName: endpointName, Action: "remove", VHostName: vhost}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return alog.Error(err)
+	}
+
 	// Get the service for this service id.
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
@@ -390,6 +423,11 @@ func (f *Facade) RemovePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	}
 	alog = alog.Entity(svc)
 
+	var certName string
+	if existingVHost := svc.GetVirtualHost(endpointName, vhost); existingVHost != nil {
+		certName = existingVHost.Certificate
+	}
+
 	err = svc.RemoveVirtualHost(endpointName, vhost)
 	if err != nil {
 		err = fmt.Errorf("Error removing vhost %s from service (%s): %v", vhost, svc.Name, err)
@@ -397,6 +435,14 @@ func (f *Facade) RemovePublicEndpointVHost(ctx datastore.Context, serviceid, end
 		return alog.Error(err)
 	}
 
+	// The uploaded certificate of the vhost goes with it
+	if certName != "" {
+		if err := f.RemoveCertificate(ctx, certName); err != nil && err != ErrCertificateNotFound {
+			glog.Error(err)
+			return alog.Error(err)
+		}
+	}
+
 	glog.V(2).Infof("Removed vhost public endpoint %s from service %s", vhost, svc.Name)
 
 	if err = f.UpdateService(ctx, *svc); err != nil {
@@ -422,6 +468,13 @@ func (f *Facade) EnablePublicEndpointVHost(ctx datastore.Context, serviceid, end
 			"endpointname": endpointName,
 			"vhost":        vhost,
 		})
+	change := &PublicEndpointChange{ServiceID: serviceid, EndpointName: endpointName, Action: "enable",
+		VHostName: vhost, Enabled: isEnabled}
+	if err := f.admit(ctx, AdmissionEndpointUpdate, change, nil); err != nil {
+		return alog.Error(err)
+	}
+	isEnabled = change.Enabled
+
 	// Get the service for this service id.
 	svc, err := f.GetService(ctx, serviceid)
 	if err != nil {
@@ -467,6 +520,62 @@ func (f *Facade) EnablePublicEndpointVHost(ctx datastore.Context, serviceid, end
 	return nil
 }
 
+// SetPublicEndpointVHostCertificate stores the certificate chain and private
+// key that a vhost public endpoint of a service presents instead of the
+// default certificate.  If certPEM is empty, the vhost presents the default
+// certificate again.
+func (f *Facade) SetPublicEndpointVHostCertificate(ctx datastore.Context, serviceid, vhost string, certPEM, keyPEM []byte) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointVHostCertificate"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint VHost Certificate").Action(audit.Update).ID(serviceid).
+		WithFields(logrus.Fields{
+			"vhost": vhost,
+			"clear": len(certPEM) == 0,
+		})
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid": serviceid,
+		"vhost":     vhost,
+	})
+
+	svc, err := f.GetService(ctx, serviceid)
+	if err != nil {
+		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
+		logger.WithError(err).Debug("Could not look up service")
+		return alog.Error(err)
+	}
+	alog = alog.Entity(svc)
+
+	// Uploaded certificates are named by their vhost
+	name := strings.ToLower(vhost)
+	if len(certPEM) == 0 {
+		if err := svc.SetVirtualHostCertificate(vhost, ""); err != nil {
+			return alog.Error(err)
+		}
+		if err := f.RemoveCertificate(ctx, name); err != nil && err != ErrCertificateNotFound {
+			logger.WithError(err).Debug("Could not remove vhost certificate")
+			return alog.Error(err)
+		}
+	} else {
+		if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+			return alog.Error(fmt.Errorf("certificate of vhost %s is not valid: %s", vhost, err))
+		}
+		if err := svc.SetVirtualHostCertificate(vhost, name); err != nil {
+			return alog.Error(err)
+		}
+		if err := f.SetCertificate(ctx, name, certificate.IssuerCustom, certPEM, keyPEM); err != nil {
+			logger.WithError(err).Debug("Could not store vhost certificate")
+			return alog.Error(err)
+		}
+	}
+
+	if err := f.UpdateService(ctx, *svc); err != nil {
+		logger.WithError(err).Debug("Could not update service")
+		return alog.Error(err)
+	}
+	logger.WithField("certificate", len(certPEM) > 0).Info("Set vhost certificate")
+	alog.Succeeded()
+	return nil
+}
+
 // GetAllPublicEndpoints returns all the public endpoints in the system
 func (f *Facade) GetAllPublicEndpoints(ctx datastore.Context) ([]service.PublicEndpoint, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetAllPublicEndpoints"))
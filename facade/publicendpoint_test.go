@@ -18,6 +18,7 @@ package facade
 import (
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
@@ -698,3 +699,39 @@ func (ft *FacadeIntegrationTest) Test_PublicEndpoint_SetAddressConfig(c *C) {
 
 	fmt.Println(" ##### Test_PublicEndpoint_SetAddressConfig: PASSED")
 }
+
+func (ft *FacadeIntegrationTest) Test_PublicEndpointVHost_SetCertificate(c *C) {
+	svcA, _ := ft.setupServiceWithPublicEndpoints(c)
+	ft.zzk.On("GetVHost", "zproxy").Return(svcA.ID, "zproxy", nil)
+	ft.zzk.On("GetPublicPort", ":22222").Return(svcA.ID, "zproxy", nil)
+	certPEM, keyPEM := testCertificateKeyPair(c, "zproxy.example.com", time.Now().Add(90*24*time.Hour))
+
+	// a key that does not match is refused
+	_, otherKey := testCertificateKeyPair(c, "zproxy.example.com", time.Now().Add(90*24*time.Hour))
+	err := ft.Facade.SetPublicEndpointVHostCertificate(ft.CTX, svcA.ID, "zproxy", certPEM, otherKey)
+	c.Assert(err, NotNil)
+
+	// the certificate is stored and the vhost refers to it
+	err = ft.Facade.SetPublicEndpointVHostCertificate(ft.CTX, svcA.ID, "zproxy", certPEM, keyPEM)
+	c.Assert(err, IsNil)
+	svc, err := ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].VHostList[0].Certificate, Equals, "zproxy")
+	actualCert, actualKey, err := ft.Facade.GetCertificateKeyPair(ft.CTX, "zproxy")
+	c.Assert(err, IsNil)
+	c.Assert(actualCert, DeepEquals, certPEM)
+	c.Assert(actualKey, DeepEquals, keyPEM)
+
+	// clearing the certificate removes it
+	err = ft.Facade.SetPublicEndpointVHostCertificate(ft.CTX, svcA.ID, "zproxy", nil, nil)
+	c.Assert(err, IsNil)
+	svc, err = ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].VHostList[0].Certificate, Equals, "")
+	_, _, err = ft.Facade.GetCertificateKeyPair(ft.CTX, "zproxy")
+	c.Assert(err, Equals, ErrCertificateNotFound)
+
+	// unknown vhosts have no certificate
+	err = ft.Facade.SetPublicEndpointVHostCertificate(ft.CTX, svcA.ID, "invalid", certPEM, keyPEM)
+	c.Assert(err, NotNil)
+}
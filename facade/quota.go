@@ -0,0 +1,138 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/volume"
+	"github.com/docker/go-units"
+)
+
+// quotaLevels tracks the highest quota threshold reached by each tenant
+// volume, so that an event is only emitted when a volume crosses one.
+type quotaLevels struct {
+	mu     sync.Mutex
+	levels map[string]int
+}
+
+// swap records the threshold reached by a tenant volume and returns the
+// previous one.
+func (q *quotaLevels) swap(tenantID string, level int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.levels == nil {
+		q.levels = make(map[string]int)
+	}
+	last := q.levels[tenantID]
+	q.levels[tenantID] = level
+	return last
+}
+
+// ResizeVolume sets the quota of the volume of the tenant that owns the
+// service.  Writes to the volume past its quota are rejected.
+func (f *Facade) ResizeVolume(ctx datastore.Context, serviceID string, size uint64) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ResizeVolume"))
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	logger := plog.WithFields(logrus.Fields{
+		"tenantid": tenantID,
+		"size":     units.BytesSize(float64(size)),
+	})
+	alog := f.auditLogger.Message(ctx, "Resize Volume").Action(audit.Update).
+		Type("Volume").ID(tenantID).WithField("size", fmt.Sprintf("%d", size))
+
+	dfslocker := f.DFSLock(ctx)
+	dfslocker.Lock("resize volume")
+	defer dfslocker.Unlock()
+
+	if err := f.dfs.Resize(tenantID, size); err != nil {
+		logger.WithError(err).Debug("Could not resize tenant volume")
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	logger.Info("Resized tenant volume")
+
+	// reset the threshold so that a volume that fills up again is reported
+	f.quotaLevels.swap(tenantID, 0)
+	return nil
+}
+
+// GetVolumeQuota returns the size limit and usage of the volume of the
+// tenant that owns the service.
+func (f *Facade) GetVolumeQuota(ctx datastore.Context, serviceID string) (*volume.Quota, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetVolumeQuota"))
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return f.dfs.Quota(tenantID)
+}
+
+// CheckVolumeQuotas emits an event for each tenant volume whose usage has
+// reached a new quota threshold since it was last checked.
+func (f *Facade) CheckVolumeQuotas(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CheckVolumeQuotas"))
+	tenantIDs, err := f.ListTenants(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tenantID := range tenantIDs {
+		logger := plog.WithField("tenantid", tenantID)
+		quota, err := f.dfs.Quota(tenantID)
+		if err == volume.ErrQuotaNotSupported {
+			return nil
+		} else if err != nil {
+			logger.WithError(err).Debug("Could not get the quota of tenant volume")
+			continue
+		}
+		level := quota.Threshold()
+		if last := f.quotaLevels.swap(tenantID, level); level <= last {
+			continue
+		}
+		logger = logger.WithFields(logrus.Fields{
+			"threshold": level,
+			"used":      units.BytesSize(float64(quota.Used)),
+			"limit":     units.BytesSize(float64(quota.Limit)),
+		})
+		logger.Warn("Tenant volume usage has reached a quota threshold")
+		f.recordEvent(ctx, event.Event{
+			Type:       event.TypeAlert,
+			EntityType: "Volume",
+			EntityID:   tenantID,
+			TenantID:   tenantID,
+			Message:    "Volume quota threshold reached",
+			Fields: map[string]string{
+				"threshold": strconv.Itoa(level),
+				"used":      strconv.FormatUint(quota.Used, 10),
+				"limit":     strconv.FormatUint(quota.Limit, 10),
+			},
+		})
+		f.auditLogger.Message(ctx, "Volume Quota Threshold Reached").Action(audit.Threshold).
+			Type("Volume").ID(tenantID).WithFields(logrus.Fields{
+			"threshold": level,
+			"used":      quota.Used,
+			"limit":     quota.Limit,
+		}).Succeeded()
+	}
+	return nil
+}
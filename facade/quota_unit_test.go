@@ -0,0 +1,92 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/audit"
+	auditmocks "github.com/control-center/serviced/audit/mocks"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/volume"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_ResizeVolume(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "quota-svc").
+		Return(&service.ServiceDetails{ID: "quota-svc", ParentServiceID: "quota-tenant"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "quota-tenant").
+		Return(&service.ServiceDetails{ID: "quota-tenant"}, nil)
+	ft.dfs.On("Resize", "quota-tenant", uint64(1<<30)).Return(nil)
+
+	err := ft.Facade.ResizeVolume(ft.ctx, "quota-svc", 1<<30)
+	c.Assert(err, IsNil)
+	ft.dfs.AssertCalled(c, "Resize", "quota-tenant", uint64(1<<30))
+}
+
+func (ft *FacadeUnitTest) Test_ResizeVolumeFail(c *C) {
+	ft.setupMockDFSLocking()
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "quota-tenant-fail").
+		Return(&service.ServiceDetails{ID: "quota-tenant-fail"}, nil)
+	errResize := errors.New("you can't shrink a device")
+	ft.dfs.On("Resize", "quota-tenant-fail", uint64(1024)).Return(errResize)
+
+	err := ft.Facade.ResizeVolume(ft.ctx, "quota-tenant-fail", 1024)
+	c.Assert(err, Equals, errResize)
+}
+
+func (ft *FacadeUnitTest) Test_CheckVolumeQuotas(c *C) {
+	logger := &auditmocks.Logger{}
+	logger.On("Message", ft.ctx, "Volume Quota Threshold Reached").Return(logger)
+	logger.On("Action", audit.Threshold).Return(logger)
+	logger.On("Type", "Volume").Return(logger)
+	logger.On("ID", mock.AnythingOfType("string")).Return(logger)
+	logger.On("WithFields", mock.AnythingOfType("logrus.Fields")).Return(logger)
+	logger.On("Succeeded")
+	ft.Facade.SetAuditLogger(logger)
+
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{{ID: "quota-low"}, {ID: "quota-high"}}, nil)
+	ft.dfs.On("Quota", "quota-low").Return(&volume.Quota{Limit: 100, Used: 50}, nil)
+	ft.dfs.On("Quota", "quota-high").Return(&volume.Quota{Limit: 100, Used: 85}, nil).Twice()
+
+	// only the volume past 80% is reported
+	err := ft.Facade.CheckVolumeQuotas(ft.ctx)
+	c.Assert(err, IsNil)
+	logger.AssertNumberOfCalls(c, "Message", 1)
+	logger.AssertCalled(c, "ID", "quota-high")
+
+	// the volume is not reported again until it crosses the next threshold
+	err = ft.Facade.CheckVolumeQuotas(ft.ctx)
+	c.Assert(err, IsNil)
+	logger.AssertNumberOfCalls(c, "Message", 1)
+
+	ft.dfs.On("Quota", "quota-high").Return(&volume.Quota{Limit: 100, Used: 95}, nil)
+	err = ft.Facade.CheckVolumeQuotas(ft.ctx)
+	c.Assert(err, IsNil)
+	logger.AssertNumberOfCalls(c, "Message", 2)
+}
+
+func (ft *FacadeUnitTest) Test_CheckVolumeQuotasNotSupported(c *C) {
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", mock.AnythingOfType("time.Duration")).
+		Return([]service.ServiceDetails{{ID: "quota-rsync"}}, nil)
+	ft.dfs.On("Quota", "quota-rsync").Return(nil, volume.ErrQuotaNotSupported)
+
+	err := ft.Facade.CheckVolumeQuotas(ft.ctx)
+	c.Assert(err, IsNil)
+}
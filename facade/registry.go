@@ -45,10 +45,16 @@ func (f *Facade) SetRegistryImage(ctx datastore.Context, rImage *registry.Image)
 	return nil
 }
 
-// DeleteRegistryImage removes an image from the docker registry index.
+// DeleteRegistryImage removes an image from the docker registry index.  Pinned
+// images cannot be removed.
 // e.g. DeleteRegistryImage(ctx, "library/reponame:tagname")
 func (f *Facade) DeleteRegistryImage(ctx datastore.Context, image string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeleteRegistryImage"))
+	if pinned, err := f.imagePinned(ctx, image); err != nil {
+		return err
+	} else if pinned {
+		return ErrImagePinned
+	}
 	if err := f.registryStore.Delete(ctx, image); err != nil {
 		return err
 	}
@@ -82,7 +88,8 @@ func (f *Facade) SearchRegistryLibraryByTag(ctx datastore.Context, library, tagn
 }
 
 // SyncRegistryImages makes sure images on es are in sync with zk.  If force is
-// enabled, all images are reset.
+// enabled, all images are reset.  If it runs as an operation, it stops at the
+// next image when the operation is canceled.
 func (f *Facade) SyncRegistryImages(ctx datastore.Context, force bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SyncRegistryImages"))
 	if err := f.DFSLock(ctx).LockWithTimeout("sync registry images", userLockTimeout); err != nil {
@@ -98,7 +105,13 @@ func (f *Facade) SyncRegistryImages(ctx datastore.Context, force bool) error {
 	}
 	// we aren't going to try to sync deletes because that can get too messy;
 	// only adds and updates
-	for _, rImage := range rImages {
+	op := operationFromContext(ctx)
+	for i, rImage := range rImages {
+		if op.IsCanceled() {
+			op.Logf("Synced %d of %d images", i, len(rImages))
+			return ErrOperationCanceled
+		}
+		op.SetProgress(float64(i)*100/float64(len(rImages)), "syncing "+rImage.String())
 		img, err := f.zzk.GetRegistryImage(rImage.ID())
 		if err != client.ErrNoNode && err != nil {
 			return err
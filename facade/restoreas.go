@@ -0,0 +1,239 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	// ErrRestoreNoDeploymentID is returned when a backup is restored
+	// alongside the existing applications without a new deployment id
+	ErrRestoreNoDeploymentID = errors.New("a deployment id is required to restore alongside the existing applications")
+
+	// ErrRestoreNameTenants is returned when a backup of more than one
+	// application is restored under a single name
+	ErrRestoreNameTenants = errors.New("a backup of more than one application cannot be restored under a single name")
+)
+
+// restoreRename describes a restore that imports the applications of a
+// backup under a new deployment, alongside the applications that were
+// backed up.
+type restoreRename struct {
+	DeploymentID string
+	Name         string
+	Tenants      map[string]string // tenant ids in the backup to the ids they are restored to
+}
+
+// RenameTenant implements dfs.TenantRenamer
+func (rn *restoreRename) RenameTenant(tenantID string) string {
+	if newTenantID, ok := rn.Tenants[tenantID]; ok {
+		return newTenantID
+	}
+	return tenantID
+}
+
+// snapshotTenant returns the tenant id and the label of a snapshot id
+func snapshotTenant(snapshotID string) (string, string) {
+	parts := strings.SplitN(snapshotID, "_", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// mapTenants assigns a new id to each tenant that has a snapshot in the
+// backup.
+func (rn *restoreRename) mapTenants(snapshots []string) error {
+	tenants := make(map[string]string)
+	for _, snapshot := range snapshots {
+		tenantID, _ := snapshotTenant(snapshot)
+		if _, ok := tenants[tenantID]; ok {
+			continue
+		}
+		newTenantID, err := utils.NewUUID36()
+		if err != nil {
+			return err
+		}
+		tenants[tenantID] = newTenantID
+	}
+	if rn.Name != "" && len(tenants) > 1 {
+		return ErrRestoreNameTenants
+	}
+	rn.Tenants = tenants
+	return nil
+}
+
+// services returns the services of a tenant in the backup with new ids
+// under the tenant it is restored to.  Their images are moved to the library
+// of the new tenant, and the virtual hosts of their public endpoints are
+// suffixed with the deployment id so that they do not collide with the
+// virtual hosts of the existing application.  Public ports that are in use
+// are disabled when the services are added.
+func (rn *restoreRename) services(tenantID string, svcs []service.Service) ([]service.Service, error) {
+	newTenantID := rn.RenameTenant(tenantID)
+	ids := map[string]string{tenantID: newTenantID}
+	for _, svc := range svcs {
+		if _, ok := ids[svc.ID]; ok {
+			continue
+		}
+		newID, err := utils.NewUUID36()
+		if err != nil {
+			return nil, err
+		}
+		ids[svc.ID] = newID
+	}
+
+	result := make([]service.Service, len(svcs))
+	for i, svc := range svcs {
+		svc.ID = ids[svc.ID]
+		if svc.ParentServiceID != "" {
+			parentID, ok := ids[svc.ParentServiceID]
+			if !ok {
+				return nil, fmt.Errorf("could not find parent %s of service %s", svc.ParentServiceID, svc.Name)
+			}
+			svc.ParentServiceID = parentID
+		} else if rn.Name != "" {
+			svc.Name = rn.Name
+		}
+		svc.DeploymentID = rn.DeploymentID
+
+		if imageID, err := commons.ParseImageID(svc.ImageID); err == nil && imageID.User == tenantID {
+			imageID.User = newTenantID
+			svc.ImageID = imageID.String()
+		}
+
+		endpoints := make([]service.ServiceEndpoint, len(svc.Endpoints))
+		for j, ep := range svc.Endpoints {
+			ep.AddressAssignment = addressassignment.AddressAssignment{}
+			if len(ep.VHosts) > 0 {
+				vhosts := make([]string, len(ep.VHosts))
+				for k, vhost := range ep.VHosts {
+					vhosts[k] = rn.vhost(vhost)
+				}
+				ep.VHosts = vhosts
+			}
+			if len(ep.VHostList) > 0 {
+				vhostList := append(ep.VHostList[:0:0], ep.VHostList...)
+				for k := range vhostList {
+					vhostList[k].Name = rn.vhost(vhostList[k].Name)
+				}
+				ep.VHostList = vhostList
+			}
+			endpoints[j] = ep
+		}
+		svc.Endpoints = endpoints
+		result[i] = svc
+	}
+	return result, nil
+}
+
+// vhost returns the name of a virtual host of the restored application
+func (rn *restoreRename) vhost(name string) string {
+	return fmt.Sprintf("%s-%s", name, rn.DeploymentID)
+}
+
+// restoreRenamer is implemented by the readers of restores that import the
+// applications of a backup under a new deployment
+type restoreRenamer interface {
+	restoreRename() *restoreRename
+}
+
+// getRestoreRename returns how the applications of a restore are renamed,
+// or nil if they replace the applications that were backed up
+func getRestoreRename(v interface{}) *restoreRename {
+	if rr, ok := v.(restoreRenamer); ok {
+		return rr.restoreRename()
+	}
+	return nil
+}
+
+// RestoreFromFileAs restores the applications of a compressed backup file
+// under a new deployment id, alongside the applications that were backed
+// up.  Each application gets a new tenant id and volume.  If name is set,
+// it renames the application; this requires a backup of a single
+// application.
+func (f *Facade) RestoreFromFileAs(ctx datastore.Context, filename, deploymentID, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestoreFromFileAs"))
+	// Do not DFSLock here, ControlPlaneDao does that
+	if deploymentID == "" {
+		return ErrRestoreNoDeploymentID
+	}
+	now := time.Now().UTC()
+	op := &backupOperation{
+		BackupOperation: dao.BackupOperation{
+			ID:        now.Format("restore-2006-01-02-150405"),
+			Operation: RestoreOperation,
+			Filename:  filename,
+			StartedAt: now,
+		},
+		Rename: &restoreRename{DeploymentID: deploymentID, Name: name},
+	}
+	if err := saveBackupOperation(op); err != nil {
+		plog.WithError(err).Debug("Could not save the state of the restore")
+		return err
+	}
+	return f.runRestoreOperation(ctx, op)
+}
+
+// restoreSnapshotAs adds the services of a snapshot in a backup to the
+// tenant that the snapshot was restored to and rolls back the volume of
+// that tenant.  It returns the id of the restored snapshot.
+func (f *Facade) restoreSnapshotAs(ctx datastore.Context, snapshotID string, rename *restoreRename) (string, error) {
+	tenantID, label := snapshotTenant(snapshotID)
+	newTenantID := rename.RenameTenant(tenantID)
+	newSnapshotID := fmt.Sprintf("%s_%s", newTenantID, label)
+	logger := plog.WithFields(logrus.Fields{
+		"snapshotid":   newSnapshotID,
+		"tenantid":     newTenantID,
+		"deploymentid": rename.DeploymentID,
+	})
+
+	info, err := f.dfs.Info(newSnapshotID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not get info for snapshot")
+		return newSnapshotID, err
+	}
+	svcs, err := rename.services(tenantID, info.Services)
+	if err != nil {
+		logger.WithError(err).Debug("Could not rename services")
+		return newSnapshotID, err
+	}
+	if err := f.lockTenant(ctx, newTenantID); err != nil {
+		logger.WithError(err).Debug("Could not lock tenant")
+		return newSnapshotID, err
+	}
+	defer f.retryUnlockTenant(ctx, newTenantID, nil, time.Second)
+	if err := f.RestoreServices(ctx, newTenantID, svcs); err != nil {
+		logger.WithError(err).Debug("Could not restore services")
+		return newSnapshotID, err
+	}
+	if err := f.dfs.Rollback(newSnapshotID); err != nil {
+		logger.WithError(err).Debug("Could not rollback snapshot")
+		return newSnapshotID, err
+	}
+	logger.Info("Restored application alongside the existing applications")
+	return newSnapshotID, nil
+}
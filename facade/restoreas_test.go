@@ -0,0 +1,92 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&RestoreAsTest{})
+
+type RestoreAsTest struct{}
+
+func (t *RestoreAsTest) Test_MapTenants(c *C) {
+	rn := &restoreRename{DeploymentID: "copy"}
+	err := rn.mapTenants([]string{"tenant1_label1", "tenant1_label2", "tenant2_label1"})
+	c.Assert(err, IsNil)
+	c.Assert(rn.Tenants, HasLen, 2)
+	c.Check(rn.RenameTenant("tenant1"), Not(Equals), "tenant1")
+	c.Check(rn.RenameTenant("tenant2"), Not(Equals), rn.RenameTenant("tenant1"))
+	c.Check(rn.RenameTenant("tenant3"), Equals, "tenant3")
+
+	rn = &restoreRename{DeploymentID: "copy", Name: "app-copy"}
+	err = rn.mapTenants([]string{"tenant1_label1", "tenant2_label1"})
+	c.Assert(err, Equals, ErrRestoreNameTenants)
+}
+
+func (t *RestoreAsTest) Test_Services(c *C) {
+	rn := &restoreRename{
+		DeploymentID: "copy",
+		Name:         "app-copy",
+		Tenants:      map[string]string{"tenant": "newtenant"},
+	}
+	svcs := []service.Service{
+		{
+			ID:           "tenant",
+			Name:         "app",
+			DeploymentID: "prod",
+			ImageID:      "localhost:5000/tenant/repo:latest",
+		}, {
+			ID:              "child",
+			Name:            "web",
+			ParentServiceID: "tenant",
+			DeploymentID:    "prod",
+			ImageID:         "localhost:5000/tenant/repo:latest",
+			Endpoints: []service.ServiceEndpoint{
+				{
+					Name:              "http",
+					VHostList:         []servicedefinition.VHost{{Name: "app", Enabled: true}},
+					AddressAssignment: addressassignment.AddressAssignment{IPAddr: "10.0.0.1"},
+				},
+			},
+		},
+	}
+	result, err := rn.services("tenant", svcs)
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 2)
+
+	c.Check(result[0].ID, Equals, "newtenant")
+	c.Check(result[0].Name, Equals, "app-copy")
+	c.Check(result[0].DeploymentID, Equals, "copy")
+	c.Check(result[0].ImageID, Equals, "localhost:5000/newtenant/repo:latest")
+
+	c.Check(result[1].ID, Not(Equals), "child")
+	c.Check(result[1].Name, Equals, "web")
+	c.Check(result[1].ParentServiceID, Equals, "newtenant")
+	c.Check(result[1].DeploymentID, Equals, "copy")
+	c.Check(result[1].ImageID, Equals, "localhost:5000/newtenant/repo:latest")
+	c.Assert(result[1].Endpoints, HasLen, 1)
+	c.Check(result[1].Endpoints[0].VHostList[0].Name, Equals, "app-copy")
+	c.Check(result[1].Endpoints[0].AddressAssignment.IPAddr, Equals, "")
+
+	// the services of the backup are unchanged
+	c.Check(svcs[1].ID, Equals, "child")
+	c.Check(svcs[1].Endpoints[0].VHostList[0].Name, Equals, "app")
+	c.Check(svcs[1].Endpoints[0].AddressAssignment.IPAddr, Equals, "10.0.0.1")
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// PurgePoolRetention deletes the application logs and metrics of the services
+// in each resource pool that are older than the retention overrides of the
+// pool.  Pools without overrides are only purged by the cluster wide
+// retention settings.
+func (f *Facade) PurgePoolRetention(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PurgePoolRetention"))
+
+	if f.retentionClient == nil {
+		return nil
+	}
+
+	pools, err := f.GetResourcePools(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up resource pools")
+		return err
+	}
+
+	now := time.Now()
+	for _, p := range pools {
+		logger := plog.WithField("poolid", p.ID)
+
+		if p.LogRetentionDays > 0 {
+			before := now.AddDate(0, 0, -p.LogRetentionDays)
+			logger := logger.WithField("logretentiondays", p.LogRetentionDays)
+			if err := f.retentionClient.PurgePoolLogs(p.ID, before); err != nil {
+				logger.WithError(err).Warn("Unable to purge application logs of resource pool")
+			} else {
+				logger.Info("Purged application logs of resource pool older than its retention")
+			}
+		}
+
+		if p.MetricRetentionDays > 0 {
+			before := now.AddDate(0, 0, -p.MetricRetentionDays)
+			logger := logger.WithField("metricretentiondays", p.MetricRetentionDays)
+			details, err := f.QueryServiceDetails(ctx, service.Query{PoolID: p.ID})
+			if err != nil {
+				logger.WithError(err).Warn("Unable to look up services of resource pool")
+				continue
+			}
+			serviceIDs := make([]string, len(details))
+			for i, d := range details {
+				serviceIDs[i] = d.ID
+			}
+			if err := f.retentionClient.PurgeServiceMetrics(serviceIDs, before); err != nil {
+				logger.WithError(err).Warn("Unable to purge metrics of resource pool")
+			} else {
+				logger.Info("Purged metrics of resource pool older than its retention")
+			}
+		}
+	}
+	return nil
+}
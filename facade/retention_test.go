@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+type testRetentionClient struct {
+	logs    map[string]time.Time
+	metrics map[string]time.Time
+}
+
+func (r *testRetentionClient) PurgePoolLogs(poolID string, before time.Time) error {
+	r.logs[poolID] = before
+	return nil
+}
+
+func (r *testRetentionClient) PurgeServiceMetrics(serviceIDs []string, before time.Time) error {
+	for _, id := range serviceIDs {
+		r.metrics[id] = before
+	}
+	return nil
+}
+
+func (ft *FacadeIntegrationTest) TestPurgePoolRetention(c *C) {
+	client := &testRetentionClient{logs: map[string]time.Time{}, metrics: map[string]time.Time{}}
+	ft.Facade.SetRetentionClient(client)
+	defer ft.Facade.SetRetentionClient(nil)
+
+	c.Assert(ft.Facade.AddResourcePool(ft.CTX, &pool.ResourcePool{ID: "prod"}), IsNil)
+	c.Assert(ft.Facade.AddResourcePool(ft.CTX, &pool.ResourcePool{ID: "dev", LogRetentionDays: 2, MetricRetentionDays: 3}), IsNil)
+	for _, svc := range []service.Service{
+		{ID: "prod-svc", Name: "prod", DeploymentID: "prod", PoolID: "prod", Launch: "auto"},
+		{ID: "dev-svc", Name: "dev", DeploymentID: "dev", PoolID: "dev", Launch: "auto"},
+	} {
+		c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+	}
+
+	now := time.Now()
+	c.Assert(ft.Facade.PurgePoolRetention(ft.CTX), IsNil)
+
+	// only the pool with overrides is purged
+	c.Assert(client.logs, HasLen, 1)
+	c.Assert(client.logs["dev"].Before(now.AddDate(0, 0, -2).Add(time.Minute)), Equals, true)
+	c.Assert(client.logs["dev"].After(now.AddDate(0, 0, -2).Add(-time.Minute)), Equals, true)
+	c.Assert(client.metrics, HasLen, 1)
+	c.Assert(client.metrics["dev-svc"].Before(now.AddDate(0, 0, -3).Add(time.Minute)), Equals, true)
+	c.Assert(client.metrics["dev-svc"].After(now.AddDate(0, 0, -3).Add(-time.Minute)), Equals, true)
+}
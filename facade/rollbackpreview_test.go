@@ -0,0 +1,78 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&RollbackPreviewTest{})
+
+type RollbackPreviewTest struct{}
+
+func (t *RollbackPreviewTest) Test_DiffConfigFiles(c *C) {
+	conf := func(filename, content string) servicedefinition.ConfigFile {
+		return servicedefinition.ConfigFile{Filename: filename, Owner: "root:root", Permissions: "0644", Content: content}
+	}
+	current := []service.Service{
+		{
+			ID:   "zope",
+			Name: "Zope",
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/zope.conf": conf("/etc/zope.conf", "threads 4"),
+				"/etc/same.conf": conf("/etc/same.conf", "same"),
+				"/etc/new.conf":  conf("/etc/new.conf", "new"),
+			},
+		}, {
+			ID:   "redis",
+			Name: "redis",
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/redis.conf": conf("/etc/redis.conf", "redis"),
+			},
+		},
+	}
+	snapshot := []service.Service{
+		{
+			ID:   "zope",
+			Name: "Zope",
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/zope.conf": conf("/etc/zope.conf", "threads 2"),
+				"/etc/same.conf": conf("/etc/same.conf", "same"),
+				"/etc/old.conf":  conf("/etc/old.conf", "old"),
+			},
+		}, {
+			ID:   "mariadb",
+			Name: "MariaDB",
+			ConfigFiles: map[string]servicedefinition.ConfigFile{
+				"/etc/my.cnf": conf("/etc/my.cnf", "mariadb"),
+			},
+		},
+	}
+	changes := diffConfigFiles(current, snapshot)
+	c.Assert(changes, DeepEquals, []dfs.ConfigFileChange{
+		{ServiceID: "mariadb", Name: "MariaDB", Filename: "/etc/my.cnf", Change: dfs.ConfigFileAdded},
+		{ServiceID: "zope", Name: "Zope", Filename: "/etc/new.conf", Change: dfs.ConfigFileRemoved},
+		{ServiceID: "zope", Name: "Zope", Filename: "/etc/old.conf", Change: dfs.ConfigFileAdded},
+		{ServiceID: "zope", Name: "Zope", Filename: "/etc/zope.conf", Change: dfs.ConfigFileModified},
+		{ServiceID: "redis", Name: "redis", Filename: "/etc/redis.conf", Change: dfs.ConfigFileRemoved},
+	})
+
+	c.Assert(diffConfigFiles(current, current), DeepEquals, []dfs.ConfigFileChange{})
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/validation"
+)
+
+// The names of the published JSON Schemas
+const (
+	SchemaServiceTemplate = "template"
+	SchemaService         = "service"
+)
+
+// ErrSchemaNotFound is returned when there is no schema with the name
+var ErrSchemaNotFound = errors.New("facade: schema not found")
+
+var schemas = map[string]*validation.Schema{
+	SchemaServiceTemplate: servicetemplate.Schema(),
+	SchemaService:         service.Schema(),
+}
+
+// GetSchema returns the JSON Schema that service templates or services are
+// validated against
+func (f *Facade) GetSchema(ctx datastore.Context, name string) (*validation.Schema, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSchema"))
+	s, ok := schemas[name]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+	return s, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/secret"
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// ErrSecretNotFound is returned when a secret does not exist
+var ErrSecretNotFound = errors.New("facade: secret not found")
+
+// SetSecret encrypts a value and stores it as the named secret, adding the
+// secret if it does not exist
+func (f *Facade) SetSecret(ctx datastore.Context, name, description, value string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetSecret"))
+	alog := f.auditLogger.Message(ctx, "Setting Secret").Action(audit.Update).ID(name).Type(secret.GetType())
+
+	ciphertext, err := auth.EncryptForMaster([]byte(value))
+	if err != nil {
+		return alog.Error(err)
+	}
+	entity := secret.New(name, ciphertext)
+	entity.Description = description
+
+	now := time.Now()
+	if s, err := f.getSecret(ctx, name); err != nil {
+		return alog.Error(err)
+	} else if s != nil {
+		entity.CreatedAt = s.CreatedAt
+		entity.DatabaseVersion = s.DatabaseVersion
+	} else {
+		entity.CreatedAt = now
+	}
+	entity.UpdatedAt = now
+	return alog.Error(f.secretStore.Put(ctx, secret.Key(name), entity))
+}
+
+// RemoveSecret removes a secret
+func (f *Facade) RemoveSecret(ctx datastore.Context, name string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveSecret"))
+	alog := f.auditLogger.Message(ctx, "Removing Secret").Action(audit.Remove).ID(name).Type(secret.GetType())
+
+	if s, err := f.getSecret(ctx, name); err != nil {
+		return alog.Error(err)
+	} else if s == nil {
+		return alog.Error(ErrSecretNotFound)
+	}
+	return alog.Error(f.secretStore.Delete(ctx, secret.Key(name)))
+}
+
+// GetSecrets returns all secrets without their values
+func (f *Facade) GetSecrets(ctx datastore.Context) ([]secret.Secret, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSecrets"))
+	secrets, err := f.secretStore.GetSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range secrets {
+		secrets[i].Value = nil
+	}
+	return secrets, nil
+}
+
+// GetSecretValue returns the decrypted value of a secret
+func (f *Facade) GetSecretValue(ctx datastore.Context, name string) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSecretValue"))
+	s, err := f.getSecret(ctx, name)
+	if err != nil {
+		return "", err
+	} else if s == nil {
+		return "", ErrSecretNotFound
+	}
+	value, err := auth.DecryptAsMaster(s.Value)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// GetServiceSecrets returns the decrypted values of the secrets that the
+// config files of a service instance reference
+func (f *Facade) GetServiceSecrets(ctx datastore.Context, serviceID string, instanceID int) (map[string]string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceSecrets"))
+	svc, err := f.GetEvaluatedService(ctx, serviceID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, configFile := range svc.ConfigFiles {
+		for _, name := range secret.References(configFile.Content) {
+			if _, ok := values[name]; ok {
+				continue
+			}
+			value, err := f.GetSecretValue(ctx, name)
+			if err == ErrSecretNotFound {
+				plog.WithFields(log.Fields{
+					"serviceid": serviceID,
+					"secret":    name,
+				}).Warn("Config file references a secret that does not exist")
+				return nil, err
+			} else if err != nil {
+				return nil, err
+			}
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// downloadImage adds an image to the registry of the tenant.  If credential is
+// set, the image is pulled with the registry credentials stored in that
+// secret.
+func (f *Facade) downloadImage(ctx datastore.Context, imageID, tenantID string, upgrade bool, credential string) (string, error) {
+	if credential == "" {
+		return f.dfs.Download(imageID, tenantID, upgrade)
+	}
+	value, err := f.GetSecretValue(ctx, credential)
+	if err != nil {
+		plog.WithError(err).WithFields(log.Fields{
+			"image":  imageID,
+			"secret": credential,
+		}).Warn("Could not look up the registry credentials of the image")
+		return "", err
+	}
+	creds, err := secret.ParseRegistryCredential(value)
+	if err != nil {
+		return "", err
+	}
+	return f.dfs.DownloadWithCredentials(imageID, tenantID, upgrade, &dockerclient.AuthConfiguration{
+		Username:      creds.Username,
+		Password:      creds.Password,
+		Email:         creds.Email,
+		ServerAddress: creds.ServerAddress,
+	})
+}
+
+// getSecret returns the secret with the given name or nil if it does not
+// exist
+func (f *Facade) getSecret(ctx datastore.Context, name string) (*secret.Secret, error) {
+	var entity secret.Secret
+	if err := f.secretStore.Get(ctx, secret.Key(name), &entity); datastore.IsErrNoSuchEntity(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
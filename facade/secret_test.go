@@ -0,0 +1,101 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) TestSecret_CRUD(c *C) {
+	err := ft.Facade.SetSecret(ft.CTX, "db.password", "database password", "hunter2")
+	c.Assert(err, IsNil)
+
+	value, err := ft.Facade.GetSecretValue(ft.CTX, "db.password")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "hunter2")
+
+	// the stored value is encrypted
+	stored, err := ft.Facade.getSecret(ft.CTX, "db.password")
+	c.Assert(err, IsNil)
+	c.Assert(string(stored.Value), Not(Equals), "hunter2")
+
+	err = ft.Facade.SetSecret(ft.CTX, "db.password", "database password", "correct horse")
+	c.Assert(err, IsNil)
+	value, err = ft.Facade.GetSecretValue(ft.CTX, "db.password")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "correct horse")
+
+	secrets, err := ft.Facade.GetSecrets(ft.CTX)
+	c.Assert(err, IsNil)
+	c.Assert(secrets, HasLen, 1)
+	c.Assert(secrets[0].Name, Equals, "db.password")
+	c.Assert(secrets[0].Description, Equals, "database password")
+	c.Assert(secrets[0].Value, IsNil)
+	c.Assert(secrets[0].CreatedAt.Unix(), Equals, stored.CreatedAt.Unix())
+
+	err = ft.Facade.RemoveSecret(ft.CTX, "db.password")
+	c.Assert(err, IsNil)
+	_, err = ft.Facade.GetSecretValue(ft.CTX, "db.password")
+	c.Assert(err, Equals, ErrSecretNotFound)
+	err = ft.Facade.RemoveSecret(ft.CTX, "db.password")
+	c.Assert(err, Equals, ErrSecretNotFound)
+}
+
+func (ft *FacadeIntegrationTest) TestSecret_GetServiceSecrets(c *C) {
+	ft.zzk.On("GetServiceState", ft.CTX, "default", "secretsvc", mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+	svc := service.Service{
+		ID:           "secretsvc",
+		Name:         "db",
+		DeploymentID: "deploymentid",
+		PoolID:       "default",
+		Launch:       "auto",
+		DesiredState: int(service.SVCStop),
+		OriginalConfigs: map[string]servicedefinition.ConfigFile{
+			"/etc/db.conf": {
+				Filename: "/etc/db.conf",
+				Content:  `user={{.Name}} password={{secret "db.password"}}`,
+			},
+		},
+	}
+	c.Assert(ft.Facade.AddService(ft.CTX, svc), IsNil)
+
+	_, err := ft.Facade.GetServiceSecrets(ft.CTX, "secretsvc", 0)
+	c.Assert(err, Equals, ErrSecretNotFound)
+
+	c.Assert(ft.Facade.SetSecret(ft.CTX, "db.password", "", "hunter2"), IsNil)
+	c.Assert(ft.Facade.SetSecret(ft.CTX, "unused", "", "other"), IsNil)
+	values, err := ft.Facade.GetServiceSecrets(ft.CTX, "secretsvc", 0)
+	c.Assert(err, IsNil)
+	c.Assert(values, DeepEquals, map[string]string{"db.password": "hunter2"})
+}
+
+func (ft *FacadeIntegrationTest) TestSecret_DownloadImageWithRegistryCredential(c *C) {
+	_, err := ft.Facade.downloadImage(ft.CTX, "private.io/repo:tag", "tenant", false, "registry.creds")
+	c.Assert(err, Equals, ErrSecretNotFound)
+
+	c.Assert(ft.Facade.SetSecret(ft.CTX, "registry.creds", "", "user:pass"), IsNil)
+	creds := &dockerclient.AuthConfiguration{Username: "user", Password: "pass"}
+	ft.dfs.On("DownloadWithCredentials", "private.io/repo:tag", "tenant", false, creds).Return("localhost:5000/tenant/repo:latest", nil)
+	image, err := ft.Facade.downloadImage(ft.CTX, "private.io/repo:tag", "tenant", false, "registry.creds")
+	c.Assert(err, IsNil)
+	c.Assert(image, Equals, "localhost:5000/tenant/repo:latest")
+	ft.dfs.AssertNotCalled(c, "Download", "private.io/repo:tag", "tenant", false)
+}
@@ -29,6 +29,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/zenoss/glog"
 
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/commons"
 	"github.com/control-center/serviced/dao"
@@ -43,6 +44,7 @@ import (
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
 	"github.com/control-center/serviced/utils"
+	zkr "github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
@@ -73,6 +75,11 @@ func (err ErrInvalidServiceOption) Error() string {
 	return fmt.Sprintf("Invalid service option specified: %s", err.error)
 }
 
+// ErrorCode implements apierror.Coder
+func (err ErrInvalidServiceOption) ErrorCode() apierror.Code {
+	return apierror.Validation
+}
+
 type IpArgs struct {
 	AuditName	string
 	Portmap		Ports
@@ -84,6 +91,10 @@ func (f *Facade) AddService(ctx datastore.Context, svc service.Service) (err err
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddService"))
 	alog := f.auditLogger.Action(audit.Add).Message(ctx, "Adding new Service ").WithField("servicename", svc.Name).Entity(&svc)
 
+	if err = f.admit(ctx, AdmissionServiceAdd, &svc, nil); err != nil {
+		return alog.Error(err)
+	}
+
 	if svc.ParentServiceID == "" {
 		tenantID = svc.ID
 	} else if tenantID, err = f.GetTenantID(ctx, svc.ParentServiceID); err != nil {
@@ -169,7 +180,7 @@ func (f *Facade) validateServiceAdd(ctx datastore.Context, svc *service.Service)
 					logger.WithField("vhost", vhost.Name).WithError(err).Error("Could not check public endpoint for virtual host")
 					return err
 				}
-				if serviceID != "" || application != "" {
+				if (serviceID != "" || application != "") && serviceID != svc.ID {
 					logger.WithFields(log.Fields{
 						"vhost": vhost.Name,
 						"otherservice": serviceID,
@@ -182,12 +193,12 @@ func (f *Facade) validateServiceAdd(ctx datastore.Context, svc *service.Service)
 
 		for j, port := range ep.PortList {
 			if port.Enabled {
-				serviceID, application, err := f.zzk.GetPublicPort(port.PortAddr)
+				serviceID, application, err := f.zzk.GetPublicPort(zkr.PublicPortName(port.PortAddr, port.ServerName))
 				if err != nil {
 					logger.WithField("portaddr", port.PortAddr).WithError(err).Error("Could not check public endpoint for port")
 					return err
 				}
-				if serviceID != "" || application != "" {
+				if (serviceID != "" || application != "") && serviceID != svc.ID {
 					logger.WithFields(log.Fields{
 						"portaddr": port.PortAddr,
 						"otherservice": serviceID,
@@ -296,6 +307,9 @@ func (f * Facade) getChanges(ctx datastore.Context, svc service.Service) string
 func (f *Facade) UpdateService(ctx datastore.Context, svc service.Service) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpdateService"))
 	alog := f.auditLogger.Action(audit.Update).Message(ctx, "Update Service").WithField("servicename", svc.Name).Entity(&svc)
+	if err := schemas[SchemaService].ValidateValue(svc); err != nil {
+		return alog.Error(err)
+	}
 	tenantID, err := f.GetTenantID(ctx, svc.ID)
 	if err != nil {
 		return alog.Error(err)
@@ -303,8 +317,14 @@ func (f *Facade) UpdateService(ctx datastore.Context, svc service.Service) error
 	mutex := getTenantLock(tenantID)
 	mutex.RLock()
 	defer mutex.RUnlock()
+	if err := f.admitServiceUpdate(ctx, &svc); err != nil {
+		return alog.Error(err)
+	}
 	updates := f.getChanges(ctx, svc)
 	alog = alog.WithField("updates", updates)
+	if cursvc, err := f.serviceStore.Get(ctx, svc.ID); err == nil {
+		alog = alog.Delta(cursvc, &svc)
+	}
 	return alog.Error(f.updateService(ctx, tenantID, svc, false, false))
 }
 
@@ -443,7 +463,7 @@ func (f *Facade) validateServiceUpdate(ctx datastore.Context, svc *service.Servi
 
 		for _, port := range ep.PortList {
 			if port.Enabled {
-				serviceID, application, err := f.zzk.GetPublicPort(port.PortAddr)
+				serviceID, application, err := f.zzk.GetPublicPort(zkr.PublicPortName(port.PortAddr, port.ServerName))
 				if err != nil {
 					logger.WithField("portaddr", port.PortAddr).WithError(err).Error("Could not check public endpoint for port")
 					return nil, err
@@ -943,6 +963,7 @@ func (f *Facade) RemoveIPs(ctx datastore.Context, args []string) error {
 
 func (f *Facade) SetIPs(ctx datastore.Context, request addressassignment.AssignmentRequest) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetIPs"))
+	request.IPAddress = utils.CanonicalIP(request.IPAddress)
 	ports := make(map[uint16]struct{})
 	port := request.Port
 	ports[port] = struct{}{}
@@ -1122,9 +1143,29 @@ func (f *Facade) GetEvaluatedService(ctx datastore.Context, serviceID string, in
 		return nil, err
 	}
 
+	// a canary deploy may pin the instance to a different image
+	state, stateErr := f.zzk.GetServiceState(ctx, svc.PoolID, serviceID, instanceID)
+	if stateErr == nil && state.ImageID != "" {
+		logger.WithField("imageid", state.ImageID).Debug("Using the pinned image of the instance")
+		svc.ImageID = state.ImageID
+	} else if svc.ImageDigest != "" {
+		svc.ImageID = f.pinnedImageID(ctx, svc)
+	}
+
 	if err := f.evaluateService(ctx, svc, instanceID); err != nil {
 		return nil, err
 	}
+
+	// the instance may override the environment of the service; later
+	// variables take precedence over earlier ones, and over the overrides
+	// of the service that the delegate merges in
+	if stateErr == nil && len(state.Environment) > 0 {
+		logger.WithField("variables", len(state.Environment)).Debug("Adding the environment override of the instance")
+		svc.Environment = append(svc.Environment, state.Environment...)
+		for _, v := range state.Environment {
+			delete(svc.EnvironmentOverrides, strings.SplitN(v, "=", 2)[0])
+		}
+	}
 	return svc, nil
 }
 
@@ -2111,23 +2152,42 @@ func (f *Facade) RestartService(ctx datastore.Context, request dao.ScheduleServi
 
 }
 
-// RebalanceService does a hard restart:  All services are stopped, and then all services are started again
+// RebalanceService does a hard restart:  All services are stopped, and then all services are started again.
+// An asynchronous rebalance runs as an operation; if it is canceled before the services are stopped, nothing
+// is restarted, and if it is canceled while they stop, they are left stopped.
 func (f *Facade) RebalanceService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RebalanceService"))
 
-	forceRestart := func() (int, error) {
+	forceRestart := func(ctx datastore.Context) (int, error) {
+		op := operationFromContext(ctx)
+		if op.IsCanceled() {
+			return 0, ErrOperationCanceled
+		}
+		op.SetProgress(0, "stopping services")
 		count, err := f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, true, service.SVCStop, false)
 		if err != nil {
 			return count, err
 		}
+		op.Logf("Stopped %d services", count)
+		if op.IsCanceled() {
+			return count, ErrOperationCanceled
+		}
 
-		return f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCRun, false)
+		op.SetProgress(50, "starting services")
+		count, err = f.ScheduleServices(ctx, request.ServiceIDs, request.AutoLaunch, request.Synchronous, service.SVCRun, false)
+		if err == nil {
+			op.Logf("Started %d services", count)
+		}
+		return count, err
 	}
 
 	if request.Synchronous {
-		return forceRestart()
+		return forceRestart(ctx)
 	} else {
-		go forceRestart()
+		f.StartOperation(ctx, OperationRebalance, strings.Join(request.ServiceIDs, ","), func(ctx datastore.Context) error {
+			_, err := forceRestart(ctx)
+			return err
+		})
 
 		// We need to figure out some count to return
 		check := func(svc *service.Service) bool {
@@ -2153,6 +2213,52 @@ func (f *Facade) StopService(ctx datastore.Context, request dao.ScheduleServiceR
 	return successCount, alog.Error(err)
 }
 
+// StartServices schedules exactly the listed services to start, without
+// their children.  The services of each tenant are handed to the service
+// state manager together, so that their desired states are written to
+// zookeeper in one transaction per pool instead of one call per service.
+func (f *Facade) StartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.StartServices"))
+	serviceIDs = uniqueServiceIDs(serviceIDs)
+	successCount, err := f.ScheduleServices(ctx, serviceIDs, false, synchronous, service.SVCRun, false)
+	alog := f.auditLogger.Action(audit.Start).Message(ctx, "Starting Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(serviceIDs, ", "), "count": successCount})
+	return successCount, alog.Error(err)
+}
+
+// RestartServices schedules exactly the listed services to restart, without
+// their children.
+func (f *Facade) RestartServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestartServices"))
+	serviceIDs = uniqueServiceIDs(serviceIDs)
+	successCount, err := f.ScheduleServices(ctx, serviceIDs, false, synchronous, service.SVCRestart, false)
+	alog := f.auditLogger.Action(audit.Restart).Message(ctx, "Restarting Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(serviceIDs, ", "), "count": successCount})
+	return successCount, alog.Error(err)
+}
+
+// StopServices schedules exactly the listed services to stop, without their
+// children.
+func (f *Facade) StopServices(ctx datastore.Context, serviceIDs []string, synchronous bool) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.StopServices"))
+	serviceIDs = uniqueServiceIDs(serviceIDs)
+	successCount, err := f.ScheduleServices(ctx, serviceIDs, false, synchronous, service.SVCStop, false)
+	alog := f.auditLogger.Action(audit.Stop).Message(ctx, "Stopping Service(s)").Type(service.GetType()).WithFields(log.Fields{"ids": strings.Join(serviceIDs, ", "), "count": successCount})
+	return successCount, alog.Error(err)
+}
+
+// uniqueServiceIDs returns the service ids without duplicates, in the order
+// they were first listed
+func uniqueServiceIDs(serviceIDs []string) []string {
+	seen := make(map[string]struct{})
+	result := []string{}
+	for _, serviceID := range serviceIDs {
+		if _, ok := seen[serviceID]; !ok {
+			seen[serviceID] = struct{}{}
+			result = append(result, serviceID)
+		}
+	}
+	return result
+}
+
 func (f *Facade) EmergencyStopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EmergencyStopService"))
 	alog := f.auditLogger.Message(ctx, "Emergency Stopping Services").Action(audit.Stop).
@@ -2349,6 +2455,7 @@ func IpVisitorFn(portmap Ports, request addressassignment.AssignmentRequest, f *
 
 func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.AssignmentRequest) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AssignIPs"))
+	request.IPAddress = utils.CanonicalIP(request.IPAddress)
 
 	svc, err := f.GetService(ctx, request.ServiceID)
 	if err != nil {
@@ -2365,6 +2472,12 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 // to latest, making sure to push changes to the registry
 func (f *Facade) ServiceUse(ctx datastore.Context, serviceID, imageName, registryName string, replaceImgs []string, noOp bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ServiceUse"))
+	endOperation, err := f.BeginDFSOperation(ctx, DFSOperationImagePush, imageName, serviceID)
+	if err != nil {
+		return err
+	}
+	defer endOperation()
+
 	glog.Infof("Pushing image %s for tenant %s into elastic", imageName, serviceID)
 	// Push into elastic
 	if err := f.Download(imageName, serviceID); err != nil {
@@ -2776,7 +2889,9 @@ func (f *Facade) SetServicesCurrentState(ctx datastore.Context, currentState ser
 	for _, sid := range serviceIDs {
 		if err := f.serviceStore.UpdateCurrentState(ctx, sid, string(currentState)); err != nil {
 			logger.WithField("serviceid", sid).WithError(err).Error("Failed to update service current state")
+			continue
 		}
+		f.publishServiceState(ctx, sid, currentState)
 	}
 }
 
@@ -2943,6 +3058,7 @@ func (f *Facade) getServicePublicEndpoints(svc service.Service) []service.Public
 				Application: ep.Application,
 				PortAddress: port.PortAddr,
 				Enabled:     port.Enabled,
+				ServerName:  port.ServerName,
 			}
 
 			if strings.HasPrefix(port.Protocol, "http") {
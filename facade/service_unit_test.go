@@ -20,9 +20,13 @@ import (
 	"strings"
 
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
 
@@ -187,6 +191,8 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceSimple(c *C) {
 	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(&svc, nil)
 	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
 
+	ft.zzk.On("GetServiceState", ft.ctx, "", serviceID, mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+
 	instanceID := 99
 	result, err := ft.Facade.GetEvaluatedService(ft.ctx, serviceID, instanceID)
 
@@ -197,6 +203,133 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceSimple(c *C) {
 	c.Assert(result.Actions["instanceID"], Equals, fmt.Sprintf("%d", instanceID))
 }
 
+// Test that GetEvaluatedService uses the image that is pinned to the instance
+func (ft *FacadeUnitTest) Test_GetEvaluatedServicePinnedImage(c *C) {
+	serviceID := "0"
+	svc := service.Service{
+		ID:      serviceID,
+		Name:    "service0",
+		PoolID:  "default",
+		ImageID: "tenant/repo:latest",
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(&svc, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+
+	state := &zkservice.State{ServiceID: serviceID, InstanceID: 1}
+	state.ImageID = "tenant/repo:20170101_000000.000"
+	ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, 1).Return(state, nil)
+	ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, 0).Return(&zkservice.State{ServiceID: serviceID}, nil)
+
+	result, err := ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.ImageID, Equals, "tenant/repo:latest")
+
+	result, err = ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 1)
+	c.Assert(err, IsNil)
+	c.Assert(result.ImageID, Equals, "tenant/repo:20170101_000000.000")
+}
+
+// Test that GetEvaluatedService adds the environment override of the instance
+func (ft *FacadeUnitTest) Test_GetEvaluatedServiceInstanceEnvironment(c *C) {
+	serviceID := "0"
+	svc := service.Service{
+		ID:                   serviceID,
+		Name:                 "service0",
+		PoolID:               "default",
+		Environment:          []string{"LOG_LEVEL=info"},
+		EnvironmentOverrides: map[string]string{"LOG_LEVEL": "warn", "TRACE": "1"},
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(func(datastore.Context, string) *service.Service {
+		copy := svc
+		copy.Environment = append([]string{}, svc.Environment...)
+		copy.EnvironmentOverrides = map[string]string{}
+		for k, v := range svc.EnvironmentOverrides {
+			copy.EnvironmentOverrides[k] = v
+		}
+		return &copy
+	}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+
+	state := &zkservice.State{ServiceID: serviceID, InstanceID: 1}
+	state.Environment = []string{"LOG_LEVEL=debug"}
+	ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, 1).Return(state, nil)
+	ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, 0).Return(&zkservice.State{ServiceID: serviceID}, nil)
+
+	result, err := ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.Environment, DeepEquals, []string{"LOG_LEVEL=info"})
+	c.Assert(result.ContainerEnvironment(), DeepEquals, []string{"LOG_LEVEL=warn", "TRACE=1"})
+
+	// the override of the instance takes precedence over the overrides of
+	// the service
+	result, err = ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 1)
+	c.Assert(err, IsNil)
+	c.Assert(result.Environment, DeepEquals, []string{"LOG_LEVEL=info", "LOG_LEVEL=debug"})
+	c.Assert(result.ContainerEnvironment(), DeepEquals, []string{"LOG_LEVEL=info", "LOG_LEVEL=debug", "TRACE=1"})
+}
+
+// Test that SetServiceInstanceEnvironment rejects variables without a name
+func (ft *FacadeUnitTest) Test_SetServiceInstanceEnvironmentInvalid(c *C) {
+	err := ft.Facade.SetServiceInstanceEnvironment(ft.ctx, "0", 1, []string{"LOG_LEVEL=debug", "=debug"})
+	c.Assert(err, Equals, facade.ErrInvalidInstanceEnvironment)
+	err = ft.Facade.SetServiceInstanceEnvironment(ft.ctx, "0", 1, []string{"LOG_LEVEL"})
+	c.Assert(err, Equals, facade.ErrInvalidInstanceEnvironment)
+}
+
+// Test that GetEvaluatedService runs the digest that the service is pinned to
+func (ft *FacadeUnitTest) Test_GetEvaluatedServiceDigestPin(c *C) {
+	digest := "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	serviceID := "0"
+	svc := service.Service{
+		ID:          serviceID,
+		Name:        "service0",
+		PoolID:      "default",
+		ImageID:     "tenant/repo:latest",
+		ImageDigest: digest,
+	}
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).Return(&service.ServiceDetails{ID: serviceID}, nil)
+	ft.serviceStore.On("Get", ft.ctx, serviceID).Return(func(datastore.Context, string) *service.Service {
+		copy := svc
+		return &copy
+	}, nil)
+	ft.configStore.On("GetConfigFiles", ft.ctx, serviceID, "/"+serviceID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
+	ft.zzk.On("GetServiceState", ft.ctx, "default", serviceID, 0).Return(&zkservice.State{ServiceID: serviceID}, nil)
+
+	pinKey := "tenant/repo:sha256-4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	pinned := ft.registryStore.On("Get", ft.ctx, pinKey).Return(&registry.Image{Library: "tenant", Repo: "repo", UUID: digest}, nil).Once()
+	result, err := ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.ImageID, Equals, pinKey)
+
+	// the service runs its image if the digest is no longer tagged
+	pinned.Return(nil, datastore.ErrNoSuchEntity{}).Once()
+	result, err = ft.Facade.GetEvaluatedService(ft.ctx, serviceID, 0)
+	c.Assert(err, IsNil)
+	c.Assert(result.ImageID, Equals, "tenant/repo:latest")
+}
+
+// Test that GetServiceImagePins reports the digests of the service and its children
+func (ft *FacadeUnitTest) Test_GetServiceImagePins(c *C) {
+	oldDigest := "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	newDigest := "sha256:9a0bca8d42d0c7b1f2a1d79dd5c9a19a1a13ebb38cb6a0c1e0d2ef5bb29c1d07"
+	parent := service.Service{ID: "parent", Name: "parent"}
+	child := service.Service{ID: "child", Name: "child", ParentServiceID: "parent", ImageID: "tenant/repo:latest", ImageDigest: oldDigest}
+	ft.serviceStore.On("Get", ft.ctx, "parent").Return(&parent, nil)
+	ft.serviceStore.On("Get", ft.ctx, "child").Return(&child, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "parent").Return([]service.Service{child}, nil)
+	ft.serviceStore.On("GetChildServices", ft.ctx, "child").Return([]service.Service{}, nil)
+	ft.registryStore.On("Get", ft.ctx, "tenant/repo:latest").Return(&registry.Image{Library: "tenant", Repo: "repo", Tag: "latest", UUID: newDigest}, nil)
+
+	pins, err := ft.Facade.GetServiceImagePins(ft.ctx, "parent")
+	c.Assert(err, IsNil)
+	c.Assert(pins, DeepEquals, []service.ImagePin{
+		{ServiceID: "child", Name: "child", ImageID: "tenant/repo:latest", Digest: oldDigest, Current: newDigest},
+	})
+	c.Assert(pins[0].Stale(), Equals, true)
+}
+
 // Test that the 'getService' function defined by facade.evaluateService() works properly on success
 func (ft *FacadeUnitTest) Test_GetEvaluatedServiceUsesParent(c *C) {
 	parentID := "parentServiceID"
@@ -220,6 +353,8 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceUsesParent(c *C) {
 	childServicePath := "/" + parentID + "/" + childID
 	ft.configStore.On("GetConfigFiles", ft.ctx, parentID, childServicePath).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
 
+	ft.zzk.On("GetServiceState", ft.ctx, "", childID, mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+
 	instanceID := 99
 	result, err := ft.Facade.GetEvaluatedService(ft.ctx, childID, instanceID)
 
@@ -257,6 +392,8 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceUsesChild(c *C) {
 	childServicePath := "/" + parentID + "/" + childID
 	ft.configStore.On("GetConfigFiles", ft.ctx, parentID, childServicePath).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
 
+	ft.zzk.On("GetServiceState", ft.ctx, "", parentID, mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+
 	instanceID := 99
 	result, err := ft.Facade.GetEvaluatedService(ft.ctx, parentID, instanceID)
 
@@ -324,6 +461,8 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceGetParentFails(c *C) {
 	ft.serviceStore.On("Get", ft.ctx, parentID).Return(nil, expectedError)
 	ft.configStore.On("GetConfigFiles", ft.ctx, parentID, "/"+parentID).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
 
+	ft.zzk.On("GetServiceState", ft.ctx, "", childID, mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+
 	unused := 0
 	result, err := ft.Facade.GetEvaluatedService(ft.ctx, childID, unused)
 
@@ -363,6 +502,8 @@ func (ft *FacadeUnitTest) Test_GetEvaluatedServiceGetChildFails(c *C) {
 	childServicePath := "/" + parentID + "/" + childID
 	ft.configStore.On("GetConfigFiles", ft.ctx, parentID, childServicePath).Return([]*serviceconfigfile.SvcConfigFile{}, nil)
 
+	ft.zzk.On("GetServiceState", ft.ctx, "", parentID, mock.AnythingOfType("int")).Return(nil, zkservice.ErrInstanceNotFound)
+
 	unused := 0
 	result, err := ft.Facade.GetEvaluatedService(ft.ctx, parentID, unused)
 
@@ -0,0 +1,151 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// serviceEventHistory is the number of state changes kept for clients that
+// wait after an earlier sequence number
+const serviceEventHistory = 1024
+
+// serviceEventBus publishes the changes to the current state of services.
+// Sequence numbers start at the time the bus was created, so that clients
+// that wait across a restart of the master do not miss changes.
+type serviceEventBus struct {
+	mu      sync.Mutex
+	seq     uint64
+	events  []service.StateEvent
+	states  map[string]service.ServiceCurrentState
+	changed chan struct{}
+}
+
+func newServiceEventBus() *serviceEventBus {
+	return &serviceEventBus{
+		seq:     uint64(time.Now().UnixNano()),
+		states:  make(map[string]service.ServiceCurrentState),
+		changed: make(chan struct{}),
+	}
+}
+
+// publish adds an event if the state of the service changed, and wakes up
+// the clients waiting for events.  It returns false if the state did not
+// change.
+func (b *serviceEventBus) publish(ev service.StateEvent) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if state, ok := b.states[ev.ServiceID]; ok && state == ev.State {
+		return false
+	}
+	b.states[ev.ServiceID] = ev.State
+	b.seq++
+	ev.Seq = b.seq
+	if len(b.events) >= serviceEventHistory {
+		copy(b.events, b.events[1:])
+		b.events = b.events[:len(b.events)-1]
+	}
+	b.events = append(b.events, ev)
+	close(b.changed)
+	b.changed = make(chan struct{})
+	return true
+}
+
+// since returns the events of a service or tenant after the given sequence
+// number, the sequence number of the last event, and a channel that is
+// closed when the next event is published.
+func (b *serviceEventBus) since(seq uint64, serviceID string) ([]service.StateEvent, uint64, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if seq == 0 || seq > b.seq {
+		seq = b.seq
+	}
+	events := []service.StateEvent{}
+	for _, ev := range b.events {
+		if ev.Seq <= seq {
+			continue
+		}
+		if serviceID == "" || ev.ServiceID == serviceID || ev.TenantID == serviceID {
+			events = append(events, ev)
+		}
+	}
+	return events, b.seq, b.changed
+}
+
+// wait returns the events of a service or tenant after the given sequence
+// number, waiting until an event is published or the timeout expires.
+func (b *serviceEventBus) wait(seq uint64, serviceID string, timeout time.Duration, cancel <-chan struct{}) *service.StateEvents {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		events, head, changed := b.since(seq, serviceID)
+		if len(events) > 0 {
+			return &service.StateEvents{Events: events, Seq: head}
+		}
+		seq = head
+		select {
+		case <-changed:
+		case <-timer.C:
+			return &service.StateEvents{Events: events, Seq: head}
+		case <-cancel:
+			return &service.StateEvents{Events: events, Seq: head}
+		}
+	}
+}
+
+// publishServiceState publishes a change to the current state of a service
+func (f *Facade) publishServiceState(ctx datastore.Context, serviceID string, state service.ServiceCurrentState) {
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up tenant of service for state event")
+	}
+	ev := service.StateEvent{
+		ServiceID: serviceID,
+		TenantID:  tenantID,
+		State:     state,
+		Timestamp: time.Now().UTC(),
+	}
+	if f.serviceEvents.publish(ev) {
+		f.recordEvent(ctx, event.Event{
+			Time:       ev.Timestamp,
+			Type:       event.TypeServiceState,
+			EntityType: service.GetType(),
+			EntityID:   serviceID,
+			TenantID:   tenantID,
+			Message:    fmt.Sprintf("Service state changed to %s", state),
+			Fields:     map[string]string{"state": string(state)},
+		})
+	}
+}
+
+// WaitServiceStateEvents returns the changes to the current state of a
+// service, or of every service of a tenant, that were published after the
+// given sequence number.  A sequence number of 0 only returns changes
+// published after the call.  It waits until a change is published, the
+// timeout expires, or cancel is closed; pass the returned sequence number to
+// the next call to receive every change.
+func (f *Facade) WaitServiceStateEvents(ctx datastore.Context, serviceID string, since uint64, timeout time.Duration, cancel <-chan struct{}) (*service.StateEvents, error) {
+	if serviceID != "" {
+		if _, err := f.GetServiceDetails(ctx, serviceID); err != nil {
+			return nil, err
+		}
+	}
+	return f.serviceEvents.wait(since, serviceID, timeout, cancel), nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&ServiceEventBusTest{})
+
+type ServiceEventBusTest struct{}
+
+func (t *ServiceEventBusTest) Test_PublishSince(c *C) {
+	bus := newServiceEventBus()
+	_, start, _ := bus.since(0, "")
+
+	bus.publish(service.StateEvent{ServiceID: "svc1", TenantID: "tenant1", State: service.SVCCSStarting})
+	bus.publish(service.StateEvent{ServiceID: "svc1", TenantID: "tenant1", State: service.SVCCSStarting})
+	bus.publish(service.StateEvent{ServiceID: "svc2", TenantID: "tenant2", State: service.SVCCSStopped})
+	bus.publish(service.StateEvent{ServiceID: "svc1", TenantID: "tenant1", State: service.SVCCSRunning})
+
+	// duplicate states are not published
+	events, head, _ := bus.since(start, "")
+	c.Assert(events, HasLen, 3)
+	c.Assert(head, Equals, start+3)
+	c.Assert(events[0].Seq, Equals, start+1)
+	c.Assert(events[2].State, Equals, service.SVCCSRunning)
+
+	// filter by service or tenant
+	events, _, _ = bus.since(start, "svc2")
+	c.Assert(events, HasLen, 1)
+	events, _, _ = bus.since(start, "tenant1")
+	c.Assert(events, HasLen, 2)
+	events, _, _ = bus.since(start+2, "tenant1")
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].State, Equals, service.SVCCSRunning)
+
+	// 0 and sequence numbers from the future only return new events
+	events, _, _ = bus.since(0, "")
+	c.Assert(events, HasLen, 0)
+	events, _, _ = bus.since(head+100, "")
+	c.Assert(events, HasLen, 0)
+}
+
+func (t *ServiceEventBusTest) Test_History(c *C) {
+	bus := newServiceEventBus()
+	_, start, _ := bus.since(0, "")
+	for i := 0; i < serviceEventHistory+10; i++ {
+		state := service.SVCCSStarting
+		if i%2 == 1 {
+			state = service.SVCCSRunning
+		}
+		bus.publish(service.StateEvent{ServiceID: "svc1", State: state})
+	}
+	events, _, _ := bus.since(start, "")
+	c.Assert(events, HasLen, serviceEventHistory)
+	c.Assert(events[0].Seq, Equals, start+11)
+}
+
+func (t *ServiceEventBusTest) Test_Wait(c *C) {
+	bus := newServiceEventBus()
+
+	// times out without events
+	result := bus.wait(0, "svc1", 10*time.Millisecond, nil)
+	c.Assert(result.Events, HasLen, 0)
+	seq := result.Seq
+
+	// returns when an event is published
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bus.publish(service.StateEvent{ServiceID: "svc2", State: service.SVCCSRunning})
+		bus.publish(service.StateEvent{ServiceID: "svc1", State: service.SVCCSRunning})
+	}()
+	result = bus.wait(seq, "svc1", 5*time.Second, nil)
+	c.Assert(result.Events, HasLen, 1)
+	c.Assert(result.Events[0].ServiceID, Equals, "svc1")
+	c.Assert(result.Seq, Equals, seq+2)
+
+	// returns when cancelled
+	cancel := make(chan struct{})
+	close(cancel)
+	result = bus.wait(result.Seq, "svc1", 5*time.Second, cancel)
+	c.Assert(result.Events, HasLen, 0)
+}
@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// ErrLogsUnavailable is returned when the facade cannot search the
+// application logs
+var ErrLogsUnavailable = errors.New("facade: application logs are not available")
+
+// GetServiceLogs returns the aggregated application log messages of all
+// instances of a service, oldest first.
+func (f *Facade) GetServiceLogs(ctx datastore.Context, query service.LogQuery) ([]service.LogMessage, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceLogs"))
+	logger := plog.WithField("serviceid", query.ServiceID)
+
+	if f.logsClient == nil {
+		return nil, ErrLogsUnavailable
+	}
+
+	// verify the service exists
+	if _, err := f.serviceStore.Get(ctx, query.ServiceID); err != nil {
+		logger.WithError(err).Debug("Could not look up service")
+		return nil, err
+	}
+
+	messages, err := f.logsClient.SearchServiceLogs(query)
+	if err != nil {
+		logger.WithError(err).Debug("Could not search service logs")
+		return nil, err
+	}
+	return messages, nil
+}
@@ -83,13 +83,14 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 			if p.Enabled {
 				key := zkr.PublicPortKey{
 					HostID:      "master",
-					PortAddress: p.PortAddr,
+					PortAddress: zkr.PublicPortName(p.PortAddr, p.ServerName),
 				}
 				pub := zkr.PublicPort{
 					TenantID:    tenantID,
 					Application: ep.Application,
 					ServiceID:   svc.ID,
 					Protocol:    p.Protocol,
+					AppProtocol: p.AppProtocol,
 					UseTLS:      p.UseTLS,
 				}
 				request.PortsToPublish[key] = pub
@@ -107,6 +108,7 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 					TenantID:    tenantID,
 					Application: ep.Application,
 					ServiceID:   svc.ID,
+					AppProtocol: v.AppProtocol,
 				}
 				request.VHostsToPublish[key] = vh
 			}
@@ -15,6 +15,7 @@ package facade
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -34,6 +35,10 @@ func (f *Facade) AddServiceTemplate(ctx datastore.Context, serviceTemplate servi
 		"template":       serviceTemplate.Name,
 		"reloadlogstash": reloadLogstashConfig,
 	})
+	if err := schemas[SchemaServiceTemplate].ValidateValue(serviceTemplate); err != nil {
+		logger.WithError(err).Debug("Service template does not conform to its schema")
+		return "", alog.Error(err)
+	}
 	store := f.templateStore
 	hash, err := serviceTemplate.Hash()
 	if err != nil {
@@ -66,6 +71,9 @@ func (f *Facade) AddServiceTemplate(ctx datastore.Context, serviceTemplate servi
 		logger.WithError(err).Error("Could not add template")
 		return "", alog.Error(err)
 	}
+	if err := f.dfs.SaveTemplate(serviceTemplate); err != nil {
+		logger.WithError(err).Warn("Could not save a copy of the template to the dfs")
+	}
 
 	if err := f.UpdateLogFilters(ctx, &serviceTemplate); err != nil {
 		logger.WithError(err).Error("Could not add/update logfilters for template")
@@ -91,9 +99,16 @@ func (f *Facade) UpdateServiceTemplate(ctx datastore.Context, template servicete
 		"reloadlogstash": reloadLogstashConfig,
 	})
 
+	if err := schemas[SchemaServiceTemplate].ValidateValue(template); err != nil {
+		logger.WithError(err).Debug("Service template does not conform to its schema")
+		return alog.Error(err)
+	}
 	if err := f.templateStore.Put(ctx, template); err != nil {
 		return alog.Error(err)
 	}
+	if err := f.dfs.SaveTemplate(template); err != nil {
+		logger.WithError(err).Warn("Could not save a copy of the template to the dfs")
+	}
 
 	if err := f.UpdateLogFilters(ctx, &template); err != nil {
 		logger.WithError(err).Error("Could not add/update logfilters for template")
@@ -128,6 +143,9 @@ func (f *Facade) RemoveServiceTemplate(ctx datastore.Context, id string) error {
 	if err := f.templateStore.Delete(ctx, id); err != nil {
 		return alog.Error(err)
 	}
+	if err := f.dfs.DeleteTemplate(id); err != nil {
+		logger.WithError(err).Warn("Could not delete the copy of the template from the dfs")
+	}
 
 	go LogstashContainerReloader(ctx, f)
 	alog.Succeeded()
@@ -173,6 +191,27 @@ func (f *Facade) RestoreServiceTemplates(ctx datastore.Context, templates []serv
 	return nil
 }
 
+// SaveServiceTemplateCopies writes a copy of each service template to the
+// dfs, including the templates that were added before copies were kept.
+func (f *Facade) SaveServiceTemplateCopies(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SaveServiceTemplateCopies"))
+	templates, err := f.templateStore.GetServiceTemplates(ctx)
+	if err != nil {
+		plog.WithError(err).Error("Could not get service templates")
+		return err
+	}
+	for _, t := range templates {
+		if err := f.dfs.SaveTemplate(*t); err != nil {
+			plog.WithFields(logrus.Fields{
+				"templateid": t.ID,
+				"template":   t.Name,
+			}).WithError(err).Error("Could not save a copy of the template to the dfs")
+			return err
+		}
+	}
+	return nil
+}
+
 // getServiceTemplateByMD5Sum returns the id of the template that matches the
 // given md5sum (if it exists)
 func (f *Facade) getServiceTemplateByMD5Sum(ctx datastore.Context, md5Sum string) (string, error) {
@@ -290,9 +329,21 @@ func (f *Facade) DeployTemplateStatus(deploymentID string, lastStatus string, ti
 	}
 }
 
-//DeployTemplate creates and deploys a service to the pool and returns the tenant id of the newly deployed service
-func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string) ([]string, error) {
+//DeployTemplate creates and deploys a service to the pool and returns the tenant id of the newly deployed service.
+//The values parameterize the services of the template, overriding the values of the template.  The deploy runs as
+//an operation; if it is canceled, the applications that were deployed are rolled back.
+func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, values servicetemplate.Values) ([]string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeployTemplate"))
+	var tenantIDs []string
+	err := f.RunOperation(ctx, OperationTemplateDeploy, templateID, func(ctx datastore.Context) (err error) {
+		tenantIDs, err = f.deployTemplate(ctx, poolID, templateID, deploymentID, values)
+		return err
+	})
+	return tenantIDs, err
+}
+
+func (f *Facade) deployTemplate(ctx datastore.Context, poolID string, templateID string, deploymentID string, values servicetemplate.Values) ([]string, error) {
+	op := operationFromContext(ctx)
 	alog := f.auditLogger.Message(ctx, "Deploying Service Template").
 		Action(audit.Deploy).ID(templateID).Type(servicetemplate.GetType()).
 		WithFields(logrus.Fields{"poolid": poolID, "deploymentid": deploymentID})
@@ -314,6 +365,26 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 		logger.WithError(err).Error("Unable to load template")
 		return nil, alog.Error(err)
 	}
+	if err := template.ApplyValues(values); err != nil {
+		logger.WithError(err).Debug("Could not apply values to template")
+		return nil, alog.Error(err)
+	}
+	template.ApplyRegistryCredential()
+	if err := template.ValidEntity(); err != nil {
+		logger.WithError(err).Debug("Template is not valid with the values")
+		return nil, alog.Error(err)
+	}
+
+	review := &TemplateDeployment{
+		TemplateID:   templateID,
+		PoolID:       poolID,
+		DeploymentID: deploymentID,
+		Template:     *template,
+	}
+	if err := f.admit(ctx, AdmissionTemplateDeploy, review, nil); err != nil {
+		return nil, alog.Error(err)
+	}
+	*template = review.Template
 
 	//check that deployment id does not already exist
 	logger = logger.WithField("template", template.Name)
@@ -339,21 +410,45 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 
 	var statusUpdater = func(status string) {
 		deployment.UpdateStatus(status)
+		op.Logf("%s", status)
 	}
 
+	// deploy the applications of the template; if one fails, roll back the
+	// applications that were deployed so no half-deployed application is left
 	tenantIDs := make([]string, len(template.Services))
+	volumes := make(map[string]bool)
 	for i, sd := range template.Services {
+		if op.IsCanceled() {
+			deployment.UpdateStatus("deploy_rolling_back|" + template.Name)
+			orphans := f.rollbackTemplate(ctx, tenantIDs[:i], volumes)
+			return nil, alog.Error(DeployTemplateError{
+				DeploymentID: deploymentID,
+				Application:  sd.Name,
+				Err:          ErrOperationCanceled,
+				Orphans:      orphans,
+			})
+		}
+		op.SetProgress(float64(i)*100/float64(len(template.Services)), "deploying "+sd.Name)
 		logger.WithField("servicename", sd.Name).Info("Deploying service")
 		tenantID, err := f.deployService(ctx, "", "", deploymentID, poolID, false, sd, statusUpdater)
+		tenantIDs[i] = tenantID
 		if err != nil {
 			logger.WithError(err).Error("Could not deploy application")
-			return nil, alog.Error(err)
-		}
-		if err := f.dfs.Create(tenantID); err != nil {
+		} else if err = f.dfs.Create(tenantID); err != nil {
 			logger.WithError(err).WithField("tenantid", tenantID).Error("Could not initialize volume for tenant")
-			return nil, alog.Error(err)
+		} else {
+			volumes[tenantID] = true
+		}
+		if err != nil {
+			deployment.UpdateStatus("deploy_rolling_back|" + template.Name)
+			orphans := f.rollbackTemplate(ctx, tenantIDs[:i+1], volumes)
+			return nil, alog.Error(DeployTemplateError{
+				DeploymentID: deploymentID,
+				Application:  sd.Name,
+				Err:          err,
+				Orphans:      orphans,
+			})
 		}
-		tenantIDs[i] = tenantID
 	}
 
 	// Update the logstash filters for the deployed services
@@ -365,6 +460,77 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 	return tenantIDs, nil
 }
 
+// DeployTemplateError is returned when a template deploy fails partway
+// through.  The applications that were deployed are rolled back; those that
+// could not be are left as orphans to remove with "serviced service remove".
+type DeployTemplateError struct {
+	DeploymentID string
+	Application  string   // the application that could not be deployed
+	Err          error    // why it could not be deployed
+	Orphans      []string // the tenant ids of the applications that could not be rolled back
+}
+
+func (err DeployTemplateError) Error() string {
+	msg := fmt.Sprintf("could not deploy application %s of deployment %s: %s", err.Application, err.DeploymentID, err.Err)
+	if len(err.Orphans) > 0 {
+		return fmt.Sprintf("%s; could not roll back applications %s, remove them with serviced service remove", msg, strings.Join(err.Orphans, ", "))
+	}
+	return msg + "; the deployment was rolled back"
+}
+
+// rollbackTemplate removes the applications of a failed template deploy:
+// their services, their volumes, and the images that were pushed to the
+// registry for them.  It returns the tenant ids of the applications that
+// could not be removed.
+func (f *Facade) rollbackTemplate(ctx datastore.Context, tenantIDs []string, volumes map[string]bool) []string {
+	orphans := []string{}
+	rImages, err := f.registryStore.GetImages(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up registry images to roll back")
+	}
+	for _, tenantID := range tenantIDs {
+		if tenantID == "" {
+			continue
+		}
+		logger := plog.WithField("tenantid", tenantID)
+		ok := err == nil
+
+		if _, err := f.serviceStore.Get(ctx, tenantID); err == nil {
+			if err := f.removeService(ctx, tenantID); err != nil {
+				logger.WithError(err).Warn("Could not remove the services of the application")
+				ok = false
+			}
+		} else if !datastore.IsErrNoSuchEntity(err) {
+			logger.WithError(err).Warn("Could not look up the application")
+			ok = false
+		}
+		if volumes[tenantID] {
+			if err := f.dfs.Destroy(tenantID); err != nil {
+				logger.WithError(err).Warn("Could not remove the volume of the application")
+				ok = false
+			}
+		}
+		for _, rImage := range rImages {
+			if rImage.Library != tenantID {
+				continue
+			}
+			if err := f.DeleteRegistryImage(ctx, rImage.String()); err != nil && !datastore.IsErrNoSuchEntity(err) {
+				logger.WithError(err).WithField("image", rImage.String()).Warn("Could not remove registry image of the application")
+				ok = false
+			}
+		}
+		f.zzk.RemoveTenantExports(tenantID)
+		f.zzk.DeleteRegistryLibrary(tenantID)
+
+		if ok {
+			logger.Info("Rolled back application")
+		} else {
+			orphans = append(orphans, tenantID)
+		}
+	}
+	return orphans
+}
+
 // DeployService converts a service definition to a service and deploys it under
 // a specific service.  If the overwrite option is enabled, existing services
 // with the same name will be overwritten, otherwise services may only be added.
@@ -422,6 +588,9 @@ func (f *Facade) DeployService(ctx datastore.Context, poolID, parentID string, o
 	return result, alog.Error(err)
 }
 
+// deployService deploys a service definition and its children.  The id of
+// the new service is returned even if the deploy fails after the id was
+// assigned, so a failed deploy can be rolled back.
 func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentServiceID, deploymentID, poolID string, overwrite bool, svcDef servicedefinition.ServiceDefinition, updateStatus func(string)) (string, error) {
 	logger := plog.WithFields(logrus.Fields{
 		"tenant":       tenantID,
@@ -449,18 +618,22 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 	}
 	if svcDef.ImageID != "" {
 		updateStatus("deploy_loading_image|" + newsvc.Name)
-		image, err := f.dfs.Download(svcDef.ImageID, tenantID, false)
+		image, err := f.downloadImage(ctx, svcDef.ImageID, tenantID, false, svcDef.RegistryCredential)
 		if err != nil {
 			logger.WithError(err).WithField("image", svcDef.ImageID).Error("Could not download image")
-			return "", err
+			return newsvc.ID, err
 		}
 		newsvc.ImageID = image
+		if err := f.pinServiceImage(ctx, newsvc); err != nil {
+			logger.WithError(err).WithField("image", image).Error("Could not pin the image of the service")
+			return newsvc.ID, err
+		}
 	}
 	// find the service
 	store := f.serviceStore
 	if svc, err := store.FindChildService(ctx, newsvc.DeploymentID, newsvc.ParentServiceID, newsvc.Name); err != nil {
 		logger.WithError(err).Error("Could not look up child service")
-		return "", err
+		return newsvc.ID, err
 	} else if svc != nil {
 		if overwrite {
 			newsvc.ID = svc.ID
@@ -472,12 +645,12 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 		} else {
 			err := fmt.Errorf("service exists")
 			logger.WithError(err).WithField("existing", newsvc.ID).Error("Child service already exists")
-			return "", err
+			return newsvc.ID, err
 		}
 	} else {
 		if err := f.AddService(ctx, *newsvc); err != nil {
 			logger.WithError(err).WithField("serviceid", newsvc.ID).Error("Could not add service")
-			return "", err
+			return newsvc.ID, err
 		}
 	}
 
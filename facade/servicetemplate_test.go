@@ -16,6 +16,7 @@
 package facade
 
 import (
+	"errors"
 	"time"
 
 	"github.com/control-center/serviced/commons"
@@ -25,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/stretchr/testify/mock"
 	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 )
@@ -252,3 +254,36 @@ func (ft *FacadeIntegrationTest) verifyLogFilters(c *C, version string) {
 	c.Assert(logFilter.Filter, Equals, filter2)
 }
 
+
+func (ft *FacadeIntegrationTest) TestFacadeDeployTemplate_RollsBack(c *C) {
+	c.Assert(ft.Facade.AddResourcePool(ft.CTX, &pool.ResourcePool{ID: "default"}), IsNil)
+	template := servicetemplate.ServiceTemplate{
+		Name: "rollback_template",
+		Services: []servicedefinition.ServiceDefinition{
+			{Name: "good_app", Launch: "manual"},
+			{Name: "bad_app", Launch: "manual", ImageID: "bad/image"},
+		},
+	}
+	templateID, err := ft.Facade.AddServiceTemplate(ft.CTX, template, false)
+	c.Assert(err, IsNil)
+
+	ft.dfs.On("Create", mock.AnythingOfType("string")).Return(nil)
+	ft.dfs.On("Download", "bad/image", mock.AnythingOfType("string"), false).Return("", errors.New("image not found"))
+
+	_, err = ft.Facade.DeployTemplate(ft.CTX, "default", templateID, "rollback", nil)
+	deployErr, ok := err.(DeployTemplateError)
+	c.Assert(ok, Equals, true)
+	c.Assert(deployErr.Application, Equals, "bad_app")
+	c.Assert(deployErr.Orphans, HasLen, 0)
+
+	// the application that was deployed was removed with its volume
+	svcs, err := ft.Facade.serviceStore.GetServicesByDeployment(ft.CTX, "rollback")
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 0)
+	ft.dfs.AssertNumberOfCalls(c, "Destroy", 1)
+
+	// the deployment id can be used again
+	_, err = ft.Facade.DeployTemplate(ft.CTX, "default", templateID, "rollback", nil)
+	_, ok = err.(DeployTemplateError)
+	c.Assert(ok, Equals, true)
+}
@@ -17,7 +17,10 @@ package facade_test
 
 import (
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/validation"
 	. "gopkg.in/check.v1"
 )
 
@@ -63,3 +66,37 @@ func (ft *FacadeUnitTest) Test_GetServiceTemplatesFails(c *C) {
 	c.Assert(result, Not(IsNil))
 	c.Assert(len(result), Equals, 0)
 }
+
+func (ft *FacadeUnitTest) Test_AddServiceTemplateSchemaViolation(c *C) {
+	template := servicetemplate.ServiceTemplate{
+		Name: "template1",
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name: "parent",
+				Services: []servicedefinition.ServiceDefinition{
+					{Name: "child", ImagePullPolicy: "Sometimes", StartTimeout: -1},
+				},
+			},
+		},
+	}
+
+	templateID, err := ft.Facade.AddServiceTemplate(ft.ctx, template, false)
+	c.Assert(templateID, Equals, "")
+	verr, ok := err.(*validation.ValidationError)
+	c.Assert(ok, Equals, true)
+	c.Assert(verr.Errors, HasLen, 2)
+	c.Assert(verr.Errors[0], ErrorMatches, `Services\[0\]\.Services\[0\]\.ImagePullPolicy: must be one of .*`)
+	c.Assert(verr.Errors[1], ErrorMatches, `Services\[0\]\.Services\[0\]\.StartTimeout: must be at least 0`)
+	ft.templateStore.AssertNotCalled(c, "Put")
+}
+
+func (ft *FacadeUnitTest) Test_GetSchema(c *C) {
+	s, err := ft.Facade.GetSchema(ft.ctx, facade.SchemaServiceTemplate)
+	c.Assert(err, IsNil)
+	c.Assert(s.Definition(servicetemplate.ServiceTemplate{}), NotNil)
+	c.Assert(s.Definition(servicedefinition.ServiceDefinition{}), NotNil)
+
+	s, err = ft.Facade.GetSchema(ft.ctx, "unknown")
+	c.Assert(err, Equals, facade.ErrSchemaNotFound)
+	c.Assert(s, IsNil)
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/setting"
+)
+
+var (
+	// ErrSettingNotFound is returned when a setting is not known
+	ErrSettingNotFound = errors.New("facade: setting not found")
+
+	// ErrInvalidSettingScope is returned when a value is set in a scope that
+	// does not exist, or without the id of its pool or deployment
+	ErrInvalidSettingScope = errors.New("facade: invalid setting scope")
+)
+
+// GetSettings returns every known setting with the values it has been set to.
+// Settings that have not been set have no values.
+func (f *Facade) GetSettings(ctx datastore.Context) ([]setting.Setting, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSettings"))
+	stored, err := f.settingStore.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settingMap := make(map[string]setting.Setting)
+	for _, s := range stored {
+		settingMap[s.ID] = s
+	}
+
+	settings := []setting.Setting{}
+	for _, name := range setting.Names() {
+		s, ok := settingMap[name]
+		if !ok {
+			s = *setting.New(name)
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// SetSetting sets the value of a setting in a scope and publishes the
+// settings to the delegates.
+func (f *Facade) SetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID, value string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetSetting"))
+	def, ok := setting.Lookup(name)
+	if !ok {
+		return ErrSettingNotFound
+	}
+	if err := def.Validate(value); err != nil {
+		return err
+	}
+	return f.changeSetting(ctx, "Setting Setting", name, scope, scopeID, func(s *setting.Setting) {
+		s.Set(scope, scopeID, value)
+	})
+}
+
+// UnsetSetting removes the value of a setting in a scope and publishes the
+// settings to the delegates.
+func (f *Facade) UnsetSetting(ctx datastore.Context, name string, scope setting.Scope, scopeID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UnsetSetting"))
+	if _, ok := setting.Lookup(name); !ok {
+		return ErrSettingNotFound
+	}
+	return f.changeSetting(ctx, "Unsetting Setting", name, scope, scopeID, func(s *setting.Setting) {
+		s.Unset(scope, scopeID)
+	})
+}
+
+// changeSetting applies a change to the stored values of a setting.
+func (f *Facade) changeSetting(ctx datastore.Context, message, name string, scope setting.Scope, scopeID string, change func(*setting.Setting)) error {
+	alog := f.auditLogger.Message(ctx, message).Action(audit.Update).ID(name).Type(setting.GetType())
+	if err := f.validateSettingScope(ctx, scope, scopeID); err != nil {
+		return alog.Error(err)
+	}
+
+	s := setting.New(name)
+	if err := f.settingStore.Get(ctx, setting.Key(name), s); err != nil && !datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(err)
+	}
+	before := *s
+	before.Values = append([]setting.Value{}, s.Values...)
+	change(s)
+	s.UpdatedAt = time.Now()
+	alog = alog.Delta(&before, s)
+	if err := f.settingStore.Put(ctx, setting.Key(name), s); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return f.SyncSettings(ctx)
+}
+
+// validateSettingScope checks that a value can be set in a scope.  Values set
+// for a resource pool must name a pool that exists.
+func (f *Facade) validateSettingScope(ctx datastore.Context, scope setting.Scope, scopeID string) error {
+	switch scope {
+	case setting.ScopeGlobal:
+		if scopeID == "" {
+			return nil
+		}
+	case setting.ScopePool:
+		if scopeID != "" {
+			if p, err := f.GetResourcePool(ctx, scopeID); err != nil {
+				return err
+			} else if p == nil {
+				return ErrPoolNotExists
+			}
+			return nil
+		}
+	case setting.ScopeDeployment:
+		if scopeID != "" {
+			return nil
+		}
+	}
+	return ErrInvalidSettingScope
+}
+
+// SyncSettings publishes the stored settings to the delegates, which watch
+// them in zookeeper.
+func (f *Facade) SyncSettings(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SyncSettings"))
+	settings, err := f.settingStore.GetSettings(ctx)
+	if err != nil {
+		plog.WithError(err).Debug("Could not look up settings")
+		return err
+	}
+	if err := f.zzk.SetSettings(settings); err != nil {
+		plog.WithError(err).Debug("Could not publish settings to zookeeper")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package facade
+
+import (
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeIntegrationTest) TestSetting_SetUnset(c *C) {
+	c.Assert(ft.Facade.AddResourcePool(ft.CTX, &pool.ResourcePool{ID: "pool"}), IsNil)
+
+	// settings that have not been set have no values
+	settings, err := ft.Facade.GetSettings(ft.CTX)
+	c.Assert(err, IsNil)
+	c.Assert(settings, HasLen, len(setting.Definitions))
+	c.Assert(settings[0].Values, HasLen, 0)
+
+	err = ft.Facade.SetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopeGlobal, "", commons.PullNever)
+	c.Assert(err, IsNil)
+	err = ft.Facade.SetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopePool, "pool", commons.PullAlways)
+	c.Assert(err, IsNil)
+	ft.zzk.AssertCalled(c, "SetSettings", mock.AnythingOfType("[]setting.Setting"))
+
+	settings, err = ft.Facade.GetSettings(ft.CTX)
+	c.Assert(err, IsNil)
+	value, ok := settings[0].ValueFor("pool", "")
+	c.Assert(ok, Equals, true)
+	c.Assert(value, Equals, commons.PullAlways)
+
+	err = ft.Facade.UnsetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopePool, "pool")
+	c.Assert(err, IsNil)
+	settings, err = ft.Facade.GetSettings(ft.CTX)
+	c.Assert(err, IsNil)
+	value, _ = settings[0].ValueFor("pool", "")
+	c.Assert(value, Equals, commons.PullNever)
+
+	// values must be valid in a scope that exists
+	err = ft.Facade.SetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopeGlobal, "", "Sometimes")
+	c.Assert(err, NotNil)
+	err = ft.Facade.SetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopePool, "", commons.PullNever)
+	c.Assert(err, Equals, ErrInvalidSettingScope)
+	err = ft.Facade.SetSetting(ft.CTX, setting.ImagePullPolicy, setting.ScopePool, "no-such-pool", commons.PullNever)
+	c.Assert(err, Equals, ErrPoolNotExists)
+	err = ft.Facade.SetSetting(ft.CTX, "no-such-setting", setting.ScopeGlobal, "", "value")
+	c.Assert(err, Equals, ErrSettingNotFound)
+}
@@ -0,0 +1,400 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/servicetemplate"
+)
+
+// ErrNoDeployment is returned when no services are deployed with a deployment
+// id.
+var ErrNoDeployment = errors.New("no services are deployed with the deployment id")
+
+// templateFields are the fields of a service that are owned by its template
+// and are replaced by a template upgrade.  The rest of the fields are either
+// edited at runtime, such as the instances and resource commitments, or are
+// merged so that only new values are added.
+var templateFields = []string{
+	"Title", "Version", "Startup", "Description", "Tags", "Launch", "Hostname",
+	"Privileged", "Volumes", "LogConfigs", "Snapshot", "DisableShell", "Runs",
+	"Commands", "Actions", "HealthChecks", "Prereqs", "ReadinessGates", "PIDFile",
+	"StartTimeout", "ImagePullPolicy", "StartLevel", "EmergencyShutdownLevel", "PriorityClass",
+	"InstanceLimits", "ChangeOptions", "MonitoringProfile", "RegistryCredential",
+}
+
+// UpgradeTemplate upgrades the services of a deployment to a version of a
+// template.  The services of the template are matched to the deployed
+// services by name.  Fields that the template owns are replaced, and new
+// services, endpoints, config files, environment variables and context values
+// are added, but runtime edits, such as the instances, resource commitments,
+// host policy and edited config files, are preserved.  Deployed services that
+// are no longer in the template are left in place.  If dryRun is true, the
+// changes are returned without being applied.
+func (f *Facade) UpgradeTemplate(ctx datastore.Context, templateID, deploymentID string, dryRun bool) ([]servicetemplate.TemplateChange, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpgradeTemplate"))
+	alog := f.auditLogger.Message(ctx, "Upgrading Service Template").
+		Action(audit.Update).ID(templateID).Type(servicetemplate.GetType()).
+		WithFields(logrus.Fields{"deploymentid": deploymentID, "dryrun": dryRun})
+	logger := plog.WithFields(logrus.Fields{
+		"templateid":   templateID,
+		"deploymentid": deploymentID,
+		"dryrun":       dryRun,
+	})
+
+	template, err := f.templateStore.Get(ctx, templateID)
+	if err != nil {
+		logger.WithError(err).Debug("Unable to load template")
+		return nil, alog.Error(err)
+	}
+	if err := template.ApplyValues(nil); err != nil {
+		logger.WithError(err).Debug("Could not apply values to template")
+		return nil, alog.Error(err)
+	}
+	template.ApplyRegistryCredential()
+
+	if !dryRun {
+		if err := f.DFSLock(ctx).LockWithTimeout("upgrade template", userLockTimeout); err != nil {
+			logger.WithError(err).Debug("Could not lock DFS")
+			return nil, alog.Error(err)
+		}
+		defer f.DFSLock(ctx).Unlock()
+	}
+
+	svcs, err := f.serviceStore.GetServicesByDeployment(ctx, deploymentID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up deployed services")
+		return nil, alog.Error(err)
+	} else if len(svcs) == 0 {
+		return nil, alog.Error(ErrNoDeployment)
+	}
+
+	u := &templateUpgrade{
+		f:            f,
+		ctx:          ctx,
+		deploymentID: deploymentID,
+		dryRun:       dryRun,
+		children:     make(map[string][]service.Service),
+		changes:      []servicetemplate.TemplateChange{},
+	}
+	for _, svc := range svcs {
+		u.children[svc.ParentServiceID] = append(u.children[svc.ParentServiceID], svc)
+	}
+	poolID := u.children[""][0].PoolID
+
+	if err := u.upgradeChildren("", "", poolID, "", template.Services); err != nil {
+		logger.WithError(err).Error("Could not upgrade deployment")
+		return nil, alog.Error(err)
+	}
+	logger.WithField("changes", len(u.changes)).Info("Upgraded deployment to template")
+	return u.changes, alog.Error(nil)
+}
+
+// templateUpgrade tracks the deployed services and the changes of a template
+// upgrade.
+type templateUpgrade struct {
+	f            *Facade
+	ctx          datastore.Context
+	deploymentID string
+	dryRun       bool
+	children     map[string][]service.Service // deployed services by parent id
+	changes      []servicetemplate.TemplateChange
+}
+
+// upgradeChildren upgrades the child services of a deployed service to the
+// service definitions of the template.  The tenant id and parent id are
+// empty for the top level services of the deployment.
+func (u *templateUpgrade) upgradeChildren(tenantID, parentID, poolID, parentPath string, sds []servicedefinition.ServiceDefinition) error {
+	deployed := make(map[string]service.Service)
+	for _, svc := range u.children[parentID] {
+		deployed[svc.Name] = svc
+	}
+	for _, sd := range sds {
+		path := joinServicePath(parentPath, sd.Name)
+		svc, ok := deployed[sd.Name]
+		if !ok {
+			if err := u.addService(tenantID, parentID, poolID, path, sd); err != nil {
+				return err
+			}
+			continue
+		}
+		delete(deployed, sd.Name)
+
+		svcTenantID := tenantID
+		if svcTenantID == "" {
+			svcTenantID = svc.ID
+		}
+		if err := u.upgradeService(svcTenantID, path, svc, sd); err != nil {
+			return err
+		}
+		if err := u.upgradeChildren(svcTenantID, svc.ID, svc.PoolID, path, sd.Services); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(deployed) {
+		svc := deployed[name]
+		u.changes = append(u.changes, servicetemplate.TemplateChange{
+			ServicePath: joinServicePath(parentPath, name),
+			ServiceID:   svc.ID,
+			Action:      servicetemplate.TemplateChangeKeep,
+		})
+	}
+	return nil
+}
+
+// addService deploys a service of the template that is not in the deployment
+func (u *templateUpgrade) addService(tenantID, parentID, poolID, path string, sd servicedefinition.ServiceDefinition) error {
+	u.changes = append(u.changes, servicetemplate.TemplateChange{
+		ServicePath: path,
+		Action:      servicetemplate.TemplateChangeAdd,
+	})
+	if u.dryRun {
+		return nil
+	}
+	serviceID, err := u.f.deployService(u.ctx, tenantID, parentID, u.deploymentID, poolID, false, sd, func(string) {})
+	if err != nil {
+		return err
+	}
+	if tenantID == "" {
+		return u.f.dfs.Create(serviceID)
+	}
+	return nil
+}
+
+// upgradeService applies the service definition of the template to a
+// deployed service.
+func (u *templateUpgrade) upgradeService(tenantID, path string, svc service.Service, sd servicedefinition.ServiceDefinition) error {
+	logger := plog.WithFields(logrus.Fields{
+		"tenantid":    tenantID,
+		"serviceid":   svc.ID,
+		"servicepath": path,
+	})
+	newsvc, err := service.BuildService(sd, svc.ParentServiceID, svc.PoolID, svc.DesiredState, svc.DeploymentID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not build service from template")
+		return err
+	}
+	newsvc.ID = svc.ID
+	if err := u.f.evaluateEndpointTemplates(u.ctx, newsvc); err != nil {
+		logger.WithError(err).Debug("Could not evaluate endpoint templates for service")
+		return err
+	}
+	if err := u.f.fillServiceConfigs(u.ctx, &svc); err != nil {
+		logger.WithError(err).Debug("Could not load config files of service")
+		return err
+	}
+
+	changes := mergeTemplateService(&svc, newsvc)
+	if sd.ImageID != "" {
+		changes = append(changes, servicetemplate.TemplateChange{
+			Field:  "ImageID",
+			Action: servicetemplate.TemplateChangePull,
+			Old:    svc.ImageID,
+			New:    sd.ImageID,
+		})
+	}
+	for i := range changes {
+		changes[i].ServicePath = path
+		changes[i].ServiceID = svc.ID
+	}
+	u.changes = append(u.changes, changes...)
+	if u.dryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if sd.ImageID != "" {
+		image, err := u.f.downloadImage(u.ctx, sd.ImageID, tenantID, true, sd.RegistryCredential)
+		if err != nil {
+			logger.WithError(err).WithField("image", sd.ImageID).Error("Could not download image")
+			return err
+		}
+		svc.ImageID = image
+		if err := u.f.pinServiceImage(u.ctx, &svc); err != nil {
+			logger.WithError(err).WithField("image", image).Error("Could not pin the image of the service")
+			return err
+		}
+	}
+	if err := u.f.MigrateService(u.ctx, svc); err != nil {
+		logger.WithError(err).Error("Could not update service")
+		return err
+	}
+	logger.WithField("changes", len(changes)).Info("Upgraded service to template")
+	return nil
+}
+
+// mergeTemplateService merges a service built from a template into a
+// deployed service and returns the changes to the deployed service.
+func mergeTemplateService(svc, newsvc *service.Service) []servicetemplate.TemplateChange {
+	changes := []servicetemplate.TemplateChange{}
+	update := func(field string, old, new interface{}) {
+		changes = append(changes, servicetemplate.TemplateChange{
+			Field:  field,
+			Action: servicetemplate.TemplateChangeUpdate,
+			Old:    changeValue(old),
+			New:    changeValue(new),
+		})
+	}
+	add := func(field string, new interface{}) {
+		changes = append(changes, servicetemplate.TemplateChange{
+			Field:  field,
+			Action: servicetemplate.TemplateChangeAdd,
+			New:    changeValue(new),
+		})
+	}
+
+	// replace the fields owned by the template
+	oldValue, newValue := reflect.ValueOf(svc).Elem(), reflect.ValueOf(newsvc).Elem()
+	for _, field := range templateFields {
+		oldField, newField := oldValue.FieldByName(field), newValue.FieldByName(field)
+		if isEmptyValue(oldField) && isEmptyValue(newField) {
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			update(field, oldField.Interface(), newField.Interface())
+			oldField.Set(newField)
+		}
+	}
+
+	// keep the instances within the limits of the template
+	instances := svc.Instances
+	if limits := svc.InstanceLimits; limits.Max > 0 && instances > limits.Max {
+		instances = limits.Max
+	} else if instances < limits.Min {
+		instances = limits.Min
+	}
+	if instances != svc.Instances {
+		update("Instances", svc.Instances, instances)
+		svc.Instances = instances
+	}
+
+	// add new environment variables and context values
+	envKeys := make(map[string]struct{})
+	for _, env := range svc.Environment {
+		envKeys[strings.SplitN(env, "=", 2)[0]] = struct{}{}
+	}
+	for _, env := range newsvc.Environment {
+		if _, ok := envKeys[strings.SplitN(env, "=", 2)[0]]; !ok {
+			add("Environment", env)
+			svc.Environment = append(svc.Environment, env)
+		}
+	}
+	for _, key := range sortedKeys(newsvc.Context) {
+		if _, ok := svc.Context[key]; !ok {
+			value := newsvc.Context[key]
+			add("Context."+key, value)
+			if svc.Context == nil {
+				svc.Context = make(map[string]interface{})
+			}
+			svc.Context[key] = value
+		}
+	}
+
+	// add new endpoints
+	endpoints := make(map[string]struct{})
+	for _, ep := range svc.Endpoints {
+		endpoints[ep.Purpose+"/"+ep.Name] = struct{}{}
+	}
+	for _, ep := range newsvc.Endpoints {
+		if _, ok := endpoints[ep.Purpose+"/"+ep.Name]; !ok {
+			add("Endpoints", ep.Purpose+" "+ep.Name)
+			svc.Endpoints = append(svc.Endpoints, ep)
+		}
+	}
+
+	// add new config files and update the config files that were not edited;
+	// edited config files are kept
+	updateFile := func(field, filename string) {
+		changes = append(changes, servicetemplate.TemplateChange{
+			Field:  field,
+			Action: servicetemplate.TemplateChangeUpdate,
+			New:    filename,
+		})
+	}
+	if svc.ConfigFiles == nil {
+		svc.ConfigFiles = make(map[string]servicedefinition.ConfigFile)
+	}
+	for _, filename := range sortedKeys(newsvc.OriginalConfigs) {
+		conf := newsvc.OriginalConfigs[filename]
+		original, hasOriginal := svc.OriginalConfigs[filename]
+		current, hasCurrent := svc.ConfigFiles[filename]
+		switch {
+		case !hasCurrent:
+			add("ConfigFiles", filename)
+			svc.ConfigFiles[filename] = conf
+		case hasOriginal && reflect.DeepEqual(original, conf):
+		case hasOriginal && reflect.DeepEqual(current, original):
+			updateFile("ConfigFiles", filename)
+			svc.ConfigFiles[filename] = conf
+		default:
+			updateFile("OriginalConfigs", filename)
+		}
+	}
+	for _, filename := range sortedKeys(svc.OriginalConfigs) {
+		if _, ok := newsvc.OriginalConfigs[filename]; !ok {
+			updateFile("OriginalConfigs", filename)
+		}
+	}
+	svc.OriginalConfigs = newsvc.OriginalConfigs
+	return changes
+}
+
+// isEmptyValue returns true if the value is the zero value or an empty map,
+// slice or string.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// changeValue returns the value of a field as it is reported by a template
+// change.
+func changeValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// sortedKeys returns the sorted keys of a map with string keys
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinServicePath(parentPath, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + "/" + name
+}
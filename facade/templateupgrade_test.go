@@ -0,0 +1,112 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade
+
+import (
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/utils"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&TemplateUpgradeTest{})
+
+type TemplateUpgradeTest struct{}
+
+func (t *TemplateUpgradeTest) TestMergeTemplateService_NoChanges(c *C) {
+	svc := &service.Service{
+		Startup:        "run",
+		Instances:      2,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 3},
+		Environment:    []string{"A=1"},
+	}
+	newsvc := &service.Service{
+		Startup:        "run",
+		Instances:      1,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 3},
+		Environment:    []string{"A=2"},
+		Tags:           []string{},
+	}
+	changes := mergeTemplateService(svc, newsvc)
+	c.Assert(changes, HasLen, 0)
+	c.Assert(svc.Instances, Equals, 2)
+	c.Assert(svc.Environment, DeepEquals, []string{"A=1"})
+}
+
+func (t *TemplateUpgradeTest) TestMergeTemplateService(c *C) {
+	svc := &service.Service{
+		Version:        "1.0",
+		Startup:        "run",
+		Instances:      5,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 10},
+		RAMCommitment:  utils.NewEngNotation(1024),
+		Environment:    []string{"A=edited"},
+		Context:        map[string]interface{}{"a": "edited"},
+		Endpoints:      []service.ServiceEndpoint{{Name: "web", Purpose: "export"}},
+		OriginalConfigs: map[string]servicedefinition.ConfigFile{
+			"/etc/a.conf": {Filename: "/etc/a.conf", Content: "a1"},
+			"/etc/b.conf": {Filename: "/etc/b.conf", Content: "b1"},
+		},
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/a.conf": {Filename: "/etc/a.conf", Content: "a1"},
+			"/etc/b.conf": {Filename: "/etc/b.conf", Content: "b edited"},
+		},
+	}
+	newsvc := &service.Service{
+		Version:        "1.1",
+		Startup:        "run",
+		Instances:      1,
+		InstanceLimits: domain.MinMax{Min: 1, Max: 3},
+		RAMCommitment:  utils.NewEngNotation(512),
+		Environment:    []string{"A=1", "B=2"},
+		Context:        map[string]interface{}{"a": "1", "b": "2"},
+		Endpoints: []service.ServiceEndpoint{
+			{Name: "web", Purpose: "export", PortNumber: 8080},
+			{Name: "db", Purpose: "import"},
+		},
+		OriginalConfigs: map[string]servicedefinition.ConfigFile{
+			"/etc/a.conf": {Filename: "/etc/a.conf", Content: "a2"},
+			"/etc/b.conf": {Filename: "/etc/b.conf", Content: "b2"},
+			"/etc/c.conf": {Filename: "/etc/c.conf", Content: "c2"},
+		},
+	}
+	changes := mergeTemplateService(svc, newsvc)
+	c.Assert(changes, DeepEquals, []servicetemplate.TemplateChange{
+		{Field: "Version", Action: servicetemplate.TemplateChangeUpdate, Old: "1.0", New: "1.1"},
+		{Field: "InstanceLimits", Action: servicetemplate.TemplateChangeUpdate, Old: `{"Min":1,"Max":10,"Default":0}`, New: `{"Min":1,"Max":3,"Default":0}`},
+		{Field: "Instances", Action: servicetemplate.TemplateChangeUpdate, Old: "5", New: "3"},
+		{Field: "Environment", Action: servicetemplate.TemplateChangeAdd, New: "B=2"},
+		{Field: "Context.b", Action: servicetemplate.TemplateChangeAdd, New: "2"},
+		{Field: "Endpoints", Action: servicetemplate.TemplateChangeAdd, New: "import db"},
+		{Field: "ConfigFiles", Action: servicetemplate.TemplateChangeUpdate, New: "/etc/a.conf"},
+		{Field: "OriginalConfigs", Action: servicetemplate.TemplateChangeUpdate, New: "/etc/b.conf"},
+		{Field: "ConfigFiles", Action: servicetemplate.TemplateChangeAdd, New: "/etc/c.conf"},
+	})
+
+	// runtime edits are preserved
+	c.Assert(svc.Instances, Equals, 3)
+	c.Assert(svc.RAMCommitment.Value, Equals, uint64(1024))
+	c.Assert(svc.Environment, DeepEquals, []string{"A=edited", "B=2"})
+	c.Assert(svc.Context, DeepEquals, map[string]interface{}{"a": "edited", "b": "2"})
+	c.Assert(svc.Endpoints, HasLen, 2)
+	c.Assert(svc.Endpoints[0].PortNumber, Equals, uint16(0))
+	c.Assert(svc.ConfigFiles["/etc/a.conf"].Content, Equals, "a2")
+	c.Assert(svc.ConfigFiles["/etc/b.conf"].Content, Equals, "b edited")
+	c.Assert(svc.ConfigFiles["/etc/c.conf"].Content, Equals, "c2")
+	c.Assert(svc.OriginalConfigs, DeepEquals, newsvc.OriginalConfigs)
+}
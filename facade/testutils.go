@@ -25,12 +25,20 @@ import (
 	"github.com/control-center/serviced/datastore/elastic"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/domain/user"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
@@ -66,6 +74,14 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	ft.Mappings = append(ft.Mappings, serviceconfigfile.MAPPING)
 	ft.Mappings = append(ft.Mappings, user.MAPPING)
 	ft.Mappings = append(ft.Mappings, registry.MAPPING)
+	ft.Mappings = append(ft.Mappings, calendar.MAPPING)
+	ft.Mappings = append(ft.Mappings, backupschedule.MAPPING)
+	ft.Mappings = append(ft.Mappings, feature.MAPPING)
+	ft.Mappings = append(ft.Mappings, pin.MAPPING)
+	ft.Mappings = append(ft.Mappings, setting.MAPPING)
+	ft.Mappings = append(ft.Mappings, delegateprofile.MAPPING)
+	ft.Mappings = append(ft.Mappings, secret.MAPPING)
+	ft.Mappings = append(ft.Mappings, certificate.MAPPING)
 
 	ft.ElasticTest.SetUpSuite(c)
 	datastore.Register(ft.Driver())
@@ -86,6 +102,7 @@ func (ft *FacadeIntegrationTest) SetUpSuite(c *gocheck.C) {
 	mockLogger.On("Entity", mock.AnythingOfType("*addressassignment.AddressAssignment")).Return(mockLogger)
 	mockLogger.On("WithField", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(mockLogger)
 	mockLogger.On("WithFields", mock.AnythingOfType("logrus.Fields")).Return(mockLogger)
+	mockLogger.On("Delta", mock.Anything, mock.Anything).Return(mockLogger)
 	mockLogger.On("Error", mock.Anything)
 	mockLogger.On("Succeeded", mock.Anything)
 	mockLogger.On("SucceededIf", mock.AnythingOfType("bool"))
@@ -128,6 +145,7 @@ func (ft *FacadeIntegrationTest) setupMockZZK(c *gocheck.C) {
 	ft.zzk.On("RemoveService", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 	ft.zzk.On("RemoveServiceEndpoints", mock.AnythingOfType("string")).Return(nil)
 	ft.zzk.On("RemoveTenantExports", mock.AnythingOfType("string")).Return(nil)
+	ft.zzk.On("SetSettings", mock.AnythingOfType("[]setting.Setting")).Return(nil)
 	ft.zzk.On("SetRegistryImage", mock.AnythingOfType("*registry.Image")).Return(nil)
 	ft.zzk.On("DeleteRegistryImage", mock.AnythingOfType("string")).Return(nil)
 	ft.zzk.On("DeleteRegistryLibrary", mock.AnythingOfType("string")).Return(nil)
@@ -142,6 +160,8 @@ func (ft *FacadeIntegrationTest) setupMockZZK(c *gocheck.C) {
 
 func (ft *FacadeIntegrationTest) setupMockDFS() {
 	ft.dfs.On("Destroy", mock.AnythingOfType("string")).Return(nil)
+	ft.dfs.On("SaveTemplate", mock.AnythingOfType("servicetemplate.ServiceTemplate")).Return(nil)
+	ft.dfs.On("DeleteTemplate", mock.AnythingOfType("string")).Return(nil)
 }
 
 func (ft *FacadeIntegrationTest) TearDownTest(c *gocheck.C) {
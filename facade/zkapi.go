@@ -28,10 +28,12 @@ import (
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/zzk"
 	zkd "github.com/control-center/serviced/zzk/docker"
 	zkr "github.com/control-center/serviced/zzk/registry"
 	zks "github.com/control-center/serviced/zzk/service"
+	zksetting "github.com/control-center/serviced/zzk/setting"
 	"github.com/zenoss/glog"
 )
 
@@ -432,6 +434,22 @@ func (z *zkf) IsHostActive(poolID string, hostID string) (bool, error) {
 	return zks.IsHostOnline(conn, poolID, hostID)
 }
 
+func (z *zkf) SetHostMaintenance(poolID, hostID string, enabled bool) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zks.SetHostMaintenance(conn, poolID, hostID, enabled)
+}
+
+func (z *zkf) SetSettings(settings []setting.Setting) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zksetting.SetSettings(conn, settings)
+}
+
 func (z *zkf) UpdateResourcePool(pool *pool.ResourcePool) error {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -448,6 +466,22 @@ func (z *zkf) RemoveResourcePool(poolID string) error {
 	return zks.RemoveResourcePool(conn, poolID)
 }
 
+func (z *zkf) GetResourcePools() ([]pool.ResourcePool, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetResourcePools(conn)
+}
+
+func (z *zkf) GetHosts(poolID string) ([]host.Host, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetHosts(conn, poolID)
+}
+
 func (z *zkf) GetVirtualIPHostID(poolID, ip string) (string, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -456,6 +490,14 @@ func (z *zkf) GetVirtualIPHostID(poolID, ip string) (string, error) {
 	return zks.GetHostID(conn, poolID, ip)
 }
 
+func (z *zkf) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zks.GetVirtualIPEvents(conn, poolID)
+}
+
 func (z *zkf) GetRegistryImage(id string) (*registry.Image, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -464,6 +506,14 @@ func (z *zkf) GetRegistryImage(id string) (*registry.Image, error) {
 	return zkimgregistry.GetRegistryImage(conn, id)
 }
 
+func (z *zkf) GetRegistryImages() ([]registry.Image, error) {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	return zkimgregistry.GetRegistryImages(conn)
+}
+
 func (z *zkf) SetRegistryImage(image *registry.Image) error {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
@@ -534,6 +584,18 @@ func (zk *zkf) GetServiceStates(ctx datastore.Context, poolID, serviceID string)
 	return zks.GetServiceStates(conn, poolID, serviceID)
 }
 
+// GetServiceInstanceHistory returns the instance history of a service
+func (zk *zkf) GetServiceInstanceHistory(ctx datastore.Context, poolID, serviceID string) ([]service.InstanceHistory, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("zzk.GetServiceInstanceHistory"))
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		glog.Errorf("Could not get connection to zookeeper: %s", err)
+		return nil, err
+	}
+
+	return zks.GetInstanceHistory(conn, poolID, serviceID)
+}
+
 // GetHostStates returns all running instances for a host
 func (zk *zkf) GetHostStates(ctx datastore.Context, poolID, hostID string) ([]zks.State, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("zzk.GetHostStates"))
@@ -775,6 +837,107 @@ func (zk *zkf) RestartInstance(ctx datastore.Context, poolID, serviceID string,
 	return nil
 }
 
+// PinInstanceImage runs an instance of a service on the given image instead
+// of the image of the service.  An empty image id removes the pin.  If
+// restart is true, a running instance is restarted onto the image.
+func (zk *zkf) PinInstanceImage(ctx datastore.Context, poolID, serviceID string, instanceID int, imageID string, restart bool) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("zzk.PinInstanceImage"))
+	logger := plog.WithFields(log.Fields{
+		"poolid":     poolID,
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+		"imageid":    imageID,
+	})
+
+	// get the root-based connection to update the service instance
+	conn, err := getLocalConnection(ctx, "/")
+	if err != nil {
+		logger.WithError(err).Debug("Could not acquire root-based connection")
+		return err
+	}
+
+	// get the hostid and make a state request for the service
+	hostID, err := zks.GetServiceStateHostID(conn, poolID, serviceID, instanceID)
+	if err != nil {
+		return err
+	}
+	logger = logger.WithField("hostid", hostID)
+
+	if restart {
+		isOnline, err := zks.IsHostOnline(conn, poolID, hostID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not check if host is online")
+			return err
+		} else if !isOnline {
+			logger.Warning("Could not restart service instance, host is not online")
+			return ErrHostOffline
+		}
+	}
+
+	req := zks.StateRequest{
+		PoolID:     poolID,
+		HostID:     hostID,
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	if err := zks.UpdateState(conn, req, func(s *zks.State) bool {
+		changed := s.ImageID != imageID
+		s.ImageID = imageID
+		if restart && s.DesiredState == service.SVCRun {
+			s.DesiredState = service.SVCRestart
+			changed = true
+		}
+		return changed
+	}); err != nil {
+		logger.WithError(err).Debug("Could not pin service instance image")
+		return err
+	}
+	logger.Debug("Pinned service instance image")
+	return nil
+}
+
+// SetInstanceEnvironment sets the variables that are added to the environment
+// of an instance of a service when it next starts.  An empty environment
+// removes the override.  The instance is not restarted.
+func (zk *zkf) SetInstanceEnvironment(ctx datastore.Context, poolID, serviceID string, instanceID int, env []string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("zzk.SetInstanceEnvironment"))
+	logger := plog.WithFields(log.Fields{
+		"poolid":     poolID,
+		"serviceid":  serviceID,
+		"instanceid": instanceID,
+	})
+
+	// get the root-based connection to update the service instance
+	conn, err := getLocalConnection(ctx, "/")
+	if err != nil {
+		logger.WithError(err).Debug("Could not acquire root-based connection")
+		return err
+	}
+
+	// get the hostid and make a state request for the service
+	hostID, err := zks.GetServiceStateHostID(conn, poolID, serviceID, instanceID)
+	if err != nil {
+		return err
+	}
+	logger = logger.WithField("hostid", hostID)
+
+	req := zks.StateRequest{
+		PoolID:     poolID,
+		HostID:     hostID,
+		ServiceID:  serviceID,
+		InstanceID: instanceID,
+	}
+	if err := zks.UpdateState(conn, req, func(s *zks.State) bool {
+		s.Environment = env
+		return true
+	}); err != nil {
+		logger.WithError(err).Debug("Could not set service instance environment")
+		return err
+	}
+	logger.WithField("variables", len(env)).Debug("Set service instance environment")
+	return nil
+}
+
 // UpdateInstanceCurrentState sets the current state of the instance
 func (zk *zkf) UpdateInstanceCurrentState(ctx datastore.Context, poolID, serviceID string, instanceID int, state service.InstanceCurrentState) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start(fmt.Sprintf("zzk.UpdateInstanceCurrentState")))
@@ -19,6 +19,7 @@ import (
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/setting"
 	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
@@ -38,25 +39,34 @@ type ZZK interface {
 	RemoveHost(_host *host.Host) error
 	GetActiveHosts(ctx datastore.Context, poolID string, hosts *[]string) error
 	IsHostActive(poolID string, hostId string) (bool, error)
+	SetHostMaintenance(poolID, hostID string, enabled bool) error
+	SetSettings(settings []setting.Setting) error
 	UpdateResourcePool(_pool *pool.ResourcePool) error
 	RemoveResourcePool(poolID string) error
+	GetResourcePools() ([]pool.ResourcePool, error)
+	GetHosts(poolID string) ([]host.Host, error)
 	GetRegistryImage(id string) (*registry.Image, error)
+	GetRegistryImages() ([]registry.Image, error)
 	SetRegistryImage(rImage *registry.Image) error
 	DeleteRegistryImage(id string) error
 	DeleteRegistryLibrary(tenantID string) error
 	LockServices(ctx datastore.Context, svcs []service.ServiceDetails) error
 	UnlockServices(ctx datastore.Context, svcs []service.ServiceDetails) error
 	GetServiceStates(ctx datastore.Context, poolID, serviceID string) ([]zkservice.State, error)
+	GetServiceInstanceHistory(ctx datastore.Context, poolID, serviceID string) ([]service.InstanceHistory, error)
 	GetHostStates(ctx datastore.Context, poolID, hostID string) ([]zkservice.State, error)
 	GetServiceState(ctx datastore.Context, poolID, serviceID string, instanceID int) (*zkservice.State, error)
 	StopServiceInstance(poolID, serviceID string, instanceID int) error
 	StopServiceInstances(ctx datastore.Context, poolID, serviceID string) error
 	RestartInstance(ctx datastore.Context, poolID, serviceID string, instanceID int) error
+	PinInstanceImage(ctx datastore.Context, poolID, serviceID string, instanceID int, imageID string, restart bool) error
+	SetInstanceEnvironment(ctx datastore.Context, poolID, serviceID string, instanceID int, env []string) error
 	SendDockerAction(poolID, serviceID string, instanceID int, command string, args []string) error
 	GetServiceStateIDs(poolID, serviceID string) ([]zkservice.StateRequest, error)
 	GetServiceNodes() ([]zkservice.ServiceNode, error)
 	RegisterDfsClients(clients ...host.Host) error
 	UnregisterDfsClients(clients ...host.Host) error
 	GetVirtualIPHostID(poolID, ip string) (string, error)
+	GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error)
 	UpdateInstanceCurrentState(ctx datastore.Context, poolID, serviceID string, instanceID int, state service.InstanceCurrentState) error
 }
@@ -0,0 +1,122 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Acquirer reserves slots on the host to run a batch of health checks for up
+// to budget, and returns a function that releases them.
+type Acquirer func(slots int, budget time.Duration) (release func())
+
+// Batch is a group of health checks of an instance that share an interval.
+// The checks of a batch ask the host for slots together and then run at the
+// same time.
+type Batch struct {
+	Interval time.Duration
+	Checks   map[string]*HealthCheck
+}
+
+// NewBatches groups the health checks of an instance into batches by
+// interval, shortest interval first.
+func NewBatches(checks map[string]HealthCheck) []*Batch {
+	byInterval := make(map[time.Duration]*Batch)
+	for name, hc := range checks {
+		hc := hc
+		b, ok := byInterval[hc.Interval]
+		if !ok {
+			b = &Batch{Interval: hc.Interval, Checks: make(map[string]*HealthCheck)}
+			byInterval[hc.Interval] = b
+		}
+		b.Checks[name] = &hc
+	}
+
+	batches := make([]*Batch, 0, len(byInterval))
+	for _, b := range byInterval {
+		batches = append(batches, b)
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Interval < batches[j].Interval })
+	return batches
+}
+
+// Names returns the names of the checks in the batch, in order.
+func (b *Batch) Names() []string {
+	names := make([]string, 0, len(b.Checks))
+	for name := range b.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Budget returns how long the batch may hold its slots, which is the longest
+// timeout of its checks.
+func (b *Batch) Budget() time.Duration {
+	var budget time.Duration
+	for _, hc := range b.Checks {
+		if timeout := hc.GetTimeout(); timeout > budget {
+			budget = timeout
+		}
+	}
+	return budget
+}
+
+// Ping runs the checks of the batch on the batch interval and reports the
+// result of each check.  Each run waits for acquire to reserve a slot per
+// check.
+func (b *Batch) Ping(cancel <-chan struct{}, key HealthStatusKey, acquire Acquirer, report func(name string, hc *HealthCheck, stat HealthStatus)) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			results := b.Run(key, acquire)
+			timer.Reset(b.Interval)
+			for _, name := range b.Names() {
+				report(name, b.Checks[name], results[name])
+			}
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// Run runs the checks of the batch once, at the same time, and returns their
+// results by name.
+func (b *Batch) Run(key HealthStatusKey, acquire Acquirer) map[string]HealthStatus {
+	release := acquire(len(b.Checks), b.Budget())
+	defer release()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]HealthStatus)
+	for name, hc := range b.Checks {
+		wg.Add(1)
+		go func(name string, hc *HealthCheck) {
+			defer wg.Done()
+			k := key
+			k.HealthCheckName = name
+			stat := hc.Run(k)
+			stat.KillFlag = hc.killFlag()
+			mu.Lock()
+			results[name] = stat
+			mu.Unlock()
+		}(name, hc)
+	}
+	wg.Wait()
+	return results
+}
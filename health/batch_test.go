@@ -0,0 +1,62 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+type BatchSuite struct{}
+
+var _ = Suite(&BatchSuite{})
+
+func (s *BatchSuite) TestNewBatches(c *C) {
+	batches := NewBatches(map[string]HealthCheck{
+		"a": {Script: "true", Interval: 10 * time.Second},
+		"b": {Script: "true", Interval: 5 * time.Second, Timeout: time.Minute},
+		"c": {Script: "true", Interval: 10 * time.Second, Timeout: 2 * time.Second},
+	})
+	c.Assert(batches, HasLen, 2)
+	c.Check(batches[0].Interval, Equals, 5*time.Second)
+	c.Check(batches[0].Names(), DeepEquals, []string{"b"})
+	c.Check(batches[0].Budget(), Equals, time.Minute)
+	c.Check(batches[1].Interval, Equals, 10*time.Second)
+	c.Check(batches[1].Names(), DeepEquals, []string{"a", "c"})
+	c.Check(batches[1].Budget(), Equals, DefaultTimeout)
+}
+
+func (s *BatchSuite) TestRun(c *C) {
+	batches := NewBatches(map[string]HealthCheck{
+		"pass": {Script: "exit 0", Timeout: time.Second, Interval: time.Second},
+		"fail": {Script: "exit 1", Timeout: time.Second, Interval: time.Second},
+	})
+	c.Assert(batches, HasLen, 1)
+
+	var slots int
+	released := false
+	results := batches[0].Run(hcKey, func(n int, budget time.Duration) func() {
+		slots = n
+		c.Check(budget, Equals, time.Second)
+		return func() { released = true }
+	})
+	c.Check(slots, Equals, 2)
+	c.Check(released, Equals, true)
+	c.Check(results["pass"].Status, Equals, OK)
+	c.Check(int(results["fail"].Status), Equals, Failed)
+}
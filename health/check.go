@@ -16,6 +16,11 @@ package health
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"os/exec"
 	"syscall"
 	"time"
@@ -97,72 +102,151 @@ func (s *Status) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Probe types
+const (
+	// ProbeScript runs a shell script; it is the default
+	ProbeScript = "script"
+	// ProbeExec runs a command without a shell
+	ProbeExec = "exec"
+	// ProbeHTTP sends a GET request to a url
+	ProbeHTTP = "http"
+	// ProbeTCP opens a tcp connection to an address
+	ProbeTCP = "tcp"
+)
+
 // HealthStatus is the output from a provided health check.
 type HealthStatus struct {
 	Status    Status
 	StartedAt time.Time
 	Duration  time.Duration
 	KillFlag  bool
+	Message   string `json:",omitempty"` // Why the probe failed
+	Failures  int    `json:",omitempty"` // Consecutive failures of the probe
 }
 
 // HealthCheck is the health check object.
 type HealthCheck struct {
-	Script    string
+	Type      string   // script (default), exec, http or tcp
+	Script    string   // Shell script of a script probe
+	Command   []string // Command and arguments of an exec probe
+	Address   string   // Url of an http probe, or host:port of a tcp probe
 	Timeout   time.Duration
 	Interval  time.Duration
 	Tolerance int
+	// StatusCodes are the http response codes of a passing http probe.  Any
+	// 2xx or 3xx code passes if it is empty.
+	StatusCodes []int
+	// FailureThreshold is the number of consecutive failures before the
+	// check reports that it is failing.
+	FailureThreshold int
+	// CPULimit is the cpu time that a script or exec probe may use before it
+	// is killed, rounded up to whole seconds.
+	CPULimit time.Duration
 	// Kill properties will kill the container if the observed error code is in the error code list
 	// and this happens <count>-times.  If the error codes list is empty and the kill count is >0, then any
 	// non-zero error code will count toward the kill count.
 	KillExitCodes  []int
 	KillCountLimit int
 	KillCounter    int
+	// OnFailure is what the host agent does about an instance while the
+	// check is failing: none (default), restart or reschedule.  Restarts are
+	// delayed by RestartBackoff, which doubles with each restart, and stop
+	// after MaxRestarts if it is set.
+	OnFailure      string
+	MaxRestarts    int
+	RestartBackoff time.Duration
+
+	failures int
+}
+
+// jsonHealthCheck is the serialized health check, with durations in seconds
+type jsonHealthCheck struct {
+	Type             string `json:",omitempty"`
+	Script           string
+	Command          []string `json:",omitempty"`
+	Address          string   `json:",omitempty"`
+	Timeout          float64
+	Interval         float64
+	Tolerance        int
+	StatusCodes      []int   `json:",omitempty"`
+	FailureThreshold int     `json:",omitempty"`
+	CPULimit         float64 `json:",omitempty"`
+	KillExitCodes    []int   `json:",omitempty"`
+	KillCountLimit   int     `json:",omitempty"`
+	OnFailure        string  `json:",omitempty"`
+	MaxRestarts      int     `json:",omitempty"`
+	RestartBackoff   float64 `json:",omitempty"`
 }
 
 // MarshalJSON implements json.Marshaller
 func (hc HealthCheck) MarshalJSON() ([]byte, error) {
-	jhc := struct {
-		Script         string
-		Timeout        float64
-		Interval       float64
-		Tolerance      int
-		KillExitCodes  []int `json:",omitempty"`
-		KillCountLimit int   `json:",omitempty"`
-	}{
-		Script:         hc.Script,
-		Timeout:        hc.Timeout.Seconds(),
-		Interval:       hc.Interval.Seconds(),
-		Tolerance:      hc.Tolerance,
-		KillCountLimit: hc.KillCountLimit,
-		KillExitCodes:  hc.KillExitCodes,
+	jhc := jsonHealthCheck{
+		Type:             hc.Type,
+		Script:           hc.Script,
+		Command:          hc.Command,
+		Address:          hc.Address,
+		Timeout:          hc.Timeout.Seconds(),
+		Interval:         hc.Interval.Seconds(),
+		Tolerance:        hc.Tolerance,
+		StatusCodes:      hc.StatusCodes,
+		FailureThreshold: hc.FailureThreshold,
+		CPULimit:         hc.CPULimit.Seconds(),
+		KillCountLimit:   hc.KillCountLimit,
+		KillExitCodes:    hc.KillExitCodes,
+		OnFailure:        hc.OnFailure,
+		MaxRestarts:      hc.MaxRestarts,
+		RestartBackoff:   hc.RestartBackoff.Seconds(),
 	}
 	return json.Marshal(jhc)
 }
 
 // UnmarshalJSON implements json.Unmarshaller
 func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
-	jhc := struct {
-		Script         string
-		Timeout        float64
-		Interval       float64
-		Tolerance      int
-		KillExitCodes  []int `json:",omitempty"`
-		KillCountLimit int   `json:",omitempty"`
-	}{}
+	jhc := jsonHealthCheck{}
 	if err := json.Unmarshal(data, &jhc); err != nil {
 		return err
 	}
 	*hc = HealthCheck{
-		Script:         jhc.Script,
-		Timeout:        time.Duration(jhc.Timeout) * time.Second,
-		Interval:       time.Duration(jhc.Interval) * time.Second,
-		Tolerance:      jhc.Tolerance,
-		KillCountLimit: jhc.KillCountLimit,
-		KillExitCodes:  jhc.KillExitCodes,
+		Type:             jhc.Type,
+		Script:           jhc.Script,
+		Command:          jhc.Command,
+		Address:          jhc.Address,
+		Timeout:          time.Duration(jhc.Timeout) * time.Second,
+		Interval:         time.Duration(jhc.Interval) * time.Second,
+		Tolerance:        jhc.Tolerance,
+		StatusCodes:      jhc.StatusCodes,
+		FailureThreshold: jhc.FailureThreshold,
+		CPULimit:         time.Duration(jhc.CPULimit * float64(time.Second)),
+		KillCountLimit:   jhc.KillCountLimit,
+		KillExitCodes:    jhc.KillExitCodes,
+		OnFailure:        jhc.OnFailure,
+		MaxRestarts:      jhc.MaxRestarts,
+		RestartBackoff:   time.Duration(jhc.RestartBackoff * float64(time.Second)),
 	}
 	return nil
 }
 
+// GetType returns the probe type of the health check.
+func (hc *HealthCheck) GetType() string {
+	if hc.Type == "" {
+		return ProbeScript
+	}
+	return hc.Type
+}
+
+// GetRemediation returns what the host agent does about an instance while
+// the check is failing, or nil if it does nothing.
+func (hc *HealthCheck) GetRemediation() *Remediation {
+	if hc.OnFailure == "" || hc.OnFailure == RemediateNone {
+		return nil
+	}
+	return &Remediation{
+		OnFailure:   hc.OnFailure,
+		MaxRestarts: hc.MaxRestarts,
+		Backoff:     hc.RestartBackoff,
+	}
+}
+
 // GetTimeout returns the timeout duration.
 func (hc *HealthCheck) GetTimeout() time.Duration {
 	timeout := hc.Timeout
@@ -200,7 +284,8 @@ func (hc *HealthCheck) Unknown() HealthStatus {
 }
 
 // Run returns the health status as a result of running the health check
-// script.
+// probe.  A failing probe reports that it passes until it has failed
+// FailureThreshold times in a row.
 func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 	logger := plog.WithFields(log.Fields{
 		"service":     key.ServiceID,
@@ -208,8 +293,69 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 		"healthcheck": key.HealthCheckName,
 	})
 	stat.StartedAt = time.Now()
-	cmd := exec.Command("sh", "-c", hc.Script)
-	cmd.Start()
+	switch hc.GetType() {
+	case ProbeHTTP:
+		stat.Status, stat.Message = hc.runHTTP()
+	case ProbeTCP:
+		stat.Status, stat.Message = hc.runTCP()
+	case ProbeExec:
+		if len(hc.Command) == 0 {
+			stat.Status, stat.Message = Failed, "no command"
+			break
+		}
+		stat.Status, stat.Message = hc.runCommand(logger, hc.execCommand())
+	default:
+		stat.Status, stat.Message = hc.runCommand(logger, hc.scriptCommand())
+	}
+	stat.Duration = time.Since(stat.StartedAt)
+
+	if stat.Status == OK {
+		hc.failures = 0
+		return
+	}
+	hc.failures++
+	stat.Failures = hc.failures
+	if hc.failures < hc.FailureThreshold {
+		logger.WithField("failures", hc.failures).WithField("reason", stat.Message).Debug("Health check failed, but has not reached its failure threshold")
+		stat.Status = OK
+	}
+	return
+}
+
+// scriptCommand returns the shell that runs a script probe within its cpu
+// limit.
+func (hc *HealthCheck) scriptCommand() *exec.Cmd {
+	if secs := hc.cpuSeconds(); secs > 0 {
+		return exec.Command("sh", "-c", fmt.Sprintf("ulimit -t %d; %s", secs, hc.Script))
+	}
+	return exec.Command("sh", "-c", hc.Script)
+}
+
+// execCommand returns the command of an exec probe.  A shell only sets the
+// cpu limit before it execs the command, so the arguments are not
+// interpreted.
+func (hc *HealthCheck) execCommand() *exec.Cmd {
+	if secs := hc.cpuSeconds(); secs > 0 {
+		args := []string{"-c", fmt.Sprintf("ulimit -t %d; exec \"$@\"", secs), "sh"}
+		return exec.Command("sh", append(args, hc.Command...)...)
+	}
+	return exec.Command(hc.Command[0], hc.Command[1:]...)
+}
+
+// cpuSeconds returns the cpu limit in the whole seconds that ulimit counts.
+func (hc *HealthCheck) cpuSeconds() int64 {
+	if hc.CPULimit <= 0 {
+		return 0
+	}
+	return int64((hc.CPULimit + time.Second - 1) / time.Second)
+}
+
+// runCommand runs the command of a script or exec probe and updates the
+// kill counter from its exit code.
+func (hc *HealthCheck) runCommand(logger *log.Entry, cmd *exec.Cmd) (Status, string) {
+	if err := cmd.Start(); err != nil {
+		return Failed, err.Error()
+	}
 	timer := time.NewTimer(hc.GetTimeout())
 	errC := make(chan error)
 	go func() { errC <- cmd.Wait() }()
@@ -219,7 +365,6 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 		if err != nil {
 			// If the command gives an error, the healthcheck status is Failed (curl command failed, connection
 			// refused, or any other error message including one that might contribute to the kill count)
-			stat.Status = Failed
 			if hc.KillCountLimit > 0 {
 				logger.Debug("Healthcheck has a KillCount.. checking the exit code")
 
@@ -253,20 +398,79 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 					logger.WithError(err).Warn("Unable to read the health check exit code")
 				}
 			}
-		} else {
-			if hc.KillCounter > 0 {
-				logger.Infof("Resetting KillCounter. KillCounter was %d", hc.KillCounter)
-				hc.KillCounter = 0
-			}
-			stat.Status = OK
+			return Failed, err.Error()
 		}
+		hc.resetKillCounter(logger)
+		return OK, ""
 	case <-timer.C:
 		cmd.Process.Kill()
 		<-errC
-		stat.Status = Timeout
+		return Timeout, fmt.Sprintf("timed out after %s", hc.GetTimeout())
+	}
+}
+
+// runHTTP sends a GET request to the address of an http probe
+func (hc *HealthCheck) runHTTP() (Status, string) {
+	client := &http.Client{Timeout: hc.GetTimeout()}
+	resp, err := client.Get(hc.Address)
+	if err != nil {
+		return hc.networkFailure(err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	passed := resp.StatusCode >= 200 && resp.StatusCode < 400
+	if len(hc.StatusCodes) > 0 {
+		passed = false
+		for _, code := range hc.StatusCodes {
+			if code == resp.StatusCode {
+				passed = true
+				break
+			}
+		}
+	}
+	if !passed {
+		return hc.countFailure(Failed, fmt.Sprintf("received status %s", resp.Status))
+	}
+	hc.KillCounter = 0
+	return OK, ""
+}
+
+// runTCP opens a connection to the address of a tcp probe
+func (hc *HealthCheck) runTCP() (Status, string) {
+	conn, err := net.DialTimeout("tcp", hc.Address, hc.GetTimeout())
+	if err != nil {
+		return hc.networkFailure(err)
+	}
+	conn.Close()
+	hc.KillCounter = 0
+	return OK, ""
+}
+
+// networkFailure returns the status of an http or tcp probe that could not
+// connect
+func (hc *HealthCheck) networkFailure(err error) (Status, string) {
+	if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
+		return hc.countFailure(Timeout, fmt.Sprintf("timed out after %s", hc.GetTimeout()))
+	}
+	return hc.countFailure(Failed, err.Error())
+}
+
+// countFailure counts a failed http or tcp probe toward the kill count.
+// These probes have no exit codes, so every failure counts.
+func (hc *HealthCheck) countFailure(status Status, message string) (Status, string) {
+	if hc.KillCountLimit > 0 {
+		hc.KillCounter++
+	}
+	return status, message
+}
+
+// resetKillCounter resets the kill count after a passing probe
+func (hc *HealthCheck) resetKillCounter(logger *log.Entry) {
+	if hc.KillCounter > 0 {
+		logger.Infof("Resetting KillCounter. KillCounter was %d", hc.KillCounter)
+		hc.KillCounter = 0
 	}
-	stat.Duration = time.Since(stat.StartedAt)
-	return
 }
 
 // Ping performs the health check on the specified interval.
@@ -277,7 +481,7 @@ func (hc *HealthCheck) Ping(cancel <-chan struct{}, key HealthStatusKey, report
 		select {
 		case <-timer.C:
 			stat := hc.Run(key)
-			stat.KillFlag = hc.KillCountLimit > 0 && hc.KillCounter >= hc.KillCountLimit
+			stat.KillFlag = hc.killFlag()
 			timer.Reset(hc.Interval)
 			report(stat)
 		case <-cancel:
@@ -285,3 +489,9 @@ func (hc *HealthCheck) Ping(cancel <-chan struct{}, key HealthStatusKey, report
 		}
 	}
 }
+
+// killFlag returns true if the container should be killed because the probe
+// has failed with a kill exit code too many times.
+func (hc *HealthCheck) killFlag() bool {
+	return hc.KillCountLimit > 0 && hc.KillCounter >= hc.KillCountLimit
+}
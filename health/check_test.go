@@ -17,6 +17,9 @@ package health_test
 
 import (
 	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
 	. "github.com/control-center/serviced/health"
@@ -413,3 +416,105 @@ func (s *HealthCheckTestSuite) TestKillScript_KillCountResetsOnOtherErrors(c *C)
 		}
 	})
 }
+
+func (s *HealthCheckTestSuite) TestRun_Exec(c *C) {
+	// Verify an exec health check does not use a shell
+	check := HealthCheck{
+		Type:     ProbeExec,
+		Command:  []string{"sh", "-c", "exit 0"},
+		Timeout:  time.Second,
+		Interval: time.Second,
+	}
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+
+	check.Command = []string{"false"}
+	stat = check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+}
+
+func (s *HealthCheckTestSuite) TestRun_HTTP(c *C) {
+	// Verify an http health check passes on the expected status codes
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	check := HealthCheck{
+		Type:     ProbeHTTP,
+		Address:  server.URL,
+		Timeout:  time.Second,
+		Interval: time.Second,
+	}
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+
+	check.StatusCodes = []int{http.StatusOK}
+	stat = check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(stat.Message, Not(Equals), "")
+}
+
+func (s *HealthCheckTestSuite) TestRun_TCP(c *C) {
+	// Verify a tcp health check passes while the port is open
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	check := HealthCheck{
+		Type:     ProbeTCP,
+		Address:  listener.Addr().String(),
+		Timeout:  time.Second,
+		Interval: time.Second,
+	}
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+
+	listener.Close()
+	stat = check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(stat.Message, Not(Equals), "")
+}
+
+func (s *HealthCheckTestSuite) TestRun_FailureThreshold(c *C) {
+	// Verify a health check only fails once it reaches its failure threshold
+	check := HealthCheck{
+		Script:           "exit 1",
+		Timeout:          time.Second,
+		Interval:         time.Second,
+		FailureThreshold: 2,
+	}
+	stat := check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+	c.Check(stat.Failures, Equals, 1)
+	stat = check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(stat.Failures, Equals, 2)
+
+	// a passing run resets the count
+	check.Script = "exit 0"
+	stat = check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+	c.Check(stat.Failures, Equals, 0)
+}
+
+func (s *HealthCheckTestSuite) TestRun_CPULimit(c *C) {
+	// Verify a probe is killed once it uses up its cpu time
+	check := HealthCheck{
+		Script:   "while :; do :; done",
+		Timeout:  10 * time.Second,
+		Interval: time.Second,
+		CPULimit: time.Second,
+	}
+	stat := check.Run(hcKey)
+	c.Check(int(stat.Status), Equals, Failed)
+	c.Check(stat.Duration < check.Timeout, Equals, true)
+
+	check = HealthCheck{
+		Type:     ProbeExec,
+		Command:  []string{"sh", "-c", "exit 0"},
+		Timeout:  time.Second,
+		Interval: time.Second,
+		CPULimit: time.Second,
+	}
+	stat = check.Run(hcKey)
+	c.Check(stat.Status, Equals, OK)
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrSlotWait is returned when a batch of health checks is not admitted
+// before it stops waiting.
+var ErrSlotWait = errors.New("timed out waiting for a health check slot")
+
+// Limiter bounds the number of health checks that run at the same time on a
+// host.  Batches are admitted in the order that they ask for slots, so a
+// large batch is not starved by a stream of small ones.  Each admission is a
+// lease that expires after its budget, in case its holder goes away without
+// releasing it.
+type Limiter struct {
+	mu     sync.Mutex
+	size   int
+	used   int
+	nextID uint64
+	leases map[string]*lease
+	queue  []*ticket
+}
+
+type lease struct {
+	slots int
+	timer *time.Timer
+}
+
+type ticket struct {
+	slots  int
+	budget time.Duration
+	ready  chan string
+}
+
+// NewLimiter returns a limiter that runs up to size health checks at a time,
+// or one per cpu if size is not positive.
+func NewLimiter(size int) *Limiter {
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+	return &Limiter{
+		size:   size,
+		leases: make(map[string]*lease),
+	}
+}
+
+// Size returns the number of health checks that may run at the same time.
+func (l *Limiter) Size() int {
+	return l.size
+}
+
+// Acquire waits for the given number of slots and returns the id of a lease
+// that holds them for up to budget.  A batch that is larger than the limiter
+// waits for all of its slots.  It returns ErrSlotWait if the wait channel
+// fires first.
+func (l *Limiter) Acquire(wait <-chan time.Time, slots int, budget time.Duration) (string, error) {
+	if slots < 1 {
+		slots = 1
+	} else if slots > l.size {
+		slots = l.size
+	}
+
+	l.mu.Lock()
+	if len(l.queue) == 0 && l.used+slots <= l.size {
+		id := l.grant(slots, budget)
+		l.mu.Unlock()
+		return id, nil
+	}
+	t := &ticket{slots: slots, budget: budget, ready: make(chan string, 1)}
+	l.queue = append(l.queue, t)
+	l.mu.Unlock()
+
+	select {
+	case id := <-t.ready:
+		return id, nil
+	case <-wait:
+	}
+
+	l.mu.Lock()
+	for i := range l.queue {
+		if l.queue[i] == t {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			// the batch at the front may have been waiting on this one
+			l.admit()
+			l.mu.Unlock()
+			return "", ErrSlotWait
+		}
+	}
+	l.mu.Unlock()
+
+	// the batch was admitted while it gave up waiting
+	l.Release(<-t.ready)
+	return "", ErrSlotWait
+}
+
+// Release frees the slots of a lease.  Releasing an expired lease does
+// nothing.
+func (l *Limiter) Release(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ls, ok := l.leases[id]
+	if !ok {
+		return
+	}
+	ls.timer.Stop()
+	delete(l.leases, id)
+	l.used -= ls.slots
+	l.admit()
+}
+
+// grant leases slots.  Call l.mu.Lock() first.
+func (l *Limiter) grant(slots int, budget time.Duration) string {
+	if budget <= 0 {
+		budget = DefaultTimeout
+	}
+	l.nextID++
+	id := strconv.FormatUint(l.nextID, 10)
+	l.used += slots
+	l.leases[id] = &lease{
+		slots: slots,
+		timer: time.AfterFunc(budget, func() { l.Release(id) }),
+	}
+	return id
+}
+
+// admit grants slots to the waiting batches, in order, while they fit.
+// Call l.mu.Lock() first.
+func (l *Limiter) admit() {
+	for len(l.queue) > 0 && l.used+l.queue[0].slots <= l.size {
+		t := l.queue[0]
+		l.queue = l.queue[1:]
+		t.ready <- l.grant(t.slots, t.budget)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+type LimiterSuite struct{}
+
+var _ = Suite(&LimiterSuite{})
+
+func (s *LimiterSuite) TestAcquireRelease(c *C) {
+	l := NewLimiter(2)
+	c.Assert(l.Size(), Equals, 2)
+
+	id1, err := l.Acquire(nil, 1, time.Minute)
+	c.Assert(err, IsNil)
+	id2, err := l.Acquire(nil, 1, time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(id1, Not(Equals), id2)
+
+	// the limiter is full
+	_, err = l.Acquire(time.After(50*time.Millisecond), 1, time.Minute)
+	c.Assert(err, Equals, ErrSlotWait)
+
+	l.Release(id1)
+	_, err = l.Acquire(time.After(50*time.Millisecond), 1, time.Minute)
+	c.Assert(err, IsNil)
+}
+
+func (s *LimiterSuite) TestAcquire_Order(c *C) {
+	l := NewLimiter(2)
+	id, err := l.Acquire(nil, 1, time.Minute)
+	c.Assert(err, IsNil)
+
+	// a batch of two waits for both slots
+	big := make(chan string)
+	go func() {
+		id, _ := l.Acquire(nil, 2, time.Minute)
+		big <- id
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// a smaller batch that asks later does not get ahead of it
+	_, err = l.Acquire(time.After(50*time.Millisecond), 1, time.Minute)
+	c.Assert(err, Equals, ErrSlotWait)
+
+	l.Release(id)
+	select {
+	case <-big:
+	case <-time.After(time.Second):
+		c.Fatalf("batch was not admitted")
+	}
+}
+
+func (s *LimiterSuite) TestAcquire_Oversized(c *C) {
+	// a batch larger than the limiter waits for all of its slots
+	l := NewLimiter(1)
+	_, err := l.Acquire(nil, 5, time.Minute)
+	c.Assert(err, IsNil)
+}
+
+func (s *LimiterSuite) TestLeaseExpires(c *C) {
+	l := NewLimiter(1)
+	_, err := l.Acquire(nil, 1, 50*time.Millisecond)
+	c.Assert(err, IsNil)
+	_, err = l.Acquire(time.After(time.Second), 1, time.Minute)
+	c.Assert(err, IsNil)
+}
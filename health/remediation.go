@@ -0,0 +1,87 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import "time"
+
+// Remediation actions of a failing health check
+const (
+	// RemediateNone leaves a failing instance running; it is the default
+	RemediateNone = "none"
+	// RemediateRestart restarts the container of a failing instance in place
+	RemediateRestart = "restart"
+	// RemediateReschedule stops a failing instance so that it is scheduled
+	// again, possibly on another host
+	RemediateReschedule = "reschedule"
+)
+
+// DefaultRestartBackoff is the delay after the first restart of an instance
+// when the health check does not set one.
+const DefaultRestartBackoff time.Duration = 30 * time.Second
+
+// MaxRestartBackoff caps the delay between restarts of an instance.
+const MaxRestartBackoff time.Duration = 10 * time.Minute
+
+// Remediation describes what the host agent does about an instance whose
+// health check is failing.
+type Remediation struct {
+	OnFailure   string
+	MaxRestarts int
+	Backoff     time.Duration
+}
+
+// Delay returns how long the host agent waits after the last restart of an
+// instance before restarting it again.  The delay doubles with each restart.
+func (r Remediation) Delay(restarts int) time.Duration {
+	if restarts <= 0 {
+		return 0
+	}
+	delay := r.Backoff
+	if delay <= 0 {
+		delay = DefaultRestartBackoff
+	}
+	for i := 1; i < restarts && delay < MaxRestartBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRestartBackoff {
+		delay = MaxRestartBackoff
+	}
+	return delay
+}
+
+// Action returns the remediation to apply to an instance that has been
+// restarted the given number of times, or RemediateNone if the instance is
+// still backing off from its last restart or has run out of restarts.  A
+// rescheduled instance is first restarted in place MaxRestarts times.
+func (r Remediation) Action(restarts int, lastRestart, now time.Time) string {
+	switch r.OnFailure {
+	case RemediateRestart, RemediateReschedule:
+	default:
+		return RemediateNone
+	}
+
+	if r.MaxRestarts > 0 && restarts >= r.MaxRestarts {
+		if r.OnFailure == RemediateReschedule {
+			return RemediateReschedule
+		}
+		return RemediateNone
+	}
+	if r.OnFailure == RemediateReschedule && r.MaxRestarts == 0 {
+		return RemediateReschedule
+	}
+	if now.Before(lastRestart.Add(r.Delay(restarts))) {
+		return RemediateNone
+	}
+	return RemediateRestart
+}
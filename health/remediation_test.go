@@ -0,0 +1,90 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+type RemediationSuite struct{}
+
+var _ = Suite(&RemediationSuite{})
+
+func (s *RemediationSuite) TestDelay(c *C) {
+	r := Remediation{OnFailure: RemediateRestart, Backoff: time.Minute}
+	c.Check(r.Delay(0), Equals, time.Duration(0))
+	c.Check(r.Delay(1), Equals, time.Minute)
+	c.Check(r.Delay(2), Equals, 2*time.Minute)
+	c.Check(r.Delay(3), Equals, 4*time.Minute)
+	c.Check(r.Delay(10), Equals, MaxRestartBackoff)
+
+	r.Backoff = 0
+	c.Check(r.Delay(1), Equals, DefaultRestartBackoff)
+}
+
+func (s *RemediationSuite) TestAction_Restart(c *C) {
+	now := time.Now()
+	r := Remediation{OnFailure: RemediateRestart, MaxRestarts: 2, Backoff: time.Minute}
+
+	// the first restart is immediate
+	c.Check(r.Action(0, time.Time{}, now), Equals, RemediateRestart)
+
+	// the next restart waits for the backoff
+	c.Check(r.Action(1, now.Add(-30*time.Second), now), Equals, RemediateNone)
+	c.Check(r.Action(1, now.Add(-time.Minute), now), Equals, RemediateRestart)
+
+	// restarts stop at the limit
+	c.Check(r.Action(2, now.Add(-time.Hour), now), Equals, RemediateNone)
+
+	// there is no limit if it is not set
+	r.MaxRestarts = 0
+	c.Check(r.Action(20, now.Add(-time.Hour), now), Equals, RemediateRestart)
+}
+
+func (s *RemediationSuite) TestAction_Reschedule(c *C) {
+	now := time.Now()
+
+	// reschedule right away if there are no restarts in place
+	r := Remediation{OnFailure: RemediateReschedule}
+	c.Check(r.Action(0, time.Time{}, now), Equals, RemediateReschedule)
+
+	// otherwise restart in place until the limit
+	r.MaxRestarts = 1
+	c.Check(r.Action(0, time.Time{}, now), Equals, RemediateRestart)
+	c.Check(r.Action(1, now, now), Equals, RemediateReschedule)
+}
+
+func (s *RemediationSuite) TestAction_None(c *C) {
+	r := Remediation{OnFailure: RemediateNone}
+	c.Check(r.Action(0, time.Time{}, time.Now()), Equals, RemediateNone)
+}
+
+func (s *RemediationSuite) TestGetRemediation(c *C) {
+	hc := HealthCheck{}
+	c.Check(hc.GetRemediation(), IsNil)
+	hc.OnFailure = RemediateNone
+	c.Check(hc.GetRemediation(), IsNil)
+
+	hc = HealthCheck{OnFailure: RemediateRestart, MaxRestarts: 3, RestartBackoff: time.Minute}
+	c.Check(*hc.GetRemediation(), DeepEquals, Remediation{
+		OnFailure:   RemediateRestart,
+		MaxRestarts: 3,
+		Backoff:     time.Minute,
+	})
+}
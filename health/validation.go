@@ -15,6 +15,9 @@ package health
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+
 	"github.com/control-center/serviced/validation"
 )
 
@@ -24,6 +27,46 @@ func (hc HealthCheck) ValidEntity() error {
 		violations.Add(fmt.Errorf("the KillCountLimit must be set if KillExitCodes are specified"))
 	}
 
+	probe := hc.GetType()
+	violations.Add(validation.StringIn(probe, ProbeScript, ProbeExec, ProbeHTTP, ProbeTCP))
+	switch probe {
+	case ProbeExec:
+		if len(hc.Command) == 0 {
+			violations.Add(fmt.Errorf("exec health checks require a Command"))
+		}
+	case ProbeHTTP:
+		if u, err := url.Parse(hc.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			violations.Add(fmt.Errorf("http health checks require an http or https url Address, not %q", hc.Address))
+		}
+	case ProbeTCP:
+		if _, _, err := net.SplitHostPort(hc.Address); err != nil {
+			violations.Add(fmt.Errorf("tcp health checks require a host:port Address, not %q", hc.Address))
+		}
+	}
+	if (probe == ProbeHTTP || probe == ProbeTCP) && len(hc.KillExitCodes) > 0 {
+		violations.Add(fmt.Errorf("KillExitCodes are only supported by script and exec health checks"))
+	}
+	if len(hc.StatusCodes) > 0 && probe != ProbeHTTP {
+		violations.Add(fmt.Errorf("StatusCodes are only supported by http health checks"))
+	}
+	if hc.FailureThreshold < 0 {
+		violations.Add(fmt.Errorf("the FailureThreshold must not be negative"))
+	}
+	if hc.CPULimit < 0 {
+		violations.Add(fmt.Errorf("the CPULimit must not be negative"))
+	} else if hc.CPULimit > 0 && (probe == ProbeHTTP || probe == ProbeTCP) {
+		violations.Add(fmt.Errorf("CPULimit is only supported by script and exec health checks"))
+	}
+	if hc.OnFailure != "" {
+		violations.Add(validation.StringIn(hc.OnFailure, RemediateNone, RemediateRestart, RemediateReschedule))
+	}
+	if hc.MaxRestarts < 0 {
+		violations.Add(fmt.Errorf("the MaxRestarts must not be negative"))
+	}
+	if hc.RestartBackoff < 0 {
+		violations.Add(fmt.Errorf("the RestartBackoff must not be negative"))
+	}
+
 	if violations.HasError() {
 		return violations
 	}
@@ -16,6 +16,8 @@
 package health_test
 
 import (
+	"time"
+
 	. "github.com/control-center/serviced/health"
 	. "gopkg.in/check.v1"
 )
@@ -37,3 +39,61 @@ func (vs *ValidationSuite) Test_Validation_Invalid_HealthCheck(c *C) {
 	err = hc.ValidEntity()
 	c.Assert(err, IsNil)
 }
+
+func (vs *ValidationSuite) Test_Validation_Probes(c *C) {
+	// the probe type must be known
+	hc := HealthCheck{Type: "udp"}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	// exec probes require a command
+	hc = HealthCheck{Type: ProbeExec}
+	c.Assert(hc.ValidEntity(), NotNil)
+	hc.Command = []string{"true"}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	// http probes require a url
+	hc = HealthCheck{Type: ProbeHTTP, Address: "localhost:8080"}
+	c.Assert(hc.ValidEntity(), NotNil)
+	hc.Address = "http://localhost:8080/ping"
+	hc.StatusCodes = []int{200}
+	c.Assert(hc.ValidEntity(), IsNil)
+	hc.KillExitCodes = []int{1}
+	hc.KillCountLimit = 1
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	// tcp probes require a host:port
+	hc = HealthCheck{Type: ProbeTCP, Address: "localhost"}
+	c.Assert(hc.ValidEntity(), NotNil)
+	hc.Address = "localhost:8080"
+	c.Assert(hc.ValidEntity(), IsNil)
+	hc.StatusCodes = []int{200}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	// the failure threshold must not be negative
+	hc = HealthCheck{FailureThreshold: -1}
+	c.Assert(hc.ValidEntity(), NotNil)
+}
+
+func (vs *ValidationSuite) Test_Validation_Remediation(c *C) {
+	hc := HealthCheck{OnFailure: "panic"}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{OnFailure: RemediateRestart, MaxRestarts: -1}
+	c.Assert(hc.ValidEntity(), NotNil)
+	hc.MaxRestarts = 3
+	hc.RestartBackoff = -time.Second
+	c.Assert(hc.ValidEntity(), NotNil)
+	hc.RestartBackoff = time.Minute
+	c.Assert(hc.ValidEntity(), IsNil)
+}
+
+func (vs *ValidationSuite) Test_Validation_CPULimit(c *C) {
+	hc := HealthCheck{CPULimit: -time.Second}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Type: ProbeTCP, Address: "localhost:8080", CPULimit: time.Second}
+	c.Assert(hc.ValidEntity(), NotNil)
+
+	hc = HealthCheck{Script: "true", CPULimit: time.Second}
+	c.Assert(hc.ValidEntity(), IsNil)
+}
@@ -141,7 +141,8 @@ type IService struct {
 	root            string
 	actions         chan actionrequest
 	startTime       time.Time
-	restartCount    int
+	restartCount    int         // consecutive runs shorter than MINIMUM_VIABLE_LIFESPAN
+	restarts        []time.Time // unexpected restarts within the last hour
 	dockerLogDriver string            // which log driver to use with containers
 	dockerLogConfig map[string]string // options for the log driver
 	docker          dfsdocker.Docker  // Docker API, needed to get stats
@@ -151,6 +152,7 @@ type IService struct {
 
 	lock           *sync.RWMutex
 	healthStatuses []map[string]*domain.HealthCheckStatus
+	healthHistory  *healthHistory
 	customStats    CustomStatsFunction
 }
 
@@ -178,6 +180,7 @@ func NewIService(sd IServiceDefinition) (*IService, error) {
 		exited:             nil,
 		lock:               &sync.RWMutex{},
 		healthStatuses:     nil,
+		healthHistory:      newHealthHistory(HEALTH_HISTORY_SIZE),
 		customStats:        nil,
 	}
 
@@ -261,6 +264,15 @@ func (svc *IService) Restart() error {
 	return <-response
 }
 
+// Signal sends a signal to the process running in the service's container.
+func (svc *IService) Signal(sig dockerclient.Signal) error {
+	ctr, err := docker.FindContainer(svc.name())
+	if err != nil {
+		return err
+	}
+	return ctr.Signal(sig)
+}
+
 func (svc *IService) Exec(command []string) ([]byte, error) {
 	ctr, err := docker.FindContainer(svc.name())
 	if err != nil {
@@ -560,6 +572,7 @@ func (svc *IService) run() {
 	var newExited <-chan int
 	var err error
 	var collecting bool
+	var pendingRestart <-chan time.Time
 	haltStats := make(chan struct{})
 	haltHealthChecks := make(chan struct{})
 	haltCustomStats := make(chan struct{})
@@ -574,6 +587,13 @@ func (svc *IService) run() {
 			switch req.action {
 			case stop:
 				log.Debug("Stopping internal service container")
+				if pendingRestart != nil {
+					// the service is waiting out its restart backoff
+					pendingRestart = nil
+					svc.halt(&collecting, haltStats, haltHealthChecks, haltCustomStats)
+					req.response <- nil
+					continue
+				}
 				if !svc.IsRunning() {
 					req.response <- ErrNotRunning
 					continue
@@ -606,6 +626,7 @@ func (svc *IService) run() {
 					req.response <- ErrRunning
 					continue
 				}
+				pendingRestart = nil
 
 				newExited, err = svc.start()
 				if err != nil && svc.Recover != nil {
@@ -634,6 +655,7 @@ func (svc *IService) run() {
 				req.response <- nil
 			case restart:
 				log.Debug("Restarting internal service")
+				pendingRestart = nil
 				if svc.IsRunning() {
 
 					if collecting {
@@ -674,53 +696,60 @@ func (svc *IService) run() {
 				"exitcode": rc,
 			}).Warn("Internal service exited unexpectedly")
 
-			stopService := svc.isFlapping()
-			if !stopService {
-				log.Debug("Restarting internal service")
-				newExited, err = svc.start()
-				svc.setExitedChannel(newExited)
-				if err != nil {
-					log.WithError(err).Error("Unable to restart internal service")
-					stopService = true
-				}
-				log.Info("Restarted internal service")
+			delay, ok := svc.nextRestart(time.Now())
+			if !ok {
+				log.Error("Not restarting internal service; restarted too many times in the last hour")
+				svc.halt(&collecting, haltStats, haltHealthChecks, haltCustomStats)
+				continue
 			}
-
-			if stopService {
-				if collecting {
-					haltStats <- struct{}{}
-					if len(svc.HealthChecks) > 0 {
-						haltHealthChecks <- struct{}{}
-					}
-					if svc.customStats != nil {
-						haltCustomStats <- struct{}{}
-					}
-					collecting = false
-				}
-				log.Error("Not restarting internal service; failed too many times")
-				svc.stop()
+			if delay > 0 {
+				log.WithFields(logrus.Fields{
+					"backoff": delay,
+				}).Info("Waiting to restart internal service")
 				svc.setExitedChannel(nil)
+				pendingRestart = time.After(delay)
+				continue
 			}
+			svc.restartAfterExit(&collecting, haltStats, haltHealthChecks, haltCustomStats)
+		case <-pendingRestart:
+			pendingRestart = nil
+			svc.restartAfterExit(&collecting, haltStats, haltHealthChecks, haltCustomStats)
 		}
 	}
 }
 
-// isFlapping returns true if the service is flapping (starting/stopping repeatedly).
-//
-// A service is considered flappping, if has been running for less than 60 seconds
-// in 3 consecutive calls to this function.
-func (svc *IService) isFlapping() bool {
-	const MINIMUM_VIABLE_LIFESPAN = 60
-	const FLAPPING_THRESHOLD = 3
-
-	upTime := time.Since(svc.startTime)
-	if upTime.Seconds() < MINIMUM_VIABLE_LIFESPAN {
-		svc.restartCount += 1
-	} else {
-		svc.restartCount = 0
+// restartAfterExit restarts the service after it exited unexpectedly, and
+// stops it for good if it cannot be started.
+func (svc *IService) restartAfterExit(collecting *bool, haltStats, haltHealthChecks, haltCustomStats chan<- struct{}) {
+	log := log.WithFields(logrus.Fields{
+		"isvc": svc.Name,
+	})
+
+	log.Debug("Restarting internal service")
+	newExited, err := svc.start()
+	svc.setExitedChannel(newExited)
+	if err != nil {
+		log.WithError(err).Error("Unable to restart internal service")
+		svc.halt(collecting, haltStats, haltHealthChecks, haltCustomStats)
+		return
 	}
+	log.Info("Restarted internal service")
+}
 
-	return svc.restartCount >= FLAPPING_THRESHOLD
+// halt stops the service and its collectors without restarting it.
+func (svc *IService) halt(collecting *bool, haltStats, haltHealthChecks, haltCustomStats chan<- struct{}) {
+	if *collecting {
+		haltStats <- struct{}{}
+		if len(svc.HealthChecks) > 0 {
+			haltHealthChecks <- struct{}{}
+		}
+		if svc.customStats != nil {
+			haltCustomStats <- struct{}{}
+		}
+		*collecting = false
+	}
+	svc.stop()
+	svc.setExitedChannel(nil)
 }
 
 func (svc *IService) checkVolumes(ctr *docker.Container) bool {
@@ -927,6 +956,7 @@ func (svc *IService) setHealthStatus(result error, currentTime int64, healthChec
 	})
 
 	if healthStatus, found := svc.healthStatuses[instanceIndex][healthCheckName]; found {
+		previous := healthStatus.Status
 		if result == nil {
 			if healthStatus.Status != "passed" && healthStatus.Status != "unknown" {
 				log.WithFields(logrus.Fields{
@@ -943,6 +973,7 @@ func (svc *IService) setHealthStatus(result error, currentTime int64, healthChec
 		if healthStatus.StartedAt == 0 {
 			healthStatus.StartedAt = currentTime
 		}
+		svc.recordTransition(healthStatus, instanceIndex, previous)
 	} else {
 		log.WithFields(logrus.Fields{
 			"healthcheck": healthCheckName,
@@ -962,8 +993,9 @@ func (svc *IService) setStoppedHealthStatus(stopResult error) {
 	svc.lock.Lock()
 	defer svc.lock.Unlock()
 
-	for _, statusMap := range svc.healthStatuses {
+	for instanceIndex, statusMap := range svc.healthStatuses {
 		for _, healthStatus := range statusMap {
+			previous := healthStatus.Status
 			healthStatus.Status = "stopped"
 
 			if stopResult == nil {
@@ -974,10 +1006,27 @@ func (svc *IService) setStoppedHealthStatus(stopResult error) {
 
 			healthStatus.Timestamp = time.Now().Unix()
 			healthStatus.StartedAt = 0
+			svc.recordTransition(healthStatus, instanceIndex, previous)
 		}
 	}
 }
 
+// recordTransition adds the health status to the service's health history if
+// its status differs from the previous one.  The caller must hold svc.lock.
+func (svc *IService) recordTransition(healthStatus *domain.HealthCheckStatus, instanceIndex int, previous string) {
+	if svc.healthHistory == nil || healthStatus.Status == previous {
+		return
+	}
+	svc.healthHistory.add(HealthTransition{
+		Timestamp:   healthStatus.Timestamp,
+		HealthCheck: healthStatus.Name,
+		Instance:    instanceIndex,
+		From:        previous,
+		To:          healthStatus.Status,
+		Failure:     healthStatus.Failure,
+	})
+}
+
 type containerStat struct {
 	Metric    string            `json:"metric"`
 	Value     string            `json:"value"`
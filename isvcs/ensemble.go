@@ -0,0 +1,263 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	zkPeerPort   = 2888
+	zkLeaderPort = 3888
+	zkClientPort = 2181
+	zkCliCommand = "zkCli.sh"
+)
+
+var (
+	// ErrZKBadMember is returned when a quorum member is not of the form
+	// ID@HOST:PEERPORT:LEADERPORT
+	ErrZKBadMember = errors.New("zookeeper quorum member must be of the form ID@HOST:PEERPORT:LEADERPORT")
+	// ErrZKNoEnsemble is returned when zookeeper is not running as an ensemble
+	ErrZKNoEnsemble = errors.New("zookeeper is not configured as an ensemble")
+	// ErrZKMemberExists is returned when adding a host that is already in
+	// the ensemble
+	ErrZKMemberExists = errors.New("host is already a member of the zookeeper ensemble")
+	// ErrZKMemberNotFound is returned when removing a host that is not in the
+	// ensemble
+	ErrZKMemberNotFound = errors.New("host is not a member of the zookeeper ensemble")
+	// ErrZKLastMember is returned when removing the last host of the ensemble
+	ErrZKLastMember = errors.New("cannot remove the last member of the zookeeper ensemble")
+	// ErrZKNoQuorum is returned when a majority of the ensemble is not
+	// serving requests behind a leader
+	ErrZKNoQuorum = errors.New("zookeeper ensemble does not have a quorum")
+	// ErrZKReconfigNotSupported is returned when the zookeeper ensemble cannot
+	// be reconfigured while it is running
+	ErrZKReconfigNotSupported = errors.New("zookeeper does not support dynamic reconfiguration")
+)
+
+// ZKMember is a server in the zookeeper ensemble
+type ZKMember struct {
+	ID         int
+	Host       string
+	PeerPort   int
+	LeaderPort int
+}
+
+// String returns the member as it is set in SERVICED_ISVCS_ZOOKEEPER_QUORUM
+func (m ZKMember) String() string {
+	return fmt.Sprintf("%d@%s:%d:%d", m.ID, m.Host, m.PeerPort, m.LeaderPort)
+}
+
+// ClientAddress returns the address that clients connect to
+func (m ZKMember) ClientAddress() string {
+	return net.JoinHostPort(m.Host, strconv.Itoa(zkClientPort))
+}
+
+// ServerSpec returns the member as it is set in a dynamic reconfiguration
+func (m ZKMember) ServerSpec() string {
+	return fmt.Sprintf("server.%d=%s:%d:%d;%d", m.ID, m.Host, m.PeerPort, m.LeaderPort, zkClientPort)
+}
+
+// ParseZKQuorum returns the members of a SERVICED_ISVCS_ZOOKEEPER_QUORUM
+// setting, ordered by id.
+func ParseZKQuorum(quorum []string) ([]ZKMember, error) {
+	members := []ZKMember{}
+	for _, entry := range quorum {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "@", 2)
+		if len(parts) != 2 {
+			return nil, ErrZKBadMember
+		}
+		id, err := strconv.Atoi(parts[0])
+		if err != nil || id < 1 {
+			return nil, ErrZKBadMember
+		}
+		addr := strings.Split(parts[1], ":")
+		if len(addr) != 3 || addr[0] == "" {
+			return nil, ErrZKBadMember
+		}
+		peer, err := strconv.Atoi(addr[1])
+		if err != nil {
+			return nil, ErrZKBadMember
+		}
+		leader, err := strconv.Atoi(addr[2])
+		if err != nil {
+			return nil, ErrZKBadMember
+		}
+		members = append(members, ZKMember{ID: id, Host: addr[0], PeerPort: peer, LeaderPort: leader})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+	return members, nil
+}
+
+// FormatZKQuorum returns the SERVICED_ISVCS_ZOOKEEPER_QUORUM setting of the
+// given members.
+func FormatZKQuorum(members []ZKMember) []string {
+	quorum := make([]string, len(members))
+	for i, m := range members {
+		quorum[i] = m.String()
+	}
+	return quorum
+}
+
+// ZKClientAddresses returns the SERVICED_ZK setting of the given members.
+func ZKClientAddresses(members []ZKMember) []string {
+	addrs := make([]string, len(members))
+	for i, m := range members {
+		addrs[i] = m.ClientAddress()
+	}
+	return addrs
+}
+
+// AddZKMember returns the ensemble with a new member on the given host.  The
+// new member gets the next unused id.
+func AddZKMember(members []ZKMember, host string) ([]ZKMember, ZKMember, error) {
+	id := 0
+	for _, m := range members {
+		if m.Host == host {
+			return nil, ZKMember{}, ErrZKMemberExists
+		}
+		if m.ID > id {
+			id = m.ID
+		}
+	}
+	member := ZKMember{ID: id + 1, Host: host, PeerPort: zkPeerPort, LeaderPort: zkLeaderPort}
+	result := append(append([]ZKMember{}, members...), member)
+	return result, member, nil
+}
+
+// RemoveZKMember returns the ensemble without the member on the given host.
+func RemoveZKMember(members []ZKMember, host string) ([]ZKMember, ZKMember, error) {
+	result := []ZKMember{}
+	var removed *ZKMember
+	for i, m := range members {
+		if m.Host == host {
+			removed = &members[i]
+			continue
+		}
+		result = append(result, m)
+	}
+	if removed == nil {
+		return nil, ZKMember{}, ErrZKMemberNotFound
+	} else if len(result) == 0 {
+		return nil, ZKMember{}, ErrZKLastMember
+	}
+	return result, *removed, nil
+}
+
+// ZKMemberStatus is the state of a member of the ensemble
+type ZKMemberStatus struct {
+	Member ZKMember
+	Mode   string // leader, follower, observer or standalone
+	Error  string
+}
+
+// Serving returns true if the member is serving requests
+func (s ZKMemberStatus) Serving() bool {
+	return s.Mode != ""
+}
+
+// CheckZKEnsemble returns the state of each member of the ensemble, and
+// ErrZKNoQuorum if a majority of the members is not serving requests behind
+// a single leader.
+func CheckZKEnsemble(members []ZKMember, timeout time.Duration) ([]ZKMemberStatus, error) {
+	statuses := make([]ZKMemberStatus, len(members))
+	serving, leaders := 0, 0
+	for i, m := range members {
+		statuses[i].Member = m
+		srvr, err := zkFourLetterWord(m.ClientAddress(), "srvr", timeout)
+		if err != nil {
+			statuses[i].Error = err.Error()
+			continue
+		}
+		mode := parseZKMode(string(srvr))
+		if mode == "" {
+			statuses[i].Error = "not currently serving requests"
+			continue
+		}
+		statuses[i].Mode = mode
+		switch mode {
+		case "leader":
+			leaders++
+			serving++
+		case "follower":
+			serving++
+		}
+	}
+	if leaders != 1 || serving <= len(members)/2 {
+		return statuses, ErrZKNoQuorum
+	}
+	return statuses, nil
+}
+
+// parseZKMode returns the mode of a srvr response
+func parseZKMode(srvr string) string {
+	for _, line := range strings.Split(srvr, "\n") {
+		if strings.HasPrefix(line, "Mode:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Mode:"))
+		}
+	}
+	return ""
+}
+
+// ReconfigZKEnsemble adds and removes members of the running ensemble
+// through the zookeeper internal service on this host.  Returns
+// ErrZKReconfigNotSupported if zookeeper is older than 3.5 or dynamic
+// reconfiguration is disabled.
+func ReconfigZKEnsemble(add []ZKMember, remove []ZKMember) error {
+	command := []string{zkCliCommand, "-server", net.JoinHostPort("127.0.0.1", strconv.Itoa(zkClientPort)), "reconfig"}
+	if len(add) > 0 {
+		specs := make([]string, len(add))
+		for i, m := range add {
+			specs[i] = m.ServerSpec()
+		}
+		command = append(command, "-add", strings.Join(specs, ","))
+	}
+	if len(remove) > 0 {
+		ids := make([]string, len(remove))
+		for i, m := range remove {
+			ids[i] = strconv.Itoa(m.ID)
+		}
+		command = append(command, "-remove", strings.Join(ids, ","))
+	}
+	output, err := zookeeper.Exec(command)
+	if err == nil {
+		err = checkZKReconfig(string(output))
+	}
+	return err
+}
+
+// checkZKReconfig returns an error if the output of a zkCli reconfig says
+// that the change was not made.
+func checkZKReconfig(output string) error {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "command not found"),
+		strings.Contains(lower, "reconfig is disabled"),
+		strings.Contains(lower, "reconfigdisabled"),
+		strings.Contains(lower, "unimplemented"):
+		return ErrZKReconfigNotSupported
+	case strings.Contains(lower, "exception"), strings.Contains(lower, "error"):
+		return fmt.Errorf("zookeeper reconfiguration failed: %s", strings.TrimSpace(output))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseZKQuorum(t *testing.T) {
+	members, err := ParseZKQuorum([]string{"2@host2:2888:3888", " 1@host1:2889:3889 ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []ZKMember{
+		{ID: 1, Host: "host1", PeerPort: 2889, LeaderPort: 3889},
+		{ID: 2, Host: "host2", PeerPort: 2888, LeaderPort: 3888},
+	}, members)
+	assert.Equal(t, []string{"1@host1:2889:3889", "2@host2:2888:3888"}, FormatZKQuorum(members))
+	assert.Equal(t, []string{"host1:2181", "host2:2181"}, ZKClientAddresses(members))
+	assert.Equal(t, "server.2=host2:2888:3888;2181", members[1].ServerSpec())
+
+	for _, bad := range []string{"host1:2888:3888", "zk1@host1:2888:3888", "1@host1:2888", "1@:2888:3888", "1@host1:peer:3888"} {
+		_, err := ParseZKQuorum([]string{bad})
+		assert.Equal(t, ErrZKBadMember, err, bad)
+	}
+}
+
+func TestAddZKMember(t *testing.T) {
+	members, _ := ParseZKQuorum([]string{"1@host1:2888:3888", "3@host3:2888:3888"})
+	result, member, err := AddZKMember(members, "host4")
+	assert.NoError(t, err)
+	assert.Equal(t, ZKMember{ID: 4, Host: "host4", PeerPort: 2888, LeaderPort: 3888}, member)
+	assert.Equal(t, []string{"1@host1:2888:3888", "3@host3:2888:3888", "4@host4:2888:3888"}, FormatZKQuorum(result))
+	assert.Len(t, members, 2)
+
+	_, _, err = AddZKMember(members, "host3")
+	assert.Equal(t, ErrZKMemberExists, err)
+}
+
+func TestRemoveZKMember(t *testing.T) {
+	members, _ := ParseZKQuorum([]string{"1@host1:2888:3888", "2@host2:2888:3888"})
+	result, member, err := RemoveZKMember(members, "host1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, member.ID)
+	assert.Equal(t, []string{"2@host2:2888:3888"}, FormatZKQuorum(result))
+
+	_, _, err = RemoveZKMember(members, "host5")
+	assert.Equal(t, ErrZKMemberNotFound, err)
+	_, _, err = RemoveZKMember(result, "host2")
+	assert.Equal(t, ErrZKLastMember, err)
+}
+
+func TestParseZKMode(t *testing.T) {
+	srvr := "Zookeeper version: 3.4.10\nLatency min/avg/max: 0/0/0\nMode: follower\nNode count: 4\n"
+	assert.Equal(t, "follower", parseZKMode(srvr))
+	assert.Equal(t, "", parseZKMode("This ZooKeeper instance is not currently serving requests\n"))
+}
+
+func TestCheckZKReconfig(t *testing.T) {
+	assert.NoError(t, checkZKReconfig("Committed new configuration:\nserver.1=host1:2888:3888:participant;0.0.0.0:2181\nversion=100000003\n"))
+	assert.Equal(t, ErrZKReconfigNotSupported, checkZKReconfig("ZooKeeper -server host:port cmd args\nCommand not found: Command not found reconfig\n"))
+	assert.Equal(t, ErrZKReconfigNotSupported, checkZKReconfig("KeeperErrorCode = ReconfigDisabled\n"))
+	assert.Error(t, checkZKReconfig("KeeperErrorCode = NewConfigNoQuorum\nException in thread main\n"))
+}
@@ -59,6 +59,15 @@ func (health ESHealth) String() string {
 	return "unknown"
 }
 
+// The directories in the elasticsearch containers where the snapshot
+// repositories of backups are stored.  They are mounted from the
+// ESSnapshotsVolume of each isvc.
+const (
+	ESSnapshotsVolume     = "snapshots"
+	esServicedSnapshotDir = "/opt/elasticsearch-serviced/snapshots"
+	esLogstashSnapshotDir = "/opt/elasticsearch-logstash/snapshots"
+)
+
 const DEFAULT_ES_STARTUP_TIMEOUT_SECONDS = 240 //default startup timeout in seconds (4 minutes)
 const MIN_ES_STARTUP_TIMEOUT_SECONDS = 30      //minimum startup timeout in seconds
 
@@ -98,7 +107,7 @@ func initElasticSearch() {
 			Tag:            IMAGE_TAG,
 			Command:        func() string { return "" },
 			PortBindings:   []portBinding{elasticsearch_servicedPortBinding},
-			Volumes:        map[string]string{"data": "/opt/elasticsearch-serviced/data"},
+			Volumes:        map[string]string{"data": "/opt/elasticsearch-serviced/data", ESSnapshotsVolume: esServicedSnapshotDir},
 			Configuration:  make(map[string]interface{}),
 			HealthChecks:   healthChecks,
 			StartupTimeout: time.Duration(DEFAULT_ES_STARTUP_TIMEOUT_SECONDS) * time.Second,
@@ -114,7 +123,7 @@ func initElasticSearch() {
 		if clusterName, ok := elasticsearch_serviced.Configuration["cluster"]; ok {
 			clusterArg = fmt.Sprintf(" -Des.cluster.name=%s ", clusterName)
 		}
-		return fmt.Sprintf(`exec /opt/elasticsearch-serviced/bin/elasticsearch -f -Des.node.name=%s %s`, elasticsearch_serviced.Name, clusterArg)
+		return fmt.Sprintf(`exec /opt/elasticsearch-serviced/bin/elasticsearch -f -Des.node.name=%s -Des.path.repo=%s %s`, elasticsearch_serviced.Name, esServicedSnapshotDir, clusterArg)
 	}
 
 	serviceName = "elasticsearch-logstash"
@@ -142,7 +151,7 @@ func initElasticSearch() {
 			Tag:            IMAGE_TAG,
 			Command:        func() string { return "" },
 			PortBindings:   []portBinding{elasticsearch_logstashPortBinding},
-			Volumes:        map[string]string{"data": "/opt/elasticsearch-logstash/data"},
+			Volumes:        map[string]string{"data": "/opt/elasticsearch-logstash/data", ESSnapshotsVolume: esLogstashSnapshotDir},
 			Configuration:  make(map[string]interface{}),
 			HealthChecks:   healthChecks,
 			Recover:        recoverES,
@@ -157,8 +166,9 @@ func initElasticSearch() {
 	}
 
 	// This value will be overwritten by SERVICED_ISVCS_ENV_X in
-	// /etc/default/serviced
-	envPerService[serviceName]["ES_JAVA_OPTS"] = "-Xmx4g"
+	// /etc/default/serviced, which must keep the path.repo setting for
+	// backups to include the logstash indices.
+	envPerService[serviceName]["ES_JAVA_OPTS"] = "-Xmx4g -Des.path.repo=" + esLogstashSnapshotDir
 	elasticsearch_logstash.Command = func() string {
 		nodeName := elasticsearch_logstash.Name
 		clusterName := elasticsearch_logstash.Configuration["cluster"]
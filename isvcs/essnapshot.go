@@ -0,0 +1,324 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// restoreHealthTimeout is how long a restore waits for the restored indices to
+// be allocated
+const restoreHealthTimeout = "5m"
+
+// SnapshotClient takes and restores snapshots of the indices of an
+// elasticsearch isvc for backups.  Each snapshot is written to its own
+// filesystem repository, so that the directory of the repository holds
+// everything needed to restore it and can be copied into a backup file.
+type SnapshotClient struct {
+	address       string
+	hostPath      string
+	containerPath string
+	client        *http.Client
+}
+
+// NewServicedSnapshotClient returns a snapshot client for the control plane
+// elasticsearch at the given host:port address.
+func NewServicedSnapshotClient(address, isvcsPath string) *SnapshotClient {
+	return newSnapshotClient(address, filepath.Join(isvcsPath, "elasticsearch-serviced", ESSnapshotsVolume), esServicedSnapshotDir)
+}
+
+// NewLogstashSnapshotClient returns a snapshot client for the logstash
+// elasticsearch at the given host:port address.
+func NewLogstashSnapshotClient(address, isvcsPath string) *SnapshotClient {
+	return newSnapshotClient(address, filepath.Join(isvcsPath, "elasticsearch-logstash", ESSnapshotsVolume), esLogstashSnapshotDir)
+}
+
+func newSnapshotClient(address, hostPath, containerPath string) *SnapshotClient {
+	return &SnapshotClient{
+		address:       address,
+		hostPath:      hostPath,
+		containerPath: containerPath,
+		client:        &http.Client{Timeout: 2 * time.Hour},
+	}
+}
+
+// SnapshotPath returns the directory on the host of the repository of a
+// snapshot.
+func (c *SnapshotClient) SnapshotPath(name string) string {
+	return filepath.Join(c.hostPath, name)
+}
+
+// IndexSizes returns the size in bytes of the primary shards of the indices
+// that match the pattern.
+func (c *SnapshotClient) IndexSizes(pattern string) (map[string]int64, error) {
+	data, status, err := c.do("GET", fmt.Sprintf("/%s/_stats/store", pattern), nil)
+	if err != nil {
+		return nil, err
+	} else if status == http.StatusNotFound {
+		// no indices match the pattern
+		return map[string]int64{}, nil
+	} else if status != http.StatusOK {
+		return nil, fmt.Errorf("received %d status code getting index sizes: %s", status, data)
+	}
+	return parseIndexSizes(data)
+}
+
+// parseIndexSizes returns the primary store sizes of an elasticsearch
+// index stats response.
+func parseIndexSizes(data []byte) (map[string]int64, error) {
+	var result struct {
+		Indices map[string]struct {
+			Primaries struct {
+				Store struct {
+					SizeInBytes int64 `json:"size_in_bytes"`
+				} `json:"store"`
+			} `json:"primaries"`
+		} `json:"indices"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64)
+	for index, stats := range result.Indices {
+		sizes[index] = stats.Primaries.Store.SizeInBytes
+	}
+	return sizes, nil
+}
+
+// CreateSnapshot creates a repository for a snapshot and waits until the
+// given indices are written to it.  If no indices are given, all of the
+// indices are included.
+func (c *SnapshotClient) CreateSnapshot(name string, indices []string) error {
+	if err := c.createRepository(name); err != nil {
+		return err
+	}
+	body, err := buildSnapshotRequest(indices)
+	if err != nil {
+		return err
+	}
+	data, status, err := c.do("PUT", fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=true", name, name), body)
+	if err != nil {
+		return err
+	} else if status != http.StatusOK {
+		return fmt.Errorf("received %d status code creating snapshot %s: %s", status, name, data)
+	}
+	return checkSnapshotState(data)
+}
+
+// buildSnapshotRequest returns the request body of a snapshot or restore of
+// the given indices.  The cluster state is never included, so that a
+// restore does not change the settings of the running cluster.
+func buildSnapshotRequest(indices []string) ([]byte, error) {
+	request := map[string]interface{}{
+		"ignore_unavailable":   true,
+		"include_global_state": false,
+	}
+	if len(indices) > 0 {
+		request["indices"] = strings.Join(indices, ",")
+	}
+	return json.Marshal(request)
+}
+
+// checkSnapshotState returns an error if a snapshot did not succeed.
+func checkSnapshotState(data []byte) error {
+	var result struct {
+		Snapshot struct {
+			Snapshot string        `json:"snapshot"`
+			State    string        `json:"state"`
+			Failures []interface{} `json:"failures"`
+		} `json:"snapshot"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if result.Snapshot.State != "SUCCESS" {
+		return fmt.Errorf("snapshot %s finished with state %q: %v", result.Snapshot.Snapshot, result.Snapshot.State, result.Snapshot.Failures)
+	}
+	return nil
+}
+
+// RestoreSnapshot restores the given indices from the repository of a
+// snapshot, replacing the indices that already exist.  The repository
+// directory must already hold the snapshot.  The restore only succeeds once
+// every shard was restored and the restored indices are allocated, so that
+// a partial restore is never mistaken for a complete one.  If the restore
+// fails, the indices that were closed for it are reopened.
+func (c *SnapshotClient) RestoreSnapshot(name string, indices []string) error {
+	if err := c.createRepository(name); err != nil {
+		return err
+	}
+	if len(indices) > 0 {
+		// open indices cannot be replaced by a restore
+		path := fmt.Sprintf("/%s/_close?ignore_unavailable=true", strings.Join(indices, ","))
+		data, status, err := c.do("POST", path, nil)
+		if err != nil {
+			return err
+		} else if status != http.StatusOK && status != http.StatusNotFound {
+			return fmt.Errorf("received %d status code closing indices: %s", status, data)
+		}
+	}
+	if err := c.restore(name, indices); err != nil {
+		c.openIndices(indices)
+		return err
+	}
+	return c.waitForIndices(indices)
+}
+
+// restore restores the indices of a snapshot and checks that none of their
+// shards failed.
+func (c *SnapshotClient) restore(name string, indices []string) error {
+	body, err := buildSnapshotRequest(indices)
+	if err != nil {
+		return err
+	}
+	data, status, err := c.do("POST", fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", name, name), body)
+	if err != nil {
+		return err
+	} else if status != http.StatusOK {
+		return fmt.Errorf("received %d status code restoring snapshot %s: %s", status, name, data)
+	}
+	return checkRestoreShards(data)
+}
+
+// checkRestoreShards returns an error if any shard of a restore failed.
+func checkRestoreShards(data []byte) error {
+	var result struct {
+		Snapshot struct {
+			Snapshot string `json:"snapshot"`
+			Shards   struct {
+				Total      int `json:"total"`
+				Failed     int `json:"failed"`
+				Successful int `json:"successful"`
+			} `json:"shards"`
+		} `json:"snapshot"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	shards := result.Snapshot.Shards
+	if shards.Failed > 0 || shards.Successful < shards.Total {
+		return fmt.Errorf("restore of snapshot %s restored %d of %d shards", result.Snapshot.Snapshot, shards.Successful, shards.Total)
+	}
+	return nil
+}
+
+// openIndices reopens indices that were closed for a restore that failed,
+// so that the cluster keeps serving the data it had.
+func (c *SnapshotClient) openIndices(indices []string) {
+	if len(indices) == 0 {
+		return
+	}
+	path := fmt.Sprintf("/%s/_open?ignore_unavailable=true", strings.Join(indices, ","))
+	if data, status, err := c.do("POST", path, nil); err != nil {
+		log.WithError(err).Warn("Could not reopen indices after a failed restore")
+	} else if status != http.StatusOK {
+		log.WithField("status", status).Warnf("Could not reopen indices after a failed restore: %s", data)
+	}
+}
+
+// waitForIndices waits until the primary shards of the restored indices are
+// allocated.
+func (c *SnapshotClient) waitForIndices(indices []string) error {
+	path := "/_cluster/health"
+	if len(indices) > 0 {
+		path += "/" + strings.Join(indices, ",")
+	}
+	data, status, err := c.do("GET", path+"?wait_for_status=yellow&timeout="+restoreHealthTimeout, nil)
+	if err != nil {
+		return err
+	} else if status != http.StatusOK && status != http.StatusRequestTimeout {
+		return fmt.Errorf("received %d status code checking the health of restored indices: %s", status, data)
+	}
+	var result struct {
+		Status   string `json:"status"`
+		TimedOut bool   `json:"timed_out"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if result.TimedOut {
+		return fmt.Errorf("restored indices did not become available, cluster status is %s", result.Status)
+	}
+	return nil
+}
+
+// DeleteSnapshot deletes a snapshot, its repository and the directory of the
+// repository.
+func (c *SnapshotClient) DeleteSnapshot(name string) error {
+	for _, path := range []string{
+		fmt.Sprintf("/_snapshot/%s/%s", name, name),
+		fmt.Sprintf("/_snapshot/%s", name),
+	} {
+		data, status, err := c.do("DELETE", path, nil)
+		if err != nil {
+			return err
+		} else if status != http.StatusOK && status != http.StatusNotFound {
+			return fmt.Errorf("received %d status code deleting %s: %s", status, path, data)
+		}
+	}
+	return os.RemoveAll(c.SnapshotPath(name))
+}
+
+// createRepository registers the filesystem repository of a snapshot
+func (c *SnapshotClient) createRepository(name string) error {
+	if err := os.MkdirAll(c.SnapshotPath(name), 0777); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"type": "fs",
+		"settings": map[string]interface{}{
+			"location": c.containerPath + "/" + name,
+			"compress": true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	data, status, err := c.do("PUT", fmt.Sprintf("/_snapshot/%s", name), body)
+	if err != nil {
+		return err
+	} else if status != http.StatusOK {
+		return fmt.Errorf("received %d status code creating snapshot repository %s: %s", status, name, data)
+	}
+	return nil
+}
+
+func (c *SnapshotClient) do(method, path string, body []byte) ([]byte, int, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("http://%s%s", c.address, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, resp.StatusCode, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIndexSizes(t *testing.T) {
+	data := []byte(`{"indices":{"logstash-2017.01.01":{"primaries":{"store":{"size_in_bytes":100}}},"logstash-2017.01.02":{"primaries":{"store":{"size_in_bytes":200}}}}}`)
+	sizes, err := parseIndexSizes(data)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"logstash-2017.01.01": 100, "logstash-2017.01.02": 200}, sizes)
+}
+
+func TestBuildSnapshotRequest(t *testing.T) {
+	body, err := buildSnapshotRequest([]string{"logstash-2017.01.01", "logstash-2017.01.02"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ignore_unavailable":true,"include_global_state":false,"indices":"logstash-2017.01.01,logstash-2017.01.02"}`, string(body))
+
+	body, err = buildSnapshotRequest(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ignore_unavailable":true,"include_global_state":false}`, string(body))
+}
+
+func TestCheckSnapshotState(t *testing.T) {
+	assert.NoError(t, checkSnapshotState([]byte(`{"snapshot":{"snapshot":"backup","state":"SUCCESS","failures":[]}}`)))
+	assert.Error(t, checkSnapshotState([]byte(`{"snapshot":{"snapshot":"backup","state":"PARTIAL","failures":[{"index":"controlplane"}]}}`)))
+}
+
+func TestSnapshotClient_CreateAndDelete(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "essnapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/_snapshot/backup":
+			assert.Contains(t, string(body), `"location":"/opt/elasticsearch-serviced/snapshots/backup"`)
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.Method == "PUT" && r.URL.Path == "/_snapshot/backup/backup":
+			assert.Equal(t, "true", r.URL.Query().Get("wait_for_completion"))
+			assert.Contains(t, string(body), `"indices":"controlplane"`)
+			w.Write([]byte(`{"snapshot":{"snapshot":"backup","state":"SUCCESS"}}`))
+		case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/_snapshot/backup"):
+			w.Write([]byte(`{"acknowledged":true}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewServicedSnapshotClient(strings.TrimPrefix(server.URL, "http://"), tmpdir)
+	dirpath := filepath.Join(tmpdir, "elasticsearch-serviced", ESSnapshotsVolume, "backup")
+	assert.Equal(t, dirpath, client.SnapshotPath("backup"))
+
+	assert.NoError(t, client.CreateSnapshot("backup", []string{"controlplane"}))
+	_, err = os.Stat(dirpath)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.DeleteSnapshot("backup"))
+	_, err = os.Stat(dirpath)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, []string{
+		"PUT /_snapshot/backup",
+		"PUT /_snapshot/backup/backup",
+		"DELETE /_snapshot/backup/backup",
+		"DELETE /_snapshot/backup",
+	}, requests)
+}
+
+func TestSnapshotClient_IndexSizesNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewLogstashSnapshotClient(strings.TrimPrefix(server.URL, "http://"), "/tmp")
+	sizes, err := client.IndexSizes("logstash-*")
+	assert.NoError(t, err)
+	assert.Empty(t, sizes)
+}
+
+func TestCheckRestoreShards(t *testing.T) {
+	assert.NoError(t, checkRestoreShards([]byte(`{"snapshot":{"snapshot":"backup","shards":{"total":5,"failed":0,"successful":5}}}`)))
+	assert.Error(t, checkRestoreShards([]byte(`{"snapshot":{"snapshot":"backup","shards":{"total":5,"failed":1,"successful":4}}}`)))
+}
+
+func TestSnapshotClient_Restore(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "essnapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	failed := 0
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/_snapshot/backup":
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.Method == "POST" && (r.URL.Path == "/controlplane/_close" || r.URL.Path == "/controlplane/_open"):
+			w.Write([]byte(`{"acknowledged":true}`))
+		case r.Method == "POST" && r.URL.Path == "/_snapshot/backup/backup/_restore":
+			w.Write([]byte(fmt.Sprintf(`{"snapshot":{"snapshot":"backup","shards":{"total":5,"failed":%d,"successful":%d}}}`, failed, 5-failed)))
+		case r.Method == "GET" && r.URL.Path == "/_cluster/health/controlplane":
+			assert.Equal(t, "yellow", r.URL.Query().Get("wait_for_status"))
+			w.Write([]byte(`{"status":"green","timed_out":false}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewServicedSnapshotClient(strings.TrimPrefix(server.URL, "http://"), tmpdir)
+	assert.NoError(t, client.RestoreSnapshot("backup", []string{"controlplane"}))
+	assert.Equal(t, []string{
+		"PUT /_snapshot/backup",
+		"POST /controlplane/_close",
+		"POST /_snapshot/backup/backup/_restore",
+		"GET /_cluster/health/controlplane",
+	}, requests)
+
+	// a partial restore reopens the indices it closed
+	failed = 1
+	requests = []string{}
+	assert.Error(t, client.RestoreSnapshot("backup", []string{"controlplane"}))
+	assert.Equal(t, []string{
+		"PUT /_snapshot/backup",
+		"POST /controlplane/_close",
+		"POST /_snapshot/backup/backup/_restore",
+		"POST /controlplane/_open",
+	}, requests)
+}
@@ -0,0 +1,61 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+// HEALTH_HISTORY_SIZE is the number of health transitions kept for each isvc.
+const HEALTH_HISTORY_SIZE = 50
+
+// HealthTransition records a change in the status of an isvc health check.
+type HealthTransition struct {
+	Timestamp   int64  // unix time of the change
+	HealthCheck string // name of the health check
+	Instance    int    // instance index of the health check
+	From        string // status before the change
+	To          string // status after the change
+	Failure     string // failure reported with the new status, if any
+}
+
+// healthHistory is a fixed size ring buffer of health transitions.  Once it
+// is full, each new transition replaces the oldest one.
+type healthHistory struct {
+	entries []HealthTransition
+	next    int
+	full    bool
+}
+
+func newHealthHistory(size int) *healthHistory {
+	return &healthHistory{entries: make([]HealthTransition, size)}
+}
+
+// add appends a transition, dropping the oldest one if the buffer is full.
+func (h *healthHistory) add(transition HealthTransition) {
+	if len(h.entries) == 0 {
+		return
+	}
+	h.entries[h.next] = transition
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the transitions in the buffer, oldest first.
+func (h *healthHistory) list() []HealthTransition {
+	if !h.full {
+		return append([]HealthTransition{}, h.entries[:h.next]...)
+	}
+	result := make([]HealthTransition, 0, len(h.entries))
+	result = append(result, h.entries[h.next:]...)
+	return append(result, h.entries[:h.next]...)
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package isvcs
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/control-center/serviced/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHistory_Wraps(t *testing.T) {
+	h := newHealthHistory(3)
+	assert.Empty(t, h.list())
+
+	for i := int64(1); i <= 5; i++ {
+		h.add(HealthTransition{Timestamp: i})
+	}
+	list := h.list()
+	assert.Len(t, list, 3)
+	assert.Equal(t, int64(3), list[0].Timestamp)
+	assert.Equal(t, int64(4), list[1].Timestamp)
+	assert.Equal(t, int64(5), list[2].Timestamp)
+}
+
+func TestSetHealthStatus_RecordsTransitions(t *testing.T) {
+	svc := &IService{
+		IServiceDefinition: IServiceDefinition{Name: "test-isvc"},
+		lock:               &sync.RWMutex{},
+		healthStatuses: []map[string]*domain.HealthCheckStatus{
+			{"running": &domain.HealthCheckStatus{Name: "running", Status: "unknown"}},
+		},
+		healthHistory: newHealthHistory(HEALTH_HISTORY_SIZE),
+	}
+
+	svc.setHealthStatus(nil, 10, "running", 0)
+	svc.setHealthStatus(nil, 20, "running", 0)
+	svc.setHealthStatus(errors.New("no answer"), 30, "running", 0)
+	svc.setStoppedHealthStatus(ExitError(1))
+
+	list := svc.healthHistory.list()
+	assert.Len(t, list, 3)
+	assert.Equal(t, HealthTransition{Timestamp: 10, HealthCheck: "running", From: "unknown", To: "passed"}, list[0])
+	assert.Equal(t, HealthTransition{Timestamp: 30, HealthCheck: "running", From: "passed", To: "failed", Failure: "no answer"}, list[1])
+	assert.Equal(t, "failed", list[2].From)
+	assert.Equal(t, "stopped", list[2].To)
+	assert.Equal(t, ExitError(1).Error(), list[2].Failure)
+}
@@ -49,6 +49,9 @@ type IServiceHealthResult struct {
 	ContainerName  string
 	ContainerID    string
 	HealthStatuses []domain.HealthCheckStatus
+	History        []HealthTransition // recent health transitions, oldest first
+	Restarts       int                // unexpected restarts within the last hour
+	RestartPolicy  RestartPolicy
 }
 
 //
@@ -213,5 +216,10 @@ func setIsvcsEnv() error {
 			return err
 		}
 	}
+	for _, val := range options.IsvcsRestartPolicies {
+		if err := SetRestartPolicy(val); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,211 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+)
+
+// DefaultLogSearchLimit is the number of log messages returned by a search
+// when the query does not set a limit.
+const DefaultLogSearchLimit = 1000
+
+// ErrInvalidLogQuery is returned when a log query does not name a service
+var ErrInvalidLogQuery = errors.New("log query requires a service id")
+
+// LogSearchClient searches the application logs that logstash stored in
+// elasticsearch.
+type LogSearchClient struct {
+	address string
+	client  *http.Client
+}
+
+// NewLogSearchClient returns a client for the logstash elasticsearch
+// instance at the given host:port.
+func NewLogSearchClient(address string) *LogSearchClient {
+	return &LogSearchClient{
+		address: address,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SearchServiceLogs returns the application log messages of a service that
+// match the query, oldest first.
+func (c *LogSearchClient) SearchServiceLogs(query service.LogQuery) ([]service.LogMessage, error) {
+	body, err := buildLogSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("http://%s/logstash-*/_search", c.address)
+	resp, err := c.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// no logstash indexes exist yet
+		return []service.LogMessage{}, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received %d status code searching logstash: %s", resp.StatusCode, data)
+	}
+	return parseLogSearch(data)
+}
+
+// buildLogSearch returns the elasticsearch request body for a log query
+func buildLogSearch(query service.LogQuery) ([]byte, error) {
+	if query.ServiceID == "" {
+		return nil, ErrInvalidLogQuery
+	}
+
+	// Only messages forwarded by filebeat have the type "log"; skip the
+	// messages that serviced and serviced-controller log directly.
+	terms := []string{
+		fmt.Sprintf("fields.service:%s", strconv.Quote(query.ServiceID)),
+		"type:log",
+	}
+	if query.InstanceID >= 0 {
+		terms = append(terms, fmt.Sprintf("fields.instance:\"%d\"", query.InstanceID))
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultLogSearchLimit
+	}
+
+	search := map[string]interface{}{
+		"size": limit,
+		"sort": []interface{}{
+			map[string]interface{}{"@timestamp": map[string]string{"order": "asc"}},
+		},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"query_string": map[string]string{"query": strings.Join(terms, " AND ")},
+				},
+				"filter": map[string]interface{}{
+					"range": map[string]interface{}{
+						"@timestamp": map[string]string{"gt": query.Since.UTC().Format(time.RFC3339Nano)},
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(search)
+}
+
+// logSearchResult is the part of an elasticsearch search response that
+// describes the matching log messages.
+type logSearchResult struct {
+	Hits struct {
+		Hits []struct {
+			Source struct {
+				Timestamp time.Time   `json:"@timestamp"`
+				File      string      `json:"file"`
+				Offset    interface{} `json:"offset"`
+				Message   interface{} `json:"message"`
+				Beat      struct {
+					Hostname string `json:"hostname"` // the container id
+				} `json:"beat"`
+				Fields struct {
+					Service    string      `json:"service"`
+					Instance   interface{} `json:"instance"`
+					CCWorkerID interface{} `json:"ccWorkerID"`
+				} `json:"fields"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// parseLogSearch converts an elasticsearch search response into log
+// messages.  Multi-line messages are joined with newlines.
+func parseLogSearch(data []byte) ([]service.LogMessage, error) {
+	var result logSearchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	messages := make([]service.LogMessage, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		src := hit.Source
+		messages[i] = service.LogMessage{
+			Timestamp:   src.Timestamp,
+			ServiceID:   src.Fields.Service,
+			InstanceID:  logSearchString(src.Fields.Instance),
+			HostID:      logSearchString(src.Fields.CCWorkerID),
+			ContainerID: src.Beat.Hostname,
+			File:        src.File,
+			Offset:      logSearchOffset(src.Offset),
+			Message:     logSearchMessage(src.Message),
+		}
+	}
+	return messages, nil
+}
+
+func logSearchString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func logSearchOffset(value interface{}) uint64 {
+	switch v := value.(type) {
+	case float64:
+		return uint64(v)
+	case string:
+		offset, _ := strconv.ParseUint(v, 10, 64)
+		return offset
+	case []interface{}:
+		if len(v) > 0 {
+			return logSearchOffset(v[0])
+		}
+	}
+	return 0
+}
+
+func logSearchMessage(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		lines := make([]string, len(v))
+		for i, line := range v {
+			lines[i] = logSearchString(line)
+		}
+		return strings.Join(lines, "\n")
+	}
+	return logSearchString(value)
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLogSearch(t *testing.T) {
+	since := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	body, err := buildLogSearch(service.LogQuery{ServiceID: "svc-1", InstanceID: 2, Since: since})
+	assert.NoError(t, err)
+
+	var search struct {
+		Size  int `json:"size"`
+		Query struct {
+			Bool struct {
+				Must struct {
+					QueryString struct {
+						Query string `json:"query"`
+					} `json:"query_string"`
+				} `json:"must"`
+				Filter struct {
+					Range struct {
+						Timestamp struct {
+							Gt string `json:"gt"`
+						} `json:"@timestamp"`
+					} `json:"range"`
+				} `json:"filter"`
+			} `json:"bool"`
+		} `json:"query"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &search))
+	assert.Equal(t, DefaultLogSearchLimit, search.Size)
+	assert.Equal(t, `fields.service:"svc-1" AND type:log AND fields.instance:"2"`, search.Query.Bool.Must.QueryString.Query)
+	assert.Equal(t, "2017-01-02T03:04:05Z", search.Query.Bool.Filter.Range.Timestamp.Gt)
+
+	_, err = buildLogSearch(service.LogQuery{InstanceID: -1})
+	assert.Equal(t, ErrInvalidLogQuery, err)
+}
+
+func TestParseLogSearch(t *testing.T) {
+	data := []byte(`{"hits":{"hits":[
+		{"_source":{"@timestamp":"2017-01-02T03:04:05Z","file":"/a.log","offset":42,"message":"single",
+			"beat":{"hostname":"c1"},"fields":{"service":"svc-1","instance":"0","ccWorkerID":"h1"}}},
+		{"_source":{"@timestamp":"2017-01-02T03:04:06Z","file":"/b.log","offset":[7,8],"message":["one","two"],
+			"beat":{"hostname":"c2"},"fields":{"service":"svc-1","instance":1,"ccWorkerID":"h2"}}}
+	]}}`)
+
+	messages, err := parseLogSearch(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []service.LogMessage{
+		{
+			Timestamp:   time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC),
+			ServiceID:   "svc-1",
+			InstanceID:  "0",
+			HostID:      "h1",
+			ContainerID: "c1",
+			File:        "/a.log",
+			Offset:      42,
+			Message:     "single",
+		}, {
+			Timestamp:   time.Date(2017, 1, 2, 3, 4, 6, 0, time.UTC),
+			ServiceID:   "svc-1",
+			InstanceID:  "1",
+			HostID:      "h2",
+			ContainerID: "c2",
+			File:        "/b.log",
+			Offset:      7,
+			Message:     "one\ntwo",
+		},
+	}, messages)
+}
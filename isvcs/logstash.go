@@ -17,6 +17,7 @@ import (
 	"path/filepath"
 
 	"github.com/control-center/serviced/utils"
+	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
 var logstash *IService
@@ -62,3 +63,23 @@ func initLogstash() {
 		log.WithError(err).Fatal("Unable to initialize Logstash internal service container")
 	}
 }
+
+// LogstashReloader applies a rewritten logstash.conf to the running logstash
+// internal service.
+type LogstashReloader struct{}
+
+// NewLogstashReloader returns a reloader for the logstash internal service.
+func NewLogstashReloader() *LogstashReloader {
+	return &LogstashReloader{}
+}
+
+// ReloadLogstash sends SIGHUP to logstash so that it reloads its pipeline in
+// place without dropping the inputs that filebeat is shipping to.
+func (r *LogstashReloader) ReloadLogstash() error {
+	return logstash.Signal(dockerclient.SIGHUP)
+}
+
+// RestartLogstash restarts the logstash container.
+func (r *LogstashReloader) RestartLogstash() error {
+	return logstash.Restart()
+}
@@ -25,6 +25,7 @@ import (
 	"path"
 	"sort"
 	"sync"
+	"time"
 )
 
 // managerOp is a type of manager operation (stop, start, notify)
@@ -178,6 +179,16 @@ func (m *Manager) GetHealthStatus(name string, instIndex int) (IServiceHealthRes
 		return IServiceHealthResult{}, fmt.Errorf("Instance index out of range %d", instIndex)
 	}
 
+	if svc.healthHistory != nil {
+		for _, transition := range svc.healthHistory.list() {
+			if instIndex == HEALTH_STATUS_INDEX_ALL || transition.Instance == instIndex {
+				result.History = append(result.History, transition)
+			}
+		}
+	}
+	result.Restarts = len(recentRestarts(svc.restarts, time.Now()))
+	result.RestartPolicy = svc.restartPolicy()
+
 	return result, nil
 }
 
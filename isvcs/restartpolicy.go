@@ -0,0 +1,167 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MINIMUM_VIABLE_LIFESPAN is how long an isvc must run before an unexpected
+// exit no longer counts towards its restart backoff.
+const MINIMUM_VIABLE_LIFESPAN = 60 * time.Second
+
+// RestartPolicy describes how an isvc is restarted when it exits unexpectedly.
+type RestartPolicy struct {
+	MaxRestartsPerHour int           // restarts allowed in any hour before the isvc is left stopped; 0 is unlimited
+	Backoff            time.Duration // delay before restarting an isvc that exited within MINIMUM_VIABLE_LIFESPAN
+	MaxBackoff         time.Duration // upper limit of the delay, which doubles with each consecutive short run
+}
+
+// DefaultRestartPolicy applies to every isvc without a policy of its own.
+var DefaultRestartPolicy = RestartPolicy{
+	MaxRestartsPerHour: 10,
+	Backoff:            5 * time.Second,
+	MaxBackoff:         5 * time.Minute,
+}
+
+var (
+	restartPolicyLock       sync.RWMutex
+	restartPolicyPerService = make(map[string]RestartPolicy)
+)
+
+// delay returns how long to wait before restarting an isvc after the given
+// number of consecutive short runs.
+func (p RestartPolicy) delay(shortRuns int) time.Duration {
+	if shortRuns <= 0 {
+		return 0
+	}
+	d := p.Backoff
+	for i := 1; i < shortRuns && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// SetRestartPolicy sets the restart policy of an isvc from a string of the
+// form SERVICE:max-restarts-per-hour=N,backoff=DURATION,max-backoff=DURATION.
+// Settings that are left out keep the values of DefaultRestartPolicy.
+func SetRestartPolicy(policyStr string) error {
+	parts := strings.SplitN(policyStr, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("Error parsing internal service restart policy: %s", policyStr)
+	}
+	service := parts[0]
+
+	if _, ok := envPerService[service]; !ok {
+		svcNames := []string{}
+		for key := range envPerService {
+			svcNames = append(svcNames, key)
+		}
+		sort.Strings(svcNames)
+		return fmt.Errorf("Error setting internal service restart policy:'%s'  Service '%s' must be one of [%s]'",
+			policyStr, service, strings.Join(svcNames, ", "))
+	}
+
+	policy := DefaultRestartPolicy
+	for _, setting := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(strings.TrimSpace(setting), "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("Error parsing internal service restart policy: %s", policyStr)
+		}
+		var err error
+		switch kv[0] {
+		case "max-restarts-per-hour":
+			policy.MaxRestartsPerHour, err = strconv.Atoi(kv[1])
+			if err == nil && policy.MaxRestartsPerHour < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "backoff":
+			policy.Backoff, err = time.ParseDuration(kv[1])
+			if err == nil && policy.Backoff < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "max-backoff":
+			policy.MaxBackoff, err = time.ParseDuration(kv[1])
+			if err == nil && policy.MaxBackoff < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return fmt.Errorf("Error parsing internal service restart policy: %s: %s: %s", policyStr, kv[0], err)
+		}
+	}
+	if policy.MaxBackoff < policy.Backoff {
+		policy.MaxBackoff = policy.Backoff
+	}
+
+	restartPolicyLock.Lock()
+	defer restartPolicyLock.Unlock()
+	restartPolicyPerService[service] = policy
+	return nil
+}
+
+// restartPolicy returns the restart policy that applies to the isvc.
+func (svc *IService) restartPolicy() RestartPolicy {
+	restartPolicyLock.RLock()
+	defer restartPolicyLock.RUnlock()
+
+	if policy, ok := restartPolicyPerService[svc.Name]; ok {
+		return policy
+	}
+	return DefaultRestartPolicy
+}
+
+// nextRestart records an unexpected exit of the isvc at the given time and
+// returns how long to wait before restarting it.  It returns false if the
+// restart policy does not allow the isvc to be restarted again.
+func (svc *IService) nextRestart(now time.Time) (time.Duration, bool) {
+	policy := svc.restartPolicy()
+
+	svc.lock.Lock()
+	defer svc.lock.Unlock()
+
+	svc.restarts = recentRestarts(svc.restarts, now)
+	if policy.MaxRestartsPerHour > 0 && len(svc.restarts) >= policy.MaxRestartsPerHour {
+		return 0, false
+	}
+	svc.restarts = append(svc.restarts, now)
+
+	if now.Sub(svc.startTime) < MINIMUM_VIABLE_LIFESPAN {
+		svc.restartCount += 1
+	} else {
+		svc.restartCount = 0
+	}
+	return policy.delay(svc.restartCount), true
+}
+
+// recentRestarts drops the restarts that happened more than an hour before
+// the given time.
+func recentRestarts(restarts []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(restarts) && !restarts[i].After(cutoff) {
+		i++
+	}
+	return restarts[i:]
+}
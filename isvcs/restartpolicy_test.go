@@ -0,0 +1,127 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package isvcs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// addTestRestartPolicyService registers an isvc name that restart policies
+// can be set for, and returns a function that removes it again.
+func addTestRestartPolicyService(name string) func() {
+	envPerService[name] = make(map[string]string)
+	return func() {
+		delete(envPerService, name)
+		restartPolicyLock.Lock()
+		delete(restartPolicyPerService, name)
+		restartPolicyLock.Unlock()
+	}
+}
+
+func TestSetRestartPolicy(t *testing.T) {
+	defer addTestRestartPolicyService("test-isvc")()
+
+	err := SetRestartPolicy("test-isvc:max-restarts-per-hour=3,backoff=10s,max-backoff=1m")
+	assert.NoError(t, err)
+	svc := &IService{IServiceDefinition: IServiceDefinition{Name: "test-isvc"}}
+	assert.Equal(t, RestartPolicy{MaxRestartsPerHour: 3, Backoff: 10 * time.Second, MaxBackoff: time.Minute}, svc.restartPolicy())
+
+	// settings left out keep their defaults
+	err = SetRestartPolicy("test-isvc:max-restarts-per-hour=0")
+	assert.NoError(t, err)
+	expected := DefaultRestartPolicy
+	expected.MaxRestartsPerHour = 0
+	assert.Equal(t, expected, svc.restartPolicy())
+
+	other := &IService{IServiceDefinition: IServiceDefinition{Name: "other-isvc"}}
+	assert.Equal(t, DefaultRestartPolicy, other.restartPolicy())
+}
+
+func TestSetRestartPolicy_Invalid(t *testing.T) {
+	defer addTestRestartPolicyService("test-isvc")()
+
+	for _, policy := range []string{
+		"test-isvc",
+		"test-isvc:",
+		"unknown-isvc:backoff=1s",
+		"test-isvc:backoff",
+		"test-isvc:backoff=soon",
+		"test-isvc:backoff=-1s",
+		"test-isvc:max-restarts-per-hour=-1",
+		"test-isvc:retries=3",
+	} {
+		assert.Error(t, SetRestartPolicy(policy), policy)
+	}
+}
+
+func TestRestartPolicy_Delay(t *testing.T) {
+	policy := RestartPolicy{Backoff: 5 * time.Second, MaxBackoff: 30 * time.Second}
+	assert.Equal(t, time.Duration(0), policy.delay(0))
+	assert.Equal(t, 5*time.Second, policy.delay(1))
+	assert.Equal(t, 10*time.Second, policy.delay(2))
+	assert.Equal(t, 20*time.Second, policy.delay(3))
+	assert.Equal(t, 30*time.Second, policy.delay(4))
+	assert.Equal(t, 30*time.Second, policy.delay(100))
+}
+
+func TestNextRestart(t *testing.T) {
+	defer addTestRestartPolicyService("test-isvc")()
+	assert.NoError(t, SetRestartPolicy("test-isvc:max-restarts-per-hour=3,backoff=5s,max-backoff=1m"))
+
+	now := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	svc := &IService{
+		IServiceDefinition: IServiceDefinition{Name: "test-isvc"},
+		lock:               &sync.RWMutex{},
+	}
+
+	// a long run restarts right away
+	svc.startTime = now.Add(-time.Hour)
+	delay, ok := svc.nextRestart(now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	// consecutive short runs back off
+	svc.startTime = now
+	now = now.Add(10 * time.Second)
+	delay, ok = svc.nextRestart(now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, delay)
+
+	svc.startTime = now
+	now = now.Add(10 * time.Second)
+	delay, ok = svc.nextRestart(now)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	// the hourly limit is reached
+	svc.startTime = now
+	now = now.Add(10 * time.Second)
+	_, ok = svc.nextRestart(now)
+	assert.False(t, ok)
+
+	// restarts older than an hour no longer count
+	now = now.Add(time.Hour)
+	svc.startTime = now.Add(-time.Hour)
+	delay, ok = svc.nextRestart(now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+	assert.Len(t, svc.restarts, 1)
+}
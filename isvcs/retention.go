@@ -0,0 +1,217 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isvcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RetentionClient deletes the application logs that logstash stored in
+// elasticsearch and the metrics stored in opentsdb before the retention
+// period of the resource pool that produced them has expired.  The cluster
+// wide purge still drops whole logstash indices, so a pool can only shorten
+// its retention.
+type RetentionClient struct {
+	logstashAddress string
+	opentsdbAddress string
+	client          *http.Client
+}
+
+// NewRetentionClient returns a client for the logstash elasticsearch and the
+// opentsdb instances at the given host:port addresses.
+func NewRetentionClient(logstashAddress, opentsdbAddress string) *RetentionClient {
+	return &RetentionClient{
+		logstashAddress: logstashAddress,
+		opentsdbAddress: opentsdbAddress,
+		client:          &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// PurgePoolLogs deletes the application log messages of the services in a
+// resource pool that were logged before the given time.
+func (c *RetentionClient) PurgePoolLogs(poolID string, before time.Time) error {
+	body, err := buildPoolLogPurge(poolID, before)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("http://%s/logstash-*/_delete_by_query?conflicts=proceed", c.logstashAddress)
+	data, status, err := c.post(url, body)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		// no logstash indexes exist yet
+		return nil
+	} else if status != http.StatusOK {
+		return fmt.Errorf("received %d status code purging logstash: %s", status, data)
+	}
+	return nil
+}
+
+// buildPoolLogPurge returns the elasticsearch delete by query request body
+// for the log messages of a resource pool.
+func buildPoolLogPurge(poolID string, before time.Time) ([]byte, error) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{
+						"term": map[string]string{"fields.poolid": poolID},
+					},
+					map[string]interface{}{
+						"range": map[string]interface{}{
+							"@timestamp": map[string]string{"lt": before.UTC().Format(time.RFC3339Nano)},
+						},
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(query)
+}
+
+// PurgeServiceMetrics deletes the data points of the given services that
+// were recorded before the given time.  Opentsdb must be configured with
+// tsd.http.query.allow_delete.
+func (c *RetentionClient) PurgeServiceMetrics(serviceIDs []string, before time.Time) error {
+	if len(serviceIDs) == 0 {
+		return nil
+	}
+
+	// look up the metrics that the services report
+	metricSet := make(map[string]struct{})
+	for _, serviceID := range serviceIDs {
+		body, err := json.Marshal(map[string]interface{}{
+			"metric": "*",
+			"tags": []map[string]string{
+				{"key": "controlplane_service_id", "value": serviceID},
+			},
+			"limit": 100000,
+		})
+		if err != nil {
+			return err
+		}
+
+		url := fmt.Sprintf("http://%s/api/search/lookup", c.opentsdbAddress)
+		data, status, err := c.post(url, body)
+		if err != nil {
+			return err
+		} else if status == http.StatusNotFound {
+			// the service has not reported any metrics
+			continue
+		} else if status != http.StatusOK {
+			return fmt.Errorf("received %d status code looking up metrics: %s", status, data)
+		}
+
+		metrics, err := parseMetricLookup(data)
+		if err != nil {
+			return err
+		}
+		for _, metric := range metrics {
+			metricSet[metric] = struct{}{}
+		}
+	}
+
+	metrics := make([]string, 0, len(metricSet))
+	for metric := range metricSet {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	for _, metric := range metrics {
+		body, err := buildMetricPurge(metric, serviceIDs, before)
+		if err != nil {
+			return err
+		}
+
+		url := fmt.Sprintf("http://%s/api/query", c.opentsdbAddress)
+		data, status, err := c.post(url, body)
+		if err != nil {
+			return err
+		} else if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusNotFound {
+			return fmt.Errorf("received %d status code purging metric %s: %s", status, metric, data)
+		}
+	}
+	return nil
+}
+
+// buildMetricPurge returns the opentsdb query request body that deletes the
+// data points of a metric reported by any of the given services.
+func buildMetricPurge(metric string, serviceIDs []string, before time.Time) ([]byte, error) {
+	query := map[string]interface{}{
+		"start":  1,
+		"end":    before.Unix(),
+		"delete": true,
+		"queries": []interface{}{
+			map[string]interface{}{
+				"aggregator": "none",
+				"metric":     metric,
+				"filters": []interface{}{
+					map[string]interface{}{
+						"type":    "literal_or",
+						"tagk":    "controlplane_service_id",
+						"filter":  strings.Join(serviceIDs, "|"),
+						"groupBy": true,
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(query)
+}
+
+// parseMetricLookup returns the distinct metric names of an opentsdb lookup
+// response.
+func parseMetricLookup(data []byte) ([]string, error) {
+	var result struct {
+		Results []struct {
+			Metric string `json:"metric"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	metrics := []string{}
+	for _, r := range result.Results {
+		if _, ok := seen[r.Metric]; !ok && r.Metric != "" {
+			seen[r.Metric] = struct{}{}
+			metrics = append(metrics, r.Metric)
+		}
+	}
+	return metrics, nil
+}
+
+func (c *RetentionClient) post(url string, body []byte) ([]byte, int, error) {
+	resp, err := c.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, resp.StatusCode, nil
+}
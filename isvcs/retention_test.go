@@ -0,0 +1,110 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package isvcs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPoolLogPurge(t *testing.T) {
+	before := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	body, err := buildPoolLogPurge("dev", before)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"query":{"bool":{"filter":[{"term":{"fields.poolid":"dev"}},{"range":{"@timestamp":{"lt":"2017-01-02T03:04:05Z"}}}]}}}`, string(body))
+}
+
+func TestBuildMetricPurge(t *testing.T) {
+	before := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	body, err := buildMetricPurge("cpu", []string{"svc-1", "svc-2"}, before)
+	assert.NoError(t, err)
+
+	var query struct {
+		Start   int64 `json:"start"`
+		End     int64 `json:"end"`
+		Delete  bool  `json:"delete"`
+		Queries []struct {
+			Metric  string `json:"metric"`
+			Filters []struct {
+				Tagk   string `json:"tagk"`
+				Filter string `json:"filter"`
+			} `json:"filters"`
+		} `json:"queries"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &query))
+	assert.Equal(t, before.Unix(), query.End)
+	assert.True(t, query.Delete)
+	assert.Len(t, query.Queries, 1)
+	assert.Equal(t, "cpu", query.Queries[0].Metric)
+	assert.Equal(t, "controlplane_service_id", query.Queries[0].Filters[0].Tagk)
+	assert.Equal(t, "svc-1|svc-2", query.Queries[0].Filters[0].Filter)
+}
+
+func TestParseMetricLookup(t *testing.T) {
+	data := []byte(`{"results":[{"metric":"cpu"},{"metric":"mem"},{"metric":"cpu"}]}`)
+	metrics, err := parseMetricLookup(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cpu", "mem"}, metrics)
+}
+
+func TestRetentionClient_PurgeServiceMetrics(t *testing.T) {
+	purged := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/api/search/lookup":
+			if strings.Contains(string(body), `"svc-1"`) {
+				w.Write([]byte(`{"results":[{"metric":"cpu"},{"metric":"mem"}]}`))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "/api/query":
+			var query struct {
+				Queries []struct {
+					Metric string `json:"metric"`
+				} `json:"queries"`
+			}
+			json.Unmarshal(body, &query)
+			purged = append(purged, query.Queries[0].Metric)
+			w.Write([]byte(`[]`))
+		case "/logstash-*/_delete_by_query":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+	client := NewRetentionClient(address, address)
+	assert.NoError(t, client.PurgeServiceMetrics([]string{"svc-1", "svc-2"}, time.Now()))
+	assert.Equal(t, []string{"cpu", "mem"}, purged)
+
+	// the lookup is skipped without services
+	purged = []string{}
+	assert.NoError(t, client.PurgeServiceMetrics(nil, time.Now()))
+	assert.Empty(t, purged)
+
+	// logstash has no indexes yet
+	assert.NoError(t, client.PurgePoolLogs("dev", time.Now()))
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logship
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaContentType = "application/vnd.kafka.json.v2+json"
+	kafkaTimeout     = 30 * time.Second
+)
+
+// kafkaRecord is the value of a record produced to the topic
+type kafkaRecord struct {
+	Timestamp string            `json:"@timestamp"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// KafkaWriter produces entries as json records to a Kafka topic through a
+// Kafka REST proxy, so that the delegate does not need a native client or
+// access to the brokers.
+type KafkaWriter struct {
+	url    string
+	topic  string
+	client *http.Client
+}
+
+// NewKafkaWriter returns a writer for the topic of the REST proxy at the url,
+// such as http://kafka-rest.example.com:8082.
+func NewKafkaWriter(url, topic string) (*KafkaWriter, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("kafka url %s must have the scheme http or https", url)
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+	return &KafkaWriter{
+		url:    strings.TrimRight(url, "/"),
+		topic:  topic,
+		client: &http.Client{Timeout: kafkaTimeout},
+	}, nil
+}
+
+// Write produces the entries to the topic in one request
+func (w *KafkaWriter) Write(entries []Entry) error {
+	type record struct {
+		Value kafkaRecord `json:"value"`
+	}
+	payload := struct {
+		Records []record `json:"records"`
+	}{Records: make([]record, len(entries))}
+	for i, e := range entries {
+		payload.Records[i].Value = kafkaRecord{
+			Timestamp: e.Time.UTC().Format(time.RFC3339Nano),
+			Message:   e.Message,
+			Fields:    e.Fields,
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(fmt.Sprintf("%s/topics/%s", w.url, w.topic), kafkaContentType, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kafka: received response status %q producing to topic %s", resp.Status, w.topic)
+	}
+	return nil
+}
+
+// Close implements Writer; the proxy needs no connection to be closed
+func (w *KafkaWriter) Close() error {
+	return nil
+}
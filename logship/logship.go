@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logship ships the application logs of the instances on a delegate
+// to the central logging of a site, such as a syslog server or a Kafka
+// topic, instead of the logstash isvc of the master.
+package logship
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+// Entry is a line of an application log with the fields of its LogConfig,
+// such as the service, instance and type of the log and its LogTags
+type Entry struct {
+	Time    time.Time
+	Fields  map[string]string
+	Message string
+}
+
+// Batch is the entries of the logs of an instance that are shipped to a
+// backend
+type Batch struct {
+	Shipper string
+	Entries []Entry
+}
+
+// Writer ships entries to a logging backend
+type Writer interface {
+	Write(entries []Entry) error
+	Close() error
+}
@@ -0,0 +1,233 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package logship
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2017, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func TestFormatSyslog(t *testing.T) {
+	e := Entry{
+		Time: testTime,
+		Fields: map[string]string{
+			"type":     "app log",
+			"instance": "0",
+			"note":     `a "quoted" \ value]`,
+		},
+		Message: "started",
+	}
+	expected := `<134>1 2017-03-04T05:06:07Z host applog 0 - [serviced@32473 instance="0" note="a \"quoted\" \\ value\]" type="app log"] started` + "\n"
+	if actual := formatSyslog("host", e); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	expected = "<134>1 2017-03-04T05:06:07Z host - - - - bare\n"
+	if actual := formatSyslog("host", Entry{Time: testTime, Message: "bare"}); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestNewSyslogWriterAddress(t *testing.T) {
+	for _, address := range []string{"syslog.example.com:514", "http://syslog.example.com", "tcp://"} {
+		if _, err := NewSyslogWriter(address, nil); err == nil {
+			t.Errorf("expected an error for address %s", address)
+		}
+	}
+	for _, address := range []string{"udp://localhost:514", "tcp://localhost:514", "tls://localhost:6514"} {
+		if _, err := NewSyslogWriter(address, nil); err != nil {
+			t.Errorf("unexpected error for address %s: %s", address, err)
+		}
+	}
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			size, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(size))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	w, err := NewSyslogWriter("tcp://"+listener.Addr().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	entries := []Entry{
+		{Time: testTime, Message: "first"},
+		{Time: testTime, Message: "second"},
+	}
+	if err := w.Write(entries); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case msg := <-received:
+			if !strings.HasSuffix(msg, " - - "+expected+"\n") {
+				t.Errorf("expected message %s, got %q", expected, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for message %s", expected)
+		}
+	}
+}
+
+func TestKafkaWriter(t *testing.T) {
+	var path, contentType string
+	var payload struct {
+		Records []struct {
+			Value kafkaRecord `json:"value"`
+		} `json:"records"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	if _, err := NewKafkaWriter("kafka:8082", "logs"); err == nil {
+		t.Errorf("expected an error for a url without a scheme")
+	}
+	if _, err := NewKafkaWriter(server.URL, ""); err == nil {
+		t.Errorf("expected an error for an empty topic")
+	}
+	w, err := NewKafkaWriter(server.URL+"/", "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Write([]Entry{{Time: testTime, Fields: map[string]string{"service": "s1"}, Message: "hello"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/topics/logs" {
+		t.Errorf("expected path /topics/logs, got %s", path)
+	}
+	if contentType != kafkaContentType {
+		t.Errorf("expected content type %s, got %s", kafkaContentType, contentType)
+	}
+	if len(payload.Records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(payload.Records))
+	}
+	value := payload.Records[0].Value
+	if value.Message != "hello" || value.Fields["service"] != "s1" || value.Timestamp != "2017-03-04T05:06:07Z" {
+		t.Errorf("unexpected record %+v", value)
+	}
+}
+
+func TestKafkaWriterError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such topic", http.StatusNotFound)
+	}))
+	defer server.Close()
+	w, err := NewKafkaWriter(server.URL, "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]Entry{{Time: testTime, Message: "hello"}}); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+type testWriter struct {
+	entries []Entry
+	err     error
+	closed  bool
+}
+
+func (w *testWriter) Write(entries []Entry) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.entries = append(w.entries, entries...)
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestShipper(t *testing.T) {
+	syslog := &testWriter{}
+	kafka := &testWriter{err: errors.New("broker unavailable")}
+	s := NewShipper()
+	s.AddWriter("syslog", syslog)
+	s.AddWriter("kafka", kafka)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	url := server.URL + "/api/logs"
+
+	entries := []Entry{{Time: testTime, Message: "hello"}}
+	if err := Post(url, Batch{Shipper: "syslog", Entries: entries}); err != nil {
+		t.Fatal(err)
+	}
+	if len(syslog.entries) != 1 || syslog.entries[0].Message != "hello" {
+		t.Errorf("unexpected entries %+v", syslog.entries)
+	}
+	for shipper, status := range map[string]int{"kafka": http.StatusBadGateway, "logstash": http.StatusServiceUnavailable} {
+		resp, err := http.Post(url, "application/json", strings.NewReader(`{"Shipper":"`+shipper+`"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("expected status %d for %s, got %d", status, shipper, resp.StatusCode)
+		}
+	}
+	if err := Post(server.URL+"/api/other", Batch{Shipper: "syslog"}); err == nil {
+		t.Errorf("expected an error for an unknown path")
+	}
+
+	s.Close()
+	if !syslog.closed || !kafka.closed {
+		t.Errorf("expected the writers to be closed")
+	}
+}
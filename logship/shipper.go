@@ -0,0 +1,127 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logship
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// postTimeout bounds a post of a batch to the shipper
+const postTimeout = time.Minute
+
+// Shipper accepts batches of entries from the instances on a delegate and
+// writes them to the backend of their LogConfig.
+type Shipper struct {
+	mu       sync.Mutex
+	writers  map[string]Writer
+	listener net.Listener
+}
+
+// NewShipper returns a shipper with no backends
+func NewShipper() *Shipper {
+	return &Shipper{writers: make(map[string]Writer)}
+}
+
+// AddWriter sets the writer of a backend, such as syslog or kafka
+func (s *Shipper) AddWriter(shipper string, w Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writers[shipper] = w
+}
+
+// Listen accepts batches at the address until the shipper is closed.
+func (s *Shipper) Listen(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+	go http.Serve(listener, s)
+	return nil
+}
+
+// ServeHTTP implements http.Handler.  It responds 503 if the backend of the
+// batch is not configured on the delegate, and 502 if the batch could not be
+// written to it, so that the instance retries.
+func (s *Shipper) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.URL.Path != "/api/logs" {
+		http.NotFound(w, r)
+		return
+	}
+	var batch Batch
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	writer, ok := s.writers[batch.Shipper]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("log shipper %q is not configured", batch.Shipper), http.StatusServiceUnavailable)
+		return
+	}
+	if err := writer.Write(batch.Entries); err != nil {
+		plog.WithFields(logrus.Fields{
+			"shipper": batch.Shipper,
+			"entries": len(batch.Entries),
+		}).WithError(err).Warn("Could not ship logs")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Close stops accepting batches and closes the backends
+func (s *Shipper) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		s.listener.Close()
+		s.listener = nil
+	}
+	for shipper, w := range s.writers {
+		if err := w.Close(); err != nil {
+			plog.WithField("shipper", shipper).WithError(err).Debug("Could not close log shipper")
+		}
+	}
+	s.writers = make(map[string]Writer)
+}
+
+// Post sends a batch to the shipper at the url
+func Post(url string, batch Batch) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: postTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("logship: received response status %q shipping to %s", resp.Status, batch.Shipper)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logship
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogPriority is the priority of the messages, facility local0 and
+	// severity informational
+	syslogPriority = 16*8 + 6
+
+	// syslogSDID is the id of the structured data element that holds the
+	// fields of an entry
+	syslogSDID = "serviced@32473"
+
+	// syslogDialTimeout bounds the connection to the syslog server
+	syslogDialTimeout = 10 * time.Second
+
+	// syslogWriteTimeout bounds the write of a batch of messages
+	syslogWriteTimeout = 30 * time.Second
+)
+
+// SyslogWriter sends entries to a syslog server as RFC 5424 messages.  The
+// address is a url with the scheme udp, tcp, or tls for RFC 5425; messages
+// sent over tcp and tls are framed by octet counting.
+type SyslogWriter struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	mu        sync.Mutex
+	conn      net.Conn
+}
+
+// NewSyslogWriter returns a writer for the syslog server at the address,
+// such as tls://syslog.example.com:6514.  The tls config is used for tls
+// addresses; nil verifies the server with the roots of the host.
+func NewSyslogWriter(address string, tlsConfig *tls.Config) (*SyslogWriter, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("syslog address %s must have the scheme udp, tcp or tls", address)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("syslog address %s must have a host and port", address)
+	}
+	if u.Scheme == "tls" && tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return &SyslogWriter{
+		network:   u.Scheme,
+		address:   u.Host,
+		tlsConfig: tlsConfig,
+		hostname:  hostname,
+	}, nil
+}
+
+// CATLSConfig returns a tls config that verifies the syslog server with the
+// certificates of the CA file
+func CATLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// Write sends the entries to the syslog server, reconnecting once if the
+// connection was lost.
+func (w *SyslogWriter) Write(entries []Entry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.write(entries)
+	if err != nil && w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+		err = w.write(entries)
+	}
+	return err
+}
+
+func (w *SyslogWriter) write(entries []Entry) error {
+	if w.conn == nil {
+		conn, err := w.dial()
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+	w.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+	for _, e := range entries {
+		msg := formatSyslog(w.hostname, e)
+		if w.network != "udp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := w.conn.Write([]byte(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *SyslogWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if w.network == "tls" {
+		return tls.DialWithDialer(dialer, "tcp", w.address, w.tlsConfig)
+	}
+	return dialer.Dial(w.network, w.address)
+}
+
+// Close closes the connection to the syslog server
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// formatSyslog formats an entry as an RFC 5424 message.  The app name is the
+// type of the log, the process id is the instance, and the fields are sent
+// as structured data.
+func formatSyslog(hostname string, e Entry) string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		if sdName(name) != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	sd := "-"
+	if len(names) > 0 {
+		params := make([]string, len(names))
+		for i, name := range names {
+			params[i] = fmt.Sprintf(`%s="%s"`, sdName(name), sdValue(e.Fields[name]))
+		}
+		sd = fmt.Sprintf("[%s %s]", syslogSDID, strings.Join(params, " "))
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %s - %s %s\n",
+		syslogPriority,
+		e.Time.UTC().Format(time.RFC3339Nano),
+		header(hostname, 255),
+		header(e.Fields["type"], 48),
+		header(e.Fields["instance"], 128),
+		sd,
+		e.Message)
+}
+
+// header returns a header field of printable ascii up to the max length,
+// or the nil value if it is empty
+func header(value string, max int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	} else if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// sdName returns the name of a structured data parameter, which cannot have
+// spaces, =, ] or " and is at most 32 characters
+func sdName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > 32 {
+		return name[:32]
+	}
+	return name
+}
+
+// sdValue escapes the value of a structured data parameter
+func sdValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
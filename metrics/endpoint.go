@@ -0,0 +1,137 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The metrics that the import proxies of the containers report for each
+// imported application
+const (
+	EndpointConnections       = "endpoint.connections"
+	EndpointActiveConnections = "endpoint.connections.active"
+	EndpointConnectErrors     = "endpoint.connect.errors"
+	EndpointBytesSent         = "endpoint.bytes.sent"
+	EndpointBytesReceived     = "endpoint.bytes.received"
+)
+
+// EndpointMetrics are the connections that the instances of a service made
+// to an imported application over a window.  Active is the number of open
+// connections at the end of the window.
+type EndpointMetrics struct {
+	ServiceID     string
+	Application   string
+	Connections   int64
+	Active        int64
+	ConnectErrors int64
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// GetEndpointMetrics returns the connections that the services of a tenant
+// made to the applications that they import since the start date.
+func (c *Client) GetEndpointMetrics(startDate time.Time, tenantID string) ([]EndpointMetrics, error) {
+	logger := log.WithField("tenantid", tenantID)
+	logger.Debug("Requesting endpoint metrics for tenant")
+
+	options := V2PerformanceOptions{
+		Start:     fmt.Sprintf("%ds-ago", int(time.Since(startDate).Seconds())),
+		End:       "now",
+		Returnset: "exact",
+	}
+	for _, metric := range []string{
+		EndpointConnections,
+		EndpointActiveConnections,
+		EndpointConnectErrors,
+		EndpointBytesSent,
+		EndpointBytesReceived,
+	} {
+		options.Metrics = append(options.Metrics, V2MetricOptions{
+			Metric:     metric,
+			Aggregator: "sum",
+			Tags: map[string][]string{
+				"controlplane_tenant_id":   []string{tenantID},
+				"controlplane_service_id":  []string{"*"},
+				"controlplane_instance_id": []string{"*"},
+				"application":              []string{"*"},
+			},
+		})
+	}
+
+	result, err := c.v2performanceQuery(options)
+	if err != nil {
+		logger.WithError(err).Debug("Endpoint metric query failed")
+		return nil, err
+	}
+	return convertEndpointMetrics(result), nil
+}
+
+// convertEndpointMetrics sums the series of the instances of each service by
+// imported application.  Counters are cumulative per instance, so the
+// increase of each series is counted, and a counter that drops is counted
+// from zero as the instance restarted.
+func convertEndpointMetrics(data *V2PerformanceData) []EndpointMetrics {
+	statsMap := make(map[string]*EndpointMetrics)
+	for _, result := range data.Series {
+		serviceID, application := result.Tags["controlplane_service_id"], result.Tags["application"]
+		key := serviceID + "/" + application
+		stat, ok := statsMap[key]
+		if !ok {
+			stat = &EndpointMetrics{ServiceID: serviceID, Application: application}
+			statsMap[key] = stat
+		}
+		if len(result.Datapoints) < 1 {
+			continue
+		}
+
+		if result.Metric == EndpointActiveConnections {
+			stat.Active += int64(result.Datapoints[len(result.Datapoints)-1].Value())
+			continue
+		}
+		var increase float64
+		for i := 1; i < len(result.Datapoints); i++ {
+			prev, curr := result.Datapoints[i-1].Value(), result.Datapoints[i].Value()
+			if curr >= prev {
+				increase += curr - prev
+			} else {
+				increase += curr
+			}
+		}
+		switch result.Metric {
+		case EndpointConnections:
+			stat.Connections += int64(increase)
+		case EndpointConnectErrors:
+			stat.ConnectErrors += int64(increase)
+		case EndpointBytesSent:
+			stat.BytesSent += int64(increase)
+		case EndpointBytesReceived:
+			stat.BytesReceived += int64(increase)
+		}
+	}
+
+	stats := []EndpointMetrics{}
+	for _, stat := range statsMap {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].ServiceID != stats[j].ServiceID {
+			return stats[i].ServiceID < stats[j].ServiceID
+		}
+		return stats[i].Application < stats[j].Application
+	})
+	return stats
+}
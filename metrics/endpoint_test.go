@@ -0,0 +1,61 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertEndpointMetrics(t *testing.T) {
+	tags := func(serviceID, instanceID string) map[string]string {
+		return map[string]string{
+			"controlplane_service_id":  serviceID,
+			"controlplane_instance_id": instanceID,
+			"application":              "zodb",
+		}
+	}
+	data := &V2PerformanceData{
+		Series: []V2ResultData{
+			{Metric: EndpointConnections, Tags: tags("zope", "0"), Datapoints: []V2Datapoint{{1, 10}, {2, 15}, {3, 18}}},
+			// the instance restarted
+			{Metric: EndpointConnections, Tags: tags("zope", "1"), Datapoints: []V2Datapoint{{1, 10}, {2, 2}, {3, 4}}},
+			{Metric: EndpointActiveConnections, Tags: tags("zope", "0"), Datapoints: []V2Datapoint{{1, 5}, {3, 3}}},
+			{Metric: EndpointActiveConnections, Tags: tags("zope", "1"), Datapoints: []V2Datapoint{{3, 1}}},
+			{Metric: EndpointConnectErrors, Tags: tags("zope", "0"), Datapoints: []V2Datapoint{{1, 0}, {3, 2}}},
+			{Metric: EndpointBytesSent, Tags: tags("zope", "0"), Datapoints: []V2Datapoint{{1, 100}, {3, 400}}},
+			{Metric: EndpointBytesReceived, Tags: tags("zope", "0"), Datapoints: []V2Datapoint{{1, 1000}, {3, 5000}}},
+			{Metric: EndpointConnections, Tags: tags("zenhub", "0"), Datapoints: []V2Datapoint{}},
+		},
+	}
+
+	expected := []EndpointMetrics{
+		{ServiceID: "zenhub", Application: "zodb"},
+		{
+			ServiceID:     "zope",
+			Application:   "zodb",
+			Connections:   12,
+			Active:        4,
+			ConnectErrors: 2,
+			BytesSent:     300,
+			BytesReceived: 4000,
+		},
+	}
+	if actual := convertEndpointMetrics(data); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+}
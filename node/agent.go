@@ -35,17 +35,22 @@ import (
 	"github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/commons/iptables"
 	coordclient "github.com/control-center/serviced/coordinator/client"
+	coordetcd "github.com/control-center/serviced/coordinator/client/etcd"
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
 	"github.com/control-center/serviced/zzk"
 	zkdocker "github.com/control-center/serviced/zzk/docker"
 	zkservice "github.com/control-center/serviced/zzk/service"
+	zksetting "github.com/control-center/serviced/zzk/setting"
 )
 
 /*
@@ -80,6 +85,7 @@ type HostAgent struct {
 	useTLS               bool   // true if TLS should be enabled for MUX
 	proxyRegistry        proxy.ProxyRegistry
 	zkClient             *coordclient.Client
+	coordDriver          string          // the name of the coordinator driver used by zkClient
 	maxContainerAge      time.Duration   // maximum age for a stopped container before it is removed
 	virtualAddressSubnet string          // subnet for virtual addresses
 	servicedChain        *iptables.Chain // Assigned IP rule chain
@@ -88,11 +94,20 @@ type HostAgent struct {
 	dockerLogDriver      string
 	dockerLogConfig      map[string]string
 	pullreg              registry.Registry
+	imagePullPolicy      string         // pull policy of services that do not select one
+	tracingCollector     string         // OTLP/HTTP collector of the spans of services with tracing
+	aggregatorPort       int            // local port of the metrics aggregator, 0 to post metrics to the master
+	logShipperPort       int            // local port of the log shipper, 0 if it is not running
+	settings             *setting.Cache // cluster settings, watched in zookeeper
 	zkSessionTimeout     int
 	delegateKeyFile      string
 	tokenFile            string
 	conntrackFlush       bool
+	preserveContainers   bool // leave containers running when the agent stops
 	serviceCache         *ServiceCache
+	healthLimiter        *health.Limiter      // bounds the health checks that run at the same time
+	maxContainerStarts   int                  // bounds the containers that start at the same time
+	crossPool            *CrossPoolAuthorizer // checks the pool of the senders of mux streams
 	vip                  VIP
 }
 
@@ -116,6 +131,11 @@ func getZkDSN(zookeepers []string,
 	return dsn.String()
 }
 
+func getEtcdDSN(endpoints []string, sessionTimeout int) string {
+	dsn := coordetcd.NewDSN(endpoints, time.Duration(sessionTimeout)*time.Second, coordetcd.DefaultRequestTimeout)
+	return dsn.String()
+}
+
 type AgentOptions struct {
 	IPAddress            string
 	PoolID               string
@@ -128,6 +148,8 @@ type AgentOptions struct {
 	Mount                []string
 	FSType               volume.DriverType
 	Zookeepers           []string
+	CoordinatorDriver    string   // zookeeper (default) or etcd
+	EtcdEndpoints        []string // etcd endpoints when CoordinatorDriver is etcd
 	Mux                  *proxy.TCPMux
 	MuxPort              string
 	UseTLS               bool
@@ -146,6 +168,14 @@ type AgentOptions struct {
 	DelegateKeyFile      string
 	TokenFile            string
 	ConntrackFlush       bool
+	PreserveContainers   bool // true if containers should keep running when the agent stops
+	MaxHealthChecks      int  // health checks that may run at the same time, 0 for one per cpu
+	MaxContainerStarts   int  // containers started at the same time, highest priority first; 0 for no limit
+	ImagePullPolicy      string // pull policy of services that do not select one; IfNotPresent if empty
+	TracingCollector     string // OTLP/HTTP collector of the spans of services with tracing
+	AggregatorPort       int    // local port of the metrics aggregator, 0 to post metrics to the master
+	LogShipperPort       int    // local port of the log shipper, 0 if it is not running
+	RelayPort            int    // local port of the relay of attach and logs streams, 0 if it is not running
 }
 
 // NewHostAgent creates a new HostAgent given a connection string
@@ -174,16 +204,38 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.delegateKeyFile = options.DelegateKeyFile
 	agent.tokenFile = options.TokenFile
 	agent.conntrackFlush = options.ConntrackFlush
-	agent.serviceCache = NewServiceCache(options.Master)
+	agent.preserveContainers = options.PreserveContainers
+	agent.serviceCache = NewServiceCache(options.Master, options.PoolID)
+	agent.healthLimiter = health.NewLimiter(options.MaxHealthChecks)
+	agent.maxContainerStarts = options.MaxContainerStarts
+	agent.imagePullPolicy = options.ImagePullPolicy
+	agent.tracingCollector = options.TracingCollector
+	agent.aggregatorPort = options.AggregatorPort
+	agent.logShipperPort = options.LogShipperPort
+	agent.settings = setting.NewCache()
+	agent.crossPool = NewCrossPoolAuthorizer(options.PoolID)
+	if options.RelayPort > 0 {
+		agent.crossPool.AllowAddress(fmt.Sprintf("127.0.0.1:%d", options.RelayPort))
+	}
+	if agent.mux != nil {
+		agent.mux.SetAuthorizer(agent.crossPool)
+	}
 
 	var err error
-	dsn := getZkDSN(options.Zookeepers,
-		agent.zkSessionTimeout,
-		options.ZKConnectTimeout,
-		options.ZKPerHostConnectDelay,
-		options.ZKReconnectStartDelay,
-		options.ZKReconnectMaxDelay)
-	if agent.zkClient, err = coordclient.New("zookeeper", dsn, "", nil); err != nil {
+	agent.coordDriver = options.CoordinatorDriver
+	var dsn string
+	if agent.coordDriver == "etcd" {
+		dsn = getEtcdDSN(options.EtcdEndpoints, agent.zkSessionTimeout)
+	} else {
+		agent.coordDriver = "zookeeper"
+		dsn = getZkDSN(options.Zookeepers,
+			agent.zkSessionTimeout,
+			options.ZKConnectTimeout,
+			options.ZKPerHostConnectDelay,
+			options.ZKReconnectStartDelay,
+			options.ZKReconnectMaxDelay)
+	}
+	if agent.zkClient, err = coordclient.New(agent.coordDriver, dsn, "", nil); err != nil {
 		return nil, err
 	}
 	if agent.storage, err = volume.GetDriver(options.VolumesPath); err != nil {
@@ -200,6 +252,12 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	return agent, err
 }
 
+// masterIP returns the host of the master endpoint.  If several master
+// endpoints are configured, the first one is used.
+func (a *HostAgent) masterIP() string {
+	return strings.Split(rpcutils.PrimaryEndpoint(a.master), ":")[0]
+}
+
 // SetVIP allows you to update the default vip (primarily for testing)
 func (a *HostAgent) SetVIP(v VIP) {
 	a.vip = v
@@ -333,8 +391,9 @@ func (a *HostAgent) setupVolume(tenantID string, service *service.Service, volum
 func (a *HostAgent) Start(shutdown <-chan interface{}) {
 	glog.Info("Starting HostAgent")
 
-	// CC-1991: Unmount NFS on agent shutdown
-	if a.storage.DriverType() == volume.DriverTypeNFS {
+	// CC-1991: Unmount NFS on agent shutdown, unless the containers that use
+	// it are kept running
+	if a.storage.DriverType() == volume.DriverTypeNFS && !a.preserveContainers {
 		defer a.releaseStorageTenants()
 	}
 
@@ -369,6 +428,24 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 		wg.Done()
 	}()
 
+	// watch the cluster settings
+	wg.Add(1)
+	go func() {
+		glog.Infof("Starting settings listener")
+		zzk.Manage(shutdown, "/", zksetting.NewSettingsListener(a.settings))
+		glog.Infof("Settings listener done")
+		wg.Done()
+	}()
+
+	// watch the cross-pool policy of the pool
+	wg.Add(1)
+	go func() {
+		glog.Infof("Starting cross-pool policy listener")
+		zzk.Manage(shutdown, "/", a.crossPool)
+		glog.Infof("Cross-pool policy listener done")
+		wg.Done()
+	}()
+
 	// Increase the number of maximal tracked connections for iptables
 	maxConnections := "655360"
 	if cnxns := strings.TrimSpace(os.Getenv("SERVICED_IPTABLES_MAX_CONNECTIONS")); cnxns != "" {
@@ -383,14 +460,18 @@ func (a *HostAgent) Start(shutdown <-chan interface{}) {
 	if err := a.servicedChain.Inject(); err != nil {
 		glog.Errorf("Error creating SERVICED iptables chain (%v)", err)
 	}
-	// Clean up when we're done
-	defer a.servicedChain.Remove()
+	// Clean up when we're done, unless the containers are kept running
+	if !a.preserveContainers {
+		defer a.servicedChain.Remove()
+	}
 
 	unregister := make(chan interface{})
 	stop := make(chan interface{})
 
 	// Create the host state listener here, so it keeps its state
 	hsListener := zkservice.NewHostStateListener(a, a.hostID, shutdown)
+	hsListener.PreserveContainers(a.preserveContainers)
+	hsListener.SetStartLimit(a.maxContainerStarts)
 
 	for {
 		// handle shutdown if we are waiting for a zk connection
@@ -27,6 +27,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/zenoss/glog"
 )
@@ -47,6 +48,7 @@ type ServiceLogInfo struct {
 type ZkInfo struct {
 	ZkDSN  string
 	PoolID string
+	Driver string
 }
 
 func (a *HostAgent) SendLogMessage(serviceLogInfo ServiceLogInfo, _ *struct{}) (err error) {
@@ -65,6 +67,7 @@ func (a *HostAgent) GetISvcEndpoints(serviceId string, response *map[string][]ap
 	a.addControlPlaneEndpoint(myList)
 	a.addControlPlaneConsumerEndpoint(myList)
 	a.addLogstashEndpoint(myList)
+	a.addLogShipperEndpoint(myList)
 	a.addKibanaEndpoint(myList)
 
 	*response = myList
@@ -88,6 +91,27 @@ func (a *HostAgent) GetEvaluatedService(request EvaluateServiceRequest, response
 	return nil
 }
 
+// GetServiceSecrets proxies GetServiceSecrets to the master server.  The
+// values are not cached on the agent.
+func (a *HostAgent) GetServiceSecrets(request EvaluateServiceRequest, response *map[string]string) error {
+	masterClient, err := master.NewClient(a.master)
+	if err != nil {
+		glog.Errorf("Could not start Control Center client: %s", err)
+		return err
+	}
+	defer masterClient.Close()
+	values, err := masterClient.GetServiceSecrets(request.ServiceID, request.InstanceID)
+	if err != nil {
+		plog.WithFields(log.Fields{
+			"serviceID":  request.ServiceID,
+			"instanceID": request.InstanceID,
+		}).WithError(err).Error("Failed to get service secrets")
+		return err
+	}
+	*response = values
+	return nil
+}
+
 // GetProxySnapshotQuiece blocks until there is a snapshot request to the service
 func (a *HostAgent) GetProxySnapshotQuiece(serviceId string, snapshotId *string) error {
 	glog.Errorf("GetProxySnapshotQuiece() Unimplemented")
@@ -101,8 +125,19 @@ func (a *HostAgent) AckProxySnapshotQuiece(snapshotId string, unused *interface{
 	return errors.New("unimplemented")
 }
 
-// ReportHealthStatus proxies ReportHealthStatus to the master server.
+// ReportHealthStatus proxies ReportHealthStatus to the master server and
+// remediates the instance if its health check is failing.
 func (a *HostAgent) ReportHealthStatus(req master.HealthStatusRequest, unused *int) error {
+	if req.Remediation != nil {
+		if err := a.remediateInstance(req.Key, *req.Remediation); err != nil {
+			plog.WithFields(log.Fields{
+				"serviceid":   req.Key.ServiceID,
+				"instanceid":  req.Key.InstanceID,
+				"healthcheck": req.Key.HealthCheckName,
+			}).WithError(err).Warn("Could not remediate failing instance")
+		}
+	}
+
 	masterClient, err := master.NewClient(a.master)
 	if err != nil {
 		glog.Errorf("Could not start Control Center client: %s", err)
@@ -123,6 +158,47 @@ func (a *HostAgent) ReportInstanceDead(req master.ServiceInstanceRequest, unused
 	return masterClient.ReportInstanceDead(req.ServiceID, req.InstanceID)
 }
 
+// GetServiceHealth proxies GetServiceHealth to the master server, so that
+// instances can check the readiness gates that depend on other services.
+func (a *HostAgent) GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error {
+	masterClient, err := master.NewClient(a.master)
+	if err != nil {
+		glog.Errorf("Could not start Control Center client: %s", err)
+		return err
+	}
+	defer masterClient.Close()
+	statuses, err := masterClient.GetServiceHealth(serviceID)
+	if err != nil {
+		plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not get service health")
+		return err
+	}
+	*results = statuses
+	return nil
+}
+
+// AcquireHealthCheckSlots waits for slots to run a batch of health checks, so
+// that the instances on the host do not all run their checks at once.
+func (a *HostAgent) AcquireHealthCheckSlots(req HealthCheckSlotRequest, leaseID *string) error {
+	var wait <-chan time.Time
+	if req.Wait > 0 {
+		timer := time.NewTimer(req.Wait)
+		defer timer.Stop()
+		wait = timer.C
+	}
+	id, err := a.healthLimiter.Acquire(wait, req.Slots, req.Budget)
+	if err != nil {
+		return err
+	}
+	*leaseID = id
+	return nil
+}
+
+// ReleaseHealthCheckSlots frees the slots of a batch of health checks.
+func (a *HostAgent) ReleaseHealthCheckSlots(leaseID string, unused *int) error {
+	a.healthLimiter.Release(leaseID)
+	return nil
+}
+
 // addControlPlaneEndpoint adds an application endpoint mapping for the master control center api
 func (a *HostAgent) addControlPlaneEndpoint(endpoints map[string][]applicationendpoint.ApplicationEndpoint) {
 	key := "tcp" + a.uiport
@@ -132,7 +208,7 @@ func (a *HostAgent) addControlPlaneEndpoint(endpoints map[string][]applicationen
 	hoststr := strings.Split(a.uiport, ":")[0]
 	if len(hoststr) == 0 {
 		// Fall back to using the master host if an interface for the uiport isn't specified.
-		hoststr = strings.Split(a.master, ":")[0]
+		hoststr = a.masterIP()
 	}
 	portstr := strings.Split(a.uiport, ":")[1]
 	port, err := strconv.Atoi(portstr)
@@ -147,12 +223,13 @@ func (a *HostAgent) addControlPlaneEndpoint(endpoints map[string][]applicationen
 	// proxies to SERVICED_UI_ENDPOINT via https
 	endpoint.ProxyPort = uint16(SERVICED_UI_ENDPOINT)
 	endpoint.HostPort = uint16(port) //unused
-	endpoint.HostIP = strings.Split(a.master, ":")[0]
+	endpoint.HostIP = a.masterIP()
 	endpoint.Protocol = "tcp"
 	a.addEndpoint(key, endpoint, endpoints)
 }
 
-// addControlPlaneConsumerEndpoint adds an application endpoint mapping for the master control center api
+// addControlPlaneConsumerEndpoint adds an application endpoint mapping for the master control center api.
+// If the delegate aggregates metrics, the endpoint maps to the local aggregator instead of the master.
 func (a *HostAgent) addControlPlaneConsumerEndpoint(endpoints map[string][]applicationendpoint.ApplicationEndpoint) {
 	key := "tcp:8444"
 	endpoint := applicationendpoint.ApplicationEndpoint{}
@@ -162,8 +239,13 @@ func (a *HostAgent) addControlPlaneConsumerEndpoint(endpoints map[string][]appli
 	endpoint.ContainerPort = 8443
 	endpoint.ProxyPort = 8444
 	endpoint.HostPort = 8443
-	endpoint.HostIP = strings.Split(a.master, ":")[0]
+	endpoint.HostIP = a.masterIP()
 	endpoint.Protocol = "tcp"
+	if a.aggregatorPort > 0 {
+		endpoint.ContainerPort = uint16(a.aggregatorPort)
+		endpoint.HostPort = uint16(a.aggregatorPort)
+		endpoint.HostIP = a.ipaddress
+	}
 	a.addEndpoint(key, endpoint, endpoints)
 }
 
@@ -176,7 +258,7 @@ func (a *HostAgent) addLogstashEndpoint(endpoints map[string][]applicationendpoi
 		ContainerPort: 5042,
 		HostPort:      5042,
 		ProxyPort:     5042,
-		HostIP:        strings.Split(a.master, ":")[0],
+		HostIP:        a.masterIP(),
 		Protocol:      "tcp",
 	}
 	a.addEndpoint("tcp:5042", tcp_endpoint, endpoints)
@@ -188,12 +270,31 @@ func (a *HostAgent) addLogstashEndpoint(endpoints map[string][]applicationendpoi
 		ContainerPort: 5043,
 		HostPort:      5043,
 		ProxyPort:     5043,
-		HostIP:        strings.Split(a.master, ":")[0],
+		HostIP:        a.masterIP(),
 		Protocol:      "tcp",
 	}
 	a.addEndpoint("tcp:5043", filebeat_endpoint, endpoints)
 }
 
+// addLogShipperEndpoint adds an application endpoint mapping for the log shipper of the delegate, which
+// ships the logs of log configs with the syslog or kafka shipper.
+func (a *HostAgent) addLogShipperEndpoint(endpoints map[string][]applicationendpoint.ApplicationEndpoint) {
+	if a.logShipperPort <= 0 {
+		return
+	}
+	endpoint := applicationendpoint.ApplicationEndpoint{
+		ServiceID:     "controlplane_logshipper",
+		Application:   "controlplane_logshipper",
+		ContainerIP:   "127.0.0.1",
+		ContainerPort: uint16(a.logShipperPort),
+		HostPort:      uint16(a.logShipperPort),
+		ProxyPort:     5045,
+		HostIP:        a.ipaddress,
+		Protocol:      "tcp",
+	}
+	a.addEndpoint("tcp:5045", endpoint, endpoints)
+}
+
 // addKibanaEndpoint adds an application endpoint mapping for the master control center api
 func (a *HostAgent) addKibanaEndpoint(endpoints map[string][]applicationendpoint.ApplicationEndpoint) {
 	tcp_endpoint := applicationendpoint.ApplicationEndpoint{
@@ -203,7 +304,7 @@ func (a *HostAgent) addKibanaEndpoint(endpoints map[string][]applicationendpoint
 		ContainerPort: 5601,
 		HostPort:      5601,
 		ProxyPort:     5601,
-		HostIP:        strings.Split(a.master, ":")[0],
+		HostIP:        a.masterIP(),
 		Protocol:      "tcp",
 	}
 	a.addEndpoint("tcp:5601", tcp_endpoint, endpoints)
@@ -234,8 +335,9 @@ func (a *HostAgent) GetHostID(_ string, hostID *string) error {
 // GetZkInfo returns the agent's zookeeper connection string and its poolID
 func (a *HostAgent) GetZkInfo(_ string, zkInfo *ZkInfo) error {
 	localDSN := a.zkClient.ConnectionString()
-	zkInfo.ZkDSN = strings.Replace(localDSN, "127.0.0.1", strings.Split(a.master, ":")[0], -1)
+	zkInfo.ZkDSN = strings.Replace(localDSN, "127.0.0.1", a.masterIP(), -1)
 	zkInfo.PoolID = a.poolID
+	zkInfo.Driver = a.coordDriver
 	glog.V(4).Infof("ControlCenterAgent.GetZkInfo(): %+v", zkInfo)
 	return nil
 }
@@ -85,3 +85,54 @@ func TestAddControlPlaneEndpoints(t *testing.T) {
 		t.Fatalf(" mapping failed %+v expected %+v", endpoints[ccuiport][0], controlplane_endpoint)
 	}
 }
+
+func TestAddControlPlaneConsumerEndpoint_Aggregator(t *testing.T) {
+	agent := &HostAgent{}
+	agent.master = "127.0.0.1:0"
+	agent.ipaddress = "10.0.0.5"
+	agent.aggregatorPort = 8445
+	endpoints := make(map[string][]applicationendpoint.ApplicationEndpoint)
+
+	agent.addControlPlaneConsumerEndpoint(endpoints)
+
+	expected := applicationendpoint.ApplicationEndpoint{
+		ServiceID:     "controlplane_consumer",
+		Application:   "controlplane_consumer",
+		ContainerIP:   "127.0.0.1",
+		ContainerPort: 8445,
+		ProxyPort:     8444,
+		HostPort:      8445,
+		HostIP:        "10.0.0.5",
+		Protocol:      "tcp",
+	}
+	if len(endpoints["tcp:8444"]) != 1 || endpoints["tcp:8444"][0] != expected {
+		t.Fatalf(" mapping failed %+v expected %+v", endpoints["tcp:8444"], expected)
+	}
+}
+
+func TestAddLogShipperEndpoint(t *testing.T) {
+	agent := &HostAgent{}
+	agent.ipaddress = "10.0.0.5"
+	endpoints := make(map[string][]applicationendpoint.ApplicationEndpoint)
+
+	agent.addLogShipperEndpoint(endpoints)
+	if len(endpoints) != 0 {
+		t.Fatalf("expected no endpoints without a log shipper, got %+v", endpoints)
+	}
+
+	agent.logShipperPort = 5046
+	agent.addLogShipperEndpoint(endpoints)
+	expected := applicationendpoint.ApplicationEndpoint{
+		ServiceID:     "controlplane_logshipper",
+		Application:   "controlplane_logshipper",
+		ContainerIP:   "127.0.0.1",
+		ContainerPort: 5046,
+		ProxyPort:     5045,
+		HostPort:      5046,
+		HostIP:        "10.0.0.5",
+		Protocol:      "tcp",
+	}
+	if len(endpoints["tcp:5045"]) != 1 || endpoints["tcp:5045"][0] != expected {
+		t.Fatalf(" mapping failed %+v expected %+v", endpoints["tcp:5045"], expected)
+	}
+}
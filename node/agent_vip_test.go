@@ -61,6 +61,25 @@ func (t *HostAgentTestSuite) TestBindIP_Match(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (t *HostAgentTestSuite) TestBindIP_MatchIPv6(c *C) {
+	var (
+		ipprefix = "2001:DB8::10"
+		iface    = "dummy0"
+	)
+
+	// vip exists and is a match, whether the netmask is a prefix length or
+	// an IPv6 mask
+	vip := &IP{
+		Addr:   "2001:db8::10/64",
+		Device: iface,
+	}
+	t.m.On("Find", "2001:db8::10").Return(vip).Twice()
+	err := t.a.BindIP(ipprefix, "64", iface)
+	c.Assert(err, IsNil)
+	err = t.a.BindIP(ipprefix, "ffff:ffff:ffff:ffff::", iface)
+	c.Assert(err, IsNil)
+}
+
 func (t *HostAgentTestSuite) TestBindIP_FailRelease(c *C) {
 	var (
 		ipprefix = "1.2.3.4"
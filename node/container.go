@@ -14,13 +14,17 @@
 package node
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
@@ -30,6 +34,8 @@ import (
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/servicedversion"
 	"github.com/control-center/serviced/utils"
@@ -38,6 +44,28 @@ import (
 	dockerclient "github.com/fsouza/go-dockerclient"
 )
 
+// ErrStartTimeout is returned when an instance does not start within the
+// start timeout of its service.
+var ErrStartTimeout = errors.New("instance did not start within the start timeout")
+
+// ErrInsufficientCores is returned when an instance requests more CPU cores
+// than the host has.
+var ErrInsufficientCores = errors.New("host does not have enough cores for the instance")
+
+// startFailedLogLines is the number of container log lines captured when an
+// instance fails to start.
+const startFailedLogLines = 100
+
+// defaultLogMaxSize and defaultLogMaxFile bound the json-file logs of
+// containers that don't set a size limit.
+const (
+	defaultLogMaxSize = "10m"
+	defaultLogMaxFile = "5"
+)
+
+// cpuPeriod is the CFS scheduler period, in microseconds, used to enforce the
+// cpu limit of a service.
+const cpuPeriod = 100000
 
 func (a *HostAgent) setInstanceState(serviceID string, instanceID int, state service.InstanceCurrentState) error {
 	logger := plog.WithFields(log.Fields{
@@ -60,6 +88,48 @@ func (a *HostAgent) setInstanceState(serviceID string, instanceID int, state ser
 	})
 }
 
+// remediateInstance restarts or reschedules an instance whose health check is
+// failing by updating its desired state.  The restart count is kept on the
+// host state so that it survives the restart of the container.
+func (a *HostAgent) remediateInstance(key health.HealthStatusKey, r health.Remediation) error {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":   key.ServiceID,
+		"instanceid":  key.InstanceID,
+		"healthcheck": key.HealthCheckName,
+		"onfailure":   r.OnFailure,
+	})
+	conn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(a.poolID))
+	if err != nil {
+		logger.WithError(err).Error("Could not connect to zookeeper")
+		return err
+	}
+	req := zkservice.StateRequest{
+		HostID:     a.hostID,
+		ServiceID:  key.ServiceID,
+		InstanceID: key.InstanceID,
+	}
+	now := time.Now()
+	return zkservice.UpdateState(conn, req, func(s *zkservice.State) bool {
+		// only remediate instances that are supposed to be running
+		if s.DesiredState != service.SVCRun {
+			return false
+		}
+		switch r.Action(s.Restarts, s.LastRestart, now) {
+		case health.RemediateRestart:
+			s.DesiredState = service.SVCRestart
+			s.Restarts++
+			s.LastRestart = now
+			logger.WithField("restarts", s.Restarts).Warn("Restarting instance with a failing health check")
+			return true
+		case health.RemediateReschedule:
+			s.DesiredState = service.SVCStop
+			logger.WithField("restarts", s.Restarts).Warn("Rescheduling instance with a failing health check")
+			return true
+		}
+		return false
+	})
+}
+
 // StopContainer stops running container or returns nil if the container does
 // not exist or has already stopped.
 func (a *HostAgent) StopContainer(serviceID string, instanceID int) error {
@@ -131,6 +201,7 @@ func (a *HostAgent) AttachContainer(state *zkservice.ServiceState, serviceID str
 		ctr.CancelOnEvent(docker.Die)
 		return nil, nil
 	}
+	a.crossPool.AddContainer(serviceID, state)
 	go a.exposeAssignedIPs(state, ctr)
 	a.setInstanceState(serviceID, instanceID, service.StateRunning)
 	return ev, nil
@@ -145,17 +216,35 @@ func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string,
 		"instanceid": instanceID,
 	})
 
-	a.serviceCache.Invalidate(serviceID, instanceID)
+	// the cache evicts the service when it changes, so starts only call the
+	// master for services that changed or are not cached yet
 	evaluatedService, tenantID, _, err := a.serviceCache.GetEvaluatedService(serviceID, instanceID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get service")
 		return nil, nil, err
 	}
 
+	// make sure the host can fit the instance
+	if cores := runtime.NumCPU(); evaluatedService.CPURequest > float64(cores) {
+		logger.WithFields(log.Fields{
+			"cpurequest": evaluatedService.CPURequest,
+			"cores":      cores,
+		}).Error("Service requests more cores than the host has")
+		return nil, nil, ErrInsufficientCores
+	}
+
+	// the start timeout bounds the image pull and the container start
+	timeout := time.Duration(evaluatedService.StartTimeout) * time.Second
+	startCancel, expired, stop := withStartTimeout(cancel, timeout)
+	defer stop()
+
 	// pull the service image
 	a.setInstanceState(serviceID, instanceID, service.StatePulling)
-	imageUUID, imageName, err := a.pullImage(logger, cancel, evaluatedService.ImageID)
+	imageUUID, imageName, err := a.pullImage(logger, startCancel, evaluatedService.ImageID, a.pullPolicy(evaluatedService))
 	if err != nil {
+		if expired() {
+			return nil, nil, a.failStart(logger, serviceID, instanceID, nil, fmt.Sprintf("Image %s was not pulled within %s", evaluatedService.ImageID, timeout))
+		}
 		logger.WithError(err).Debug("Could not pull the service image")
 		return nil, nil, err
 	}
@@ -187,15 +276,93 @@ func (a *HostAgent) StartContainer(cancel <-chan interface{}, serviceID string,
 		return nil, nil, err
 	}
 
+	if expired() {
+		ctr.CancelOnEvent(docker.Die)
+		return nil, nil, a.failStart(logger, serviceID, instanceID, ctr, fmt.Sprintf("Container did not reach the running state within %s", timeout))
+	}
+
 	state.HostIP = a.ipaddress
 	state.PrivateIP = ctr.NetworkSettings.IPAddress
 	state.Started = dctr.State.StartedAt
 
+	a.crossPool.AddContainer(serviceID, state)
 	go a.exposeAssignedIPs(state, ctr)
 	a.setInstanceState(serviceID, instanceID, service.StateRunning)
 	return state, ev, nil
 }
 
+// withStartTimeout returns a channel that closes when either the cancel
+// channel closes or the timeout elapses, a function that reports whether the
+// timeout has elapsed, and a function to release the timer.  A timeout <= 0
+// never expires.
+func withStartTimeout(cancel <-chan interface{}, timeout time.Duration) (<-chan interface{}, func() bool, func()) {
+	if timeout <= 0 {
+		return cancel, func() bool { return false }, func() {}
+	}
+
+	startCancel := make(chan interface{})
+	expiredC := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			close(expiredC)
+		case <-cancel:
+		case <-done:
+			return
+		}
+		close(startCancel)
+	}()
+
+	expired := func() bool {
+		select {
+		case <-expiredC:
+			return true
+		default:
+			return false
+		}
+	}
+	var once sync.Once
+	stop := func() { once.Do(func() { close(done) }) }
+	return startCancel, expired, stop
+}
+
+// failStart records the diagnostics of an instance that did not start within
+// its start timeout on the service's event timeline and marks the start as
+// failed.  ctr may be nil if the container was never created.
+func (a *HostAgent) failStart(logger *log.Entry, serviceID string, instanceID int, ctr *docker.Container, message string) error {
+	evt := zkservice.InstanceEvent{
+		Timestamp:  time.Now(),
+		Type:       zkservice.EventStartFailed,
+		HostID:     a.hostID,
+		InstanceID: instanceID,
+		Message:    message,
+	}
+
+	if ctr != nil {
+		dctr, err := ctr.Inspect()
+		if err != nil {
+			logger.WithError(err).Debug("Could not inspect container")
+		} else if data, err := json.MarshalIndent(dctr, "", "  "); err == nil {
+			evt.Inspect = string(data)
+		}
+		evt.Logs = dockerLogs(ctr.ID, logDriver(dctr), startFailedLogLines)
+	}
+
+	logger.WithField("reason", message).Warn("Instance did not start within its start timeout")
+
+	if conn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(a.poolID)); err != nil {
+		logger.WithError(err).Error("Could not connect to zookeeper")
+	} else if err := zkservice.AddInstanceEvent(conn, serviceID, evt); err != nil {
+		logger.WithError(err).Warn("Could not add start failure to the event timeline")
+	}
+
+	a.setInstanceState(serviceID, instanceID, service.StateStartFailed)
+	return ErrStartTimeout
+}
+
 // RestartContainer asynchronously pulls the latest image of a running
 // container before stopping the service.  After the service has stopped, the
 // listener will be notified by the event monitor.
@@ -225,11 +392,16 @@ func (a *HostAgent) RestartContainer(cancel <-chan interface{}, serviceID string
 		return nil
 	}
 
+	policy := commons.PullIfNotPresent
+	if svc, _, _, err := a.serviceCache.GetEvaluatedService(serviceID, instanceID); err == nil {
+		policy = a.pullPolicy(svc)
+	}
+
 	go func() {
 		a.setInstanceState(serviceID, instanceID, service.StatePulling)
 		for {
 			// relentlessly try to pull the image
-			_, _, err := a.pullImage(logger, cancel, ctr.Config.Image)
+			_, _, err := a.pullImage(logger, cancel, ctr.Config.Image, policy)
 			if err != nil {
 				logger.WithError(err).Debug("Could not pull the service image")
 				// wait 5 seconds and try again
@@ -383,9 +555,27 @@ func (a *HostAgent) PauseContainer(serviceID string, instanceID int) error {
 	return nil
 }
 
-// pullImage pulls the service image and returns the uuid string
-// of the image and the fully qualified image name.
-func (a *HostAgent) pullImage(logger *log.Entry, cancel <-chan interface{}, imageID string) (string, string, error) {
+// pullPolicy returns the image pull policy of the service.  If the service
+// does not select one, the cluster setting for the pool and deployment is
+// used, and then the delegate's policy.
+func (a *HostAgent) pullPolicy(svc *service.Service) string {
+	if svc.ImagePullPolicy != "" {
+		return svc.ImagePullPolicy
+	}
+	if policy, ok := a.settings.Value(setting.ImagePullPolicy, a.poolID, svc.DeploymentID); ok {
+		return policy
+	}
+	if a.imagePullPolicy != "" {
+		return a.imagePullPolicy
+	}
+	return commons.PullIfNotPresent
+}
+
+// pullImage pulls the service image according to the pull policy and returns
+// the uuid string of the image and the fully qualified image name.
+func (a *HostAgent) pullImage(logger *log.Entry, cancel <-chan interface{}, imageID, policy string) (string, string, error) {
+	logger = logger.WithField("pullpolicy", policy)
+
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
 		logger.WithError(err).Debug("Could not connect to coordinator")
@@ -409,7 +599,7 @@ func (a *HostAgent) pullImage(logger *log.Entry, cancel <-chan interface{}, imag
 	}()
 
 	a.pullreg.SetConnection(conn)
-	if err := a.pullreg.PullImage(timeoutC, imageID); err != nil {
+	if err := a.pullreg.PullImageWithPolicy(timeoutC, imageID, policy); err != nil {
 		logger.WithError(err).Debug("Could not pull image")
 
 		// TODO: wrap error?
@@ -446,6 +636,7 @@ func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container) <
 		serviceID := someSlice[0]
 		instanceID, err := strconv.ParseInt(someSlice[1], 10, 0)
 		a.setInstanceState(serviceID, int(instanceID), service.StateStopped)
+		a.crossPool.RemoveContainer(ctr.ID)
 		defer close(ev)
 		dctr, err := ctr.Inspect()
 		if err != nil {
@@ -460,7 +651,7 @@ func (a *HostAgent) monitorContainer(logger *log.Entry, ctr *docker.Container) <
 		}).Debug("Container exited")
 
 		if dctr.State.ExitCode != 0 || log.GetLevel() == log.DebugLevel {
-			dockerLogsToFile(ctr.ID, 1000)
+			dockerLogsToFile(ctr.ID, logDriver(dctr), 1000)
 		}
 
 		if err := ctr.Delete(true); err != nil {
@@ -541,9 +732,61 @@ func (a *HostAgent) getService(serviceID string) (*service.Service, error) {
 	return svc, nil
 }
 
+// serviceLogConfig returns the docker log driver and options of the service's
+// containers.  Services without a log driver use the delegate's.  json-file
+// logs are rotated unless a size limit is set, so they can't fill the disk.
+func (a *HostAgent) serviceLogConfig(svc *service.Service) (string, map[string]string) {
+	driver, opts := a.dockerLogDriver, a.dockerLogConfig
+	if svc.DockerLogDriver != "" {
+		driver, opts = svc.DockerLogDriver, svc.DockerLogConfig
+	}
+	if driver != commons.LogDriverJSONFile {
+		return driver, opts
+	}
+	if _, ok := opts["max-size"]; ok {
+		return driver, opts
+	}
+	config := map[string]string{
+		"max-size": defaultLogMaxSize,
+		"max-file": defaultLogMaxFile,
+	}
+	for k, v := range opts {
+		config[k] = v
+	}
+	return driver, config
+}
+
+// logDriver returns the log driver of a container
+func logDriver(dctr *dockerclient.Container) string {
+	if dctr == nil || dctr.HostConfig == nil {
+		return ""
+	}
+	return dctr.HostConfig.LogConfig.Type
+}
+
+// logsCommand returns the command that reads the last numlines lines of the
+// container logs.  Logs sent to journald are read from the journal, so they
+// remain readable after the container is deleted.
+func logsCommand(containerid, driver string, numlines int) *exec.Cmd {
+	if driver == commons.LogDriverJournald {
+		return exec.Command("journalctl", "--no-pager", "-o", "cat", "-n", fmt.Sprintf("%d", numlines), "CONTAINER_ID_FULL="+containerid)
+	}
+	return exec.Command("docker", "logs", "--tail", fmt.Sprintf("%d", numlines), containerid)
+}
+
+// dockerLogs returns the last numlines lines of the container logs
+func dockerLogs(containerid, driver string, numlines int) string {
+	// TODO: need to get logs from api
+	cmd := logsCommand(containerid, driver, numlines)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		plog.WithError(err).Debug("Unable to get logs for container")
+	}
+	return string(output)
+}
 
 // dockerLogsToFile dumps container logs to file
-func dockerLogsToFile(containerid string, numlines int) {
+func dockerLogsToFile(containerid, driver string, numlines int) {
 	// TODO: need to get logs from api
 
 	fname := filepath.Join(os.TempDir(), fmt.Sprintf("%s.container.log", containerid))
@@ -555,7 +798,7 @@ func dockerLogsToFile(containerid string, numlines int) {
 		return
 	}
 	defer f.Close()
-	cmd := exec.Command("docker", "logs", "--tail", fmt.Sprintf("%d", numlines), containerid)
+	cmd := logsCommand(containerid, driver, numlines)
 	cmd.Stdout = f
 	cmd.Stderr = f
 	if err := cmd.Run(); err != nil {
@@ -761,7 +1004,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 	}
 
 	// Get host IP
-	ips, err := utils.GetIPv4Addresses()
+	ips, err := utils.GetIPAddresses()
 	if err != nil {
 		logger.WithError(err).Error("Unable to get host IP addresses")
 		return nil, nil, nil, err
@@ -776,7 +1019,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 	// End temp fix part 1. See immediately below for part 2.
 
 	// add arguments for environment variables
-	cfg.Env = append(svc.Environment,
+	cfg.Env = append(svc.ContainerEnvironment(),
 		fmt.Sprintf("SERVICED_VERSION='%s'", servicedversion.Version),
 		fmt.Sprintf("CONTROLPLANE_HOST_IPS='%s'", strings.Join(ips, " ")),
 		fmt.Sprintf("SERVICED_VIRTUAL_ADDRESS_SUBNET=%s", a.virtualAddressSubnet),
@@ -789,13 +1032,22 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		fmt.Sprintf("SERVICED_LOG_ADDRESS=%s", a.logstashURL),
 		//The SERVICED_UI_PORT environment variable is deprecated and services should always use port 443 to contact serviced from inside a container
 		"SERVICED_UI_PORT=443",
-		fmt.Sprintf("SERVICED_MASTER_IP=%s", strings.Split(a.master, ":")[0]),
+		fmt.Sprintf("SERVICED_MASTER_IP=%s", a.masterIP()),
 		fmt.Sprintf("TZ=%s", os.Getenv("TZ")),
 		// XXX: Hopefully temp fix for CC-1384 & CC-1631 (docker/docker issue 14203).
 		fmt.Sprintf("DOCKER_14203_FIX='%s'", fix),
 		// End temp fix part 2. See immediately above for part 1.
 	)
 
+	// add the tracing environment if the service has tracing
+	if svc.Tracing {
+		if collector := a.traceCollector(svc); collector != "" {
+			cfg.Env = append(cfg.Env, tracingEnv(svc, instanceID, collector)...)
+		} else {
+			logger.Warn("Service has tracing, but no tracing collector is configured")
+		}
+	}
+
 	// add dns values to setup
 	for _, addr := range a.dockerDNS {
 		_addr := strings.TrimSpace(addr)
@@ -842,8 +1094,13 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 		cfg.CPUShares = svc.CPUShares
 	}
 
-	hcfg.LogConfig.Type = a.dockerLogDriver
-	hcfg.LogConfig.Config = a.dockerLogConfig
+	// Cap the cores the container may use
+	if svc.CPULimit > 0 {
+		hcfg.CPUPeriod = cpuPeriod
+		hcfg.CPUQuota = int64(svc.CPULimit * cpuPeriod)
+	}
+
+	hcfg.LogConfig.Type, hcfg.LogConfig.Config = a.serviceLogConfig(svc)
 
 	// CC-1848: set core ulimit to 0
 	hcfg.Ulimits = []dockerclient.ULimit{
@@ -17,11 +17,13 @@ package node
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	regmocks "github.com/control-center/serviced/dfs/registry/mocks"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/setting"
 )
 
 func TestSetupContainer_DockerLog(t *testing.T) {
@@ -61,3 +63,163 @@ func TestSetupContainer_DockerLog(t *testing.T) {
 	assert.Equal(hcfg.LogConfig.Config["bravo"], "two")
 	assert.Equal(hcfg.LogConfig.Config["charlie"], "three")
 }
+
+func TestSetupContainer_ServiceDockerLog(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeHostAgent := &HostAgent{
+		uiport:               ":443",
+		dockerLogDriver:      "json-file",
+		dockerLogConfig:      map[string]string{"max-size": "1g"},
+		virtualAddressSubnet: "0.0.0.0",
+		pullreg:              &regmocks.Registry{},
+	}
+
+	// the service log driver replaces the delegate's
+	fakeService := &service.Service{
+		ImageID:         "busybox:latest",
+		ID:              "faketestService",
+		Name:            "fakeTestServiceName",
+		DockerLogDriver: "journald",
+		DockerLogConfig: map[string]string{"tag": "svc"},
+	}
+	_, hcfg, _, err := fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal("journald", hcfg.LogConfig.Type)
+	assert.Equal(map[string]string{"tag": "svc"}, hcfg.LogConfig.Config)
+
+	// json-file logs without a size limit are rotated
+	fakeService.DockerLogDriver = "json-file"
+	fakeService.DockerLogConfig = map[string]string{"labels": "a"}
+	_, hcfg, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal("json-file", hcfg.LogConfig.Type)
+	assert.Equal(map[string]string{"labels": "a", "max-size": "10m", "max-file": "5"}, hcfg.LogConfig.Config)
+
+	// the delegate's size limit is kept
+	fakeService.DockerLogDriver = ""
+	fakeService.DockerLogConfig = nil
+	_, hcfg, _, err = fakeHostAgent.createContainerConfig("unused", fakeService, 0, "unused")
+	assert.Nil(err)
+	assert.Equal(map[string]string{"max-size": "1g"}, hcfg.LogConfig.Config)
+}
+
+func TestLogsCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := logsCommand("abc", "journald", 10)
+	assert.Equal([]string{"journalctl", "--no-pager", "-o", "cat", "-n", "10", "CONTAINER_ID_FULL=abc"}, cmd.Args)
+
+	cmd = logsCommand("abc", "json-file", 10)
+	assert.Equal([]string{"docker", "logs", "--tail", "10", "abc"}, cmd.Args)
+}
+
+func TestWithStartTimeout_NoTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	cancel := make(chan interface{})
+	startCancel, expired, stop := withStartTimeout(cancel, 0)
+	defer stop()
+
+	assert.False(expired())
+	close(cancel)
+	select {
+	case <-startCancel:
+	case <-time.After(time.Second):
+		t.Fatal("start was not cancelled")
+	}
+	assert.False(expired())
+}
+
+func TestWithStartTimeout_Expired(t *testing.T) {
+	assert := assert.New(t)
+
+	cancel := make(chan interface{})
+	startCancel, expired, stop := withStartTimeout(cancel, 10*time.Millisecond)
+	defer stop()
+
+	select {
+	case <-startCancel:
+	case <-time.After(time.Second):
+		t.Fatal("start did not time out")
+	}
+	assert.True(expired())
+}
+
+func TestWithStartTimeout_Cancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	cancel := make(chan interface{})
+	startCancel, expired, stop := withStartTimeout(cancel, time.Minute)
+	defer stop()
+
+	close(cancel)
+	select {
+	case <-startCancel:
+	case <-time.After(time.Second):
+		t.Fatal("start was not cancelled")
+	}
+	assert.False(expired())
+}
+
+func TestPullPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	// the delegate's policy defaults to IfNotPresent
+	fakeHostAgent := &HostAgent{}
+	fakeService := &service.Service{ID: "faketestService"}
+	assert.Equal("IfNotPresent", fakeHostAgent.pullPolicy(fakeService))
+
+	// the delegate's policy is the cluster default
+	fakeHostAgent.imagePullPolicy = "Always"
+	assert.Equal("Always", fakeHostAgent.pullPolicy(fakeService))
+
+	// the cluster setting replaces the delegate's policy
+	fakeHostAgent.poolID = "pool"
+	fakeHostAgent.settings = setting.NewCache()
+	s := setting.New(setting.ImagePullPolicy)
+	s.Set(setting.ScopePool, "pool", "Never")
+	fakeHostAgent.settings.Update([]setting.Setting{*s})
+	assert.Equal("Never", fakeHostAgent.pullPolicy(fakeService))
+
+	// the service's policy replaces the setting
+	fakeService.ImagePullPolicy = "Always"
+	fakeHostAgent.settings.Update(nil)
+	assert.Equal("Always", fakeHostAgent.pullPolicy(fakeService))
+	fakeService.ImagePullPolicy = "Never"
+	assert.Equal("Never", fakeHostAgent.pullPolicy(fakeService))
+}
+
+func TestTracingEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	// services without tracing are not traced
+	fakeHostAgent := &HostAgent{tracingCollector: "collector:4318"}
+	fakeService := &service.Service{ID: "faketestService", Name: "fake", DeploymentID: "dep", PoolID: "pool"}
+	assert.Equal("", fakeHostAgent.traceCollector(fakeService))
+
+	// the delegate's collector is the cluster default
+	fakeService.Tracing = true
+	assert.Equal("collector:4318", fakeHostAgent.traceCollector(fakeService))
+
+	// the cluster setting replaces the delegate's collector
+	fakeHostAgent.poolID = "pool"
+	fakeHostAgent.settings = setting.NewCache()
+	s := setting.New(setting.TracingCollector)
+	s.Set(setting.ScopeDeployment, "dep", "other:4318")
+	fakeHostAgent.settings.Update([]setting.Setting{*s})
+	assert.Equal("other:4318", fakeHostAgent.traceCollector(fakeService))
+
+	// the sdk sends spans to the forwarder of the controller
+	env := tracingEnv(fakeService, 2, "other:4318")
+	assert.Contains(env, "SERVICED_TRACING_COLLECTOR=other:4318")
+	assert.Contains(env, "OTEL_SERVICE_NAME=fake")
+	assert.Contains(env, "OTEL_EXPORTER_OTLP_ENDPOINT=http://"+TracingForwarderAddress)
+	assert.Contains(env, "OTEL_RESOURCE_ATTRIBUTES=service.instance.id=faketestService/2,serviced.service.id=faketestService,serviced.deployment.id=dep,serviced.pool.id=pool")
+
+	// variables that the service sets are left alone
+	fakeService.Environment = []string{"OTEL_SERVICE_NAME=custom"}
+	env = tracingEnv(fakeService, 2, "other:4318")
+	assert.NotContains(env, "OTEL_SERVICE_NAME=fake")
+	assert.Len(env, 5)
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/pool"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+// ErrCrossPoolDenied is returned when the cross-pool policy of the pool of
+// the host does not let the sender import the endpoint
+var ErrCrossPoolDenied = errors.New("cross-pool import is not allowed")
+
+// crossPoolExport is an endpoint that a container on the host exports
+type crossPoolExport struct {
+	serviceID   string
+	application string
+}
+
+// CrossPoolAuthorizer only lets the instances of other pools connect through
+// the mux to the endpoints that the cross-pool policy of the pool of the host
+// allows.  Instances of the same pool may always connect, and every host may
+// connect to the services of the host that authenticate the sender
+// themselves, such as the relay.  Until the policy of the pool is loaded,
+// every import is allowed.
+type CrossPoolAuthorizer struct {
+	poolID     string
+	mu         sync.RWMutex
+	pool       *pool.ResourcePool
+	exports    map[string]crossPoolExport // container address -> export
+	containers map[string][]string        // container id -> container addresses
+	allowed    map[string]bool            // addresses of the services of the host
+}
+
+// NewCrossPoolAuthorizer returns an authorizer for the mux of a host in the
+// pool.
+func NewCrossPoolAuthorizer(poolID string) *CrossPoolAuthorizer {
+	return &CrossPoolAuthorizer{
+		poolID:     poolID,
+		exports:    make(map[string]crossPoolExport),
+		containers: make(map[string][]string),
+		allowed:    make(map[string]bool),
+	}
+}
+
+// AllowAddress lets every host connect to a service of the host at the
+// address, which must authenticate the sender itself
+func (a *CrossPoolAuthorizer) AllowAddress(address string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.allowed[address] = true
+}
+
+// AuthorizeMux implements proxy.MuxAuthorizer
+func (a *CrossPoolAuthorizer) AuthorizeMux(sender auth.Identity, address string) error {
+	senderPoolID := sender.PoolID()
+	if senderPoolID == a.poolID {
+		return nil
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.pool == nil || a.pool.GetCrossPoolPolicy() != pool.CrossPoolRestrict || a.allowed[address] {
+		return nil
+	}
+
+	// addresses that are not exported by a container on this host cannot
+	// be matched against the rules of the pool
+	exp, ok := a.exports[address]
+	if !ok || !a.pool.AllowsImport(senderPoolID, exp.application) {
+		plog.WithFields(log.Fields{
+			"senderpoolid":  senderPoolID,
+			"containeraddr": address,
+			"serviceid":     exp.serviceID,
+			"application":   exp.application,
+		}).Debug("Denied cross-pool import")
+		return ErrCrossPoolDenied
+	}
+	return nil
+}
+
+// AddContainer records the endpoints that a container exports
+func (a *CrossPoolAuthorizer) AddContainer(serviceID string, state *zkservice.ServiceState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeContainer(state.ContainerID)
+
+	addresses := []string{}
+	for _, exp := range state.Exports {
+		address := net.JoinHostPort(state.PrivateIP, strconv.Itoa(int(exp.PortNumber)))
+		a.exports[address] = crossPoolExport{serviceID: serviceID, application: exp.Application}
+		addresses = append(addresses, address)
+	}
+	a.containers[state.ContainerID] = addresses
+}
+
+// RemoveContainer forgets the endpoints of a container that stopped
+func (a *CrossPoolAuthorizer) RemoveContainer(containerID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeContainer(containerID)
+}
+
+func (a *CrossPoolAuthorizer) removeContainer(containerID string) {
+	for _, address := range a.containers[containerID] {
+		delete(a.exports, address)
+	}
+	delete(a.containers, containerID)
+}
+
+// setPool updates the cross-pool policy of the pool
+func (a *CrossPoolAuthorizer) setPool(p *pool.ResourcePool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pool = p
+}
+
+// Listen implements zzk.Listener2.  It keeps the cross-pool policy of the
+// pool up to date until it is cancelled or the connection fails. (uses a
+// root-based connection)
+func (a *CrossPoolAuthorizer) Listen(cancel <-chan interface{}, conn client.Connection) {
+	pth := zkservice.Base().Pools().ID(a.poolID).Path()
+	logger := plog.WithFields(log.Fields{
+		"poolid": a.poolID,
+		"zkpath": pth,
+	})
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		ok, ev, err := conn.ExistsW(pth, done)
+		if err != nil {
+			logger.WithError(err).Error("Could not watch the resource pool")
+			return
+		}
+
+		if ok {
+			node := &zkservice.PoolNode{ResourcePool: &pool.ResourcePool{}}
+			ev, err = conn.GetW(pth, node, done)
+			if err == client.ErrNoNode {
+				close(done)
+				done = make(chan struct{})
+				continue
+			} else if err != nil {
+				logger.WithError(err).Error("Could not get the resource pool")
+				return
+			}
+			a.setPool(node.ResourcePool)
+			logger.WithField("crosspoolpolicy", node.GetCrossPoolPolicy()).Debug("Updated the cross-pool policy")
+		}
+
+		select {
+		case <-ev:
+		case <-cancel:
+			return
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// Exited implements zzk.Listener2
+func (a *CrossPoolAuthorizer) Exited() {}
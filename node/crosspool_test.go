@@ -0,0 +1,91 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package node
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/auth/mocks"
+	"github.com/control-center/serviced/domain/pool"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func sender(poolID string) *mocks.Identity {
+	id := &mocks.Identity{}
+	id.On("PoolID").Return(poolID)
+	return id
+}
+
+func TestCrossPoolAuthorizer_AuthorizeMux(t *testing.T) {
+	a := NewCrossPoolAuthorizer("backend")
+	a.AddContainer("mariadb-svc", &zkservice.ServiceState{
+		ContainerID: "ctr1",
+		PrivateIP:   "172.17.0.2",
+		Exports: []zkservice.ExportBinding{
+			{Application: "mariadb", Protocol: "tcp", PortNumber: 3306},
+		},
+	})
+	a.AddContainer("rabbitmq-svc", &zkservice.ServiceState{
+		ContainerID: "ctr2",
+		PrivateIP:   "172.17.0.3",
+		Exports: []zkservice.ExportBinding{
+			{Application: "rabbitmq", Protocol: "tcp", PortNumber: 5672},
+		},
+	})
+
+	// every import is allowed until the policy is loaded
+	if err := a.AuthorizeMux(sender("frontend"), "172.17.0.2:3306"); err != nil {
+		t.Fatalf("expected no error before the policy is loaded, got %s", err)
+	}
+
+	a.setPool(&pool.ResourcePool{
+		ID:              "backend",
+		CrossPoolPolicy: pool.CrossPoolRestrict,
+		CrossPoolImports: []pool.CrossPoolImport{
+			{Application: "rabbitmq"},
+		},
+	})
+	for _, tc := range []struct {
+		pool, address string
+		expected      error
+	}{
+		{"backend", "172.17.0.2:3306", nil},
+		{"backend", "172.17.0.9:22", nil},
+		{"frontend", "172.17.0.3:5672", nil},
+		{"frontend", "172.17.0.2:3306", ErrCrossPoolDenied},
+		{"frontend", "172.17.0.9:22", ErrCrossPoolDenied},
+	} {
+		if err := a.AuthorizeMux(sender(tc.pool), tc.address); err != tc.expected {
+			t.Errorf("pool %q address %s: expected %v, got %v", tc.pool, tc.address, tc.expected, err)
+		}
+	}
+
+	// services of the host that authenticate the sender are allowed
+	a.AllowAddress("127.0.0.1:22251")
+	if err := a.AuthorizeMux(sender("frontend"), "127.0.0.1:22251"); err != nil {
+		t.Errorf("expected an allowed address to be allowed, got %v", err)
+	}
+
+	// the endpoints of a stopped container are no longer known
+	a.RemoveContainer("ctr2")
+	if err := a.AuthorizeMux(sender("frontend"), "172.17.0.3:5672"); err != ErrCrossPoolDenied {
+		t.Errorf("expected the endpoint of a stopped container to be denied, got %v", err)
+	}
+	if len(a.exports) != 1 || len(a.containers) != 1 {
+		t.Errorf("expected the endpoints of one container, got %v", a.exports)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 )
 
@@ -97,6 +98,14 @@ type HealthCheckRequest struct {
 	InstanceID int
 }
 
+// HealthCheckSlotRequest asks the host for slots to run a batch of health
+// checks.
+type HealthCheckSlotRequest struct {
+	Slots  int           // number of checks in the batch
+	Budget time.Duration // how long the batch may hold the slots
+	Wait   time.Duration // how long to wait for the slots
+}
+
 type EvaluateServiceRequest struct {
 	ServiceID  string
 	InstanceID int
@@ -129,9 +138,23 @@ type LoadBalancer interface {
 	// cache.
 	ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error
 
+	// GetServiceHealth returns the health checks of the instances of a
+	// service.
+	GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error
+
+	// AcquireHealthCheckSlots waits for slots to run a batch of health checks
+	// and returns the id of the lease that holds them.
+	AcquireHealthCheckSlots(req HealthCheckSlotRequest, leaseID *string) error
+
+	// ReleaseHealthCheckSlots frees the slots of a batch of health checks.
+	ReleaseHealthCheckSlots(leaseID string, unused *int) error
+
 	// GetEvaluatedService returns a service where an evaluation has been executed against all templated properties.
 	GetEvaluatedService(request EvaluateServiceRequest, response *EvaluateServiceResponse) error
 
+	// GetServiceSecrets returns the values of the secrets that the config files of a service instance reference.
+	GetServiceSecrets(request EvaluateServiceRequest, response *map[string]string) error
+
 	// Ping waits for the specified time then returns the server time
 	Ping(waitFor time.Duration, timestamp *time.Time) error
 }
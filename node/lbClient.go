@@ -15,6 +15,7 @@ package node
 
 import (
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/zenoss/glog"
@@ -71,6 +72,12 @@ func (a *LBClient) GetEvaluatedService(request EvaluateServiceRequest, response
 	return a.rpcClient.Call("ControlCenterAgent.GetEvaluatedService", request, response, 0)
 }
 
+// GetServiceSecrets returns the values of the secrets that the config files of a service instance reference.
+func (a *LBClient) GetServiceSecrets(request EvaluateServiceRequest, response *map[string]string) error {
+	glog.V(4).Infof("ControlCenterAgent.GetServiceSecrets()")
+	return a.rpcClient.Call("ControlCenterAgent.GetServiceSecrets", request, response, 0)
+}
+
 // GetProxySnapshotQuiece blocks until there is a snapshot request to the service
 func (a *LBClient) GetProxySnapshotQuiece(serviceId string, snapshotId *string) error {
 	glog.V(4).Infof("ControlCenterAgent.GetProxySnapshotQuiece()")
@@ -96,6 +103,24 @@ func (a *LBClient) ReportInstanceDead(req master.ServiceInstanceRequest, unused
 	return a.rpcClient.Call("ControlCenterAgent.ReportInstanceDead", req, unused, 0)
 }
 
+// GetServiceHealth returns the health checks of the instances of a service.
+func (a *LBClient) GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error {
+	glog.V(4).Infof("ControlCenterAgent.GetServiceHealth()")
+	return a.rpcClient.Call("ControlCenterAgent.GetServiceHealth", serviceID, results, 0)
+}
+
+// AcquireHealthCheckSlots waits for slots to run a batch of health checks.
+func (a *LBClient) AcquireHealthCheckSlots(req HealthCheckSlotRequest, leaseID *string) error {
+	glog.V(4).Infof("ControlCenterAgent.AcquireHealthCheckSlots()")
+	return a.rpcClient.Call("ControlCenterAgent.AcquireHealthCheckSlots", req, leaseID, 0)
+}
+
+// ReleaseHealthCheckSlots frees the slots of a batch of health checks.
+func (a *LBClient) ReleaseHealthCheckSlots(leaseID string, unused *int) error {
+	glog.V(4).Infof("ControlCenterAgent.ReleaseHealthCheckSlots()")
+	return a.rpcClient.Call("ControlCenterAgent.ReleaseHealthCheckSlots", leaseID, unused, 0)
+}
+
 // GetHostID returns the agent's host id
 func (a *LBClient) GetHostID(hostID *string) error {
 	glog.V(4).Infof("ControlCenterAgent.GetHostID()")
@@ -14,41 +14,86 @@ package node
 
 import (
 	"fmt"
-
-	log "github.com/Sirupsen/logrus"
-
+	"path"
+	"sync"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils/cache"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
 )
 
+const (
+	// serviceCacheSize is the number of evaluated instances that the cache
+	// holds, which should cover the instances that a host runs
+	serviceCacheSize = 1000
+	// serviceCacheTTL is how long an unused instance stays in the cache
+	serviceCacheTTL = 10 * time.Minute
+	// serviceCacheMaxAge bounds how long an instance is served from the
+	// cache, because changes to the parents of a service, such as their
+	// context, don't change the node of the service
+	serviceCacheMaxAge = 30 * time.Minute
+)
+
+// ServiceCache caches the evaluated services of the instances that run on
+// the host, so that starting an instance, such as during a mass restart
+// after the master was unavailable, does not need a round trip to the
+// master.  The coordinator node of each cached service is watched, and the
+// instances of a service are evicted when its node changes, such as when the
+// service or its config files are edited or its desired state changes.  A
+// service is not cached while its node can't be watched.
 type ServiceCache struct {
 	master string // the connection string to the master agent
 	cache  cache.LRUCache
 
+	// watchService watches the coordinator node of a service until done is
+	// closed
+	watchService func(serviceID string, done <-chan struct{}) (<-chan client.Event, error)
+
+	mu      sync.Mutex
+	watches map[string]*serviceWatch
+
 	masterClient master.ClientInterface // ONLY USED FOR UNIT-TESTING
 }
 
 type cachedService struct {
-	Service          *service.Service
-	TenantID         string
-	ServiceNamePath  string
+	Service         *service.Service
+	TenantID        string
+	ServiceNamePath string
+	Fetched         time.Time
 }
 
-func NewServiceCache(master string) *ServiceCache {
+// serviceWatch tracks the cached instances of a watched service
+type serviceWatch struct {
+	instances map[int]struct{}
+	done      chan struct{}
+}
+
+// NewServiceCache creates a cache of the services of the pool of the host
+func NewServiceCache(master, poolID string) *ServiceCache {
 	serviceCache := ServiceCache{
-		master: master,
+		master:  master,
+		watches: make(map[string]*serviceWatch),
+	}
+	serviceCache.watchService = func(serviceID string, done <-chan struct{}) (<-chan client.Event, error) {
+		conn, err := zzk.GetLocalConnection(zzk.GeneratePoolPath(poolID))
+		if err != nil {
+			return nil, err
+		}
+		return conn.GetW(path.Join("/services", serviceID), &zkservice.ServiceNode{}, done)
 	}
 
-	maxCacheSize := 60
-	itemTimeToLive := time.Minute * 2
 	cleanupInterval := time.Second * 30
-	serviceCache.cache, _ = cache.NewSimpleLRUCache(maxCacheSize, itemTimeToLive, cleanupInterval, nil)
+	serviceCache.cache, _ = cache.NewSimpleLRUCache(serviceCacheSize, serviceCacheTTL, cleanupInterval, nil)
 	return &serviceCache
 }
 
+// GetEvaluatedService returns the evaluated service of an instance, its
+// tenant id and the path of its name
 func (sc *ServiceCache) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, string, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":  serviceID,
@@ -56,11 +101,20 @@ func (sc *ServiceCache) GetEvaluatedService(serviceID string, instanceID int) (*
 	})
 
 	var item cachedService
-	key := fmt.Sprintf("%s-%d", serviceID, instanceID)
+	key := serviceCacheKey(serviceID, instanceID)
 	data, ok := sc.cache.Get(key)
 	if ok {
 		item, _ = data.(cachedService)
-		return item.Service, item.TenantID, item.ServiceNamePath, nil
+		if time.Since(item.Fetched) < serviceCacheMaxAge {
+			return item.Service, item.TenantID, item.ServiceNamePath, nil
+		}
+	}
+
+	// watch the service before it is fetched, so that a change while it is
+	// fetched evicts it
+	watch, err := sc.watch(serviceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not watch service, so it will not be cached")
 	}
 
 	masterClient, err := sc.getMasterClient()
@@ -70,18 +124,76 @@ func (sc *ServiceCache) GetEvaluatedService(serviceID string, instanceID int) (*
 	}
 	defer masterClient.Close()
 
+	item.Fetched = time.Now()
 	item.Service, item.TenantID, item.ServiceNamePath, err = masterClient.GetEvaluatedService(serviceID, instanceID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get service")
 		return nil, "", "", err
 	}
-	sc.cache.Set(key, item)
+
+	sc.mu.Lock()
+	if watch != nil && sc.watches[serviceID] == watch {
+		watch.instances[instanceID] = struct{}{}
+		sc.cache.Set(key, item)
+	}
+	sc.mu.Unlock()
 	return item.Service, item.TenantID, item.ServiceNamePath, nil
 }
 
+// Invalidate evicts an instance from the cache
 func (sc *ServiceCache) Invalidate(serviceID string, instanceID int) {
-	key := fmt.Sprintf("%s-%d", serviceID, instanceID)
-	sc.cache.Invalidate(key)
+	sc.cache.Invalidate(serviceCacheKey(serviceID, instanceID))
+}
+
+// watch returns the watch of a service, and sets it if it isn't set
+func (sc *ServiceCache) watch(serviceID string) (*serviceWatch, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if w, ok := sc.watches[serviceID]; ok {
+		return w, nil
+	}
+
+	w := &serviceWatch{
+		instances: make(map[int]struct{}),
+		done:      make(chan struct{}),
+	}
+	ev, err := sc.watchService(serviceID, w.done)
+	if err != nil {
+		close(w.done)
+		return nil, err
+	}
+	sc.watches[serviceID] = w
+
+	go func() {
+		<-ev
+		sc.evict(serviceID, w)
+	}()
+	return w, nil
+}
+
+// evict removes the instances of a service from the cache when its node
+// changes
+func (sc *ServiceCache) evict(serviceID string, w *serviceWatch) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.watches[serviceID] != w {
+		return
+	}
+	delete(sc.watches, serviceID)
+	close(w.done)
+	for instanceID := range w.instances {
+		sc.cache.Invalidate(serviceCacheKey(serviceID, instanceID))
+	}
+	plog.WithFields(log.Fields{
+		"serviceid": serviceID,
+		"instances": len(w.instances),
+	}).Debug("Evicted changed service from the cache")
+}
+
+func serviceCacheKey(serviceID string, instanceID int) string {
+	return fmt.Sprintf("%s-%d", serviceID, instanceID)
 }
 
 func (sc *ServiceCache) getMasterClient() (master.ClientInterface, error) {
@@ -0,0 +1,110 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/rpc/master/mocks"
+)
+
+// newTestServiceCache returns a cache whose service watches are triggered
+// by writing to the returned channel
+func newTestServiceCache(masterClient *mocks.ClientInterface, watchErr error) (*ServiceCache, chan client.Event) {
+	sc := NewServiceCache("127.0.0.1:0", "default")
+	sc.masterClient = masterClient
+	events := make(chan client.Event, 1)
+	sc.watchService = func(serviceID string, done <-chan struct{}) (<-chan client.Event, error) {
+		if watchErr != nil {
+			return nil, watchErr
+		}
+		return events, nil
+	}
+	return sc, events
+}
+
+func TestServiceCache_CachesUntilServiceChanges(t *testing.T) {
+	masterClient := &mocks.ClientInterface{}
+	masterClient.On("Close").Return(nil)
+	masterClient.On("GetEvaluatedService", "svc", 0).Return(&service.Service{ID: "svc"}, "tenant", "/app/svc", nil)
+	masterClient.On("GetEvaluatedService", "svc", 1).Return(&service.Service{ID: "svc"}, "tenant", "/app/svc", nil)
+	sc, events := newTestServiceCache(masterClient, nil)
+
+	for i := 0; i < 3; i++ {
+		if _, tenantID, _, err := sc.GetEvaluatedService("svc", 0); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if tenantID != "tenant" {
+			t.Errorf("expected tenant, got %s", tenantID)
+		}
+	}
+	if _, _, _, err := sc.GetEvaluatedService("svc", 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	masterClient.AssertNumberOfCalls(t, "GetEvaluatedService", 2)
+
+	// a change to the service evicts all of its instances
+	events <- client.Event{Type: client.EventNodeDataChanged}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sc.mu.Lock()
+		_, watched := sc.watches["svc"]
+		sc.mu.Unlock()
+		if !watched {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("service was not evicted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sc.GetEvaluatedService("svc", 0)
+	sc.GetEvaluatedService("svc", 1)
+	masterClient.AssertNumberOfCalls(t, "GetEvaluatedService", 4)
+}
+
+func TestServiceCache_NoCacheWithoutWatch(t *testing.T) {
+	masterClient := &mocks.ClientInterface{}
+	masterClient.On("Close").Return(nil)
+	masterClient.On("GetEvaluatedService", "svc", 0).Return(&service.Service{ID: "svc"}, "tenant", "/app/svc", nil)
+	sc, _ := newTestServiceCache(masterClient, client.ErrNoNode)
+
+	sc.GetEvaluatedService("svc", 0)
+	sc.GetEvaluatedService("svc", 0)
+	masterClient.AssertNumberOfCalls(t, "GetEvaluatedService", 2)
+}
+
+func TestServiceCache_MasterError(t *testing.T) {
+	masterClient := &mocks.ClientInterface{}
+	masterClient.On("Close").Return(nil)
+	masterClient.On("GetEvaluatedService", "svc", 0).Return(nil, "", "", errors.New("master unavailable")).Once()
+	masterClient.On("GetEvaluatedService", "svc", 0).Return(&service.Service{ID: "svc"}, "tenant", "/app/svc", nil)
+	sc, _ := newTestServiceCache(masterClient, nil)
+
+	if _, _, _, err := sc.GetEvaluatedService("svc", 0); err == nil {
+		t.Fatalf("expected error")
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, _, err := sc.GetEvaluatedService("svc", 0); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	masterClient.AssertNumberOfCalls(t, "GetEvaluatedService", 2)
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/setting"
+)
+
+// TracingForwarderAddress is where the controller of an instance with tracing
+// receives OTLP/HTTP spans and forwards them to the tracing collector
+const TracingForwarderAddress = "127.0.0.1:4318"
+
+// traceCollector returns the collector that the spans of the service are
+// forwarded to, or an empty string if tracing is off for the service.  The
+// cluster setting for the pool and deployment replaces the delegate's
+// collector.
+func (a *HostAgent) traceCollector(svc *service.Service) string {
+	if !svc.Tracing {
+		return ""
+	}
+	if collector, ok := a.settings.Value(setting.TracingCollector, a.poolID, svc.DeploymentID); ok {
+		return collector
+	}
+	return a.tracingCollector
+}
+
+// tracingEnv returns the environment that points the OpenTelemetry sdk of an
+// instance at the forwarder of its controller.  Variables that the service
+// sets itself are left alone.
+func tracingEnv(svc *service.Service, instanceID int, collector string) []string {
+	env := []string{
+		fmt.Sprintf("SERVICED_TRACING_COLLECTOR=%s", collector),
+		fmt.Sprintf("OTEL_SERVICE_NAME=%s", svc.Name),
+		"OTEL_TRACES_EXPORTER=otlp",
+		"OTEL_EXPORTER_OTLP_PROTOCOL=http/protobuf",
+		fmt.Sprintf("OTEL_EXPORTER_OTLP_ENDPOINT=http://%s", TracingForwarderAddress),
+		fmt.Sprintf("OTEL_RESOURCE_ATTRIBUTES=service.instance.id=%s/%d,serviced.service.id=%s,serviced.deployment.id=%s,serviced.pool.id=%s",
+			svc.ID, instanceID, svc.ID, svc.DeploymentID, svc.PoolID),
+	}
+
+	set := make(map[string]struct{})
+	for _, v := range svc.ContainerEnvironment() {
+		set[strings.SplitN(v, "=", 2)[0]] = struct{}{}
+	}
+	result := []string{}
+	for _, v := range env {
+		if _, ok := set[strings.SplitN(v, "=", 2)[0]]; !ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
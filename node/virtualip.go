@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/utils"
 )
 
 var (
@@ -51,22 +52,38 @@ func (ip *IP) Matches(ipaddr, device string) bool {
 	return ip.Addr == ipaddr && ip.Device == device
 }
 
-// VirtualIPManager manages virtual ip bindings
+// VirtualIPManager manages virtual ip bindings.  IPv4 virtual ips are found
+// by the label of the address, but IPv6 addresses cannot be labeled, so the
+// manager keeps track of the IPv6 virtual ips that it binds.
 type VirtualIPManager struct {
-	label string
-	mu    *sync.Mutex
+	label  string
+	mu     *sync.Mutex
+	ipv6mu *sync.Mutex
+	ipv6   map[string]string // ip prefix to device of bound IPv6 virtual ips
 }
 
 // NewVirtualIPManager instantiates an instance of the VirtualIPManager
 func NewVirtualIPManager(label string) *VirtualIPManager {
 	return &VirtualIPManager{
-		label: label,
-		mu:    &sync.Mutex{},
+		label:  label,
+		mu:     &sync.Mutex{},
+		ipv6mu: &sync.Mutex{},
+		ipv6:   make(map[string]string),
 	}
 }
 
 // GetAll returns all the bound virtual ips
 func (v *VirtualIPManager) GetAll() (ips []IP) {
+	defer func() {
+		v.ipv6mu.Lock()
+		defer v.ipv6mu.Unlock()
+		for ipprefix, device := range v.ipv6 {
+			if ip := v.findIPv6(ipprefix, device); ip != nil {
+				ips = append(ips, *ip)
+			}
+		}
+	}()
+
 	cmd := exec.Command("ip", "-o", "a", "show", "label", fmt.Sprintf("*:%s*", v.label))
 	stdout, _ := cmd.StdoutPipe()
 
@@ -85,6 +102,14 @@ func (v *VirtualIPManager) GetAll() (ips []IP) {
 
 // Find returns the matching binding for the given ip address
 func (v *VirtualIPManager) Find(ipprefix string) *IP {
+	if isIPv6(ipprefix) {
+		v.ipv6mu.Lock()
+		defer v.ipv6mu.Unlock()
+		if device, ok := v.ipv6[ipprefix]; ok {
+			return v.findIPv6(ipprefix, device)
+		}
+		return nil
+	}
 	cmd := exec.Command("ip", "-o", "a", "show", "label", fmt.Sprintf("*:%s*", v.label), "to", ipprefix)
 	output, _ := cmd.CombinedOutput()
 	return v.loadIP(output)
@@ -106,6 +131,9 @@ func (v *VirtualIPManager) Release(ipaddr, device string) error {
 		}).Debug("Could not remove virtual ip")
 		return err
 	}
+	v.ipv6mu.Lock()
+	delete(v.ipv6, ipPrefix(ipaddr))
+	v.ipv6mu.Unlock()
 
 	logger.Debug("Removed virtual ip")
 	return nil
@@ -121,7 +149,15 @@ func (v *VirtualIPManager) Bind(ipaddr, device string) error {
 		"device":    device,
 	})
 
-	cmd := exec.Command("ip", "a", "add", ipaddr, "dev", device, "label", v.nextLabel(device))
+	ipprefix := ipPrefix(ipaddr)
+	var cmd *exec.Cmd
+	if isIPv6(ipprefix) {
+		// IPv6 addresses cannot be labeled, and duplicate address detection
+		// would keep the address from being used until it completes
+		cmd = exec.Command("ip", "a", "add", ipaddr, "dev", device, "nodad")
+	} else {
+		cmd = exec.Command("ip", "a", "add", ipaddr, "dev", device, "label", v.nextLabel(device))
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		logger.WithError(err).WithFields(logrus.Fields{
@@ -130,6 +166,11 @@ func (v *VirtualIPManager) Bind(ipaddr, device string) error {
 		}).Debug("Could not add virtual ip")
 		return err
 	}
+	if isIPv6(ipprefix) {
+		v.ipv6mu.Lock()
+		v.ipv6[ipprefix] = device
+		v.ipv6mu.Unlock()
+	}
 
 	logger.Debug("Added virtual ip")
 	return nil
@@ -148,6 +189,13 @@ func (v *VirtualIPManager) loadIP(b []byte) *IP {
 	}
 }
 
+// findIPv6 returns the binding of an IPv6 address on a device
+func (v *VirtualIPManager) findIPv6(ipprefix, device string) *IP {
+	cmd := exec.Command("ip", "-o", "a", "show", "dev", device, "to", ipprefix)
+	output, _ := cmd.CombinedOutput()
+	return v.loadIP(output)
+}
+
 func (v *VirtualIPManager) nextLabel(device string) string {
 	ips := v.GetAll()
 	var ids []int
@@ -180,8 +228,8 @@ func (a *HostAgent) BindIP(ipprefix, netmask, iface string) error {
 	})
 
 	// calculate the cidr bytes
-	cidr, _ := net.IPMask(net.ParseIP(netmask).To4()).Size()
-	ip := fmt.Sprintf("%s/%d", ipprefix, cidr)
+	ipprefix = utils.CanonicalIP(ipprefix)
+	ip := fmt.Sprintf("%s/%d", ipprefix, netmaskSize(netmask))
 
 	if vip := a.vip.Find(ipprefix); vip != nil {
 		// return if the ips match
@@ -213,7 +261,7 @@ func (a *HostAgent) BindIP(ipprefix, netmask, iface string) error {
 // ReleaseIP releases a virtual ip if hasn't yet been released.
 func (a *HostAgent) ReleaseIP(ipprefix string) error {
 	logger := plog.WithField("ipaddress", ipprefix)
-	if vip := a.vip.Find(ipprefix); vip != nil {
+	if vip := a.vip.Find(utils.CanonicalIP(ipprefix)); vip != nil {
 		if err := a.vip.Release(vip.Addr, vip.Device); err != nil {
 			logger.WithError(err).Error("Could not release virtual ip")
 			return err
@@ -222,3 +270,29 @@ func (a *HostAgent) ReleaseIP(ipprefix string) error {
 
 	return nil
 }
+
+// netmaskSize returns the prefix length of a netmask, which is either an IPv4
+// or IPv6 mask or a prefix length
+func netmaskSize(netmask string) int {
+	if size, err := strconv.Atoi(netmask); err == nil {
+		return size
+	}
+	mask := net.ParseIP(netmask)
+	if mask4 := mask.To4(); mask4 != nil {
+		size, _ := net.IPMask(mask4).Size()
+		return size
+	}
+	size, _ := net.IPMask(mask.To16()).Size()
+	return size
+}
+
+// ipPrefix returns the ip of an ip/prefix length address
+func ipPrefix(ipaddr string) string {
+	return strings.SplitN(ipaddr, "/", 2)[0]
+}
+
+// isIPv6 returns true if the ip is an IPv6 address
+func isIPv6(ip string) bool {
+	ipaddr := net.ParseIP(ip)
+	return ipaddr != nil && ipaddr.To4() == nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// sessionDialTimeout bounds the dial and preface of a new session
+	sessionDialTimeout = 10 * time.Second
+
+	// legacyMuxRetry is how long a mux that refused a session is dialed with
+	// one connection per stream before a session is tried again
+	legacyMuxRetry = 5 * time.Minute
+)
+
+// MuxDialer opens connections to the muxes of remote hosts.  The connections
+// to a mux are streams of one multiplexed session, which is opened on the
+// first dial and opened again if it closes.  Muxes that do not support
+// sessions are dialed with one connection per stream.  Callers write the
+// signed mux header to each connection, as they would to a connection of its
+// own.
+type MuxDialer struct {
+	tlsConfig *tls.Config
+	mu        sync.Mutex
+	muxes     map[string]*muxHost
+}
+
+// muxHost is the session to the mux at an address
+type muxHost struct {
+	sync.Mutex
+	session     *Session
+	legacyUntil time.Time // the mux refused a session; do not try again until
+}
+
+// NewMuxDialer returns a dialer for muxes.  If tlsConfig is not nil, the
+// connections to the muxes are encrypted.
+func NewMuxDialer(tlsConfig *tls.Config) *MuxDialer {
+	return &MuxDialer{
+		tlsConfig: tlsConfig,
+		muxes:     make(map[string]*muxHost),
+	}
+}
+
+// Dial opens a connection to the mux at the address
+func (d *MuxDialer) Dial(network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	host, ok := d.muxes[address]
+	if !ok {
+		host = &muxHost{}
+		d.muxes[address] = host
+	}
+	d.mu.Unlock()
+
+	host.Lock()
+	defer host.Unlock()
+	logger := log.WithFields(logrus.Fields{
+		"muxaddr": address,
+	})
+
+	if time.Now().Before(host.legacyUntil) {
+		return d.dial(network, address)
+	}
+
+	// open a stream on the current session, or on a new session if it closed
+	for i := 0; i < 2; i++ {
+		if host.session == nil || host.session.IsClosed() {
+			conn, err := d.dial(network, address)
+			if err != nil {
+				return nil, err
+			}
+			session, err := NewClientSession(conn, sessionDialTimeout)
+			if err == ErrSessionRefused {
+				conn.Close()
+				logger.Info("Mux does not support sessions; using a connection per stream")
+				host.legacyUntil = time.Now().Add(legacyMuxRetry)
+				return d.dial(network, address)
+			} else if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			logger.Debug("Opened mux session")
+			host.session = session
+		}
+		stream, err := host.session.OpenStream()
+		if err == nil {
+			return stream, nil
+		} else if err != ErrSessionClosed {
+			return nil, err
+		}
+		host.session = nil
+	}
+	return nil, ErrSessionClosed
+}
+
+// Close closes the sessions of the dialer and the streams on them
+func (d *MuxDialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for address, host := range d.muxes {
+		host.Lock()
+		if host.session != nil {
+			host.session.Close()
+		}
+		host.Unlock()
+		delete(d.muxes, address)
+	}
+}
+
+func (d *MuxDialer) dial(network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: sessionDialTimeout, KeepAlive: 3 * time.Minute}
+	if d.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, network, address, d.tlsConfig)
+	}
+	return dialer.Dial(network, address)
+}
@@ -19,10 +19,12 @@ import (
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
 
+	"bytes"
 	"fmt"
 	"io"
 	"net"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -31,12 +33,21 @@ var (
 	log = logging.PackageLogger()
 )
 
+// MuxAuthorizer decides whether the sender of a stream may connect to the
+// container address in the header of the stream.
+type MuxAuthorizer interface {
+	AuthorizeMux(sender auth.Identity, address string) error
+}
+
 // TCPMux is an implementation of tcp muxing RFC 1078.
 type TCPMux struct {
 	listener    net.Listener    // the connection this mux listens on
 	connections chan net.Conn   // stream of accepted connections
 	closing     chan chan error // shutdown noticiation
 	log         *logrus.Entry
+
+	mu         sync.RWMutex
+	authorizer MuxAuthorizer // checks the sender of each stream, nil allows every stream
 }
 
 // NewTCPMux creates a new tcp mux with the given listener. If it succees, it
@@ -60,6 +71,25 @@ func NewTCPMux(listener net.Listener) (mux *TCPMux, err error) {
 	return mux, nil
 }
 
+// SetAuthorizer sets the authorizer that checks the sender of each stream
+// before the mux connects it to its container address.
+func (mux *TCPMux) SetAuthorizer(authorizer MuxAuthorizer) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.authorizer = authorizer
+}
+
+// authorize returns an error if the authorizer rejects the stream
+func (mux *TCPMux) authorize(sender auth.Identity, address string) error {
+	mux.mu.RLock()
+	authorizer := mux.authorizer
+	mux.mu.RUnlock()
+	if authorizer == nil {
+		return nil
+	}
+	return authorizer.AuthorizeMux(sender, address)
+}
+
 func (mux *TCPMux) Close() {
 	mux.log.Debug("Closing TCP multiplexer")
 	close(mux.closing)
@@ -129,11 +159,8 @@ func (mux *TCPMux) loop() {
 	}
 }
 
-// muxConnection takes an inbound connection reads a line from it and
-// then attempts to set up a connection to the service specified by the
-// line. The service is specified in the form "IP:PORT\n". If the connection
-// to the service is sucessful, all traffic continues to be proxied between
-// two connections.
+// muxConnection takes an inbound connection and either serves the streams of
+// a multiplexed session on it, or proxies it as a single stream.
 func (mux *TCPMux) muxConnection(conn net.Conn) {
 
 	log := mux.log.WithFields(logrus.Fields{
@@ -142,8 +169,51 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 	// make sure that we don't block indefinitely
 	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
 
-	// TODO retrieve and validate the identity of the sender
-	addrPacked, _, err := auth.ReadMuxHeader(conn)
+	var magic [3]byte
+	if _, err := io.ReadFull(conn, magic[:]); err != nil {
+		log.WithError(err).Warn("Unable to read mux preface. Closing connection")
+		conn.Close()
+		return
+	}
+	if IsSessionPreface(magic) {
+		session, err := AcceptSession(conn)
+		if err != nil {
+			log.WithError(err).Warn("Unable to accept mux session. Closing connection")
+			conn.Close()
+			return
+		}
+		conn.SetReadDeadline(time.Time{})
+		log.Debug("Accepted mux session")
+		go mux.serveSession(session)
+		return
+	}
+
+	// the magic number belongs to the header of a single stream
+	mux.muxStream(conn, io.MultiReader(bytes.NewReader(magic[:]), conn), log)
+}
+
+// serveSession proxies each stream of a session until the session closes.
+func (mux *TCPMux) serveSession(session *Session) {
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			return
+		}
+		stream.SetReadDeadline(time.Now().Add(time.Second * 5))
+		go mux.muxStream(stream, stream, mux.log.WithFields(logrus.Fields{
+			"remoteaddr": stream.RemoteAddr(),
+			"streamid":   stream.ID(),
+		}))
+	}
+}
+
+// muxStream reads the header of a stream and then attempts to set up a
+// connection to the service specified by the header. If the connection to the
+// service is sucessful, all traffic continues to be proxied between the
+// stream and the connection.
+func (mux *TCPMux) muxStream(conn net.Conn, header io.Reader, log *logrus.Entry) {
+
+	addrPacked, sender, err := auth.ReadMuxHeader(header)
 	if err != nil {
 		log.WithError(err).Warn("Unable to read valid mux header. Closing connection")
 		conn.Close()
@@ -160,7 +230,12 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 		"remoteaddr":    conn.RemoteAddr(),
 		"containeraddr": address,
 	})
-	svc, err := net.Dial("tcp4", address)
+	if err := mux.authorize(sender, address); err != nil {
+		log.WithError(err).WithField("senderpoolid", sender.PoolID()).Warn("Sender may not connect to container address. Closing connection")
+		conn.Close()
+		return
+	}
+	svc, err := net.Dial("tcp", address)
 	if err != nil {
 		log.Debug("Unable to dial container address. Perhaps the container is still starting?")
 		conn.Close()
@@ -161,3 +161,120 @@ func TestTCPMux(t *testing.T) {
 	conn.Close()
 
 }
+
+func TestTCPMuxSession(t *testing.T) {
+
+	// Create a master key pair
+	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadMasterKeysFromPEM(pub, priv)
+
+	dpub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadDelegateKeysFromPEM(pub, priv)
+
+	auth.RefreshToken(func() (string, int64, error) {
+		return auth.CreateJWTIdentity("host", "pool", true, true, dpub, time.Duration(365*24*60*60)*time.Second)
+	}, "")
+
+	target := newEchoListener(t)
+	defer target.Close()
+
+	muxEndpoint, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("could not create tcpmux endpoint: %s", err)
+	}
+	mux, err := NewTCPMux(muxEndpoint)
+	if err != nil {
+		t.Fatalf("did not expect failure creating TCPMux: %s", err)
+	}
+
+	addr, err := utils.PackTCPAddressString(fmt.Sprintf("127.0.0.1:%s", listenerToPort(target.listener)))
+	if err != nil {
+		t.Fail()
+	}
+	token, err := auth.AuthTokenNonBlocking()
+	if err != nil {
+		t.Fail()
+	}
+
+	// every connection is a stream of the same session
+	dialer := NewMuxDialer(nil)
+	defer dialer.Close()
+	muxAddr := fmt.Sprintf("127.0.0.1:%s", listenerToPort(mux.listener))
+	for i := 0; i < 3; i++ {
+		conn, err := dialer.Dial("tcp4", muxAddr)
+		if err != nil {
+			t.Fatalf("could not dial mux: %s", err)
+		}
+		if _, ok := conn.(*Stream); !ok {
+			t.Fatalf("expected a mux stream, got %T", conn)
+		}
+		auth.AddSignedMuxHeader(conn, addr, token)
+		testMsg := fmt.Sprintf("\nhello %d\n", i)
+		conn.Write([]byte(testMsg))
+		buffer := make([]byte, len(testMsg))
+		if _, err := io.ReadFull(conn, buffer); err != nil {
+			t.Fatalf("could not read from stream: %s", err)
+		}
+		if string(buffer) != testMsg {
+			t.Fatalf("got back %+v expected %+v", string(buffer), testMsg)
+		}
+		conn.Close()
+	}
+	if len(dialer.muxes) != 1 {
+		t.Fatalf("expected one mux, got %d", len(dialer.muxes))
+	}
+}
+
+type denyPool string
+
+func (pool denyPool) AuthorizeMux(sender auth.Identity, address string) error {
+	if sender.PoolID() == string(pool) {
+		return fmt.Errorf("pool %s may not connect to %s", pool, address)
+	}
+	return nil
+}
+
+func TestTCPMuxAuthorizer(t *testing.T) {
+
+	// Create a master key pair
+	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadMasterKeysFromPEM(pub, priv)
+
+	dpub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadDelegateKeysFromPEM(pub, priv)
+
+	auth.RefreshToken(func() (string, int64, error) {
+		return auth.CreateJWTIdentity("host", "pool", true, true, dpub, time.Duration(365*24*60*60)*time.Second)
+	}, "")
+
+	target := newEchoListener(t)
+	defer target.Close()
+
+	muxEndpoint, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("could not create tcpmux endpoint: %s", err)
+	}
+	mux, err := NewTCPMux(muxEndpoint)
+	if err != nil {
+		t.Fatalf("did not expect failure creating TCPMux: %s", err)
+	}
+	mux.SetAuthorizer(denyPool("pool"))
+
+	conn := mux.testConnect(t)
+	defer conn.Close()
+	addr, err := utils.PackTCPAddressString(fmt.Sprintf("127.0.0.1:%s", listenerToPort(target.listener)))
+	if err != nil {
+		t.Fail()
+	}
+	token, err := auth.AuthTokenNonBlocking()
+	if err != nil {
+		t.Fail()
+	}
+	auth.AddSignedMuxHeader(conn, addr, token)
+	conn.Write([]byte("\nhello\n"))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(make([]byte, 4096))
+	if ne, ok := err.(net.Error); err == nil || n > 0 || ok && ne.Timeout() {
+		t.Fatalf("expected the mux to close the connection, got %d bytes and error %v", n, err)
+	}
+}
@@ -0,0 +1,736 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+/*
+   A session carries many proxied streams over one connection to the mux, so
+   that a host does not need a connection (and a conntrack entry and file
+   descriptor on both ends) for every proxied connection.
+
+   The client opens the session by sending a preface, which the mux tells
+   apart from the signed header of a single-stream connection by its magic
+   number, and the mux answers with the same preface:
+
+   ----------------------------------------------
+   | Magic number (3 bytes) | Version (1 byte)  |
+   ----------------------------------------------
+
+   After the preface, both ends send frames:
+
+   ---------------------------------------------------------------------------------------
+   | Version (1 byte) | Type (1 byte) | Flags (2 bytes) | Stream ID (4 bytes) | Length (4 bytes) |
+   ---------------------------------------------------------------------------------------
+
+   Data frames are followed by Length bytes of the stream.  Window update
+   frames add Length bytes to the send window of the stream, and carry the
+   SYN, FIN and RST flags that open, half-close and reset streams.  Ping
+   frames carry an opaque value in Length that is echoed back with the ACK
+   flag.  A go away frame closes the session.
+
+   Each stream starts with a receive window of StreamWindowSize bytes.  A
+   sender never has more than the window in flight; the receiver grows the
+   window as the stream is read, so a slow reader only stalls its own stream.
+
+   The first bytes of every stream are the signed mux header of the
+   single-stream protocol, so each stream is authenticated on its own.
+*/
+
+const (
+	// StreamWindowSize is the number of bytes that may be in flight on a
+	// stream before the sender waits for the receiver to read them
+	StreamWindowSize = 256 * 1024
+
+	// MaxSessionStreams is the number of streams that may be open on a
+	// session at the same time
+	MaxSessionStreams = 8192
+
+	sessionVersion   uint8 = 1
+	frameHeaderSize        = 12
+	maxFramePayload        = 64 * 1024
+	acceptBacklog          = 256
+	sessionKeepAlive       = 30 * time.Second
+	sessionTimeout         = 15 * time.Second
+)
+
+const (
+	frameData uint8 = iota
+	frameWindowUpdate
+	framePing
+	frameGoAway
+)
+
+const (
+	flagSYN uint16 = 1 << iota
+	flagACK
+	flagFIN
+	flagRST
+)
+
+var (
+	// ErrSessionClosed is returned when a session or one of its streams is
+	// used after the session has closed
+	ErrSessionClosed = errors.New("mux session closed")
+
+	// ErrSessionRefused is returned when the mux does not answer the preface
+	// of a session, such as when it only supports single-stream connections
+	ErrSessionRefused = errors.New("mux session refused")
+
+	// ErrStreamClosed is returned when a stream is used after it was closed
+	ErrStreamClosed = errors.New("mux stream closed")
+
+	// ErrStreamReset is returned when the other end resets a stream
+	ErrStreamReset = errors.New("mux stream reset")
+
+	// ErrTooManyStreams is returned when a session has no room for another
+	// stream
+	ErrTooManyStreams = errors.New("too many mux streams")
+
+	sessionMagic = [3]byte{'m', 'u', 'x'}
+	frameOrder   = binary.BigEndian
+)
+
+// timeoutError is returned when a deadline of a stream passes
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "mux stream i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// IsSessionPreface returns true if the magic number opens a session.
+func IsSessionPreface(magic [3]byte) bool {
+	return magic == sessionMagic
+}
+
+// Session multiplexes streams over a connection to the mux
+type Session struct {
+	conn   net.Conn
+	client bool
+	log    *logrus.Entry
+
+	mu      sync.Mutex
+	nextID  uint32
+	streams map[uint32]*Stream
+	pings   map[uint32]chan struct{}
+	pingID  uint32
+
+	writeLock sync.Mutex
+	accept    chan *Stream
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClientSession opens a session on a connection to the mux.  It returns
+// ErrSessionRefused if the mux does not answer the preface within the timeout.
+func NewClientSession(conn net.Conn, timeout time.Duration) (*Session, error) {
+	preface := append(sessionMagic[:], sessionVersion)
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(preface); err != nil {
+		return nil, err
+	}
+	reply := make([]byte, len(preface))
+	if _, err := io.ReadFull(conn, reply); err != nil || !bytes.Equal(reply, preface) {
+		return nil, ErrSessionRefused
+	}
+	conn.SetDeadline(time.Time{})
+	return newSession(conn, true), nil
+}
+
+// AcceptSession answers the preface of a session whose magic number has
+// already been read from the connection.
+func AcceptSession(conn net.Conn) (*Session, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(conn, version[:]); err != nil {
+		return nil, err
+	}
+	if version[0] != sessionVersion {
+		return nil, ErrSessionRefused
+	}
+	if _, err := conn.Write(append(sessionMagic[:], sessionVersion)); err != nil {
+		return nil, err
+	}
+	return newSession(conn, false), nil
+}
+
+func newSession(conn net.Conn, client bool) *Session {
+	s := &Session{
+		conn:    conn,
+		client:  client,
+		streams: make(map[uint32]*Stream),
+		pings:   make(map[uint32]chan struct{}),
+		accept:  make(chan *Stream, acceptBacklog),
+		closed:  make(chan struct{}),
+		log: log.WithFields(logrus.Fields{
+			"remoteaddr": conn.RemoteAddr(),
+		}),
+	}
+	// clients open odd streams and the mux opens even streams
+	if client {
+		s.nextID = 1
+	} else {
+		s.nextID = 2
+	}
+	go s.recvLoop()
+	go s.keepalive()
+	return s
+}
+
+// OpenStream opens a new stream on the session
+func (s *Session) OpenStream() (*Stream, error) {
+	s.mu.Lock()
+	if s.IsClosed() {
+		s.mu.Unlock()
+		return nil, ErrSessionClosed
+	}
+	if len(s.streams) >= MaxSessionStreams {
+		s.mu.Unlock()
+		return nil, ErrTooManyStreams
+	}
+	stream := newStream(s, s.nextID)
+	s.nextID += 2
+	s.streams[stream.id] = stream
+	s.mu.Unlock()
+
+	if err := s.sendFrame(frameWindowUpdate, flagSYN, stream.id, 0, nil); err != nil {
+		s.removeStream(stream.id)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// AcceptStream waits for the other end to open a stream
+func (s *Session) AcceptStream() (*Stream, error) {
+	select {
+	case stream := <-s.accept:
+		return stream, nil
+	case <-s.closed:
+		return nil, ErrSessionClosed
+	}
+}
+
+// NumStreams returns the number of open streams
+func (s *Session) NumStreams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.streams)
+}
+
+// IsClosed returns true if the session is closed
+func (s *Session) IsClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the session and resets all of its streams
+func (s *Session) Close() error {
+	s.sendFrame(frameGoAway, 0, 0, 0, nil)
+	s.shutdown()
+	return nil
+}
+
+func (s *Session) shutdown() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.conn.Close()
+		s.mu.Lock()
+		streams := s.streams
+		s.streams = make(map[uint32]*Stream)
+		s.mu.Unlock()
+		for _, stream := range streams {
+			stream.abort(ErrSessionClosed)
+		}
+		s.log.Debug("Closed mux session")
+	})
+}
+
+// Ping sends a ping and waits for the answer
+func (s *Session) Ping(timeout time.Duration) error {
+	ack := make(chan struct{})
+	s.mu.Lock()
+	s.pingID++
+	id := s.pingID
+	s.pings[id] = ack
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pings, id)
+		s.mu.Unlock()
+	}()
+
+	if err := s.sendFrame(framePing, flagSYN, 0, id, nil); err != nil {
+		return err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ack:
+		return nil
+	case <-s.closed:
+		return ErrSessionClosed
+	case <-timer.C:
+		return timeoutError{}
+	}
+}
+
+// keepalive closes the session if the other end stops answering pings
+func (s *Session) keepalive() {
+	ticker := time.NewTicker(sessionKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Ping(sessionTimeout); err != nil {
+				s.log.WithError(err).Warn("Mux session did not answer a ping; closing")
+				s.shutdown()
+				return
+			}
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// sendFrame writes a frame to the connection.  A frame that cannot be written
+// in time closes the session, because the frames after it cannot be sent.
+func (s *Session) sendFrame(typ uint8, flags uint16, id, length uint32, payload []byte) error {
+	var hdr [frameHeaderSize]byte
+	hdr[0] = sessionVersion
+	hdr[1] = typ
+	frameOrder.PutUint16(hdr[2:4], flags)
+	frameOrder.PutUint32(hdr[4:8], id)
+	frameOrder.PutUint32(hdr[8:12], length)
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	if s.IsClosed() {
+		return ErrSessionClosed
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(sessionTimeout))
+	bufs := net.Buffers{hdr[:], payload}
+	if _, err := bufs.WriteTo(s.conn); err != nil {
+		s.log.WithError(err).Debug("Could not write to mux session")
+		s.shutdown()
+		return ErrSessionClosed
+	}
+	return nil
+}
+
+func (s *Session) recvLoop() {
+	defer s.shutdown()
+	var hdr [frameHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(s.conn, hdr[:]); err != nil {
+			if err != io.EOF && !s.IsClosed() {
+				s.log.WithError(err).Debug("Could not read from mux session")
+			}
+			return
+		}
+		if hdr[0] != sessionVersion {
+			s.log.WithField("version", hdr[0]).Warn("Unknown mux session version")
+			return
+		}
+		typ := hdr[1]
+		flags := frameOrder.Uint16(hdr[2:4])
+		id := frameOrder.Uint32(hdr[4:8])
+		length := frameOrder.Uint32(hdr[8:12])
+
+		switch typ {
+		case frameData:
+			if err := s.recvData(id, length); err != nil {
+				return
+			}
+		case frameWindowUpdate:
+			s.recvWindowUpdate(id, flags, length)
+		case framePing:
+			if flags&flagSYN != 0 {
+				go s.sendFrame(framePing, flagACK, 0, length, nil)
+			} else if flags&flagACK != 0 {
+				s.mu.Lock()
+				if ack, ok := s.pings[length]; ok {
+					close(ack)
+					delete(s.pings, length)
+				}
+				s.mu.Unlock()
+			}
+		case frameGoAway:
+			return
+		default:
+			s.log.WithField("type", typ).Warn("Unknown mux session frame")
+			return
+		}
+	}
+}
+
+func (s *Session) recvData(id, length uint32) error {
+	if length > maxFramePayload {
+		s.log.WithField("length", length).Warn("Mux session frame is too large")
+		return io.ErrShortBuffer
+	}
+	s.mu.Lock()
+	stream := s.streams[id]
+	s.mu.Unlock()
+	if stream == nil {
+		// the stream is gone; drop its data and tell the other end
+		if _, err := io.CopyN(ioutil.Discard, s.conn, int64(length)); err != nil {
+			return err
+		}
+		s.sendFrame(frameWindowUpdate, flagRST, id, 0, nil)
+		return nil
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(s.conn, payload); err != nil {
+		return err
+	}
+	if !stream.receive(payload) {
+		s.resetStream(stream)
+	}
+	return nil
+}
+
+func (s *Session) recvWindowUpdate(id uint32, flags uint16, delta uint32) {
+	if flags&flagSYN != 0 {
+		s.mu.Lock()
+		if _, ok := s.streams[id]; ok || s.IsClosed() {
+			s.mu.Unlock()
+			return
+		}
+		if len(s.streams) >= MaxSessionStreams {
+			s.mu.Unlock()
+			s.log.Warn("Too many mux streams; resetting new stream")
+			s.sendFrame(frameWindowUpdate, flagRST, id, 0, nil)
+			return
+		}
+		stream := newStream(s, id)
+		s.streams[id] = stream
+		s.mu.Unlock()
+		select {
+		case s.accept <- stream:
+		default:
+			s.log.Warn("Mux stream backlog is full; resetting new stream")
+			s.resetStream(stream)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	stream := s.streams[id]
+	s.mu.Unlock()
+	if stream == nil {
+		return
+	}
+	if delta > 0 {
+		stream.grow(delta)
+	}
+	if flags&flagRST != 0 {
+		stream.abort(ErrStreamReset)
+		s.removeStream(id)
+	} else if flags&flagFIN != 0 {
+		if stream.remoteClose() {
+			s.removeStream(id)
+		}
+	}
+}
+
+// resetStream aborts a stream on both ends
+func (s *Session) resetStream(stream *Stream) {
+	stream.abort(ErrStreamReset)
+	s.removeStream(stream.id)
+	s.sendFrame(frameWindowUpdate, flagRST, stream.id, 0, nil)
+}
+
+func (s *Session) removeStream(id uint32) {
+	s.mu.Lock()
+	delete(s.streams, id)
+	s.mu.Unlock()
+}
+
+// Stream is a connection multiplexed over a session
+type Stream struct {
+	id      uint32
+	session *Session
+
+	mu            sync.Mutex
+	recvBuf       bytes.Buffer
+	recvWindow    uint32 // bytes the other end may still send
+	consumed      uint32 // bytes read since the last window update
+	sendWindow    uint32 // bytes this end may still send
+	localFIN      bool   // this end will not write any more
+	remoteFIN     bool   // the other end will not write any more
+	closed        bool   // this end will not read or write any more
+	err           error  // the stream was reset or the session closed
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	recvNotify chan struct{}
+	sendNotify chan struct{}
+}
+
+func newStream(session *Session, id uint32) *Stream {
+	return &Stream{
+		id:         id,
+		session:    session,
+		recvWindow: StreamWindowSize,
+		sendWindow: StreamWindowSize,
+		recvNotify: make(chan struct{}, 1),
+		sendNotify: make(chan struct{}, 1),
+	}
+}
+
+// ID returns the id of the stream on its session
+func (s *Stream) ID() uint32 {
+	return s.id
+}
+
+// Read reads data that the other end wrote to the stream
+func (s *Stream) Read(p []byte) (int, error) {
+	for {
+		s.mu.Lock()
+		if s.recvBuf.Len() > 0 {
+			n, _ := s.recvBuf.Read(p)
+			s.consumed += uint32(n)
+			var delta uint32
+			if s.consumed >= StreamWindowSize/2 && !s.remoteFIN && s.err == nil {
+				delta = s.consumed
+				s.consumed = 0
+				s.recvWindow += delta
+			}
+			s.mu.Unlock()
+			if delta > 0 {
+				s.session.sendFrame(frameWindowUpdate, 0, s.id, delta, nil)
+			}
+			return n, nil
+		}
+		switch {
+		case s.err != nil:
+			err := s.err
+			s.mu.Unlock()
+			return 0, err
+		case s.remoteFIN:
+			s.mu.Unlock()
+			return 0, io.EOF
+		case s.closed:
+			s.mu.Unlock()
+			return 0, ErrStreamClosed
+		case s.session.IsClosed():
+			s.mu.Unlock()
+			return 0, ErrSessionClosed
+		}
+		deadline := s.readDeadline
+		s.mu.Unlock()
+
+		if err := s.wait(s.recvNotify, deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Write writes data to the stream, waiting while the send window is full
+func (s *Stream) Write(p []byte) (int, error) {
+	total := 0
+	for total < len(p) {
+		s.mu.Lock()
+		switch {
+		case s.err != nil:
+			err := s.err
+			s.mu.Unlock()
+			return total, err
+		case s.localFIN || s.closed:
+			s.mu.Unlock()
+			return total, ErrStreamClosed
+		case s.session.IsClosed():
+			s.mu.Unlock()
+			return total, ErrSessionClosed
+		}
+		if s.sendWindow == 0 {
+			deadline := s.writeDeadline
+			s.mu.Unlock()
+			if err := s.wait(s.sendNotify, deadline); err != nil {
+				return total, err
+			}
+			continue
+		}
+		n := uint32(len(p) - total)
+		if n > s.sendWindow {
+			n = s.sendWindow
+		}
+		if n > maxFramePayload {
+			n = maxFramePayload
+		}
+		s.sendWindow -= n
+		s.mu.Unlock()
+
+		if err := s.session.sendFrame(frameData, 0, s.id, n, p[total:total+int(n)]); err != nil {
+			return total, err
+		}
+		total += int(n)
+	}
+	return total, nil
+}
+
+// CloseWrite tells the other end that this end will not write any more
+func (s *Stream) CloseWrite() error {
+	s.mu.Lock()
+	if s.localFIN || s.err != nil {
+		s.mu.Unlock()
+		return nil
+	}
+	s.localFIN = true
+	done := s.remoteFIN
+	s.mu.Unlock()
+
+	err := s.session.sendFrame(frameWindowUpdate, flagFIN, s.id, 0, nil)
+	if done {
+		s.session.removeStream(s.id)
+	}
+	return err
+}
+
+// Close closes both directions of the stream.  Data that the other end
+// writes after the stream is closed resets it.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.recvBuf.Reset()
+	s.mu.Unlock()
+	s.notify(s.recvNotify)
+	s.notify(s.sendNotify)
+	return s.CloseWrite()
+}
+
+// LocalAddr returns the local address of the session
+func (s *Stream) LocalAddr() net.Addr {
+	return s.session.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the session
+func (s *Stream) RemoteAddr() net.Addr {
+	return s.session.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the stream
+func (s *Stream) SetDeadline(t time.Time) error {
+	s.SetReadDeadline(t)
+	return s.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline of reads from the stream
+func (s *Stream) SetReadDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.readDeadline = t
+	s.mu.Unlock()
+	s.notify(s.recvNotify)
+	return nil
+}
+
+// SetWriteDeadline sets the deadline of writes to the stream
+func (s *Stream) SetWriteDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.writeDeadline = t
+	s.mu.Unlock()
+	s.notify(s.sendNotify)
+	return nil
+}
+
+// receive buffers data from the other end.  It returns false if the other
+// end sent more than the receive window.
+func (s *Stream) receive(payload []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if uint32(len(payload)) > s.recvWindow || s.closed {
+		return false
+	}
+	s.recvWindow -= uint32(len(payload))
+	s.recvBuf.Write(payload)
+	s.notify(s.recvNotify)
+	return true
+}
+
+// grow adds to the send window of the stream
+func (s *Stream) grow(delta uint32) {
+	s.mu.Lock()
+	s.sendWindow += delta
+	s.mu.Unlock()
+	s.notify(s.sendNotify)
+}
+
+// remoteClose marks that the other end will not write any more.  It returns
+// true if this end will not write any more either.
+func (s *Stream) remoteClose() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remoteFIN = true
+	s.notify(s.recvNotify)
+	return s.localFIN
+}
+
+// abort fails all reads and writes of the stream with the error
+func (s *Stream) abort(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+	s.notify(s.recvNotify)
+	s.notify(s.sendNotify)
+}
+
+func (s *Stream) notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// wait waits for a notification, the deadline or the end of the session.  The
+// caller checks the state of the stream again after a notification.
+func (s *Stream) wait(ch chan struct{}, deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := deadline.Sub(time.Now())
+		if d <= 0 {
+			return timeoutError{}
+		}
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-timeout:
+		return timeoutError{}
+	case <-s.session.closed:
+		return nil
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package proxy
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestSessions returns both ends of a session over a pipe
+func newTestSessions(t *testing.T) (*Session, *Session) {
+	clientConn, serverConn := net.Pipe()
+	serverC := make(chan *Session, 1)
+	go func() {
+		var magic [3]byte
+		if _, err := io.ReadFull(serverConn, magic[:]); err != nil || !IsSessionPreface(magic) {
+			serverConn.Close()
+			serverC <- nil
+			return
+		}
+		session, err := AcceptSession(serverConn)
+		if err != nil {
+			serverConn.Close()
+		}
+		serverC <- session
+	}()
+	client, err := NewClientSession(clientConn, time.Second)
+	if err != nil {
+		t.Fatalf("could not open session: %s", err)
+	}
+	server := <-serverC
+	if server == nil {
+		t.Fatalf("could not accept session")
+	}
+	return client, server
+}
+
+func acceptStream(t *testing.T, session *Session) *Stream {
+	streamC := make(chan *Stream, 1)
+	go func() {
+		stream, _ := session.AcceptStream()
+		streamC <- stream
+	}()
+	select {
+	case stream := <-streamC:
+		if stream == nil {
+			t.Fatalf("could not accept stream")
+		}
+		return stream
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stream was not accepted")
+	}
+	return nil
+}
+
+func TestSession_Streams(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer client.Close()
+	defer server.Close()
+
+	// echo every stream that the client opens
+	go func() {
+		for {
+			stream, err := server.AcceptStream()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(stream, stream)
+				stream.Close()
+			}()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stream, err := client.OpenStream()
+			if err != nil {
+				t.Errorf("could not open stream: %s", err)
+				return
+			}
+			defer stream.Close()
+			msg := bytes.Repeat([]byte(fmt.Sprintf("stream %d;", i)), 10000)
+			go func() {
+				stream.Write(msg)
+				stream.CloseWrite()
+			}()
+			echo, err := ioutil.ReadAll(stream)
+			if err != nil {
+				t.Errorf("could not read stream %d: %s", i, err)
+			} else if !bytes.Equal(echo, msg) {
+				t.Errorf("stream %d echoed %d bytes, expected %d", i, len(echo), len(msg))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSession_FlowControl(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer client.Close()
+	defer server.Close()
+
+	stream, err := client.OpenStream()
+	if err != nil {
+		t.Fatalf("could not open stream: %s", err)
+	}
+	remote := acceptStream(t, server)
+
+	// a writer that is not read from stops at the window
+	written := make(chan int, 1)
+	go func() {
+		n, _ := stream.Write(make([]byte, 2*StreamWindowSize))
+		written <- n
+	}()
+	select {
+	case n := <-written:
+		t.Fatalf("wrote %d bytes past the window", n)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// other streams are not stalled
+	other, err := client.OpenStream()
+	if err != nil {
+		t.Fatalf("could not open stream: %s", err)
+	}
+	otherRemote := acceptStream(t, server)
+	if _, err := other.Write([]byte("hello")); err != nil {
+		t.Fatalf("could not write other stream: %s", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(otherRemote, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("could not read other stream: %q %v", buf, err)
+	}
+
+	// reading the stream opens the window
+	if n, err := io.CopyN(ioutil.Discard, remote, 2*StreamWindowSize); err != nil {
+		t.Fatalf("read %d bytes: %s", n, err)
+	}
+	select {
+	case n := <-written:
+		if n != 2*StreamWindowSize {
+			t.Fatalf("wrote %d bytes, expected %d", n, 2*StreamWindowSize)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("writer did not finish")
+	}
+}
+
+func TestSession_ReadDeadline(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer client.Close()
+	defer server.Close()
+
+	if _, err := client.OpenStream(); err != nil {
+		t.Fatalf("could not open stream: %s", err)
+	}
+	remote := acceptStream(t, server)
+	remote.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, err := remote.Read(make([]byte, 1))
+	if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func TestSession_Close(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer server.Close()
+
+	stream, err := client.OpenStream()
+	if err != nil {
+		t.Fatalf("could not open stream: %s", err)
+	}
+	remote := acceptStream(t, server)
+
+	client.Close()
+	if _, err := stream.Write([]byte("hello")); err == nil {
+		t.Fatalf("expected an error writing to a closed session")
+	}
+	if _, err := client.OpenStream(); err != ErrSessionClosed {
+		t.Fatalf("expected %s, got %v", ErrSessionClosed, err)
+	}
+
+	// the other end sees its streams fail
+	remote.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := remote.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("expected an error reading from a closed session")
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		t.Fatalf("stream did not fail when the session closed")
+	}
+}
+
+func TestSession_Reset(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer client.Close()
+	defer server.Close()
+
+	stream, err := client.OpenStream()
+	if err != nil {
+		t.Fatalf("could not open stream: %s", err)
+	}
+	remote := acceptStream(t, server)
+
+	// writing to a stream that the other end closed resets it
+	remote.Close()
+	stream.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := ioutil.ReadAll(stream); err != nil {
+		t.Fatalf("expected the end of the stream, got %s", err)
+	}
+	if _, err := stream.Write([]byte("hello")); err != nil {
+		t.Fatalf("could not write stream: %s", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for client.NumStreams() > 0 || server.NumStreams() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("streams were not removed: client %d, server %d", client.NumStreams(), server.NumStreams())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := stream.Write([]byte("hello")); err != ErrStreamReset {
+		t.Fatalf("expected %s, got %v", ErrStreamReset, err)
+	}
+}
+
+func TestSession_Refused(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+
+	// a mux that does not support sessions closes the connection
+	go func() {
+		io.ReadFull(serverConn, make([]byte, 4))
+		serverConn.Close()
+	}()
+	if _, err := NewClientSession(clientConn, time.Second); err != ErrSessionRefused {
+		t.Fatalf("expected %s, got %v", ErrSessionRefused, err)
+	}
+}
+
+func TestSession_Ping(t *testing.T) {
+	client, server := newTestSessions(t)
+	defer client.Close()
+	defer server.Close()
+
+	if err := client.Ping(time.Second); err != nil {
+		t.Fatalf("client ping failed: %s", err)
+	}
+	if err := server.Ping(time.Second); err != nil {
+		t.Fatalf("server ping failed: %s", err)
+	}
+}
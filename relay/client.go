@@ -0,0 +1,77 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/utils"
+)
+
+// dialTimeout bounds the connection to a mux and the response of the relay
+const dialTimeout = 30 * time.Second
+
+// Dial opens a relayed stream through the mux at the address to the relay
+// on the port of the host of the mux.  The request is signed with the token
+// of this host.  The stream is returned once the relay has accepted the
+// request.
+func Dial(muxAddress string, useTLS bool, relayPort int, req Request) (net.Conn, error) {
+	token, err := auth.AuthTokenNonBlocking()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	relayAddress, err := utils.PackTCPAddress("127.0.0.1", uint16(relayPort))
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	if useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", muxAddress, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		conn, err = dialer.Dial("tcp", muxAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if err := auth.AddSignedMuxHeader(conn, relayAddress, token); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := auth.AddSignedRelayHeader(conn, payload, token); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := readResponse(conn); err == io.EOF || err == io.ErrUnexpectedEOF {
+		conn.Close()
+		return nil, ErrRelayUnavailable
+	} else if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package relay relays the streams of serviced service attach and logs
+// through the master, for clients that cannot reach the delegate of the
+// instance directly, such as hosts behind a NAT or a port forward.
+//
+// The client opens a stream through the mux of the master to the relay of
+// the master and sends a signed request for the instance.  The relay decides
+// whether the sender may relay by its policy, locates the instance, and opens
+// a stream through the mux of the delegate to the relay of the delegate,
+// which only accepts requests signed by the master and runs the command in
+// the container of the instance.
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+const (
+	// KindAttach runs a command in the container of the instance
+	KindAttach = "attach"
+
+	// KindLogs streams the docker logs of the container of the instance
+	KindLogs = "logs"
+)
+
+const (
+	// PolicyDisabled does not relay any streams
+	PolicyDisabled = "disabled"
+
+	// PolicyAdmin relays the streams of hosts with admin access, such as
+	// the masters
+	PolicyAdmin = "admin"
+
+	// PolicyPool relays the streams of hosts with admin access and of the
+	// hosts in the pool of the instance
+	PolicyPool = "pool"
+
+	// PolicyAll relays the streams of every host of the cluster
+	PolicyAll = "all"
+)
+
+// Policies are the relay policies of the master
+var Policies = []string{PolicyDisabled, PolicyAdmin, PolicyPool, PolicyAll}
+
+var (
+	// ErrRelayDisabled is returned when the master does not relay streams
+	ErrRelayDisabled = errors.New("the master does not relay streams")
+
+	// ErrRelayDenied is returned when the policy of the master does not let
+	// the sender relay streams to the instance
+	ErrRelayDenied = errors.New("host may not relay streams to the instance")
+
+	// ErrNotMaster is returned when a request for an instance is sent to
+	// the relay of a delegate
+	ErrNotMaster = errors.New("only the master relays streams to instances")
+
+	// ErrRelayUnavailable is returned when the mux closes the stream before
+	// the relay responds, such as when the relay is not running
+	ErrRelayUnavailable = errors.New("the relay closed the stream; is it running?")
+
+	// ErrInvalidKind is returned when the request is neither an attach nor
+	// a logs request
+	ErrInvalidKind = errors.New("invalid relay request")
+)
+
+// Request is the stream that the sender asks for.  Clients set the service
+// and instance; the master sets the container of the instance when it
+// forwards the request to the delegate.
+type Request struct {
+	ServiceID   string
+	InstanceID  int
+	ContainerID string
+	Kind        string
+	Command     []string // the command to attach with, or the arguments of docker logs
+	TTY         bool     // allocate a tty for the command
+}
+
+// Location is where an instance runs
+type Location struct {
+	PoolID      string
+	HostID      string
+	HostIP      string
+	ContainerID string
+}
+
+// Locator finds the instances of services for the relay of the master
+type Locator interface {
+	LocateInstance(serviceID string, instanceID int) (*Location, error)
+	// CheckInteractiveAccess returns an error if the hosts of the pool may
+	// not attach to the instances of the service
+	CheckInteractiveAccess(serviceID, poolID string) error
+}
+
+// Authorize returns an error if the policy does not let the sender relay
+// streams to an instance in the pool
+func Authorize(policy string, sender auth.Identity, poolID string) error {
+	switch policy {
+	case PolicyAll:
+		return nil
+	case PolicyPool:
+		if sender.HasAdminAccess() || sender.PoolID() == poolID {
+			return nil
+		}
+	case PolicyAdmin:
+		if sender.HasAdminAccess() {
+			return nil
+		}
+	default:
+		return ErrRelayDisabled
+	}
+	return ErrRelayDenied
+}
+
+// response is the line that the relay writes before the stream starts
+type response struct {
+	Error string
+}
+
+// writeResponse writes the outcome of a request
+func writeResponse(w io.Writer, err error) error {
+	var resp response
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return json.NewEncoder(w).Encode(resp)
+}
+
+// readResponse reads the outcome of a request without reading past it, so
+// that the rest of the stream is left to the caller
+func readResponse(r io.Reader) error {
+	var line bytes.Buffer
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return err
+		}
+		if b[0] == '\n' {
+			break
+		}
+		line.WriteByte(b[0])
+	}
+	var resp response
+	if err := json.Unmarshal(line.Bytes(), &resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
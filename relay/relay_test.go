@@ -0,0 +1,202 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package relay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/auth/mocks"
+)
+
+func identity(poolID string, admin bool) *mocks.Identity {
+	sender := &mocks.Identity{}
+	sender.On("HostID").Return("senderhost")
+	sender.On("PoolID").Return(poolID)
+	sender.On("HasAdminAccess").Return(admin)
+	return sender
+}
+
+func TestAuthorize(t *testing.T) {
+	admin := identity("default", true)
+	member := identity("backend", false)
+	other := identity("frontend", false)
+	for _, tc := range []struct {
+		policy string
+		sender auth.Identity
+		err    error
+	}{
+		{PolicyDisabled, admin, ErrRelayDisabled},
+		{"", admin, ErrRelayDisabled},
+		{PolicyAdmin, admin, nil},
+		{PolicyAdmin, member, ErrRelayDenied},
+		{PolicyPool, admin, nil},
+		{PolicyPool, member, nil},
+		{PolicyPool, other, ErrRelayDenied},
+		{PolicyAll, other, nil},
+	} {
+		if err := Authorize(tc.policy, tc.sender, "backend"); err != tc.err {
+			t.Errorf("policy %s, pool %s: expected %v, got %v", tc.policy, tc.sender.PoolID(), tc.err, err)
+		}
+	}
+}
+
+func TestResponse(t *testing.T) {
+	r, w := io.Pipe()
+	go func() {
+		writeResponse(w, nil)
+		writeResponse(w, errors.New("no such instance"))
+		w.Write([]byte("stream"))
+		w.Close()
+	}()
+	if err := readResponse(r); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+	if err := readResponse(r); err == nil || err.Error() != "no such instance" {
+		t.Errorf("expected no such instance, got %v", err)
+	}
+	rest, _ := ioutil.ReadAll(r)
+	if string(rest) != "stream" {
+		t.Errorf("expected the rest of the stream, got %q", rest)
+	}
+}
+
+type testLocator map[string]*Location
+
+func (l testLocator) LocateInstance(serviceID string, instanceID int) (*Location, error) {
+	loc, ok := l[serviceID]
+	if !ok {
+		return nil, errors.New("no such instance")
+	}
+	return loc, nil
+}
+
+func (l testLocator) CheckInteractiveAccess(serviceID, poolID string) error {
+	if poolID == "support" {
+		return errors.New("no interactive access")
+	}
+	return nil
+}
+
+// serve handles the request from the sender on a new stream, and returns the
+// end of the client
+func serve(s *Server, sender auth.Identity, req Request) net.Conn {
+	payload, _ := json.Marshal(req)
+	s.readReq = func(io.Reader) (auth.Identity, []byte, error) {
+		return sender, payload, nil
+	}
+	client, server := net.Pipe()
+	go s.handle(server)
+	return client
+}
+
+func TestServerRelay(t *testing.T) {
+	var dialed string
+	var forwarded Request
+	s := NewServer(ServerOptions{
+		Port:    22251,
+		MuxPort: 22250,
+		Policy:  PolicyPool,
+		Locator: testLocator{"svc": {PoolID: "backend", HostID: "delegate", HostIP: "10.0.0.5", ContainerID: "ctr"}},
+	})
+	s.dial = func(muxAddress string, req Request) (net.Conn, error) {
+		dialed, forwarded = muxAddress, req
+		local, remote := net.Pipe()
+		go func() {
+			// echo the stream like an attached shell
+			io.Copy(remote, remote)
+			remote.Close()
+		}()
+		return local, nil
+	}
+
+	conn := serve(s, identity("backend", false), Request{ServiceID: "svc", InstanceID: 1, Kind: KindAttach, Command: []string{"ls"}})
+	defer conn.Close()
+	if err := readResponse(conn); err != nil {
+		t.Fatalf("expected the relay to accept the request, got %s", err)
+	}
+	if dialed != "10.0.0.5:22250" {
+		t.Errorf("expected the mux of the delegate, got %s", dialed)
+	}
+	if forwarded.ContainerID != "ctr" || forwarded.ServiceID != "svc" || forwarded.Command[0] != "ls" {
+		t.Errorf("unexpected forwarded request %+v", forwarded)
+	}
+	conn.Write([]byte("hello\n"))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "hello\n" {
+		t.Errorf("expected the stream to be relayed, got %q, %v", line, err)
+	}
+}
+
+func TestServerRelayErrors(t *testing.T) {
+	locator := testLocator{"svc": {PoolID: "backend", HostIP: "10.0.0.5", ContainerID: "ctr"}}
+	for _, tc := range []struct {
+		name    string
+		opts    ServerOptions
+		sender  auth.Identity
+		req     Request
+		errText string
+	}{
+		{"policy", ServerOptions{Policy: PolicyPool, Locator: locator}, identity("frontend", false), Request{ServiceID: "svc", Kind: KindAttach}, ErrRelayDenied.Error()},
+		{"interactive", ServerOptions{Policy: PolicyAll, Locator: locator}, identity("support", false), Request{ServiceID: "svc", Kind: KindAttach}, "no interactive access"},
+		{"disabled", ServerOptions{Policy: PolicyDisabled, Locator: locator}, identity("backend", true), Request{ServiceID: "svc", Kind: KindLogs}, ErrRelayDisabled.Error()},
+		{"delegate", ServerOptions{Policy: PolicyAll}, identity("backend", true), Request{ServiceID: "svc", Kind: KindLogs}, ErrNotMaster.Error()},
+		{"instance", ServerOptions{Policy: PolicyAll, Locator: locator}, identity("backend", true), Request{ServiceID: "other", Kind: KindLogs}, "no such instance"},
+		{"kind", ServerOptions{Policy: PolicyAll, Locator: locator}, identity("backend", true), Request{ServiceID: "svc", Kind: "exec"}, ErrInvalidKind.Error()},
+		{"container", ServerOptions{}, identity("backend", false), Request{ContainerID: "ctr", Kind: KindAttach}, ErrRelayDenied.Error()},
+	} {
+		s := NewServer(tc.opts)
+		s.dial = func(string, Request) (net.Conn, error) {
+			t.Errorf("%s: unexpected dial", tc.name)
+			return nil, errors.New("unexpected dial")
+		}
+		conn := serve(s, tc.sender, tc.req)
+		if err := readResponse(conn); err == nil || err.Error() != tc.errText {
+			t.Errorf("%s: expected %q, got %v", tc.name, tc.errText, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestServerRun(t *testing.T) {
+	var ran Request
+	s := NewServer(ServerOptions{})
+	s.run = func(conn net.Conn, req Request) error {
+		ran = req
+		writeResponse(conn, nil)
+		conn.Write([]byte("logs\n"))
+		return nil
+	}
+	conn := serve(s, identity("default", true), Request{ContainerID: "ctr", Kind: KindLogs, Command: []string{"--tail", "10"}})
+	defer conn.Close()
+	if err := readResponse(conn); err != nil {
+		t.Fatalf("expected the request to run, got %s", err)
+	}
+	line, _ := bufio.NewReader(conn).ReadString('\n')
+	if line != "logs\n" {
+		t.Errorf("expected the output of the command, got %q", line)
+	}
+	if ran.ContainerID != "ctr" || len(ran.Command) != 2 {
+		t.Errorf("unexpected request %+v", ran)
+	}
+}
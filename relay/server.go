@@ -0,0 +1,240 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/commons/docker"
+	"github.com/control-center/serviced/proxy"
+)
+
+// headerTimeout bounds the read of the signed request of a stream
+const headerTimeout = 10 * time.Second
+
+// ServerOptions configures the relay of a host
+type ServerOptions struct {
+	Port    int     // port of the relay on every host, which the mux connects streams to
+	MuxPort int     // port of the muxes of the delegates
+	UseTLS  bool    // whether the muxes of the delegates use TLS
+	Policy  string  // which hosts may relay streams through the master
+	Locator Locator // finds instances on the master, nil on delegates
+}
+
+// Server is the relay of a host.  On the master, it relays the streams of
+// clients to the relays of the delegates; on every host, it runs the
+// commands that the master asks for in the containers of the host.
+type Server struct {
+	opts     ServerOptions
+	dial     func(muxAddress string, req Request) (net.Conn, error)
+	run      func(conn net.Conn, req Request) error
+	readReq  func(r io.Reader) (auth.Identity, []byte, error)
+	mu       sync.Mutex
+	listener net.Listener
+}
+
+// NewServer returns the relay of a host
+func NewServer(opts ServerOptions) *Server {
+	s := &Server{opts: opts, readReq: auth.ReadRelayHeader}
+	s.dial = func(muxAddress string, req Request) (net.Conn, error) {
+		return Dial(muxAddress, s.opts.UseTLS, s.opts.Port, req)
+	}
+	s.run = runInContainer
+	return s
+}
+
+// Listen accepts streams at the address until the relay is closed.  The
+// address should be on the loopback interface; streams of other hosts come
+// through the mux.
+func (s *Server) Listen(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+	go s.serve(listener)
+	return nil
+}
+
+func (s *Server) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			plog.WithError(err).Debug("Stopped relaying streams")
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops accepting streams
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		s.listener.Close()
+		s.listener = nil
+	}
+}
+
+// handle reads the request of a stream and relays it, or runs it if it is
+// for a container of this host
+func (s *Server) handle(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(headerTimeout))
+	sender, payload, err := s.readReq(conn)
+	if err != nil {
+		plog.WithField("remoteaddr", conn.RemoteAddr()).WithError(err).Warn("Unable to read valid relay request. Closing connection")
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	var req Request
+	if err := json.Unmarshal(payload, &req); err != nil {
+		writeResponse(conn, err)
+		conn.Close()
+		return
+	}
+	logger := plog.WithFields(log.Fields{
+		"senderhostid": sender.HostID(),
+		"serviceid":    req.ServiceID,
+		"instanceid":   req.InstanceID,
+		"kind":         req.Kind,
+	})
+	if req.Kind != KindAttach && req.Kind != KindLogs {
+		writeResponse(conn, ErrInvalidKind)
+		conn.Close()
+		return
+	}
+
+	if req.ContainerID != "" {
+		// only the master may run commands in the containers of this host
+		if !sender.HasAdminAccess() {
+			logger.Warn("Denied relay request that was not sent by a master")
+			writeResponse(conn, ErrRelayDenied)
+			conn.Close()
+			return
+		}
+		logger = logger.WithField("containerid", req.ContainerID)
+		logger.Debug("Running relayed request")
+		if err := s.run(conn, req); err != nil {
+			logger.WithError(err).Debug("Relayed request failed")
+		}
+		conn.Close()
+		return
+	}
+
+	remote, err := s.relay(sender, req, logger)
+	if err != nil {
+		writeResponse(conn, err)
+		conn.Close()
+		return
+	}
+	if err := writeResponse(conn, nil); err != nil {
+		remote.Close()
+		conn.Close()
+		return
+	}
+	proxy.ProxyLoop(conn, remote, make(chan bool))
+	logger.Debug("Finished relaying stream")
+}
+
+// relay opens a stream to the relay of the delegate of the instance, if the
+// policy lets the sender relay
+func (s *Server) relay(sender auth.Identity, req Request, logger *log.Entry) (net.Conn, error) {
+	if s.opts.Locator == nil {
+		return nil, ErrNotMaster
+	} else if s.opts.Policy == PolicyDisabled {
+		return nil, ErrRelayDisabled
+	}
+	loc, err := s.opts.Locator.LocateInstance(req.ServiceID, req.InstanceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not locate instance")
+		return nil, err
+	}
+	logger = logger.WithFields(log.Fields{
+		"poolid": loc.PoolID,
+		"hostid": loc.HostID,
+	})
+	if err := Authorize(s.opts.Policy, sender, loc.PoolID); err != nil {
+		logger.WithField("senderpoolid", sender.PoolID()).WithError(err).Warn("Denied relay request")
+		return nil, err
+	}
+	if req.Kind == KindAttach {
+		if err := s.opts.Locator.CheckInteractiveAccess(req.ServiceID, sender.PoolID()); err != nil {
+			logger.WithField("senderpoolid", sender.PoolID()).WithError(err).Warn("Denied interactive relay request")
+			return nil, err
+		}
+	}
+	req.ContainerID = loc.ContainerID
+	remote, err := s.dial(net.JoinHostPort(loc.HostIP, strconv.Itoa(s.opts.MuxPort)), req)
+	if err != nil {
+		logger.WithError(err).Warn("Could not relay stream to the delegate")
+		return nil, err
+	}
+	logger.Info("Relaying stream to instance")
+	return remote, nil
+}
+
+// runInContainer runs the request in its container with the stream attached
+func runInContainer(conn net.Conn, req Request) error {
+	ctr, err := docker.FindContainer(req.ContainerID)
+	if err != nil {
+		writeResponse(conn, err)
+		return err
+	}
+	switch req.Kind {
+	case KindAttach:
+		command := req.Command
+		if len(command) == 0 {
+			command = []string{"/bin/bash"}
+		}
+		if err := writeResponse(conn, nil); err != nil {
+			return err
+		}
+		return ctr.Exec(command, req.TTY, conn, conn, conn)
+	case KindLogs:
+		args := append([]string{"logs"}, req.Command...)
+		cmd := exec.Command("docker", append(args, ctr.ID)...)
+		cmd.Stdout = conn
+		cmd.Stderr = conn
+		if err := writeResponse(conn, nil); err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			fmt.Fprintln(conn, err)
+			return err
+		}
+		// the client does not write to the stream, so a read returns once
+		// it has gone away, such as while following the logs
+		go func() {
+			io.Copy(ioutil.Discard, conn)
+			cmd.Process.Kill()
+		}()
+		return cmd.Wait()
+	}
+	return ErrInvalidKind
+}
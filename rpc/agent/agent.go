@@ -18,10 +18,12 @@ import (
 	"os/exec"
 	"time"
 
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/logging"
+	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/zzk"
 	"github.com/Sirupsen/logrus"
 )
@@ -46,10 +48,11 @@ type AgentServer struct {
 //BuildHostRequest request to build a new host. IP and IPResources will be validated to ensure they exist
 //on the host. If IPResources is not set and IPResource using the IP parameter will be used
 type BuildHostRequest struct {
-	IP     string // IP for the host
-	Port   int    // Port to contact the host on
-	PoolID string // Pool to set on host
-	Memory string // Memory allotted to this host
+	IP        string // IP for the host
+	Port      int    // Port to contact the host on
+	PoolID    string // Pool to set on host
+	Memory    string // Memory allotted to this host
+	Benchmark bool   // Benchmark the disk, network, and cpu of the host
 }
 
 // BuildHost creates a Host object from the current host.
@@ -67,11 +70,49 @@ func (a *AgentServer) BuildHost(request BuildHostRequest, hostResponse *host.Hos
 		"ipcount": len(a.staticIPs),
 	}).Info("Built Host record")
 	if h != nil {
+		if request.Benchmark {
+			if h.Benchmark, err = benchmark(); err != nil {
+				plog.WithError(err).Warn("Could not benchmark host")
+				return err
+			}
+		}
 		*hostResponse = *h
 	}
 	return nil
 }
 
+// benchmark measures the disk throughput and latency to the DFS mount path,
+// the network bandwidth to the master, and the cpu of the host.
+func benchmark() (*host.Benchmark, error) {
+	options := config.GetOptions()
+	b := &host.Benchmark{RanAt: time.Now().UTC()}
+
+	var err error
+	if b.DiskThroughput, b.DiskLatency, err = host.BenchmarkDisk(options.VolumesPath, host.BenchmarkDiskSize); err != nil {
+		return nil, fmt.Errorf("could not benchmark disk at %s: %s", options.VolumesPath, err)
+	}
+
+	if options.Endpoint != "" {
+		client, err := master.NewClient(options.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to master at %s: %s", options.Endpoint, err)
+		}
+		if b.NetworkBandwidth, err = client.BenchmarkNetwork(host.BenchmarkNetworkSize); err != nil {
+			return nil, fmt.Errorf("could not benchmark network to master at %s: %s", options.Endpoint, err)
+		}
+	}
+
+	b.CPUScore = host.BenchmarkCPU()
+
+	plog.WithFields(logrus.Fields{
+		"diskthroughput":   b.DiskThroughput,
+		"disklatency":      b.DiskLatency,
+		"networkbandwidth": b.NetworkBandwidth,
+		"cpuscore":         b.CPUScore,
+	}).Info("Benchmarked host")
+	return b, nil
+}
+
 // GetDockerLogs returns the last 2000 lines of logs from the docker container
 func (a *AgentServer) GetDockerLogs(dockerID string, logs *string) error {
 	cmd := exec.Command("docker", "logs", "--tail=2000", dockerID)
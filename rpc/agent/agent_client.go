@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/rpc/rpcutils"
 )
 
@@ -59,6 +60,28 @@ func (c *Client) GetDockerLogs(dockerID string) (string, error) {
 	return logs, err
 }
 
+// HashContainerFiles returns the sha256 sum of each file that exists inside
+// the container, keyed by filename.
+func (c *Client) HashContainerFiles(containerID string, filenames []string) (map[string]string, error) {
+	req := ContainerFilesRequest{
+		ContainerID: containerID,
+		Filenames:   filenames,
+	}
+	hashes := make(map[string]string)
+	err := c.rpcClient.Call("Agent.HashContainerFiles", req, &hashes, 0)
+	return hashes, err
+}
+
+// WriteContainerFiles writes files inside the container with their owner and
+// permissions.
+func (c *Client) WriteContainerFiles(containerID string, files []servicedefinition.ConfigFile) error {
+	req := WriteContainerFilesRequest{
+		ContainerID: containerID,
+		Files:       files,
+	}
+	return c.rpcClient.Call("Agent.WriteContainerFiles", req, new(int), 0)
+}
+
 // PullImage pulls the image from the provided registry and returns the local
 // image tag.
 func (c *Client) PullImage(registry, image string, timeout time.Duration) (string, error) {
@@ -71,3 +94,22 @@ func (c *Client) PullImage(registry, image string, timeout time.Duration) (strin
 	err := c.rpcClient.Call("Agent.PullImage", req, &imageTag, 0)
 	return imageTag, err
 }
+
+// CollectProfiles collects pprof profiles from the agent process, keyed by
+// profile name.
+func (c *Client) CollectProfiles(profiles []string, duration time.Duration) (map[string][]byte, error) {
+	req := ProfileRequest{
+		Profiles: profiles,
+		Duration: duration,
+	}
+	result := make(map[string][]byte)
+	err := c.rpcClient.Call("Agent.CollectProfiles", req, &result, 0)
+	return result, err
+}
+
+// ApplyDelegateProfile applies a version of the profile of the agent's pool
+func (c *Client) ApplyDelegateProfile(req DelegateProfileRequest) (DelegateProfileResult, error) {
+	var result DelegateProfileResult
+	err := c.rpcClient.Call("Agent.ApplyDelegateProfile", req, &result, 0)
+	return result, err
+}
@@ -16,9 +16,12 @@
 package agent
 
 import (
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/utils"
 
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -109,3 +112,39 @@ func TestGetInfo(t *testing.T) {
 		t.Fatalf("Unexpected error %v", err)
 	}
 }
+
+func TestBuildHostBenchmark(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "agent")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	options := config.GetOptions()
+	defer config.LoadOptions(options)
+	testOptions := options
+	testOptions.VolumesPath = tmpdir
+	testOptions.Endpoint = ""
+	config.LoadOptions(testOptions)
+
+	agent := NewServer([]string{})
+	h := host.New()
+	request := BuildHostRequest{PoolID: "testpool", Benchmark: true}
+	if err := agent.BuildHost(request, h); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if h.Benchmark == nil {
+		t.Fatalf("Expected a benchmark")
+	}
+	if h.Benchmark.DiskThroughput == 0 || h.Benchmark.CPUScore == 0 {
+		t.Errorf("Unexpected benchmark %+v", h.Benchmark)
+	}
+
+	// Test that the benchmark fails if the DFS mount path does not exist.
+	testOptions.VolumesPath = tmpdir + "/missing"
+	config.LoadOptions(testOptions)
+	h = host.New()
+	if err := agent.BuildHost(request, h); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os/exec"
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/dfs/docker"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	dockerclient "github.com/fsouza/go-dockerclient"
+)
+
+// ContainerFilesRequest names files inside a container
+type ContainerFilesRequest struct {
+	ContainerID string
+	Filenames   []string
+}
+
+// WriteContainerFilesRequest carries files to write inside a container
+type WriteContainerFilesRequest struct {
+	ContainerID string
+	Files       []servicedefinition.ConfigFile
+}
+
+// HashContainerFiles returns the sha256 sum of each requested file that
+// exists inside the container, keyed by filename.
+func (a *AgentServer) HashContainerFiles(req ContainerFilesRequest, hashes *map[string]string) error {
+	logger := plog.WithField("containerid", req.ContainerID)
+
+	dc, err := dockerclient.NewClient(docker.DefaultSocket)
+	if err != nil {
+		logger.WithError(err).Error("Could not connect to docker client")
+		return err
+	}
+
+	*hashes = make(map[string]string)
+	for _, filename := range req.Filenames {
+		buf := &bytes.Buffer{}
+		opts := dockerclient.DownloadFromContainerOptions{OutputStream: buf, Path: filename}
+		if err := dc.DownloadFromContainer(req.ContainerID, opts); err != nil {
+			if e, ok := err.(*dockerclient.Error); ok && e.Status == http.StatusNotFound {
+				continue
+			}
+			logger.WithField("filename", filename).WithError(err).Error("Could not download file from container")
+			return err
+		}
+
+		// the archive holds the file itself as its first entry
+		rd := tar.NewReader(buf)
+		if _, err := rd.Next(); err != nil {
+			logger.WithField("filename", filename).WithError(err).Error("Could not read file archive from container")
+			return err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, rd); err != nil {
+			logger.WithField("filename", filename).WithError(err).Error("Could not read file from container")
+			return err
+		}
+		(*hashes)[filename] = hex.EncodeToString(h.Sum(nil))
+	}
+	return nil
+}
+
+// WriteContainerFiles writes files inside the container the way the container
+// controller writes config files, including their owner and permissions.
+func (a *AgentServer) WriteContainerFiles(req WriteContainerFilesRequest, unused *int) error {
+	logger := plog.WithField("containerid", req.ContainerID)
+
+	dc, err := dockerclient.NewClient(docker.DefaultSocket)
+	if err != nil {
+		logger.WithError(err).Error("Could not connect to docker client")
+		return err
+	}
+
+	for _, file := range req.Files {
+		logger := logger.WithField("filename", file.Filename)
+		dir := path.Dir(file.Filename)
+		if output, err := exec.Command("docker", "exec", req.ContainerID, "mkdir", "-p", dir).CombinedOutput(); err != nil {
+			logger.WithError(err).WithField("output", string(output)).Error("Could not create directory in container")
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		wr := tar.NewWriter(buf)
+		hdr := &tar.Header{
+			Name:    path.Base(file.Filename),
+			Mode:    0664,
+			Size:    int64(len(file.Content)),
+			ModTime: time.Now(),
+		}
+		if err := wr.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := wr.Write([]byte(file.Content)); err != nil {
+			return err
+		}
+		if err := wr.Close(); err != nil {
+			return err
+		}
+		opts := dockerclient.UploadToContainerOptions{InputStream: buf, Path: dir}
+		if err := dc.UploadToContainer(req.ContainerID, opts); err != nil {
+			logger.WithError(err).Error("Could not upload file to container")
+			return err
+		}
+
+		if file.Owner != "" {
+			if output, err := exec.Command("docker", "exec", req.ContainerID, "chown", file.Owner, file.Filename).CombinedOutput(); err != nil {
+				logger.WithError(err).WithField("output", string(output)).Error("Could not change the owner of file in container")
+				return err
+			}
+		}
+		if file.Permissions != "" {
+			if output, err := exec.Command("docker", "exec", req.ContainerID, "chmod", file.Permissions, file.Filename).CombinedOutput(); err != nil {
+				logger.WithError(err).WithField("output", string(output)).Error("Could not change the permissions of file in container")
+				return err
+			}
+		}
+		logger.WithField("owner", file.Owner).Info("Wrote config file in container")
+	}
+	return nil
+}
+
+// ContainerFilesClient reaches the files inside containers through the agent
+// of their host
+type ContainerFilesClient struct{}
+
+// NewContainerFilesClient returns a new ContainerFilesClient
+func NewContainerFilesClient() *ContainerFilesClient {
+	return &ContainerFilesClient{}
+}
+
+// HashContainerFiles returns the sha256 sum of each file that exists inside a
+// container on the agent at the given address.
+func (c *ContainerFilesClient) HashContainerFiles(address, containerID string, filenames []string) (map[string]string, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.HashContainerFiles(containerID, filenames)
+}
+
+// WriteContainerFiles writes files inside a container on the agent at the
+// given address.
+func (c *ContainerFilesClient) WriteContainerFiles(address, containerID string, files []servicedefinition.ConfigFile) error {
+	client, err := NewClient(address)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.WriteContainerFiles(containerID, files)
+}
@@ -0,0 +1,233 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/logging"
+)
+
+// DelegateProfileFile is the file that the agent writes the options of the
+// profile of its pool to.  The service unit of serviced reads it after
+// /etc/default/serviced, so that the options of the profile override the
+// options of the host.
+var DelegateProfileFile = "/etc/default/serviced-profile"
+
+const (
+	profileHeader = "# Managed by serviced; edit the delegate profile of the pool instead"
+	profilePrefix = "# profile: "
+	versionPrefix = "# version: "
+)
+
+// DelegateProfileRequest is a version of the profile of the agent's pool.
+// An empty profile removes the options of the previous profile.
+type DelegateProfileRequest struct {
+	Profile string
+	Version int
+	Options map[string]string
+}
+
+// DelegateProfileResult is the outcome of applying a profile on the agent
+type DelegateProfileResult struct {
+	Version int      // Version of the profile that the agent applied
+	Restart []string // Changed options that take effect when the delegate restarts
+}
+
+// ApplyDelegateProfile writes the options of the profile for the delegate to
+// read when it restarts, and applies the log level right away.
+func (a *AgentServer) ApplyDelegateProfile(req DelegateProfileRequest, result *DelegateProfileResult) error {
+	res, err := ApplyDelegateProfile(DelegateProfileFile, req)
+	if err != nil {
+		return err
+	}
+	*result = res
+	return nil
+}
+
+// ApplyDelegateProfile writes the options of a profile to filename and
+// applies the log level to the running process.  It returns the options that
+// changed and only take effect when the delegate restarts.
+func ApplyDelegateProfile(filename string, req DelegateProfileRequest) (DelegateProfileResult, error) {
+	logger := plog.WithFields(logrus.Fields{
+		"profile":  req.Profile,
+		"version":  req.Version,
+		"filename": filename,
+	})
+
+	current, err := ReadDelegateProfile(filename)
+	if err != nil {
+		logger.WithError(err).Error("Could not read the current delegate profile")
+		return DelegateProfileResult{}, err
+	}
+	result := DelegateProfileResult{Version: req.Version}
+	if current.Profile == req.Profile && current.Version == req.Version {
+		logger.Debug("Delegate profile is up to date")
+		return result, nil
+	}
+
+	if err := writeDelegateProfile(filename, req); err != nil {
+		logger.WithError(err).Error("Could not write the delegate profile")
+		return DelegateProfileResult{}, err
+	}
+
+	for _, name := range changedOptions(current.Options, req.Options) {
+		value, ok := req.Options[name]
+		if name != delegateprofile.LogLevel || !ok {
+			// the other options, and a removed log level, take effect when
+			// the delegate restarts
+			result.Restart = append(result.Restart, name)
+			continue
+		}
+		verbosity, err := strconv.Atoi(value)
+		if err != nil {
+			logger.WithError(err).Warn("Could not apply the log level of the delegate profile")
+			continue
+		}
+		setVerbosity(verbosity)
+	}
+	logger.WithField("restart", result.Restart).Info("Applied delegate profile")
+	return result, nil
+}
+
+// ReadDelegateProfile reads the profile that was last written to filename.
+// It returns an empty profile if the file does not exist.
+func ReadDelegateProfile(filename string) (DelegateProfileRequest, error) {
+	req := DelegateProfileRequest{Options: make(map[string]string)}
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return req, nil
+	} else if err != nil {
+		return req, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, profilePrefix):
+			req.Profile = strings.TrimPrefix(line, profilePrefix)
+		case strings.HasPrefix(line, versionPrefix):
+			req.Version, _ = strconv.Atoi(strings.TrimPrefix(line, versionPrefix))
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+				req.Options[parts[0]] = parts[1]
+			}
+		}
+	}
+	return req, scanner.Err()
+}
+
+// writeDelegateProfile replaces filename with the options of the profile
+func writeDelegateProfile(filename string, req DelegateProfileRequest) error {
+	names := make([]string, 0, len(req.Options))
+	for name := range req.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := []string{
+		profileHeader,
+		profilePrefix + req.Profile,
+		versionPrefix + strconv.Itoa(req.Version),
+	}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s=%s", name, req.Options[name]))
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// changedOptions returns the sorted names of the options that are set,
+// changed or removed between two profiles
+func changedOptions(before, after map[string]string) []string {
+	changed := []string{}
+	for name, value := range after {
+		if v, ok := before[name]; !ok || v != value {
+			changed = append(changed, name)
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// setVerbosity sets the log level of the process the way SIGUSR1 toggles it
+var setVerbosity = func(verbosity int) {
+	logControl := logging.NewLogControl()
+	logControl.SetVerbosity(verbosity)
+	if verbosity == 0 {
+		logControl.SetLevel(logrus.InfoLevel)
+	} else {
+		logControl.SetLevel(logrus.DebugLevel)
+	}
+}
+
+// DelegateProfileClient applies profiles through the agent of a host
+type DelegateProfileClient struct{}
+
+// NewDelegateProfileClient returns a new DelegateProfileClient
+func NewDelegateProfileClient() *DelegateProfileClient {
+	return &DelegateProfileClient{}
+}
+
+// ApplyDelegateProfile applies a version of a profile on the agent at the
+// given address.  It returns the options that take effect when the delegate
+// restarts.
+func (c *DelegateProfileClient) ApplyDelegateProfile(address, profile string, version int, options map[string]string) ([]string, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	result, err := client.ApplyDelegateProfile(DelegateProfileRequest{
+		Profile: profile,
+		Version: version,
+		Options: options,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Restart, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyDelegateProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "delegateprofile")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "serviced-profile")
+
+	var verbosity []int
+	defer func(f func(int)) { setVerbosity = f }(setVerbosity)
+	setVerbosity = func(v int) { verbosity = append(verbosity, v) }
+
+	req := DelegateProfileRequest{
+		Profile: "debug",
+		Version: 2,
+		Options: map[string]string{"SERVICED_LOG_LEVEL": "2", "SERVICED_MUX_PORT": "22251"},
+	}
+	result, err := ApplyDelegateProfile(filename, req)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if result.Version != 2 || !reflect.DeepEqual(result.Restart, []string{"SERVICED_MUX_PORT"}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if !reflect.DeepEqual(verbosity, []int{2}) {
+		t.Errorf("Expected the log level to be applied, got %v", verbosity)
+	}
+
+	current, err := ReadDelegateProfile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(current, req) {
+		t.Errorf("Expected %+v, got %+v", req, current)
+	}
+
+	// the same version is not applied again
+	if result, err = ApplyDelegateProfile(filename, req); err != nil || len(result.Restart) != 0 || len(verbosity) != 1 {
+		t.Errorf("Expected the profile to be up to date, got %+v, %v", result, err)
+	}
+
+	// removing the profile removes its options
+	result, err = ApplyDelegateProfile(filename, DelegateProfileRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if !reflect.DeepEqual(result.Restart, []string{"SERVICED_LOG_LEVEL", "SERVICED_MUX_PORT"}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if current, _ = ReadDelegateProfile(filename); len(current.Options) != 0 || current.Version != 0 {
+		t.Errorf("Expected an empty profile, got %+v", current)
+	}
+}
+
+func TestReadDelegateProfile_Missing(t *testing.T) {
+	current, err := ReadDelegateProfile(filepath.Join(os.TempDir(), "no-such-serviced-profile"))
+	if err != nil || current.Version != 0 || len(current.Options) != 0 {
+		t.Errorf("Expected an empty profile, got %+v, %v", current, err)
+	}
+}
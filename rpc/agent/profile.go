@@ -0,0 +1,64 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/utils"
+)
+
+// ProfileRequest names the pprof profiles to collect from the agent
+type ProfileRequest struct {
+	Profiles []string
+	Duration time.Duration
+}
+
+// CollectProfiles collects pprof profiles from the agent process, keyed by
+// profile name.
+func (a *AgentServer) CollectProfiles(req ProfileRequest, profiles *map[string][]byte) error {
+	logger := plog.WithFields(logrus.Fields{
+		"profiles": req.Profiles,
+		"duration": req.Duration,
+	})
+
+	result, err := utils.CollectProfiles(req.Profiles, req.Duration)
+	if err != nil {
+		logger.WithError(err).Error("Could not collect profiles")
+		return err
+	}
+	*profiles = result
+	logger.Info("Collected profiles")
+	return nil
+}
+
+// ProfileClient collects pprof profiles from the agent of a host
+type ProfileClient struct{}
+
+// NewProfileClient returns a new ProfileClient
+func NewProfileClient() *ProfileClient {
+	return &ProfileClient{}
+}
+
+// CollectProfiles collects pprof profiles from the agent at the given
+// address.
+func (c *ProfileClient) CollectProfiles(address string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	client, err := NewClient(address)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return client.CollectProfiles(profiles, duration)
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backupschedule"
+)
+
+// GetBackupOperations returns the backups and restores that can be resumed
+func (c *Client) GetBackupOperations() ([]dao.BackupOperation, error) {
+	response := make([]dao.BackupOperation, 0)
+	if err := c.call("GetBackupOperations", empty, &response); err != nil {
+		return []dao.BackupOperation{}, err
+	}
+	return response, nil
+}
+
+// GetDFSOperations returns the snapshots, rollbacks, backups, restores and
+// image pushes that are running or queued
+func (c *Client) GetDFSOperations() ([]dao.DFSOperation, error) {
+	response := make([]dao.DFSOperation, 0)
+	if err := c.call("GetDFSOperations", empty, &response); err != nil {
+		return []dao.DFSOperation{}, err
+	}
+	return response, nil
+}
+
+// ResumeBackupOperation continues a backup or restore from its last
+// checkpoint and returns the name of its backup file
+func (c *Client) ResumeBackupOperation(id string) (string, error) {
+	var filename string
+	if err := c.call("ResumeBackupOperation", id, &filename); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// DiscardBackupOperation abandons a backup or restore
+func (c *Client) DiscardBackupOperation(id string) error {
+	return c.call("DiscardBackupOperation", id, nil)
+}
+
+// RestoreElastic restores the indices of the given elasticsearch clusters
+// from a backup file
+func (c *Client) RestoreElastic(filename string, clusters []string) error {
+	req := RestoreElasticRequest{Filename: filename, Clusters: clusters}
+	return c.call("RestoreElastic", req, nil)
+}
+
+// PruneBackupLayers removes the image layers that no backup includes and
+// returns the number of layers removed and the number of bytes freed
+func (c *Client) PruneBackupLayers() (int, int64, error) {
+	var response PruneBackupLayersResponse
+	if err := c.call("PruneBackupLayers", empty, &response); err != nil {
+		return 0, 0, err
+	}
+	return response.Layers, response.Freed, nil
+}
+
+// VerifyBackup checks the integrity of a backup file.  If testRestore is
+// true, the metadata of its snapshots is read as it would be restored.
+func (c *Client) VerifyBackup(filename string, testRestore bool) (*dfs.BackupVerification, error) {
+	req := VerifyBackupRequest{Filename: filename, TestRestore: testRestore}
+	response := &dfs.BackupVerification{}
+	if err := c.call("VerifyBackup", req, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetBackupProgress returns the progress of the backup or restore that is
+// running, or of the last one
+func (c *Client) GetBackupProgress() (*dao.BackupProgress, error) {
+	response := &dao.BackupProgress{}
+	if err := c.call("GetBackupProgress", empty, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetBackupSchedules returns all backup schedules
+func (c *Client) GetBackupSchedules() ([]backupschedule.BackupSchedule, error) {
+	response := make([]backupschedule.BackupSchedule, 0)
+	if err := c.call("GetBackupSchedules", empty, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// AddBackupSchedule adds a backup schedule and returns its id
+func (c *Client) AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error) {
+	var id string
+	if err := c.call("AddBackupSchedule", schedule, &id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// RemoveBackupSchedule removes a backup schedule
+func (c *Client) RemoveBackupSchedule(id string) error {
+	return c.call("RemoveBackupSchedule", id, nil)
+}
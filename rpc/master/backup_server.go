@@ -0,0 +1,137 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/backupschedule"
+)
+
+// GetBackupOperations returns the backups and restores that can be resumed
+func (s *Server) GetBackupOperations(empty struct{}, reply *[]dao.BackupOperation) error {
+	ops, err := s.f.GetBackupOperations(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = ops
+	return nil
+}
+
+// GetDFSOperations returns the snapshots, rollbacks, backups, restores and
+// image pushes that are running or queued
+func (s *Server) GetDFSOperations(empty struct{}, reply *[]dao.DFSOperation) error {
+	ops, err := s.f.GetDFSOperations(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = ops
+	return nil
+}
+
+// ResumeBackupOperation continues a backup or restore from its last
+// checkpoint
+func (s *Server) ResumeBackupOperation(id string, reply *string) error {
+	filename, err := s.f.ResumeBackupOperation(s.context(), id)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = filename
+	return nil
+}
+
+// DiscardBackupOperation abandons a backup or restore
+func (s *Server) DiscardBackupOperation(id string, _ *struct{}) error {
+	return rpcError(s.f.DiscardBackupOperation(s.context(), id))
+}
+
+// RestoreElasticRequest is the request to restore the elasticsearch indices
+// of a backup file
+type RestoreElasticRequest struct {
+	Filename string
+	Clusters []string
+}
+
+// RestoreElastic restores the elasticsearch indices of a backup file
+func (s *Server) RestoreElastic(req RestoreElasticRequest, _ *struct{}) error {
+	return rpcError(s.f.RestoreElasticFromFile(s.context(), req.Filename, req.Clusters))
+}
+
+// PruneBackupLayersResponse is the result of pruning the image layers of
+// backups
+type PruneBackupLayersResponse struct {
+	Layers int
+	Freed  int64
+}
+
+// PruneBackupLayers removes the image layers that no backup includes
+func (s *Server) PruneBackupLayers(empty struct{}, reply *PruneBackupLayersResponse) error {
+	layers, freed, err := s.f.PruneBackupLayers(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = PruneBackupLayersResponse{Layers: layers, Freed: freed}
+	return nil
+}
+
+// VerifyBackupRequest is the request to check the integrity of a backup file
+type VerifyBackupRequest struct {
+	Filename    string
+	TestRestore bool
+}
+
+// VerifyBackup checks the integrity of a backup file
+func (s *Server) VerifyBackup(req VerifyBackupRequest, reply *dfs.BackupVerification) error {
+	result, err := s.f.VerifyBackupFile(s.context(), req.Filename, req.TestRestore)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *result
+	return nil
+}
+
+// GetBackupProgress returns the progress of the backup or restore that is
+// running, or of the last one
+func (s *Server) GetBackupProgress(empty struct{}, reply *dao.BackupProgress) error {
+	progress, err := s.f.GetBackupProgress(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *progress
+	return nil
+}
+
+// GetBackupSchedules returns all backup schedules
+func (s *Server) GetBackupSchedules(empty struct{}, reply *[]backupschedule.BackupSchedule) error {
+	schedules, err := s.f.GetBackupSchedules(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = schedules
+	return nil
+}
+
+// AddBackupSchedule adds a backup schedule and returns its id
+func (s *Server) AddBackupSchedule(schedule backupschedule.BackupSchedule, reply *string) error {
+	if err := s.f.AddBackupSchedule(s.context(), &schedule); err != nil {
+		return rpcError(err)
+	}
+	*reply = schedule.ID
+	return nil
+}
+
+// RemoveBackupSchedule removes a backup schedule
+func (s *Server) RemoveBackupSchedule(id string, _ *struct{}) error {
+	return rpcError(s.f.RemoveBackupSchedule(s.context(), id))
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+// GetCalendar gets the calendar for the given calendarID
+func (c *Client) GetCalendar(calendarID string) (*calendar.Calendar, error) {
+	response := calendar.New(calendarID)
+	if err := c.call("GetCalendar", calendarID, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetCalendars returns all calendars or empty array
+func (c *Client) GetCalendars() ([]calendar.Calendar, error) {
+	response := make([]calendar.Calendar, 0)
+	if err := c.call("GetCalendars", empty, &response); err != nil {
+		return []calendar.Calendar{}, err
+	}
+	return response, nil
+}
+
+// AddCalendar adds a calendar
+func (c *Client) AddCalendar(cal calendar.Calendar) error {
+	return c.call("AddCalendar", cal, nil)
+}
+
+// UpdateCalendar updates a calendar
+func (c *Client) UpdateCalendar(cal calendar.Calendar) error {
+	return c.call("UpdateCalendar", cal, nil)
+}
+
+// RemoveCalendar removes a calendar
+func (c *Client) RemoveCalendar(calendarID string) error {
+	return c.call("RemoveCalendar", calendarID, nil)
+}
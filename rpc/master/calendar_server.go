@@ -0,0 +1,57 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/apierror"
+	"github.com/control-center/serviced/domain/calendar"
+)
+
+// GetCalendars returns all calendars
+func (s *Server) GetCalendars(empty struct{}, reply *[]calendar.Calendar) error {
+	calendars, err := s.f.GetCalendars(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = calendars
+	return nil
+}
+
+// GetCalendar gets the calendar
+func (s *Server) GetCalendar(calendarID string, reply *calendar.Calendar) error {
+	response, err := s.f.GetCalendar(s.context(), calendarID)
+	if err != nil {
+		return rpcError(err)
+	}
+	if response == nil {
+		return rpcError(apierror.New(apierror.NotFound, "calendar not found"))
+	}
+	*reply = *response
+	return nil
+}
+
+// AddCalendar adds the calendar
+func (s *Server) AddCalendar(c calendar.Calendar, _ *struct{}) error {
+	return rpcError(s.f.AddCalendar(s.context(), &c))
+}
+
+// UpdateCalendar updates the calendar
+func (s *Server) UpdateCalendar(c calendar.Calendar, _ *struct{}) error {
+	return rpcError(s.f.UpdateCalendar(s.context(), &c))
+}
+
+// RemoveCalendar removes the calendar
+func (s *Server) RemoveCalendar(calendarID string, _ *struct{}) error {
+	return rpcError(s.f.RemoveCalendar(s.context(), calendarID))
+}
@@ -14,6 +14,7 @@
 package master
 
 import (
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/rpc/rpcutils"
 )
 
@@ -39,8 +40,10 @@ func NewClient(addr string) (*Client, error) {
 	return s, nil
 }
 
+// call calls a method of the master and restores the code of the error that
+// it returns; see apierror.CodeOf.
 func (c *Client) call(name string, request interface{}, response interface{}) error {
-	return c.rpcClient.Call("Master."+name, request, response, 0)
+	return apierror.Decode(c.rpcClient.Call("Master."+name, request, response, 0))
 }
 
 // Close closes rpc client
@@ -13,6 +13,10 @@
 
 package master
 
+import (
+	"time"
+)
+
 // Enable internal metrics collection
 func (c *Client) DebugEnableMetrics() (string, error) {
 	result := ""
@@ -32,3 +36,17 @@ func (c *Client) DebugDisableMetrics() (string, error) {
 	}
 	return result, nil
 }
+
+// Collect pprof profiles from the master or the agent of a delegate
+func (c *Client) CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	req := ProfileRequest{
+		HostID:   hostID,
+		Profiles: profiles,
+		Duration: duration,
+	}
+	result := make(map[string][]byte)
+	if err := c.call("CollectProfiles", req, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
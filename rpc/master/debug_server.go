@@ -14,6 +14,8 @@
 package master
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/metrics"
 )
 
@@ -48,3 +50,21 @@ func (s *Server) DebugDisableMetrics(unused struct{}, results *string) error {
 	}
 	return nil
 }
+
+// ProfileRequest names the pprof profiles to collect from the master, or from
+// the agent of the delegate if HostID is set
+type ProfileRequest struct {
+	HostID   string
+	Profiles []string
+	Duration time.Duration
+}
+
+// CollectProfiles collects pprof profiles, keyed by profile name
+func (s *Server) CollectProfiles(req ProfileRequest, profiles *map[string][]byte) error {
+	result, err := s.f.CollectProfiles(s.context(), req.HostID, req.Profiles, req.Duration)
+	if err != nil {
+		return rpcError(err)
+	}
+	*profiles = result
+	return nil
+}
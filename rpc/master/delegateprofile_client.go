@@ -0,0 +1,102 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/delegateprofile"
+)
+
+// GetDelegateProfiles returns the delegate profiles
+func (c *Client) GetDelegateProfiles() ([]delegateprofile.Profile, error) {
+	response := make([]delegateprofile.Profile, 0)
+	if err := c.call("GetDelegateProfiles", empty, &response); err != nil {
+		return []delegateprofile.Profile{}, err
+	}
+	return response, nil
+}
+
+// GetDelegateProfile returns a delegate profile with its previous versions
+func (c *Client) GetDelegateProfile(name string) (*delegateprofile.Profile, error) {
+	response := &delegateprofile.Profile{}
+	if err := c.call("GetDelegateProfile", name, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetHostDelegateProfile returns the delegate profile of the pool of a host,
+// or an empty profile if the pool has none
+func (c *Client) GetHostDelegateProfile(hostID string) (*delegateprofile.Profile, error) {
+	response := &delegateprofile.Profile{}
+	if err := c.call("GetHostDelegateProfile", hostID, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// AddDelegateProfile adds a delegate profile without options
+func (c *Client) AddDelegateProfile(name, description string) error {
+	request := DelegateProfileRequest{Name: name, Description: description}
+	return c.call("AddDelegateProfile", request, nil)
+}
+
+// RemoveDelegateProfile removes a delegate profile that is not assigned to
+// any resource pool
+func (c *Client) RemoveDelegateProfile(name string) error {
+	return c.call("RemoveDelegateProfile", name, nil)
+}
+
+// SetDelegateProfileOption sets an option of a delegate profile and pushes
+// the new version to the hosts of its pools
+func (c *Client) SetDelegateProfileOption(name, option, value string) ([]delegateprofile.HostStatus, error) {
+	request := DelegateProfileRequest{Name: name, Option: option, Value: value}
+	return c.callDelegateProfile("SetDelegateProfileOption", request)
+}
+
+// UnsetDelegateProfileOption removes an option from a delegate profile and
+// pushes the new version to the hosts of its pools
+func (c *Client) UnsetDelegateProfileOption(name, option string) ([]delegateprofile.HostStatus, error) {
+	request := DelegateProfileRequest{Name: name, Option: option}
+	return c.callDelegateProfile("UnsetDelegateProfileOption", request)
+}
+
+// RollbackDelegateProfile restores the options of a previous version of a
+// delegate profile, or of the version before the current one if version is
+// 0, and pushes them to the hosts of its pools
+func (c *Client) RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error) {
+	request := DelegateProfileRequest{Name: name, Version: version}
+	return c.callDelegateProfile("RollbackDelegateProfile", request)
+}
+
+// AssignDelegateProfile assigns a delegate profile to a resource pool, or
+// removes the profile of the pool if name is empty, and pushes it to the
+// hosts of the pool
+func (c *Client) AssignDelegateProfile(poolID, name string) ([]delegateprofile.HostStatus, error) {
+	request := DelegateProfileRequest{Name: name, PoolID: poolID}
+	return c.callDelegateProfile("AssignDelegateProfile", request)
+}
+
+// PushDelegateProfile pushes the delegate profile of a resource pool to the
+// hosts of the pool
+func (c *Client) PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error) {
+	return c.callDelegateProfile("PushDelegateProfile", poolID)
+}
+
+func (c *Client) callDelegateProfile(method string, request interface{}) ([]delegateprofile.HostStatus, error) {
+	response := make([]delegateprofile.HostStatus, 0)
+	if err := c.call(method, request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
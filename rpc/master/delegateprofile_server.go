@@ -0,0 +1,124 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/delegateprofile"
+)
+
+// DelegateProfileRequest is the request to change a delegate profile or its
+// assignment to a resource pool
+type DelegateProfileRequest struct {
+	Name        string
+	Description string
+	Option      string
+	Value       string
+	Version     int
+	PoolID      string
+}
+
+// GetDelegateProfiles returns the delegate profiles
+func (s *Server) GetDelegateProfiles(empty struct{}, reply *[]delegateprofile.Profile) error {
+	profiles, err := s.f.GetDelegateProfiles(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = profiles
+	return nil
+}
+
+// GetDelegateProfile returns a delegate profile with its previous versions
+func (s *Server) GetDelegateProfile(name string, reply *delegateprofile.Profile) error {
+	p, err := s.f.GetDelegateProfile(s.context(), name)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *p
+	return nil
+}
+
+// GetHostDelegateProfile returns the delegate profile of the pool of a host
+func (s *Server) GetHostDelegateProfile(hostID string, reply *delegateprofile.Profile) error {
+	p, err := s.f.GetHostDelegateProfile(s.context(), hostID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *p
+	return nil
+}
+
+// AddDelegateProfile adds a delegate profile without options
+func (s *Server) AddDelegateProfile(request DelegateProfileRequest, _ *struct{}) error {
+	return rpcError(s.f.AddDelegateProfile(s.context(), request.Name, request.Description))
+}
+
+// RemoveDelegateProfile removes a delegate profile
+func (s *Server) RemoveDelegateProfile(name string, _ *struct{}) error {
+	return rpcError(s.f.RemoveDelegateProfile(s.context(), name))
+}
+
+// SetDelegateProfileOption sets an option of a delegate profile and pushes
+// the new version
+func (s *Server) SetDelegateProfileOption(request DelegateProfileRequest, reply *[]delegateprofile.HostStatus) error {
+	statuses, err := s.f.SetDelegateProfileOption(s.context(), request.Name, request.Option, request.Value)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = statuses
+	return nil
+}
+
+// UnsetDelegateProfileOption removes an option from a delegate profile and
+// pushes the new version
+func (s *Server) UnsetDelegateProfileOption(request DelegateProfileRequest, reply *[]delegateprofile.HostStatus) error {
+	statuses, err := s.f.UnsetDelegateProfileOption(s.context(), request.Name, request.Option)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = statuses
+	return nil
+}
+
+// RollbackDelegateProfile restores a previous version of a delegate profile
+// and pushes it
+func (s *Server) RollbackDelegateProfile(request DelegateProfileRequest, reply *[]delegateprofile.HostStatus) error {
+	statuses, err := s.f.RollbackDelegateProfile(s.context(), request.Name, request.Version)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = statuses
+	return nil
+}
+
+// AssignDelegateProfile assigns a delegate profile to a resource pool and
+// pushes it to the hosts of the pool
+func (s *Server) AssignDelegateProfile(request DelegateProfileRequest, reply *[]delegateprofile.HostStatus) error {
+	statuses, err := s.f.AssignDelegateProfile(s.context(), request.PoolID, request.Name)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = statuses
+	return nil
+}
+
+// PushDelegateProfile pushes the delegate profile of a resource pool to the
+// hosts of the pool
+func (s *Server) PushDelegateProfile(poolID string, reply *[]delegateprofile.HostStatus) error {
+	statuses, err := s.f.PushDelegateProfile(s.context(), poolID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = statuses
+	return nil
+}
@@ -13,6 +13,11 @@
 
 package master
 
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/facade"
+)
+
 // UpgradeDockerRequest are options for upgrading/migrating the docker registry.
 type UpgradeDockerRequest struct {
 	Endpoint string
@@ -28,22 +33,25 @@ type DockerOverrideRequest struct {
 // ResetRegistry pulls from the configured docker registry and updates the
 // index.
 func (s *Server) ResetRegistry(req struct{}, reply *int) error {
-	return s.f.RepairRegistry(s.context())
+	return rpcError(s.f.RepairRegistry(s.context()))
 }
 
 // SyncRegistry prompts the master to repush all images in the index into the
-// docker registry.
+// docker registry.  The sync runs as an operation that can be followed and
+// canceled while the client waits.
 func (s *Server) SyncRegistry(req struct{}, reply *int) error {
-	return s.f.SyncRegistryImages(s.context(), true)
+	return rpcError(s.f.RunOperation(s.context(), facade.OperationRegistrySync, "", func(ctx datastore.Context) error {
+		return s.f.SyncRegistryImages(ctx, true)
+	}))
 }
 
 // UpgradeRegistry migrates docker registry images from an older or remote
 // docker registry.
 func (s *Server) UpgradeRegistry(req UpgradeDockerRequest, reply *int) error {
-	return s.f.UpgradeRegistry(s.context(), req.Endpoint, req.Override)
+	return rpcError(s.f.UpgradeRegistry(s.context(), req.Endpoint, req.Override))
 }
 
 // DockerOverride replaces an image in the registry with a new image
 func (s *Server) DockerOverride(overrideReq DockerOverrideRequest, _ *int) error {
-	return s.f.DockerOverride(s.context(), overrideReq.NewImage, overrideReq.OldImage)
+	return rpcError(s.f.DockerOverride(s.context(), overrideReq.NewImage, overrideReq.OldImage))
 }
@@ -14,6 +14,8 @@
 package master
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/domain/applicationendpoint"
 )
 
@@ -32,3 +34,25 @@ func (c *Client) GetServiceEndpoints(serviceIDs []string, reportImports, reportE
 	}
 	return result, nil
 }
+
+// GetServiceEndpointGraph returns the dependency graph of the services of the
+// application that the service belongs to, through the endpoints that they
+// import and export
+func (c *Client) GetServiceEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	response := &applicationendpoint.EndpointGraph{}
+	if err := c.call("GetServiceEndpointGraph", serviceID, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetEndpointMetrics returns the connection statistics of the imports and
+// exports of a service over the window
+func (c *Client) GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	request := EndpointMetricsRequest{ServiceID: serviceID, Window: window}
+	response := []applicationendpoint.EndpointMetrics{}
+	if err := c.call("GetEndpointMetrics", request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
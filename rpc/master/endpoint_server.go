@@ -14,6 +14,8 @@
 package master
 
 import (
+	"time"
+
 	"github.com/control-center/serviced/domain/applicationendpoint"
 )
 
@@ -29,9 +31,38 @@ type EndpointRequest struct {
 func (s *Server) GetServiceEndpoints(request *EndpointRequest, reply *[]applicationendpoint.EndpointReport) error {
 	endpoints, err := s.f.GetServiceEndpoints(s.context(), request.ServiceIDs[0], request.ReportImports, request.ReportExports, request.Validate)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 
 	*reply = endpoints
 	return nil
 }
+
+// GetServiceEndpointGraph returns the dependency graph of the services of the
+// application that the service belongs to
+func (s *Server) GetServiceEndpointGraph(serviceID string, reply *applicationendpoint.EndpointGraph) error {
+	graph, err := s.f.GetServiceEndpointGraph(s.context(), serviceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *graph
+	return nil
+}
+
+// EndpointMetricsRequest is the request for the connection statistics of the
+// endpoints of a service over a window
+type EndpointMetricsRequest struct {
+	ServiceID string
+	Window    time.Duration
+}
+
+// GetEndpointMetrics returns the connection statistics of the imports and
+// exports of a service
+func (s *Server) GetEndpointMetrics(request EndpointMetricsRequest, reply *[]applicationendpoint.EndpointMetrics) error {
+	stats, err := s.f.GetEndpointMetrics(s.context(), request.ServiceID, request.Window)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = stats
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/apierror"
+)
+
+func init() {
+	apierror.Register(apierror.Unauthorized, ErrRequestExpired, ErrRequestFromFuture)
+	apierror.Register(apierror.Conflict, ErrZKNotCoordinator)
+}
+
+// rpcError encodes the code of an error returned by the server, because the
+// rpc server only sends the message of the error to the client.  The client
+// decodes it in call.
+func rpcError(err error) error {
+	return apierror.Encode(err)
+}
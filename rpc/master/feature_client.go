@@ -0,0 +1,41 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/feature"
+)
+
+// GetFeatureFlags returns the state of every known feature
+func (c *Client) GetFeatureFlags() ([]feature.Flag, error) {
+	response := make([]feature.Flag, 0)
+	if err := c.call("GetFeatureFlags", empty, &response); err != nil {
+		return []feature.Flag{}, err
+	}
+	return response, nil
+}
+
+// EnableFeature enables a feature in a resource pool, in a deployment, or
+// everywhere if neither is set
+func (c *Client) EnableFeature(name, poolID, deploymentID string) error {
+	request := FeatureRequest{Name: name, PoolID: poolID, DeploymentID: deploymentID}
+	return c.call("EnableFeature", request, nil)
+}
+
+// DisableFeature disables a feature in a resource pool, in a deployment, or
+// everywhere if neither is set
+func (c *Client) DisableFeature(name, poolID, deploymentID string) error {
+	request := FeatureRequest{Name: name, PoolID: poolID, DeploymentID: deploymentID}
+	return c.call("DisableFeature", request, nil)
+}
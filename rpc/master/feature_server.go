@@ -0,0 +1,46 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/feature"
+)
+
+// FeatureRequest is the request to toggle a feature in a resource pool, in a
+// deployment, or everywhere if neither is set
+type FeatureRequest struct {
+	Name         string
+	PoolID       string
+	DeploymentID string
+}
+
+// GetFeatureFlags returns the state of every known feature
+func (s *Server) GetFeatureFlags(empty struct{}, reply *[]feature.Flag) error {
+	flags, err := s.f.GetFeatureFlags(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = flags
+	return nil
+}
+
+// EnableFeature enables a feature
+func (s *Server) EnableFeature(request FeatureRequest, _ *struct{}) error {
+	return rpcError(s.f.EnableFeature(s.context(), request.Name, request.PoolID, request.DeploymentID))
+}
+
+// DisableFeature disables a feature
+func (s *Server) DisableFeature(request FeatureRequest, _ *struct{}) error {
+	return rpcError(s.f.DisableFeature(s.context(), request.Name, request.PoolID, request.DeploymentID))
+}
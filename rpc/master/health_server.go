@@ -25,6 +25,9 @@ type HealthStatusRequest struct {
 	Key     health.HealthStatusKey
 	Value   health.HealthStatus
 	Expires time.Duration
+	// Remediation is set by a failing health check that asks the host agent
+	// to restart or reschedule the instance.  The master ignores it.
+	Remediation *health.Remediation `json:",omitempty"`
 }
 
 type ReportDeadInstanceRequest struct {
@@ -42,7 +45,7 @@ func (s *Server) GetISvcsHealth(IServiceNames []string, results *[]isvcs.IServic
 	for i, name := range IServiceNames {
 		status, err := isvcs.Mgr.GetHealthStatus(name, isvcs.HEALTH_STATUS_INDEX_ALL)
 		if err != nil {
-			return err
+			return rpcError(err)
 		}
 
 		healthStatuses[i] = status
@@ -55,13 +58,23 @@ func (s *Server) GetISvcsHealth(IServiceNames []string, results *[]isvcs.IServic
 // GetServicesHealth returns health checks for all services.
 func (s *Server) GetServicesHealth(unused struct{}, results *map[string]map[int]map[string]health.HealthStatus) error {
 	if healthStatuses, err := s.f.GetServicesHealth(s.context()); err != nil {
-		return err
+		return rpcError(err)
 	} else {
 		*results = healthStatuses
 	}
 	return nil
 }
 
+// GetServiceHealth returns the health checks of the instances of a service.
+func (s *Server) GetServiceHealth(serviceID string, results *map[int]map[string]health.HealthStatus) error {
+	healthStatuses, err := s.f.GetServiceHealth(s.context(), serviceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*results = healthStatuses
+	return nil
+}
+
 // ReportHealthStatus sends an update to the health check status cache.
 func (s *Server) ReportHealthStatus(request HealthStatusRequest, _ *struct{}) error {
 	s.f.ReportHealthStatus(request.Key, request.Value, request.Expires)
@@ -38,6 +38,16 @@ func (c *Client) GetHosts() ([]host.Host, error) {
 	return response, nil
 }
 
+// GetHostsChanges returns the hosts modified since the change token.  An
+// empty token returns all hosts.
+func (c *Client) GetHostsChanges(token string) (*HostChanges, error) {
+	changes := &HostChanges{}
+	if err := c.call("GetHostsChanges", token, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
 //GetActiveHosts returns all active host ids or empty array
 func (c *Client) GetActiveHostIDs() ([]string, error) {
 	response := []string{}
@@ -75,6 +85,23 @@ func (c *Client) RemoveHost(hostID string) error {
 	return c.call("RemoveHost", hostID, nil)
 }
 
+//SetHostMaintenance enables or disables maintenance mode on a host
+func (c *Client) SetHostMaintenance(hostID string, enabled bool) error {
+	req := HostMaintenanceRequest{
+		HostID:  hostID,
+		Enabled: enabled,
+	}
+	return c.call("SetHostMaintenance", req, nil)
+}
+
+// BenchmarkNetwork returns the bytes per second sent to the master
+func (c *Client) BenchmarkNetwork(size int) (uint64, error) {
+	return host.BenchmarkNetwork(size, func(data []byte) error {
+		received := 0
+		return c.call("BenchmarkNetwork", data, &received)
+	})
+}
+
 //FindHostsInPool returns all hosts in a pool
 func (c *Client) FindHostsInPool(poolID string) ([]host.Host, error) {
 	response := make([]host.Host, 0)
@@ -17,7 +17,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/facade"
@@ -34,10 +36,10 @@ var (
 func (s *Server) GetHost(hostID string, reply *host.Host) error {
 	response, err := s.f.GetHost(s.context(), hostID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	if response == nil {
-		return facade.ErrHostDoesNotExist
+		return rpcError(facade.ErrHostDoesNotExist)
 	}
 	*reply = *response
 	return nil
@@ -47,17 +49,50 @@ func (s *Server) GetHost(hostID string, reply *host.Host) error {
 func (s *Server) GetHosts(empty struct{}, hostReply *[]host.Host) error {
 	hosts, err := s.f.GetHosts(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*hostReply = hosts
 	return nil
 }
 
+// HostChanges are the hosts modified since a change token
+type HostChanges struct {
+	Hosts   []host.Host // hosts modified since the token
+	HostIDs []string    // ids of all hosts, to detect removals
+	Token   string      // change token for the next request
+}
+
+// GetHostsChanges returns the Hosts modified since the change token
+func (s *Server) GetHostsChanges(token string, reply *HostChanges) error {
+	since, err := domain.ParseChangeToken(token)
+	if err != nil {
+		return rpcError(err)
+	}
+	start := time.Now()
+	hosts, err := s.f.GetHosts(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	changes := HostChanges{
+		Hosts:   []host.Host{},
+		HostIDs: make([]string, len(hosts)),
+		Token:   domain.NewChangeToken(start),
+	}
+	for i, h := range hosts {
+		changes.HostIDs[i] = h.ID
+		if !h.UpdatedAt.Before(since) {
+			changes.Hosts = append(changes.Hosts, h)
+		}
+	}
+	*reply = changes
+	return nil
+}
+
 // GetActiveHosts returns all active host ids
 func (s *Server) GetActiveHostIDs(empty struct{}, hostReply *[]string) error {
 	hosts, err := s.f.GetActiveHostIDs(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*hostReply = hosts
 	return nil
@@ -67,7 +102,7 @@ func (s *Server) GetActiveHostIDs(empty struct{}, hostReply *[]string) error {
 func (s *Server) AddHost(host host.Host, hostReply *[]byte) error {
 	privateKey, err := s.f.AddHost(s.context(), &host)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*hostReply = privateKey
 	return nil
@@ -77,7 +112,7 @@ func (s *Server) AddHost(host host.Host, hostReply *[]byte) error {
 func (s *Server) AddHostPrivate(host host.Host, hostReply *[]byte) error {
 	masterPublicKey, err := s.f.AddHostPrivate(s.context(), &host)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*hostReply = masterPublicKey
 	return nil
@@ -85,19 +120,40 @@ func (s *Server) AddHostPrivate(host host.Host, hostReply *[]byte) error {
 
 // UpdateHost updates the host
 func (s *Server) UpdateHost(host host.Host, _ *struct{}) error {
-	return s.f.UpdateHost(s.context(), &host)
+	return rpcError(s.f.UpdateHost(s.context(), &host))
 }
 
 // RemoveHost removes the host
 func (s *Server) RemoveHost(hostID string, _ *struct{}) error {
-	return s.f.RemoveHost(s.context(), hostID)
+	return rpcError(s.f.RemoveHost(s.context(), hostID))
+}
+
+// HostMaintenanceRequest enables or disables maintenance mode on a host
+type HostMaintenanceRequest struct {
+	HostID  string
+	Enabled bool
+}
+
+// SetHostMaintenance enables or disables maintenance mode on a host
+func (s *Server) SetHostMaintenance(req HostMaintenanceRequest, _ *struct{}) error {
+	return rpcError(s.f.SetHostMaintenance(s.context(), req.HostID, req.Enabled))
+}
+
+// BenchmarkNetwork receives the data sent by a host that is being added to
+// measure its network bandwidth to the master.
+func (s *Server) BenchmarkNetwork(data []byte, received *int) error {
+	if len(data) > host.BenchmarkNetworkSize {
+		return rpcError(apierror.Errorf(apierror.Validation, "benchmark data exceeds %d bytes", host.BenchmarkNetworkSize))
+	}
+	*received = len(data)
+	return nil
 }
 
 // FindHostsInPool  Returns all Hosts in a pool
 func (s *Server) FindHostsInPool(poolID string, hostReply *[]host.Host) error {
 	hosts, err := s.f.FindHostsInPool(s.context(), poolID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*hostReply = hosts
 	return nil
@@ -143,51 +199,64 @@ func (s *Server) AuthenticateHost(req HostAuthenticationRequest, resp *HostAuthe
 	keypem, err := s.f.GetHostKey(s.context(), req.HostID)
 	if err != nil {
 		s.f.RemoveHostExpiration(s.context(), req.HostID)
-		return err
+		return rpcError(err)
 	}
 	if err := req.valid(keypem); err != nil {
 		s.f.RemoveHostExpiration(s.context(), req.HostID)
-		return err
+		return rpcError(err)
 	}
 
 	host, err := s.f.GetHost(s.context(), req.HostID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	if host == nil {
-		return facade.ErrHostDoesNotExist
+		return rpcError(facade.ErrHostDoesNotExist)
 	}
 	p, err := s.f.GetResourcePool(s.context(), host.PoolID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	if p == nil {
-		return facade.ErrPoolNotExists
+		return rpcError(facade.ErrPoolNotExists)
 	}
-	adminAccess := p.Permissions&pool.AdminAccess != 0
 	dfsAccess := p.Permissions&pool.DFSAccess != 0
-	signed, expires, err := auth.CreateJWTIdentity(host.ID, host.PoolID, adminAccess, dfsAccess, keypem, s.expiration)
+	signed, expires, err := auth.CreateJWTIdentityWithRole(host.ID, host.PoolID, poolRole(p), dfsAccess, keypem, s.expiration)
 	if err != nil {
 		s.f.RemoveHostExpiration(s.context(), host.ID)
-		return err
+		return rpcError(err)
 	}
 	s.f.SetHostExpiration(s.context(), host.ID, expires)
 	*resp = HostAuthenticationResponse{signed, expires}
 	return nil
 }
 
+// poolRole returns the broadest role granted by the permissions of a pool
+func poolRole(p *pool.ResourcePool) auth.Role {
+	switch {
+	case p.HasAdminAccess():
+		return auth.RoleAdmin
+	case p.HasOperatorAccess():
+		return auth.RoleOperator
+	case p.HasReadOnlyAccess():
+		return auth.RoleReadOnly
+	default:
+		return ""
+	}
+}
+
 // Return host's public key
 func (s *Server) GetHostPublicKey(hostID string, key *[]byte) error {
 	publicKey, err := s.f.GetHostKey(s.context(), hostID)
 	*key = publicKey
-	return err
+	return rpcError(err)
 }
 
 // Reset and return host's private key
 func (s *Server) ResetHostKey(hostID string, key *[]byte) error {
 	publicKey, err := s.f.ResetHostKey(s.context(), hostID)
 	*key = publicKey
-	return err
+	return rpcError(err)
 }
 
 // Given a list of hostsID return if they are authenticated
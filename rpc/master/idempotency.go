@@ -0,0 +1,66 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/apierror"
+	"github.com/control-center/serviced/utils"
+)
+
+var (
+	// callRetries is the number of times a mutating call is retried after
+	// a transient error
+	callRetries = 3
+
+	// callRetryDelay is the delay before the first retry of a mutating
+	// call; it grows with each retry
+	callRetryDelay = time.Second
+)
+
+// newIdempotencyKey returns a key that identifies a mutating call across its
+// retries.
+func newIdempotencyKey() string {
+	key, err := utils.NewUUID36()
+	if err != nil {
+		// without a key the call still works, but is not deduplicated
+		plog.WithError(err).Warn("Could not generate idempotency key")
+		return ""
+	}
+	return key
+}
+
+// callIdempotent calls a mutating method of the master and retries it while
+// it fails with a transient error.  The request must carry an idempotency
+// key so that the master only applies the call once.
+func (c *Client) callIdempotent(name string, request interface{}, response interface{}) error {
+	for i := 0; ; i++ {
+		err := c.call(name, request, response)
+		if err == nil || !apierror.IsTransient(err) || i >= callRetries {
+			return err
+		}
+		plog.WithError(err).WithField("method", name).Debug("Retrying call to the master")
+		time.Sleep(callRetryDelay * time.Duration(i+1))
+	}
+}
+
+// idempotent runs fn once per idempotency key of the given method.  Calls
+// from clients that do not send a key always run.
+func (s *Server) idempotent(method, key string, fn func() (interface{}, error)) (interface{}, error) {
+	if key != "" {
+		key = method + "/" + key
+	}
+	return s.f.Idempotent(s.context(), key, fn)
+}
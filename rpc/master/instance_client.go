@@ -26,6 +26,30 @@ func (c *Client) GetServiceInstances(serviceID string) ([]service.Instance, erro
 	return insts, nil
 }
 
+// GetServiceInstanceHistory returns the hosts each instance of a service has
+// run on
+func (c *Client) GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error) {
+	history := []service.InstanceHistory{}
+
+	err := c.call("GetServiceInstanceHistory", serviceID, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetServiceLogs returns the aggregated application log messages of a
+// service, oldest first
+func (c *Client) GetServiceLogs(query service.LogQuery) ([]service.LogMessage, error) {
+	messages := []service.LogMessage{}
+
+	err := c.call("GetServiceLogs", query, &messages)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
 // StopServiceInstance stops a service instance.
 func (c *Client) StopServiceInstance(serviceID string, instanceID int) error {
 	req := ServiceInstanceRequest{
@@ -36,6 +60,19 @@ func (c *Client) StopServiceInstance(serviceID string, instanceID int) error {
 	return err
 }
 
+// SetServiceInstanceEnvironment sets the variables that are added to the
+// environment of a service instance when it next starts.  An empty
+// environment clears the override.
+func (c *Client) SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error {
+	req := ServiceInstanceEnvironmentRequest{
+		ServiceID:   serviceID,
+		InstanceID:  instanceID,
+		Environment: env,
+	}
+	err := c.call("SetServiceInstanceEnvironment", req, new(string))
+	return err
+}
+
 // LocateServiceInstance returns the location of a service instance
 func (c *Client) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
 	req := ServiceInstanceRequest{
@@ -63,3 +100,44 @@ func (c *Client) SendDockerAction(serviceID string, instanceID int, action strin
 	err := c.call("SendDockerAction", req, new(string))
 	return err
 }
+
+// CheckInteractiveAccess returns an error if a host may not open shells in,
+// attach to or run actions in the instances of a service
+func (c *Client) CheckInteractiveAccess(serviceID, hostID string) error {
+	req := InteractiveAccessRequest{
+		ServiceID: serviceID,
+		HostID:    hostID,
+	}
+
+	err := c.call("CheckInteractiveAccess", req, new(string))
+	return err
+}
+
+// GetConfigDrift compares the config files of the running instances of a
+// service against the files inside their containers
+func (c *Client) GetConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	drift := []service.ConfigDrift{}
+
+	err := c.call("GetConfigDrift", serviceID, &drift)
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// SyncServiceConfigs writes the config files of a service over the files
+// that have drifted inside its containers, optionally restarting it, and
+// returns the drift found before writing.
+func (c *Client) SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	req := SyncServiceConfigsRequest{
+		ServiceID: serviceID,
+		Restart:   restart,
+	}
+	drift := []service.ConfigDrift{}
+
+	err := c.call("SyncServiceConfigs", req, &drift)
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
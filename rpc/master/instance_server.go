@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
 )
 
 // GetServiceInstances returns all instances of a service
@@ -29,6 +30,26 @@ func (s *Server) GetServiceInstances(serviceID string, res *[]service.Instance)
 	return
 }
 
+// GetServiceInstanceHistory returns the instance history of a service
+func (s *Server) GetServiceInstanceHistory(serviceID string, res *[]service.InstanceHistory) (err error) {
+	history, err := s.f.GetServiceInstanceHistory(s.context(), serviceID)
+	if err != nil {
+		return
+	}
+	*res = history
+	return
+}
+
+// GetServiceLogs returns the aggregated application log messages of a service
+func (s *Server) GetServiceLogs(query service.LogQuery, res *[]service.LogMessage) (err error) {
+	messages, err := s.f.GetServiceLogs(s.context(), query)
+	if err != nil {
+		return
+	}
+	*res = messages
+	return
+}
+
 type ServiceInstanceRequest struct {
 	ServiceID  string
 	InstanceID int
@@ -40,6 +61,19 @@ func (s *Server) StopServiceInstance(req ServiceInstanceRequest, unused *string)
 	return
 }
 
+type ServiceInstanceEnvironmentRequest struct {
+	ServiceID   string
+	InstanceID  int
+	Environment []string
+}
+
+// SetServiceInstanceEnvironment sets the environment override of a single
+// service instance
+func (s *Server) SetServiceInstanceEnvironment(req ServiceInstanceEnvironmentRequest, unused *string) (err error) {
+	err = s.f.SetServiceInstanceEnvironment(s.context(), req.ServiceID, req.InstanceID, req.Environment)
+	return
+}
+
 // LocateServiceInstance locates a single service instance
 func (s *Server) LocateServiceInstance(req ServiceInstanceRequest, res *service.LocationInstance) (err error) {
 	location, err := s.f.LocateServiceInstance(s.context(), req.ServiceID, req.InstanceID)
@@ -62,3 +96,48 @@ func (s *Server) SendDockerAction(req DockerActionRequest, unused *string) (err
 	err = s.f.SendDockerAction(s.context(), req.ServiceID, req.InstanceID, req.Action, req.Args)
 	return
 }
+
+type InteractiveAccessRequest struct {
+	ServiceID string
+	HostID    string
+}
+
+// CheckInteractiveAccess returns an error if a host may not open shells in,
+// attach to or run actions in the instances of a service
+func (s *Server) CheckInteractiveAccess(req InteractiveAccessRequest, unused *string) (err error) {
+	h, err := s.f.GetHost(s.context(), req.HostID)
+	if err != nil {
+		return
+	} else if h == nil {
+		return facade.ErrInteractiveAccessDenied
+	}
+	err = s.f.CheckInteractiveAccess(s.context(), req.ServiceID, h.PoolID)
+	return
+}
+
+// GetConfigDrift compares the config files of the running instances of a
+// service against the files inside their containers
+func (s *Server) GetConfigDrift(serviceID string, res *[]service.ConfigDrift) (err error) {
+	drift, err := s.f.GetConfigDrift(s.context(), serviceID)
+	if err != nil {
+		return
+	}
+	*res = drift
+	return
+}
+
+type SyncServiceConfigsRequest struct {
+	ServiceID string
+	Restart   bool
+}
+
+// SyncServiceConfigs writes the config files of a service over the files
+// that have drifted inside its containers
+func (s *Server) SyncServiceConfigs(req SyncServiceConfigsRequest, res *[]service.ConfigDrift) (err error) {
+	drift, err := s.f.SyncServiceConfigs(s.context(), req.ServiceID, req.Restart)
+	if err != nil {
+		return
+	}
+	*res = drift
+	return
+}
@@ -16,16 +16,29 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/commons/startup"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/backupschedule"
+	"github.com/control-center/serviced/domain/calendar"
+	"github.com/control-center/serviced/domain/delegateprofile"
+	"github.com/control-center/serviced/domain/event"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pin"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/secret"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/setting"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/validation"
 	"github.com/control-center/serviced/volume"
 )
 
@@ -45,6 +58,9 @@ type ClientInterface interface {
 	// GetHosts returns all hosts or empty array
 	GetHosts() ([]host.Host, error)
 
+	// GetHostsChanges returns the hosts modified since the change token
+	GetHostsChanges(token string) (*HostChanges, error)
+
 	// GetActiveHosts returns all active host ids or empty array
 	GetActiveHostIDs() ([]string, error)
 
@@ -60,9 +76,15 @@ type ClientInterface interface {
 	// RemoveHost removes a host
 	RemoveHost(hostID string) error
 
+	// SetHostMaintenance enables or disables maintenance mode on a host
+	SetHostMaintenance(hostID string, enabled bool) error
+
 	// FindHostsInPool returns all hosts in a pool
 	FindHostsInPool(poolID string) ([]host.Host, error)
 
+	// BenchmarkNetwork returns the bytes per second sent to the master
+	BenchmarkNetwork(size int) (uint64, error)
+
 	// Authenticate a host and receive an identity token and expiration
 	AuthenticateHost(hostID string) (string, int64, error)
 
@@ -102,36 +124,290 @@ type ClientInterface interface {
 	// RemoveVirtualIP removes a VirtualIP from a specific pool
 	RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error
 
+	// GetVirtualIPEvents returns the reachability and failover events of the
+	// virtual IPs of a pool, from oldest to newest.
+	GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error)
+
+	// GetCrossPoolFlows returns the running imports of endpoints that
+	// services in other pools export, and whether the policy of the export
+	// pool allows them.
+	GetCrossPoolFlows() ([]pool.CrossPoolFlow, error)
+
+	//--------------------------------------------------------------------------
+	// Audit Functions
+
+	// GetAuditEntries returns the audit log entries logged at or after the
+	// given time
+	GetAuditEntries(since time.Time) ([]audit.Entry, error)
+
+	//--------------------------------------------------------------------------
+	// Event Functions
+
+	// QueryEvents returns a page of the recorded events that match the query,
+	// oldest first
+	QueryEvents(q event.Query) (*event.Page, error)
+
+	//--------------------------------------------------------------------------
+	// Calendar Management Functions
+
+	// GetCalendar gets the calendar for the given calendarID
+	GetCalendar(calendarID string) (*calendar.Calendar, error)
+
+	// GetCalendars returns all calendars or empty array
+	GetCalendars() ([]calendar.Calendar, error)
+
+	// AddCalendar adds a calendar
+	AddCalendar(c calendar.Calendar) error
+
+	// UpdateCalendar updates a calendar
+	UpdateCalendar(c calendar.Calendar) error
+
+	// RemoveCalendar removes a calendar
+	RemoveCalendar(calendarID string) error
+
+	//--------------------------------------------------------------------------
+	// Feature Flag Functions
+
+	// GetFeatureFlags returns the state of every known feature
+	GetFeatureFlags() ([]feature.Flag, error)
+
+	// EnableFeature enables a feature in a resource pool, in a deployment, or
+	// everywhere if neither is set
+	EnableFeature(name, poolID, deploymentID string) error
+
+	// DisableFeature disables a feature in a resource pool, in a deployment,
+	// or everywhere if neither is set
+	DisableFeature(name, poolID, deploymentID string) error
+
+	//--------------------------------------------------------------------------
+	// Pin Functions
+
+	// GetPins returns the pinned snapshots and image tags
+	GetPins() ([]pin.Pin, error)
+
+	// PinSnapshot protects a snapshot from the snapshot TTL and from being
+	// deleted
+	PinSnapshot(snapshotID, reason string) error
+
+	// PinImage protects a tag of an image in the docker registry from
+	// registry cleanup and from being deleted
+	PinImage(image, reason string) error
+
+	// Unpin removes the pin of a snapshot or image tag
+	Unpin(kind pin.Kind, target string) error
+
+	//--------------------------------------------------------------------------
+	// Setting Functions
+
+	// GetSettings returns every known setting with the values it has been
+	// set to
+	GetSettings() ([]setting.Setting, error)
+
+	// SetSetting sets the value of a setting in a scope
+	SetSetting(name string, scope setting.Scope, scopeID, value string) error
+
+	// UnsetSetting removes the value of a setting in a scope
+	UnsetSetting(name string, scope setting.Scope, scopeID string) error
+
+	//--------------------------------------------------------------------------
+	// Delegate Profile Functions
+
+	// GetDelegateProfiles returns the delegate profiles
+	GetDelegateProfiles() ([]delegateprofile.Profile, error)
+
+	// GetDelegateProfile returns a delegate profile with its previous versions
+	GetDelegateProfile(name string) (*delegateprofile.Profile, error)
+
+	// GetHostDelegateProfile returns the delegate profile of the pool of a host
+	GetHostDelegateProfile(hostID string) (*delegateprofile.Profile, error)
+
+	// AddDelegateProfile adds a delegate profile without options
+	AddDelegateProfile(name, description string) error
+
+	// RemoveDelegateProfile removes a delegate profile
+	RemoveDelegateProfile(name string) error
+
+	// SetDelegateProfileOption sets an option of a delegate profile
+	SetDelegateProfileOption(name, option, value string) ([]delegateprofile.HostStatus, error)
+
+	// UnsetDelegateProfileOption removes an option from a delegate profile
+	UnsetDelegateProfileOption(name, option string) ([]delegateprofile.HostStatus, error)
+
+	// RollbackDelegateProfile restores a previous version of a delegate profile
+	RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error)
+
+	// AssignDelegateProfile assigns a delegate profile to a resource pool
+	AssignDelegateProfile(poolID, name string) ([]delegateprofile.HostStatus, error)
+
+	// PushDelegateProfile pushes the delegate profile of a resource pool to its hosts
+	PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error)
+
+	//--------------------------------------------------------------------------
+	// Secret Management Functions
+
+	// GetSecrets returns all secrets without their values
+	GetSecrets() ([]secret.Secret, error)
+
+	// GetSecretValue returns the decrypted value of a secret
+	GetSecretValue(name string) (string, error)
+
+	// SetSecret adds or updates a secret
+	SetSecret(name, description, value string) error
+
+	// RemoveSecret removes a secret
+	RemoveSecret(name string) error
+
+	// GetServiceSecrets returns the values of the secrets that the config
+	// files of a service instance reference
+	GetServiceSecrets(serviceID string, instanceID int) (map[string]string, error)
+
+	//--------------------------------------------------------------------------
+	// ZooKeeper Ensemble Management Functions
+
+	// GetZKEnsemble returns the state of each member of the zookeeper ensemble
+	GetZKEnsemble() ([]isvcs.ZKMemberStatus, error)
+
+	// AddZKEnsembleMember adds a host to the zookeeper ensemble
+	AddZKEnsembleMember(host string) (*ZKEnsembleChange, error)
+
+	// RemoveZKEnsembleMember removes a host from the zookeeper ensemble
+	RemoveZKEnsembleMember(host string) (*ZKEnsembleChange, error)
+
+	//--------------------------------------------------------------------------
+	// Backup Management Functions
+
+	// GetBackupOperations returns the backups and restores that can be resumed
+	GetBackupOperations() ([]dao.BackupOperation, error)
+
+	// GetDFSOperations returns the snapshots, rollbacks, backups, restores
+	// and image pushes that are running or queued
+	GetDFSOperations() ([]dao.DFSOperation, error)
+
+	// ResumeBackupOperation continues a backup or restore from its last
+	// checkpoint and returns the name of its backup file
+	ResumeBackupOperation(id string) (string, error)
+
+	// DiscardBackupOperation abandons a backup or restore
+	DiscardBackupOperation(id string) error
+
+	// PruneBackupLayers removes the image layers that no backup includes and
+	// returns the number of layers removed and the number of bytes freed
+	PruneBackupLayers() (int, int64, error)
+
+	// RestoreElastic restores the indices of the given elasticsearch clusters
+	// from a backup file
+	RestoreElastic(filename string, clusters []string) error
+
+	// VerifyBackup checks the integrity of a backup file.  If testRestore is
+	// true, the metadata of its snapshots is read as it would be restored.
+	VerifyBackup(filename string, testRestore bool) (*dfs.BackupVerification, error)
+
+	// GetBackupProgress returns the progress of the backup or restore that
+	// is running, or of the last one
+	GetBackupProgress() (*dao.BackupProgress, error)
+
+	// GetBackupSchedules returns all backup schedules
+	GetBackupSchedules() ([]backupschedule.BackupSchedule, error)
+
+	// AddBackupSchedule adds a backup schedule that the master takes backups
+	// on and returns its id
+	AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error)
+
+	// RemoveBackupSchedule removes a backup schedule
+	RemoveBackupSchedule(id string) error
+
+	//--------------------------------------------------------------------------
+	// Operation Management Functions
+
+	// GetOperations returns the backups, restores, template deploys,
+	// registry syncs and rebalances that are running or recently ended
+	GetOperations() ([]dao.Operation, error)
+
+	// GetOperation returns the status, progress and logs of an operation
+	GetOperation(id string) (*dao.Operation, error)
+
+	// CancelOperation asks a running operation to stop
+	CancelOperation(id string) error
+
 	//--------------------------------------------------------------------------
 	// Service Management Functions
 
 	// ServiceUse will use a new image for a given service - this will pull the image and tag it
 	ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error)
 
+	// DeployServiceCanary rolls a new image out to a fraction of the instances of a service and
+	// promotes it if they pass their health checks, returning the id of the snapshot taken first
+	DeployServiceCanary(serviceID, imageID string, fraction float64, timeout time.Duration) (string, error)
+
+	// GetServiceImagePins returns the image digests that a service and its descendants are pinned to
+	GetServiceImagePins(serviceID string) ([]service.ImagePin, error)
+
+	// RefreshServiceImagePins pins a service and its descendants to the digests that their images
+	// are currently tagged to, and returns the new pins
+	RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error)
+
+	// SetMaintenanceMode puts a tenant into maintenance mode, so that its http public endpoints
+	// serve a maintenance page, or takes it out of maintenance mode if mode is nil
+	SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error
+
 	// WaitService will wait for the specified services to reach the specified state, within the given timeout
 	WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error
 
+	// WaitServiceStateEvents returns the state changes of a service or tenant after a sequence number
+	WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error)
+
 	// GetAllServiceDetails will return a list of all ServiceDetails
 	GetAllServiceDetails(since time.Duration) ([]service.ServiceDetails, error)
 
+	// GetServiceDetailsChanges will return the ServiceDetails modified since the change token
+	GetServiceDetailsChanges(token string) (*ServiceDetailsChanges, error)
+
+	// GetServiceDetailsInPool will return a list of ServiceDetails for the services in a resource pool
+	GetServiceDetailsInPool(poolID string) ([]service.ServiceDetails, error)
+
 	// GetServiceDetailsByTenantID will return a list of ServiceDetails for the specified tenant ID
 	GetServiceDetailsByTenantID(tenantID string) ([]service.ServiceDetails, error)
 
 	// GetServiceDetails will return a ServiceDetails for the specified service
 	GetServiceDetails(serviceID string) (*service.ServiceDetails, error)
 
+	// GetService will return the service with the specified id
+	GetService(serviceID string) (*service.Service, error)
+
 	// ResolveServicePath will return ServiceDetails that match the given path and prefix matching style
 	ResolveServicePath(path string, noprefix bool) ([]service.ServiceDetails, error)
 
 	// ClearEmergency will set EmergencyShutdown to false on the service and all child services
 	ClearEmergency(serviceID string) (int, error)
 
+	// GetServicePriorities returns the effective priority classes of a
+	// service and its descendents, or of every deployment if serviceID is
+	// empty, with the batches that they are started and emergency stopped in
+	GetServicePriorities(serviceID string) ([]service.ServicePriority, error)
+
+	// StartServices schedules a list of services to start in one call
+	StartServices(serviceIDs []string, synchronous bool) (int, error)
+
+	// RestartServices schedules a list of services to restart in one call
+	RestartServices(serviceIDs []string, synchronous bool) (int, error)
+
+	// StopServices schedules a list of services to stop in one call
+	StopServices(serviceIDs []string, synchronous bool) (int, error)
+
 	//--------------------------------------------------------------------------
 	// Service Instance Management Functions
 
 	// GetServiceInstances returns all running instances of a service
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
 
+	// GetServiceInstanceHistory returns the hosts each instance of a service
+	// has run on, with start and stop times
+	GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error)
+
+	// GetServiceLogs returns the aggregated application log messages of a
+	// service that match the query, oldest first
+	GetServiceLogs(query service.LogQuery) ([]service.LogMessage, error)
+
 	// Get a service from serviced where all templated properties have been evaluated
 	GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, string, error)
 
@@ -141,6 +417,10 @@ type ClientInterface interface {
 	// StopServiceInstance stops a single service instance
 	StopServiceInstance(serviceID string, instanceID int) error
 
+	// SetServiceInstanceEnvironment sets the variables that are added to the
+	// environment of a service instance when it next starts
+	SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error
+
 	// LocateServiceInstance returns location information about a service
 	// instance
 	LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error)
@@ -148,6 +428,18 @@ type ClientInterface interface {
 	// SendDockerAction submits a docker action to a running container
 	SendDockerAction(serviceID string, instanceID int, action string, args []string) error
 
+	// CheckInteractiveAccess returns an error if a host may not open shells
+	// in, attach to or run actions in the instances of a service
+	CheckInteractiveAccess(serviceID, hostID string) error
+
+	// GetConfigDrift compares the config files of the running instances of a
+	// service against the files inside their containers
+	GetConfigDrift(serviceID string) ([]service.ConfigDrift, error)
+
+	// SyncServiceConfigs writes the config files of a service over the files
+	// that have drifted inside its containers and optionally restarts it
+	SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error)
+
 	//--------------------------------------------------------------------------
 	// Service Tempatate Management Functions
 
@@ -163,18 +455,47 @@ type ClientInterface interface {
 	// Deploy an application template
 	DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) (tenantIDs []string, err error)
 
+	// Upgrade the services of a deployment to a version of a template
+	UpgradeTemplate(request servicetemplate.ServiceTemplateUpgradeRequest) ([]servicetemplate.TemplateChange, error)
+
+	// GetSchema returns the JSON Schema of service templates or services
+	GetSchema(name string) (*validation.Schema, error)
+
 	//--------------------------------------------------------------------------
 	// Volume Management Functions
 
 	// GetVolumeStatus gets status information for the given volume or nil
 	GetVolumeStatus() (*volume.Statuses, error)
 
+	// ResizeVolume sets the quota of the volume of the tenant that owns the
+	// service and returns the resized quota
+	ResizeVolume(serviceID string, size uint64) (*volume.Quota, error)
+
+	// CheckDFS cross-checks the volumes, snapshots, registry and service
+	// images of the dfs and returns the problems that it finds.  If repair is
+	// true, the problems that can be repaired safely are repaired.
+	CheckDFS(repair bool) ([]dfs.FsckProblem, error)
+
+	// GetRollbackPreview returns the services that are stopped, the images
+	// that are retagged and the config files that are changed by a rollback
+	// to the snapshot
+	GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error)
+
 	//--------------------------------------------------------------------------
 	// Endpoint Management Functions
 
 	// GetServiceEndpoints gets the endpoints for one or more services
 	GetServiceEndpoints(serviceIDs []string, reportImports, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error)
 
+	// GetServiceEndpointGraph returns the dependency graph of the services of
+	// the application that the service belongs to, through the endpoints
+	// that they import and export
+	GetServiceEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error)
+
+	// GetEndpointMetrics returns the connection statistics of the imports
+	// and exports of a service over the window
+	GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error)
+
 	//--------------------------------------------------------------------------
 	// Docker Registry Management Functions
 
@@ -206,8 +527,14 @@ type ClientInterface interface {
 
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
 
+	// SetPublicEndpointVHostCertificate sets the certificate that a vhost presents, or clears it if certPEM is empty
+	SetPublicEndpointVHostCertificate(serviceid, vhost string, certPEM, keyPEM []byte) error
+
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
 
+	// GetPublicEndpointsInPool returns the public endpoints of the services in a resource pool
+	GetPublicEndpointsInPool(poolID string) ([]service.PublicEndpoint, error)
+
 	//--------------------------------------------------------------------------
 	// User Management Functions
 
@@ -226,12 +553,19 @@ type ClientInterface interface {
 	// GetServicesHealth returns health checks for all services.
 	GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error)
 
+	// GetServiceHealth returns the health checks of the instances of a
+	// service.
+	GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error)
+
 	// ReportHealthStatus sends an update to the health check status cache.
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) error
 
 	// ReportInstanceDead removes stopped instances from the health check status cache.
 	ReportInstanceDead(serviceID string, instanceID int) error
 
+	// GetStartupProgress returns the status of each step of the master's startup
+	GetStartupProgress() ([]startup.Status, error)
+
 	//--------------------------------------------------------------------------
 	// Debug Management Functions
 
@@ -241,6 +575,9 @@ type ClientInterface interface {
 	// Disable internal metrics collection
 	DebugDisableMetrics() (string, error)
 
+	// Collect pprof profiles from the master or the agent of a delegate
+	CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error)
+
 	//--------------------------------------------------------------------------
 	// Assignment management functions
 
@@ -1,25 +1,52 @@
 package mocks
 
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import audit "github.com/control-center/serviced/audit"
+import calendar "github.com/control-center/serviced/domain/calendar"
+import delegateprofile "github.com/control-center/serviced/domain/delegateprofile"
+import event "github.com/control-center/serviced/domain/event"
+import backupschedule "github.com/control-center/serviced/domain/backupschedule"
+import feature "github.com/control-center/serviced/domain/feature"
+import dao "github.com/control-center/serviced/dao"
+import dfs "github.com/control-center/serviced/dfs"
 import health "github.com/control-center/serviced/health"
+import startup "github.com/control-center/serviced/commons/startup"
 import host "github.com/control-center/serviced/domain/host"
 import isvcs "github.com/control-center/serviced/isvcs"
 import master "github.com/control-center/serviced/rpc/master"
 import mock "github.com/stretchr/testify/mock"
+import pin "github.com/control-center/serviced/domain/pin"
 import pool "github.com/control-center/serviced/domain/pool"
+import secret "github.com/control-center/serviced/domain/secret"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import setting "github.com/control-center/serviced/domain/setting"
 import time "time"
 import user "github.com/control-center/serviced/domain/user"
 import volume "github.com/control-center/serviced/volume"
 import addressassignment "github.com/control-center/serviced/domain/addressassignment"
+import validation "github.com/control-center/serviced/validation"
 
 // ClientInterface is an autogenerated mock type for the ClientInterface type
 type ClientInterface struct {
 	mock.Mock
 }
 
+// AddCalendar provides a mock function with given fields: c
+func (_m *ClientInterface) AddCalendar(c calendar.Calendar) error {
+	ret := _m.Called(c)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(calendar.Calendar) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: h
 func (_m *ClientInterface) AddHost(h host.Host) ([]byte, error) {
 	ret := _m.Called(h)
@@ -161,6 +188,29 @@ func (_m *ClientInterface) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return r0
 }
 
+// AddZKEnsembleMember provides a mock function with given fields: host
+func (_m *ClientInterface) AddZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	ret := _m.Called(host)
+
+	var r0 *master.ZKEnsembleChange
+	if rf, ok := ret.Get(0).(func(string) *master.ZKEnsembleChange); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*master.ZKEnsembleChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AuthenticateHost provides a mock function with given fields: hostID
 func (_m *ClientInterface) AuthenticateHost(hostID string) (string, int64, error) {
 	ret := _m.Called(hostID)
@@ -210,6 +260,29 @@ func (_m *ClientInterface) ClearEmergency(serviceID string) (int, error) {
 	return r0, r1
 }
 
+// GetServicePriorities provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServicePriorities(serviceID string) ([]service.ServicePriority, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ServicePriority
+	if rf, ok := ret.Get(0).(func(string) []service.ServicePriority); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServicePriority)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Close provides a mock function with given fields:
 func (_m *ClientInterface) Close() error {
 	ret := _m.Called()
@@ -266,6 +339,173 @@ func (_m *ClientInterface) DebugEnableMetrics() (string, error) {
 	return r0, r1
 }
 
+// CollectProfiles provides a mock function with given fields: hostID, profiles, duration
+func (_m *ClientInterface) CollectProfiles(hostID string, profiles []string, duration time.Duration) (map[string][]byte, error) {
+	ret := _m.Called(hostID, profiles, duration)
+
+	var r0 map[string][]byte
+	if rf, ok := ret.Get(0).(func(string, []string, time.Duration) map[string][]byte); ok {
+		r0 = rf(hostID, profiles, duration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, []string, time.Duration) error); ok {
+		r1 = rf(hostID, profiles, duration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceImagePins provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ImagePin
+	if rf, ok := ret.Get(0).(func(string) []service.ImagePin); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ImagePin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshServiceImagePins provides a mock function with given fields: serviceID
+func (_m *ClientInterface) RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ImagePin
+	if rf, ok := ret.Get(0).(func(string) []service.ImagePin); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ImagePin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetMaintenanceMode provides a mock function with given fields: tenantID, mode
+func (_m *ClientInterface) SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error {
+	ret := _m.Called(tenantID, mode)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *service.MaintenanceMode) error); ok {
+		r0 = rf(tenantID, mode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartServices provides a mock function with given fields: serviceIDs, synchronous
+func (_m *ClientInterface) StartServices(serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(serviceIDs, synchronous)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]string, bool) int); ok {
+		r0 = rf(serviceIDs, synchronous)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, bool) error); ok {
+		r1 = rf(serviceIDs, synchronous)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestartServices provides a mock function with given fields: serviceIDs, synchronous
+func (_m *ClientInterface) RestartServices(serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(serviceIDs, synchronous)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]string, bool) int); ok {
+		r0 = rf(serviceIDs, synchronous)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, bool) error); ok {
+		r1 = rf(serviceIDs, synchronous)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StopServices provides a mock function with given fields: serviceIDs, synchronous
+func (_m *ClientInterface) StopServices(serviceIDs []string, synchronous bool) (int, error) {
+	ret := _m.Called(serviceIDs, synchronous)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]string, bool) int); ok {
+		r0 = rf(serviceIDs, synchronous)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, bool) error); ok {
+		r1 = rf(serviceIDs, synchronous)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeployServiceCanary provides a mock function with given fields: serviceID, imageID, fraction, timeout
+func (_m *ClientInterface) DeployServiceCanary(serviceID string, imageID string, fraction float64, timeout time.Duration) (string, error) {
+	ret := _m.Called(serviceID, imageID, fraction, timeout)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, float64, time.Duration) string); ok {
+		r0 = rf(serviceID, imageID, fraction, timeout)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, float64, time.Duration) error); ok {
+		r1 = rf(serviceID, imageID, fraction, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeployTemplate provides a mock function with given fields: request
 func (_m *ClientInterface) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) ([]string, error) {
 	ret := _m.Called(request)
@@ -289,6 +529,43 @@ func (_m *ClientInterface) DeployTemplate(request servicetemplate.ServiceTemplat
 	return r0, r1
 }
 
+// UpgradeTemplate provides a mock function with given fields: request
+func (_m *ClientInterface) UpgradeTemplate(request servicetemplate.ServiceTemplateUpgradeRequest) ([]servicetemplate.TemplateChange, error) {
+	ret := _m.Called(request)
+
+	var r0 []servicetemplate.TemplateChange
+	if rf, ok := ret.Get(0).(func(servicetemplate.ServiceTemplateUpgradeRequest) []servicetemplate.TemplateChange); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]servicetemplate.TemplateChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(servicetemplate.ServiceTemplateUpgradeRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardBackupOperation provides a mock function with given fields: id
+func (_m *ClientInterface) DiscardBackupOperation(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DockerOverride provides a mock function with given fields: newImage, oldImage
 func (_m *ClientInterface) DockerOverride(newImage string, oldImage string) error {
 	ret := _m.Called(newImage, oldImage)
@@ -331,6 +608,20 @@ func (_m *ClientInterface) EnablePublicEndpointVHost(serviceid string, endpointN
 	return r0
 }
 
+// SetPublicEndpointVHostCertificate provides a mock function with given fields: serviceid, vhost, certPEM, keyPEM
+func (_m *ClientInterface) SetPublicEndpointVHostCertificate(serviceid string, vhost string, certPEM []byte, keyPEM []byte) error {
+	ret := _m.Called(serviceid, vhost, certPEM, keyPEM)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, []byte, []byte) error); ok {
+		r0 = rf(serviceid, vhost, certPEM, keyPEM)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindHostsInPool provides a mock function with given fields: poolID
 func (_m *ClientInterface) FindHostsInPool(poolID string) ([]host.Host, error) {
 	ret := _m.Called(poolID)
@@ -423,59 +714,45 @@ func (_m *ClientInterface) GetAllServiceDetails(since time.Duration) ([]service.
 	return r0, r1
 }
 
-// GetEvaluatedService provides a mock function with given fields: serviceID, instanceID
-func (_m *ClientInterface) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, string, error) {
-	ret := _m.Called(serviceID, instanceID)
+// GetBackupOperations provides a mock function with given fields:
+func (_m *ClientInterface) GetBackupOperations() ([]dao.BackupOperation, error) {
+	ret := _m.Called()
 
-	var r0 *service.Service
-	if rf, ok := ret.Get(0).(func(string, int) *service.Service); ok {
-		r0 = rf(serviceID, instanceID)
+	var r0 []dao.BackupOperation
+	if rf, ok := ret.Get(0).(func() []dao.BackupOperation); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.Service)
+			r0 = ret.Get(0).([]dao.BackupOperation)
 		}
 	}
 
-	var r1 string
-	if rf, ok := ret.Get(1).(func(string, int) string); ok {
-		r1 = rf(serviceID, instanceID)
-	} else {
-		r1 = ret.Get(1).(string)
-	}
-
-	var r2 string
-	if rf, ok := ret.Get(1).(func(string, int) string); ok {
-		r2 = rf(serviceID, instanceID)
-	} else {
-		r2 = ret.Get(1).(string)
-	}
-
-	var r3 error
-	if rf, ok := ret.Get(2).(func(string, int) error); ok {
-		r3 = rf(serviceID, instanceID)
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
-		r3 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2, r3
+	return r0, r1
 }
 
-// GetHost provides a mock function with given fields: hostID
-func (_m *ClientInterface) GetHost(hostID string) (*host.Host, error) {
-	ret := _m.Called(hostID)
+// GetDFSOperations provides a mock function with given fields:
+func (_m *ClientInterface) GetDFSOperations() ([]dao.DFSOperation, error) {
+	ret := _m.Called()
 
-	var r0 *host.Host
-	if rf, ok := ret.Get(0).(func(string) *host.Host); ok {
-		r0 = rf(hostID)
+	var r0 []dao.DFSOperation
+	if rf, ok := ret.Get(0).(func() []dao.DFSOperation); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*host.Host)
+			r0 = ret.Get(0).([]dao.DFSOperation)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(hostID)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -483,22 +760,36 @@ func (_m *ClientInterface) GetHost(hostID string) (*host.Host, error) {
 	return r0, r1
 }
 
-// GetHostPublicKey provides a mock function with given fields: hostID
-func (_m *ClientInterface) GetHostPublicKey(hostID string) ([]byte, error) {
-	ret := _m.Called(hostID)
+// CancelOperation provides a mock function with given fields: id
+func (_m *ClientInterface) CancelOperation(id string) error {
+	ret := _m.Called(id)
 
-	var r0 []byte
-	if rf, ok := ret.Get(0).(func(string) []byte); ok {
-		r0 = rf(hostID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOperation provides a mock function with given fields: id
+func (_m *ClientInterface) GetOperation(id string) (*dao.Operation, error) {
+	ret := _m.Called(id)
+
+	var r0 *dao.Operation
+	if rf, ok := ret.Get(0).(func(string) *dao.Operation); ok {
+		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).(*dao.Operation)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(hostID)
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -506,16 +797,16 @@ func (_m *ClientInterface) GetHostPublicKey(hostID string) ([]byte, error) {
 	return r0, r1
 }
 
-// GetHosts provides a mock function with given fields:
-func (_m *ClientInterface) GetHosts() ([]host.Host, error) {
+// GetOperations provides a mock function with given fields:
+func (_m *ClientInterface) GetOperations() ([]dao.Operation, error) {
 	ret := _m.Called()
 
-	var r0 []host.Host
-	if rf, ok := ret.Get(0).(func() []host.Host); ok {
+	var r0 []dao.Operation
+	if rf, ok := ret.Get(0).(func() []dao.Operation); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]host.Host)
+			r0 = ret.Get(0).([]dao.Operation)
 		}
 	}
 
@@ -529,22 +820,22 @@ func (_m *ClientInterface) GetHosts() ([]host.Host, error) {
 	return r0, r1
 }
 
-// GetISvcsHealth provides a mock function with given fields: IServiceNames
-func (_m *ClientInterface) GetISvcsHealth(IServiceNames []string) ([]isvcs.IServiceHealthResult, error) {
-	ret := _m.Called(IServiceNames)
+// GetCalendar provides a mock function with given fields: calendarID
+func (_m *ClientInterface) GetCalendar(calendarID string) (*calendar.Calendar, error) {
+	ret := _m.Called(calendarID)
 
-	var r0 []isvcs.IServiceHealthResult
-	if rf, ok := ret.Get(0).(func([]string) []isvcs.IServiceHealthResult); ok {
-		r0 = rf(IServiceNames)
+	var r0 *calendar.Calendar
+	if rf, ok := ret.Get(0).(func(string) *calendar.Calendar); ok {
+		r0 = rf(calendarID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]isvcs.IServiceHealthResult)
+			r0 = ret.Get(0).(*calendar.Calendar)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]string) error); ok {
-		r1 = rf(IServiceNames)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(calendarID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -552,22 +843,22 @@ func (_m *ClientInterface) GetISvcsHealth(IServiceNames []string) ([]isvcs.IServ
 	return r0, r1
 }
 
-// GetPoolIPs provides a mock function with given fields: poolID
-func (_m *ClientInterface) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
-	ret := _m.Called(poolID)
+// GetCalendars provides a mock function with given fields:
+func (_m *ClientInterface) GetCalendars() ([]calendar.Calendar, error) {
+	ret := _m.Called()
 
-	var r0 *pool.PoolIPs
-	if rf, ok := ret.Get(0).(func(string) *pool.PoolIPs); ok {
-		r0 = rf(poolID)
+	var r0 []calendar.Calendar
+	if rf, ok := ret.Get(0).(func() []calendar.Calendar); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*pool.PoolIPs)
+			r0 = ret.Get(0).([]calendar.Calendar)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(poolID)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -575,22 +866,22 @@ func (_m *ClientInterface) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
 	return r0, r1
 }
 
-// GetResourcePool provides a mock function with given fields: poolID
-func (_m *ClientInterface) GetResourcePool(poolID string) (*pool.ResourcePool, error) {
-	ret := _m.Called(poolID)
+// GetAuditEntries provides a mock function with given fields: since
+func (_m *ClientInterface) GetAuditEntries(since time.Time) ([]audit.Entry, error) {
+	ret := _m.Called(since)
 
-	var r0 *pool.ResourcePool
-	if rf, ok := ret.Get(0).(func(string) *pool.ResourcePool); ok {
-		r0 = rf(poolID)
+	var r0 []audit.Entry
+	if rf, ok := ret.Get(0).(func(time.Time) []audit.Entry); ok {
+		r0 = rf(since)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*pool.ResourcePool)
+			r0 = ret.Get(0).([]audit.Entry)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(poolID)
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -598,22 +889,22 @@ func (_m *ClientInterface) GetResourcePool(poolID string) (*pool.ResourcePool, e
 	return r0, r1
 }
 
-// GetResourcePools provides a mock function with given fields:
-func (_m *ClientInterface) GetResourcePools() ([]pool.ResourcePool, error) {
-	ret := _m.Called()
+// QueryEvents provides a mock function with given fields: q
+func (_m *ClientInterface) QueryEvents(q event.Query) (*event.Page, error) {
+	ret := _m.Called(q)
 
-	var r0 []pool.ResourcePool
-	if rf, ok := ret.Get(0).(func() []pool.ResourcePool); ok {
-		r0 = rf()
+	var r0 *event.Page
+	if rf, ok := ret.Get(0).(func(event.Query) *event.Page); ok {
+		r0 = rf(q)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]pool.ResourcePool)
+			r0 = ret.Get(0).(*event.Page)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(event.Query) error); ok {
+		r1 = rf(q)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -621,22 +912,59 @@ func (_m *ClientInterface) GetResourcePools() ([]pool.ResourcePool, error) {
 	return r0, r1
 }
 
-// GetServiceDetails provides a mock function with given fields: serviceID
-func (_m *ClientInterface) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
-	ret := _m.Called(serviceID)
+// GetEvaluatedService provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) GetEvaluatedService(serviceID string, instanceID int) (*service.Service, string, string, error) {
+	ret := _m.Called(serviceID, instanceID)
 
-	var r0 *service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(string) *service.ServiceDetails); ok {
-		r0 = rf(serviceID)
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string, int) *service.Service); ok {
+		r0 = rf(serviceID, instanceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.ServiceDetails)
+			r0 = ret.Get(0).(*service.Service)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, int) string); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 string
+	if rf, ok := ret.Get(2).(func(string, int) string); ok {
+		r2 = rf(serviceID, instanceID)
+	} else {
+		r2 = ret.Get(2).(string)
+	}
+
+	var r3 error
+	if rf, ok := ret.Get(3).(func(string, int) error); ok {
+		r3 = rf(serviceID, instanceID)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// GetHost provides a mock function with given fields: hostID
+func (_m *ClientInterface) GetHost(hostID string) (*host.Host, error) {
+	ret := _m.Called(hostID)
+
+	var r0 *host.Host
+	if rf, ok := ret.Get(0).(func(string) *host.Host); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*host.Host)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(serviceID)
+		r1 = rf(hostID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -644,22 +972,22 @@ func (_m *ClientInterface) GetServiceDetails(serviceID string) (*service.Service
 	return r0, r1
 }
 
-// GetServiceDetailsByTenantID provides a mock function with given fields: tenantID
-func (_m *ClientInterface) GetServiceDetailsByTenantID(tenantID string) ([]service.ServiceDetails, error) {
-	ret := _m.Called(tenantID)
+// GetHostPublicKey provides a mock function with given fields: hostID
+func (_m *ClientInterface) GetHostPublicKey(hostID string) ([]byte, error) {
+	ret := _m.Called(hostID)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
-		r0 = rf(tenantID)
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(hostID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).([]byte)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(tenantID)
+		r1 = rf(hostID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -667,22 +995,22 @@ func (_m *ClientInterface) GetServiceDetailsByTenantID(tenantID string) ([]servi
 	return r0, r1
 }
 
-// GetServiceEndpoints provides a mock function with given fields: serviceIDs, reportImports, reportExports, validate
-func (_m *ClientInterface) GetServiceEndpoints(serviceIDs []string, reportImports bool, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error) {
-	ret := _m.Called(serviceIDs, reportImports, reportExports, validate)
+// GetHosts provides a mock function with given fields:
+func (_m *ClientInterface) GetHosts() ([]host.Host, error) {
+	ret := _m.Called()
 
-	var r0 []applicationendpoint.EndpointReport
-	if rf, ok := ret.Get(0).(func([]string, bool, bool, bool) []applicationendpoint.EndpointReport); ok {
-		r0 = rf(serviceIDs, reportImports, reportExports, validate)
+	var r0 []host.Host
+	if rf, ok := ret.Get(0).(func() []host.Host); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]applicationendpoint.EndpointReport)
+			r0 = ret.Get(0).([]host.Host)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]string, bool, bool, bool) error); ok {
-		r1 = rf(serviceIDs, reportImports, reportExports, validate)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -690,22 +1018,22 @@ func (_m *ClientInterface) GetServiceEndpoints(serviceIDs []string, reportImport
 	return r0, r1
 }
 
-// GetServiceInstances provides a mock function with given fields: serviceID
-func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Instance, error) {
-	ret := _m.Called(serviceID)
+// GetHostsChanges provides a mock function with given fields: token
+func (_m *ClientInterface) GetHostsChanges(token string) (*master.HostChanges, error) {
+	ret := _m.Called(token)
 
-	var r0 []service.Instance
-	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
-		r0 = rf(serviceID)
+	var r0 *master.HostChanges
+	if rf, ok := ret.Get(0).(func(string) *master.HostChanges); ok {
+		r0 = rf(token)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.Instance)
+			r0 = ret.Get(0).(*master.HostChanges)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(serviceID)
+		r1 = rf(token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -713,22 +1041,22 @@ func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Inst
 	return r0, r1
 }
 
-// GetServiceTemplates provides a mock function with given fields:
-func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.ServiceTemplate, error) {
-	ret := _m.Called()
+// GetISvcsHealth provides a mock function with given fields: IServiceNames
+func (_m *ClientInterface) GetISvcsHealth(IServiceNames []string) ([]isvcs.IServiceHealthResult, error) {
+	ret := _m.Called(IServiceNames)
 
-	var r0 map[string]servicetemplate.ServiceTemplate
-	if rf, ok := ret.Get(0).(func() map[string]servicetemplate.ServiceTemplate); ok {
-		r0 = rf()
+	var r0 []isvcs.IServiceHealthResult
+	if rf, ok := ret.Get(0).(func([]string) []isvcs.IServiceHealthResult); ok {
+		r0 = rf(IServiceNames)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
+			r0 = ret.Get(0).([]isvcs.IServiceHealthResult)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(IServiceNames)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -736,16 +1064,16 @@ func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.Ser
 	return r0, r1
 }
 
-// GetServicesHealth provides a mock function with given fields:
-func (_m *ClientInterface) GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error) {
+// GetStartupProgress provides a mock function with given fields:
+func (_m *ClientInterface) GetStartupProgress() ([]startup.Status, error) {
 	ret := _m.Called()
 
-	var r0 map[string]map[int]map[string]health.HealthStatus
-	if rf, ok := ret.Get(0).(func() map[string]map[int]map[string]health.HealthStatus); ok {
+	var r0 []startup.Status
+	if rf, ok := ret.Get(0).(func() []startup.Status); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]map[int]map[string]health.HealthStatus)
+			r0 = ret.Get(0).([]startup.Status)
 		}
 	}
 
@@ -759,20 +1087,22 @@ func (_m *ClientInterface) GetServicesHealth() (map[string]map[int]map[string]he
 	return r0, r1
 }
 
-// GetSystemUser provides a mock function with given fields:
-func (_m *ClientInterface) GetSystemUser() (user.User, error) {
-	ret := _m.Called()
+// GetPoolIPs provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
+	ret := _m.Called(poolID)
 
-	var r0 user.User
-	if rf, ok := ret.Get(0).(func() user.User); ok {
-		r0 = rf()
+	var r0 *pool.PoolIPs
+	if rf, ok := ret.Get(0).(func(string) *pool.PoolIPs); ok {
+		r0 = rf(poolID)
 	} else {
-		r0 = ret.Get(0).(user.User)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pool.PoolIPs)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -780,20 +1110,22 @@ func (_m *ClientInterface) GetSystemUser() (user.User, error) {
 	return r0, r1
 }
 
-// GetTenantID provides a mock function with given fields: serviceID
-func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
-	ret := _m.Called(serviceID)
+// GetPublicEndpointsInPool provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetPublicEndpointsInPool(poolID string) ([]service.PublicEndpoint, error) {
+	ret := _m.Called(poolID)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(serviceID)
+	var r0 []service.PublicEndpoint
+	if rf, ok := ret.Get(0).(func(string) []service.PublicEndpoint); ok {
+		r0 = rf(poolID)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.PublicEndpoint)
+		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(serviceID)
+		r1 = rf(poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -801,22 +1133,22 @@ func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
 	return r0, r1
 }
 
-// GetVolumeStatus provides a mock function with given fields:
-func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
-	ret := _m.Called()
+// GetResourcePool provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetResourcePool(poolID string) (*pool.ResourcePool, error) {
+	ret := _m.Called(poolID)
 
-	var r0 *volume.Statuses
-	if rf, ok := ret.Get(0).(func() *volume.Statuses); ok {
-		r0 = rf()
+	var r0 *pool.ResourcePool
+	if rf, ok := ret.Get(0).(func(string) *pool.ResourcePool); ok {
+		r0 = rf(poolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*volume.Statuses)
+			r0 = ret.Get(0).(*pool.ResourcePool)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -824,22 +1156,22 @@ func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
 	return r0, r1
 }
 
-// HostsAuthenticated provides a mock function with given fields: hostIDs
-func (_m *ClientInterface) HostsAuthenticated(hostIDs []string) (map[string]bool, error) {
-	ret := _m.Called(hostIDs)
+// GetResourcePools provides a mock function with given fields:
+func (_m *ClientInterface) GetResourcePools() ([]pool.ResourcePool, error) {
+	ret := _m.Called()
 
-	var r0 map[string]bool
-	if rf, ok := ret.Get(0).(func([]string) map[string]bool); ok {
-		r0 = rf(hostIDs)
+	var r0 []pool.ResourcePool
+	if rf, ok := ret.Get(0).(func() []pool.ResourcePool); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]bool)
+			r0 = ret.Get(0).([]pool.ResourcePool)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]string) error); ok {
-		r1 = rf(hostIDs)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -847,22 +1179,22 @@ func (_m *ClientInterface) HostsAuthenticated(hostIDs []string) (map[string]bool
 	return r0, r1
 }
 
-// LocateServiceInstance provides a mock function with given fields: serviceID, instanceID
-func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
-	ret := _m.Called(serviceID, instanceID)
+// GetService provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetService(serviceID string) (*service.Service, error) {
+	ret := _m.Called(serviceID)
 
-	var r0 *service.LocationInstance
-	if rf, ok := ret.Get(0).(func(string, int) *service.LocationInstance); ok {
-		r0 = rf(serviceID, instanceID)
+	var r0 *service.Service
+	if rf, ok := ret.Get(0).(func(string) *service.Service); ok {
+		r0 = rf(serviceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*service.LocationInstance)
+			r0 = ret.Get(0).(*service.Service)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(serviceID, instanceID)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -870,27 +1202,1415 @@ func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID in
 	return r0, r1
 }
 
-// RemoveHost provides a mock function with given fields: hostID
-func (_m *ClientInterface) RemoveHost(hostID string) error {
-	ret := _m.Called(hostID)
+// GetServiceDetails provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
+	ret := _m.Called(serviceID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(hostID)
+	var r0 *service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string) *service.ServiceDetails); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDetailsByTenantID provides a mock function with given fields: tenantID
+func (_m *ClientInterface) GetServiceDetailsByTenantID(tenantID string) ([]service.ServiceDetails, error) {
+	ret := _m.Called(tenantID)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
+		r0 = rf(tenantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tenantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDetailsChanges provides a mock function with given fields: token
+func (_m *ClientInterface) GetServiceDetailsChanges(token string) (*master.ServiceDetailsChanges, error) {
+	ret := _m.Called(token)
+
+	var r0 *master.ServiceDetailsChanges
+	if rf, ok := ret.Get(0).(func(string) *master.ServiceDetailsChanges); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*master.ServiceDetailsChanges)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceDetailsInPool provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetServiceDetailsInPool(poolID string) ([]service.ServiceDetails, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceEndpointGraph provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceEndpointGraph(serviceID string) (*applicationendpoint.EndpointGraph, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 *applicationendpoint.EndpointGraph
+	if rf, ok := ret.Get(0).(func(string) *applicationendpoint.EndpointGraph); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*applicationendpoint.EndpointGraph)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEndpointMetrics provides a mock function with given fields: serviceID, window
+func (_m *ClientInterface) GetEndpointMetrics(serviceID string, window time.Duration) ([]applicationendpoint.EndpointMetrics, error) {
+	ret := _m.Called(serviceID, window)
+
+	var r0 []applicationendpoint.EndpointMetrics
+	if rf, ok := ret.Get(0).(func(string, time.Duration) []applicationendpoint.EndpointMetrics); ok {
+		r0 = rf(serviceID, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointMetrics)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(serviceID, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceEndpoints provides a mock function with given fields: serviceIDs, reportImports, reportExports, validate
+func (_m *ClientInterface) GetServiceEndpoints(serviceIDs []string, reportImports bool, reportExports bool, validate bool) ([]applicationendpoint.EndpointReport, error) {
+	ret := _m.Called(serviceIDs, reportImports, reportExports, validate)
+
+	var r0 []applicationendpoint.EndpointReport
+	if rf, ok := ret.Get(0).(func([]string, bool, bool, bool) []applicationendpoint.EndpointReport); ok {
+		r0 = rf(serviceIDs, reportImports, reportExports, validate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]applicationendpoint.EndpointReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string, bool, bool, bool) error); ok {
+		r1 = rf(serviceIDs, reportImports, reportExports, validate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceInstances provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Instance, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.Instance
+	if rf, ok := ret.Get(0).(func(string) []service.Instance); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Instance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceInstanceHistory provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceInstanceHistory(serviceID string) ([]service.InstanceHistory, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.InstanceHistory
+	if rf, ok := ret.Get(0).(func(string) []service.InstanceHistory); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.InstanceHistory)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceLogs provides a mock function with given fields: query
+func (_m *ClientInterface) GetServiceLogs(query service.LogQuery) ([]service.LogMessage, error) {
+	ret := _m.Called(query)
+
+	var r0 []service.LogMessage
+	if rf, ok := ret.Get(0).(func(service.LogQuery) []service.LogMessage); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.LogMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(service.LogQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchema provides a mock function with given fields: name
+func (_m *ClientInterface) GetSchema(name string) (*validation.Schema, error) {
+	ret := _m.Called(name)
+
+	var r0 *validation.Schema
+	if rf, ok := ret.Get(0).(func(string) *validation.Schema); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*validation.Schema)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceTemplates provides a mock function with given fields:
+func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.ServiceTemplate, error) {
+	ret := _m.Called()
+
+	var r0 map[string]servicetemplate.ServiceTemplate
+	if rf, ok := ret.Get(0).(func() map[string]servicetemplate.ServiceTemplate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]servicetemplate.ServiceTemplate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServicesHealth provides a mock function with given fields:
+func (_m *ClientInterface) GetServicesHealth() (map[string]map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called()
+
+	var r0 map[string]map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func() map[string]map[int]map[string]health.HealthStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHealth provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceHealth(serviceID string) (map[int]map[string]health.HealthStatus, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 map[int]map[string]health.HealthStatus
+	if rf, ok := ret.Get(0).(func(string) map[int]map[string]health.HealthStatus); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]map[string]health.HealthStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSystemUser provides a mock function with given fields:
+func (_m *ClientInterface) GetSystemUser() (user.User, error) {
+	ret := _m.Called()
+
+	var r0 user.User
+	if rf, ok := ret.Get(0).(func() user.User); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(user.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTenantID provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVolumeStatus provides a mock function with given fields:
+func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
+	ret := _m.Called()
+
+	var r0 *volume.Statuses
+	if rf, ok := ret.Get(0).(func() *volume.Statuses); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*volume.Statuses)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetZKEnsemble provides a mock function with given fields:
+func (_m *ClientInterface) GetZKEnsemble() ([]isvcs.ZKMemberStatus, error) {
+	ret := _m.Called()
+
+	var r0 []isvcs.ZKMemberStatus
+	if rf, ok := ret.Get(0).(func() []isvcs.ZKMemberStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]isvcs.ZKMemberStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckDFS provides a mock function with given fields: repair
+func (_m *ClientInterface) CheckDFS(repair bool) ([]dfs.FsckProblem, error) {
+	ret := _m.Called(repair)
+
+	var r0 []dfs.FsckProblem
+	if rf, ok := ret.Get(0).(func(bool) []dfs.FsckProblem); ok {
+		r0 = rf(repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dfs.FsckProblem)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRollbackPreview provides a mock function with given fields: snapshotID
+func (_m *ClientInterface) GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error) {
+	ret := _m.Called(snapshotID)
+
+	var r0 *dfs.RollbackPreview
+	if rf, ok := ret.Get(0).(func(string) *dfs.RollbackPreview); ok {
+		r0 = rf(snapshotID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.RollbackPreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(snapshotID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResizeVolume provides a mock function with given fields: serviceID, size
+func (_m *ClientInterface) ResizeVolume(serviceID string, size uint64) (*volume.Quota, error) {
+	ret := _m.Called(serviceID, size)
+
+	var r0 *volume.Quota
+	if rf, ok := ret.Get(0).(func(string, uint64) *volume.Quota); ok {
+		r0 = rf(serviceID, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*volume.Quota)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64) error); ok {
+		r1 = rf(serviceID, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HostsAuthenticated provides a mock function with given fields: hostIDs
+func (_m *ClientInterface) HostsAuthenticated(hostIDs []string) (map[string]bool, error) {
+	ret := _m.Called(hostIDs)
+
+	var r0 map[string]bool
+	if rf, ok := ret.Get(0).(func([]string) map[string]bool); ok {
+		r0 = rf(hostIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(hostIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LocateServiceInstance provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 *service.LocationInstance
+	if rf, ok := ret.Get(0).(func(string, int) *service.LocationInstance); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.LocationInstance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneBackupLayers provides a mock function with given fields:
+func (_m *ClientInterface) PruneBackupLayers() (int, int64, error) {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func() int64); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// VerifyBackup provides a mock function with given fields: filename, testRestore
+func (_m *ClientInterface) VerifyBackup(filename string, testRestore bool) (*dfs.BackupVerification, error) {
+	ret := _m.Called(filename, testRestore)
+
+	var r0 *dfs.BackupVerification
+	if rf, ok := ret.Get(0).(func(string, bool) *dfs.BackupVerification); ok {
+		r0 = rf(filename, testRestore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(filename, testRestore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBackupProgress provides a mock function with given fields:
+func (_m *ClientInterface) GetBackupProgress() (*dao.BackupProgress, error) {
+	ret := _m.Called()
+
+	var r0 *dao.BackupProgress
+	if rf, ok := ret.Get(0).(func() *dao.BackupProgress); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.BackupProgress)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBackupSchedules provides a mock function with given fields:
+func (_m *ClientInterface) GetBackupSchedules() ([]backupschedule.BackupSchedule, error) {
+	ret := _m.Called()
+
+	var r0 []backupschedule.BackupSchedule
+	if rf, ok := ret.Get(0).(func() []backupschedule.BackupSchedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backupschedule.BackupSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddBackupSchedule provides a mock function with given fields: schedule
+func (_m *ClientInterface) AddBackupSchedule(schedule backupschedule.BackupSchedule) (string, error) {
+	ret := _m.Called(schedule)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(backupschedule.BackupSchedule) string); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(backupschedule.BackupSchedule) error); ok {
+		r1 = rf(schedule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveBackupSchedule provides a mock function with given fields: id
+func (_m *ClientInterface) RemoveBackupSchedule(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveCalendar provides a mock function with given fields: calendarID
+func (_m *ClientInterface) RemoveCalendar(calendarID string) error {
+	ret := _m.Called(calendarID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(calendarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFeatureFlags provides a mock function with given fields:
+func (_m *ClientInterface) GetFeatureFlags() ([]feature.Flag, error) {
+	ret := _m.Called()
+
+	var r0 []feature.Flag
+	if rf, ok := ret.Get(0).(func() []feature.Flag); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]feature.Flag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnableFeature provides a mock function with given fields: name, poolID, deploymentID
+func (_m *ClientInterface) EnableFeature(name string, poolID string, deploymentID string) error {
+	ret := _m.Called(name, poolID, deploymentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, poolID, deploymentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableFeature provides a mock function with given fields: name, poolID, deploymentID
+func (_m *ClientInterface) DisableFeature(name string, poolID string, deploymentID string) error {
+	ret := _m.Called(name, poolID, deploymentID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, poolID, deploymentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSettings provides a mock function with given fields:
+func (_m *ClientInterface) GetSettings() ([]setting.Setting, error) {
+	ret := _m.Called()
+
+	var r0 []setting.Setting
+	if rf, ok := ret.Get(0).(func() []setting.Setting); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]setting.Setting)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSetting provides a mock function with given fields: name, scope, scopeID, value
+func (_m *ClientInterface) SetSetting(name string, scope setting.Scope, scopeID string, value string) error {
+	ret := _m.Called(name, scope, scopeID, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, setting.Scope, string, string) error); ok {
+		r0 = rf(name, scope, scopeID, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnsetSetting provides a mock function with given fields: name, scope, scopeID
+func (_m *ClientInterface) UnsetSetting(name string, scope setting.Scope, scopeID string) error {
+	ret := _m.Called(name, scope, scopeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, setting.Scope, string) error); ok {
+		r0 = rf(name, scope, scopeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSecrets provides a mock function with given fields:
+func (_m *ClientInterface) GetSecrets() ([]secret.Secret, error) {
+	ret := _m.Called()
+
+	var r0 []secret.Secret
+	if rf, ok := ret.Get(0).(func() []secret.Secret); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]secret.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSecretValue provides a mock function with given fields: name
+func (_m *ClientInterface) GetSecretValue(name string) (string, error) {
+	ret := _m.Called(name)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetSecret provides a mock function with given fields: name, description, value
+func (_m *ClientInterface) SetSecret(name string, description string, value string) error {
+	ret := _m.Called(name, description, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(name, description, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveSecret provides a mock function with given fields: name
+func (_m *ClientInterface) RemoveSecret(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetServiceSecrets provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) GetServiceSecrets(serviceID string, instanceID int) (map[string]string, error) {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(string, int) map[string]string); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(serviceID, instanceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveHost provides a mock function with given fields: hostID
+func (_m *ClientInterface) RemoveHost(hostID string) error {
+	ret := _m.Called(hostID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(hostID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePublicEndpointPort provides a mock function with given fields: serviceid, endpointName, portAddr
+func (_m *ClientInterface) RemovePublicEndpointPort(serviceid string, endpointName string, portAddr string) error {
+	ret := _m.Called(serviceid, endpointName, portAddr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePublicEndpointVHost provides a mock function with given fields: serviceid, endpointName, vhost
+func (_m *ClientInterface) RemovePublicEndpointVHost(serviceid string, endpointName string, vhost string) error {
+	ret := _m.Called(serviceid, endpointName, vhost)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(serviceid, endpointName, vhost)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveResourcePool provides a mock function with given fields: poolID
+func (_m *ClientInterface) RemoveResourcePool(poolID string) error {
+	ret := _m.Called(poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveServiceTemplate provides a mock function with given fields: serviceTemplateID
+func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error {
+	ret := _m.Called(serviceTemplateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(serviceTemplateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCrossPoolFlows provides a mock function with given fields:
+func (_m *ClientInterface) GetCrossPoolFlows() ([]pool.CrossPoolFlow, error) {
+	ret := _m.Called()
+
+	var r0 []pool.CrossPoolFlow
+	if rf, ok := ret.Get(0).(func() []pool.CrossPoolFlow); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.CrossPoolFlow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVirtualIPEvents provides a mock function with given fields: poolID
+func (_m *ClientInterface) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	ret := _m.Called(poolID)
+
+	var r0 []pool.VirtualIPEvent
+	if rf, ok := ret.Get(0).(func(string) []pool.VirtualIPEvent); ok {
+		r0 = rf(poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pool.VirtualIPEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveVirtualIP provides a mock function with given fields: requestVirtualIP
+func (_m *ClientInterface) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
+	ret := _m.Called(requestVirtualIP)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(pool.VirtualIP) error); ok {
+		r0 = rf(requestVirtualIP)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveZKEnsembleMember provides a mock function with given fields: host
+func (_m *ClientInterface) RemoveZKEnsembleMember(host string) (*master.ZKEnsembleChange, error) {
+	ret := _m.Called(host)
+
+	var r0 *master.ZKEnsembleChange
+	if rf, ok := ret.Get(0).(func(string) *master.ZKEnsembleChange); ok {
+		r0 = rf(host)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*master.ZKEnsembleChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(host)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReportHealthStatus provides a mock function with given fields: key, value, expires
+func (_m *ClientInterface) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) error {
+	ret := _m.Called(key, value, expires)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(health.HealthStatusKey, health.HealthStatus, time.Duration) error); ok {
+		r0 = rf(key, value, expires)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportInstanceDead provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) ReportInstanceDead(serviceID string, instanceID int) error {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetHostKey provides a mock function with given fields: hostID
+func (_m *ClientInterface) ResetHostKey(hostID string) ([]byte, error) {
+	ret := _m.Called(hostID)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(hostID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResetRegistry provides a mock function with given fields:
+func (_m *ClientInterface) ResetRegistry() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveServicePath provides a mock function with given fields: path, noprefix
+func (_m *ClientInterface) ResolveServicePath(path string, noprefix bool) ([]service.ServiceDetails, error) {
+	ret := _m.Called(path,noprefix)
+
+	var r0 []service.ServiceDetails
+	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
+		r0 = rf(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ServiceDetails)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreElastic provides a mock function with given fields: filename, clusters
+func (_m *ClientInterface) RestoreElastic(filename string, clusters []string) error {
+	ret := _m.Called(filename, clusters)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(filename, clusters)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResumeBackupOperation provides a mock function with given fields: id
+func (_m *ClientInterface) ResumeBackupOperation(id string) (string, error) {
+	ret := _m.Called(id)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendDockerAction provides a mock function with given fields: serviceID, instanceID, action, args
+func (_m *ClientInterface) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
+	ret := _m.Called(serviceID, instanceID, action, args)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
+		r0 = rf(serviceID, instanceID, action, args)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetConfigDrift provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetConfigDrift(serviceID string) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string) []service.ConfigDrift); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncServiceConfigs provides a mock function with given fields: serviceID, restart
+func (_m *ClientInterface) SyncServiceConfigs(serviceID string, restart bool) ([]service.ConfigDrift, error) {
+	ret := _m.Called(serviceID, restart)
+
+	var r0 []service.ConfigDrift
+	if rf, ok := ret.Get(0).(func(string, bool) []service.ConfigDrift); ok {
+		r0 = rf(serviceID, restart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.ConfigDrift)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(serviceID, restart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ServiceUse provides a mock function with given fields: serviceID, imageID, registry, replaceImgs, noOp
+func (_m *ClientInterface) ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error) {
+	ret := _m.Called(serviceID, imageID, registry, replaceImgs, noOp)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, []string, bool) string); ok {
+		r0 = rf(serviceID, imageID, registry, replaceImgs, noOp)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, []string, bool) error); ok {
+		r1 = rf(serviceID, imageID, registry, replaceImgs, noOp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BenchmarkNetwork provides a mock function with given fields: size
+func (_m *ClientInterface) BenchmarkNetwork(size int) (uint64, error) {
+	ret := _m.Called(size)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(int) uint64); ok {
+		r0 = rf(size)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetHostMaintenance provides a mock function with given fields: hostID, enabled
+func (_m *ClientInterface) SetHostMaintenance(hostID string, enabled bool) error {
+	ret := _m.Called(hostID, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(hostID, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StopServiceInstance provides a mock function with given fields: serviceID, instanceID
+func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
+	ret := _m.Called(serviceID, instanceID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int) error); ok {
+		r0 = rf(serviceID, instanceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetServiceInstanceEnvironment provides a mock function with given fields: serviceID, instanceID, env
+func (_m *ClientInterface) SetServiceInstanceEnvironment(serviceID string, instanceID int, env []string) error {
+	ret := _m.Called(serviceID, instanceID, env)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, int, []string) error); ok {
+		r0 = rf(serviceID, instanceID, env)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncRegistry provides a mock function with given fields:
+func (_m *ClientInterface) SyncRegistry() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCalendar provides a mock function with given fields: c
+func (_m *ClientInterface) UpdateCalendar(c calendar.Calendar) error {
+	ret := _m.Called(c)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(calendar.Calendar) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateHost provides a mock function with given fields: h
+func (_m *ClientInterface) UpdateHost(h host.Host) error {
+	ret := _m.Called(h)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(host.Host) error); ok {
+		r0 = rf(h)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateResourcePool provides a mock function with given fields: p
+func (_m *ClientInterface) UpdateResourcePool(p pool.ResourcePool) error {
+	ret := _m.Called(p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(pool.ResourcePool) error); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpgradeRegistry provides a mock function with given fields: endpoint, override
+func (_m *ClientInterface) UpgradeRegistry(endpoint string, override bool) error {
+	ret := _m.Called(endpoint, override)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
+		r0 = rf(endpoint, override)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateCredentials provides a mock function with given fields: _a0
+func (_m *ClientInterface) ValidateCredentials(_a0 user.User) (bool, error) {
+	ret := _m.Called(_a0)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(user.User) bool); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(user.User) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitServiceStateEvents provides a mock function with given fields: serviceID, since, timeout
+func (_m *ClientInterface) WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error) {
+	ret := _m.Called(serviceID, since, timeout)
+
+	var r0 *service.StateEvents
+	if rf, ok := ret.Get(0).(func(string, uint64, time.Duration) *service.StateEvents); ok {
+		r0 = rf(serviceID, since, timeout)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.StateEvents)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64, time.Duration) error); ok {
+		r1 = rf(serviceID, since, timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// RemovePublicEndpointPort provides a mock function with given fields: serviceid, endpointName, portAddr
-func (_m *ClientInterface) RemovePublicEndpointPort(serviceid string, endpointName string, portAddr string) error {
-	ret := _m.Called(serviceid, endpointName, portAddr)
+// WaitService provides a mock function with given fields: serviceIDs, state, timeout, recursive
+func (_m *ClientInterface) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
+	ret := _m.Called(serviceIDs, state, timeout, recursive)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(serviceid, endpointName, portAddr)
+	if rf, ok := ret.Get(0).(func([]string, service.DesiredState, time.Duration, bool) error); ok {
+		r0 = rf(serviceIDs, state, timeout, recursive)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -898,27 +2618,23 @@ func (_m *ClientInterface) RemovePublicEndpointPort(serviceid string, endpointNa
 	return r0
 }
 
-// RemovePublicEndpointVHost provides a mock function with given fields: serviceid, endpointName, vhost
-func (_m *ClientInterface) RemovePublicEndpointVHost(serviceid string, endpointName string, vhost string) error {
-	ret := _m.Called(serviceid, endpointName, vhost)
-
+func (_m *ClientInterface) RemoveIPs(args []string) error {
+	ret := _m.Called(args)
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(serviceid, endpointName, vhost)
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(args)
 	} else {
 		r0 = ret.Error(0)
 	}
-
 	return r0
 }
 
-// RemoveResourcePool provides a mock function with given fields: poolID
-func (_m *ClientInterface) RemoveResourcePool(poolID string) error {
-	ret := _m.Called(poolID)
+func (_m *ClientInterface) SetIPs(assignmentRequest addressassignment.AssignmentRequest) error {
+	ret := _m.Called(assignmentRequest)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(poolID)
+	if rf, ok := ret.Get(0).(func(addressassignment.AssignmentRequest) error); ok {
+		r0 = rf(assignmentRequest)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -926,13 +2642,38 @@ func (_m *ClientInterface) RemoveResourcePool(poolID string) error {
 	return r0
 }
 
-// RemoveServiceTemplate provides a mock function with given fields: serviceTemplateID
-func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error {
-	ret := _m.Called(serviceTemplateID)
+var _ master.ClientInterface = (*ClientInterface)(nil)
+
+// GetPins provides a mock function with given fields:
+func (_m *ClientInterface) GetPins() ([]pin.Pin, error) {
+	ret := _m.Called()
+
+	var r0 []pin.Pin
+	if rf, ok := ret.Get(0).(func() []pin.Pin); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pin.Pin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PinSnapshot provides a mock function with given fields: snapshotID, reason
+func (_m *ClientInterface) PinSnapshot(snapshotID string, reason string) error {
+	ret := _m.Called(snapshotID, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(serviceTemplateID)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(snapshotID, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -940,13 +2681,13 @@ func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error
 	return r0
 }
 
-// RemoveVirtualIP provides a mock function with given fields: requestVirtualIP
-func (_m *ClientInterface) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
-	ret := _m.Called(requestVirtualIP)
+// PinImage provides a mock function with given fields: image, reason
+func (_m *ClientInterface) PinImage(image string, reason string) error {
+	ret := _m.Called(image, reason)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(pool.VirtualIP) error); ok {
-		r0 = rf(requestVirtualIP)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(image, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -954,13 +2695,13 @@ func (_m *ClientInterface) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) erro
 	return r0
 }
 
-// ReportHealthStatus provides a mock function with given fields: key, value, expires
-func (_m *ClientInterface) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) error {
-	ret := _m.Called(key, value, expires)
+// Unpin provides a mock function with given fields: kind, target
+func (_m *ClientInterface) Unpin(kind pin.Kind, target string) error {
+	ret := _m.Called(kind, target)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(health.HealthStatusKey, health.HealthStatus, time.Duration) error); ok {
-		r0 = rf(key, value, expires)
+	if rf, ok := ret.Get(0).(func(pin.Kind, string) error); ok {
+		r0 = rf(kind, target)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -968,13 +2709,13 @@ func (_m *ClientInterface) ReportHealthStatus(key health.HealthStatusKey, value
 	return r0
 }
 
-// ReportInstanceDead provides a mock function with given fields: serviceID, instanceID
-func (_m *ClientInterface) ReportInstanceDead(serviceID string, instanceID int) error {
-	ret := _m.Called(serviceID, instanceID)
+// CheckInteractiveAccess provides a mock function with given fields: serviceID, hostID
+func (_m *ClientInterface) CheckInteractiveAccess(serviceID string, hostID string) error {
+	ret := _m.Called(serviceID, hostID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(serviceID, instanceID)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(serviceID, hostID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -982,22 +2723,22 @@ func (_m *ClientInterface) ReportInstanceDead(serviceID string, instanceID int)
 	return r0
 }
 
-// ResetHostKey provides a mock function with given fields: hostID
-func (_m *ClientInterface) ResetHostKey(hostID string) ([]byte, error) {
-	ret := _m.Called(hostID)
+// GetDelegateProfiles provides a mock function with given fields:
+func (_m *ClientInterface) GetDelegateProfiles() ([]delegateprofile.Profile, error) {
+	ret := _m.Called()
 
-	var r0 []byte
-	if rf, ok := ret.Get(0).(func(string) []byte); ok {
-		r0 = rf(hostID)
+	var r0 []delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func() []delegateprofile.Profile); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).([]delegateprofile.Profile)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(hostID)
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1005,36 +2746,22 @@ func (_m *ClientInterface) ResetHostKey(hostID string) ([]byte, error) {
 	return r0, r1
 }
 
-// ResetRegistry provides a mock function with given fields:
-func (_m *ClientInterface) ResetRegistry() error {
-	ret := _m.Called()
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// ResolveServicePath provides a mock function with given fields: path, noprefix
-func (_m *ClientInterface) ResolveServicePath(path string, noprefix bool) ([]service.ServiceDetails, error) {
-	ret := _m.Called(path,noprefix)
+// GetDelegateProfile provides a mock function with given fields: name
+func (_m *ClientInterface) GetDelegateProfile(name string) (*delegateprofile.Profile, error) {
+	ret := _m.Called(name)
 
-	var r0 []service.ServiceDetails
-	if rf, ok := ret.Get(0).(func(string) []service.ServiceDetails); ok {
-		r0 = rf(path)
+	var r0 *delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(string) *delegateprofile.Profile); ok {
+		r0 = rf(name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]service.ServiceDetails)
+			r0 = ret.Get(0).(*delegateprofile.Profile)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(path)
+		r1 = rf(name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1042,34 +2769,22 @@ func (_m *ClientInterface) ResolveServicePath(path string, noprefix bool) ([]ser
 	return r0, r1
 }
 
-// SendDockerAction provides a mock function with given fields: serviceID, instanceID, action, args
-func (_m *ClientInterface) SendDockerAction(serviceID string, instanceID int, action string, args []string) error {
-	ret := _m.Called(serviceID, instanceID, action, args)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
-		r0 = rf(serviceID, instanceID, action, args)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// ServiceUse provides a mock function with given fields: serviceID, imageID, registry, replaceImgs, noOp
-func (_m *ClientInterface) ServiceUse(serviceID string, imageID string, registry string, replaceImgs []string, noOp bool) (string, error) {
-	ret := _m.Called(serviceID, imageID, registry, replaceImgs, noOp)
+// GetHostDelegateProfile provides a mock function with given fields: hostID
+func (_m *ClientInterface) GetHostDelegateProfile(hostID string) (*delegateprofile.Profile, error) {
+	ret := _m.Called(hostID)
 
-	var r0 string
-	if rf, ok := ret.Get(0).(func(string, string, string, []string, bool) string); ok {
-		r0 = rf(serviceID, imageID, registry, replaceImgs, noOp)
+	var r0 *delegateprofile.Profile
+	if rf, ok := ret.Get(0).(func(string) *delegateprofile.Profile); ok {
+		r0 = rf(hostID)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delegateprofile.Profile)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, []string, bool) error); ok {
-		r1 = rf(serviceID, imageID, registry, replaceImgs, noOp)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1077,13 +2792,13 @@ func (_m *ClientInterface) ServiceUse(serviceID string, imageID string, registry
 	return r0, r1
 }
 
-// StopServiceInstance provides a mock function with given fields: serviceID, instanceID
-func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
-	ret := _m.Called(serviceID, instanceID)
+// AddDelegateProfile provides a mock function with given fields: name, description
+func (_m *ClientInterface) AddDelegateProfile(name string, description string) error {
+	ret := _m.Called(name, description)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int) error); ok {
-		r0 = rf(serviceID, instanceID)
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, description)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1091,13 +2806,13 @@ func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int)
 	return r0
 }
 
-// SyncRegistry provides a mock function with given fields:
-func (_m *ClientInterface) SyncRegistry() error {
-	ret := _m.Called()
+// RemoveDelegateProfile provides a mock function with given fields: name
+func (_m *ClientInterface) RemoveDelegateProfile(name string) error {
+	ret := _m.Called(name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -1105,62 +2820,68 @@ func (_m *ClientInterface) SyncRegistry() error {
 	return r0
 }
 
-// UpdateHost provides a mock function with given fields: h
-func (_m *ClientInterface) UpdateHost(h host.Host) error {
-	ret := _m.Called(h)
+// SetDelegateProfileOption provides a mock function with given fields: name, option, value
+func (_m *ClientInterface) SetDelegateProfileOption(name string, option string, value string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, option, value)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(host.Host) error); ok {
-		r0 = rf(h)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, option, value)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
-	return r0
-}
-
-// UpdateResourcePool provides a mock function with given fields: p
-func (_m *ClientInterface) UpdateResourcePool(p pool.ResourcePool) error {
-	ret := _m.Called(p)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(pool.ResourcePool) error); ok {
-		r0 = rf(p)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(name, option, value)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// UpgradeRegistry provides a mock function with given fields: endpoint, override
-func (_m *ClientInterface) UpgradeRegistry(endpoint string, override bool) error {
-	ret := _m.Called(endpoint, override)
+// UnsetDelegateProfileOption provides a mock function with given fields: name, option
+func (_m *ClientInterface) UnsetDelegateProfileOption(name string, option string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, option)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(string, bool) error); ok {
-		r0 = rf(endpoint, override)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, option)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, option)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// ValidateCredentials provides a mock function with given fields: _a0
-func (_m *ClientInterface) ValidateCredentials(_a0 user.User) (bool, error) {
-	ret := _m.Called(_a0)
+// RollbackDelegateProfile provides a mock function with given fields: name, version
+func (_m *ClientInterface) RollbackDelegateProfile(name string, version int) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(name, version)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(user.User) bool); ok {
-		r0 = rf(_a0)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, int) []delegateprofile.HostStatus); ok {
+		r0 = rf(name, version)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(user.User) error); ok {
-		r1 = rf(_a0)
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(name, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1168,42 +2889,48 @@ func (_m *ClientInterface) ValidateCredentials(_a0 user.User) (bool, error) {
 	return r0, r1
 }
 
-// WaitService provides a mock function with given fields: serviceIDs, state, timeout, recursive
-func (_m *ClientInterface) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
-	ret := _m.Called(serviceIDs, state, timeout, recursive)
+// AssignDelegateProfile provides a mock function with given fields: poolID, name
+func (_m *ClientInterface) AssignDelegateProfile(poolID string, name string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(poolID, name)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]string, service.DesiredState, time.Duration, bool) error); ok {
-		r0 = rf(serviceIDs, state, timeout, recursive)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string, string) []delegateprofile.HostStatus); ok {
+		r0 = rf(poolID, name)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
-	return r0
-}
-
-func (_m *ClientInterface) RemoveIPs(args []string) error {
-	ret := _m.Called(args)
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]string) error); ok {
-		r0 = rf(args)
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(poolID, name)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
-	return r0
+
+	return r0, r1
 }
 
-func (_m *ClientInterface) SetIPs(assignmentRequest addressassignment.AssignmentRequest) error {
-	ret := _m.Called(assignmentRequest)
+// PushDelegateProfile provides a mock function with given fields: poolID
+func (_m *ClientInterface) PushDelegateProfile(poolID string) ([]delegateprofile.HostStatus, error) {
+	ret := _m.Called(poolID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(addressassignment.AssignmentRequest) error); ok {
-		r0 = rf(assignmentRequest)
+	var r0 []delegateprofile.HostStatus
+	if rf, ok := ret.Get(0).(func(string) []delegateprofile.HostStatus); ok {
+		r0 = rf(poolID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]delegateprofile.HostStatus)
+		}
 	}
 
-	return r0
-}
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
 
-var _ master.ClientInterface = (*ClientInterface)(nil)
+	return r0, r1
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dao"
+)
+
+// GetOperations returns the operations that are running or recently ended
+func (c *Client) GetOperations() ([]dao.Operation, error) {
+	response := make([]dao.Operation, 0)
+	if err := c.call("GetOperations", empty, &response); err != nil {
+		return []dao.Operation{}, err
+	}
+	return response, nil
+}
+
+// GetOperation returns the status, progress and logs of an operation
+func (c *Client) GetOperation(id string) (*dao.Operation, error) {
+	response := &dao.Operation{}
+	if err := c.call("GetOperation", id, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CancelOperation asks a running operation to stop
+func (c *Client) CancelOperation(id string) error {
+	return c.call("CancelOperation", id, nil)
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/dao"
+)
+
+// GetOperations returns the operations that are running or recently ended
+func (s *Server) GetOperations(empty struct{}, reply *[]dao.Operation) error {
+	ops, err := s.f.GetOperations(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = ops
+	return nil
+}
+
+// GetOperation returns the status, progress and logs of an operation
+func (s *Server) GetOperation(id string, reply *dao.Operation) error {
+	op, err := s.f.GetOperation(s.context(), id)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *op
+	return nil
+}
+
+// CancelOperation asks a running operation to stop
+func (s *Server) CancelOperation(id string, _ *struct{}) error {
+	return rpcError(s.f.CancelOperation(s.context(), id))
+}
@@ -0,0 +1,47 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/pin"
+)
+
+// GetPins returns the pinned snapshots and image tags
+func (c *Client) GetPins() ([]pin.Pin, error) {
+	response := make([]pin.Pin, 0)
+	if err := c.call("GetPins", empty, &response); err != nil {
+		return []pin.Pin{}, err
+	}
+	return response, nil
+}
+
+// PinSnapshot protects a snapshot from the snapshot TTL and from being
+// deleted
+func (c *Client) PinSnapshot(snapshotID, reason string) error {
+	request := PinRequest{Kind: pin.KindSnapshot, Target: snapshotID, Reason: reason}
+	return c.call("Pin", request, nil)
+}
+
+// PinImage protects a tag of an image in the docker registry from registry
+// cleanup and from being deleted
+func (c *Client) PinImage(image, reason string) error {
+	request := PinRequest{Kind: pin.KindImage, Target: image, Reason: reason}
+	return c.call("Pin", request, nil)
+}
+
+// Unpin removes the pin of a snapshot or image tag
+func (c *Client) Unpin(kind pin.Kind, target string) error {
+	request := PinRequest{Kind: kind, Target: target}
+	return c.call("Unpin", request, nil)
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/pin"
+	"github.com/control-center/serviced/facade"
+)
+
+// PinRequest is the request to pin or unpin a snapshot or image tag
+type PinRequest struct {
+	Kind   pin.Kind
+	Target string
+	Reason string
+}
+
+// GetPins returns the pinned snapshots and image tags
+func (s *Server) GetPins(empty struct{}, reply *[]pin.Pin) error {
+	pins, err := s.f.GetPins(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = pins
+	return nil
+}
+
+// Pin pins a snapshot or image tag
+func (s *Server) Pin(request PinRequest, _ *struct{}) error {
+	switch request.Kind {
+	case pin.KindSnapshot:
+		return rpcError(s.f.PinSnapshot(s.context(), request.Target, request.Reason))
+	case pin.KindImage:
+		return rpcError(s.f.PinImage(s.context(), request.Target, request.Reason))
+	default:
+		return rpcError(facade.ErrInvalidPinKind)
+	}
+}
+
+// Unpin removes the pin of a snapshot or image tag
+func (s *Server) Unpin(request PinRequest, _ *struct{}) error {
+	return rpcError(s.f.Unpin(s.context(), request.Kind, request.Target))
+}
@@ -59,6 +59,27 @@ func (c *Client) GetPoolIPs(poolID string) (*pool.PoolIPs, error) {
 	return &poolIPs, nil
 }
 
+// GetVirtualIPEvents returns the reachability and failover events of the
+// virtual IPs of a pool, from oldest to newest.
+func (c *Client) GetVirtualIPEvents(poolID string) ([]pool.VirtualIPEvent, error) {
+	events := []pool.VirtualIPEvent{}
+	if err := c.call("GetVirtualIPEvents", poolID, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetCrossPoolFlows returns the running imports of endpoints that services
+// in other pools export, and whether the policy of the export pool allows
+// them.
+func (c *Client) GetCrossPoolFlows() ([]pool.CrossPoolFlow, error) {
+	flows := []pool.CrossPoolFlow{}
+	if err := c.call("GetCrossPoolFlows", empty, &flows); err != nil {
+		return nil, err
+	}
+	return flows, nil
+}
+
 //AddVirtualIP adds a VirtualIP to a specificpool
 func (c *Client) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return c.call("AddVirtualIP", requestVirtualIP, nil)
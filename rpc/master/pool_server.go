@@ -14,8 +14,7 @@
 package master
 
 import (
-	"errors"
-
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/domain/pool"
 )
 
@@ -23,7 +22,7 @@ import (
 func (s *Server) GetResourcePools(empty struct{}, poolsReply *[]pool.ResourcePool) error {
 	pools, err := s.f.GetResourcePools(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 
 	*poolsReply = pools
@@ -32,22 +31,22 @@ func (s *Server) GetResourcePools(empty struct{}, poolsReply *[]pool.ResourcePoo
 
 // AddResourcePool adds the pool
 func (s *Server) AddResourcePool(pool pool.ResourcePool, _ *struct{}) error {
-	return s.f.AddResourcePool(s.context(), &pool)
+	return rpcError(s.f.AddResourcePool(s.context(), &pool))
 }
 
 // UpdateResourcePool updates the pool
 func (s *Server) UpdateResourcePool(pool pool.ResourcePool, _ *struct{}) error {
-	return s.f.UpdateResourcePool(s.context(), &pool)
+	return rpcError(s.f.UpdateResourcePool(s.context(), &pool))
 }
 
 // GetResourcePool gets the pool
 func (s *Server) GetResourcePool(poolID string, reply *pool.ResourcePool) error {
 	response, err := s.f.GetResourcePool(s.context(), poolID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	if response == nil {
-		return errors.New("pool not found")
+		return rpcError(apierror.New(apierror.NotFound, "pool not found"))
 	}
 	*reply = *response
 	return nil
@@ -55,17 +54,39 @@ func (s *Server) GetResourcePool(poolID string, reply *pool.ResourcePool) error
 
 // RemoveResourcePool removes the pool
 func (s *Server) RemoveResourcePool(poolID string, _ *struct{}) error {
-	return s.f.RemoveResourcePool(s.context(), poolID)
+	return rpcError(s.f.RemoveResourcePool(s.context(), poolID))
+}
+
+// GetVirtualIPEvents gets the reachability and failover events of the
+// virtual ips of a pool
+func (s *Server) GetVirtualIPEvents(poolID string, reply *[]pool.VirtualIPEvent) error {
+	events, err := s.f.GetVirtualIPEvents(s.context(), poolID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = events
+	return nil
+}
+
+// GetCrossPoolFlows gets the running imports of endpoints that services in
+// other pools export
+func (s *Server) GetCrossPoolFlows(empty struct{}, reply *[]pool.CrossPoolFlow) error {
+	flows, err := s.f.GetCrossPoolFlows(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = flows
+	return nil
 }
 
 // GetPoolIPs gets all ips available to a pool
 func (s *Server) GetPoolIPs(poolID string, reply *pool.PoolIPs) error {
 	response, err := s.f.GetPoolIPs(s.context(), poolID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	if response == nil {
-		return errors.New("pool not found")
+		return rpcError(apierror.New(apierror.NotFound, "pool not found"))
 	}
 	*reply = *response
 	return nil
@@ -73,10 +94,10 @@ func (s *Server) GetPoolIPs(poolID string, reply *pool.PoolIPs) error {
 
 // AddVirtualIP adds a specific virtual IP to a pool
 func (s *Server) AddVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
-	return s.f.AddVirtualIP(s.context(), requestVirtualIP)
+	return rpcError(s.f.AddVirtualIP(s.context(), requestVirtualIP))
 }
 
 // RemoveVirtualIP removes a specific virtual IP from a pool
 func (s *Server) RemoveVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
-	return s.f.RemoveVirtualIP(s.context(), requestVirtualIP)
+	return rpcError(s.f.RemoveVirtualIP(s.context(), requestVirtualIP))
 }
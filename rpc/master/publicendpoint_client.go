@@ -22,16 +22,17 @@ import (
 func (c *Client) AddPublicEndpointPort(serviceid, endpointName, portAddr string, usetls bool,
 	protocol string, isEnabled bool, restart bool) (*servicedefinition.Port, error) {
 	request := &PublicEndpointRequest{
-		Serviceid:    serviceid,
-		EndpointName: endpointName,
-		Name:         portAddr,
-		UseTLS:       usetls,
-		Protocol:     protocol,
-		IsEnabled:    isEnabled,
-		Restart:      restart,
+		Serviceid:      serviceid,
+		EndpointName:   endpointName,
+		Name:           portAddr,
+		UseTLS:         usetls,
+		Protocol:       protocol,
+		IsEnabled:      isEnabled,
+		Restart:        restart,
+		IdempotencyKey: newIdempotencyKey(),
 	}
 	var result servicedefinition.Port
-	err := c.call("AddPublicEndpointPort", request, &result)
+	err := c.callIdempotent("AddPublicEndpointPort", request, &result)
 	return &result, err
 }
 
@@ -60,14 +61,15 @@ func (c *Client) EnablePublicEndpointPort(serviceid, endpointName, portAddr stri
 func (c *Client) AddPublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled,
 	restart bool) (*servicedefinition.VHost, error) {
 	request := &PublicEndpointRequest{
-		Serviceid:    serviceid,
-		EndpointName: endpointName,
-		Name:         vhost,
-		IsEnabled:    isEnabled,
-		Restart:      restart,
+		Serviceid:      serviceid,
+		EndpointName:   endpointName,
+		Name:           vhost,
+		IsEnabled:      isEnabled,
+		Restart:        restart,
+		IdempotencyKey: newIdempotencyKey(),
 	}
 	var result servicedefinition.VHost
-	err := c.call("AddPublicEndpointVHost", request, &result)
+	err := c.callIdempotent("AddPublicEndpointVHost", request, &result)
 	return &result, err
 }
 
@@ -92,6 +94,18 @@ func (c *Client) EnablePublicEndpointVHost(serviceid, endpointName, vhost string
 	return c.call("EnablePublicEndpointVHost", request, nil)
 }
 
+// Set the certificate that a vhost public endpoint of a service presents.  If
+// certPEM is empty, the vhost presents the default certificate again.
+func (c *Client) SetPublicEndpointVHostCertificate(serviceid, vhost string, certPEM, keyPEM []byte) error {
+	request := &PublicEndpointRequest{
+		Serviceid: serviceid,
+		Name:      vhost,
+		CertPEM:   certPEM,
+		KeyPEM:    keyPEM,
+	}
+	return c.call("SetPublicEndpointVHostCertificate", request, nil)
+}
+
 // GetAllPublicEndpoints
 func (c *Client) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	var response []service.PublicEndpoint
@@ -100,3 +114,13 @@ func (c *Client) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	}
 	return response, nil
 }
+
+// GetPublicEndpointsInPool returns the public endpoints of the services in a
+// resource pool
+func (c *Client) GetPublicEndpointsInPool(poolID string) ([]service.PublicEndpoint, error) {
+	var response []service.PublicEndpoint
+	if err := c.call("GetPublicEndpointsInPool", poolID, &response); err != nil {
+		return response, err
+	}
+	return response, nil
+}
@@ -27,56 +27,99 @@ type PublicEndpointRequest struct {
 	Protocol     string
 	IsEnabled    bool
 	Restart      bool
+
+	// IdempotencyKey identifies the request to add a public endpoint
+	// across retries
+	IdempotencyKey string
+
+	// CertPEM and KeyPEM are the certificate chain and private key of a
+	// vhost; empty to present the default certificate
+	CertPEM []byte
+	KeyPEM  []byte
 }
 
 // Adds a port public endpoint to a service.
 func (s *Server) AddPublicEndpointPort(request *PublicEndpointRequest, reply *servicedefinition.Port) error {
-	port, err := s.f.AddPublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name,
-		request.UseTLS, request.Protocol, request.IsEnabled, request.Restart)
+	port, err := s.idempotent("AddPublicEndpointPort", request.IdempotencyKey, func() (interface{}, error) {
+		return s.f.AddPublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name,
+			request.UseTLS, request.Protocol, request.IsEnabled, request.Restart)
+	})
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
-	*reply = *port
-	return err
+	*reply = *port.(*servicedefinition.Port)
+	return rpcError(err)
 }
 
 // Remove a port public endpoint from a service.
 func (s *Server) RemovePublicEndpointPort(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.RemovePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name)
+	return rpcError(s.f.RemovePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name))
 }
 
 // Enable/disable a port public endpoint for a service.
 func (s *Server) EnablePublicEndpointPort(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.EnablePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
+	return rpcError(s.f.EnablePublicEndpointPort(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled))
 }
 
 // Adds a vhost public endpoint to a service.
 func (s *Server) AddPublicEndpointVHost(request *PublicEndpointRequest, reply *servicedefinition.VHost) error {
-	vhost, err := s.f.AddPublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name,
-		request.IsEnabled, request.Restart)
+	vhost, err := s.idempotent("AddPublicEndpointVHost", request.IdempotencyKey, func() (interface{}, error) {
+		return s.f.AddPublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name,
+			request.IsEnabled, request.Restart)
+	})
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
-	*reply = *vhost
-	return err
+	*reply = *vhost.(*servicedefinition.VHost)
+	return rpcError(err)
 }
 
 // Remove a vhost public endpoint from a service.
 func (s *Server) RemovePublicEndpointVHost(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.RemovePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name)
+	return rpcError(s.f.RemovePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name))
 }
 
 // Enable/disable a vhost public endpoint for a service.
 func (s *Server) EnablePublicEndpointVHost(request *PublicEndpointRequest, _ *struct{}) error {
-	return s.f.EnablePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
+	return rpcError(s.f.EnablePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled))
+}
+
+// Set the certificate that a vhost public endpoint of a service presents.
+func (s *Server) SetPublicEndpointVHostCertificate(request *PublicEndpointRequest, _ *struct{}) error {
+	return rpcError(s.f.SetPublicEndpointVHostCertificate(s.context(), request.Serviceid, request.Name, request.CertPEM, request.KeyPEM))
 }
 
 // GetAllPublicEndpoints get all public endpoints
 func (s *Server) GetAllPublicEndpoints(empty struct{}, publicEndpoints *[]service.PublicEndpoint) error {
 	peps, err := s.f.GetAllPublicEndpoints(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*publicEndpoints = peps
 	return nil
 }
+
+// GetPublicEndpointsInPool gets the public endpoints of the services in a
+// resource pool
+func (s *Server) GetPublicEndpointsInPool(poolID string, publicEndpoints *[]service.PublicEndpoint) error {
+	svcs, err := s.f.QueryServiceDetails(s.context(), service.Query{PoolID: poolID})
+	if err != nil {
+		return rpcError(err)
+	}
+	inPool := make(map[string]struct{})
+	for _, svc := range svcs {
+		inPool[svc.ID] = struct{}{}
+	}
+	peps, err := s.f.GetAllPublicEndpoints(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	result := []service.PublicEndpoint{}
+	for _, pep := range peps {
+		if _, ok := inPool[pep.ServiceID]; ok {
+			result = append(result, pep)
+		}
+	}
+	*publicEndpoints = result
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/rpc/rpcutils"
+)
+
+// readOnlyPrefixes are the prefixes of the methods that do not change the
+// cluster
+var readOnlyPrefixes = []string{"Get", "Find", "Locate", "Resolve", "Wait"}
+
+// adminReadCalls are the methods that do not change the cluster but return
+// data that only admins may see
+var adminReadCalls = map[string]struct{}{
+	"GetSecretValue":   struct{}{},
+	"GetHostPublicKey": struct{}{},
+}
+
+// operatorCalls are the methods that operate services and hosts without
+// changing the configuration of the cluster
+var operatorCalls = map[string]struct{}{
+	"StartServices":             struct{}{},
+	"StopServices":              struct{}{},
+	"RestartServices":           struct{}{},
+	"StopServiceInstance":       struct{}{},
+	"SendDockerAction":          struct{}{},
+	"SetHostMaintenance":        struct{}{},
+	"SetMaintenanceMode":        struct{}{},
+	"EnablePublicEndpointPort":  struct{}{},
+	"EnablePublicEndpointVHost": struct{}{},
+	"DeployServiceCanary":       struct{}{},
+}
+
+func init() {
+	rpcutils.RegisterCallRoles("Master", callRole)
+}
+
+// callRole returns the role that a delegate needs to call a method of the
+// master.  Methods that are not read-only or operator calls require an
+// admin.
+func callRole(method string) auth.Role {
+	if _, ok := adminReadCalls[method]; ok {
+		return auth.RoleAdmin
+	}
+	if _, ok := operatorCalls[method]; ok {
+		return auth.RoleOperator
+	}
+	if method == "HostsAuthenticated" || method == "CheckInteractiveAccess" {
+		return auth.RoleReadOnly
+	}
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return auth.RoleReadOnly
+		}
+	}
+	return auth.RoleAdmin
+}
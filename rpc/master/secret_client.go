@@ -0,0 +1,65 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// SetSecretRequest is the request to add or update a secret
+type SetSecretRequest struct {
+	Name        string
+	Description string
+	Value       string
+}
+
+// GetSecrets returns all secrets without their values
+func (c *Client) GetSecrets() ([]secret.Secret, error) {
+	response := make([]secret.Secret, 0)
+	if err := c.call("GetSecrets", empty, &response); err != nil {
+		return []secret.Secret{}, err
+	}
+	return response, nil
+}
+
+// GetSecretValue returns the decrypted value of a secret
+func (c *Client) GetSecretValue(name string) (string, error) {
+	var response string
+	if err := c.call("GetSecretValue", name, &response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// SetSecret adds or updates a secret
+func (c *Client) SetSecret(name, description, value string) error {
+	request := SetSecretRequest{Name: name, Description: description, Value: value}
+	return c.call("SetSecret", request, nil)
+}
+
+// RemoveSecret removes a secret
+func (c *Client) RemoveSecret(name string) error {
+	return c.call("RemoveSecret", name, nil)
+}
+
+// GetServiceSecrets returns the values of the secrets that the config files
+// of a service instance reference
+func (c *Client) GetServiceSecrets(serviceID string, instanceID int) (map[string]string, error) {
+	request := EvaluateServiceRequest{ServiceID: serviceID, InstanceID: instanceID}
+	response := make(map[string]string)
+	if err := c.call("GetServiceSecrets", request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/secret"
+)
+
+// GetSecrets returns all secrets without their values
+func (s *Server) GetSecrets(empty struct{}, reply *[]secret.Secret) error {
+	secrets, err := s.f.GetSecrets(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = secrets
+	return nil
+}
+
+// GetSecretValue returns the decrypted value of a secret
+func (s *Server) GetSecretValue(name string, reply *string) error {
+	value, err := s.f.GetSecretValue(s.context(), name)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = value
+	return nil
+}
+
+// SetSecret adds or updates a secret
+func (s *Server) SetSecret(request SetSecretRequest, _ *struct{}) error {
+	return rpcError(s.f.SetSecret(s.context(), request.Name, request.Description, request.Value))
+}
+
+// RemoveSecret removes a secret
+func (s *Server) RemoveSecret(name string, _ *struct{}) error {
+	return rpcError(s.f.RemoveSecret(s.context(), name))
+}
+
+// GetServiceSecrets returns the values of the secrets that the config files
+// of a service instance reference
+func (s *Server) GetServiceSecrets(request EvaluateServiceRequest, reply *map[string]string) error {
+	values, err := s.f.GetServiceSecrets(s.context(), request.ServiceID, request.InstanceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = values
+	return nil
+}
@@ -36,6 +36,22 @@ func (c *Client) ServiceUse(serviceID string, imageID string, registry string, r
 	return result, nil
 }
 
+// WaitServiceStateEvents returns the state changes of a service, or of all
+// services of a tenant, after a sequence number.  It waits up to the timeout
+// for a change to be published.
+func (c *Client) WaitServiceStateEvents(serviceID string, since uint64, timeout time.Duration) (*service.StateEvents, error) {
+	request := &ServiceStateEventsRequest{
+		ServiceID: serviceID,
+		Since:     since,
+		Timeout:   timeout,
+	}
+	response := &service.StateEvents{}
+	if err := c.call("WaitServiceStateEvents", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // WaitService will wait for the specified services to reach the specified
 // state, within the given timeout
 func (c *Client) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
@@ -57,6 +73,24 @@ func (c *Client) GetAllServiceDetails(since time.Duration) ([]service.ServiceDet
 	return svcs, err
 }
 
+// GetServiceDetailsChanges will return the ServiceDetails modified since the
+// change token.  An empty token returns all services.
+func (c *Client) GetServiceDetailsChanges(token string) (*ServiceDetailsChanges, error) {
+	changes := &ServiceDetailsChanges{}
+	if err := c.call("GetServiceDetailsChanges", token, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// GetServiceDetailsInPool will return a list of ServiceDetails for the
+// services in a resource pool
+func (c *Client) GetServiceDetailsInPool(poolID string) ([]service.ServiceDetails, error) {
+	svcs := []service.ServiceDetails{}
+	err := c.call("GetServiceDetailsInPool", poolID, &svcs)
+	return svcs, err
+}
+
 // GetServiceDetailsByTenantID will return a list of ServiceDetails for the specified tenant ID
 func (c *Client) GetServiceDetailsByTenantID(tenantID string) ([]service.ServiceDetails, error) {
 	svcs := []service.ServiceDetails{}
@@ -124,6 +158,83 @@ func (c *Client) ClearEmergency(serviceID string) (int, error) {
 	return affected, err
 }
 
+// GetServicePriorities returns the effective priority classes of a service
+// and its descendents, or of every deployment if serviceID is empty, with
+// the batches that they are started and emergency stopped in
+func (c *Client) GetServicePriorities(serviceID string) ([]service.ServicePriority, error) {
+	priorities := []service.ServicePriority{}
+	err := c.call("GetServicePriorities", serviceID, &priorities)
+	return priorities, err
+}
+
+// DeployServiceCanary rolls a new image out to a fraction of the instances
+// of a service and promotes it if they pass their health checks.  It returns
+// the id of the snapshot taken before the deploy.
+func (c *Client) DeployServiceCanary(serviceID, imageID string, fraction float64, timeout time.Duration) (string, error) {
+	request := CanaryDeployRequest{
+		ServiceID: serviceID,
+		ImageID:   imageID,
+		Fraction:  fraction,
+		Timeout:   timeout,
+	}
+	snapshotID := ""
+	err := c.call("DeployServiceCanary", request, &snapshotID)
+	return snapshotID, err
+}
+
+// GetServiceImagePins returns the image digests that a service and its
+// descendants are pinned to
+func (c *Client) GetServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	pins := []service.ImagePin{}
+	err := c.call("GetServiceImagePins", serviceID, &pins)
+	return pins, err
+}
+
+// RefreshServiceImagePins pins a service and its descendants to the digests
+// that their images are currently tagged to, and returns the new pins
+func (c *Client) RefreshServiceImagePins(serviceID string) ([]service.ImagePin, error) {
+	pins := []service.ImagePin{}
+	err := c.call("RefreshServiceImagePins", serviceID, &pins)
+	return pins, err
+}
+
+// SetMaintenanceMode puts a tenant into maintenance mode, or takes it out of
+// maintenance mode if mode is nil
+func (c *Client) SetMaintenanceMode(tenantID string, mode *service.MaintenanceMode) error {
+	request := MaintenanceModeRequest{
+		TenantID: tenantID,
+		Mode:     mode,
+	}
+	return c.call("SetMaintenanceMode", request, nil)
+}
+
+// StartServices schedules a list of services to start in one call and
+// returns the number of affected services
+func (c *Client) StartServices(serviceIDs []string, synchronous bool) (int, error) {
+	request := ScheduleServicesRequest{ServiceIDs: serviceIDs, Synchronous: synchronous}
+	affected := 0
+	err := c.call("StartServices", request, &affected)
+	return affected, err
+}
+
+// RestartServices schedules a list of services to restart in one call and
+// returns the number of affected services
+func (c *Client) RestartServices(serviceIDs []string, synchronous bool) (int, error) {
+	request := ScheduleServicesRequest{ServiceIDs: serviceIDs, Synchronous: synchronous}
+	affected := 0
+	err := c.call("RestartServices", request, &affected)
+	return affected, err
+}
+
+// StopServices schedules a list of services to stop in one call and returns
+// the number of affected services
+func (c *Client) StopServices(serviceIDs []string, synchronous bool) (int, error) {
+	request := ScheduleServicesRequest{ServiceIDs: serviceIDs, Synchronous: synchronous}
+	affected := 0
+	err := c.call("StopServices", request, &affected)
+	return affected, err
+}
+
 // Remove the IP assignment of a service's endpoints
 func (c *Client) RemoveIPs(args []string) error {
 	return c.call("RemoveIPs", args, new(string))
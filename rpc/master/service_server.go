@@ -16,6 +16,7 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/addressassignment"
 )
@@ -28,6 +29,27 @@ type ServiceUseRequest struct {
 	NoOp        bool
 }
 
+// CanaryDeployRequest are options for rolling a new image out to a service
+type CanaryDeployRequest struct {
+	ServiceID string
+	ImageID   string
+	Fraction  float64
+	Timeout   time.Duration
+}
+
+// ScheduleServicesRequest is a list of services to schedule in one call
+// MaintenanceModeRequest sets the maintenance mode of a tenant; a nil mode
+// takes the tenant out of maintenance mode
+type MaintenanceModeRequest struct {
+	TenantID string
+	Mode     *service.MaintenanceMode
+}
+
+type ScheduleServicesRequest struct {
+	ServiceIDs  []string
+	Synchronous bool
+}
+
 type WaitServiceRequest struct {
 	ServiceIDs []string
 	State      service.DesiredState
@@ -35,6 +57,12 @@ type WaitServiceRequest struct {
 	Recursive  bool
 }
 
+type ServiceStateEventsRequest struct {
+	ServiceID string
+	Since     uint64
+	Timeout   time.Duration
+}
+
 type EvaluateServiceRequest struct {
 	ServiceID  string
 	InstanceID int
@@ -56,10 +84,19 @@ type ServiceDetailsByTenantIDRequest struct {
 	Since    time.Duration
 }
 
+// ServiceDetailsChanges are the services modified since a change token.
+// Volatile fields, such as the running instance count, do not count as
+// modifications.
+type ServiceDetailsChanges struct {
+	Services   []service.ServiceDetails // services modified since the token
+	ServiceIDs []string                 // ids of all services, to detect removals
+	Token      string                   // change token for the next request
+}
+
 // Use a new image for a given service - this will pull the image and tag it
 func (s *Server) ServiceUse(request *ServiceUseRequest, response *string) error {
 	if err := s.f.ServiceUse(s.context(), request.ServiceID, request.ImageID, request.Registry, request.ReplaceImgs, request.NoOp); err != nil {
-		return err
+		return rpcError(err)
 	}
 	*response = ""
 	return nil
@@ -68,14 +105,63 @@ func (s *Server) ServiceUse(request *ServiceUseRequest, response *string) error
 // Wait on specified services to be in the given state
 func (s *Server) WaitService(request *WaitServiceRequest, throwaway *string) error {
 	err := s.f.WaitService(s.context(), request.State, request.Timeout, request.Recursive, request.ServiceIDs...)
-	return err
+	return rpcError(err)
+}
+
+// WaitServiceStateEvents returns the state changes of a service or tenant
+// after a sequence number, waiting up to the timeout for a change
+func (s *Server) WaitServiceStateEvents(request *ServiceStateEventsRequest, response *service.StateEvents) error {
+	events, err := s.f.WaitServiceStateEvents(s.context(), request.ServiceID, request.Since, request.Timeout, nil)
+	if err != nil {
+		return rpcError(err)
+	}
+	*response = *events
+	return nil
 }
 
 // GetAllServiceDetails will return a list of all ServiceDetails
 func (s *Server) GetAllServiceDetails(since time.Duration, response *[]service.ServiceDetails) error {
 	svcs, err := s.f.QueryServiceDetails(s.context(), service.Query{Since: since})
 	if err != nil {
-		return err
+		return rpcError(err)
+	}
+	*response = svcs
+	return nil
+}
+
+// GetServiceDetailsChanges will return the ServiceDetails modified since the
+// change token
+func (s *Server) GetServiceDetailsChanges(token string, response *ServiceDetailsChanges) error {
+	since, err := domain.ParseChangeToken(token)
+	if err != nil {
+		return rpcError(err)
+	}
+	start := time.Now()
+	svcs, err := s.f.QueryServiceDetails(s.context(), service.Query{})
+	if err != nil {
+		return rpcError(err)
+	}
+	changes := ServiceDetailsChanges{
+		Services:   []service.ServiceDetails{},
+		ServiceIDs: make([]string, len(svcs)),
+		Token:      domain.NewChangeToken(start),
+	}
+	for i, svc := range svcs {
+		changes.ServiceIDs[i] = svc.ID
+		if !svc.UpdatedAt.Before(since) {
+			changes.Services = append(changes.Services, svc)
+		}
+	}
+	*response = changes
+	return nil
+}
+
+// GetServiceDetailsInPool will return a list of ServiceDetails for the
+// services in a resource pool
+func (s *Server) GetServiceDetailsInPool(poolID string, response *[]service.ServiceDetails) error {
+	svcs, err := s.f.QueryServiceDetails(s.context(), service.Query{PoolID: poolID})
+	if err != nil {
+		return rpcError(err)
 	}
 	*response = svcs
 	return nil
@@ -85,7 +171,7 @@ func (s *Server) GetAllServiceDetails(since time.Duration, response *[]service.S
 func (s *Server) GetServiceDetails(serviceID string, response *service.ServiceDetails) error {
 	svc, err := s.f.GetServiceDetails(s.context(), serviceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*response = *svc
 	return nil
@@ -95,7 +181,7 @@ func (s *Server) GetServiceDetails(serviceID string, response *service.ServiceDe
 func (s *Server) GetServiceDetailsByTenantID(tenantID string, response *[]service.ServiceDetails) error {
 	svcs, err := s.f.GetServiceDetailsByTenantID(s.context(), tenantID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*response = svcs
 	return nil
@@ -105,7 +191,7 @@ func (s *Server) GetServiceDetailsByTenantID(tenantID string, response *[]servic
 func (s *Server) GetService(serviceID string, svc *service.Service) error {
 	sv, err := s.f.GetService(s.context(), serviceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*svc = *sv
 	return nil
@@ -115,12 +201,12 @@ func (s *Server) GetService(serviceID string, svc *service.Service) error {
 func (s *Server) GetEvaluatedService(request EvaluateServiceRequest, response *EvaluateServiceResponse) error {
 	svc, err := s.f.GetEvaluatedService(s.context(), request.ServiceID, request.InstanceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 
 	tenantID, svcPath, err := s.f.GetServiceNamePath(s.context(), request.ServiceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	response.Service = *svc
 	response.TenantID = tenantID
@@ -132,7 +218,7 @@ func (s *Server) GetEvaluatedService(request EvaluateServiceRequest, response *E
 func (s *Server) GetTenantID(serviceID string, tenantId *string) error {
 	result, err := s.f.GetTenantID(s.context(), serviceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*tenantId = result
 	return nil
@@ -142,7 +228,7 @@ func (s *Server) GetTenantID(serviceID string, tenantId *string) error {
 func (s *Server) ResolveServicePath(request ResolveServiceRequest, response *[]service.ServiceDetails) error {
 	svcs, err := s.f.ResolveServicePath(s.context(), request.Path, request.NoPrefix)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*response = svcs
 	return nil
@@ -153,16 +239,89 @@ func (s *Server) ResolveServicePath(request ResolveServiceRequest, response *[]s
 func (s *Server) ClearEmergency(serviceID string, count *int) error {
 	c, err := s.f.ClearEmergencyStopFlag(s.context(), serviceID)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*count = c
 	return nil
 }
 
+// GetServicePriorities returns the effective priority classes of a service
+// and its descendents, or of every deployment if serviceID is empty, with
+// the batches that they are started and emergency stopped in
+func (s *Server) GetServicePriorities(serviceID string, priorities *[]service.ServicePriority) error {
+	p, err := s.f.GetServicePriorities(s.context(), serviceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*priorities = p
+	return nil
+}
+
+// DeployServiceCanary rolls a new image out to a fraction of the instances
+// of a service and promotes it if they pass their health checks.  It returns
+// the id of the snapshot taken before the deploy.
+func (s *Server) DeployServiceCanary(request CanaryDeployRequest, snapshotID *string) error {
+	id, err := s.f.DeployServiceCanary(s.context(), request.ServiceID, request.ImageID, request.Fraction, request.Timeout)
+	*snapshotID = id
+	return rpcError(err)
+}
+
+// GetServiceImagePins returns the image digests that a service and its
+// descendants are pinned to
+func (s *Server) GetServiceImagePins(serviceID string, pins *[]service.ImagePin) error {
+	result, err := s.f.GetServiceImagePins(s.context(), serviceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*pins = result
+	return nil
+}
+
+// RefreshServiceImagePins pins a service and its descendants to the digests
+// that their images are currently tagged to, and returns the new pins
+func (s *Server) RefreshServiceImagePins(serviceID string, pins *[]service.ImagePin) error {
+	result, err := s.f.RefreshServiceImagePins(s.context(), serviceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*pins = result
+	return nil
+}
+
+// SetMaintenanceMode puts a tenant into maintenance mode, or takes it out of
+// maintenance mode
+func (s *Server) SetMaintenanceMode(request MaintenanceModeRequest, _ *struct{}) error {
+	return rpcError(s.f.SetMaintenanceMode(s.context(), request.TenantID, request.Mode))
+}
+
+// StartServices schedules a list of services to start and returns the
+// number of affected services
+func (s *Server) StartServices(request ScheduleServicesRequest, affected *int) error {
+	count, err := s.f.StartServices(s.context(), request.ServiceIDs, request.Synchronous)
+	*affected = count
+	return rpcError(err)
+}
+
+// RestartServices schedules a list of services to restart and returns the
+// number of affected services
+func (s *Server) RestartServices(request ScheduleServicesRequest, affected *int) error {
+	count, err := s.f.RestartServices(s.context(), request.ServiceIDs, request.Synchronous)
+	*affected = count
+	return rpcError(err)
+}
+
+// StopServices schedules a list of services to stop and returns the number of
+// affected services
+func (s *Server) StopServices(request ScheduleServicesRequest, affected *int) error {
+	count, err := s.f.StopServices(s.context(), request.ServiceIDs, request.Synchronous)
+	*affected = count
+	return rpcError(err)
+}
+
 func (s *Server) RemoveIPs(args []string, unused *string) error {
-	return s.f.RemoveIPs(s.context(), args)
+	return rpcError(s.f.RemoveIPs(s.context(), args))
 }
 
 func (s *Server) SetIPs(request addressassignment.AssignmentRequest, unused *string) error {
-	return s.f.SetIPs(s.context(), request)
+	return rpcError(s.f.SetIPs(s.context(), request))
 }
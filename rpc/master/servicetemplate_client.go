@@ -15,12 +15,17 @@ package master
 
 import (
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/validation"
 )
 
 // Add a new service template
 func (c *Client) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate) (templateID string, err error) {
+	request := AddServiceTemplateRequest{
+		Template:       serviceTemplate,
+		IdempotencyKey: newIdempotencyKey(),
+	}
 	response := ""
-	if err := c.call("AddServiceTemplate", serviceTemplate, &response); err != nil {
+	if err := c.callIdempotent("AddServiceTemplate", request, &response); err != nil {
 		return "", err
 	}
 	return response, nil
@@ -47,10 +52,31 @@ func (c *Client) RemoveServiceTemplate(serviceTemplateID string) error {
 // Deploy a service Template
 func (c *Client) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) (tenantIDs []string, err error){
 	response := []string{}
-	if err := c.call("DeployTemplate", request, &response); err != nil {
+	if request.IdempotencyKey == "" {
+		request.IdempotencyKey = newIdempotencyKey()
+	}
+	if err := c.callIdempotent("DeployTemplate", request, &response); err != nil {
 		return nil, err
 	}
 	return response, nil
 
 }
 
+
+// Upgrade the services of a deployment to a version of a service template
+func (c *Client) UpgradeTemplate(request servicetemplate.ServiceTemplateUpgradeRequest) ([]servicetemplate.TemplateChange, error) {
+	response := []servicetemplate.TemplateChange{}
+	if err := c.call("UpgradeTemplate", request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Get the JSON Schema of service templates or services
+func (c *Client) GetSchema(name string) (*validation.Schema, error) {
+	response := &validation.Schema{}
+	if err := c.call("GetSchema", name, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
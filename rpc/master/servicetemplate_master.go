@@ -15,16 +15,25 @@ package master
 
 import (
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/validation"
 )
 
+// AddServiceTemplateRequest is the request to add a service template
+type AddServiceTemplateRequest struct {
+	Template       servicetemplate.ServiceTemplate
+	IdempotencyKey string
+}
+
 // Add a new service template
-func (s *Server) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate, response *string) error  {
+func (s *Server) AddServiceTemplate(request AddServiceTemplateRequest, response *string) error  {
 	reloadLogstashConfig := true
-	templateID, err := s.f.AddServiceTemplate(s.context(), serviceTemplate, reloadLogstashConfig)
+	templateID, err := s.idempotent("AddServiceTemplate", request.IdempotencyKey, func() (interface{}, error) {
+		return s.f.AddServiceTemplate(s.context(), request.Template, reloadLogstashConfig)
+	})
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
-	*response = templateID
+	*response = templateID.(string)
 	return nil
 }
 
@@ -32,7 +41,7 @@ func (s *Server) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTempl
 func (s *Server) GetServiceTemplates(unused struct{}, response *map[string]servicetemplate.ServiceTemplate) error  {
 	templates, err := s.f.GetServiceTemplates(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*response = templates
 	return nil
@@ -40,15 +49,37 @@ func (s *Server) GetServiceTemplates(unused struct{}, response *map[string]servi
 
 // Remove a service template
 func (s *Server) RemoveServiceTemplate(templateID string,  _ *struct{}) error  {
-	return s.f.RemoveServiceTemplate(s.context(), templateID)
+	return rpcError(s.f.RemoveServiceTemplate(s.context(), templateID))
 }
 
 // Deploy a service template
 func (s *Server) DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest, response *[]string) error  {
-	tenantIDs, err := s.f.DeployTemplate(s.context(), request.PoolID, request.TemplateID, request.DeploymentID)
+	tenantIDs, err := s.idempotent("DeployTemplate", request.IdempotencyKey, func() (interface{}, error) {
+		return s.f.DeployTemplate(s.context(), request.PoolID, request.TemplateID, request.DeploymentID, request.Values)
+	})
+	if err != nil {
+		return rpcError(err)
+	}
+	*response = tenantIDs.([]string)
+	return nil
+}
+
+// Upgrade the services of a deployment to a version of a service template
+func (s *Server) UpgradeTemplate(request servicetemplate.ServiceTemplateUpgradeRequest, response *[]servicetemplate.TemplateChange) error {
+	changes, err := s.f.UpgradeTemplate(s.context(), request.TemplateID, request.DeploymentID, request.DryRun)
+	if err != nil {
+		return rpcError(err)
+	}
+	*response = changes
+	return nil
+}
+
+// Get the JSON Schema of service templates or services
+func (s *Server) GetSchema(name string, response *validation.Schema) error {
+	schema, err := s.f.GetSchema(s.context(), name)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
-	*response = tenantIDs
+	*response = *schema
 	return nil
 }
@@ -0,0 +1,39 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/setting"
+)
+
+// GetSettings returns every known setting with the values it has been set to
+func (c *Client) GetSettings() ([]setting.Setting, error) {
+	response := make([]setting.Setting, 0)
+	if err := c.call("GetSettings", empty, &response); err != nil {
+		return []setting.Setting{}, err
+	}
+	return response, nil
+}
+
+// SetSetting sets the value of a setting in a scope
+func (c *Client) SetSetting(name string, scope setting.Scope, scopeID, value string) error {
+	request := SettingRequest{Name: name, Scope: scope, ScopeID: scopeID, Value: value}
+	return c.call("SetSetting", request, nil)
+}
+
+// UnsetSetting removes the value of a setting in a scope
+func (c *Client) UnsetSetting(name string, scope setting.Scope, scopeID string) error {
+	request := SettingRequest{Name: name, Scope: scope, ScopeID: scopeID}
+	return c.call("UnsetSetting", request, nil)
+}
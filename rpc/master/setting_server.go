@@ -0,0 +1,47 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/setting"
+)
+
+// SettingRequest is the request to set or unset the value of a setting in a
+// scope
+type SettingRequest struct {
+	Name    string
+	Scope   setting.Scope
+	ScopeID string
+	Value   string
+}
+
+// GetSettings returns every known setting with the values it has been set to
+func (s *Server) GetSettings(empty struct{}, reply *[]setting.Setting) error {
+	settings, err := s.f.GetSettings(s.context())
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = settings
+	return nil
+}
+
+// SetSetting sets the value of a setting
+func (s *Server) SetSetting(request SettingRequest, _ *struct{}) error {
+	return rpcError(s.f.SetSetting(s.context(), request.Name, request.Scope, request.ScopeID, request.Value))
+}
+
+// UnsetSetting removes the value of a setting
+func (s *Server) UnsetSetting(request SettingRequest, _ *struct{}) error {
+	return rpcError(s.f.UnsetSetting(s.context(), request.Name, request.Scope, request.ScopeID))
+}
@@ -0,0 +1,36 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/commons/startup"
+)
+
+// StartupServer reports the progress of the master's startup.  It is
+// registered ahead of the rest of the master RPC so that it can answer while
+// the master is still starting.
+type StartupServer struct {
+	graph *startup.Graph
+}
+
+// NewStartupServer returns a server that reports the progress of the graph
+func NewStartupServer(graph *startup.Graph) *StartupServer {
+	return &StartupServer{graph: graph}
+}
+
+// GetStartupProgress returns the status of each step of the master's startup
+func (s *StartupServer) GetStartupProgress(unused struct{}, progress *[]startup.Status) error {
+	*progress = s.graph.Progress()
+	return nil
+}
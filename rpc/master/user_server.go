@@ -21,7 +21,7 @@ import (
 func (s *Server) GetSystemUser(unused struct{}, systemUser *user.User) error {
 	result, err := s.f.GetSystemUser(s.context())
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*systemUser = result
 	return nil
@@ -31,7 +31,7 @@ func (s *Server) GetSystemUser(unused struct{}, systemUser *user.User) error {
 func (s *Server) ValidateCredentials(someUser user.User, valid *bool) error {
 	result, err := s.f.ValidateCredentials(s.context(), someUser)
 	if err != nil {
-		return err
+		return rpcError(err)
 	}
 	*valid = result
 	return nil
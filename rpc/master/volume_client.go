@@ -14,6 +14,7 @@
 package master
 
 import (
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/volume"
 )
 
@@ -25,3 +26,37 @@ func (c *Client) GetVolumeStatus() (*volume.Statuses, error) {
 	}
 	return response, nil
 }
+
+// ResizeVolume sets the quota of the volume of the tenant that owns the
+// service and returns the resized quota
+func (c *Client) ResizeVolume(serviceID string, size uint64) (*volume.Quota, error) {
+	request := ResizeVolumeRequest{ServiceID: serviceID, Size: size}
+	response := &volume.Quota{}
+	if err := c.call("ResizeVolume", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// CheckDFS cross-checks the volumes, snapshots, registry and service images
+// of the dfs and returns the problems that it finds.  If repair is true, the
+// problems that can be repaired safely are repaired.
+func (c *Client) CheckDFS(repair bool) ([]dfs.FsckProblem, error) {
+	request := CheckDFSRequest{Repair: repair}
+	response := []dfs.FsckProblem{}
+	if err := c.call("CheckDFS", request, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetRollbackPreview returns the services that are stopped, the images that
+// are retagged and the config files that are changed by a rollback to the
+// snapshot
+func (c *Client) GetRollbackPreview(snapshotID string) (*dfs.RollbackPreview, error) {
+	response := &dfs.RollbackPreview{}
+	if err := c.call("GetRollbackPreview", snapshotID, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
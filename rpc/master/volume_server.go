@@ -16,15 +16,64 @@ package master
 import (
 	"errors"
 
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/volume"
 )
 
+// ResizeVolumeRequest sets the quota of the volume of the tenant that owns
+// a service
+type ResizeVolumeRequest struct {
+	ServiceID string
+	Size      uint64
+}
+
 // GetVolumeStatus gets the volume status
 func (s *Server) GetVolumeStatus(empty struct{}, reply *volume.Statuses) error {
 	response := volume.GetStatus()
 	if response == nil {
-		return errors.New("volume_server.go GetStatus failed")
+		return rpcError(errors.New("volume_server.go GetStatus failed"))
 	}
 	*reply = *response
 	return nil
 }
+
+// ResizeVolume sets the quota of the volume of the tenant that owns the
+// service and returns the resized quota
+func (s *Server) ResizeVolume(request ResizeVolumeRequest, reply *volume.Quota) error {
+	if err := s.f.ResizeVolume(s.context(), request.ServiceID, request.Size); err != nil {
+		return rpcError(err)
+	}
+	quota, err := s.f.GetVolumeQuota(s.context(), request.ServiceID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *quota
+	return nil
+}
+
+// CheckDFSRequest is the request to check the consistency of the dfs
+type CheckDFSRequest struct {
+	Repair bool
+}
+
+// CheckDFS cross-checks the volumes, snapshots, registry and service images
+// of the dfs and returns the problems that it finds, repairing the safe
+// ones if requested
+func (s *Server) CheckDFS(request CheckDFSRequest, reply *[]dfs.FsckProblem) error {
+	problems, err := s.f.CheckDFS(s.context(), request.Repair)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = problems
+	return nil
+}
+
+// GetRollbackPreview returns what a rollback to the snapshot changes
+func (s *Server) GetRollbackPreview(snapshotID string, reply *dfs.RollbackPreview) error {
+	preview, err := s.f.RollbackPreview(s.context(), snapshotID)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *preview
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import "github.com/control-center/serviced/isvcs"
+
+// ZKEnsembleChange is the outcome of adding or removing a member of the
+// zookeeper ensemble
+type ZKEnsembleChange struct {
+	Member   isvcs.ZKMember         // member that was added or removed
+	Dynamic  bool                   // true if the running ensemble was reconfigured
+	Quorum   []string               // SERVICED_ISVCS_ZOOKEEPER_QUORUM of the new ensemble
+	Servers  []string               // SERVICED_ZK of the new ensemble
+	Statuses []isvcs.ZKMemberStatus // state of the new ensemble, if it was reconfigured
+}
+
+// GetZKEnsemble returns the state of each member of the zookeeper ensemble
+func (c *Client) GetZKEnsemble() ([]isvcs.ZKMemberStatus, error) {
+	statuses := []isvcs.ZKMemberStatus{}
+	if err := c.call("GetZKEnsemble", empty, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// AddZKEnsembleMember adds a host to the zookeeper ensemble
+func (c *Client) AddZKEnsembleMember(host string) (*ZKEnsembleChange, error) {
+	change := &ZKEnsembleChange{}
+	if err := c.call("AddZKEnsembleMember", host, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// RemoveZKEnsembleMember removes a host from the zookeeper ensemble
+func (c *Client) RemoveZKEnsembleMember(host string) (*ZKEnsembleChange, error) {
+	change := &ZKEnsembleChange{}
+	if err := c.call("RemoveZKEnsembleMember", host, change); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
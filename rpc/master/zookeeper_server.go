@@ -0,0 +1,169 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/zzk"
+)
+
+const (
+	zkCheckTimeout    = 5 * time.Second
+	zkReconfigTimeout = 2 * time.Minute
+)
+
+// ErrZKNotCoordinator is returned when the ensemble is resized while serviced
+// is coordinated by etcd
+var ErrZKNotCoordinator = errors.New("zookeeper is not the coordinator of this cluster")
+
+// GetZKEnsemble returns the state of each member of the zookeeper ensemble
+func (s *Server) GetZKEnsemble(empty struct{}, reply *[]isvcs.ZKMemberStatus) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return rpcError(err)
+	}
+	members, err := currentZKEnsemble(conn)
+	if err != nil {
+		return rpcError(err)
+	}
+	statuses, _ := isvcs.CheckZKEnsemble(members, zkCheckTimeout)
+	*reply = statuses
+	return nil
+}
+
+// AddZKEnsembleMember adds a host to the zookeeper ensemble
+func (s *Server) AddZKEnsembleMember(host string, reply *ZKEnsembleChange) error {
+	change, err := resizeZKEnsemble(host, true)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *change
+	return nil
+}
+
+// RemoveZKEnsembleMember removes a host from the zookeeper ensemble
+func (s *Server) RemoveZKEnsembleMember(host string, reply *ZKEnsembleChange) error {
+	change, err := resizeZKEnsemble(host, false)
+	if err != nil {
+		return rpcError(err)
+	}
+	*reply = *change
+	return nil
+}
+
+// currentZKEnsemble returns the members of the ensemble that was last
+// published, or the configured members if it was never reconfigured.
+func currentZKEnsemble(conn client.Connection) ([]isvcs.ZKMember, error) {
+	options := config.GetOptions()
+	if options.CoordinatorDriver == "etcd" {
+		return nil, ErrZKNotCoordinator
+	}
+	quorum := options.IsvcsZKQuorum
+	if ensemble, err := zzk.GetEnsemble(conn); err != nil {
+		return nil, err
+	} else if ensemble != nil && len(ensemble.Quorum) > 0 {
+		quorum = ensemble.Quorum
+	}
+	members, err := isvcs.ParseZKQuorum(quorum)
+	if err != nil {
+		return nil, err
+	} else if len(members) == 0 {
+		return nil, isvcs.ErrZKNoEnsemble
+	}
+	return members, nil
+}
+
+// resizeZKEnsemble adds or removes a member of the ensemble.  The quorum is
+// verified before the change and, if the running ensemble was reconfigured,
+// after it.  The new ensemble is then published so that hosts can update
+// their connection strings.  If zookeeper cannot be reconfigured while it
+// is running, the new settings are returned without changing anything.
+func resizeZKEnsemble(host string, add bool) (*ZKEnsembleChange, error) {
+	logger := plog.WithFields(logrus.Fields{
+		"host": host,
+		"add":  add,
+	})
+
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return nil, err
+	}
+	members, err := currentZKEnsemble(conn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := isvcs.CheckZKEnsemble(members, zkCheckTimeout); err != nil {
+		logger.WithError(err).Warn("Will not resize an unhealthy zookeeper ensemble")
+		return nil, err
+	}
+	logger.Debug("Verified zookeeper quorum before resizing the ensemble")
+
+	var next []isvcs.ZKMember
+	var member isvcs.ZKMember
+	var added, removed []isvcs.ZKMember
+	if add {
+		next, member, err = isvcs.AddZKMember(members, host)
+		added = []isvcs.ZKMember{member}
+	} else {
+		next, member, err = isvcs.RemoveZKMember(members, host)
+		removed = []isvcs.ZKMember{member}
+	}
+	if err != nil {
+		return nil, err
+	}
+	change := &ZKEnsembleChange{
+		Member:  member,
+		Quorum:  isvcs.FormatZKQuorum(next),
+		Servers: isvcs.ZKClientAddresses(next),
+	}
+	logger = logger.WithField("member", member.String())
+
+	if err := isvcs.ReconfigZKEnsemble(added, removed); err == isvcs.ErrZKReconfigNotSupported {
+		logger.Info("Zookeeper cannot be reconfigured while running; the ensemble must be restarted with the new quorum")
+		return change, nil
+	} else if err != nil {
+		logger.WithError(err).Warn("Could not reconfigure the zookeeper ensemble")
+		return nil, err
+	}
+	change.Dynamic = true
+	logger.Info("Reconfigured the zookeeper ensemble")
+
+	// wait for the new ensemble to establish a quorum
+	timeout := time.After(zkReconfigTimeout)
+	for {
+		change.Statuses, err = isvcs.CheckZKEnsemble(next, zkCheckTimeout)
+		if err == nil {
+			break
+		}
+		select {
+		case <-timeout:
+			logger.WithError(err).Warn("Zookeeper ensemble did not establish a quorum after it was reconfigured")
+			return nil, err
+		case <-time.After(time.Second):
+		}
+	}
+	logger.Debug("Verified zookeeper quorum after resizing the ensemble")
+
+	if err := zzk.UpdateEnsemble(conn, zzk.Ensemble{Quorum: change.Quorum, Servers: change.Servers}); err != nil {
+		logger.WithError(err).Warn("Could not publish the zookeeper ensemble")
+		return nil, err
+	}
+	return change, nil
+}
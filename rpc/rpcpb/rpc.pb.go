@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go.
+// source: rpc.proto
+// DO NOT EDIT!
+
+/*
+Package rpcpb is a generated protocol buffer package.
+
+It is generated from these files:
+	rpc.proto
+
+It has these top-level messages:
+	Frame
+*/
+package rpcpb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// Frame carries a serviced rpc request or response exactly as the jsonrpc
+// transport writes it to its connection: the rpc header, including the signed
+// authentication header when the call requires one, followed by the json
+// encoded request or response of a Master, Agent or ControlCenterAgent method.
+// Carrying the existing frames keeps a single authentication path and lets
+// both transports serve the same registered receivers.
+type Frame struct {
+	Payload []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (m *Frame) Reset()         { *m = Frame{} }
+func (m *Frame) String() string { return proto.CompactTextString(m) }
+func (*Frame) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*Frame)(nil), "rpcpb.Frame")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// Client API for Transport service
+
+type TransportClient interface {
+	// Call serves a single request frame and returns its response frame.
+	Call(ctx context.Context, in *Frame, opts ...grpc.CallOption) (*Frame, error)
+}
+
+type transportClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTransportClient(cc *grpc.ClientConn) TransportClient {
+	return &transportClient{cc}
+}
+
+func (c *transportClient) Call(ctx context.Context, in *Frame, opts ...grpc.CallOption) (*Frame, error) {
+	out := new(Frame)
+	err := grpc.Invoke(ctx, "/rpcpb.Transport/Call", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Transport service
+
+type TransportServer interface {
+	// Call serves a single request frame and returns its response frame.
+	Call(context.Context, *Frame) (*Frame, error)
+}
+
+func RegisterTransportServer(s *grpc.Server, srv TransportServer) {
+	s.RegisterService(&_Transport_serviceDesc, srv)
+}
+
+func _Transport_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(Frame)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(TransportServer).Call(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var _Transport_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "rpcpb.Transport",
+	HandlerType: (*TransportServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    _Transport_Call_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/rpc"
 	"net/rpc/jsonrpc"
+	"strings"
 	"sync"
 
 	"github.com/control-center/serviced/auth"
@@ -33,36 +34,52 @@ var (
 		"Agent.BuildHost",
 		"ControlCenterAgent.Ping",
 		"Master.AddHostPrivate",
+		"Master.BenchmarkNetwork",
 	}
 	// RPC calls that do not require admin access:
 	NonAdminRequiredCalls = map[string]struct{}{
-		"Master.GetHost":                         struct{}{},
-		"Master.GetHosts":                        struct{}{},
-		"Master.GetEvaluatedService":             struct{}{},
-		"Master.GetSystemUser":                   struct{}{},
-		"Master.ReportHealthStatus":              struct{}{},
-		"Master.ReportInstanceDead":              struct{}{},
-		"Master.UpdateHost":                      struct{}{},
-		"ControlCenterAgent.GetEvaluatedService": struct{}{},
-		"ControlCenterAgent.GetHostID":           struct{}{},
-		"ControlCenterAgent.GetZkInfo":           struct{}{},
-		"ControlCenterAgent.GetISvcEndpoints":    struct{}{},
-		"ControlCenterAgent.ReportHealthStatus":  struct{}{},
-		"ControlCenterAgent.ReportInstanceDead":  struct{}{},
-		"ControlCenterAgent.SendLogMessage":      struct{}{},
-		"ControlCenterAgent.AddHostPrivate":      struct{}{},
+		"Master.GetHost":                             struct{}{},
+		"Master.GetHosts":                            struct{}{},
+		"Master.GetHostsChanges":                     struct{}{},
+		"Master.GetEvaluatedService":                 struct{}{},
+		"Master.GetServiceSecrets":                   struct{}{},
+		"Master.GetSystemUser":                       struct{}{},
+		"Master.ReportHealthStatus":                  struct{}{},
+		"Master.ReportInstanceDead":                  struct{}{},
+		"Master.UpdateHost":                          struct{}{},
+		"ControlCenterAgent.GetEvaluatedService":     struct{}{},
+		"ControlCenterAgent.GetServiceSecrets":       struct{}{},
+		"ControlCenterAgent.GetHostID":               struct{}{},
+		"ControlCenterAgent.GetZkInfo":               struct{}{},
+		"ControlCenterAgent.GetISvcEndpoints":        struct{}{},
+		"ControlCenterAgent.ReportHealthStatus":      struct{}{},
+		"ControlCenterAgent.ReportInstanceDead":      struct{}{},
+		"ControlCenterAgent.AcquireHealthCheckSlots": struct{}{},
+		"ControlCenterAgent.ReleaseHealthCheckSlots": struct{}{},
+		"ControlCenterAgent.SendLogMessage":          struct{}{},
+		"ControlCenterAgent.AddHostPrivate":          struct{}{},
 	}
 	endian = binary.BigEndian
 
 	ErrNoAdmin = errors.New("Delegate does not have admin access")
 
+	// ErrRoleDenied is returned when the role of a delegate does not allow
+	// the call
+	ErrRoleDenied = errors.New("Delegate role does not allow this call")
+
+	callRoles = make(map[string]func(method string) auth.Role)
+
 	log = logging.PackageLogger()
 )
 
 // Checks the RPC method name to see if authentication is required.
-//  If it is, calls on the client side will include a signed header, which will be
-//  Verified on the server side
+//
+//	If it is, calls on the client side will include a signed header, which will be
+//	Verified on the server side
 func requiresAuthentication(callName string) bool {
+	if callName == negotiateMethod {
+		return false
+	}
 	for _, name := range NonAuthenticatingCalls {
 		if name == callName {
 			return false
@@ -72,14 +89,56 @@ func requiresAuthentication(callName string) bool {
 }
 
 // Checks the RPC method name to see if admin-level permissions are required.
-//  If they are, it will also check the "admin" attribute on the identity after validating it.
+//
+//	If they are, it will also check the "admin" attribute on the identity after validating it.
 func requiresAdmin(callName string) bool {
 	_, ok := NonAdminRequiredCalls[callName]
 	return !ok
 }
 
+// RegisterCallRoles sets the function that returns the role required to call
+// the methods of an rpc service.  Calls to services without one require an
+// admin identity.
+func RegisterCallRoles(service string, roleOf func(method string) auth.Role) {
+	callRoles[service] = roleOf
+}
+
+// requiredRole returns the role needed to make a call that requires more
+// than a valid identity.
+func requiredRole(callName string) auth.Role {
+	parts := strings.SplitN(callName, ".", 2)
+	if len(parts) == 2 {
+		if roleOf, ok := callRoles[parts[0]]; ok {
+			return roleOf(parts[1])
+		}
+	}
+	return auth.RoleAdmin
+}
+
+// authorize returns an error if the identity may not make the call
+func authorize(ident auth.Identity, callName string) error {
+	if !requiresAdmin(callName) {
+		return nil
+	}
+	if ident == nil {
+		return ErrNoAdmin
+	}
+	if ident.HasAdminAccess() {
+		return nil
+	}
+	role := ident.Role()
+	if !role.IsValid() {
+		return ErrNoAdmin
+	}
+	if !role.Allows(requiredRole(callName)) {
+		return ErrRoleDenied
+	}
+	return nil
+}
+
 // We nead a ReadWriteCloser that we can pass to the underlying codec and use
-//  To buffer requests and responses from the actual connection
+//
+//	To buffer requests and responses from the actual connection
 type ByteBufferReadWriteCloser struct {
 	ReadBuff  bytes.Buffer // Reads will happen from this buffer
 	WriteBuff bytes.Buffer // Writes will happen to this buffer
@@ -107,6 +166,7 @@ type AuthServerCodec struct {
 	parser       auth.RPCHeaderParser
 	wBuffMutex   sync.Mutex // Make sure we buffer one response at a time
 	lastError    error
+	encoding     string // Payload encoding negotiated with the client
 }
 
 func NewDefaultAuthServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
@@ -124,9 +184,10 @@ func NewAuthServerCodec(conn io.ReadWriteCloser, createCodec ServerCodecCreator,
 }
 
 // Reads the request header and populates the rpc.Request object.
-//  This implementation reads the auth header off the stream first, then
-//  lets the underlying codec read the rest.
-//  Finally, it validates the identity if necessary.
+//
+//	This implementation reads the auth header off the stream first, then
+//	lets the underlying codec read the rest.
+//	Finally, it validates the identity if necessary.
 func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 
 	// There is no need for synchronization here, since go's RPC server
@@ -148,6 +209,11 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 	}
 
 	// Now write the actual request to the buffer
+	if a.encoding != "" {
+		if body, err = decodePayload(body); err != nil {
+			return err
+		}
+	}
 	if _, err = a.buff.ReadBuff.Write(body); err != nil {
 		return err
 	}
@@ -157,6 +223,15 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 		return err
 	}
 
+	// Payload encoding is negotiated by the codecs and never reaches the
+	// rpc server
+	if r.ServiceMethod == negotiateMethod && a.encoding == "" {
+		if err := a.negotiate(r); err != nil {
+			return err
+		}
+		return a.ReadRequestHeader(r)
+	}
+
 	log.WithField("ServiceMethod", r.ServiceMethod).Debug("Received RPC request")
 
 	// Now we can get the method name from r and authenticate if required
@@ -166,9 +241,9 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 	//   (unless ReadRequestHeader returns an error)
 	if requiresAuthentication(r.ServiceMethod) {
 		if a.lastError == nil {
-			if requiresAdmin(r.ServiceMethod) && (ident == nil || !ident.HasAdminAccess()) {
+			if err := authorize(ident, r.ServiceMethod); err != nil {
 				log.WithField("ServiceMethod", r.ServiceMethod).Debug("Received unauthorized RPC request")
-				a.lastError = ErrNoAdmin
+				a.lastError = err
 			}
 		}
 		//TODO: save the identity so we can inject it into the request body later
@@ -177,8 +252,9 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 }
 
 // Decodes the request and populates the body object with the body of the request
-//  We don't change anything here, just let the underlying codec handle it.
-//  This always gets called after ReadRequestHeader
+//
+//	We don't change anything here, just let the underlying codec handle it.
+//	This always gets called after ReadRequestHeader
 func (a *AuthServerCodec) ReadRequestBody(body interface{}) error {
 	if a.lastError != nil {
 		return a.lastError
@@ -187,8 +263,8 @@ func (a *AuthServerCodec) ReadRequestBody(body interface{}) error {
 	return a.wrappedcodec.ReadRequestBody(body)
 }
 
-//  Encodes the response before sending it back down to the client.
-//  We don't change anything here, just let the underlying codec handle it.
+// Encodes the response before sending it back down to the client.
+// We don't change anything here, just let the underlying codec handle it.
 func (a *AuthServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
 	// We do need a lock here, because the ServerCodec interface specifies
 	//  that WriteResponse must be safe for concurrent use by multiple goroutines
@@ -204,6 +280,9 @@ func (a *AuthServerCodec) WriteResponse(r *rpc.Response, body interface{}) error
 
 	// Get the response from the buffer and write it to the actual connection
 	response := a.buff.WriteBuff.Bytes()
+	if a.encoding != "" {
+		return writeChunked(a.conn, a.encoding, response)
+	}
 	if err := auth.WriteLengthAndBytes(response, a.conn); err != nil {
 		return err
 	}
@@ -211,8 +290,28 @@ func (a *AuthServerCodec) WriteResponse(r *rpc.Response, body interface{}) error
 	return nil
 }
 
+// negotiate selects the payload encoding requested by the client and replies
+// before any encoding is applied.  Subsequent requests and responses on this
+// connection use the selected encoding.
+func (a *AuthServerCodec) negotiate(r *rpc.Request) error {
+	var req NegotiateRequest
+	if err := a.wrappedcodec.ReadRequestBody(&req); err != nil {
+		return err
+	}
+	resp := NegotiateResponse{Encoding: selectEncoding(req.Encodings)}
+	if err := a.WriteResponse(&rpc.Response{ServiceMethod: r.ServiceMethod, Seq: r.Seq}, resp); err != nil {
+		return err
+	}
+	a.wBuffMutex.Lock()
+	a.encoding = resp.Encoding
+	a.wBuffMutex.Unlock()
+	log.WithField("encoding", resp.Encoding).Debug("Negotiated RPC payload encoding")
+	return nil
+}
+
 // Closes the connection on the server side
-//  We don't change anything here, just let the underlying codec handle it.
+//
+//	We don't change anything here, just let the underlying codec handle it.
 func (a *AuthServerCodec) Close() error {
 	var err error
 	if err = a.wrappedcodec.Close(); err != nil {
@@ -234,6 +333,7 @@ type AuthClientCodec struct {
 	wrappedcodec  rpc.ClientCodec
 	headerBuilder auth.RPCHeaderBuilder
 	wBuffMutex    sync.Mutex // Make sure we buffer a whole request before starting the next one
+	encoding      string     // Payload encoding negotiated with the server
 }
 
 func NewDefaultAuthClientCodec(conn io.ReadWriteCloser) rpc.ClientCodec {
@@ -252,7 +352,8 @@ func NewAuthClientCodec(conn io.ReadWriteCloser, createCodec ClientCodecCreator,
 
 // Encodes the request and sends it to the server.
 // This implementation gets an auth header when appropriate, and writes it to the stream
-//  before letting the underlying codec send the rest of the request.
+//
+//	before letting the underlying codec send the rest of the request.
 func (a *AuthClientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
 	// Lock to ensure we write the header and the rest of the request back-to-back
 	//  This method may be called by multiple goroutines concurrently
@@ -267,6 +368,12 @@ func (a *AuthClientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
 
 	// Get the request off the buffer
 	request := a.buff.WriteBuff.Bytes()
+	if a.encoding != "" {
+		var err error
+		if request, err = encodePayload(a.encoding, request); err != nil {
+			return err
+		}
+	}
 
 	needsAuth := requiresAuthentication(r.ServiceMethod)
 	if err := a.headerBuilder.WriteHeader(a.conn, request, needsAuth); err != nil {
@@ -279,7 +386,8 @@ func (a *AuthClientCodec) WriteRequest(r *rpc.Request, body interface{}) error {
 }
 
 // Decodes the response and reads the header, building the rpc.Response object
-//  We don't change anything here, just let the underlying codec handle it.
+//
+//	We don't change anything here, just let the underlying codec handle it.
 func (a *AuthClientCodec) ReadResponseHeader(r *rpc.Response) error {
 
 	// No need for synchronization here, Go's RPC Client makes sure only
@@ -287,20 +395,24 @@ func (a *AuthClientCodec) ReadResponseHeader(r *rpc.Response) error {
 
 	a.buff.ReadBuff.Reset()
 
-	// Read the response from the connection
-	response, err := auth.ReadLengthAndBytes(a.conn)
-	if err != nil {
-		// It is common to get harmless errors here whenever the client is closed
-		return err
-	}
-
-	// Write the response to the buffer
-	if _, err = a.buff.ReadBuff.Write(response); err != nil {
-		return err
+	// Read the response from the connection and write it to the buffer
+	if a.encoding != "" {
+		if err := readChunked(a.conn, &a.buff.ReadBuff); err != nil {
+			return err
+		}
+	} else {
+		response, err := auth.ReadLengthAndBytes(a.conn)
+		if err != nil {
+			// It is common to get harmless errors here whenever the client is closed
+			return err
+		}
+		if _, err = a.buff.ReadBuff.Write(response); err != nil {
+			return err
+		}
 	}
 
 	// Let the underlying codec read and parse the response from the buffer
-	if err = a.wrappedcodec.ReadResponseHeader(r); err != nil {
+	if err := a.wrappedcodec.ReadResponseHeader(r); err != nil {
 		return err
 	}
 
@@ -315,8 +427,36 @@ func (a *AuthClientCodec) ReadResponseBody(body interface{}) error {
 	return a.wrappedcodec.ReadResponseBody(body)
 }
 
+// Negotiate offers the given payload encodings to the server and returns
+// the one it selected.  It must be called before the codec is used by an
+// rpc.Client.  Servers that do not support negotiation leave the connection
+// unencoded.
+func (a *AuthClientCodec) Negotiate(encodings []string) (string, error) {
+	req := &rpc.Request{ServiceMethod: negotiateMethod}
+	if err := a.WriteRequest(req, NegotiateRequest{Encodings: encodings}); err != nil {
+		return "", err
+	}
+	var resp rpc.Response
+	if err := a.ReadResponseHeader(&resp); err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		log.WithField("error", resp.Error).Debug("Server does not support RPC payload encoding")
+		return "", a.ReadResponseBody(nil)
+	}
+	var reply NegotiateResponse
+	if err := a.ReadResponseBody(&reply); err != nil {
+		return "", err
+	}
+	a.wBuffMutex.Lock()
+	a.encoding = reply.Encoding
+	a.wBuffMutex.Unlock()
+	return reply.Encoding, nil
+}
+
 // Closes the connection on the client side
-//  We don't change anything here, just let the underlying codec handle it.
+//
+//	We don't change anything here, just let the underlying codec handle it.
 func (a *AuthClientCodec) Close() error {
 	var err error
 	if err = a.wrappedcodec.Close(); err != nil {
@@ -330,7 +470,12 @@ func (a *AuthClientCodec) Close() error {
 	return err
 }
 
-// NewDefaultAuthClient returns a new rpc.Client that uses our default client codec
+// NewDefaultAuthClient returns a new rpc.Client that uses our default client
+// codec, with payload encoding negotiated with the server.
 func NewDefaultAuthClient(conn io.ReadWriteCloser) *rpc.Client {
-	return rpc.NewClientWithCodec(NewDefaultAuthClientCodec(conn))
+	codec := NewDefaultAuthClientCodec(conn).(*AuthClientCodec)
+	if _, err := codec.Negotiate(SupportedEncodings); err != nil {
+		log.WithError(err).Debug("Could not negotiate RPC payload encoding")
+	}
+	return rpc.NewClientWithCodec(codec)
 }
@@ -103,6 +103,7 @@ func (s *MySuite) TestReadRequestHeader(c *C) {
 	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
 	codectest.headerParser.On("ReadHeader", codectest.conn).Return(ident, body, nil).Once()
 	ident.On("HasAdminAccess").Return(false).Once()
+	ident.On("Role").Return(auth.Role("")).Once()
 	err = codectest.authServerCodec.ReadRequestHeader(req)
 	// Error won't come through until we call ReadRequestBody
 	c.Assert(err, IsNil)
@@ -294,6 +295,48 @@ func (s *MySuite) TestCloseClientCodec(c *C) {
 	codectest.wrappedClientCodec.AssertExpectations(c)
 }
 
+func (s *MySuite) TestAuthorize(c *C) {
+	RegisterCallRoles("RPCRoleTest", func(method string) auth.Role {
+		switch method {
+		case "Get":
+			return auth.RoleReadOnly
+		case "Restart":
+			return auth.RoleOperator
+		default:
+			return auth.RoleAdmin
+		}
+	})
+	defer delete(callRoles, "RPCRoleTest")
+
+	identWithRole := func(role auth.Role) auth.Identity {
+		ident := &authmocks.Identity{}
+		ident.On("HasAdminAccess").Return(role == auth.RoleAdmin)
+		ident.On("Role").Return(role)
+		return ident
+	}
+	readonly := identWithRole(auth.RoleReadOnly)
+	operator := identWithRole(auth.RoleOperator)
+	admin := identWithRole(auth.RoleAdmin)
+	none := identWithRole("")
+
+	c.Assert(authorize(readonly, "RPCRoleTest.Get"), IsNil)
+	c.Assert(authorize(readonly, "RPCRoleTest.Restart"), Equals, ErrRoleDenied)
+	c.Assert(authorize(readonly, "RPCRoleTest.Remove"), Equals, ErrRoleDenied)
+	c.Assert(authorize(operator, "RPCRoleTest.Get"), IsNil)
+	c.Assert(authorize(operator, "RPCRoleTest.Restart"), IsNil)
+	c.Assert(authorize(operator, "RPCRoleTest.Remove"), Equals, ErrRoleDenied)
+	c.Assert(authorize(admin, "RPCRoleTest.Remove"), IsNil)
+	c.Assert(authorize(none, "RPCRoleTest.Get"), Equals, ErrNoAdmin)
+	c.Assert(authorize(nil, "RPCRoleTest.Get"), Equals, ErrNoAdmin)
+
+	// services without registered roles require an admin
+	c.Assert(authorize(operator, "RPCTestType.AdminRequiredCall"), Equals, ErrRoleDenied)
+	c.Assert(authorize(admin, "RPCTestType.AdminRequiredCall"), IsNil)
+
+	// calls that do not require admin only need a valid identity
+	c.Assert(authorize(none, "RPCTestType.NonAdminRequiredCall"), IsNil)
+}
+
 func (s *MySuite) TestRequiresAdmin(c *C) {
 	result := requiresAdmin("RPCTestType.NonAdminRequiredCall")
 	c.Assert(result, Equals, false)
@@ -14,6 +14,7 @@
 package rpcutils
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -27,6 +28,9 @@ var RPCCertVerify = false
 // RPCDisableTLS used to disable TLS connections
 var RPCDisableTLS = false
 
+// RPCUseGRPC used to call servers over gRPC when they support it
+var RPCUseGRPC = false
+
 // DiscardClientTimeout timeout for removing client from pool if a call is taking too long. Does not interrupt call.
 var DiscardClientTimeout = 30 * time.Second
 
@@ -43,7 +47,9 @@ type Client interface {
 	Call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error
 }
 
-// GetCachedClient createa or gets a cached Client.
+// GetCachedClient createa or gets a cached Client.  The address may be a
+// comma-separated list of endpoints, in which case the client fails over
+// between them.
 func GetCachedClient(addr string) (Client, error) {
 	return getClient(addr)
 }
@@ -84,7 +90,20 @@ func setAndGetClient(addr string) (Client, error) {
 	addrLock.Lock()
 	defer addrLock.Unlock()
 	client, found := clientCache[addr]
-	if !found {
+	if !found && strings.Contains(addr, ",") {
+		client, err = newFailoverClient(SplitEndpoints(addr), getClient)
+		if err != nil {
+			return nil, err
+		}
+		clientCache[addr] = client
+	} else if !found {
+		if RPCUseGRPC {
+			// fall back to jsonrpc if the server does not support grpc
+			if client, err = dialGRPC(addr); err == nil {
+				clientCache[addr] = client
+				return client, nil
+			}
+		}
 		connFn := connectRPCTLS
 		if RPCDisableTLS {
 			connFn = connectRPC
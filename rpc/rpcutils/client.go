@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/commons/pool"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
@@ -38,6 +39,14 @@ func SetDialTimeout(timeout int) {
 
 type connectRPCFn func(add string) (*rpc.Client, error)
 
+// timeoutError is returned when an rpc call times out.  The call may not have
+// reached the server, so it is transient.
+type timeoutError string
+
+func (e timeoutError) Error() string { return string(e) }
+
+func (e timeoutError) ErrorCode() apierror.Code { return apierror.Transient }
+
 func connectRPC(addr string) (*rpc.Client, error) {
 	logger := plog.WithFields(logrus.Fields{
 		"address": addr,
@@ -60,7 +69,7 @@ func connectRPCTLS(addr string) (*rpc.Client, error) {
 	logger.Debug("Connecting to RPC server with TLS")
 	config := tls.Config{InsecureSkipVerify: !RPCCertVerify}
 	timeoutDialer := net.Dialer{Timeout: time.Duration(dialTimeoutSecs) * time.Second}
-	conn, err := tls.DialWithDialer(&timeoutDialer, "tcp4", addr, &config)
+	conn, err := tls.DialWithDialer(&timeoutDialer, "tcp", addr, &config)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +127,7 @@ func (rc *reconnectingClient) Call(serviceMethod string, args interface{}, reply
 		if remaining < 0 {
 			rc.pool.Return(item)
 			item = nil
-			return fmt.Errorf("RPC call to %s timed out waiting for client", serviceMethod)
+			return timeoutError(fmt.Sprintf("RPC call to %s timed out waiting for client", serviceMethod))
 		}
 		timeout = remaining
 	}
@@ -154,7 +163,7 @@ func (rc *reconnectingClient) Call(serviceMethod string, args interface{}, reply
 			item = nil
 			return e
 		case <-timer.C:
-			err = fmt.Errorf("RPC call to %s timed out after %s", serviceMethod, timeout)
+			err = timeoutError(fmt.Sprintf("RPC call to %s timed out after %s", serviceMethod, timeout))
 			rpcClient.Close()
 			rc.pool.Remove(item)
 			item = nil
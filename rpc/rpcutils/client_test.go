@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/control-center/serviced/apierror"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/commons/pool"
 	. "gopkg.in/check.v1"
@@ -167,6 +168,7 @@ func (s *MySuite) TestTimeout(c *C) {
 	err = client.Call("RPCTestType.Sleep", &sleepTime, &reply, sleepTime/2)
 	c.Assert(err, NotNil)
 	c.Assert(err, ErrorMatches, "RPC call to RPCTestType.Sleep timed out after .+")
+	c.Assert(apierror.IsTransient(err), Equals, true)
 }
 
 func (s *MySuite) TestLongCall(c *C) {
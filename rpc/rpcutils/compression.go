@@ -0,0 +1,232 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/control-center/serviced/auth"
+)
+
+const (
+	// EncodingGzip compresses rpc payloads with gzip
+	EncodingGzip = "gzip"
+
+	// negotiateMethod is handled by the server codec itself and is never
+	// dispatched to a registered receiver.  Servers that predate payload
+	// encoding return an error, and the connection stays uncompressed.
+	negotiateMethod = "RPCCodec.Negotiate"
+)
+
+// payload frame flags
+const (
+	frameRaw  byte = 0
+	frameGzip byte = 1
+)
+
+var (
+	// SupportedEncodings are the payload encodings offered by clients, in
+	// order of preference.
+	SupportedEncodings = []string{EncodingGzip}
+
+	// CompressionThreshold is the minimum size in bytes of a payload before
+	// it is compressed.
+	CompressionThreshold = 32 * 1024
+
+	// ChunkSize is the maximum size in bytes of a single frame of a chunked
+	// response.
+	ChunkSize = 256 * 1024
+
+	// ErrBadPayloadFrame is returned when a payload frame cannot be decoded
+	ErrBadPayloadFrame = errors.New("bad rpc payload frame")
+)
+
+// NegotiateRequest lists the payload encodings supported by the client
+type NegotiateRequest struct {
+	Encodings []string
+}
+
+// NegotiateResponse is the payload encoding selected by the server.  An
+// empty encoding means payloads are sent as-is.
+type NegotiateResponse struct {
+	Encoding string
+}
+
+// selectEncoding returns the first offered encoding that is supported
+func selectEncoding(offered []string) string {
+	for _, enc := range offered {
+		for _, supported := range SupportedEncodings {
+			if enc == supported {
+				return enc
+			}
+		}
+	}
+	return ""
+}
+
+// encodePayload prefixes the payload with its frame flag, compressing it if
+// it is large enough.
+func encodePayload(encoding string, payload []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := writePayload(buf, encoding, payload); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodePayload is the inverse of encodePayload
+func decodePayload(frame []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := readPayload(bytes.NewReader(frame), buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writePayload writes the frame flag and the (possibly compressed) payload
+func writePayload(w io.Writer, encoding string, payload []byte) error {
+	if encoding != EncodingGzip || len(payload) < CompressionThreshold {
+		if _, err := w.Write([]byte{frameRaw}); err != nil {
+			return err
+		}
+		_, err := w.Write(payload)
+		return err
+	}
+	if _, err := w.Write([]byte{frameGzip}); err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(payload); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readPayload reads the frame flag and copies the decoded payload to dst
+func readPayload(r io.Reader, dst io.Writer) error {
+	var flag [1]byte
+	if _, err := io.ReadFull(r, flag[:]); err != nil {
+		return err
+	}
+	switch flag[0] {
+	case frameRaw:
+		_, err := io.Copy(dst, r)
+		return err
+	case frameGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		_, err = io.Copy(dst, gz)
+		return err
+	default:
+		return ErrBadPayloadFrame
+	}
+}
+
+// writeChunked writes the payload as a series of length-prefixed frames of at
+// most ChunkSize bytes, terminated by an empty frame, so that large payloads
+// are compressed straight onto the connection.
+func writeChunked(w io.Writer, encoding string, payload []byte) error {
+	cw := &chunkWriter{w: w, buf: make([]byte, 0, ChunkSize)}
+	if err := writePayload(cw, encoding, payload); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// readChunked is the inverse of writeChunked
+func readChunked(r io.Reader, dst io.Writer) error {
+	cr := &chunkReader{r: r}
+	if err := readPayload(cr, dst); err != nil {
+		return err
+	}
+	// consume the terminating frame
+	_, err := io.Copy(ioutil.Discard, cr)
+	return err
+}
+
+// chunkWriter buffers writes into frames of at most ChunkSize bytes
+type chunkWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		m := cap(cw.buf) - len(cw.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		cw.buf = append(cw.buf, p[:m]...)
+		p = p[m:]
+		n += m
+		if len(cw.buf) == cap(cw.buf) {
+			if err := cw.flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (cw *chunkWriter) flush() error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	err := auth.WriteLengthAndBytes(cw.buf, cw.w)
+	cw.buf = cw.buf[:0]
+	return err
+}
+
+// Close flushes any buffered data and writes the terminating frame
+func (cw *chunkWriter) Close() error {
+	if err := cw.flush(); err != nil {
+		return err
+	}
+	return auth.WriteLengthAndBytes([]byte{}, cw.w)
+}
+
+// chunkReader reads frames written by a chunkWriter until the terminating
+// frame.
+type chunkReader struct {
+	r    io.Reader
+	cur  []byte
+	done bool
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.cur) == 0 {
+		if cr.done {
+			return 0, io.EOF
+		}
+		b, err := auth.ReadLengthAndBytes(cr.r)
+		if err != nil {
+			return 0, err
+		}
+		if len(b) == 0 {
+			cr.done = true
+		}
+		cr.cur = b
+	}
+	n := copy(p, cr.cur)
+	cr.cur = cr.cur[n:]
+	return n, nil
+}
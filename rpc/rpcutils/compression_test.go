@@ -0,0 +1,95 @@
+// +build unit
+
+package rpcutils
+
+import (
+	"bytes"
+	"net"
+	"net/rpc"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) TestEncodePayload(c *C) {
+	small := []byte("small payload")
+	frame, err := encodePayload(EncodingGzip, small)
+	c.Assert(err, IsNil)
+	c.Assert(frame[0], Equals, frameRaw)
+	decoded, err := decodePayload(frame)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, small)
+
+	large := []byte(strings.Repeat("large payload ", CompressionThreshold))
+	frame, err = encodePayload(EncodingGzip, large)
+	c.Assert(err, IsNil)
+	c.Assert(frame[0], Equals, frameGzip)
+	c.Assert(len(frame) < len(large), Equals, true)
+	decoded, err = decodePayload(frame)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, large)
+
+	frame, err = encodePayload("", large)
+	c.Assert(err, IsNil)
+	c.Assert(frame[0], Equals, frameRaw)
+
+	_, err = decodePayload([]byte{9, 1, 2})
+	c.Assert(err, Equals, ErrBadPayloadFrame)
+}
+
+func (s *MySuite) TestChunked(c *C) {
+	defer func(size int) { ChunkSize = size }(ChunkSize)
+	ChunkSize = 16
+
+	for _, enc := range []string{"", EncodingGzip} {
+		payload := []byte(strings.Repeat("0123456789", CompressionThreshold/5))
+		buf := &bytes.Buffer{}
+		c.Assert(writeChunked(buf, enc, payload), IsNil)
+		c.Assert(writeChunked(buf, enc, []byte("next")), IsNil)
+
+		out := &bytes.Buffer{}
+		c.Assert(readChunked(buf, out), IsNil)
+		c.Assert(out.Bytes(), DeepEquals, payload)
+
+		// the stream is positioned at the next payload
+		out.Reset()
+		c.Assert(readChunked(buf, out), IsNil)
+		c.Assert(out.String(), Equals, "next")
+		c.Assert(buf.Len(), Equals, 0)
+	}
+}
+
+func (s *MySuite) TestSelectEncoding(c *C) {
+	c.Assert(selectEncoding([]string{"zstd", EncodingGzip}), Equals, EncodingGzip)
+	c.Assert(selectEncoding([]string{"zstd"}), Equals, "")
+	c.Assert(selectEncoding(nil), Equals, "")
+}
+
+func (s *MySuite) TestNegotiatedCall(c *C) {
+	conn, err := net.Dial("tcp", "localhost:32111")
+	c.Assert(err, IsNil)
+	codec := NewDefaultAuthClientCodec(conn).(*AuthClientCodec)
+	enc, err := codec.Negotiate(SupportedEncodings)
+	c.Assert(err, IsNil)
+	c.Assert(enc, Equals, EncodingGzip)
+
+	client := rpc.NewClientWithCodec(codec)
+	defer client.Close()
+	var reply string
+	arg := strings.Repeat("echo ", CompressionThreshold)
+	c.Assert(client.Call("RPCTestType.Echo", arg, &reply), IsNil)
+	c.Assert(reply, Equals, arg)
+	c.Assert(client.Call("RPCTestType.Echo", "short", &reply), IsNil)
+	c.Assert(reply, Equals, "short")
+}
+
+func (s *MySuite) TestUnnegotiatedCall(c *C) {
+	conn, err := net.Dial("tcp", "localhost:32111")
+	c.Assert(err, IsNil)
+	client := rpc.NewClientWithCodec(NewDefaultAuthClientCodec(conn))
+	defer client.Close()
+	var reply string
+	arg := strings.Repeat("echo ", CompressionThreshold)
+	c.Assert(client.Call("RPCTestType.Echo", arg, &reply), IsNil)
+	c.Assert(reply, Equals, arg)
+}
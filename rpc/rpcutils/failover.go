@@ -0,0 +1,188 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// EndpointCheckInterval is how often an unreachable endpoint is checked
+// before it is used again.
+var EndpointCheckInterval = 5 * time.Second
+
+// ErrNoEndpoints is returned when a failover client has no endpoints
+var ErrNoEndpoints = errors.New("no rpc endpoints configured")
+
+// SplitEndpoints splits a comma-separated list of rpc endpoints.
+func SplitEndpoints(addrs string) []string {
+	endpoints := []string{}
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			endpoints = append(endpoints, addr)
+		}
+	}
+	return endpoints
+}
+
+// PrimaryEndpoint returns the first endpoint of a comma-separated list of rpc
+// endpoints.
+func PrimaryEndpoint(addrs string) string {
+	if endpoints := SplitEndpoints(addrs); len(endpoints) > 0 {
+		return endpoints[0]
+	}
+	return ""
+}
+
+type getClientFn func(addr string) (Client, error)
+
+// failoverClient sends calls to one of several endpoints and moves on to the
+// next endpoint when the current one cannot be reached.  Unreachable endpoints
+// are checked in the background and used again once they accept connections.
+type failoverClient struct {
+	mu        sync.Mutex
+	endpoints []string
+	down      []bool
+	current   int
+	getClient getClientFn
+}
+
+func newFailoverClient(endpoints []string, fn getClientFn) (Client, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return &failoverClient{
+		endpoints: endpoints,
+		down:      make([]bool, len(endpoints)),
+		getClient: fn,
+	}, nil
+}
+
+// order returns the endpoint indexes in the order they should be tried; the
+// current endpoint first, then the other reachable endpoints and finally the
+// unreachable ones as a last resort.
+func (fc *failoverClient) order() []int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	up, down := []int{}, []int{}
+	for i := 0; i < len(fc.endpoints); i++ {
+		idx := (fc.current + i) % len(fc.endpoints)
+		if fc.down[idx] {
+			down = append(down, idx)
+		} else {
+			up = append(up, idx)
+		}
+	}
+	return append(up, down...)
+}
+
+func (fc *failoverClient) Call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	var err error
+	for _, idx := range fc.order() {
+		addr := fc.endpoints[idx]
+		logger := plog.WithFields(logrus.Fields{
+			"method":   serviceMethod,
+			"endpoint": addr,
+		})
+
+		if !deadline.IsZero() {
+			if timeout = deadline.Sub(time.Now()); timeout <= 0 {
+				break
+			}
+		}
+
+		var client Client
+		if client, err = fc.getClient(addr); err == nil {
+			err = client.Call(serviceMethod, args, reply, timeout)
+		}
+		if err == nil || !isConnectionError(err) {
+			fc.setCurrent(idx)
+			return err
+		}
+
+		logger.WithError(err).Warn("Could not reach rpc endpoint, trying the next one")
+		fc.markDown(idx)
+	}
+	return err
+}
+
+func (fc *failoverClient) Close() error {
+	// the endpoint clients are cached and reused
+	return nil
+}
+
+func (fc *failoverClient) setCurrent(idx int) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.current = idx
+	fc.down[idx] = false
+}
+
+// markDown flags an endpoint as unreachable and checks it in the background
+// until it accepts connections again.
+func (fc *failoverClient) markDown(idx int) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if fc.down[idx] {
+		return
+	}
+	fc.down[idx] = true
+	go fc.check(idx)
+}
+
+func (fc *failoverClient) check(idx int) {
+	addr := fc.endpoints[idx]
+	for {
+		<-time.After(EndpointCheckInterval)
+		conn, err := net.DialTimeout("tcp", addr, time.Duration(dialTimeoutSecs)*time.Second)
+		if err == nil {
+			conn.Close()
+			fc.mu.Lock()
+			fc.down[idx] = false
+			fc.mu.Unlock()
+			plog.WithField("endpoint", addr).Info("Rpc endpoint is reachable again")
+			return
+		}
+	}
+}
+
+// isConnectionError returns true if the error means the endpoint could not be
+// reached, rather than an error returned by the remote method.
+func isConnectionError(err error) bool {
+	if _, ok := err.(rpc.ServerError); ok {
+		return false
+	}
+	if err == rpc.ErrShutdown || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if grpc.Code(err) == codes.Unavailable {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
@@ -0,0 +1,98 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package rpcutils
+
+import (
+	"net"
+	"net/rpc"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func testClientFn(addr string) (Client, error) {
+	return newClient(addr, 1, DiscardClientTimeout, connectRPC)
+}
+
+// closedAddr returns an address that refuses connections
+func closedAddr(c *C) string {
+	l, err := net.Listen("tcp", "localhost:0")
+	c.Assert(err, IsNil)
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func (s *MySuite) TestSplitEndpoints(c *C) {
+	c.Assert(SplitEndpoints(""), DeepEquals, []string{})
+	c.Assert(SplitEndpoints("a:1"), DeepEquals, []string{"a:1"})
+	c.Assert(SplitEndpoints(" a:1, b:2 ,,"), DeepEquals, []string{"a:1", "b:2"})
+	c.Assert(PrimaryEndpoint("a:1,b:2"), Equals, "a:1")
+	c.Assert(PrimaryEndpoint(""), Equals, "")
+}
+
+func (s *MySuite) TestFailoverClient_NoEndpoints(c *C) {
+	_, err := newFailoverClient([]string{}, testClientFn)
+	c.Assert(err, Equals, ErrNoEndpoints)
+}
+
+func (s *MySuite) TestFailoverClient_Failover(c *C) {
+	down := closedAddr(c)
+	client, err := newFailoverClient([]string{down, "localhost:32111"}, testClientFn)
+	c.Assert(err, IsNil)
+	fc := client.(*failoverClient)
+
+	var reply string
+	err = client.Call("RPCTestType.Echo", "hello", &reply, 5*time.Second)
+	c.Assert(err, IsNil)
+	c.Assert(reply, Equals, "hello")
+
+	fc.mu.Lock()
+	c.Check(fc.current, Equals, 1)
+	c.Check(fc.down[0], Equals, true)
+	c.Check(fc.down[1], Equals, false)
+	fc.mu.Unlock()
+
+	// the working endpoint is tried first from now on
+	c.Assert(fc.order(), DeepEquals, []int{1, 0})
+}
+
+func (s *MySuite) TestFailoverClient_ServerError(c *C) {
+	client, err := newFailoverClient([]string{"localhost:32111", closedAddr(c)}, testClientFn)
+	c.Assert(err, IsNil)
+	fc := client.(*failoverClient)
+
+	// errors from the remote method are returned without failing over
+	var reply string
+	err = client.Call("RPCTestType.NoSuchMethod", "hello", &reply, 5*time.Second)
+	c.Assert(err, FitsTypeOf, rpc.ServerError(""))
+
+	fc.mu.Lock()
+	c.Check(fc.current, Equals, 0)
+	c.Check(fc.down[0], Equals, false)
+	c.Check(fc.down[1], Equals, false)
+	fc.mu.Unlock()
+}
+
+func (s *MySuite) TestFailoverClient_AllDown(c *C) {
+	client, err := newFailoverClient([]string{closedAddr(c), closedAddr(c)}, testClientFn)
+	c.Assert(err, IsNil)
+
+	var reply string
+	err = client.Call("RPCTestType.Echo", "hello", &reply, 5*time.Second)
+	c.Assert(err, NotNil)
+	c.Assert(isConnectionError(err), Equals, true)
+}
@@ -0,0 +1,233 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcutils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/rpc/rpcpb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// http2Preface is the start of the preface that every gRPC client sends on a
+// new connection
+var http2Preface = []byte("PRI")
+
+// ErrListenerClosed is returned when accepting connections on a closed
+// gRPC listener
+var ErrListenerClosed = errors.New("grpc listener closed")
+
+// GRPCServer serves the receivers of an rpc.Server over gRPC and jsonrpc on
+// the same connections.
+type GRPCServer struct {
+	rpcServer *rpc.Server
+	server    *grpc.Server
+	listener  *connListener
+}
+
+// NewGRPCServer returns a server for the receivers registered on rpcServer
+func NewGRPCServer(rpcServer *rpc.Server) *GRPCServer {
+	s := &GRPCServer{
+		rpcServer: rpcServer,
+		server:    grpc.NewServer(),
+		listener:  newConnListener(),
+	}
+	rpcpb.RegisterTransportServer(s.server, &grpcTransport{rpcServer: rpcServer})
+	go s.server.Serve(s.listener)
+	return s
+}
+
+// ServeConn serves a connection over gRPC if the client opened it with the
+// HTTP/2 preface, or over jsonrpc otherwise.
+func (s *GRPCServer) ServeConn(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	start, err := r.Peek(len(http2Preface))
+	if err != nil {
+		plog.WithError(err).Debug("Could not read from RPC connection")
+		conn.Close()
+		return
+	}
+	conn = &peekedConn{Conn: conn, r: r}
+	if bytes.Equal(start, http2Preface) {
+		s.listener.serve(conn)
+		return
+	}
+	s.rpcServer.ServeCodec(NewDefaultAuthServerCodec(conn))
+}
+
+// Stop closes all gRPC connections
+func (s *GRPCServer) Stop() {
+	s.server.Stop()
+}
+
+// grpcTransport serves the frames of the jsonrpc transport over gRPC
+type grpcTransport struct {
+	rpcServer *rpc.Server
+}
+
+// Call serves a single request frame with the auth codec and returns the
+// response frame.  An empty frame is answered with an empty frame, so that
+// clients can detect gRPC support.
+func (t *grpcTransport) Call(ctx context.Context, in *rpcpb.Frame) (*rpcpb.Frame, error) {
+	if len(in.Payload) == 0 {
+		return &rpcpb.Frame{}, nil
+	}
+	buff := &ByteBufferReadWriteCloser{}
+	buff.ReadBuff.Write(in.Payload)
+	if err := t.rpcServer.ServeRequest(NewDefaultAuthServerCodec(buff)); err != nil && buff.WriteBuff.Len() == 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "%s", err)
+	}
+	return &rpcpb.Frame{Payload: buff.WriteBuff.Bytes()}, nil
+}
+
+// peekedConn is a connection whose first bytes have been buffered
+type peekedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// connListener hands connections accepted by another listener to the gRPC
+// server
+type connListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newConnListener() *connListener {
+	return &connListener{
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+}
+
+func (l *connListener) serve(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		conn.Close()
+	}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, ErrListenerClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+// grpcClient calls rpc methods over a gRPC connection
+type grpcClient struct {
+	addr   string
+	conn   *grpc.ClientConn
+	client rpcpb.TransportClient
+}
+
+// dialGRPC returns a gRPC client for the address if the server supports it
+func dialGRPC(addr string) (Client, error) {
+	logger := plog.WithFields(logrus.Fields{
+		"address": addr,
+		"tls":     !RPCDisableTLS,
+	})
+	timeout := time.Duration(dialTimeoutSecs) * time.Second
+	dialer := func(addr string, timeout time.Duration) (net.Conn, error) {
+		if RPCDisableTLS {
+			return net.DialTimeout("tcp", addr, timeout)
+		}
+		config := tls.Config{InsecureSkipVerify: !RPCCertVerify}
+		return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, &config)
+	}
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithDialer(dialer), grpc.WithTimeout(timeout))
+	if err != nil {
+		return nil, err
+	}
+	client := rpcpb.NewTransportClient(conn)
+
+	// probe the server with an empty frame
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := client.Call(ctx, &rpcpb.Frame{}); err != nil {
+		logger.WithError(err).Debug("RPC server does not support gRPC")
+		conn.Close()
+		return nil, err
+	}
+	logger.Debug("Connected to RPC server with gRPC")
+	return &grpcClient{addr: addr, conn: conn, client: client}, nil
+}
+
+// Call encodes the request with the auth codec and sends it to the server
+func (c *grpcClient) Call(serviceMethod string, args interface{}, reply interface{}, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 365 * 24 * time.Hour
+	}
+	buff := &ByteBufferReadWriteCloser{}
+	codec := NewAuthClientCodec(buff, jsonrpc.NewClientCodec, &auth.RPCHeaderHandler{})
+	if err := codec.WriteRequest(&rpc.Request{ServiceMethod: serviceMethod}, args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := c.client.Call(ctx, &rpcpb.Frame{Payload: buff.WriteBuff.Bytes()})
+	if err != nil {
+		if grpc.Code(err) == codes.DeadlineExceeded {
+			return timeoutError(fmt.Sprintf("RPC call to %s timed out after %s", serviceMethod, timeout))
+		}
+		return err
+	}
+
+	buff.ReadBuff.Write(out.Payload)
+	var resp rpc.Response
+	if err := codec.ReadResponseHeader(&resp); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		codec.ReadResponseBody(nil)
+		return rpc.ServerError(resp.Error)
+	}
+	return codec.ReadResponseBody(reply)
+}
+
+// Close closes the gRPC connection
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
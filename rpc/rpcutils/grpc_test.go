@@ -0,0 +1,65 @@
+// +build unit
+
+package rpcutils
+
+import (
+	"net"
+	"net/rpc"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MySuite) serveGRPC(c *C, addr string) {
+	listener, err := net.Listen("tcp", addr)
+	c.Assert(err, IsNil)
+	server := NewGRPCServer(rpc.DefaultServer)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeConn(conn)
+		}
+	}()
+}
+
+func (s *MySuite) TestGRPCCall(c *C) {
+	defer func(v bool) { RPCDisableTLS = v }(RPCDisableTLS)
+	RPCDisableTLS = true
+	s.serveGRPC(c, ":32113")
+
+	client, err := dialGRPC("localhost:32113")
+	c.Assert(err, IsNil)
+	defer client.Close()
+
+	var reply string
+	c.Assert(client.Call("RPCTestType.Echo", "hello", &reply, 0), IsNil)
+	c.Assert(reply, Equals, "hello")
+
+	args := TestArgs{A: "a", B: 1, C: true, D: []string{"d"}}
+	var structReply TestArgs
+	c.Assert(client.Call("RPCTestType.StructCall", args, &structReply, 0), IsNil)
+	c.Assert(structReply, DeepEquals, args)
+
+	err = client.Call("RPCTestType.Missing", "hello", &reply, 0)
+	c.Assert(err, FitsTypeOf, rpc.ServerError(""))
+
+	// jsonrpc clients are served on the same port
+	jsonClient, err := newClient("localhost:32113", 1, DiscardClientTimeout, connectRPC)
+	c.Assert(err, IsNil)
+	defer jsonClient.Close()
+	reply = ""
+	c.Assert(jsonClient.Call("RPCTestType.Echo", "world", &reply, 0), IsNil)
+	c.Assert(reply, Equals, "world")
+}
+
+func (s *MySuite) TestGRPCUnsupported(c *C) {
+	defer func(v bool, t int) { RPCDisableTLS, dialTimeoutSecs = v, t }(RPCDisableTLS, dialTimeoutSecs)
+	RPCDisableTLS = true
+	dialTimeoutSecs = 1
+
+	// the suite server only speaks jsonrpc
+	_, err := dialGRPC("localhost:32111")
+	c.Assert(err, NotNil)
+}
@@ -21,6 +21,7 @@ import (
 	coordclient "github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/feature"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/scheduler/strategy"
@@ -62,6 +63,13 @@ func Lead(shutdown <-chan interface{}, conn coordclient.Connection, cpClient dao
 // the host with the least amount of memory committed to running containers will
 // be chosen.  Returns the hostid, hostip (if it has an address assignment).
 func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
+	return l.SelectInstanceHost(sn, "")
+}
+
+// SelectInstanceHost chooses a host like SelectHost, but keeps the instance on
+// the preferred host (where it last ran) if that host is available and still
+// satisfies the service's host policy.
+func (l *leader) SelectInstanceHost(sn *zkservice.ServiceNode, preferredHostID string) (string, error) {
 	logger := plog.WithFields(log.Fields{
 		"serviceid":   sn.ID,
 		"servicename": sn.Name,
@@ -99,6 +107,13 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", ErrNoAuthenticatedHosts
 	}
 
+	// only schedule on hosts that match the node selector of the service
+	hosts = NodeSelectorHosts(sn, hosts)
+	if len(hosts) == 0 {
+		logger.Debug("No hosts match the node selector of the service")
+		return "", ErrNoSelectedHosts
+	}
+
 	assignment := sn.AddressAssignment
 	if sn.ShouldHaveAddressAssignment && assignment.IPAddr == "" {
 		plog.WithField("endpoint", sn.Name).Debug("Service is missing an address assignment")
@@ -146,12 +161,51 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", errors.New("assigned ip is not available")
 	}
 
-	hp := sn.HostPolicy
+	// apply the scheduling strategy of the pool
+	p, err := l.facade.GetResourcePool(datastore.Get(), l.poolID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up resource pool")
+		return "", err
+	} else if p == nil {
+		return "", errors.New("resource pool not found")
+	}
+
+	hosts, hp := PoolStrategyHosts(p.GetSchedulingStrategy(), sn, hosts)
+	if len(hosts) == 0 {
+		logger.WithField("strategy", p.GetSchedulingStrategy()).Debug("No hosts match the host affinity of the service")
+		return "", ErrNoMatchingHosts
+	}
+
 	strat, err := strategy.Get(string(hp))
 	if err != nil {
 		return "", err
 	}
 
+	// try to keep the instance where it last ran
+	if preferredHostID != "" {
+		sticky, err := l.facade.FeatureEnabled(datastore.Get(), feature.StickyRescheduling, l.poolID, "")
+		if err != nil {
+			logger.WithError(err).Debug("Could not check whether sticky rescheduling is enabled")
+		}
+		if err != nil || !sticky {
+			preferredHostID = ""
+		}
+	}
+	if preferredHostID != "" {
+		hlogger := logger.WithField("hostid", preferredHostID)
+		for _, h := range hosts {
+			if h.ID == preferredHostID {
+				if ok, err := StrategyKeepHost(sn, h, l.facade); err != nil {
+					hlogger.WithError(err).Debug("Could not check whether the instance can stay on its previous host")
+				} else if ok {
+					hlogger.Debug("Rescheduling instance on its previous host")
+					return preferredHostID, nil
+				}
+				break
+			}
+		}
+	}
+
 	return StrategySelectHost(sn, hosts, strat, l.facade)
 }
 
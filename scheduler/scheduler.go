@@ -36,6 +36,8 @@ var plog = logging.PackageLogger()
 
 var (
 	ErrNoAuthenticatedHosts = errors.New("no authenticated hosts found")
+	ErrNoMatchingHosts      = errors.New("no hosts match the host affinity of the service")
+	ErrNoSelectedHosts      = errors.New("no hosts match the node selector of the service")
 )
 
 type leaderFunc func(<-chan interface{}, coordclient.Connection, dao.ControlPlane, *facade.Facade, string)
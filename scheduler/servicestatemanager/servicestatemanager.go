@@ -23,6 +23,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/logging"
 )
 
@@ -85,10 +86,19 @@ type CancellableServices []*CancellableService
 func (s CancellableServices) Len() int      { return len(s) }
 func (s CancellableServices) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
+// priority returns the rank of the priority class of the service at index i
+func (s CancellableServices) priority(i int) int {
+	return servicedefinition.PriorityRank(s[i].PriorityClass)
+}
+
 type ByEmergencyShutdown struct{ CancellableServices }
 
-// Sort by EmergencyShutdownLevel - 1, to ensure level of 0 is last
+// Sort by priority class, lowest first, and then by EmergencyShutdownLevel - 1,
+// to ensure level of 0 is last
 func (s ByEmergencyShutdown) Less(i, j int) bool {
+	if pi, pj := s.priority(i), s.priority(j); pi != pj {
+		return pi < pj
+	}
 	if s.CancellableServices[i].EmergencyShutdownLevel == s.CancellableServices[j].EmergencyShutdownLevel {
 		// If emergency shutdown level is the same, order by reverse start level
 		return s.CancellableServices[i].StartLevel-1 > s.CancellableServices[j].StartLevel-1
@@ -99,13 +109,21 @@ func (s ByEmergencyShutdown) Less(i, j int) bool {
 
 type ByStartLevel struct{ CancellableServices }
 
+// Within a start level, the highest priority class starts first
 func (s ByStartLevel) Less(i, j int) bool {
+	if s.CancellableServices[i].StartLevel == s.CancellableServices[j].StartLevel {
+		return s.priority(i) > s.priority(j)
+	}
 	return s.CancellableServices[i].StartLevel-1 < s.CancellableServices[j].StartLevel-1
 }
 
 type ByReverseStartLevel struct{ CancellableServices }
 
+// Within a start level, the lowest priority class stops first
 func (s ByReverseStartLevel) Less(i, j int) bool {
+	if s.CancellableServices[i].StartLevel == s.CancellableServices[j].StartLevel {
+		return s.priority(i) < s.priority(j)
+	}
 	return s.CancellableServices[i].StartLevel-1 > s.CancellableServices[j].StartLevel-1
 }
 
@@ -833,8 +851,8 @@ func (s *ServiceStateQueue) mergeBatch(newBatch ServiceStateChangeBatch) error {
 	return nil
 }
 
-// MergeBatches sorts the services is batches by StartLevel, desiredState, and emergency,
-// creates a new batch from the services and returns is
+// MergeBatches sorts the services is batches by StartLevel, priority class, desiredState,
+// and emergency, creates a new batch from the services and returns is
 func MergeBatches(batches []ServiceStateChangeBatch) ([]ServiceStateChangeBatch, error) {
 	if len(batches) < 1 {
 		return batches, nil
@@ -882,6 +900,7 @@ func MergeBatches(batches []ServiceStateChangeBatch) ([]ServiceStateChangeBatch,
 	// regroup the services by level
 	previousEmergencyLevel := fullServiceList[0].EmergencyShutdownLevel
 	previousStartLevel := fullServiceList[0].StartLevel
+	previousPriority := servicedefinition.PriorityRank(fullServiceList[0].PriorityClass)
 
 	newBatches := []ServiceStateChangeBatch{}
 	newSvcs := make(map[string]*CancellableService)
@@ -889,6 +908,7 @@ func MergeBatches(batches []ServiceStateChangeBatch) ([]ServiceStateChangeBatch,
 	for _, svc := range fullServiceList {
 		currentEmergencyLevel := svc.EmergencyShutdownLevel
 		currentStartLevel := svc.StartLevel
+		currentPriority := servicedefinition.PriorityRank(svc.PriorityClass)
 		var sameBatch bool
 
 		if emergency && desiredState == service.SVCStop {
@@ -902,6 +922,8 @@ func MergeBatches(batches []ServiceStateChangeBatch) ([]ServiceStateChangeBatch,
 			// this service should be in the same batch if it has the same start level
 			sameBatch = currentStartLevel == previousStartLevel
 		}
+		// services of different priority classes are never in the same batch
+		sameBatch = sameBatch && currentPriority == previousPriority
 
 		if sameBatch {
 			// add it to newSvcs
@@ -918,6 +940,7 @@ func MergeBatches(batches []ServiceStateChangeBatch) ([]ServiceStateChangeBatch,
 		}
 		previousEmergencyLevel = currentEmergencyLevel
 		previousStartLevel = currentStartLevel
+		previousPriority = currentPriority
 	}
 
 	// Add the last batch
@@ -22,6 +22,7 @@ import (
 
 	datastoremocks "github.com/control-center/serviced/datastore/mocks"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	ssm "github.com/control-center/serviced/scheduler/servicestatemanager"
 	"github.com/control-center/serviced/scheduler/servicestatemanager/mocks"
 	"github.com/stretchr/testify/mock"
@@ -1886,6 +1887,35 @@ func (s *ServiceStateManagerSuite) TestServiceStateManager_MergeBatches_Unmatche
 	c.Assert(err, Equals, ssm.ErrMismatchedDesiredStates)
 }
 
+func (s *ServiceStateManagerSuite) TestServiceStateManager_MergeBatches_PriorityClass(c *C) {
+	svcs := map[string]*ssm.CancellableService{
+		"critical": ssm.NewCancellableService(&service.Service{ID: "critical", StartLevel: 1, PriorityClass: servicedefinition.PriorityCritical}),
+		"normal":   ssm.NewCancellableService(&service.Service{ID: "normal", StartLevel: 1}),
+		"low":      ssm.NewCancellableService(&service.Service{ID: "low", StartLevel: 1, EmergencyShutdownLevel: 2, PriorityClass: servicedefinition.PriorityLow}),
+		"level2":   ssm.NewCancellableService(&service.Service{ID: "level2", StartLevel: 2, EmergencyShutdownLevel: 1, PriorityClass: servicedefinition.PriorityHigh}),
+	}
+	batchIDs := func(batches []ssm.ServiceStateChangeBatch) [][]string {
+		ids := [][]string{}
+		for _, b := range batches {
+			c.Assert(b.Services, HasLen, 1)
+			for id := range b.Services {
+				ids = append(ids, []string{id})
+			}
+		}
+		return ids
+	}
+
+	// within a start level, the highest class starts first
+	batches, err := ssm.MergeBatches([]ssm.ServiceStateChangeBatch{{Services: svcs, DesiredState: service.SVCRun}})
+	c.Assert(err, IsNil)
+	c.Assert(batchIDs(batches), DeepEquals, [][]string{{"critical"}, {"normal"}, {"low"}, {"level2"}})
+
+	// emergency shutdowns stop the lowest class first, regardless of level
+	batches, err = ssm.MergeBatches([]ssm.ServiceStateChangeBatch{{Services: svcs, DesiredState: service.SVCStop, Emergency: true}})
+	c.Assert(err, IsNil)
+	c.Assert(batchIDs(batches), DeepEquals, [][]string{{"low"}, {"normal"}, {"level2"}, {"critical"}})
+}
+
 func (s *ServiceStateManagerSuite) TestServiceStateManager_AddAndRemoveTenants(c *C) {
 	// Add a tenant without starting the manager, should fail
 	err := s.serviceStateManager.AddTenant("tenant")
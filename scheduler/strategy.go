@@ -16,6 +16,7 @@ package scheduler
 import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/facade"
@@ -26,9 +27,13 @@ import (
 
 // Verify we implement all the interfaces
 var (
-	_ strategy.Host          = &StrategyHost{}
-	_ strategy.ServiceConfig = &StrategyRunningService{}
-	_ strategy.ServiceConfig = &StrategyService{}
+	_ strategy.Host            = &StrategyHost{}
+	_ strategy.ServiceConfig   = &StrategyRunningService{}
+	_ strategy.ServiceConfig   = &StrategyService{}
+	_ strategy.CoreRequester   = &StrategyRunningService{}
+	_ strategy.CoreRequester   = &StrategyService{}
+	_ strategy.BenchmarkedHost = &StrategyHost{}
+	_ strategy.LabeledHost     = &StrategyHost{}
 )
 
 type StrategyHost struct {
@@ -48,6 +53,70 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 
 	glog.V(2).Infof("Applying %s strategy for service %s", strat.Name(), sn.ID)
 
+	shosts, err := strategyHosts(hosts, facade)
+	if err != nil {
+		return "", err
+	}
+	if result, err := strat.SelectHost(&StrategyService{sn}, shosts); result == nil || err != nil {
+		return "", err
+	} else {
+		h := result.(*StrategyHost).host
+		glog.V(2).Infof("Deploying service %s to host %s", sn.ID, h.ID)
+		return h.ID, nil
+	}
+}
+
+// PoolStrategyHosts applies the scheduling strategy of a pool.  It returns the
+// hosts that are eligible to run the service and the host policy to schedule
+// it with.  Services that set their own host policy keep it.
+func PoolStrategyHosts(poolStrategy string, sn *zkservice.ServiceNode, hosts []host.Host) ([]host.Host, servicedefinition.HostPolicy) {
+	hp := sn.HostPolicy
+	switch poolStrategy {
+	case pool.StrategyBinpack:
+		if hp == servicedefinition.DEFAULT {
+			hp = servicedefinition.Pack
+		}
+	case pool.StrategyLabelAffinity:
+		matches := []host.Host{}
+		for _, h := range hosts {
+			if h.MatchesLabels(sn.HostAffinity, sn.HostAntiAffinity) {
+				matches = append(matches, h)
+			}
+		}
+		hosts = matches
+	}
+	return hosts, hp
+}
+
+// NodeSelectorHosts returns the hosts that have all of the labels in the
+// node selector of the service.
+func NodeSelectorHosts(sn *zkservice.ServiceNode, hosts []host.Host) []host.Host {
+	if len(sn.NodeSelector) == 0 {
+		return hosts
+	}
+	matches := []host.Host{}
+	for _, h := range hosts {
+		if h.MatchesLabels(sn.NodeSelector, nil) {
+			matches = append(matches, h)
+		}
+	}
+	return matches
+}
+
+// StrategyKeepHost returns true if an instance of the service can be
+// rescheduled on the given host without oversubscribing it or sharing it with
+// another instance of the same service.
+func StrategyKeepHost(sn *zkservice.ServiceNode, h host.Host, facade *facade.Facade) (bool, error) {
+	shosts, err := strategyHosts([]host.Host{h}, facade)
+	if err != nil {
+		return false, err
+	}
+	under, _ := strategy.ScoreHosts(&StrategyService{sn}, shosts)
+	return len(under) == 1 && under[0].NumInstances == 0, nil
+}
+
+// strategyHosts loads the running instances of each host for scoring
+func strategyHosts(hosts []host.Host, facade *facade.Facade) ([]strategy.Host, error) {
 	hostmap := map[string]*StrategyHost{}
 	hostids := []string{}
 
@@ -62,7 +131,7 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 	glog.V(2).Infof("Looking up instances for hosts: %+v", hostids)
 	svcs, err := facade.GetHostStrategyInstances(datastore.Get(), hosts)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	// Assign the services to the StrategyHosts
 	for _, s := range svcs {
@@ -75,13 +144,7 @@ func StrategySelectHost(sn *zkservice.ServiceNode, hosts []host.Host, strat stra
 		glog.V(2).Infof("Host %s is running %d service instances", h.HostID(), len(h.services))
 		shosts = append(shosts, h)
 	}
-	if result, err := strat.SelectHost(&StrategyService{sn}, shosts); result == nil || err != nil {
-		return "", err
-	} else {
-		h := result.(*StrategyHost).host
-		glog.V(2).Infof("Deploying service %s to host %s", sn.ID, h.ID)
-		return h.ID, nil
-	}
+	return shosts, nil
 }
 
 // Implement everything
@@ -102,6 +165,27 @@ func (h *StrategyHost) TotalMemory() uint64 {
 	return h.host.TotalRAM()
 }
 
+func (h *StrategyHost) Labels() map[string]string {
+	return h.host.Labels
+}
+
+func (h *StrategyHost) BenchmarkResults() []float64 {
+	b := h.host.Benchmark
+	if b == nil {
+		return nil
+	}
+	var syncsPerSecond float64
+	if b.DiskLatency > 0 {
+		syncsPerSecond = 1 / b.DiskLatency.Seconds()
+	}
+	return []float64{
+		float64(b.DiskThroughput),
+		syncsPerSecond,
+		float64(b.NetworkBandwidth),
+		float64(b.CPUScore),
+	}
+}
+
 func (s *StrategyService) GetServiceID() string {
 	return s.svc.ID
 }
@@ -110,6 +194,10 @@ func (s *StrategyService) RequestedCorePercent() int {
 	return s.svc.CPUCommitment
 }
 
+func (s *StrategyService) RequestedCores() float64 {
+	return s.svc.CPURequest
+}
+
 func (s *StrategyService) RequestedMemoryBytes() uint64 {
 	return s.svc.RAMCommitment.Value
 }
@@ -126,6 +214,10 @@ func (s *StrategyRunningService) RequestedCorePercent() int {
 	return s.svc.CPUCommitment
 }
 
+func (s *StrategyRunningService) RequestedCores() float64 {
+	return s.svc.CPURequest
+}
+
 func (s *StrategyRunningService) RequestedMemoryBytes() uint64 {
 	return s.svc.RAMCommitment
 }
@@ -29,7 +29,7 @@ func (s *BalanceStrategy) SelectHost(service ServiceConfig, hosts []Host) (Host,
 	if under != nil && len(under) > 0 {
 		choice := under[0]
 		for _, scored := range under {
-			if scored.Score != choice.Score {
+			if scored.balanceScore() != choice.balanceScore() {
 				break
 			}
 			if len(scored.Host.RunningServices()) < len(choice.Host.RunningServices()) {
@@ -27,14 +27,11 @@ func (s *PackStrategy) SelectHost(service ServiceConfig, hosts []Host) (Host, er
 	// Return the host with the least amount of free resources that can handle
 	// the service. In case of a tie, choose the one running more instances.
 	if under != nil && len(under) > 0 {
-		last := len(under) - 1
-		choice := under[last]
-		for i := last; i >= 0; i-- {
-			scored := under[i]
-			if scored.Score != choice.Score {
-				break
-			}
-			if len(scored.Host.RunningServices()) > len(choice.Host.RunningServices()) {
+		choice := under[len(under)-1]
+		for _, scored := range under {
+			if scored.packScore() > choice.packScore() {
+				choice = scored
+			} else if scored.packScore() == choice.packScore() && len(scored.Host.RunningServices()) > len(choice.Host.RunningServices()) {
 				choice = scored
 			}
 		}
@@ -0,0 +1,249 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/zenoss/glog"
+)
+
+// MaxPluginScore is the highest penalty that a score plugin may give a host.
+// A host with the highest penalty is penalized by the full weight of the
+// plugin.
+const MaxPluginScore = 100
+
+// DefaultPluginTimeout is how long an exec score plugin may run
+const DefaultPluginTimeout = 5 * time.Second
+
+// ScorePlugin contributes site-specific scoring, such as rack awareness or
+// power domains, to the hosts scored for a service.  Score returns a penalty
+// from 0 to MaxPluginScore for each host, where a higher penalty makes the
+// host less preferred.  Hosts missing from the result are not penalized.
+type ScorePlugin interface {
+	Name() string
+	Score(service ServiceConfig, hosts []Host) (map[string]int, error)
+}
+
+// LabeledHost is implemented by hosts with user assigned labels, which are
+// passed on to score plugins.
+type LabeledHost interface {
+	Labels() map[string]string
+}
+
+type weightedPlugin struct {
+	plugin ScorePlugin
+	weight int
+}
+
+var (
+	scoringLock     sync.RWMutex
+	scorePlugins    []weightedPlugin
+	benchmarkWeight = BenchmarkWeight
+)
+
+// RegisterScorePlugin adds a plugin whose penalties are scaled to the given
+// weight and added to the built-in scores of each host.
+func RegisterScorePlugin(plugin ScorePlugin, weight int) {
+	scoringLock.Lock()
+	defer scoringLock.Unlock()
+	scorePlugins = append(scorePlugins, weightedPlugin{plugin, weight})
+}
+
+// ScoringConfig sets the weight of the built-in benchmark score and the exec
+// score plugins of the scheduler.
+type ScoringConfig struct {
+	BenchmarkWeight *int `json:",omitempty"`
+	Plugins         []ScorePluginConfig
+}
+
+// ScorePluginConfig describes an exec score plugin.  Command is run once for
+// each service that is scheduled; it reads a ScoreRequest as json from stdin
+// and writes a ScoreResponse as json to stdout.
+type ScorePluginConfig struct {
+	Name           string
+	Command        []string
+	Weight         int
+	TimeoutSeconds int
+}
+
+// LoadScoringConfig reads the scoring configuration from a json file
+func LoadScoringConfig(filename string) (ScoringConfig, error) {
+	var cfg ScoringConfig
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("could not parse scheduler plugins in %s: %s", filename, err)
+	}
+	if cfg.BenchmarkWeight != nil && *cfg.BenchmarkWeight < 0 {
+		return cfg, fmt.Errorf("benchmark weight in %s is negative", filename)
+	}
+	for i, p := range cfg.Plugins {
+		if len(p.Command) == 0 {
+			return cfg, fmt.Errorf("scheduler plugin %d in %s has no command", i, filename)
+		}
+		if p.Weight < 0 {
+			return cfg, fmt.Errorf("scheduler plugin %d in %s has a negative weight", i, filename)
+		}
+		if p.Name == "" {
+			cfg.Plugins[i].Name = p.Command[0]
+		}
+	}
+	return cfg, nil
+}
+
+// SetScoring replaces the benchmark weight and the score plugins with those
+// of the configuration.
+func SetScoring(cfg ScoringConfig) {
+	plugins := make([]weightedPlugin, len(cfg.Plugins))
+	for i, p := range cfg.Plugins {
+		timeout := time.Duration(p.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = DefaultPluginTimeout
+		}
+		plugins[i] = weightedPlugin{&ExecScorePlugin{name: p.Name, command: p.Command, timeout: timeout}, p.Weight}
+	}
+
+	scoringLock.Lock()
+	defer scoringLock.Unlock()
+	scorePlugins = plugins
+	benchmarkWeight = BenchmarkWeight
+	if cfg.BenchmarkWeight != nil {
+		benchmarkWeight = *cfg.BenchmarkWeight
+	}
+}
+
+// getBenchmarkWeight returns the configured weight of the benchmark score
+func getBenchmarkWeight() int {
+	scoringLock.RLock()
+	defer scoringLock.RUnlock()
+	return benchmarkWeight
+}
+
+// pluginPenalties returns the weighted penalties of the score plugins for
+// each host.  A plugin that fails is logged and does not affect the scores.
+func pluginPenalties(service ServiceConfig, hosts []Host) map[string]int {
+	scoringLock.RLock()
+	plugins := scorePlugins
+	scoringLock.RUnlock()
+
+	penalties := make(map[string]int)
+	for _, wp := range plugins {
+		scores, err := wp.plugin.Score(service, hosts)
+		if err != nil {
+			glog.Warningf("Score plugin %s failed for service %s: %s", wp.plugin.Name(), service.GetServiceID(), err)
+			continue
+		}
+		for hostID, score := range scores {
+			if score < 0 {
+				score = 0
+			} else if score > MaxPluginScore {
+				score = MaxPluginScore
+			}
+			penalties[hostID] += wp.weight * score / MaxPluginScore
+		}
+	}
+	return penalties
+}
+
+// ScoreRequest is the json that an exec score plugin reads from stdin
+type ScoreRequest struct {
+	ServiceID            string
+	RequestedCorePercent int
+	RequestedMemoryBytes uint64
+	Hosts                []ScoreRequestHost
+}
+
+// ScoreRequestHost is a host to be scored by an exec score plugin
+type ScoreRequestHost struct {
+	HostID          string
+	TotalCores      int
+	TotalMemory     uint64
+	Labels          map[string]string `json:",omitempty"`
+	RunningServices []string          // service ids of the running instances
+}
+
+// ScoreResponse is the json that an exec score plugin writes to stdout
+type ScoreResponse struct {
+	Penalties map[string]int
+}
+
+// ExecScorePlugin is a score plugin that runs a command
+type ExecScorePlugin struct {
+	name    string
+	command []string
+	timeout time.Duration
+}
+
+// Name implements ScorePlugin
+func (p *ExecScorePlugin) Name() string {
+	return p.name
+}
+
+// Score implements ScorePlugin
+func (p *ExecScorePlugin) Score(service ServiceConfig, hosts []Host) (map[string]int, error) {
+	req := ScoreRequest{
+		ServiceID:            service.GetServiceID(),
+		RequestedCorePercent: service.RequestedCorePercent(),
+		RequestedMemoryBytes: service.RequestedMemoryBytes(),
+		Hosts:                make([]ScoreRequestHost, len(hosts)),
+	}
+	for i, host := range hosts {
+		rh := ScoreRequestHost{
+			HostID:          host.HostID(),
+			TotalCores:      host.TotalCores(),
+			TotalMemory:     host.TotalMemory(),
+			RunningServices: []string{},
+		}
+		if lh, ok := host.(LabeledHost); ok {
+			rh.Labels = lh.Labels()
+		}
+		for _, svc := range host.RunningServices() {
+			rh.RunningServices = append(rh.RunningServices, svc.GetServiceID())
+		}
+		req.Hosts[i] = rh
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out after %s", p.timeout)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var resp ScoreResponse
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("could not parse output: %s", err)
+	}
+	return resp.Penalties, nil
+}
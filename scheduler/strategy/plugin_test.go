@@ -0,0 +1,125 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package strategy_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/control-center/serviced/scheduler/strategy"
+	"github.com/control-center/serviced/scheduler/strategy/mocks"
+	. "gopkg.in/check.v1"
+)
+
+type testScorePlugin struct {
+	penalties map[string]int
+	err       error
+}
+
+func (p *testScorePlugin) Name() string { return "test" }
+
+func (p *testScorePlugin) Score(service strategy.ServiceConfig, hosts []strategy.Host) (map[string]int, error) {
+	return p.penalties, p.err
+}
+
+func newNamedHost(id string, cores int, memgigs uint64) *mocks.Host {
+	host := &mocks.Host{}
+	host.On("TotalCores").Return(cores)
+	host.On("TotalMemory").Return(memgigs * Gigabyte)
+	host.On("HostID").Return(id)
+	host.On("RunningServices").Return([]strategy.ServiceConfig{})
+	return host
+}
+
+// Given two identical hosts, one of which is penalized by a score plugin,
+// verify that the other host is preferred and that a failing plugin is
+// ignored
+func (s *StrategySuite) TestPluginScoring(c *C) {
+	defer strategy.SetScoring(strategy.ScoringConfig{})
+	hostA := newNamedHost("hostA", 2, 2)
+	hostB := newNamedHost("hostB", 2, 2)
+	svc := newService(1, 1)
+
+	strategy.RegisterScorePlugin(&testScorePlugin{penalties: map[string]int{"hostA": 50, "hostB": 500}}, 40)
+	strategy.RegisterScorePlugin(&testScorePlugin{err: errors.New("unavailable")}, 100)
+
+	under, _ := strategy.ScoreHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(under, HasLen, 2)
+	c.Assert(under[0].Host, Equals, hostA)
+	c.Assert(under[0].Penalty, Equals, 20)
+	c.Assert(under[1].Host, Equals, hostB)
+	c.Assert(under[1].Penalty, Equals, 40)
+
+	pack := &strategy.PackStrategy{}
+	result, err := pack.SelectHost(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, hostA)
+}
+
+func (s *StrategySuite) TestExecScorePlugin(c *C) {
+	defer strategy.SetScoring(strategy.ScoringConfig{})
+	dir := c.MkDir()
+	script := filepath.Join(dir, "score")
+	request := filepath.Join(dir, "request.json")
+	err := ioutil.WriteFile(script, []byte("#!/bin/sh\ncat > "+request+"\necho '{\"Penalties\": {\"hostA\": 100}}'\n"), 0755)
+	c.Assert(err, IsNil)
+
+	config := filepath.Join(dir, "plugins.json")
+	err = ioutil.WriteFile(config, []byte(`{"BenchmarkWeight": 0, "Plugins": [{"Command": ["`+script+`"], "Weight": 30}]}`), 0644)
+	c.Assert(err, IsNil)
+	cfg, err := strategy.LoadScoringConfig(config)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Plugins[0].Name, Equals, script)
+	strategy.SetScoring(cfg)
+
+	hostA := newNamedHost("hostA", 2, 2)
+	hostB := newNamedHost("hostB", 2, 2)
+	svc := newService(1, 1)
+
+	under, _ := strategy.ScoreHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(under, HasLen, 2)
+	c.Assert(under[0].Host, Equals, hostB)
+	c.Assert(under[1].Host, Equals, hostA)
+	c.Assert(under[1].Penalty, Equals, 30)
+
+	data, err := ioutil.ReadFile(request)
+	c.Assert(err, IsNil)
+	var req strategy.ScoreRequest
+	c.Assert(json.Unmarshal(data, &req), IsNil)
+	c.Assert(req.ServiceID, Equals, svc.GetServiceID())
+	c.Assert(req.Hosts, HasLen, 2)
+}
+
+func (s *StrategySuite) TestLoadScoringConfig_Invalid(c *C) {
+	dir := c.MkDir()
+	for _, data := range []string{
+		`{"Plugins": [{"Name": "rack"}]}`,
+		`{"Plugins": [{"Command": ["/bin/true"], "Weight": -1}]}`,
+		`{"BenchmarkWeight": -5}`,
+		`not json`,
+	} {
+		config := filepath.Join(dir, "plugins.json")
+		c.Assert(ioutil.WriteFile(config, []byte(data), 0644), IsNil)
+		_, err := strategy.LoadScoringConfig(config)
+		c.Assert(err, NotNil, Commentf("%s", data))
+	}
+	_, err := strategy.LoadScoringConfig(filepath.Join(dir, "missing.json"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
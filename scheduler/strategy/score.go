@@ -19,10 +19,28 @@ import (
 	"github.com/zenoss/glog"
 )
 
+// BenchmarkWeight is the default penalty of a host that performed worst on
+// every commissioning benchmark compared to the other hosts being scored.
+// It may be changed with SetScoring.
+const BenchmarkWeight = 20
+
 type ScoredHost struct {
 	Host         Host
 	Score        int
 	NumInstances int
+	Penalty      int // Raised for hosts that performed worse on their benchmark or were penalized by a score plugin
+}
+
+// balanceScore is lower for hosts with more free resources that performed
+// better on their benchmark
+func (h *ScoredHost) balanceScore() int {
+	return h.Score + h.Penalty
+}
+
+// packScore is higher for hosts with fewer free resources that performed
+// better on their benchmark
+func (h *ScoredHost) packScore() int {
+	return h.Score - h.Penalty
 }
 
 type scoredHostList []*ScoredHost
@@ -36,14 +54,89 @@ func (l scoredHostList) Swap(i, j int) {
 }
 
 func (l scoredHostList) Less(i, j int) bool {
-	return l[i].Score < l[j].Score
+	return l[i].balanceScore() < l[j].balanceScore()
+}
+
+// BenchmarkedHost is implemented by hosts that can be benchmarked when they
+// are added.  BenchmarkResults returns the results of the benchmark, where a
+// higher value is better and zero was not measured, or nil if the host was
+// not benchmarked.
+type BenchmarkedHost interface {
+	BenchmarkResults() []float64
+}
+
+// benchmarkPenalties returns the penalty of each benchmarked host, which is
+// the benchmark weight scaled by how far its results fall short of the best
+// result of the hosts on average.  Hosts that were not benchmarked have no
+// penalty.
+func benchmarkPenalties(hosts []Host) map[string]int {
+	results := make(map[string][]float64)
+	best := []float64{}
+	for _, host := range hosts {
+		bh, ok := host.(BenchmarkedHost)
+		if !ok {
+			continue
+		}
+		r := bh.BenchmarkResults()
+		if r == nil {
+			continue
+		}
+		results[host.HostID()] = r
+		for i, v := range r {
+			if i >= len(best) {
+				best = append(best, v)
+			} else if v > best[i] {
+				best[i] = v
+			}
+		}
+	}
+
+	weight := getBenchmarkWeight()
+	penalties := make(map[string]int)
+	for hostID, r := range results {
+		var total float64
+		var count int
+		for i, v := range r {
+			if v > 0 {
+				total += 1 - v/best[i]
+				count++
+			}
+		}
+		if count > 0 {
+			penalties[hostID] = int(float64(weight) * total / float64(count))
+		}
+	}
+	return penalties
+}
+
+// requestedCores returns the cores reserved by a service, if any
+func requestedCores(service ServiceConfig) float64 {
+	if r, ok := service.(CoreRequester); ok {
+		return r.RequestedCores()
+	}
+	return 0
+}
+
+// HasCoreCapacity returns false if the host cannot fit the cores requested by
+// the service alongside the cores reserved by its running services.
+func HasCoreCapacity(service ServiceConfig, host Host) bool {
+	requested := requestedCores(service)
+	if requested <= 0 {
+		return true
+	}
+	reserved := requested
+	for _, svc := range host.RunningServices() {
+		reserved += requestedCores(svc)
+	}
+	return reserved <= float64(host.TotalCores())
 }
 
 // ScoreHosts returns two arrays of hosts. The first lists hosts that have
 // enough resources to handle the service, sorted in order of combined free
-// resources. The second lists hosts that do not have enough resources to
+// resources, benchmark penalty and score plugin penalties. The second lists hosts that do not have enough resources to
 // handle the service, sorted in order of percentage memory used were the
-// service deployed to the host.
+// service deployed to the host.  Hosts without enough free cores for the
+// cores requested by the service are in neither list.
 func ScoreHosts(service ServiceConfig, hosts []Host) ([]*ScoredHost, []*ScoredHost) {
 
 	glog.V(2).Infof("Scoring %d hosts for service %s", len(hosts), service.GetServiceID())
@@ -51,9 +144,18 @@ func ScoreHosts(service ServiceConfig, hosts []Host) ([]*ScoredHost, []*ScoredHo
 
 	undersubscribed := scoredHostList{}
 	oversubscribed := scoredHostList{}
+	penalties := benchmarkPenalties(hosts)
+	for hostID, penalty := range pluginPenalties(service, hosts) {
+		penalties[hostID] += penalty
+	}
 
 	for _, host := range hosts {
 
+		if !HasCoreCapacity(service, host) {
+			glog.V(2).Infof("Host %s does not have enough free cores for service %s", host.HostID(), service.GetServiceID())
+			continue
+		}
+
 		scoredHost := &ScoredHost{Host: host}
 
 		totalMem := host.TotalMemory()
@@ -101,6 +203,7 @@ func ScoreHosts(service ServiceConfig, hosts []Host) ([]*ScoredHost, []*ScoredHo
 		if cpuScore <= 100 && memScore <= 100 {
 			glog.V(2).Infof("Host %s can run service %s", host.HostID(), service.GetServiceID())
 			scoredHost.Score = cpuScore + memScore
+			scoredHost.Penalty = penalties[host.HostID()]
 			undersubscribed = append(undersubscribed, scoredHost)
 		} else {
 			glog.V(2).Infof("Host %s would be oversubscribed with service %s", host.HostID(), service.GetServiceID())
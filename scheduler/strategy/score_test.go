@@ -143,3 +143,98 @@ func (s *StrategySuite) TestMemoryPreferred(c *C) {
 	c.Assert(over[0].Host, Equals, hostA)
 	c.Assert(over[1].Host, Equals, hostB)
 }
+
+// coreService is a service that reserves cpu cores
+type coreService struct {
+	*mocks.ServiceConfig
+	cores float64
+}
+
+func (s *coreService) RequestedCores() float64 {
+	return s.cores
+}
+
+func newCoreService(cores float64, memgigs uint64) *coreService {
+	return &coreService{newService(0, memgigs), cores}
+}
+
+// Given two hosts, one of which does not have enough free cores for the
+// requested cores of the service, make sure that host is never selected
+func (s *StrategySuite) TestCoreRequest(c *C) {
+	hostA := newHost(2, 5)
+	hostB := newHost(4, 5)
+
+	svc := newCoreService(1.5, 1)
+	svc2 := newCoreService(1.5, 1)
+
+	hostA.On("RunningServices").Return([]strategy.ServiceConfig{svc})
+	hostB.On("RunningServices").Return([]strategy.ServiceConfig{svc})
+
+	c.Assert(strategy.HasCoreCapacity(svc2, hostA), Equals, false)
+	c.Assert(strategy.HasCoreCapacity(svc2, hostB), Equals, true)
+
+	under, over := strategy.ScoreHosts(svc2, []strategy.Host{hostA, hostB})
+	c.Assert(under, HasLen, 1)
+	c.Assert(under[0].Host, Equals, hostB)
+	c.Assert(over, HasLen, 0)
+
+	// services without a core request can still be scheduled anywhere
+	svc3 := newService(1, 1)
+	under, over = strategy.ScoreHosts(svc3, []strategy.Host{hostA, hostB})
+	c.Assert(len(under)+len(over), Equals, 2)
+}
+
+// benchmarkedHost is a host with commissioning benchmark results
+type benchmarkedHost struct {
+	*mocks.Host
+	results []float64
+}
+
+func (h *benchmarkedHost) BenchmarkResults() []float64 {
+	return h.results
+}
+
+func newBenchmarkedHost(cores int, memgigs uint64, results ...float64) *benchmarkedHost {
+	return &benchmarkedHost{newHost(cores, memgigs), results}
+}
+
+// Given two identical hosts, one of which performed worse on its benchmark,
+// verify that the faster host is preferred by both balance and pack
+func (s *StrategySuite) TestBenchmarkScoring(c *C) {
+	hostA := newBenchmarkedHost(2, 2, 50, 100)
+	hostB := newBenchmarkedHost(2, 2, 100, 100)
+	hostC := newHost(2, 2)
+
+	svc := newService(1, 1)
+
+	hostA.On("RunningServices").Return([]strategy.ServiceConfig{})
+	hostB.On("RunningServices").Return([]strategy.ServiceConfig{})
+	hostC.On("RunningServices").Return([]strategy.ServiceConfig{})
+
+	under, over := strategy.ScoreHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(over, HasLen, 0)
+	c.Assert(under, HasLen, 2)
+	c.Assert(under[0].Host, Equals, hostB)
+	c.Assert(under[0].Penalty, Equals, 0)
+	c.Assert(under[1].Host, Equals, hostA)
+	c.Assert(under[1].Penalty, Equals, strategy.BenchmarkWeight/4)
+	c.Assert(under[0].Score, Equals, under[1].Score)
+
+	balance := &strategy.BalanceStrategy{}
+	result, err := balance.SelectHost(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, hostB)
+
+	pack := &strategy.PackStrategy{}
+	result, err = pack.SelectHost(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(result, Equals, hostB)
+
+	// hosts that were not benchmarked are not penalized
+	under, _ = strategy.ScoreHosts(svc, []strategy.Host{hostA, hostC})
+	for _, scored := range under {
+		if scored.Host == hostC {
+			c.Assert(scored.Penalty, Equals, 0)
+		}
+	}
+}
@@ -48,6 +48,13 @@ type ServiceConfig interface {
 	HostPolicy() servicedefinition.HostPolicy
 }
 
+// CoreRequester is implemented by services that reserve CPU cores.  A host is
+// never selected if the reserved cores of its running services plus the
+// requested cores would exceed the cores of the host.
+type CoreRequester interface {
+	RequestedCores() float64
+}
+
 type Strategy interface {
 	// The name of this strategy
 	Name() string
@@ -0,0 +1,98 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func TestPoolStrategyHosts(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "east", Labels: map[string]string{"zone": "east"}},
+		{ID: "west", Labels: map[string]string{"zone": "west"}},
+		{ID: "none"},
+	}
+	sn := &zkservice.ServiceNode{
+		HostAffinity: map[string]string{"zone": "east"},
+	}
+
+	// spread keeps the default host policy and all hosts
+	actual, hp := PoolStrategyHosts(pool.StrategySpread, sn, hosts)
+	if len(actual) != 3 || hp != servicedefinition.DEFAULT {
+		t.Errorf("Expected 3 hosts with the default policy, got %d with %q", len(actual), hp)
+	}
+
+	// binpack packs services without a host policy
+	actual, hp = PoolStrategyHosts(pool.StrategyBinpack, sn, hosts)
+	if len(actual) != 3 || hp != servicedefinition.Pack {
+		t.Errorf("Expected 3 hosts with the pack policy, got %d with %q", len(actual), hp)
+	}
+
+	// binpack does not override an explicit host policy
+	sn.HostPolicy = servicedefinition.RequireSeparate
+	_, hp = PoolStrategyHosts(pool.StrategyBinpack, sn, hosts)
+	if hp != servicedefinition.RequireSeparate {
+		t.Errorf("Expected the require separate policy, got %q", hp)
+	}
+
+	// label-affinity filters hosts by label
+	actual, _ = PoolStrategyHosts(pool.StrategyLabelAffinity, sn, hosts)
+	if len(actual) != 1 || actual[0].ID != "east" {
+		t.Errorf("Expected only host east, got %+v", actual)
+	}
+
+	sn.HostAffinity = nil
+	sn.HostAntiAffinity = map[string]string{"zone": "east"}
+	actual, _ = PoolStrategyHosts(pool.StrategyLabelAffinity, sn, hosts)
+	if len(actual) != 2 || actual[0].ID != "west" || actual[1].ID != "none" {
+		t.Errorf("Expected hosts west and none, got %+v", actual)
+	}
+}
+
+func TestNodeSelectorHosts(t *testing.T) {
+	hosts := []host.Host{
+		{ID: "gpu-ssd", Labels: map[string]string{"gpu": "nvidia", "disk": "ssd"}},
+		{ID: "gpu", Labels: map[string]string{"gpu": "nvidia"}},
+		{ID: "none"},
+	}
+
+	// services without a node selector can run anywhere
+	sn := &zkservice.ServiceNode{}
+	if actual := NodeSelectorHosts(sn, hosts); len(actual) != 3 {
+		t.Errorf("Expected 3 hosts, got %+v", actual)
+	}
+
+	// hosts must match all of the selectors
+	sn.NodeSelector = map[string]string{"gpu": "nvidia"}
+	if actual := NodeSelectorHosts(sn, hosts); len(actual) != 2 || actual[0].ID != "gpu-ssd" || actual[1].ID != "gpu" {
+		t.Errorf("Expected hosts gpu-ssd and gpu, got %+v", actual)
+	}
+
+	sn.NodeSelector = map[string]string{"gpu": "nvidia", "disk": "ssd"}
+	if actual := NodeSelectorHosts(sn, hosts); len(actual) != 1 || actual[0].ID != "gpu-ssd" {
+		t.Errorf("Expected only host gpu-ssd, got %+v", actual)
+	}
+
+	sn.NodeSelector = map[string]string{"disk": "hdd"}
+	if actual := NodeSelectorHosts(sn, hosts); len(actual) != 0 {
+		t.Errorf("Expected no hosts, got %+v", actual)
+	}
+}
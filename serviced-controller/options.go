@@ -42,6 +42,7 @@ type ControllerOptions struct {
 	VirtualAddressSubnet    string // The subnet of virtual addresses, 10.3
 	MetricForwardingEnabled bool   // Enable metric forwarding from the container
 	HostIPs			string // The ip addresses of the host
+	TracingCollector        string // The collector that the spans of the service are forwarded to
 }
 
 func (c ControllerOptions) toContainerControllerOptions() (options container.ControllerOptions, err error) {
@@ -64,6 +65,7 @@ func (c ControllerOptions) toContainerControllerOptions() (options container.Con
 	options.Metric.RemoteEndoint = "http://localhost:8444/api/metrics/store"
 	options.VirtualAddressSubnet = c.VirtualAddressSubnet
 	options.HostIPs = c.HostIPs
+	options.TracingCollector = c.TracingCollector
 	options.Logforwarder.SettleTime, err = time.ParseDuration(c.LogstashSettleTime)
 	if err != nil {
 		return options, err
@@ -21,6 +21,7 @@ import (
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/container"
+	_ "github.com/control-center/serviced/coordinator/client/etcd" // registers the etcd coordinator driver
 	coordzk "github.com/control-center/serviced/coordinator/client/zookeeper"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/rpc/rpcutils"
@@ -68,6 +69,7 @@ func CmdServiceProxy(ctx *cli.Context) {
 	options.VirtualAddressSubnet = cfg.StringVal("VIRTUAL_ADDRESS_SUBNET", options.VirtualAddressSubnet)
 	options.ServicedEndpoint = utils.GetGateway(options.RPCPort)
 	options.HostIPs = os.Getenv("CONTROLPLANE_HOST_IPS")
+	options.TracingCollector = cfg.StringVal("TRACING_COLLECTOR", "")
 
 	if ctx.IsSet("logtostderr") {
 		glog.SetToStderr(ctx.GlobalBool("logtostderr"))
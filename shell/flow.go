@@ -0,0 +1,95 @@
+// Copyright 2014 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"sync"
+)
+
+const (
+	// DefaultWindow is the number of output bytes that may be sent to a
+	// client before the client acknowledges them.
+	DefaultWindow = 64 * 1024
+
+	// maxChunkSize is the largest chunk of output sent in one message
+	maxChunkSize = 4096
+)
+
+// flowWindow limits the number of bytes in flight to a client.  The sender
+// acquires bytes from the window before it sends them and the window is
+// replenished as the client acknowledges what it has consumed.
+type flowWindow struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	size        int
+	outstanding int
+	closed      bool
+}
+
+func newFlowWindow(size int) *flowWindow {
+	w := &flowWindow{size: size}
+	w.cond = sync.NewCond(&w.mu)
+	return w
+}
+
+// acquire waits until n bytes fit in the window.  A chunk larger than the
+// window is let through once nothing else is outstanding.  Returns false if
+// the window was closed.
+func (w *flowWindow) acquire(n int) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for !w.closed && w.outstanding > 0 && w.outstanding+n > w.size {
+		w.cond.Wait()
+	}
+	if w.closed {
+		return false
+	}
+	w.outstanding += n
+	return true
+}
+
+// release returns n acknowledged bytes to the window
+func (w *flowWindow) release(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.outstanding -= n; w.outstanding < 0 {
+		w.outstanding = 0
+	}
+	w.cond.Broadcast()
+}
+
+// close wakes up any waiting sender
+func (w *flowWindow) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	w.cond.Broadcast()
+}
+
+// appendAvailable appends whatever bytes are immediately available on the
+// channel to the chunk, up to maxChunkSize bytes.
+func appendAvailable(chunk []byte, ch <-chan byte) []byte {
+	for len(chunk) < maxChunkSize {
+		select {
+		case b, ok := <-ch:
+			if !ok {
+				return chunk
+			}
+			chunk = append(chunk, b)
+		default:
+			return chunk
+		}
+	}
+	return chunk
+}
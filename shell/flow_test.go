@@ -0,0 +1,78 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package shell
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowWindow_BlocksUntilRelease(t *testing.T) {
+	w := newFlowWindow(10)
+	assert.True(t, w.acquire(8))
+
+	acquired := make(chan bool)
+	go func() { acquired <- w.acquire(4) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired bytes beyond the window")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	w.release(8)
+	select {
+	case ok := <-acquired:
+		assert.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("window was not released")
+	}
+}
+
+func TestFlowWindow_OversizedChunk(t *testing.T) {
+	w := newFlowWindow(10)
+	assert.True(t, w.acquire(20))
+	w.release(20)
+	assert.True(t, w.acquire(10))
+}
+
+func TestFlowWindow_Close(t *testing.T) {
+	w := newFlowWindow(10)
+	assert.True(t, w.acquire(10))
+
+	acquired := make(chan bool)
+	go func() { acquired <- w.acquire(1) }()
+	w.close()
+	select {
+	case ok := <-acquired:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("close did not wake up the sender")
+	}
+	assert.False(t, w.acquire(1))
+}
+
+func TestAppendAvailable(t *testing.T) {
+	ch := make(chan byte, 3)
+	ch <- 'b'
+	ch <- 'c'
+	assert.Equal(t, []byte("abc"), appendAvailable([]byte("a"), ch))
+
+	close(ch)
+	assert.Equal(t, []byte("a"), appendAvailable([]byte("a"), ch))
+}
@@ -44,6 +44,7 @@ type ProcessConfig struct {
 	Mount       []string
 	Command     string
 	LogToStderr bool // log the command output for stderr
+	Window      int  // max unacknowledged output bytes; 0 disables flow control
 	LogStash    struct {
 		Enable        bool          //enable log stash
 		SettleTime    time.Duration //how long to wait for log stash to flush logs before exiting, ex. 1s
@@ -59,6 +60,8 @@ type Result struct {
 type ProcessInstance struct {
 	disconnected bool
 	closed       bool
+	window       *flowWindow // limits unacknowledged output sent to the client
+	ack          bool        // acknowledge output received from the server
 
 	Stdin  chan byte
 	Stdout chan byte
@@ -87,6 +87,9 @@ func (s *ProcessServer) onProcess(ns *socketio.NameSpace, cfg *ProcessConfig) {
 	// Kick it off
 	plog.Info("Received process packet")
 	proc := s.actor.Exec(cfg)
+	if cfg.Window > 0 {
+		proc.window = newFlowWindow(cfg.Window)
+	}
 	ns.Session.Values[PROCESSKEY] = proc
 
 	// Wire up output
@@ -109,6 +112,9 @@ func onExecutorDisconnect(ns *socketio.NameSpace) {
 
 func (p *ProcessInstance) Disconnect() {
 	p.disconnected = true
+	if p.window != nil {
+		p.window.close()
+	}
 	if p.Stdin != nil {
 		close(p.Stdin)
 		p.Stdin = nil
@@ -145,6 +151,12 @@ func (p *ProcessInstance) ReadRequest(ns *socketio.NameSpace) {
 		}
 	})
 
+	ns.On("ack", func(n *socketio.NameSpace, count int) {
+		if p.window != nil {
+			p.window.release(count)
+		}
+	})
+
 	plog.Debug("Hooked up incoming events")
 }
 
@@ -170,6 +182,9 @@ func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 			for _, b := range []byte(stdout) {
 				p.Stdout <- b
 			}
+			if p.ack {
+				n.Emit("ack", len(stdout))
+			}
 		}
 	})
 
@@ -180,6 +195,9 @@ func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 			for _, b := range []byte(stderr) {
 				p.Stderr <- b
 			}
+			if p.ack {
+				n.Emit("ack", len(stderr))
+			}
 		}
 	})
 
@@ -193,6 +211,11 @@ func (p *ProcessInstance) ReadResponse(ns *socketio.NameSpace) {
 func (p *ProcessInstance) WriteResponse(ns *socketio.NameSpace) {
 	plog.Debug("Hooking up output channels")
 
+	if p.window != nil {
+		p.writeResponseWindowed(ns)
+		return
+	}
+
 	for p.Stdout != nil || p.Stderr != nil {
 		select {
 		case m, ok := <-p.Stdout:
@@ -213,6 +236,37 @@ func (p *ProcessInstance) WriteResponse(ns *socketio.NameSpace) {
 	p.Disconnect()
 }
 
+// writeResponseWindowed sends output in chunks, waiting for the client to
+// acknowledge what it has consumed so that a slow client does not cause
+// output to pile up in memory.
+func (p *ProcessInstance) writeResponseWindowed(ns *socketio.NameSpace) {
+	stdout, stderr := p.Stdout, p.Stderr
+	emit := func(event string, chunk []byte) {
+		if p.window.acquire(len(chunk)) {
+			ns.Emit(event, string(chunk))
+		}
+	}
+
+	for stdout != nil || stderr != nil {
+		select {
+		case b, ok := <-stdout:
+			if !ok {
+				stdout = nil
+			} else {
+				emit("stdout", appendAvailable([]byte{b}, stdout))
+			}
+		case b, ok := <-stderr:
+			if !ok {
+				stderr = nil
+			} else {
+				emit("stderr", appendAvailable([]byte{b}, stderr))
+			}
+		}
+	}
+	ns.Emit("result", <-p.Result)
+	p.Disconnect()
+}
+
 func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 	// TODO: make me more extensible
 	urlAddr, err := url.Parse(f.addr)
@@ -222,6 +276,10 @@ func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 
 	host := fmt.Sprintf("http://%s:50000/", strings.Split(urlAddr.Host, ":")[0])
 
+	if cfg.Window == 0 {
+		cfg.Window = DefaultWindow
+	}
+
 	// Dial the remote ProcessServer
 	client, err := socketio.Dial(host)
 
@@ -243,6 +301,7 @@ func (f *Forwarder) Exec(cfg *ProcessConfig) *ProcessInstance {
 		Stdout: make(chan byte, 1024),
 		Stderr: make(chan byte, 1024),
 		Result: make(chan Result),
+		ack:    cfg.Window > 0,
 	}
 
 	client.On("disconnect", func(ns *socketio.NameSpace) {
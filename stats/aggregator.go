@@ -0,0 +1,338 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Windows of the summaries that delegates roll metrics up into
+const (
+	Rollup10s = 10 * time.Second
+	Rollup1m  = time.Minute
+)
+
+const (
+	// aggregatorBatchSize is the number of samples in each post to the TSDB
+	aggregatorBatchSize = 1000
+
+	// passthroughInterval is how often samples are shipped when they are
+	// not rolled up
+	passthroughInterval = time.Second
+
+	// minShipBackoff and maxShipBackoff bound the wait before shipping again
+	// after a post fails
+	minShipBackoff = time.Second
+	maxShipBackoff = 5 * time.Minute
+)
+
+// AggregatorOptions configure the metrics aggregator of a delegate
+type AggregatorOptions struct {
+	Destination  string        // metric consumer url that the summaries are shipped to
+	Window       time.Duration // length of the summaries, 0 ships samples as they are collected
+	SpoolPath    string        // directory of the batches that could not be shipped
+	SpoolMaxSize int64         // bytes of spooled batches to keep, 0 for no limit
+}
+
+// summary is the rollup of a series over a window
+type summary struct {
+	metric string
+	tags   map[string]string
+	start  int64
+	count  int
+	sum    float64
+	min    float64
+	max    float64
+}
+
+// Aggregator rolls up the metrics of the instances on a delegate into
+// summaries over a fixed window and ships them to the TSDB in batches, so
+// that the master receives one sample per series and window instead of every
+// sample of every container.  Batches that cannot be shipped are spooled to
+// disk and shipped once the TSDB is reachable again, with exponential backoff
+// between attempts.
+type Aggregator struct {
+	opts     AggregatorOptions
+	spool    *spool
+	post     func(destination string, samples []Sample) error
+	mu       sync.Mutex
+	windows  map[string]*summary // series and window -> summary
+	pending  []Sample            // samples that are ready to ship
+	backoff  time.Duration
+	retryAt  time.Time
+	listener net.Listener
+	closing  chan struct{}
+	done     chan struct{}
+}
+
+// NewAggregator creates the spool and starts shipping summaries.
+func NewAggregator(opts AggregatorOptions) (*Aggregator, error) {
+	s, err := newSpool(opts.SpoolPath, opts.SpoolMaxSize)
+	if err != nil {
+		plog.WithField("spoolpath", opts.SpoolPath).WithError(err).Debug("Could not create metrics spool")
+		return nil, err
+	}
+	a := newAggregator(opts, s, Post)
+	go a.loop()
+	return a, nil
+}
+
+func newAggregator(opts AggregatorOptions, s *spool, post func(string, []Sample) error) *Aggregator {
+	return &Aggregator{
+		opts:    opts,
+		spool:   s,
+		post:    post,
+		windows: make(map[string]*summary),
+		pending: []Sample{},
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Listen accepts samples in the metric consumer format at the address until
+// the aggregator is closed.
+func (a *Aggregator) Listen(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.listener = listener
+	a.mu.Unlock()
+	go http.Serve(listener, a)
+	return nil
+}
+
+// ServeHTTP implements http.Handler.  The query parameters of the request,
+// such as the tenant and service of the instance that sent it, are added as
+// tags to the samples that do not have them.
+func (a *Aggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.URL.Path != "/api/metrics/store" {
+		http.NotFound(w, r)
+		return
+	}
+	var payload struct {
+		Metrics []Sample `json:"metrics"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tags := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			tags[key] = values[0]
+		}
+	}
+	a.Add(payload.Metrics, tags)
+	w.WriteHeader(http.StatusOK)
+}
+
+// Add rolls the samples up into the summaries of their windows, or queues
+// them to be shipped if they are not rolled up.
+func (a *Aggregator) Add(samples []Sample, tags map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, s := range samples {
+		if len(tags) > 0 {
+			merged := make(map[string]string, len(s.Tags)+len(tags))
+			for k, v := range tags {
+				merged[k] = v
+			}
+			for k, v := range s.Tags {
+				merged[k] = v
+			}
+			s.Tags = merged
+		}
+		if a.opts.Window <= 0 {
+			a.pending = append(a.pending, s)
+			continue
+		}
+
+		value, err := strconv.ParseFloat(s.Value, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			plog.WithFields(logrus.Fields{
+				"metric": s.Metric,
+				"value":  s.Value,
+			}).Debug("Dropped sample with a value that is not a number")
+			continue
+		}
+		window := int64(a.opts.Window / time.Second)
+		start := s.Timestamp - s.Timestamp%window
+		key := seriesKey(s.Metric, s.Tags) + "@" + strconv.FormatInt(start, 10)
+		sum, ok := a.windows[key]
+		if !ok {
+			sum = &summary{metric: s.Metric, tags: s.Tags, start: start, min: value, max: value}
+			a.windows[key] = sum
+		}
+		sum.count++
+		sum.sum += value
+		sum.min = math.Min(sum.min, value)
+		sum.max = math.Max(sum.max, value)
+	}
+}
+
+// seriesKey identifies a series by its metric and tags
+func seriesKey(metric string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{metric}
+	for _, k := range keys {
+		parts = append(parts, k+"="+tags[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// rollup queues the summaries of the windows that ended by now, or of every
+// window if force is set.  The sample of a summary is the mean of its window.
+func (a *Aggregator) rollup(now time.Time, force bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	window := int64(a.opts.Window / time.Second)
+	keys := []string{}
+	for key, sum := range a.windows {
+		if force || sum.start+window <= now.Unix() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		sum := a.windows[key]
+		a.pending = append(a.pending, Sample{
+			Metric:    sum.metric,
+			Value:     strconv.FormatFloat(sum.sum/float64(sum.count), 'f', -1, 64),
+			Timestamp: sum.start,
+			Tags:      sum.tags,
+		})
+		delete(a.windows, key)
+	}
+}
+
+// ship posts the queued samples in batches.  Batches are spooled instead if
+// a post fails or the aggregator is backing off, and the spooled batches are
+// shipped after a post succeeds.
+func (a *Aggregator) ship(now time.Time) {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = []Sample{}
+	a.mu.Unlock()
+
+	for len(pending) > 0 {
+		n := len(pending)
+		if n > aggregatorBatchSize {
+			n = aggregatorBatchSize
+		}
+		batch := pending[:n]
+		pending = pending[n:]
+		if !a.send(now, batch) {
+			if err := a.spool.write(batch); err != nil {
+				plog.WithField("samples", len(batch)).WithError(err).Error("Could not spool metrics; dropped them")
+			}
+		}
+	}
+
+	// ship the spooled batches once the destination is reachable
+	if now.Before(a.retryAt) {
+		return
+	}
+	names, err := a.spool.batches()
+	if err != nil {
+		plog.WithError(err).Warn("Could not read the metrics spool")
+		return
+	}
+	for _, name := range names {
+		batch, err := a.spool.read(name)
+		if err != nil {
+			plog.WithField("batch", name).WithError(err).Warn("Could not read spooled metrics; dropped them")
+			a.spool.remove(name)
+			continue
+		}
+		if !a.send(now, batch) {
+			return
+		}
+		a.spool.remove(name)
+	}
+}
+
+// send posts a batch unless the aggregator is backing off, and returns true
+// if the batch was shipped.
+func (a *Aggregator) send(now time.Time, batch []Sample) bool {
+	if now.Before(a.retryAt) {
+		return false
+	}
+	if err := a.post(a.opts.Destination, batch); err != nil {
+		if a.backoff < minShipBackoff {
+			a.backoff = minShipBackoff
+		} else if a.backoff *= 2; a.backoff > maxShipBackoff {
+			a.backoff = maxShipBackoff
+		}
+		a.retryAt = now.Add(a.backoff)
+		plog.WithFields(logrus.Fields{
+			"destination": a.opts.Destination,
+			"samples":     len(batch),
+			"retry":       a.backoff,
+		}).WithError(err).Warn("Could not ship metrics; spooling them")
+		return false
+	}
+	a.backoff = 0
+	return true
+}
+
+func (a *Aggregator) loop() {
+	defer close(a.done)
+	interval := a.opts.Window
+	if interval <= 0 {
+		interval = passthroughInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			a.rollup(now, false)
+			a.ship(now)
+		case <-a.closing:
+			now := time.Now()
+			a.rollup(now, true)
+			a.ship(now)
+			return
+		}
+	}
+}
+
+// Close stops accepting samples and ships or spools the summaries of the
+// windows that have not ended yet.
+func (a *Aggregator) Close() {
+	a.mu.Lock()
+	if a.listener != nil {
+		a.listener.Close()
+		a.listener = nil
+	}
+	a.mu.Unlock()
+	close(a.closing)
+	<-a.done
+}
@@ -0,0 +1,185 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package stats
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPoster records the batches that it is asked to post, and fails while
+// down is set
+type testPoster struct {
+	down    bool
+	batches [][]Sample
+}
+
+func (p *testPoster) post(destination string, samples []Sample) error {
+	if p.down {
+		return errors.New("connection refused")
+	}
+	p.batches = append(p.batches, samples)
+	return nil
+}
+
+func newTestAggregator(t *testing.T, window time.Duration) (*Aggregator, *testPoster, func()) {
+	dir, err := ioutil.TempDir("", "metrics-spool-")
+	if err != nil {
+		t.Fatalf("Could not create spool directory: %s", err)
+	}
+	s, err := newSpool(dir, 0)
+	if err != nil {
+		t.Fatalf("Could not create spool: %s", err)
+	}
+	p := &testPoster{}
+	a := newAggregator(AggregatorOptions{Destination: "http://master:8443/api/metrics/store", Window: window}, s, p.post)
+	return a, p, func() { os.RemoveAll(dir) }
+}
+
+func TestAggregator_Rollup(t *testing.T) {
+	a, p, cleanup := newTestAggregator(t, Rollup10s)
+	defer cleanup()
+
+	tags := map[string]string{"controlplane_service_id": "svc"}
+	a.Add([]Sample{
+		{Metric: "cpu", Value: "1", Timestamp: 100, Tags: map[string]string{"core": "0"}},
+		{Metric: "cpu", Value: "3", Timestamp: 105, Tags: map[string]string{"core": "0"}},
+		{Metric: "cpu", Value: "8", Timestamp: 110, Tags: map[string]string{"core": "0"}},
+		{Metric: "cpu", Value: "bogus", Timestamp: 101, Tags: map[string]string{"core": "0"}},
+	}, tags)
+
+	// only the window that ended is shipped
+	a.rollup(time.Unix(115, 0), false)
+	a.ship(time.Unix(115, 0))
+	expected := []Sample{
+		{Metric: "cpu", Value: "2", Timestamp: 100, Tags: map[string]string{"core": "0", "controlplane_service_id": "svc"}},
+	}
+	if len(p.batches) != 1 || !reflect.DeepEqual(p.batches[0], expected) {
+		t.Fatalf("expected %v, got %v", expected, p.batches)
+	}
+
+	a.rollup(time.Unix(120, 0), false)
+	a.ship(time.Unix(120, 0))
+	if len(p.batches) != 2 || p.batches[1][0].Value != "8" || p.batches[1][0].Timestamp != 110 {
+		t.Fatalf("expected the second window, got %v", p.batches)
+	}
+}
+
+func TestAggregator_Spool(t *testing.T) {
+	a, p, cleanup := newTestAggregator(t, 0)
+	defer cleanup()
+
+	// batches are spooled while the destination is down
+	p.down = true
+	a.Add([]Sample{{Metric: "a", Value: "1", Timestamp: 100}}, nil)
+	a.ship(time.Unix(100, 0))
+	a.Add([]Sample{{Metric: "b", Value: "2", Timestamp: 100}}, nil)
+	a.ship(time.Unix(100, 0))
+	if names, _ := a.spool.batches(); len(names) != 2 {
+		t.Fatalf("expected 2 spooled batches, got %v", names)
+	}
+	if a.backoff != minShipBackoff {
+		t.Errorf("expected a backoff of %s, got %s", minShipBackoff, a.backoff)
+	}
+
+	// nothing is posted until the backoff elapses
+	p.down = false
+	a.ship(time.Unix(100, 0))
+	if len(p.batches) != 0 {
+		t.Fatalf("expected no posts while backing off, got %v", p.batches)
+	}
+
+	// then the spooled batches are shipped from oldest to newest
+	a.Add([]Sample{{Metric: "c", Value: "3", Timestamp: 102}}, nil)
+	a.ship(time.Unix(102, 0))
+	if len(p.batches) != 3 || p.batches[0][0].Metric != "c" || p.batches[1][0].Metric != "a" || p.batches[2][0].Metric != "b" {
+		t.Fatalf("expected the new batch and the spooled batches, got %v", p.batches)
+	}
+	if names, _ := a.spool.batches(); len(names) != 0 {
+		t.Errorf("expected an empty spool, got %v", names)
+	}
+	if a.backoff != 0 {
+		t.Errorf("expected no backoff, got %s", a.backoff)
+	}
+}
+
+func TestAggregator_Backoff(t *testing.T) {
+	a, p, cleanup := newTestAggregator(t, 0)
+	defer cleanup()
+
+	p.down = true
+	now := time.Unix(1000, 0)
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		a.Add([]Sample{{Metric: "a", Value: "1", Timestamp: now.Unix()}}, nil)
+		a.ship(now)
+		if a.backoff != expected {
+			t.Fatalf("expected a backoff of %s, got %s", expected, a.backoff)
+		}
+		now = a.retryAt
+	}
+}
+
+func TestSpool_Trim(t *testing.T) {
+	a, _, cleanup := newTestAggregator(t, 0)
+	defer cleanup()
+
+	batch := []Sample{{Metric: "a", Value: "1", Timestamp: 100}}
+	for i := 0; i < 3; i++ {
+		if err := a.spool.write(batch); err != nil {
+			t.Fatalf("Could not spool batch: %s", err)
+		}
+	}
+	names, _ := a.spool.batches()
+	info, _ := os.Stat(a.spool.path + "/" + names[0])
+	a.spool.maxSize = 2 * info.Size()
+	if err := a.spool.write(batch); err != nil {
+		t.Fatalf("Could not spool batch: %s", err)
+	}
+	if trimmed, _ := a.spool.batches(); len(trimmed) != 2 || trimmed[0] != names[2] {
+		t.Errorf("expected the 2 newest batches, got %v", trimmed)
+	}
+}
+
+func TestAggregator_ServeHTTP(t *testing.T) {
+	a, _, cleanup := newTestAggregator(t, 0)
+	defer cleanup()
+
+	body := `{"metrics":[{"metric":"net.rx","value":"5","timestamp":100,"tags":{"component":"eth0"}}]}`
+	req := httptest.NewRequest("POST", "/api/metrics/store?controlplane_tenant_id=tenant&controlplane_instance_id=0", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	expected := []Sample{
+		{Metric: "net.rx", Value: "5", Timestamp: 100, Tags: map[string]string{
+			"component":                "eth0",
+			"controlplane_tenant_id":   "tenant",
+			"controlplane_instance_id": "0",
+		}},
+	}
+	if !reflect.DeepEqual(a.pending, expected) {
+		t.Errorf("expected %v, got %v", expected, a.pending)
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/go-procfs/linux"
+	"github.com/control-center/serviced/auth"
 	coordclient "github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/utils"
@@ -171,6 +172,7 @@ func (sr *ServicedStatsReporter) gatherStats(t time.Time) []Sample {
 func (sr *ServicedStatsReporter) updateStats() {
 	// Stats for host.
 	sr.updateHostStats()
+	sr.updateCoordinatorStats()
 	// Stats for the containers.
 	states, err := zkservice.GetHostStates(sr.conn, "", sr.hostID)
 	if err != nil {
@@ -284,6 +286,19 @@ func (sr *ServicedStatsReporter) updateStats() {
 	sr.removeStaleRegistries(states)
 }
 
+// updateCoordinatorStats reports the session and watch activity of the
+// coordinator connections of this host.
+func (sr *ServicedStatsReporter) updateCoordinatorStats() {
+	zkstats := coordclient.GetSessionStats()
+	metrics.GetOrRegisterGauge("zk.sessions", sr.hostRegistry).Update(zkstats.Sessions)
+	metrics.GetOrRegisterGauge("zk.disconnects", sr.hostRegistry).Update(zkstats.Disconnects)
+	metrics.GetOrRegisterGauge("zk.expirations", sr.hostRegistry).Update(zkstats.Expirations)
+	metrics.GetOrRegisterGauge("zk.watches.outstanding", sr.hostRegistry).Update(zkstats.WatchesOutstanding)
+	metrics.GetOrRegisterGauge("zk.watches.lost", sr.hostRegistry).Update(zkstats.WatchesLost)
+	metrics.GetOrRegisterGauge("zk.ephemerals.replayed", sr.hostRegistry).Update(zkstats.EphemeralReplays)
+	metrics.GetOrRegisterGauge("zk.ephemerals.replayerrors", sr.hostRegistry).Update(zkstats.EphemeralReplayErrors)
+}
+
 func (sr *ServicedStatsReporter) updateHostStats() {
 
 	loadavg, err := linux.ReadLoadavg()
@@ -347,4 +362,10 @@ func (sr *ServicedStatsReporter) updateHostStats() {
 	} else {
 		metrics.GetOrRegisterGauge("Serviced.OpenFileDescriptors", sr.hostRegistry).Update(openFileDescriptorCount)
 	}
+
+	tokens := auth.GetTokenCacheStats()
+	metrics.GetOrRegisterGauge("Serviced.AuthTokenCacheHits", sr.hostRegistry).Update(tokens.Hits)
+	metrics.GetOrRegisterGauge("Serviced.AuthTokenCacheMisses", sr.hostRegistry).Update(tokens.Misses)
+	metrics.GetOrRegisterGauge("Serviced.AuthTokenGracePeriodUses", sr.hostRegistry).Update(tokens.Grace)
+	metrics.GetOrRegisterGauge("Serviced.AuthTokenGracePeriodExpirations", sr.hostRegistry).Update(tokens.Expired)
 }
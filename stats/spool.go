@@ -0,0 +1,127 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// spool keeps the batches of samples that could not be shipped on disk until
+// the TSDB is reachable again.  The oldest batches are dropped once the spool
+// exceeds its maximum size.
+type spool struct {
+	path    string
+	maxSize int64 // bytes, 0 for no limit
+	seq     uint64
+}
+
+// newSpool creates the spool directory
+func newSpool(path string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(path, 0750); err != nil {
+		return nil, err
+	}
+	return &spool{path: path, maxSize: maxSize}, nil
+}
+
+// write saves a batch to the spool
+func (s *spool) write(samples []Sample) error {
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return err
+	}
+
+	// names sort in the order that the batches were written
+	name := fmt.Sprintf("%020d-%06d.json", time.Now().UnixNano(), atomic.AddUint64(&s.seq, 1)%1000000)
+	tmp := filepath.Join(s.path, "."+name)
+	if err := ioutil.WriteFile(tmp, data, 0640); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.path, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return s.trim()
+}
+
+// batches returns the names of the spooled batches, from oldest to newest
+func (s *spool) batches() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if name := info.Name(); !info.IsDir() && strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// read returns the samples of a spooled batch
+func (s *spool) read(name string) ([]Sample, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.path, name))
+	if err != nil {
+		return nil, err
+	}
+	samples := []Sample{}
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// remove deletes a spooled batch
+func (s *spool) remove(name string) error {
+	return os.Remove(filepath.Join(s.path, name))
+}
+
+// trim drops the oldest batches until the spool fits its maximum size
+func (s *spool) trim() error {
+	if s.maxSize <= 0 {
+		return nil
+	}
+	names, err := s.batches()
+	if err != nil {
+		return err
+	}
+	sizes := make([]int64, len(names))
+	var total int64
+	for i, name := range names {
+		info, err := os.Stat(filepath.Join(s.path, name))
+		if err != nil {
+			continue
+		}
+		sizes[i] = info.Size()
+		total += sizes[i]
+	}
+	for i := 0; total > s.maxSize && i < len(names)-1; i++ {
+		if err := s.remove(names[i]); err != nil {
+			return err
+		}
+		total -= sizes[i]
+		plog.WithField("batch", names[i]).Warn("Metrics spool is full; dropped the oldest batch")
+	}
+	return nil
+}
@@ -231,8 +231,8 @@ func (c *VolumeResize) Execute(args []string) error {
 		"volume":    c.Args.Name,
 		"type":      driver.DriverType(),
 	})
-	if driver.DriverType() != volume.DriverTypeDeviceMapper {
-		logger.Fatal("Only devicemapper volumes can be resized")
+	if _, ok := driver.(volume.QuotaDriver); !ok {
+		logger.Fatal("Only devicemapper and btrfs volumes can be resized")
 	}
 	if !driver.Exists(c.Args.Name) {
 		logger.Fatal("Volume does not exist")
@@ -42,7 +42,7 @@ func GetIPAddress() (ip string, err error) {
 	switch {
 	case err != nil:
 		return "", err
-	case err == nil && strings.HasPrefix(ip, "127"):
+	case err == nil && net.ParseIP(ip).IsLoopback():
 		return "", fmt.Errorf("unable to identify local ip address")
 	default:
 		return ip, err
@@ -71,6 +71,32 @@ func GetIPv4Addresses() (ips []string, err error) {
 	return ips, nil
 }
 
+// GetIPAddresses returns a list of all IPv4 and global IPv6 interface addresses
+func GetIPAddresses() (ips []string, err error) {
+	ips = []string{}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("unable to use InterfaceAddrs to find local ip addresses: %v", err)
+	}
+
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.IsLoopback() {
+			continue
+		}
+		if ipnet.IP.To4() != nil || ipnet.IP.IsGlobalUnicast() {
+			ips = append(ips, ipnet.IP.String())
+		}
+	}
+
+	if len(ips) == 0 {
+		return ips, fmt.Errorf("unable to identify local ip address")
+	}
+
+	return ips, nil
+}
+
 // GetMemorySize attempts to get the size of the installed RAM.
 func GetMemorySize() (size uint64, err error) {
 	return getMemorySize()
@@ -103,19 +129,39 @@ func getIPAddrFromHostname() (ip string, err error) {
 }
 
 // getIPAddrFromOutGoingConnection get the IP bound to the interface which
-// handles the default route traffic.
+// handles the default route traffic.  Hosts without an IPv4 default route use
+// the interface of the IPv6 default route.
 func getIPAddrFromOutGoingConnection() (ip string, err error) {
-	addr, err := net.ResolveUDPAddr("udp4", "8.8.8.8:53")
+	ip, err = getIPAddrFromOutGoingUDP("udp4", "8.8.8.8:53")
+	if err != nil {
+		if ip6, err6 := getIPAddrFromOutGoingUDP("udp6", "[2001:4860:4860::8888]:53"); err6 == nil {
+			return ip6, nil
+		}
+	}
+	return ip, err
+}
+
+func getIPAddrFromOutGoingUDP(network, address string) (ip string, err error) {
+	addr, err := net.ResolveUDPAddr(network, address)
 	if err != nil {
 		return "", err
 	}
 
-	conn, err := net.DialUDP("udp4", nil, addr)
+	conn, err := net.DialUDP(network, nil, addr)
 	if err != nil {
 		return "", err
 	}
+	defer conn.Close()
 
-	localAddr := conn.LocalAddr()
-	parts := strings.Split(localAddr.String(), ":")
-	return parts[0], nil
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
+
+// CanonicalIP returns the canonical form of an IP address, which may be an
+// IPv6 address in brackets.  Strings that are not IP addresses are returned
+// unchanged.
+func CanonicalIP(ip string) string {
+	if ipaddr := net.ParseIP(strings.Trim(ip, "[]")); ipaddr != nil {
+		return ipaddr.String()
+	}
+	return ip
 }
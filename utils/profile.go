@@ -0,0 +1,80 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+const (
+	// ProfileCPU samples the cpu usage of the process for the duration
+	ProfileCPU = "cpu"
+
+	// ProfileHeap is a snapshot of the live heap allocations
+	ProfileHeap = "heap"
+
+	// ProfileGoroutine is a snapshot of the stacks of all goroutines
+	ProfileGoroutine = "goroutine"
+)
+
+// Profiles are the pprof profiles that can be collected, in the order they
+// are collected.
+var Profiles = []string{ProfileCPU, ProfileHeap, ProfileGoroutine}
+
+// CollectProfiles collects the named pprof profiles from the running process
+// and returns each one keyed by its name.  The cpu profile is sampled for the
+// duration and the snapshots are taken once it completes.
+func CollectProfiles(profiles []string, duration time.Duration) (map[string][]byte, error) {
+	want := make(map[string]bool)
+	for _, name := range profiles {
+		switch name {
+		case ProfileCPU, ProfileHeap, ProfileGoroutine:
+			want[name] = true
+		default:
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+	}
+
+	result := make(map[string][]byte)
+	if want[ProfileCPU] {
+		buf := &bytes.Buffer{}
+		if err := pprof.StartCPUProfile(buf); err != nil {
+			return nil, err
+		}
+		<-time.After(duration)
+		pprof.StopCPUProfile()
+		result[ProfileCPU] = buf.Bytes()
+	}
+	if want[ProfileHeap] {
+		// collect garbage so the profile reflects the live heap
+		runtime.GC()
+		buf := &bytes.Buffer{}
+		if err := pprof.Lookup("heap").WriteTo(buf, 0); err != nil {
+			return nil, err
+		}
+		result[ProfileHeap] = buf.Bytes()
+	}
+	if want[ProfileGoroutine] {
+		buf := &bytes.Buffer{}
+		if err := pprof.Lookup("goroutine").WriteTo(buf, 0); err != nil {
+			return nil, err
+		}
+		result[ProfileGoroutine] = buf.Bytes()
+	}
+	return result, nil
+}
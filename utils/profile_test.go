@@ -0,0 +1,40 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCollectProfiles(t *testing.T) {
+	result, err := CollectProfiles(Profiles, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, name := range Profiles {
+		if len(result[name]) == 0 {
+			t.Errorf("Expected a %s profile", name)
+		}
+	}
+}
+
+func TestCollectProfiles_Unknown(t *testing.T) {
+	if _, err := CollectProfiles([]string{ProfileHeap, "mutex"}, 0); err == nil {
+		t.Fatalf("Expected an error for an unknown profile")
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
-	"fmt"
 	"net"
 	"strconv"
 	"strings"
@@ -29,7 +28,8 @@ var (
 	ErrInvalidTCPAddress = errors.New("Invalid TCP address")
 )
 
-// PackTCPAddress packs a TCP address (IP and port) to 6 bytes
+// PackTCPAddress packs a TCP address (IP and port) to 6 bytes for an IPv4
+// address, or to 18 bytes for an IPv6 address
 func PackTCPAddress(ip string, port uint16) ([]byte, error) {
 	var result bytes.Buffer
 
@@ -38,35 +38,36 @@ func PackTCPAddress(ip string, port uint16) ([]byte, error) {
 	endian.PutUint16(portBuf, port)
 	result.Write(portBuf)
 
-	// Pack the ip address to 4 bytes
-	ipaddr := net.ParseIP(ip)
+	// Pack the ip address to 4 or 16 bytes
+	ipaddr := net.ParseIP(strings.Trim(ip, "[]"))
 	if ipaddr == nil {
 		return nil, ErrInvalidTCPAddress
 	}
-	ipbytes := ipaddr.To4()
-	result.Write(ipbytes)
+	if ipbytes := ipaddr.To4(); ipbytes != nil {
+		result.Write(ipbytes)
+	} else {
+		result.Write(ipaddr.To16())
+	}
 
 	return result.Bytes(), nil
 }
 
-// PackTCPAddressString packs a TCP address represented as a string ("IP:port")
-// to 6 bytes
+// PackTCPAddressString packs a TCP address represented as a string ("IP:port"
+// or "[IPv6]:port") to 6 or 18 bytes
 func PackTCPAddressString(address string) ([]byte, error) {
-	parts := strings.Split(address, ":")
-	if len(parts) != 2 {
+	ip, portstr, err := net.SplitHostPort(address)
+	if err != nil {
 		return nil, ErrInvalidTCPAddress
 	}
-	ip := parts[0]
-	intport, err := strconv.Atoi(parts[1])
-	if err != nil || uint16(intport) == 0 {
+	port, err := strconv.ParseUint(portstr, 10, 16)
+	if err != nil || port == 0 {
 		return nil, ErrInvalidTCPAddress
 	}
-	port := uint16(intport)
-	return PackTCPAddress(ip, port)
+	return PackTCPAddress(ip, uint16(port))
 }
 
-// UnpackTCPAddress unpacks a 6-byte representation of a TCP address produced
-// by PackTCPAddress into an IP and port
+// UnpackTCPAddress unpacks a 6 or 18-byte representation of a TCP address
+// produced by PackTCPAddress into an IP and port
 func UnpackTCPAddress(packed []byte) (ip string, port uint16) {
 
 	// Read off the port
@@ -74,16 +75,20 @@ func UnpackTCPAddress(packed []byte) (ip string, port uint16) {
 	binary.Read(buf, endian, &port)
 
 	// Read off the IP
-	ipbytes := make([]byte, 4)
+	size := net.IPv4len
+	if buf.Len() >= net.IPv6len {
+		size = net.IPv6len
+	}
+	ipbytes := make([]byte, size)
 	buf.Read(ipbytes)
 	ip = net.IP(ipbytes).String()
 
 	return
 }
 
-// UnpackTCPAddressToString unpacks a 6-byte representation of a TCP address
-// produced by PackTCPAddress into a string of the format "IP:port"
+// UnpackTCPAddressToString unpacks a representation of a TCP address produced
+// by PackTCPAddress into a string of the format "IP:port" or "[IPv6]:port"
 func UnpackTCPAddressToString(packed []byte) string {
 	ip, port := UnpackTCPAddress(packed)
-	return fmt.Sprintf("%s:%d", ip, port)
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
 }
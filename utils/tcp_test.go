@@ -56,6 +56,9 @@ func TestPackTCPAddresses(t *testing.T) {
 		"1.2.3.4:65536",
 		"not an address",
 		"666.666.666.666:123",
+		"1.2.3.4:0",
+		"fe80::1:123",
+		"[fe80::1]",
 	} {
 		if _, err := PackTCPAddressString(invalidaddr); err != ErrInvalidTCPAddress {
 			t.Logf("Invalid address didn't produce an error: %s", invalidaddr)
@@ -63,3 +66,30 @@ func TestPackTCPAddresses(t *testing.T) {
 		}
 	}
 }
+
+func TestPackTCPAddressesIPv6(t *testing.T) {
+	packed, err := PackTCPAddressString("[2001:DB8::10]:22250")
+	if err != nil {
+		t.Fatalf("Could not pack IPv6 address: %s", err)
+	}
+	if len(packed) != 18 {
+		t.Errorf("Expected 18 bytes, got %d", len(packed))
+	}
+	ip, port := UnpackTCPAddress(packed)
+	if ip != "2001:db8::10" || port != 22250 {
+		t.Errorf("Unpacked %s %d", ip, port)
+	}
+	if addr := UnpackTCPAddressToString(packed); addr != "[2001:db8::10]:22250" {
+		t.Errorf("Unpacked %s", addr)
+	}
+	bpacked, err := PackTCPAddress("[2001:db8::10]", 22250)
+	if err != nil || string(bpacked) != string(packed) {
+		t.Errorf("Bracketed IP packed to %v (%v)", bpacked, err)
+	}
+
+	// IPv4-mapped addresses pack to 6 bytes
+	packed, err = PackTCPAddressString("[::ffff:10.0.0.1]:80")
+	if err != nil || len(packed) != 6 {
+		t.Errorf("Expected IPv4-mapped address to pack to 6 bytes, got %v (%v)", packed, err)
+	}
+}
@@ -29,8 +29,9 @@ import (
 // file.
 //---------------------------------------------------------------------------
 
-// DefaultTLSMinVersion minimum TLS version supported
-const DefaultTLSMinVersion = "VersionTLS10"
+// DefaultTLSMinVersion minimum TLS version supported.  TLS 1.0 and 1.1 may
+// still be enabled for old clients with the min version options.
+const DefaultTLSMinVersion = "VersionTLS12"
 
 var cipherLookup map[string]uint16
 
@@ -70,41 +71,38 @@ func init() {
 		"TLS_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
 		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
 		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":    tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":  tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
 	}
 
-	// All supported ciphers in descending order with (roughly) newest first. Note that in GO 1.6 the HTTP2
-	// server will fail to start if some of the ciphers lower in the list appear before several which
-	// are higher in the list or if ECDHE_RSA_WITH_AES_128_GCM_SHA256 is NOT in the list.
+	// Only ciphers with forward secrecy and authenticated encryption are
+	// enabled by default, since security scans flag the CBC, 3DES and RSA key
+	// exchange ciphers.  They can still be enabled with the cipher options for
+	// clients that need them.  The list applies to TLS 1.2 and older; the
+	// TLS 1.3 cipher suites are all secure and are always enabled.
+	//
+	// Note that the HTTP2 server will fail to start if
+	// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 is NOT in the list.
 	// See ConfigureServer() and isBadCipher() in https://github.com/golang/net/blob/master/http2/server.go
 	httpDefaultCiphers := []string{
 		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
 		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
 		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
 		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
-		"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
-		"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
-		"TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
-		"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
-		"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
-		"TLS_RSA_WITH_AES_256_CBC_SHA",
-		"TLS_RSA_WITH_AES_128_CBC_SHA",
-		"TLS_RSA_WITH_3DES_EDE_CBC_SHA",
-		"TLS_RSA_WITH_AES_128_GCM_SHA256",
-		"TLS_RSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
+		"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305",
 	}
 
 	// For RPC/MUX communication, we want the fastest ciphers possible.
 	//
 	// CC-2512/CC-2514 - based on testing in our lab, some ciphers have terrible performance and some
-	//                   do not work with HTTP, RPC or MUX communications. This list is ordered by most-performant
-	//                   to least performant based on our tests.
+	//                   do not work with HTTP, RPC or MUX communications.  AES-GCM is the fastest of the
+	//                   default ciphers on hosts with AES instructions, so it is listed first.
 	//
 	rpcDefaultCiphers := []string{
-		"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
-		"TLS_RSA_WITH_AES_128_CBC_SHA",
-		"TLS_RSA_WITH_AES_256_CBC_SHA",
-		"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
 		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305",
 	}
 
 	muxDefaultCiphers := rpcDefaultCiphers
@@ -187,6 +185,8 @@ func tlsVersionStringToUint(version string) (uint16, error) {
 		return tls.VersionTLS11, nil
 	case "VERSIONTLS12":
 		return tls.VersionTLS12, nil
+	case "VERSIONTLS13":
+		return tls.VersionTLS13, nil
 	default:
 		return 0, fmt.Errorf("Invalid TLS version %s", version)
 	}
@@ -218,6 +218,22 @@ func CipherSuitesByName(c *tls.Config) []string {
 	return suiteList
 }
 
+// TLSVersionName returns the name of a TLS version, as it is set in the min
+// version options
+func TLSVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "VersionTLS10"
+	case tls.VersionTLS11:
+		return "VersionTLS11"
+	case tls.VersionTLS12:
+		return "VersionTLS12"
+	case tls.VersionTLS13:
+		return "VersionTLS13"
+	}
+	return "unsupported"
+}
+
 // Get the name of the cipher
 func GetCipherName(cipher uint16) string {
 	for key, value := range cipherLookup {
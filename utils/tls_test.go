@@ -0,0 +1,68 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package utils
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestSetMinTLS(t *testing.T) {
+	defer SetMinTLS("mux", DefaultTLSMinVersion)
+
+	if MinTLS("mux") != tls.VersionTLS12 {
+		t.Errorf("expected the default minimum version to be TLS 1.2, got %s", TLSVersionName(MinTLS("mux")))
+	}
+	if err := SetMinTLS("mux", "versiontls13"); err != nil {
+		t.Fatalf("could not set TLS 1.3: %s", err)
+	}
+	if MinTLS("mux") != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3, got %s", TLSVersionName(MinTLS("mux")))
+	}
+	if err := SetMinTLS("mux", "VersionSSL30"); err == nil {
+		t.Errorf("expected an error for an unsupported version")
+	}
+	if MinTLS("mux") != tls.VersionTLS13 {
+		t.Errorf("an invalid version changed the minimum version to %s", TLSVersionName(MinTLS("mux")))
+	}
+}
+
+func TestSetCiphers(t *testing.T) {
+	defer SetCiphers("rpc", GetDefaultCiphers("rpc"))
+
+	for _, connectionType := range []string{"http", "rpc", "mux"} {
+		for _, cipher := range CipherSuites(connectionType) {
+			switch cipher {
+			case tls.TLS_RSA_WITH_AES_128_CBC_SHA, tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+				tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA, tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+				tls.TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA, tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA:
+				t.Errorf("legacy cipher %s is enabled by default for %s", GetCipherName(cipher), connectionType)
+			}
+		}
+	}
+
+	// legacy ciphers can still be enabled
+	if err := SetCiphers("rpc", []string{"tls_rsa_with_aes_128_cbc_sha", " TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305"}); err != nil {
+		t.Fatalf("could not set ciphers: %s", err)
+	}
+	if ciphers := CipherSuites("rpc"); len(ciphers) != 2 || ciphers[0] != tls.TLS_RSA_WITH_AES_128_CBC_SHA || ciphers[1] != tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305 {
+		t.Errorf("unexpected ciphers %v", ciphers)
+	}
+	if err := SetCiphers("rpc", []string{"TLS_NULL_WITH_NULL_NULL"}); err == nil {
+		t.Errorf("expected an error for an unknown cipher")
+	}
+}
@@ -38,3 +38,32 @@ func TestGetIPv4Addresses(t *testing.T) {
 		t.Fail()
 	}
 }
+
+// Test GetIPAddresses()
+func TestGetIPAddresses(t *testing.T) {
+	ips, err := GetIPAddresses()
+	if err != nil {
+		t.Errorf("Failed to get ip addresses: %s", err)
+	}
+
+	ipv4s, _ := GetIPv4Addresses()
+	if len(ips) < len(ipv4s) {
+		t.Errorf("expected at least the %d ipv4 addresses, retrieved %d ips:%v", len(ipv4s), len(ips), ips)
+	}
+}
+
+// Test CanonicalIP()
+func TestCanonicalIP(t *testing.T) {
+	for ip, expected := range map[string]string{
+		"10.0.0.1":              "10.0.0.1",
+		"2001:DB8:0:0:0:0:0:10": "2001:db8::10",
+		"[2001:db8::10]":        "2001:db8::10",
+		"::ffff:10.0.0.1":       "10.0.0.1",
+		"not an ip":             "not an ip",
+		"":                      "",
+	} {
+		if actual := CanonicalIP(ip); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, ip, actual)
+		}
+	}
+}
@@ -0,0 +1,498 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaDraft is the JSON Schema draft that schemas are published as
+const SchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema document, or a subschema of one.  Only the keywords
+// that describe the JSON encoding of go types, and the constraints that are
+// added to them, are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 SchemaTypes        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// SchemaTypes are the JSON types that a schema allows.  A single type is
+// encoded as a string.
+type SchemaTypes []string
+
+// MarshalJSON implements json.Marshaler
+func (t SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = SchemaTypes{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	*t = SchemaTypes(names)
+	return nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// NewSchema returns the schema of the JSON encoding of the type of v.  Named
+// struct types are described once under definitions and referenced, so
+// recursive types can be described.  Types that encode themselves are
+// described as any value.
+func NewSchema(title string, v interface{}) *Schema {
+	g := &schemaGenerator{
+		definitions: make(map[string]*Schema),
+		names:       make(map[reflect.Type]string),
+	}
+	root := g.schema(reflect.TypeOf(v))
+	root.Schema = SchemaDraft
+	root.Title = title
+	root.Definitions = g.definitions
+	return root
+}
+
+// Definition returns the definition of the named struct type of v in the
+// schema, for adding constraints to it.  It returns nil if the type is not
+// defined.
+func (s *Schema) Definition(v interface{}) *Schema {
+	return s.Definitions[definitionName(reflect.TypeOf(v))]
+}
+
+// Property returns the schema of a property of an object schema, or nil if
+// the object does not have the property.
+func (s *Schema) Property(name string) *Schema {
+	return s.Properties[name]
+}
+
+// SetMinimum sets the minimum of a numeric schema
+func (s *Schema) SetMinimum(min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+// SetMaximum sets the maximum of a numeric schema
+func (s *Schema) SetMaximum(max float64) *Schema {
+	s.Maximum = &max
+	return s
+}
+
+// SetMinLength sets the minimum length of a string schema
+func (s *Schema) SetMinLength(min int) *Schema {
+	s.MinLength = &min
+	return s
+}
+
+// SetEnum sets the values that a schema allows
+func (s *Schema) SetEnum(values ...interface{}) *Schema {
+	s.Enum = values
+	return s
+}
+
+// Require requires properties of an object schema
+func (s *Schema) Require(names ...string) *Schema {
+	s.Required = append(s.Required, names...)
+	return s
+}
+
+func definitionName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+// schemaGenerator describes go types as schemas
+type schemaGenerator struct {
+	definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: SchemaTypes{"string"}, Format: "date-time"}
+	}
+	if t.Kind() == reflect.Ptr {
+		return nullable(g.schema(t.Elem()))
+	}
+	if t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType) {
+		return &Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: SchemaTypes{"string"}}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaTypes{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: SchemaTypes{"integer"}}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		s := &Schema{Type: SchemaTypes{"integer"}}
+		return s.SetMinimum(0).SetMaximum(float64(uint64(1)<<uint(t.Bits()) - 1))
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		s := &Schema{Type: SchemaTypes{"integer"}}
+		return s.SetMinimum(0)
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaTypes{"number"}}
+	case reflect.String:
+		return &Schema{Type: SchemaTypes{"string"}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as base64 strings
+			return &Schema{Type: SchemaTypes{"string", "null"}}
+		}
+		return &Schema{Type: SchemaTypes{"array", "null"}, Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: SchemaTypes{"array"}, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: SchemaTypes{"object", "null"}, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.define(t)
+	}
+	return &Schema{}
+}
+
+// define adds a named struct type to the definitions and returns a reference
+// to it
+func (g *schemaGenerator) define(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = definitionName(t)
+		g.names[t] = name
+		// reserve the name before describing the fields, for recursion
+		def := &Schema{}
+		g.definitions[name] = def
+		*def = *g.object(t)
+	}
+	return &Schema{Ref: "#/definitions/" + name}
+}
+
+// object describes the encoded fields of a struct, including the fields of
+// its embedded structs
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: SchemaTypes{"object"}, Properties: make(map[string]*Schema)}
+	g.fields(t, s.Properties)
+	return s
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]*Schema) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		if hasOption(opts, "string") {
+			properties[name] = &Schema{Type: SchemaTypes{"string"}}
+		} else {
+			properties[name] = g.schema(field.Type)
+		}
+	}
+
+	// fields of embedded structs are shadowed by the fields of the struct
+	for _, et := range embedded {
+		g.fields(et, properties)
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable allows a schema to be null
+func nullable(s *Schema) *Schema {
+	if len(s.Type) > 0 {
+		for _, t := range s.Type {
+			if t == "null" {
+				return s
+			}
+		}
+		s.Type = append(s.Type, "null")
+		return s
+	} else if s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: SchemaTypes{"null"}}}}
+	}
+	return s
+}
+
+// Validate checks a JSON document against the schema, and returns a
+// ValidationError with a violation for each value that does not conform,
+// identified by its path in the document.
+func (s *Schema) Validate(data []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	violations := NewValidationError()
+	s.validate(s, "", doc, violations)
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
+
+// ValidateValue checks the JSON encoding of a value against the schema
+func (s *Schema) ValidateValue(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Validate(data)
+}
+
+func (s *Schema) validate(root *Schema, p string, v interface{}, violations *ValidationError) {
+	if s.Ref != "" {
+		ref := root.resolve(s.Ref)
+		if ref == nil {
+			violations.AddViolation(fmt.Sprintf("%s: unknown schema reference %s", pathName(p), s.Ref))
+			return
+		}
+		ref.validate(root, p, v, violations)
+		return
+	}
+
+	if len(s.AnyOf) > 0 {
+		s.validateAnyOf(root, p, v, violations)
+		return
+	}
+
+	if len(s.Type) > 0 && !s.Type.allows(v) {
+		violations.AddViolation(fmt.Sprintf("%s: expected %s, got %s", pathName(p), strings.Join(s.Type, " or "), jsonType(v)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		allowed := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			b, _ := json.Marshal(e)
+			allowed[i] = string(b)
+		}
+		violations.AddViolation(fmt.Sprintf("%s: must be one of %s", pathName(p), strings.Join(allowed, ", ")))
+	}
+
+	switch value := v.(type) {
+	case float64:
+		if s.Minimum != nil && value < *s.Minimum {
+			violations.AddViolation(fmt.Sprintf("%s: must be at least %v", pathName(p), *s.Minimum))
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			violations.AddViolation(fmt.Sprintf("%s: must be at most %v", pathName(p), *s.Maximum))
+		}
+	case string:
+		if s.MinLength != nil && len([]rune(value)) < *s.MinLength {
+			violations.AddViolation(fmt.Sprintf("%s: must be at least %d characters", pathName(p), *s.MinLength))
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err != nil {
+				violations.AddViolation(fmt.Sprintf("%s: invalid pattern %s", pathName(p), s.Pattern))
+			} else if !re.MatchString(value) {
+				violations.AddViolation(fmt.Sprintf("%s: must match %s", pathName(p), s.Pattern))
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(root, fmt.Sprintf("%s[%d]", p, i), item, violations)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				violations.AddViolation(fmt.Sprintf("%s: is required", pathName(joinPath(p, name))))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(root, joinPath(p, name), value[name], violations)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(root, joinPath(p, name), value[name], violations)
+			}
+		}
+	}
+}
+
+// validateAnyOf passes if the value conforms to any of the schemas.
+// Otherwise, it reports the violations of the first schema that allows the
+// type of the value, so the path of the violation is not lost.
+func (s *Schema) validateAnyOf(root *Schema, p string, v interface{}, violations *ValidationError) {
+	var first *ValidationError
+	for _, alt := range s.AnyOf {
+		altViolations := NewValidationError()
+		alt.validate(root, p, v, altViolations)
+		if !altViolations.HasError() {
+			return
+		}
+		if first == nil && !alt.typeMismatch(root, v) {
+			first = altViolations
+		}
+	}
+	if first != nil {
+		violations.Errors = append(violations.Errors, first.Errors...)
+		return
+	}
+	violations.AddViolation(fmt.Sprintf("%s: unexpected %s", pathName(p), jsonType(v)))
+}
+
+// typeMismatch returns true if the schema does not allow the type of the value
+func (s *Schema) typeMismatch(root *Schema, v interface{}) bool {
+	if s.Ref != "" {
+		if ref := root.resolve(s.Ref); ref != nil {
+			return ref.typeMismatch(root, v)
+		}
+		return true
+	}
+	return len(s.Type) > 0 && !s.Type.allows(v)
+}
+
+// resolve returns the definition of a reference of the schema
+func (s *Schema) resolve(ref string) *Schema {
+	const prefix = "#/definitions/"
+	if !strings.HasPrefix(ref, prefix) {
+		return nil
+	}
+	return s.Definitions[strings.TrimPrefix(ref, prefix)]
+}
+
+// allows returns true if the value is one of the types
+func (t SchemaTypes) allows(v interface{}) bool {
+	actual := jsonType(v)
+	for _, name := range t {
+		if name == actual {
+			return true
+		} else if name == "integer" && actual == "number" && v.(float64) == math.Trunc(v.(float64)) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON type of a decoded value
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	actual, _ := json.Marshal(v)
+	for _, e := range enum {
+		if b, _ := json.Marshal(e); bytes.Equal(b, actual) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(p, name string) string {
+	if p == "" {
+		return name
+	}
+	return p + "." + name
+}
+
+func pathName(p string) string {
+	if p == "" {
+		return "(root)"
+	}
+	return p
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type schemaTestChild struct {
+	Name     string
+	Port     uint16 `json:",omitempty"`
+	Children []schemaTestChild
+}
+
+type schemaTestEmbedded struct {
+	Version int
+	Name    string
+}
+
+type schemaTestParent struct {
+	schemaTestEmbedded
+	Name    string `json:"name"`
+	Ignored string `json:"-"`
+	Child   *schemaTestChild
+	Tags    map[string]string
+	Count   int64 `json:",string"`
+	secret  string
+}
+
+func (vs *ValidationSuite) Test_NewSchema(c *C) {
+	s := NewSchema("Parent", schemaTestParent{})
+	c.Assert(s.Schema, Equals, SchemaDraft)
+	c.Assert(s.Title, Equals, "Parent")
+	c.Assert(s.Ref, Equals, "#/definitions/validation.schemaTestParent")
+
+	parent := s.Definition(schemaTestParent{})
+	c.Assert(parent, NotNil)
+	names := []string{}
+	for name := range parent.Properties {
+		names = append(names, name)
+	}
+	c.Assert(names, HasLen, 6)
+	c.Assert(parent.Property("name").Type, DeepEquals, SchemaTypes{"string"})
+	c.Assert(parent.Property("Version").Type, DeepEquals, SchemaTypes{"integer"})
+	c.Assert(*s.Definition(schemaTestChild{}).Property("Port").Maximum, Equals, float64(65535))
+	c.Assert(parent.Property("Count").Type, DeepEquals, SchemaTypes{"string"})
+	c.Assert(parent.Property("Tags").Type, DeepEquals, SchemaTypes{"object", "null"})
+	c.Assert(parent.Property("Child").AnyOf, HasLen, 2)
+	c.Assert(parent.Property("Ignored"), IsNil)
+	c.Assert(parent.Property("secret"), IsNil)
+
+	// recursive types are referenced
+	child := s.Definition(schemaTestChild{})
+	c.Assert(child, NotNil)
+	c.Assert(child.Property("Children").Items.Ref, Equals, "#/definitions/validation.schemaTestChild")
+}
+
+func (vs *ValidationSuite) Test_SchemaRoundTrip(c *C) {
+	s := NewSchema("Parent", schemaTestParent{})
+	s.Definition(schemaTestChild{}).Require("Name").Property("Port").SetMinimum(1)
+	data, err := json.Marshal(s)
+	c.Assert(err, IsNil)
+
+	var actual Schema
+	err = json.Unmarshal(data, &actual)
+	c.Assert(err, IsNil)
+	c.Assert(&actual, DeepEquals, s)
+}
+
+func (vs *ValidationSuite) Test_SchemaValidate(c *C) {
+	s := NewSchema("Parent", schemaTestParent{})
+	s.Definition(schemaTestChild{}).Require("Name").Property("Port").SetMinimum(1)
+	s.Definition(schemaTestParent{}).Property("name").SetEnum("a", "b")
+
+	err := s.Validate([]byte(`{"name": "a", "Version": 2, "Child": {"Name": "c", "Port": 80, "Children": null}, "Tags": {"x": "y"}}`))
+	c.Assert(err, IsNil)
+
+	err = s.Validate([]byte(`{"name": "a", "Child": null}`))
+	c.Assert(err, IsNil)
+
+	err = s.ValidateValue(schemaTestParent{Name: "a", Child: &schemaTestChild{Name: "c", Port: 1}})
+	c.Assert(err, IsNil)
+
+	err = s.Validate([]byte(`{
+		"name": "z",
+		"Version": 1.5,
+		"Tags": {"x": 1},
+		"Child": {"Port": 0, "Children": [{"Name": "d", "Port": "80"}]}
+	}`))
+	c.Assert(err, NotNil)
+	verr, ok := err.(*ValidationError)
+	c.Assert(ok, Equals, true)
+	msgs := []string{}
+	for _, e := range verr.Errors {
+		msgs = append(msgs, e.Error())
+	}
+	c.Assert(msgs, DeepEquals, []string{
+		"Child.Name: is required",
+		"Child.Children[0].Port: expected integer, got string",
+		"Child.Port: must be at least 1",
+		"Tags.x: expected string, got number",
+		"Version: expected integer, got number",
+		`name: must be one of "a", "b"`,
+	})
+
+	err = s.Validate([]byte(`[]`))
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "(root): expected object, got array"), Equals, true)
+}
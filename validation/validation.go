@@ -15,6 +15,8 @@ package validation
 
 import (
 	"fmt"
+
+	"github.com/control-center/serviced/apierror"
 )
 
 //ValidationError is an error that contains other errors
@@ -48,6 +50,11 @@ func (v *ValidationError) Error() string {
 	return errString
 }
 
+//ErrorCode implements apierror.Coder
+func (v *ValidationError) ErrorCode() apierror.Code {
+	return apierror.Validation
+}
+
 //HasError test to see if length of  Errors slice is greater than 0
 func (v *ValidationError) HasError() bool {
 
@@ -68,3 +75,8 @@ type Violation struct {
 func (v *Violation) Error() string {
 	return v.msg
 }
+
+//ErrorCode implements apierror.Coder
+func (v *Violation) ErrorCode() apierror.Code {
+	return apierror.Validation
+}
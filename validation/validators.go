@@ -45,6 +45,33 @@ func IsIP(value string) error {
 	return nil
 }
 
+// IsNetmask checks to see if the value is a valid netmask for the IP, which
+// is either a mask of the same address family as the IP or a prefix length.
+// Returns an error if not valid
+func IsNetmask(ip, value string) error {
+	ipaddr := net.ParseIP(ip)
+	if ipaddr == nil {
+		return NewViolation(fmt.Sprintf("invalid IP Address %s", ip))
+	}
+	bits := 8 * net.IPv6len
+	if ipaddr.To4() != nil {
+		bits = 8 * net.IPv4len
+	}
+	if size, err := strconv.Atoi(value); err == nil {
+		if size < 0 || size > bits {
+			return NewViolation(fmt.Sprintf("invalid prefix length %s for IP Address %s", value, ip))
+		}
+		return nil
+	}
+	mask := net.ParseIP(value)
+	if mask == nil {
+		return NewViolation(fmt.Sprintf("invalid netmask %s", value))
+	} else if bits == 8*net.IPv6len && mask.To4() != nil {
+		return NewViolation(fmt.Sprintf("invalid netmask %s for IPv6 Address %s", value, ip))
+	}
+	return nil
+}
+
 //IsSubnet16 checks to see if the value is a valid /16 subnet.  Returns an error if not valid
 func IsSubnet16(value string) error {
 	parts := strings.Split(value, ".")
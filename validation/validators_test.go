@@ -177,3 +177,30 @@ func (vs *ValidationSuite) Test_ExcludeString(c *C) {
 	err = ExcludeChars("field", "", "")
 	c.Assert(err, IsNil)
 }
+
+func (vs *ValidationSuite) Test_IsNetmask(c *C) {
+	valid := [][2]string{
+		{"10.0.0.10", "255.255.255.0"},
+		{"10.0.0.10", "24"},
+		{"2001:db8::10", "64"},
+		{"2001:db8::10", "ffff:ffff:ffff:ffff::"},
+	}
+	for _, v := range valid {
+		if err := IsNetmask(v[0], v[1]); err != nil {
+			c.Errorf("Unexpected error validating netmask %s for %s: %v", v[1], v[0], err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"10.0.0.10", "33"},
+		{"10.0.0.10", "abc"},
+		{"2001:db8::10", "129"},
+		{"2001:db8::10", "255.255.255.0"},
+		{"not an ip", "24"},
+	}
+	for _, v := range invalid {
+		if err := IsNetmask(v[0], v[1]); err == nil {
+			c.Errorf("Expected error validating netmask %s for %s", v[1], v[0])
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path"
@@ -188,6 +189,9 @@ func (d *BtrfsDriver) Remove(volumeName string) error {
 		glog.Errorf("Could not remove volume %s: %s (%s)", v.Name(), output, err)
 		return volume.ErrRemovingVolume
 	}
+	if err := os.Remove(d.quotaPath(volumeName)); err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Could not remove the quota of volume %s: %s", volumeName, err)
+	}
 	return nil
 }
 
@@ -233,11 +237,71 @@ func (d *BtrfsDriver) GetTenant(volumeName string) (volume.Volume, error) {
 	return d.Get(getTenant(volumeName))
 }
 
-// Resize implements volume.Driver.Resize. For btrfs it's a noop.
+// Resize implements volume.Driver.Resize.  For btrfs it limits the data
+// that the volume's subvolume may reference with a qgroup, and records the
+// limit so that it can be reapplied when the subvolume is rolled back.
 func (d *BtrfsDriver) Resize(volumeName string, size uint64) error {
+	if !d.Exists(volumeName) {
+		return volume.ErrVolumeNotExists
+	}
+	if quota, err := d.Quota(volumeName); err == nil && size < quota.Used {
+		return volume.ErrQuotaTooSmall
+	}
+	if err := d.limit(volumeName, size); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(d.quotaPath(volumeName), []byte(strconv.FormatUint(size, 10)), 0644); err != nil {
+		glog.Errorf("Could not record the quota of volume %s: %s", volumeName, err)
+		return err
+	}
+	glog.Infof("Set quota of volume %s to %s", volumeName, humanize.IBytes(size))
+	return nil
+}
+
+// Quota implements volume.QuotaDriver.Quota
+func (d *BtrfsDriver) Quota(volumeName string) (*volume.Quota, error) {
+	output, err := volume.RunBtrFSCmd(d.sudoer, "qgroup", "show", "-r", "--raw", "-f", filepath.Join(d.root, volumeName))
+	if err != nil {
+		glog.Errorf("Could not get the quota of volume %s: %s (%s)", volumeName, output, err)
+		return nil, err
+	}
+	return parseQgroupShow(strings.Split(string(output), "\n"))
+}
+
+// quotaPath returns the path of the file that records a volume's quota
+func (d *BtrfsDriver) quotaPath(volumeName string) string {
+	return filepath.Join(d.MetadataDir(), volumeName+".quota")
+}
+
+// limit applies a qgroup limit to a volume's subvolume
+func (d *BtrfsDriver) limit(volumeName string, size uint64) error {
+	if output, err := volume.RunBtrFSCmd(d.sudoer, "quota", "enable", d.root); err != nil {
+		glog.Errorf("Could not enable quotas at %s: %s (%s)", d.root, output, err)
+		return err
+	}
+	if output, err := volume.RunBtrFSCmd(d.sudoer, "qgroup", "limit", strconv.FormatUint(size, 10), filepath.Join(d.root, volumeName)); err != nil {
+		glog.Errorf("Could not limit volume %s to %d bytes: %s (%s)", volumeName, size, output, err)
+		return err
+	}
 	return nil
 }
 
+// reapplyQuota restores the recorded quota of a volume whose subvolume was
+// recreated.
+func (d *BtrfsDriver) reapplyQuota(volumeName string) error {
+	data, err := ioutil.ReadFile(d.quotaPath(volumeName))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	size, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return err
+	}
+	return d.limit(volumeName, size)
+}
+
 // Get implements volume.Driver.Get
 func (d *BtrfsDriver) Get(volumeName string) (volume.Volume, error) {
 	volumePath := filepath.Join(d.root, volumeName)
@@ -587,11 +651,19 @@ func (v *BtrfsVolume) Rollback(label string) error {
 	_, err = volume.RunBtrFSCmd(v.sudoer, cmd...)
 	if err != nil {
 		glog.Errorf("rollback of snapshot %s failed for cmd:%s", label, cmd)
-	} else {
-		duration := time.Now().Sub(start)
-		glog.Infof("rollback of snapshot %s took %s", label, duration)
+		return err
 	}
-	return err
+	duration := time.Now().Sub(start)
+	glog.Infof("rollback of snapshot %s took %s", label, duration)
+
+	// the new subvolume does not inherit the qgroup limit of the old one
+	if d, ok := v.driver.(*BtrfsDriver); ok {
+		if err := d.reapplyQuota(v.name); err != nil {
+			glog.Errorf("Could not reapply the quota of volume %s: %s", v.name, err)
+			return err
+		}
+	}
+	return nil
 }
 
 // Export implements volume.Volume.Export
@@ -763,6 +835,34 @@ func parseDF(lines []string) ([]BtrfsDFData, error) {
 	return df, nil
 }
 
+// output of btrfs qgroup show -r --raw -f:
+/*
+	qgroupid         rfer         excl     max_rfer
+	--------         ----         ----     --------
+	0/258        10485760     10485760    104857600
+*/
+func parseQgroupShow(lines []string) (*volume.Quota, error) {
+	lines = removeBlankLines(lines)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "0/") {
+			continue
+		}
+		used, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse referenced size in line %q: %s", line, err)
+		}
+		quota := &volume.Quota{Used: used}
+		if fields[3] != "none" {
+			if quota.Limit, err = strconv.ParseUint(fields[3], 10, 64); err != nil {
+				return nil, fmt.Errorf("could not parse referenced limit in line %q: %s", line, err)
+			}
+		}
+		return quota, nil
+	}
+	return nil, fmt.Errorf("qgroup not found in output: %v", strings.Join(lines, "\n"))
+}
+
 func parseSize(size string) (uint64, error) {
 	sizemod := strings.Trim(size, " ,:")
 	sizeret, err := humanize.ParseBytes(sizemod)
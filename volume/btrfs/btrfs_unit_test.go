@@ -236,3 +236,27 @@ func TestParseDF(t *testing.T) {
 		assert.Equal(t, result, tc.out, fmt.Sprintf("%s: %s", tc.label, tc.outmsg))
 	}
 }
+
+func TestParseQgroupShow(t *testing.T) {
+	quota, err := parseQgroupShow([]string{
+		"qgroupid         rfer         excl     max_rfer ",
+		"--------         ----         ----     -------- ",
+		"0/258        10485760     10485760    104857600 ",
+		"",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10485760), quota.Used)
+	assert.Equal(t, uint64(104857600), quota.Limit)
+
+	quota, err = parseQgroupShow([]string{
+		"qgroupid         rfer         excl     max_rfer     max_excl ",
+		"--------         ----         ----     --------     -------- ",
+		"0/259           16384        16384         none         none ",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(16384), quota.Used)
+	assert.Equal(t, uint64(0), quota.Limit)
+
+	_, err = parseQgroupShow([]string{"ERROR: can't list qgroups: quotas not enabled"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,261 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"sync"
+
+	gzip "github.com/klauspost/pgzip"
+)
+
+// Compression is the codec that compresses an exported tar stream
+type Compression string
+
+const (
+	// CompressionGzip compresses with gzip
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses with zstandard
+	CompressionZstd Compression = "zstd"
+	// CompressionNone does not compress
+	CompressionNone Compression = "none"
+
+	// DefaultCompression is the codec used when none is selected
+	DefaultCompression = CompressionGzip
+)
+
+var (
+	// ErrUnknownCompression is returned when a compression codec is not
+	// supported
+	ErrUnknownCompression = errors.New("unknown compression codec")
+
+	// ZstdCommand is the zstd binary that compresses and decompresses
+	// zstandard streams
+	ZstdCommand = "zstd"
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ParseCompression returns the codec with the given name.  An empty name is
+// the default codec.
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(strings.ToLower(strings.TrimSpace(name))); c {
+	case "":
+		return DefaultCompression, nil
+	case CompressionGzip, CompressionZstd, CompressionNone:
+		return c, nil
+	}
+	return "", ErrUnknownCompression
+}
+
+// Extension returns the file extension of a tar archive compressed with the
+// codec.
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionZstd:
+		return ".tar.zst"
+	case CompressionNone:
+		return ".tar"
+	}
+	return ".tgz"
+}
+
+// TrimArchiveExtension removes the extension of a compressed tar archive from
+// a filename.
+func TrimArchiveExtension(filename string) string {
+	for _, c := range []Compression{CompressionGzip, CompressionZstd, CompressionNone} {
+		if strings.HasSuffix(filename, c.Extension()) {
+			return strings.TrimSuffix(filename, c.Extension())
+		}
+	}
+	return filename
+}
+
+// NewCompressor returns a writer that compresses to w with the codec.  The
+// stream is complete when the writer is closed; w is not closed.  Streams
+// that are written one after the other to the same file can be read back with
+// a single decompressor.
+func NewCompressor(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case "", CompressionGzip:
+		gz := gzip.NewWriter(w)
+		// CC-2292: Limit concurrency of backup gzipping
+		// This setting will cause the writer to process up to 2 100KB blocks
+		// at a time before the writer blocks. The default was 16 250KB blocks.
+		// Smaller blocks will allow other goroutines to get time more frequently.
+		gz.SetConcurrency(100000, 2)
+		return gz, nil
+	case CompressionZstd:
+		return newZstdWriter(w)
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	}
+	return nil, ErrUnknownCompression
+}
+
+// NewDecompressor returns a reader that decompresses r, and the codec that r
+// was compressed with.  The codec is detected from the start of the stream.
+func NewDecompressor(r io.Reader) (io.ReadCloser, Compression, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, "", err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, "", err
+		}
+		return gz, CompressionGzip, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := newZstdReader(br)
+		if err != nil {
+			return nil, "", err
+		}
+		return zr, CompressionZstd, nil
+	}
+	return ioutil.NopCloser(br), CompressionNone, nil
+}
+
+// ExportCompressed exports a snapshot like Volume.Export, compressed with the
+// codec.
+func ExportCompressed(vol Volume, label, parent string, w io.Writer, excludes []string, c Compression) error {
+	cw, err := NewCompressor(w, c)
+	if err != nil {
+		return err
+	}
+	if err := vol.Export(label, parent, cw, excludes); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// ImportCompressed imports a snapshot like Volume.Import from a stream that
+// was exported with any codec.
+func ImportCompressed(vol Volume, label string, r io.Reader) error {
+	dr, _, err := NewDecompressor(r)
+	if err != nil {
+		return err
+	}
+	defer dr.Close()
+	return vol.Import(label, dr)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// zstdWriter compresses through the zstd binary
+type zstdWriter struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+}
+
+func newZstdWriter(w io.Writer) (*zstdWriter, error) {
+	z := &zstdWriter{cmd: exec.Command(ZstdCommand, "-q", "-c", "-T0")}
+	z.cmd.Stdout = w
+	z.cmd.Stderr = &z.stderr
+	stdin, err := z.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	z.stdin = stdin
+	if err := z.cmd.Start(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (z *zstdWriter) Write(p []byte) (int, error) {
+	return z.stdin.Write(p)
+}
+
+// Close ends the zstd frame and waits for it to be written
+func (z *zstdWriter) Close() error {
+	z.stdin.Close()
+	return zstdError(z.cmd.Wait(), &z.stderr)
+}
+
+// zstdReader decompresses through the zstd binary
+type zstdReader struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	stderr bytes.Buffer
+	once   sync.Once
+	err    error
+}
+
+func newZstdReader(r io.Reader) (*zstdReader, error) {
+	z := &zstdReader{cmd: exec.Command(ZstdCommand, "-d", "-q", "-c")}
+	z.cmd.Stdin = r
+	z.cmd.Stderr = &z.stderr
+	stdout, err := z.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	z.stdout = stdout
+	if err := z.cmd.Start(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (z *zstdReader) Read(p []byte) (int, error) {
+	n, err := z.stdout.Read(p)
+	if err == io.EOF {
+		if werr := z.wait(); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Close stops the decompression if the stream has not been read to the end
+func (z *zstdReader) Close() error {
+	z.once.Do(func() {
+		z.cmd.Process.Kill()
+		z.cmd.Wait()
+	})
+	return nil
+}
+
+func (z *zstdReader) wait() error {
+	z.once.Do(func() {
+		z.err = zstdError(z.cmd.Wait(), &z.stderr)
+	})
+	return z.err
+}
+
+func zstdError(err error, stderr *bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("zstd: %s", msg)
+	}
+	return fmt.Errorf("zstd: %s", err)
+}
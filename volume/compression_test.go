@@ -0,0 +1,128 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package volume_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os/exec"
+
+	. "github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/volume/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+type CompressionSuite struct{}
+
+var _ = Suite(&CompressionSuite{})
+
+func (s *CompressionSuite) codecs(c *C) []Compression {
+	codecs := []Compression{CompressionGzip, CompressionNone}
+	if _, err := exec.LookPath(ZstdCommand); err == nil {
+		codecs = append(codecs, CompressionZstd)
+	} else {
+		c.Log("zstd is not installed, skipping zstd")
+	}
+	return codecs
+}
+
+func (s *CompressionSuite) TestParseCompression(c *C) {
+	for name, expected := range map[string]Compression{
+		"":      CompressionGzip,
+		"gzip":  CompressionGzip,
+		" ZSTD": CompressionZstd,
+		"none":  CompressionNone,
+	} {
+		codec, err := ParseCompression(name)
+		c.Assert(err, IsNil)
+		c.Check(codec, Equals, expected)
+	}
+	_, err := ParseCompression("bzip2")
+	c.Assert(err, Equals, ErrUnknownCompression)
+}
+
+func (s *CompressionSuite) TestArchiveExtension(c *C) {
+	for _, codec := range []Compression{CompressionGzip, CompressionZstd, CompressionNone} {
+		c.Check(TrimArchiveExtension("backup"+codec.Extension()), Equals, "backup")
+	}
+	c.Check(TrimArchiveExtension("backup.json"), Equals, "backup.json")
+}
+
+func (s *CompressionSuite) TestRoundTrip(c *C) {
+	for _, codec := range s.codecs(c) {
+		var buf bytes.Buffer
+		// streams written one after the other are read back as one
+		for _, part := range []string{"first part,", "second part"} {
+			w, err := NewCompressor(&buf, codec)
+			c.Assert(err, IsNil)
+			_, err = io.WriteString(w, part)
+			c.Assert(err, IsNil)
+			c.Assert(w.Close(), IsNil)
+		}
+		r, detected, err := NewDecompressor(&buf)
+		c.Assert(err, IsNil)
+		c.Check(detected, Equals, codec)
+		data, err := ioutil.ReadAll(r)
+		c.Assert(err, IsNil)
+		c.Check(string(data), Equals, "first part,second part")
+		c.Assert(r.Close(), IsNil)
+	}
+}
+
+func (s *CompressionSuite) TestDecompressorCloseEarly(c *C) {
+	for _, codec := range s.codecs(c) {
+		var buf bytes.Buffer
+		w, err := NewCompressor(&buf, codec)
+		c.Assert(err, IsNil)
+		_, err = w.Write(bytes.Repeat([]byte("0123456789"), 1<<16))
+		c.Assert(err, IsNil)
+		c.Assert(w.Close(), IsNil)
+
+		r, _, err := NewDecompressor(&buf)
+		c.Assert(err, IsNil)
+		p := make([]byte, 10)
+		_, err = io.ReadFull(r, p)
+		c.Assert(err, IsNil)
+		c.Check(string(p), Equals, "0123456789")
+		c.Assert(r.Close(), IsNil)
+	}
+}
+
+func (s *CompressionSuite) TestExportImportCompressed(c *C) {
+	for _, codec := range s.codecs(c) {
+		var buf bytes.Buffer
+		vol := &mocks.Volume{}
+		vol.On("Export", "label", "", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+			io.WriteString(a.Get(2).(io.Writer), "exported")
+		})
+		c.Assert(ExportCompressed(vol, "label", "", &buf, nil, codec), IsNil)
+		if codec != CompressionNone {
+			c.Check(buf.String(), Not(Equals), "exported")
+		}
+
+		var imported string
+		vol.On("Import", "label", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+			data, err := ioutil.ReadAll(a.Get(1).(io.Reader))
+			c.Assert(err, IsNil)
+			imported = string(data)
+		})
+		c.Assert(ImportCompressed(vol, "label", &buf), IsNil)
+		c.Check(imported, Equals, "exported")
+	}
+}
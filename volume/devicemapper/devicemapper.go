@@ -392,6 +392,20 @@ func (d *DeviceMapperDriver) Resize(volumeName string, size uint64) error {
 	return nil
 }
 
+// Quota implements volume.QuotaDriver.Quota.  The quota of a devicemapper
+// volume is the size of its device, which is grown online by Resize.
+func (d *DeviceMapperDriver) Quota(volumeName string) (*volume.Quota, error) {
+	vol, err := d.getVolume(volumeName, false)
+	if err != nil {
+		return nil, err
+	}
+	size, err := d.deviceSize(vol.deviceHash())
+	if err != nil {
+		return nil, err
+	}
+	return &volume.Quota{Limit: size, Used: volume.FilesystemBytesUsed(vol.Path())}, nil
+}
+
 func (d *DeviceMapperDriver) resize(deviceHash string, size uint64) error {
 
 	// Get the current size of the device
@@ -831,6 +845,8 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 				}
 			} else if strings.HasPrefix(option, "enablelvmmonitoring=") {
 				enableLVMMonitoring = strings.TrimPrefix(option, "enablelvmmonitoring=")
+			} else if strings.HasPrefix(option, "exportconcurrency=") {
+				// Used by Export
 			} else {
 				glog.Errorf("Unable to parse option %s", option)
 				return ErrInvalidOption
@@ -847,7 +863,7 @@ func (d *DeviceMapperDriver) ensureInitialized() error {
 			if _, thinError := err.(devmapper.ThinpoolInitError); thinError {
 				//Try recreating the base image because sometimes something deletes it
 				glog.Errorf("Error intializing thin pool device, %s, attempting to create to new base device", err)
-				deviceSet, err = devmapper.NewDeviceSet(poolPath, false, d.options, nil, nil)
+				deviceSet, err = devmapper.NewDeviceSet(poolPath, false, dmoptions, nil, nil)
 				if err != nil {
 					return err
 				}
@@ -1298,7 +1314,9 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 		d.DeviceSet.Unlock()
 	}(v.driver, deviceHash, mountpoint)
 
-	tarOut := tar.NewWriter(writer)
+	pipeOut := newPipelinedWriter(writer)
+	defer pipeOut.Close()
+	tarOut := tar.NewWriter(pipeOut)
 
 	// Set the driver type
 	drivertype := []byte(v.Driver().DriverType())
@@ -1339,11 +1357,14 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 	if err := exportDirectoryAsTar(mdpath, fmt.Sprintf("%s-metadata", label), tarOut, []string{}); err != nil {
 		return err
 	}
-	if err := exportDirectoryAsTar(mountpoint, fmt.Sprintf("%s-volume", label), tarOut, excludes); err != nil {
+	if err := exportVolumeAsTar(mountpoint, fmt.Sprintf("%s-volume", label), tarOut, excludes, v.driver.exportConcurrency()); err != nil {
 		return err
 	}
 
-	return tarOut.Close()
+	if err := tarOut.Close(); err != nil {
+		return err
+	}
+	return pipeOut.Close()
 }
 
 func (d *DeviceMapperDriver) Status() (volume.Status, error) {
@@ -1795,36 +1816,11 @@ func resize2fs(dmDevice string) error {
 }
 
 func exportDirectoryAsTar(path, prefix string, out *tar.Writer, excludes []string) error {
-	cmdString := []string{"-C", path, "-cf", "-", "--transform", fmt.Sprintf("s,^,%s/,", prefix)}
-	for _, excludeDir := range excludes {
-		cmdString = append(cmdString, []string{"--exclude", excludeDir, "--exclude", fmt.Sprintf(".%s.serviced.initialized", excludeDir)}...)
-	}
-	cmdString = append(cmdString, ".")
-	cmd := exec.Command("tar", cmdString...)
-	defer cmd.Wait()
-	pipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
-	}
-	defer pipe.Close()
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-	tarReader := tar.NewReader(pipe)
-	for {
-		hdr, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
+	return streamDirectoryAsTar(path, prefix, []string{"."}, excludes, func(hdr *tar.Header, r io.Reader) error {
 		if err := out.WriteHeader(hdr); err != nil {
 			return err
 		}
-		if _, err := io.Copy(out, tarReader); err != nil {
-			return err
-		}
-	}
-	return nil
+		_, err := io.Copy(out, r)
+		return err
+	})
 }
@@ -0,0 +1,300 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && !darwin
+// +build linux,!darwin
+
+package devicemapper
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zenoss/glog"
+)
+
+const (
+	// DefaultExportConcurrency is the number of application directories of
+	// a volume that are exported at the same time, unless the
+	// exportconcurrency option says otherwise.
+	DefaultExportConcurrency = 4
+
+	// exportEntryBuffer is the largest file that a worker reads ahead while
+	// another worker is writing to the export.  Larger files are streamed.
+	exportEntryBuffer = 4 << 20
+
+	// exportChunkSize and exportChunks size the buffers between the tar
+	// stream and the compressing writer of the export.
+	exportChunkSize = 1 << 20
+	exportChunks    = 4
+)
+
+// exportConcurrency returns the value of the exportconcurrency option if it
+// is set, otherwise returns DefaultExportConcurrency.
+func (d *DeviceMapperDriver) exportConcurrency() int {
+	for _, option := range d.options {
+		if strings.HasPrefix(option, "exportconcurrency=") {
+			if n, err := strconv.Atoi(strings.TrimPrefix(option, "exportconcurrency=")); err == nil && n > 0 {
+				return n
+			}
+			glog.Warningf("Ignoring invalid option %s", option)
+		}
+	}
+	return DefaultExportConcurrency
+}
+
+// tarEntryWriter writes an entry read from a tar stream
+type tarEntryWriter func(hdr *tar.Header, r io.Reader) error
+
+// streamDirectoryAsTar runs tar on the members of the directory at path and
+// passes each entry to out, with its name under prefix.
+func streamDirectoryAsTar(path, prefix string, members, excludes []string, out tarEntryWriter) error {
+	cmdString := []string{"-C", path, "-cf", "-", "--transform", fmt.Sprintf("s,^,%s/,", prefix)}
+	for _, excludeDir := range excludes {
+		cmdString = append(cmdString, []string{"--exclude", excludeDir, "--exclude", fmt.Sprintf(".%s.serviced.initialized", excludeDir)}...)
+	}
+	cmdString = append(cmdString, members...)
+	cmd := exec.Command("tar", cmdString...)
+	defer cmd.Wait()
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	defer pipe.Close()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	tarReader := tar.NewReader(pipe)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := out(hdr, tarReader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isExcludedMember returns true if a top level entry of a volume matches one
+// of the export excludes.
+func isExcludedMember(name string, excludes []string) bool {
+	for _, excludeDir := range excludes {
+		for _, pattern := range []string{excludeDir, fmt.Sprintf(".%s.serviced.initialized", excludeDir)} {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exportVolumeAsTar writes the contents of the volume at path to out.  The
+// application directories at the top of the volume are read by up to
+// concurrency tar processes at the same time, so their entries are
+// interleaved in the export.  Each directory is still written parents first,
+// which is all that an import needs.
+func exportVolumeAsTar(path, prefix string, out *tar.Writer, excludes []string, concurrency int) error {
+	if concurrency <= 1 {
+		return exportDirectoryAsTar(path, prefix, out, excludes)
+	}
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	// The volume root and the files beside the application directories are
+	// exported first, then the directories are spread over the workers.
+	members := []string{"--no-recursion", "."}
+	var dirs []string
+	for _, fi := range fis {
+		if isExcludedMember(fi.Name(), excludes) {
+			continue
+		}
+		if fi.IsDir() {
+			dirs = append(dirs, "./"+fi.Name())
+		} else {
+			members = append(members, "./"+fi.Name())
+		}
+	}
+	if len(dirs) < 2 {
+		return exportDirectoryAsTar(path, prefix, out, excludes)
+	}
+	if concurrency > len(dirs) {
+		concurrency = len(dirs)
+	}
+
+	var (
+		mu        sync.Mutex
+		exportErr error
+	)
+	write := func(hdr *tar.Header, r io.Reader) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if exportErr != nil {
+			// Another worker failed, so stop this one too
+			return exportErr
+		}
+		if err := out.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := io.Copy(out, r)
+		return err
+	}
+	if err := streamDirectoryAsTar(path, prefix, members, excludes, write); err != nil {
+		return err
+	}
+	glog.V(2).Infof("Exporting %d directories of %s with %d workers", len(dirs), path, concurrency)
+
+	// Read ahead small files while another worker holds the writer
+	readAhead := func(hdr *tar.Header, r io.Reader) error {
+		if hdr.Size > exportEntryBuffer {
+			return write(hdr, r)
+		}
+		buf := &bytes.Buffer{}
+		if _, err := buf.ReadFrom(r); err != nil {
+			return err
+		}
+		return write(hdr, buf)
+	}
+
+	jobs := make(chan string)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dir := range jobs {
+				err := streamDirectoryAsTar(path, prefix, []string{dir}, excludes, readAhead)
+				mu.Lock()
+				if err != nil && exportErr == nil {
+					glog.Errorf("Could not export directory %s of %s: %s", dir, path, err)
+					exportErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, dir := range dirs {
+		mu.Lock()
+		failed := exportErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		jobs <- dir
+	}
+	close(jobs)
+	wg.Wait()
+	return exportErr
+}
+
+// pipelinedWriter hands the export to the underlying writer in chunks from
+// a goroutine, so that compressing and writing the backup overlaps with
+// reading the volume.
+type pipelinedWriter struct {
+	w      io.Writer
+	buf    []byte
+	chunks chan []byte
+	free   chan []byte
+	done   chan struct{}
+	closed bool
+	mu     sync.Mutex
+	err    error
+}
+
+// newPipelinedWriter starts writing chunks to w.  Close must be called to
+// flush the last chunk and stop the goroutine.
+func newPipelinedWriter(w io.Writer) *pipelinedWriter {
+	p := &pipelinedWriter{
+		w:      w,
+		chunks: make(chan []byte, exportChunks),
+		free:   make(chan []byte, exportChunks+1),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i <= exportChunks; i++ {
+		p.free <- make([]byte, 0, exportChunkSize)
+	}
+	p.buf = <-p.free
+	go func(chunks <-chan []byte) {
+		defer close(p.done)
+		for chunk := range chunks {
+			if p.writeErr() == nil {
+				if _, err := p.w.Write(chunk); err != nil {
+					p.mu.Lock()
+					p.err = err
+					p.mu.Unlock()
+				}
+			}
+			p.free <- chunk[:0]
+		}
+	}(p.chunks)
+	return p
+}
+
+// writeErr returns the first error of the underlying writer
+func (p *pipelinedWriter) writeErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Write implements io.Writer
+func (p *pipelinedWriter) Write(data []byte) (int, error) {
+	n := 0
+	for len(data) > 0 {
+		if err := p.writeErr(); err != nil {
+			return n, err
+		}
+		size := exportChunkSize - len(p.buf)
+		if size > len(data) {
+			size = len(data)
+		}
+		p.buf = append(p.buf, data[:size]...)
+		data = data[size:]
+		n += size
+		if len(p.buf) == exportChunkSize {
+			p.chunks <- p.buf
+			p.buf = <-p.free
+		}
+	}
+	return n, nil
+}
+
+// Close flushes the buffered data and returns the first error of the
+// underlying writer.  Closing the writer again is a no-op.
+func (p *pipelinedWriter) Close() error {
+	if p.closed {
+		return p.writeErr()
+	}
+	if len(p.buf) > 0 {
+		p.chunks <- p.buf
+	}
+	p.buf = nil
+	close(p.chunks)
+	p.closed = true
+	<-p.done
+	return p.writeErr()
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit && linux && !darwin
+// +build unit,linux,!darwin
+
+package devicemapper
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+type ExportSuite struct{}
+
+var _ = Suite(&ExportSuite{})
+
+// exportNames exports the directory and returns the names of the entries
+func exportNames(c *C, path string, excludes []string, concurrency int) []string {
+	buf := &bytes.Buffer{}
+	pipeOut := newPipelinedWriter(buf)
+	tarOut := tar.NewWriter(pipeOut)
+	err := exportVolumeAsTar(path, "label-volume", tarOut, excludes, concurrency)
+	c.Assert(err, IsNil)
+	c.Assert(tarOut.Close(), IsNil)
+	c.Assert(pipeOut.Close(), IsNil)
+
+	var names []string
+	seen := map[string]bool{}
+	tarIn := tar.NewReader(buf)
+	for {
+		hdr, err := tarIn.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		// Every entry comes after its parent directory
+		name := strings.TrimSuffix(hdr.Name, "/")
+		if parent := name[:strings.LastIndex(name, "/")+1]; parent != "label-volume/" {
+			c.Check(seen[parent], Equals, true, Commentf("%s before its parent", hdr.Name))
+		}
+		seen[hdr.Name] = true
+		if hdr.Typeflag == tar.TypeReg {
+			data, err := ioutil.ReadAll(tarIn)
+			c.Assert(err, IsNil)
+			c.Check(string(data), Equals, filepath.Base(hdr.Name))
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *ExportSuite) TestExportVolumeAsTar(c *C) {
+	path := c.MkDir()
+	for _, file := range []string{"app1/a", "app1/sub/b", "app2/c", "app3/d", "exclude/e", "f"} {
+		filename := filepath.Join(path, file)
+		c.Assert(os.MkdirAll(filepath.Dir(filename), 0755), IsNil)
+		c.Assert(ioutil.WriteFile(filename, []byte(filepath.Base(file)), 0644), IsNil)
+	}
+	expected := []string{
+		"label-volume/./",
+		"label-volume/./app1/",
+		"label-volume/./app1/a",
+		"label-volume/./app1/sub/",
+		"label-volume/./app1/sub/b",
+		"label-volume/./app2/",
+		"label-volume/./app2/c",
+		"label-volume/./app3/",
+		"label-volume/./app3/d",
+		"label-volume/./f",
+	}
+	c.Check(exportNames(c, path, []string{"exclude"}, 1), DeepEquals, expected)
+	c.Check(exportNames(c, path, []string{"exclude"}, 2), DeepEquals, expected)
+	c.Check(exportNames(c, path, []string{"exclude"}, 8), DeepEquals, expected)
+}
+
+func (s *ExportSuite) TestExportConcurrency(c *C) {
+	d := &DeviceMapperDriver{}
+	c.Check(d.exportConcurrency(), Equals, DefaultExportConcurrency)
+	d.options = []string{"dm.basesize=10G", "exportconcurrency=8"}
+	c.Check(d.exportConcurrency(), Equals, 8)
+	d.options = []string{"exportconcurrency=none"}
+	c.Check(d.exportConcurrency(), Equals, DefaultExportConcurrency)
+}
+
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n += len(p); w.n > exportChunkSize {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+func (s *ExportSuite) TestPipelinedWriter(c *C) {
+	buf := &bytes.Buffer{}
+	p := newPipelinedWriter(buf)
+	data := bytes.Repeat([]byte("0123456789"), exportChunkSize/4)
+	n, err := p.Write(data)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, len(data))
+	c.Assert(p.Close(), IsNil)
+	c.Assert(p.Close(), IsNil)
+	c.Check(buf.Bytes(), DeepEquals, data)
+
+	// Errors of the underlying writer are returned
+	p = newPipelinedWriter(&failingWriter{})
+	for i := 0; i < 2*exportChunks+4 && err == nil; i++ {
+		_, err = p.Write(data)
+	}
+	c.Check(p.Close(), ErrorMatches, "disk full")
+}
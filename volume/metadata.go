@@ -0,0 +1,182 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+var (
+	// ErrUnknownMetadata is returned when a kind of snapshot metadata has
+	// not been registered.
+	ErrUnknownMetadata = errors.New("unknown snapshot metadata kind")
+	// ErrMetadataVersion is returned when a snapshot metadata document was
+	// written by a newer version of serviced.
+	ErrMetadataVersion = errors.New("snapshot metadata version is not supported")
+)
+
+// MetadataSchema describes a kind of document that is stored with a
+// snapshot.
+type MetadataSchema struct {
+	// Name is the path of the document in the snapshot.
+	Name string
+	// Version is the version of the documents that are written.
+	Version int
+	// Migrations upgrade the data of a document from the version at their
+	// index to the next version.  Documents that were written before
+	// metadata was versioned are version 0.
+	Migrations []func(data json.RawMessage) (json.RawMessage, error)
+	// Validate checks a document before it is written and after it is read.
+	Validate func(v interface{}) error
+}
+
+// metadataDocument is the envelope of a snapshot metadata document.
+type metadataDocument struct {
+	Kind    string          `json:"kind"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+var (
+	metadataMu      sync.RWMutex
+	metadataSchemas = make(map[string]MetadataSchema)
+)
+
+// RegisterMetadata registers the schema of a kind of snapshot metadata.
+// Each version after the first must have a migration.
+func RegisterMetadata(kind string, schema MetadataSchema) error {
+	if kind == "" || schema.Name == "" {
+		return errors.New("snapshot metadata requires a kind and a name")
+	}
+	if len(schema.Migrations) != schema.Version {
+		return fmt.Errorf("snapshot metadata %s version %d requires %d migrations", kind, schema.Version, schema.Version)
+	}
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+	metadataSchemas[kind] = schema
+	return nil
+}
+
+func getMetadataSchema(kind string) (MetadataSchema, error) {
+	metadataMu.RLock()
+	defer metadataMu.RUnlock()
+	schema, ok := metadataSchemas[kind]
+	if !ok {
+		return MetadataSchema{}, ErrUnknownMetadata
+	}
+	return schema, nil
+}
+
+// WriteSnapshotMetadata validates a document and writes it as the current
+// version of its kind to the snapshot with the given label.
+func WriteSnapshotMetadata(vol Volume, label, kind string, v interface{}) error {
+	schema, err := getMetadataSchema(kind)
+	if err != nil {
+		return err
+	}
+	if schema.Validate != nil {
+		if err := schema.Validate(v); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	w, err := vol.WriteMetadata(label, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	return json.NewEncoder(w).Encode(metadataDocument{
+		Kind:    kind,
+		Version: schema.Version,
+		Data:    data,
+	})
+}
+
+// ReadSnapshotMetadata reads a document of the given kind from the snapshot
+// with the given label into v, migrating it from the version that it was
+// written as.
+func ReadSnapshotMetadata(vol Volume, label, kind string, v interface{}) error {
+	schema, err := getMetadataSchema(kind)
+	if err != nil {
+		return err
+	}
+
+	r, err := vol.ReadMetadata(label, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return decodeSnapshotMetadata(r, kind, schema, v)
+}
+
+// DecodeSnapshotMetadata reads a document of the given kind that was copied
+// out of a snapshot into v, migrating it from the version that it was
+// written as.
+func DecodeSnapshotMetadata(r io.Reader, kind string, v interface{}) error {
+	schema, err := getMetadataSchema(kind)
+	if err != nil {
+		return err
+	}
+	return decodeSnapshotMetadata(r, kind, schema, v)
+}
+
+func decodeSnapshotMetadata(r io.Reader, kind string, schema MetadataSchema, v interface{}) error {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	doc, err := decodeMetadata(kind, raw)
+	if err != nil {
+		return err
+	}
+	if doc.Version > schema.Version {
+		return ErrMetadataVersion
+	}
+	for ; doc.Version < schema.Version; doc.Version++ {
+		if doc.Data, err = schema.Migrations[doc.Version](doc.Data); err != nil {
+			return fmt.Errorf("could not migrate snapshot metadata %s from version %d: %s", kind, doc.Version, err)
+		}
+	}
+
+	if err := json.Unmarshal(doc.Data, v); err != nil {
+		return err
+	}
+	if schema.Validate != nil {
+		return schema.Validate(v)
+	}
+	return nil
+}
+
+// decodeMetadata returns the envelope of a document.  Documents without one
+// were written before metadata was versioned, and are version 0.
+func decodeMetadata(kind string, raw []byte) (*metadataDocument, error) {
+	doc := &metadataDocument{}
+	if err := json.Unmarshal(raw, doc); err != nil || doc.Kind == "" {
+		return &metadataDocument{Data: raw}, nil
+	}
+	if doc.Kind != kind {
+		return nil, fmt.Errorf("snapshot metadata is %s, not %s", doc.Kind, kind)
+	}
+	return doc, nil
+}
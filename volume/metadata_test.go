@@ -0,0 +1,132 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package volume_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+type MetadataSuite struct{}
+
+var _ = Suite(&MetadataSuite{})
+
+type metadataBuffer struct {
+	*bytes.Buffer
+}
+
+func (b metadataBuffer) Close() error { return nil }
+
+type testMetadata struct {
+	Names []string
+}
+
+func (s *MetadataSuite) SetUpSuite(c *C) {
+	err := RegisterMetadata("test", MetadataSchema{
+		Name:    "test.json",
+		Version: 1,
+		Migrations: []func(json.RawMessage) (json.RawMessage, error){
+			// version 0 was a plain list of names
+			func(data json.RawMessage) (json.RawMessage, error) {
+				var names []string
+				if err := json.Unmarshal(data, &names); err != nil {
+					return nil, err
+				}
+				return json.Marshal(testMetadata{Names: names})
+			},
+		},
+		Validate: func(v interface{}) error {
+			if len(v.(*testMetadata).Names) == 0 {
+				return errors.New("no names")
+			}
+			return nil
+		},
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *MetadataSuite) TestRegisterMetadata(c *C) {
+	err := RegisterMetadata("bad", MetadataSchema{Name: "bad.json", Version: 2})
+	c.Assert(err, NotNil)
+}
+
+func (s *MetadataSuite) TestWriteRead(c *C) {
+	buf := metadataBuffer{&bytes.Buffer{}}
+	vol := &mocks.Volume{}
+	vol.On("WriteMetadata", "LABEL", "test.json").Return(buf, nil)
+	vol.On("ReadMetadata", "LABEL", "test.json").Return(buf, nil)
+
+	err := WriteSnapshotMetadata(vol, "LABEL", "test", &testMetadata{Names: []string{"a"}})
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(buf.String(), `"version":1`), Equals, true)
+
+	actual := &testMetadata{}
+	err = ReadSnapshotMetadata(vol, "LABEL", "test", actual)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Names, DeepEquals, []string{"a"})
+}
+
+func (s *MetadataSuite) TestWrite_Invalid(c *C) {
+	vol := &mocks.Volume{}
+	err := WriteSnapshotMetadata(vol, "LABEL", "test", &testMetadata{})
+	c.Assert(err, NotNil)
+	err = WriteSnapshotMetadata(vol, "LABEL", "unknown", &testMetadata{})
+	c.Assert(err, Equals, ErrUnknownMetadata)
+}
+
+func (s *MetadataSuite) TestRead_Unversioned(c *C) {
+	// documents without an envelope are migrated from version 0
+	vol := &mocks.Volume{}
+	vol.On("ReadMetadata", "LABEL", "test.json").Return(ioutil.NopCloser(strings.NewReader(`["a","b"]`)), nil)
+
+	actual := &testMetadata{}
+	err := ReadSnapshotMetadata(vol, "LABEL", "test", actual)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Names, DeepEquals, []string{"a", "b"})
+}
+
+func (s *MetadataSuite) TestRead_Newer(c *C) {
+	vol := &mocks.Volume{}
+	vol.On("ReadMetadata", "LABEL", "test.json").Return(ioutil.NopCloser(strings.NewReader(`{"kind":"test","version":2,"data":{}}`)), nil)
+
+	err := ReadSnapshotMetadata(vol, "LABEL", "test", &testMetadata{})
+	c.Assert(err, Equals, ErrMetadataVersion)
+}
+
+func (s *MetadataSuite) TestRead_WrongKind(c *C) {
+	vol := &mocks.Volume{}
+	vol.On("ReadMetadata", "LABEL", "test.json").Return(ioutil.NopCloser(strings.NewReader(`{"kind":"other","version":1,"data":{}}`)), nil)
+
+	err := ReadSnapshotMetadata(vol, "LABEL", "test", &testMetadata{})
+	c.Assert(err, NotNil)
+}
+
+func (s *MetadataSuite) TestDecode(c *C) {
+	actual := &testMetadata{}
+	err := DecodeSnapshotMetadata(strings.NewReader(`["a"]`), "test", actual)
+	c.Assert(err, IsNil)
+	c.Assert(actual.Names, DeepEquals, []string{"a"})
+
+	err = DecodeSnapshotMetadata(strings.NewReader(`["a"]`), "unknown", actual)
+	c.Assert(err, Equals, ErrUnknownMetadata)
+}
@@ -56,6 +56,17 @@ func (m *Driver) Resize(volumeName string, size uint64) error {
 
 	return r0
 }
+func (m *Driver) Quota(volumeName string) (*volume.Quota, error) {
+	ret := m.Called(volumeName)
+
+	var r0 *volume.Quota
+	if ret.Get(0) != nil {
+		r0 = ret.Get(0).(*volume.Quota)
+	}
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
 func (m *Driver) GetTenant(volumeName string) (volume.Volume, error) {
 	ret := m.Called(volumeName)
 
@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package volume
+
+import "errors"
+
+var (
+	// ErrQuotaNotSupported is returned when the driver cannot limit the size
+	// of a volume.
+	ErrQuotaNotSupported = errors.New("driver does not support volume quotas")
+	// ErrQuotaTooSmall is returned when the requested quota is smaller than
+	// the data already stored in the volume.
+	ErrQuotaTooSmall = errors.New("quota is smaller than the volume usage")
+)
+
+// QuotaThresholds are the percentages of a volume quota that raise a
+// warning once the volume usage reaches them.
+var QuotaThresholds = []int{80, 90}
+
+// Quota describes the size limit of a volume and how much of it is used.
+type Quota struct {
+	Limit uint64
+	Used  uint64
+}
+
+// Percent returns the percentage of the quota that is used.
+func (q Quota) Percent() float64 {
+	if q.Limit == 0 {
+		return 0
+	}
+	return float64(q.Used) * 100 / float64(q.Limit)
+}
+
+// Threshold returns the highest quota threshold that the volume usage has
+// reached, or 0 if it is below all of them.
+func (q Quota) Threshold() int {
+	if q.Limit == 0 {
+		return 0
+	}
+	threshold, percent := 0, q.Percent()
+	for _, t := range QuotaThresholds {
+		if percent >= float64(t) && t > threshold {
+			threshold = t
+		}
+	}
+	return threshold
+}
+
+// QuotaDriver is implemented by drivers that reject writes past the size
+// set on a volume by Driver.Resize.
+type QuotaDriver interface {
+	// Quota returns the size limit and usage of a volume.
+	Quota(volumeName string) (*Quota, error)
+}
+
+// GetQuota returns the quota of a volume, or ErrQuotaNotSupported if the
+// driver does not enforce quotas.
+func GetQuota(driver Driver, volumeName string) (*Quota, error) {
+	qd, ok := driver.(QuotaDriver)
+	if !ok {
+		return nil, ErrQuotaNotSupported
+	}
+	return qd.Quota(volumeName)
+}
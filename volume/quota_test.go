@@ -0,0 +1,49 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package volume_test
+
+import (
+	. "github.com/control-center/serviced/volume"
+	"github.com/control-center/serviced/volume/mocks"
+	. "gopkg.in/check.v1"
+)
+
+type QuotaSuite struct{}
+
+var _ = Suite(&QuotaSuite{})
+
+func (s *QuotaSuite) TestThreshold(c *C) {
+	c.Assert(Quota{Limit: 0, Used: 10}.Threshold(), Equals, 0)
+	c.Assert(Quota{Limit: 100, Used: 79}.Threshold(), Equals, 0)
+	c.Assert(Quota{Limit: 100, Used: 80}.Threshold(), Equals, 80)
+	c.Assert(Quota{Limit: 100, Used: 89}.Threshold(), Equals, 80)
+	c.Assert(Quota{Limit: 100, Used: 90}.Threshold(), Equals, 90)
+	c.Assert(Quota{Limit: 100, Used: 120}.Threshold(), Equals, 90)
+}
+
+func (s *QuotaSuite) TestGetQuotaNotSupported(c *C) {
+	quota, err := GetQuota(struct{ Driver }{}, "tenant")
+	c.Assert(err, Equals, ErrQuotaNotSupported)
+	c.Assert(quota, IsNil)
+}
+
+func (s *QuotaSuite) TestGetQuota(c *C) {
+	driver := &mocks.Driver{}
+	driver.On("Quota", "tenant").Return(&Quota{Limit: 100, Used: 50}, nil)
+	quota, err := GetQuota(driver, "tenant")
+	c.Assert(err, IsNil)
+	c.Assert(*quota, Equals, Quota{Limit: 100, Used: 50})
+}
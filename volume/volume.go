@@ -138,9 +138,13 @@ type Volume interface {
 	Snapshot(label, message string, tags []string) (err error)
 	// SnapshotInfo returns general information about a particular snapshot
 	SnapshotInfo(label string) (*SnapshotInfo, error)
-	// WriteMetadata returns a handle to write metadata to a snapshot
+	// WriteMetadata returns a handle to write metadata to a snapshot.  Only
+	// drivers use it directly; other components write typed documents with
+	// WriteSnapshotMetadata.
 	WriteMetadata(label, name string) (io.WriteCloser, error)
-	// ReadMetadata returns a handle to read metadata from a snapshot
+	// ReadMetadata returns a handle to read metadata from a snapshot.  Only
+	// drivers use it directly; other components read typed documents with
+	// ReadSnapshotMetadata.
 	ReadMetadata(label, name string) (io.ReadCloser, error)
 	// Snapshots lists all snapshots of this volume
 	Snapshots() ([]string, error)
@@ -0,0 +1,193 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/acme"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/facade"
+)
+
+const (
+	// acmeRenewWindow is how long before it expires that a certificate is
+	// renewed
+	acmeRenewWindow = 30 * 24 * time.Hour
+
+	// acmeCheckInterval is how often the vhosts are checked for certificates
+	// to obtain or renew
+	acmeCheckInterval = time.Hour
+)
+
+// ACMEManager obtains certificates for the enabled vhost public endpoints
+// from an ACME certificate authority, and renews them before they expire.
+// The certificates are stored with the facade and presented by the https
+// server as soon as they are obtained.
+type ACMEManager struct {
+	client     *acme.Client
+	solver     *acme.HTTPSolver
+	issuer     string
+	email      string
+	domain     string
+	facade     facade.FacadeInterface
+	certs      *certificateCache
+	registered bool
+}
+
+// NewACMEManager returns a manager of the vhost certificates of the account
+// with the key file at the certificate authority of the directory url.
+// Vhost names without a dot are qualified with the domain.  The certificates
+// are presented from the cache.
+func NewACMEManager(directoryURL, email, domain, accountKeyFile string, facade facade.FacadeInterface, certs *certificateCache) (*ACMEManager, error) {
+	key, err := acme.LoadOrCreateKey(accountKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &ACMEManager{
+		client: acme.NewClient(directoryURL, key),
+		solver: acme.NewHTTPSolver(),
+		issuer: directoryURL,
+		email:  email,
+		domain: strings.ToLower(strings.Trim(domain, ".")),
+		facade: facade,
+		certs:  certs,
+	}, nil
+}
+
+// Run loads the stored certificates, and then obtains and renews
+// certificates until shutdown
+func (m *ACMEManager) Run(shutdown <-chan interface{}) {
+	ctx := datastore.Get()
+	m.load(ctx)
+	for {
+		m.renew(ctx)
+		select {
+		case <-time.After(acmeCheckInterval):
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// hostName returns the host name of the certificate of a vhost, or an empty
+// string if the vhost cannot have one
+func (m *ACMEManager) hostName(vhost string) string {
+	vhost = strings.ToLower(vhost)
+	if strings.Contains(vhost, ".") {
+		return vhost
+	} else if m.domain == "" {
+		return ""
+	}
+	return vhost + "." + m.domain
+}
+
+// load adds the stored certificates to the cache
+func (m *ACMEManager) load(ctx datastore.Context) {
+	certs, err := m.facade.GetCertificates(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not load vhost certificates")
+		return
+	}
+	for _, c := range certs {
+		if c.Issuer == certificate.IssuerCustom {
+			continue
+		}
+		logger := plog.WithField("hostname", c.Name)
+		certPEM, keyPEM, err := m.facade.GetCertificateKeyPair(ctx, c.Name)
+		if err != nil {
+			logger.WithError(err).Warn("Could not load vhost certificate")
+			continue
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			logger.WithError(err).Warn("Stored vhost certificate is not valid")
+			continue
+		}
+		m.certs.Set(c.Name, &cert)
+	}
+	plog.WithField("count", len(certs)).Debug("Loaded vhost certificates")
+}
+
+// renew obtains a certificate for each enabled vhost that does not have one,
+// or whose certificate is about to expire.  Vhosts with an uploaded
+// certificate are left alone.
+func (m *ACMEManager) renew(ctx datastore.Context) {
+	endpoints, err := m.facade.GetAllPublicEndpoints(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up vhosts to renew certificates")
+		return
+	}
+	certs, err := m.facade.GetCertificates(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up vhost certificates")
+		return
+	}
+	stored := make(map[string]certificate.Certificate)
+	for _, c := range certs {
+		stored[c.Name] = c
+	}
+
+	now := time.Now()
+	done := make(map[string]struct{})
+	for _, ep := range endpoints {
+		if ep.VHostName == "" || !ep.Enabled || ep.Certificate != "" {
+			continue
+		}
+		name := m.hostName(ep.VHostName)
+		if _, ok := done[name]; ok || name == "" {
+			continue
+		}
+		done[name] = struct{}{}
+		if c, ok := stored[name]; ok && !c.NeedsRenewal(now, acmeRenewWindow) {
+			continue
+		}
+		logger := plog.WithFields(logrus.Fields{
+			"hostname":  name,
+			"serviceid": ep.ServiceID,
+		})
+		if err := m.obtain(ctx, name); err != nil {
+			logger.WithError(err).Warn("Could not obtain vhost certificate; will retry")
+		} else {
+			logger.Info("Obtained vhost certificate")
+		}
+	}
+}
+
+// obtain orders a certificate for the host name, stores it and presents it
+func (m *ACMEManager) obtain(ctx datastore.Context, name string) error {
+	if !m.registered {
+		if err := m.client.Register(m.email); err != nil {
+			return err
+		}
+		m.registered = true
+	}
+	certPEM, keyPEM, err := m.client.ObtainCertificate([]string{name}, m.solver)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	if err := m.facade.SetCertificate(ctx, name, m.issuer, certPEM, keyPEM); err != nil {
+		return err
+	}
+	m.certs.Set(name, &cert)
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package web
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/control-center/serviced/acme"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func testACMEKeyPair(c *C, name string, notAfter time.Time) ([]byte, []byte) {
+	key, err := acme.GenerateKey()
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{name},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyPEM, err := acme.EncodeKey(key)
+	c.Assert(err, IsNil)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM
+}
+
+func (s *TestWebSuite) TestACMEManager_HostName(c *C) {
+	m := &ACMEManager{}
+	c.Assert(m.hostName("App.Example.com"), Equals, "app.example.com")
+	c.Assert(m.hostName("app"), Equals, "")
+	m.domain = "example.com"
+	c.Assert(m.hostName("app"), Equals, "app.example.com")
+}
+
+func (s *TestWebSuite) TestACMEManager_LoadAndRenew(c *C) {
+	// the certificate authority is down
+	ca := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ca.Close()
+	key, err := acme.GenerateKey()
+	c.Assert(err, IsNil)
+	m := &ACMEManager{
+		client: acme.NewClient(ca.URL+"/directory", key),
+		solver: acme.NewHTTPSolver(),
+		domain: "example.com",
+		facade: s.mockFacade,
+		certs:  newCertificateCache(),
+	}
+	ctx := datastore.Get()
+
+	notAfter := time.Now().Add(60 * 24 * time.Hour)
+	certPEM, keyPEM := testACMEKeyPair(c, "app.example.com", notAfter)
+	s.mockFacade.On("GetCertificates", mock.Anything).Return([]certificate.Certificate{
+		{Name: "app.example.com", NotAfter: notAfter},
+	}, nil)
+	s.mockFacade.On("GetCertificateKeyPair", mock.Anything, "app.example.com").Return(certPEM, keyPEM, nil)
+	s.mockFacade.On("GetAllPublicEndpoints", mock.Anything).Return([]service.PublicEndpoint{
+		{ServiceID: "svc1", VHostName: "app", Enabled: true},
+		{ServiceID: "svc2", VHostName: "new", Enabled: true},
+		{ServiceID: "svc3", VHostName: "off", Enabled: false},
+		{ServiceID: "svc5", VHostName: "custom", Enabled: true, Certificate: "custom"},
+		{ServiceID: "svc4", PortAddress: ":1234", Enabled: true},
+	}, nil)
+
+	m.load(ctx)
+	cert, err := m.certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "APP.example.com."})
+	c.Assert(err, IsNil)
+	c.Assert(cert, NotNil)
+	cert, err = m.certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert, IsNil)
+
+	// only the vhost without a certificate is ordered, and it fails
+	m.renew(ctx)
+	c.Assert(m.registered, Equals, false)
+	s.mockFacade.AssertNotCalled(c, "SetCertificate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -18,20 +18,45 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/host"
 	"github.com/zenoss/go-json-rest"
 )
 
+// changeTokenHeader is the response header holding the change token of a
+// listing.  Passing it back as the "token" query parameter returns only the
+// entities modified since.
+const changeTokenHeader = "X-Change-Token"
+
 // getPools returns the list of pools requested.
 func getHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 
+	since, err := domain.ParseChangeToken(r.URL.Query().Get("token"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
 	hosts, err := facade.GetReadHosts(dataCtx)
 	if err != nil {
 		restServerError(w, err)
 		return
 	}
 
+	if !since.IsZero() {
+		changed := []host.ReadHost{}
+		for _, h := range hosts {
+			if !h.UpdatedAt.Before(since) {
+				changed = append(changed, h)
+			}
+		}
+		hosts = changed
+	}
+
+	w.Header().Set(changeTokenHeader, domain.NewChangeToken(start))
 	w.WriteJson(hosts)
 }
 
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/zenoss/go-json-rest"
 )
@@ -35,12 +36,14 @@ func getAllServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestCon
 		return
 	}
 
+	start := time.Now()
 	details, err := c.getFacade().QueryServiceDetails(ctx, query)
 	if err != nil {
 		restServerError(w, err)
 		return
 	}
 
+	w.Header().Set(changeTokenHeader, domain.NewChangeToken(start))
 	w.WriteJson(details)
 }
 
@@ -233,6 +236,8 @@ func buildQuery(r *rest.Request) (service.Query, error) {
 		query.Tenants = true
 	}
 
+	query.PoolID = r.URL.Query().Get("pool")
+
 	since := r.URL.Query().Get("since")
 	if since != "" {
 		i, err := strconv.ParseInt(since, 10, 64)
@@ -242,5 +247,11 @@ func buildQuery(r *rest.Request) (service.Query, error) {
 		query.Since = time.Duration(i) * time.Millisecond
 	}
 
+	changedSince, err := domain.ParseChangeToken(r.URL.Query().Get("token"))
+	if err != nil {
+		return service.Query{}, err
+	}
+	query.ChangedSince = changedSince
+
 	return query, nil
 }
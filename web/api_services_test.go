@@ -17,6 +17,7 @@ package web
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/control-center/serviced/datastore"
@@ -294,3 +295,19 @@ func (s *TestWebSuite) TestRestQueryServiceDetailsShouldQueryForUpdated(c *C) {
 	query := service.Query{Tags: []string{}, Since: time.Duration(5 * time.Second)}
 	s.mockFacade.AssertCalled(c, "QueryServiceDetails", s.ctx.getDatastoreContext(), query)
 }
+
+func (s *TestWebSuite) TestRestQueryServiceDetailsShouldQueryForChanged(c *C) {
+	s.mockFacade.On("QueryServiceDetails",
+		mock.Anything,
+		mock.AnythingOfType("service.Query")).
+		Return(allServices, nil)
+
+	changedSince := time.Unix(1500000000, 0)
+	request := s.buildRequest("GET", "http://www.example.com/services?token="+strconv.FormatInt(changedSince.UnixNano(), 10), "")
+
+	getAllServiceDetails(&(s.writer), &request, s.ctx)
+
+	query := service.Query{Tags: []string{}, ChangedSince: changedSince}
+	s.mockFacade.AssertCalled(c, "QueryServiceDetails", s.ctx.getDatastoreContext(), query)
+	c.Assert(s.recorder.Header().Get(changeTokenHeader), Not(Equals), "")
+}
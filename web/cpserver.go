@@ -26,9 +26,11 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/acme"
 	"github.com/control-center/serviced/config"
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/master"
@@ -58,6 +60,10 @@ type ServiceConfig struct {
 	uiConfig    UIConfig
 	facade      facade.FacadeInterface
 	vhostmgr    *VHostManager
+	acme        *ACMEManager
+	certs       *certificateCache
+	maintenance *Maintenance
+	maintPage   string
 }
 
 // Auth0Config contains configuration values pertaining to Auth0
@@ -97,9 +103,10 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 		keyPEMFile:  keyPEMFile,
 		uiConfig:    uiCfg,
 		facade:      facade,
+		certs:       newCertificateCache(),
 	}
 
-	hostAddrs, err := utils.GetIPv4Addresses()
+	hostAddrs, err := utils.GetIPAddresses()
 	if err != nil {
 		logger.Fatal(err)
 	}
@@ -115,6 +122,25 @@ func NewServiceConfig(bindPort string, agentPort string, stats bool, hostaliases
 	return &cfg
 }
 
+// EnableACME obtains and renews the certificates of the vhosts from the ACME
+// certificate authority at the directory url.  It must be called before
+// Serve.
+func (sc *ServiceConfig) EnableACME(directoryURL, email, domain, accountKeyFile string) error {
+	m, err := NewACMEManager(directoryURL, email, domain, accountKeyFile, sc.facade, sc.certs)
+	if err != nil {
+		return err
+	}
+	sc.acme = m
+	return nil
+}
+
+// SetMaintenancePage sets the html page that the public endpoints of tenants
+// in maintenance mode serve, instead of the built-in page.  It must be called
+// before Serve.
+func (sc *ServiceConfig) SetMaintenancePage(filename string) {
+	sc.maintPage = filename
+}
+
 // borrowed from gorilla mux, which was cleaning the public endpoint urls.
 func cleanPath(p string) string {
 	if p == "" {
@@ -139,6 +165,12 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 	logger := plog.WithField("bindport", sc.bindPort)
 	logger.Debug("Starting vhost synching")
 
+	// serve the maintenance page from the endpoints of tenants in maintenance
+	// mode
+	sc.maintenance = NewMaintenance(sc.maintPage, func(tenantID string) (*service.MaintenanceMode, error) {
+		return sc.facade.GetMaintenanceMode(datastore.Get(), tenantID)
+	})
+
 	// start public port listener
 	sc.startPublicPortListener(shutdown)
 
@@ -198,6 +230,10 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			return
 		}
 		r.URL.Path = cleanPath(r.URL.Path)
+		if r.URL.Path == serviceEventsPath {
+			sc.serveServiceEvents(w, r)
+			return
+		}
 		uiHandler.ServeHTTP(w, r)
 	}
 
@@ -220,6 +256,12 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 
 	go func() {
 		redirect := func(w http.ResponseWriter, req *http.Request) {
+			// The certificate authority validates http-01 challenges on
+			// port 80.
+			if sc.acme != nil && strings.HasPrefix(req.URL.Path, acme.ChallengePath) {
+				sc.acme.solver.ServeHTTP(w, req)
+				return
+			}
 			// bindPort has already been validated, so the Split/access below won't break.
 			http.Redirect(w, req, fmt.Sprintf("https://%s:%s%s", req.Host, strings.Split(sc.bindPort, ":")[1], req.URL), http.StatusMovedPermanently)
 		}
@@ -237,8 +279,19 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			PreferServerCipherSuites: true,
 			CipherSuites:             utils.CipherSuites("http"),
 		}
+		// Vhosts with an uploaded certificate or a certificate from the
+		// certificate authority present it, and everything else presents the
+		// certificate of the files.
+		config.GetCertificate = sc.certs.GetCertificate
+		go runVHostCertificates(sc.facade, sc.certs, shutdown)
+		if sc.acme != nil {
+			go sc.acme.Run(shutdown)
+		}
 		server := &http.Server{Addr: sc.bindPort, TLSConfig: config, Handler: http.HandlerFunc(httphandler)}
-		logger.WithField("ciphersuite", utils.CipherSuitesByName(config)).Info("Creating HTTP server")
+		logger.WithFields(logrus.Fields{
+			"ciphersuite": utils.CipherSuitesByName(config),
+			"minversion":  utils.TLSVersionName(config.MinVersion),
+		}).Info("Creating HTTP server")
 		err := server.ListenAndServeTLS(certFile, keyFile)
 		if err != nil {
 			logger.WithError(err).Error("Could not setup HTTPS webserver")
@@ -435,8 +488,8 @@ type getRoutes func(sc *ServiceConfig) []rest.Route
 // changes in state
 func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 	// set up the public port manager
-	pubmgr := NewPublicPortManager("", sc.certPEMFile, sc.keyPEMFile, func(portAddress string, err error) {
-		logger := plog.WithField("portaddress", portAddress).WithError(err)
+	pubmgr := NewPublicPortManager("", sc.certPEMFile, sc.keyPEMFile, sc.maintenance, func(portName string, err error) {
+		logger := plog.WithField("portaddress", portName).WithError(err)
 
 		// connect to zookeeper
 		conn, err := zzk.GetLocalConnection("/")
@@ -448,7 +501,7 @@ func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 		// get the public port
 		key := registry.PublicPortKey{
 			HostID:      "master",
-			PortAddress: portAddress,
+			PortAddress: portName,
 		}
 		serviceID, application, err := registry.GetPublicPort(conn, key)
 		if err != nil {
@@ -457,6 +510,7 @@ func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 		}
 
 		// disable the public port
+		portAddress, _ := registry.SplitPublicPortName(portName)
 		if err := sc.facade.EnablePublicEndpointPort(datastore.Get(), serviceID, application, portAddress, false); err != nil {
 			logger.WithError(err).Error("Could not disable public port")
 			return
@@ -491,7 +545,7 @@ func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 // startVHostListener manages proxies for all vhosts
 func (sc *ServiceConfig) startVHostListener(shutdown <-chan interface{}) {
 	// set up the vhost manager
-	sc.vhostmgr = NewVHostManager(sc.muxTLS)
+	sc.vhostmgr = NewVHostManager(sc.muxTLS, sc.maintenance)
 
 	// set up the vhost listener
 	listener := registry.NewVHostListener("master", sc.vhostmgr)
@@ -0,0 +1,144 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// maintenanceTTL is how long the public endpoints remember the maintenance
+// mode of a tenant before looking it up again
+const maintenanceTTL = 5 * time.Second
+
+// maintenancePage is the page that the public endpoints of a tenant in
+// maintenance mode serve if no page is configured
+var maintenancePage = template.Must(template.New("maintenance").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Down for maintenance</title>
+</head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 10%;">
+<h1>Down for maintenance</h1>
+<p>{{if .Message}}{{.Message}}{{else}}This application is undergoing maintenance.{{end}}</p>
+<p>Please try again later.</p>
+</body>
+</html>
+`))
+
+// MaintenanceLookup returns the maintenance mode of a tenant, or nil if the
+// tenant is not in maintenance mode
+type MaintenanceLookup func(tenantID string) (*service.MaintenanceMode, error)
+
+// Maintenance serves a maintenance page, instead of proxying the request,
+// from the http public endpoints of tenants that are in maintenance mode
+type Maintenance struct {
+	pageFile string
+	lookup   MaintenanceLookup
+	mu       *sync.Mutex
+	tenants  map[string]maintenanceEntry
+}
+
+type maintenanceEntry struct {
+	mode    *service.MaintenanceMode
+	expires time.Time
+}
+
+// NewMaintenance creates a new maintenance page server.  The page is read
+// from pageFile on every request, so that it can be changed without
+// restarting serviced; the built-in page is served if it is empty.
+func NewMaintenance(pageFile string, lookup MaintenanceLookup) *Maintenance {
+	return &Maintenance{
+		pageFile: pageFile,
+		lookup:   lookup,
+		mu:       &sync.Mutex{},
+		tenants:  make(map[string]maintenanceEntry),
+	}
+}
+
+// Get returns the maintenance mode of a tenant, or nil if the tenant is not
+// in maintenance mode.  If the tenant cannot be looked up, its last known
+// mode is returned.
+func (m *Maintenance) Get(tenantID string) *service.MaintenanceMode {
+	if m == nil || tenantID == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.tenants[tenantID]
+	if ok && time.Now().Before(entry.expires) {
+		return entry.mode
+	}
+
+	mode, err := m.lookup(tenantID)
+	if err != nil {
+		plog.WithField("tenantid", tenantID).WithError(err).Debug("Could not look up the maintenance mode of tenant")
+		mode = entry.mode
+	}
+	m.tenants[tenantID] = maintenanceEntry{mode: mode, expires: time.Now().Add(maintenanceTTL)}
+	return mode
+}
+
+// Serve writes the maintenance page with a 503 status and returns true if the
+// tenant is in maintenance mode, otherwise it returns false and the request
+// should be proxied to the tenant.
+func (m *Maintenance) Serve(tenantID string, w http.ResponseWriter) bool {
+	mode := m.Get(tenantID)
+	if mode == nil {
+		return false
+	}
+
+	logger := plog.WithFields(log.Fields{
+		"tenantid": tenantID,
+		"pagefile": m.pageFile,
+	})
+
+	var page []byte
+	if m.pageFile != "" {
+		var err error
+		if page, err = ioutil.ReadFile(m.pageFile); err != nil {
+			logger.WithError(err).Warn("Could not read maintenance page, serving the built-in page")
+			page = nil
+		}
+	}
+	if page == nil {
+		buf := &bytes.Buffer{}
+		if err := maintenancePage.Execute(buf, mode); err != nil {
+			logger.WithError(err).Error("Could not render maintenance page")
+		}
+		page = buf.Bytes()
+	}
+
+	retryAfter := mode.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = service.DefaultMaintenanceRetryAfter
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write(page)
+	return true
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package web
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/control-center/serviced/domain/service"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestMaintenanceServe(c *C) {
+	lookups := 0
+	m := NewMaintenance("", func(tenantID string) (*service.MaintenanceMode, error) {
+		lookups++
+		if tenantID == "tenant1" {
+			return &service.MaintenanceMode{Message: "Upgrading <now>", RetryAfter: 600}, nil
+		}
+		return nil, nil
+	})
+
+	// tenants that are not in maintenance mode are proxied
+	w := httptest.NewRecorder()
+	c.Assert(m.Serve("tenant2", w), Equals, false)
+	c.Assert(w.Body.Len(), Equals, 0)
+
+	w = httptest.NewRecorder()
+	c.Assert(m.Serve("tenant1", w), Equals, true)
+	c.Assert(w.Code, Equals, http.StatusServiceUnavailable)
+	c.Assert(w.Header().Get("Retry-After"), Equals, "600")
+	c.Assert(w.Header().Get("Content-Type"), Equals, "text/html; charset=utf-8")
+	c.Assert(strings.Contains(w.Body.String(), "Upgrading &lt;now&gt;"), Equals, true)
+
+	// the modes are cached
+	c.Assert(m.Serve("tenant1", httptest.NewRecorder()), Equals, true)
+	c.Assert(lookups, Equals, 2)
+
+	// a nil maintenance server proxies every request
+	var none *Maintenance
+	c.Assert(none.Serve("tenant1", httptest.NewRecorder()), Equals, false)
+}
+
+func (s *TestWebSuite) TestMaintenanceServePageFile(c *C) {
+	dir := c.MkDir()
+	page := filepath.Join(dir, "maintenance.html")
+	err := ioutil.WriteFile(page, []byte("<h1>Back soon</h1>"), 0644)
+	c.Assert(err, IsNil)
+
+	m := NewMaintenance(page, func(tenantID string) (*service.MaintenanceMode, error) {
+		return &service.MaintenanceMode{}, nil
+	})
+	w := httptest.NewRecorder()
+	c.Assert(m.Serve("tenant1", w), Equals, true)
+	c.Assert(w.Header().Get("Retry-After"), Equals, "300")
+	c.Assert(w.Body.String(), Equals, "<h1>Back soon</h1>")
+
+	// the built-in page is served if the page file is gone
+	c.Assert(os.Remove(page), IsNil)
+	w = httptest.NewRecorder()
+	c.Assert(m.Serve("tenant1", w), Equals, true)
+	c.Assert(strings.Contains(w.Body.String(), "Down for maintenance"), Equals, true)
+}
+
+func (s *TestWebSuite) TestMaintenanceGetLookupError(c *C) {
+	fail := false
+	m := NewMaintenance("", func(tenantID string) (*service.MaintenanceMode, error) {
+		if fail {
+			return nil, errors.New("elastic is down")
+		}
+		return &service.MaintenanceMode{Message: "Upgrading"}, nil
+	})
+	c.Assert(m.Get("tenant1"), NotNil)
+
+	// the last known mode is kept if the tenant cannot be looked up
+	fail = true
+	m.tenants["tenant1"] = maintenanceEntry{mode: m.tenants["tenant1"].mode}
+	c.Assert(m.Get("tenant1"), NotNil)
+	c.Assert(m.Get("tenant2"), IsNil)
+}
+
+func (s *TestWebSuite) TestVHostManagerMaintenance(c *C) {
+	m := NewMaintenance("", func(tenantID string) (*service.MaintenanceMode, error) {
+		return &service.MaintenanceMode{Message: "Upgrading", RetryAfter: 60}, nil
+	})
+	mgr := NewVHostManager(false, m)
+	mgr.Enable("zenoss5", "tenant1", "")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "https://zenoss5.example.com/zport/dmd", nil)
+	c.Assert(mgr.Handle("zenoss5.example.com", w, r), Equals, true)
+	c.Assert(w.Code, Equals, http.StatusServiceUnavailable)
+	c.Assert(w.Header().Get("Retry-After"), Equals, "60")
+
+	// disabled vhosts are not handled
+	mgr.Disable("zenoss5")
+	c.Assert(mgr.Handle("zenoss5.example.com", httptest.NewRecorder(), r), Equals, false)
+}
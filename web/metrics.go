@@ -92,6 +92,21 @@ var (
 					domain.Metric{ID: "Serviced.OpenFileDescriptors", Name: "OpenFileDescriptors", Unit: "Open File Descriptors"},
 				},
 			},
+			//Coordinator
+			domain.MetricConfig{
+				ID:          "coordinator",
+				Name:        "Coordinator Sessions",
+				Description: "Coordinator session and watch statistics",
+				Metrics: []domain.Metric{
+					domain.Metric{ID: "zk.sessions", Name: "Sessions Established", Unit: "Sessions", Counter: true},
+					domain.Metric{ID: "zk.disconnects", Name: "Disconnects", Unit: "Disconnects", Counter: true},
+					domain.Metric{ID: "zk.expirations", Name: "Session Expirations", Unit: "Sessions", Counter: true},
+					domain.Metric{ID: "zk.watches.outstanding", Name: "Outstanding Watches", Unit: "Watches"},
+					domain.Metric{ID: "zk.watches.lost", Name: "Lost Watches", Unit: "Watches", Counter: true},
+					domain.Metric{ID: "zk.ephemerals.replayed", Name: "Replayed Ephemeral Nodes", Unit: "Nodes", Counter: true},
+					domain.Metric{ID: "zk.ephemerals.replayerrors", Name: "Failed Ephemeral Replays", Unit: "Nodes", Counter: true},
+				},
+			},
 		},
 		ThresholdConfigs: []domain.ThresholdConfig{
 			domain.ThresholdConfig{
@@ -17,6 +17,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"net"
+	"net/http"
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
@@ -28,31 +29,46 @@ var ErrPortServerRunning = errors.New("port server is already running")
 
 // PublicPortManager manages all the port servers for a particular host id
 type PublicPortManager struct {
-	hostID    string
-	certFile  string
-	keyFile   string
-	onFailure func(portNumber string, err error)
-	mu        *sync.RWMutex
-	ports     map[string]*PublicPortHandler
+	hostID      string
+	certFile    string
+	keyFile     string
+	maintenance *Maintenance
+	onFailure   func(portNumber string, err error)
+	mu          *sync.RWMutex
+	ports       map[string]*PublicPortHandler
+	sniPorts    map[string]*SNIPortHandler
 }
 
-// NewPublicPortManager creates a new public port manager for a host id
-func NewPublicPortManager(hostID, certFile, keyFile string, onFailure func(portAddr string, err error)) *PublicPortManager {
+// NewPublicPortManager creates a new public port manager for a host id.  The
+// http ports of tenants in maintenance mode serve the maintenance page, if
+// maintenance is set.
+func NewPublicPortManager(hostID, certFile, keyFile string, maintenance *Maintenance, onFailure func(portAddr string, err error)) *PublicPortManager {
 	return &PublicPortManager{
-		hostID:    hostID,
-		certFile:  certFile,
-		keyFile:   keyFile,
-		onFailure: onFailure,
-		mu:        &sync.RWMutex{},
-		ports:     make(map[string]*PublicPortHandler),
+		hostID:      hostID,
+		certFile:    certFile,
+		keyFile:     keyFile,
+		maintenance: maintenance,
+		onFailure:   onFailure,
+		mu:          &sync.RWMutex{},
+		ports:       make(map[string]*PublicPortHandler),
+		sniPorts:    make(map[string]*SNIPortHandler),
 	}
 }
 
-// Enable implements starts the public port server at the port address
-func (m *PublicPortManager) Enable(portAddr, protocol string, useTLS bool) {
+// Enable implements starts the public port server of a tenant at the port
+// address.  Ports that are routed by server name share the port server at
+// their address.
+func (m *PublicPortManager) Enable(portAddr, tenantID, protocol, appProtocol string, useTLS bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if addr, serverName := registry.SplitPublicPortName(portAddr); serverName != "" {
+		if err := m.getSNIPort(addr).Enable(serverName, useTLS, m.certFile, m.keyFile); err != nil {
+			m.onFailure(portAddr, err)
+		}
+		return
+	}
+
 	// get the port handler or create if it doesn't exist
 	h, ok := m.ports[portAddr]
 	if !ok {
@@ -61,7 +77,10 @@ func (m *PublicPortManager) Enable(portAddr, protocol string, useTLS bool) {
 	}
 
 	// start the port server
-	if err := h.Serve(protocol, useTLS, m.certFile, m.keyFile); err != nil {
+	maintenance := func(w http.ResponseWriter) bool {
+		return m.maintenance.Serve(tenantID, w)
+	}
+	if err := h.Serve(protocol, appProtocol, useTLS, m.certFile, m.keyFile, maintenance); err != nil {
 		m.onFailure(portAddr, err)
 	}
 }
@@ -71,6 +90,13 @@ func (m *PublicPortManager) Disable(portAddr string) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	if addr, serverName := registry.SplitPublicPortName(portAddr); serverName != "" {
+		if h, ok := m.sniPorts[addr]; ok {
+			h.Disable(serverName)
+		}
+		return
+	}
+
 	// get the port handler and stop it if it exists
 	h, ok := m.ports[portAddr]
 	if ok {
@@ -83,6 +109,11 @@ func (m *PublicPortManager) Set(portAddr string, data []registry.ExportDetails)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if addr, serverName := registry.SplitPublicPortName(portAddr); serverName != "" {
+		m.getSNIPort(addr).SetExports(serverName, data)
+		return
+	}
+
 	h, ok := m.ports[portAddr]
 	if ok {
 		h.SetExports(data)
@@ -92,6 +123,17 @@ func (m *PublicPortManager) Set(portAddr string, data []registry.ExportDetails)
 	}
 }
 
+// getSNIPort returns the handler of the port address that is shared by ports
+// that are routed by server name, creating it if it doesn't exist.
+func (m *PublicPortManager) getSNIPort(portAddr string) *SNIPortHandler {
+	h, ok := m.sniPorts[portAddr]
+	if !ok {
+		h = NewSNIPortHandler(portAddr)
+		m.sniPorts[portAddr] = h
+	}
+	return h
+}
+
 // PublicPortHandler manages the port server at a specific port address
 type PublicPortHandler struct {
 	portAddr string
@@ -113,11 +155,13 @@ func NewPublicPortHandler(portAddr string, data ...registry.ExportDetails) *Publ
 	}
 }
 
-// Serve starts the port server at address
-func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFile string) error {
+// Serve starts the port server at address.  Http ports call maintenance
+// before proxying each request, which is not proxied if it returns true.
+func (h *PublicPortHandler) Serve(protocol, appProtocol string, useTLS bool, certFile, keyFile string, maintenance func(http.ResponseWriter) bool) error {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": h.portAddr,
 		"protocol":    protocol,
+		"appprotocol": appProtocol,
 		"usetls":      useTLS,
 	})
 
@@ -132,26 +176,11 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 
 	var tlsConfig *tls.Config
 	if useTLS {
-
-		// get the certificate
-		certFile, keyFile = GetCertFiles(certFile, keyFile)
-		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
+		var err error
+		if tlsConfig, err = publicPortTLSConfig(certFile, keyFile); err != nil {
 			logger.WithError(err).Debug("Could not set up certificate")
 			return err
 		}
-
-		// cipher suites and tls min version change may not be needed with
-		// golang 1.5:
-		// https://github.com/golang/go/issues/10094
-		// https://github.com/golang/go/issues/9364
-		tlsConfig = &tls.Config{
-			MinVersion:               utils.MinTLS("http"),
-			PreferServerCipherSuites: true,
-			CipherSuites:             utils.CipherSuites("http"),
-			Certificates:             []tls.Certificate{cert},
-		}
-
 		logger.Debug("Set up tls certificate")
 	}
 
@@ -168,7 +197,7 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 		defer logger.Debug("Port server exited")
 
 		if protocol == "http" || protocol == "https" {
-			ServeHTTP(h.cancel, h.portAddr, protocol, listener, tlsConfig, h.exports)
+			ServeHTTP(h.cancel, h.portAddr, protocol, appProtocol, listener, tlsConfig, h.exports, maintenance)
 		} else {
 			ServeTCP(h.cancel, listener, tlsConfig, h.exports)
 		}
@@ -178,6 +207,27 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 	return nil
 }
 
+// publicPortTLSConfig returns the tls configuration of a public port that
+// terminates tls
+func publicPortTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	certFile, keyFile = GetCertFiles(certFile, keyFile)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// cipher suites and tls min version change may not be needed with
+	// golang 1.5:
+	// https://github.com/golang/go/issues/10094
+	// https://github.com/golang/go/issues/9364
+	return &tls.Config{
+		MinVersion:               utils.MinTLS("http"),
+		PreferServerCipherSuites: true,
+		CipherSuites:             utils.CipherSuites("http"),
+		Certificates:             []tls.Certificate{cert},
+	}, nil
+}
+
 // Stop shuts down the port server
 func (h *PublicPortHandler) Stop() {
 	select {
@@ -14,15 +14,18 @@
 package web
 
 import (
-	"fmt"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk/registry"
+	"golang.org/x/net/http2"
 )
 
 // rpcache keeps track of all used reverse proxies
@@ -36,6 +39,7 @@ type ReverseProxyKey struct {
 	HostAddress string
 	PrivateAddress string
 	UseTLS  bool
+	AppProtocol string
 }
 
 // ReverseProxyCache keeps track of all available reverse proxies
@@ -45,36 +49,39 @@ type ReverseProxyCache struct {
 }
 
 // Get retrieves a reverse proxy from the cache
-func (cache *ReverseProxyCache) Get(hostAddress, privateAddress string, useTLS bool) (*httputil.ReverseProxy, bool) {
+func (cache *ReverseProxyCache) Get(hostAddress, privateAddress string, useTLS bool, appProtocol string) (*httputil.ReverseProxy, bool) {
 	cache.mu.RLock()
 	defer cache.mu.RUnlock()
 	key := ReverseProxyKey{
 		HostAddress: hostAddress,
 		PrivateAddress: privateAddress,
 		UseTLS:  useTLS,
+		AppProtocol: appProtocol,
 	}
 	rp, ok := cache.data[key]
 	return rp, ok
 }
 
 // Set sets an instantiated reverse proxy
-func (cache *ReverseProxyCache) Set(hostAddress, privateAddress string, useTLS bool, rp *httputil.ReverseProxy) {
+func (cache *ReverseProxyCache) Set(hostAddress, privateAddress string, useTLS bool, appProtocol string, rp *httputil.ReverseProxy) {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 	key := ReverseProxyKey{
 		HostAddress: hostAddress,
 		PrivateAddress: privateAddress,
 		UseTLS:  useTLS,
+		AppProtocol: appProtocol,
 	}
 	cache.data[key] = rp
 }
 
 // GetReverseProxy acquires a reverse proxy from the cache if it exists or
-// creates it if it is not found.
-func GetReverseProxy(useTLS bool, export *registry.ExportDetails) *httputil.ReverseProxy {
+// creates it if it is not found.  If the app protocol is HTTP/2, requests are
+// forwarded to the backend with HTTP/2.
+func GetReverseProxy(useTLS bool, appProtocol string, export *registry.ExportDetails) *httputil.ReverseProxy {
 	remoteAddress := ""
-	hostAddress := fmt.Sprintf("%s:%d", export.HostIP, export.MuxPort)
-	privateAddress := fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber)
+	hostAddress := net.JoinHostPort(export.HostIP, strconv.Itoa(int(export.MuxPort)))
+	privateAddress := net.JoinHostPort(export.PrivateIP, strconv.Itoa(int(export.PortNumber)))
 
 	// Set the remote address based on whether the container is running on this
 	// host.
@@ -86,20 +93,34 @@ func GetReverseProxy(useTLS bool, export *registry.ExportDetails) *httputil.Reve
 	}
 
 	// Look up the reverse proxy in the cache and return it if it exists.
-	rp, ok := rpcache.Get(hostAddress, privateAddress, useTLS)
+	rp, ok := rpcache.Get(hostAddress, privateAddress, useTLS, appProtocol)
 	if ok {
 		return rp
 	}
 
 	// Set up the reverse proxy and add it to the cache
 	rpurl := url.URL{Scheme: "http", Host: remoteAddress}
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
-	transport.Dial = func(network, addr string) (net.Conn, error) {
-		return GetRemoteConnection(useTLS, export)
+	var transport http.RoundTripper
+	if servicedefinition.IsHTTP2(appProtocol) {
+		// The http2 transport only sends requests for https urls, but the
+		// connection to the backend is the cleartext (or mux) connection, so
+		// the backend sees HTTP/2 with prior knowledge (h2c).
+		rpurl.Scheme = "https"
+		transport = &http2.Transport{
+			DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+				return GetRemoteConnection(useTLS, export)
+			},
+		}
+	} else {
+		t := &http.Transport{Proxy: http.ProxyFromEnvironment}
+		t.Dial = func(network, addr string) (net.Conn, error) {
+			return GetRemoteConnection(useTLS, export)
+		}
+		transport = t
 	}
 	rp = httputil.NewSingleHostReverseProxy(&rpurl)
 	rp.Transport = transport
 	rp.FlushInterval = time.Millisecond * 10
-	rpcache.Set(hostAddress, privateAddress, useTLS, rp)
+	rpcache.Set(hostAddress, privateAddress, useTLS, appProtocol, rp)
 	return rp
 }
@@ -21,6 +21,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/proxy"
 )
 
@@ -98,11 +99,15 @@ func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Conf
 	wg.Wait()
 }
 
-// ServeHTTP sets up an http server for handling a collection of endpoints
-func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports) {
+// ServeHTTP sets up an http server for handling a collection of endpoints.  If
+// the app protocol is HTTP/2 and the server terminates tls, clients may also
+// connect with HTTP/2.  Requests are not proxied if maintenance returns true
+// for them, as when it served a maintenance page instead.
+func ServeHTTP(cancel <-chan struct{}, address, protocol, appProtocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports, maintenance func(http.ResponseWriter) bool) {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": address,
 		"protocol":    protocol,
+		"appprotocol": appProtocol,
 		"usetls":      tlsConfig != nil,
 	})
 
@@ -137,13 +142,17 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 
 		logger.WithField("handlerrequest", r).Debug("Handler handling (port) request")
 
+		if maintenance != nil && maintenance(w) {
+			return
+		}
+
 		export := exports.Next()
 		if export == nil {
 			http.Error(w, "endpoint not available", http.StatusNotFound)
 			return
 		}
 
-		rp := GetReverseProxy(config.MuxTLSIsEnabled(), export)
+		rp := GetReverseProxy(config.MuxTLSIsEnabled(), appProtocol, export)
 
 		logger.WithFields(log.Fields{
 			"application": export.Application,
@@ -171,6 +180,13 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 	server := &http.Server{Addr: address, Handler: http.HandlerFunc(httphandler)}
 
 	if tlsConfig != nil {
+		// Offer HTTP/2 during the tls handshake, so that gRPC and other
+		// HTTP/2 clients are not downgraded to HTTP/1.1.  The http server
+		// serves HTTP/2 on any connection that negotiated it.
+		if servicedefinition.IsHTTP2(appProtocol) {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+		}
 		keepAliveListener := &TCPKeepAliveListener{
 			TCPListener: listener.(*net.TCPListener),
 			cancel:      cancel,
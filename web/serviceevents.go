@@ -0,0 +1,129 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/facade"
+	"github.com/zenoss/go-json-rest"
+	"golang.org/x/net/websocket"
+)
+
+// serviceEventsPath is the websocket endpoint that streams the changes to the
+// current state of a service, or of every service of a tenant.  The query
+// parameters are serviceId, which streams every service if it is empty, and
+// since, the sequence number of the last event received by the client.
+const serviceEventsPath = "/ws/services/events"
+
+// serviceEventsTimeout is how long each wait for state changes lasts
+var serviceEventsTimeout = 30 * time.Second
+
+// serveServiceEvents authenticates the request and upgrades it to a websocket
+// that streams service state changes as json.  It is served outside of the
+// rest handler, which cannot hijack connections.
+func (sc *ServiceConfig) serveServiceEvents(w http.ResponseWriter, r *http.Request) {
+	if !loginOK(&rest.ResponseWriter{ResponseWriter: w}, &rest.Request{Request: r}) {
+		http.Error(w, "Not authorized", http.StatusUnauthorized)
+		return
+	}
+
+	serviceID := r.URL.Query().Get("serviceId")
+	var since uint64
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid sequence number %q", s), http.StatusBadRequest)
+			return
+		}
+	}
+	if serviceID != "" {
+		if _, err := sc.facade.GetServiceDetails(datastore.Get(), serviceID); err != nil {
+			http.Error(w, fmt.Sprintf("Could not find service %s", serviceID), http.StatusNotFound)
+			return
+		}
+	}
+	serviceEventsServer(sc.facade, serviceID, since).ServeHTTP(w, r)
+}
+
+// serviceEventsServer returns the websocket server that streams the state
+// changes of a service.  Browsers may only open it from the same host, so
+// that other sites cannot use the session cookie of the user.
+func serviceEventsServer(f facade.FacadeInterface, serviceID string, since uint64) websocket.Server {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if r.Header.Get("Origin") == "" {
+				return nil
+			}
+			origin, err := websocket.Origin(config, r)
+			if err != nil {
+				return err
+			}
+			if origin == nil || origin.Host != r.Host {
+				return fmt.Errorf("origin %v does not match host %s", origin, r.Host)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			streamServiceEvents(f, ws, serviceID, since)
+		},
+	}
+}
+
+// streamServiceEvents sends the state changes of a service over a websocket
+// until the client closes it.
+func streamServiceEvents(f facade.FacadeInterface, ws *websocket.Conn, serviceID string, since uint64) {
+	logger := plog.WithFields(logrus.Fields{
+		"serviceid": serviceID,
+		"remote":    ws.Request().RemoteAddr,
+	})
+	defer ws.Close()
+
+	// the client does not send anything, so a read only returns when the
+	// connection is closed
+	done := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, ws)
+		close(done)
+	}()
+
+	logger.Debug("Streaming service state events")
+	for {
+		events, err := f.WaitServiceStateEvents(datastore.Get(), serviceID, since, serviceEventsTimeout, done)
+		if err != nil {
+			logger.WithError(err).Debug("Could not wait for service state events")
+			return
+		}
+		for _, ev := range events.Events {
+			if err := websocket.JSON.Send(ws, ev); err != nil {
+				logger.WithError(err).Debug("Could not send service state event")
+				return
+			}
+		}
+		since = events.Seq
+		select {
+		case <-done:
+			logger.Debug("Client closed service state event stream")
+			return
+		default:
+		}
+	}
+}
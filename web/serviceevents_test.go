@@ -0,0 +1,75 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/websocket"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestServiceEventsServer(c *C) {
+	event := service.StateEvent{
+		Seq:       5,
+		ServiceID: "service1",
+		TenantID:  "tenant1",
+		State:     service.SVCCSRunning,
+		Timestamp: time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+	s.mockFacade.On("WaitServiceStateEvents", mock.Anything, "tenant1", uint64(0), serviceEventsTimeout, mock.Anything).
+		Return(&service.StateEvents{Events: []service.StateEvent{event}, Seq: 5}, nil).Once()
+	waited := make(chan struct{})
+	s.mockFacade.On("WaitServiceStateEvents", mock.Anything, "tenant1", uint64(5), serviceEventsTimeout, mock.Anything).
+		Return(func(_ datastore.Context, _ string, _ uint64, _ time.Duration, cancel <-chan struct{}) *service.StateEvents {
+			<-cancel
+			close(waited)
+			return &service.StateEvents{Seq: 5}
+		}, nil)
+
+	server := httptest.NewServer(serviceEventsServer(s.mockFacade, "tenant1", 0))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	ws, err := websocket.Dial(url, "", server.URL)
+	c.Assert(err, IsNil)
+	var actual service.StateEvent
+	c.Assert(websocket.JSON.Receive(ws, &actual), IsNil)
+	c.Assert(actual.Seq, Equals, event.Seq)
+	c.Assert(actual.ServiceID, Equals, event.ServiceID)
+	c.Assert(actual.State, Equals, event.State)
+	ws.Close()
+
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		c.Fatalf("stream did not stop after the client closed the connection")
+	}
+}
+
+func (s *TestWebSuite) TestServiceEventsServer_origin(c *C) {
+	server := httptest.NewServer(serviceEventsServer(s.mockFacade, "tenant1", 0))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	_, err := websocket.Dial(url, "", "http://example.com")
+	c.Assert(err, NotNil)
+}
@@ -48,6 +48,10 @@ func restAddAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 
 	var b bytes.Buffer
 	_, err = io.Copy(&b, file)
+	if err := servicetemplate.ValidateJSON(b.Bytes()); err != nil {
+		restServerError(w, err)
+		return
+	}
 	template, err := servicetemplate.FromJSON(b.String())
 	if err != nil {
 		restServerError(w, err)
@@ -88,7 +92,7 @@ func restDeployAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		restBadRequest(w, err)
 		return
 	}
-	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID)
+	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID, payload.Values)
 	if err != nil {
 		glog.Error("Could not deploy template: ", err)
 		restServerError(w, err)
@@ -148,3 +152,19 @@ func restDeployAppTemplateActive(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	}
 	w.WriteJson(&active)
 }
+
+// restGetSchema returns the JSON Schema of service templates or services, for
+// editors and pipelines that validate them before they are submitted
+func restGetSchema(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	name, err := url.QueryUnescape(r.PathParam("name"))
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	}
+	schema, err := ctx.getFacade().GetSchema(ctx.getDatastoreContext(), name)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+	w.WriteJson(schema)
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/validation"
 	"github.com/stretchr/testify/mock"
 	"github.com/zenoss/go-json-rest"
 	. "gopkg.in/check.v1"
@@ -127,6 +128,49 @@ func (s *TestWebSuite) TestRestAddAppTemplateFailsForBadJson(c *C) {
 	s.assertServerError(c, expectedError)
 }
 
+func (s *TestWebSuite) TestRestAddAppTemplateFailsForSchema(c *C) {
+	jsonBuffer := bytes.NewBufferString(`{"Name": "template", "Services": [{"Name": "service", "Instances": {"Min": "1"}}]}`)
+	request, err := buildUploadRequest("POST", "/templates/add", jsonBuffer)
+	if err != nil {
+		c.Fatalf("Unable to build mock upload request: %s", err)
+	}
+
+	restAddAppTemplate(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusBadRequest)
+	s.assertSimpleResponse(c, `(?s)Bad Request: .*Services\[0\]\.Instances\.Min: expected integer, got string.*`, homeLink())
+	s.mockFacade.AssertNotCalled(c, "AddServiceTemplate", s.ctx.getDatastoreContext(), mock.AnythingOfType("servicetemplate.ServiceTemplate"), true)
+}
+
+func (s *TestWebSuite) TestRestGetSchema(c *C) {
+	expectedSchema := servicetemplate.Schema()
+	request := s.buildRequest("GET", "/schemas/template", "")
+	request.PathParams["name"] = "template"
+	s.mockFacade.
+		On("GetSchema", s.ctx.getDatastoreContext(), "template").
+		Return(expectedSchema, nil)
+
+	restGetSchema(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	actualSchema := validation.Schema{}
+	s.getResult(c, &actualSchema)
+	c.Assert(&actualSchema, DeepEquals, expectedSchema)
+}
+
+func (s *TestWebSuite) TestRestGetSchemaFails(c *C) {
+	expectedError := fmt.Errorf("mock GetSchema failed")
+	request := s.buildRequest("GET", "/schemas/template", "")
+	request.PathParams["name"] = "template"
+	s.mockFacade.
+		On("GetSchema", s.ctx.getDatastoreContext(), "template").
+		Return(nil, expectedError)
+
+	restGetSchema(&(s.writer), &request, s.ctx)
+
+	s.assertServerError(c, expectedError)
+}
+
 func (s *TestWebSuite) TestRestRemoveAppTemplate(c *C) {
 	templateID := "someTemplateID"
 	request := s.buildRequest("DELETE", "/templates/someTemplateID", "")
@@ -179,7 +223,7 @@ func (s *TestWebSuite) TestRestDeployAppTemplate(c *C) {
 	}
 	request := s.buildRequest("GET", "/templates/deploy", string(jsonPayload))
 	s.mockFacade.
-		On("DeployTemplate", s.ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID).
+		On("DeployTemplate", s.ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID, payload.Values).
 		Return(expectedResult, nil)
 	s.mockFacade.
 		On("AssignIPs", s.ctx.getDatastoreContext(), mock.AnythingOfType("addressassignment.AssignmentRequest")).
@@ -195,7 +239,7 @@ func (s *TestWebSuite) TestRestDeployAppTemplateFails(c *C) {
 	expectedError := fmt.Errorf("mock DeployTemplate failed")
 	request := s.buildRequest("GET", "/templates/deploy", `{"DeploymentID": "someID"}`)
 	s.mockFacade.
-		On("DeployTemplate", s.ctx.getDatastoreContext(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		On("DeployTemplate", s.ctx.getDatastoreContext(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).
 		Return(nil, expectedError)
 
 	restDeployAppTemplate(&(s.writer), &request, s.ctx)
@@ -0,0 +1,296 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/proxy"
+	"github.com/control-center/serviced/zzk/registry"
+)
+
+// sniPeekTimeout is how long a client has to send its TLS client hello
+const sniPeekTimeout = 10 * time.Second
+
+// errServerNamePeeked stops the handshake that reads the client hello
+var errServerNamePeeked = errors.New("server name peeked")
+
+// sniRoute is a public port that is routed by TLS server name
+type sniRoute struct {
+	exports   Exports
+	tlsConfig *tls.Config // terminates tls; nil to pass the connection through
+	enabled   bool
+}
+
+// SNIPortHandler manages the port server at a port address that is shared by
+// public ports, routing each connection by the server name of its TLS client
+// hello.
+type SNIPortHandler struct {
+	portAddr string
+	mu       *sync.RWMutex
+	routes   map[string]*sniRoute
+	cancel   chan struct{}
+	wg       *sync.WaitGroup
+}
+
+// NewSNIPortHandler sets up a new shared public port at the given port address
+func NewSNIPortHandler(portAddr string) *SNIPortHandler {
+	cancel := make(chan struct{})
+	close(cancel)
+
+	return &SNIPortHandler{
+		portAddr: portAddr,
+		mu:       &sync.RWMutex{},
+		routes:   make(map[string]*sniRoute),
+		cancel:   cancel,
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+// SetExports updates the export list of the server name
+func (h *SNIPortHandler) SetExports(serverName string, data []registry.ExportDetails) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.routes[serverName]; ok {
+		r.exports.Set(data)
+	} else {
+		h.routes[serverName] = &sniRoute{exports: NewRoundRobinExports(data)}
+	}
+}
+
+// Enable routes connections for the server name, and starts the port server
+// if it is not already running.
+func (h *SNIPortHandler) Enable(serverName string, useTLS bool, certFile, keyFile string) error {
+	logger := plog.WithFields(log.Fields{
+		"portaddress": h.portAddr,
+		"servername":  serverName,
+		"usetls":      useTLS,
+	})
+
+	var tlsConfig *tls.Config
+	if useTLS {
+		var err error
+		if tlsConfig, err = publicPortTLSConfig(certFile, keyFile); err != nil {
+			logger.WithError(err).Debug("Could not set up certificate")
+			return err
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.routes[serverName]
+	if !ok {
+		r = &sniRoute{exports: NewRoundRobinExports(nil)}
+		h.routes[serverName] = r
+	}
+	r.tlsConfig, r.enabled = tlsConfig, true
+
+	// start the port server if it is not running
+	select {
+	case <-h.cancel:
+	default:
+		logger.Debug("Enabled server name on running port server")
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", h.portAddr)
+	if err != nil {
+		logger.WithError(err).Debug("Could not start TCP listener")
+		r.enabled = false
+		return err
+	}
+	h.cancel = make(chan struct{})
+
+	h.wg.Add(1)
+	go func(cancel <-chan struct{}) {
+		logger.Info("Starting shared port server")
+		defer logger.Debug("Shared port server exited")
+		h.serve(cancel, listener)
+		h.wg.Done()
+	}(h.cancel)
+
+	return nil
+}
+
+// Disable stops routing connections for the server name, and stops the port
+// server when no server name is routed.
+func (h *SNIPortHandler) Disable(serverName string) {
+	h.mu.Lock()
+	if r, ok := h.routes[serverName]; ok {
+		r.enabled = false
+	}
+	for _, r := range h.routes {
+		if r.enabled {
+			h.mu.Unlock()
+			return
+		}
+	}
+	select {
+	case <-h.cancel:
+	default:
+		close(h.cancel)
+	}
+	h.mu.Unlock()
+
+	// connections that are being routed need the lock
+	h.wg.Wait()
+}
+
+// route returns the enabled route of the server name, or of the wildcard of
+// its domain.
+func (h *SNIPortHandler) route(serverName string) (Exports, *tls.Config, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := []string{serverName}
+	if i := strings.Index(serverName, "."); i > 0 {
+		names = append(names, "*"+serverName[i:])
+	}
+	for _, name := range names {
+		if r, ok := h.routes[name]; ok && r.enabled {
+			return r.exports, r.tlsConfig, true
+		}
+	}
+	return nil, nil, false
+}
+
+// serve accepts connections on the listener until cancelled
+func (h *SNIPortHandler) serve(cancel <-chan struct{}, listener net.Listener) {
+	stopChan := make(chan bool)
+	wg := &sync.WaitGroup{}
+
+	go func() {
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				plog.WithError(err).Debug("Stopping accept on host:port")
+				return
+			}
+			wg.Add(1)
+			go func() {
+				h.proxy(local, stopChan)
+				wg.Done()
+			}()
+		}
+	}()
+
+	<-cancel
+	listener.Close()
+	close(stopChan)
+	wg.Wait()
+}
+
+// proxy routes a connection by its server name and forwards it to an export
+func (h *SNIPortHandler) proxy(local net.Conn, stopChan chan bool) {
+	logger := plog.WithFields(log.Fields{
+		"portaddress":   h.portAddr,
+		"remoteaddress": local.RemoteAddr(),
+	})
+
+	local.SetReadDeadline(time.Now().Add(sniPeekTimeout))
+	serverName, local, err := peekServerName(local)
+	if err != nil {
+		logger.WithError(err).Debug("Could not read the TLS client hello")
+		local.Close()
+		return
+	}
+	local.SetReadDeadline(time.Time{})
+	logger = logger.WithField("servername", serverName)
+
+	exports, tlsConfig, ok := h.route(serverName)
+	if !ok {
+		logger.Warn("No public port for server name")
+		local.Close()
+		return
+	}
+	if tlsConfig != nil {
+		local = tls.Server(local, tlsConfig)
+	}
+
+	export := exports.Next()
+	if export == nil {
+		// This happens if the endpoint is accessed and the containers
+		// have died or not come up yet.
+		logger.Warn("Could not retrieve endpoint")
+		local.Close()
+		return
+	}
+
+	logger = logger.WithFields(log.Fields{
+		"application": export.Application,
+		"hostip":      export.HostIP,
+		"privateip":   export.PrivateIP,
+	})
+
+	remote, err := GetRemoteConnection(config.MuxTLSIsEnabled(), export)
+	if err != nil {
+		logger.WithError(err).Error("Could not get remote connection for endpoint")
+		local.Close()
+		return
+	}
+	logger.Debug("Established remote connection")
+	proxy.ProxyLoop(local, remote, stopChan)
+}
+
+// peekServerName reads the TLS client hello of a connection and returns the
+// server name that the client asked for, and a connection that replays what
+// was read.  The connection is closed if the client hello cannot be read.
+func peekServerName(conn net.Conn) (string, net.Conn, error) {
+	peeked := &bytes.Buffer{}
+	var hello *tls.ClientHelloInfo
+	err := tls.Server(readOnlyConn{io.TeeReader(conn, peeked)}, &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello = info
+			return nil, errServerNamePeeked
+		},
+	}).Handshake()
+	if hello == nil {
+		return "", conn, err
+	}
+	return strings.ToLower(hello.ServerName), &peekedConn{Conn: conn, r: io.MultiReader(peeked, conn)}, nil
+}
+
+// peekedConn is a connection that replays the bytes that were peeked from it
+type peekedConn struct {
+	net.Conn
+	r io.Reader
+}
+
+func (c *peekedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// readOnlyConn is a connection that only reads, for peeking at a handshake
+type readOnlyConn struct {
+	r io.Reader
+}
+
+func (c readOnlyConn) Read(p []byte) (int, error)         { return c.r.Read(p) }
+func (c readOnlyConn) Write(p []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c readOnlyConn) Close() error                       { return nil }
+func (c readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c readOnlyConn) SetDeadline(t time.Time) error      { return nil }
+func (c readOnlyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c readOnlyConn) SetWriteDeadline(t time.Time) error { return nil }
@@ -0,0 +1,113 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package web
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"time"
+
+	"github.com/control-center/serviced/zzk/registry"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestPeekServerName(c *C) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	// the client hello is replayed to the reader of the peeked connection
+	hello := &bytes.Buffer{}
+	go func() {
+		tls.Client(teeConn{Conn: client, w: hello}, &tls.Config{ServerName: "DB.example.com"}).Handshake()
+	}()
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	serverName, conn, err := peekServerName(server)
+	c.Assert(err, IsNil)
+	c.Assert(serverName, Equals, "db.example.com")
+
+	replayed := make([]byte, hello.Len())
+	_, err = io.ReadFull(conn, replayed)
+	c.Assert(err, IsNil)
+	c.Assert(replayed, DeepEquals, hello.Bytes())
+	conn.Close()
+
+	// a connection that is not tls has no client hello
+	client, server = net.Pipe()
+	defer client.Close()
+	go client.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = peekServerName(server)
+	c.Assert(err, NotNil)
+}
+
+func (s *TestWebSuite) TestSNIPortHandlerRoute(c *C) {
+	h := NewSNIPortHandler(":0")
+	h.SetExports("db.example.com", []registry.ExportDetails{{PrivateIP: "10.0.0.1"}})
+	h.SetExports("*.example.com", []registry.ExportDetails{{PrivateIP: "10.0.0.2"}})
+	h.routes["db.example.com"].enabled = true
+	h.routes["*.example.com"].enabled = true
+
+	exports, tlsConfig, ok := h.route("db.example.com")
+	c.Assert(ok, Equals, true)
+	c.Assert(tlsConfig, IsNil)
+	c.Assert(exports.Next().PrivateIP, Equals, "10.0.0.1")
+
+	exports, _, ok = h.route("cache.example.com")
+	c.Assert(ok, Equals, true)
+	c.Assert(exports.Next().PrivateIP, Equals, "10.0.0.2")
+
+	_, _, ok = h.route("example.com")
+	c.Assert(ok, Equals, false)
+
+	// disabled server names are not routed
+	h.routes["*.example.com"].enabled = false
+	_, _, ok = h.route("cache.example.com")
+	c.Assert(ok, Equals, false)
+}
+
+func (s *TestWebSuite) TestSNIPortHandlerEnable(c *C) {
+	h := NewSNIPortHandler("127.0.0.1:0")
+	c.Assert(h.Enable("db.example.com", false, "", ""), IsNil)
+	c.Assert(h.Enable("cache.example.com", false, "", ""), IsNil)
+
+	// the port server runs until the last server name is disabled
+	h.Disable("db.example.com")
+	select {
+	case <-h.cancel:
+		c.Fatalf("port server stopped with an enabled server name")
+	default:
+	}
+	h.Disable("cache.example.com")
+	select {
+	case <-h.cancel:
+	default:
+		c.Fatalf("port server did not stop")
+	}
+}
+
+// teeConn copies what is written to a connection
+type teeConn struct {
+	net.Conn
+	w io.Writer
+}
+
+func (c teeConn) Write(p []byte) (int, error) {
+	c.w.Write(p)
+	return c.Conn.Write(p)
+}
@@ -88,6 +88,7 @@
             this.currentState = model.CurrentState;
             this.desiredState = model.DesiredState;
             this.emergencyShutdown = model.EmergencyShutdown;
+            this.maintenance = model.Maintenance;
             this.model = Object.freeze(model);
             this.evaluateServiceType();
             this.touch();
@@ -13,6 +13,7 @@
                 </div>
                 <span ng-if="!app.service.deploying" ng-click="app.routeToService()" class="link" ng-class="app.getStatusClass()">{{app.service.name}}<span class="version" ng-show="app.service.model.Version"> (v{{app.service.model.Version}})</span></span>
                 <span ng-if="app.service.deploying">{{app.service.name}}<span class="version" ng-show="app.service.model.Version"> (v{{app.service.model.Version}})</span></span>
+                <span ng-if="app.service.maintenance" class="label label-warning" title="{{app.service.maintenance.Message}}" translate>maintenance_mode</span>
             </div>
             `;
 
@@ -18,6 +18,7 @@ import (
 	"net/http"
 	"path"
 
+	"github.com/control-center/serviced/apierror"
 	"github.com/zenoss/go-json-rest"
 	"github.com/control-center/serviced/utils"
 )
@@ -65,13 +66,33 @@ func restUnauthorized(w *rest.ResponseWriter) {
 }
 
 /*
- * Provide a generic response for an oopsie.
+ * Provide a generic response for an oopsie.  Errors that carry an api error
+ * code are returned with the matching http status.
  */
 func restServerError(w *rest.ResponseWriter, err error) {
-	writeJSON(w, &simpleResponse{fmt.Sprintf("Internal Server Error: %v", err), homeLink()}, http.StatusInternalServerError)
+	status := errorStatus(err)
+	writeJSON(w, &simpleResponse{fmt.Sprintf("%s: %v", http.StatusText(status), err), homeLink()}, status)
 	return
 }
 
+// errorStatus returns the http status for the api error code of an error
+func errorStatus(err error) int {
+	switch apierror.CodeOf(err) {
+	case apierror.NotFound:
+		return http.StatusNotFound
+	case apierror.Conflict:
+		return http.StatusConflict
+	case apierror.Validation:
+		return http.StatusBadRequest
+	case apierror.Transient:
+		return http.StatusServiceUnavailable
+	case apierror.Unauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 /*
  * The user sent us junk, or we were incapabale of decoding what they sent.
  */
@@ -16,8 +16,8 @@ package web
 import (
 	"crypto/tls"
 	"errors"
-	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/control-center/serviced/auth"
@@ -35,7 +35,7 @@ var ipmap = make(map[string]struct{})
 
 func init() {
 	// set up the ipmap
-	ips, err := utils.GetIPv4Addresses()
+	ips, err := utils.GetIPAddresses()
 	if err != nil {
 		plog.WithError(err).Fatal("Could not get interface ip addresses")
 	}
@@ -46,7 +46,7 @@ func init() {
 
 // IsLocalAddress returns true if the ip address is available on this host
 func IsLocalAddress(ip string) bool {
-	_, ok := ipmap[ip]
+	_, ok := ipmap[utils.CanonicalIP(ip)]
 	return ok
 }
 
@@ -89,26 +89,23 @@ func newNetDialer() dialerInterface {
 	return &netDialer{}
 }
 
-type tlsDialer struct {
-	config *tls.Config
-}
-
-func (d *tlsDialer) Dial(network, address string) (net.Conn, error) {
-	return tls.Dial(network, address, d.config)
-}
-
-func newTlsDialer(config *tls.Config) dialerInterface {
-	return &tlsDialer{config: config}
-}
+// Connections to remote exports share a multiplexed session to the mux of
+// each remote host.
+var (
+	muxDialer    = proxy.NewMuxDialer(nil)
+	tlsMuxDialer = proxy.NewMuxDialer(&tls.Config{InsecureSkipVerify: true})
+)
 
 // GetRemoteConnection returns a connection to a remote address
 func GetRemoteConnection(useTLS bool, export *registry.ExportDetails) (remote net.Conn, err error) {
 	var dialer dialerInterface
-	if useTLS && !IsLocalAddress(export.HostIP) {
-		config := tls.Config{InsecureSkipVerify: true}
-		dialer = newTlsDialer(&config)
-	} else {
+	switch {
+	case IsLocalAddress(export.HostIP):
 		dialer = newNetDialer()
+	case useTLS:
+		dialer = tlsMuxDialer
+	default:
+		dialer = muxDialer
 	}
 	return getRemoteConnection(export, dialer)
 }
@@ -117,13 +114,13 @@ func getRemoteConnection(export *registry.ExportDetails, dialer dialerInterface)
 	// If the exported endpoint is on this Host, we don't go through the mux.
 	if IsLocalAddress(export.HostIP) {
 		// if the address is local return a connection directly to the container
-		address := fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber)
-		return dialer.Dial("tcp4", address)
+		address := net.JoinHostPort(export.PrivateIP, strconv.Itoa(int(export.PortNumber)))
+		return dialer.Dial("tcp", address)
 	}
 
 	// Set up the remote address for the mux
-	remoteAddress := fmt.Sprintf("%s:%d", export.HostIP, export.MuxPort)
-	remote, err := dialer.Dial("tcp4", remoteAddress)
+	remoteAddress := net.JoinHostPort(export.HostIP, strconv.Itoa(int(export.MuxPort)))
+	remote, err := dialer.Dial("tcp", remoteAddress)
 
 	// Prevent a panic if we couldn't connect to the mux.
 	if err != nil {
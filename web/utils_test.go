@@ -47,7 +47,7 @@ func (s *TestWebSuite) TestDoNotMuxLocalConnections(c *C) {
 	dialer := &mocks.Dialer{}
 	export := getExportDetails()
 
-	dialer.On("Dial", "tcp4", serviceAddress).Return(unusedConnection, nil)
+	dialer.On("Dial", "tcp", serviceAddress).Return(unusedConnection, nil)
 
 	_, err := getRemoteConnection(&export, dialer)
 
@@ -63,7 +63,7 @@ func (s *TestWebSuite) TestMuxRemoteConnections(c *C) {
 	export := getExportDetails()
 	muxHeader, _ := utils.PackTCPAddress(export.PrivateIP, export.PortNumber)
 	
-	dialer.On("Dial", "tcp4", muxAddress).Return(conn, nil)
+	dialer.On("Dial", "tcp", muxAddress).Return(conn, nil)
 	conn.On("Write", muxHeader).Return(0, nil)
 
 	_, err := getRemoteConnection(&export, dialer)
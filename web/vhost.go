@@ -24,22 +24,26 @@ import (
 
 // VHostManager manages all vhosts on a host
 type VHostManager struct {
-	useTLS bool
-	mu     *sync.RWMutex
-	vhosts map[string]*VHostHandler
+	useTLS      bool
+	maintenance *Maintenance
+	mu          *sync.RWMutex
+	vhosts      map[string]*VHostHandler
 }
 
-// NewVHostManager creates a new vhost manager for a host
-func NewVHostManager(useTLS bool) *VHostManager {
+// NewVHostManager creates a new vhost manager for a host.  Vhosts of tenants
+// in maintenance mode serve the maintenance page, if maintenance is set.
+func NewVHostManager(useTLS bool, maintenance *Maintenance) *VHostManager {
 	return &VHostManager{
-		useTLS: useTLS,
-		mu:     &sync.RWMutex{},
-		vhosts: make(map[string]*VHostHandler),
+		useTLS:      useTLS,
+		maintenance: maintenance,
+		mu:          &sync.RWMutex{},
+		vhosts:      make(map[string]*VHostHandler),
 	}
 }
 
-// Enable implements enables the vhost
-func (m *VHostManager) Enable(name string) {
+// Enable implements enables the vhost of a tenant.  appProtocol is the
+// protocol hint of the vhost (see servicedefinition.AppProtocolHTTP2).
+func (m *VHostManager) Enable(name, tenantID, appProtocol string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -48,7 +52,7 @@ func (m *VHostManager) Enable(name string) {
 		h = NewVHostHandler()
 		m.vhosts[name] = h
 	}
-	h.Enable()
+	h.Enable(tenantID, appProtocol)
 }
 
 // Disable disables the vhost
@@ -91,16 +95,18 @@ func (m *VHostManager) handle(name string, w http.ResponseWriter, r *http.Reques
 	h, ok := m.vhosts[name]
 	if ok {
 		plog.WithField("name", name).Debug("Found VHost handler")
-		return h.Handle(m.useTLS, w, r)
+		return h.Handle(m.useTLS, m.maintenance, w, r)
 	}
 	return false
 }
 
 // VHostHandler manages a vhost endpoint
 type VHostHandler struct {
-	exports Exports
-	mu      *sync.RWMutex
-	enabled bool
+	exports     Exports
+	mu          *sync.RWMutex
+	enabled     bool
+	tenantID    string
+	appProtocol string
 }
 
 // NewVHostHandler instantiates a new vhost handler
@@ -113,10 +119,12 @@ func NewVHostHandler(data ...registry.ExportDetails) *VHostHandler {
 }
 
 // Enable enables a vhost endpoint
-func (h *VHostHandler) Enable() {
+func (h *VHostHandler) Enable(tenantID, appProtocol string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.enabled = true
+	h.tenantID = tenantID
+	h.appProtocol = appProtocol
 }
 
 // Disable disables a vhost endpoint
@@ -132,7 +140,7 @@ func (h *VHostHandler) SetExports(data []registry.ExportDetails) {
 }
 
 // Handle is the vhost handler, returns true if the vhost is enabled
-func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Request) bool {
+func (h *VHostHandler) Handle(useTLS bool, maintenance *Maintenance, w http.ResponseWriter, r *http.Request) bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -141,6 +149,11 @@ func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Reques
 		return false
 	}
 
+	// serve the maintenance page if the tenant is in maintenance mode
+	if maintenance.Serve(h.tenantID, w) {
+		return true
+	}
+
 	// get the next available export
 	export := h.exports.Next()
 	if export == nil {
@@ -154,13 +167,14 @@ func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Reques
 		"application": export.Application,
 		"hostip":      export.HostIP,
 		"privateip":   export.PrivateIP,
+		"appprotocol": h.appProtocol,
 		"request":     r,
 	})
 
 	logger.Debug("Proxying endpoint")
 
 	// get the reverse proxy for the export
-	rp := GetReverseProxy(useTLS, export)
+	rp := GetReverseProxy(useTLS, h.appProtocol, export)
 
 	// Set up the X-Forwarded-Proto header so that downstream servers know
 	// the request originated as HTTPS.
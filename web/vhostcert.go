@@ -0,0 +1,132 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/facade"
+)
+
+// vhostCertificateInterval is how often the uploaded vhost certificates are
+// reloaded
+const vhostCertificateInterval = time.Minute
+
+// certificateCache is the certificates of the vhosts that the https server
+// presents by server name.  Uploaded certificates are presented for the
+// names that they cover, ahead of certificates from the certificate
+// authority.
+type certificateCache struct {
+	mu     sync.RWMutex
+	certs  map[string]*tls.Certificate
+	custom map[string]*tls.Certificate
+}
+
+func newCertificateCache() *certificateCache {
+	return &certificateCache{
+		certs:  make(map[string]*tls.Certificate),
+		custom: make(map[string]*tls.Certificate),
+	}
+}
+
+// Set replaces the certificate of a host name
+func (c *certificateCache) Set(name string, cert *tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certs[name] = cert
+}
+
+// SetCustom replaces the uploaded certificates, indexed by the dns names of
+// their leaves
+func (c *certificateCache) SetCustom(custom map[string]*tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.custom = custom
+}
+
+// GetCertificate returns the certificate of the server name of the tls
+// handshake, or nil to present the default certificate
+func (c *certificateCache) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if cert, ok := c.custom[name]; ok {
+		return cert, nil
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		if cert, ok := c.custom["*"+name[i:]]; ok {
+			return cert, nil
+		}
+	}
+	return c.certs[name], nil
+}
+
+// runVHostCertificates loads the uploaded vhost certificates into the cache
+// until shutdown
+func runVHostCertificates(f facade.FacadeInterface, certs *certificateCache, shutdown <-chan interface{}) {
+	ctx := datastore.Get()
+	for {
+		loadVHostCertificates(ctx, f, certs)
+		select {
+		case <-time.After(vhostCertificateInterval):
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// loadVHostCertificates replaces the uploaded certificates in the cache
+func loadVHostCertificates(ctx datastore.Context, f facade.FacadeInterface, certs *certificateCache) {
+	stored, err := f.GetCertificates(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not load uploaded vhost certificates")
+		return
+	}
+	custom := make(map[string]*tls.Certificate)
+	for _, c := range stored {
+		if c.Issuer != certificate.IssuerCustom {
+			continue
+		}
+		logger := plog.WithField("vhost", c.Name)
+		certPEM, keyPEM, err := f.GetCertificateKeyPair(ctx, c.Name)
+		if err != nil {
+			logger.WithError(err).Warn("Could not load uploaded vhost certificate")
+			continue
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			logger.WithError(err).Warn("Uploaded vhost certificate is not valid")
+			continue
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			logger.WithError(err).Warn("Uploaded vhost certificate is not valid")
+			continue
+		}
+		names := leaf.DNSNames
+		if len(names) == 0 && leaf.Subject.CommonName != "" {
+			names = []string{leaf.Subject.CommonName}
+		}
+		for _, name := range names {
+			custom[strings.ToLower(name)] = &cert
+		}
+	}
+	certs.SetCustom(custom)
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package web
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestLoadVHostCertificates(c *C) {
+	notAfter := time.Now().Add(60 * 24 * time.Hour)
+	customPEM, customKey := testACMEKeyPair(c, "*.example.com", notAfter)
+	acmePEM, acmeKey := testACMEKeyPair(c, "app.example.com", notAfter)
+	s.mockFacade.On("GetCertificates", mock.Anything).Return([]certificate.Certificate{
+		{Name: "app.example.com", NotAfter: notAfter},
+		{Name: "custom", Issuer: certificate.IssuerCustom, NotAfter: notAfter},
+	}, nil)
+	s.mockFacade.On("GetCertificateKeyPair", mock.Anything, "custom").Return(customPEM, customKey, nil)
+
+	certs := newCertificateCache()
+	acmeCert, err := tls.X509KeyPair(acmePEM, acmeKey)
+	c.Assert(err, IsNil)
+	certs.Set("app.example.com", &acmeCert)
+	certs.Set("app.other.com", &acmeCert)
+	loadVHostCertificates(datastore.Get(), s.mockFacade, certs)
+
+	// the uploaded certificate is presented for the names it covers
+	cert, err := certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "app.example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert, NotNil)
+	c.Assert(cert.Certificate[0], DeepEquals, mustLeaf(c, customPEM, customKey))
+
+	// other names present the certificate from the authority, or the default
+	cert, err = certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "app.other.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert, Equals, &acmeCert)
+	cert, err = certs.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	c.Assert(err, IsNil)
+	c.Assert(cert, IsNil)
+}
+
+func mustLeaf(c *C, certPEM, keyPEM []byte) []byte {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	c.Assert(err, IsNil)
+	return cert.Certificate[0]
+}
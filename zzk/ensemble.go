@@ -0,0 +1,89 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zzk
+
+import "github.com/control-center/serviced/coordinator/client"
+
+const ensemblePath = "/ensemble"
+
+// Ensemble is the zookeeper ensemble that the master last reconfigured, so
+// that hosts can find the members that were added after they started.
+type Ensemble struct {
+	Quorum  []string // SERVICED_ISVCS_ZOOKEEPER_QUORUM of the ensemble
+	Servers []string // SERVICED_ZK of the ensemble
+	version interface{}
+}
+
+// Version implements client.Node
+func (node *Ensemble) Version() interface{} { return node.version }
+
+// SetVersion implements client.Node
+func (node *Ensemble) SetVersion(version interface{}) { node.version = version }
+
+// UpdateEnsemble publishes the zookeeper ensemble
+func UpdateEnsemble(conn client.Connection, ensemble Ensemble) error {
+	node := &Ensemble{Quorum: ensemble.Quorum, Servers: ensemble.Servers}
+	if err := conn.Create(ensemblePath, node); err == client.ErrNodeExists {
+		current := &Ensemble{}
+		if err := conn.Get(ensemblePath, current); err != nil && err != client.ErrEmptyNode {
+			return err
+		}
+		current.Quorum, current.Servers = ensemble.Quorum, ensemble.Servers
+		return conn.Set(ensemblePath, current)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetEnsemble returns the published zookeeper ensemble, or nil if the
+// ensemble has never been reconfigured.
+func GetEnsemble(conn client.Connection) (*Ensemble, error) {
+	node := &Ensemble{}
+	if err := conn.Get(ensemblePath, node); err == client.ErrNoNode {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// WatchEnsemble calls update with the published zookeeper ensemble each time
+// it changes, until shutdown is closed.
+func WatchEnsemble(shutdown <-chan interface{}, conn client.Connection, update func(Ensemble)) error {
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		ok, ev, err := conn.ExistsW(ensemblePath, done)
+		if err != nil {
+			return err
+		}
+		if ok {
+			node := &Ensemble{}
+			if ev, err = conn.GetW(ensemblePath, node, done); err == client.ErrNoNode {
+				continue
+			} else if err != nil {
+				return err
+			}
+			update(*node)
+		}
+		select {
+		case <-ev:
+		case <-shutdown:
+			return nil
+		}
+		close(done)
+		done = make(chan struct{})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zzk
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+const masterLeasePath = "/master"
+
+// MasterLeader is the node of a master in the election of the master lease.
+// Only the master that holds the lease starts the internal services and
+// serves the cluster; the others wait to take over.
+type MasterLeader struct {
+	HostID   string
+	Endpoint string    // RPC endpoint of the master
+	Started  time.Time // when the master joined the election
+	version  interface{}
+}
+
+// Version implements client.Node
+func (node *MasterLeader) Version() interface{} { return node.version }
+
+// SetVersion implements client.Node
+func (node *MasterLeader) SetVersion(version interface{}) { node.version = version }
+
+// NewMasterLease returns the election of the master lease
+func NewMasterLease(conn client.Connection) (client.Leader, error) {
+	return conn.NewLeader(masterLeasePath)
+}
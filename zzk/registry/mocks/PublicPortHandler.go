@@ -7,8 +7,8 @@ type PublicPortHandler struct {
 	mock.Mock
 }
 
-func (_m *PublicPortHandler) Enable(port string, protocol string, useTLS bool) {
-	_m.Called(port, protocol, useTLS)
+func (_m *PublicPortHandler) Enable(port string, tenantID string, protocol string, appProtocol string, useTLS bool) {
+	_m.Called(port, tenantID, protocol, appProtocol, useTLS)
 }
 func (_m *PublicPortHandler) Disable(port string) {
 	_m.Called(port)
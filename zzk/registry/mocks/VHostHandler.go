@@ -7,8 +7,8 @@ type VHostHandler struct {
 	mock.Mock
 }
 
-func (_m *VHostHandler) Enable(name string) {
-	_m.Called(name)
+func (_m *VHostHandler) Enable(name string, tenantID string, appProtocol string) {
+	_m.Called(name, tenantID, appProtocol)
 }
 func (_m *VHostHandler) Disable(name string) {
 	_m.Called(name)
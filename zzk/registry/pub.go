@@ -15,17 +15,42 @@ package registry
 
 import (
 	"path"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
 )
 
+// serverNameSep separates the port address from the server name in the name
+// of a public port that is routed by TLS server name
+const serverNameSep = "#"
+
+// PublicPortName returns the name of the node of a public port.  Ports that
+// are routed by TLS server name share their port address, so the server name
+// is part of their name.
+func PublicPortName(portAddr, serverName string) string {
+	if serverName == "" {
+		return portAddr
+	}
+	return portAddr + serverNameSep + strings.ToLower(serverName)
+}
+
+// SplitPublicPortName returns the port address and the server name of the
+// node of a public port.
+func SplitPublicPortName(name string) (string, string) {
+	if i := strings.LastIndex(name, serverNameSep); i >= 0 {
+		return name[:i], name[i+len(serverNameSep):]
+	}
+	return name, ""
+}
+
 // PublicPort describes a public endpoint
 type PublicPort struct {
 	TenantID    string
 	Application string
 	ServiceID   string // TODO: search by tenant and application
 	Protocol    string
+	AppProtocol string // protocol spoken by the backend of an http port
 	UseTLS      bool
 	version     interface{}
 }
@@ -42,7 +67,7 @@ func (node *PublicPort) SetVersion(version interface{}) {
 
 // PublicPortHandler manages a public port and its exports
 type PublicPortHandler interface {
-	Enable(port string, protocol string, useTLS bool)
+	Enable(port, tenantID, protocol, appProtocol string, useTLS bool)
 	Disable(port string)
 	Set(port string, exports []ExportDetails)
 }
@@ -191,7 +216,7 @@ func (l *PublicPortListener) Spawn(shutdown <-chan interface{}, portAddr string)
 		}
 
 		if !isEnabled {
-			l.handler.Enable(portAddr, dat.Protocol, dat.UseTLS)
+			l.handler.Enable(portAddr, dat.TenantID, dat.Protocol, dat.AppProtocol, dat.UseTLS)
 			logger.Debug("Enabled port")
 			isEnabled = true
 		}
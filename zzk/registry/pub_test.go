@@ -35,11 +35,12 @@ func (t *ZZKTest) TestPublicPortListener(c *C) {
 	listener := NewPublicPortListener("master", handler)
 	listener.SetConnection(conn)
 
-	handler.On("Enable", "10.187.22.151:2181", "proto", true).Return().Once()
+	handler.On("Enable", "10.187.22.151:2181", "tenantid", "proto", "grpc", true).Return().Once()
 	publicPort := &PublicPort{
 		TenantID:    "tenantid",
 		Application: "app",
 		Protocol:    "proto",
+		AppProtocol: "grpc",
 		UseTLS:      true,
 	}
 	err = conn.Create("/net/pub/master/10.187.22.151:2181", publicPort)
@@ -39,7 +39,7 @@ func (err RegistryError) Error() string {
 // PublicPortKey points to a specific public port node
 type PublicPortKey struct {
 	HostID      string
-	PortAddress string
+	PortAddress string // the name of the node; see PublicPortName
 }
 
 // VHostKey points to a specific vhost node
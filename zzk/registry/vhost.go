@@ -25,6 +25,7 @@ type VHost struct {
 	TenantID    string
 	ServiceID   string
 	Application string
+	AppProtocol string // protocol spoken by the backend
 	version     interface{}
 }
 
@@ -40,7 +41,7 @@ func (node *VHost) SetVersion(version interface{}) {
 
 // VHostHandler manages the vhosts for a host
 type VHostHandler interface {
-	Enable(name string)
+	Enable(name, tenantID, appProtocol string)
 	Disable(name string)
 	Set(name string, exports []ExportDetails)
 }
@@ -95,8 +96,10 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 	// looked up.
 	exportMap := make(map[string]ExportDetails)
 
-	// keep track of the on/off state of the export
+	// keep track of the on/off state of the export, and the tenant and the
+	// protocol of its backend
 	isEnabled := false
+	tenantID, appProtocol := "", ""
 	defer func() {
 		if isEnabled {
 			l.handler.Disable(subdomain)
@@ -189,10 +192,14 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 		}
 
 		// do something if the state of the vhost has changed
-		if !isEnabled {
-			l.handler.Enable(subdomain)
-			logger.Debug("Enabled vhost")
+		if !isEnabled || dat.TenantID != tenantID || dat.AppProtocol != appProtocol {
+			l.handler.Enable(subdomain, dat.TenantID, dat.AppProtocol)
+			logger.WithFields(log.Fields{
+				"tenantid":    dat.TenantID,
+				"appprotocol": dat.AppProtocol,
+			}).Debug("Enabled vhost")
 			isEnabled = true
+			tenantID, appProtocol = dat.TenantID, dat.AppProtocol
 		}
 
 		select {
@@ -35,7 +35,7 @@ func (t *ZZKTest) TestVHostListener(c *C) {
 	listener := NewVHostListener("master", handler)
 	listener.SetConnection(conn)
 
-	handler.On("Enable", "myhost").Return().Once()
+	handler.On("Enable", "myhost", "tenantid", "").Return().Once()
 	vhost := &VHost{
 		TenantID:    "tenantid",
 		Application: "app",
@@ -85,6 +85,21 @@ func (t *ZZKTest) TestVHostListener(c *C) {
 	case <-timer.C:
 	}
 
+	// the protocol of the backend changed
+	handler.On("Enable", "myhost", "tenantid", "http2").Return().Once()
+	err = conn.Get("/net/vhost/master/myhost", vhost)
+	c.Assert(err, IsNil)
+	vhost.AppProtocol = "http2"
+	err = conn.Set("/net/vhost/master/myhost", vhost)
+	c.Assert(err, IsNil)
+
+	timer.Reset(time.Second)
+	select {
+	case <-done:
+		c.Fatalf("Listener exited unexpectedly")
+	case <-timer.C:
+	}
+
 	// shutdown
 	handler.On("Disable", "myhost").Return().Once()
 
@@ -185,6 +185,18 @@ func (h *ZKAssignmentHandler) assignToHost(poolID, ipAddress, netmask, binding s
 	return CreateIP(h.connection, request, netmask, binding)
 }
 
+// ExcludeHost keeps the virtual IP from being assigned to the host for the
+// given duration, as when the virtual IP was unreachable on the host.
+func (h *ZKAssignmentHandler) ExcludeHost(ipAddress, hostID string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.timeouts[ipAddress]; !ok {
+		h.timeouts[ipAddress] = make(map[string]time.Time)
+	}
+	h.timeouts[ipAddress][hostID] = time.Now().Add(d)
+}
+
 func (h *ZKAssignmentHandler) addExcludeHost(ipAddress string, host host.Host) {
 	if _, ok := h.timeouts[ipAddress]; !ok {
 		h.timeouts[ipAddress] = make(map[string]time.Time)
@@ -0,0 +1,185 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/pool"
+)
+
+// MaxInstanceEvents is the maximum number of events kept on the timeline of
+// a service.  Older events are discarded as new events are added.
+const MaxInstanceEvents = 25
+
+// Event types recorded on the service timeline
+const (
+	// EventStartFailed is recorded when an instance does not start within
+	// the service's start timeout.
+	EventStartFailed = "start_failed"
+)
+
+// InstanceEvent is an entry on the event timeline of a service
+type InstanceEvent struct {
+	Timestamp  time.Time
+	Type       string
+	HostID     string
+	InstanceID int
+	Message    string
+	Logs       string // Tail of the container logs, if available
+	Inspect    string // Output of docker inspect, if available
+}
+
+// ServiceEvents is the event timeline of a service, ordered from oldest to
+// newest.
+type ServiceEvents struct {
+	Events  []InstanceEvent
+	version interface{}
+}
+
+// Version implements client.Node
+func (e *ServiceEvents) Version() interface{} {
+	return e.version
+}
+
+// SetVersion implements client.Node
+func (e *ServiceEvents) SetVersion(version interface{}) {
+	e.version = version
+}
+
+// AddInstanceEvent appends an event to the timeline of a service.
+func AddInstanceEvent(conn client.Connection, serviceID string, evt InstanceEvent) error {
+	logger := plog.WithFields(log.Fields{
+		"serviceid":  serviceID,
+		"instanceid": evt.InstanceID,
+		"type":       evt.Type,
+	})
+
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	pth := path.Join("/events", serviceID)
+	dat := &ServiceEvents{}
+	if err := conn.Get(pth, dat); err == client.ErrNoNode {
+		dat.Events = []InstanceEvent{evt}
+		if err := conn.Create(pth, dat); err != nil {
+			logger.WithError(err).Debug("Could not create event timeline")
+			return err
+		}
+		logger.Debug("Created event timeline")
+		return nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up event timeline")
+		return err
+	}
+
+	dat.Events = append(dat.Events, evt)
+	if n := len(dat.Events); n > MaxInstanceEvents {
+		dat.Events = dat.Events[n-MaxInstanceEvents:]
+	}
+	if err := conn.Set(pth, dat); err != nil {
+		logger.WithError(err).Debug("Could not update event timeline")
+		return err
+	}
+	logger.Debug("Added event to timeline")
+	return nil
+}
+
+// GetInstanceEvents returns the event timeline of a service.
+func GetInstanceEvents(conn client.Connection, serviceID string) ([]InstanceEvent, error) {
+	dat := &ServiceEvents{}
+	if err := conn.Get(path.Join("/events", serviceID), dat); err == client.ErrNoNode {
+		return []InstanceEvent{}, nil
+	} else if err != nil {
+		plog.WithField("serviceid", serviceID).WithError(err).Debug("Could not look up event timeline")
+		return nil, err
+	}
+	return dat.Events, nil
+}
+
+// MaxVirtualIPEvents is the maximum number of events kept on the virtual IP
+// timeline of a pool.
+const MaxVirtualIPEvents = 50
+
+// VirtualIPEvents is the virtual IP timeline of a pool, ordered from oldest
+// to newest.
+type VirtualIPEvents struct {
+	Events  []pool.VirtualIPEvent
+	version interface{}
+}
+
+// Version implements client.Node
+func (e *VirtualIPEvents) Version() interface{} {
+	return e.version
+}
+
+// SetVersion implements client.Node
+func (e *VirtualIPEvents) SetVersion(version interface{}) {
+	e.version = version
+}
+
+// AddVirtualIPEvent appends an event to the virtual IP timeline of a pool.
+func AddVirtualIPEvent(conn client.Connection, poolID string, evt pool.VirtualIPEvent) error {
+	logger := plog.WithFields(log.Fields{
+		"poolid":    poolID,
+		"ipaddress": evt.IPAddress,
+		"type":      evt.Type,
+	})
+
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+
+	pth := Base().Pools().ID(poolID).Events().Path()
+	dat := &VirtualIPEvents{}
+	if err := conn.Get(pth, dat); err == client.ErrNoNode {
+		dat.Events = []pool.VirtualIPEvent{evt}
+		if err := conn.Create(pth, dat); err != nil {
+			logger.WithError(err).Debug("Could not create virtual ip timeline")
+			return err
+		}
+		logger.Debug("Created virtual ip timeline")
+		return nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up virtual ip timeline")
+		return err
+	}
+
+	dat.Events = append(dat.Events, evt)
+	if n := len(dat.Events); n > MaxVirtualIPEvents {
+		dat.Events = dat.Events[n-MaxVirtualIPEvents:]
+	}
+	if err := conn.Set(pth, dat); err != nil {
+		logger.WithError(err).Debug("Could not update virtual ip timeline")
+		return err
+	}
+	logger.Debug("Added event to virtual ip timeline")
+	return nil
+}
+
+// GetVirtualIPEvents returns the virtual IP timeline of a pool.
+func GetVirtualIPEvents(conn client.Connection, poolID string) ([]pool.VirtualIPEvent, error) {
+	dat := &VirtualIPEvents{}
+	if err := conn.Get(Base().Pools().ID(poolID).Events().Path(), dat); err == client.ErrNoNode {
+		return []pool.VirtualIPEvent{}, nil
+	} else if err != nil {
+		plog.WithField("poolid", poolID).WithError(err).Debug("Could not look up virtual ip timeline")
+		return nil, err
+	}
+	return dat.Events, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/service"
+)
+
+// MaxInstanceHistory is the maximum number of records kept in the instance
+// history of a service.  Older records are discarded as new ones are added.
+const MaxInstanceHistory = 100
+
+// ServiceInstanceHistory is the instance history of a service, ordered from
+// oldest to newest.
+type ServiceInstanceHistory struct {
+	Records []service.InstanceHistory
+	version interface{}
+}
+
+// Version implements client.Node
+func (h *ServiceInstanceHistory) Version() interface{} {
+	return h.version
+}
+
+// SetVersion implements client.Node
+func (h *ServiceInstanceHistory) SetVersion(version interface{}) {
+	h.version = version
+}
+
+func historyPath(poolID, serviceID string) string {
+	basepth := "/"
+	if poolID != "" {
+		basepth = path.Join("/pools", poolID)
+	}
+	return path.Join(basepth, "/history", serviceID)
+}
+
+// updateInstanceHistory applies mutate to the instance history of a service
+// and saves the result if mutate returns true.
+func updateInstanceHistory(conn client.Connection, poolID, serviceID string, mutate func(*ServiceInstanceHistory) bool) error {
+	pth := historyPath(poolID, serviceID)
+	dat := &ServiceInstanceHistory{}
+	err := conn.Get(pth, dat)
+	if err != nil && err != client.ErrNoNode {
+		return err
+	}
+	exists := err == nil
+
+	if !mutate(dat) {
+		return nil
+	}
+	if n := len(dat.Records); n > MaxInstanceHistory {
+		dat.Records = dat.Records[n-MaxInstanceHistory:]
+	}
+
+	if exists {
+		return conn.Set(pth, dat)
+	}
+	return conn.Create(pth, dat)
+}
+
+// RecordInstanceStart adds a record to the history of a service when an
+// instance is scheduled to a host.
+func RecordInstanceStart(conn client.Connection, req StateRequest) error {
+	logger := plog.WithFields(log.Fields{
+		"hostid":     req.HostID,
+		"serviceid":  req.ServiceID,
+		"instanceid": req.InstanceID,
+	})
+
+	if err := updateInstanceHistory(conn, req.PoolID, req.ServiceID, func(h *ServiceInstanceHistory) bool {
+		h.Records = append(h.Records, service.InstanceHistory{
+			InstanceID: req.InstanceID,
+			HostID:     req.HostID,
+			Started:    time.Now(),
+		})
+		return true
+	}); err != nil {
+		logger.WithError(err).Debug("Could not record instance start")
+		return err
+	}
+
+	logger.Debug("Recorded instance start")
+	return nil
+}
+
+// RecordInstanceStop sets the stop time on the open history record of an
+// instance when it is removed from a host.
+func RecordInstanceStop(conn client.Connection, req StateRequest) error {
+	logger := plog.WithFields(log.Fields{
+		"hostid":     req.HostID,
+		"serviceid":  req.ServiceID,
+		"instanceid": req.InstanceID,
+	})
+
+	if err := updateInstanceHistory(conn, req.PoolID, req.ServiceID, func(h *ServiceInstanceHistory) bool {
+		for i := len(h.Records) - 1; i >= 0; i-- {
+			r := &h.Records[i]
+			if r.InstanceID == req.InstanceID && r.HostID == req.HostID && r.Stopped.IsZero() {
+				r.Stopped = time.Now()
+				return true
+			}
+		}
+		return false
+	}); err != nil {
+		logger.WithError(err).Debug("Could not record instance stop")
+		return err
+	}
+
+	logger.Debug("Recorded instance stop")
+	return nil
+}
+
+// GetInstanceHistory returns the instance history of a service.
+func GetInstanceHistory(conn client.Connection, poolID, serviceID string) ([]service.InstanceHistory, error) {
+	dat := &ServiceInstanceHistory{}
+	if err := conn.Get(historyPath(poolID, serviceID), dat); err == client.ErrNoNode {
+		return []service.InstanceHistory{}, nil
+	} else if err != nil {
+		plog.WithFields(log.Fields{
+			"poolid":    poolID,
+			"serviceid": serviceID,
+		}).WithError(err).Debug("Could not look up instance history")
+		return nil, err
+	}
+	return dat.Records, nil
+}
+
+// GetLastInstanceHost returns the host that an instance of a service most
+// recently ran on, or an empty string if the instance has no history.
+func GetLastInstanceHost(conn client.Connection, poolID, serviceID string, instanceID int) (string, error) {
+	records, err := GetInstanceHistory(conn, poolID, serviceID)
+	if err != nil {
+		return "", err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].InstanceID == instanceID {
+			return records[i].HostID, nil
+		}
+	}
+	return "", nil
+}
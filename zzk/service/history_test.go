@@ -0,0 +1,78 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestInstanceHistory(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	err = conn.CreateDir("/pools/poolid/services/serviceid")
+	c.Assert(err, IsNil)
+	err = conn.CreateDir("/pools/poolid/hosts/hostid1")
+	c.Assert(err, IsNil)
+	err = conn.CreateDir("/pools/poolid/hosts/hostid2")
+	c.Assert(err, IsNil)
+
+	// no history
+	history, err := GetInstanceHistory(conn, "poolid", "serviceid")
+	c.Assert(err, IsNil)
+	c.Assert(history, HasLen, 0)
+	hostID, err := GetLastInstanceHost(conn, "poolid", "serviceid", 0)
+	c.Assert(err, IsNil)
+	c.Assert(hostID, Equals, "")
+
+	// start instance 0 on host 1
+	req := StateRequest{
+		PoolID:     "poolid",
+		HostID:     "hostid1",
+		ServiceID:  "serviceid",
+		InstanceID: 0,
+	}
+	err = CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	history, err = GetInstanceHistory(conn, "poolid", "serviceid")
+	c.Assert(err, IsNil)
+	c.Assert(history, HasLen, 1)
+	c.Check(history[0].InstanceID, Equals, 0)
+	c.Check(history[0].HostID, Equals, "hostid1")
+	c.Check(history[0].Started.IsZero(), Equals, false)
+	c.Check(history[0].Stopped.IsZero(), Equals, true)
+
+	// stop it and start it again on host 2
+	err = DeleteState(conn, req)
+	c.Assert(err, IsNil)
+	req.HostID = "hostid2"
+	err = CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	history, err = GetInstanceHistory(conn, "poolid", "serviceid")
+	c.Assert(err, IsNil)
+	c.Assert(history, HasLen, 2)
+	c.Check(history[0].Stopped.IsZero(), Equals, false)
+	c.Check(history[1].HostID, Equals, "hostid2")
+	c.Check(history[1].Stopped.IsZero(), Equals, true)
+
+	hostID, err = GetLastInstanceHost(conn, "poolid", "serviceid", 0)
+	c.Assert(err, IsNil)
+	c.Assert(hostID, Equals, "hostid2")
+}
@@ -269,6 +269,39 @@ func SyncHosts(conn client.Connection, hosts []host.Host) error {
 	return nil
 }
 
+// GetHosts returns the hosts that are stored in a resource pool, whether or
+// not they are currently active.
+func GetHosts(conn client.Connection, poolid string) ([]host.Host, error) {
+	pth := path.Join("/pools", poolid, "hosts")
+
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolid,
+		"zkpath": pth,
+	})
+
+	ch, err := conn.Children(pth)
+	if err == client.ErrNoNode {
+		return []host.Host{}, nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not look up hosts in resource pool")
+		return nil, err
+	}
+
+	hosts := []host.Host{}
+	for _, hostid := range ch {
+		h := host.Host{}
+		if err := conn.Get(path.Join(pth, hostid), &HostNode{Host: &h}); err == client.ErrEmptyNode {
+			logger.WithField("hostid", hostid).Debug("Skipping host without data")
+			continue
+		} else if err != nil {
+			logger.WithField("hostid", hostid).WithError(err).Debug("Could not get host entry from zookeeper")
+			return nil, err
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
+
 // GetCurrentHosts returns the list of hosts that are currently active in a
 // resource pool.
 func GetCurrentHosts(conn client.Connection, poolid string) ([]string, error) {
@@ -176,6 +176,15 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 			isLocked = len(ch) > 0
 		}
 
+		// a host in maintenance mode cannot receive new services either
+		// path: /pools/<poolid>/hosts/<hostid>/maintenance
+		inMaintenance, maintev, err := h.conn.ExistsW(h.GetPath(hostid, "maintenance"), stop)
+		if err != nil {
+
+			logger.WithError(err).Error("Could not check maintenance status of host")
+			return
+		}
+
 		// clean up invalid states and find out if the host is running anything
 		// path: /pools/<poolid>/hosts/<hostid>/instances
 		if err := CleanHostStates(h.conn, h.poolid, hostid); err != nil {
@@ -192,17 +201,18 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 		isRunning := len(ch) > 0
 
 		eventLogger := plog.WithFields(log.Fields{
-			"poolid":    h.poolid,
-			"hostid":    hostid,
-			"isonline":  isOnline,
-			"islocked":  isLocked,
-			"isrunning": isRunning,
+			"poolid":        h.poolid,
+			"hostid":        hostid,
+			"isonline":      isOnline,
+			"islocked":      isLocked,
+			"inmaintenance": inMaintenance,
+			"isrunning":     isRunning,
 		})
 		eventLogger.Debug("Waiting for host event")
 
 		if isOnline {
 
-			if !isLocked {
+			if !isLocked && !inMaintenance {
 
 				// If the host is online, try to tell someone who cares.
 				// Expectedly, this is not something that should be in high
@@ -210,6 +220,7 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 				select {
 				case h.isOnline <- struct{}{}:
 				case <-lockev:
+				case <-maintev:
 				case <-availEv:
 				case <-onlineEv:
 				case <-cancel:
@@ -223,6 +234,7 @@ func (h *HostRegistryListener) Spawn(cancel <-chan interface{}, hostid string) {
 				// freed.
 				select {
 				case <-lockev:
+				case <-maintev:
 				case <-availEv:
 				case <-onlineEv:
 				case <-cancel:
@@ -392,6 +404,19 @@ func GetRegisteredHostsForPool(conn client.Connection, poolID string) ([]host.Ho
 			}
 
 			isLocked := len(ch) > 0
+
+			// hosts in maintenance mode cannot receive new instances
+			if !isLocked {
+				isLocked, err = IsHostInMaintenance(conn, poolID, hostid)
+				if err != nil {
+
+					hstlog.WithError(err).Debug("Could not check if host is in maintenance mode")
+
+					// TODO: wrap error?
+					return nil, err
+				}
+			}
+
 			if !isLocked {
 				hdat := host.Host{}
 				err := conn.Get(Base().Pools().ID(poolID).Hosts().ID(hostid).Path(), &HostNode{Host: &hdat})
@@ -68,6 +68,8 @@ type HostStateListener struct {
 		exited <-chan time.Time
 	}
 	shutdowncomplete chan interface{}
+	preserve         bool
+	starts           *StartQueue
 }
 
 // NewHostStateListener instantiates a HostStateListener object
@@ -82,11 +84,30 @@ func NewHostStateListener(handler HostStateHandler, hostID string, shutdown <-ch
 			exited <-chan time.Time
 		}),
 		shutdowncomplete: make(chan interface{}),
+		starts:           NewStartQueue(0),
 	}
 	go l.watchForShutdown()
 	return l
 }
 
+// PreserveContainers keeps the containers running when the listener shuts
+// down, so that they can be re-adopted by the next agent on this host.
+func (l *HostStateListener) PreserveContainers(preserve bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.preserve = preserve
+}
+
+// SetStartLimit limits the number of containers that the listener starts at
+// the same time.  The instances that are waiting to start are started in the
+// order of the priority classes of their services.  A limit of 0 or less does
+// not limit the starts.
+func (l *HostStateListener) SetStartLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.starts = NewStartQueue(limit)
+}
+
 // GetConnection implements zzk.Listener
 func (l *HostStateListener) SetConnection(conn client.Connection) { l.conn = conn }
 
@@ -300,13 +321,24 @@ func (l *HostStateListener) setInstanceState(containerExit <-chan time.Time, ssd
 		if !l.setExistingThreadOrShutdown(stateID, ssdat, containerExit) {
 			return nil, nil, false
 		}
+
+		if containerExit != nil {
+			logger.WithField("containerid", ssdat.ContainerID).Info("Adopted running container")
+		}
 	}
 
 	switch hsdat.DesiredState {
 	case service.SVCRun:
 		if containerExit == nil {
 			// container is detached because it doesn't exist
+			l.mu.RLock()
+			starts := l.starts
+			l.mu.RUnlock()
+			if !starts.Acquire(l.shutdown, hsdat.Priority) {
+				return nil, nil, false
+			}
 			ssdat, containerExit, err = l.handler.StartContainer(l.shutdown, serviceID, instanceID)
+			starts.Release()
 			if err != nil {
 				logger.WithError(err).Error("Could not start container")
 				l.cleanUpContainers([]string{stateID}, true)
@@ -600,7 +632,15 @@ func (l *HostStateListener) watchForShutdown() {
 	defer l.mu.Unlock()
 
 	stateIDs := l.getExistingThreadStateIDs()
-	l.cleanUpContainers(stateIDs, false)
+	if l.preserve {
+		// leave the containers and their states in place for the next agent
+		for _, s := range stateIDs {
+			l.removeExistingThread(s)
+		}
+		plog.WithField("count", len(stateIDs)).Info("Left containers running to be re-adopted")
+	} else {
+		l.cleanUpContainers(stateIDs, false)
+	}
 	close(l.shutdowncomplete)
 }
 
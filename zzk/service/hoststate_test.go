@@ -1386,6 +1386,110 @@ func (t *ZZKTest) TestHostStateListener_Spawn_Cancel_Shutdown(c *C) {
 	handler.AssertExpectations(c) //this makes sure StopContainer was called
 }
 
+// Test Case: shutdown leaves preserved containers running
+func (t *ZZKTest) TestHostStateListener_Spawn_PreserveShutdown(c *C) {
+
+	conn := setUpServiceAndHostPaths(c)
+	handler := &mocks.HostStateHandler{}
+	shutdown := make(chan interface{})
+
+	req := StateRequest{
+		HostID:     hostId,
+		ServiceID:  serviceId,
+		InstanceID: 1,
+	}
+	err := CreateState(conn, req)
+	c.Assert(err, IsNil)
+
+	cancel := make(chan interface{})
+	listener := NewHostStateListener(handler, hostId, shutdown)
+	listener.SetConnection(conn)
+	listener.PreserveContainers(true)
+
+	// set up a running container
+	ssdat := &ServiceState{
+		ContainerID: containerId,
+		ImageUUID:   imageId,
+		Paused:      false,
+		Started:     time.Now(),
+	}
+	err = UpdateState(conn, req, func(s *State) bool {
+		s.DesiredState = service.SVCRun
+		s.ServiceState = *ssdat
+		return true
+	})
+	c.Assert(err, IsNil)
+
+	containerExit := make(chan time.Time, 1)
+	var retExit <-chan time.Time = containerExit
+
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
+	done := make(chan struct{})
+
+	go func() {
+		listener.Spawn(cancel, req.StateID())
+		close(done)
+	}()
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-done:
+		c.Fatalf("Listener exit")
+	case <-timer.C:
+	}
+
+	// Shutdown without stopping the container
+	shutdowndone := listener.GetShutdownComplete()
+	close(shutdown)
+	timer.Reset(time.Second)
+	select {
+	case <-shutdowndone:
+		c.Logf("Listener shut down, checking that the state was kept")
+		ok, err := conn.Exists("/services/serviceid/" + req.StateID())
+		c.Assert(err, IsNil)
+		c.Check(ok, Equals, true)
+	case <-timer.C:
+		c.Fatalf("Listener shut down took too long")
+	}
+
+	// Make sure spawn thread exited
+	timer.Reset(time.Second)
+	select {
+	case <-done:
+		c.Logf("Spawn thread exited")
+	case <-timer.C:
+		c.Fatalf("Spawn thread did not exit")
+	}
+
+	// A new listener re-adopts the running container
+	shutdown = make(chan interface{})
+	cancel = make(chan interface{})
+	listener = NewHostStateListener(handler, hostId, shutdown)
+	listener.SetConnection(conn)
+	listener.PreserveContainers(true)
+	handler.On("AttachContainer", mock.AnythingOfType("*service.ServiceState"), serviceId, 1).Return(retExit, nil).Once()
+	done = make(chan struct{})
+
+	go func() {
+		listener.Spawn(cancel, req.StateID())
+		close(done)
+	}()
+
+	timer.Reset(time.Second)
+	select {
+	case <-done:
+		c.Fatalf("Listener exit")
+	case <-timer.C:
+	}
+
+	close(cancel)
+	close(shutdown)
+	<-done
+	<-listener.GetShutdownComplete()
+
+	handler.AssertExpectations(c) // this makes sure StopContainer was never called
+}
+
 // Test Case: spawn after shutdown does nothing
 func (t *ZZKTest) TestHostStateListener_Shutdown_Spawn(c *C) {
 
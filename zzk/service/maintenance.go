@@ -0,0 +1,81 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// HostMaintenance marks a host as unschedulable while it is being serviced
+type HostMaintenance struct {
+	Started time.Time
+	version interface{}
+}
+
+// Version implements client.Node
+func (m *HostMaintenance) Version() interface{} {
+	return m.version
+}
+
+// SetVersion implements client.Node
+func (m *HostMaintenance) SetVersion(version interface{}) {
+	m.version = version
+}
+
+// SetHostMaintenance enables or disables maintenance mode on a host.  No new
+// instances are scheduled to a host in maintenance mode. (uses a root-based
+// connection)
+func SetHostMaintenance(conn client.Connection, poolID, hostID string, enabled bool) error {
+	pth := Base().Pools().ID(poolID).Hosts().ID(hostID).Maintenance().Path()
+
+	logger := plog.WithFields(log.Fields{
+		"poolid": poolID,
+		"hostid": hostID,
+		"zkpath": pth,
+	})
+
+	if enabled {
+		if ok, err := conn.Exists(Base().Pools().ID(poolID).Hosts().ID(hostID).Path()); err != nil {
+			logger.WithError(err).Debug("Could not look up host")
+			return err
+		} else if !ok {
+			logger.Debug("Host not found")
+			return client.ErrNoNode
+		}
+
+		err := conn.Create(pth, &HostMaintenance{Started: time.Now()})
+		if err != nil && err != client.ErrNodeExists {
+			logger.WithError(err).Debug("Could not enable maintenance mode on host")
+			return err
+		}
+		logger.Debug("Enabled maintenance mode on host")
+		return nil
+	}
+
+	if err := conn.Delete(pth); err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Debug("Could not disable maintenance mode on host")
+		return err
+	}
+	logger.Debug("Disabled maintenance mode on host")
+	return nil
+}
+
+// IsHostInMaintenance returns true if the host is in maintenance mode. (uses
+// a root-based connection)
+func IsHostInMaintenance(conn client.Connection, poolID, hostID string) (bool, error) {
+	return conn.Exists(Base().Pools().ID(poolID).Hosts().ID(hostID).Maintenance().Path())
+}
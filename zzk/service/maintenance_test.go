@@ -0,0 +1,79 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"path"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestHostMaintenance(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	// enabling maintenance on a host that doesn't exist fails
+	err = SetHostMaintenance(conn, "poolid", "hostid", true)
+	c.Assert(err, Equals, client.ErrNoNode)
+
+	// set up an online host
+	h := &host.Host{
+		ID:        "hostid",
+		PoolID:    "poolid",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	hpth := path.Join("/pools/poolid/hosts", h.ID)
+	err = conn.Create(hpth, &HostNode{Host: h})
+	c.Assert(err, IsNil)
+	_, err = conn.CreateEphemeral(path.Join(hpth, "online", h.ID), &client.Dir{})
+	c.Assert(err, IsNil)
+
+	hosts, err := GetRegisteredHostsForPool(conn, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(hosts, HasLen, 1)
+
+	// enable maintenance; the host is no longer schedulable
+	err = SetHostMaintenance(conn, "poolid", "hostid", true)
+	c.Assert(err, IsNil)
+	ok, err := IsHostInMaintenance(conn, "poolid", "hostid")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	// enabling it again is a no-op
+	err = SetHostMaintenance(conn, "poolid", "hostid", true)
+	c.Assert(err, IsNil)
+
+	hosts, err = GetRegisteredHostsForPool(conn, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(hosts, HasLen, 0)
+
+	// disable maintenance; the host is schedulable again
+	err = SetHostMaintenance(conn, "poolid", "hostid", false)
+	c.Assert(err, IsNil)
+	ok, err = IsHostInMaintenance(conn, "poolid", "hostid")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	hosts, err = GetRegisteredHostsForPool(conn, "poolid")
+	c.Assert(err, IsNil)
+	c.Assert(hosts, HasLen, 1)
+}
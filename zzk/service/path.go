@@ -63,6 +63,16 @@ func (p *ZKPath) Locked() *ZKPath {
 	return p.concat("locked")
 }
 
+// Maintenance appends the node name for maintenance to the zookeeper path.
+func (p *ZKPath) Maintenance() *ZKPath {
+	return p.concat("maintenance")
+}
+
+// Events appends the node name for events to the zookeeper path.
+func (p *ZKPath) Events() *ZKPath {
+	return p.concat("events")
+}
+
 // ID appends the given id to the zookeeper path.  If the string is empty,
 // the method will add nothing to the path.  If this behavior is not desired,
 // then checks for a empty string should be done before this method is called.
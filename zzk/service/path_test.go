@@ -35,6 +35,7 @@ func (s *ZKPathUnitTestSuite) TestPathReturnsCorrectValuesForNodes(c *C) {
 		{Base().VirtualIPs().Path(), "/virtualIPs"},
 		{Base().IPs().Path(), "/ips"},
 		{Base().Online().Path(), "/online"},
+		{Base().Events().Path(), "/events"},
 	}
 
 	for _, v := range values {
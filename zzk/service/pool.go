@@ -19,6 +19,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/zzk"
 )
 
 // PoolNode is the storage object for resource pool data
@@ -96,42 +97,47 @@ func RemoveResourcePool(conn client.Connection, poolid string) error {
 	return nil
 }
 
-// SyncResourcePools synchronizes the resource pools to the provided list
-func SyncResourcePools(conn client.Connection, pools []pool.ResourcePool) error {
+// GetResourcePools returns the resource pools that are stored in the
+// coordinator.
+func GetResourcePools(conn client.Connection) ([]pool.ResourcePool, error) {
 	pth := path.Join("/pools")
 
 	logger := plog.WithField("zkpath", pth)
 
-	// look up the children pool ids
 	ch, err := conn.Children(pth)
-	if err != nil && err != client.ErrNoNode {
+	if err == client.ErrNoNode {
+		return []pool.ResourcePool{}, nil
+	} else if err != nil {
 		logger.WithError(err).Debug("Could not look up resource pools")
-		return err
+		return nil, err
 	}
 
-	// store the pool ids in a hash map
-	chmap := make(map[string]struct{})
+	pools := []pool.ResourcePool{}
 	for _, poolid := range ch {
-		chmap[poolid] = struct{}{}
-	}
-
-	// set the resource pools
-	for _, p := range pools {
-		if err := UpdateResourcePool(conn, p); err != nil {
-			return err
+		p := pool.ResourcePool{}
+		if err := conn.Get(path.Join(pth, poolid), &PoolNode{ResourcePool: &p}); err == client.ErrEmptyNode {
+			logger.WithField("poolid", poolid).Debug("Skipping resource pool without data")
+			continue
+		} else if err != nil {
+			logger.WithField("poolid", poolid).WithError(err).Debug("Could not get resource pool entry from zookeeper")
+			return nil, err
 		}
+		pools = append(pools, p)
+	}
+	return pools, nil
+}
 
-		// delete matching records
-		if _, ok := chmap[p.ID]; ok {
-			delete(chmap, p.ID)
-		}
+// SyncResourcePools synchronizes the resource pools to the provided list.
+// Only the pools that changed are written, in batched transactions.
+func SyncResourcePools(conn client.Connection, pools []pool.ResourcePool) error {
+	nodes := make([]zzk.SyncNode, len(pools))
+	for i := range pools {
+		nodes[i] = &PoolNode{ResourcePool: &pools[i]}
 	}
 
-	// remove any leftovers
-	for poolid := range chmap {
-		if err := RemoveResourcePool(conn, poolid); err != nil {
-			return err
-		}
+	if err := zzk.Sync(conn, nodes, "/pools"); err != nil {
+		plog.WithError(err).Debug("Could not sync resource pools")
+		return err
 	}
 	return nil
 }
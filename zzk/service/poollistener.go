@@ -172,5 +172,7 @@ func StartPoolListener(shutdown <-chan interface{}, connection client.Connection
 	synchronizer := NewZKVirtualIPSynchronizer(assignmentHandler)
 	poolListener := NewPoolListener(synchronizer)
 
-	zzk.Start(shutdown, connection, poolListener)
+	monitor := NewVirtualIPMonitor(assignmentHandler, NewRegisteredHostHandler(connection), CommandChecker{})
+
+	zzk.Start(shutdown, connection, poolListener, monitor)
 }
@@ -16,6 +16,7 @@ package service
 import (
 	"fmt"
 	"path"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
@@ -23,6 +24,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/zzk"
 )
 
 // ServiceError manages service errors
@@ -42,12 +44,18 @@ type ServiceNode struct {
 	Name                        string
 	DesiredState                int
 	HostPolicy                  servicedefinition.HostPolicy
+	HostAffinity                map[string]string
+	HostAntiAffinity            map[string]string
+	NodeSelector                map[string]string
 	Instances                   int
 	RAMCommitment               utils.EngNotation
 	CPUCommitment               int
+	CPURequest                  float64
 	ChangeOptions               []servicedefinition.ChangeOption
 	AddressAssignment           addressassignment.AddressAssignment
 	ShouldHaveAddressAssignment bool
+	PriorityClass               string
+	UpdatedAt                   time.Time // changes when the service is edited, so delegates can invalidate their caches
 	//non-service fields
 	Locked  bool
 	version interface{}
@@ -55,14 +63,20 @@ type ServiceNode struct {
 
 func NewServiceNodeFromService(s *service.Service) (*ServiceNode, error) {
 	sn := ServiceNode{
-		ID:            s.ID,
-		Name:          s.Name,
-		DesiredState:  s.DesiredState,
-		Instances:     s.Instances,
-		CPUCommitment: int(s.CPUCommitment),
-		RAMCommitment: s.RAMCommitment,
-		ChangeOptions: s.ChangeOptions,
-		HostPolicy:    s.HostPolicy,
+		ID:               s.ID,
+		Name:             s.Name,
+		DesiredState:     s.DesiredState,
+		Instances:        s.Instances,
+		CPUCommitment:    int(s.CPUCommitment),
+		CPURequest:       s.CPURequest,
+		RAMCommitment:    s.RAMCommitment,
+		ChangeOptions:    s.ChangeOptions,
+		HostPolicy:       s.HostPolicy,
+		HostAffinity:     s.HostAffinity,
+		HostAntiAffinity: s.HostAntiAffinity,
+		NodeSelector:     s.NodeSelector,
+		PriorityClass:    s.PriorityClass,
+		UpdatedAt:        s.UpdatedAt,
 	}
 
 	// Copy address assignment if it exists. Note whether assignment is expected, so the scheduler can verify it later.
@@ -87,6 +101,11 @@ func (s *ServiceNode) SetVersion(version interface{}) {
 	s.version = version
 }
 
+// GetID implements zzk.SyncNode
+func (s *ServiceNode) GetID() string {
+	return s.ID
+}
+
 func GetServiceNodes(conn client.Connection) ([]ServiceNode, error) {
 	svcNodes := []ServiceNode{}
 
@@ -136,7 +155,9 @@ func UpdateService(conn client.Connection, svc *service.Service, setLockOnCreate
 }
 
 // UpdateServices creates the services if they doesn't exist or updates it if it
-// does exist. (uses a pool-based connection). All svcs MUST be in the same pool
+// does exist. (uses a pool-based connection). All svcs MUST be in the same pool.
+// The services are written in a single transaction, so either all of them are
+// updated or none are.
 func UpdateServices(conn client.Connection, svcs []*service.Service, setLockOnCreate, setLockOnUpdate bool) error {
 	poolLogger := plog.WithFields(log.Fields{
 		"poolid":       svcs[0].PoolID,
@@ -152,14 +173,13 @@ func UpdateServices(conn client.Connection, svcs []*service.Service, setLockOnCr
 		}
 	}
 
+	tx := conn.NewTransaction()
 	for _, svc := range svcs {
 		pth := path.Join("/services", svc.ID)
 		logger := poolLogger.WithFields(log.Fields{
 			"zkpath": pth,
 		})
-		//
-		// create the service if it doesn't exist
-		// setLockOnCreate sets the lock as the node is created
+
 		sn, err := NewServiceNodeFromService(svc)
 		if err != nil {
 			logger.WithError(err).Error("Could not create service node from service")
@@ -170,48 +190,35 @@ func UpdateServices(conn client.Connection, svcs []*service.Service, setLockOnCr
 			}
 		}
 
+		// create the service if it doesn't exist
+		// setLockOnCreate sets the lock as the node is created
 		sn.Locked = setLockOnCreate
-		if err := conn.CreateIfExists(pth, sn); err == client.ErrNodeExists {
-
-			// the node exists so get it and update it
-			node := &ServiceNode{}
-			if err := conn.Get(pth, node); err != nil && err != client.ErrEmptyNode {
-				logger.WithError(err).Error("Could not get service entry from zookeeper")
-				return &ServiceError{
-					Action:    "update",
-					ServiceID: svc.ID,
-					Message:   "could not get service for update",
-				}
-			}
-
-			// setLockOnUpdate sets the lock to true if enabled, otherwise it uses
-			// whatever existing value was previously set on the node.
-			if setLockOnUpdate {
-				node.Locked = true
-			}
-			sn.SetVersion(node.Version())
-			if err := conn.Set(pth, sn); err != nil {
-				logger.WithError(err).Error("Could not update service entry in zookeeper")
-				return &ServiceError{
-					Action:    "update",
-					ServiceID: svc.ID,
-					Message:   "could not update service",
-				}
-			}
-
-			logger.Debug("Updated entry for service in zookeeper")
-		} else if err != nil {
-			logger.WithError(err).Error("Could not create service entry in zookeeper")
+		node := &ServiceNode{}
+		if err := conn.Get(pth, node); err == client.ErrNoNode {
+			tx.Create(pth, sn)
+		} else if err != nil && err != client.ErrEmptyNode {
+			logger.WithError(err).Error("Could not get service entry from zookeeper")
 			return &ServiceError{
 				Action:    "update",
 				ServiceID: svc.ID,
-				Message:   "could not create service",
+				Message:   "could not get service for update",
 			}
+		} else {
+			// the node exists so update it
+			sn.SetVersion(node.Version())
+			tx.Set(pth, sn)
 		}
+	}
 
+	if err := tx.Commit(); err != nil {
+		poolLogger.WithError(err).Error("Could not update service entries in zookeeper")
+		return &ServiceError{
+			Action:  "update",
+			Message: "could not update services",
+		}
 	}
 
-	poolLogger.Debug("Created entry for service in zookeeper")
+	poolLogger.Debug("Updated entries for services in zookeeper")
 	return nil
 }
 
@@ -262,57 +269,45 @@ func RemoveService(conn client.Connection, poolID, serviceID string) error {
 		}
 	}
 
+	// clean up the event timeline of the service
+	if err := conn.Delete(path.Join(basepth, "/events", serviceID)); err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Warn("Could not delete event timeline from zookeeper")
+	}
+
+	// clean up the instance history of the service
+	if err := conn.Delete(historyPath(poolID, serviceID)); err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Warn("Could not delete instance history from zookeeper")
+	}
+
 	logger.Debug("Deleted service entry from zookeeper")
 	return nil
 }
 
 // SyncServices synchronizes the services to the provided list (uses a pool-
-// based connection)
+// based connection).  Only the services that changed are written, in batched
+// transactions.
 func SyncServices(conn client.Connection, svcs []service.Service) error {
-	pth := path.Join("/services")
-
-	logger := plog.WithField("zkpath", pth)
-
-	// look up children service ids
-	ch, err := conn.Children(pth)
-	if err != nil && err != client.ErrNoNode {
-		logger.WithError(err).Debug("Could not look up services")
-
-		// TODO: wrap error?
-		return err
-	}
-
-	// store the service ids in a hash map
-	chmap := make(map[string]struct{})
-	for _, serviceid := range ch {
-		chmap[serviceid] = struct{}{}
-	}
-
-	// set the services
-	for _, s := range svcs {
-		if err := UpdateService(conn, &s, false, false); err != nil {
-			return err
-		}
-
-		// delete matching records
-		if _, ok := chmap[s.ID]; ok {
-			delete(chmap, s.ID)
-		}
-	}
-
-	// remove any leftovers
-	for serviceid := range chmap {
-		if err := conn.Delete(path.Join(pth, serviceid)); err != nil {
-
-			logger.WithField("serviceid", serviceid).WithError(err).Debug("Could not delete service entry from zookeeper")
+	nodes := make([]zzk.SyncNode, len(svcs))
+	for i := range svcs {
+		sn, err := NewServiceNodeFromService(&svcs[i])
+		if err != nil {
+			plog.WithField("serviceid", svcs[i].ID).WithError(err).Debug("Could not create service node from service")
 			return &ServiceError{
 				Action:    "sync",
-				ServiceID: serviceid,
-				Message:   "could not delete service",
+				ServiceID: svcs[i].ID,
+				Message:   "could not create service node from service",
 			}
 		}
+		nodes[i] = sn
 	}
 
+	if err := zzk.Sync(conn, nodes, "/services"); err != nil {
+		plog.WithError(err).Debug("Could not sync service entries in zookeeper")
+		return &ServiceError{
+			Action:  "sync",
+			Message: "could not sync services",
+		}
+	}
 	return nil
 }
 
@@ -164,3 +164,33 @@ func (t *ZZKTest) TestWaitInstance(c *C) {
 		c.Fatalf("Timed out waiting for listener")
 	}
 }
+
+func (t *ZZKTest) TestUpdateServices(c *C) {
+	conn, err := zzk.GetLocalConnection("/pools/poolid")
+	c.Assert(err, IsNil)
+
+	svcs := []*service.Service{
+		{ID: "serviceid1", PoolID: "poolid", DesiredState: int(service.SVCStop)},
+		{ID: "serviceid2", PoolID: "poolid", DesiredState: int(service.SVCStop)},
+	}
+
+	// create the services
+	err = UpdateServices(conn, svcs[:1], false, false)
+	c.Assert(err, IsNil)
+	err = UpdateServices(conn, svcs, false, false)
+	c.Assert(err, IsNil)
+
+	// update all of the services in one call
+	for _, svc := range svcs {
+		svc.DesiredState = int(service.SVCRun)
+	}
+	err = UpdateServices(conn, svcs, false, false)
+	c.Assert(err, IsNil)
+
+	for _, svc := range svcs {
+		node := &ServiceNode{}
+		err = conn.Get("/services/"+svc.ID, node)
+		c.Assert(err, IsNil)
+		c.Assert(node.DesiredState, Equals, int(service.SVCRun))
+	}
+}
@@ -29,6 +29,13 @@ type ServiceHandler interface {
 	SelectHost(*ServiceNode) (string, error)
 }
 
+// InstanceHostSelector is implemented by service handlers that can schedule
+// an instance back onto the host where it last ran.  The handler should fall
+// back to its normal selection if the preferred host is not suitable.
+type InstanceHostSelector interface {
+	SelectInstanceHost(sn *ServiceNode, preferredHostID string) (string, error)
+}
+
 // ServiceListener is the listener for /services
 type ServiceListener struct {
 	conn    client.Connection
@@ -291,7 +298,7 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 	})
 
 	// pick a host
-	hostID, err := l.handler.SelectHost(sn)
+	hostID, err := l.selectHost(logger, sn, instanceID)
 	if err != nil {
 		logger.WithError(err).Warn("Could not select host")
 		return false
@@ -319,7 +326,7 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 	// make sure the state exists on neither the service nor the host
 	DeleteState(l.conn, req)
 
-	if err := CreateState(l.conn, req); err != nil {
+	if err := CreatePriorityState(l.conn, req, servicedefinition.PriorityRank(sn.PriorityClass)); err != nil {
 
 		logger.WithError(err).Warn("Could not schedule service instance")
 		return false
@@ -328,6 +335,23 @@ func (l *ServiceListener) Start(sn *ServiceNode, instanceID int) bool {
 	return true
 }
 
+// selectHost picks a host for the instance, preferring the host where the
+// instance last ran if the handler supports it.
+func (l *ServiceListener) selectHost(logger *log.Entry, sn *ServiceNode, instanceID int) (string, error) {
+	selector, ok := l.handler.(InstanceHostSelector)
+	if !ok {
+		return l.handler.SelectHost(sn)
+	}
+
+	lastHostID, err := GetLastInstanceHost(l.conn, l.poolid, sn.ID, instanceID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not look up the last host of the instance")
+		return l.handler.SelectHost(sn)
+	}
+
+	return selector.SelectInstanceHost(sn, lastHostID)
+}
+
 // Stop unschedules the list of service instances and returns the number of
 // instances successfully stopped.
 func (l *ServiceListener) Stop(reqs []StateRequest) (int, bool) {
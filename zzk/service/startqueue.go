@@ -0,0 +1,134 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// StartQueue limits the number of containers that a host starts at the same
+// time.  When an outage or a large deployment schedules many instances to a
+// host at once, the starts wait in the queue instead of all contending for
+// docker, and the instances of the highest priority class start first.
+// Starts of the same priority are taken in the order they arrived.
+type StartQueue struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	seq     uint64
+	waiting startTickets
+}
+
+// NewStartQueue returns a queue that runs up to limit starts at a time.  A
+// limit of 0 or less does not limit the starts.
+func NewStartQueue(limit int) *StartQueue {
+	return &StartQueue{limit: limit}
+}
+
+// Acquire blocks until a start of the given priority may run or the cancel
+// channel closes, returning false if it was canceled.  A start that was
+// acquired must be released.
+func (q *StartQueue) Acquire(cancel <-chan interface{}, priority int) bool {
+	q.mu.Lock()
+	if q.limit <= 0 || (q.running < q.limit && len(q.waiting) == 0) {
+		q.running++
+		q.mu.Unlock()
+		return true
+	}
+	t := &startTicket{priority: priority, seq: q.seq, ready: make(chan struct{}), index: -1}
+	q.seq++
+	heap.Push(&q.waiting, t)
+	q.mu.Unlock()
+
+	select {
+	case <-t.ready:
+		return true
+	case <-cancel:
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if t.index < 0 {
+			// the start was handed the slot while it was canceled
+			q.release()
+		} else {
+			heap.Remove(&q.waiting, t.index)
+		}
+		return false
+	}
+}
+
+// Release ends a start, letting the next start in the queue run
+func (q *StartQueue) Release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.release()
+}
+
+func (q *StartQueue) release() {
+	q.running--
+	for len(q.waiting) > 0 && (q.limit <= 0 || q.running < q.limit) {
+		t := heap.Pop(&q.waiting).(*startTicket)
+		q.running++
+		close(t.ready)
+	}
+}
+
+// Waiting returns the number of starts that are waiting in the queue
+func (q *StartQueue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting)
+}
+
+// startTicket is a start waiting in a StartQueue
+type startTicket struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+	index    int
+}
+
+// startTickets is a heap of the starts that are waiting, with the highest
+// priority first
+type startTickets []*startTicket
+
+func (h startTickets) Len() int { return len(h) }
+
+func (h startTickets) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h startTickets) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *startTickets) Push(x interface{}) {
+	t := x.(*startTicket)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *startTickets) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	t.index = -1
+	*h = old[:len(old)-1]
+	return t
+}
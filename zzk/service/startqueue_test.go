@@ -0,0 +1,91 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package service_test
+
+import (
+	"time"
+
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&StartQueueTestSuite{})
+
+type StartQueueTestSuite struct{}
+
+func (s *StartQueueTestSuite) TestPriorityOrder(c *C) {
+	q := NewStartQueue(1)
+	cancel := make(chan interface{})
+	c.Assert(q.Acquire(cancel, 0), Equals, true)
+
+	started := make(chan string, 3)
+	wait := func(name string, priority int) {
+		waiting := q.Waiting()
+		go func() {
+			if q.Acquire(cancel, priority) {
+				started <- name
+				q.Release()
+			}
+		}()
+		for q.Waiting() == waiting {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wait("low", -1)
+	wait("normal", 0)
+	wait("critical", 2)
+	c.Assert(q.Waiting(), Equals, 3)
+	c.Assert(started, HasLen, 0)
+
+	q.Release()
+	for _, name := range []string{"critical", "normal", "low"} {
+		select {
+		case actual := <-started:
+			c.Assert(actual, Equals, name)
+		case <-time.After(5 * time.Second):
+			c.Fatalf("start %s did not run", name)
+		}
+	}
+	c.Assert(q.Waiting(), Equals, 0)
+}
+
+func (s *StartQueueTestSuite) TestCancel(c *C) {
+	q := NewStartQueue(1)
+	c.Assert(q.Acquire(nil, 0), Equals, true)
+
+	cancel := make(chan interface{})
+	done := make(chan bool)
+	go func() { done <- q.Acquire(cancel, 1) }()
+	for q.Waiting() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(cancel)
+	c.Assert(<-done, Equals, false)
+	c.Assert(q.Waiting(), Equals, 0)
+
+	// the canceled start does not hold the slot
+	q.Release()
+	c.Assert(q.Acquire(nil, 0), Equals, true)
+}
+
+func (s *StartQueueTestSuite) TestUnlimited(c *C) {
+	q := NewStartQueue(0)
+	for i := 0; i < 10; i++ {
+		c.Assert(q.Acquire(nil, 0), Equals, true)
+	}
+	c.Assert(q.Waiting(), Equals, 0)
+}
@@ -93,6 +93,11 @@ func (s *ServiceState) SetVersion(version interface{}) {
 type HostState struct {
 	DesiredState service.DesiredState
 	Scheduled    time.Time
+	ImageID      string    // overrides the service image while set
+	Environment  []string  // added to the service environment while set
+	Restarts     int       // restarts triggered by failing health checks
+	LastRestart  time.Time // time of the last of those restarts
+	Priority     int       // rank of the priority class of the service, orders the starts on the host
 	version      interface{}
 }
 
@@ -441,6 +446,12 @@ func GetHostStates(conn client.Connection, poolID, hostID string) ([]State, erro
 
 // CreateState creates a new service state and host state
 func CreateState(conn client.Connection, req StateRequest) error {
+	return CreatePriorityState(conn, req, 0)
+}
+
+// CreatePriorityState creates a new service state and host state for an
+// instance whose service has a priority class of the given rank
+func CreatePriorityState(conn client.Connection, req StateRequest, priority int) error {
 	logger := plog.WithFields(log.Fields{
 		"hostid":     req.HostID,
 		"serviceid":  req.ServiceID,
@@ -471,6 +482,7 @@ func CreateState(conn client.Connection, req StateRequest) error {
 	hsdat := &HostState{
 		DesiredState: service.SVCRun,
 		Scheduled:    time.Now(),
+		Priority:     priority,
 	}
 	t.Create(hspth, hsdat)
 
@@ -493,6 +505,11 @@ func CreateState(conn client.Connection, req StateRequest) error {
 		}
 	}
 
+	// the history is informational, so failing to record it is not fatal
+	if err := RecordInstanceStart(conn, req); err != nil {
+		logger.WithError(err).Warn("Could not update instance history")
+	}
+
 	logger.Debug("Created state")
 	return nil
 }
@@ -672,6 +689,11 @@ func DeleteState(conn client.Connection, req StateRequest) error {
 		}
 	}
 
+	// the history is informational, so failing to record it is not fatal
+	if err := RecordInstanceStop(conn, req); err != nil {
+		logger.WithError(err).Warn("Could not update instance history")
+	}
+
 	logger.Debug("Deleted state")
 	return nil
 }
@@ -0,0 +1,308 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/pool"
+)
+
+const (
+	// VirtualIPCheckFailures is the number of consecutive reachability
+	// checks that a virtual IP fails before it is moved to another host.
+	VirtualIPCheckFailures = 3
+
+	// VirtualIPFailoverExclusion is how long a virtual IP is kept off of the
+	// host that it failed over from.
+	VirtualIPFailoverExclusion = 5 * time.Minute
+
+	// maxVirtualIPCheckTimeout bounds how long one check waits for an answer
+	maxVirtualIPCheckTimeout = 5 * time.Second
+)
+
+// VirtualIPChecker checks whether a virtual IP answers on the network
+type VirtualIPChecker interface {
+	Reachable(ipAddress string, timeout time.Duration) bool
+}
+
+// CommandChecker checks virtual IPs with an ICMP ping, falling back to an
+// ARP ping for networks that drop ICMP.
+type CommandChecker struct{}
+
+// Reachable implements VirtualIPChecker
+func (CommandChecker) Reachable(ipAddress string, timeout time.Duration) bool {
+	secs := int(timeout.Seconds())
+	if secs < 1 {
+		secs = 1
+	}
+	wait := strconv.Itoa(secs)
+	if err := exec.Command("ping", "-c", "1", "-W", wait, ipAddress).Run(); err == nil {
+		return true
+	}
+	if arping, err := exec.LookPath("arping"); err == nil {
+		return exec.Command(arping, "-c", "1", "-w", wait, ipAddress).Run() == nil
+	}
+	return false
+}
+
+// VirtualIPFailoverHandler unassigns virtual IPs from the hosts that they
+// are unreachable on.
+type VirtualIPFailoverHandler interface {
+	Unassign(poolID, ipAddress string) error
+	ExcludeHost(ipAddress, hostID string, d time.Duration)
+}
+
+// VirtualIPMonitor implements zzk.Listener.  It checks the reachability of
+// the virtual IPs of a pool (/pools/poolid/ips) at the check interval of the
+// pool, and moves a virtual IP that fails VirtualIPCheckFailures consecutive
+// checks off of its host.  The pool listener then assigns the virtual IP to
+// another host.
+type VirtualIPMonitor struct {
+	connection  client.Connection
+	handler     VirtualIPFailoverHandler
+	hostHandler RegisteredHostHandler
+	checker     VirtualIPChecker
+}
+
+// NewVirtualIPMonitor instantiates a new VirtualIPMonitor
+func NewVirtualIPMonitor(handler VirtualIPFailoverHandler, hostHandler RegisteredHostHandler, checker VirtualIPChecker) *VirtualIPMonitor {
+	return &VirtualIPMonitor{
+		handler:     handler,
+		hostHandler: hostHandler,
+		checker:     checker,
+	}
+}
+
+// SetConnection implements zzk.Listener
+func (m *VirtualIPMonitor) SetConnection(connection client.Connection) {
+	m.connection = connection
+}
+
+// GetPath implements zzk.Listener
+func (m *VirtualIPMonitor) GetPath(nodes ...string) string {
+	return Base().Pools().Path()
+}
+
+// Ready implements zzk.Listener
+func (m *VirtualIPMonitor) Ready() error { return nil }
+
+// Done implements zzk.Listener
+func (m *VirtualIPMonitor) Done() {}
+
+// PostProcess implements zzk.Listener
+func (m *VirtualIPMonitor) PostProcess(p map[string]struct{}) {}
+
+// Spawn checks the virtual IPs of a pool until shutdown or the pool is
+// removed.
+func (m *VirtualIPMonitor) Spawn(shutdown <-chan interface{}, poolID string) {
+	logger := plog.WithField("poolid", poolID)
+	logger.Debug("Spawning virtual ip monitor")
+
+	failures := make(map[string]int)
+	var lastCheck time.Time
+
+	for {
+		stop := make(chan struct{})
+		node := &PoolNode{ResourcePool: &pool.ResourcePool{}}
+		poolEvent, err := m.connection.GetW(Base().Pools().ID(poolID).Path(), node, stop)
+		if err == client.ErrNoNode {
+			close(stop)
+			logger.Debug("Pool was removed; stopping virtual ip monitor")
+			return
+		} else if err != nil {
+			close(stop)
+			logger.WithError(err).Error("Unable to watch pool")
+			return
+		}
+
+		// wait for the next check, or for the pool to change
+		var timer *time.Timer
+		var tick <-chan time.Time
+		if interval := node.GetVirtualIPCheckInterval(); interval > 0 {
+			wait := lastCheck.Add(interval).Sub(time.Now())
+			if wait < 0 {
+				wait = 0
+			}
+			timer = time.NewTimer(wait)
+			tick = timer.C
+		} else {
+			failures = make(map[string]int)
+		}
+
+		select {
+		case <-poolEvent:
+		case <-tick:
+			lastCheck = time.Now()
+			m.check(poolID, node.GetVirtualIPCheckInterval(), failures)
+		case <-shutdown:
+			if timer != nil {
+				timer.Stop()
+			}
+			close(stop)
+			return
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+		close(stop)
+	}
+}
+
+// check checks each virtual IP that is bound on its host, and fails over the
+// virtual IPs that failed too many checks in a row.  failures counts the
+// consecutive failed checks of each assignment.
+func (m *VirtualIPMonitor) check(poolID string, interval time.Duration, failures map[string]int) {
+	logger := plog.WithField("poolid", poolID)
+
+	ipsPath := Base().Pools().ID(poolID).IPs().Path()
+	ipIDs, err := m.connection.Children(ipsPath)
+	if err != nil && err != client.ErrNoNode {
+		logger.WithError(err).Warn("Could not look up virtual ip assignments")
+		return
+	}
+
+	timeout := interval / 2
+	if timeout > maxVirtualIPCheckTimeout {
+		timeout = maxVirtualIPCheckTimeout
+	}
+
+	// check the assignments whose host has bound the virtual ip
+	reachable := make(map[string]bool)
+	mu := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for _, ipID := range ipIDs {
+		_, ipAddress, err := ParseIPID(ipID)
+		if err != nil {
+			continue
+		}
+		poolIP := &PoolIP{}
+		if err := m.connection.Get(path.Join(ipsPath, ipID), poolIP); err != nil || !poolIP.OK {
+			continue
+		}
+		wg.Add(1)
+		go func(ipID, ipAddress string) {
+			defer wg.Done()
+			ok := m.checker.Reachable(ipAddress, timeout)
+			mu.Lock()
+			reachable[ipID] = ok
+			mu.Unlock()
+		}(ipID, ipAddress)
+	}
+	wg.Wait()
+
+	// forget the assignments that were not checked
+	for ipID := range failures {
+		if _, ok := reachable[ipID]; !ok {
+			delete(failures, ipID)
+		}
+	}
+
+	for ipID, ok := range reachable {
+		hostID, ipAddress, _ := ParseIPID(ipID)
+		ipLogger := logger.WithFields(log.Fields{
+			"hostid":    hostID,
+			"ipaddress": ipAddress,
+		})
+
+		if ok {
+			if failures[ipID] > 0 {
+				ipLogger.Info("Virtual ip is reachable again")
+				m.addEvent(poolID, pool.VirtualIPEvent{
+					Type:      pool.EventVirtualIPRecovered,
+					IPAddress: ipAddress,
+					HostID:    hostID,
+					Message:   fmt.Sprintf("virtual ip answered after %d failed checks", failures[ipID]),
+				})
+			}
+			delete(failures, ipID)
+			continue
+		}
+
+		failures[ipID]++
+		ipLogger.WithField("failures", failures[ipID]).Warn("Virtual ip did not answer its reachability check")
+		if failures[ipID] == 1 {
+			m.addEvent(poolID, pool.VirtualIPEvent{
+				Type:      pool.EventVirtualIPUnreachable,
+				IPAddress: ipAddress,
+				HostID:    hostID,
+				Message:   "virtual ip did not answer its reachability check",
+			})
+		}
+		if failures[ipID] >= VirtualIPCheckFailures {
+			delete(failures, ipID)
+			m.failover(poolID, hostID, ipAddress)
+		}
+	}
+}
+
+// failover moves a virtual IP off of the host that it is unreachable on, if
+// another host in the pool can take it.
+func (m *VirtualIPMonitor) failover(poolID, hostID, ipAddress string) {
+	logger := plog.WithFields(log.Fields{
+		"poolid":    poolID,
+		"hostid":    hostID,
+		"ipaddress": ipAddress,
+	})
+
+	hosts, err := m.hostHandler.GetRegisteredHosts(poolID)
+	if err != nil {
+		logger.WithError(err).Warn("Could not look up hosts to fail over virtual ip")
+		return
+	}
+	canMove := false
+	for _, h := range hosts {
+		if h.ID != hostID {
+			canMove = true
+			break
+		}
+	}
+	if !canMove {
+		logger.Warn("Virtual ip is unreachable, but no other host in the pool can take it")
+		m.addEvent(poolID, pool.VirtualIPEvent{
+			Type:      pool.EventVirtualIPFailoverSkipped,
+			IPAddress: ipAddress,
+			HostID:    hostID,
+			Message:   "no other host in the pool can take the virtual ip",
+		})
+		return
+	}
+
+	m.handler.ExcludeHost(ipAddress, hostID, VirtualIPFailoverExclusion)
+	if err := m.handler.Unassign(poolID, ipAddress); err != nil {
+		logger.WithError(err).Warn("Could not unassign unreachable virtual ip")
+		return
+	}
+	logger.Warn("Moved unreachable virtual ip off of its host")
+	m.addEvent(poolID, pool.VirtualIPEvent{
+		Type:      pool.EventVirtualIPFailover,
+		IPAddress: ipAddress,
+		HostID:    hostID,
+		Message:   fmt.Sprintf("virtual ip failed %d reachability checks and was moved off of the host", VirtualIPCheckFailures),
+	})
+}
+
+func (m *VirtualIPMonitor) addEvent(poolID string, evt pool.VirtualIPEvent) {
+	if err := AddVirtualIPEvent(m.connection, poolID, evt); err != nil {
+		plog.WithField("poolid", poolID).WithError(err).Warn("Could not record virtual ip event")
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+// +build unit
+
+package service_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/host"
+	p "github.com/control-center/serviced/domain/pool"
+	. "github.com/control-center/serviced/zzk/service"
+	"github.com/control-center/serviced/zzk/service/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&VirtualIPMonitorTestSuite{})
+
+// testChecker reports every virtual ip as unreachable
+type testChecker struct {
+	mu     sync.Mutex
+	checks int
+}
+
+func (t *testChecker) Reachable(ipAddress string, timeout time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checks++
+	return false
+}
+
+// testFailoverHandler records the hosts that are excluded and the virtual
+// ips that are unassigned
+type testFailoverHandler struct {
+	excluded   chan string
+	unassigned chan string
+}
+
+func (t *testFailoverHandler) Unassign(poolID, ipAddress string) error {
+	select {
+	case t.unassigned <- poolID + "/" + ipAddress:
+	default:
+	}
+	return nil
+}
+
+func (t *testFailoverHandler) ExcludeHost(ipAddress, hostID string, d time.Duration) {
+	select {
+	case t.excluded <- hostID + "-" + ipAddress:
+	default:
+	}
+}
+
+type VirtualIPMonitorTestSuite struct {
+	connection  *mocks.Connection
+	hostHandler *mocks.RegisteredHostHandler
+	handler     *testFailoverHandler
+	checker     *testChecker
+	monitor     *VirtualIPMonitor
+	events      chan p.VirtualIPEvent
+}
+
+func (s *VirtualIPMonitorTestSuite) SetUpTest(c *C) {
+	s.connection = &mocks.Connection{}
+	s.hostHandler = &mocks.RegisteredHostHandler{}
+	s.handler = &testFailoverHandler{excluded: make(chan string, 1), unassigned: make(chan string, 1)}
+	s.checker = &testChecker{}
+	s.events = make(chan p.VirtualIPEvent, 10)
+
+	pool := p.ResourcePool{ID: "test", VirtualIPCheckInterval: 10}
+	s.connection.On("GetW", "/pools/test", mock.AnythingOfType("*service.PoolNode"), mock.AnythingOfType("<-chan struct {}")).
+		Return((<-chan client.Event)(make(chan client.Event)), nil).
+		Run(func(a mock.Arguments) {
+			node := a.Get(1).(*PoolNode)
+			node.ResourcePool = &pool
+		})
+	s.connection.On("Children", "/pools/test/ips").Return([]string{"host1-1.2.3.4"}, nil)
+	s.connection.On("Get", "/pools/test/ips/host1-1.2.3.4", mock.AnythingOfType("*service.PoolIP")).
+		Return(nil).
+		Run(func(a mock.Arguments) {
+			a.Get(1).(*PoolIP).OK = true
+		})
+	s.connection.On("Get", "/pools/test/events", mock.AnythingOfType("*service.VirtualIPEvents")).Return(client.ErrNoNode)
+	s.connection.On("Create", "/pools/test/events", mock.AnythingOfType("*service.VirtualIPEvents")).
+		Return(nil).
+		Run(func(a mock.Arguments) {
+			for _, evt := range a.Get(1).(*VirtualIPEvents).Events {
+				select {
+				case s.events <- evt:
+				default:
+				}
+			}
+		})
+
+	s.monitor = NewVirtualIPMonitor(s.handler, s.hostHandler, s.checker)
+	s.monitor.SetConnection(s.connection)
+}
+
+func (s *VirtualIPMonitorTestSuite) nextEvent(c *C) p.VirtualIPEvent {
+	select {
+	case evt := <-s.events:
+		return evt
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for virtual ip event")
+	}
+	return p.VirtualIPEvent{}
+}
+
+func (s *VirtualIPMonitorTestSuite) TestMonitorShouldFailOverUnreachableIP(c *C) {
+	s.hostHandler.On("GetRegisteredHosts", "test").Return([]host.Host{{ID: "host1"}, {ID: "host2"}}, nil)
+
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		s.monitor.Spawn(shutdown, "test")
+		close(done)
+	}()
+	defer func() {
+		close(shutdown)
+		<-done
+	}()
+
+	evt := s.nextEvent(c)
+	c.Assert(evt.Type, Equals, p.EventVirtualIPUnreachable)
+	c.Assert(evt.HostID, Equals, "host1")
+	c.Assert(evt.IPAddress, Equals, "1.2.3.4")
+
+	select {
+	case excluded := <-s.handler.excluded:
+		c.Assert(excluded, Equals, "host1-1.2.3.4")
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for host to be excluded")
+	}
+	select {
+	case unassigned := <-s.handler.unassigned:
+		c.Assert(unassigned, Equals, "test/1.2.3.4")
+	case <-time.After(5 * time.Second):
+		c.Fatalf("Timed out waiting for virtual ip to be unassigned")
+	}
+
+	evt = s.nextEvent(c)
+	c.Assert(evt.Type, Equals, p.EventVirtualIPFailover)
+	c.Assert(evt.HostID, Equals, "host1")
+
+	s.checker.mu.Lock()
+	c.Assert(s.checker.checks >= VirtualIPCheckFailures, Equals, true)
+	s.checker.mu.Unlock()
+}
+
+func (s *VirtualIPMonitorTestSuite) TestMonitorShouldNotFailOverWithoutAnotherHost(c *C) {
+	s.hostHandler.On("GetRegisteredHosts", "test").Return([]host.Host{{ID: "host1"}}, nil)
+
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		s.monitor.Spawn(shutdown, "test")
+		close(done)
+	}()
+	defer func() {
+		close(shutdown)
+		<-done
+	}()
+
+	c.Assert(s.nextEvent(c).Type, Equals, p.EventVirtualIPUnreachable)
+	c.Assert(s.nextEvent(c).Type, Equals, p.EventVirtualIPFailoverSkipped)
+	select {
+	case <-s.handler.unassigned:
+		c.Fatalf("Virtual ip was unassigned without another host")
+	default:
+	}
+}
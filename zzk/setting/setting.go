@@ -0,0 +1,138 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setting
+
+import (
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+// settingsPath is the node that holds every setting of the cluster, so that
+// delegates need a single watch to follow them.
+const settingsPath = "/settings"
+
+// SettingsNode is the zookeeper node of the cluster settings
+type SettingsNode struct {
+	Settings []setting.Setting
+	version  interface{}
+}
+
+// Version implements client.Node
+func (node *SettingsNode) Version() interface{} {
+	return node.version
+}
+
+// SetVersion implements client.Node
+func (node *SettingsNode) SetVersion(version interface{}) {
+	node.version = version
+}
+
+// SetSettings replaces the settings of the cluster. (uses a root-based
+// connection)
+func SetSettings(conn client.Connection, settings []setting.Setting) error {
+	logger := plog.WithField("zkpath", settingsPath)
+
+	if err := conn.Create(settingsPath, &SettingsNode{Settings: settings}); err == client.ErrNodeExists {
+		node := &SettingsNode{}
+		if err := conn.Get(settingsPath, node); err != nil && err != client.ErrEmptyNode {
+			logger.WithError(err).Debug("Could not get settings from zookeeper")
+			return err
+		}
+		node.Settings = settings
+		if err := conn.Set(settingsPath, node); err != nil {
+			logger.WithError(err).Debug("Could not update settings in zookeeper")
+			return err
+		}
+		logger.Debug("Updated settings in zookeeper")
+		return nil
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not create settings in zookeeper")
+		return err
+	}
+	logger.Debug("Created settings in zookeeper")
+	return nil
+}
+
+// GetSettings returns the settings of the cluster. (uses a root-based
+// connection)
+func GetSettings(conn client.Connection) ([]setting.Setting, error) {
+	node := &SettingsNode{}
+	if err := conn.Get(settingsPath, node); err == client.ErrNoNode || err == client.ErrEmptyNode {
+		return []setting.Setting{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return node.Settings, nil
+}
+
+// SettingsListener keeps a cache up to date with the settings of the cluster
+type SettingsListener struct {
+	cache *setting.Cache
+}
+
+// NewSettingsListener instantiates a listener that updates the cache
+func NewSettingsListener(cache *setting.Cache) *SettingsListener {
+	return &SettingsListener{cache: cache}
+}
+
+// Listen implements zzk.Listener2.  It watches the settings of the cluster
+// until it is cancelled or the connection fails. (uses a root-based
+// connection)
+func (l *SettingsListener) Listen(cancel <-chan interface{}, conn client.Connection) {
+	logger := plog.WithField("zkpath", settingsPath)
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+	for {
+		ok, ev, err := conn.ExistsW(settingsPath, done)
+		if err != nil {
+			logger.WithError(err).Error("Could not watch settings")
+			return
+		}
+
+		settings := []setting.Setting{}
+		if ok {
+			node := &SettingsNode{}
+			ev, err = conn.GetW(settingsPath, node, done)
+			if err == client.ErrNoNode {
+				close(done)
+				done = make(chan struct{})
+				continue
+			} else if err != nil && err != client.ErrEmptyNode {
+				logger.WithError(err).Error("Could not get settings")
+				return
+			}
+			settings = node.Settings
+		}
+		l.cache.Update(settings)
+		logger.WithField("settings", len(settings)).Debug("Updated settings")
+
+		select {
+		case <-ev:
+		case <-cancel:
+			return
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// Exited implements zzk.Listener2
+func (l *SettingsListener) Exited() {
+	plog.Info("Settings listener exited")
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration && !quick
+// +build integration,!quick
+
+package setting_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/setting"
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/setting"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&ZZKTest{})
+
+type ZZKTest struct {
+	zzk.ZZKTestSuite
+}
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+func (t *ZZKTest) TestSettingsListener(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	settings, err := GetSettings(conn)
+	c.Assert(err, IsNil)
+	c.Assert(settings, HasLen, 0)
+
+	cache := setting.NewCache()
+	shutdown := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		NewSettingsListener(cache).Listen(shutdown, conn)
+		close(done)
+	}()
+	defer func() {
+		close(shutdown)
+		<-done
+	}()
+
+	s := setting.New(setting.ImagePullPolicy)
+	s.Set(setting.ScopePool, "pool", commons.PullAlways)
+	err = SetSettings(conn, []setting.Setting{*s})
+	c.Assert(err, IsNil)
+	waitForValue(c, cache, commons.PullAlways)
+
+	s.Set(setting.ScopePool, "pool", commons.PullNever)
+	err = SetSettings(conn, []setting.Setting{*s})
+	c.Assert(err, IsNil)
+	waitForValue(c, cache, commons.PullNever)
+
+	settings, err = GetSettings(conn)
+	c.Assert(err, IsNil)
+	c.Assert(settings, HasLen, 1)
+}
+
+func waitForValue(c *C, cache *setting.Cache, expected string) {
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	for {
+		if value, _ := cache.Value(setting.ImagePullPolicy, "pool", ""); value == expected {
+			return
+		}
+		select {
+		case <-timer.C:
+			c.Fatalf("setting was not updated to %s", expected)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -14,11 +14,15 @@
 package zzk
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"path"
+	"reflect"
 	"sync"
 	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
 	"github.com/zenoss/glog"
 )
@@ -63,47 +67,190 @@ type Node interface {
 	Update(conn client.Connection) error
 }
 
-// Sync synchronizes zookeeper data with what is in elastic or any other storage facility
-func Sync(conn client.Connection, data []Node, zkpath string) error {
+// SyncBatchSize is the maximum number of operations that Sync commits in a
+// single transaction.
+var SyncBatchSize = 100
+
+// SyncNode is a child node that is written by Sync
+type SyncNode interface {
+	client.Node
+	// GetID relates to the child node mapping in zookeeper
+	GetID() string
+}
+
+// Sync synchronizes the children of zkpath with what is in elastic or any
+// other storage facility.  The current children are diffed against data, so
+// only nodes that were added, changed or removed are written, and the writes
+// are committed in multi-op transactions of up to SyncBatchSize operations.
+// Removed children are deleted recursively.  If a transaction fails, the
+// batches that were already committed are kept and the next sync picks up
+// where this one left off.
+func Sync(conn client.Connection, data []SyncNode, zkpath string) error {
+	logger := plog.WithField("zkpath", zkpath)
+
+	// create the parent if it doesn't exist
 	var current []string
-	if exists, err := PathExists(conn, zkpath); err != nil {
-		return err
-	} else if !exists {
-		// pass
-	} else if current, err = conn.Children(zkpath); err != nil {
+	if err := conn.CreateIfExists(zkpath, &client.Dir{}); err == client.ErrNodeExists {
+		if current, err = conn.Children(zkpath); err != nil {
+			logger.WithError(err).Debug("Could not look up children")
+			return err
+		}
+	} else if err != nil {
+		logger.WithError(err).Debug("Could not initialize path")
 		return err
 	}
 
-	datamap := make(map[string]Node)
+	datamap := make(map[string]SyncNode)
 	for i, node := range data {
 		datamap[node.GetID()] = data[i]
 	}
 
+	b := &syncBatch{conn: conn, tx: conn.NewTransaction()}
+
+	// update or delete the existing children
 	for _, id := range current {
+		pth := path.Join(zkpath, id)
 		if node, ok := datamap[id]; ok {
-			glog.V(2).Infof("Updating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
-			if err := node.Update(conn); err != nil {
+			delete(datamap, id)
+
+			changed, err := syncChanged(conn, pth, node)
+			if err != nil {
+				logger.WithField("id", id).WithError(err).Debug("Could not look up child")
+				return err
+			} else if !changed {
+				b.unchanged++
+				continue
+			}
+			b.tx.Set(pth, node)
+			b.updated++
+			if err := b.add(1); err != nil {
+				logger.WithError(err).Debug("Could not commit sync transaction")
 				return err
 			}
-			delete(datamap, id)
 		} else {
-			glog.V(2).Infof("Deleting id:'%s' at zkpath:%s not found in elastic\nzk current children: %v", id, zkpath, current)
-			if err := conn.Delete(path.Join(zkpath, id)); err != nil {
+			if err := b.deleteAll(pth); err != nil {
+				logger.WithField("id", id).WithError(err).Debug("Could not delete child")
 				return err
 			}
+			b.deleted++
 		}
 	}
 
-	for id, node := range datamap {
-		glog.V(2).Infof("Creating id:'%s' at zkpath:%s with: %+v", id, zkpath, node)
-		if err := node.Create(conn); err != nil {
+	// create the new children in the order that they were passed in
+	for _, node := range data {
+		id := node.GetID()
+		if _, ok := datamap[id]; !ok {
+			continue
+		}
+		delete(datamap, id)
+		b.tx.Create(path.Join(zkpath, id), node)
+		b.created++
+		if err := b.add(1); err != nil {
+			logger.WithError(err).Debug("Could not commit sync transaction")
 			return err
 		}
 	}
 
+	if err := b.commit(); err != nil {
+		logger.WithError(err).Debug("Could not commit sync transaction")
+		return err
+	}
+
+	logger.WithFields(log.Fields{
+		"created":      b.created,
+		"updated":      b.updated,
+		"deleted":      b.deleted,
+		"unchanged":    b.unchanged,
+		"transactions": b.commits,
+	}).Debug("Synchronized children")
 	return nil
 }
 
+// syncChanged returns true if the node at pth does not match node.  The
+// version of the node at pth is copied onto node, so that the update fails if
+// the node is changed before the transaction is committed.
+func syncChanged(conn client.Connection, pth string, node SyncNode) (bool, error) {
+	t := reflect.TypeOf(node)
+	if t.Kind() != reflect.Ptr {
+		return false, ErrInvalidType
+	}
+	cur, ok := reflect.New(t.Elem()).Interface().(SyncNode)
+	if !ok {
+		return false, ErrInvalidType
+	}
+
+	if err := conn.Get(pth, cur); err == client.ErrEmptyNode {
+		node.SetVersion(cur.Version())
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	node.SetVersion(cur.Version())
+
+	want, err := json.Marshal(node)
+	if err != nil {
+		return false, err
+	}
+	have, err := json.Marshal(cur)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(want, have), nil
+}
+
+// syncBatch commits sync operations in transactions of up to SyncBatchSize
+// operations.
+type syncBatch struct {
+	conn client.Connection
+	tx   client.Transaction
+	ops  int
+
+	created, updated, deleted, unchanged, commits int
+}
+
+// add counts n operations onto the open transaction and commits the
+// transaction if it is full.
+func (b *syncBatch) add(n int) error {
+	b.ops += n
+	if b.ops >= SyncBatchSize {
+		return b.commit()
+	}
+	return nil
+}
+
+// commit commits the open transaction and opens a new one.
+func (b *syncBatch) commit() error {
+	if b.ops == 0 {
+		return nil
+	}
+	if err := b.tx.Commit(); err != nil {
+		return err
+	}
+	b.tx = b.conn.NewTransaction()
+	b.ops = 0
+	b.commits++
+	return nil
+}
+
+// deleteAll adds a recursive delete of pth to the batch.  Children are
+// deleted before their parents, so a large subtree may span several
+// transactions.
+func (b *syncBatch) deleteAll(pth string) error {
+	ch, err := b.conn.Children(pth)
+	if err == client.ErrNoNode {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, n := range ch {
+		if err := b.deleteAll(path.Join(pth, n)); err != nil {
+			return err
+		}
+	}
+	b.tx.Delete(pth)
+	return b.add(1)
+}
+
 // Synchronizer is the remote synchronizer object
 type Synchronizer struct {
 	SyncHandler
@@ -0,0 +1,97 @@
+// Copyright 2017 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration && !quick
+// +build integration,!quick
+
+package zzk_test
+
+import (
+	"path"
+	"sort"
+
+	. "github.com/control-center/serviced/zzk"
+	. "gopkg.in/check.v1"
+)
+
+type testSyncNode struct {
+	ID      string
+	Value   string
+	version interface{}
+}
+
+func (n *testSyncNode) GetID() string                  { return n.ID }
+func (n *testSyncNode) Version() interface{}           { return n.version }
+func (n *testSyncNode) SetVersion(version interface{}) { n.version = version }
+
+func (t *ZZKTest) TestSync(c *C) {
+	conn, err := GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	defer func(size int) { SyncBatchSize = size }(SyncBatchSize)
+	SyncBatchSize = 2
+
+	// create nodes on a path that doesn't exist
+	err = Sync(conn, []SyncNode{
+		&testSyncNode{ID: "a", Value: "1"},
+		&testSyncNode{ID: "b", Value: "1"},
+		&testSyncNode{ID: "c", Value: "1"},
+	}, "/sync")
+	c.Assert(err, IsNil)
+	ch, err := conn.Children("/sync")
+	c.Assert(err, IsNil)
+	sort.Strings(ch)
+	c.Check(ch, DeepEquals, []string{"a", "b", "c"})
+
+	// add children to a node that will be removed
+	err = conn.CreateDir("/sync/c/child/grandchild")
+	c.Assert(err, IsNil)
+
+	// capture the version of the node that won't change
+	a := &testSyncNode{}
+	err = conn.Get("/sync/a", a)
+	c.Assert(err, IsNil)
+
+	// update, delete and create
+	err = Sync(conn, []SyncNode{
+		&testSyncNode{ID: "a", Value: "1"},
+		&testSyncNode{ID: "b", Value: "2"},
+		&testSyncNode{ID: "d", Value: "1"},
+	}, "/sync")
+	c.Assert(err, IsNil)
+	ch, err = conn.Children("/sync")
+	c.Assert(err, IsNil)
+	sort.Strings(ch)
+	c.Check(ch, DeepEquals, []string{"a", "b", "d"})
+
+	expected := map[string]string{"a": "1", "b": "2", "d": "1"}
+	for id, value := range expected {
+		node := &testSyncNode{}
+		err = conn.Get(path.Join("/sync", id), node)
+		c.Assert(err, IsNil)
+		c.Check(node.Value, Equals, value)
+	}
+
+	// the unchanged node was not written
+	actual := &testSyncNode{}
+	err = conn.Get("/sync/a", actual)
+	c.Assert(err, IsNil)
+	c.Check(actual.Version(), DeepEquals, a.Version())
+
+	// remove everything
+	err = Sync(conn, []SyncNode{}, "/sync")
+	c.Assert(err, IsNil)
+	ch, err = conn.Children("/sync")
+	c.Assert(err, IsNil)
+	c.Check(ch, HasLen, 0)
+}